@@ -6,13 +6,21 @@
 // How to run:
 // 1) `go run main.go`
 // 2) On your Mac: open http://localhost:8080/sender and click "Start Share".
+//    Another machine finds the server with `share-screen discover`.
 //    The page will show a Viewer URL (with a one-time token).
+//    On a machine without a browser, `share-screen sender -server http://host:8080`
+//    captures the screen with ffmpeg and prints the Viewer URL instead.
 // 3) On your iPhone: open the Viewer URL in Safari. Boom — mirrored.
+//    `share-screen view -token ... -out session.webm` records it instead,
+//    and `-play` shows it in ffplay.
+// 4) On a Linux server, `share-screen install-service` writes a systemd unit;
+//    on Windows, `share-screen service install` registers a service.
 //
 // Notes:
 // - Uses `getDisplayMedia` (you choose which screen/window to share).
 // - Codec is whatever Safari negotiates (H.264/VP8). No audio, just video.
-// - LAN only by default; NAT traversal via Google STUN for convenience.
+// - LAN only by default; NAT traversal via Google STUN for convenience, or the
+//   STUN/TURN servers in ICE_SERVERS, fetched by each page per session.
 // - Single viewer at a time per token. No persistence, no login, no tracking.
 // - This is intentionally bare-bones; tweak constraints or add PIN if needed.
 //
@@ -22,135 +30,1548 @@
 package main
 
 import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"share-screen/pkg/annotation"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/cluster"
 	"share-screen/pkg/infrastructure/config"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/pkg/infrastructure/jwt"
+	"share-screen/pkg/infrastructure/letsencrypt"
+	"share-screen/pkg/infrastructure/mdns"
+	"share-screen/pkg/infrastructure/mqtt"
 	"share-screen/pkg/infrastructure/network"
+	"share-screen/pkg/infrastructure/qrcode"
+	"share-screen/pkg/infrastructure/recording"
 	"share-screen/pkg/infrastructure/repository"
+	"share-screen/pkg/infrastructure/sfu"
+	"share-screen/pkg/infrastructure/snapshot"
+	"share-screen/pkg/infrastructure/systemd"
 	"share-screen/pkg/infrastructure/template"
+	"share-screen/pkg/infrastructure/tunnel"
+	"share-screen/pkg/infrastructure/turn"
+	"share-screen/pkg/infrastructure/webhook"
+	"share-screen/pkg/infrastructure/winsvc"
+	"share-screen/pkg/presentation/cli"
+	grpcserver "share-screen/pkg/presentation/grpc"
 	httphandlers "share-screen/pkg/presentation/http"
+	"share-screen/pkg/probe"
 	"share-screen/pkg/usecase/usecases"
 )
 
+// shutdownDrainPeriod is how long the server keeps answering with 503 before
+// it stops accepting connections, so polling clients learn it is going away
+const shutdownDrainPeriod = 2 * time.Second
+
+// limitCheckInterval is how often usage is compared with the soft limits; it
+// matches the sender heartbeat that reports bitrates
+const limitCheckInterval = 10 * time.Second
+
+// adaptationCheckInterval is how often the viewers' stats are checked for
+// constraints to suggest to their senders; pages upload stats every 5 seconds
+const adaptationCheckInterval = 10 * time.Second
+
+// publicIPRefreshInterval is how often the public IP is discovered again,
+// since home connections are often given a new one
+const publicIPRefreshInterval = 10 * time.Minute
+
+// recordingPruneInterval is how often recordings past their retention
+// period or beyond the disk limit are deleted
+const recordingPruneInterval = time.Minute
+
+// appVersion is reported by /api/v1/info and the OpenAPI document
+const appVersion = "1.0.0"
+
 func main() {
+	// Command line modes run instead of the server: `sender` shares this
+	// machine's screen, `view` records or plays a session, `discover` lists
+	// the servers on the LAN, `migrate` copies stored state between
+	// backends, `install-service` writes a systemd unit and `service`
+	// manages the Windows service
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "sender":
+			if err := runSender(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Sender failed: %v", err)
+			}
+			return
+		case "view":
+			if err := runViewer(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Viewer failed: %v", err)
+			}
+			return
+		case "discover":
+			if err := cli.RunDiscover(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("❌ Discovery failed: %v", err)
+			}
+			return
+		case "migrate":
+			if err := cli.RunMigrate(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("❌ Migration failed: %v", err)
+			}
+			return
+		case "install-service":
+			if err := cli.RunInstallService(os.Args[2:], os.Stdout); err != nil {
+				log.Fatalf("❌ Installing the service failed: %v", err)
+			}
+			return
+		case "service":
+			if err := runService(os.Args[2:]); err != nil {
+				log.Fatalf("❌ Service command failed: %v", err)
+			}
+			return
+		}
+	}
+
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Cancelled on SIGINT/SIGTERM to start graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serve(ctx, cfg)
+}
+
+// serve runs the server until ctx is cancelled and it has shut down
+func serve(ctx context.Context, cfg *config.Config) {
+	// A tunnel makes the server reachable from the internet; viewer links
+	// use its public URL
+	if cfg.Tunnel != "" {
+		publicURL, err := openTunnel(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Failed to open the tunnel: %v", err)
+		}
+		log.Printf("🚇 Tunnel open at %s", publicURL)
+		cfg.ExternalURL = strings.TrimSuffix(publicURL, "/")
+	}
+
 	// Initialize dependencies following Clean Architecture
 	dependencies := initializeDependencies(cfg)
 
-	// Setup routes
-	setupRoutes(dependencies.staticHandlers, dependencies.apiHandlers)
+	// Setup routes, wrapped in the configured middleware chain
+	mux := http.NewServeMux()
+	setupRoutes(mux, dependencies)
+	notifier := httphandlers.NewShutdownNotifier(mux)
+	handler, err := newMiddlewareChain(cfg, dependencies, notifier)
+	if err != nil {
+		log.Fatalf("Invalid MIDDLEWARE: %v", err)
+	}
 
 	// Start background services
-	startBackgroundServices(dependencies.sessionRepo, cfg.TokenExpiry)
+	var background sync.WaitGroup
+	startBackgroundServices(ctx, &background, dependencies, cfg.GCInterval, cfg.TokenExpiry, cfg.SnapshotInterval)
+	if dependencies.grpcServer != nil {
+		startGRPCServer(ctx, &background, cfg, dependencies.grpcServer)
+	}
+
+	// Start server and block until it has shut down
+	runServer(ctx, cfg, handler, notifier, dependencies.eventBroker.Close)
+
+	background.Wait()
+
+	// The last snapshot holds everything that changed while draining
+	if dependencies.snapshotUseCase != nil {
+		saveSnapshot(dependencies.snapshotUseCase)
+	}
+	if dependencies.mqttBus != nil {
+		if err := dependencies.mqttBus.Close(); err != nil {
+			log.Printf("❌ Error disconnecting from the MQTT broker: %v", err)
+		}
+	}
+	if dependencies.leaderElector != nil {
+		if err := dependencies.leaderElector.Close(); err != nil {
+			log.Printf("❌ Error leaving the cluster: %v", err)
+		}
+	}
+	if closer, ok := dependencies.sessionRepo.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("❌ Error closing session storage: %v", err)
+		}
+	}
+	log.Printf("👋 Shutdown complete")
+}
+
+// runService runs the `service` mode; `service run` is the command line the
+// Windows service control manager starts the server with
+func runService(args []string) error {
+	if len(args) == 0 || args[0] != "run" {
+		return cli.RunService(args, os.Stdout)
+	}
+
+	name, workDir, serverArgs, err := cli.ParseServiceRun(args[1:])
+	if err != nil {
+		return err
+	}
+	// Services start in the system directory; the templates and .env are
+	// in the install directory
+	if err := os.Chdir(workDir); err != nil {
+		return err
+	}
+	os.Args = append([]string{os.Args[0]}, serverArgs...)
+
+	eventLog, err := winsvc.OpenEventLog(name)
+	if err != nil {
+		return err
+	}
+	defer eventLog.Close()
+	log.SetOutput(eventLog)
+	log.SetFlags(0)
+
+	cfg := config.LoadConfig()
+	return winsvc.Run(name, shutdownDrainPeriod+cfg.ShutdownTimeout, func(ctx context.Context) {
+		serve(ctx, cfg)
+	})
+}
+
+// runSender runs the native sender until SIGINT/SIGTERM
+func runSender(args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cli.RunSender(ctx, args, os.Stdout)
+}
+
+// runViewer runs the native viewer until the session ends or SIGINT/SIGTERM
+func runViewer(args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Start server
-	startServer(cfg)
+	return cli.RunViewer(ctx, args, os.Stdout)
 }
 
 // Dependencies holds all application dependencies
 type Dependencies struct {
-	sessionRepo       *repository.MemorySessionRepository
-	networkService    *network.NetworkService
-	templateService   *template.TemplateService
-	sessionUseCase    *usecases.SessionUseCase
-	serverInfoUseCase *usecases.ServerInfoUseCase
-	staticHandlers    *httphandlers.StaticHandlers
-	apiHandlers       *httphandlers.APIHandlers
+	sessionRepo          interfaces.SessionRepository
+	leaderElector        interfaces.LeaderElector
+	historyRepo          *repository.MemorySessionHistoryRepository
+	settingsRepo         *repository.MemoryDeviceSettingsRepository
+	roomRepo             *repository.MemoryRoomRepository
+	fileRepo             *repository.MemoryFileRepository
+	statsRepo            *repository.MemoryStatsRepository
+	auditRepo            *repository.MemoryAuditLogRepository
+	networkService       *network.NetworkService
+	publicIPService      interfaces.PublicIPService
+	mdnsAdvertiser       *mdns.Advertiser
+	qrCodeService        *qrcode.QRCodeService
+	templateService      *template.TemplateService
+	eventBroker          *events.Broker
+	sessionUseCase       *usecases.SessionUseCase
+	serverInfoUseCase    *usecases.ServerInfoUseCase
+	queueUseCase         *usecases.ViewerQueueUseCase
+	historyUseCase       *usecases.SessionHistoryUseCase
+	settingsUseCase      *usecases.ViewerSettingsUseCase
+	handoutUseCase       *usecases.HandoutUseCase
+	inviteUseCase        *usecases.InviteUseCase
+	statusUseCase        *usecases.StatusUseCase
+	limitsUseCase        *usecases.LimitsUseCase
+	adaptationUseCase    *usecases.AdaptationUseCase
+	iceUseCase           *usecases.ICEConfigUseCase
+	capabilitiesUseCase  *usecases.CapabilitiesUseCase
+	presetUseCase        *usecases.PresetUseCase
+	roomUseCase          *usecases.RoomUseCase
+	viewerLinkUseCase    *usecases.ViewerLinkUseCase
+	chatUseCase          *usecases.ChatUseCase
+	negotiationUseCase   *usecases.NegotiationUseCase
+	keyExchangeUseCase   *usecases.KeyExchangeUseCase
+	fingerprintUseCase   *usecases.FingerprintUseCase
+	fileUseCase          *usecases.FileUseCase
+	statsUseCase         *usecases.StatsUseCase
+	auditUseCase         *usecases.AuditUseCase
+	cleanupUseCase       *usecases.CleanupUseCase
+	snapshotUseCase      *usecases.SnapshotUseCase
+	recordingJanitor     *usecases.RecordingUseCase
+	staticHandlers       *httphandlers.StaticHandlers
+	apiHandlers          *httphandlers.APIHandlers
+	queueHandlers        *httphandlers.QueueHandlers
+	eventHandlers        *httphandlers.EventHandlers
+	historyHandlers      *httphandlers.HistoryHandlers
+	settingsHandlers     *httphandlers.SettingsHandlers
+	openAPIHandlers      *httphandlers.OpenAPIHandlers
+	handoutHandlers      *httphandlers.HandoutHandlers
+	inviteHandlers       *httphandlers.InviteHandlers
+	statusHandlers       *httphandlers.StatusHandlers
+	metricsHandlers      *httphandlers.MetricsHandlers
+	iceHandlers          *httphandlers.ICEHandlers
+	capabilitiesHandlers *httphandlers.CapabilitiesHandlers
+	presetHandlers       *httphandlers.PresetHandlers
+	annotationHandlers   *httphandlers.AnnotationHandlers
+	probeHandlers        *httphandlers.ProbeHandlers
+	roomHandlers         *httphandlers.RoomHandlers
+	viewerLinkHandlers   *httphandlers.ViewerLinkHandlers
+	chatHandlers         *httphandlers.ChatHandlers
+	negotiationHandlers  *httphandlers.NegotiationHandlers
+	keyExchangeHandlers  *httphandlers.KeyExchangeHandlers
+	fingerprintHandlers  *httphandlers.FingerprintHandlers
+	whipHandlers         *httphandlers.WHIPHandlers
+	hlsHandlers          *httphandlers.HLSHandlers
+	mjpegHandlers        *httphandlers.MJPEGHandlers
+	recordingHandlers    *httphandlers.RecordingHandlers
+	templateHandlers     *httphandlers.SessionTemplateHandlers
+	profileHandlers      *httphandlers.SenderProfileHandlers
+	pairingHandlers      *httphandlers.DevicePairingHandlers
+	fileHandlers         *httphandlers.FileHandlers
+	statsHandlers        *httphandlers.StatsHandlers
+	adminHandlers        *httphandlers.AdminHandlers
+	networkPolicy        *httphandlers.NetworkPolicy
+	banFilter            *httphandlers.BanFilter
+	tokenFilter          *httphandlers.TokenFilter
+	basicAuth            *httphandlers.BasicAuth
+	jwtAuth              *httphandlers.JWTAuth
+	grpcServer           *grpc.Server
+	mqttBridge           *usecases.MQTTBridgeUseCase
+	mqttBus              interfaces.MessageBus
+	// staticDir serves the files under /static/ that are not hashed assets
+	staticDir string
 }
 
 // initializeDependencies sets up dependency injection following Clean Architecture
 func initializeDependencies(cfg *config.Config) *Dependencies {
 	// Infrastructure Layer
-	sessionRepo := repository.NewMemorySessionRepository().(*repository.MemorySessionRepository)
-	networkService := network.NewNetworkService().(*network.NetworkService)
+	tokenPolicy, err := entities.ParseTokenPolicy(cfg.TokenFormat, cfg.TokenBytes, cfg.TokenSecret, cfg.TokenLifetime)
+	if err != nil {
+		log.Fatalf("Invalid session token settings: %v", err)
+	}
+	sessionRepo, err := newSessionRepository(cfg, tokenPolicy)
+	if err != nil {
+		log.Fatalf("Failed to open session storage: %v", err)
+	}
+	historyRepo := repository.NewMemorySessionHistoryRepository().(*repository.MemorySessionHistoryRepository)
+	settingsRepo := repository.NewMemoryDeviceSettingsRepository().(*repository.MemoryDeviceSettingsRepository)
+	roomRepo := repository.NewMemoryRoomRepository().(*repository.MemoryRoomRepository)
+	fileRepo := repository.NewMemoryFileRepository().(*repository.MemoryFileRepository)
+	statsRepo := repository.NewMemoryStatsRepository().(*repository.MemoryStatsRepository)
+	auditRepo := repository.NewMemoryAuditLogRepository().(*repository.MemoryAuditLogRepository)
+	banRepo, err := repository.NewFileBanRepository(cfg.BanFile)
+	if err != nil {
+		log.Fatalf("Failed to load ban list: %v", err)
+	}
+	templateRepo, err := repository.NewFileSessionTemplateRepository(cfg.TemplatesFile)
+	if err != nil {
+		log.Fatalf("Failed to load session templates: %v", err)
+	}
+	deviceRepo, err := repository.NewFileTrustedDeviceRepository(cfg.DevicesFile)
+	if err != nil {
+		log.Fatalf("Failed to load trusted devices: %v", err)
+	}
+	recordingRepo := newRecordingRepository(cfg)
+	profileRepo, profileCookies := newSenderProfiles(cfg)
+	networkService := network.NewNetworkService(cfg.Interface).(*network.NetworkService)
+	qrCodeService := qrcode.NewQRCodeService().(*qrcode.QRCodeService)
+	eventPolicy, err := events.ParsePolicy(cfg.EventPolicy)
+	if err != nil {
+		log.Fatalf("Invalid event policy: %v", err)
+	}
+	eventBroker := events.NewBroker(cfg.EventBuffer, eventPolicy).(*events.Broker)
+	turnCredentials := newTURNCredentialService(cfg)
+	iceServers, err := config.ParseICEServers(cfg.ICEServers, cfg.STUNServer, turnCredentials != nil)
+	if err != nil {
+		log.Fatalf("Invalid ICE servers: %v", err)
+	}
+	logICEServers(iceServers)
+	if cfg.Interface != "" && !slices.ContainsFunc(networkService.GetInterfaces(), func(iface entities.NetworkInterface) bool {
+		return iface.Name == cfg.Interface
+	}) {
+		log.Printf("⚠️  Interface %s has no usable address, links use the first address of any interface", cfg.Interface)
+	}
+	if cfg.ExternalURL != "" {
+		if u, err := url.Parse(cfg.ExternalURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Fatalf("Invalid external URL %q: expected e.g. https://share.example.com:8443", cfg.ExternalURL)
+		}
+	}
+	publicIPService, err := newPublicIPService(cfg, iceServers)
+	if err != nil {
+		log.Fatalf("Invalid public IP discovery: %v", err)
+	}
+	inviteNotifiers, err := newInviteNotifiers(cfg)
+	if err != nil {
+		log.Fatalf("Invalid chat webhook: %v", err)
+	}
+
+	videoCodec, err := entities.ParseVideoCodec(cfg.VideoCodec)
+	if err != nil {
+		log.Fatalf("Invalid video codec: %v", err)
+	}
+	codec := entities.CodecPreference{Codec: videoCodec, Force: cfg.ForceVideoCodec}
+	if cfg.MaxFrameRate < 0 || cfg.MaxFrameRate > usecases.MaxFrameRateCap {
+		log.Fatalf("Invalid frame rate cap %d: expected 0 to %d fps", cfg.MaxFrameRate, usecases.MaxFrameRateCap)
+	}
+	if cfg.GCInterval <= 0 {
+		log.Fatalf("Invalid GC interval: %v", cfg.GCInterval)
+	}
 
-	templateService, err := template.NewTemplateService("web/templates", cfg.STUNServer)
+	templatesDir := filepath.Join(cfg.WebDir, "templates")
+	templateService, err := template.NewTemplateService(templatesDir)
 	if err != nil {
-		log.Fatalf("Failed to initialize template service: %v", err)
+		log.Fatalf("Failed to initialize template service from %s: %v", templatesDir, err)
+	}
+	templateService.AddAsset("/static/css/style.css", filepath.Join(cfg.WebDir, "static", "css", "style.css"))
+	branding := template.Branding{Name: cfg.BrandName, Logo: cfg.BrandLogo, Accent: cfg.BrandAccent, Footer: cfg.BrandFooter}
+	if err := templateService.SetBranding(branding); err != nil {
+		log.Fatalf("Invalid branding: %v", err)
+	}
+	if cfg.DevMode {
+		templateService.ReloadOnChange()
+		log.Printf("🛠️  Dev mode: page templates in %s are parsed again when they change", templatesDir)
 	}
 
 	// Use Case Layer
-	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, cfg.TokenExpiry)
-	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, cfg.STUNServer, "1.0.0")
+	// The aggregator counts the lifecycle events on their way to the audit log
+	statsAggregator := usecases.NewStatsAggregator(auditRepo, sessionRepo)
+	streamRelay := newStreamRelay(cfg, iceServers)
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, statsAggregator, templateRepo, eventBroker, streamRelay, cfg.TokenExpiry, usecases.SessionDefaults{
+		HeartbeatTimeout: cfg.HeartbeatTimeout,
+		IdleTimeout:      cfg.IdleTimeout,
+		MaxBitrateKbps:   cfg.MaxBitrateKbps,
+		MaxFrameRate:     cfg.MaxFrameRate,
+		Codec:            codec,
+		MaxSessions:      cfg.SessionLimit,
+	})
+	iceUseCase := usecases.NewICEConfigUseCase(sessionRepo, historyRepo, iceServers, turnCredentials)
+	fileRelayLimit := int64(cfg.FileRelayMB) << 20
+	capabilitiesUseCase := usecases.NewCapabilitiesUseCase(iceServers, cfg.StatusToken != "", fileRelayLimit > 0, cfg.SFU, cfg.HLSDir != "", len(inviteNotifiers) > 0, profileRepo != nil)
+	presetUseCase := usecases.NewPresetUseCase()
+	roomUseCase := usecases.NewRoomUseCase(roomRepo, sessionRepo, historyRepo)
+	viewerLinkUseCase := usecases.NewViewerLinkUseCase(sessionRepo, historyRepo)
+	chatUseCase := usecases.NewChatUseCase(sessionRepo, historyRepo, eventBroker)
+	negotiationUseCase := usecases.NewNegotiationUseCase(sessionRepo, historyRepo, eventBroker)
+	keyExchangeUseCase := usecases.NewKeyExchangeUseCase(sessionRepo, historyRepo, eventBroker)
+	fingerprintUseCase := usecases.NewFingerprintUseCase(sessionRepo, historyRepo)
+	frameUseCase := usecases.NewFrameUseCase(sessionRepo, historyRepo, streamRelay)
+	fileUseCase := usecases.NewFileUseCase(fileRepo, sessionRepo, historyRepo, eventBroker, fileRelayLimit)
+	statsUseCase := usecases.NewStatsUseCase(statsRepo, sessionRepo, historyRepo, statsAggregator)
+	auditUseCase := usecases.NewAuditUseCase(auditRepo)
+	leaderElector, err := newLeaderElector(cfg)
+	if err != nil {
+		log.Fatalf("Invalid cluster configuration: %v", err)
+	}
+	cleanupUseCase := usecases.NewCleanupUseCase(sessionUseCase, fileUseCase, statsUseCase, cfg.GCInterval, leaderElector)
+	banUseCase, err := usecases.NewBanUseCase(banRepo)
+	if err != nil {
+		log.Fatalf("Invalid ban list: %v", err)
+	}
+	snapshotUseCase := newSnapshotUseCase(cfg, sessionRepo, historyRepo, settingsRepo)
+	templateUseCase := usecases.NewSessionTemplateUseCase(templateRepo)
+	profileUseCase := usecases.NewSenderProfileUseCase(profileRepo)
+	pairingUseCase := usecases.NewDevicePairingUseCase(sessionRepo, historyRepo, deviceRepo)
+	recordingUseCase := usecases.NewRecordingUseCase(recordingRepo, int64(cfg.RecordingsMaxMB)<<20, cfg.RecordingsRetention)
+	// The janitor only runs where recordings are kept
+	var recordingJanitor *usecases.RecordingUseCase
+	if recordingRepo != nil {
+		recordingJanitor = recordingUseCase
+	}
+	// Links handed to viewers use the certificate's domain when Let's Encrypt
+	// issued it, since the LAN IP would fail validation
+	publicHost := ""
+	if cfg.HTTPSAuto && len(cfg.AutocertDomains) > 0 {
+		publicHost = cfg.AutocertDomains[0]
+	}
+	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, publicIPService, entities.STUNURL(iceServers), appVersion, publicHost, cfg.ExternalURL, cleanupUseCase)
+	queueUseCase := usecases.NewViewerQueueUseCase(sessionRepo, historyRepo, eventBroker)
+	historyUseCase := usecases.NewSessionHistoryUseCase(historyRepo)
+	settingsUseCase := usecases.NewViewerSettingsUseCase(settingsRepo, sessionRepo, historyRepo, eventBroker)
+	handoutUseCase := usecases.NewHandoutUseCase(sessionRepo, historyRepo, networkService, qrCodeService, publicHost, cfg.ExternalURL)
+	inviteUseCase := usecases.NewInviteUseCase(sessionRepo, historyRepo, inviteNotifiers, networkService, publicHost, cfg.ExternalURL)
+	statusUseCase := usecases.NewStatusUseCase(sessionRepo)
+	limitsUseCase := usecases.NewLimitsUseCase(sessionRepo, eventBroker, cfg.MaxSessions, int64(cfg.MaxBandwidthMbps)*1_000_000, cfg.LimitWarningPercent)
+	adaptationUseCase := usecases.NewAdaptationUseCase(sessionRepo, statsRepo, eventBroker)
 
 	// Presentation Layer
-	staticHandlers := httphandlers.NewStaticHandlers(templateService)
-	apiHandlers := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase)
+	staticHandlers := httphandlers.NewStaticHandlers(templateService, sessionUseCase, settingsUseCase, cfg.LinkPreview)
+	tokenFilter := httphandlers.NewTokenFilter(tokenPolicy)
+	apiHandlers := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase, tokenFilter)
+	whipHandlers := httphandlers.NewWHIPHandlers(sessionUseCase, tokenFilter)
+	hlsHandlers := httphandlers.NewHLSHandlers(sessionUseCase, cfg.HLSDir)
+	mjpegHandlers := httphandlers.NewMJPEGHandlers(frameUseCase)
+	queueHandlers := httphandlers.NewQueueHandlers(queueUseCase)
+	eventHandlers := httphandlers.NewEventHandlers(eventBroker, queueUseCase)
+	historyHandlers := httphandlers.NewHistoryHandlers(historyUseCase)
+	settingsHandlers := httphandlers.NewSettingsHandlers(settingsUseCase)
+	openAPIHandlers := httphandlers.NewOpenAPIHandlers(appVersion)
+	handoutHandlers := httphandlers.NewHandoutHandlers(templateService, handoutUseCase)
+	inviteHandlers := httphandlers.NewInviteHandlers(inviteUseCase)
+	statusHandlers := httphandlers.NewStatusHandlers(statusUseCase, cfg.StatusToken)
+	metricsHandlers := httphandlers.NewMetricsHandlers(eventBroker)
+	iceHandlers := httphandlers.NewICEHandlers(iceUseCase)
+	capabilitiesHandlers := httphandlers.NewCapabilitiesHandlers(capabilitiesUseCase)
+	presetHandlers := httphandlers.NewPresetHandlers(presetUseCase)
+	annotationHandlers := httphandlers.NewAnnotationHandlers(annotation.DefaultSchema())
+	probeHandlers := httphandlers.NewProbeHandlers(probe.DefaultSchema(), statsUseCase)
+	roomHandlers := httphandlers.NewRoomHandlers(roomUseCase)
+	viewerLinkHandlers := httphandlers.NewViewerLinkHandlers(viewerLinkUseCase)
+	chatHandlers := httphandlers.NewChatHandlers(chatUseCase)
+	negotiationHandlers := httphandlers.NewNegotiationHandlers(negotiationUseCase)
+	keyExchangeHandlers := httphandlers.NewKeyExchangeHandlers(keyExchangeUseCase)
+	fingerprintHandlers := httphandlers.NewFingerprintHandlers(fingerprintUseCase)
+	fileHandlers := httphandlers.NewFileHandlers(fileUseCase, fileRelayLimit)
+	tokenVerifier, err := newTokenVerifier(cfg)
+	if err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
+	jwtAuth := httphandlers.NewJWTAuth(tokenVerifier)
+	adminHandlers := httphandlers.NewAdminHandlers(sessionUseCase, statsUseCase, auditUseCase, cleanupUseCase, banUseCase, eventBroker, cfg.AdminToken, jwtAuth)
+	statsHandlers := httphandlers.NewStatsHandlers(statsUseCase, adminHandlers)
+	recordingHandlers := httphandlers.NewRecordingHandlers(recordingUseCase, adminHandlers)
+	templateHandlers := httphandlers.NewSessionTemplateHandlers(templateUseCase, adminHandlers)
+	profileHandlers := httphandlers.NewSenderProfileHandlers(profileUseCase, profileCookies)
+	pairingHandlers := httphandlers.NewDevicePairingHandlers(pairingUseCase)
+	networkPolicy, err := httphandlers.NewNetworkPolicy(cfg.AllowedNetworks)
+	if err != nil {
+		log.Fatalf("Invalid allowed networks: %v", err)
+	}
+	banFilter := httphandlers.NewBanFilter(banUseCase)
+	basicAuth, err := httphandlers.NewBasicAuth(cfg.AuthUser, cfg.AuthPassword)
+	if err != nil {
+		log.Fatalf("Invalid basic auth: %v", err)
+	}
+	grpcServer, err := newGRPCServer(cfg, sessionUseCase, networkPolicy, banUseCase, tokenPolicy, tokenVerifier, basicAuth)
+	if err != nil {
+		log.Fatalf("Invalid gRPC configuration: %v", err)
+	}
+	mqttBridge, mqttBus, err := newMQTTBridge(cfg, eventBroker, sessionRepo, sessionUseCase, tokenPolicy)
+	if err != nil {
+		log.Fatalf("MQTT bridge failed to start: %v", err)
+	}
 
 	return &Dependencies{
-		sessionRepo:       sessionRepo,
-		networkService:    networkService,
-		templateService:   templateService,
-		sessionUseCase:    sessionUseCase,
-		serverInfoUseCase: serverInfoUseCase,
-		staticHandlers:    staticHandlers,
-		apiHandlers:       apiHandlers,
+		sessionRepo:          sessionRepo,
+		leaderElector:        leaderElector,
+		historyRepo:          historyRepo,
+		settingsRepo:         settingsRepo,
+		roomRepo:             roomRepo,
+		fileRepo:             fileRepo,
+		statsRepo:            statsRepo,
+		auditRepo:            auditRepo,
+		networkService:       networkService,
+		publicIPService:      publicIPService,
+		mdnsAdvertiser:       newMDNSAdvertiser(cfg, networkService),
+		qrCodeService:        qrCodeService,
+		templateService:      templateService,
+		staticDir:            filepath.Join(cfg.WebDir, "static"),
+		eventBroker:          eventBroker,
+		sessionUseCase:       sessionUseCase,
+		serverInfoUseCase:    serverInfoUseCase,
+		queueUseCase:         queueUseCase,
+		historyUseCase:       historyUseCase,
+		settingsUseCase:      settingsUseCase,
+		handoutUseCase:       handoutUseCase,
+		inviteUseCase:        inviteUseCase,
+		statusUseCase:        statusUseCase,
+		limitsUseCase:        limitsUseCase,
+		adaptationUseCase:    adaptationUseCase,
+		iceUseCase:           iceUseCase,
+		capabilitiesUseCase:  capabilitiesUseCase,
+		presetUseCase:        presetUseCase,
+		roomUseCase:          roomUseCase,
+		viewerLinkUseCase:    viewerLinkUseCase,
+		chatUseCase:          chatUseCase,
+		negotiationUseCase:   negotiationUseCase,
+		keyExchangeUseCase:   keyExchangeUseCase,
+		fingerprintUseCase:   fingerprintUseCase,
+		fileUseCase:          fileUseCase,
+		statsUseCase:         statsUseCase,
+		auditUseCase:         auditUseCase,
+		cleanupUseCase:       cleanupUseCase,
+		snapshotUseCase:      snapshotUseCase,
+		recordingJanitor:     recordingJanitor,
+		staticHandlers:       staticHandlers,
+		apiHandlers:          apiHandlers,
+		queueHandlers:        queueHandlers,
+		eventHandlers:        eventHandlers,
+		historyHandlers:      historyHandlers,
+		settingsHandlers:     settingsHandlers,
+		openAPIHandlers:      openAPIHandlers,
+		handoutHandlers:      handoutHandlers,
+		inviteHandlers:       inviteHandlers,
+		statusHandlers:       statusHandlers,
+		metricsHandlers:      metricsHandlers,
+		iceHandlers:          iceHandlers,
+		capabilitiesHandlers: capabilitiesHandlers,
+		presetHandlers:       presetHandlers,
+		annotationHandlers:   annotationHandlers,
+		probeHandlers:        probeHandlers,
+		roomHandlers:         roomHandlers,
+		viewerLinkHandlers:   viewerLinkHandlers,
+		chatHandlers:         chatHandlers,
+		negotiationHandlers:  negotiationHandlers,
+		keyExchangeHandlers:  keyExchangeHandlers,
+		fingerprintHandlers:  fingerprintHandlers,
+		whipHandlers:         whipHandlers,
+		hlsHandlers:          hlsHandlers,
+		mjpegHandlers:        mjpegHandlers,
+		recordingHandlers:    recordingHandlers,
+		templateHandlers:     templateHandlers,
+		profileHandlers:      profileHandlers,
+		pairingHandlers:      pairingHandlers,
+		fileHandlers:         fileHandlers,
+		statsHandlers:        statsHandlers,
+		adminHandlers:        adminHandlers,
+		networkPolicy:        networkPolicy,
+		banFilter:            banFilter,
+		tokenFilter:          tokenFilter,
+		basicAuth:            basicAuth,
+		jwtAuth:              jwtAuth,
+		grpcServer:           grpcServer,
+		mqttBridge:           mqttBridge,
+		mqttBus:              mqttBus,
+	}
+}
+
+// newSessionRepository returns the session storage of the configured backend
+func newSessionRepository(cfg *config.Config, tokenPolicy entities.TokenPolicy) (interfaces.SessionRepository, error) {
+	switch strings.ToLower(cfg.StorageBackend) {
+	case "", "memory":
+		return repository.NewMemorySessionRepository(tokenPolicy), nil
+	case "bolt", "bbolt":
+		sessionRepo, err := repository.NewBoltSessionRepository(cfg.BoltPath, tokenPolicy)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("💾 Storing sessions in %s", cfg.BoltPath)
+		return sessionRepo, nil
+	case "postgres", "postgresql":
+		if cfg.PostgresDSN == "" {
+			return nil, errors.New("the postgres backend needs POSTGRES_DSN")
+		}
+		sessionRepo, err := repository.NewPostgresSessionRepository(cfg.PostgresDSN, cfg.PostgresMaxConns, tokenPolicy)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("💾 Storing sessions in Postgres (up to %d connections)", cfg.PostgresMaxConns)
+		return sessionRepo, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q: expected memory, bolt or postgres", cfg.StorageBackend)
+	}
+}
+
+// newSenderProfiles returns the sender profiles kept in PROFILES_FILE and the
+// cookies naming them, or nil for both when PROFILE_SECRET is not set
+func newSenderProfiles(cfg *config.Config) (interfaces.SenderProfileRepository, *httphandlers.ProfileCookies) {
+	switch n := len(cfg.ProfileSecret); {
+	case n == 0:
+		return nil, nil
+	case n < entities.MinTokenSecretLength:
+		log.Fatalf("Invalid profile secret: it must be at least %d characters", entities.MinTokenSecretLength)
+	}
+	profileRepo, err := repository.NewFileSenderProfileRepository(cfg.ProfilesFile)
+	if err != nil {
+		log.Fatalf("Failed to load sender profiles: %v", err)
+	}
+	return profileRepo, httphandlers.NewProfileCookies(cfg.ProfileSecret)
+}
+
+// newRecordingRepository returns the recordings in RECORDINGS_DIR, creating
+// the directory, or nil when recordings are disabled
+func newRecordingRepository(cfg *config.Config) interfaces.RecordingRepository {
+	if cfg.RecordingsDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(cfg.RecordingsDir, 0o755); err != nil {
+		log.Fatalf("Failed to set up recordings: %v", err)
+	}
+	return repository.NewFileRecordingRepository(cfg.RecordingsDir)
+}
+
+// newLeaderElector returns the leader election of a cluster, which shares
+// its sessions through Postgres, and nil for a single server
+func newLeaderElector(cfg *config.Config) (interfaces.LeaderElector, error) {
+	if !cfg.Cluster {
+		return nil, nil
+	}
+	if backend := strings.ToLower(cfg.StorageBackend); backend != "postgres" && backend != "postgresql" {
+		return nil, errors.New("clustering needs STORAGE_BACKEND=postgres, so the instances share their sessions")
+	}
+
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("naming the instance: %w", err)
+		}
+		instanceID = hostname
+	}
+	log.Printf("🕸️  Running as cluster instance %s", instanceID)
+	return cluster.NewPostgresElector(cfg.PostgresDSN, instanceID), nil
+}
+
+// newGRPCServer returns the gRPC signaling API when GRPC_ADDR is set, under the
+// access rules of the HTTP signaling endpoints, and nil otherwise
+func newGRPCServer(cfg *config.Config, sessionUseCase interfaces.SessionUseCase, networkPolicy *httphandlers.NetworkPolicy, banUseCase interfaces.BanUseCase, tokenPolicy entities.TokenPolicy, tokenVerifier interfaces.TokenVerifier, basicAuth *httphandlers.BasicAuth) (*grpc.Server, error) {
+	if cfg.GRPCAddr == "" {
+		return nil, nil
+	}
+
+	var options []grpc.ServerOption
+	if cfg.HTTPSAuto {
+		return nil, errors.New("the gRPC API needs CERT_FILE and KEY_FILE; Let's Encrypt certificates are not supported for it")
+	}
+	if cfg.EnableHTTPS {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+
+	// Creating sessions needs the same credentials as /api/new
+	var authenticator grpcserver.Authenticator
+	switch {
+	case tokenVerifier != nil:
+		authenticator = grpcserver.BearerAuthenticator(tokenVerifier)
+	case basicAuth.Enabled():
+		authenticator = grpcserver.BasicAuthenticator(basicAuth.Check)
+	}
+
+	access := grpcserver.NewAccess(networkPolicy, banUseCase, tokenPolicy, authenticator)
+	return grpcserver.NewServer(grpcserver.NewSignalingServer(sessionUseCase), access, options...), nil
+}
+
+// newMQTTBridge returns the MQTT signaling bridge, connected to its broker,
+// when MQTT_BROKER is set, and nil otherwise
+func newMQTTBridge(cfg *config.Config, subscriber interfaces.EventSubscriber, sessionRepo interfaces.SessionRepository, sessionUseCase interfaces.SessionUseCase, tokenPolicy entities.TokenPolicy) (*usecases.MQTTBridgeUseCase, interfaces.MessageBus, error) {
+	if cfg.MQTTBroker == "" {
+		return nil, nil, nil
+	}
+
+	// Client IDs must be unique on the broker, even within a cluster
+	instanceID := cfg.InstanceID
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "server"
+		}
+		instanceID = hostname
+	}
+	bus, err := mqtt.NewClient(cfg.MQTTBroker, "share-screen-"+instanceID, cfg.MQTTUsername, cfg.MQTTPassword)
+	if err != nil {
+		return nil, nil, err
+	}
+	if scheme, _, _ := strings.Cut(cfg.MQTTBroker, "://"); scheme == "tcp" || scheme == "mqtt" || scheme == "ws" {
+		log.Printf("⚠️  MQTT bridge running without TLS - consider an ssl:// broker URL for production")
+	}
+	log.Printf("📡 Connected to MQTT broker %s", cfg.MQTTBroker)
+	return usecases.NewMQTTBridgeUseCase(bus, subscriber, sessionRepo, sessionUseCase, tokenPolicy, cfg.MQTTPrefix, cfg.Cluster), bus, nil
+}
+
+// startGRPCServer serves the gRPC signaling API until ctx is cancelled, then
+// lets running calls finish within the shutdown timeout
+func startGRPCServer(ctx context.Context, wg *sync.WaitGroup, cfg *config.Config, server *grpc.Server) {
+	ln, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatalf("gRPC server failed to start: %v", err)
+	}
+	log.Printf("gRPC signaling API listening on %s", ln.Addr())
+	if !cfg.EnableHTTPS {
+		log.Printf("⚠️  gRPC signaling API running without TLS - consider enabling HTTPS for production")
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := server.Serve(ln); err != nil {
+			log.Printf("❌ gRPC server failed: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(cfg.ShutdownTimeout):
+			log.Printf("❌ gRPC graceful shutdown incomplete")
+			server.Stop()
+		}
+	}()
+}
+
+// newSnapshotUseCase returns the snapshotting of the in-memory state when a
+// snapshot file is configured, with the state of the last run restored, and
+// nil otherwise
+func newSnapshotUseCase(cfg *config.Config, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, settingsRepo interfaces.DeviceSettingsRepository) *usecases.SnapshotUseCase {
+	if cfg.SnapshotFile == "" {
+		return nil
+	}
+	if cfg.SnapshotInterval <= 0 {
+		log.Fatalf("Invalid snapshot interval: %v", cfg.SnapshotInterval)
+	}
+
+	snapshotUseCase := usecases.NewSnapshotUseCase(snapshot.NewFileStore(cfg.SnapshotFile), sessionRepo, historyRepo, settingsRepo)
+	restored, err := snapshotUseCase.RestoreSnapshot()
+	if err != nil {
+		log.Fatalf("Failed to restore the snapshot: %v", err)
+	}
+	log.Printf("💾 Restored %d sessions from %s (saved every %v)", restored, snapshotUseCase.Location(), cfg.SnapshotInterval)
+	return snapshotUseCase
+}
+
+// saveSnapshot saves the in-memory state, logging failures
+func saveSnapshot(snapshotUseCase *usecases.SnapshotUseCase) {
+	if _, err := snapshotUseCase.SaveSnapshot(); err != nil {
+		log.Printf("❌ Error saving the snapshot: %v", err)
+	}
+}
+
+// logICEServers lists the configured STUN/TURN URLs without their credentials
+func logICEServers(servers []entities.ICEServer) {
+	var urls []string
+	for _, server := range servers {
+		for _, url := range server.URLs {
+			if server.NeedsCredentials() {
+				url += " (minted credentials)"
+			}
+			urls = append(urls, url)
+		}
+	}
+	if len(urls) == 0 {
+		log.Printf("ICE Servers: none (LAN only)")
+		return
+	}
+	log.Printf("ICE Servers: %s", strings.Join(urls, ", "))
+}
+
+// newTURNCredentialService returns the minter for TURN credentials when a
+// secret is shared with the TURN server, and nil otherwise
+func newTURNCredentialService(cfg *config.Config) interfaces.TURNCredentialService {
+	if len(cfg.TURNSecret) == 0 {
+		return nil
 	}
+	return turn.NewCredentialService(cfg.TURNSecret, cfg.TURNCredentialTTL)
 }
 
-// startBackgroundServices starts background processes like garbage collection
-func startBackgroundServices(sessionRepo *repository.MemorySessionRepository, tokenExpiry time.Duration) {
+// minJWTSecretLength is the shortest HS256 secret accepted, the size of the
+// SHA-256 digest as RFC 7518 asks
+const minJWTSecretLength = 32
+
+// newTokenVerifier returns the verifier of API JWTs when a secret or public
+// key is configured, and nil otherwise
+func newTokenVerifier(cfg *config.Config) (interfaces.TokenVerifier, error) {
+	if !cfg.JWTEnabled() {
+		return nil, nil
+	}
+	if n := len(cfg.JWTSecret); n > 0 && n < minJWTSecretLength {
+		return nil, fmt.Errorf("the JWT secret must be at least %d bytes", minJWTSecretLength)
+	}
+
+	var publicKey *rsa.PublicKey
+	if cfg.JWTPublicKeyFile != "" {
+		var err error
+		if publicKey, err = jwt.LoadPublicKey(cfg.JWTPublicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+	return jwt.NewVerifier([]byte(cfg.JWTSecret), publicKey, cfg.JWTAudience), nil
+}
+
+// newPublicIPService returns the discovery of the server's public IP through
+// the first STUN server when enabled, and nil otherwise
+func newPublicIPService(cfg *config.Config, iceServers []entities.ICEServer) (interfaces.PublicIPService, error) {
+	if !cfg.DiscoverPublicIP {
+		return nil, nil
+	}
+	stunURL := entities.STUNURL(iceServers)
+	if stunURL == "" {
+		return nil, errors.New("discovering the public IP needs a STUN server")
+	}
+	return network.NewSTUNDiscovery(stunURL)
+}
+
+// newInviteNotifiers creates a notifier for each configured chat webhook;
+// none leaves invites disabled
+func newInviteNotifiers(cfg *config.Config) ([]interfaces.InviteNotifier, error) {
+	var notifiers []interfaces.InviteNotifier
+	if cfg.SlackWebhookURL != "" {
+		notifier, err := webhook.NewSlackNotifier(cfg.SlackWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if cfg.DiscordWebhookURL != "" {
+		notifier, err := webhook.NewDiscordNotifier(cfg.DiscordWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	return notifiers, nil
+}
+
+// openTunnel opens the configured tunnel to the first listen address and
+// returns its public URL; it closes when ctx is cancelled
+func openTunnel(ctx context.Context, cfg *config.Config) (string, error) {
+	// Tunneled connections arrive from this process, so any local address
+	// the server listens on will do
+	localAddress := cfg.ListenAddresses()[0]
+	if host, port, err := net.SplitHostPort(localAddress); err == nil {
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			localAddress = net.JoinHostPort("127.0.0.1", port)
+		}
+	}
+	if cfg.EnableHTTPS {
+		log.Printf("⚠️  The tunnel forwards HTTPS as is; providers expecting plain HTTP need ENABLE_HTTPS=false")
+	}
+
+	provider, err := tunnel.New(tunnel.Config{
+		Spec:           cfg.Tunnel,
+		LocalAddress:   localAddress,
+		RemoteAddress:  cfg.TunnelRemote,
+		PublicURL:      cfg.TunnelURL,
+		KeyFile:        cfg.TunnelKeyFile,
+		KnownHostsFile: cfg.TunnelKnownHosts,
+	})
+	if err != nil {
+		return "", err
+	}
+	return provider.Open(ctx)
+}
+
+// newMiddlewareChain wraps handler in the middlewares listed in MIDDLEWARE;
+// the shutdown notifier always runs, so draining works whatever the chain
+func newMiddlewareChain(cfg *config.Config, deps *Dependencies, handler http.Handler) (http.Handler, error) {
+	registry := httphandlers.NewMiddlewareRegistry()
+	requestLogger := newRequestLogger(cfg.RequestLog)
+	registry.Register("logging", func(next http.Handler) http.Handler {
+		return httphandlers.NewRequestLogger(requestLogger, next)
+	})
+	registry.Register("security-headers", httphandlers.SecurityHeaders)
+	registry.Register("cors", func(next http.Handler) http.Handler {
+		return httphandlers.NewCORS(cfg.CORSOrigins, next)
+	})
+	registry.Register("compress", httphandlers.Compress)
+	registry.Register("ratelimit", func(next http.Handler) http.Handler {
+		return httphandlers.NewRateLimiter(float64(cfg.RateLimit), cfg.RateLimitBurst).Wrap(next)
+	})
+	// Unlike the per-route basic auth, this puts every page and endpoint,
+	// viewers included, behind the login
+	registry.Register("auth", func(next http.Handler) http.Handler {
+		return deps.basicAuth.Wrap(next.ServeHTTP)
+	})
+
+	if slices.Contains(cfg.Middleware, "ratelimit") && cfg.RateLimit <= 0 {
+		return nil, fmt.Errorf("ratelimit needs a positive RATE_LIMIT, got %d", cfg.RateLimit)
+	}
+	if slices.Contains(cfg.Middleware, "auth") && !deps.basicAuth.Enabled() {
+		log.Printf("⚠️  The auth middleware has no AUTH_USER and lets every request through")
+	}
+	return registry.Chain(cfg.Middleware, handler)
+}
+
+// newRequestLogger returns the logger of the per-request lines in format, or
+// nil when they are off; both formats follow the log output, so a Windows
+// service sends them to the event log
+func newRequestLogger(format string) *slog.Logger {
+	switch format {
+	case "off":
+		return nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(log.Writer(), nil))
+	case "text":
+	default:
+		log.Printf("⚠️  Unknown REQUEST_LOG format %q, using text", format)
+	}
+	return slog.Default()
+}
+
+// newMDNSAdvertiser returns the advertiser that lets `share-screen discover`
+// find the server, or nil when advertising is off
+func newMDNSAdvertiser(cfg *config.Config, networkService interfaces.NetworkService) *mdns.Advertiser {
+	if !cfg.MDNS {
+		return nil
+	}
+	port, err := strconv.Atoi(cfg.Port)
+	if err != nil {
+		log.Fatalf("Invalid port for mDNS: %v", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "share-screen"
+	}
+	scheme := "http"
+	if cfg.EnableHTTPS {
+		scheme = "https"
+	}
+	return mdns.NewAdvertiser(hostname, port, map[string]string{"scheme": scheme, "version": appVersion}, networkService.GetLANIPs)
+}
+
+// newStreamRelay returns the SFU, or nil when the server runs without one
+func newStreamRelay(cfg *config.Config, iceServers []entities.ICEServer) interfaces.StreamRelay {
+	if !cfg.SFU {
+		if cfg.RTMPURL != "" {
+			log.Printf("⚠️  RTMP_URL ignored: restreaming needs the SFU (SFU=true)")
+		}
+		if cfg.HLSDir != "" {
+			log.Printf("⚠️  HLS_DIR ignored: HLS packaging needs the SFU (SFU=true)")
+		}
+		if cfg.RecordingsDir != "" {
+			log.Printf("⚠️  Nothing is recorded into RECORDINGS_DIR without the SFU (SFU=true); the recordings there stay available")
+		}
+		return nil
+	}
+	relay, err := sfu.NewRelay(iceServers, cfg.SFUPort, newRestreamer(cfg))
+	if err != nil {
+		log.Fatalf("Failed to start the SFU: %v", err)
+	}
+	if cfg.SFUPort != 0 {
+		log.Printf("📡 SFU enabled on UDP port %d", cfg.SFUPort)
+	} else {
+		log.Printf("📡 SFU enabled")
+	}
+	return relay
+}
+
+// newRestreamer returns what the SFU republishes each session's video to:
+// ffmpeg pushing it to RTMP_URL, with {token} replaced by the session token,
+// ffmpeg packaging it as HLS in the session's directory under HLS_DIR, and
+// ffmpeg recording it into RECORDINGS_DIR. Without any it returns nil, which
+// disables restreaming.
+func newRestreamer(cfg *config.Config) func(token string) interfaces.VideoRecorder {
+	if cfg.RTMPURL == "" && cfg.HLSDir == "" && cfg.RecordingsDir == "" {
+		return nil
+	}
+	sessionURL := func(token string) string {
+		return strings.ReplaceAll(cfg.RTMPURL, "{token}", token)
+	}
+	if cfg.RTMPURL != "" {
+		if _, err := recording.NewRTMPRestreamer(sessionURL("token"), ""); err != nil {
+			log.Fatalf("Failed to set up restreaming: %v", err)
+		}
+		log.Printf("📺 SFU restreaming sessions to %s", recording.RedactRTMPURL(cfg.RTMPURL))
+	}
+	if cfg.HLSDir != "" {
+		if err := os.MkdirAll(cfg.HLSDir, 0o755); err != nil {
+			log.Fatalf("Failed to set up HLS: %v", err)
+		}
+		log.Printf("📺 SFU packaging sessions as HLS in %s", cfg.HLSDir)
+	}
+	if cfg.RecordingsDir != "" {
+		log.Printf("📼 SFU recording sessions into %s", cfg.RecordingsDir)
+	}
+
+	return func(token string) interfaces.VideoRecorder {
+		var outputs []interfaces.VideoRecorder
+		if cfg.RTMPURL != "" {
+			if restreamer, err := recording.NewRTMPRestreamer(sessionURL(token), ""); err == nil {
+				outputs = append(outputs, restreamer)
+			}
+		}
+		if cfg.HLSDir != "" {
+			outputs = append(outputs, recording.NewHLSPackager(filepath.Join(cfg.HLSDir, token), ""))
+		}
+		if cfg.RecordingsDir != "" {
+			outputs = append(outputs, recording.NewMatroskaRecorder(cfg.RecordingsDir, token, ""))
+		}
+		switch len(outputs) {
+		case 0:
+			return nil
+		case 1:
+			return outputs[0]
+		default:
+			return recording.NewTee(outputs...)
+		}
+	}
+}
+
+// startBackgroundServices starts background processes like garbage collection;
+// they stop when ctx is cancelled
+func startBackgroundServices(ctx context.Context, wg *sync.WaitGroup, deps *Dependencies, gcInterval, tokenExpiry, snapshotInterval time.Duration) {
 	// Start garbage collection for expired sessions
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(gcInterval)
+		defer ticker.Stop()
+
+		log.Printf("🗑️  Token garbage collector started (cleanup every %v, expiry: %v)", gcInterval, tokenExpiry)
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("🗑️  Token garbage collector stopped")
+				return
+			case <-ticker.C:
+				// Failures are logged and recorded for the health endpoint
+				deps.cleanupUseCase.RunCleanup(entities.CleanupTriggerSchedule)
+			}
+		}
+	}()
+
+	// Answer mDNS queries from `share-screen discover`; the server works
+	// without it, e.g. when another responder holds the port exclusively
+	if deps.mdnsAdvertiser != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			log.Printf("📣 Advertising on the LAN with mDNS")
+			if err := deps.mdnsAdvertiser.Run(ctx); err != nil {
+				log.Printf("❌ mDNS advertising stopped: %v", err)
+			}
+		}()
+	}
+
+	// Mirror the sessions to devices on the MQTT broker
+	if deps.mqttBridge != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := deps.mqttBridge.Run(ctx); err != nil {
+				log.Printf("❌ MQTT bridge stopped: %v", err)
+			}
+		}()
+	}
+
+	// Keep the public IP current for links used outside the LAN
+	if deps.publicIPService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(publicIPRefreshInterval)
+			defer ticker.Stop()
+
+			previous := ""
+			for {
+				if err := deps.publicIPService.Refresh(ctx); err != nil {
+					log.Printf("❌ Error discovering the public IP: %v", err)
+				} else if ip := deps.publicIPService.PublicIP(); ip != previous {
+					log.Printf("🌍 Public IP: %s", ip)
+					previous = ip
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	// Save the in-memory state so a restart does not lose live sessions;
+	// the final snapshot is taken once the server has shut down
+	if deps.snapshotUseCase != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(snapshotInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					saveSnapshot(deps.snapshotUseCase)
+				}
+			}
+		}()
+	}
+
+	// Keep the recordings within their retention period and disk limit
+	if deps.recordingJanitor != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(recordingPruneInterval)
+			defer ticker.Stop()
+
+			for {
+				removed, err := deps.recordingJanitor.PruneRecordings()
+				if err != nil {
+					log.Printf("❌ Error pruning recordings: %v", err)
+				} else if len(removed) > 0 {
+					log.Printf("🗑️  Pruned %d recordings", len(removed))
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	// Warn senders when the server nears its soft limits
+	wg.Add(1)
 	go func() {
-		ticker := time.NewTicker(time.Minute)
+		defer wg.Done()
+
+		ticker := time.NewTicker(limitCheckInterval)
 		defer ticker.Stop()
 
-		log.Printf("🗑️  Token garbage collector started (cleanup every 1 min, expiry: %v)", tokenExpiry)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := deps.limitsUseCase.CheckLimits(); err != nil {
+					log.Printf("❌ Error checking soft limits: %v", err)
+				}
+			}
+		}
+	}()
+
+	// Suggest lower constraints to senders whose viewers struggle, and the
+	// sender's own settings again once they recover
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
 
-		for range ticker.C {
-			sessionRepo.CleanupExpiredSessions()
+		ticker := time.NewTicker(adaptationCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := deps.adaptationUseCase.CheckAdaptation(); err != nil {
+					log.Printf("❌ Error checking viewer connections: %v", err)
+				}
+			}
 		}
 	}()
 }
 
 // setupRoutes configures all HTTP routes
-func setupRoutes(static *httphandlers.StaticHandlers, api *httphandlers.APIHandlers) {
+func setupRoutes(mux *http.ServeMux, deps *Dependencies) {
+	router := httphandlers.NewRouter(mux)
+	static := deps.staticHandlers
+	api := deps.apiHandlers
+	queue := deps.queueHandlers
+	// Signaling is limited to the allowed networks and closed to banned
+	// clients, and malformed session tokens are refused before any lookup;
+	// server information, metrics and the token-protected status and admin
+	// endpoints are not
+	lan := func(next http.HandlerFunc) http.HandlerFunc {
+		return deps.banFilter.Wrap(deps.networkPolicy.Wrap(deps.tokenFilter.Wrap(next)))
+	}
+	// Starting shares and the admin page need the basic auth credentials, if
+	// any; viewers never do. With JWTs configured, API clients creating
+	// sessions authenticate with a token instead.
+	auth := deps.basicAuth.Wrap
+	createAuth := auth
+	if deps.jwtAuth.Enabled() {
+		createAuth = deps.jwtAuth.Wrap
+	}
+
 	// Static pages
-	http.HandleFunc("/", static.ServeIndex)
-	http.HandleFunc("/sender", static.ServeSender)
-	http.HandleFunc("/viewer", static.ServeViewer)
+	router.Page("/", static.ServeIndex)
+	router.Page("/sender", auth(static.ServeSender))
+	router.Page("/viewer", static.ServeViewer)
+	router.Page("/demo", static.ServeDemo)
+	router.Page("/summary", static.ServeSummary)
+	router.Page("/preferences", static.HandlePreferences)
+	router.Page("/handout", deps.handoutHandlers.ServeHandout)
+	router.Page("/r/{name}", static.ServeRoom)
+	router.Page("/l/{id}", static.ServeViewerLink)
+	router.Page("/v/{alias}", lan(static.ServeViewerAlias))
+	router.Page("/admin", auth(static.ServeAdmin))
+
+	// WHIP ingest for encoders such as OBS, with the session token as bearer
+	router.Page("/whip", lan(deps.whipHandlers.HandleEndpoint))
+	router.Page("/whip/{token}", lan(deps.whipHandlers.HandleResource))
+	router.Page("/hls/{token}/{file}", lan(deps.hlsHandlers.ServeFile))
+	router.Page("/mjpeg", lan(deps.mjpegHandlers.HandleMJPEG))
+
+	// Static assets: the scripts rendered from templates and the stylesheet
+	// under hashed URLs, anything else straight from the web directory's static/
+	router.Handle("/static/", static.Assets(http.StripPrefix("/static/", http.FileServer(http.Dir(deps.staticDir)))))
+
+	// API endpoints, served under /api/v1 with the original /api paths as aliases
+	router.API("/new", lan(createAuth(api.HandleNewToken)))
+	router.API("/offer", lan(api.HandleOffer))
+	router.API("/answer", lan(api.HandleAnswer))
+	router.API("/session", lan(api.HandleSession))
+	router.API("/info", api.HandleInfo)
+	router.API("/interfaces", lan(api.HandleInterfaces))
+	router.API("/health", api.HandleHealth)
+	router.API("/capabilities", deps.capabilitiesHandlers.HandleCapabilities)
+	router.API("/presets", deps.presetHandlers.HandlePresets)
+	router.API("/templates", lan(deps.templateHandlers.HandleTemplates))
+	router.API("/annotations/schema", deps.annotationHandlers.HandleSchema)
+	router.API("/probe/schema", deps.probeHandlers.HandleSchema)
+	router.API("/ping", lan(deps.probeHandlers.HandlePing))
+	router.API("/spec.json", deps.openAPIHandlers.HandleSpec)
+	router.API("/sessions/{token}/heartbeat", lan(api.HandleHeartbeat))
+	router.API("/session/extend", lan(api.HandleExtendSession))
+	router.API("/pause", lan(api.HandlePause))
+	router.API("/sessions/{token}/end", lan(api.HandleEndSession))
+	router.API("/sessions/{token}/resume", lan(api.HandleResumeSession))
+	router.API("/sessions/{token}/rename", lan(api.HandleRenameSession))
+	router.API("/sessions/{token}/viewers/{viewer}/kick", lan(api.HandleKickViewer))
+	router.API("/sender/sessions", lan(api.HandleOwnSessions))
+	router.API("/sender/profile", lan(deps.profileHandlers.HandleProfile))
+	router.API("/sessions/{token}/publish", lan(api.HandlePublish))
+	router.API("/sessions/{token}/layer", lan(api.HandleSelectLayer))
+	router.API("/snapshot", lan(deps.mjpegHandlers.HandleSnapshot))
+	router.API("/sessions/{token}/events", lan(deps.eventHandlers.HandleEvents))
+	router.API("/sessions/{token}/ice-config", lan(deps.iceHandlers.HandleICEConfig))
+	router.API("/sessions/{token}/turn-credentials", lan(deps.iceHandlers.HandleTURNCredentials))
+	router.API("/metrics/events", deps.metricsHandlers.HandleEventMetrics)
+
+	// Viewer queue
+	router.API("/sessions/{token}/queue", lan(queue.HandleQueue))
+	router.API("/sessions/{token}/queue/{viewer}/leave", lan(queue.HandleLeaveQueue))
+	router.API("/sessions/{token}/queue/{viewer}/promote", lan(queue.HandlePromoteViewer))
+	router.API("/sessions/{token}/leave", lan(queue.HandleReleaseViewer))
+
+	// Named rooms that lead to a sender's current session
+	router.API("/rooms/{name}", lan(deps.roomHandlers.HandleRoom))
 
-	// Static assets (CSS, images, etc.)
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("web/static/"))))
+	// Viewer links that expire and run out of uses ahead of their session
+	router.API("/sessions/{token}/links", lan(deps.viewerLinkHandlers.HandleLinks))
+	router.API("/sessions/{token}/links/{id}/revoke", lan(deps.viewerLinkHandlers.HandleRevokeLink))
+	router.API("/sessions/{token}/invite", lan(deps.inviteHandlers.HandleInvite))
+	router.API("/links/{id}", lan(deps.viewerLinkHandlers.HandleRedeemLink))
 
-	// Dynamic JavaScript (with template rendering)
-	http.HandleFunc("/static/js/sender.js", static.ServeSenderJS)
-	http.HandleFunc("/static/js/viewer.js", static.ServeViewerJS)
+	// Viewer devices senders trust to join their sessions without a PIN
+	router.API("/sessions/{token}/pairings", lan(deps.pairingHandlers.HandlePairings))
+	router.API("/sessions/{token}/pairings/{code}/confirm", lan(deps.pairingHandlers.HandleConfirmPairing))
+	router.API("/sessions/{token}/unlock", lan(deps.pairingHandlers.HandleUnlock))
+	router.API("/sender/devices", lan(deps.pairingHandlers.HandleDevices))
+	router.API("/sender/devices/{id}", lan(deps.pairingHandlers.HandleDevice))
 
-	// API endpoints
-	http.HandleFunc("/api/new", api.HandleNewToken)
-	http.HandleFunc("/api/offer", api.HandleOffer)
-	http.HandleFunc("/api/answer", api.HandleAnswer)
-	http.HandleFunc("/api/info", api.HandleInfo)
+	// Chat relayed until the peers' data channel is open
+	router.API("/chat", lan(deps.chatHandlers.HandleChat))
+
+	// Renegotiation of connected peers, e.g. to add audio or a second screen
+	router.API("/sessions/{token}/negotiation", lan(deps.negotiationHandlers.HandleNegotiation))
+	// Content keys of end-to-end encrypted sessions, relayed but never seen
+	router.API("/sessions/{token}/keys", lan(deps.keyExchangeHandlers.HandleKeys))
+	router.API("/sessions/{token}/fingerprints", lan(deps.fingerprintHandlers.HandleFingerprints))
+
+	// Files relayed until the peers' data channel is open
+	router.API("/sessions/{token}/files", lan(deps.fileHandlers.HandleFiles))
+	router.API("/sessions/{token}/files/{id}", lan(deps.fileHandlers.HandleFile))
+
+	// Session history
+	router.API("/sessions/{token}/summary", lan(deps.historyHandlers.HandleSummary))
+	router.API("/sessions/{token}/notes", lan(deps.historyHandlers.HandleNotes))
+
+	// Viewer settings and stream quality
+	router.API("/viewer/settings", lan(deps.settingsHandlers.HandleViewerSettings))
+	router.API("/sessions/{token}/quality", lan(deps.settingsHandlers.HandleQualityRequest))
+
+	// Viewer status for widgets and menu bar apps
+	router.API("/status", deps.statusHandlers.HandleStatus)
+
+	// Connection stats and latency probe results uploaded by the peers, the
+	// sender's view of its viewers' connections and the admin dashboard's API
+	router.API("/stats", lan(deps.statsHandlers.HandleStats))
+	router.API("/stats/summary", deps.statsHandlers.HandleSummary)
+	router.API("/sessions/{token}/viewer-stats", lan(deps.statsHandlers.HandleViewerStats))
+	router.API("/sessions/{token}/latency", lan(deps.probeHandlers.HandleLatency))
+	router.API("/admin/sessions", deps.adminHandlers.HandleSessions)
+	router.API("/admin/sessions/{token}/stats", deps.adminHandlers.HandleSessionStats)
+	router.API("/admin/sessions/{token}/audit", deps.adminHandlers.HandleAuditLog)
+	router.API("/admin/sessions/{token}/viewers/{viewer}/kick", deps.adminHandlers.HandleKickViewer)
+	router.API("/admin/cleanup", deps.adminHandlers.HandleCleanup)
+	router.API("/admin/bans", deps.adminHandlers.HandleBans)
+	router.API("/admin/templates/{name}", deps.templateHandlers.HandleTemplate)
+	router.API("/admin/feed", deps.adminHandlers.HandleFeed)
+	router.API("/recordings", deps.recordingHandlers.HandleRecordings)
+	router.API("/recordings/{name}", deps.recordingHandlers.HandleRecording)
 }
 
-// startServer starts the HTTP or HTTPS server based on configuration
-func startServer(cfg *config.Config) {
-	addr := ":" + cfg.Port
+// runServer starts the HTTP or HTTPS server based on configuration and shuts
+// it down gracefully once ctx is cancelled
+func runServer(ctx context.Context, cfg *config.Config, handler http.Handler, notifier *httphandlers.ShutdownNotifier, onDrain ...func()) {
 	protocol := "HTTP"
 	if cfg.EnableHTTPS {
 		protocol = "HTTPS"
 	}
 
-	log.Printf("%s Server listening on %s", protocol, addr)
-	log.Printf("STUN Server: %s", cfg.STUNServer)
+	server := &http.Server{
+		Handler: handler,
+	}
+
+	// Every bind address is served by the same server, so shutting it down
+	// closes all of them; sockets opened by systemd replace the bind
+	// addresses
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		log.Fatalf("Invalid systemd sockets: %v", err)
+	}
+	if len(listeners) > 0 {
+		log.Printf("Using %d sockets from systemd socket activation", len(listeners))
+	}
+	if len(listeners) == 0 {
+		for _, address := range cfg.ListenAddresses() {
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				log.Fatalf("Server failed to start: %v", err)
+			}
+			listeners = append(listeners, ln)
+		}
+	}
+
+	// Plain HTTP requests to an HTTPS server are sent to the same URL over
+	// HTTPS, so typing the address without https:// still works
+	var redirect http.Handler = httphandlers.NewHTTPSRedirect(cfg.Port)
+
+	// With automatic HTTPS, certificates come from Let's Encrypt, which checks
+	// the domain through the TLS listener on port 443 or the redirect
+	// listener on port 80
+	if cfg.HTTPSAuto {
+		manager, err := letsencrypt.NewManager(cfg.AutocertDomains, cfg.AutocertCacheDir, cfg.AutocertEmail)
+		if err != nil {
+			log.Fatalf("Invalid automatic HTTPS configuration: %v", err)
+		}
+		server.TLSConfig = manager.TLSConfig()
+		redirect = manager.HTTPHandler(redirect)
+	}
+
+	var redirectServer *http.Server
+	if cfg.EnableHTTPS && cfg.HTTPRedirectPort != "" && cfg.HTTPRedirectPort != "0" && cfg.HTTPRedirectPort != cfg.Port {
+		redirectServer = &http.Server{
+			Handler: redirect,
+		}
+	}
+
+	for _, ln := range listeners {
+		log.Printf("%s Server listening on %s", protocol, ln.Addr())
+	}
 	log.Printf("Token Expiry: %s", cfg.TokenExpiry)
+	if len(cfg.CORSOrigins) > 0 {
+		log.Printf("CORS Origins: %s", strings.Join(cfg.CORSOrigins, ", "))
+	}
+	log.Printf("Signaling allowed from: %s (and loopback)", strings.Join(cfg.AllowedNetworks, ", "))
+	if cfg.ExternalURL != "" {
+		log.Printf("Viewer links use %s", cfg.ExternalURL)
+	}
+	// Tunneled viewers arrive from loopback, which is always allowed
+	if (cfg.ExternalURL != "" || cfg.DiscoverPublicIP) && cfg.Tunnel == "" && !slices.Contains(cfg.AllowedNetworks, "*") {
+		log.Printf("⚠️  Viewers outside the LAN are refused signaling; set ALLOWED_NETWORKS=* to let them in")
+	}
+	if cfg.MaxSessions > 0 || cfg.MaxBandwidthMbps > 0 {
+		log.Printf("Soft limits: %d sessions, %d Mbps (warning at %d%%)", cfg.MaxSessions, cfg.MaxBandwidthMbps, cfg.LimitWarningPercent)
+	}
+	if cfg.SessionLimit > 0 {
+		log.Printf("Session limit: %d live sessions", cfg.SessionLimit)
+	}
+	if cfg.StatusToken != "" {
+		log.Printf("Viewer status endpoint enabled at %s/status", "/api/"+httphandlers.APIVersion)
+	}
+	if cfg.AdminToken != "" || cfg.JWTEnabled() {
+		log.Printf("Admin dashboard enabled at /admin")
+	}
+	if cfg.JWTEnabled() {
+		log.Printf("JWT required for /api/new and accepted with the admin scope by the admin API")
+	}
+	if cfg.AuthUser != "" {
+		log.Printf("Basic auth required for /sender, /admin and /api/new")
+		if !cfg.EnableHTTPS {
+			log.Printf("⚠️  Basic auth over HTTP sends the password in the clear; enable HTTPS")
+		}
+	}
 
-	var err error
-	if cfg.EnableHTTPS {
+	if redirectServer != nil {
+		for _, address := range cfg.RedirectAddresses() {
+			// HTTPS keeps working without it, e.g. when the port is taken
+			ln, err := net.Listen("tcp", address)
+			if err != nil {
+				log.Printf("❌ HTTP redirect listener failed: %v", err)
+				continue
+			}
+			log.Printf("HTTP Server on %s redirecting to HTTPS", ln.Addr())
+			go func() {
+				if err := redirectServer.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+					log.Printf("❌ HTTP redirect listener failed: %v", err)
+				}
+			}()
+		}
+	}
+
+	if cfg.HTTPSAuto {
+		log.Printf("TLS certificates from Let's Encrypt for %s, cached in %s", strings.Join(cfg.AutocertDomains, ", "), cfg.AutocertCacheDir)
+	} else if cfg.EnableHTTPS {
 		log.Printf("TLS Certificate: %s", cfg.CertFile)
 		log.Printf("TLS Private Key: %s", cfg.KeyFile)
-		err = http.ListenAndServeTLS(addr, cfg.CertFile, cfg.KeyFile, nil)
 	} else {
 		log.Printf("⚠️  Running in HTTP mode - consider enabling HTTPS for production")
-		err = http.ListenAndServe(addr, nil)
 	}
 
-	if err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	serverErr := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		go func() {
+			if cfg.HTTPSAuto {
+				serverErr <- server.ServeTLS(ln, "", "")
+			} else if cfg.EnableHTTPS {
+				serverErr <- server.ServeTLS(ln, cfg.CertFile, cfg.KeyFile)
+			} else {
+				serverErr <- server.Serve(ln)
+			}
+		}()
+	}
+
+	// With Type=notify, systemd starts dependent units once this arrives
+	if _, err := systemd.Notify(systemd.Ready); err != nil {
+		log.Printf("❌ Failed to notify systemd: %v", err)
+	}
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+		return
+	case <-ctx.Done():
+	}
+
+	log.Printf("🛑 Shutdown signal received, draining connections (timeout: %v)", cfg.ShutdownTimeout)
+	systemd.Notify(systemd.Stopping)
+	notifier.Drain()
+	for _, fn := range onDrain {
+		fn()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	select {
+	case <-time.After(shutdownDrainPeriod):
+	case <-shutdownCtx.Done():
+	}
+
+	if redirectServer != nil {
+		_ = redirectServer.Shutdown(shutdownCtx)
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("❌ Graceful shutdown incomplete: %v", err)
 	}
 }
@@ -0,0 +1,142 @@
+// Package annotation defines the strokes a viewer draws over the shared screen
+// and sends to the sender over the "annotations" data channel, so the sender
+// page can show what the viewer points at.
+//
+// The server does not relay strokes; it publishes the schema the pages agree
+// on, so both sides draw with the same palette and limits. Messages are small
+// JSON objects such as
+// {"type":"stroke","id":"s1","color":"#ff3b30","width":4,"points":[[0.5,0.25],[0.52,0.26]]};
+// points are fractions of the shared screen, as in remote control, and a long
+// stroke is sent as several messages with the same ID.
+package annotation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ChannelLabel is the label of the data channel carrying strokes
+const ChannelLabel = "annotations"
+
+// MaxMessageSize bounds a single annotation message
+const MaxMessageSize = 1024
+
+// MaxPoints bounds the points in one stroke message; longer strokes are split
+const MaxPoints = 32
+
+// MaxWidth is the widest line, in pixels at the sender's preview size
+const MaxWidth = 12
+
+// MaxIDLength bounds the ID joining the messages of one stroke
+const MaxIDLength = 16
+
+// FadeAfter is how long a stroke stays on the sender's preview
+const FadeAfter = 4 * time.Second
+
+// Colors is the palette viewers draw with
+var Colors = []string{"#ff3b30", "#ffcc00", "#34c759", "#0a84ff", "#ffffff"}
+
+// Message types
+const (
+	// MessageStroke carries points of a stroke, continuing earlier messages
+	// with the same ID
+	MessageStroke = "stroke"
+
+	// MessageClear wipes every stroke before it fades
+	MessageClear = "clear"
+)
+
+// ErrInvalidMessage is returned for messages that are not a valid annotation
+var ErrInvalidMessage = errors.New("invalid annotation message")
+
+// Point locates a stroke point as fractions of the screen's width and height,
+// from the top left corner
+type Point [2]float64
+
+// Message is one annotation message from the viewer
+type Message struct {
+	Type   string  `json:"type"`
+	ID     string  `json:"id,omitempty"`
+	Color  string  `json:"color,omitempty"`
+	Width  int     `json:"width,omitempty"`
+	Points []Point `json:"points,omitempty"`
+}
+
+// Schema describes the messages and limits the sender and viewer pages use
+type Schema struct {
+	Channel        string   `json:"channel"`
+	Types          []string `json:"types"`
+	MaxMessageSize int      `json:"maxMessageSize"`
+	MaxPoints      int      `json:"maxPoints"`
+	MaxWidth       int      `json:"maxWidth"`
+	MaxIDLength    int      `json:"maxIdLength"`
+	Colors         []string `json:"colors"`
+
+	// FadeMillis is how long a stroke stays on the sender's preview
+	FadeMillis int64 `json:"fadeMillis"`
+}
+
+// DefaultSchema returns the schema of this package's messages
+func DefaultSchema() Schema {
+	return Schema{
+		Channel:        ChannelLabel,
+		Types:          []string{MessageStroke, MessageClear},
+		MaxMessageSize: MaxMessageSize,
+		MaxPoints:      MaxPoints,
+		MaxWidth:       MaxWidth,
+		MaxIDLength:    MaxIDLength,
+		Colors:         slices.Clone(Colors),
+		FadeMillis:     FadeAfter.Milliseconds(),
+	}
+}
+
+// Parse decodes and validates one annotation message
+func Parse(data []byte) (Message, error) {
+	var message Message
+	if len(data) > MaxMessageSize {
+		return message, fmt.Errorf("%w: message of %d bytes is too large", ErrInvalidMessage, len(data))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&message); err != nil {
+		return Message{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if err := message.Validate(); err != nil {
+		return Message{}, err
+	}
+	return message, nil
+}
+
+// Validate checks that the message is one the sender can draw
+func (m Message) Validate() error {
+	switch m.Type {
+	case MessageClear:
+		return nil
+	case MessageStroke:
+		if m.ID == "" || len(m.ID) > MaxIDLength {
+			return fmt.Errorf("%w: stroke ID must be 1 to %d bytes", ErrInvalidMessage, MaxIDLength)
+		}
+		if !slices.Contains(Colors, m.Color) {
+			return fmt.Errorf("%w: color %q is not in the palette", ErrInvalidMessage, m.Color)
+		}
+		if m.Width < 1 || m.Width > MaxWidth {
+			return fmt.Errorf("%w: width %d is not between 1 and %d", ErrInvalidMessage, m.Width, MaxWidth)
+		}
+		if len(m.Points) == 0 || len(m.Points) > MaxPoints {
+			return fmt.Errorf("%w: stroke must have 1 to %d points", ErrInvalidMessage, MaxPoints)
+		}
+		for _, p := range m.Points {
+			if p[0] < 0 || p[0] > 1 || p[1] < 0 || p[1] > 1 {
+				return fmt.Errorf("%w: point %.3f,%.3f is off the screen", ErrInvalidMessage, p[0], p[1])
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidMessage, m.Type)
+	}
+}
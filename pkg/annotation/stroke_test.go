@@ -0,0 +1,109 @@
+package annotation
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		expected      Message
+		expectedError bool
+	}{
+		{
+			name:     "stroke",
+			message:  `{"type":"stroke","id":"s1","color":"#ff3b30","width":4,"points":[[0.5,0.25],[1,0]]}`,
+			expected: Message{Type: MessageStroke, ID: "s1", Color: "#ff3b30", Width: 4, Points: []Point{{0.5, 0.25}, {1, 0}}},
+		},
+		{
+			name:     "clear",
+			message:  `{"type":"clear"}`,
+			expected: Message{Type: MessageClear},
+		},
+		{
+			name:          "unknown type",
+			message:       `{"type":"text","id":"s1"}`,
+			expectedError: true,
+		},
+		{
+			name:          "stroke without ID",
+			message:       `{"type":"stroke","color":"#ff3b30","width":4,"points":[[0.5,0.5]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "ID too long",
+			message:       `{"type":"stroke","id":"` + strings.Repeat("s", MaxIDLength+1) + `","color":"#ff3b30","width":4,"points":[[0.5,0.5]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "color outside the palette",
+			message:       `{"type":"stroke","id":"s1","color":"url(x)","width":4,"points":[[0.5,0.5]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "too wide",
+			message:       `{"type":"stroke","id":"s1","color":"#ff3b30","width":40,"points":[[0.5,0.5]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "no points",
+			message:       `{"type":"stroke","id":"s1","color":"#ff3b30","width":4,"points":[]}`,
+			expectedError: true,
+		},
+		{
+			name:          "too many points",
+			message:       `{"type":"stroke","id":"s1","color":"#ff3b30","width":4,"points":[` + strings.Repeat("[0,0],", MaxPoints) + `[0,0]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "off screen",
+			message:       `{"type":"stroke","id":"s1","color":"#ff3b30","width":4,"points":[[0.5,1.5]]}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			message:       `{"type":"clear","script":"x"}`,
+			expectedError: true,
+		},
+		{
+			name:          "too large",
+			message:       `{"type":"clear"` + strings.Repeat(" ", MaxMessageSize) + `}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Parse([]byte(tt.message))
+			if tt.expectedError {
+				if !errors.Is(err, ErrInvalidMessage) {
+					t.Errorf("Expected ErrInvalidMessage, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(message, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, message)
+			}
+		})
+	}
+}
+
+func TestDefaultSchema(t *testing.T) {
+	schema := DefaultSchema()
+	if schema.Channel != ChannelLabel || schema.MaxPoints != MaxPoints || schema.FadeMillis != FadeAfter.Milliseconds() {
+		t.Errorf("Expected the package limits, got %+v", schema)
+	}
+
+	// Callers may not change the palette through the schema
+	schema.Colors[0] = "#000000"
+	if Colors[0] == "#000000" {
+		t.Error("Expected the schema to hold a copy of the palette")
+	}
+}
@@ -0,0 +1,668 @@
+// Package client wraps the share-screen HTTP API for Go programs that act as
+// a sender or viewer, such as test automation or native capture tools. The
+// WebRTC side is left to the caller; this package only handles signaling.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+)
+
+// pollInterval is how often WaitForOffer and WaitForAnswer poll the server,
+// matching the browser pages
+const pollInterval = time.Second
+
+// APIError is returned when the server answers with a non-success status
+type APIError struct {
+	StatusCode int
+	Message    string
+
+	// RequestID finds the request in the server log
+	RequestID string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("share-screen API error %d: %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("share-screen API error %d: %s", e.StatusCode, e.Message)
+}
+
+// StatusCode returns the HTTP status of an *APIError, or 0 for other errors
+func StatusCode(err error) int {
+	if apiErr, ok := err.(*APIError); ok {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// Client calls the versioned share-screen API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	// bearerToken is sent with requests that carry no other credentials
+	bearerToken string
+}
+
+// NewClient creates a client for the server at baseURL (e.g.
+// "http://192.168.1.10:8080"); a nil httpClient uses http.DefaultClient
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/v1",
+		httpClient: httpClient,
+	}
+}
+
+// SetBearerToken makes the client authenticate with token, such as the JWT a
+// server requires for creating sessions; an empty token sends none
+func (c *Client) SetBearerToken(token string) {
+	c.bearerToken = token
+}
+
+// Info returns server and network information
+func (c *Client) Info(ctx context.Context) (*entities.ServerInfo, error) {
+	var info entities.ServerInfo
+	if err := c.do(ctx, "GET", "/info", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Capabilities returns which optional subsystems work on the server
+func (c *Client) Capabilities(ctx context.Context) (*entities.Capabilities, error) {
+	var capabilities entities.Capabilities
+	if err := c.do(ctx, "GET", "/capabilities", nil, &capabilities); err != nil {
+		return nil, err
+	}
+	return &capabilities, nil
+}
+
+// CreateSession creates a session and returns its token
+func (c *Client) CreateSession(ctx context.Context, request *dto.CreateSessionRequest) (*dto.CreateSessionResponse, error) {
+	var response dto.CreateSessionResponse
+	if err := c.do(ctx, "POST", "/new", request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SubmitOffer publishes the sender's offer for the next viewer; replacing
+// an offer already posted takes the sender key the session was created with
+func (c *Client) SubmitOffer(ctx context.Context, token, senderKey string, offer *entities.WebRTCOffer) error {
+	return c.do(ctx, "POST", "/offer", &dto.SubmitOfferRequest{Token: token, Offer: offer, SenderKey: senderKey}, nil)
+}
+
+// RestartICE posts an offer with new ICE credentials, e.g. from
+// CreateOffer with ICERestart set, for the connected viewer to answer on its
+// connection once the network path broke; it takes the sender key
+func (c *Client) RestartICE(ctx context.Context, token, senderKey string, offer *entities.WebRTCOffer) error {
+	return c.do(ctx, "POST", "/offer", &dto.SubmitOfferRequest{Token: token, Offer: offer, SenderKey: senderKey, ICERestart: true}, nil)
+}
+
+// ResetOffer clears the sender's offer and answer, returning the session to
+// pending, so the sender can capture again and submit a new offer; a
+// connected viewer is told to renegotiate. It takes the sender key.
+func (c *Client) ResetOffer(ctx context.Context, token, senderKey string) error {
+	return c.do(ctx, "DELETE", "/offer?"+url.Values{"token": {token}, "senderKey": {senderKey}}.Encode(), nil, nil)
+}
+
+// GetOffer fetches the sender's offer; it fails with 404 until the offer is
+// posted, 403 when pin is wrong for a protected session, 429 once too many
+// wrong PINs locked it, 409 while another viewer holds the session and 410
+// once a single-use link was used by another viewer
+func (c *Client) GetOffer(ctx context.Context, token, viewerID, pin string) (*entities.WebRTCOffer, error) {
+	query := url.Values{"token": {token}}
+	if viewerID != "" {
+		query.Set("viewer", viewerID)
+	}
+	if pin != "" {
+		query.Set("pin", pin)
+	}
+
+	var offer entities.WebRTCOffer
+	if err := c.do(ctx, "GET", "/offer?"+query.Encode(), nil, &offer); err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// WaitForOffer polls until the sender's offer is available or ctx is done
+func (c *Client) WaitForOffer(ctx context.Context, token, viewerID, pin string) (*entities.WebRTCOffer, error) {
+	for {
+		offer, err := c.GetOffer(ctx, token, viewerID, pin)
+		if StatusCode(err) != 404 {
+			return offer, err
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// SubmitAnswer publishes the viewer's answer; viewerID is the ID returned by
+// JoinQueue, or empty when the viewer did not queue, and pin is empty for
+// an unprotected session. A wrong pin fails with 403 as with GetOffer.
+func (c *Client) SubmitAnswer(ctx context.Context, token, viewerID, pin string, answer *entities.WebRTCAnswer) error {
+	return c.do(ctx, "POST", "/answer", &dto.SubmitAnswerRequest{Token: token, Answer: answer, ViewerID: viewerID, PIN: pin}, nil)
+}
+
+// AnswerICERestart posts the viewer's answer to the ICE restart numbered
+// generation, as told by the ice-restart event; answers to an older offer
+// fail with 409
+func (c *Client) AnswerICERestart(ctx context.Context, token, viewerID, pin string, generation int, answer *entities.WebRTCAnswer) error {
+	return c.do(ctx, "POST", "/answer", &dto.SubmitAnswerRequest{Token: token, Answer: answer, ViewerID: viewerID, PIN: pin, ICEGeneration: generation}, nil)
+}
+
+// GetAnswer fetches the viewer's answer; it fails with 404 until one is posted
+func (c *Client) GetAnswer(ctx context.Context, token string) (*entities.WebRTCAnswer, error) {
+	var answer entities.WebRTCAnswer
+	if err := c.do(ctx, "GET", "/answer?"+url.Values{"token": {token}}.Encode(), nil, &answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// WaitForAnswer polls until a viewer's answer is available or ctx is done
+func (c *Client) WaitForAnswer(ctx context.Context, token string) (*entities.WebRTCAnswer, error) {
+	for {
+		answer, err := c.GetAnswer(ctx, token)
+		if StatusCode(err) != 404 {
+			return answer, err
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// GetICEConfig returns the STUN and TURN servers the session's peers should
+// use; protected sessions need the pin
+func (c *Client) GetICEConfig(ctx context.Context, token, pin string) (*dto.ICEConfigResponse, error) {
+	path := sessionPath(token, "ice-config")
+	if pin != "" {
+		path += "?" + url.Values{"pin": {pin}}.Encode()
+	}
+
+	var response dto.ICEConfigResponse
+	if err := c.do(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetTURNCredentials mints a short-lived credential for the session's TURN
+// servers; pin is needed for protected sessions
+func (c *Client) GetTURNCredentials(ctx context.Context, token, pin string) (*dto.TURNCredentialsResponse, error) {
+	path := sessionPath(token, "turn-credentials")
+	if pin != "" {
+		path += "?" + url.Values{"pin": {pin}}.Encode()
+	}
+
+	var response dto.TURNCredentialsResponse
+	if err := c.do(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Heartbeat keeps the session alive; a session whose sender misses
+// heartbeats for the server's heartbeat timeout (2 minutes by default) goes stale
+func (c *Client) Heartbeat(ctx context.Context, token string, bytesSent int64) error {
+	return c.do(ctx, "POST", sessionPath(token, "heartbeat"), &dto.HeartbeatRequest{BytesSent: bytesSent}, nil)
+}
+
+// PublishStream sends the sender's offer to the server's SFU and returns its
+// answer; it fails with 404 unless the session was created with SFU, and
+// replacing a published stream takes the sender key
+func (c *Client) PublishStream(ctx context.Context, token, senderKey string, offer *entities.WebRTCOffer) (*entities.WebRTCAnswer, error) {
+	var answer entities.WebRTCAnswer
+	if err := c.do(ctx, "POST", sessionPath(token, "publish"), &dto.PublishStreamRequest{Offer: offer, SenderKey: senderKey}, &answer); err != nil {
+		return nil, err
+	}
+	return &answer, nil
+}
+
+// SelectLayer picks the simulcast layer a viewer of an SFU session receives,
+// or entities.LayerAuto to follow its connection; it fails with 404 unless the
+// viewer was offered a connection by the SFU
+func (c *Client) SelectLayer(ctx context.Context, token, viewerID string, layer entities.SimulcastLayer) error {
+	return c.do(ctx, "POST", sessionPath(token, "layer"), &dto.SelectLayerRequest{ViewerID: viewerID, Layer: layer}, nil)
+}
+
+// UploadStats uploads a summary of a peer's WebRTC stats to the session's
+// stats time series; request.Role is entities.StatsRoleSender or
+// entities.StatsRoleViewer with the viewer's ID
+func (c *Client) UploadStats(ctx context.Context, request *dto.RecordStatsRequest) error {
+	return c.do(ctx, "POST", "/stats", request, nil)
+}
+
+// ResumeSession reattaches a sender to its session with the sender key from
+// CreateSession, e.g. after a restart, and returns the session's options
+func (c *Client) ResumeSession(ctx context.Context, token, senderKey string) (*dto.CreateSessionResponse, error) {
+	var response dto.CreateSessionResponse
+	if err := c.do(ctx, "POST", sessionPath(token, "resume"), &dto.ResumeSessionRequest{SenderKey: senderKey}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// KickViewer removes a viewer from the session with the sender key from
+// CreateSession; the viewer's ID is refused from then on
+func (c *Client) KickViewer(ctx context.Context, token, viewerID, senderKey string) error {
+	return c.do(ctx, "POST", sessionPath(token, "viewers", viewerID, "kick"), &dto.KickViewerRequest{SenderKey: senderKey}, nil)
+}
+
+// GetSession describes where a session stands: its status, whether the offer
+// and answer are there and how many viewers are connected or queued
+func (c *Client) GetSession(ctx context.Context, token string) (*dto.SessionDetailResponse, error) {
+	var response dto.SessionDetailResponse
+	if err := c.do(ctx, "GET", "/session?"+url.Values{"token": {token}}.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ExtendSession gives the session the server's full token expiry again from
+// now, given the sender key; senders call it while they share so a long
+// session does not expire
+func (c *Client) ExtendSession(ctx context.Context, token, senderKey string) (*dto.ExtendSessionResponse, error) {
+	var response dto.ExtendSessionResponse
+	if err := c.do(ctx, "POST", "/session/extend", &dto.ExtendSessionRequest{Token: token, SenderKey: senderKey}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// PauseSession pauses the session's stream for its viewers, or resumes it
+// when paused is false, given the sender key; the sender stops sending the
+// picture itself
+func (c *Client) PauseSession(ctx context.Context, token, senderKey string, paused bool) error {
+	return c.do(ctx, "POST", "/pause", &dto.PauseSessionRequest{Token: token, Paused: paused, SenderKey: senderKey}, nil)
+}
+
+// EndSession ends the session, given the sender key it was created with
+func (c *Client) EndSession(ctx context.Context, token, senderKey string) error {
+	return c.do(ctx, "POST", sessionPath(token, "end"), &dto.EndSessionRequest{SenderKey: senderKey}, nil)
+}
+
+// JoinQueue joins the viewer queue of a full session, or reserves the free
+// slot when the response position is 0; pin is empty for unprotected sessions
+func (c *Client) JoinQueue(ctx context.Context, token, pin string) (*dto.JoinQueueResponse, error) {
+	var response dto.JoinQueueResponse
+	if err := c.do(ctx, "POST", sessionPath(token, "queue"), &dto.JoinQueueRequest{PIN: pin}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetQueue lists the viewers waiting on a session
+func (c *Client) GetQueue(ctx context.Context, token string) (*dto.GetQueueResponse, error) {
+	var response dto.GetQueueResponse
+	if err := c.do(ctx, "GET", sessionPath(token, "queue"), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// LeaveQueue removes a viewer from the queue
+func (c *Client) LeaveQueue(ctx context.Context, token, viewerID string) error {
+	return c.do(ctx, "POST", sessionPath(token, "queue", viewerID, "leave"), nil, nil)
+}
+
+// PromoteViewer moves a viewer to the front of the queue
+func (c *Client) PromoteViewer(ctx context.Context, token, viewerID string) error {
+	return c.do(ctx, "POST", sessionPath(token, "queue", viewerID, "promote"), nil, nil)
+}
+
+// ReleaseViewer frees the slot held by the connected viewer
+func (c *Client) ReleaseViewer(ctx context.Context, token string) error {
+	return c.do(ctx, "POST", sessionPath(token, "leave"), nil, nil)
+}
+
+// RequestQuality asks the sender to cap its frame rate; 0 removes the cap
+func (c *Client) RequestQuality(ctx context.Context, token string, maxFrameRate int) error {
+	return c.do(ctx, "POST", sessionPath(token, "quality"), &dto.QualityRequest{MaxFrameRate: maxFrameRate}, nil)
+}
+
+// SendChatMessage relays a chat message from "sender" or "viewer" through the
+// server; pin is needed for protected sessions
+func (c *Client) SendChatMessage(ctx context.Context, token, pin, from, text string) (*entities.ChatMessage, error) {
+	var message entities.ChatMessage
+	request := &dto.SendChatMessageRequest{Token: token, PIN: pin, From: from, Text: text}
+	if err := c.do(ctx, "POST", "/chat", request, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetChatMessages returns the chat messages relayed after the message ID since
+func (c *Client) GetChatMessages(ctx context.Context, token, pin string, since int) (*dto.ChatMessagesResponse, error) {
+	query := url.Values{"token": {token}}
+	if pin != "" {
+		query.Set("pin", pin)
+	}
+	if since > 0 {
+		query.Set("since", strconv.Itoa(since))
+	}
+
+	var response dto.ChatMessagesResponse
+	if err := c.do(ctx, "GET", "/chat?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SendNegotiationMessage relays a description or candidate of the perfect
+// negotiation between the sender and the connected viewer
+func (c *Client) SendNegotiationMessage(ctx context.Context, token string, request *dto.SendNegotiationMessageRequest) (*entities.NegotiationMessage, error) {
+	var message entities.NegotiationMessage
+	if err := c.do(ctx, "POST", sessionPath(token, "negotiation"), request, &message); err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetNegotiationMessages returns the negotiation messages relayed on the
+// viewer's connection after the sequence number since
+func (c *Client) GetNegotiationMessages(ctx context.Context, token, pin, viewerID string, since int) (*dto.NegotiationMessagesResponse, error) {
+	query := url.Values{"viewer": {viewerID}}
+	if pin != "" {
+		query.Set("pin", pin)
+	}
+	if since > 0 {
+		query.Set("since", strconv.Itoa(since))
+	}
+
+	var response dto.NegotiationMessagesResponse
+	if err := c.do(ctx, "GET", sessionPath(token, "negotiation")+"?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ShareFile relays a file to the session's viewers through the server, for
+// when no data channel is open; it fails with 413 once the session's relay
+// limit is used up
+func (c *Client) ShareFile(ctx context.Context, token, name, contentType string, content io.Reader) (*entities.SharedFile, error) {
+	path := sessionPath(token, "files") + "?" + url.Values{"name": {name}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, content)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, readError(res)
+	}
+
+	var file entities.SharedFile
+	if err := json.NewDecoder(res.Body).Decode(&file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// ListFiles returns the files relayed in a session
+func (c *Client) ListFiles(ctx context.Context, token, pin string) (*dto.FilesResponse, error) {
+	path := sessionPath(token, "files")
+	if pin != "" {
+		path += "?" + url.Values{"pin": {pin}}.Encode()
+	}
+
+	var response dto.FilesResponse
+	if err := c.do(ctx, "GET", path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DownloadFile opens a relayed file; the caller closes the returned body
+func (c *Client) DownloadFile(ctx context.Context, token, pin, id string) (io.ReadCloser, error) {
+	path := sessionPath(token, "files", id)
+	if pin != "" {
+		path += "?" + url.Values{"pin": {pin}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		defer res.Body.Close()
+		return nil, readError(res)
+	}
+	return res.Body, nil
+}
+
+// ClaimRoom points a named room at a live session. Leave key empty to claim a
+// free name; the response carries the key needed to move the room later.
+func (c *Client) ClaimRoom(ctx context.Context, name, token, key string) (*dto.ClaimRoomResponse, error) {
+	var response dto.ClaimRoomResponse
+	request := &dto.ClaimRoomRequest{Token: token, Key: key}
+	if err := c.do(ctx, "PUT", "/rooms/"+url.PathEscape(name), request, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetRoom returns the session a named room currently leads to
+func (c *Client) GetRoom(ctx context.Context, name string) (*dto.RoomResponse, error) {
+	var response dto.RoomResponse
+	if err := c.do(ctx, "GET", "/rooms/"+url.PathEscape(name), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetSummary returns the summary of a finished session
+func (c *Client) GetSummary(ctx context.Context, token string) (*dto.SessionSummaryResponse, error) {
+	var response dto.SessionSummaryResponse
+	if err := c.do(ctx, "GET", sessionPath(token, "summary"), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateNotes replaces the notes stored with a finished session; senderKey is
+// the one CreateSession returned
+func (c *Client) UpdateNotes(ctx context.Context, token, senderKey, notes string) (*dto.SessionSummaryResponse, error) {
+	var response dto.SessionSummaryResponse
+	if err := c.do(ctx, "PUT", sessionPath(token, "notes"), &dto.UpdateSessionNotesRequest{Notes: notes, SenderKey: senderKey}, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Status reports whether anyone is viewing, authenticating with the server's
+// status token. Pass the ETag of the previous status and a wait to long-poll:
+// the call returns when the status changes, or with a nil status and the same
+// ETag when it has not changed after wait.
+func (c *Client) Status(ctx context.Context, statusToken, etag string, wait time.Duration) (*dto.ViewerStatusResponse, string, error) {
+	path := "/status"
+	if wait > 0 {
+		path += "?" + url.Values{"wait": {strconv.Itoa(int(wait.Seconds()))}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+statusToken)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	res, err := c.send(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == 304:
+		return nil, etag, nil
+	case res.StatusCode != 200:
+		return nil, "", readError(res)
+	}
+
+	var status dto.ViewerStatusResponse
+	if err := json.NewDecoder(res.Body).Decode(&status); err != nil {
+		return nil, "", err
+	}
+	return &status, res.Header.Get("ETag"), nil
+}
+
+// Events streams session events until ctx is done or the server closes the
+// stream; pass a viewerID to also receive that viewer's queue events. Event
+// data is delivered as json.RawMessage.
+func (c *Client) Events(ctx context.Context, token, viewerID string) (<-chan entities.Event, error) {
+	path := sessionPath(token, "events")
+	if viewerID != "" {
+		path += "?" + url.Values{"viewer": {viewerID}}.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != 200 {
+		defer res.Body.Close()
+		return nil, readError(res)
+	}
+
+	events := make(chan entities.Event)
+	go func() {
+		defer close(events)
+		defer res.Body.Close()
+
+		var event entities.Event
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				event.Data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+			case line == "" && event.Type != "":
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+				event = entities.Event{}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// do sends a JSON request and decodes the JSON response into out when given
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return readError(res)
+	}
+	if out == nil || res.StatusCode == 204 {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// send sends req, adding the client's bearer token unless the request
+// carries its own credentials
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.bearerToken != "" && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+	return c.httpClient.Do(req)
+}
+
+// readError converts an error response to an *APIError
+func readError(res *http.Response) error {
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	return &APIError{
+		StatusCode: res.StatusCode,
+		Message:    strings.TrimSpace(string(message)),
+		RequestID:  res.Header.Get("X-Request-ID"),
+	}
+}
+
+// sessionPath builds the path of a per-session endpoint, escaping each segment
+func sessionPath(token string, segments ...string) string {
+	path := "/sessions/" + url.PathEscape(token)
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return path
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,856 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/pkg/infrastructure/network"
+	"share-screen/pkg/infrastructure/repository"
+	httphandlers "share-screen/pkg/presentation/http"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+)
+
+// testStatusToken is the status token of the test server
+const testStatusToken = "status-token"
+
+// testFileRelayLimit is how many bytes each session of the test server may relay
+const testFileRelayLimit = 16
+
+// testICEServers are the STUN and TURN servers of the test server
+var testICEServers = []entities.ICEServer{
+	{URLs: []string{"stun:test.com:19302"}},
+	{URLs: []string{"turn:turn.test.com:3478"}, Username: "user", Credential: "turn-pass"},
+}
+
+// newTestServer runs the API with real dependencies
+// testSDP builds a minimal valid session description named after label
+func testSDP(label string) string {
+	return "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=" + label + "\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"
+}
+
+func newTestServer(t *testing.T) *Client {
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	broker := events.NewBroker(events.DefaultBufferSize, events.PolicyDropOldest).(*events.Broker)
+
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, broker, nil, 30*time.Minute, usecases.SessionDefaults{})
+	serverInfoUseCase := usecases.NewServerInfoUseCase(network.NewNetworkService(""), nil, "stun:test.com:19302", "1.0.0", "", "", nil)
+	queueUseCase := usecases.NewViewerQueueUseCase(sessionRepo, historyRepo, broker)
+	settingsUseCase := usecases.NewViewerSettingsUseCase(repository.NewMemoryDeviceSettingsRepository(), sessionRepo, historyRepo, broker)
+
+	api := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase, nil)
+	queue := httphandlers.NewQueueHandlers(queueUseCase)
+	history := httphandlers.NewHistoryHandlers(usecases.NewSessionHistoryUseCase(historyRepo))
+	settings := httphandlers.NewSettingsHandlers(settingsUseCase)
+	eventHandlers := httphandlers.NewEventHandlers(broker, queueUseCase)
+	ice := httphandlers.NewICEHandlers(usecases.NewICEConfigUseCase(sessionRepo, historyRepo, testICEServers, nil))
+	status := httphandlers.NewStatusHandlers(usecases.NewStatusUseCase(sessionRepo), testStatusToken)
+	capabilities := httphandlers.NewCapabilitiesHandlers(usecases.NewCapabilitiesUseCase(testICEServers, true, true, false, false, false, false))
+	chat := httphandlers.NewChatHandlers(usecases.NewChatUseCase(sessionRepo, historyRepo, broker))
+	negotiation := httphandlers.NewNegotiationHandlers(usecases.NewNegotiationUseCase(sessionRepo, historyRepo, broker))
+	files := httphandlers.NewFileHandlers(usecases.NewFileUseCase(repository.NewMemoryFileRepository(), sessionRepo, historyRepo, broker, testFileRelayLimit), testFileRelayLimit)
+	stats := httphandlers.NewStatsHandlers(usecases.NewStatsUseCase(repository.NewMemoryStatsRepository(), sessionRepo, historyRepo, nil), nil)
+	rooms := httphandlers.NewRoomHandlers(usecases.NewRoomUseCase(repository.NewMemoryRoomRepository(), sessionRepo, historyRepo))
+
+	mux := http.NewServeMux()
+	router := httphandlers.NewRouter(mux)
+	router.API("/new", api.HandleNewToken)
+	router.API("/offer", api.HandleOffer)
+	router.API("/answer", api.HandleAnswer)
+	router.API("/session", api.HandleSession)
+	router.API("/info", api.HandleInfo)
+	router.API("/capabilities", capabilities.HandleCapabilities)
+	router.API("/sessions/{token}/heartbeat", api.HandleHeartbeat)
+	router.API("/sessions/{token}/end", api.HandleEndSession)
+	router.API("/session/extend", api.HandleExtendSession)
+	router.API("/pause", api.HandlePause)
+	router.API("/sessions/{token}/resume", api.HandleResumeSession)
+	router.API("/sessions/{token}/viewers/{viewer}/kick", api.HandleKickViewer)
+	router.API("/sessions/{token}/publish", api.HandlePublish)
+	router.API("/sessions/{token}/layer", api.HandleSelectLayer)
+	router.API("/sessions/{token}/events", eventHandlers.HandleEvents)
+	router.API("/sessions/{token}/ice-config", ice.HandleICEConfig)
+	router.API("/sessions/{token}/turn-credentials", ice.HandleTURNCredentials)
+	router.API("/sessions/{token}/queue", queue.HandleQueue)
+	router.API("/sessions/{token}/leave", queue.HandleReleaseViewer)
+	router.API("/sessions/{token}/summary", history.HandleSummary)
+	router.API("/sessions/{token}/notes", history.HandleNotes)
+	router.API("/sessions/{token}/quality", settings.HandleQualityRequest)
+	router.API("/rooms/{name}", rooms.HandleRoom)
+	router.API("/chat", chat.HandleChat)
+	router.API("/sessions/{token}/negotiation", negotiation.HandleNegotiation)
+	router.API("/sessions/{token}/files", files.HandleFiles)
+	router.API("/sessions/{token}/files/{id}", files.HandleFile)
+	router.API("/status", status.HandleStatus)
+	router.API("/stats", stats.HandleStats)
+
+	server := httptest.NewServer(httphandlers.NewRequestLogger(nil, mux))
+	t.Cleanup(func() {
+		broker.Close()
+		server.Close()
+	})
+	return NewClient(server.URL, server.Client())
+}
+
+func TestClient_SignalingWorkflow(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	info, err := c.Info(ctx)
+	if err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	if info.Version != "1.0.0" {
+		t.Errorf("Expected version 1.0.0, got %s", info.Version)
+	}
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{Name: "Automation"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if _, err := c.GetOffer(ctx, session.Token, "", ""); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 before the offer is posted, got %v", err)
+	}
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, offer); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+
+	received, err := c.WaitForOffer(ctx, session.Token, "", "")
+	if err != nil {
+		t.Fatalf("WaitForOffer failed: %v", err)
+	}
+	if received.SDP != offer.SDP {
+		t.Errorf("Expected offer SDP %q, got %q", offer.SDP, received.SDP)
+	}
+
+	answer := &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("v=0 answer")}
+	if err := c.SubmitAnswer(ctx, session.Token, "", "", answer); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+
+	gotAnswer, err := c.WaitForAnswer(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("WaitForAnswer failed: %v", err)
+	}
+	if gotAnswer.SDP != answer.SDP {
+		t.Errorf("Expected answer SDP %q, got %q", answer.SDP, gotAnswer.SDP)
+	}
+
+	if err := c.RequestQuality(ctx, session.Token, 15); err != nil {
+		t.Errorf("RequestQuality failed: %v", err)
+	}
+	if err := c.Heartbeat(ctx, session.Token, 4096); err != nil {
+		t.Errorf("Heartbeat failed: %v", err)
+	}
+	if err := c.EndSession(ctx, session.Token, session.SenderKey); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+
+	if _, err := c.UpdateNotes(ctx, session.Token, "", "defaced"); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 for notes without the sender key, got %v", err)
+	}
+	summary, err := c.UpdateNotes(ctx, session.Token, session.SenderKey, "automated run")
+	if err != nil {
+		t.Fatalf("UpdateNotes failed: %v", err)
+	}
+	if summary.Name != "Automation" || summary.Notes != "automated run" {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+
+	if _, err := c.GetOffer(ctx, session.Token, "", ""); StatusCode(err) != 410 {
+		t.Errorf("Expected 410 after the session ended, got %v", err)
+	}
+}
+
+func TestClient_Events(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	stream, err := c.Events(ctx, session.Token, "")
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+
+	// The stream opens with the current queue
+	event := <-stream
+	if event.Type != entities.EventQueueChanged {
+		t.Errorf("Expected initial queue event, got %q", event.Type)
+	}
+
+	if err := c.RequestQuality(ctx, session.Token, 15); err != nil {
+		t.Fatalf("RequestQuality failed: %v", err)
+	}
+
+	event = <-stream
+	if event.Type != entities.EventQualityRequest {
+		t.Fatalf("Expected quality event, got %q", event.Type)
+	}
+	var data struct {
+		MaxFrameRate int `json:"maxFrameRate"`
+	}
+	if err := json.Unmarshal(event.Data.(json.RawMessage), &data); err != nil {
+		t.Fatalf("Invalid event data: %v", err)
+	}
+	if data.MaxFrameRate != 15 {
+		t.Errorf("Expected max frame rate 15, got %d", data.MaxFrameRate)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	c := newTestServer(t)
+
+	err := c.EndSession(context.Background(), "missing", "")
+	if StatusCode(err) != 404 {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+	if err.(*APIError).Message != "session not found" {
+		t.Errorf("Expected server message, got %q", err.(*APIError).Message)
+	}
+	if requestID := err.(*APIError).RequestID; requestID == "" || !strings.Contains(err.Error(), requestID) {
+		t.Errorf("Expected the request ID in %q", err.Error())
+	}
+}
+
+func TestClient_PublishStream(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	// The test server runs without an SFU, so sessions stay peer-to-peer
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{SFU: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if session.SFU {
+		t.Error("Expected SFU to be off without a relay")
+	}
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}
+	if _, err := c.PublishStream(ctx, session.Token, session.SenderKey, offer); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for a peer-to-peer session, got %v", err)
+	}
+	if _, err := c.PublishStream(ctx, session.Token, session.SenderKey, nil); StatusCode(err) != 400 {
+		t.Errorf("Expected 400 without an offer, got %v", err)
+	}
+	if err := c.SelectLayer(ctx, session.Token, "viewer-1", entities.LayerLow); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 picking a layer of a peer-to-peer session, got %v", err)
+	}
+	if err := c.SelectLayer(ctx, session.Token, "viewer-1", "ultra"); StatusCode(err) != 400 {
+		t.Errorf("Expected 400 for an unknown layer, got %v", err)
+	}
+}
+
+func TestClient_PIN(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if session.PIN == "" {
+		t.Fatal("Expected a PIN")
+	}
+
+	if _, err := c.GetOffer(ctx, session.Token, "", ""); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without PIN, got %v", err)
+	}
+	if _, err := c.JoinQueue(ctx, session.Token, "wrong"); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 with wrong PIN, got %v", err)
+	}
+	if _, err := c.JoinQueue(ctx, session.Token, session.PIN); err != nil {
+		t.Errorf("JoinQueue with PIN failed: %v", err)
+	}
+}
+
+func TestClient_KickViewer(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+
+	if err := c.KickViewer(ctx, session.Token, "viewer-1", "wrong-key"); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 with a wrong sender key, got %v", err)
+	}
+	if err := c.KickViewer(ctx, session.Token, "viewer-1", session.SenderKey); err != nil {
+		t.Fatalf("KickViewer failed: %v", err)
+	}
+	if _, err := c.GetOffer(ctx, session.Token, "viewer-1", ""); StatusCode(err) != 410 {
+		t.Errorf("Expected 410 for the removed viewer, got %v", err)
+	}
+	if _, err := c.GetOffer(ctx, session.Token, "viewer-2", ""); err != nil {
+		t.Errorf("Expected other viewers to get the offer, got %v", err)
+	}
+}
+
+func TestClient_GetICEConfig(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if _, err := c.GetICEConfig(ctx, session.Token, ""); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without PIN, got %v", err)
+	}
+	if _, err := c.GetICEConfig(ctx, "missing", ""); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for an unknown session, got %v", err)
+	}
+
+	config, err := c.GetICEConfig(ctx, session.Token, session.PIN)
+	if err != nil {
+		t.Fatalf("GetICEConfig failed: %v", err)
+	}
+	if !reflect.DeepEqual(config.ICEServers, testICEServers) {
+		t.Errorf("Expected %+v, got %+v", testICEServers, config.ICEServers)
+	}
+}
+
+func TestClient_GetTURNCredentials(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	// The test server's TURN servers all carry static credentials
+	if _, err := c.GetTURNCredentials(ctx, session.Token, ""); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 without a shared secret, got %v", err)
+	}
+}
+
+func TestClient_Capabilities(t *testing.T) {
+	c := newTestServer(t)
+
+	capabilities, err := c.Capabilities(context.Background())
+	if err != nil {
+		t.Fatalf("Capabilities failed: %v", err)
+	}
+	if !capabilities.TURN.Enabled || !capabilities.StatusAPI.Enabled {
+		t.Errorf("Expected TURN and the status API to be enabled, got %+v", capabilities)
+	}
+	if capabilities.SFU.Enabled || capabilities.SFU.Reason == "" {
+		t.Errorf("Expected the SFU to be disabled with a reason, got %+v", capabilities.SFU)
+	}
+}
+
+func TestClient_Chat(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	plain, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if _, err := c.SendChatMessage(ctx, plain.Token, "", entities.ChatFromSender, "hello"); StatusCode(err) != 404 {
+		t.Fatalf("Expected 404 for a session without chat, got %v", err)
+	}
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{Chat: true, RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if !session.Chat {
+		t.Fatal("Expected the session to have chat")
+	}
+
+	events, err := c.Events(ctx, session.Token, "")
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	sent, err := c.SendChatMessage(ctx, session.Token, session.PIN, entities.ChatFromViewer, "can you zoom in?")
+	if err != nil {
+		t.Fatalf("SendChatMessage failed: %v", err)
+	}
+	// The stream opens with the queue; the message follows
+	timeout := time.After(5 * time.Second)
+	for received := false; !received; {
+		select {
+		case event := <-events:
+			received = event.Type == entities.EventChatMessage
+		case <-timeout:
+			t.Fatal("Expected the message on the event stream")
+		}
+	}
+
+	if _, err := c.GetChatMessages(ctx, session.Token, "", 0); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without the PIN, got %v", err)
+	}
+	relayed, err := c.GetChatMessages(ctx, session.Token, session.PIN, 0)
+	if err != nil {
+		t.Fatalf("GetChatMessages failed: %v", err)
+	}
+	if len(relayed.Messages) != 1 || relayed.Messages[0].ID != sent.ID || relayed.Messages[0].Text != "can you zoom in?" {
+		t.Errorf("Expected the relayed message, got %+v", relayed.Messages)
+	}
+	if relayed, err := c.GetChatMessages(ctx, session.Token, session.PIN, sent.ID); err != nil || len(relayed.Messages) != 0 {
+		t.Errorf("Expected no newer messages, got %+v, %v", relayed, err)
+	}
+}
+
+func TestClient_Files(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	shared, err := c.ShareFile(ctx, session.Token, "notes.txt", "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+	if shared.Name != "notes.txt" || shared.Size != 5 {
+		t.Errorf("Unexpected file %+v", shared)
+	}
+	if _, err := c.ShareFile(ctx, session.Token, "big.bin", "", strings.NewReader(strings.Repeat("x", testFileRelayLimit))); StatusCode(err) != 413 {
+		t.Errorf("Expected 413 past the relay limit, got %v", err)
+	}
+
+	if _, err := c.ListFiles(ctx, session.Token, ""); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without the PIN, got %v", err)
+	}
+	listed, err := c.ListFiles(ctx, session.Token, session.PIN)
+	if err != nil || len(listed.Files) != 1 || listed.Files[0].ID != shared.ID {
+		t.Fatalf("Expected the shared file, got %+v, %v", listed, err)
+	}
+
+	body, err := c.DownloadFile(ctx, session.Token, session.PIN, shared.ID)
+	if err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	defer body.Close()
+	if content, _ := io.ReadAll(body); string(content) != "hello" {
+		t.Errorf("Expected the file content, got %q", content)
+	}
+	if _, err := c.DownloadFile(ctx, session.Token, session.PIN, "missing"); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for a missing file, got %v", err)
+	}
+}
+
+func TestClient_Rooms(t *testing.T) {
+	c := newTestServer(t)
+	ctx := context.Background()
+
+	first, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	claimed, err := c.ClaimRoom(ctx, "design-review", first.Token, "")
+	if err != nil {
+		t.Fatalf("ClaimRoom failed: %v", err)
+	}
+
+	// The next session moves the room only with its key
+	second, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if _, err := c.ClaimRoom(ctx, "design-review", second.Token, ""); StatusCode(err) != 403 {
+		t.Fatalf("Expected 403 without the room key, got %v", err)
+	}
+	if _, err := c.ClaimRoom(ctx, "design-review", second.Token, claimed.Key); err != nil {
+		t.Fatalf("ClaimRoom with the key failed: %v", err)
+	}
+
+	room, err := c.GetRoom(ctx, "Design-Review")
+	if err != nil {
+		t.Fatalf("GetRoom failed: %v", err)
+	}
+	if !room.Live || room.Token != second.Token {
+		t.Errorf("Expected the room to lead to the second session, got %+v", room)
+	}
+
+	if err := c.EndSession(ctx, second.Token, second.SenderKey); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if room, err := c.GetRoom(ctx, "design-review"); err != nil || room.Live || room.Token != "" {
+		t.Errorf("Expected an idle room after the session ended, got %+v, %v", room, err)
+	}
+	if _, err := c.GetRoom(ctx, "standup"); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for an unknown room, got %v", err)
+	}
+}
+
+func TestClient_UploadStats(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	sample := &dto.RecordStatsRequest{Token: session.Token, Role: entities.StatsRoleSender, Bitrate: 2_000_000, FrameRate: 30, RTTMillis: 25}
+	if err := c.UploadStats(ctx, sample); err != nil {
+		t.Fatalf("UploadStats failed: %v", err)
+	}
+
+	invalid := &dto.RecordStatsRequest{Token: session.Token, Role: entities.StatsRoleSender, PacketLoss: 2}
+	if err := c.UploadStats(ctx, invalid); StatusCode(err) != 400 {
+		t.Errorf("Expected 400 for a packet loss above 1, got %v", err)
+	}
+
+	if err := c.EndSession(ctx, session.Token, session.SenderKey); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if err := c.UploadStats(ctx, sample); StatusCode(err) != 410 {
+		t.Errorf("Expected 410 once the session ended, got %v", err)
+	}
+}
+
+func TestClient_ResumeSession(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if session.SenderKey == "" {
+		t.Fatal("Expected a sender key for the new session")
+	}
+
+	resumed, err := c.ResumeSession(ctx, session.Token, session.SenderKey)
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if resumed.Token != session.Token || resumed.PIN != session.PIN {
+		t.Errorf("Expected the session's options back, got %+v", resumed)
+	}
+
+	if _, err := c.ResumeSession(ctx, session.Token, "wrong-key"); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 for a wrong sender key, got %v", err)
+	}
+}
+
+func TestClient_GetSession(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{Name: "Standup"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	detail, err := c.GetSession(ctx, session.Token)
+	if err != nil {
+		t.Fatalf("GetSession failed: %v", err)
+	}
+	if detail.Status != entities.SessionStatusPending || detail.HasOffer || detail.Name != "Standup" {
+		t.Errorf("Expected a session waiting for its offer, got %+v", detail)
+	}
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, offer); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	if detail, err = c.GetSession(ctx, session.Token); err != nil || !detail.HasOffer || detail.OfferedAt == nil || detail.HasAnswer {
+		t.Errorf("Expected the offer without an answer, got %+v (%v)", detail, err)
+	}
+
+	// Unlike the signaling calls, an ended session is still described
+	if err := c.EndSession(ctx, session.Token, session.SenderKey); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if detail, err = c.GetSession(ctx, session.Token); err != nil || detail.Status != entities.SessionStatusEnded {
+		t.Errorf("Expected the session reported ended, got %+v (%v)", detail, err)
+	}
+
+	if _, err := c.GetSession(ctx, "unknown-token"); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for an unknown session, got %v", err)
+	}
+}
+
+func TestClient_ResetOffer(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+
+	if err := c.ResetOffer(ctx, session.Token, session.SenderKey); err != nil {
+		t.Fatalf("ResetOffer failed: %v", err)
+	}
+	if _, err := c.GetOffer(ctx, session.Token, "", ""); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for the cleared offer, got %v", err)
+	}
+	if detail, err := c.GetSession(ctx, session.Token); err != nil || detail.Status != entities.SessionStatusPending {
+		t.Errorf("Expected the session back to pending, got %+v (%v)", detail, err)
+	}
+
+	// The sender carries on under the same token
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 other window")}); err != nil {
+		t.Fatalf("SubmitOffer after reset failed: %v", err)
+	}
+	if offer, err := c.GetOffer(ctx, session.Token, "", ""); err != nil || offer.SDP != testSDP("v=0 other window") {
+		t.Errorf("Expected the new offer, got %+v (%v)", offer, err)
+	}
+}
+
+func TestClient_ICERestart(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}
+	if err := c.RestartICE(ctx, session.Token, session.SenderKey, offer); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for a restart without a viewer, got %v", err)
+	}
+
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, offer); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	if err := c.SubmitAnswer(ctx, session.Token, "phone", "", &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("v=0 answer")}); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+
+	if err := c.RestartICE(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 restart")}); err != nil {
+		t.Fatalf("RestartICE failed: %v", err)
+	}
+	if _, err := c.GetAnswer(ctx, session.Token); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 until the viewer answers the restart, got %v", err)
+	}
+
+	restarted := &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("v=0 restarted")}
+	if err := c.SubmitAnswer(ctx, session.Token, "phone", "", restarted); StatusCode(err) != 409 {
+		t.Errorf("Expected 409 for an answer to the old offer, got %v", err)
+	}
+	if err := c.AnswerICERestart(ctx, session.Token, "phone", "", 1, restarted); err != nil {
+		t.Fatalf("AnswerICERestart failed: %v", err)
+	}
+	if detail, err := c.GetSession(ctx, session.Token); err != nil || detail.ICEGeneration != 1 || !detail.HasAnswer {
+		t.Errorf("Expected the restarted handshake complete, got %+v (%v)", detail, err)
+	}
+}
+
+func TestClient_Negotiation(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	offer := &entities.SessionDescription{Type: "offer", SDP: testSDP("v=0 audio added")}
+	request := &dto.SendNegotiationMessageRequest{From: entities.NegotiationFromSender, ViewerID: "phone", Description: offer}
+	if _, err := c.SendNegotiationMessage(ctx, session.Token, request); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 before the viewer connected, got %v", err)
+	}
+
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	if err := c.SubmitAnswer(ctx, session.Token, "phone", "", &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("v=0 answer")}); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+
+	events, err := c.Events(ctx, session.Token, "phone")
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	sent, err := c.SendNegotiationMessage(ctx, session.Token, request)
+	if err != nil {
+		t.Fatalf("SendNegotiationMessage failed: %v", err)
+	}
+	for received := false; !received; {
+		select {
+		case event := <-events:
+			received = event.Type == entities.EventNegotiation
+		case <-ctx.Done():
+			t.Fatal("Expected the offer on the viewer's event stream")
+		}
+	}
+
+	answer := &dto.SendNegotiationMessageRequest{From: entities.NegotiationFromViewer, ViewerID: "phone", Description: &entities.SessionDescription{Type: "answer", SDP: testSDP("v=0 audio accepted")}}
+	if _, err := c.SendNegotiationMessage(ctx, session.Token, answer); err != nil {
+		t.Fatalf("SendNegotiationMessage failed: %v", err)
+	}
+	messages, err := c.GetNegotiationMessages(ctx, session.Token, "", "phone", sent.Seq)
+	if err != nil {
+		t.Fatalf("GetNegotiationMessages failed: %v", err)
+	}
+	if len(messages.Messages) != 1 || messages.Messages[0].Description.SDP != testSDP("v=0 audio accepted") {
+		t.Errorf("Expected the viewer's answer, got %+v", messages.Messages)
+	}
+	if _, err := c.GetNegotiationMessages(ctx, session.Token, "", "laptop", 0); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for a viewer not connected, got %v", err)
+	}
+}
+
+func TestClient_ExtendSession(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if _, err := c.ExtendSession(ctx, session.Token, ""); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without the sender key, got %v", err)
+	}
+	extended, err := c.ExtendSession(ctx, session.Token, session.SenderKey)
+	if err != nil {
+		t.Fatalf("ExtendSession failed: %v", err)
+	}
+	if extended.ExpiresInSeconds < 29*60 || time.Until(extended.ExpiresAt) < 29*time.Minute {
+		t.Errorf("Expected the full 30-minute expiry, got %+v", extended)
+	}
+
+	if _, err := c.ExtendSession(ctx, "unknown-token", session.SenderKey); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for an unknown session, got %v", err)
+	}
+
+	if err := c.EndSession(ctx, session.Token, session.SenderKey); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if _, err := c.ExtendSession(ctx, session.Token, session.SenderKey); StatusCode(err) != 410 {
+		t.Errorf("Expected 410 once the session ended, got %v", err)
+	}
+}
+
+func TestClient_PauseSession(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	if err := c.PauseSession(ctx, session.Token, "", true); StatusCode(err) != 403 {
+		t.Errorf("Expected 403 without the sender key, got %v", err)
+	}
+	if err := c.PauseSession(ctx, session.Token, session.SenderKey, true); err != nil {
+		t.Fatalf("PauseSession failed: %v", err)
+	}
+	if err := c.PauseSession(ctx, session.Token, session.SenderKey, false); err != nil {
+		t.Fatalf("PauseSession failed to resume: %v", err)
+	}
+	if err := c.PauseSession(ctx, "unknown-token", session.SenderKey, true); StatusCode(err) != 404 {
+		t.Errorf("Expected 404 for an unknown session, got %v", err)
+	}
+}
+
+func TestClient_Status(t *testing.T) {
+	c := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, _, err := c.Status(ctx, "wrong", "", 0); StatusCode(err) != 401 {
+		t.Fatalf("Expected 401 with wrong token, got %v", err)
+	}
+
+	session, err := c.CreateSession(ctx, &dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	status, etag, err := c.Status(ctx, testStatusToken, "", 0)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Viewing || status.Sessions != 1 || etag == "" {
+		t.Errorf("Expected one idle session, got %+v (etag %q)", status, etag)
+	}
+
+	unchanged, sameETag, err := c.Status(ctx, testStatusToken, etag, time.Second)
+	if err != nil {
+		t.Fatalf("Status long-poll failed: %v", err)
+	}
+	if unchanged != nil || sameETag != etag {
+		t.Errorf("Expected no change, got %+v (etag %q)", unchanged, sameETag)
+	}
+
+	if err := c.SubmitOffer(ctx, session.Token, session.SenderKey, &entities.WebRTCOffer{Type: "offer", SDP: testSDP("v=0 offer")}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = c.SubmitAnswer(ctx, session.Token, "", "", &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("v=0 answer")})
+	}()
+
+	status, _, err = c.Status(ctx, testStatusToken, etag, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Status long-poll failed: %v", err)
+	}
+	if status == nil || !status.Viewing || status.Viewers != 1 {
+		t.Errorf("Expected a connected viewer, got %+v", status)
+	}
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := NewClient(server.URL, server.Client())
+	if _, err := c.Info(ctx); err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	c.SetBearerToken("api-jwt")
+	if _, err := c.Info(ctx); err != nil {
+		t.Fatalf("Info failed: %v", err)
+	}
+	// The status token is sent instead of the client's
+	_, _, _ = c.Status(ctx, testStatusToken, "", 0)
+
+	expected := []string{"", "Bearer api-jwt", "Bearer " + testStatusToken}
+	if !slices.Equal(authorization, expected) {
+		t.Errorf("Expected Authorization headers %q, got %q", expected, authorization)
+	}
+}
@@ -0,0 +1,153 @@
+// Package control defines the remote-control input events a viewer sends over
+// the "control" data channel, and validates them before a sender injects them.
+//
+// The sender opens the channel only when it lets the viewer control the
+// machine, so a viewer cannot turn remote control on by itself. Messages are
+// small JSON objects such as {"type":"pointer-down","x":0.5,"y":0.25,"button":0};
+// coordinates are fractions of the shared screen so they do not depend on the
+// size the viewer displays it at.
+package control
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChannelLabel is the label of the data channel carrying control events
+const ChannelLabel = "control"
+
+// MaxMessageSize bounds a single control message; real ones are far smaller
+const MaxMessageSize = 256
+
+// MaxWheelSteps bounds the scroll notches carried by one wheel event
+const MaxWheelSteps = 10
+
+// Event types
+const (
+	EventPointerMove = "pointer-move"
+	EventPointerDown = "pointer-down"
+	EventPointerUp   = "pointer-up"
+	EventWheel       = "wheel"
+	EventKeyDown     = "key-down"
+	EventKeyUp       = "key-up"
+)
+
+// Mouse buttons, numbered like the DOM's MouseEvent.button
+const (
+	ButtonLeft   = 0
+	ButtonMiddle = 1
+	ButtonRight  = 2
+)
+
+// ErrInvalidEvent is returned for messages that are not a valid control event
+var ErrInvalidEvent = errors.New("invalid control event")
+
+// namedKeys are the DOM KeyboardEvent.key names accepted besides single
+// printable characters
+var namedKeys = map[string]bool{
+	"Enter": true, "Backspace": true, "Tab": true, "Escape": true, "Delete": true,
+	"ArrowUp": true, "ArrowDown": true, "ArrowLeft": true, "ArrowRight": true,
+	"Home": true, "End": true, "PageUp": true, "PageDown": true,
+	"Shift": true, "Control": true, "Alt": true, "Meta": true,
+}
+
+// Event is one input event from the viewer
+type Event struct {
+	Type string `json:"type"`
+
+	// X and Y locate pointer events as fractions of the screen's width and
+	// height, from the top left corner
+	X float64 `json:"x,omitempty"`
+	Y float64 `json:"y,omitempty"`
+
+	// Button is the mouse button of pointer-down and pointer-up
+	Button int `json:"button,omitempty"`
+
+	// DeltaX and DeltaY are the scroll notches of a wheel event; positive
+	// values scroll right and down
+	DeltaX int `json:"dx,omitempty"`
+	DeltaY int `json:"dy,omitempty"`
+
+	// Key is the DOM key name of key-down and key-up: a single printable
+	// character or one of the named keys such as "Enter" or "ArrowUp"
+	Key string `json:"key,omitempty"`
+}
+
+// Parse decodes and validates one control message
+func Parse(data []byte) (Event, error) {
+	var event Event
+	if len(data) > MaxMessageSize {
+		return event, fmt.Errorf("%w: message of %d bytes is too large", ErrInvalidEvent, len(data))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&event); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if err := event.Validate(); err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+// Validate checks that the event is one the sender can safely replay
+func (e Event) Validate() error {
+	switch e.Type {
+	case EventPointerMove:
+		return e.validatePosition()
+	case EventPointerDown, EventPointerUp:
+		if e.Button < ButtonLeft || e.Button > ButtonRight {
+			return fmt.Errorf("%w: unknown button %d", ErrInvalidEvent, e.Button)
+		}
+		return e.validatePosition()
+	case EventWheel:
+		if abs(e.DeltaX) > MaxWheelSteps || abs(e.DeltaY) > MaxWheelSteps {
+			return fmt.Errorf("%w: scrolls more than %d steps", ErrInvalidEvent, MaxWheelSteps)
+		}
+		if e.DeltaX == 0 && e.DeltaY == 0 {
+			return fmt.Errorf("%w: wheel event without scrolling", ErrInvalidEvent)
+		}
+		return e.validatePosition()
+	case EventKeyDown, EventKeyUp:
+		if !ValidKey(e.Key) {
+			return fmt.Errorf("%w: unsupported key %q", ErrInvalidEvent, e.Key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidEvent, e.Type)
+	}
+}
+
+// IsPrintable reports whether the event's key is a character rather than a named key
+func (e Event) IsPrintable() bool {
+	return utf8.RuneCountInString(e.Key) == 1 && !namedKeys[e.Key]
+}
+
+// ValidKey reports whether key is a single printable character or a named key
+func ValidKey(key string) bool {
+	if namedKeys[key] {
+		return true
+	}
+	r, size := utf8.DecodeRuneInString(key)
+	return size == len(key) && r != utf8.RuneError && unicode.IsPrint(r)
+}
+
+// validatePosition checks that the pointer is on the screen
+func (e Event) validatePosition() error {
+	if e.X < 0 || e.X > 1 || e.Y < 0 || e.Y > 1 {
+		return fmt.Errorf("%w: position %.3f,%.3f is off the screen", ErrInvalidEvent, e.X, e.Y)
+	}
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
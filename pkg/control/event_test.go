@@ -0,0 +1,128 @@
+package control
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		expected      Event
+		expectedError bool
+	}{
+		{
+			name:     "pointer move",
+			message:  `{"type":"pointer-move","x":0.5,"y":0.25}`,
+			expected: Event{Type: EventPointerMove, X: 0.5, Y: 0.25},
+		},
+		{
+			name:     "right button down",
+			message:  `{"type":"pointer-down","x":1,"y":0,"button":2}`,
+			expected: Event{Type: EventPointerDown, X: 1, Button: ButtonRight},
+		},
+		{
+			name:     "wheel",
+			message:  `{"type":"wheel","x":0.1,"y":0.1,"dy":-3}`,
+			expected: Event{Type: EventWheel, X: 0.1, Y: 0.1, DeltaY: -3},
+		},
+		{
+			name:     "printable key",
+			message:  `{"type":"key-down","key":"é"}`,
+			expected: Event{Type: EventKeyDown, Key: "é"},
+		},
+		{
+			name:     "named key",
+			message:  `{"type":"key-up","key":"ArrowLeft"}`,
+			expected: Event{Type: EventKeyUp, Key: "ArrowLeft"},
+		},
+		{
+			name:          "unknown type",
+			message:       `{"type":"shell","key":"a"}`,
+			expectedError: true,
+		},
+		{
+			name:          "off screen",
+			message:       `{"type":"pointer-move","x":1.5,"y":0.5}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown button",
+			message:       `{"type":"pointer-up","x":0.5,"y":0.5,"button":7}`,
+			expectedError: true,
+		},
+		{
+			name:          "wheel too far",
+			message:       `{"type":"wheel","x":0.5,"y":0.5,"dy":50}`,
+			expectedError: true,
+		},
+		{
+			name:          "wheel without scrolling",
+			message:       `{"type":"wheel","x":0.5,"y":0.5}`,
+			expectedError: true,
+		},
+		{
+			name:          "several characters",
+			message:       `{"type":"key-down","key":"rm -rf"}`,
+			expectedError: true,
+		},
+		{
+			name:          "control character",
+			message:       `{"type":"key-down","key":"\u0007"}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			message:       `{"type":"key-down","key":"a","command":"x"}`,
+			expectedError: true,
+		},
+		{
+			name:          "not JSON",
+			message:       `pointer-move`,
+			expectedError: true,
+		},
+		{
+			name:          "too large",
+			message:       `{"type":"key-down","key":"a"` + strings.Repeat(" ", MaxMessageSize) + `}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := Parse([]byte(tt.message))
+			if tt.expectedError {
+				if !errors.Is(err, ErrInvalidEvent) {
+					t.Errorf("Expected ErrInvalidEvent, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if event != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, event)
+			}
+		})
+	}
+}
+
+func TestEvent_IsPrintable(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{key: "a", expected: true},
+		{key: " ", expected: true},
+		{key: "Enter", expected: false},
+		{key: "Shift", expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := (Event{Type: EventKeyDown, Key: tt.key}).IsPrintable(); got != tt.expected {
+			t.Errorf("IsPrintable(%q) = %v, expected %v", tt.key, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package entities
+
+// Constraints caps what the sender encodes, a step on the adaptation ladder
+type Constraints struct {
+	Level int `json:"level"`
+
+	// ScaleResolutionDownBy divides the captured width and height, as the
+	// WebRTC encoding parameter of the same name; 1 keeps them
+	ScaleResolutionDownBy float64 `json:"scaleResolutionDownBy"`
+
+	// MaxFrameRate caps the frames per second sent; 0 leaves them alone
+	MaxFrameRate int `json:"maxFrameRate"`
+}
+
+// MaxAdaptationLevel is the lowest step of the adaptation ladder
+const MaxAdaptationLevel = 3
+
+// adaptationLadder lists the constraints from none to the lowest: the frame
+// rate goes first, since a shared screen stays readable at 15 fps, and the
+// resolution after
+var adaptationLadder = [MaxAdaptationLevel + 1]Constraints{
+	{Level: 0, ScaleResolutionDownBy: 1},
+	{Level: 1, ScaleResolutionDownBy: 1, MaxFrameRate: 15},
+	{Level: 2, ScaleResolutionDownBy: 2, MaxFrameRate: 15},
+	{Level: 3, ScaleResolutionDownBy: 2, MaxFrameRate: 8},
+}
+
+// ConstraintsAt returns the constraints of a step of the ladder, clamped to
+// its ends
+func ConstraintsAt(level int) Constraints {
+	return adaptationLadder[max(0, min(level, MaxAdaptationLevel))]
+}
+
+// Reasons for suggesting constraints
+const (
+	AdaptationPacketLoss = "packet-loss"
+	AdaptationLowFPS     = "low-fps"
+	AdaptationRecovered  = "recovered"
+)
+
+// lowFrameRateRatio is the share of the sender's frame rate below which a
+// viewer is falling behind; comparing with the sender's rate keeps a still
+// screen, which sends few frames to begin with, from counting
+const lowFrameRateRatio = 0.5
+
+// minAdaptationFrameRate is the sender frame rate from which a viewer's lower
+// rate counts as falling behind
+const minAdaptationFrameRate = 10
+
+// ConnectionSummary is what the adaptation policy knows of a connection over
+// a window: the viewer's average packet loss and frames decoded per second,
+// and the average frames per second the sender sent
+type ConnectionSummary struct {
+	PacketLoss      float64
+	ViewerFrameRate float64
+	SenderFrameRate float64
+}
+
+// ConstraintSuggestion is the server's advice to a sender to encode with
+// other constraints, and why
+type ConstraintSuggestion struct {
+	Constraints
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// SuggestConstraints is the adaptation policy: from the current step of the
+// ladder and a summary of the connection it returns the step to move to and
+// why, or the current step and "" to stay. Sustained packet loss, or a viewer
+// decoding well under the frames sent, moves one step down; recover, when the
+// caller has waited long enough since the last step, allows one step back up
+// once the loss is low and the viewer keeps up.
+func SuggestConstraints(level int, summary ConnectionSummary, recover bool) (int, string) {
+	fallingBehind := summary.SenderFrameRate >= minAdaptationFrameRate &&
+		summary.ViewerFrameRate < summary.SenderFrameRate*lowFrameRateRatio
+
+	switch {
+	case summary.PacketLoss >= poorPacketLoss:
+		if level < MaxAdaptationLevel {
+			return level + 1, AdaptationPacketLoss
+		}
+	case fallingBehind:
+		if level < MaxAdaptationLevel {
+			return level + 1, AdaptationLowFPS
+		}
+	case recover && level > 0 && summary.PacketLoss < fairPacketLoss:
+		return level - 1, AdaptationRecovered
+	}
+	return level, ""
+}
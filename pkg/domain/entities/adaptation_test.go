@@ -0,0 +1,93 @@
+package entities
+
+import "testing"
+
+func TestConstraintsAt(t *testing.T) {
+	if c := ConstraintsAt(0); c.ScaleResolutionDownBy != 1 || c.MaxFrameRate != 0 {
+		t.Errorf("Expected no constraints at level 0, got %+v", c)
+	}
+	if c := ConstraintsAt(-1); c.Level != 0 {
+		t.Errorf("Expected levels below the ladder clamped to 0, got %+v", c)
+	}
+	if c := ConstraintsAt(MaxAdaptationLevel + 5); c.Level != MaxAdaptationLevel {
+		t.Errorf("Expected levels past the ladder clamped to the lowest step, got %+v", c)
+	}
+	for level := 1; level <= MaxAdaptationLevel; level++ {
+		prev, c := ConstraintsAt(level-1), ConstraintsAt(level)
+		if c.Level != level || c.ScaleResolutionDownBy < prev.ScaleResolutionDownBy || c.MaxFrameRate == 0 ||
+			(prev.MaxFrameRate != 0 && c.MaxFrameRate > prev.MaxFrameRate) {
+			t.Errorf("Expected level %d to constrain at least as much as the one above, got %+v after %+v", level, c, prev)
+		}
+	}
+}
+
+func TestSuggestConstraints(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          int
+		summary        ConnectionSummary
+		recover        bool
+		expectedLevel  int
+		expectedReason string
+	}{
+		{
+			name:          "healthy connection stays",
+			summary:       ConnectionSummary{PacketLoss: 0.002, ViewerFrameRate: 29, SenderFrameRate: 30},
+			expectedLevel: 0,
+		},
+		{
+			name:           "sustained loss steps down",
+			level:          1,
+			summary:        ConnectionSummary{PacketLoss: 0.08, ViewerFrameRate: 15, SenderFrameRate: 15},
+			expectedLevel:  2,
+			expectedReason: AdaptationPacketLoss,
+		},
+		{
+			name:          "loss at the lowest step stays",
+			level:         MaxAdaptationLevel,
+			summary:       ConnectionSummary{PacketLoss: 0.2},
+			expectedLevel: MaxAdaptationLevel,
+		},
+		{
+			name:           "viewer falling behind steps down",
+			summary:        ConnectionSummary{PacketLoss: 0.01, ViewerFrameRate: 9, SenderFrameRate: 30},
+			expectedLevel:  1,
+			expectedReason: AdaptationLowFPS,
+		},
+		{
+			name:          "still screen is not falling behind",
+			summary:       ConnectionSummary{ViewerFrameRate: 1, SenderFrameRate: 3},
+			expectedLevel: 0,
+		},
+		{
+			name:           "clean connection steps back up",
+			level:          2,
+			summary:        ConnectionSummary{PacketLoss: 0.001, ViewerFrameRate: 15, SenderFrameRate: 15},
+			recover:        true,
+			expectedLevel:  1,
+			expectedReason: AdaptationRecovered,
+		},
+		{
+			name:          "no step up before the caller allows it",
+			level:         2,
+			summary:       ConnectionSummary{ViewerFrameRate: 15, SenderFrameRate: 15},
+			expectedLevel: 2,
+		},
+		{
+			name:          "fair loss holds the step",
+			level:         2,
+			summary:       ConnectionSummary{PacketLoss: 0.02, ViewerFrameRate: 15, SenderFrameRate: 15},
+			recover:       true,
+			expectedLevel: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, reason := SuggestConstraints(tt.level, tt.summary, tt.recover)
+			if level != tt.expectedLevel || reason != tt.expectedReason {
+				t.Errorf("Expected level %d for %q, got %d for %q", tt.expectedLevel, tt.expectedReason, level, reason)
+			}
+		})
+	}
+}
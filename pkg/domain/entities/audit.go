@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"time"
+)
+
+// AuditEventType names a step in the life of a session
+type AuditEventType string
+
+// Session lifecycle steps recorded in the audit log
+const (
+	AuditSessionCreated AuditEventType = "created"
+	AuditOffer          AuditEventType = "offer"
+	AuditOfferReset     AuditEventType = "offer-reset"
+	AuditAnswer         AuditEventType = "answer"
+	AuditConnected      AuditEventType = "connected"
+	AuditExtended       AuditEventType = "extended"
+	AuditDetached       AuditEventType = "detached"
+	AuditResumed        AuditEventType = "resumed"
+	AuditPaused         AuditEventType = "paused"
+	AuditUnpaused       AuditEventType = "unpaused"
+	AuditStale          AuditEventType = "stale"
+	AuditTerminated     AuditEventType = "terminated"
+	AuditKicked         AuditEventType = "kicked"
+	AuditExpired        AuditEventType = "expired"
+	AuditError          AuditEventType = "error"
+)
+
+// AuditEvent is one entry in a session's append-only audit log
+type AuditEvent struct {
+	At    time.Time      `json:"at"`
+	Token string         `json:"token"`
+	Type  AuditEventType `json:"type"`
+
+	// ClientIP is the address of the client whose request caused the event;
+	// empty for events of the server's own, such as expiry
+	ClientIP string `json:"clientIp,omitempty"`
+
+	// User is the subject of the API token behind the request, if any
+	User string `json:"user,omitempty"`
+
+	// ViewerID is the viewer the event concerns, if any, and ViewerName the
+	// name it gave itself
+	ViewerID   string `json:"viewerId,omitempty"`
+	ViewerName string `json:"viewerName,omitempty"`
+
+	// Detail describes the event, e.g. the error of a failed request
+	Detail string `json:"detail,omitempty"`
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+)
+
+// Ban blocks a client address or range from the signaling endpoints
+type Ban struct {
+	// Network is the banned range in CIDR form; single addresses are stored
+	// as /32 or /128
+	Network   string    `json:"network"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ParseNetwork parses a CIDR range or a single IP address into its masked
+// prefix
+func ParseNetwork(network string) (netip.Prefix, error) {
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(network)
+		if addrErr != nil {
+			return netip.Prefix{}, fmt.Errorf("%q is not a CIDR range or IP address", network)
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	return prefix.Masked(), nil
+}
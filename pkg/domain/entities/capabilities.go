@@ -0,0 +1,27 @@
+package entities
+
+// AuthProviderNone means the server has no login; sessions are protected by
+// their token and optional PIN alone
+const AuthProviderNone = "none"
+
+// Capability reports whether an optional subsystem works on this deployment
+type Capability struct {
+	Enabled bool `json:"enabled"`
+	// Reason explains to users why a disabled subsystem is unavailable
+	Reason string `json:"reason,omitempty"`
+}
+
+// Capabilities describes which optional subsystems are active, so clients
+// only offer controls that will work
+type Capabilities struct {
+	TURN         Capability `json:"turn"`
+	SFU          Capability `json:"sfu"`
+	HLS          Capability `json:"hls"`
+	Recording    Capability `json:"recording"`
+	Chat         Capability `json:"chat"`
+	FileRelay    Capability `json:"fileRelay"`
+	StatusAPI    Capability `json:"statusApi"`
+	Invites      Capability `json:"invites"`
+	Profiles     Capability `json:"profiles"`
+	AuthProvider string     `json:"authProvider"`
+}
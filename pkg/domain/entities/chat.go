@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+)
+
+// Chat message authors
+const (
+	ChatFromSender = "sender"
+	ChatFromViewer = "viewer"
+)
+
+// maxChatMessages bounds the chat a session relays; the oldest messages are
+// dropped first
+const maxChatMessages = 100
+
+// ChatMessage is a chat line between the sender and a viewer. Messages sent
+// over the peers' data channel use the same form without an ID.
+type ChatMessage struct {
+	ID     int       `json:"id,omitempty"`
+	From   string    `json:"from"`
+	Text   string    `json:"text"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+// AddChatMessage appends a relayed message with the next ID and returns it
+func (s *Session) AddChatMessage(from, text string, sentAt time.Time) ChatMessage {
+	id := 1
+	if len(s.Chat) > 0 {
+		id = s.Chat[len(s.Chat)-1].ID + 1
+	}
+
+	message := ChatMessage{ID: id, From: from, Text: text, SentAt: sentAt}
+	s.Chat = append(s.Chat, message)
+	if len(s.Chat) > maxChatMessages {
+		s.Chat = s.Chat[len(s.Chat)-maxChatMessages:]
+	}
+	return message
+}
+
+// ChatSince returns the relayed messages with an ID above since
+func (s *Session) ChatSince(since int) []ChatMessage {
+	messages := []ChatMessage{}
+	for _, message := range s.Chat {
+		if message.ID > since {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
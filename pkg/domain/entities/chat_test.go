@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSession_AddChatMessage(t *testing.T) {
+	session := &Session{Token: "test-token"}
+
+	first := session.AddChatMessage(ChatFromSender, "hello", time.Now())
+	second := session.AddChatMessage(ChatFromViewer, "hi", time.Now())
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("Expected IDs 1 and 2, got %d and %d", first.ID, second.ID)
+	}
+
+	since := session.ChatSince(1)
+	if len(since) != 1 || since[0].Text != "hi" {
+		t.Errorf("Expected only the second message, got %+v", since)
+	}
+	if len(session.ChatSince(2)) != 0 {
+		t.Error("Expected no messages after the last ID")
+	}
+
+	for i := 0; i < maxChatMessages; i++ {
+		session.AddChatMessage(ChatFromSender, "more", time.Now())
+	}
+	if len(session.Chat) != maxChatMessages {
+		t.Fatalf("Expected the chat to be capped at %d messages, got %d", maxChatMessages, len(session.Chat))
+	}
+	if session.Chat[0].ID != 3 || session.Chat[maxChatMessages-1].ID != maxChatMessages+2 {
+		t.Errorf("Expected the oldest messages to be dropped, got IDs %d to %d", session.Chat[0].ID, session.Chat[maxChatMessages-1].ID)
+	}
+
+	// Messages added to a copy stay out of the original
+	clone := session.Clone()
+	clone.AddChatMessage(ChatFromViewer, "later", time.Now())
+	if len(session.ChatSince(maxChatMessages+2)) != 0 {
+		t.Error("Expected the clone's chat to be independent")
+	}
+}
@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+)
+
+// What started a cleanup run
+const (
+	CleanupTriggerSchedule = "schedule"
+	CleanupTriggerAdmin    = "admin"
+)
+
+// CleanupRun is what one garbage collection of sessions and their leftovers removed
+type CleanupRun struct {
+	StartedAt      time.Time `json:"startedAt"`
+	DurationMillis int64     `json:"durationMs"`
+	Trigger        string    `json:"trigger"`
+
+	// StaleSessions went stale for lack of sender heartbeats, IdleSessions
+	// expired early for want of a viewer and ExpiredSessions were removed
+	StaleSessions   int `json:"staleSessions"`
+	IdleSessions    int `json:"idleSessions"`
+	ExpiredSessions int `json:"expiredSessions"`
+
+	// Files, Stats and Streams count the sessions whose relayed files, stats
+	// and SFU streams were dropped
+	Files   int `json:"files"`
+	Stats   int `json:"stats"`
+	Streams int `json:"streams"`
+
+	// Follower is set when another instance of the cluster leads it, so the
+	// shared sessions were left to that instance and only this instance's
+	// files, stats and streams were collected
+	Follower bool `json:"follower,omitempty"`
+
+	// Error describes the steps that failed, if any
+	Error string `json:"error,omitempty"`
+}
+
+// CleanupStatus is the garbage collection schedule with its latest run
+type CleanupStatus struct {
+	IntervalSeconds float64     `json:"intervalSeconds"`
+	LastRun         *CleanupRun `json:"lastRun,omitempty"`
+}
+
+// IsOverdue reports whether the scheduled collection has missed several runs
+// in a row by now
+func (s *CleanupStatus) IsOverdue(now time.Time) bool {
+	if s.LastRun == nil || s.IntervalSeconds <= 0 {
+		return false
+	}
+	interval := time.Duration(s.IntervalSeconds * float64(time.Second))
+	return now.Sub(s.LastRun.StartedAt) > 3*interval
+}
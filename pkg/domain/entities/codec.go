@@ -0,0 +1,168 @@
+package entities
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// VideoCodec names a video codec a deployment or session can prefer
+type VideoCodec string
+
+const (
+	VideoCodecH264 VideoCodec = "h264"
+	VideoCodecVP8  VideoCodec = "vp8"
+	VideoCodecVP9  VideoCodec = "vp9"
+)
+
+// IsValid reports whether the codec is one of the known codecs
+func (c VideoCodec) IsValid() bool {
+	switch c {
+	case VideoCodecH264, VideoCodecVP8, VideoCodecVP9:
+		return true
+	}
+	return false
+}
+
+// ParseVideoCodec parses a codec name such as "H264" or "vp9"; an empty name
+// is no preference
+func ParseVideoCodec(name string) (VideoCodec, error) {
+	codec := VideoCodec(strings.ToLower(strings.TrimSpace(name)))
+	if codec == "" || codec.IsValid() {
+		return codec, nil
+	}
+	return "", fmt.Errorf("unknown video codec %q (want h264, vp8 or vp9)", name)
+}
+
+// CodecPreference is the video codec a session's descriptions are steered to
+type CodecPreference struct {
+	Codec VideoCodec `json:"codec,omitempty"`
+
+	// Force drops the other codecs instead of only ranking them lower
+	Force bool `json:"force,omitempty"`
+}
+
+// WithCodec returns the offer steered to pref's codec; see PreferVideoCodec
+func (o *WebRTCOffer) WithCodec(pref CodecPreference) *WebRTCOffer {
+	if o == nil || pref.Codec == "" {
+		return o
+	}
+	return &WebRTCOffer{Type: o.Type, SDP: PreferVideoCodec(o.SDP, pref.Codec, pref.Force)}
+}
+
+// WithCodec returns the answer steered to pref's codec; see PreferVideoCodec
+func (a *WebRTCAnswer) WithCodec(pref CodecPreference) *WebRTCAnswer {
+	if a == nil || pref.Codec == "" {
+		return a
+	}
+	return &WebRTCAnswer{Type: a.Type, SDP: PreferVideoCodec(a.SDP, pref.Codec, pref.Force)}
+}
+
+// PreferVideoCodec moves codec's payload types, and the retransmission types
+// that go with them, to the front of every video section of sdp. A peer sends
+// with the first format of the remote description it supports, so this picks
+// the codec for both directions. With force the other formats and their
+// attributes are dropped. A section that does not offer codec is left as it
+// is: forcing a codec the browser lacks would only break the call.
+func PreferVideoCodec(sdp string, codec VideoCodec, force bool) string {
+	if codec == "" {
+		return sdp
+	}
+
+	eol := "\n"
+	if strings.Contains(sdp, "\r\n") {
+		eol = "\r\n"
+	}
+
+	var lines, section []string
+	for _, line := range strings.Split(strings.TrimSuffix(sdp, eol), eol) {
+		if strings.HasPrefix(line, "m=") {
+			lines = append(lines, reorderVideoSection(section, codec, force)...)
+			section = nil
+		}
+		section = append(section, line)
+	}
+	lines = append(lines, reorderVideoSection(section, codec, force)...)
+	return strings.Join(lines, eol) + eol
+}
+
+// reorderVideoSection applies PreferVideoCodec to one media section, the
+// session part before the first one, or nothing
+func reorderVideoSection(section []string, codec VideoCodec, force bool) []string {
+	if len(section) == 0 || !strings.HasPrefix(section[0], "m=video ") {
+		return section
+	}
+	fields := strings.Fields(section[0])
+	if len(fields) < 4 {
+		return section
+	}
+
+	// Encoding names by payload type, and the type each retransmission
+	// format repeats
+	names := make(map[string]string)
+	repeats := make(map[string]string)
+	for _, line := range section[1:] {
+		if pt, value, ok := sdpAttribute(line, "a=rtpmap:"); ok {
+			name, _, _ := strings.Cut(value, "/")
+			names[pt] = strings.ToLower(name)
+		}
+		if pt, value, ok := sdpAttribute(line, "a=fmtp:"); ok {
+			if apt, found := strings.CutPrefix(value, "apt="); found {
+				repeats[pt], _, _ = strings.Cut(apt, ";")
+			}
+		}
+	}
+
+	var codecTypes []string
+	for _, pt := range fields[3:] {
+		if names[pt] == string(codec) {
+			codecTypes = append(codecTypes, pt)
+		}
+	}
+	if len(codecTypes) == 0 {
+		return section
+	}
+
+	var preferred, rest []string
+	for _, pt := range fields[3:] {
+		if slices.Contains(codecTypes, pt) || slices.Contains(codecTypes, repeats[pt]) {
+			preferred = append(preferred, pt)
+		} else {
+			rest = append(rest, pt)
+		}
+	}
+
+	formats := preferred
+	if !force {
+		formats = append(formats, rest...)
+	}
+	result := []string{strings.Join(append(fields[:3:3], formats...), " ")}
+	for _, line := range section[1:] {
+		if force && droppedFormat(line, rest) {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// droppedFormat reports whether line describes one of the dropped payload types
+func droppedFormat(line string, dropped []string) bool {
+	for _, prefix := range []string{"a=rtpmap:", "a=fmtp:", "a=rtcp-fb:"} {
+		if pt, _, ok := sdpAttribute(line, prefix); ok {
+			return slices.Contains(dropped, pt)
+		}
+	}
+	return false
+}
+
+// sdpAttribute splits a payload type attribute such as "a=rtpmap:96 VP8/90000"
+// into its payload type and value
+func sdpAttribute(line, prefix string) (pt, value string, ok bool) {
+	rest, found := strings.CutPrefix(line, prefix)
+	if !found {
+		return "", "", false
+	}
+	pt, value, _ = strings.Cut(rest, " ")
+	return pt, value, true
+}
@@ -0,0 +1,136 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+// chromeOffer is a trimmed video section as Chrome offers it
+const chromeOffer = "v=0\r\n" +
+	"m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n" +
+	"a=rtpmap:111 opus/48000/2\r\n" +
+	"m=video 9 UDP/TLS/RTP/SAVPF 96 97 98 99 102 103\r\n" +
+	"a=rtpmap:96 VP8/90000\r\n" +
+	"a=rtcp-fb:96 nack\r\n" +
+	"a=rtpmap:97 rtx/90000\r\n" +
+	"a=fmtp:97 apt=96\r\n" +
+	"a=rtpmap:98 VP9/90000\r\n" +
+	"a=fmtp:98 profile-id=0\r\n" +
+	"a=rtpmap:99 rtx/90000\r\n" +
+	"a=fmtp:99 apt=98\r\n" +
+	"a=rtpmap:102 H264/90000\r\n" +
+	"a=fmtp:102 level-asymmetry-allowed=1;packetization-mode=1;profile-level-id=42001f\r\n" +
+	"a=rtcp-fb:102 nack\r\n" +
+	"a=rtpmap:103 rtx/90000\r\n" +
+	"a=fmtp:103 apt=102\r\n"
+
+func TestParseVideoCodec(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected VideoCodec
+		wantErr  bool
+	}{
+		{name: "", expected: ""},
+		{name: "H264", expected: VideoCodecH264},
+		{name: " vp9 ", expected: VideoCodecVP9},
+		{name: "vp8", expected: VideoCodecVP8},
+		{name: "av1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := ParseVideoCodec(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if codec != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, codec)
+			}
+		})
+	}
+}
+
+func TestPreferVideoCodec(t *testing.T) {
+	tests := []struct {
+		name        string
+		codec       VideoCodec
+		force       bool
+		expectedM   string
+		dropped     []string
+		keptAttribs []string
+	}{
+		{
+			name:      "prefer H264",
+			codec:     VideoCodecH264,
+			expectedM: "m=video 9 UDP/TLS/RTP/SAVPF 102 103 96 97 98 99",
+		},
+		{
+			name:      "prefer VP8 already first",
+			codec:     VideoCodecVP8,
+			expectedM: "m=video 9 UDP/TLS/RTP/SAVPF 96 97 98 99 102 103",
+		},
+		{
+			name:        "force H264",
+			codec:       VideoCodecH264,
+			force:       true,
+			expectedM:   "m=video 9 UDP/TLS/RTP/SAVPF 102 103",
+			dropped:     []string{"a=rtpmap:96 ", "a=rtcp-fb:96 ", "a=fmtp:97 ", "a=rtpmap:98 ", "a=fmtp:99 "},
+			keptAttribs: []string{"a=rtpmap:102 ", "a=rtcp-fb:102 ", "a=fmtp:103 apt=102", "a=rtpmap:111 opus"},
+		},
+		{
+			name:      "no preference",
+			codec:     "",
+			force:     true,
+			expectedM: "m=video 9 UDP/TLS/RTP/SAVPF 96 97 98 99 102 103",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PreferVideoCodec(chromeOffer, tt.codec, tt.force)
+			if !strings.Contains(result, tt.expectedM+"\r\n") {
+				t.Errorf("Expected %q in:\n%s", tt.expectedM, result)
+			}
+			if !strings.Contains(result, "m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n") {
+				t.Errorf("Expected the audio section to stay as it is:\n%s", result)
+			}
+			for _, line := range tt.dropped {
+				if strings.Contains(result, line) {
+					t.Errorf("Expected %q to be dropped:\n%s", line, result)
+				}
+			}
+			for _, line := range tt.keptAttribs {
+				if !strings.Contains(result, line) {
+					t.Errorf("Expected %q to be kept:\n%s", line, result)
+				}
+			}
+		})
+	}
+}
+
+func TestPreferVideoCodec_MissingCodec(t *testing.T) {
+	vp8Only := "v=0\nm=video 9 UDP/TLS/RTP/SAVPF 96\na=rtpmap:96 VP8/90000\n"
+
+	if result := PreferVideoCodec(vp8Only, VideoCodecH264, true); result != vp8Only {
+		t.Errorf("Expected a section without the codec to stay as it is, got %q", result)
+	}
+}
+
+func TestWebRTCOffer_WithCodec(t *testing.T) {
+	offer := &WebRTCOffer{Type: "offer", SDP: chromeOffer}
+
+	preferred := offer.WithCodec(CodecPreference{Codec: VideoCodecVP9})
+	if !strings.Contains(preferred.SDP, "SAVPF 98 99 96 97 102 103") {
+		t.Errorf("Expected VP9 first, got %q", preferred.SDP)
+	}
+	if offer.SDP != chromeOffer {
+		t.Error("Expected the original offer to stay unchanged")
+	}
+	if offer.WithCodec(CodecPreference{Force: true}) != offer {
+		t.Error("Expected no preference to return the offer itself")
+	}
+	var missing *WebRTCAnswer
+	if missing.WithCodec(CodecPreference{Codec: VideoCodecH264, Force: true}) != nil {
+		t.Error("Expected a nil answer to stay nil")
+	}
+}
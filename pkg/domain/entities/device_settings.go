@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+)
+
+// LowPowerFrameRate is the frame rate a low-power viewer asks the sender for
+const LowPowerFrameRate = 15
+
+// DeviceSettings holds viewer preferences remembered for one device
+type DeviceSettings struct {
+	DeviceID  string    `json:"deviceId"`
+	LowPower  bool      `json:"lowPower"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MaxFrameRate returns the frame rate cap the device wants, or 0 for no cap
+func (s *DeviceSettings) MaxFrameRate() int {
+	if s.LowPower {
+		return LowPowerFrameRate
+	}
+	return 0
+}
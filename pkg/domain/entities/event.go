@@ -0,0 +1,55 @@
+package entities
+
+// Event is a realtime notification delivered to sender and viewer pages
+type Event struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+// Event types
+const (
+	EventQueueChanged   = "queue"
+	EventQueuePosition  = "position"
+	EventViewerAdmitted = "admitted"
+	EventViewerLeft     = "viewer-left"
+	EventServerShutdown = "server-shutdown"
+	EventQualityRequest = "quality"
+	EventLimitWarning   = "limit-warning"
+	EventRenegotiate    = "renegotiate"
+	EventICERestart     = "ice-restart"
+	EventLinkUsed       = "link-used"
+	EventChatMessage    = "chat-message"
+	EventNegotiation    = "negotiation"
+	EventKeyExchange    = "key-exchange"
+	EventFileShared     = "file-shared"
+	EventSFUViewers     = "sfu-viewers"
+	EventPaused         = "paused"
+	EventSession        = "session"
+	EventKicked         = "kicked"
+	EventConstraints    = "constraints"
+)
+
+// AdminTopic carries the lifecycle events of every session to the admin
+// dashboard; it cannot clash with a session topic, since tokens are longer
+const AdminTopic = "admin"
+
+// SessionTopic returns the topic for events addressed to everyone on a session
+func SessionTopic(token string) string {
+	return token
+}
+
+// ViewerTopic returns the topic for events addressed to a single viewer
+func ViewerTopic(token, viewerID string) string {
+	return token + "/" + viewerID
+}
+
+// EventMetrics describes event delivery since the server started
+type EventMetrics struct {
+	Subscribers int    `json:"subscribers"`
+	BufferSize  int    `json:"bufferSize"`
+	Policy      string `json:"policy"`
+	Published   int64  `json:"published"`
+	Delivered   int64  `json:"delivered"`
+	Dropped     int64  `json:"dropped"`
+	SlowClosed  int64  `json:"slowClosed"`
+}
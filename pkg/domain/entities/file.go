@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"path"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxFileNameLength keeps relayed file names within what file systems accept
+const maxFileNameLength = 200
+
+// SharedFile describes a file the sender relayed through the server because
+// no data channel to the viewer was open yet; the content is stored alongside
+type SharedFile struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"contentType,omitempty"`
+	SharedAt    time.Time `json:"sharedAt"`
+}
+
+// SanitizeFileName reduces a client-supplied file name to a safe base name
+// for the download, without directories or control characters; it returns
+// "file" when nothing usable is left
+func SanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	for len(name) > maxFileNameLength {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+	return name
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain name", input: "slides.pdf", expected: "slides.pdf"},
+		{name: "unix path", input: "../../etc/passwd", expected: "passwd"},
+		{name: "windows path", input: `C:\Users\me\notes.txt`, expected: "notes.txt"},
+		{name: "control characters and quotes", input: "bad\r\nname\".txt", expected: "badname.txt"},
+		{name: "empty", input: "", expected: "file"},
+		{name: "dots only", input: "..", expected: "file"},
+		{name: "directory", input: "photos/", expected: "photos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeFileName(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSanitizeFileName_Long(t *testing.T) {
+	got := SanitizeFileName(strings.Repeat("é", maxFileNameLength))
+	if len(got) > maxFileNameLength || !utf8.ValidString(got) {
+		t.Errorf("Expected a valid name of at most %d bytes, got %d bytes", maxFileNameLength, len(got))
+	}
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"strings"
+)
+
+// fingerprintShortBytes is how many trailing bytes of a fingerprint its short
+// form keeps: few enough to read out, too many to forge a certificate for
+const fingerprintShortBytes = 6
+
+// maxFingerprintBytes is the length of a SHA-512 fingerprint, the longest hash
+// RFC 8122 lists
+const maxFingerprintBytes = 64
+
+// DTLSFingerprint is the fingerprint of the certificate a peer secures its
+// connection with, as announced in its session description (RFC 8122). A peer
+// whose fingerprint differs from the one the other side reads out is not the
+// peer it is connected to.
+type DTLSFingerprint struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+	Short     string `json:"short"`
+}
+
+// ParseDTLSFingerprint returns the first a=fingerprint: line of sdp, at session
+// or media level, with its value in upper case; nil when sdp has none or it is
+// malformed. Browsers use one certificate for every section of a connection.
+func ParseDTLSFingerprint(sdp string) *DTLSFingerprint {
+	for _, line := range strings.Split(strings.ReplaceAll(sdp, "\r\n", "\n"), "\n") {
+		attribute, ok := strings.CutPrefix(line, "a=fingerprint:")
+		if !ok {
+			continue
+		}
+		algorithm, value, ok := strings.Cut(strings.TrimSpace(attribute), " ")
+		if !ok || algorithm == "" {
+			return nil
+		}
+		groups := strings.Split(strings.ToUpper(strings.TrimSpace(value)), ":")
+		if len(groups) < fingerprintShortBytes || len(groups) > maxFingerprintBytes {
+			return nil
+		}
+		for _, group := range groups {
+			if !isHexByte(group) {
+				return nil
+			}
+		}
+		return &DTLSFingerprint{
+			Algorithm: strings.ToLower(algorithm),
+			Value:     strings.Join(groups, ":"),
+			Short:     strings.Join(groups[len(groups)-fingerprintShortBytes:], ":"),
+		}
+	}
+	return nil
+}
+
+// Fingerprint returns the DTLS fingerprint the offer announces; see
+// ParseDTLSFingerprint
+func (o *WebRTCOffer) Fingerprint() *DTLSFingerprint {
+	if o == nil {
+		return nil
+	}
+	return ParseDTLSFingerprint(o.SDP)
+}
+
+// Fingerprint returns the DTLS fingerprint the answer announces; see
+// ParseDTLSFingerprint
+func (a *WebRTCAnswer) Fingerprint() *DTLSFingerprint {
+	if a == nil {
+		return nil
+	}
+	return ParseDTLSFingerprint(a.SDP)
+}
+
+// isHexByte reports whether s is a byte in two hexadecimal digits
+func isHexByte(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDTLSFingerprint(t *testing.T) {
+	sha256 := "4a:ad:b9:b1:3f:82:18:3b:54:02:12:df:3e:5d:49:6b:19:e5:7c:ab:3f:e5:e6:1b:b1:c5:a1:8d:11:36:f3:2e"
+	media := "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"
+	tests := []struct {
+		name     string
+		sdp      string
+		expected *DTLSFingerprint
+	}{
+		{
+			name: "media level",
+			sdp:  media + "a=fingerprint:sha-256 " + sha256 + "\r\n",
+			expected: &DTLSFingerprint{
+				Algorithm: "sha-256",
+				Value:     strings.ToUpper(sha256),
+				Short:     "A1:8D:11:36:F3:2E",
+			},
+		},
+		{
+			name: "session level, LF line endings",
+			sdp:  strings.ReplaceAll("v=0\r\na=fingerprint:SHA-1 "+strings.ToUpper(sha256[:59])+"\r\n", "\r\n", "\n"),
+			expected: &DTLSFingerprint{
+				Algorithm: "sha-1",
+				Value:     strings.ToUpper(sha256[:59]),
+				Short:     "49:6B:19:E5:7C:AB",
+			},
+		},
+		{name: "none", sdp: media},
+		{name: "no value", sdp: media + "a=fingerprint:sha-256\r\n"},
+		{name: "not hexadecimal", sdp: media + "a=fingerprint:sha-256 " + strings.Replace(sha256, "4a", "zz", 1) + "\r\n"},
+		{name: "too short", sdp: media + "a=fingerprint:sha-256 4A:AD:B9\r\n"},
+		{name: "too long", sdp: media + "a=fingerprint:sha-256 " + strings.Repeat("AA:", maxFingerprintBytes) + "AA\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fingerprint := ParseDTLSFingerprint(tt.sdp)
+			if tt.expected == nil {
+				if fingerprint != nil {
+					t.Errorf("Expected no fingerprint, got %+v", fingerprint)
+				}
+				return
+			}
+			if fingerprint == nil || *fingerprint != *tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, fingerprint)
+			}
+		})
+	}
+
+	var offer *WebRTCOffer
+	if offer.Fingerprint() != nil {
+		t.Error("Expected no fingerprint for no offer")
+	}
+}
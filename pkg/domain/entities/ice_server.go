@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICEServer is a STUN or TURN server offered to peers, in the shape of the
+// browser's RTCIceServer so pages can pass it straight to RTCPeerConnection
+type ICEServer struct {
+	URLs       []string `json:"urls"`
+	Username   string   `json:"username,omitempty"`
+	Credential string   `json:"credential,omitempty"`
+}
+
+// IsTURN checks if any of the server's URLs is a TURN relay
+func (s ICEServer) IsTURN() bool {
+	for _, url := range s.URLs {
+		if strings.HasPrefix(url, "turn:") || strings.HasPrefix(url, "turns:") {
+			return true
+		}
+	}
+	return false
+}
+
+// NeedsCredentials checks if the server is a TURN relay listed without
+// credentials, which are minted per session from a shared secret instead
+func (s ICEServer) NeedsCredentials() bool {
+	return s.IsTURN() && s.Username == "" && s.Credential == ""
+}
+
+// Validate checks the URL schemes and that TURN servers carry credentials
+func (s ICEServer) Validate() error {
+	if len(s.URLs) == 0 {
+		return fmt.Errorf("ICE server has no URLs")
+	}
+	for _, url := range s.URLs {
+		scheme, _, _ := strings.Cut(url, ":")
+		switch scheme {
+		case "stun", "stuns", "turn", "turns":
+		default:
+			return fmt.Errorf("ICE server URL %q must start with stun:, stuns:, turn: or turns:", url)
+		}
+	}
+	if s.IsTURN() && (s.Username == "" || s.Credential == "") {
+		return fmt.Errorf("TURN server %s needs a username and credential", s.URLs[0])
+	}
+	return nil
+}
+
+// STUNURL returns the first STUN URL among servers, for clients that take a
+// single STUN server
+func STUNURL(servers []ICEServer) string {
+	for _, server := range servers {
+		for _, url := range server.URLs {
+			if strings.HasPrefix(url, "stun:") || strings.HasPrefix(url, "stuns:") {
+				return url
+			}
+		}
+	}
+	return ""
+}
+
+// TURNCredential is a TURN username and password that the relay accepts
+// until ExpiresAt
+type TURNCredential struct {
+	Username   string
+	Credential string
+	ExpiresAt  time.Time
+}
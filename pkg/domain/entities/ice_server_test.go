@@ -0,0 +1,57 @@
+package entities
+
+import "testing"
+
+func TestICEServer_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		server      ICEServer
+		expectError bool
+	}{
+		{
+			name:   "STUN server",
+			server: ICEServer{URLs: []string{"stun:stun.l.google.com:19302"}},
+		},
+		{
+			name:   "TURN server with credentials",
+			server: ICEServer{URLs: []string{"turn:turn.example.com:3478", "turns:turn.example.com:5349"}, Username: "user", Credential: "turn-pass"},
+		},
+		{
+			name:        "TURN server without credentials",
+			server:      ICEServer{URLs: []string{"turn:turn.example.com:3478"}},
+			expectError: true,
+		},
+		{
+			name:        "no URLs",
+			server:      ICEServer{},
+			expectError: true,
+		},
+		{
+			name:        "unknown scheme",
+			server:      ICEServer{URLs: []string{"https://turn.example.com"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("Validate() = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestSTUNURL(t *testing.T) {
+	servers := []ICEServer{
+		{URLs: []string{"turn:turn.example.com:3478"}, Username: "user", Credential: "turn-pass"},
+		{URLs: []string{"stun:stun.example.com:3478", "stun:backup.example.com:3478"}},
+	}
+	if url := STUNURL(servers); url != "stun:stun.example.com:3478" {
+		t.Errorf("Expected the first STUN URL, got %q", url)
+	}
+	if url := STUNURL(servers[:1]); url != "" {
+		t.Errorf("Expected no STUN URL for TURN only, got %q", url)
+	}
+}
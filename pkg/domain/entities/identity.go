@@ -0,0 +1,23 @@
+package entities
+
+import "slices"
+
+// ScopeAdmin is the token scope that grants access to the admin API
+const ScopeAdmin = "admin"
+
+// UserIdentity is the user an API token was issued to, taken from its claims
+type UserIdentity struct {
+	// Subject identifies the user at the token's issuer
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+
+	// Scopes are what the token lets its holder do beyond starting shares
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// HasScope reports whether the token granted scope
+func (u *UserIdentity) HasScope(scope string) bool {
+	return u != nil && slices.Contains(u.Scopes, scope)
+}
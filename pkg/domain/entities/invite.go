@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// Invite announces a live session in a team chat channel. It never carries
+// the PIN, since everyone in the channel can read it.
+type Invite struct {
+	Name      string
+	ViewerURL string
+
+	// ShortURL is the session's speakable viewer URL, if it has one
+	ShortURL string
+
+	PINRequired bool
+	ExpiresAt   time.Time
+}
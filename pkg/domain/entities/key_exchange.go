@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"encoding/base64"
+	"time"
+)
+
+// Key exchange message types. Viewers send their ECDH P-256 public key, the
+// sender answers each with the content key wrapped for that viewer, and
+// tells every viewer to rotate to a new key once the keys are handed out,
+// e.g. after removing a viewer.
+const (
+	KeyMessagePublicKey  = "public-key"
+	KeyMessageContentKey = "content-key"
+	KeyMessageRotate     = "rotate"
+)
+
+// Key exchange message limits: keys are raw or SPKI encoded P-256 public
+// keys, wrapped keys an AES-GCM sealed content key and IVs AES-GCM nonces
+const (
+	maxPublicKeyLength  = 128
+	maxWrappedKeyLength = 128
+	keyExchangeIVLength = 12
+	MaxContentKeyID     = 255
+)
+
+// maxKeyExchangeMessages bounds the key messages a session relays; the
+// oldest messages are dropped first
+const maxKeyExchangeMessages = 500
+
+// KeyExchangeMessage is one message of the exchange of the content keys an
+// end-to-end encrypted session's media is encrypted with. The server relays
+// the messages without being able to unwrap the keys they carry, so even
+// media forwarded by the SFU stays opaque to it.
+type KeyExchangeMessage struct {
+	// Seq orders the messages of a session; it never goes back, even when
+	// old messages are dropped
+	Seq int `json:"seq"`
+
+	// Type is "public-key", "content-key" or "rotate"
+	Type string `json:"type"`
+
+	// From is who sent the message, "sender" or "viewer"
+	From string `json:"from"`
+
+	// ViewerID is the viewer whose public key it is or whom the content key
+	// is wrapped for; rotations address every viewer
+	ViewerID string `json:"viewerId,omitempty"`
+
+	// KeyID names the content key wrapped or to rotate to, as the key ID of
+	// the encrypted frames
+	KeyID int `json:"keyId"`
+
+	// PublicKey is the viewer's public key, or the sender's ephemeral
+	// public key the content key was wrapped with; WrappedKey and IV are the
+	// wrapped content key and its nonce. All are base64url without padding.
+	PublicKey  string `json:"publicKey,omitempty"`
+	WrappedKey string `json:"wrappedKey,omitempty"`
+	IV         string `json:"iv,omitempty"`
+
+	SentAt time.Time `json:"sentAt"`
+}
+
+// Valid reports whether the message has a known type sent by the right peer
+// with the fields that type needs, and no others
+func (m *KeyExchangeMessage) Valid() bool {
+	if m.KeyID < 0 || m.KeyID > MaxContentKeyID {
+		return false
+	}
+	switch m.Type {
+	case KeyMessagePublicKey:
+		return m.From == NegotiationFromViewer && m.ViewerID != "" &&
+			validKeyField(m.PublicKey, 1, maxPublicKeyLength) && m.WrappedKey == "" && m.IV == ""
+	case KeyMessageContentKey:
+		return m.From == NegotiationFromSender && m.ViewerID != "" &&
+			validKeyField(m.PublicKey, 1, maxPublicKeyLength) &&
+			validKeyField(m.WrappedKey, 1, maxWrappedKeyLength) &&
+			validKeyField(m.IV, keyExchangeIVLength, keyExchangeIVLength)
+	case KeyMessageRotate:
+		return m.From == NegotiationFromSender && m.ViewerID == "" &&
+			m.PublicKey == "" && m.WrappedKey == "" && m.IV == ""
+	}
+	return false
+}
+
+// validKeyField reports whether field is base64url without padding of
+// between minLength and maxLength bytes
+func validKeyField(field string, minLength, maxLength int) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(field)
+	return err == nil && len(decoded) >= minLength && len(decoded) <= maxLength
+}
+
+// AddKeyExchangeMessage appends a relayed key message with the next sequence
+// number and returns it. A message replaces the earlier one it supersedes:
+// a viewer's new public key its old one, a content key rewrapped for a
+// viewer the earlier wrapping, and a rotation the one before.
+func (s *Session) AddKeyExchangeMessage(message KeyExchangeMessage) KeyExchangeMessage {
+	s.KeyExchangeSeq++
+	message.Seq = s.KeyExchangeSeq
+
+	kept := s.KeyExchange[:0]
+	for _, previous := range s.KeyExchange {
+		if !message.supersedes(previous) {
+			kept = append(kept, previous)
+		}
+	}
+	s.KeyExchange = append(kept, message)
+	if len(s.KeyExchange) > maxKeyExchangeMessages {
+		s.KeyExchange = s.KeyExchange[len(s.KeyExchange)-maxKeyExchangeMessages:]
+	}
+	return message
+}
+
+// supersedes reports whether m makes previous useless to every peer
+func (m *KeyExchangeMessage) supersedes(previous KeyExchangeMessage) bool {
+	if m.Type != previous.Type || m.ViewerID != previous.ViewerID {
+		return false
+	}
+	return m.Type != KeyMessageContentKey || m.KeyID == previous.KeyID
+}
+
+// DropViewerKeys drops the key messages of a viewer, e.g. one removed from
+// the session, so the sender does not wrap the next key for it
+func (s *Session) DropViewerKeys(viewerID string) {
+	kept := s.KeyExchange[:0]
+	for _, message := range s.KeyExchange {
+		if message.ViewerID != viewerID {
+			kept = append(kept, message)
+		}
+	}
+	s.KeyExchange = kept
+}
+
+// KeyExchangeSince returns the relayed key messages with a sequence number
+// above since that viewerID may read: its own and the rotations. An empty
+// viewerID, for the sender, reads them all.
+func (s *Session) KeyExchangeSince(since int, viewerID string) []KeyExchangeMessage {
+	messages := []KeyExchangeMessage{}
+	for _, message := range s.KeyExchange {
+		if message.Seq <= since {
+			continue
+		}
+		if viewerID == "" || message.ViewerID == viewerID || message.Type == KeyMessageRotate {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyExchangeMessage_Valid(t *testing.T) {
+	publicKey := base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+	wrapped := base64.RawURLEncoding.EncodeToString(make([]byte, 48))
+	iv := base64.RawURLEncoding.EncodeToString(make([]byte, 12))
+	tests := []struct {
+		name    string
+		message KeyExchangeMessage
+		valid   bool
+	}{
+		{"public key", KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: publicKey}, true},
+		{"content key", KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-1", KeyID: 1, PublicKey: publicKey, WrappedKey: wrapped, IV: iv}, true},
+		{"rotate", KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, KeyID: 2}, true},
+		{"public key from the sender", KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromSender, ViewerID: "viewer-1", PublicKey: publicKey}, false},
+		{"public key without viewer", KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, PublicKey: publicKey}, false},
+		{"padded public key", KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: base64.URLEncoding.EncodeToString(make([]byte, 65))}, false},
+		{"public key too long", KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: strings.Repeat("A", 200)}, false},
+		{"content key from a viewer", KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: publicKey, WrappedKey: wrapped, IV: iv}, false},
+		{"content key with a short IV", KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-1", PublicKey: publicKey, WrappedKey: wrapped, IV: wrapped}, false},
+		{"content key without wrapped key", KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-1", PublicKey: publicKey, IV: iv}, false},
+		{"rotate with a key", KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, KeyID: 2, WrappedKey: wrapped}, false},
+		{"rotate for one viewer", KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, ViewerID: "viewer-1", KeyID: 2}, false},
+		{"key ID out of range", KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, KeyID: MaxContentKeyID + 1}, false},
+		{"unknown type", KeyExchangeMessage{Type: "private-key", From: NegotiationFromSender}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := tt.message.Valid(); valid != tt.valid {
+				t.Errorf("Expected valid %v, got %v", tt.valid, valid)
+			}
+		})
+	}
+}
+
+func TestSession_AddKeyExchangeMessage(t *testing.T) {
+	session := &Session{Token: "test-token", E2EE: true}
+	add := func(message KeyExchangeMessage) KeyExchangeMessage {
+		message.SentAt = time.Now()
+		return session.AddKeyExchangeMessage(message)
+	}
+
+	add(KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: "old"})
+	add(KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-2", PublicKey: "other"})
+	latest := add(KeyExchangeMessage{Type: KeyMessagePublicKey, From: NegotiationFromViewer, ViewerID: "viewer-1", PublicKey: "new"})
+	if latest.Seq != 3 || len(session.KeyExchange) != 2 {
+		t.Fatalf("Expected the viewer's new key to replace its old one, got %+v", session.KeyExchange)
+	}
+
+	add(KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-1", KeyID: 1})
+	add(KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-2", KeyID: 1})
+	add(KeyExchangeMessage{Type: KeyMessageContentKey, From: NegotiationFromSender, ViewerID: "viewer-1", KeyID: 2})
+	add(KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, KeyID: 1})
+	rotation := add(KeyExchangeMessage{Type: KeyMessageRotate, From: NegotiationFromSender, KeyID: 2})
+
+	// Each viewer reads its own messages and the latest rotation; the sender
+	// reads them all
+	own := session.KeyExchangeSince(0, "viewer-1")
+	if len(own) != 4 || own[len(own)-1].Seq != rotation.Seq {
+		t.Errorf("Expected the viewer's key, two content keys and the rotation, got %+v", own)
+	}
+	if since := session.KeyExchangeSince(latest.Seq, "viewer-2"); len(since) != 2 {
+		t.Errorf("Expected a content key and the rotation, got %+v", since)
+	}
+	if all := session.KeyExchangeSince(0, ""); len(all) != 6 {
+		t.Errorf("Expected every message for the sender, got %+v", all)
+	}
+
+	clone := session.Clone()
+	session.Revoke("viewer-2")
+	if len(session.KeyExchangeSince(0, "viewer-2")) != 1 {
+		t.Errorf("Expected only the rotation left for a removed viewer, got %+v", session.KeyExchange)
+	}
+	if len(clone.KeyExchangeSince(0, "viewer-2")) != 3 {
+		t.Error("Expected the clone's key messages to be independent")
+	}
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Paths a latency probe times
+const (
+	// LatencyPathPeer is the echo over the data channel between the sender
+	// and a viewer
+	LatencyPathPeer = "peer"
+
+	// LatencyPathServer is the ping of this server's HTTP endpoint
+	LatencyPathServer = "server"
+)
+
+// Networks a probed path crosses
+const (
+	NetworkLAN      = "lan"
+	NetworkInternet = "internet"
+
+	// NetworkRelay is a peer connection relayed through a TURN server
+	NetworkRelay = "relay"
+)
+
+// LatencySample is the round-trip time a page measured with the latency
+// probe, the median of its last few probes
+type LatencySample struct {
+	At       time.Time `json:"at"`
+	Role     string    `json:"role"`
+	ViewerID string    `json:"viewerId,omitempty"`
+	Path     string    `json:"path"`
+
+	// RTTMillis is the round-trip time in milliseconds
+	RTTMillis float64 `json:"rttMs"`
+
+	// Network is the network the path crosses, when the page could tell
+	Network string `json:"network,omitempty"`
+}
+
+// IsValid reports whether the sample has a known role, path and network and
+// a plausible round-trip time
+func (s *LatencySample) IsValid() bool {
+	if s.Role != StatsRoleSender && s.Role != StatsRoleViewer {
+		return false
+	}
+	if s.Path != LatencyPathPeer && s.Path != LatencyPathServer {
+		return false
+	}
+	switch s.Network {
+	case "", NetworkLAN, NetworkInternet, NetworkRelay:
+	default:
+		return false
+	}
+	return s.RTTMillis >= 0 && s.RTTMillis <= maxStatsRTTMillis
+}
+
+// Source identifies the peer that measured the sample, as for stats samples
+func (s *LatencySample) Source() string {
+	if s.Role == StatsRoleViewer {
+		return StatsRoleViewer + ":" + s.ViewerID
+	}
+	return StatsRoleSender
+}
+
+// NetworkOf tells whether a client at addr reaches the server over the LAN:
+// from a private, link-local or loopback address, or else over the internet
+func NetworkOf(addr netip.Addr) string {
+	addr = addr.Unmap()
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return NetworkLAN
+	}
+	return NetworkInternet
+}
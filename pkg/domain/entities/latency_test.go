@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestLatencySample_IsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample LatencySample
+		valid  bool
+	}{
+		{name: "sender to viewer", sample: LatencySample{Role: StatsRoleSender, Path: LatencyPathPeer, RTTMillis: 35, Network: NetworkLAN}, valid: true},
+		{name: "viewer to server", sample: LatencySample{Role: StatsRoleViewer, ViewerID: "v1", Path: LatencyPathServer, RTTMillis: 80}, valid: true},
+		{name: "unknown role", sample: LatencySample{Role: "admin", Path: LatencyPathPeer}, valid: false},
+		{name: "unknown path", sample: LatencySample{Role: StatsRoleSender, Path: "dns"}, valid: false},
+		{name: "unknown network", sample: LatencySample{Role: StatsRoleSender, Path: LatencyPathPeer, Network: "wifi"}, valid: false},
+		{name: "negative RTT", sample: LatencySample{Role: StatsRoleSender, Path: LatencyPathPeer, RTTMillis: -1}, valid: false},
+		{name: "RTT too long", sample: LatencySample{Role: StatsRoleSender, Path: LatencyPathPeer, RTTMillis: 120_000}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sample.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestNetworkOf(t *testing.T) {
+	tests := map[string]string{
+		"192.168.1.20":        NetworkLAN,
+		"10.0.0.5":            NetworkLAN,
+		"127.0.0.1":           NetworkLAN,
+		"::1":                 NetworkLAN,
+		"fe80::1":             NetworkLAN,
+		"fd12:3456::1":        NetworkLAN,
+		"::ffff:172.16.0.9":   NetworkLAN,
+		"203.0.113.7":         NetworkInternet,
+		"2001:db8::1":         NetworkInternet,
+		"::ffff:198.51.100.1": NetworkInternet,
+	}
+	for addr, expected := range tests {
+		if got := NetworkOf(netip.MustParseAddr(addr)); got != expected {
+			t.Errorf("NetworkOf(%s) = %q, expected %q", addr, got, expected)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package entities
+
+// Soft limits the server warns about
+const (
+	LimitSessions  = "sessions"
+	LimitBandwidth = "bandwidth"
+)
+
+// LimitWarning tells senders the server is close to one of its soft limits,
+// or with Cleared set, that it has dropped back below it
+type LimitWarning struct {
+	Limit   string `json:"limit"`
+	Percent int    `json:"percent"`
+	Message string `json:"message"`
+	Cleared bool   `json:"cleared,omitempty"`
+}
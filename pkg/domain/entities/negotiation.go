@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"time"
+)
+
+// Negotiation message authors
+const (
+	NegotiationFromSender = "sender"
+	NegotiationFromViewer = "viewer"
+)
+
+// maxNegotiationMessages bounds the negotiation a session relays; the oldest
+// messages are dropped first
+const maxNegotiationMessages = 200
+
+// SessionDescription is an offer or answer exchanged while the peers
+// renegotiate their connection
+type SessionDescription struct {
+	// Type is "offer" or "answer"; a rollback is the peer's own business
+	Type string `json:"type"`
+	SDP  string `json:"sdp"`
+}
+
+// ICECandidate is a trickled ICE candidate, in the form of the browser's
+// RTCIceCandidateInit
+type ICECandidate struct {
+	Candidate        string  `json:"candidate"`
+	SDPMid           *string `json:"sdpMid,omitempty"`
+	SDPMLineIndex    *int    `json:"sdpMLineIndex,omitempty"`
+	UsernameFragment string  `json:"usernameFragment,omitempty"`
+}
+
+// NegotiationMessage is one message of the perfect negotiation between the
+// sender and the connected viewer, relayed so they can add or remove tracks,
+// such as audio or a second screen, mid-session. It carries either a
+// description or a candidate.
+type NegotiationMessage struct {
+	// Seq orders the messages of a session; it never goes back, even when
+	// old messages are dropped
+	Seq int `json:"seq"`
+
+	// From is who sent the message, "sender" or "viewer"
+	From string `json:"from"`
+
+	// ViewerID is the viewer on the connection being renegotiated
+	ViewerID string `json:"viewerId"`
+
+	Description *SessionDescription `json:"description,omitempty"`
+	Candidate   *ICECandidate       `json:"candidate,omitempty"`
+	SentAt      time.Time           `json:"sentAt"`
+}
+
+// Valid reports whether the message has a known author and exactly one of a
+// description of type offer or answer and a candidate
+func (m *NegotiationMessage) Valid() bool {
+	if m.From != NegotiationFromSender && m.From != NegotiationFromViewer {
+		return false
+	}
+	if (m.Description == nil) == (m.Candidate == nil) {
+		return false
+	}
+	if m.Description != nil {
+		return (m.Description.Type == "offer" || m.Description.Type == "answer") && m.Description.SDP != ""
+	}
+	return true
+}
+
+// AddNegotiationMessage appends a relayed message with the next sequence
+// number and returns it. Messages of any viewer other than message's are
+// dropped, since only one connection is renegotiated at a time.
+func (s *Session) AddNegotiationMessage(message NegotiationMessage) NegotiationMessage {
+	s.NegotiationSeq++
+	message.Seq = s.NegotiationSeq
+
+	kept := s.Negotiation[:0]
+	for _, previous := range s.Negotiation {
+		if previous.ViewerID == message.ViewerID {
+			kept = append(kept, previous)
+		}
+	}
+	s.Negotiation = append(kept, message)
+	if len(s.Negotiation) > maxNegotiationMessages {
+		s.Negotiation = s.Negotiation[len(s.Negotiation)-maxNegotiationMessages:]
+	}
+	return message
+}
+
+// NegotiationSince returns the relayed messages of viewerID's connection with
+// a sequence number above since
+func (s *Session) NegotiationSince(since int, viewerID string) []NegotiationMessage {
+	messages := []NegotiationMessage{}
+	for _, message := range s.Negotiation {
+		if message.Seq > since && message.ViewerID == viewerID {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
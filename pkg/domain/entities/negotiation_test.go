@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNegotiationMessage_Valid(t *testing.T) {
+	mid := "0"
+	tests := []struct {
+		name    string
+		message NegotiationMessage
+		valid   bool
+	}{
+		{"offer", NegotiationMessage{From: NegotiationFromSender, Description: &SessionDescription{Type: "offer", SDP: "sdp"}}, true},
+		{"answer", NegotiationMessage{From: NegotiationFromViewer, Description: &SessionDescription{Type: "answer", SDP: "sdp"}}, true},
+		{"candidate", NegotiationMessage{From: NegotiationFromViewer, Candidate: &ICECandidate{Candidate: "candidate:1", SDPMid: &mid}}, true},
+		{"end of candidates", NegotiationMessage{From: NegotiationFromSender, Candidate: &ICECandidate{}}, true},
+		{"rollback", NegotiationMessage{From: NegotiationFromSender, Description: &SessionDescription{Type: "rollback"}}, false},
+		{"empty SDP", NegotiationMessage{From: NegotiationFromSender, Description: &SessionDescription{Type: "offer"}}, false},
+		{"both", NegotiationMessage{From: NegotiationFromSender, Description: &SessionDescription{Type: "offer", SDP: "sdp"}, Candidate: &ICECandidate{}}, false},
+		{"neither", NegotiationMessage{From: NegotiationFromSender}, false},
+		{"unknown author", NegotiationMessage{From: "admin", Candidate: &ICECandidate{}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := tt.message.Valid(); valid != tt.valid {
+				t.Errorf("Expected valid %v, got %v", tt.valid, valid)
+			}
+		})
+	}
+}
+
+func TestSession_AddNegotiationMessage(t *testing.T) {
+	session := &Session{Token: "test-token"}
+	candidate := &ICECandidate{Candidate: "candidate:1"}
+
+	first := session.AddNegotiationMessage(NegotiationMessage{From: NegotiationFromSender, ViewerID: "viewer-1", Candidate: candidate, SentAt: time.Now()})
+	second := session.AddNegotiationMessage(NegotiationMessage{From: NegotiationFromViewer, ViewerID: "viewer-1", Candidate: candidate, SentAt: time.Now()})
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Fatalf("Expected sequence numbers 1 and 2, got %d and %d", first.Seq, second.Seq)
+	}
+	if since := session.NegotiationSince(1, "viewer-1"); len(since) != 1 || since[0].From != NegotiationFromViewer {
+		t.Errorf("Expected only the second message, got %+v", since)
+	}
+	if len(session.NegotiationSince(0, "viewer-2")) != 0 {
+		t.Error("Expected no messages for another viewer")
+	}
+
+	// A new viewer's connection starts from an empty relay, without reusing
+	// sequence numbers
+	clone := session.Clone()
+	third := session.AddNegotiationMessage(NegotiationMessage{From: NegotiationFromSender, ViewerID: "viewer-2", Candidate: candidate, SentAt: time.Now()})
+	if third.Seq != 3 || len(session.Negotiation) != 1 {
+		t.Errorf("Expected the first viewer's messages dropped, got %+v", session.Negotiation)
+	}
+	if len(clone.NegotiationSince(0, "viewer-1")) != 2 {
+		t.Error("Expected the clone's relay to be independent")
+	}
+
+	for i := 0; i < maxNegotiationMessages; i++ {
+		session.AddNegotiationMessage(NegotiationMessage{From: NegotiationFromSender, ViewerID: "viewer-2", Candidate: candidate, SentAt: time.Now()})
+	}
+	if len(session.Negotiation) != maxNegotiationMessages || session.Negotiation[0].Seq != 4 {
+		t.Errorf("Expected the relay capped at %d messages from the oldest, got %d from %d", maxNegotiationMessages, len(session.Negotiation), session.Negotiation[0].Seq)
+	}
+}
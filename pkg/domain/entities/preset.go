@@ -0,0 +1,67 @@
+package entities
+
+// QualityPreset is a named set of capture and bitrate limits a sender picks
+// when starting a session
+type QualityPreset struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Width, Height and FrameRate are the capture size and rate asked of
+	// the browser; it may deliver less
+	Width     int `json:"width"`
+	Height    int `json:"height"`
+	FrameRate int `json:"frameRate"`
+
+	// MaxBitrateKbps caps the video bitrate unless the session sets its own cap
+	MaxBitrateKbps int `json:"maxBitrateKbps"`
+
+	// ContentHint tells the encoder to keep edges sharp ("detail") or
+	// movement fluid ("motion")
+	ContentHint string `json:"contentHint"`
+}
+
+// QualityPresets are the presets the server offers; sender pages start with
+// the first one selected
+var QualityPresets = []QualityPreset{
+	{
+		ID:             "text",
+		Name:           "Text sharp",
+		Description:    "Full resolution at a modest frame rate, for code, documents and slides",
+		Width:          2560,
+		Height:         1440,
+		FrameRate:      15,
+		MaxBitrateKbps: 4000,
+		ContentHint:    "detail",
+	},
+	{
+		ID:             "motion",
+		Name:           "Motion smooth",
+		Description:    "HD at a high frame rate, for video and animations",
+		Width:          1280,
+		Height:         720,
+		FrameRate:      60,
+		MaxBitrateKbps: 6000,
+		ContentHint:    "motion",
+	},
+	{
+		ID:             "low",
+		Name:           "Low bandwidth",
+		Description:    "Small and slow, to spare a congested or metered network",
+		Width:          1280,
+		Height:         720,
+		FrameRate:      10,
+		MaxBitrateKbps: 600,
+		ContentHint:    "detail",
+	},
+}
+
+// FindQualityPreset returns the preset with the given ID, or nil
+func FindQualityPreset(id string) *QualityPreset {
+	for _, preset := range QualityPresets {
+		if preset.ID == id {
+			return &preset
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// RecordingExtension is the file extension of recordings, which are
+	// Matroska files holding the video as the sender encoded it
+	RecordingExtension = ".mkv"
+
+	// recordingTimeLayout is how a recording's name carries its start, in UTC
+	recordingTimeLayout = "20060102T150405Z"
+)
+
+// Recording describes the recorded video of a session streamed through the
+// server, kept as a file named after the session and when it started
+type Recording struct {
+	Name      string    `json:"name"`
+	Token     string    `json:"token"`
+	Size      int64     `json:"size"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+}
+
+// RecordingName returns the file name of a recording of the session with
+// token starting at start
+func RecordingName(token string, start time.Time) string {
+	return token + "-" + start.UTC().Format(recordingTimeLayout) + RecordingExtension
+}
+
+// ParseRecordingName returns the session token and start time a recording's
+// file name carries; ok is false for any other name, including paths
+func ParseRecordingName(name string) (token string, start time.Time, ok bool) {
+	base, found := strings.CutSuffix(name, RecordingExtension)
+	if !found || len(base) < len(recordingTimeLayout)+2 {
+		return "", time.Time{}, false
+	}
+	token = base[:len(base)-len(recordingTimeLayout)-1]
+	stamp := base[len(token):]
+	if stamp[0] != '-' || !onlyChars(token, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_.") || strings.HasPrefix(token, ".") {
+		return "", time.Time{}, false
+	}
+	start, err := time.Parse(recordingTimeLayout, stamp[1:])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return token, start, true
+}
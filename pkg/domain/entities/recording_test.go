@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordingName(t *testing.T) {
+	start := time.Date(2025, 1, 15, 10, 4, 31, 0, time.FixedZone("CET", 3600))
+
+	for _, token := range []string{"Ab-_9xYz0123", "550e8400-e29b-41d4-a716-446655440000", "abc.DEF-_"} {
+		name := RecordingName(token, start)
+		if name != token+"-20250115T090431Z.mkv" {
+			t.Errorf("Unexpected name %q", name)
+		}
+		parsed, at, ok := ParseRecordingName(name)
+		if !ok || parsed != token || !at.Equal(start) {
+			t.Errorf("ParseRecordingName(%q) = %q, %v, %v", name, parsed, at, ok)
+		}
+	}
+
+	for _, name := range []string{
+		"token-20250115T090431Z.mkv.part",
+		"token-20250115T090431Z.webm",
+		"-20250115T090431Z.mkv",
+		"token_20250115T090431Z.mkv",
+		"token-2025011XT090431Z.mkv",
+		"../token-20250115T090431Z.mkv",
+		".hidden-20250115T090431Z.mkv",
+		"a/b-20250115T090431Z.mkv",
+	} {
+		if _, _, ok := ParseRecordingName(name); ok {
+			t.Errorf("Expected %q to be refused", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+)
+
+// maxRoomNameLength keeps room URLs short enough to type on a TV remote
+const maxRoomNameLength = 40
+
+// Room is a stable, human-readable name that always leads to its sender's
+// current session, so viewers can bookmark one URL such as /r/design-review
+// instead of opening a new link every time
+type Room struct {
+	Name string `json:"name"`
+
+	// Key proves that a sender owns the room; only its holder may point the
+	// room at a new session
+	Key string `json:"key"`
+
+	SessionToken string    `json:"sessionToken"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// NormalizeRoomName returns name in the form rooms are stored under, or an
+// empty string when it is not a valid room name. Names are case-insensitive
+// and made of letters, digits and single hyphens, e.g. "design-review".
+func NormalizeRoomName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > maxRoomNameLength {
+		return ""
+	}
+	if strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") || strings.Contains(name, "--") {
+		return ""
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return ""
+		}
+	}
+	return name
+}
+
+// CheckKey reports whether key unlocks the room
+func (r *Room) CheckKey(key string) bool {
+	return key != "" && subtle.ConstantTimeCompare([]byte(r.Key), []byte(key)) == 1
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRoomName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "simple name", input: "design-review", expected: "design-review"},
+		{name: "mixed case and spaces", input: "  Design-Review ", expected: "design-review"},
+		{name: "digits", input: "room42", expected: "room42"},
+		{name: "empty", input: "", expected: ""},
+		{name: "leading hyphen", input: "-design", expected: ""},
+		{name: "trailing hyphen", input: "design-", expected: ""},
+		{name: "double hyphen", input: "design--review", expected: ""},
+		{name: "space inside", input: "design review", expected: ""},
+		{name: "slash", input: "design/review", expected: ""},
+		{name: "non-ASCII letter", input: "café", expected: ""},
+		{name: "too long", input: strings.Repeat("a", maxRoomNameLength+1), expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeRoomName(tt.input); got != tt.expected {
+				t.Errorf("NormalizeRoomName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRoom_CheckKey(t *testing.T) {
+	room := &Room{Name: "design-review", Key: "room-key"}
+
+	if !room.CheckKey("room-key") {
+		t.Error("Expected the room key to match")
+	}
+	if room.CheckKey("other-key") {
+		t.Error("Expected another key to be rejected")
+	}
+	if (&Room{Name: "design-review"}).CheckKey("") {
+		t.Error("Expected an empty key to never match")
+	}
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Themes a sender profile may pick; the empty theme follows the page's
+// contrast toggle and the system setting
+const (
+	ThemeHighContrast   = "high"
+	ThemeNormalContrast = "normal"
+)
+
+// SenderProfile holds the defaults a sender's browser starts the sender page
+// with, kept on the server so they survive cleared site data
+type SenderProfile struct {
+	// ID is the signed profile cookie's identifier
+	ID string `json:"id"`
+
+	// Preset is the ID of the quality preset picked by default, if any
+	Preset string `json:"preset,omitempty"`
+
+	// Audio shares the screen's sound by default
+	Audio bool `json:"audio"`
+
+	// Interface is the LAN address preferred in viewer links, if any
+	Interface string `json:"interface,omitempty"`
+
+	// Theme is ThemeHighContrast, ThemeNormalContrast or empty
+	Theme string `json:"theme,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// IsValid reports whether the profile's interface is an IP address and its
+// theme a known one; the preset is checked against the presets by the caller
+func (p *SenderProfile) IsValid() bool {
+	if p.ID == "" {
+		return false
+	}
+	if p.Interface != "" {
+		if _, err := netip.ParseAddr(p.Interface); err != nil {
+			return false
+		}
+	}
+	switch p.Theme {
+	case "", ThemeHighContrast, ThemeNormalContrast:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestSenderProfile_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  SenderProfile
+		expected bool
+	}{
+		{
+			name:     "every option",
+			profile:  SenderProfile{ID: "abc", Preset: "text", Audio: true, Interface: "192.168.1.20", Theme: ThemeHighContrast},
+			expected: true,
+		},
+		{
+			name:     "defaults",
+			profile:  SenderProfile{ID: "abc"},
+			expected: true,
+		},
+		{
+			name:     "IPv6 interface",
+			profile:  SenderProfile{ID: "abc", Interface: "fd00::20"},
+			expected: true,
+		},
+		{
+			name:    "no ID",
+			profile: SenderProfile{},
+		},
+		{
+			name:    "interface not an address",
+			profile: SenderProfile{ID: "abc", Interface: "en0"},
+		},
+		{
+			name:    "unknown theme",
+			profile: SenderProfile{ID: "abc", Theme: "dark"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.profile.IsValid(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -6,4 +6,33 @@ type ServerInfo struct {
 	LANIP      string `json:"lanIP"`
 	STUNServer string `json:"stunServer,omitempty"`
 	Version    string `json:"version,omitempty"`
+
+	// LANIPs lists every address other devices may reach the server on, in
+	// order of preference; LANIP is the first of them, and is IPv6 only on
+	// networks without IPv4
+	LANIPs []string `json:"lanIPs,omitempty"`
+
+	// PublicHost is the domain the server's certificate was issued for; pages
+	// build links with it instead of the LAN IP, which the certificate does
+	// not cover
+	PublicHost string `json:"publicHost,omitempty"`
+
+	// PublicIP is the server's address on the internet as seen by a STUN
+	// server, and ExternalURL the base URL viewers outside the LAN use,
+	// e.g. through a port forwarded on the router
+	PublicIP    string `json:"publicIP,omitempty"`
+	ExternalURL string `json:"externalURL,omitempty"`
+
+	// Cleanup is the garbage collection schedule and its latest run
+	Cleanup *CleanupStatus `json:"cleanup,omitempty"`
+}
+
+// NetworkInterface is a network interface of the server and the addresses
+// other devices may reach it on, in order of preference
+type NetworkInterface struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+
+	// Selected marks the interface whose address links are built with
+	Selected bool `json:"selected,omitempty"`
 }
@@ -1,17 +1,184 @@
 package entities
 
 import (
+	"crypto/subtle"
+	"slices"
 	"time"
 )
 
 // Session represents a screen sharing session
 type Session struct {
-	Token     string
-	Offer     *WebRTCOffer
-	Answer    *WebRTCAnswer
-	CreatedAt time.Time
-	ExpiresAt time.Time
-	Status    SessionStatus
+	Token     string        `json:"token"`
+	Name      string        `json:"name,omitempty"`
+	Offer     *WebRTCOffer  `json:"offer,omitempty"`
+	Answer    *WebRTCAnswer `json:"answer,omitempty"`
+	CreatedAt time.Time     `json:"createdAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+	Status    SessionStatus `json:"status"`
+
+	// Deadline is the expiry a signed token carries; the session is never
+	// extended past it. It is zero for unsigned tokens.
+	Deadline time.Time `json:"deadline"`
+
+	// Alias is the short, speakable name viewers can also open the session
+	// by, e.g. /v/BlueTiger42; no two stored sessions share one
+	Alias string `json:"alias,omitempty"`
+
+	// PreviewDisabled hides the session name from link preview metadata
+	PreviewDisabled bool `json:"previewDisabled,omitempty"`
+
+	// SenderSeenAt is the time of the last heartbeat from the sender page
+	SenderSeenAt time.Time `json:"senderSeenAt"`
+
+	// HeartbeatTimeout is how long the sender may stay silent after a
+	// heartbeat before the session goes stale; 0 disables the check
+	HeartbeatTimeout time.Duration `json:"heartbeatTimeout,omitempty"`
+
+	// IdleTimeout is how long the session may wait for the sender's offer,
+	// and then for a viewer to connect, before it expires ahead of its
+	// token; 0 disables the check. OfferedAt and ConnectedAt are when the
+	// first offer arrived and the first viewer connected.
+	IdleTimeout time.Duration `json:"idleTimeout,omitempty"`
+	OfferedAt   time.Time     `json:"offeredAt"`
+	ConnectedAt time.Time     `json:"connectedAt"`
+
+	// SingleUse makes the viewer link work for one device: once a viewer's
+	// answer is accepted, only ConsumedBy may fetch offers, so a leaked link
+	// cannot be used later by someone else
+	SingleUse  bool   `json:"singleUse,omitempty"`
+	ConsumedBy string `json:"consumedBy,omitempty"`
+
+	// ViewerID is the viewer holding the peer-to-peer slot, as known from
+	// its answer
+	ViewerID string `json:"viewerId,omitempty"`
+
+	// ViewerName is the name the viewer holding the slot gave with its
+	// answer, if any
+	ViewerName string `json:"viewerName,omitempty"`
+
+	// ICEGeneration counts the ICE restarts of the current offer, so answers
+	// to the offer before the last restart are refused; a new offer starts
+	// again from 0
+	ICEGeneration int `json:"iceGeneration,omitempty"`
+
+	// Revoked lists the viewers removed by the sender or an admin; they get
+	// no more offers and their answers are refused
+	Revoked []string `json:"revoked,omitempty"`
+
+	// ChatEnabled lets the peers chat; Chat holds the messages relayed
+	// through the server before their data channel is open
+	ChatEnabled bool          `json:"chatEnabled,omitempty"`
+	Chat        []ChatMessage `json:"chat,omitempty"`
+
+	// Negotiation holds the messages the sender and the connected viewer
+	// relay to renegotiate their connection; NegotiationSeq is the sequence
+	// number of the last one
+	Negotiation    []NegotiationMessage `json:"negotiation,omitempty"`
+	NegotiationSeq int                  `json:"negotiationSeq,omitempty"`
+
+	// E2EE says the peers encrypt the media end to end with content keys
+	// the server never sees; KeyExchange holds the key messages relayed
+	// between them and KeyExchangeSeq the sequence number of the last one
+	E2EE           bool                 `json:"e2ee,omitempty"`
+	KeyExchange    []KeyExchangeMessage `json:"keyExchange,omitempty"`
+	KeyExchangeSeq int                  `json:"keyExchangeSeq,omitempty"`
+
+	// SFU streams the session through the server's selective forwarding
+	// unit to any number of viewers instead of peer-to-peer to one;
+	// SFUViewers counts the viewers connected to it
+	SFU        bool `json:"sfu,omitempty"`
+	SFUViewers int  `json:"sfuViewers,omitempty"`
+
+	// MaxBitrateKbps caps the sender's video bitrate through the bandwidth
+	// lines of the relayed SDP; 0 leaves it uncapped
+	MaxBitrateKbps int `json:"maxBitrateKbps,omitempty"`
+
+	// MaxFrameRate caps the frames per second the sender captures, through
+	// its page and the framerate attribute of the relayed SDP; 0 leaves it
+	// uncapped
+	MaxFrameRate int `json:"maxFrameRate,omitempty"`
+
+	// Codec is the video codec the relayed descriptions are steered to
+	Codec CodecPreference `json:"codec"`
+
+	// Preset is the ID of the quality preset the sender picked, if any
+	Preset string `json:"preset,omitempty"`
+
+	// Template is the name of the session template the session started
+	// from, if any
+	Template string `json:"template,omitempty"`
+
+	// Audio says the sender shares the screen's sound with its picture
+	Audio bool `json:"audio,omitempty"`
+
+	// MaxViewers bounds how many viewers may watch or wait at once; 0
+	// leaves the audience unbounded
+	MaxViewers int `json:"maxViewers,omitempty"`
+
+	// Queue holds viewers waiting for the session's viewer slot
+	Queue []QueuedViewer `json:"queue,omitempty"`
+
+	// ReservedFor is the queued viewer admitted to the free slot, if any
+	ReservedFor   string    `json:"reservedFor,omitempty"`
+	ReservedUntil time.Time `json:"reservedUntil"`
+
+	// EndedAt is when the session was ended
+	EndedAt time.Time `json:"endedAt"`
+
+	// PeakViewers is the largest audience seen, counting queued viewers
+	PeakViewers int `json:"peakViewers"`
+
+	// BytesSent is the sender's reported total of media bytes sent
+	BytesSent int64 `json:"bytesSent"`
+
+	// Bitrate is the send rate in bits per second between the last two heartbeats
+	Bitrate int64 `json:"bitrate"`
+
+	// PIN, when set, must be given by viewers before they receive the offer.
+	// PINFailures counts the wrong PINs given; at MaxPINFailures the
+	// session refuses every PIN, so the six digits cannot be guessed.
+	PIN         string `json:"pin,omitempty"`
+	PINFailures int    `json:"pinFailures,omitempty"`
+
+	// Paused says the sender hid its stream for a moment; viewers show a
+	// splash instead of the picture until it resumes
+	Paused bool `json:"paused,omitempty"`
+
+	// SenderKey proves that a sender page owns the session, so a reloaded
+	// page can resume it. DetachedAt is when the page went away saying it
+	// may come back; the session goes stale unless it does within
+	// SenderResumeWindow.
+	SenderKey  string    `json:"senderKey,omitempty"`
+	DetachedAt time.Time `json:"detachedAt"`
+
+	// Links are the viewer links the sender handed out, each expiring and
+	// running out of uses on its own
+	Links []ViewerLink `json:"links,omitempty"`
+
+	// InvitedAt is when the sender posted the session's viewer link to the
+	// team chat; it is posted once per session
+	InvitedAt time.Time `json:"invitedAt"`
+
+	// Owner is the ID in the sender cookie of the browser that created the
+	// session, so its landing page can list and rename its own shares
+	Owner string `json:"owner,omitempty"`
+
+	// User is who started the session, from the API token it was created
+	// with; nil when the server does not require tokens
+	User *UserIdentity `json:"user,omitempty"`
+}
+
+// MaxPINFailures is how many wrong PINs lock a session
+const MaxPINFailures = 10
+
+// SenderResumeWindow is how long a session waits for a sender page that went
+// away, e.g. to reload, to resume it
+const SenderResumeWindow = 30 * time.Second
+
+// QueuedViewer is a viewer waiting for a full session to free up
+type QueuedViewer struct {
+	ID       string    `json:"id"`
+	JoinedAt time.Time `json:"joinedAt"`
 }
 
 // SessionStatus represents the current status of a session
@@ -22,24 +189,335 @@ const (
 	SessionStatusActive    SessionStatus = "active"
 	SessionStatusCompleted SessionStatus = "completed"
 	SessionStatusExpired   SessionStatus = "expired"
+	SessionStatusEnded     SessionStatus = "ended"
+	SessionStatusStale     SessionStatus = "stale"
 )
 
+// IsValid reports whether the status is one of the known statuses
+func (s SessionStatus) IsValid() bool {
+	switch s {
+	case SessionStatusPending, SessionStatusActive, SessionStatusCompleted, SessionStatusExpired, SessionStatusEnded, SessionStatusStale:
+		return true
+	}
+	return false
+}
+
+// Clone returns a deep copy of the session
+func (s *Session) Clone() *Session {
+	sessionCopy := *s
+	if s.Offer != nil {
+		offerCopy := *s.Offer
+		sessionCopy.Offer = &offerCopy
+	}
+	if s.Answer != nil {
+		answerCopy := *s.Answer
+		sessionCopy.Answer = &answerCopy
+	}
+	if s.Queue != nil {
+		sessionCopy.Queue = append([]QueuedViewer(nil), s.Queue...)
+	}
+	if s.Chat != nil {
+		sessionCopy.Chat = append([]ChatMessage(nil), s.Chat...)
+	}
+	if s.Negotiation != nil {
+		sessionCopy.Negotiation = append([]NegotiationMessage(nil), s.Negotiation...)
+	}
+	if s.KeyExchange != nil {
+		sessionCopy.KeyExchange = append([]KeyExchangeMessage(nil), s.KeyExchange...)
+	}
+	if s.Revoked != nil {
+		sessionCopy.Revoked = append([]string(nil), s.Revoked...)
+	}
+	if s.Links != nil {
+		sessionCopy.Links = make([]ViewerLink, len(s.Links))
+		for i, link := range s.Links {
+			link.Redeemers = append([]string(nil), link.Redeemers...)
+			sessionCopy.Links[i] = link
+		}
+	}
+	return &sessionCopy
+}
+
 // IsExpired checks if the session has expired
 func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
 
+// IsEnded checks if the sender has ended the session
+func (s *Session) IsEnded() bool {
+	return s.Status == SessionStatusEnded
+}
+
+// IsSenderGone checks if a sender that has sent heartbeats stopped sending them
+func (s *Session) IsSenderGone(timeout time.Duration) bool {
+	return !s.SenderSeenAt.IsZero() && time.Since(s.SenderSeenAt) > timeout
+}
+
+// IsStale checks if the session was given up after its sender went silent
+func (s *Session) IsStale() bool {
+	return s.Status == SessionStatusStale
+}
+
+// ShouldGoStale checks if the sender has been silent for longer than the
+// session's heartbeat timeout, or went away and did not resume in time
+func (s *Session) ShouldGoStale() bool {
+	if !s.DetachedAt.IsZero() && time.Since(s.DetachedAt) > SenderResumeWindow {
+		return true
+	}
+	return s.HeartbeatTimeout > 0 && s.IsSenderGone(s.HeartbeatTimeout)
+}
+
+// CheckSenderKey reports whether key proves ownership of the session
+func (s *Session) CheckSenderKey(key string) bool {
+	return s.SenderKey != "" && subtle.ConstantTimeCompare([]byte(s.SenderKey), []byte(key)) == 1
+}
+
+// CheckOwner reports whether owner is the sender ID that created the session
+func (s *Session) CheckOwner(owner string) bool {
+	return s.Owner != "" && subtle.ConstantTimeCompare([]byte(s.Owner), []byte(owner)) == 1
+}
+
+// Detach notes that the sender page went away and may come back
+func (s *Session) Detach() {
+	s.DetachedAt = time.Now()
+}
+
+// Reattach notes that the sender page is back
+func (s *Session) Reattach() {
+	s.DetachedAt = time.Time{}
+	s.SenderSeenAt = time.Now()
+}
+
+// MarkStale marks the session as abandoned by its sender. It ends at the last
+// heartbeat and, like an ended session, is due for cleanup before its token
+// would expire.
+func (s *Session) MarkStale() {
+	s.Status = SessionStatusStale
+	s.EndedAt = s.SenderSeenAt
+	s.ExpiresAt = time.Now()
+}
+
+// RecordOffer notes the arrival of the session's first offer
+func (s *Session) RecordOffer() {
+	if s.OfferedAt.IsZero() {
+		s.OfferedAt = time.Now()
+	}
+}
+
+// RecordConnection notes that the session's first viewer connected
+func (s *Session) RecordConnection() {
+	if s.ConnectedAt.IsZero() {
+		s.ConnectedAt = time.Now()
+	}
+}
+
+// IsIdle checks if the session stalled before any viewer connected: no offer
+// within the idle timeout of its creation, or no viewer within the idle
+// timeout of its first offer
+func (s *Session) IsIdle() bool {
+	if s.IdleTimeout <= 0 || !s.ConnectedAt.IsZero() {
+		return false
+	}
+	waitingSince := s.CreatedAt
+	if !s.OfferedAt.IsZero() {
+		waitingSince = s.OfferedAt
+	}
+	return time.Since(waitingSince) > s.IdleTimeout
+}
+
+// ExpireIdle marks a session that stalled before any viewer connected as
+// expired and due for cleanup
+func (s *Session) ExpireIdle() {
+	now := time.Now()
+	s.Status = SessionStatusExpired
+	s.EndedAt = now
+	s.ExpiresAt = now
+}
+
+// IsIdleExpired checks if the session was expired early for idling
+func (s *Session) IsIdleExpired() bool {
+	return s.Status == SessionStatusExpired
+}
+
+// End marks the session as ended and due for cleanup
+func (s *Session) End() {
+	now := time.Now()
+	s.Status = SessionStatusEnded
+	s.EndedAt = now
+	s.ExpiresAt = now
+}
+
+// RecordAudience updates the peak audience with the connected and queued viewers
+func (s *Session) RecordAudience() {
+	if audience := s.audience(); audience > s.PeakViewers {
+		s.PeakViewers = audience
+	}
+}
+
+// audience counts the connected and queued viewers
+func (s *Session) audience() int {
+	audience := len(s.Queue) + s.SFUViewers
+	if s.IsFull() {
+		audience++
+	}
+	return audience
+}
+
+// RecordTraffic stores a sender heartbeat with its running total of bytes
+// sent, deriving the bitrate since the previous heartbeat
+func (s *Session) RecordTraffic(bytesSent int64, now time.Time) {
+	if bytesSent < s.BytesSent {
+		// Totals only grow; a lower figure is a stale or reordered heartbeat
+		bytesSent = s.BytesSent
+	}
+	if elapsed := now.Sub(s.SenderSeenAt); !s.SenderSeenAt.IsZero() && elapsed > 0 {
+		s.Bitrate = int64(float64(bytesSent-s.BytesSent) * 8 / elapsed.Seconds())
+	}
+	s.BytesSent = bytesSent
+	s.SenderSeenAt = now
+}
+
+// CheckPIN reports whether pin unlocks the session; sessions without a PIN accept any value
+func (s *Session) CheckPIN(pin string) bool {
+	if s.PIN == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(s.PIN), []byte(pin)) == 1
+}
+
+// RecordPINFailure counts a wrong PIN towards the lock
+func (s *Session) RecordPINFailure() {
+	s.PINFailures++
+}
+
+// PINLocked reports whether too many wrong PINs were given for the session
+func (s *Session) PINLocked() bool {
+	return s.PIN != "" && s.PINFailures >= MaxPINFailures
+}
+
 // IsActive checks if the session is currently active
 func (s *Session) IsActive() bool {
 	return s.Status == SessionStatusActive && !s.IsExpired()
 }
 
-// CanAcceptOffer checks if the session can accept a WebRTC offer
+// CanAcceptOffer checks if the session can accept a WebRTC offer, either the
+// first one or a replacement while negotiated
 func (s *Session) CanAcceptOffer() bool {
-	return s.Status == SessionStatusPending && !s.IsExpired()
+	return (s.Status == SessionStatusPending || s.Status == SessionStatusActive) && !s.IsExpired()
 }
 
 // CanAcceptAnswer checks if the session can accept a WebRTC answer
 func (s *Session) CanAcceptAnswer() bool {
 	return s.Offer != nil && s.Answer == nil && !s.IsExpired()
 }
+
+// IsFull checks if the session's viewer slot is taken
+func (s *Session) IsFull() bool {
+	return s.Answer != nil
+}
+
+// IsAudienceFull checks if the session has as many viewers, watching or
+// waiting, as its viewer limit allows
+func (s *Session) IsAudienceFull() bool {
+	return s.MaxViewers > 0 && s.audience() >= s.MaxViewers
+}
+
+// IsReservedForOther checks if the free slot is held for a different viewer
+func (s *Session) IsReservedForOther(viewerID string) bool {
+	return s.ReservedFor != "" && s.ReservedFor != viewerID && time.Now().Before(s.ReservedUntil)
+}
+
+// IsConsumed checks if a single-use session's viewer link has been used
+func (s *Session) IsConsumed() bool {
+	return s.SingleUse && s.ConsumedBy != ""
+}
+
+// IsConsumedByOther checks if the viewer link was used by a different viewer
+func (s *Session) IsConsumedByOther(viewerID string) bool {
+	return s.IsConsumed() && s.ConsumedBy != viewerID
+}
+
+// IsRevoked checks if the viewer was removed from the session
+func (s *Session) IsRevoked(viewerID string) bool {
+	return viewerID != "" && slices.Contains(s.Revoked, viewerID)
+}
+
+// Revoke removes a viewer from the session for good: it loses the slot,
+// its place in the queue or its reservation and its key messages, and its
+// ID stops working. A single-use link it had used up works once more, for
+// the viewer it was meant for. It reports whether the viewer held the
+// peer-to-peer slot.
+func (s *Session) Revoke(viewerID string) (connected bool) {
+	if !s.IsRevoked(viewerID) {
+		s.Revoked = append(s.Revoked, viewerID)
+	}
+	s.RemoveFromQueue(viewerID)
+	s.DropViewerKeys(viewerID)
+	if s.ReservedFor == viewerID {
+		s.ClearReservation()
+	}
+	if s.ConsumedBy == viewerID {
+		s.ConsumedBy = ""
+	}
+	if s.ViewerID != viewerID || !s.IsFull() {
+		return false
+	}
+	// Like a viewer leaving, the sender offers again for the next one
+	s.Offer = nil
+	s.Answer = nil
+	s.ViewerID = ""
+	s.ViewerName = ""
+	s.Status = SessionStatusPending
+	return true
+}
+
+// Consume ties a single-use session to the viewer whose answer was accepted
+func (s *Session) Consume(viewerID string) {
+	if s.SingleUse && s.ConsumedBy == "" {
+		s.ConsumedBy = viewerID
+	}
+}
+
+// Reserve holds the free slot for a viewer until the reservation lapses
+func (s *Session) Reserve(viewerID string, duration time.Duration) {
+	s.ReservedFor = viewerID
+	s.ReservedUntil = time.Now().Add(duration)
+}
+
+// ClearReservation releases any reservation on the slot
+func (s *Session) ClearReservation() {
+	s.ReservedFor = ""
+	s.ReservedUntil = time.Time{}
+}
+
+// QueuePosition returns the 1-based position of a viewer in the queue, or 0 if absent
+func (s *Session) QueuePosition(viewerID string) int {
+	for i, viewer := range s.Queue {
+		if viewer.ID == viewerID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// RemoveFromQueue removes a viewer from the queue
+func (s *Session) RemoveFromQueue(viewerID string) bool {
+	position := s.QueuePosition(viewerID)
+	if position == 0 {
+		return false
+	}
+	s.Queue = append(s.Queue[:position-1], s.Queue[position:]...)
+	return true
+}
+
+// PromoteInQueue moves a viewer to the front of the queue
+func (s *Session) PromoteInQueue(viewerID string) bool {
+	position := s.QueuePosition(viewerID)
+	if position == 0 {
+		return false
+	}
+	viewer := s.Queue[position-1]
+	copy(s.Queue[1:position], s.Queue[:position-1])
+	s.Queue[0] = viewer
+	return true
+}
@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// SessionRecord is the summary of a finished session kept in history
+type SessionRecord struct {
+	Token        string    `json:"token"`
+	Name         string    `json:"name,omitempty"`
+	StartedAt    time.Time `json:"startedAt"`
+	EndedAt      time.Time `json:"endedAt"`
+	PeakViewers  int       `json:"peakViewers"`
+	BytesSent    int64     `json:"bytesSent"`
+	RecordingURL string    `json:"recordingUrl,omitempty"`
+	Notes        string    `json:"notes,omitempty"`
+
+	// SenderKey and Owner are the session's, so only its sender can edit
+	// the notes once the session is over
+	SenderKey string `json:"senderKey,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+// NewSessionRecord builds the history record for an ended session
+func NewSessionRecord(session *Session) *SessionRecord {
+	endedAt := session.EndedAt
+	if endedAt.IsZero() {
+		endedAt = time.Now()
+	}
+
+	return &SessionRecord{
+		Token:       session.Token,
+		Name:        session.Name,
+		StartedAt:   session.CreatedAt,
+		EndedAt:     endedAt,
+		PeakViewers: session.PeakViewers,
+		BytesSent:   session.BytesSent,
+		SenderKey:   session.SenderKey,
+		Owner:       session.Owner,
+	}
+}
+
+// CheckSender reports whether senderKey or owner, the sender cookie's ID,
+// belongs to the session's sender
+func (r *SessionRecord) CheckSender(senderKey, owner string) bool {
+	if r.SenderKey != "" && subtle.ConstantTimeCompare([]byte(r.SenderKey), []byte(senderKey)) == 1 {
+		return true
+	}
+	return r.Owner != "" && subtle.ConstantTimeCompare([]byte(r.Owner), []byte(owner)) == 1
+}
+
+// Duration returns how long the session ran
+func (r *SessionRecord) Duration() time.Duration {
+	return r.EndedAt.Sub(r.StartedAt)
+}
+
+// AverageBitrate returns the average outgoing bitrate in bits per second
+func (r *SessionRecord) AverageBitrate() int64 {
+	seconds := r.Duration().Seconds()
+	if seconds <= 0 {
+		return 0
+	}
+	return int64(float64(r.BytesSent*8) / seconds)
+}
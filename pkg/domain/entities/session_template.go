@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// Bounds of the options a session template may set
+const (
+	// MaxTemplateViewers bounds a template's viewer limit
+	MaxTemplateViewers = 1000
+
+	// MinTemplateExpiry and MaxTemplateExpiry bound how long sessions
+	// started from a template last
+	MinTemplateExpiry = time.Minute
+	MaxTemplateExpiry = 24 * time.Hour
+)
+
+// SessionTemplate is a named set of session options admins define, so
+// senders start common kinds of shares, e.g. a meeting, in one step. The
+// template's options take precedence over those the sender picked.
+type SessionTemplate struct {
+	// Name is what senders pick the template by, e.g. "meeting"; it follows
+	// the rules of room names
+	Name string `json:"name"`
+
+	// Preset is the ID of the quality preset sessions use, if any
+	Preset string `json:"preset,omitempty"`
+
+	// Audio shares the screen's sound along with its picture
+	Audio bool `json:"audio"`
+
+	// RequirePIN protects sessions with a generated PIN
+	RequirePIN bool `json:"requirePin"`
+
+	// MaxViewers bounds how many viewers may watch or wait at once; 0
+	// leaves it to the session
+	MaxViewers int `json:"maxViewers,omitempty"`
+
+	// ExpirySeconds is how long sessions last; 0 keeps the server's
+	// token expiry
+	ExpirySeconds int `json:"expirySeconds,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Expiry returns how long sessions started from the template last, 0 for
+// the server's token expiry
+func (t *SessionTemplate) Expiry() time.Duration {
+	return time.Duration(t.ExpirySeconds) * time.Second
+}
+
+// IsValid reports whether the template's options are within bounds; the
+// preset is checked against the presets by the caller
+func (t *SessionTemplate) IsValid() bool {
+	if t.Name == "" || NormalizeRoomName(t.Name) != t.Name {
+		return false
+	}
+	if t.MaxViewers < 0 || t.MaxViewers > MaxTemplateViewers {
+		return false
+	}
+	expiry := t.Expiry()
+	return t.ExpirySeconds == 0 || (expiry >= MinTemplateExpiry && expiry <= MaxTemplateExpiry)
+}
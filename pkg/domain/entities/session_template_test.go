@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestSessionTemplate_IsValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template SessionTemplate
+		expected bool
+	}{
+		{
+			name:     "meeting",
+			template: SessionTemplate{Name: "meeting", Preset: "presentation", RequirePIN: true, MaxViewers: 10, ExpirySeconds: 3600},
+			expected: true,
+		},
+		{
+			name:     "name only",
+			template: SessionTemplate{Name: "quick-demo"},
+			expected: true,
+		},
+		{
+			name:     "no name",
+			template: SessionTemplate{},
+		},
+		{
+			name:     "name not in normal form",
+			template: SessionTemplate{Name: "Meeting"},
+		},
+		{
+			name:     "negative viewer limit",
+			template: SessionTemplate{Name: "meeting", MaxViewers: -1},
+		},
+		{
+			name:     "viewer limit too high",
+			template: SessionTemplate{Name: "meeting", MaxViewers: MaxTemplateViewers + 1},
+		},
+		{
+			name:     "expiry too short",
+			template: SessionTemplate{Name: "meeting", ExpirySeconds: 30},
+		},
+		{
+			name:     "expiry too long",
+			template: SessionTemplate{Name: "meeting", ExpirySeconds: 2 * 24 * 3600},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := tt.template.IsValid(); valid != tt.expected {
+				t.Errorf("Expected valid %v, got %v", tt.expected, valid)
+			}
+		})
+	}
+}
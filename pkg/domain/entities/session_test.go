@@ -108,13 +108,23 @@ func TestSession_CanAcceptOffer(t *testing.T) {
 			expected: true,
 		},
 		{
-			name: "active session cannot accept offer",
+			name: "active session can accept a replacement offer",
 			session: &Session{
 				Token:     "test-token",
 				CreatedAt: time.Now().Add(-10 * time.Minute),
 				ExpiresAt: time.Now().Add(10 * time.Minute),
 				Status:    SessionStatusActive,
 			},
+			expected: true,
+		},
+		{
+			name: "ended session cannot accept offer",
+			session: &Session{
+				Token:     "test-token",
+				CreatedAt: time.Now().Add(-10 * time.Minute),
+				ExpiresAt: time.Now().Add(10 * time.Minute),
+				Status:    SessionStatusEnded,
+			},
 			expected: false,
 		},
 		{
@@ -204,3 +214,444 @@ func TestSession_CanAcceptAnswer(t *testing.T) {
 		})
 	}
 }
+
+func TestSession_IsSenderGone(t *testing.T) {
+	tests := []struct {
+		name     string
+		seenAt   time.Time
+		expected bool
+	}{
+		{
+			name:     "no heartbeat yet",
+			seenAt:   time.Time{},
+			expected: false,
+		},
+		{
+			name:     "recent heartbeat",
+			seenAt:   time.Now().Add(-5 * time.Second),
+			expected: false,
+		},
+		{
+			name:     "heartbeat timed out",
+			seenAt:   time.Now().Add(-2 * time.Minute),
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{
+				Token:        "test-token",
+				ExpiresAt:    time.Now().Add(10 * time.Minute),
+				Status:       SessionStatusActive,
+				SenderSeenAt: tt.seenAt,
+			}
+			if result := session.IsSenderGone(30 * time.Second); result != tt.expected {
+				t.Errorf("IsSenderGone() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSession_ShouldGoStale(t *testing.T) {
+	tests := []struct {
+		name       string
+		seenAt     time.Time
+		detachedAt time.Time
+		timeout    time.Duration
+		expected   bool
+	}{
+		{
+			name:     "no heartbeat yet",
+			timeout:  time.Minute,
+			expected: false,
+		},
+		{
+			name:     "recent heartbeat",
+			seenAt:   time.Now().Add(-5 * time.Second),
+			timeout:  time.Minute,
+			expected: false,
+		},
+		{
+			name:     "heartbeat timed out",
+			seenAt:   time.Now().Add(-2 * time.Minute),
+			timeout:  time.Minute,
+			expected: true,
+		},
+		{
+			name:     "presence tracking disabled",
+			seenAt:   time.Now().Add(-2 * time.Minute),
+			expected: false,
+		},
+		{
+			name:       "sender page may still come back",
+			seenAt:     time.Now().Add(-20 * time.Second),
+			detachedAt: time.Now().Add(-10 * time.Second),
+			timeout:    time.Minute,
+			expected:   false,
+		},
+		{
+			name:       "sender page did not come back",
+			seenAt:     time.Now().Add(-40 * time.Second),
+			detachedAt: time.Now().Add(-35 * time.Second),
+			timeout:    time.Minute,
+			expected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{
+				Token:            "test-token",
+				ExpiresAt:        time.Now().Add(10 * time.Minute),
+				Status:           SessionStatusActive,
+				SenderSeenAt:     tt.seenAt,
+				HeartbeatTimeout: tt.timeout,
+				DetachedAt:       tt.detachedAt,
+			}
+			if result := session.ShouldGoStale(); result != tt.expected {
+				t.Errorf("ShouldGoStale() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSession_IsIdle(t *testing.T) {
+	tests := []struct {
+		name        string
+		createdAt   time.Time
+		offeredAt   time.Time
+		connectedAt time.Time
+		timeout     time.Duration
+		expected    bool
+	}{
+		{
+			name:      "waiting for the offer",
+			createdAt: time.Now().Add(-time.Minute),
+			timeout:   5 * time.Minute,
+			expected:  false,
+		},
+		{
+			name:      "no offer in time",
+			createdAt: time.Now().Add(-6 * time.Minute),
+			timeout:   5 * time.Minute,
+			expected:  true,
+		},
+		{
+			name:      "recent offer",
+			createdAt: time.Now().Add(-6 * time.Minute),
+			offeredAt: time.Now().Add(-time.Minute),
+			timeout:   5 * time.Minute,
+			expected:  false,
+		},
+		{
+			name:      "no viewer in time",
+			createdAt: time.Now().Add(-12 * time.Minute),
+			offeredAt: time.Now().Add(-6 * time.Minute),
+			timeout:   5 * time.Minute,
+			expected:  true,
+		},
+		{
+			name:        "viewer connected",
+			createdAt:   time.Now().Add(-12 * time.Minute),
+			offeredAt:   time.Now().Add(-11 * time.Minute),
+			connectedAt: time.Now().Add(-10 * time.Minute),
+			timeout:     5 * time.Minute,
+			expected:    false,
+		},
+		{
+			name:      "idle timeout disabled",
+			createdAt: time.Now().Add(-20 * time.Minute),
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{
+				Token:       "test-token",
+				CreatedAt:   tt.createdAt,
+				ExpiresAt:   time.Now().Add(10 * time.Minute),
+				Status:      SessionStatusPending,
+				IdleTimeout: tt.timeout,
+				OfferedAt:   tt.offeredAt,
+				ConnectedAt: tt.connectedAt,
+			}
+			if result := session.IsIdle(); result != tt.expected {
+				t.Errorf("IsIdle() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSession_MarkStale(t *testing.T) {
+	seenAt := time.Now().Add(-3 * time.Minute)
+	session := &Session{
+		Token:        "test-token",
+		CreatedAt:    time.Now().Add(-10 * time.Minute),
+		ExpiresAt:    time.Now().Add(20 * time.Minute),
+		Status:       SessionStatusActive,
+		SenderSeenAt: seenAt,
+	}
+
+	session.MarkStale()
+
+	if !session.IsStale() || session.IsEnded() {
+		t.Errorf("Expected stale status, got %s", session.Status)
+	}
+	if !session.EndedAt.Equal(seenAt) {
+		t.Errorf("Expected the session to end at the last heartbeat, got %v", session.EndedAt)
+	}
+	if !session.IsExpired() {
+		t.Error("Expected a stale session to be due for cleanup")
+	}
+}
+
+func TestSession_End(t *testing.T) {
+	session := &Session{
+		Token:     "test-token",
+		CreatedAt: time.Now().Add(-10 * time.Minute),
+		ExpiresAt: time.Now().Add(20 * time.Minute),
+		Status:    SessionStatusActive,
+	}
+
+	session.End()
+
+	if !session.IsEnded() {
+		t.Error("Expected session to be ended")
+	}
+	if session.IsActive() {
+		t.Error("Ended session should not be active")
+	}
+	if time.Until(session.ExpiresAt) > 0 {
+		t.Error("Ended session should be due for cleanup")
+	}
+}
+
+func TestSession_CheckPIN(t *testing.T) {
+	tests := []struct {
+		name     string
+		pin      string
+		given    string
+		expected bool
+	}{
+		{name: "no PIN accepts empty", pin: "", given: "", expected: true},
+		{name: "no PIN accepts any", pin: "", given: "123456", expected: true},
+		{name: "matching PIN", pin: "482913", given: "482913", expected: true},
+		{name: "wrong PIN", pin: "482913", given: "482914", expected: false},
+		{name: "missing PIN", pin: "482913", given: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{PIN: tt.pin}
+			if got := session.CheckPIN(tt.given); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSession_PINLocked(t *testing.T) {
+	session := &Session{PIN: "482913"}
+	for i := 1; i < MaxPINFailures; i++ {
+		session.RecordPINFailure()
+		if session.PINLocked() {
+			t.Fatalf("Expected the session open after %d wrong PINs", i)
+		}
+	}
+	session.RecordPINFailure()
+	if !session.PINLocked() {
+		t.Errorf("Expected the session locked after %d wrong PINs", MaxPINFailures)
+	}
+
+	open := &Session{PINFailures: MaxPINFailures}
+	if open.PINLocked() {
+		t.Error("Expected a session without a PIN never to lock")
+	}
+}
+
+func TestSession_Consume(t *testing.T) {
+	tests := []struct {
+		name             string
+		singleUse        bool
+		consumer         string
+		viewer           string
+		expectedOther    bool
+		expectConsumed   bool
+		expectedConsumer string
+	}{
+		{name: "reusable link is never consumed", singleUse: false, consumer: "first", viewer: "second", expectedOther: false, expectConsumed: false},
+		{name: "consumer may come back", singleUse: true, consumer: "first", viewer: "first", expectedOther: false, expectConsumed: true, expectedConsumer: "first"},
+		{name: "second device is refused", singleUse: true, consumer: "first", viewer: "second", expectedOther: true, expectConsumed: true, expectedConsumer: "first"},
+		{name: "unknown viewer is refused", singleUse: true, consumer: "first", viewer: "", expectedOther: true, expectConsumed: true, expectedConsumer: "first"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{SingleUse: tt.singleUse}
+			if session.IsConsumedByOther(tt.viewer) {
+				t.Fatal("Expected an unused link to accept anyone")
+			}
+
+			session.Consume(tt.consumer)
+			// Later answers must not move the link to another viewer
+			session.Consume("later")
+
+			if got := session.IsConsumed(); got != tt.expectConsumed {
+				t.Errorf("Expected consumed %v, got %v", tt.expectConsumed, got)
+			}
+			if got := session.IsConsumedByOther(tt.viewer); got != tt.expectedOther {
+				t.Errorf("Expected consumed by other %v, got %v", tt.expectedOther, got)
+			}
+			if session.ConsumedBy != tt.expectedConsumer {
+				t.Errorf("Expected consumer %q, got %q", tt.expectedConsumer, session.ConsumedBy)
+			}
+		})
+	}
+}
+
+func TestSession_RecordTraffic(t *testing.T) {
+	start := time.Now()
+	session := &Session{}
+
+	// The first heartbeat has nothing to compare against
+	session.RecordTraffic(1000, start)
+	if session.Bitrate != 0 || session.BytesSent != 1000 || !session.SenderSeenAt.Equal(start) {
+		t.Errorf("Unexpected first heartbeat state: %+v", session)
+	}
+
+	session.RecordTraffic(126000, start.Add(10*time.Second))
+	if session.Bitrate != 100000 {
+		t.Errorf("Expected 100000 bps, got %d", session.Bitrate)
+	}
+
+	// A stale total keeps the stored one and reports no traffic
+	session.RecordTraffic(5000, start.Add(20*time.Second))
+	if session.BytesSent != 126000 || session.Bitrate != 0 {
+		t.Errorf("Expected stale total ignored, got %d bytes at %d bps", session.BytesSent, session.Bitrate)
+	}
+}
+
+func TestSession_RecordAudience(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  *Session
+		expected int
+	}{
+		{
+			name:     "connected viewer and queue",
+			session:  &Session{Answer: &WebRTCAnswer{}, Queue: []QueuedViewer{{ID: "a"}, {ID: "b"}}},
+			expected: 3,
+		},
+		{
+			name:     "SFU viewers",
+			session:  &Session{SFU: true, SFUViewers: 20},
+			expected: 20,
+		},
+		{
+			name:     "peak is kept",
+			session:  &Session{SFU: true, SFUViewers: 2, PeakViewers: 5},
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.session.RecordAudience()
+			if tt.session.PeakViewers != tt.expected {
+				t.Errorf("Expected peak of %d, got %d", tt.expected, tt.session.PeakViewers)
+			}
+		})
+	}
+}
+
+func TestSession_IsAudienceFull(t *testing.T) {
+	tests := []struct {
+		name     string
+		session  *Session
+		expected bool
+	}{
+		{
+			name:    "no viewer limit",
+			session: &Session{SFU: true, SFUViewers: 500},
+		},
+		{
+			name:     "connected viewer and queue at the limit",
+			session:  &Session{MaxViewers: 3, Answer: &WebRTCAnswer{}, Queue: []QueuedViewer{{ID: "a"}, {ID: "b"}}},
+			expected: true,
+		},
+		{
+			name:    "room in the queue",
+			session: &Session{MaxViewers: 3, Answer: &WebRTCAnswer{}, Queue: []QueuedViewer{{ID: "a"}}},
+		},
+		{
+			name:     "SFU viewers at the limit",
+			session:  &Session{SFU: true, MaxViewers: 20, SFUViewers: 20},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if full := tt.session.IsAudienceFull(); full != tt.expected {
+				t.Errorf("Expected audience full %v, got %v", tt.expected, full)
+			}
+		})
+	}
+}
+
+func TestSession_Revoke(t *testing.T) {
+	tests := []struct {
+		name              string
+		session           *Session
+		viewerID          string
+		expectedConnected bool
+	}{
+		{
+			name:              "connected viewer",
+			session:           &Session{Offer: &WebRTCOffer{}, Answer: &WebRTCAnswer{}, ViewerID: "a", Status: SessionStatusActive},
+			viewerID:          "a",
+			expectedConnected: true,
+		},
+		{
+			name:     "queued viewer",
+			session:  &Session{Offer: &WebRTCOffer{}, Answer: &WebRTCAnswer{}, ViewerID: "a", Status: SessionStatusActive, Queue: []QueuedViewer{{ID: "b"}}},
+			viewerID: "b",
+		},
+		{
+			name:     "reserved viewer",
+			session:  &Session{Offer: &WebRTCOffer{}, ReservedFor: "b", ReservedUntil: time.Now().Add(time.Minute), Status: SessionStatusActive},
+			viewerID: "b",
+		},
+		{
+			name:              "viewer of a used single-use link",
+			session:           &Session{Offer: &WebRTCOffer{}, Answer: &WebRTCAnswer{}, SingleUse: true, ConsumedBy: "a", ViewerID: "a", Status: SessionStatusActive},
+			viewerID:          "a",
+			expectedConnected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if connected := tt.session.Revoke(tt.viewerID); connected != tt.expectedConnected {
+				t.Errorf("Expected connected %v, got %v", tt.expectedConnected, connected)
+			}
+			if !tt.session.IsRevoked(tt.viewerID) {
+				t.Error("Expected the viewer revoked")
+			}
+			if tt.session.QueuePosition(tt.viewerID) != 0 || tt.session.ReservedFor == tt.viewerID || tt.session.ConsumedBy == tt.viewerID {
+				t.Errorf("Expected the viewer to lose its place, got %+v", tt.session)
+			}
+			if tt.expectedConnected && (tt.session.IsFull() || tt.session.Offer != nil || tt.session.Status != SessionStatusPending) {
+				t.Errorf("Expected the slot freed for a new offer, got %+v", tt.session)
+			}
+			if !tt.expectedConnected && tt.session.Offer == nil {
+				t.Error("Expected the connected viewer's handshake kept")
+			}
+			if tt.session.IsRevoked("") {
+				t.Error("Expected viewers without an ID never revoked")
+			}
+		})
+	}
+}
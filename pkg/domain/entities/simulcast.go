@@ -0,0 +1,35 @@
+package entities
+
+import "slices"
+
+// SimulcastLayer names one of the encodings a simulcast sender publishes, or
+// LayerAuto for the relay to pick one
+type SimulcastLayer string
+
+const (
+	LayerLow  SimulcastLayer = "low"
+	LayerMid  SimulcastLayer = "mid"
+	LayerHigh SimulcastLayer = "high"
+
+	// LayerAuto lets the relay move a viewer between layers as its
+	// connection gets worse or better
+	LayerAuto SimulcastLayer = "auto"
+)
+
+// SimulcastLayers are the layers a simulcast sender publishes, smallest
+// first; their names are the RIDs of the sender's encodings
+var SimulcastLayers = []SimulcastLayer{LayerLow, LayerMid, LayerHigh}
+
+// IsValid reports whether the layer is a known layer or LayerAuto
+func (l SimulcastLayer) IsValid() bool {
+	return l == LayerAuto || slices.Contains(SimulcastLayers, l)
+}
+
+// Rank orders layers by picture size. A stream without simulcast, or with
+// encodings named otherwise, carries the full picture and ranks highest.
+func (l SimulcastLayer) Rank() int {
+	if rank := slices.Index(SimulcastLayers, l); rank >= 0 {
+		return rank
+	}
+	return len(SimulcastLayers) - 1
+}
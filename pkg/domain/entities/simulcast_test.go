@@ -0,0 +1,29 @@
+package entities
+
+import "testing"
+
+func TestSimulcastLayer(t *testing.T) {
+	tests := []struct {
+		layer SimulcastLayer
+		valid bool
+		rank  int
+	}{
+		{layer: LayerLow, valid: true, rank: 0},
+		{layer: LayerMid, valid: true, rank: 1},
+		{layer: LayerHigh, valid: true, rank: 2},
+		{layer: LayerAuto, valid: true, rank: 2},
+		{layer: "", valid: false, rank: 2},
+		{layer: "ultra", valid: false, rank: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.layer), func(t *testing.T) {
+			if got := tt.layer.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+			if got := tt.layer.Rank(); got != tt.rank {
+				t.Errorf("Rank() = %d, want %d", got, tt.rank)
+			}
+		})
+	}
+}
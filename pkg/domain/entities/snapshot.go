@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// SnapshotVersion is the format version of snapshots written by this build
+const SnapshotVersion = 1
+
+// Snapshot is the stored state of a server: live sessions, the history of
+// finished sessions with their recording links, and viewer device settings.
+// Storage backends exchange state in this form.
+type Snapshot struct {
+	Version        int               `json:"version"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	Sessions       []*Session        `json:"sessions"`
+	History        []*SessionRecord  `json:"history"`
+	DeviceSettings []*DeviceSettings `json:"deviceSettings"`
+}
+
+// NewSnapshot creates an empty snapshot in the current format
+func NewSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate lists the problems that make the snapshot unsafe to load
+func (s *Snapshot) Validate() []error {
+	var problems []error
+	if s.Version < 1 || s.Version > SnapshotVersion {
+		problems = append(problems, fmt.Errorf("unsupported snapshot version %d", s.Version))
+	}
+
+	tokens := make(map[string]bool)
+	for i, session := range s.Sessions {
+		switch {
+		case session == nil || session.Token == "":
+			problems = append(problems, fmt.Errorf("session %d has no token", i))
+		case tokens[session.Token]:
+			problems = append(problems, fmt.Errorf("session %s appears twice", ShortToken(session.Token)))
+		case !session.Status.IsValid():
+			problems = append(problems, fmt.Errorf("session %s has unknown status %q", ShortToken(session.Token), session.Status))
+		default:
+			tokens[session.Token] = true
+		}
+	}
+
+	records := make(map[string]bool)
+	for i, record := range s.History {
+		switch {
+		case record == nil || record.Token == "":
+			problems = append(problems, fmt.Errorf("history record %d has no token", i))
+		case records[record.Token]:
+			problems = append(problems, fmt.Errorf("history record %s appears twice", ShortToken(record.Token)))
+		case record.EndedAt.Before(record.StartedAt):
+			problems = append(problems, fmt.Errorf("history record %s ends before it starts", ShortToken(record.Token)))
+		default:
+			records[record.Token] = true
+		}
+	}
+
+	devices := make(map[string]bool)
+	for i, settings := range s.DeviceSettings {
+		switch {
+		case settings == nil || settings.DeviceID == "":
+			problems = append(problems, fmt.Errorf("device settings %d have no device ID", i))
+		case devices[settings.DeviceID]:
+			problems = append(problems, fmt.Errorf("device %s appears twice", settings.DeviceID))
+		default:
+			devices[settings.DeviceID] = true
+		}
+	}
+
+	return problems
+}
+
+// Recordings counts the history records that link to a recording
+func (s *Snapshot) Recordings() int {
+	count := 0
+	for _, record := range s.History {
+		if record != nil && record.RecordingURL != "" {
+			count++
+		}
+	}
+	return count
+}
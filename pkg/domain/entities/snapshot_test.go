@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshot_Validate(t *testing.T) {
+	now := time.Now()
+	validSession := &Session{Token: "session-token", Status: SessionStatusActive}
+	validRecord := &SessionRecord{Token: "record-token", StartedAt: now.Add(-time.Hour), EndedAt: now}
+	validSettings := &DeviceSettings{DeviceID: "device-1"}
+
+	tests := []struct {
+		name             string
+		snapshot         *Snapshot
+		expectedProblems int
+	}{
+		{
+			name: "valid snapshot",
+			snapshot: &Snapshot{
+				Version:        SnapshotVersion,
+				Sessions:       []*Session{validSession},
+				History:        []*SessionRecord{validRecord},
+				DeviceSettings: []*DeviceSettings{validSettings},
+			},
+			expectedProblems: 0,
+		},
+		{
+			name:             "empty snapshot",
+			snapshot:         NewSnapshot(),
+			expectedProblems: 0,
+		},
+		{
+			name:             "unsupported version",
+			snapshot:         &Snapshot{Version: SnapshotVersion + 1},
+			expectedProblems: 1,
+		},
+		{
+			name: "broken sessions",
+			snapshot: &Snapshot{
+				Version: SnapshotVersion,
+				Sessions: []*Session{
+					validSession,
+					validSession,
+					nil,
+					{Token: "other-token", Status: "paused"},
+				},
+			},
+			expectedProblems: 3,
+		},
+		{
+			name: "broken history",
+			snapshot: &Snapshot{
+				Version: SnapshotVersion,
+				History: []*SessionRecord{
+					validRecord,
+					validRecord,
+					{Token: ""},
+					{Token: "backwards", StartedAt: now, EndedAt: now.Add(-time.Minute)},
+				},
+			},
+			expectedProblems: 3,
+		},
+		{
+			name: "broken device settings",
+			snapshot: &Snapshot{
+				Version:        SnapshotVersion,
+				DeviceSettings: []*DeviceSettings{validSettings, validSettings, {}},
+			},
+			expectedProblems: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := tt.snapshot.Validate()
+			if len(problems) != tt.expectedProblems {
+				t.Errorf("Validate() returned %d problems %v, want %d", len(problems), problems, tt.expectedProblems)
+			}
+		})
+	}
+}
+
+func TestSnapshot_Recordings(t *testing.T) {
+	snapshot := &Snapshot{History: []*SessionRecord{
+		{Token: "a", RecordingURL: "https://example.com/a.webm"},
+		{Token: "b"},
+		nil,
+	}}
+
+	if count := snapshot.Recordings(); count != 1 {
+		t.Errorf("Recordings() = %d, want 1", count)
+	}
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"time"
+)
+
+// Roles of the peers that upload stats
+const (
+	StatsRoleSender = "sender"
+	StatsRoleViewer = "viewer"
+)
+
+// Bounds on uploaded stats; values outside them are measurement errors or junk
+const (
+	maxStatsFrameRate = 240
+	maxStatsRTTMillis = 60_000
+)
+
+// StatsSample is a summary of one peer's WebRTC statistics (getStats()) at a
+// point in a session
+type StatsSample struct {
+	At       time.Time `json:"at"`
+	Role     string    `json:"role"`
+	ViewerID string    `json:"viewerId,omitempty"`
+
+	// Bitrate is the video the peer sent (sender) or received (viewer) since
+	// its previous sample, in bits per second
+	Bitrate int64 `json:"bitrate"`
+
+	// FrameRate is the frames per second sent or decoded
+	FrameRate float64 `json:"fps"`
+
+	// PacketLoss is the share of packets lost since the previous sample, 0 to 1
+	PacketLoss float64 `json:"packetLoss"`
+
+	// RTTMillis is the round-trip time of the connection in milliseconds
+	RTTMillis float64 `json:"rttMs"`
+}
+
+// IsValid reports whether the sample has a known role and plausible values
+func (s *StatsSample) IsValid() bool {
+	if s.Role != StatsRoleSender && s.Role != StatsRoleViewer {
+		return false
+	}
+	return s.Bitrate >= 0 &&
+		s.FrameRate >= 0 && s.FrameRate <= maxStatsFrameRate &&
+		s.PacketLoss >= 0 && s.PacketLoss <= 1 &&
+		s.RTTMillis >= 0 && s.RTTMillis <= maxStatsRTTMillis
+}
+
+// Source identifies the peer that uploaded the sample: "sender", or
+// "viewer:<id>" for a viewer
+func (s *StatsSample) Source() string {
+	if s.Role == StatsRoleViewer {
+		return StatsRoleViewer + ":" + s.ViewerID
+	}
+	return StatsRoleSender
+}
+
+// Connection quality ratings, from the best
+const (
+	QualityGood = "good"
+	QualityFair = "fair"
+	QualityPoor = "poor"
+)
+
+// Thresholds of packet loss and round-trip time beyond which a connection is
+// rated fair or poor: video stutters past a few percent of loss, and remote
+// control lags past a few hundred milliseconds
+const (
+	fairPacketLoss = 0.01
+	poorPacketLoss = 0.05
+	fairRTTMillis  = 150
+	poorRTTMillis  = 400
+)
+
+// RateConnection rates a connection by its packet loss and round-trip time.
+// Frame rates are left out: a shared screen that does not change sends
+// hardly any frames over a perfect connection.
+func RateConnection(packetLoss, rttMillis float64) string {
+	switch {
+	case packetLoss >= poorPacketLoss || rttMillis >= poorRTTMillis:
+		return QualityPoor
+	case packetLoss >= fairPacketLoss || rttMillis >= fairRTTMillis:
+		return QualityFair
+	default:
+		return QualityGood
+	}
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "testing"
+
+func TestStatsSample_IsValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample StatsSample
+		valid  bool
+	}{
+		{name: "sender", sample: StatsSample{Role: StatsRoleSender, Bitrate: 2_500_000, FrameRate: 30, PacketLoss: 0.01, RTTMillis: 12}, valid: true},
+		{name: "viewer", sample: StatsSample{Role: StatsRoleViewer, ViewerID: "v1"}, valid: true},
+		{name: "unknown role", sample: StatsSample{Role: "admin"}, valid: false},
+		{name: "negative bitrate", sample: StatsSample{Role: StatsRoleSender, Bitrate: -1}, valid: false},
+		{name: "frame rate too high", sample: StatsSample{Role: StatsRoleSender, FrameRate: 1000}, valid: false},
+		{name: "loss above one", sample: StatsSample{Role: StatsRoleSender, PacketLoss: 1.5}, valid: false},
+		{name: "negative RTT", sample: StatsSample{Role: StatsRoleSender, RTTMillis: -3}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sample.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestStatsSample_Source(t *testing.T) {
+	sender := StatsSample{Role: StatsRoleSender, ViewerID: "ignored"}
+	viewer := StatsSample{Role: StatsRoleViewer, ViewerID: "v1"}
+	if sender.Source() != "sender" || viewer.Source() != "viewer:v1" {
+		t.Errorf("Unexpected sources %q and %q", sender.Source(), viewer.Source())
+	}
+}
+
+func TestRateConnection(t *testing.T) {
+	tests := []struct {
+		packetLoss float64
+		rttMillis  float64
+		expected   string
+	}{
+		{0, 12, QualityGood},
+		{0.009, 149, QualityGood},
+		{0.01, 20, QualityFair},
+		{0, 150, QualityFair},
+		{0.05, 20, QualityPoor},
+		{0.001, 400, QualityPoor},
+	}
+	for _, tt := range tests {
+		if got := RateConnection(tt.packetLoss, tt.rttMillis); got != tt.expected {
+			t.Errorf("RateConnection(%v, %v) = %q, expected %q", tt.packetLoss, tt.rttMillis, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenFormat is how the random bytes of a session token are written
+type TokenFormat string
+
+const (
+	// TokenFormatBase64URL is URL-safe base64 without padding, the default
+	TokenFormatBase64URL TokenFormat = "base64url"
+	// TokenFormatHex is lowercase hexadecimal
+	TokenFormatHex TokenFormat = "hex"
+	// TokenFormatBase32 is Crockford's base32, uppercase and without the
+	// easily confused letters I, L, O and U, for tokens read out or typed
+	TokenFormatBase32 TokenFormat = "base32"
+	// TokenFormatUUID is a random (version 4) UUID, always 16 bytes
+	TokenFormatUUID TokenFormat = "uuid"
+)
+
+const (
+	// DefaultTokenBytes is the random bytes in a token when none are configured
+	DefaultTokenBytes = 9
+	// MinTokenBytes keeps tokens too long to guess; MaxTokenBytes keeps
+	// them short enough for links and QR codes
+	MinTokenBytes = 8
+	MaxTokenBytes = 64
+
+	// DefaultTokenLifetime is how long signed tokens stay valid when no
+	// lifetime is configured
+	DefaultTokenLifetime = 12 * time.Hour
+	// MinTokenSecretLength is the shortest secret accepted for signing
+	MinTokenSecretLength = 16
+
+	// tokenExpiryBytes and tokenMACBytes make up the signed part of a
+	// token: the expiry in Unix seconds and the truncated HMAC-SHA256
+	tokenExpiryBytes = 5
+	tokenMACBytes    = 16
+)
+
+// crockfordAlphabet is Crockford's base32 alphabet
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var crockfordEncoding = base32.NewEncoding(crockfordAlphabet).WithPadding(base32.NoPadding)
+
+// TokenPolicy describes the session tokens a server hands out. The zero
+// value is the default: 9 random bytes in base64url.
+type TokenPolicy struct {
+	Format TokenFormat
+	Bytes  int
+
+	// Secret, when set, signs tokens: after a dot, each token carries its
+	// expiry and an HMAC-SHA256 of the rest, so forged and expired tokens
+	// are refused without a session lookup. Signed tokens are valid for
+	// Lifetime.
+	Secret   string
+	Lifetime time.Duration
+}
+
+// ParseTokenPolicy validates a configured token format and length; an empty
+// format is base64url and 0 bytes is DefaultTokenBytes. UUIDs have a fixed
+// length, so bytes must be 0 or 16 with them. A secret signs the tokens,
+// valid for lifetime, or DefaultTokenLifetime when it is 0.
+func ParseTokenPolicy(format string, bytes int, secret string, lifetime time.Duration) (TokenPolicy, error) {
+	policy, err := parseTokenFormat(format, bytes)
+	if err != nil {
+		return TokenPolicy{}, err
+	}
+	if len(secret) == 0 {
+		return policy, nil
+	}
+
+	if len(secret) < MinTokenSecretLength {
+		return TokenPolicy{}, fmt.Errorf("token secret must be at least %d characters", MinTokenSecretLength)
+	}
+	if lifetime == 0 {
+		lifetime = DefaultTokenLifetime
+	}
+	if lifetime < time.Minute {
+		return TokenPolicy{}, fmt.Errorf("token lifetime of %s is shorter than a minute", lifetime)
+	}
+	policy.Secret = secret
+	policy.Lifetime = lifetime
+	return policy, nil
+}
+
+// parseTokenFormat validates the format and length of the random part
+func parseTokenFormat(format string, bytes int) (TokenPolicy, error) {
+	policy := TokenPolicy{Format: TokenFormat(strings.ToLower(strings.TrimSpace(format))), Bytes: bytes}
+	switch policy.Format {
+	case "":
+		policy.Format = TokenFormatBase64URL
+	case TokenFormatBase64URL, TokenFormatHex, TokenFormatBase32:
+	case TokenFormatUUID:
+		if bytes != 0 && bytes != 16 {
+			return TokenPolicy{}, fmt.Errorf("uuid tokens are 16 bytes, not %d", bytes)
+		}
+		policy.Bytes = 16
+		return policy, nil
+	default:
+		return TokenPolicy{}, fmt.Errorf("unknown token format %q: expected base64url, hex, base32 or uuid", format)
+	}
+
+	if policy.Bytes == 0 {
+		policy.Bytes = DefaultTokenBytes
+	}
+	if policy.Bytes < MinTokenBytes || policy.Bytes > MaxTokenBytes {
+		return TokenPolicy{}, fmt.Errorf("token length of %d bytes is outside %d-%d", policy.Bytes, MinTokenBytes, MaxTokenBytes)
+	}
+	return policy, nil
+}
+
+// RandomBytes is how many random bytes Encode expects
+func (p TokenPolicy) RandomBytes() int {
+	if p.Format == TokenFormatUUID {
+		return 16
+	}
+	if p.Bytes == 0 {
+		return DefaultTokenBytes
+	}
+	return p.Bytes
+}
+
+// Signed reports whether tokens carry an expiry and signature
+func (p TokenPolicy) Signed() bool {
+	return len(p.Secret) > 0
+}
+
+// Deadline is the expiry a signed token created at now carries, or the zero
+// time for unsigned tokens, which do not expire by themselves
+func (p TokenPolicy) Deadline(now time.Time) time.Time {
+	if !p.Signed() {
+		return time.Time{}
+	}
+	return now.Add(p.Lifetime).Truncate(time.Second)
+}
+
+// Encode writes random bytes, RandomBytes of them, as a token; signed tokens
+// get the Deadline for now
+func (p TokenPolicy) Encode(random []byte, now time.Time) string {
+	token := p.encodeRandom(random)
+	if !p.Signed() {
+		return token
+	}
+
+	signed := make([]byte, tokenExpiryBytes, tokenExpiryBytes+tokenMACBytes)
+	var expiry [8]byte
+	binary.BigEndian.PutUint64(expiry[:], uint64(p.Deadline(now).Unix()))
+	copy(signed, expiry[8-tokenExpiryBytes:])
+	signed = append(signed, p.mac(token, signed)...)
+	return token + "." + p.encodeBytes(signed)
+}
+
+// encodeRandom writes the random part of a token
+func (p TokenPolicy) encodeRandom(random []byte) string {
+	switch p.Format {
+	case TokenFormatHex:
+		return hex.EncodeToString(random)
+	case TokenFormatBase32:
+		return crockfordEncoding.EncodeToString(random)
+	case TokenFormatUUID:
+		// Version 4, RFC 4122 variant
+		random[6] = random[6]&0x0f | 0x40
+		random[8] = random[8]&0x3f | 0x80
+		s := hex.EncodeToString(random)
+		return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:32]
+	default:
+		return base64.RawURLEncoding.EncodeToString(random)
+	}
+}
+
+// encodeBytes writes the signed part of a token in the alphabet of the
+// format; UUIDs use hex
+func (p TokenPolicy) encodeBytes(b []byte) string {
+	switch p.Format {
+	case TokenFormatHex, TokenFormatUUID:
+		return hex.EncodeToString(b)
+	case TokenFormatBase32:
+		return crockfordEncoding.EncodeToString(b)
+	default:
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+}
+
+// decodeBytes reads the signed part of a token
+func (p TokenPolicy) decodeBytes(s string) ([]byte, error) {
+	switch p.Format {
+	case TokenFormatHex, TokenFormatUUID:
+		return hex.DecodeString(s)
+	case TokenFormatBase32:
+		return crockfordEncoding.DecodeString(s)
+	default:
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+}
+
+// mac signs the random part of a token and its expiry
+func (p TokenPolicy) mac(random string, expiry []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+	mac.Write([]byte(random))
+	mac.Write(expiry)
+	return mac.Sum(nil)[:tokenMACBytes]
+}
+
+// Valid reports whether token could have been handed out under the policy,
+// with a correct signature if tokens are signed, so malformed and forged
+// tokens can be turned away without a lookup. It does not check the expiry.
+func (p TokenPolicy) Valid(token string) bool {
+	if !p.Signed() {
+		return p.validRandom(token)
+	}
+	_, ok := p.verify(token)
+	return ok
+}
+
+// ExpiresAt returns the expiry a valid signed token carries, or the zero
+// time for unsigned and invalid tokens
+func (p TokenPolicy) ExpiresAt(token string) time.Time {
+	if !p.Signed() {
+		return time.Time{}
+	}
+	expiresAt, _ := p.verify(token)
+	return expiresAt
+}
+
+// verify checks the signature of a signed token and returns its expiry
+func (p TokenPolicy) verify(token string) (time.Time, bool) {
+	random, signature, found := strings.Cut(token, ".")
+	if !found || !p.validRandom(random) || len(signature) != len(p.encodeBytes(make([]byte, tokenExpiryBytes+tokenMACBytes))) {
+		return time.Time{}, false
+	}
+	signed, err := p.decodeBytes(signature)
+	if err != nil || len(signed) != tokenExpiryBytes+tokenMACBytes {
+		return time.Time{}, false
+	}
+	expiry, mac := signed[:tokenExpiryBytes], signed[tokenExpiryBytes:]
+	if !hmac.Equal(mac, p.mac(random, expiry)) {
+		return time.Time{}, false
+	}
+
+	var unix [8]byte
+	copy(unix[8-tokenExpiryBytes:], expiry)
+	return time.Unix(int64(binary.BigEndian.Uint64(unix[:])), 0), true
+}
+
+// validRandom reports whether s is a well-formed random part of a token
+func (p TokenPolicy) validRandom(token string) bool {
+	n := p.RandomBytes()
+	switch p.Format {
+	case TokenFormatHex:
+		return len(token) == hex.EncodedLen(n) && onlyChars(token, "0123456789abcdef")
+	case TokenFormatBase32:
+		return len(token) == crockfordEncoding.EncodedLen(n) && onlyChars(token, crockfordAlphabet)
+	case TokenFormatUUID:
+		if len(token) != 36 || token[8] != '-' || token[13] != '-' || token[18] != '-' || token[23] != '-' {
+			return false
+		}
+		digits := token[0:8] + token[9:13] + token[14:18] + token[19:23] + token[24:36]
+		return onlyChars(digits, "0123456789abcdef") && token[14] == '4' && strings.ContainsRune("89ab", rune(token[19]))
+	default:
+		return len(token) == base64.RawURLEncoding.EncodedLen(n) &&
+			onlyChars(token, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_")
+	}
+}
+
+// onlyChars reports whether every byte of s is in chars
+func onlyChars(s, chars string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(chars, s[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ShortToken truncates a token for logs and messages; tokens from clients may
+// be any length
+func ShortToken(token string) string {
+	if len(token) > 8 {
+		return token[:8] + "..."
+	}
+	return token + "..."
+}
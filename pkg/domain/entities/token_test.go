@@ -0,0 +1,169 @@
+package entities
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTokenPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		bytes    int
+		expected TokenPolicy
+		wantErr  bool
+	}{
+		{name: "defaults", expected: TokenPolicy{Format: TokenFormatBase64URL, Bytes: DefaultTokenBytes}},
+		{name: "hex", format: " HEX ", bytes: 16, expected: TokenPolicy{Format: TokenFormatHex, Bytes: 16}},
+		{name: "base32", format: "base32", expected: TokenPolicy{Format: TokenFormatBase32, Bytes: DefaultTokenBytes}},
+		{name: "uuid", format: "uuid", expected: TokenPolicy{Format: TokenFormatUUID, Bytes: 16}},
+		{name: "uuid of 16 bytes", format: "uuid", bytes: 16, expected: TokenPolicy{Format: TokenFormatUUID, Bytes: 16}},
+		{name: "uuid of another length", format: "uuid", bytes: 12, wantErr: true},
+		{name: "too short", format: "hex", bytes: MinTokenBytes - 1, wantErr: true},
+		{name: "too long", bytes: MaxTokenBytes + 1, wantErr: true},
+		{name: "unknown format", format: "base58", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParseTokenPolicy(tt.format, tt.bytes, "", 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v but got %v", tt.wantErr, err)
+			}
+			if policy != tt.expected {
+				t.Errorf("Expected %+v but got %+v", tt.expected, policy)
+			}
+		})
+	}
+}
+
+func TestTokenPolicy_EncodeAndValid(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    TokenPolicy
+		expected  string
+		malformed []string
+	}{
+		{
+			name:      "zero value",
+			policy:    TokenPolicy{},
+			expected:  "AAECAwQFBgcI",
+			malformed: []string{"AAECAwQFBgc", "AAECAwQFBgc+", "AAECAwQFBgcI0"},
+		},
+		{
+			name:      "hex",
+			policy:    TokenPolicy{Format: TokenFormatHex, Bytes: 9},
+			expected:  "000102030405060708",
+			malformed: []string{"00010203040506070", "00010203040506070G", "000102030405060708AB"},
+		},
+		{
+			name:      "base32",
+			policy:    TokenPolicy{Format: TokenFormatBase32, Bytes: 9},
+			expected:  "000G40R40M30E20",
+			malformed: []string{"000G40R40M30E2", "000G40R40M30E2U", "000g40r40m30e20"},
+		},
+		{
+			name:      "uuid",
+			policy:    TokenPolicy{Format: TokenFormatUUID},
+			expected:  "00010203-0405-4607-8809-0a0b0c0d0e0f",
+			malformed: []string{"00010203-0405-1607-8809-0a0b0c0d0e0f", "00010203-0405-4607-c809-0a0b0c0d0e0f", "000102030405460788090a0b0c0d0e0f", "00010203-0405-4607-8809-0A0B0C0D0E0F"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random := make([]byte, tt.policy.RandomBytes())
+			for i := range random {
+				random[i] = byte(i)
+			}
+			token := tt.policy.Encode(bytes.Clone(random), time.Now())
+			if token != tt.expected {
+				t.Errorf("Expected %q but got %q", tt.expected, token)
+			}
+			if !tt.policy.Valid(token) {
+				t.Errorf("Expected %q to be valid", token)
+			}
+			for _, malformed := range tt.malformed {
+				if tt.policy.Valid(malformed) {
+					t.Errorf("Expected %q to be malformed", malformed)
+				}
+			}
+			if tt.policy.Valid(strings.Repeat("x", 200)) {
+				t.Error("Expected an overlong token to be malformed")
+			}
+		})
+	}
+}
+
+func TestTokenPolicy_Signed(t *testing.T) {
+	secret := strings.Repeat("s", MinTokenSecretLength)
+	for _, format := range []string{"base64url", "hex", "base32", "uuid"} {
+		t.Run(format, func(t *testing.T) {
+			policy, err := ParseTokenPolicy(format, 0, secret, time.Hour)
+			if err != nil {
+				t.Fatalf("ParseTokenPolicy failed: %v", err)
+			}
+			now := time.Now()
+			token := policy.Encode(make([]byte, policy.RandomBytes()), now)
+
+			if !policy.Valid(token) {
+				t.Fatalf("Expected %q to be valid", token)
+			}
+			if expiresAt := policy.ExpiresAt(token); !expiresAt.Equal(now.Add(time.Hour).Truncate(time.Second)) {
+				t.Errorf("Expected the token to carry its expiry, got %v", expiresAt)
+			}
+
+			random, signature, _ := strings.Cut(token, ".")
+			if policy.Valid(random) {
+				t.Error("Expected a token without its signature to be refused")
+			}
+			// Changing any character of the signed part breaks the signature
+			forged := []byte(signature)
+			if forged[0] == '0' {
+				forged[0] = '1'
+			} else {
+				forged[0] = '0'
+			}
+			if policy.Valid(random + "." + string(forged)) {
+				t.Error("Expected a token with a changed expiry to be refused")
+			}
+
+			other, _ := ParseTokenPolicy(format, 0, strings.Repeat("t", MinTokenSecretLength), time.Hour)
+			if other.Valid(token) {
+				t.Error("Expected a token signed with another secret to be refused")
+			}
+		})
+	}
+}
+
+func TestParseTokenPolicy_Signed(t *testing.T) {
+	policy, err := ParseTokenPolicy("", 0, strings.Repeat("s", MinTokenSecretLength), 0)
+	if err != nil || policy.Lifetime != DefaultTokenLifetime || !policy.Signed() {
+		t.Errorf("Expected signed tokens with the default lifetime, got %+v (%v)", policy, err)
+	}
+	if _, err := ParseTokenPolicy("", 0, "short", time.Hour); err == nil {
+		t.Error("Expected an error for a short secret")
+	}
+	if _, err := ParseTokenPolicy("", 0, strings.Repeat("s", MinTokenSecretLength), time.Second); err == nil {
+		t.Error("Expected an error for a lifetime under a minute")
+	}
+	if unsigned, _ := ParseTokenPolicy("", 0, "", 0); unsigned.Signed() || !unsigned.Deadline(time.Now()).IsZero() {
+		t.Errorf("Expected unsigned tokens without a deadline, got %+v", unsigned)
+	}
+}
+
+func TestShortToken(t *testing.T) {
+	tests := map[string]string{
+		"abcdefghijkl": "abcdefgh...",
+		"abcdefgh":     "abcdefgh...",
+		"abc":          "abc...",
+		"":             "...",
+	}
+	for token, expected := range tests {
+		if got := ShortToken(token); got != expected {
+			t.Errorf("ShortToken(%q) = %q, expected %q", token, got, expected)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// PairingCodeTTL is how long the code a viewer device shows waits for
+	// the sender to confirm it
+	PairingCodeTTL = 5 * time.Minute
+
+	// MaxDeviceNameLength caps the name a viewer device pairs under
+	MaxDeviceNameLength = 64
+)
+
+// TrustedDevice is a viewer device a sender paired with; it joins that
+// sender's sessions without their PIN until the sender forgets it
+type TrustedDevice struct {
+	// ID names the pairing to the sender; the device ID stays secret, since
+	// it is what the viewer's device cookie holds
+	ID         string    `json:"id"`
+	Owner      string    `json:"owner"`
+	DeviceID   string    `json:"deviceId"`
+	Name       string    `json:"name"`
+	PairedAt   time.Time `json:"pairedAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// PairingRequest is a viewer device waiting for the sender of a session to
+// confirm the code it shows
+type PairingRequest struct {
+	Code      string
+	Token     string
+	DeviceID  string
+	Name      string
+	ExpiresAt time.Time
+}
+
+// IsExpired reports whether the sender may no longer confirm the request
+func (p *PairingRequest) IsExpired(now time.Time) bool {
+	return !now.Before(p.ExpiresAt)
+}
+
+// ValidDeviceName reports whether name may label a trusted device
+func ValidDeviceName(name string) bool {
+	return utf8.ValidString(name) && utf8.RuneCountInString(name) <= MaxDeviceNameLength
+}
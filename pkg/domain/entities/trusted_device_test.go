@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPairingRequest_IsExpired(t *testing.T) {
+	now := time.Now()
+	request := &PairingRequest{Code: "123456", ExpiresAt: now.Add(PairingCodeTTL)}
+
+	if request.IsExpired(now) {
+		t.Error("Expected a new pairing request to be open")
+	}
+	if !request.IsExpired(now.Add(PairingCodeTTL)) {
+		t.Error("Expected the pairing request to expire after its TTL")
+	}
+}
+
+func TestValidDeviceName(t *testing.T) {
+	tests := []struct {
+		name     string
+		device   string
+		expected bool
+	}{
+		{name: "empty", device: "", expected: true},
+		{name: "short", device: "Kitchen tablet", expected: true},
+		{name: "at the limit in runes", device: strings.Repeat("é", MaxDeviceNameLength), expected: true},
+		{name: "too long", device: strings.Repeat("a", MaxDeviceNameLength+1), expected: false},
+		{name: "invalid UTF-8", device: "\xff", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidDeviceName(tt.device); got != tt.expected {
+				t.Errorf("ValidDeviceName(%q) = %v, want %v", tt.device, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Viewer aliases are made of a word from each list and a two-digit number,
+// e.g. BlueTiger42: easy to say over the phone and to type on a TV remote.
+// The words are short, common and unlike each other when spoken.
+var (
+	aliasAdjectives = []string{
+		"Amber", "Blue", "Brave", "Bright", "Calm", "Clever", "Cool", "Coral",
+		"Crimson", "Daring", "Eager", "Fancy", "Fast", "Gentle", "Golden", "Grand",
+		"Green", "Happy", "Honest", "Jolly", "Kind", "Lucky", "Lively", "Mellow",
+		"Merry", "Mighty", "Misty", "Noble", "Olive", "Orange", "Proud", "Purple",
+		"Quick", "Quiet", "Rapid", "Red", "Royal", "Ruby", "Silver", "Smart",
+		"Snowy", "Sunny", "Swift", "Teal", "Tidy", "Velvet", "Violet", "Witty",
+	}
+	aliasAnimals = []string{
+		"Badger", "Bear", "Beaver", "Bison", "Camel", "Cat", "Cheetah", "Cobra",
+		"Condor", "Cougar", "Crane", "Deer", "Dingo", "Dolphin", "Dove", "Eagle",
+		"Falcon", "Ferret", "Finch", "Fox", "Gecko", "Goose", "Hawk", "Heron",
+		"Horse", "Husky", "Ibis", "Jaguar", "Kiwi", "Koala", "Lemur", "Lion",
+		"Llama", "Lynx", "Mole", "Moose", "Newt", "Orca", "Otter", "Owl",
+		"Panda", "Parrot", "Pelican", "Penguin", "Puma", "Quail", "Rabbit", "Raven",
+		"Robin", "Salmon", "Seal", "Shark", "Sparrow", "Swan", "Tiger", "Toucan",
+		"Trout", "Turtle", "Walrus", "Whale", "Wolf", "Wombat", "Yak", "Zebra",
+	}
+)
+
+const (
+	// aliasNumbers are the two-digit numbers ending an alias, 10 to 99
+	aliasNumbers = 90
+
+	// maxViewerAliasLength bounds what is worth looking up as an alias
+	maxViewerAliasLength = 24
+)
+
+// ViewerAliasCount is the number of distinct viewer aliases
+var ViewerAliasCount = int64(len(aliasAdjectives) * len(aliasAnimals) * aliasNumbers)
+
+// ViewerAliasAt returns the viewer alias numbered n, from 0 to
+// ViewerAliasCount-1, so a random n picks a random alias
+func ViewerAliasAt(n int64) string {
+	number := n % aliasNumbers
+	n /= aliasNumbers
+	animal := n % int64(len(aliasAnimals))
+	n /= int64(len(aliasAnimals))
+	adjective := n % int64(len(aliasAdjectives))
+	return aliasAdjectives[adjective] + aliasAnimals[animal] + strconv.FormatInt(number+10, 10)
+}
+
+// IsViewerAlias reports whether s has the form of a viewer alias: letters
+// followed by two digits. Case does not matter, since aliases are read out.
+func IsViewerAlias(s string) bool {
+	if len(s) < 4 || len(s) > maxViewerAliasLength {
+		return false
+	}
+	letters, digits := s[:len(s)-2], s[len(s)-2:]
+	return onlyChars(strings.ToLower(letters), "abcdefghijklmnopqrstuvwxyz") && onlyChars(digits, "0123456789")
+}
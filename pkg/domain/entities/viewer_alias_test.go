@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestViewerAliasAt(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, words := range [][]string{aliasAdjectives, aliasAnimals} {
+		for _, word := range words {
+			if seen[strings.ToLower(word)] {
+				t.Errorf("Expected each word once, got %s again", word)
+			}
+			seen[strings.ToLower(word)] = true
+		}
+	}
+
+	first, last := ViewerAliasAt(0), ViewerAliasAt(ViewerAliasCount-1)
+	if first != "AmberBadger10" || last != "WittyZebra99" {
+		t.Errorf("Expected AmberBadger10 to WittyZebra99, got %s to %s", first, last)
+	}
+
+	aliases := make(map[string]bool)
+	for n := int64(0); n < ViewerAliasCount; n += 997 {
+		alias := ViewerAliasAt(n)
+		if !IsViewerAlias(alias) {
+			t.Fatalf("Expected %s to be a viewer alias", alias)
+		}
+		if aliases[alias] {
+			t.Fatalf("Expected distinct aliases, got %s twice", alias)
+		}
+		aliases[alias] = true
+	}
+}
+
+func TestIsViewerAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  bool
+	}{
+		{alias: "BlueTiger42", want: true},
+		{alias: "bluetiger42", want: true},
+		{alias: "BlueTiger4", want: false},
+		{alias: "BlueTiger", want: false},
+		{alias: "Blue-Tiger42", want: false},
+		{alias: "42", want: false},
+		{alias: "ThisIsFarTooLongForAnAlias42", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsViewerAlias(tt.alias); got != tt.want {
+			t.Errorf("IsViewerAlias(%q) = %v, want %v", tt.alias, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"crypto/subtle"
+	"slices"
+	"time"
+)
+
+// MaxViewerLinks bounds the viewer links a session keeps; expired links make
+// room for new ones
+const MaxViewerLinks = 20
+
+// ViewerLink is an extra way for viewers into a session, with an expiry and
+// a number of uses of its own, so a sender can hand out a link for a few
+// minutes while the session itself lasts for hours. Opening the link's page
+// redeems it for the session token.
+type ViewerLink struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// MaxRedemptions is how many viewers may open the link; 0 for any number
+	MaxRedemptions int `json:"maxRedemptions,omitempty"`
+
+	// Redeemers are the viewers that opened the link, in order
+	Redeemers []string `json:"redeemers,omitempty"`
+}
+
+// IsExpired checks if the link can no longer be redeemed at now
+func (l *ViewerLink) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// IsUsedUp checks if the link has been redeemed as often as it may be
+func (l *ViewerLink) IsUsedUp() bool {
+	return l.MaxRedemptions > 0 && len(l.Redeemers) >= l.MaxRedemptions
+}
+
+// Redeem records that viewerID opened the link; a viewer opening it again
+// takes no further use
+func (l *ViewerLink) Redeem(viewerID string) {
+	if !slices.Contains(l.Redeemers, viewerID) {
+		l.Redeemers = append(l.Redeemers, viewerID)
+	}
+}
+
+// HasRedeemed reports whether viewerID has already opened the link
+func (l *ViewerLink) HasRedeemed(viewerID string) bool {
+	return slices.Contains(l.Redeemers, viewerID)
+}
+
+// FindLink returns the session's viewer link with the given ID, or nil
+func (s *Session) FindLink(id string) *ViewerLink {
+	for i := range s.Links {
+		if subtle.ConstantTimeCompare([]byte(s.Links[i].ID), []byte(id)) == 1 {
+			return &s.Links[i]
+		}
+	}
+	return nil
+}
+
+// AddLink adds a viewer link to the session, first dropping the links that
+// have expired; it reports false when the session has no room for another
+func (s *Session) AddLink(link ViewerLink) bool {
+	s.Links = slices.DeleteFunc(s.Links, func(l ViewerLink) bool {
+		return l.IsExpired(link.CreatedAt)
+	})
+	if len(s.Links) >= MaxViewerLinks {
+		return false
+	}
+	s.Links = append(s.Links, link)
+	return true
+}
+
+// RemoveLink removes the viewer link with the given ID, reporting whether
+// the session had it
+func (s *Session) RemoveLink(id string) bool {
+	link := s.FindLink(id)
+	if link == nil {
+		return false
+	}
+	linkID := link.ID
+	s.Links = slices.DeleteFunc(s.Links, func(l ViewerLink) bool {
+		return l.ID == linkID
+	})
+	return true
+}
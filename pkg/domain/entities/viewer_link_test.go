@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestViewerLink_Redeem(t *testing.T) {
+	now := time.Now()
+	link := ViewerLink{ID: "link", CreatedAt: now, ExpiresAt: now.Add(5 * time.Minute), MaxRedemptions: 2}
+
+	link.Redeem("viewer-1")
+	link.Redeem("viewer-1")
+	if link.IsUsedUp() || len(link.Redeemers) != 1 {
+		t.Fatalf("Expected a viewer opening the link again to take no use, got %v", link.Redeemers)
+	}
+	link.Redeem("viewer-2")
+	if !link.IsUsedUp() || !link.HasRedeemed("viewer-2") {
+		t.Errorf("Expected the link used up by its second viewer, got %v", link.Redeemers)
+	}
+
+	if link.IsExpired(now) || !link.IsExpired(now.Add(5*time.Minute)) {
+		t.Error("Expected the link to expire at its expiry")
+	}
+
+	unlimited := ViewerLink{Redeemers: []string{"viewer-1", "viewer-2", "viewer-3"}}
+	if unlimited.IsUsedUp() {
+		t.Error("Expected a link without a maximum never to be used up")
+	}
+}
+
+func TestSession_Links(t *testing.T) {
+	now := time.Now()
+	session := &Session{Token: "token"}
+	session.Links = []ViewerLink{{ID: "expired", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}}
+
+	for i := 0; i < MaxViewerLinks; i++ {
+		if !session.AddLink(ViewerLink{ID: fmt.Sprintf("link-%d", i), CreatedAt: now, ExpiresAt: now.Add(time.Minute)}) {
+			t.Fatalf("Expected link %d to be added", i)
+		}
+	}
+	if session.FindLink("expired") != nil {
+		t.Error("Expected the expired link dropped to make room")
+	}
+	if session.AddLink(ViewerLink{ID: "one-too-many", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}) {
+		t.Error("Expected no room for more than MaxViewerLinks unexpired links")
+	}
+
+	clone := session.Clone()
+	session.FindLink("link-0").Redeem("viewer-1")
+	if clone.FindLink("link-0").HasRedeemed("viewer-1") {
+		t.Error("Expected the clone's links to be independent")
+	}
+
+	if !session.RemoveLink("link-3") || session.FindLink("link-3") != nil || len(session.Links) != MaxViewerLinks-1 {
+		t.Errorf("Expected only link-3 removed, got %d links", len(session.Links))
+	}
+	if session.FindLink("link-4") == nil {
+		t.Error("Expected the links after the removed one kept")
+	}
+	if session.RemoveLink("missing") {
+		t.Error("Expected removing an unknown link to report false")
+	}
+}
@@ -1,5 +1,10 @@
 package entities
 
+import (
+	"fmt"
+	"strings"
+)
+
 // WebRTCOffer represents a WebRTC offer
 type WebRTCOffer struct {
 	Type string `json:"type"`
@@ -21,3 +26,118 @@ func (o *WebRTCOffer) IsValid() bool {
 func (a *WebRTCAnswer) IsValid() bool {
 	return a != nil && a.Type != "" && a.SDP != ""
 }
+
+// WithBandwidth returns the offer with its video capped at kbps; see
+// CapVideoBitrate
+func (o *WebRTCOffer) WithBandwidth(kbps int) *WebRTCOffer {
+	if o == nil || kbps <= 0 {
+		return o
+	}
+	return &WebRTCOffer{Type: o.Type, SDP: CapVideoBitrate(o.SDP, kbps)}
+}
+
+// WithBandwidth returns the answer with its video capped at kbps; see
+// CapVideoBitrate
+func (a *WebRTCAnswer) WithBandwidth(kbps int) *WebRTCAnswer {
+	if a == nil || kbps <= 0 {
+		return a
+	}
+	return &WebRTCAnswer{Type: a.Type, SDP: CapVideoBitrate(a.SDP, kbps)}
+}
+
+// WithFrameRate returns the offer with its video capped at fps; see
+// CapVideoFrameRate
+func (o *WebRTCOffer) WithFrameRate(fps int) *WebRTCOffer {
+	if o == nil || fps <= 0 {
+		return o
+	}
+	return &WebRTCOffer{Type: o.Type, SDP: CapVideoFrameRate(o.SDP, fps)}
+}
+
+// WithFrameRate returns the answer with its video capped at fps; see
+// CapVideoFrameRate
+func (a *WebRTCAnswer) WithFrameRate(fps int) *WebRTCAnswer {
+	if a == nil || fps <= 0 {
+		return a
+	}
+	return &WebRTCAnswer{Type: a.Type, SDP: CapVideoFrameRate(a.SDP, fps)}
+}
+
+// CapVideoBitrate caps every video section of sdp at kbps, replacing any cap it
+// had. Browsers apply the cap of the remote description to what they send:
+// b=AS in kilobits per second for Chrome and Safari, b=TIAS in bits per second
+// for Firefox. A kbps of 0 returns sdp unchanged.
+func CapVideoBitrate(sdp string, kbps int) string {
+	if kbps <= 0 {
+		return sdp
+	}
+
+	eol := "\n"
+	if strings.Contains(sdp, "\r\n") {
+		eol = "\r\n"
+	}
+	limits := []string{fmt.Sprintf("b=AS:%d", kbps), fmt.Sprintf("b=TIAS:%d", kbps*1000)}
+
+	var lines []string
+	video, pending := false, false
+	for _, line := range strings.Split(strings.TrimSuffix(sdp, eol), eol) {
+		if strings.HasPrefix(line, "m=") {
+			if pending {
+				lines = append(lines, limits...)
+			}
+			video = strings.HasPrefix(line, "m=video ")
+			pending = video
+			lines = append(lines, line)
+			continue
+		}
+		if video && (strings.HasPrefix(line, "b=AS:") || strings.HasPrefix(line, "b=TIAS:")) {
+			continue
+		}
+		// Bandwidth lines follow the section's title and connection lines
+		if pending && !strings.HasPrefix(line, "i=") && !strings.HasPrefix(line, "c=") {
+			lines = append(lines, limits...)
+			pending = false
+		}
+		lines = append(lines, line)
+	}
+	if pending {
+		lines = append(lines, limits...)
+	}
+	return strings.Join(lines, eol) + eol
+}
+
+// CapVideoFrameRate caps every video section of sdp at fps frames per second
+// with an a=framerate attribute, replacing any it had. The attribute tells the
+// peer the most frames the sender will send; the sender page caps its capture
+// to match, as browsers do not all apply it on their own. A fps of 0 returns
+// sdp unchanged.
+func CapVideoFrameRate(sdp string, fps int) string {
+	if fps <= 0 {
+		return sdp
+	}
+
+	eol := "\n"
+	if strings.Contains(sdp, "\r\n") {
+		eol = "\r\n"
+	}
+	limit := fmt.Sprintf("a=framerate:%d", fps)
+
+	var lines []string
+	video := false
+	for _, line := range strings.Split(strings.TrimSuffix(sdp, eol), eol) {
+		if strings.HasPrefix(line, "m=") {
+			// Attributes end the section before it
+			if video {
+				lines = append(lines, limit)
+			}
+			video = strings.HasPrefix(line, "m=video ")
+		} else if video && strings.HasPrefix(line, "a=framerate:") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if video {
+		lines = append(lines, limit)
+	}
+	return strings.Join(lines, eol) + eol
+}
@@ -1,6 +1,9 @@
 package entities
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestWebRTCOffer_IsValid(t *testing.T) {
 	tests := []struct {
@@ -111,3 +114,121 @@ func TestWebRTCAnswer_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestCapVideoBitrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdp      string
+		kbps     int
+		expected string
+	}{
+		{
+			name:     "no cap",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\n",
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\n",
+		},
+		{
+			name:     "cap after the connection line",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\n",
+			kbps:     1500,
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\nb=AS:1500\r\nb=TIAS:1500000\r\na=mid:0\r\n",
+		},
+		{
+			name:     "existing cap replaced",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\nb=AS:8000\r\na=mid:0\r\n",
+			kbps:     500,
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\nb=AS:500\r\nb=TIAS:500000\r\na=mid:0\r\n",
+		},
+		{
+			name:     "only video sections",
+			sdp:      "v=0\nm=audio 9 UDP/TLS/RTP/SAVPF 111\nc=IN IP4 0.0.0.0\nb=AS:64\nm=video 9 UDP/TLS/RTP/SAVPF 96\nc=IN IP4 0.0.0.0\nm=application 9 UDP/DTLS/SCTP webrtc-datachannel\n",
+			kbps:     1000,
+			expected: "v=0\nm=audio 9 UDP/TLS/RTP/SAVPF 111\nc=IN IP4 0.0.0.0\nb=AS:64\nm=video 9 UDP/TLS/RTP/SAVPF 96\nc=IN IP4 0.0.0.0\nb=AS:1000\nb=TIAS:1000000\nm=application 9 UDP/DTLS/SCTP webrtc-datachannel\n",
+		},
+		{
+			name:     "video section ends the description",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\n",
+			kbps:     250,
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nb=AS:250\r\nb=TIAS:250000\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CapVideoBitrate(tt.sdp, tt.kbps); result != tt.expected {
+				t.Errorf("CapVideoBitrate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWebRTCAnswer_WithBandwidth(t *testing.T) {
+	answer := &WebRTCAnswer{Type: "answer", SDP: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"}
+
+	if answer.WithBandwidth(0) != answer {
+		t.Error("Expected the answer itself without a cap")
+	}
+	capped := answer.WithBandwidth(800)
+	if capped == answer || !strings.Contains(capped.SDP, "b=AS:800\r\n") || capped.Type != "answer" {
+		t.Errorf("Expected a capped copy, got %+v", capped)
+	}
+	if strings.Contains(answer.SDP, "b=AS") {
+		t.Error("Expected the original answer to be left untouched")
+	}
+}
+
+func TestCapVideoFrameRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdp      string
+		fps      int
+		expected string
+	}{
+		{
+			name:     "no cap",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
+		},
+		{
+			name:     "cap ends the section",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\n",
+			fps:      10,
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\na=framerate:10\r\n",
+		},
+		{
+			name:     "existing cap replaced",
+			sdp:      "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=framerate:60\r\na=mid:0\r\n",
+			fps:      5,
+			expected: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\na=framerate:5\r\n",
+		},
+		{
+			name:     "only video sections",
+			sdp:      "v=0\nm=audio 9 UDP/TLS/RTP/SAVPF 111\na=mid:0\nm=video 9 UDP/TLS/RTP/SAVPF 96\na=mid:1\nm=application 9 UDP/DTLS/SCTP webrtc-datachannel\na=mid:2\n",
+			fps:      15,
+			expected: "v=0\nm=audio 9 UDP/TLS/RTP/SAVPF 111\na=mid:0\nm=video 9 UDP/TLS/RTP/SAVPF 96\na=mid:1\na=framerate:15\nm=application 9 UDP/DTLS/SCTP webrtc-datachannel\na=mid:2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := CapVideoFrameRate(tt.sdp, tt.fps); result != tt.expected {
+				t.Errorf("CapVideoFrameRate() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWebRTCOffer_WithFrameRate(t *testing.T) {
+	offer := &WebRTCOffer{Type: "offer", SDP: "v=0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"}
+
+	if offer.WithFrameRate(0) != offer {
+		t.Error("Expected the offer itself without a cap")
+	}
+	capped := offer.WithFrameRate(12)
+	if capped == offer || !strings.Contains(capped.SDP, "a=framerate:12\r\n") || capped.Type != "offer" {
+		t.Errorf("Expected a capped copy, got %+v", capped)
+	}
+	if strings.Contains(offer.SDP, "a=framerate") {
+		t.Error("Expected the original offer to be left untouched")
+	}
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// AuditLogRepository defines the contract for the append-only audit logs of
+// sessions; events are never changed once appended
+type AuditLogRepository interface {
+	// AppendEvent adds an event to the log of its session
+	AppendEvent(event *entities.AuditEvent) error
+
+	// ListEvents returns the log of a session, oldest first
+	ListEvents(token string) ([]entities.AuditEvent, error)
+}
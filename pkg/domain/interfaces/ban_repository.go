@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// BanRepository defines the contract for the ban list storage
+type BanRepository interface {
+	// ListBans returns every ban, oldest first
+	ListBans() ([]*entities.Ban, error)
+
+	// SaveBan stores a ban, replacing any ban of the same network
+	SaveBan(ban *entities.Ban) error
+
+	// DeleteBan removes the ban of a network
+	DeleteBan(network string) error
+}
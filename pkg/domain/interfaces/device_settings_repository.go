@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// DeviceSettingsRepository defines the contract for per-device viewer settings storage
+type DeviceSettingsRepository interface {
+	// GetSettings retrieves the settings for a device
+	GetSettings(deviceID string) (*entities.DeviceSettings, error)
+
+	// SaveSettings stores or replaces the settings for a device
+	SaveSettings(settings *entities.DeviceSettings) error
+
+	// ListSettings returns copies of the settings of every device
+	ListSettings() ([]*entities.DeviceSettings, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import "share-screen/pkg/domain/entities"
+
+// EventPublisher defines the contract for delivering realtime events
+type EventPublisher interface {
+	// Publish sends an event to all subscribers of a topic
+	Publish(topic string, event entities.Event)
+}
+
+// EventSubscriber defines the contract for receiving realtime events
+type EventSubscriber interface {
+	// Subscribe registers for events on the given topics; the channel is closed
+	// when the subscription ends and cancel must be called when done
+	Subscribe(topics ...string) (events <-chan entities.Event, cancel func())
+}
+
+// EventMetricsProvider defines the contract for event delivery metrics
+type EventMetricsProvider interface {
+	// Metrics reports delivery counters since the server started
+	Metrics() entities.EventMetrics
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// FileRepository defines the contract for storing files relayed to viewers
+type FileRepository interface {
+	// SaveFile stores a file and its content for the session with token
+	SaveFile(token string, file *entities.SharedFile, data []byte) error
+
+	// GetFile retrieves a file of a session and its content
+	GetFile(token, id string) (*entities.SharedFile, []byte, error)
+
+	// ListFiles returns the files of a session, oldest first
+	ListFiles(token string) ([]*entities.SharedFile, error)
+
+	// DeleteFiles removes every file of a session
+	DeleteFiles(token string) error
+
+	// GetTokens returns the tokens of the sessions holding files
+	GetTokens() ([]string, error)
+}
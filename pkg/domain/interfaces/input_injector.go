@@ -0,0 +1,10 @@
+package interfaces
+
+import "share-screen/pkg/control"
+
+// InputInjector defines the contract for replaying a viewer's remote-control
+// input on the sender's machine
+type InputInjector interface {
+	// Inject replays one validated event
+	Inject(event control.Event) error
+}
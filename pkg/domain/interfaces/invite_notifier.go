@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// InviteNotifier defines the contract for posting session invites to a team
+// chat channel through its incoming webhook
+type InviteNotifier interface {
+	// Service names the chat service, e.g. Slack, for senders and logs
+	Service() string
+
+	// PostInvite posts the invite to the channel
+	PostInvite(invite *entities.Invite) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+// LeaderElector defines the contract for choosing the one instance of a
+// cluster that runs the jobs on shared state, such as the removal of
+// expired sessions
+type LeaderElector interface {
+	// IsLeader reports whether this instance leads the cluster, taking the
+	// lead when no instance holds it
+	IsLeader() bool
+
+	// Close gives up the lead, so another instance takes over right away
+	Close() error
+}
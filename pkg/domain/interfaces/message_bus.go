@@ -0,0 +1,17 @@
+package interfaces
+
+// MessageBus defines the contract for exchanging messages with devices over
+// a publish/subscribe broker, such as an MQTT broker
+type MessageBus interface {
+	// Publish sends a payload to a topic; a retained payload is kept by the
+	// broker for devices that subscribe later, and an empty retained payload
+	// clears it
+	Publish(topic string, payload []byte, retained bool) error
+
+	// Subscribe calls handler with the messages of the topics matching filter
+	// until the bus is closed, across reconnections to the broker
+	Subscribe(filter string, handler func(topic string, payload []byte)) error
+
+	// Close disconnects from the broker
+	Close() error
+}
@@ -1,7 +1,22 @@
 package interfaces
 
+import (
+	"share-screen/pkg/domain/entities"
+)
+
 // NetworkService defines the contract for network-related operations
 type NetworkService interface {
 	// GetLANIP returns the local area network IP address
 	GetLANIP() string
+
+	// GetLANIPs returns every address other devices may reach the server on,
+	// private IPv4 first, then IPv6 unique local and global addresses
+	GetLANIPs() []string
+
+	// GetInterfaces returns the interfaces that are up with usable addresses
+	GetInterfaces() []entities.NetworkInterface
+
+	// InterfaceName returns the interface addresses are taken from, empty
+	// when any interface may be used
+	InterfaceName() string
 }
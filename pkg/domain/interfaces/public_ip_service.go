@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// PublicIPService defines the contract for discovering the address the
+// server is reached on from the internet
+type PublicIPService interface {
+	// PublicIP returns the last discovered public IP address, empty until
+	// one has been found
+	PublicIP() string
+
+	// Refresh discovers the public IP address again
+	Refresh(ctx context.Context) error
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+// QRCodeService defines the contract for rendering QR codes
+type QRCodeService interface {
+	// PNG encodes content as a square QR code image of the given pixel size
+	PNG(content string, size int) ([]byte, error)
+
+	// Text renders content as a QR code of block characters for terminals
+	Text(content string) (string, error)
+}
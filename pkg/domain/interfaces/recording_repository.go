@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"io"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// RecordingRepository defines the contract for the recordings of sessions
+// streamed through the server
+type RecordingRepository interface {
+	// ListRecordings returns every finished recording, oldest first
+	ListRecordings() ([]*entities.Recording, error)
+
+	// OpenRecording returns a recording and its content, which the caller
+	// must close
+	OpenRecording(name string) (*entities.Recording, io.ReadSeekCloser, error)
+
+	// DeleteRecording removes a recording
+	DeleteRecording(name string) error
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// RoomRepository defines the contract for named room storage
+type RoomRepository interface {
+	// GetRoom retrieves a room by its normalized name
+	GetRoom(name string) (*entities.Room, error)
+
+	// SaveRoom stores or replaces a room
+	SaveRoom(room *entities.Room) error
+}
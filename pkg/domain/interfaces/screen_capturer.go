@@ -0,0 +1,19 @@
+package interfaces
+
+import "context"
+
+// ScreenCapturer defines the contract for capturing the screen without a browser
+type ScreenCapturer interface {
+	// Start captures the screen at frameRate as VP8 frames until ctx is done
+	// or the stream is closed
+	Start(ctx context.Context, frameRate int) (VideoStream, error)
+}
+
+// VideoStream is a running screen capture
+type VideoStream interface {
+	// NextFrame returns the next encoded frame, or an error once capture stops
+	NextFrame() ([]byte, error)
+
+	// Close stops the capture
+	Close() error
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SenderProfileRepository defines the contract for the storage of the
+// defaults senders keep on the server
+type SenderProfileRepository interface {
+	// GetProfile returns the profile with the given ID
+	GetProfile(id string) (*entities.SenderProfile, error)
+
+	// SaveProfile stores a profile, replacing any profile with the same ID
+	SaveProfile(profile *entities.SenderProfile) error
+
+	// DeleteProfile removes the profile with the given ID
+	DeleteProfile(id string) error
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SessionHistoryRepository defines the contract for storing finished sessions
+type SessionHistoryRepository interface {
+	// SaveRecord stores or replaces the record for a session
+	SaveRecord(record *entities.SessionRecord) error
+
+	// GetRecord retrieves the record for a session by token
+	GetRecord(token string) (*entities.SessionRecord, error)
+
+	// ListRecords returns copies of all stored records, oldest first
+	ListRecords() ([]*entities.SessionRecord, error)
+}
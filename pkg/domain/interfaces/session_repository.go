@@ -25,4 +25,11 @@ type SessionRepository interface {
 
 	// GetActiveSessionsCount returns the number of active sessions
 	GetActiveSessionsCount() (int, error)
+
+	// ListSessions returns copies of all stored sessions
+	ListSessions() ([]*entities.Session, error)
+
+	// RestoreSession stores a session under its own token, replacing any
+	// session with that token; used to load a snapshot
+	RestoreSession(session *entities.Session) error
 }
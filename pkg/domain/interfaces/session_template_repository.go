@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SessionTemplateRepository defines the contract for the storage of the
+// session templates admins define
+type SessionTemplateRepository interface {
+	// ListTemplates returns every template, by name
+	ListTemplates() ([]*entities.SessionTemplate, error)
+
+	// GetTemplate returns the template with the given name
+	GetTemplate(name string) (*entities.SessionTemplate, error)
+
+	// SaveTemplate stores a template, replacing any template of the same name
+	SaveTemplate(template *entities.SessionTemplate) error
+
+	// DeleteTemplate removes the template with the given name
+	DeleteTemplate(name string) error
+}
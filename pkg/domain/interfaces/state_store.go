@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// StateStore defines the contract for a storage backend that can hand over
+// its whole state at once, used to migrate between backends
+type StateStore interface {
+	// Load reads the stored state; a backend holding nothing yet returns an
+	// error wrapping os.ErrNotExist
+	Load() (*entities.Snapshot, error)
+
+	// Save replaces the stored state
+	Save(snapshot *entities.Snapshot) error
+
+	// Location describes where the state is stored, for messages
+	Location() string
+
+	// SessionsOnly reports whether the backend keeps live sessions only,
+	// leaving session history and device settings to snapshot files
+	SessionsOnly() bool
+
+	// Close releases the backend, e.g. its database connections
+	Close() error
+}
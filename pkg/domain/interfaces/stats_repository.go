@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// StatsRepository defines the contract for storing the WebRTC stats time
+// series of sessions
+type StatsRepository interface {
+	// AppendStats adds a sample to a session's series; a repository may drop
+	// the oldest samples of long sessions
+	AppendStats(token string, sample *entities.StatsSample) error
+
+	// ListStats returns a session's samples taken after since, oldest first
+	ListStats(token string, since time.Time) ([]entities.StatsSample, error)
+
+	// SetLatency records a latency probe result of a session, replacing the
+	// earlier one of the same peer and path
+	SetLatency(token string, sample *entities.LatencySample) error
+
+	// ListLatency returns the latest latency probe result of each peer and
+	// path of a session, the sender's first
+	ListLatency(token string) ([]entities.LatencySample, error)
+
+	// DeleteStats removes the series and latency results of a session
+	DeleteStats(token string) error
+
+	// GetTokens returns the tokens of the sessions holding stats or latency
+	// results
+	GetTokens() ([]string, error)
+}
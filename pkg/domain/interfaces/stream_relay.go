@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// StreamRelay defines the contract for a selective forwarding unit (SFU): the
+// sender of a session publishes its stream once to the server, which forwards
+// it to any number of viewers
+type StreamRelay interface {
+	// Publish connects the sender's offer to the relay, replacing any stream
+	// the session published before, and returns the relay's answer
+	Publish(token string, offer *entities.WebRTCOffer) (*entities.WebRTCAnswer, error)
+
+	// IsPublished reports whether a session's video is reaching the relay
+	IsPublished(token string) bool
+
+	// Offer creates the relay's offer to a viewer of a session, replacing any
+	// earlier connection of the same viewer
+	Offer(token, viewerID string) (*entities.WebRTCOffer, error)
+
+	// Answer completes a viewer's connection with its answer to Offer
+	Answer(token, viewerID string, answer *entities.WebRTCAnswer) error
+
+	// SelectLayer sets the simulcast layer a viewer receives, or LayerAuto
+	// to follow its connection
+	SelectLayer(token, viewerID string, layer entities.SimulcastLayer) error
+
+	// WatchFrames streams JPEG snapshots of a session's video until stop is
+	// called; the channel is closed when the stream ends
+	WatchFrames(token string) (frames <-chan []byte, stop func(), err error)
+
+	// Snapshot returns a PNG of the latest keyframe of a session's video
+	Snapshot(token string) ([]byte, error)
+
+	// DisconnectViewer closes a viewer's connection to a session
+	DisconnectViewer(token, viewerID string) error
+
+	// Close disconnects the sender and viewers of a session
+	Close(token string)
+
+	// GetTokens returns the tokens of the sessions on the relay
+	GetTokens() []string
+
+	// OnViewersChanged registers fn to be called with the number of connected
+	// viewers of a session whenever it changes
+	OnViewersChanged(fn func(token string, viewers int))
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "share-screen/pkg/domain/entities"
+
+// TokenVerifier defines the contract for checking API bearer tokens
+type TokenVerifier interface {
+	// Verify checks the token's signature and validity and returns the user
+	// it was issued to
+	Verify(token string) (*entities.UserIdentity, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// TrustedDeviceRepository defines the contract for the storage of the viewer
+// devices senders paired with
+type TrustedDeviceRepository interface {
+	// ListDevices returns the devices a sender trusts, oldest pairing first
+	ListDevices(owner string) ([]*entities.TrustedDevice, error)
+
+	// FindDevice returns the pairing of a viewer device with a sender
+	FindDevice(owner, deviceID string) (*entities.TrustedDevice, error)
+
+	// SaveDevice stores a pairing, replacing any pairing with the same ID
+	SaveDevice(device *entities.TrustedDevice) error
+
+	// DeleteDevice removes the pairing with the given ID from a sender's devices
+	DeleteDevice(owner, id string) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// TunnelProvider defines the contract for tunnels that expose the server to
+// the internet through an outbound connection, without opening ports
+type TunnelProvider interface {
+	// Open establishes the tunnel and returns its public URL; the tunnel
+	// stays up until ctx is cancelled
+	Open(ctx context.Context) (string, error)
+}
@@ -0,0 +1,9 @@
+package interfaces
+
+import "share-screen/pkg/domain/entities"
+
+// TURNCredentialService defines the contract for minting TURN credentials
+type TURNCredentialService interface {
+	// Mint returns a credential for user that the TURN server accepts until it expires
+	Mint(user string) *entities.TURNCredential
+}
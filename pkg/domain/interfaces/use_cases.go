@@ -8,11 +8,15 @@ import (
 // SessionUseCase defines the contract for session-related business logic
 type SessionUseCase interface {
 	// CreateSession creates a new screen sharing session
-	CreateSession() (*dto.CreateSessionResponse, error)
+	CreateSession(request *dto.CreateSessionRequest) (*dto.CreateSessionResponse, error)
 
 	// SubmitOffer submits a WebRTC offer for a session
 	SubmitOffer(request *dto.SubmitOfferRequest) error
 
+	// ResetOffer clears a session's offer and answer and returns it to
+	// pending, so the sender can start capturing again under the same token
+	ResetOffer(request *dto.ResetOfferRequest) error
+
 	// GetOffer retrieves a WebRTC offer for a session
 	GetOffer(request *dto.GetOfferRequest) (*dto.GetOfferResponse, error)
 
@@ -21,10 +25,336 @@ type SessionUseCase interface {
 
 	// GetAnswer retrieves a WebRTC answer for a session
 	GetAnswer(request *dto.GetAnswerRequest) (*dto.GetAnswerResponse, error)
+
+	// GetSession describes where a session stands, including sessions that
+	// ended or expired but are not cleaned up yet
+	GetSession(request *dto.GetSessionRequest) (*dto.SessionDetailResponse, error)
+
+	// PublishStream connects the sender of an SFU session to the server
+	PublishStream(request *dto.PublishStreamRequest) (*dto.PublishStreamResponse, error)
+
+	// SelectLayer sets the simulcast layer a viewer of an SFU session receives
+	SelectLayer(request *dto.SelectLayerRequest) error
+
+	// KickViewer removes a viewer from a session and revokes its viewer ID
+	KickViewer(request *dto.KickViewerRequest) error
+
+	// GetLinkPreview returns the public details used for viewer link previews
+	GetLinkPreview(request *dto.GetLinkPreviewRequest) (*dto.LinkPreviewResponse, error)
+
+	// ResolveViewerAlias returns the token of the session a viewer alias names
+	ResolveViewerAlias(request *dto.ResolveViewerAliasRequest) (*dto.ResolveViewerAliasResponse, error)
+
+	// Heartbeat records that the sender is still present
+	Heartbeat(request *dto.HeartbeatRequest) error
+
+	// ResumeSession reattaches a reloaded sender page to its session
+	ResumeSession(request *dto.ResumeSessionRequest) (*dto.CreateSessionResponse, error)
+
+	// ExtendSession pushes back the expiry of a live session
+	ExtendSession(request *dto.ExtendSessionRequest) (*dto.ExtendSessionResponse, error)
+
+	// PauseSession pauses or resumes the sender's stream for the viewers
+	PauseSession(request *dto.PauseSessionRequest) error
+
+	// ListOwnSessions lists the live sessions a sender created
+	ListOwnSessions(request *dto.ListOwnSessionsRequest) (*dto.OwnSessionsResponse, error)
+
+	// RenameSession renames a live session for the sender that created it
+	RenameSession(request *dto.RenameSessionRequest) error
+
+	// EndSession ends a session at the sender's request
+	EndSession(request *dto.EndSessionRequest) error
+}
+
+// ViewerQueueUseCase defines the contract for queueing viewers on a full session
+type ViewerQueueUseCase interface {
+	// JoinQueue places a viewer in the queue, or reserves the slot if it is free
+	JoinQueue(request *dto.JoinQueueRequest) (*dto.JoinQueueResponse, error)
+
+	// LeaveQueue removes a viewer from the queue
+	LeaveQueue(request *dto.QueueViewerRequest) error
+
+	// PromoteViewer moves a viewer to the front of the queue
+	PromoteViewer(request *dto.QueueViewerRequest) error
+
+	// GetQueue lists the viewers waiting on a session
+	GetQueue(request *dto.GetQueueRequest) (*dto.GetQueueResponse, error)
+
+	// ReleaseViewer frees the slot held by the connected viewer
+	ReleaseViewer(request *dto.ReleaseViewerRequest) error
 }
 
 // ServerInfoUseCase defines the contract for server information
 type ServerInfoUseCase interface {
 	// GetServerInfo returns server information including network details
 	GetServerInfo(host string) (*entities.ServerInfo, error)
+
+	// GetHealth returns whether the server is healthy
+	GetHealth() *dto.HealthResponse
+
+	// GetInterfaces lists the network interfaces links may use
+	GetInterfaces() *dto.InterfacesResponse
+}
+
+// ICEConfigUseCase defines the contract for the ICE servers given to peers
+type ICEConfigUseCase interface {
+	// GetICEConfig returns the STUN and TURN servers for the peers of a session
+	GetICEConfig(request *dto.GetICEConfigRequest) (*dto.ICEConfigResponse, error)
+
+	// GetTURNCredentials mints a short-lived credential for the session's TURN servers
+	GetTURNCredentials(request *dto.GetICEConfigRequest) (*dto.TURNCredentialsResponse, error)
+}
+
+// CapabilitiesUseCase defines the contract for the optional subsystems a
+// deployment supports
+type CapabilitiesUseCase interface {
+	// GetCapabilities returns which optional subsystems work on this deployment
+	GetCapabilities() *entities.Capabilities
+}
+
+// PresetUseCase defines the contract for the quality presets senders pick from
+type PresetUseCase interface {
+	// ListPresets returns the quality presets the server offers
+	ListPresets() *dto.PresetsResponse
+}
+
+// SessionHistoryUseCase defines the contract for finished session summaries
+type SessionHistoryUseCase interface {
+	// GetSessionSummary returns the summary of a finished session
+	GetSessionSummary(request *dto.GetSessionSummaryRequest) (*dto.SessionSummaryResponse, error)
+
+	// UpdateSessionNotes replaces the notes stored with a finished session
+	UpdateSessionNotes(request *dto.UpdateSessionNotesRequest) (*dto.SessionSummaryResponse, error)
+}
+
+// ViewerSettingsUseCase defines the contract for per-device viewer settings
+type ViewerSettingsUseCase interface {
+	// GetSettings returns the settings stored for a device, or the defaults
+	GetSettings(request *dto.GetViewerSettingsRequest) (*dto.ViewerSettingsResponse, error)
+
+	// UpdateSettings stores the settings for a device
+	UpdateSettings(request *dto.UpdateViewerSettingsRequest) (*dto.ViewerSettingsResponse, error)
+
+	// RequestQuality asks the sender of a live session to adjust its stream
+	RequestQuality(request *dto.QualityRequest) error
+}
+
+// HandoutUseCase defines the contract for printable session handouts
+type HandoutUseCase interface {
+	// GetHandout returns the joining details for a live session
+	GetHandout(request *dto.GetHandoutRequest) (*dto.HandoutResponse, error)
+}
+
+// InviteUseCase defines the contract for posting viewer links to team chat
+type InviteUseCase interface {
+	// PostInvite posts the viewer link of a live session to the configured
+	// chat channels
+	PostInvite(request *dto.PostInviteRequest) (*dto.PostInviteResponse, error)
+}
+
+// StatusUseCase defines the contract for the viewer status summary
+type StatusUseCase interface {
+	// GetViewerStatus counts the connected and queued viewers across live sessions
+	GetViewerStatus() (*dto.ViewerStatusResponse, error)
+}
+
+// StatsUseCase defines the contract for the WebRTC stats peers upload
+type StatsUseCase interface {
+	// RecordStats stores a peer's stats summary in its session's time series
+	RecordStats(request *dto.RecordStatsRequest) error
+
+	// RecordLatency stores a latency probe result of a peer of a session
+	RecordLatency(request *dto.RecordLatencyRequest) error
+
+	// GetSessionStats returns a session's stats time series
+	GetSessionStats(request *dto.GetStatsRequest) (*dto.SessionStatsResponse, error)
+
+	// ListSessionStats returns the live sessions with their peers' latest stats
+	ListSessionStats() (*dto.AdminSessionsResponse, error)
+
+	// GetStatsSummary returns today's session totals
+	GetStatsSummary() (*dto.StatsSummaryResponse, error)
+
+	// GetViewerStats returns the connection quality of each of a session's
+	// viewers, for its sender
+	GetViewerStats(request *dto.GetViewerStatsRequest) (*dto.ViewerStatsResponse, error)
+}
+
+// AuditUseCase defines the contract for reading the audit logs of sessions
+type AuditUseCase interface {
+	// GetAuditLog returns the lifecycle events recorded for a session
+	GetAuditLog(request *dto.GetAuditLogRequest) (*dto.AuditLogResponse, error)
+}
+
+// CleanupUseCase defines the contract for the garbage collection of ended and expired sessions
+type CleanupUseCase interface {
+	// RunCleanup collects garbage now and returns what it removed
+	RunCleanup(trigger string) (*entities.CleanupRun, error)
+
+	// CleanupStatus returns the collection interval and the latest run
+	CleanupStatus() *entities.CleanupStatus
+}
+
+// RoomUseCase defines the contract for named rooms that lead to a sender's current session
+type RoomUseCase interface {
+	// ClaimRoom points a room at a live session, creating the room if the name is free
+	ClaimRoom(request *dto.ClaimRoomRequest) (*dto.ClaimRoomResponse, error)
+
+	// GetRoom returns the session a room currently leads to
+	GetRoom(request *dto.GetRoomRequest) (*dto.RoomResponse, error)
+}
+
+// ViewerLinkUseCase defines the contract for viewer links that expire and run
+// out of uses on their own, ahead of the session they lead to
+type ViewerLinkUseCase interface {
+	// CreateLink hands out a new viewer link for the sender's session
+	CreateLink(request *dto.CreateViewerLinkRequest) (*dto.ViewerLink, error)
+
+	// ListLinks returns the viewer links of the sender's session
+	ListLinks(request *dto.ListViewerLinksRequest) (*dto.ViewerLinksResponse, error)
+
+	// RevokeLink withdraws a viewer link before it expires
+	RevokeLink(request *dto.RevokeViewerLinkRequest) error
+
+	// RedeemLink returns the session a viewer link leads to, taking one of its uses
+	RedeemLink(request *dto.RedeemViewerLinkRequest) (*dto.RedeemViewerLinkResponse, error)
+}
+
+// BanUseCase defines the contract for the ban list that keeps clients away
+// from the signaling endpoints
+type BanUseCase interface {
+	// ListBans returns the ban list, oldest first
+	ListBans() (*dto.BansResponse, error)
+
+	// AddBan bans an address or range
+	AddBan(request *dto.AddBanRequest) (*entities.Ban, error)
+
+	// RemoveBan lifts the ban of an address or range
+	RemoveBan(request *dto.RemoveBanRequest) error
+
+	// IsBanned reports whether a client IP address falls in a banned range
+	IsBanned(ip string) bool
+}
+
+// ChatUseCase defines the contract for relaying chat before the peers' data channel is open
+type ChatUseCase interface {
+	// SendMessage relays a chat message to the other peer
+	SendMessage(request *dto.SendChatMessageRequest) (*entities.ChatMessage, error)
+
+	// GetMessages returns the relayed chat messages after request.Since
+	GetMessages(request *dto.GetChatMessagesRequest) (*dto.ChatMessagesResponse, error)
+}
+
+// NegotiationUseCase defines the contract for relaying the perfect
+// negotiation of the sender and the connected viewer
+type NegotiationUseCase interface {
+	// SendMessage relays a description or candidate to the other peer
+	SendMessage(request *dto.SendNegotiationMessageRequest) (*entities.NegotiationMessage, error)
+
+	// GetMessages returns the messages relayed on the viewer's connection
+	// after request.Since
+	GetMessages(request *dto.GetNegotiationMessagesRequest) (*dto.NegotiationMessagesResponse, error)
+}
+
+// KeyExchangeUseCase defines the contract for relaying the content keys of
+// end-to-end encrypted sessions between the sender and its viewers
+type KeyExchangeUseCase interface {
+	// SendMessage relays a public key, wrapped content key or rotation
+	SendMessage(request *dto.SendKeyMessageRequest) (*entities.KeyExchangeMessage, error)
+
+	// GetMessages returns the key messages the caller may read after
+	// request.Since
+	GetMessages(request *dto.GetKeyMessagesRequest) (*dto.KeyMessagesResponse, error)
+}
+
+// FingerprintUseCase defines the contract for the DTLS fingerprints users
+// compare to verify who they are connected to
+type FingerprintUseCase interface {
+	// GetFingerprints returns the fingerprints of the session's offer and
+	// answer
+	GetFingerprints(request *dto.GetFingerprintsRequest) (*dto.FingerprintsResponse, error)
+}
+
+// FrameUseCase defines the contract for snapshots of a session's screen
+type FrameUseCase interface {
+	// WatchFrames streams JPEG snapshots of a session's screen
+	WatchFrames(request *dto.WatchFramesRequest) (*dto.FrameStream, error)
+
+	// Snapshot returns a PNG of a session's screen
+	Snapshot(request *dto.SnapshotRequest) ([]byte, error)
+}
+
+// RecordingUseCase defines the contract for managing the recordings of
+// sessions streamed through the server
+type RecordingUseCase interface {
+	// ListRecordings returns the recordings, oldest first
+	ListRecordings() (*dto.RecordingsResponse, error)
+
+	// GetRecording opens a recording for download
+	GetRecording(request *dto.GetRecordingRequest) (*dto.RecordingDownload, error)
+
+	// DeleteRecording removes a recording
+	DeleteRecording(request *dto.DeleteRecordingRequest) error
+
+	// PruneRecordings removes the recordings past the retention period, then
+	// the oldest until the rest fit the disk limit, and returns them
+	PruneRecordings() ([]*entities.Recording, error)
+}
+
+// SessionTemplateUseCase defines the contract for the session templates
+// admins define and senders start sessions from
+type SessionTemplateUseCase interface {
+	// ListTemplates returns the session templates, by name
+	ListTemplates() (*dto.SessionTemplatesResponse, error)
+
+	// SaveTemplate defines a session template, replacing any of the same name
+	SaveTemplate(request *dto.SaveSessionTemplateRequest) (*entities.SessionTemplate, error)
+
+	// DeleteTemplate removes a session template
+	DeleteTemplate(request *dto.DeleteSessionTemplateRequest) error
+}
+
+// SenderProfileUseCase defines the contract for the defaults senders keep
+// on the server
+type SenderProfileUseCase interface {
+	// GetProfile returns a sender's defaults
+	GetProfile(request *dto.GetSenderProfileRequest) (*dto.SenderProfileResponse, error)
+
+	// UpdateProfile replaces a sender's defaults
+	UpdateProfile(request *dto.UpdateSenderProfileRequest) (*dto.SenderProfileResponse, error)
+
+	// DeleteProfile forgets a sender's defaults
+	DeleteProfile(request *dto.DeleteSenderProfileRequest) error
+}
+
+// DevicePairingUseCase defines the contract for the viewer devices senders
+// trust to join their sessions without a PIN
+type DevicePairingUseCase interface {
+	// RequestPairing hands a viewer device the code its sender confirms
+	RequestPairing(request *dto.RequestPairingRequest) (*dto.PairingCodeResponse, error)
+
+	// ConfirmPairing trusts the viewer device showing a code
+	ConfirmPairing(request *dto.ConfirmPairingRequest) (*dto.TrustedDevice, error)
+
+	// UnlockSession returns a session's PIN to a device its sender trusts
+	UnlockSession(request *dto.UnlockSessionRequest) (*dto.UnlockSessionResponse, error)
+
+	// ListDevices returns the devices a sender trusts
+	ListDevices(request *dto.ListTrustedDevicesRequest) (*dto.TrustedDevicesResponse, error)
+
+	// ForgetDevice withdraws a sender's trust in a device
+	ForgetDevice(request *dto.ForgetTrustedDeviceRequest) error
+}
+
+// FileUseCase defines the contract for relaying files before the peers' data channel is open
+type FileUseCase interface {
+	// ShareFile keeps a file for the session's viewers and announces it
+	ShareFile(request *dto.ShareFileRequest) (*entities.SharedFile, error)
+
+	// ListFiles returns the files relayed in a session
+	ListFiles(request *dto.GetFilesRequest) (*dto.FilesResponse, error)
+
+	// GetFile returns one relayed file and its content
+	GetFile(request *dto.GetFileRequest) (*entities.SharedFile, []byte, error)
 }
@@ -0,0 +1,24 @@
+package interfaces
+
+// VideoRecorder defines the contract for storing or showing a received video
+// track without a browser
+type VideoRecorder interface {
+	// Open prepares a sink for a track sent in codec
+	Open(codec VideoCodec) (VideoSink, error)
+}
+
+// VideoCodec describes the RTP payload of a received track
+type VideoCodec struct {
+	MimeType    string
+	ClockRate   uint32
+	PayloadType uint8
+}
+
+// VideoSink consumes the RTP packets of one track
+type VideoSink interface {
+	// WriteRTP takes one marshalled RTP packet
+	WriteRTP(packet []byte) error
+
+	// Close flushes and releases the sink
+	Close() error
+}
@@ -0,0 +1,193 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pion/webrtc/v4/pkg/media/ivfreader"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// videoBitrate is the VP8 target bitrate, enough for a sharp 1080p desktop
+const videoBitrate = "2M"
+
+// FFmpegCapturer implements the ScreenCapturer interface by running ffmpeg,
+// which grabs the screen and encodes it as VP8 in an IVF stream on stdout
+type FFmpegCapturer struct {
+	binary string
+	input  string
+}
+
+// NewFFmpegCapturer creates a new ffmpeg screen capturer; binary is the ffmpeg
+// executable and input the screen to grab, empty for the platform default
+// ($DISPLAY on Linux, the main screen on macOS, the desktop on Windows)
+func NewFFmpegCapturer(binary, input string) interfaces.ScreenCapturer {
+	if binary == "" {
+		binary = "ffmpeg"
+	}
+	return &FFmpegCapturer{
+		binary: binary,
+		input:  input,
+	}
+}
+
+// Start launches ffmpeg and waits for the first bytes of its output
+func (c *FFmpegCapturer) Start(ctx context.Context, frameRate int) (interfaces.VideoStream, error) {
+	if frameRate <= 0 {
+		return nil, fmt.Errorf("invalid frame rate %d", frameRate)
+	}
+
+	args, err := captureArgs(runtime.GOOS, c.input, frameRate)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(ctx, c.binary, args...)
+	stderr := &limitedBuffer{limit: 4096}
+	cmd.Stderr = stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, fmt.Errorf("starting %s: %w", c.binary, err)
+	}
+
+	stream := &ffmpegStream{cmd: cmd, cancel: cancel, stderr: stderr}
+	reader, header, err := ivfreader.NewWith(stdout)
+	if err != nil {
+		_ = stream.Close()
+		return nil, stream.failure(err)
+	}
+	if header.FourCC != "VP80" {
+		_ = stream.Close()
+		return nil, fmt.Errorf("unexpected codec %q from ffmpeg", header.FourCC)
+	}
+	stream.reader = reader
+
+	return stream, nil
+}
+
+// ffmpegStream is a running ffmpeg capture
+type ffmpegStream struct {
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	reader *ivfreader.IVFReader
+	stderr *limitedBuffer
+	once   sync.Once
+}
+
+// NextFrame returns the next VP8 frame
+func (s *ffmpegStream) NextFrame() ([]byte, error) {
+	frame, _, err := s.reader.ParseNextFrame()
+	if err != nil {
+		// Waiting for ffmpeg to exit collects the rest of its error output
+		_ = s.Close()
+		return nil, s.failure(err)
+	}
+	return frame, nil
+}
+
+// Close stops ffmpeg and waits for it to exit
+func (s *ffmpegStream) Close() error {
+	s.once.Do(func() {
+		s.cancel()
+		_ = s.cmd.Wait()
+	})
+	return nil
+}
+
+// failure explains a read error with ffmpeg's own message when it printed one
+func (s *ffmpegStream) failure(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		if message := strings.TrimSpace(s.stderr.String()); message != "" {
+			return fmt.Errorf("ffmpeg stopped: %s", message)
+		}
+	}
+	return err
+}
+
+// captureArgs builds the ffmpeg command line for grabbing the screen on goos
+func captureArgs(goos, input string, frameRate int) ([]string, error) {
+	var format string
+	switch goos {
+	case "linux", "freebsd", "openbsd":
+		format = "x11grab"
+		if input == "" {
+			input = os.Getenv("DISPLAY")
+		}
+		if input == "" {
+			input = ":0"
+		}
+	case "darwin":
+		format = "avfoundation"
+		if input == "" {
+			input = "Capture screen 0:none"
+		}
+	case "windows":
+		format = "gdigrab"
+		if input == "" {
+			input = "desktop"
+		}
+	default:
+		return nil, fmt.Errorf("screen capture is not supported on %s", goos)
+	}
+
+	fps := strconv.Itoa(frameRate)
+	return []string{
+		"-loglevel", "error",
+		"-f", format,
+		"-framerate", fps,
+		"-i", input,
+		// VP8 needs even dimensions
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-pix_fmt", "yuv420p",
+		"-c:v", "libvpx",
+		"-deadline", "realtime",
+		"-cpu-used", "8",
+		"-b:v", videoBitrate,
+		// A keyframe every two seconds lets a new viewer start decoding quickly
+		"-g", strconv.Itoa(frameRate * 2),
+		"-an",
+		"-f", "ivf",
+		"pipe:1",
+	}, nil
+}
+
+// limitedBuffer keeps the first bytes written to it, for error messages
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write implements io.Writer, discarding bytes past the limit
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+// String returns the kept bytes
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
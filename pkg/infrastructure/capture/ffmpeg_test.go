@@ -0,0 +1,79 @@
+package capture
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCaptureArgs(t *testing.T) {
+	tests := []struct {
+		name          string
+		goos          string
+		input         string
+		expectedInput string
+		expectedError bool
+	}{
+		{
+			name:          "linux display",
+			goos:          "linux",
+			input:         ":99",
+			expectedInput: ":99",
+		},
+		{
+			name:          "macOS main screen",
+			goos:          "darwin",
+			expectedInput: "Capture screen 0:none",
+		},
+		{
+			name:          "windows desktop",
+			goos:          "windows",
+			expectedInput: "desktop",
+		},
+		{
+			name:          "unsupported platform",
+			goos:          "plan9",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := captureArgs(tt.goos, tt.input, 15)
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			i := slices.Index(args, "-i")
+			if i < 0 || args[i+1] != tt.expectedInput {
+				t.Errorf("Expected input %q, got %v", tt.expectedInput, args)
+			}
+			if g := slices.Index(args, "-g"); g < 0 || args[g+1] != "30" {
+				t.Errorf("Expected a keyframe every 30 frames, got %v", args)
+			}
+			if args[len(args)-1] != "pipe:1" {
+				t.Errorf("Expected output on stdout, got %v", args)
+			}
+		})
+	}
+}
+
+func TestFFmpegCapturer_StartFailure(t *testing.T) {
+	capturer := NewFFmpegCapturer("/nonexistent/ffmpeg", ":0")
+
+	if _, err := capturer.Start(context.Background(), 0); err == nil {
+		t.Error("Expected error for invalid frame rate")
+	}
+
+	_, err := capturer.Start(context.Background(), 15)
+	if err == nil || !strings.Contains(err.Error(), "/nonexistent/ffmpeg") {
+		t.Errorf("Expected error naming the missing binary, got %v", err)
+	}
+}
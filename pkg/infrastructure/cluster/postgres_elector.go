@@ -0,0 +1,92 @@
+// Package cluster coordinates the instances of a clustered deployment.
+package cluster
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// electionTimeout bounds each leadership check, so a database that stops
+// answering costs the lead instead of hanging cleanup
+const electionTimeout = 5 * time.Second
+
+// LeaderLock is the advisory lock key the leader holds
+const LeaderLock = 0x5e55_1ead
+
+// PostgresElector implements LeaderElector with a PostgreSQL session-level
+// advisory lock. The lock lives as long as the connection that took it, so
+// an instance that crashes or loses the database gives up the lead without
+// any timeout, and the next instance to check takes over.
+type PostgresElector struct {
+	dsn        string
+	instanceID string
+
+	mu   sync.Mutex
+	conn *pgx.Conn
+}
+
+// NewPostgresElector creates an elector for the instance named instanceID,
+// campaigning through its own connection to the database at dsn
+func NewPostgresElector(dsn, instanceID string) interfaces.LeaderElector {
+	return &PostgresElector{dsn: dsn, instanceID: instanceID}
+}
+
+// IsLeader reports whether this instance holds the lock, trying to take it
+// when it does not; errors count as not leading
+func (e *PostgresElector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), electionTimeout)
+	defer cancel()
+
+	if e.conn != nil {
+		err := e.conn.Ping(ctx)
+		if err == nil {
+			return true
+		}
+		log.Printf("⚠️  Instance %s lost the cluster lead: %v", e.instanceID, err)
+		e.conn.Close(ctx)
+		e.conn = nil
+	}
+
+	conn, err := pgx.Connect(ctx, e.dsn)
+	if err != nil {
+		log.Printf("❌ Error joining the leader election: %v", err)
+		return false
+	}
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, LeaderLock).Scan(&acquired); err != nil || !acquired {
+		if err != nil {
+			log.Printf("❌ Error joining the leader election: %v", err)
+		}
+		conn.Close(ctx)
+		return false
+	}
+
+	log.Printf("👑 Instance %s leads the cluster", e.instanceID)
+	e.conn = conn
+	return true
+}
+
+// Close releases the lock by closing its connection
+func (e *PostgresElector) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), electionTimeout)
+	defer cancel()
+
+	err := e.conn.Close(ctx)
+	e.conn = nil
+	return err
+}
@@ -0,0 +1,49 @@
+package cluster
+
+import (
+	"os"
+	"testing"
+)
+
+func TestPostgresElector(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	first := NewPostgresElector(dsn, "first")
+	second := NewPostgresElector(dsn, "second")
+	defer first.Close()
+	defer second.Close()
+
+	if !first.IsLeader() {
+		t.Fatal("Expected the first instance to take the lead")
+	}
+	if !first.IsLeader() {
+		t.Error("Expected the leader to keep the lead")
+	}
+	if second.IsLeader() {
+		t.Error("Expected only one leader")
+	}
+
+	// Giving up the lead hands it to the next instance to check
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if !second.IsLeader() {
+		t.Error("Expected the second instance to take over")
+	}
+	if first.IsLeader() {
+		t.Error("Expected the first instance to follow now")
+	}
+}
+
+func TestPostgresElector_Unreachable(t *testing.T) {
+	elector := NewPostgresElector("postgres://user@127.0.0.1:1/db?connect_timeout=1", "lonely")
+	if elector.IsLeader() {
+		t.Error("Expected an instance without a database not to lead")
+	}
+	if err := elector.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bufio"
 	"flag"
 	"log"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -17,6 +19,263 @@ type Config struct {
 	EnableHTTPS bool
 	CertFile    string
 	KeyFile     string
+	LinkPreview bool
+
+	// BindAddresses limits the server to these interfaces, as IP addresses
+	// or host names with an optional port (PORT when omitted), e.g.
+	// 127.0.0.1 and the LAN address; empty listens on every interface,
+	// including VPN tunnels
+	BindAddresses []string
+
+	// Interface is the network interface, e.g. en0, whose address is put in
+	// viewer links instead of the first private address of any interface,
+	// which may belong to Docker or a VPN
+	Interface string
+
+	// MDNS advertises the server on the LAN so `share-screen discover` on
+	// another machine finds it
+	MDNS bool
+
+	// ExternalURL is the base URL viewers outside the LAN reach the server
+	// on, e.g. https://share.example.com:8443 through a forwarded port, used
+	// in viewer links instead of the LAN IP; DiscoverPublicIP asks the STUN
+	// server for the server's public IP and reports it in /api/v1/info
+	ExternalURL      string
+	DiscoverPublicIP bool
+
+	// Tunnel exposes the server through an outbound tunnel and puts its
+	// public URL in viewer links: ssh://user@host for an SSH remote forward
+	// to TunnelRemote, or command:<program> for a tunnel program whose
+	// output announces the URL. TunnelURL is the public URL when the
+	// provider does not print it; TunnelKeyFile and TunnelKnownHosts are
+	// the SSH key and the file host keys are checked against.
+	Tunnel           string
+	TunnelURL        string
+	TunnelRemote     string
+	TunnelKeyFile    string
+	TunnelKnownHosts string
+
+	// HTTPRedirectPort is the plain HTTP port that redirects to HTTPS when
+	// HTTPS is enabled, so typed http:// URLs still work; with HTTPSAuto it
+	// also answers Let's Encrypt's HTTP challenges. Empty or "0" disables it.
+	HTTPRedirectPort string
+
+	// HTTPSAuto serves HTTPS with certificates obtained from Let's Encrypt
+	// for AutocertDomains, cached in AutocertCacheDir; AutocertEmail is the
+	// contact for expiry notices
+	HTTPSAuto        bool
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+
+	// ICEServers is a JSON array of STUN/TURN servers, or the path of a file
+	// holding one; when empty, peers use STUNServer alone
+	ICEServers string
+
+	// TURNSecret is shared with the TURN server (coturn's static-auth-secret)
+	// to mint credentials for TURN entries in ICEServers that have none, valid
+	// for TURNCredentialTTL
+	TURNSecret        string
+	TURNCredentialTTL time.Duration
+
+	// HeartbeatTimeout is how long a sender may go without a heartbeat before
+	// its session goes stale and is cleaned up
+	HeartbeatTimeout time.Duration
+
+	// IdleTimeout expires sessions that get no offer, or after the offer no
+	// viewer, for this long, ahead of TokenExpiry; 0 disables it
+	IdleTimeout time.Duration
+
+	// GCInterval is how often ended, stale and expired sessions are cleaned up
+	GCInterval time.Duration
+
+	// ShutdownTimeout bounds how long in-flight requests may run after SIGTERM
+	ShutdownTimeout time.Duration
+
+	// CORSOrigins lists the origins allowed to call the API from another site;
+	// "*" allows any origin and an empty list disables cross-origin access
+	CORSOrigins []string
+
+	// RequestLog is the format of the per-request log lines: "text" for the
+	// server log, "json" for log collectors or "off"
+	RequestLog string
+
+	// Middleware lists the middlewares wrapping every request, outermost
+	// first, from logging, security-headers, cors, compress, ratelimit and
+	// auth; RateLimit and RateLimitBurst are the requests per second and
+	// burst each client IP gets from ratelimit
+	Middleware     []string
+	RateLimit      int
+	RateLimitBurst int
+
+	// WebDir holds the pages' templates and static files, in templates/ and
+	// static/, so the UI can be customized without rebuilding; DevMode
+	// parses the page templates again whenever one of them changes
+	WebDir  string
+	DevMode bool
+
+	// BrandName, BrandLogo, BrandAccent and BrandFooter give the pages a
+	// company's look: its name in the header and titles, a logo URL or file,
+	// an accent color and a footer line; empty ones keep the stock look
+	BrandName   string
+	BrandLogo   string
+	BrandAccent string
+	BrandFooter string
+
+	// MaxSessions and MaxBandwidthMbps are soft limits: senders are warned
+	// when usage reaches LimitWarningPercent of them; 0 disables a limit
+	MaxSessions         int
+	MaxBandwidthMbps    int
+	LimitWarningPercent int
+
+	// SessionLimit is a hard cap on live sessions: creating one more fails
+	// with "server busy"; 0 for none
+	SessionLimit int
+
+	// TokenFormat and TokenBytes shape session tokens: "base64url", "hex",
+	// "base32" (Crockford) or "uuid", and the random bytes in each; 0 bytes
+	// is the default of 9
+	TokenFormat string
+	TokenBytes  int
+
+	// TokenSecret, when set, signs session tokens with their expiry, valid
+	// for TokenLifetime, so junk and expired tokens are refused without a
+	// lookup
+	TokenSecret   string
+	TokenLifetime time.Duration
+
+	// EventBuffer is how many events each realtime client may have pending, and
+	// EventPolicy what happens to a client that fills it: "drop-oldest",
+	// "drop-newest" or "close"
+	EventBuffer int
+	EventPolicy string
+
+	// AllowedNetworks are the CIDR ranges allowed to use the signaling
+	// endpoints; "*" allows every client and loopback is always allowed
+	AllowedNetworks []string
+
+	// StatusToken is the bearer token for the viewer status endpoint; empty disables it
+	StatusToken string
+
+	// AdminToken is the bearer token for the admin dashboard and its API;
+	// empty disables them
+	AdminToken string
+
+	// BanFile keeps the ban list managed through the admin API across
+	// restarts; empty keeps it in memory only
+	BanFile string
+
+	// TemplatesFile keeps the session templates managed through the admin
+	// API across restarts; empty keeps them in memory only
+	TemplatesFile string
+
+	// DevicesFile keeps the viewer devices senders trust across restarts;
+	// empty keeps them in memory only
+	DevicesFile string
+
+	// SnapshotFile keeps live sessions, session history and viewer device
+	// settings across restarts: the server saves them there every
+	// SnapshotInterval and on shutdown, and loads them on startup; empty
+	// keeps them in memory only
+	SnapshotFile     string
+	SnapshotInterval time.Duration
+
+	// StorageBackend is where sessions are stored: "memory", the default,
+	// "bolt" for the bbolt database file at BoltPath, which keeps them across
+	// restarts, or "postgres" for the database at PostgresDSN, which several
+	// instances can share through a pool of up to PostgresMaxConns
+	// connections
+	StorageBackend   string
+	BoltPath         string
+	PostgresDSN      string
+	PostgresMaxConns int
+
+	// Cluster runs the server as one of several instances sharing the
+	// postgres backend; only the instance elected leader removes expired
+	// sessions. InstanceID names this instance in logs, the host name when
+	// empty.
+	Cluster    bool
+	InstanceID string
+
+	// GRPCAddr is the address the gRPC signaling API listens on, e.g.
+	// ":9443"; empty disables it. It uses the HTTPS certificate when HTTPS
+	// is enabled.
+	GRPCAddr string
+
+	// MQTTBroker is the URL of the MQTT broker the signaling bridge mirrors
+	// the sessions to, e.g. "tcp://broker:1883"; empty disables the bridge.
+	// MQTTPrefix is the root of its topics, and MQTTUsername and
+	// MQTTPassword log in to the broker when set.
+	MQTTBroker   string
+	MQTTPrefix   string
+	MQTTUsername string
+	MQTTPassword string
+
+	// AuthUser and AuthPassword protect the sender and admin pages and
+	// session creation with HTTP basic auth; an empty user disables it
+	AuthUser     string
+	AuthPassword string
+
+	// JWTSecret and JWTPublicKeyFile require API clients to send a bearer
+	// JWT, signed with HS256 by the secret or RS256 by the private key of the
+	// PEM public key in the file, to create sessions and use the admin API;
+	// both empty disable it. JWTAudience, when set, must be in the "aud".
+	JWTSecret        string
+	JWTPublicKeyFile string
+	JWTAudience      string
+
+	// FileRelayMB is how many megabytes of files a session may relay through
+	// the server while no data channel is open; 0 disables the relay
+	FileRelayMB int
+
+	// SFU lets sessions stream through the server to many viewers at once
+	SFU bool
+
+	// SFUPort is the UDP port all SFU media uses; 0 picks a random port per
+	// connection
+	SFUPort int
+
+	// RTMPURL is where the SFU republishes the video of each session through
+	// ffmpeg, with {token} replaced by the session token; empty disables it
+	RTMPURL string
+
+	// HLSDir is where the SFU packages each session's video as HLS through
+	// ffmpeg, in a directory per session, for viewers without WebRTC; empty
+	// disables it
+	HLSDir string
+
+	// RecordingsDir is where the SFU records the video of each session
+	// through ffmpeg, for admins to download; empty disables recording.
+	// RecordingsMaxMB and RecordingsRetention bound the disk space and the
+	// age of the recordings kept, 0 for no bound.
+	RecordingsDir       string
+	RecordingsMaxMB     int
+	RecordingsRetention time.Duration
+
+	// SlackWebhookURL and DiscordWebhookURL are the incoming webhooks senders
+	// may post their viewer link to, to invite a team channel; empty
+	// disables each
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+
+	// ProfileSecret, when set, signs the cookies naming sender profiles,
+	// the defaults senders keep in ProfilesFile; empty disables profiles.
+	// An empty ProfilesFile keeps them in memory only.
+	ProfileSecret string
+	ProfilesFile  string
+
+	// MaxBitrateKbps caps the video bitrate of sessions that set no cap of
+	// their own; 0 leaves them uncapped
+	MaxBitrateKbps int
+
+	// MaxFrameRate caps the frames per second of sessions that set no cap of
+	// their own; 0 leaves them uncapped
+	MaxFrameRate int
+
+	// VideoCodec is the codec (h264, vp8 or vp9) sessions prefer unless they
+	// pick their own; ForceVideoCodec drops the other codecs
+	VideoCodec      string
+	ForceVideoCodec bool
 }
 
 // LoadConfig loads configuration from environment variables and command line flags
@@ -26,28 +285,408 @@ func LoadConfig() *Config {
 
 	// Define flags
 	port := flag.String("port", "8080", "Server port")
+	bind := flag.String("bind", "", "Comma-separated addresses to listen on, e.g. 127.0.0.1,192.168.1.10:8080 (empty listens on every interface)")
+	iface := flag.String("interface", "", "Network interface whose address is used in viewer links, e.g. en0 (empty picks one automatically)")
+	advertise := flag.Bool("mdns", true, "Advertise the server on the LAN with mDNS for share-screen discover")
+	externalURL := flag.String("external-url", "", "Base URL viewers outside the LAN use, e.g. https://share.example.com:8443 (empty uses the LAN IP)")
+	tunnel := flag.String("tunnel", "", "Expose the server through a tunnel: ssh://user@host for an SSH remote forward, or command:<program> (empty disables it)")
+	tunnelURL := flag.String("tunnel-url", "", "Public URL of the tunnel when the provider does not print it")
+	tunnelRemote := flag.String("tunnel-remote", "localhost:80", "Address the SSH server listens on for the tunnel")
+	tunnelKeyFile := flag.String("tunnel-key", "", "Private key for the SSH tunnel (the SSH agent is used too)")
+	tunnelKnownHosts := flag.String("tunnel-known-hosts", "", "known_hosts file the SSH tunnel server is checked against (default ~/.ssh/known_hosts)")
+	discoverPublicIP := flag.Bool("discover-public-ip", false, "Discover the server's public IP through the STUN server for viewers outside the LAN")
 	stunServer := flag.String("stun", "stun:stun.l.google.com:19302", "STUN server URL")
+	iceServers := flag.String("ice-servers", "", "JSON array of STUN/TURN servers, or a file holding one (overrides -stun)")
 	tokenExpiry := flag.Duration("token-expiry", 30*time.Minute, "Token expiry duration")
+	turnSecret := flag.String("turn-secret", "", "Secret shared with the TURN server for minting short-lived credentials")
+	turnTTL := flag.Duration("turn-ttl", 12*time.Hour, "How long minted TURN credentials stay valid")
+	heartbeatTimeout := flag.Duration("heartbeat-timeout", 2*time.Minute, "Time without a sender heartbeat before a session goes stale")
+	idleTimeout := flag.Duration("idle-timeout", 10*time.Minute, "Time a session may wait for its offer, then for a viewer, before it expires early (0 disables)")
+	gcInterval := flag.Duration("gc-interval", time.Minute, "How often ended and expired sessions are cleaned up")
 	enableHTTPS := flag.Bool("https", false, "Enable HTTPS")
+	httpRedirectPort := flag.String("http-redirect-port", "8080", "Plain HTTP port redirecting to HTTPS when HTTPS is enabled (0 disables it)")
+	httpsAuto := flag.Bool("https-auto", false, "Enable HTTPS with certificates from Let's Encrypt for -autocert-domain")
+	autocertDomains := flag.String("autocert-domain", "", "Comma-separated public domain names to obtain certificates for with -https-auto")
+	autocertCacheDir := flag.String("autocert-cache", "certs/autocert", "Directory caching the Let's Encrypt account and certificates")
+	autocertEmail := flag.String("autocert-email", "", "Contact email for Let's Encrypt expiry notices")
 	certFile := flag.String("cert", "/certs/fullchain.pem", "Path to TLS certificate file")
 	keyFile := flag.String("key", "/certs/privkey.pem", "Path to TLS private key file")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "Time allowed for in-flight requests on shutdown")
+	linkPreview := flag.Bool("link-preview", true, "Serve Open Graph metadata on viewer links")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated origins allowed to call the API cross-origin")
+	requestLog := flag.String("request-log", "text", "Format of the per-request log lines: text, json or off")
+	webDir := flag.String("web-dir", "web", "Directory holding the templates/ and static/ the pages are served from")
+	devMode := flag.Bool("dev", false, "Parse the page templates again whenever one of them changes, for working on the UI")
+	brandName := flag.String("brand-name", "", "Name shown in the page header and titles instead of Share Screen")
+	brandLogo := flag.String("brand-logo", "", "Logo shown in the page header: an http(s) URL, a path under /static/ or an image file")
+	brandAccent := flag.String("brand-accent", "", "Accent color of buttons and highlights, e.g. #e4002b")
+	brandFooter := flag.String("brand-footer", "", "Line of text shown at the bottom of every page")
+	middleware := flag.String("middleware", "logging,security-headers,cors,compress", "Comma-separated middlewares wrapping every request, outermost first: logging, security-headers, cors, compress, ratelimit, auth")
+	rateLimit := flag.Int("rate-limit", 20, "Requests per second each client IP may make with the ratelimit middleware")
+	rateLimitBurst := flag.Int("rate-limit-burst", 40, "Requests each client IP may make at once with the ratelimit middleware")
+	allowedNetworks := flag.String("allowed-networks", "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16", "Comma-separated CIDR ranges allowed to use signaling, or * for any client")
+	statusToken := flag.String("status-token", "", "Bearer token for the viewer status endpoint (empty disables it)")
+	adminToken := flag.String("admin-token", "", "Bearer token for the admin dashboard and its API (empty disables them)")
+	banFile := flag.String("ban-file", "bans.json", "File keeping the ban list across restarts (empty keeps it in memory)")
+	templatesFile := flag.String("templates-file", "templates.json", "File keeping the session templates across restarts (empty keeps them in memory)")
+	devicesFile := flag.String("devices-file", "devices.json", "File keeping the viewer devices senders trust across restarts (empty keeps them in memory)")
+	snapshotFile := flag.String("snapshot-file", "", "File keeping live sessions, history and device settings across restarts (empty keeps them in memory)")
+	snapshotInterval := flag.Duration("snapshot-interval", 30*time.Second, "How often the state is saved to -snapshot-file")
+	storageBackend := flag.String("storage", "memory", "Where sessions are stored: memory, bolt or postgres")
+	boltPath := flag.String("bolt-path", "sessions.db", "Database file holding the sessions with -storage bolt")
+	postgresDSN := flag.String("postgres-dsn", "", "Connection string of the database holding the sessions with -storage postgres")
+	postgresMaxConns := flag.Int("postgres-max-conns", 10, "Connections pooled to the database with -storage postgres")
+	cluster := flag.Bool("cluster", false, "Run as one of several instances sharing -storage postgres, electing one to collect expired sessions")
+	instanceID := flag.String("instance-id", "", "Name of this instance in a cluster (empty uses the host name)")
+	grpcAddr := flag.String("grpc-addr", "", "Address for the gRPC signaling API, e.g. :9443 (empty disables it)")
+	mqttBroker := flag.String("mqtt-broker", "", "URL of the MQTT broker to bridge signaling to, e.g. tcp://broker:1883 (empty disables it)")
+	mqttPrefix := flag.String("mqtt-prefix", "share-screen", "Root of the MQTT bridge's topics")
+	mqttUsername := flag.String("mqtt-username", "", "User name for the MQTT broker (empty connects anonymously)")
+	mqttPassword := flag.String("mqtt-password", "", "Password for -mqtt-username")
+	authUser := flag.String("auth-user", "", "User name required by basic auth on the sender and admin pages and session creation (empty disables it)")
+	authPassword := flag.String("auth-password", "", "Password for -auth-user")
+	jwtSecret := flag.String("jwt-secret", "", "Secret for HS256 JWTs required to create sessions and use the admin API")
+	jwtPublicKey := flag.String("jwt-public-key", "", "PEM file with the RSA public key for RS256 JWTs required to create sessions and use the admin API")
+	jwtAudience := flag.String("jwt-audience", "", "Audience JWTs must be issued for (empty accepts any)")
+	maxSessions := flag.Int("max-sessions", 0, "Soft limit on live sessions, 0 for none")
+	sessionLimit := flag.Int("session-limit", 0, "Hard limit on live sessions; new sessions are refused beyond it, 0 for none")
+	maxBitrate := flag.Int("max-bitrate", 0, "Default cap on each sender's video bitrate in kbps, 0 for none")
+	maxFrameRate := flag.Int("max-fps", 0, "Default cap on each sender's frame rate, up to 60, 0 for none")
+	videoCodec := flag.String("video-codec", "", "Video codec sessions prefer: h264, vp8 or vp9 (empty leaves it to the browsers)")
+	forceVideoCodec := flag.Bool("force-video-codec", false, "Offer only the -video-codec codec instead of preferring it")
+	maxBandwidth := flag.Int("max-bandwidth", 0, "Soft limit on the senders' combined bitrate in Mbps, 0 for none")
+	limitWarning := flag.Int("limit-warning-percent", 90, "Share of a soft limit at which senders are warned")
+	eventBuffer := flag.Int("event-buffer", 16, "Pending events allowed per realtime client")
+	fileRelay := flag.Int("file-relay-mb", 25, "Megabytes of files each session may relay through the server, 0 to disable")
+	sfu := flag.Bool("sfu", false, "Let sessions stream through the server to many viewers at once")
+	sfuPort := flag.Int("sfu-port", 0, "UDP port for SFU media, 0 for a random port per connection")
+	hlsDir := flag.String("hls-dir", "", "Directory the SFU packages each session's video into as HLS through ffmpeg, for viewers without WebRTC")
+	recordingsDir := flag.String("recordings-dir", "", "Directory the SFU records each session's video into through ffmpeg, for admins to download")
+	recordingsMax := flag.Int("recordings-max-mb", 0, "Megabytes of recordings kept; the oldest are deleted beyond it, 0 for no limit")
+	recordingsRetention := flag.Duration("recordings-retention", 0, "How long recordings are kept after they end, 0 for ever")
+	slackWebhook := flag.String("slack-webhook", "", "Slack incoming webhook URL senders may post their viewer link to (empty disables it)")
+	discordWebhook := flag.String("discord-webhook", "", "Discord webhook URL senders may post their viewer link to (empty disables it)")
+	profileSecret := flag.String("profile-secret", "", "Secret of at least 16 characters for signing sender profile cookies (empty disables profiles)")
+	profilesFile := flag.String("profiles-file", "profiles.json", "File keeping sender profiles across restarts (empty keeps them in memory)")
+	rtmpURL := flag.String("rtmp-url", "", "RTMP URL the SFU republishes each session's video to through ffmpeg; {token} is replaced by the session token")
+	eventPolicy := flag.String("event-policy", "drop-oldest", "Slow realtime client policy: drop-oldest, drop-newest or close")
+	tokenFormat := flag.String("token-format", "base64url", "Session token format: base64url, hex, base32 or uuid")
+	tokenBytes := flag.Int("token-bytes", 0, "Random bytes in each session token, 8-64 (0 for the default of 9; uuid is always 16)")
+	tokenSecret := flag.String("token-secret", "", "Secret of at least 16 characters for signing session tokens with their expiry (empty for unsigned tokens)")
+	tokenLifetime := flag.Duration("token-lifetime", 12*time.Hour, "How long signed session tokens stay valid, extensions included")
 	flag.Parse()
 
 	// Override with environment variables
 	if envPort := os.Getenv("PORT"); envPort != "" {
 		*port = envPort
 	}
+	if envBind := os.Getenv("BIND_ADDRESSES"); envBind != "" {
+		*bind = envBind
+	}
+	if envInterface := os.Getenv("NETWORK_INTERFACE"); envInterface != "" {
+		*iface = envInterface
+	}
+	if envMDNS := os.Getenv("MDNS"); envMDNS != "" {
+		*advertise = envMDNS == "true"
+	}
+	if envExternal := os.Getenv("EXTERNAL_URL"); envExternal != "" {
+		*externalURL = envExternal
+	}
+	if envDiscover := os.Getenv("DISCOVER_PUBLIC_IP"); envDiscover != "" {
+		*discoverPublicIP = envDiscover == "true"
+	}
+	if envTunnel := os.Getenv("TUNNEL"); envTunnel != "" {
+		*tunnel = envTunnel
+	}
+	if envTunnelURL := os.Getenv("TUNNEL_URL"); envTunnelURL != "" {
+		*tunnelURL = envTunnelURL
+	}
+	if envTunnelRemote := os.Getenv("TUNNEL_REMOTE"); envTunnelRemote != "" {
+		*tunnelRemote = envTunnelRemote
+	}
+	if envTunnelKey := os.Getenv("TUNNEL_KEY_FILE"); envTunnelKey != "" {
+		*tunnelKeyFile = envTunnelKey
+	}
+	if envKnownHosts := os.Getenv("TUNNEL_KNOWN_HOSTS"); envKnownHosts != "" {
+		*tunnelKnownHosts = envKnownHosts
+	}
 	if envStun := os.Getenv("STUN_SERVER"); envStun != "" {
 		*stunServer = envStun
 	}
+	if envICE := os.Getenv("ICE_SERVERS"); envICE != "" {
+		*iceServers = envICE
+	}
+	if envTURNSecret := os.Getenv("TURN_SECRET"); envTURNSecret != "" {
+		*turnSecret = envTURNSecret
+	}
+	if envTURNTTL := os.Getenv("TURN_TTL"); envTURNTTL != "" {
+		if duration, err := time.ParseDuration(envTURNTTL); err == nil {
+			*turnTTL = duration
+		}
+	}
 	if envExpiry := os.Getenv("TOKEN_EXPIRY"); envExpiry != "" {
 		if duration, err := time.ParseDuration(envExpiry); err == nil {
 			*tokenExpiry = duration
 		}
 	}
+	if envHeartbeat := os.Getenv("HEARTBEAT_TIMEOUT"); envHeartbeat != "" {
+		if duration, err := time.ParseDuration(envHeartbeat); err == nil {
+			*heartbeatTimeout = duration
+		}
+	}
+	if envIdle := os.Getenv("IDLE_TIMEOUT"); envIdle != "" {
+		if duration, err := time.ParseDuration(envIdle); err == nil {
+			*idleTimeout = duration
+		}
+	}
+	if envGC := os.Getenv("GC_INTERVAL"); envGC != "" {
+		if duration, err := time.ParseDuration(envGC); err == nil {
+			*gcInterval = duration
+		}
+	}
 	if envHTTPS := os.Getenv("ENABLE_HTTPS"); envHTTPS != "" {
 		*enableHTTPS = envHTTPS == "true"
 	}
+	if envRedirect := os.Getenv("HTTP_REDIRECT_PORT"); envRedirect != "" {
+		*httpRedirectPort = envRedirect
+	}
+	if envAuto := os.Getenv("HTTPS_AUTO"); envAuto != "" {
+		*httpsAuto = envAuto == "true"
+	}
+	if envDomains := os.Getenv("AUTOCERT_DOMAIN"); envDomains != "" {
+		*autocertDomains = envDomains
+	}
+	if envCache := os.Getenv("AUTOCERT_CACHE_DIR"); envCache != "" {
+		*autocertCacheDir = envCache
+	}
+	if envEmail := os.Getenv("AUTOCERT_EMAIL"); envEmail != "" {
+		*autocertEmail = envEmail
+	}
+	if envPreview := os.Getenv("LINK_PREVIEW"); envPreview != "" {
+		*linkPreview = envPreview == "true"
+	}
+	if envShutdown := os.Getenv("SHUTDOWN_TIMEOUT"); envShutdown != "" {
+		if duration, err := time.ParseDuration(envShutdown); err == nil {
+			*shutdownTimeout = duration
+		}
+	}
+	if envCORS := os.Getenv("CORS_ORIGINS"); envCORS != "" {
+		*corsOrigins = envCORS
+	}
+	if envRequestLog := os.Getenv("REQUEST_LOG"); envRequestLog != "" {
+		*requestLog = envRequestLog
+	}
+	if envMiddleware := os.Getenv("MIDDLEWARE"); envMiddleware != "" {
+		*middleware = envMiddleware
+	}
+	if envWebDir := os.Getenv("WEB_DIR"); envWebDir != "" {
+		*webDir = envWebDir
+	}
+	if envDev := os.Getenv("DEV_MODE"); envDev != "" {
+		*devMode = envDev == "true"
+	}
+	if envBrandName := os.Getenv("BRAND_NAME"); envBrandName != "" {
+		*brandName = envBrandName
+	}
+	if envBrandLogo := os.Getenv("BRAND_LOGO"); envBrandLogo != "" {
+		*brandLogo = envBrandLogo
+	}
+	if envBrandAccent := os.Getenv("BRAND_ACCENT"); envBrandAccent != "" {
+		*brandAccent = envBrandAccent
+	}
+	if envBrandFooter := os.Getenv("BRAND_FOOTER"); envBrandFooter != "" {
+		*brandFooter = envBrandFooter
+	}
+	if envRate := os.Getenv("RATE_LIMIT"); envRate != "" {
+		if n, err := strconv.Atoi(envRate); err == nil {
+			*rateLimit = n
+		}
+	}
+	if envBurst := os.Getenv("RATE_LIMIT_BURST"); envBurst != "" {
+		if n, err := strconv.Atoi(envBurst); err == nil {
+			*rateLimitBurst = n
+		}
+	}
+	if envNetworks := os.Getenv("ALLOWED_NETWORKS"); envNetworks != "" {
+		*allowedNetworks = envNetworks
+	}
+	if envStatus := os.Getenv("STATUS_TOKEN"); envStatus != "" {
+		*statusToken = envStatus
+	}
+	if envAdmin := os.Getenv("ADMIN_TOKEN"); envAdmin != "" {
+		*adminToken = envAdmin
+	}
+	if envBans, ok := os.LookupEnv("BAN_FILE"); ok {
+		*banFile = envBans
+	}
+	if envTemplates, ok := os.LookupEnv("TEMPLATES_FILE"); ok {
+		*templatesFile = envTemplates
+	}
+	if envDevices, ok := os.LookupEnv("DEVICES_FILE"); ok {
+		*devicesFile = envDevices
+	}
+	if envSnapshot := os.Getenv("SNAPSHOT_FILE"); envSnapshot != "" {
+		*snapshotFile = envSnapshot
+	}
+	if envSnapshotInterval := os.Getenv("SNAPSHOT_INTERVAL"); envSnapshotInterval != "" {
+		if duration, err := time.ParseDuration(envSnapshotInterval); err == nil {
+			*snapshotInterval = duration
+		}
+	}
+	if envStorage := os.Getenv("STORAGE_BACKEND"); envStorage != "" {
+		*storageBackend = envStorage
+	}
+	if envBolt := os.Getenv("BOLT_PATH"); envBolt != "" {
+		*boltPath = envBolt
+	}
+	if envPostgres := os.Getenv("POSTGRES_DSN"); envPostgres != "" {
+		*postgresDSN = envPostgres
+	}
+	if envConns := os.Getenv("POSTGRES_MAX_CONNS"); envConns != "" {
+		if conns, err := strconv.Atoi(envConns); err == nil {
+			*postgresMaxConns = conns
+		}
+	}
+	if envCluster := os.Getenv("CLUSTER"); envCluster != "" {
+		*cluster = envCluster == "true"
+	}
+	if envInstance := os.Getenv("INSTANCE_ID"); envInstance != "" {
+		*instanceID = envInstance
+	}
+	if envGRPC := os.Getenv("GRPC_ADDR"); envGRPC != "" {
+		*grpcAddr = envGRPC
+	}
+	if envMQTTBroker := os.Getenv("MQTT_BROKER"); envMQTTBroker != "" {
+		*mqttBroker = envMQTTBroker
+	}
+	if envMQTTPrefix := os.Getenv("MQTT_PREFIX"); envMQTTPrefix != "" {
+		*mqttPrefix = envMQTTPrefix
+	}
+	if envMQTTUsername := os.Getenv("MQTT_USERNAME"); envMQTTUsername != "" {
+		*mqttUsername = envMQTTUsername
+	}
+	if envMQTTPassword := os.Getenv("MQTT_PASSWORD"); envMQTTPassword != "" {
+		*mqttPassword = envMQTTPassword
+	}
+	if envUser := os.Getenv("AUTH_USER"); envUser != "" {
+		*authUser = envUser
+	}
+	if envPassword := os.Getenv("AUTH_PASSWORD"); envPassword != "" {
+		*authPassword = envPassword
+	}
+	if envJWTSecret := os.Getenv("JWT_SECRET"); envJWTSecret != "" {
+		*jwtSecret = envJWTSecret
+	}
+	if envJWTKey := os.Getenv("JWT_PUBLIC_KEY_FILE"); envJWTKey != "" {
+		*jwtPublicKey = envJWTKey
+	}
+	if envJWTAudience := os.Getenv("JWT_AUDIENCE"); envJWTAudience != "" {
+		*jwtAudience = envJWTAudience
+	}
+	if envMax := os.Getenv("MAX_SESSIONS"); envMax != "" {
+		if n, err := strconv.Atoi(envMax); err == nil {
+			*maxSessions = n
+		}
+	}
+	if envLimit := os.Getenv("SESSION_LIMIT"); envLimit != "" {
+		if n, err := strconv.Atoi(envLimit); err == nil {
+			*sessionLimit = n
+		}
+	}
+	if envBandwidth := os.Getenv("MAX_BANDWIDTH_MBPS"); envBandwidth != "" {
+		if n, err := strconv.Atoi(envBandwidth); err == nil {
+			*maxBandwidth = n
+		}
+	}
+	if envBitrate := os.Getenv("MAX_BITRATE_KBPS"); envBitrate != "" {
+		if n, err := strconv.Atoi(envBitrate); err == nil {
+			*maxBitrate = n
+		}
+	}
+	if envFrameRate := os.Getenv("MAX_FRAME_RATE"); envFrameRate != "" {
+		if n, err := strconv.Atoi(envFrameRate); err == nil {
+			*maxFrameRate = n
+		}
+	}
+	if envCodec := os.Getenv("VIDEO_CODEC"); envCodec != "" {
+		*videoCodec = envCodec
+	}
+	if envForceCodec := os.Getenv("FORCE_VIDEO_CODEC"); envForceCodec != "" {
+		*forceVideoCodec = envForceCodec == "true"
+	}
+	if envWarning := os.Getenv("LIMIT_WARNING_PERCENT"); envWarning != "" {
+		if n, err := strconv.Atoi(envWarning); err == nil {
+			*limitWarning = n
+		}
+	}
+	if envBuffer := os.Getenv("EVENT_BUFFER"); envBuffer != "" {
+		if n, err := strconv.Atoi(envBuffer); err == nil {
+			*eventBuffer = n
+		}
+	}
+	if envPolicy := os.Getenv("EVENT_POLICY"); envPolicy != "" {
+		*eventPolicy = envPolicy
+	}
+	if envFormat := os.Getenv("TOKEN_FORMAT"); envFormat != "" {
+		*tokenFormat = envFormat
+	}
+	if envBytes := os.Getenv("TOKEN_BYTES"); envBytes != "" {
+		if n, err := strconv.Atoi(envBytes); err == nil {
+			*tokenBytes = n
+		}
+	}
+	if envTokenSecret := os.Getenv("TOKEN_SECRET"); envTokenSecret != "" {
+		*tokenSecret = envTokenSecret
+	}
+	if envLifetime := os.Getenv("TOKEN_LIFETIME"); envLifetime != "" {
+		if duration, err := time.ParseDuration(envLifetime); err == nil {
+			*tokenLifetime = duration
+		}
+	}
+	if envFileRelay := os.Getenv("FILE_RELAY_MB"); envFileRelay != "" {
+		if n, err := strconv.Atoi(envFileRelay); err == nil {
+			*fileRelay = n
+		}
+	}
+	if envSFU := os.Getenv("SFU"); envSFU != "" {
+		*sfu = envSFU == "true"
+	}
+	if envSFUPort := os.Getenv("SFU_PORT"); envSFUPort != "" {
+		if n, err := strconv.Atoi(envSFUPort); err == nil {
+			*sfuPort = n
+		}
+	}
+	if envRTMPURL := os.Getenv("RTMP_URL"); envRTMPURL != "" {
+		*rtmpURL = envRTMPURL
+	}
+	if envHLSDir := os.Getenv("HLS_DIR"); envHLSDir != "" {
+		*hlsDir = envHLSDir
+	}
+	if envRecordingsDir := os.Getenv("RECORDINGS_DIR"); envRecordingsDir != "" {
+		*recordingsDir = envRecordingsDir
+	}
+	if envRecordingsMax := os.Getenv("RECORDINGS_MAX_MB"); envRecordingsMax != "" {
+		if n, err := strconv.Atoi(envRecordingsMax); err == nil {
+			*recordingsMax = n
+		}
+	}
+	if envRetention := os.Getenv("RECORDINGS_RETENTION"); envRetention != "" {
+		if duration, err := time.ParseDuration(envRetention); err == nil {
+			*recordingsRetention = duration
+		}
+	}
+	if envSlack := os.Getenv("SLACK_WEBHOOK_URL"); envSlack != "" {
+		*slackWebhook = envSlack
+	}
+	if envDiscord := os.Getenv("DISCORD_WEBHOOK_URL"); envDiscord != "" {
+		*discordWebhook = envDiscord
+	}
+	if envProfileSecret := os.Getenv("PROFILE_SECRET"); envProfileSecret != "" {
+		*profileSecret = envProfileSecret
+	}
+	if envProfiles, ok := os.LookupEnv("PROFILES_FILE"); ok {
+		*profilesFile = envProfiles
+	}
 	// Certificate paths are hardcoded for production deployment
 	*certFile = "/certs/fullchain.pem"
 	*keyFile = "/certs/privkey.pem"
@@ -56,10 +695,167 @@ func LoadConfig() *Config {
 		Port:        *port,
 		STUNServer:  *stunServer,
 		TokenExpiry: *tokenExpiry,
-		EnableHTTPS: *enableHTTPS,
+		// Let's Encrypt certificates replace the certificate files
+		EnableHTTPS: *enableHTTPS || *httpsAuto,
 		CertFile:    *certFile,
 		KeyFile:     *keyFile,
+		LinkPreview: *linkPreview,
+		ICEServers:  *iceServers,
+
+		BindAddresses: splitList(*bind),
+		Interface:     *iface,
+		MDNS:          *advertise,
+
+		ExternalURL:      strings.TrimSuffix(*externalURL, "/"),
+		DiscoverPublicIP: *discoverPublicIP,
+
+		Tunnel:           *tunnel,
+		TunnelURL:        *tunnelURL,
+		TunnelRemote:     *tunnelRemote,
+		TunnelKeyFile:    *tunnelKeyFile,
+		TunnelKnownHosts: *tunnelKnownHosts,
+
+		HTTPRedirectPort: *httpRedirectPort,
+		HTTPSAuto:        *httpsAuto,
+		AutocertDomains:  splitList(*autocertDomains),
+		AutocertCacheDir: *autocertCacheDir,
+		AutocertEmail:    *autocertEmail,
+
+		TURNSecret:        *turnSecret,
+		TURNCredentialTTL: *turnTTL,
+
+		HeartbeatTimeout: *heartbeatTimeout,
+		IdleTimeout:      *idleTimeout,
+		GCInterval:       *gcInterval,
+		ShutdownTimeout:  *shutdownTimeout,
+		CORSOrigins:      splitList(*corsOrigins),
+		RequestLog:       *requestLog,
+		Middleware:       splitList(*middleware),
+		RateLimit:        *rateLimit,
+		RateLimitBurst:   *rateLimitBurst,
+		WebDir:           *webDir,
+		DevMode:          *devMode,
+		BrandName:        *brandName,
+		BrandLogo:        *brandLogo,
+		BrandAccent:      *brandAccent,
+		BrandFooter:      *brandFooter,
+		AllowedNetworks:  splitList(*allowedNetworks),
+		StatusToken:      *statusToken,
+		AdminToken:       *adminToken,
+		BanFile:          *banFile,
+		TemplatesFile:    *templatesFile,
+		DevicesFile:      *devicesFile,
+		SnapshotFile:     *snapshotFile,
+		SnapshotInterval: *snapshotInterval,
+		StorageBackend:   *storageBackend,
+		BoltPath:         *boltPath,
+		PostgresDSN:      *postgresDSN,
+		PostgresMaxConns: *postgresMaxConns,
+		Cluster:          *cluster,
+		InstanceID:       *instanceID,
+		GRPCAddr:         *grpcAddr,
+		MQTTBroker:       *mqttBroker,
+		MQTTPrefix:       *mqttPrefix,
+		MQTTUsername:     *mqttUsername,
+		MQTTPassword:     *mqttPassword,
+		AuthUser:         *authUser,
+		AuthPassword:     *authPassword,
+		JWTSecret:        *jwtSecret,
+		JWTPublicKeyFile: *jwtPublicKey,
+		JWTAudience:      *jwtAudience,
+
+		MaxSessions:         *maxSessions,
+		MaxBandwidthMbps:    *maxBandwidth,
+		MaxBitrateKbps:      *maxBitrate,
+		MaxFrameRate:        *maxFrameRate,
+		LimitWarningPercent: *limitWarning,
+		SessionLimit:        *sessionLimit,
+
+		VideoCodec:      *videoCodec,
+		ForceVideoCodec: *forceVideoCodec,
+
+		TokenFormat:   *tokenFormat,
+		TokenBytes:    *tokenBytes,
+		TokenSecret:   *tokenSecret,
+		TokenLifetime: *tokenLifetime,
+
+		EventBuffer: *eventBuffer,
+		EventPolicy: *eventPolicy,
+
+		FileRelayMB: *fileRelay,
+
+		SFU:     *sfu,
+		SFUPort: *sfuPort,
+		RTMPURL: *rtmpURL,
+		HLSDir:  *hlsDir,
+
+		RecordingsDir:       *recordingsDir,
+		RecordingsMaxMB:     *recordingsMax,
+		RecordingsRetention: *recordingsRetention,
+
+		SlackWebhookURL:   *slackWebhook,
+		DiscordWebhookURL: *discordWebhook,
+
+		ProfileSecret: *profileSecret,
+		ProfilesFile:  *profilesFile,
+	}
+}
+
+// ListenAddresses returns the addresses the server listens on: each bind
+// address, with PORT unless it names its own port, or PORT on every interface
+func (c *Config) ListenAddresses() []string {
+	if len(c.BindAddresses) == 0 {
+		return []string{":" + c.Port}
+	}
+	addresses := make([]string, 0, len(c.BindAddresses))
+	for _, address := range c.BindAddresses {
+		if _, _, err := net.SplitHostPort(address); err == nil {
+			addresses = append(addresses, address)
+		} else {
+			addresses = append(addresses, net.JoinHostPort(bindHost(address), c.Port))
+		}
+	}
+	return addresses
+}
+
+// RedirectAddresses returns the addresses the HTTP to HTTPS redirect listens
+// on: HTTPRedirectPort on the hosts of the bind addresses, or on every
+// interface
+func (c *Config) RedirectAddresses() []string {
+	if len(c.BindAddresses) == 0 {
+		return []string{":" + c.HTTPRedirectPort}
+	}
+	addresses := make([]string, 0, len(c.BindAddresses))
+	for _, address := range c.BindAddresses {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = bindHost(address)
+		}
+		addresses = append(addresses, net.JoinHostPort(host, c.HTTPRedirectPort))
+	}
+	return addresses
+}
+
+// bindHost returns a bind address given without a port as a bare host,
+// dropping the brackets of an IPv6 literal such as [::1]
+func bindHost(address string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(address, "["), "]")
+}
+
+// JWTEnabled reports whether API clients must authenticate with JWTs
+func (c *Config) JWTEnabled() bool {
+	return c.JWTSecret != "" || c.JWTPublicKeyFile != ""
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
 
 // loadEnv loads environment variables from .env file
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConfig_ListenAddresses(t *testing.T) {
+	tests := []struct {
+		name              string
+		bind              []string
+		expectedListen    []string
+		expectedRedirects []string
+	}{
+		{
+			name:              "every interface",
+			expectedListen:    []string{":8443"},
+			expectedRedirects: []string{":8080"},
+		},
+		{
+			name:              "hosts take the port",
+			bind:              []string{"127.0.0.1", "192.168.1.10"},
+			expectedListen:    []string{"127.0.0.1:8443", "192.168.1.10:8443"},
+			expectedRedirects: []string{"127.0.0.1:8080", "192.168.1.10:8080"},
+		},
+		{
+			name:              "own port kept",
+			bind:              []string{"127.0.0.1:9000", "share.lan"},
+			expectedListen:    []string{"127.0.0.1:9000", "share.lan:8443"},
+			expectedRedirects: []string{"127.0.0.1:8080", "share.lan:8080"},
+		},
+		{
+			name:              "IPv6",
+			bind:              []string{"[::1]", "[fe80::1%eth0]:9000"},
+			expectedListen:    []string{"[::1]:8443", "[fe80::1%eth0]:9000"},
+			expectedRedirects: []string{"[::1]:8080", "[fe80::1%eth0]:8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Port: "8443", HTTPRedirectPort: "8080", BindAddresses: tt.bind}
+
+			if listen := cfg.ListenAddresses(); !reflect.DeepEqual(listen, tt.expectedListen) {
+				t.Errorf("Expected to listen on %v, got %v", tt.expectedListen, listen)
+			}
+			if redirects := cfg.RedirectAddresses(); !reflect.DeepEqual(redirects, tt.expectedRedirects) {
+				t.Errorf("Expected redirects on %v, got %v", tt.expectedRedirects, redirects)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// ParseICEServers reads the ICE_SERVERS setting: a JSON array of
+// RTCIceServer-style entries, or the path of a file holding one. Without it
+// peers get the single stunServer, if any. With mintCredentials, TURN entries
+// may leave out their credentials to have short-lived ones minted per session.
+func ParseICEServers(value, stunServer string, mintCredentials bool) ([]entities.ICEServer, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if stunServer == "" {
+			return nil, nil
+		}
+		return []entities.ICEServer{{URLs: []string{stunServer}}}, nil
+	}
+
+	data := []byte(value)
+	if !strings.HasPrefix(value, "[") {
+		var err error
+		if data, err = os.ReadFile(value); err != nil {
+			return nil, err
+		}
+	}
+
+	var servers []entities.ICEServer
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("ICE servers must be a JSON array of {urls, username, credential}: %w", err)
+	}
+	for _, server := range servers {
+		if server.NeedsCredentials() {
+			if !mintCredentials {
+				return nil, fmt.Errorf("TURN server %s needs a username and credential, or TURN_SECRET to mint them", server.URLs[0])
+			}
+			continue
+		}
+		if err := server.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return servers, nil
+}
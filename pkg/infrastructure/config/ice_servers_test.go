@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestParseICEServers(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "ice.json")
+	if err := os.WriteFile(file, []byte(`[{"urls":["turn:turn.example.com:3478"],"username":"user","credential":"turn-pass"}]`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		value       string
+		stunServer  string
+		mint        bool
+		expected    []entities.ICEServer
+		expectError bool
+	}{
+		{
+			name:       "STUN server alone",
+			stunServer: "stun:stun.l.google.com:19302",
+			expected:   []entities.ICEServer{{URLs: []string{"stun:stun.l.google.com:19302"}}},
+		},
+		{
+			name:     "nothing configured",
+			expected: nil,
+		},
+		{
+			name:       "JSON list replaces the STUN server",
+			value:      `[{"urls":["stun:a.example.com"]},{"urls":["stun:b.example.com"]}]`,
+			stunServer: "stun:stun.l.google.com:19302",
+			expected: []entities.ICEServer{
+				{URLs: []string{"stun:a.example.com"}},
+				{URLs: []string{"stun:b.example.com"}},
+			},
+		},
+		{
+			name:  "file",
+			value: file,
+			expected: []entities.ICEServer{
+				{URLs: []string{"turn:turn.example.com:3478"}, Username: "user", Credential: "turn-pass"},
+			},
+		},
+		{
+			name:        "missing file",
+			value:       filepath.Join(t.TempDir(), "missing.json"),
+			expectError: true,
+		},
+		{
+			name:        "invalid JSON",
+			value:       `[{"urls":`,
+			expectError: true,
+		},
+		{
+			name:        "TURN without credentials",
+			value:       `[{"urls":["turn:turn.example.com:3478"]}]`,
+			expectError: true,
+		},
+		{
+			name:     "TURN without credentials minted from a secret",
+			value:    `[{"urls":["turn:turn.example.com:3478"]}]`,
+			mint:     true,
+			expected: []entities.ICEServer{{URLs: []string{"turn:turn.example.com:3478"}}},
+		},
+		{
+			name:        "TURN with half its credentials",
+			value:       `[{"urls":["turn:turn.example.com:3478"],"username":"user"}]`,
+			mint:        true,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := ParseICEServers(tt.value, tt.stunServer, tt.mint)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ParseICEServers() error = %v, expectError %v", err, tt.expectError)
+			}
+			if !tt.expectError && !reflect.DeepEqual(servers, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, servers)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package events
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+const (
+	// DefaultBufferSize is how many undelivered events a subscriber may hold
+	// before the slow-client policy applies
+	DefaultBufferSize = 16
+
+	// slowClientDrops is how many events in a row a subscriber may lose under
+	// a drop policy before it is treated as stalled and closed
+	slowClientDrops = 64
+)
+
+// Policy decides what happens when a subscriber's buffer is full
+type Policy string
+
+const (
+	// PolicyDropOldest discards the oldest buffered event to make room, so a
+	// lagging client still ends up with the latest state
+	PolicyDropOldest Policy = "drop-oldest"
+
+	// PolicyDropNewest discards the event that does not fit
+	PolicyDropNewest Policy = "drop-newest"
+
+	// PolicyClose ends the subscription; SSE clients reconnect and receive
+	// the current state afresh
+	PolicyClose Policy = "close"
+)
+
+// ParsePolicy validates a policy name from configuration
+func ParsePolicy(name string) (Policy, error) {
+	switch policy := Policy(name); policy {
+	case PolicyDropOldest, PolicyDropNewest, PolicyClose:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown slow client policy %q", name)
+	}
+}
+
+// subscriber is one client's buffered subscription
+type subscriber struct {
+	ch     chan entities.Event
+	topics []string
+	closed bool // guarded by Broker.mu
+
+	// dropped counts events lost in a row since the client last kept up
+	dropped atomic.Int64
+}
+
+// Broker implements EventPublisher with in-process topic subscriptions. Each
+// subscriber has its own buffer, so a stalled client loses its own events
+// (or its subscription) without delaying delivery to anyone else.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscriber]struct{}
+	count       int
+	closed      bool
+
+	bufferSize int
+	policy     Policy
+
+	published  atomic.Int64
+	delivered  atomic.Int64
+	dropped    atomic.Int64
+	slowClosed atomic.Int64
+}
+
+// NewBroker creates a new event broker; bufferSize is each subscriber's
+// buffer (DefaultBufferSize when not positive) and policy what to do when it
+// fills up
+func NewBroker(bufferSize int, policy Policy) interfaces.EventPublisher {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Broker{
+		subscribers: make(map[string]map[*subscriber]struct{}),
+		bufferSize:  bufferSize,
+		policy:      policy,
+	}
+}
+
+// Subscribe registers for events on the given topics. The returned channel is
+// closed when the broker shuts down or drops the subscriber as too slow; call
+// cancel to unsubscribe.
+func (b *Broker) Subscribe(topics ...string) (<-chan entities.Event, func()) {
+	sub := &subscriber{ch: make(chan entities.Event, b.bufferSize), topics: topics}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	for _, topic := range topics {
+		if b.subscribers[topic] == nil {
+			b.subscribers[topic] = make(map[*subscriber]struct{})
+		}
+		b.subscribers[topic][sub] = struct{}{}
+	}
+	b.count++
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.remove(sub)
+		})
+	}
+
+	return sub.ch, cancel
+}
+
+// Publish sends an event to all subscribers of a topic without blocking;
+// subscribers that cannot keep up are handled by the broker's policy
+func (b *Broker) Publish(topic string, event entities.Event) {
+	b.published.Add(1)
+
+	var slow []*subscriber
+	b.mu.RLock()
+	for sub := range b.subscribers[topic] {
+		if !b.deliver(sub, event) {
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range slow {
+		if !sub.closed {
+			b.slowClosed.Add(1)
+			b.remove(sub)
+		}
+	}
+}
+
+// deliver buffers an event for a subscriber, applying the policy when the
+// buffer is full; it reports false when the subscriber should be closed
+func (b *Broker) deliver(sub *subscriber, event entities.Event) bool {
+	select {
+	case sub.ch <- event:
+		b.delivered.Add(1)
+		sub.dropped.Store(0)
+		return true
+	default:
+	}
+
+	b.dropped.Add(1)
+	switch b.policy {
+	case PolicyClose:
+		return false
+	case PolicyDropOldest:
+		select {
+		case <-sub.ch:
+		default:
+		}
+		select {
+		case sub.ch <- event:
+			b.delivered.Add(1)
+		default:
+		}
+	}
+	return sub.dropped.Add(1) < slowClientDrops
+}
+
+// remove unsubscribes a subscriber and closes its channel; b.mu must be held
+func (b *Broker) remove(sub *subscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	for _, topic := range sub.topics {
+		delete(b.subscribers[topic], sub)
+		if len(b.subscribers[topic]) == 0 {
+			delete(b.subscribers, topic)
+		}
+	}
+	b.count--
+	close(sub.ch)
+}
+
+// Metrics reports delivery counters since the broker started
+func (b *Broker) Metrics() entities.EventMetrics {
+	b.mu.RLock()
+	subscribers := b.count
+	b.mu.RUnlock()
+
+	return entities.EventMetrics{
+		Subscribers: subscribers,
+		BufferSize:  b.bufferSize,
+		Policy:      string(b.policy),
+		Published:   b.published.Load(),
+		Delivered:   b.delivered.Load(),
+		Dropped:     b.dropped.Load(),
+		SlowClosed:  b.slowClosed.Load(),
+	}
+}
+
+// Close notifies every subscriber that the server is shutting down and ends
+// their subscriptions
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+
+	for _, subscribers := range b.subscribers {
+		for sub := range subscribers {
+			if sub.closed {
+				continue
+			}
+			select {
+			case sub.ch <- entities.Event{Type: entities.EventServerShutdown}:
+			default:
+			}
+			b.remove(sub)
+		}
+	}
+	b.subscribers = make(map[string]map[*subscriber]struct{})
+}
@@ -0,0 +1,160 @@
+package events
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestBroker_PublishSubscribe(t *testing.T) {
+	broker := NewBroker(DefaultBufferSize, PolicyDropOldest).(*Broker)
+
+	events, cancel := broker.Subscribe("session", "session/viewer")
+	defer cancel()
+
+	broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+	broker.Publish("session/viewer", entities.Event{Type: entities.EventQueuePosition})
+	broker.Publish("other", entities.Event{Type: entities.EventViewerLeft})
+
+	for _, expected := range []string{entities.EventQueueChanged, entities.EventQueuePosition} {
+		event := <-events
+		if event.Type != expected {
+			t.Errorf("Expected event %q but got %q", expected, event.Type)
+		}
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("Unexpected event %q from unsubscribed topic", event.Type)
+	default:
+	}
+}
+
+func TestBroker_Cancel(t *testing.T) {
+	broker := NewBroker(DefaultBufferSize, PolicyDropOldest).(*Broker)
+
+	events, cancel := broker.Subscribe("session")
+	cancel()
+	cancel() // cancelling twice is safe
+
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after cancel")
+	}
+
+	// Publishing after cancel must not panic
+	broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+}
+
+func TestBroker_SlowSubscriberDoesNotBlock(t *testing.T) {
+	broker := NewBroker(DefaultBufferSize, PolicyDropOldest).(*Broker)
+
+	_, cancel := broker.Subscribe("session")
+	defer cancel()
+
+	for i := 0; i < DefaultBufferSize*2; i++ {
+		broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker(DefaultBufferSize, PolicyDropOldest).(*Broker)
+
+	events, cancel := broker.Subscribe("session", "session/viewer")
+	broker.Close()
+	cancel() // cancelling after close is safe
+
+	event, ok := <-events
+	if !ok || event.Type != entities.EventServerShutdown {
+		t.Errorf("Expected shutdown event but got %+v (open: %v)", event, ok)
+	}
+	if _, ok := <-events; ok {
+		t.Error("Expected channel to be closed after shutdown")
+	}
+
+	late, _ := broker.Subscribe("session")
+	if _, ok := <-late; ok {
+		t.Error("Expected subscription after close to be closed")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, name := range []string{"drop-oldest", "drop-newest", "close"} {
+		if policy, err := ParsePolicy(name); err != nil || string(policy) != name {
+			t.Errorf("Expected policy %q, got %q (%v)", name, policy, err)
+		}
+	}
+	if _, err := ParsePolicy("block"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
+
+func TestBroker_Policies(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        Policy
+		expectedFirst int
+		expectClosed  bool
+	}{
+		{name: "drop oldest keeps the latest events", policy: PolicyDropOldest, expectedFirst: 2},
+		{name: "drop newest keeps the earliest events", policy: PolicyDropNewest, expectedFirst: 0},
+		{name: "close ends the subscription", policy: PolicyClose, expectedFirst: 0, expectClosed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := NewBroker(2, tt.policy).(*Broker)
+			events, cancel := broker.Subscribe("session")
+			defer cancel()
+
+			// A healthy subscriber on the same topic is unaffected
+			healthy, cancelHealthy := broker.Subscribe("session")
+			defer cancelHealthy()
+
+			for i := 0; i < 4; i++ {
+				broker.Publish("session", entities.Event{Type: entities.EventQueueChanged, Data: i})
+				<-healthy
+			}
+
+			first := <-events
+			if first.Data != tt.expectedFirst {
+				t.Errorf("Expected first buffered event %d, got %v", tt.expectedFirst, first.Data)
+			}
+
+			<-events
+			open := true
+			select {
+			case _, open = <-events:
+			default:
+			}
+			if tt.expectClosed == open {
+				t.Errorf("Expected closed %v, got open %v", tt.expectClosed, open)
+			}
+
+			metrics := broker.Metrics()
+			if metrics.Published != 4 || metrics.Dropped == 0 || metrics.Policy != string(tt.policy) {
+				t.Errorf("Unexpected metrics %+v", metrics)
+			}
+			if tt.expectClosed && (metrics.SlowClosed != 1 || metrics.Subscribers != 1) {
+				t.Errorf("Expected one slow client closed, got %+v", metrics)
+			}
+		})
+	}
+}
+
+func TestBroker_StalledSubscriberClosed(t *testing.T) {
+	broker := NewBroker(1, PolicyDropNewest).(*Broker)
+	events, cancel := broker.Subscribe("session")
+	defer cancel()
+
+	for i := 0; i < slowClientDrops+1; i++ {
+		broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+	}
+
+	<-events
+	if _, open := <-events; open {
+		t.Error("Expected a stalled subscriber to be closed")
+	}
+	if metrics := broker.Metrics(); metrics.SlowClosed != 1 || metrics.Subscribers != 0 {
+		t.Errorf("Unexpected metrics %+v", metrics)
+	}
+}
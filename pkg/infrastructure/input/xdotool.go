@@ -0,0 +1,167 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/control"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// keysyms maps the DOM names of the named control keys to X keysyms
+var keysyms = map[string]string{
+	"Enter":      "Return",
+	"Backspace":  "BackSpace",
+	"Tab":        "Tab",
+	"Escape":     "Escape",
+	"Delete":     "Delete",
+	"ArrowUp":    "Up",
+	"ArrowDown":  "Down",
+	"ArrowLeft":  "Left",
+	"ArrowRight": "Right",
+	"Home":       "Home",
+	"End":        "End",
+	"PageUp":     "Page_Up",
+	"PageDown":   "Page_Down",
+	"Shift":      "shift",
+	"Control":    "ctrl",
+	"Alt":        "alt",
+	"Meta":       "super",
+}
+
+// XdotoolInjector implements the InputInjector interface by running xdotool,
+// which drives the pointer and keyboard of the X11 display in $DISPLAY
+type XdotoolInjector struct {
+	binary string
+
+	mu     sync.Mutex
+	width  int
+	height int
+}
+
+// NewXdotoolInjector creates a new xdotool input injector; binary is the
+// xdotool executable
+func NewXdotoolInjector(binary string) interfaces.InputInjector {
+	if binary == "" {
+		binary = "xdotool"
+	}
+	return &XdotoolInjector{binary: binary}
+}
+
+// Supported reports whether xdotool can inject input on goos
+func Supported(goos string) bool {
+	switch goos {
+	case "linux", "freebsd", "openbsd":
+		return true
+	}
+	return false
+}
+
+// Inject replays one event on the display
+func (x *XdotoolInjector) Inject(event control.Event) error {
+	width, height, err := x.screenSize()
+	if err != nil {
+		return err
+	}
+
+	args := injectArgs(event, width, height)
+	if len(args) == 0 {
+		return nil
+	}
+	if output, err := exec.Command(x.binary, args...).CombinedOutput(); err != nil {
+		if message := strings.TrimSpace(string(output)); message != "" {
+			return fmt.Errorf("%s %s: %s", x.binary, args[0], message)
+		}
+		return fmt.Errorf("%s %s: %w", x.binary, args[0], err)
+	}
+	return nil
+}
+
+// screenSize asks xdotool for the display size once and remembers it
+func (x *XdotoolInjector) screenSize() (int, int, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.width > 0 {
+		return x.width, x.height, nil
+	}
+
+	output, err := exec.Command(x.binary, "getdisplaygeometry").Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading display size with %s: %w", x.binary, err)
+	}
+	width, height, err := parseGeometry(string(output))
+	if err != nil {
+		return 0, 0, err
+	}
+	x.width, x.height = width, height
+	return width, height, nil
+}
+
+// parseGeometry reads the "<width> <height>" printed by xdotool getdisplaygeometry
+func parseGeometry(output string) (int, int, error) {
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected display size %q", strings.TrimSpace(output))
+	}
+	width, err := strconv.Atoi(fields[0])
+	if err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf("unexpected display width %q", fields[0])
+	}
+	height, err := strconv.Atoi(fields[1])
+	if err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf("unexpected display height %q", fields[1])
+	}
+	return width, height, nil
+}
+
+// injectArgs builds the xdotool command line replaying event on a screen of
+// width by height pixels; it is empty for events with nothing to replay
+func injectArgs(event control.Event, width, height int) []string {
+	x := strconv.Itoa(int(math.Round(event.X * float64(width-1))))
+	y := strconv.Itoa(int(math.Round(event.Y * float64(height-1))))
+	// X numbers mouse buttons from 1, the DOM from 0
+	button := strconv.Itoa(event.Button + 1)
+
+	switch event.Type {
+	case control.EventPointerMove:
+		return []string{"mousemove", x, y}
+	case control.EventPointerDown:
+		return []string{"mousemove", x, y, "mousedown", button}
+	case control.EventPointerUp:
+		return []string{"mousemove", x, y, "mouseup", button}
+	case control.EventWheel:
+		args := []string{"mousemove", x, y}
+		// Buttons 4 to 7 scroll up, down, left and right
+		args = append(args, wheelClicks(event.DeltaY, "4", "5")...)
+		return append(args, wheelClicks(event.DeltaX, "6", "7")...)
+	case control.EventKeyDown:
+		if event.IsPrintable() {
+			// Typing the character works for any layout and symbol
+			return []string{"type", "--", event.Key}
+		}
+		return []string{"keydown", keysyms[event.Key]}
+	case control.EventKeyUp:
+		if event.IsPrintable() {
+			// Typing already released the key
+			return nil
+		}
+		return []string{"keyup", keysyms[event.Key]}
+	}
+	return nil
+}
+
+// wheelClicks scrolls steps notches with the back or forward wheel button
+func wheelClicks(steps int, back, forward string) []string {
+	button := forward
+	if steps < 0 {
+		button, steps = back, -steps
+	}
+	if steps == 0 {
+		return nil
+	}
+	return []string{"click", "--repeat", strconv.Itoa(steps), button}
+}
@@ -0,0 +1,97 @@
+package input
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/control"
+)
+
+func TestInjectArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    control.Event
+		expected []string
+	}{
+		{
+			name:     "pointer move to the centre",
+			event:    control.Event{Type: control.EventPointerMove, X: 0.5, Y: 0.5},
+			expected: []string{"mousemove", "960", "540"},
+		},
+		{
+			name:     "right button down in the corner",
+			event:    control.Event{Type: control.EventPointerDown, X: 1, Y: 1, Button: control.ButtonRight},
+			expected: []string{"mousemove", "1919", "1079", "mousedown", "3"},
+		},
+		{
+			name:     "left button up",
+			event:    control.Event{Type: control.EventPointerUp},
+			expected: []string{"mousemove", "0", "0", "mouseup", "1"},
+		},
+		{
+			name:     "scroll up and right",
+			event:    control.Event{Type: control.EventWheel, DeltaY: -2, DeltaX: 1},
+			expected: []string{"mousemove", "0", "0", "click", "--repeat", "2", "4", "click", "--repeat", "1", "7"},
+		},
+		{
+			name:     "printable key down types it",
+			event:    control.Event{Type: control.EventKeyDown, Key: "-"},
+			expected: []string{"type", "--", "-"},
+		},
+		{
+			name:  "printable key up does nothing",
+			event: control.Event{Type: control.EventKeyUp, Key: "a"},
+		},
+		{
+			name:     "named key",
+			event:    control.Event{Type: control.EventKeyDown, Key: "Enter"},
+			expected: []string{"keydown", "Return"},
+		},
+		{
+			name:     "modifier release",
+			event:    control.Event{Type: control.EventKeyUp, Key: "Control"},
+			expected: []string{"keyup", "ctrl"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := injectArgs(tt.event, 1920, 1080)
+			if !slices.Equal(args, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, args)
+			}
+		})
+	}
+}
+
+func TestParseGeometry(t *testing.T) {
+	width, height, err := parseGeometry("2560 1440\n")
+	if err != nil || width != 2560 || height != 1440 {
+		t.Errorf("Expected 2560x1440, got %dx%d (%v)", width, height, err)
+	}
+
+	for _, output := range []string{"", "2560", "wide 1440", "2560 0"} {
+		if _, _, err := parseGeometry(output); err == nil {
+			t.Errorf("Expected error for %q", output)
+		}
+	}
+}
+
+func TestXdotoolInjector_MissingBinary(t *testing.T) {
+	injector := NewXdotoolInjector("/nonexistent/xdotool")
+
+	err := injector.Inject(control.Event{Type: control.EventPointerMove, X: 0.5, Y: 0.5})
+	if err == nil || !strings.Contains(err.Error(), "/nonexistent/xdotool") {
+		t.Errorf("Expected error naming the missing binary, got %v", err)
+	}
+}
+
+func TestSupported(t *testing.T) {
+	if !Supported("linux") {
+		t.Error("Expected xdotool to be supported on linux")
+	}
+	if Supported("windows") {
+		t.Error("Expected xdotool to be unsupported on windows")
+	}
+}
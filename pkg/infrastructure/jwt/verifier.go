@@ -0,0 +1,233 @@
+// Package jwt verifies the JSON Web Tokens API clients send as bearer tokens.
+// Tokens are signed with HS256 by a secret shared with their issuer, or with
+// RS256 by the issuer's private key, whose public key the server is given.
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, badly signed,
+// expired or meant for another audience
+var ErrInvalidToken = errors.New("invalid token")
+
+// maxTokenSize bounds the tokens the verifier decodes
+const maxTokenSize = 8 << 10
+
+// clockSkew is how far the issuer's clock may be off from the server's
+const clockSkew = 30 * time.Second
+
+// minRSAKeyBits is the smallest RSA key accepted for RS256
+const minRSAKeyBits = 2048
+
+// Verifier checks the signature and registered claims of tokens
+type Verifier struct {
+	hmacKey   []byte
+	publicKey *rsa.PublicKey
+	audience  string
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier accepting HS256 tokens signed with hmacKey
+// and RS256 tokens signed by publicKey's private key; either may be empty to
+// refuse that algorithm. A non-empty audience must be among a token's "aud".
+func NewVerifier(hmacKey []byte, publicKey *rsa.PublicKey, audience string) interfaces.TokenVerifier {
+	return &Verifier{
+		hmacKey:   hmacKey,
+		publicKey: publicKey,
+		audience:  audience,
+		now:       time.Now,
+	}
+}
+
+// header is the JOSE header of a token
+type header struct {
+	Algorithm string `json:"alg"`
+}
+
+// claims are the claims of a token the server reads
+type claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt float64  `json:"exp"`
+	NotBefore float64  `json:"nbf"`
+	Name      string   `json:"name"`
+	Email     string   `json:"email"`
+
+	// Scope lists the token's scopes separated by spaces, as in OAuth 2.0
+	Scope string `json:"scope"`
+}
+
+// audience is the "aud" claim, which is a single string or an array of them
+type audience []string
+
+// UnmarshalJSON accepts both forms of the claim
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Verify checks the token's signature, expiry and audience and returns the
+// user it was issued to. Tokens must expire and name their subject.
+func (v *Verifier) Verify(token string) (*entities.UserIdentity, error) {
+	if len(token) > maxTokenSize {
+		return nil, fmt.Errorf("%w: token of %d bytes is too large", ErrInvalidToken, len(token))
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: not a signed JWT", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+	if err := v.verifySignature(h.Algorithm, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.checkClaims(&c); err != nil {
+		return nil, err
+	}
+
+	return &entities.UserIdentity{
+		Subject: c.Subject,
+		Issuer:  c.Issuer,
+		Name:    c.Name,
+		Email:   c.Email,
+		Scopes:  strings.Fields(c.Scope),
+	}, nil
+}
+
+// verifySignature checks the signature over the token's header and claims
+// with the key for its algorithm; "none" and unconfigured algorithms fail
+func (v *Verifier) verifySignature(algorithm, signed string, signature []byte) error {
+	switch {
+	case algorithm == "HS256" && len(v.hmacKey) > 0:
+		mac := hmac.New(sha256.New, v.hmacKey)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	case algorithm == "RS256" && v.publicKey != nil:
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: algorithm %q is not accepted", ErrInvalidToken, algorithm)
+	}
+}
+
+// checkClaims checks the registered claims against the current time and the
+// configured audience
+func (v *Verifier) checkClaims(c *claims) error {
+	now := v.now()
+	if c.Subject == "" {
+		return fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("%w: no expiry", ErrInvalidToken)
+	}
+	if now.Add(-clockSkew).After(unixTime(c.ExpiresAt)) {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if c.NotBefore != 0 && now.Add(clockSkew).Before(unixTime(c.NotBefore)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.audience != "" && !slices.Contains(c.Audience, v.audience) {
+		return fmt.Errorf("%w: meant for another audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a token into out
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
+
+// unixTime converts a NumericDate claim, which may have a fraction, to a time
+func unixTime(seconds float64) time.Time {
+	return time.UnixMilli(int64(seconds * 1000))
+}
+
+// LoadPublicKey reads the RSA public key RS256 tokens are checked with from
+// a PEM file holding a PKIX or PKCS #1 public key or a certificate
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s holds no PEM block", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var certificate *x509.Certificate
+		if certificate, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = certificate.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%s holds a %s, not a public key", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not hold an RSA key", path)
+	}
+	if rsaKey.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("%s holds a %d-bit key; at least %d bits are needed", path, rsaKey.N.BitLen(), minRSAKeyBits)
+	}
+	return rsaKey, nil
+}
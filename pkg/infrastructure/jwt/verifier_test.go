@@ -0,0 +1,178 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// testNow is the time the verifier under test runs at
+var testNow = time.Unix(1700000000, 0)
+
+// testHMACKey signs the HS256 tokens of the tests
+var testHMACKey = []byte("0123456789abcdef0123456789abcdef")
+
+// sign builds a token with the given header algorithm and claims, signed
+// with the HMAC key or the RSA key
+func sign(t *testing.T, algorithm string, claims map[string]interface{}, rsaKey *rsa.PrivateKey) string {
+	t.Helper()
+	segment := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(map[string]string{"alg": algorithm, "typ": "JWT"}) + "." + segment(claims)
+
+	var signature []byte
+	switch algorithm {
+	case "HS256":
+		mac := hmac.New(sha256.New, testHMACKey)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case "RS256":
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]interface{}{"sub": "alice", "iss": "idp", "aud": "share-screen", "exp": testNow.Unix() + 60, "name": "Alice", "email": "alice@example.com", "scope": "share admin"}
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := map[string]interface{}{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name          string
+		token         string
+		expectedError bool
+	}{
+		{name: "HS256", token: sign(t, "HS256", valid, nil)},
+		{name: "RS256", token: sign(t, "RS256", valid, rsaKey)},
+		{name: "audience in a list", token: sign(t, "HS256", with("aud", []string{"other", "share-screen"}), nil)},
+		{name: "expired within the clock skew", token: sign(t, "HS256", with("exp", testNow.Unix()-10), nil)},
+		{name: "expired", token: sign(t, "HS256", with("exp", testNow.Unix()-60), nil), expectedError: true},
+		{name: "no expiry", token: sign(t, "HS256", with("exp", nil), nil), expectedError: true},
+		{name: "not valid yet", token: sign(t, "HS256", with("nbf", testNow.Unix()+60), nil), expectedError: true},
+		{name: "no subject", token: sign(t, "HS256", with("sub", nil), nil), expectedError: true},
+		{name: "other audience", token: sign(t, "HS256", with("aud", "other"), nil), expectedError: true},
+		{name: "signed by another key", token: sign(t, "RS256", valid, otherKey), expectedError: true},
+		{name: "unsigned", token: sign(t, "none", valid, nil), expectedError: true},
+		{name: "tampered token", token: sign(t, "HS256", valid, nil)[:20] + "x" + sign(t, "HS256", valid, nil)[21:], expectedError: true},
+		{name: "not a JWT", token: "admin-token", expectedError: true},
+	}
+
+	verifier := NewVerifier(testHMACKey, &rsaKey.PublicKey, "share-screen").(*Verifier)
+	verifier.now = func() time.Time { return testNow }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := verifier.Verify(tt.token)
+			if tt.expectedError {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := &entities.UserIdentity{Subject: "alice", Issuer: "idp", Name: "Alice", Email: "alice@example.com", Scopes: []string{"share", "admin"}}
+			if !reflect.DeepEqual(user, expected) {
+				t.Errorf("Expected %+v, got %+v", expected, user)
+			}
+		})
+	}
+}
+
+func TestVerifier_RefusesUnconfiguredAlgorithm(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]interface{}{"sub": "alice", "exp": time.Now().Unix() + 60}
+
+	// An RS256-only server must not accept HS256 tokens, whatever their key
+	if _, err := NewVerifier(nil, &rsaKey.PublicKey, "").Verify(sign(t, "HS256", claims, nil)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected HS256 to be refused, got %v", err)
+	}
+	if _, err := NewVerifier(testHMACKey, nil, "").Verify(sign(t, "RS256", claims, rsaKey)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected RS256 to be refused, got %v", err)
+	}
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkix, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	write := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	for _, path := range []string{
+		write("pkix.pem", "PUBLIC KEY", pkix),
+		write("pkcs1.pem", "RSA PUBLIC KEY", x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)),
+	} {
+		key, err := LoadPublicKey(path)
+		if err != nil {
+			t.Fatalf("Unexpected error loading %s: %v", path, err)
+		}
+		if !key.Equal(&rsaKey.PublicKey) {
+			t.Errorf("Expected the generated key from %s", path)
+		}
+	}
+
+	if _, err := LoadPublicKey(write("private.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))); err == nil {
+		t.Error("Expected a private key to be refused")
+	}
+	if _, err := LoadPublicKey(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
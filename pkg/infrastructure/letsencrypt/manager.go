@@ -0,0 +1,55 @@
+// Package letsencrypt obtains and renews the server's TLS certificates from
+// Let's Encrypt, so a deployment on a public hostname serves a certificate
+// every browser trusts instead of a self-signed one.
+package letsencrypt
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// NewManager creates the certificate manager for domains, caching account
+// keys and certificates in cacheDir so restarts do not request new ones.
+// Certificates are only requested for the listed domains; email, if given,
+// is the contact Let's Encrypt warns about expiring certificates.
+//
+// Let's Encrypt proves control of a domain by connecting to it on port 443,
+// which the manager's TLS config answers, or on port 80, which its
+// HTTPHandler answers.
+func NewManager(domains []string, cacheDir, email string) (*autocert.Manager, error) {
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("automatic HTTPS needs the public domain name of the server")
+	}
+	for _, domain := range domains {
+		if err := validateDomain(domain); err != nil {
+			return nil, err
+		}
+	}
+	if cacheDir == "" {
+		return nil, fmt.Errorf("automatic HTTPS needs a cache directory for its certificates")
+	}
+
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Email:      email,
+	}, nil
+}
+
+// validateDomain checks that Let's Encrypt can issue a certificate for
+// domain: a fully qualified name, not an IP address, localhost or wildcard
+func validateDomain(domain string) error {
+	switch {
+	case net.ParseIP(domain) != nil:
+		return fmt.Errorf("%q is an IP address; Let's Encrypt needs a domain name", domain)
+	case strings.Contains(domain, "*"):
+		return fmt.Errorf("%q is a wildcard; automatic HTTPS needs each domain listed", domain)
+	case !strings.Contains(strings.Trim(domain, "."), ".") || strings.HasSuffix(domain, ".local"):
+		return fmt.Errorf("%q is not a public domain name", domain)
+	}
+	return nil
+}
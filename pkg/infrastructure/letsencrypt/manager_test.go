@@ -0,0 +1,56 @@
+package letsencrypt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewManager(t *testing.T) {
+	tests := []struct {
+		name          string
+		domains       []string
+		cacheDir      string
+		expectedError bool
+	}{
+		{name: "public domains", domains: []string{"share.example.com", "screen.example.org"}, cacheDir: t.TempDir()},
+		{name: "no domain", cacheDir: t.TempDir(), expectedError: true},
+		{name: "IP address", domains: []string{"203.0.113.7"}, cacheDir: t.TempDir(), expectedError: true},
+		{name: "wildcard", domains: []string{"*.example.com"}, cacheDir: t.TempDir(), expectedError: true},
+		{name: "single label", domains: []string{"localhost"}, cacheDir: t.TempDir(), expectedError: true},
+		{name: "mDNS name", domains: []string{"office-pc.local"}, cacheDir: t.TempDir(), expectedError: true},
+		{name: "no cache directory", domains: []string{"share.example.com"}, expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := NewManager(tt.domains, tt.cacheDir, "ops@example.com")
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if manager.Email != "ops@example.com" {
+				t.Errorf("Expected the contact email, got %q", manager.Email)
+			}
+		})
+	}
+}
+
+func TestNewManager_OnlyListedDomains(t *testing.T) {
+	manager, err := NewManager([]string{"share.example.com"}, t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := manager.HostPolicy(context.Background(), "share.example.com"); err != nil {
+		t.Errorf("Expected the configured domain allowed, got %v", err)
+	}
+	// Certificates must not be requested for whatever name a client sends
+	if err := manager.HostPolicy(context.Background(), "other.example.com"); err == nil {
+		t.Error("Expected other domains refused")
+	}
+}
@@ -0,0 +1,180 @@
+package mdns
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Advertiser answers mDNS queries for the server's service, so clients on
+// the LAN find it without knowing its address
+type Advertiser struct {
+	instance string
+	host     string
+	port     uint16
+	text     []string
+	lanIPs   func() []string
+}
+
+// NewAdvertiser creates an advertiser for a server named name, e.g. the
+// machine's host name, on port; text becomes the TXT record and lanIPs is
+// asked for the addresses on every answer, since they may change
+func NewAdvertiser(name string, port int, text map[string]string, lanIPs func() []string) *Advertiser {
+	// dots would split the instance and host labels
+	label := strings.ReplaceAll(name, ".", "-")
+
+	entries := make([]string, 0, len(text))
+	for key, value := range text {
+		entries = append(entries, key+"="+value)
+	}
+	sort.Strings(entries)
+
+	return &Advertiser{
+		instance: "share-screen on " + label + "." + ServiceType,
+		host:     label + ".local.",
+		port:     uint16(port),
+		text:     entries,
+		lanIPs:   lanIPs,
+	}
+}
+
+// Run announces the service and answers queries until ctx is cancelled
+func (a *Advertiser) Run(ctx context.Context) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, groupAddress)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if announcement, err := a.response(dnsmessage.Header{}, nil); err == nil {
+		conn.WriteToUDP(announcement, groupAddress)
+	}
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		answer, destination := a.answer(buf[:n], from)
+		if answer != nil {
+			conn.WriteToUDP(answer, destination)
+		}
+	}
+}
+
+// answer returns the response to a query and where to send it, or nil when
+// the query is not about this service. Queries from a port other than 5353
+// come from simple resolvers and are answered directly, echoing the query
+// (RFC 6762 section 6.7).
+func (a *Advertiser) answer(query []byte, from *net.UDPAddr) ([]byte, *net.UDPAddr) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil || header.Response {
+		return nil, nil
+	}
+	questions, err := parser.AllQuestions()
+	if err != nil {
+		return nil, nil
+	}
+
+	var asked []dnsmessage.Question
+	for _, q := range questions {
+		name := strings.ToLower(q.Name.String())
+		if name == ServiceType || name == strings.ToLower(a.instance) || name == strings.ToLower(a.host) {
+			asked = append(asked, q)
+		}
+	}
+	if len(asked) == 0 {
+		return nil, nil
+	}
+
+	if from.Port != groupAddress.Port {
+		response, err := a.response(dnsmessage.Header{ID: header.ID}, asked)
+		if err != nil {
+			return nil, nil
+		}
+		return response, from
+	}
+	response, err := a.response(dnsmessage.Header{}, nil)
+	if err != nil {
+		return nil, nil
+	}
+	return response, groupAddress
+}
+
+// response builds the full set of records: the service pointer, its SRV and
+// TXT records and the host's addresses
+func (a *Advertiser) response(header dnsmessage.Header, questions []dnsmessage.Question) ([]byte, error) {
+	serviceName, err := dnsmessage.NewName(ServiceType)
+	if err != nil {
+		return nil, err
+	}
+	instanceName, err := dnsmessage.NewName(a.instance)
+	if err != nil {
+		return nil, err
+	}
+	hostName, err := dnsmessage.NewName(a.host)
+	if err != nil {
+		return nil, err
+	}
+
+	header.Response = true
+	header.Authoritative = true
+	builder := dnsmessage.NewBuilder(nil, header)
+	builder.EnableCompression()
+
+	if len(questions) > 0 {
+		if err := builder.StartQuestions(); err != nil {
+			return nil, err
+		}
+		for _, q := range questions {
+			// the unicast-response bit is not part of a legacy question
+			q.Class = dnsmessage.ClassINET
+			if err := builder.Question(q); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := builder.StartAnswers(); err != nil {
+		return nil, err
+	}
+	resourceHeader := func(name dnsmessage.Name) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: recordTTL}
+	}
+	if err := builder.PTRResource(resourceHeader(serviceName), dnsmessage.PTRResource{PTR: instanceName}); err != nil {
+		return nil, err
+	}
+	if err := builder.SRVResource(resourceHeader(instanceName), dnsmessage.SRVResource{Target: hostName, Port: a.port}); err != nil {
+		return nil, err
+	}
+	text := a.text
+	if len(text) == 0 {
+		// a TXT record holds at least one string
+		text = []string{""}
+	}
+	if err := builder.TXTResource(resourceHeader(instanceName), dnsmessage.TXTResource{TXT: text}); err != nil {
+		return nil, err
+	}
+	for _, address := range a.lanIPs() {
+		ip := net.ParseIP(address)
+		if ipv4 := ip.To4(); ipv4 != nil {
+			err = builder.AResource(resourceHeader(hostName), dnsmessage.AResource{A: [4]byte(ipv4)})
+		} else if ip != nil {
+			err = builder.AAAAResource(resourceHeader(hostName), dnsmessage.AAAAResource{AAAA: [16]byte(ip.To16())})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return builder.Finish()
+}
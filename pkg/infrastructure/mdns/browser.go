@@ -0,0 +1,69 @@
+package mdns
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Browse asks the LAN for share-screen servers and returns those that
+// answered within timeout
+func Browse(ctx context.Context, timeout time.Duration) ([]Service, error) {
+	query, err := browseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	// Asking from an ephemeral port gets the answers sent straight back
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.WriteToUDP(query, groupAddress); err != nil {
+		return nil, err
+	}
+
+	services := map[string]*Service{}
+	hosts := map[string][]net.IP{}
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			// the deadline ends the browse
+			break
+		}
+		parseResponse(buf[:n], services, hosts)
+	}
+	return resolve(services, hosts), nil
+}
+
+// browseQuery builds the PTR query for the service type
+func browseQuery() ([]byte, error) {
+	name, err := dnsmessage.NewName(ServiceType)
+	if err != nil {
+		return nil, err
+	}
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: uint16(rand.IntN(1 << 16))})
+	if err := builder.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return builder.Finish()
+}
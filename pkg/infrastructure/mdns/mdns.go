@@ -0,0 +1,150 @@
+// Package mdns advertises the server on the LAN with multicast DNS service
+// discovery (RFC 6762 and 6763) and finds the servers advertised that way
+package mdns
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// ServiceType is the DNS-SD service type share-screen servers advertise
+const ServiceType = "_share-screen._tcp.local."
+
+// recordTTL is how long, in seconds, answers may be cached
+const recordTTL = 120
+
+// groupAddress is the IPv4 mDNS multicast group
+var groupAddress = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Service is a share-screen server found on the LAN
+type Service struct {
+	Instance string
+	Host     string
+	Port     int
+	IPs      []net.IP
+
+	// Text holds the key=value pairs of the TXT record, e.g. scheme and version
+	Text map[string]string
+}
+
+// Name returns the instance name without the service type, e.g.
+// "share-screen on office-mac"
+func (s Service) Name() string {
+	return strings.TrimSuffix(s.Instance, "."+ServiceType)
+}
+
+// URL returns the address of the server's pages, on its first IPv4
+// address when it has one
+func (s Service) URL() string {
+	scheme := s.Text["scheme"]
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	host := strings.TrimSuffix(s.Host, ".")
+	if len(s.IPs) > 0 {
+		host = s.IPs[0].String()
+	}
+	for _, ip := range s.IPs {
+		if ip.To4() != nil {
+			host = ip.String()
+			break
+		}
+	}
+	return scheme + "://" + net.JoinHostPort(host, strconv.Itoa(s.Port)) + "/"
+}
+
+// parseResponse adds the records of an mDNS response to the services found
+// so far, keyed by instance name, and the addresses to hosts
+func parseResponse(msg []byte, services map[string]*Service, hosts map[string][]net.IP) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(msg)
+	if err != nil || !header.Response {
+		return
+	}
+	if err := parser.SkipAllQuestions(); err != nil {
+		return
+	}
+
+	service := func(instance string) *Service {
+		if services[instance] == nil {
+			services[instance] = &Service{Instance: instance, Text: map[string]string{}}
+		}
+		return services[instance]
+	}
+
+	// Answers, authorities and additionals are read alike
+	for {
+		resource, err := nextResource(&parser)
+		if err != nil {
+			return
+		}
+		name := resource.Header.Name.String()
+		switch body := resource.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if strings.EqualFold(name, ServiceType) {
+				service(body.PTR.String())
+			}
+		case *dnsmessage.SRVResource:
+			if strings.HasSuffix(strings.ToLower(name), "."+ServiceType) {
+				s := service(name)
+				s.Host = body.Target.String()
+				s.Port = int(body.Port)
+			}
+		case *dnsmessage.TXTResource:
+			if strings.HasSuffix(strings.ToLower(name), "."+ServiceType) {
+				s := service(name)
+				for _, entry := range body.TXT {
+					if key, value, ok := strings.Cut(entry, "="); ok {
+						s.Text[key] = value
+					}
+				}
+			}
+		case *dnsmessage.AResource:
+			hosts[strings.ToLower(name)] = appendIP(hosts[strings.ToLower(name)], body.A[:])
+		case *dnsmessage.AAAAResource:
+			hosts[strings.ToLower(name)] = appendIP(hosts[strings.ToLower(name)], body.AAAA[:])
+		}
+	}
+}
+
+// nextResource returns the next record of any section
+func nextResource(parser *dnsmessage.Parser) (dnsmessage.Resource, error) {
+	resource, err := parser.Answer()
+	if err == dnsmessage.ErrSectionDone {
+		if resource, err = parser.Authority(); err == dnsmessage.ErrSectionDone {
+			resource, err = parser.Additional()
+		}
+	}
+	return resource, err
+}
+
+// appendIP adds ip to ips unless it is there already
+func appendIP(ips []net.IP, ip []byte) []net.IP {
+	address := net.IP(append([]byte(nil), ip...))
+	for _, existing := range ips {
+		if existing.Equal(address) {
+			return ips
+		}
+	}
+	return append(ips, address)
+}
+
+// resolve fills in the addresses of the services and returns the complete
+// ones, sorted by name
+func resolve(services map[string]*Service, hosts map[string][]net.IP) []Service {
+	var result []Service
+	for _, s := range services {
+		if s.Port == 0 || s.Host == "" {
+			continue
+		}
+		s.IPs = hosts[strings.ToLower(s.Host)]
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Instance < result[j].Instance })
+	return result
+}
@@ -0,0 +1,83 @@
+package mdns
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestAdvertiser_AnswerBrowse(t *testing.T) {
+	advertiser := NewAdvertiser("office.mac", 8443, map[string]string{"scheme": "https", "version": "1.0.0"}, func() []string {
+		return []string{"fd00::10", "192.168.1.10"}
+	})
+
+	query, err := browseQuery()
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+
+	from := &net.UDPAddr{IP: net.ParseIP("192.168.1.20"), Port: 50000}
+	answer, destination := advertiser.answer(query, from)
+	if answer == nil {
+		t.Fatal("Expected an answer to the browse query")
+	}
+	if destination != from {
+		t.Errorf("Expected a query from an ephemeral port to be answered directly, got %v", destination)
+	}
+
+	services := map[string]*Service{}
+	hosts := map[string][]net.IP{}
+	parseResponse(answer, services, hosts)
+	found := resolve(services, hosts)
+	if len(found) != 1 {
+		t.Fatalf("Expected one service, got %+v", found)
+	}
+
+	service := found[0]
+	if service.Name() != "share-screen on office-mac" {
+		t.Errorf("Expected the instance name, got %q", service.Name())
+	}
+	if service.Text["version"] != "1.0.0" {
+		t.Errorf("Expected the TXT record, got %v", service.Text)
+	}
+	if url := service.URL(); url != "https://192.168.1.10:8443/" {
+		t.Errorf("Expected the IPv4 URL, got %s", url)
+	}
+}
+
+func TestAdvertiser_AnswerMulticast(t *testing.T) {
+	advertiser := NewAdvertiser("office", 8080, nil, func() []string { return []string{"fd00::10"} })
+	query, _ := browseQuery()
+
+	answer, destination := advertiser.answer(query, &net.UDPAddr{IP: net.ParseIP("192.168.1.20"), Port: 5353})
+	if answer == nil || destination != groupAddress {
+		t.Fatalf("Expected a multicast answer, got %v", destination)
+	}
+
+	services := map[string]*Service{}
+	hosts := map[string][]net.IP{}
+	parseResponse(answer, services, hosts)
+	found := resolve(services, hosts)
+	if len(found) != 1 || found[0].URL() != "http://[fd00::10]:8080/" {
+		t.Errorf("Expected the IPv6 URL, got %+v", found)
+	}
+}
+
+func TestAdvertiser_IgnoresOtherQueries(t *testing.T) {
+	advertiser := NewAdvertiser("office", 8080, nil, func() []string { return nil })
+
+	if answer, _ := advertiser.answer([]byte("not dns"), &net.UDPAddr{Port: 5353}); answer != nil {
+		t.Error("Expected no answer to garbage")
+	}
+
+	// A response of another server is not a query
+	other := NewAdvertiser("other", 8080, nil, func() []string { return nil })
+	response, err := other.response(dnsmessage.Header{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build response: %v", err)
+	}
+	if answer, _ := advertiser.answer(response, &net.UDPAddr{Port: 5353}); answer != nil {
+		t.Error("Expected no answer to a response")
+	}
+}
@@ -0,0 +1,130 @@
+package mqtt
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	// connectTimeout bounds the first connection to the broker
+	connectTimeout = 10 * time.Second
+
+	// operationTimeout bounds waiting for the broker to acknowledge a
+	// publish or subscribe
+	operationTimeout = 5 * time.Second
+
+	// disconnectQuiesce is how long Close lets pending work finish, in
+	// milliseconds
+	disconnectQuiesce = 250
+
+	// qos is the MQTT quality of service of every message: at least once
+	qos = 1
+)
+
+// ErrUnsupportedScheme is returned for broker URLs the client cannot dial
+var ErrUnsupportedScheme = errors.New("unsupported MQTT broker scheme: expected tcp, mqtt, ssl, tls, mqtts, ws or wss")
+
+// supportedSchemes are the broker URL schemes the client dials
+var supportedSchemes = map[string]bool{
+	"tcp": true, "mqtt": true, "ssl": true, "tls": true, "mqtts": true, "ws": true, "wss": true,
+}
+
+// Client implements MessageBus over an MQTT 3.1.1 broker. It reconnects on
+// its own when the connection drops and subscribes again to its filters,
+// since the broker forgets them with the clean session; the handlers stay
+// routed in the client.
+type Client struct {
+	client paho.Client
+
+	mu      sync.Mutex
+	filters map[string]byte
+}
+
+// NewClient connects to the broker at brokerURL, e.g. tcp://broker:1883, as
+// clientID; empty username and password connect anonymously
+func NewClient(brokerURL, clientID, username, password string) (*Client, error) {
+	parsed, err := url.Parse(brokerURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid MQTT broker URL %q", brokerURL)
+	}
+	if !supportedSchemes[parsed.Scheme] {
+		return nil, ErrUnsupportedScheme
+	}
+
+	c := &Client{filters: make(map[string]byte)}
+	options := paho.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID(clientID).
+		SetUsername(username).
+		SetPassword(password).
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetConnectTimeout(connectTimeout).
+		SetOrderMatters(false).
+		SetOnConnectHandler(c.resubscribe).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			log.Printf("⚠️  Lost the MQTT broker, reconnecting: %v", err)
+		})
+	c.client = paho.NewClient(options)
+
+	token := c.client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return nil, fmt.Errorf("timed out connecting to MQTT broker %s", parsed.Host)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("connecting to MQTT broker %s: %w", parsed.Host, err)
+	}
+	return c, nil
+}
+
+// Publish sends a payload to a topic and waits for the broker to take it
+func (c *Client) Publish(topic string, payload []byte, retained bool) error {
+	return wait(c.client.Publish(topic, qos, retained, payload))
+}
+
+// Subscribe calls handler with the messages of the topics matching filter
+func (c *Client) Subscribe(filter string, handler func(topic string, payload []byte)) error {
+	c.mu.Lock()
+	c.filters[filter] = qos
+	c.mu.Unlock()
+
+	return wait(c.client.Subscribe(filter, qos, func(_ paho.Client, message paho.Message) {
+		handler(message.Topic(), message.Payload())
+	}))
+}
+
+// Close disconnects from the broker
+func (c *Client) Close() error {
+	c.client.Disconnect(disconnectQuiesce)
+	return nil
+}
+
+// resubscribe subscribes again to every filter after a reconnection; on the
+// first connection there are none yet
+func (c *Client) resubscribe(client paho.Client) {
+	c.mu.Lock()
+	filters := maps.Clone(c.filters)
+	c.mu.Unlock()
+
+	if len(filters) == 0 {
+		return
+	}
+	if err := wait(client.SubscribeMultiple(filters, nil)); err != nil {
+		log.Printf("❌ Error subscribing again to MQTT topics: %v", err)
+	}
+}
+
+// wait waits for the broker to acknowledge an operation
+func wait(token paho.Token) error {
+	if !token.WaitTimeout(operationTimeout) {
+		return errors.New("timed out waiting for the MQTT broker")
+	}
+	return token.Error()
+}
@@ -0,0 +1,29 @@
+package mqtt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewClient_InvalidBrokerURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		brokerURL string
+	}{
+		{"missing scheme", "broker:1883"},
+		{"http", "http://broker:1883"},
+		{"no host", "tcp://"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewClient(tt.brokerURL, "share-screen-test", "", ""); err == nil {
+				t.Errorf("Expected an error for %q", tt.brokerURL)
+			}
+		})
+	}
+
+	if _, err := NewClient("http://broker:1883", "share-screen-test", "", ""); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+}
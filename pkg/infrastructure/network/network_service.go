@@ -3,25 +3,99 @@ package network
 import (
 	"log"
 	"net"
+	"slices"
 
+	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/domain/interfaces"
 )
 
 // NetworkService implements the NetworkService interface
-type NetworkService struct{}
+type NetworkService struct {
+	interfaceName string
+}
+
+// NewNetworkService creates a new network service; interfaceName limits the
+// LAN IPs to the addresses of one interface, falling back to every interface
+// while it has none, and is empty to pick from all of them
+func NewNetworkService(interfaceName string) interfaces.NetworkService {
+	return &NetworkService{interfaceName: interfaceName}
+}
 
-// NewNetworkService creates a new network service
-func NewNetworkService() interfaces.NetworkService {
-	return &NetworkService{}
+// interfaceAddrs are the addresses of one interface that is up
+type interfaceAddrs struct {
+	name string
+	ips  []net.IP
 }
 
-// GetLANIP returns the local area network IP address
+// GetLANIP returns the local area network IP address, the first of GetLANIPs
 func (s *NetworkService) GetLANIP() string {
+	if ips := s.GetLANIPs(); len(ips) > 0 {
+		return ips[0]
+	}
+	return ""
+}
+
+// GetLANIPs returns the usable addresses of the interfaces that are up:
+// private IPv4 addresses first, then IPv6 unique local and global addresses
+func (s *NetworkService) GetLANIPs() []string {
+	return s.lanIPs(upInterfaces())
+}
+
+// GetInterfaces returns the interfaces that are up with usable addresses,
+// marking the one GetLANIP takes its address from
+func (s *NetworkService) GetInterfaces() []entities.NetworkInterface {
+	ifaces := upInterfaces()
+	lanIPs := s.lanIPs(ifaces)
+
+	var result []entities.NetworkInterface
+	for _, iface := range ifaces {
+		addresses := rankLANIPs(iface.ips)
+		if len(addresses) == 0 {
+			continue
+		}
+		result = append(result, entities.NetworkInterface{
+			Name:      iface.name,
+			Addresses: addresses,
+			Selected:  len(lanIPs) > 0 && slices.Contains(addresses, lanIPs[0]),
+		})
+	}
+	return result
+}
+
+// InterfaceName returns the configured interface, empty when any may be used
+func (s *NetworkService) InterfaceName() string {
+	return s.interfaceName
+}
+
+// lanIPs ranks the addresses of the configured interface, or of every
+// interface when none is configured or it has no usable address
+func (s *NetworkService) lanIPs(ifaces []interfaceAddrs) []string {
+	if s.interfaceName != "" {
+		for _, iface := range ifaces {
+			if iface.name != s.interfaceName {
+				continue
+			}
+			if ips := rankLANIPs(iface.ips); len(ips) > 0 {
+				return ips
+			}
+		}
+	}
+
+	var all []net.IP
+	for _, iface := range ifaces {
+		all = append(all, iface.ips...)
+	}
+	return rankLANIPs(all)
+}
+
+// upInterfaces returns the addresses of every interface that is up
+func upInterfaces() []interfaceAddrs {
 	ifaces, err := net.Interfaces()
 	if err != nil {
 		log.Printf("Error getting network interfaces: %v", err)
-		return ""
+		return nil
 	}
+	var result []interfaceAddrs
 	for _, iface := range ifaces {
 		if (iface.Flags & net.FlagUp) == 0 {
 			continue
@@ -30,20 +104,51 @@ func (s *NetworkService) GetLANIP() string {
 		if err != nil {
 			continue
 		}
+		entry := interfaceAddrs{name: iface.Name}
 		for _, a := range addrs {
-			ipnet, ok := a.(*net.IPNet)
-			if !ok || ipnet.IP == nil || ipnet.IP.IsLoopback() {
-				continue
-			}
-			ipv4 := ipnet.IP.To4()
-			if ipv4 == nil {
-				continue
-			}
-			// pick typical private ranges
-			if ipv4[0] == 10 || (ipv4[0] == 192 && ipv4[1] == 168) || (ipv4[0] == 172 && ipv4[1] >= 16 && ipv4[1] <= 31) {
-				return ipv4.String()
+			if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP != nil {
+				entry.ips = append(entry.ips, ipnet.IP)
 			}
 		}
+		result = append(result, entry)
 	}
-	return ""
+	return result
+}
+
+// rankLANIPs keeps the addresses other devices can reach, ordered by
+// preference and in their original order within each rank
+func rankLANIPs(ips []net.IP) []string {
+	var ranked [3][]string
+	for _, ip := range ips {
+		if rank := lanRank(ip); rank >= 0 {
+			ranked[rank] = append(ranked[rank], ip.String())
+		}
+	}
+
+	var result []string
+	for _, group := range ranked {
+		result = append(result, group...)
+	}
+	return result
+}
+
+// lanRank returns the preference of an address, lower being better, or -1
+// for loopback, link-local and public IPv4 addresses
+func lanRank(ip net.IP) int {
+	if ip.IsLoopback() || !ip.IsGlobalUnicast() {
+		return -1
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		// pick typical private ranges
+		if ipv4[0] == 10 || (ipv4[0] == 192 && ipv4[1] == 168) || (ipv4[0] == 172 && ipv4[1] >= 16 && ipv4[1] <= 31) {
+			return 0
+		}
+		return -1
+	}
+	// fc00::/7 unique local addresses stay on the site, while global ones
+	// may change with the provider's prefix
+	if ip.IsPrivate() {
+		return 1
+	}
+	return 2
 }
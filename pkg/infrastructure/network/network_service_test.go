@@ -6,7 +6,7 @@ import (
 )
 
 func TestNetworkService_GetLANIP(t *testing.T) {
-	service := NewNetworkService().(*NetworkService)
+	service := NewNetworkService("").(*NetworkService)
 
 	ip := service.GetLANIP()
 
@@ -22,10 +22,13 @@ func TestNetworkService_GetLANIP(t *testing.T) {
 			t.Errorf("Returned IP %q is not a valid IP address", ip)
 		}
 
-		// Convert to IPv4 to ensure it's IPv4
+		// IPv6 is only returned when the host has no private IPv4 address
 		ipv4 := parsedIP.To4()
 		if ipv4 == nil {
-			t.Errorf("Returned IP %q is not a valid IPv4 address", ip)
+			if !parsedIP.IsGlobalUnicast() {
+				t.Errorf("Returned IP %q is not a usable IPv6 address", ip)
+			}
+			return
 		}
 
 		// Check if it's in private ranges (this is what the function should return)
@@ -47,7 +50,7 @@ func TestNetworkService_GetLANIP(t *testing.T) {
 func TestNetworkService_GetLANIP_Integration(t *testing.T) {
 	// This is more of an integration test that verifies the function
 	// works with the actual network interfaces
-	service := NewNetworkService()
+	service := NewNetworkService("")
 
 	// Call multiple times to ensure consistency
 	ip1 := service.GetLANIP()
@@ -57,3 +60,60 @@ func TestNetworkService_GetLANIP_Integration(t *testing.T) {
 		t.Errorf("GetLANIP should return consistent results: got %q and %q", ip1, ip2)
 	}
 }
+
+func TestRankLANIPs(t *testing.T) {
+	var ips []net.IP
+	for _, s := range []string{
+		"127.0.0.1",
+		"::1",
+		"fe80::1",
+		"2001:db8::10",
+		"8.8.8.8",
+		"fd00::10",
+		"192.168.1.10",
+		"169.254.1.1",
+		"10.0.0.5",
+	} {
+		ips = append(ips, net.ParseIP(s))
+	}
+
+	expected := []string{"192.168.1.10", "10.0.0.5", "fd00::10", "2001:db8::10"}
+	got := rankLANIPs(ips)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, got)
+			break
+		}
+	}
+}
+
+func TestNetworkService_LANIPsInterface(t *testing.T) {
+	ifaces := []interfaceAddrs{
+		{name: "docker0", ips: []net.IP{net.ParseIP("172.17.0.1")}},
+		{name: "en0", ips: []net.IP{net.ParseIP("fe80::1"), net.ParseIP("192.168.1.10")}},
+		{name: "utun3", ips: []net.IP{net.ParseIP("fe80::2")}},
+	}
+
+	tests := []struct {
+		name          string
+		interfaceName string
+		expected      string
+	}{
+		{name: "automatic", expected: "172.17.0.1"},
+		{name: "configured interface", interfaceName: "en0", expected: "192.168.1.10"},
+		{name: "interface without usable address", interfaceName: "utun3", expected: "172.17.0.1"},
+		{name: "missing interface", interfaceName: "eth9", expected: "172.17.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &NetworkService{interfaceName: tt.interfaceName}
+			if got := service.lanIPs(ifaces); len(got) == 0 || got[0] != tt.expected {
+				t.Errorf("Expected %s first, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pion/stun/v3"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// stunTimeout bounds one binding request to the STUN server
+const stunTimeout = 5 * time.Second
+
+// STUNDiscovery finds the server's public IP address by asking a STUN
+// server which address its binding request came from
+type STUNDiscovery struct {
+	address string
+
+	mu       sync.RWMutex
+	publicIP string
+}
+
+// NewSTUNDiscovery creates a public IP discovery through the STUN server at
+// stunURL, e.g. stun:stun.l.google.com:19302; only plain UDP STUN is used
+func NewSTUNDiscovery(stunURL string) (interfaces.PublicIPService, error) {
+	uri, err := stun.ParseURI(stunURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid STUN server %q: %w", stunURL, err)
+	}
+	if uri.Scheme != stun.SchemeTypeSTUN {
+		return nil, fmt.Errorf("STUN server %q must use the stun: scheme", stunURL)
+	}
+	return &STUNDiscovery{address: net.JoinHostPort(uri.Host, strconv.Itoa(uri.Port))}, nil
+}
+
+// PublicIP returns the last discovered public IP address
+func (d *STUNDiscovery) PublicIP() string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.publicIP
+}
+
+// Refresh sends a binding request and keeps the mapped address of the reply;
+// the previous address is kept when it fails
+func (d *STUNDiscovery) Refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, stunTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp4", d.address)
+	if err != nil {
+		return fmt.Errorf("failed to reach STUN server: %w", err)
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	request, err := stun.Build(stun.TransactionID, stun.BindingRequest)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(request.Raw); err != nil {
+		return fmt.Errorf("failed to send STUN request: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return fmt.Errorf("no STUN response: %w", err)
+		}
+
+		response := &stun.Message{Raw: buf[:n]}
+		if err := response.Decode(); err != nil || response.TransactionID != request.TransactionID {
+			// a stray or late datagram, keep waiting for ours
+			continue
+		}
+		if response.Type != stun.BindingSuccess {
+			return errors.New("STUN server refused the binding request")
+		}
+
+		var mapped stun.XORMappedAddress
+		if err := mapped.GetFrom(response); err != nil {
+			return fmt.Errorf("STUN response without a mapped address: %w", err)
+		}
+
+		d.mu.Lock()
+		d.publicIP = mapped.IP.String()
+		d.mu.Unlock()
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pion/stun/v3"
+)
+
+// startSTUNServer answers binding requests with the address they came from
+func startSTUNServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			request := &stun.Message{Raw: append([]byte(nil), buf[:n]...)}
+			if err := request.Decode(); err != nil {
+				continue
+			}
+			udpAddr := addr.(*net.UDPAddr)
+			response, err := stun.Build(request, stun.BindingSuccess, &stun.XORMappedAddress{IP: udpAddr.IP, Port: udpAddr.Port})
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(response.Raw, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestSTUNDiscovery_Refresh(t *testing.T) {
+	service, err := NewSTUNDiscovery("stun:" + startSTUNServer(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if ip := service.PublicIP(); ip != "" {
+		t.Errorf("Expected no public IP before discovery, got %s", ip)
+	}
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if ip := service.PublicIP(); ip != "127.0.0.1" {
+		t.Errorf("Expected the mapped address 127.0.0.1, got %q", ip)
+	}
+}
+
+func TestNewSTUNDiscovery_InvalidURL(t *testing.T) {
+	for _, stunURL := range []string{"", "turn:turn.example.com:3478", "stuns:stun.example.com", "http://stun.example.com"} {
+		if _, err := NewSTUNDiscovery(stunURL); err == nil {
+			t.Errorf("Expected %q to be rejected", stunURL)
+		}
+	}
+}
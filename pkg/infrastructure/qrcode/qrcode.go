@@ -0,0 +1,30 @@
+package qrcode
+
+import (
+	goqrcode "github.com/skip2/go-qrcode"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// QRCodeService implements the QRCodeService interface
+type QRCodeService struct{}
+
+// NewQRCodeService creates a new QR code service
+func NewQRCodeService() interfaces.QRCodeService {
+	return &QRCodeService{}
+}
+
+// PNG encodes content as a square QR code image of the given pixel size
+func (s *QRCodeService) PNG(content string, size int) ([]byte, error) {
+	return goqrcode.Encode(content, goqrcode.Medium, size)
+}
+
+// Text renders content as a QR code of half-block characters, two modules
+// per line, for printing in a terminal
+func (s *QRCodeService) Text(content string) (string, error) {
+	code, err := goqrcode.New(content, goqrcode.Medium)
+	if err != nil {
+		return "", err
+	}
+	return code.ToSmallString(false), nil
+}
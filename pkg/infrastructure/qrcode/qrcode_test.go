@@ -0,0 +1,42 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestQRCodeService_PNG(t *testing.T) {
+	service := NewQRCodeService()
+
+	data, err := service.PNG("http://192.168.1.10:8080/viewer?token=abc", 256)
+	if err != nil {
+		t.Fatalf("Failed to encode QR code: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a PNG image: %v", err)
+	}
+	if img.Bounds().Dx() != 256 || img.Bounds().Dy() != 256 {
+		t.Errorf("Expected 256x256 image, got %v", img.Bounds())
+	}
+}
+
+func TestQRCodeService_Text(t *testing.T) {
+	service := NewQRCodeService()
+
+	text, err := service.Text("http://192.168.1.10:8080/")
+	if err != nil {
+		t.Fatalf("Failed to encode QR code: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) < 10 {
+		t.Fatalf("Expected a QR code of several lines, got %q", text)
+	}
+	if !strings.ContainsAny(text, "█▀▄") {
+		t.Errorf("Expected block characters, got %q", text)
+	}
+}
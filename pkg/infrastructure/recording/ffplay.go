@@ -0,0 +1,39 @@
+package recording
+
+import (
+	"errors"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// errPlayerClosed is returned once the ffplay window has been closed
+var errPlayerClosed = errors.New("ffplay window closed")
+
+// FFplayPlayer implements the VideoRecorder interface by forwarding the RTP
+// packets over loopback UDP to ffplay, which reads them as described by a
+// session description file
+type FFplayPlayer struct {
+	binary string
+}
+
+// NewFFplayPlayer creates a new ffplay player; binary is the ffplay executable
+func NewFFplayPlayer(binary string) interfaces.VideoRecorder {
+	if binary == "" {
+		binary = "ffplay"
+	}
+	return &FFplayPlayer{binary: binary}
+}
+
+// Open starts ffplay listening for the track
+func (p *FFplayPlayer) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	return startRTPPipe(p.binary, codec, func(sdpPath string) []string {
+		return []string{
+			"-loglevel", "error",
+			"-protocol_whitelist", "file,udp,rtp",
+			"-fflags", "nobuffer",
+			"-flags", "low_delay",
+			"-window_title", "share-screen",
+			"-i", sdpPath,
+		}
+	}, errPlayerClosed)
+}
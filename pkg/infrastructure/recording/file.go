@@ -0,0 +1,141 @@
+// Package recording stores and plays the video tracks received by the native
+// viewer.
+package recording
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media/ivfwriter"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileRecorder implements the VideoRecorder interface by writing the track to
+// a file: IVF directly, or WebM by remuxing the IVF stream through ffmpeg
+type FileRecorder struct {
+	path   string
+	ffmpeg string
+}
+
+// NewFileRecorder creates a new file recorder for path, which must end in
+// .ivf or .webm; ffmpeg is the executable used for WebM
+func NewFileRecorder(path, ffmpeg string) (interfaces.VideoRecorder, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ivf", ".webm":
+	default:
+		return nil, fmt.Errorf("unsupported recording format %q, use .ivf or .webm", filepath.Ext(path))
+	}
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	return &FileRecorder{
+		path:   path,
+		ffmpeg: ffmpeg,
+	}, nil
+}
+
+// Open creates the recording file
+func (r *FileRecorder) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	if !ivfCodec(codec.MimeType) {
+		return nil, fmt.Errorf("cannot record %s, only VP8, VP9 and AV1", codec.MimeType)
+	}
+	if strings.EqualFold(filepath.Ext(r.path), ".webm") {
+		return r.openWebM(codec)
+	}
+
+	file, err := os.Create(r.path)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := ivfwriter.NewWith(file, ivfwriter.WithCodec(codec.MimeType))
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &ivfSink{writer: writer}, nil
+}
+
+// openWebM starts ffmpeg copying an IVF stream on its stdin into the WebM file
+func (r *FileRecorder) openWebM(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	cmd := exec.Command(r.ffmpeg,
+		"-loglevel", "error",
+		"-y",
+		"-f", "ivf",
+		"-i", "pipe:0",
+		"-c", "copy",
+		r.path,
+	)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting %s: %w", r.ffmpeg, err)
+	}
+	writer, err := ivfwriter.NewWith(stdin, ivfwriter.WithCodec(codec.MimeType))
+	if err != nil {
+		_ = stdin.Close()
+		_ = cmd.Wait()
+		return nil, err
+	}
+
+	return &ivfSink{
+		writer: writer,
+		wait: func() error {
+			if err := cmd.Wait(); err != nil {
+				if message := strings.TrimSpace(stderr.String()); message != "" {
+					return fmt.Errorf("ffmpeg failed: %s", message)
+				}
+				return err
+			}
+			return nil
+		},
+	}, nil
+}
+
+// ivfCodec reports whether IVF can hold a track of mimeType
+func ivfCodec(mimeType string) bool {
+	for _, supported := range []string{webrtc.MimeTypeVP8, webrtc.MimeTypeVP9, webrtc.MimeTypeAV1} {
+		if strings.EqualFold(mimeType, supported) {
+			return true
+		}
+	}
+	return false
+}
+
+// ivfSink writes RTP packets into an IVF stream, waiting on the process
+// reading that stream, if any, when closed
+type ivfSink struct {
+	writer *ivfwriter.IVFWriter
+	wait   func() error
+}
+
+// WriteRTP depacketizes one packet into the stream
+func (s *ivfSink) WriteRTP(packet []byte) error {
+	var p rtp.Packet
+	if err := p.Unmarshal(packet); err != nil {
+		return err
+	}
+	return s.writer.WriteRTP(&p)
+}
+
+// Close finishes the stream
+func (s *ivfSink) Close() error {
+	err := s.writer.Close()
+	if s.wait != nil {
+		if waitErr := s.wait(); waitErr != nil {
+			return waitErr
+		}
+	}
+	return err
+}
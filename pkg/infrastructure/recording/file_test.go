@@ -0,0 +1,130 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4/pkg/media/ivfreader"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+var vp8Codec = interfaces.VideoCodec{MimeType: "video/VP8", ClockRate: 90000, PayloadType: 96}
+
+// vp8Packet builds an RTP packet holding a whole VP8 frame
+func vp8Packet(t *testing.T, sequence uint16, keyframe bool) []byte {
+	// A payload descriptor starting a partition, then the frame tag
+	frame := []byte{0x10, 0x02, 0x00, 0x9d, 0x01, 0x2a}
+	if !keyframe {
+		frame = []byte{0x10, 0x01, 0x00, 0x00}
+	}
+	packet := rtp.Packet{
+		Header: rtp.Header{
+			Version:        2,
+			Marker:         true,
+			PayloadType:    96,
+			SequenceNumber: sequence,
+			Timestamp:      uint32(sequence) * 3000,
+		},
+		Payload: frame,
+	}
+	raw, err := packet.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	return raw
+}
+
+func TestNewFileRecorder(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		expectError bool
+	}{
+		{name: "ivf", path: "out.ivf"},
+		{name: "webm", path: "out.WEBM"},
+		{name: "unsupported", path: "out.mp4", expectError: true},
+		{name: "no extension", path: "out", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileRecorder(tt.path, "")
+			if tt.expectError && err == nil {
+				t.Error("Expected error")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFileRecorder_IVF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.ivf")
+	recorder, err := NewFileRecorder(path, "")
+	if err != nil {
+		t.Fatalf("NewFileRecorder failed: %v", err)
+	}
+
+	sink, err := recorder.Open(vp8Codec)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// The frame before the first keyframe cannot be decoded and is skipped
+	for i, keyframe := range []bool{false, true, false} {
+		if err := sink.WriteRTP(vp8Packet(t, uint16(i), keyframe)); err != nil {
+			t.Fatalf("WriteRTP failed: %v", err)
+		}
+	}
+	if err := sink.WriteRTP([]byte{0x01}); err == nil {
+		t.Error("Expected error for a malformed packet")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Recording missing: %v", err)
+	}
+	defer file.Close()
+	reader, header, err := ivfreader.NewWith(file)
+	if err != nil {
+		t.Fatalf("Invalid IVF: %v", err)
+	}
+	if header.FourCC != "VP80" || header.NumFrames != 2 {
+		t.Errorf("Expected 2 VP8 frames, got %d %s frames", header.NumFrames, header.FourCC)
+	}
+	if _, _, err := reader.ParseNextFrame(); err != nil {
+		t.Errorf("Reading first frame failed: %v", err)
+	}
+}
+
+func TestFileRecorder_UnsupportedCodec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.ivf")
+	recorder, err := NewFileRecorder(path, "")
+	if err != nil {
+		t.Fatalf("NewFileRecorder failed: %v", err)
+	}
+
+	if _, err := recorder.Open(interfaces.VideoCodec{MimeType: "video/H264", ClockRate: 90000}); err == nil {
+		t.Fatal("Expected error for H.264")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected no recording file")
+	}
+}
+
+func TestSessionDescription(t *testing.T) {
+	sdp := sessionDescription(vp8Codec, 5004)
+
+	for _, line := range []string{"c=IN IP4 127.0.0.1", "m=video 5004 RTP/AVP 96", "a=rtpmap:96 VP8/90000"} {
+		if !strings.Contains(sdp, line+"\r\n") {
+			t.Errorf("Expected %q in %q", line, sdp)
+		}
+	}
+}
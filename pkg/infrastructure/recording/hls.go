@@ -0,0 +1,114 @@
+package recording
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// errPackagingStopped is returned once ffmpeg has stopped writing the HLS
+// playlist
+var errPackagingStopped = errors.New("ffmpeg stopped packaging HLS")
+
+const (
+	// HLSPlaylist is the name of the playlist an HLS packager writes
+	HLSPlaylist = "index.m3u8"
+
+	// hlsSegmentSeconds is the target length of a segment; players start
+	// about three segments behind the live edge
+	hlsSegmentSeconds = "2"
+
+	// hlsPlaylistSegments is how many segments the playlist lists; older
+	// segments are deleted
+	hlsPlaylistSegments = "6"
+)
+
+// HLSPackager implements the VideoRecorder interface by forwarding the RTP
+// packets over loopback UDP to ffmpeg, which packages the video as a live HLS
+// playlist of short MPEG-TS segments in a directory. Like an RTMP restream
+// the video is H.264, encoded on the way when the track is VP8 or VP9.
+type HLSPackager struct {
+	dir    string
+	ffmpeg string
+}
+
+// NewHLSPackager creates a new packager writing to dir, which is created when
+// the track opens and removed when it closes; ffmpeg is the executable used
+func NewHLSPackager(dir, ffmpeg string) interfaces.VideoRecorder {
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	return &HLSPackager{dir: dir, ffmpeg: ffmpeg}
+}
+
+// Open starts ffmpeg listening for the track and writing the playlist
+func (p *HLSPackager) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	// Segments of an earlier track would be listed out of order
+	if err := os.RemoveAll(p.dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(p.dir, 0o755); err != nil {
+		return nil, err
+	}
+	sink, err := startRTPPipe(p.ffmpeg, codec, func(sdpPath string) []string {
+		return hlsArgs(codec, sdpPath, p.dir)
+	}, errPackagingStopped)
+	if err != nil {
+		_ = os.RemoveAll(p.dir)
+		return nil, err
+	}
+	return &hlsSink{rtpPipeSink: sink, dir: p.dir}, nil
+}
+
+// HLSContentType returns the media type of a file an HLS packager writes,
+// named name, and false for any other name
+func HLSContentType(name string) (string, bool) {
+	switch {
+	case name == HLSPlaylist:
+		return "application/vnd.apple.mpegurl", true
+	case strings.HasPrefix(name, "segment") && filepath.Ext(name) == ".ts" && filepath.Base(name) == name:
+		return "video/mp2t", true
+	default:
+		return "", false
+	}
+}
+
+// hlsArgs are ffmpeg's arguments for packaging a track in codec, described by
+// the file at sdpPath, as HLS in dir
+func hlsArgs(codec interfaces.VideoCodec, sdpPath, dir string) []string {
+	args := []string{
+		"-loglevel", "error",
+		"-protocol_whitelist", "file,udp,rtp",
+		"-fflags", "nobuffer",
+		"-i", sdpPath,
+		"-map", "0:v",
+	}
+	args = append(args, h264Args(codec)...)
+	return append(args,
+		"-f", "hls",
+		"-hls_time", hlsSegmentSeconds,
+		"-hls_list_size", hlsPlaylistSegments,
+		"-hls_flags", "delete_segments+independent_segments+temp_file",
+		"-hls_segment_filename", filepath.Join(dir, "segment%05d.ts"),
+		filepath.Join(dir, HLSPlaylist),
+	)
+}
+
+// hlsSink removes the playlist and its segments once packaging stops, so
+// players stop rather than replay the last seconds
+type hlsSink struct {
+	*rtpPipeSink
+	dir string
+}
+
+// Close stops ffmpeg and removes its output
+func (s *hlsSink) Close() error {
+	err := s.rtpPipeSink.Close()
+	if removeErr := os.RemoveAll(s.dir); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+func TestHLSArgs(t *testing.T) {
+	dir := filepath.Join("hls", "token")
+
+	h264 := strings.Join(hlsArgs(interfaces.VideoCodec{MimeType: "video/H264"}, "in.sdp", dir), " ")
+	if !strings.Contains(h264, "-c:v copy") {
+		t.Errorf("Expected H.264 to be passed on, got %q", h264)
+	}
+	vp8 := strings.Join(hlsArgs(vp8Codec, "in.sdp", dir), " ")
+	if !strings.Contains(vp8, "-c:v libx264") {
+		t.Errorf("Expected VP8 to be encoded to H.264, got %q", vp8)
+	}
+
+	for _, args := range []string{h264, vp8} {
+		if !strings.Contains(args, "-i in.sdp") || !strings.Contains(args, "-f hls") || !strings.HasSuffix(args, filepath.Join(dir, HLSPlaylist)) {
+			t.Errorf("Expected the SDP in and the playlist out, got %q", args)
+		}
+		if !strings.Contains(args, filepath.Join(dir, "segment%05d.ts")) {
+			t.Errorf("Expected segments next to the playlist, got %q", args)
+		}
+	}
+}
+
+func TestHLSContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "index.m3u8", want: "application/vnd.apple.mpegurl", ok: true},
+		{name: "segment00042.ts", want: "video/mp2t", ok: true},
+		{name: "segment00042.ts.tmp"},
+		{name: "other.m3u8"},
+		{name: "passwd"},
+		{name: ".."},
+	}
+
+	for _, tt := range tests {
+		got, ok := HLSContentType(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("HLSContentType(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHLSPackager_OpenFails(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "token")
+
+	if _, err := NewHLSPackager(dir, filepath.Join(t.TempDir(), "missing-ffmpeg")).Open(vp8Codec); err == nil {
+		t.Fatal("Expected error without ffmpeg")
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the session directory to be removed, got %v", err)
+	}
+}
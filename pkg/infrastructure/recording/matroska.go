@@ -0,0 +1,97 @@
+package recording
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// errRecordingStopped is returned once ffmpeg has stopped writing a recording
+var errRecordingStopped = errors.New("ffmpeg stopped recording")
+
+const (
+	// partialSuffix marks a recording still being written
+	partialSuffix = ".part"
+
+	// finishTimeout is how long ffmpeg may take to finish a recording once
+	// asked to stop, before it is killed
+	finishTimeout = 5 * time.Second
+)
+
+// MatroskaRecorder implements the VideoRecorder interface by forwarding the
+// RTP packets over loopback UDP to ffmpeg, which copies the video into a
+// Matroska file in a directory, one per track, without encoding it again
+type MatroskaRecorder struct {
+	dir    string
+	token  string
+	ffmpeg string
+}
+
+// NewMatroskaRecorder creates a new recorder of the session with token into
+// dir; ffmpeg is the executable used
+func NewMatroskaRecorder(dir, token, ffmpeg string) interfaces.VideoRecorder {
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	return &MatroskaRecorder{dir: dir, token: token, ffmpeg: ffmpeg}
+}
+
+// Open starts ffmpeg listening for the track and writing a new recording,
+// named after the session and the time it starts. The file ends in .part
+// until the recording is finished.
+func (r *MatroskaRecorder) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	path := filepath.Join(r.dir, entities.RecordingName(r.token, time.Now()))
+	sink, err := startRTPPipe(r.ffmpeg, codec, func(sdpPath string) []string {
+		return matroskaArgs(sdpPath, path+partialSuffix)
+	}, errRecordingStopped)
+	if err != nil {
+		return nil, err
+	}
+	return &matroskaSink{rtpPipeSink: sink, path: path}, nil
+}
+
+// matroskaArgs are ffmpeg's arguments for copying a track, described by the
+// file at sdpPath, into a Matroska file at path
+func matroskaArgs(sdpPath, path string) []string {
+	return []string{
+		"-loglevel", "error",
+		"-protocol_whitelist", "file,udp,rtp",
+		"-i", sdpPath,
+		"-map", "0:v",
+		"-c:v", "copy",
+		"-f", "matroska",
+		path,
+	}
+}
+
+// matroskaSink lets ffmpeg finish the file when the track ends, so it gets
+// its index and duration, and then gives it its final name
+type matroskaSink struct {
+	*rtpPipeSink
+	path string
+}
+
+// Close stops ffmpeg and finishes the recording
+func (s *matroskaSink) Close() error {
+	// ffmpeg finishes its output on an interrupt; where processes cannot be
+	// interrupted, such as on Windows, it is killed and the file left as is
+	if err := s.cmd.Process.Signal(os.Interrupt); err == nil {
+		select {
+		case <-s.exited:
+		case <-time.After(finishTimeout):
+		}
+	}
+	err := s.rtpPipeSink.Close()
+
+	// ffmpeg writes nothing before the first keyframe
+	renameErr := os.Rename(s.path+partialSuffix, s.path)
+	if renameErr != nil && !errors.Is(renameErr, fs.ErrNotExist) && err == nil {
+		err = renameErr
+	}
+	return err
+}
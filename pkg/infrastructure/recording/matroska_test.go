@@ -0,0 +1,69 @@
+package recording
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMatroskaArgs(t *testing.T) {
+	args := strings.Join(matroskaArgs("in.sdp", "out.mkv.part"), " ")
+	if !strings.Contains(args, "-i in.sdp") || !strings.Contains(args, "-c:v copy") || !strings.HasSuffix(args, "-f matroska out.mkv.part") {
+		t.Errorf("Expected the SDP copied into the Matroska file, got %q", args)
+	}
+}
+
+func TestMatroskaRecorder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script standing in for ffmpeg")
+	}
+	// The stand-in writes its output file and finishes on an interrupt
+	ffmpeg := filepath.Join(t.TempDir(), "ffmpeg")
+	script := "#!/bin/sh\ntrap 'exit 0' INT\nfor out; do :; done\necho video > \"$out\"\nwhile :; do sleep 0.05; done\n"
+	if err := os.WriteFile(ffmpeg, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+
+	sink, err := NewMatroskaRecorder(dir, "test-token", ffmpeg).Open(vp8Codec)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// The recording is written to a .part file until it is finished
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		parts, _ := filepath.Glob(filepath.Join(dir, "*.mkv.part"))
+		if len(parts) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected a .part file while recording")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected one recording, got %d files", len(entries))
+	}
+	if token, _, ok := entities.ParseRecordingName(entries[0].Name()); !ok || token != "test-token" {
+		t.Errorf("Expected a finished recording of the session, got %q", entries[0].Name())
+	}
+}
+
+func TestMatroskaRecorder_OpenFails(t *testing.T) {
+	if _, err := NewMatroskaRecorder(t.TempDir(), "test-token", filepath.Join(t.TempDir(), "missing-ffmpeg")).Open(vp8Codec); err == nil {
+		t.Error("Expected error without ffmpeg")
+	}
+}
@@ -0,0 +1,94 @@
+package recording
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// errRestreamStopped is returned once ffmpeg has stopped republishing, e.g.
+// because the RTMP server refused the stream key
+var errRestreamStopped = errors.New("ffmpeg stopped restreaming")
+
+// keyframeInterval is the keyframe interval, in frames, of video encoded for
+// RTMP and HLS; streaming platforms ask for one every two seconds or less
+const keyframeInterval = "60"
+
+// RTMPRestreamer implements the VideoRecorder interface by forwarding the RTP
+// packets over loopback UDP to ffmpeg, which republishes the video to an RTMP
+// server such as a streaming platform's ingest. H.264 is passed on as it is;
+// VP8 and VP9, which FLV cannot carry, are encoded to H.264 on the way.
+type RTMPRestreamer struct {
+	url    string
+	ffmpeg string
+}
+
+// NewRTMPRestreamer creates a new restreamer to an rtmp:// or rtmps:// URL,
+// which usually ends with the stream key; ffmpeg is the executable used
+func NewRTMPRestreamer(rtmpURL, ffmpeg string) (interfaces.VideoRecorder, error) {
+	parsed, err := url.Parse(rtmpURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "rtmp" && parsed.Scheme != "rtmps") {
+		return nil, fmt.Errorf("invalid RTMP URL %q: expected rtmp:// or rtmps://", RedactRTMPURL(rtmpURL))
+	}
+	if ffmpeg == "" {
+		ffmpeg = "ffmpeg"
+	}
+	return &RTMPRestreamer{url: rtmpURL, ffmpeg: ffmpeg}, nil
+}
+
+// Open starts ffmpeg listening for the track and publishing to the RTMP URL
+func (r *RTMPRestreamer) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	return startRTPPipe(r.ffmpeg, codec, func(sdpPath string) []string {
+		return restreamArgs(codec, sdpPath, r.url)
+	}, errRestreamStopped)
+}
+
+// restreamArgs are ffmpeg's arguments for republishing a track in codec,
+// described by the file at sdpPath, to rtmpURL
+func restreamArgs(codec interfaces.VideoCodec, sdpPath, rtmpURL string) []string {
+	args := []string{
+		"-loglevel", "error",
+		"-protocol_whitelist", "file,udp,rtp",
+		"-fflags", "nobuffer",
+		"-i", sdpPath,
+		// Streaming platforms expect an audio track, so a silent one is added
+		"-f", "lavfi", "-i", "anullsrc=channel_layout=stereo:sample_rate=44100",
+		"-map", "0:v", "-map", "1:a",
+	}
+	args = append(args, h264Args(codec)...)
+	return append(args, "-c:a", "aac", "-shortest", "-f", "flv", rtmpURL)
+}
+
+// h264Args are ffmpeg's arguments for H.264 video out of a track in codec:
+// H.264 is passed on as it is, and anything else is encoded
+func h264Args(codec interfaces.VideoCodec) []string {
+	if strings.EqualFold(codec.MimeType, "video/H264") {
+		return []string{"-c:v", "copy"}
+	}
+	return []string{
+		"-c:v", "libx264", "-preset", "veryfast", "-tune", "zerolatency",
+		"-pix_fmt", "yuv420p", "-g", keyframeInterval,
+	}
+}
+
+// RedactRTMPURL hides the stream key, the last element of an RTMP URL's
+// path, and any credentials, for logs
+func RedactRTMPURL(rtmpURL string) string {
+	parsed, err := url.Parse(rtmpURL)
+	if err != nil {
+		return "rtmp URL"
+	}
+	mask := ""
+	if i := strings.LastIndex(parsed.Path, "/"); i >= 0 && i < len(parsed.Path)-1 {
+		parsed.Path = parsed.Path[:i+1]
+		mask = "***"
+	}
+	parsed.RawPath = ""
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+	parsed.User = nil
+	return parsed.String() + mask
+}
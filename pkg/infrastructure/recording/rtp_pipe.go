@@ -0,0 +1,120 @@
+package recording
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// startRTPPipe starts binary reading a track's RTP packets from a loopback
+// UDP port, as described by a session description file whose path args
+// places on its command line; the returned sink fails with exited once the
+// process is gone
+func startRTPPipe(binary string, codec interfaces.VideoCodec, args func(sdpPath string) []string, exited error) (*rtpPipeSink, error) {
+	port, err := freeUDPPort()
+	if err != nil {
+		return nil, err
+	}
+
+	sdpFile, err := os.CreateTemp("", "share-screen-*.sdp")
+	if err != nil {
+		return nil, err
+	}
+	_, err = sdpFile.WriteString(sessionDescription(codec, port))
+	if closeErr := sdpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(sdpFile.Name())
+		return nil, err
+	}
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		_ = os.Remove(sdpFile.Name())
+		return nil, err
+	}
+
+	cmd := exec.Command(binary, args(sdpFile.Name())...)
+	if err := cmd.Start(); err != nil {
+		_ = conn.Close()
+		_ = os.Remove(sdpFile.Name())
+		return nil, fmt.Errorf("starting %s: %w", binary, err)
+	}
+
+	sink := &rtpPipeSink{
+		conn:       conn,
+		addr:       &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port},
+		cmd:        cmd,
+		sdpPath:    sdpFile.Name(),
+		exited:     make(chan struct{}),
+		exitedWith: exited,
+	}
+	go func() {
+		_ = cmd.Wait()
+		close(sink.exited)
+	}()
+	return sink, nil
+}
+
+// rtpPipeSink forwards packets to a running process
+type rtpPipeSink struct {
+	conn       *net.UDPConn
+	addr       *net.UDPAddr
+	cmd        *exec.Cmd
+	sdpPath    string
+	exited     chan struct{}
+	exitedWith error
+}
+
+// WriteRTP forwards one packet; packets sent before the process listens are
+// lost, which only delays the first picture until the next keyframe
+func (s *rtpPipeSink) WriteRTP(packet []byte) error {
+	select {
+	case <-s.exited:
+		return s.exitedWith
+	default:
+	}
+	_, err := s.conn.WriteToUDP(packet, s.addr)
+	return err
+}
+
+// Close stops the process and removes its session description
+func (s *rtpPipeSink) Close() error {
+	_ = s.conn.Close()
+	select {
+	case <-s.exited:
+	default:
+		_ = s.cmd.Process.Kill()
+		<-s.exited
+	}
+	return os.Remove(s.sdpPath)
+}
+
+// sessionDescription tells the process where the RTP stream arrives and how
+// it is encoded
+func sessionDescription(codec interfaces.VideoCodec, port int) string {
+	encoding := strings.TrimPrefix(codec.MimeType, "video/")
+	return fmt.Sprintf("v=0\r\n"+
+		"o=- 0 0 IN IP4 127.0.0.1\r\n"+
+		"s=share-screen\r\n"+
+		"c=IN IP4 127.0.0.1\r\n"+
+		"t=0 0\r\n"+
+		"m=video %d RTP/AVP %d\r\n"+
+		"a=rtpmap:%d %s/%d\r\n",
+		port, codec.PayloadType, codec.PayloadType, encoding, codec.ClockRate)
+}
+
+// freeUDPPort finds a loopback UDP port for the process to listen on
+func freeUDPPort() (int, error) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port, nil
+}
@@ -0,0 +1,74 @@
+package recording
+
+import (
+	"errors"
+	"log"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// errNoSinks is returned once every sink of a tee has failed
+var errNoSinks = errors.New("every output stopped")
+
+// Tee implements the VideoRecorder interface by passing a track on to several
+// recorders, such as an RTMP restream and an HLS packager
+type Tee struct {
+	recorders []interfaces.VideoRecorder
+}
+
+// NewTee creates a new tee of recorders
+func NewTee(recorders ...interfaces.VideoRecorder) interfaces.VideoRecorder {
+	return &Tee{recorders: recorders}
+}
+
+// Open opens the track on every recorder; if one fails, those already opened
+// are closed again
+func (t *Tee) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	sinks := make([]interfaces.VideoSink, 0, len(t.recorders))
+	for _, recorder := range t.recorders {
+		sink, err := recorder.Open(codec)
+		if err != nil {
+			for _, opened := range sinks {
+				_ = opened.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return &teeSink{sinks: sinks}, nil
+}
+
+// teeSink writes every packet to each sink still working
+type teeSink struct {
+	sinks []interfaces.VideoSink
+}
+
+// WriteRTP writes a packet to each sink. A sink that fails is closed and left
+// out from then on, so one refused restream does not stop the others; the
+// tee fails once none are left.
+func (s *teeSink) WriteRTP(packet []byte) error {
+	working := s.sinks[:0]
+	for _, sink := range s.sinks {
+		if err := sink.WriteRTP(packet); err != nil {
+			log.Printf("❌ Output stopped: %v", err)
+			_ = sink.Close()
+			continue
+		}
+		working = append(working, sink)
+	}
+	s.sinks = working
+	if len(s.sinks) == 0 {
+		return errNoSinks
+	}
+	return nil
+}
+
+// Close closes every sink still working
+func (s *teeSink) Close() error {
+	var errs []error
+	for _, sink := range s.sinks {
+		errs = append(errs, sink.Close())
+	}
+	s.sinks = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package recording
+
+import (
+	"errors"
+	"testing"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// fakeRecorder opens a fakeSink, or fails with openErr
+type fakeRecorder struct {
+	sink    *fakeSink
+	openErr error
+}
+
+func (r *fakeRecorder) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	if r.openErr != nil {
+		return nil, r.openErr
+	}
+	return r.sink, nil
+}
+
+// fakeSink counts what it is given
+type fakeSink struct {
+	written  int
+	writeErr error
+	closed   int
+}
+
+func (s *fakeSink) WriteRTP(packet []byte) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	s.written++
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestTee(t *testing.T) {
+	first, second := &fakeSink{}, &fakeSink{}
+	sink, err := NewTee(&fakeRecorder{sink: first}, &fakeRecorder{sink: second}).Open(vp8Codec)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if err := sink.WriteRTP([]byte{0x80}); err != nil {
+		t.Fatalf("WriteRTP failed: %v", err)
+	}
+	if first.written != 1 || second.written != 1 {
+		t.Fatalf("Expected the packet on both sinks, got %d and %d", first.written, second.written)
+	}
+
+	// A failing sink is dropped and the other carries on
+	first.writeErr = errors.New("refused")
+	if err := sink.WriteRTP([]byte{0x80}); err != nil {
+		t.Fatalf("Expected the tee to carry on, got %v", err)
+	}
+	if first.closed != 1 || second.written != 2 {
+		t.Errorf("Expected the failed sink closed and the other written, got %+v and %+v", first, second)
+	}
+
+	second.writeErr = errors.New("gone")
+	if err := sink.WriteRTP([]byte{0x80}); err == nil {
+		t.Error("Expected an error once every sink failed")
+	}
+	if err := sink.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if first.closed != 1 || second.closed != 1 {
+		t.Errorf("Expected each sink closed once, got %d and %d", first.closed, second.closed)
+	}
+}
+
+func TestTee_OpenFails(t *testing.T) {
+	opened := &fakeSink{}
+	_, err := NewTee(&fakeRecorder{sink: opened}, &fakeRecorder{openErr: errors.New("no ffmpeg")}).Open(vp8Codec)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if opened.closed != 1 {
+		t.Error("Expected the opened sink to be closed again")
+	}
+}
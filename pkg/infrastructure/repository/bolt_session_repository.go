@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// boltOpenTimeout is how long opening the database waits for another process
+// holding it, instead of blocking forever
+const boltOpenTimeout = time.Second
+
+var (
+	// boltStatusBuckets holds one bucket per session status, keyed by token
+	boltStatusBuckets = []byte("sessions")
+	// boltIndexBucket maps each token to the status bucket holding it
+	boltIndexBucket = []byte("index")
+	// boltExpiryBucket orders the tokens by expiry: the keys are the expiry
+	// in Unix nanoseconds, big-endian, followed by the token
+	boltExpiryBucket = []byte("expiry")
+)
+
+// BoltSessionRepository implements SessionRepository on a bbolt database
+// file, so sessions survive restarts without a database server. Sessions
+// are stored as JSON in a bucket per status, next to an index from token to
+// status and an expiry index that lets cleanup sweep expired sessions
+// without reading the live ones.
+type BoltSessionRepository struct {
+	db     *bolt.DB
+	tokens entities.TokenPolicy
+}
+
+// NewBoltSessionRepository opens or creates the database at path and hands
+// out tokens of the given policy
+func NewBoltSessionRepository(path string, tokens entities.TokenPolicy) (*BoltSessionRepository, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		statuses, err := tx.CreateBucketIfNotExists(boltStatusBuckets)
+		if err != nil {
+			return err
+		}
+		for _, status := range []entities.SessionStatus{
+			entities.SessionStatusPending, entities.SessionStatusActive, entities.SessionStatusCompleted,
+			entities.SessionStatusExpired, entities.SessionStatusEnded, entities.SessionStatusStale,
+		} {
+			if _, err := statuses.CreateBucketIfNotExists([]byte(status)); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.CreateBucketIfNotExists(boltIndexBucket); err != nil {
+			return err
+		}
+		_, err = tx.CreateBucketIfNotExists(boltExpiryBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &BoltSessionRepository{db: db, tokens: tokens}, nil
+}
+
+// Close closes the database
+func (r *BoltSessionRepository) Close() error {
+	return r.db.Close()
+}
+
+// CreateSession creates a new session with a unique token
+func (r *BoltSessionRepository) CreateSession(expiryDuration time.Duration) (*entities.Session, error) {
+	session, err := newSession(r.tokens, expiryDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Update(func(tx *bolt.Tx) error {
+		return putSession(tx, session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSession retrieves a session by token
+func (r *BoltSessionRepository) GetSession(token string) (*entities.Session, error) {
+	var session *entities.Session
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		session, err = getSession(tx, token)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// UpdateSession updates an existing session, moving it to the bucket of its
+// new status
+func (r *BoltSessionRepository) UpdateSession(session *entities.Session) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteSession(tx, session.Token); err != nil {
+			return err
+		}
+		return putSession(tx, session)
+	})
+}
+
+// DeleteSession removes a session
+func (r *BoltSessionRepository) DeleteSession(token string) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteSession(tx, token); err != ErrSessionNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// CleanupExpiredSessions removes all expired sessions, walking the expiry
+// index up to now
+func (r *BoltSessionRepository) CleanupExpiredSessions() (int, error) {
+	var expiredTokens []string
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		now := expiryKey(time.Now(), "")
+		cursor := tx.Bucket(boltExpiryBucket).Cursor()
+		for key, _ := cursor.First(); key != nil && bytes.Compare(key[:8], now[:8]) < 0; key, _ = cursor.First() {
+			token := string(key[8:])
+			if err := deleteSession(tx, token); err == ErrSessionNotFound {
+				// An entry left behind by an interrupted write
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+			expiredTokens = append(expiredTokens, token)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(expiredTokens) > 0 {
+		truncatedTokens := make([]string, 0, len(expiredTokens))
+		for _, token := range expiredTokens {
+			truncatedTokens = append(truncatedTokens, entities.ShortToken(token))
+		}
+		log.Printf("🗑️  GC: cleaned up %d expired tokens: %v", len(expiredTokens), truncatedTokens)
+	}
+	return len(expiredTokens), nil
+}
+
+// GetActiveSessionsCount returns the number of active sessions; only the
+// bucket of active sessions is read
+func (r *BoltSessionRepository) GetActiveSessionsCount() (int, error) {
+	count := 0
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltStatusBuckets).Bucket([]byte(entities.SessionStatusActive))
+		return bucket.ForEach(func(_, value []byte) error {
+			var session entities.Session
+			if err := json.Unmarshal(value, &session); err != nil {
+				return err
+			}
+			if session.IsActive() {
+				count++
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+// ListSessions returns all stored sessions
+func (r *BoltSessionRepository) ListSessions() ([]*entities.Session, error) {
+	var sessions []*entities.Session
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltStatusBuckets).ForEachBucket(func(status []byte) error {
+			return tx.Bucket(boltStatusBuckets).Bucket(status).ForEach(func(_, value []byte) error {
+				var session entities.Session
+				if err := json.Unmarshal(value, &session); err != nil {
+					return err
+				}
+				sessions = append(sessions, &session)
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RestoreSession stores a session under its own token, replacing any session
+// with that token
+func (r *BoltSessionRepository) RestoreSession(session *entities.Session) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteSession(tx, session.Token); err != nil && err != ErrSessionNotFound {
+			return err
+		}
+		return putSession(tx, session)
+	})
+}
+
+// getSession reads a session through the token index
+func getSession(tx *bolt.Tx, token string) (*entities.Session, error) {
+	status := tx.Bucket(boltIndexBucket).Get([]byte(token))
+	if status == nil {
+		return nil, ErrSessionNotFound
+	}
+	value := tx.Bucket(boltStatusBuckets).Bucket(status).Get([]byte(token))
+	if value == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	var session entities.Session
+	if err := json.Unmarshal(value, &session); err != nil {
+		return nil, fmt.Errorf("reading session %s: %w", entities.ShortToken(token), err)
+	}
+	return &session, nil
+}
+
+// putSession writes a session to the bucket of its status and indexes it
+func putSession(tx *bolt.Tx, session *entities.Session) error {
+	if !session.Status.IsValid() {
+		return fmt.Errorf("unknown session status %q", session.Status)
+	}
+	value, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	token := []byte(session.Token)
+	if err := tx.Bucket(boltStatusBuckets).Bucket([]byte(session.Status)).Put(token, value); err != nil {
+		return err
+	}
+	if err := tx.Bucket(boltIndexBucket).Put(token, []byte(session.Status)); err != nil {
+		return err
+	}
+	return tx.Bucket(boltExpiryBucket).Put(expiryKey(session.ExpiresAt, session.Token), nil)
+}
+
+// deleteSession removes a session and its index entries
+func deleteSession(tx *bolt.Tx, token string) error {
+	session, err := getSession(tx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(boltStatusBuckets).Bucket([]byte(session.Status)).Delete([]byte(token)); err != nil {
+		return err
+	}
+	if err := tx.Bucket(boltIndexBucket).Delete([]byte(token)); err != nil {
+		return err
+	}
+	return tx.Bucket(boltExpiryBucket).Delete(expiryKey(session.ExpiresAt, token))
+}
+
+// expiryKey is the key of a token in the expiry index; expiries before 1970
+// sort first
+func expiryKey(expiresAt time.Time, token string) []byte {
+	var nanos int64
+	if expiresAt.After(time.Unix(0, 0)) {
+		nanos = expiresAt.UnixNano()
+	}
+	key := make([]byte, 8, 8+len(token))
+	binary.BigEndian.PutUint64(key, uint64(nanos))
+	return append(key, token...)
+}
+
+// Ensure BoltSessionRepository implements SessionRepository
+var _ interfaces.SessionRepository = (*BoltSessionRepository)(nil)
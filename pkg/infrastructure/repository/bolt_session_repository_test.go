@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// newTestBoltRepository opens a bolt repository in a temporary directory
+func newTestBoltRepository(t *testing.T) (*BoltSessionRepository, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	repo, err := NewBoltSessionRepository(path, entities.TokenPolicy{})
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	return repo, path
+}
+
+func TestBoltSessionRepository_CreateAndGet(t *testing.T) {
+	repo, _ := newTestBoltRepository(t)
+
+	session, err := repo.CreateSession(30 * time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !(entities.TokenPolicy{}).Valid(session.Token) || session.Status != entities.SessionStatusPending {
+		t.Errorf("Unexpected session: %+v", session)
+	}
+
+	stored, err := repo.GetSession(session.Token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored.Token != session.Token || !stored.ExpiresAt.Equal(session.ExpiresAt) {
+		t.Errorf("Expected the created session, got %+v", stored)
+	}
+
+	if _, err := repo.GetSession("missing"); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestBoltSessionRepository_UpdateSession(t *testing.T) {
+	repo, _ := newTestBoltRepository(t)
+	session, _ := repo.CreateSession(30 * time.Minute)
+
+	session.Status = entities.SessionStatusActive
+	session.Name = "Standup"
+	session.ExpiresAt = session.ExpiresAt.Add(time.Hour)
+	if err := repo.UpdateSession(session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stored, _ := repo.GetSession(session.Token)
+	if stored.Status != entities.SessionStatusActive || stored.Name != "Standup" {
+		t.Errorf("Expected the updated session, got %+v", stored)
+	}
+
+	// The session moved to the bucket of its new status, and the expiry
+	// index follows the extended expiry
+	repo.db.View(func(tx *bolt.Tx) error {
+		statuses := tx.Bucket(boltStatusBuckets)
+		if statuses.Bucket([]byte(entities.SessionStatusPending)).Get([]byte(session.Token)) != nil {
+			t.Error("Expected the session to leave the pending bucket")
+		}
+		if statuses.Bucket([]byte(entities.SessionStatusActive)).Get([]byte(session.Token)) == nil {
+			t.Error("Expected the session in the active bucket")
+		}
+		if n := tx.Bucket(boltExpiryBucket).Stats().KeyN; n != 1 {
+			t.Errorf("Expected 1 expiry entry, got %d", n)
+		}
+		return nil
+	})
+
+	if err := repo.UpdateSession(&entities.Session{Token: "missing", Status: entities.SessionStatusActive}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestBoltSessionRepository_DeleteSession(t *testing.T) {
+	repo, _ := newTestBoltRepository(t)
+	session, _ := repo.CreateSession(30 * time.Minute)
+
+	if err := repo.DeleteSession(session.Token); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := repo.GetSession(session.Token); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	if err := repo.DeleteSession(session.Token); err != nil {
+		t.Errorf("Deleting a missing session should succeed, got %v", err)
+	}
+}
+
+func TestBoltSessionRepository_CleanupExpiredSessions(t *testing.T) {
+	repo, _ := newTestBoltRepository(t)
+	live, _ := repo.CreateSession(30 * time.Minute)
+	for _, token := range []string{"expired-1", "expired-2"} {
+		repo.RestoreSession(&entities.Session{
+			Token:     token,
+			ExpiresAt: time.Now().Add(-time.Minute),
+			Status:    entities.SessionStatusActive,
+		})
+	}
+
+	cleaned, err := repo.CleanupExpiredSessions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cleaned != 2 {
+		t.Errorf("Expected 2 sessions cleaned up, got %d", cleaned)
+	}
+	if _, err := repo.GetSession("expired-1"); err != ErrSessionNotFound {
+		t.Errorf("Expected the expired session to be removed, got %v", err)
+	}
+	if _, err := repo.GetSession(live.Token); err != nil {
+		t.Errorf("Expected the live session to be kept, got %v", err)
+	}
+}
+
+func TestBoltSessionRepository_CountAndList(t *testing.T) {
+	repo, _ := newTestBoltRepository(t)
+	pending, _ := repo.CreateSession(30 * time.Minute)
+	active, _ := repo.CreateSession(30 * time.Minute)
+	active.Status = entities.SessionStatusActive
+	repo.UpdateSession(active)
+
+	count, err := repo.GetActiveSessionsCount()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 active session, got %d", count)
+	}
+
+	sessions, err := repo.ListSessions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tokens := map[string]bool{}
+	for _, session := range sessions {
+		tokens[session.Token] = true
+	}
+	if len(sessions) != 2 || !tokens[pending.Token] || !tokens[active.Token] {
+		t.Errorf("Expected both sessions listed, got %+v", sessions)
+	}
+}
+
+func TestBoltSessionRepository_Reopen(t *testing.T) {
+	repo, path := newTestBoltRepository(t)
+	session, _ := repo.CreateSession(30 * time.Minute)
+	session.Status = entities.SessionStatusActive
+	repo.UpdateSession(session)
+	repo.Close()
+
+	reopened, err := NewBoltSessionRepository(path, entities.TokenPolicy{})
+	if err != nil {
+		t.Fatalf("Failed to reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	stored, err := reopened.GetSession(session.Token)
+	if err != nil {
+		t.Fatalf("Session lost across reopening: %v", err)
+	}
+	if stored.Status != entities.SessionStatusActive {
+		t.Errorf("Expected the active session, got %+v", stored)
+	}
+}
+
+func TestBoltSessionRepository_Locked(t *testing.T) {
+	_, path := newTestBoltRepository(t)
+
+	// A second server on the same file must fail instead of waiting forever
+	if _, err := NewBoltSessionRepository(path, entities.TokenPolicy{}); err == nil {
+		t.Error("Expected the locked database to be refused")
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileBanRepository implements BanRepository in memory, writing the list to
+// a JSON file on every change so bans survive restarts
+type FileBanRepository struct {
+	mu   sync.RWMutex
+	path string
+	bans []*entities.Ban
+}
+
+// NewFileBanRepository creates a ban repository backed by the file at path,
+// loading the bans it holds; a missing file is an empty list, and an empty
+// path keeps the bans in memory only
+func NewFileBanRepository(path string) (interfaces.BanRepository, error) {
+	r := &FileBanRepository{path: path}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.bans); err != nil {
+		return nil, fmt.Errorf("reading ban list %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ListBans returns every ban, oldest first
+func (r *FileBanRepository) ListBans() ([]*entities.Ban, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bans := make([]*entities.Ban, 0, len(r.bans))
+	for _, ban := range r.bans {
+		banCopy := *ban
+		bans = append(bans, &banCopy)
+	}
+	return bans, nil
+}
+
+// SaveBan stores a ban, replacing any ban of the same network
+func (r *FileBanRepository) SaveBan(ban *entities.Ban) error {
+	banCopy := *ban
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bans := slices.Clone(r.bans)
+	if i := r.index(ban.Network); i >= 0 {
+		bans[i] = &banCopy
+	} else {
+		bans = append(bans, &banCopy)
+	}
+	return r.store(bans)
+}
+
+// DeleteBan removes the ban of a network
+func (r *FileBanRepository) DeleteBan(network string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(network)
+	if i < 0 {
+		return ErrBanNotFound
+	}
+	return r.store(slices.Delete(slices.Clone(r.bans), i, i+1))
+}
+
+// index returns the position of a network's ban, or -1
+func (r *FileBanRepository) index(network string) int {
+	return slices.IndexFunc(r.bans, func(ban *entities.Ban) bool { return ban.Network == network })
+}
+
+// store writes bans to the file and, once that worked, keeps them; the file
+// is written to a temporary file and renamed into place, so a crash never
+// leaves a half-written list
+func (r *FileBanRepository) store(bans []*entities.Ban) error {
+	if r.path != "" {
+		data, err := json.MarshalIndent(bans, "", "  ")
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(r.path), ".bans-*.json")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), r.path); err != nil {
+			return err
+		}
+	}
+	r.bans = bans
+	return nil
+}
+
+// ErrBanNotFound is returned when no ban has the given network
+var ErrBanNotFound = &RepositoryError{Message: "ban not found"}
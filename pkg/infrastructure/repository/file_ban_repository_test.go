@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileBanRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	repo, err := NewFileBanRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open a missing ban file: %v", err)
+	}
+	if bans, _ := repo.ListBans(); len(bans) != 0 {
+		t.Fatalf("Expected no bans, got %d", len(bans))
+	}
+
+	for _, network := range []string{"192.168.1.50/32", "10.0.0.0/24"} {
+		if err := repo.SaveBan(&entities.Ban{Network: network, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Failed to save ban: %v", err)
+		}
+	}
+	// Saving a network again replaces its ban
+	if err := repo.SaveBan(&entities.Ban{Network: "192.168.1.50/32", Reason: "spam"}); err != nil {
+		t.Fatalf("Failed to update ban: %v", err)
+	}
+	if err := repo.DeleteBan("10.0.0.0/24"); err != nil {
+		t.Fatalf("Failed to delete ban: %v", err)
+	}
+	if err := repo.DeleteBan("10.0.0.0/24"); err != ErrBanNotFound {
+		t.Errorf("Expected ErrBanNotFound for a lifted ban, got %v", err)
+	}
+
+	// The bans survive a restart
+	reopened, err := NewFileBanRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen ban file: %v", err)
+	}
+	bans, _ := reopened.ListBans()
+	if len(bans) != 1 || bans[0].Network != "192.168.1.50/32" || bans[0].Reason != "spam" {
+		t.Errorf("Expected the updated ban only, got %+v", bans)
+	}
+
+	// The returned list is a copy
+	bans[0].Reason = "changed"
+	if again, _ := reopened.ListBans(); again[0].Reason != "spam" {
+		t.Error("Expected the stored ban to be unaffected by changes to a listed copy")
+	}
+}
+
+func TestFileBanRepository_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bans.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileBanRepository(path); err == nil {
+		t.Error("Expected an error for a corrupt ban file")
+	}
+}
+
+func TestFileBanRepository_MemoryOnly(t *testing.T) {
+	repo, err := NewFileBanRepository("")
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if err := repo.SaveBan(&entities.Ban{Network: "10.0.0.1/32"}); err != nil {
+		t.Fatalf("Failed to save ban: %v", err)
+	}
+	if bans, _ := repo.ListBans(); len(bans) != 1 {
+		t.Errorf("Expected the ban in memory, got %d", len(bans))
+	}
+}
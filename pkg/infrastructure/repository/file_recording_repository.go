@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileRecordingRepository implements RecordingRepository over the directory
+// the SFU records sessions into. Recordings still being written end in
+// .part and are left out until they are finished.
+type FileRecordingRepository struct {
+	dir string
+}
+
+// NewFileRecordingRepository creates a recording repository for dir
+func NewFileRecordingRepository(dir string) interfaces.RecordingRepository {
+	return &FileRecordingRepository{dir: dir}
+}
+
+// ListRecordings returns every finished recording, oldest first
+func (r *FileRecordingRepository) ListRecordings() ([]*entities.Recording, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	recordings := make([]*entities.Recording, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Deleted since the directory was read
+			continue
+		}
+		if recording, ok := newRecording(info); ok {
+			recordings = append(recordings, recording)
+		}
+	}
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].StartedAt.Before(recordings[j].StartedAt)
+	})
+	return recordings, nil
+}
+
+// OpenRecording returns a recording and its content
+func (r *FileRecordingRepository) OpenRecording(name string) (*entities.Recording, io.ReadSeekCloser, error) {
+	if _, _, ok := entities.ParseRecordingName(name); !ok {
+		return nil, nil, ErrRecordingNotFound
+	}
+	file, err := os.Open(filepath.Join(r.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrRecordingNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+	recording, ok := newRecording(info)
+	if !ok {
+		_ = file.Close()
+		return nil, nil, ErrRecordingNotFound
+	}
+	return recording, file, nil
+}
+
+// DeleteRecording removes a recording
+func (r *FileRecordingRepository) DeleteRecording(name string) error {
+	if _, _, ok := entities.ParseRecordingName(name); !ok {
+		return ErrRecordingNotFound
+	}
+	err := os.Remove(filepath.Join(r.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrRecordingNotFound
+	}
+	return err
+}
+
+// newRecording describes the file behind info, and reports false when it is
+// not a finished recording
+func newRecording(info fs.FileInfo) (*entities.Recording, bool) {
+	token, start, ok := entities.ParseRecordingName(info.Name())
+	if !ok || !info.Mode().IsRegular() {
+		return nil, false
+	}
+	return &entities.Recording{
+		Name:      info.Name(),
+		Token:     token,
+		Size:      info.Size(),
+		StartedAt: start,
+		EndedAt:   info.ModTime(),
+	}, true
+}
+
+// ErrRecordingNotFound is returned when no finished recording has the given
+// name
+var ErrRecordingNotFound = &RepositoryError{Message: "recording not found"}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileRecordingRepository(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	later := entities.RecordingName("later-token", start.Add(time.Hour))
+	first := entities.RecordingName("first-token", start)
+	for name, content := range map[string]string{
+		later: "later video",
+		first: "first video",
+		entities.RecordingName("live", start) + ".part": "still recording",
+		"notes.txt": "not a recording",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := NewFileRecordingRepository(dir)
+
+	recordings, err := repo.ListRecordings()
+	if err != nil {
+		t.Fatalf("ListRecordings failed: %v", err)
+	}
+	if len(recordings) != 2 || recordings[0].Name != first || recordings[1].Name != later {
+		t.Fatalf("Expected the two finished recordings, oldest first, got %+v", recordings)
+	}
+	if recordings[0].Token != "first-token" || !recordings[0].StartedAt.Equal(start) || recordings[0].Size != int64(len("first video")) {
+		t.Errorf("Unexpected recording: %+v", recordings[0])
+	}
+
+	recording, content, err := repo.OpenRecording(first)
+	if err != nil {
+		t.Fatalf("OpenRecording failed: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	_ = content.Close()
+	if recording.Name != first || string(data) != "first video" {
+		t.Errorf("Expected the first recording, got %+v %q", recording, data)
+	}
+	for _, name := range []string{"notes.txt", "../" + first, entities.RecordingName("live", start) + ".part", entities.RecordingName("missing", start)} {
+		if _, _, err := repo.OpenRecording(name); err != ErrRecordingNotFound {
+			t.Errorf("Expected ErrRecordingNotFound for %q, got %v", name, err)
+		}
+	}
+
+	if err := repo.DeleteRecording(first); err != nil {
+		t.Fatalf("DeleteRecording failed: %v", err)
+	}
+	if err := repo.DeleteRecording(first); err != ErrRecordingNotFound {
+		t.Errorf("Expected ErrRecordingNotFound for a deleted recording, got %v", err)
+	}
+	if err := repo.DeleteRecording("notes.txt"); err != ErrRecordingNotFound {
+		t.Errorf("Expected ErrRecordingNotFound for another file, got %v", err)
+	}
+	if recordings, _ := repo.ListRecordings(); len(recordings) != 1 {
+		t.Errorf("Expected one recording left, got %d", len(recordings))
+	}
+}
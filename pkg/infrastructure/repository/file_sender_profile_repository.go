@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileSenderProfileRepository implements SenderProfileRepository in memory,
+// writing the profiles to a JSON file on every change so they survive
+// restarts
+type FileSenderProfileRepository struct {
+	mu       sync.RWMutex
+	path     string
+	profiles map[string]*entities.SenderProfile
+}
+
+// NewFileSenderProfileRepository creates a profile repository backed by the
+// file at path, loading the profiles it holds; a missing file holds no
+// profiles, and an empty path keeps them in memory only
+func NewFileSenderProfileRepository(path string) (interfaces.SenderProfileRepository, error) {
+	r := &FileSenderProfileRepository{path: path, profiles: make(map[string]*entities.SenderProfile)}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profiles []*entities.SenderProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("reading sender profiles %s: %w", path, err)
+	}
+	for _, profile := range profiles {
+		r.profiles[profile.ID] = profile
+	}
+	return r, nil
+}
+
+// GetProfile returns the profile with the given ID
+func (r *FileSenderProfileRepository) GetProfile(id string) (*entities.SenderProfile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	profile, exists := r.profiles[id]
+	if !exists {
+		return nil, ErrSenderProfileNotFound
+	}
+	profileCopy := *profile
+	return &profileCopy, nil
+}
+
+// SaveProfile stores a profile, replacing any profile with the same ID
+func (r *FileSenderProfileRepository) SaveProfile(profile *entities.SenderProfile) error {
+	profileCopy := *profile
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	profiles := maps.Clone(r.profiles)
+	profiles[profile.ID] = &profileCopy
+	return r.store(profiles)
+}
+
+// DeleteProfile removes the profile with the given ID
+func (r *FileSenderProfileRepository) DeleteProfile(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.profiles[id]; !exists {
+		return ErrSenderProfileNotFound
+	}
+	profiles := maps.Clone(r.profiles)
+	delete(profiles, id)
+	return r.store(profiles)
+}
+
+// store writes profiles to the file, by ID so the file diffs cleanly, and,
+// once that worked, keeps them; the file is written to a temporary file and
+// renamed into place, so a crash never leaves a half-written file
+func (r *FileSenderProfileRepository) store(profiles map[string]*entities.SenderProfile) error {
+	if r.path != "" {
+		sorted := slices.AppendSeq(make([]*entities.SenderProfile, 0, len(profiles)), maps.Values(profiles))
+		slices.SortFunc(sorted, func(a, b *entities.SenderProfile) int { return strings.Compare(a.ID, b.ID) })
+		data, err := json.MarshalIndent(sorted, "", "  ")
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(r.path), ".profiles-*.json")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), r.path); err != nil {
+			return err
+		}
+	}
+	r.profiles = profiles
+	return nil
+}
+
+// ErrSenderProfileNotFound is returned when no profile has the given ID
+var ErrSenderProfileNotFound = &RepositoryError{Message: "sender profile not found"}
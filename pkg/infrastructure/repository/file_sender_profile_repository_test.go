@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileSenderProfileRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	repo, err := NewFileSenderProfileRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open a missing profile file: %v", err)
+	}
+	if _, err := repo.GetProfile("first"); err != ErrSenderProfileNotFound {
+		t.Fatalf("Expected ErrSenderProfileNotFound, got %v", err)
+	}
+
+	for _, id := range []string{"first", "second", "third"} {
+		if err := repo.SaveProfile(&entities.SenderProfile{ID: id}); err != nil {
+			t.Fatalf("Failed to save profile: %v", err)
+		}
+	}
+	// Saving an ID again replaces its profile
+	if err := repo.SaveProfile(&entities.SenderProfile{ID: "first", Preset: "text", Audio: true}); err != nil {
+		t.Fatalf("Failed to update profile: %v", err)
+	}
+	if err := repo.DeleteProfile("third"); err != nil {
+		t.Fatalf("Failed to delete profile: %v", err)
+	}
+	if err := repo.DeleteProfile("third"); err != ErrSenderProfileNotFound {
+		t.Errorf("Expected ErrSenderProfileNotFound for a deleted profile, got %v", err)
+	}
+
+	// The profiles survive a restart
+	reopened, err := NewFileSenderProfileRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen profile file: %v", err)
+	}
+	first, err := reopened.GetProfile("first")
+	if err != nil || first.Preset != "text" || !first.Audio {
+		t.Fatalf("Expected the updated first profile, got %+v, %v", first, err)
+	}
+	if _, err := reopened.GetProfile("second"); err != nil {
+		t.Errorf("Expected the second profile, got %v", err)
+	}
+	if _, err := reopened.GetProfile("third"); err != ErrSenderProfileNotFound {
+		t.Errorf("Expected the third profile to stay deleted, got %v", err)
+	}
+
+	// Returned profiles are copies
+	first.Audio = false
+	if again, _ := reopened.GetProfile("first"); !again.Audio {
+		t.Error("Expected the stored profile to be unaffected by changes to a copy")
+	}
+}
+
+func TestFileSenderProfileRepository_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSenderProfileRepository(path); err == nil {
+		t.Error("Expected an error for a corrupt profile file")
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileSessionTemplateRepository implements SessionTemplateRepository in
+// memory, writing the templates to a JSON file on every change so they
+// survive restarts
+type FileSessionTemplateRepository struct {
+	mu        sync.RWMutex
+	path      string
+	templates []*entities.SessionTemplate
+}
+
+// NewFileSessionTemplateRepository creates a template repository backed by
+// the file at path, loading the templates it holds; a missing file holds no
+// templates, and an empty path keeps them in memory only
+func NewFileSessionTemplateRepository(path string) (interfaces.SessionTemplateRepository, error) {
+	r := &FileSessionTemplateRepository{path: path}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.templates); err != nil {
+		return nil, fmt.Errorf("reading session templates %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// ListTemplates returns every template, by name
+func (r *FileSessionTemplateRepository) ListTemplates() ([]*entities.SessionTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	templates := make([]*entities.SessionTemplate, 0, len(r.templates))
+	for _, template := range r.templates {
+		templateCopy := *template
+		templates = append(templates, &templateCopy)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns the template with the given name
+func (r *FileSessionTemplateRepository) GetTemplate(name string) (*entities.SessionTemplate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.index(name)
+	if i < 0 {
+		return nil, ErrSessionTemplateNotFound
+	}
+	templateCopy := *r.templates[i]
+	return &templateCopy, nil
+}
+
+// SaveTemplate stores a template, replacing any template of the same name
+func (r *FileSessionTemplateRepository) SaveTemplate(template *entities.SessionTemplate) error {
+	templateCopy := *template
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	templates := slices.Clone(r.templates)
+	if i := r.index(template.Name); i >= 0 {
+		templates[i] = &templateCopy
+	} else {
+		templates = append(templates, &templateCopy)
+		slices.SortFunc(templates, func(a, b *entities.SessionTemplate) int { return strings.Compare(a.Name, b.Name) })
+	}
+	return r.store(templates)
+}
+
+// DeleteTemplate removes the template with the given name
+func (r *FileSessionTemplateRepository) DeleteTemplate(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(name)
+	if i < 0 {
+		return ErrSessionTemplateNotFound
+	}
+	return r.store(slices.Delete(slices.Clone(r.templates), i, i+1))
+}
+
+// index returns the position of the named template, or -1
+func (r *FileSessionTemplateRepository) index(name string) int {
+	return slices.IndexFunc(r.templates, func(template *entities.SessionTemplate) bool { return template.Name == name })
+}
+
+// store writes templates to the file and, once that worked, keeps them; the
+// file is written to a temporary file and renamed into place, so a crash
+// never leaves a half-written file
+func (r *FileSessionTemplateRepository) store(templates []*entities.SessionTemplate) error {
+	if r.path != "" {
+		data, err := json.MarshalIndent(templates, "", "  ")
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(r.path), ".templates-*.json")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), r.path); err != nil {
+			return err
+		}
+	}
+	r.templates = templates
+	return nil
+}
+
+// ErrSessionTemplateNotFound is returned when no template has the given name
+var ErrSessionTemplateNotFound = &RepositoryError{Message: "session template not found"}
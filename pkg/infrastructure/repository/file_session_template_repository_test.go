@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileSessionTemplateRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	repo, err := NewFileSessionTemplateRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open a missing template file: %v", err)
+	}
+	if templates, _ := repo.ListTemplates(); len(templates) != 0 {
+		t.Fatalf("Expected no templates, got %d", len(templates))
+	}
+
+	for _, name := range []string{"webinar", "meeting", "demo"} {
+		if err := repo.SaveTemplate(&entities.SessionTemplate{Name: name}); err != nil {
+			t.Fatalf("Failed to save template: %v", err)
+		}
+	}
+	// Saving a name again replaces its template
+	if err := repo.SaveTemplate(&entities.SessionTemplate{Name: "meeting", RequirePIN: true}); err != nil {
+		t.Fatalf("Failed to update template: %v", err)
+	}
+	if err := repo.DeleteTemplate("demo"); err != nil {
+		t.Fatalf("Failed to delete template: %v", err)
+	}
+	if err := repo.DeleteTemplate("demo"); err != ErrSessionTemplateNotFound {
+		t.Errorf("Expected ErrSessionTemplateNotFound for a deleted template, got %v", err)
+	}
+
+	// The templates survive a restart, listed by name
+	reopened, err := NewFileSessionTemplateRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen template file: %v", err)
+	}
+	templates, _ := reopened.ListTemplates()
+	if len(templates) != 2 || templates[0].Name != "meeting" || !templates[0].RequirePIN || templates[1].Name != "webinar" {
+		t.Errorf("Expected the meeting and webinar templates, got %+v", templates)
+	}
+
+	// Returned templates are copies
+	meeting, err := reopened.GetTemplate("meeting")
+	if err != nil {
+		t.Fatalf("Failed to get template: %v", err)
+	}
+	meeting.RequirePIN = false
+	if again, _ := reopened.GetTemplate("meeting"); !again.RequirePIN {
+		t.Error("Expected the stored template to be unaffected by changes to a copy")
+	}
+	if _, err := reopened.GetTemplate("missing"); err != ErrSessionTemplateNotFound {
+		t.Errorf("Expected ErrSessionTemplateNotFound, got %v", err)
+	}
+}
+
+func TestFileSessionTemplateRepository_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileSessionTemplateRepository(path); err == nil {
+		t.Error("Expected an error for a corrupt template file")
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// FileTrustedDeviceRepository implements TrustedDeviceRepository in memory,
+// writing the pairings to a JSON file on every change so they survive
+// restarts
+type FileTrustedDeviceRepository struct {
+	mu      sync.RWMutex
+	path    string
+	devices map[string]*entities.TrustedDevice
+}
+
+// NewFileTrustedDeviceRepository creates a trusted device repository backed
+// by the file at path, loading the pairings it holds; a missing file holds
+// none, and an empty path keeps them in memory only
+func NewFileTrustedDeviceRepository(path string) (interfaces.TrustedDeviceRepository, error) {
+	r := &FileTrustedDeviceRepository{path: path, devices: make(map[string]*entities.TrustedDevice)}
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var devices []*entities.TrustedDevice
+	if err := json.Unmarshal(data, &devices); err != nil {
+		return nil, fmt.Errorf("reading trusted devices %s: %w", path, err)
+	}
+	for _, device := range devices {
+		r.devices[device.ID] = device
+	}
+	return r, nil
+}
+
+// ListDevices returns the devices a sender trusts, oldest pairing first
+func (r *FileTrustedDeviceRepository) ListDevices(owner string) ([]*entities.TrustedDevice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	devices := make([]*entities.TrustedDevice, 0)
+	for _, device := range r.devices {
+		if device.Owner == owner {
+			deviceCopy := *device
+			devices = append(devices, &deviceCopy)
+		}
+	}
+	slices.SortFunc(devices, func(a, b *entities.TrustedDevice) int {
+		return a.PairedAt.Compare(b.PairedAt)
+	})
+	return devices, nil
+}
+
+// FindDevice returns the pairing of a viewer device with a sender
+func (r *FileTrustedDeviceRepository) FindDevice(owner, deviceID string) (*entities.TrustedDevice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, device := range r.devices {
+		if device.Owner == owner && device.DeviceID == deviceID {
+			deviceCopy := *device
+			return &deviceCopy, nil
+		}
+	}
+	return nil, ErrTrustedDeviceNotFound
+}
+
+// SaveDevice stores a pairing, replacing any pairing with the same ID
+func (r *FileTrustedDeviceRepository) SaveDevice(device *entities.TrustedDevice) error {
+	deviceCopy := *device
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	devices := maps.Clone(r.devices)
+	devices[device.ID] = &deviceCopy
+	return r.store(devices)
+}
+
+// DeleteDevice removes the pairing with the given ID from a sender's devices
+func (r *FileTrustedDeviceRepository) DeleteDevice(owner, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if device, exists := r.devices[id]; !exists || device.Owner != owner {
+		return ErrTrustedDeviceNotFound
+	}
+	devices := maps.Clone(r.devices)
+	delete(devices, id)
+	return r.store(devices)
+}
+
+// store writes devices to the file, by ID so the file diffs cleanly, and,
+// once that worked, keeps them; the file is written to a temporary file and
+// renamed into place, so a crash never leaves a half-written file
+func (r *FileTrustedDeviceRepository) store(devices map[string]*entities.TrustedDevice) error {
+	if r.path != "" {
+		sorted := slices.AppendSeq(make([]*entities.TrustedDevice, 0, len(devices)), maps.Values(devices))
+		slices.SortFunc(sorted, func(a, b *entities.TrustedDevice) int { return strings.Compare(a.ID, b.ID) })
+		data, err := json.MarshalIndent(sorted, "", "  ")
+		if err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(r.path), ".devices-*.json")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			_ = tmp.Close()
+			return err
+		}
+		if err := tmp.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp.Name(), r.path); err != nil {
+			return err
+		}
+	}
+	r.devices = devices
+	return nil
+}
+
+// ErrTrustedDeviceNotFound is returned when a sender has no such pairing
+var ErrTrustedDeviceNotFound = &RepositoryError{Message: "trusted device not found"}
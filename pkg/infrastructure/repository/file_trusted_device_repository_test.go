@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileTrustedDeviceRepository(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devices.json")
+	repo, err := NewFileTrustedDeviceRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to open a missing device file: %v", err)
+	}
+	if _, err := repo.FindDevice("alice", "tablet"); err != ErrTrustedDeviceNotFound {
+		t.Fatalf("Expected ErrTrustedDeviceNotFound, got %v", err)
+	}
+
+	now := time.Now()
+	devices := []*entities.TrustedDevice{
+		{ID: "p2", Owner: "alice", DeviceID: "phone", PairedAt: now.Add(time.Minute)},
+		{ID: "p1", Owner: "alice", DeviceID: "tablet", PairedAt: now},
+		{ID: "p3", Owner: "bob", DeviceID: "tablet", PairedAt: now},
+	}
+	for _, device := range devices {
+		if err := repo.SaveDevice(device); err != nil {
+			t.Fatalf("Failed to save device: %v", err)
+		}
+	}
+	// Saving an ID again replaces its pairing
+	if err := repo.SaveDevice(&entities.TrustedDevice{ID: "p1", Owner: "alice", DeviceID: "tablet", Name: "Kitchen", PairedAt: now}); err != nil {
+		t.Fatalf("Failed to update device: %v", err)
+	}
+	// Senders only remove their own pairings
+	if err := repo.DeleteDevice("alice", "p3"); err != ErrTrustedDeviceNotFound {
+		t.Errorf("Expected ErrTrustedDeviceNotFound for another sender's device, got %v", err)
+	}
+	if err := repo.DeleteDevice("bob", "p3"); err != nil {
+		t.Fatalf("Failed to delete device: %v", err)
+	}
+
+	// The pairings survive a restart
+	reopened, err := NewFileTrustedDeviceRepository(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen device file: %v", err)
+	}
+	listed, err := reopened.ListDevices("alice")
+	if err != nil || len(listed) != 2 || listed[0].ID != "p1" || listed[1].ID != "p2" {
+		t.Fatalf("Expected alice's devices, oldest first, got %+v, %v", listed, err)
+	}
+	if listed[0].Name != "Kitchen" {
+		t.Errorf("Expected the updated pairing, got %+v", listed[0])
+	}
+	if _, err := reopened.FindDevice("bob", "tablet"); err != ErrTrustedDeviceNotFound {
+		t.Errorf("Expected bob's device to stay deleted, got %v", err)
+	}
+	if bob, _ := reopened.ListDevices("bob"); len(bob) != 0 {
+		t.Errorf("Expected no devices for bob, got %+v", bob)
+	}
+
+	// Returned pairings are copies
+	found, err := reopened.FindDevice("alice", "tablet")
+	if err != nil {
+		t.Fatalf("Failed to find device: %v", err)
+	}
+	found.Name = "changed"
+	if again, _ := reopened.FindDevice("alice", "tablet"); again.Name != "Kitchen" {
+		t.Error("Expected the stored pairing to be unaffected by changes to a copy")
+	}
+}
+
+func TestFileTrustedDeviceRepository_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "devices.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileTrustedDeviceRepository(path); err == nil {
+		t.Error("Expected an error for a corrupt device file")
+	}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+const (
+	// maxAuditSessions bounds how many sessions keep a log, like the history;
+	// the logs of the oldest sessions are dropped first
+	maxAuditSessions = 1000
+
+	// maxAuditEvents bounds the log of one session, e.g. against a client
+	// guessing PINs; the oldest events are dropped first
+	maxAuditEvents = 500
+)
+
+// MemoryAuditLogRepository implements AuditLogRepository using in-memory storage
+type MemoryAuditLogRepository struct {
+	mu     sync.RWMutex
+	events map[string][]entities.AuditEvent
+	order  []string
+}
+
+// NewMemoryAuditLogRepository creates a new in-memory audit log repository
+func NewMemoryAuditLogRepository() interfaces.AuditLogRepository {
+	return &MemoryAuditLogRepository{
+		events: make(map[string][]entities.AuditEvent),
+	}
+}
+
+// AppendEvent adds an event to the log of its session
+func (r *MemoryAuditLogRepository) AppendEvent(event *entities.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, exists := r.events[event.Token]
+	if !exists {
+		r.order = append(r.order, event.Token)
+		if len(r.order) > maxAuditSessions {
+			delete(r.events, r.order[0])
+			r.order = r.order[1:]
+		}
+	}
+
+	log = append(log, *event)
+	if len(log) > maxAuditEvents {
+		log = append([]entities.AuditEvent(nil), log[len(log)-maxAuditEvents:]...)
+	}
+	r.events[event.Token] = log
+	return nil
+}
+
+// ListEvents returns the log of a session, oldest first
+func (r *MemoryAuditLogRepository) ListEvents(token string) ([]entities.AuditEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	log, exists := r.events[token]
+	if !exists {
+		return nil, ErrAuditLogNotFound
+	}
+	return append([]entities.AuditEvent(nil), log...), nil
+}
+
+// ErrAuditLogNotFound is returned when a session has no audit log
+var ErrAuditLogNotFound = &RepositoryError{Message: "audit log not found"}
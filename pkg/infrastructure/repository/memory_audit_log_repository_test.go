@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemoryAuditLogRepository(t *testing.T) {
+	repo := NewMemoryAuditLogRepository()
+
+	for _, eventType := range []entities.AuditEventType{entities.AuditSessionCreated, entities.AuditOffer, entities.AuditTerminated} {
+		if err := repo.AppendEvent(&entities.AuditEvent{At: time.Now(), Token: "token", Type: eventType}); err != nil {
+			t.Fatalf("Failed to append event: %v", err)
+		}
+	}
+
+	events, err := repo.ListEvents("token")
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+	if len(events) != 3 || events[0].Type != entities.AuditSessionCreated || events[2].Type != entities.AuditTerminated {
+		t.Errorf("Expected three events oldest first, got %+v", events)
+	}
+
+	// The returned log is a copy
+	events[0].Type = entities.AuditError
+	if again, _ := repo.ListEvents("token"); again[0].Type != entities.AuditSessionCreated {
+		t.Error("Expected the stored log to be unaffected by changes to a listed copy")
+	}
+
+	if _, err := repo.ListEvents("other"); err != ErrAuditLogNotFound {
+		t.Errorf("Expected ErrAuditLogNotFound for an unknown session, got %v", err)
+	}
+}
+
+func TestMemoryAuditLogRepository_Bounds(t *testing.T) {
+	repo := NewMemoryAuditLogRepository()
+
+	for i := 0; i < maxAuditEvents+5; i++ {
+		_ = repo.AppendEvent(&entities.AuditEvent{Token: "busy", Type: entities.AuditError, Detail: fmt.Sprint(i)})
+	}
+	events, _ := repo.ListEvents("busy")
+	if len(events) != maxAuditEvents || events[0].Detail != "5" {
+		t.Errorf("Expected the newest %d events, got %d starting at %s", maxAuditEvents, len(events), events[0].Detail)
+	}
+
+	for i := 0; i < maxAuditSessions; i++ {
+		_ = repo.AppendEvent(&entities.AuditEvent{Token: fmt.Sprint("session-", i), Type: entities.AuditSessionCreated})
+	}
+	if _, err := repo.ListEvents("busy"); err != ErrAuditLogNotFound {
+		t.Error("Expected the log of the oldest session to be dropped")
+	}
+	if _, err := repo.ListEvents("session-0"); err != nil {
+		t.Errorf("Expected newer logs to be kept, got %v", err)
+	}
+}
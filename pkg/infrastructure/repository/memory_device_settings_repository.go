@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"slices"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// maxDeviceSettings bounds the in-memory device settings; anyone can save
+// settings for a new device ID, so the devices updated longest ago are dropped first
+const maxDeviceSettings = 1000
+
+// MemoryDeviceSettingsRepository implements DeviceSettingsRepository using in-memory storage
+type MemoryDeviceSettingsRepository struct {
+	mu       sync.RWMutex
+	settings map[string]*entities.DeviceSettings
+	order    []string
+}
+
+// NewMemoryDeviceSettingsRepository creates a new in-memory device settings repository
+func NewMemoryDeviceSettingsRepository() interfaces.DeviceSettingsRepository {
+	return &MemoryDeviceSettingsRepository{
+		settings: make(map[string]*entities.DeviceSettings),
+	}
+}
+
+// GetSettings retrieves the settings for a device
+func (r *MemoryDeviceSettingsRepository) GetSettings(deviceID string) (*entities.DeviceSettings, error) {
+	r.mu.RLock()
+	settings, exists := r.settings[deviceID]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrSettingsNotFound
+	}
+
+	settingsCopy := *settings
+	return &settingsCopy, nil
+}
+
+// SaveSettings stores or replaces the settings for a device
+func (r *MemoryDeviceSettingsRepository) SaveSettings(settings *entities.DeviceSettings) error {
+	settingsCopy := *settings
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.settings[settings.DeviceID]; exists {
+		r.order = slices.DeleteFunc(r.order, func(deviceID string) bool { return deviceID == settings.DeviceID })
+	}
+	r.order = append(r.order, settings.DeviceID)
+	if len(r.order) > maxDeviceSettings {
+		delete(r.settings, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.settings[settings.DeviceID] = &settingsCopy
+	return nil
+}
+
+// ListSettings returns copies of the settings of every device, least
+// recently saved first
+func (r *MemoryDeviceSettingsRepository) ListSettings() ([]*entities.DeviceSettings, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	settings := make([]*entities.DeviceSettings, 0, len(r.order))
+	for _, deviceID := range r.order {
+		settingsCopy := *r.settings[deviceID]
+		settings = append(settings, &settingsCopy)
+	}
+	return settings, nil
+}
+
+// ErrSettingsNotFound is returned when a device has no stored settings
+var ErrSettingsNotFound = &RepositoryError{Message: "device settings not found"}
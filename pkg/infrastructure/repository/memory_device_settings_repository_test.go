@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemoryDeviceSettingsRepository_SaveAndGet(t *testing.T) {
+	repo := NewMemoryDeviceSettingsRepository()
+
+	if _, err := repo.GetSettings("device-1"); err != ErrSettingsNotFound {
+		t.Errorf("Expected ErrSettingsNotFound, got %v", err)
+	}
+
+	if err := repo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-1", LowPower: true}); err != nil {
+		t.Fatalf("Failed to save settings: %v", err)
+	}
+
+	settings, err := repo.GetSettings("device-1")
+	if err != nil {
+		t.Fatalf("Failed to get settings: %v", err)
+	}
+	if !settings.LowPower {
+		t.Error("Expected low power to be stored")
+	}
+
+	// Changing the returned copy must not affect the stored settings
+	settings.LowPower = false
+	stored, _ := repo.GetSettings("device-1")
+	if !stored.LowPower {
+		t.Error("Expected stored settings to be unchanged")
+	}
+}
+
+func TestMemoryDeviceSettingsRepository_ListSettings(t *testing.T) {
+	repo := NewMemoryDeviceSettingsRepository()
+	repo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-1", LowPower: true})
+	repo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-2"})
+
+	settings, err := repo.ListSettings()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(settings) != 2 {
+		t.Fatalf("Expected 2 devices but got %d", len(settings))
+	}
+
+	// Changing listed settings must not affect the stored ones
+	for _, listed := range settings {
+		listed.LowPower = false
+	}
+	if stored, _ := repo.GetSettings("device-1"); !stored.LowPower {
+		t.Error("ListSettings should return copies")
+	}
+}
+
+func TestMemoryDeviceSettingsRepository_DropsLeastRecentlySaved(t *testing.T) {
+	repo := NewMemoryDeviceSettingsRepository()
+
+	for i := 0; i < maxDeviceSettings; i++ {
+		repo.SaveSettings(&entities.DeviceSettings{DeviceID: fmt.Sprintf("device-%d", i)})
+	}
+	// Saving again keeps a device in use
+	repo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-0", LowPower: true})
+	repo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-new"})
+
+	if _, err := repo.GetSettings("device-1"); err != ErrSettingsNotFound {
+		t.Errorf("Expected the least recently saved device to be dropped, got %v", err)
+	}
+	if stored, err := repo.GetSettings("device-0"); err != nil || !stored.LowPower {
+		t.Errorf("Expected the re-saved device to be kept, got %+v, %v", stored, err)
+	}
+	settings, _ := repo.ListSettings()
+	if len(settings) != maxDeviceSettings {
+		t.Fatalf("Expected %d devices but got %d", maxDeviceSettings, len(settings))
+	}
+	if last := settings[len(settings)-1].DeviceID; last != "device-new" {
+		t.Errorf("Expected the newest device listed last, got %s", last)
+	}
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// storedFile is a relayed file with its content
+type storedFile struct {
+	file entities.SharedFile
+	data []byte
+}
+
+// MemoryFileRepository implements FileRepository using in-memory storage
+type MemoryFileRepository struct {
+	mu    sync.RWMutex
+	files map[string][]*storedFile
+}
+
+// NewMemoryFileRepository creates a new in-memory file repository
+func NewMemoryFileRepository() interfaces.FileRepository {
+	return &MemoryFileRepository{
+		files: make(map[string][]*storedFile),
+	}
+}
+
+// SaveFile stores a file and its content for the session with token
+func (r *MemoryFileRepository) SaveFile(token string, file *entities.SharedFile, data []byte) error {
+	stored := &storedFile{file: *file, data: append([]byte(nil), data...)}
+
+	r.mu.Lock()
+	r.files[token] = append(r.files[token], stored)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetFile retrieves a file of a session and its content
+func (r *MemoryFileRepository) GetFile(token, id string) (*entities.SharedFile, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, stored := range r.files[token] {
+		if stored.file.ID == id {
+			fileCopy := stored.file
+			// Stored content is never modified, so it can be shared
+			return &fileCopy, stored.data, nil
+		}
+	}
+	return nil, nil, ErrFileNotFound
+}
+
+// ListFiles returns the files of a session, oldest first
+func (r *MemoryFileRepository) ListFiles(token string) ([]*entities.SharedFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	files := make([]*entities.SharedFile, 0, len(r.files[token]))
+	for _, stored := range r.files[token] {
+		fileCopy := stored.file
+		files = append(files, &fileCopy)
+	}
+	return files, nil
+}
+
+// DeleteFiles removes every file of a session
+func (r *MemoryFileRepository) DeleteFiles(token string) error {
+	r.mu.Lock()
+	delete(r.files, token)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetTokens returns the tokens of the sessions holding files
+func (r *MemoryFileRepository) GetTokens() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := make([]string, 0, len(r.files))
+	for token := range r.files {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// ErrFileNotFound is returned when a session has no file with the given ID
+var ErrFileNotFound = &RepositoryError{Message: "file not found"}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemoryFileRepository(t *testing.T) {
+	repo := NewMemoryFileRepository()
+
+	if _, _, err := repo.GetFile("token", "a"); err != ErrFileNotFound {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+
+	data := []byte("hello")
+	if err := repo.SaveFile("token", &entities.SharedFile{ID: "a", Name: "a.txt", Size: 5}, data); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+	if err := repo.SaveFile("token", &entities.SharedFile{ID: "b", Name: "b.txt"}, nil); err != nil {
+		t.Fatalf("Failed to save file: %v", err)
+	}
+	// The repository keeps its own copy of the content
+	data[0] = 'j'
+
+	file, content, err := repo.GetFile("token", "a")
+	if err != nil {
+		t.Fatalf("Failed to get file: %v", err)
+	}
+	if file.Name != "a.txt" || string(content) != "hello" {
+		t.Errorf("Expected a.txt with hello, got %s with %q", file.Name, content)
+	}
+
+	files, _ := repo.ListFiles("token")
+	if len(files) != 2 || files[0].ID != "a" || files[1].ID != "b" {
+		t.Errorf("Expected files a and b in order, got %+v", files)
+	}
+	if tokens, _ := repo.GetTokens(); len(tokens) != 1 || tokens[0] != "token" {
+		t.Errorf("Expected one session with files, got %v", tokens)
+	}
+
+	if err := repo.DeleteFiles("token"); err != nil {
+		t.Fatalf("Failed to delete files: %v", err)
+	}
+	if files, _ := repo.ListFiles("token"); len(files) != 0 {
+		t.Errorf("Expected no files after delete, got %d", len(files))
+	}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// MemoryRoomRepository implements RoomRepository using in-memory storage
+type MemoryRoomRepository struct {
+	mu    sync.RWMutex
+	rooms map[string]*entities.Room
+}
+
+// NewMemoryRoomRepository creates a new in-memory room repository
+func NewMemoryRoomRepository() interfaces.RoomRepository {
+	return &MemoryRoomRepository{
+		rooms: make(map[string]*entities.Room),
+	}
+}
+
+// GetRoom retrieves a room by its normalized name
+func (r *MemoryRoomRepository) GetRoom(name string) (*entities.Room, error) {
+	r.mu.RLock()
+	room, exists := r.rooms[name]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrRoomNotFound
+	}
+
+	roomCopy := *room
+	return &roomCopy, nil
+}
+
+// SaveRoom stores or replaces a room
+func (r *MemoryRoomRepository) SaveRoom(room *entities.Room) error {
+	roomCopy := *room
+
+	r.mu.Lock()
+	r.rooms[room.Name] = &roomCopy
+	r.mu.Unlock()
+
+	return nil
+}
+
+// ErrRoomNotFound is returned when no room has the given name
+var ErrRoomNotFound = &RepositoryError{Message: "room not found"}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemoryRoomRepository_SaveAndGet(t *testing.T) {
+	repo := NewMemoryRoomRepository()
+
+	if _, err := repo.GetRoom("design-review"); err != ErrRoomNotFound {
+		t.Errorf("Expected ErrRoomNotFound, got %v", err)
+	}
+
+	if err := repo.SaveRoom(&entities.Room{Name: "design-review", Key: "key", SessionToken: "first"}); err != nil {
+		t.Fatalf("Failed to save room: %v", err)
+	}
+
+	room, err := repo.GetRoom("design-review")
+	if err != nil {
+		t.Fatalf("Failed to get room: %v", err)
+	}
+	if room.SessionToken != "first" {
+		t.Errorf("Expected session token %q, got %q", "first", room.SessionToken)
+	}
+
+	// Changing the returned copy must not affect the stored room
+	room.SessionToken = "second"
+	stored, _ := repo.GetRoom("design-review")
+	if stored.SessionToken != "first" {
+		t.Error("Expected stored room to be unchanged")
+	}
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// maxHistoryRecords bounds the in-memory history; the oldest records are dropped first
+const maxHistoryRecords = 1000
+
+// MemorySessionHistoryRepository implements SessionHistoryRepository using in-memory storage
+type MemorySessionHistoryRepository struct {
+	mu      sync.RWMutex
+	records map[string]*entities.SessionRecord
+	order   []string
+}
+
+// NewMemorySessionHistoryRepository creates a new in-memory session history repository
+func NewMemorySessionHistoryRepository() interfaces.SessionHistoryRepository {
+	return &MemorySessionHistoryRepository{
+		records: make(map[string]*entities.SessionRecord),
+	}
+}
+
+// SaveRecord stores or replaces the record for a session
+func (r *MemorySessionHistoryRepository) SaveRecord(record *entities.SessionRecord) error {
+	recordCopy := *record
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.records[record.Token]; !exists {
+		r.order = append(r.order, record.Token)
+		if len(r.order) > maxHistoryRecords {
+			delete(r.records, r.order[0])
+			r.order = r.order[1:]
+		}
+	}
+	r.records[record.Token] = &recordCopy
+	return nil
+}
+
+// GetRecord retrieves the record for a session by token
+func (r *MemorySessionHistoryRepository) GetRecord(token string) (*entities.SessionRecord, error) {
+	r.mu.RLock()
+	record, exists := r.records[token]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrRecordNotFound
+	}
+
+	recordCopy := *record
+	return &recordCopy, nil
+}
+
+// ListRecords returns copies of all stored records, oldest first
+func (r *MemorySessionHistoryRepository) ListRecords() ([]*entities.SessionRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := make([]*entities.SessionRecord, 0, len(r.order))
+	for _, token := range r.order {
+		recordCopy := *r.records[token]
+		records = append(records, &recordCopy)
+	}
+	return records, nil
+}
+
+// ErrRecordNotFound is returned when a session has no history record
+var ErrRecordNotFound = &RepositoryError{Message: "session record not found"}
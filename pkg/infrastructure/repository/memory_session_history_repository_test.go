@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemorySessionHistoryRepository_SaveAndGet(t *testing.T) {
+	repo := NewMemorySessionHistoryRepository()
+
+	if err := repo.SaveRecord(&entities.SessionRecord{Token: "test-token", Notes: "first"}); err != nil {
+		t.Fatalf("Failed to save record: %v", err)
+	}
+
+	record, err := repo.GetRecord("test-token")
+	if err != nil {
+		t.Fatalf("Failed to get record: %v", err)
+	}
+
+	// Changing the returned copy must not affect the stored record
+	record.Notes = "changed"
+	stored, _ := repo.GetRecord("test-token")
+	if stored.Notes != "first" {
+		t.Errorf("Expected stored notes to be unchanged, got %q", stored.Notes)
+	}
+
+	if _, err := repo.GetRecord("missing"); err != ErrRecordNotFound {
+		t.Errorf("Expected ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestMemorySessionHistoryRepository_DropsOldestRecords(t *testing.T) {
+	repo := NewMemorySessionHistoryRepository()
+
+	for i := 0; i <= maxHistoryRecords; i++ {
+		repo.SaveRecord(&entities.SessionRecord{Token: fmt.Sprintf("token-%d", i)})
+	}
+
+	if _, err := repo.GetRecord("token-0"); err != ErrRecordNotFound {
+		t.Errorf("Expected oldest record to be dropped, got %v", err)
+	}
+	if _, err := repo.GetRecord(fmt.Sprintf("token-%d", maxHistoryRecords)); err != nil {
+		t.Errorf("Expected newest record to be kept, got %v", err)
+	}
+}
+
+func TestMemorySessionHistoryRepository_ListRecords(t *testing.T) {
+	repo := NewMemorySessionHistoryRepository()
+	repo.SaveRecord(&entities.SessionRecord{Token: "first"})
+	repo.SaveRecord(&entities.SessionRecord{Token: "second"})
+	repo.SaveRecord(&entities.SessionRecord{Token: "first", Notes: "replaced"})
+
+	records, err := repo.ListRecords()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 2 || records[0].Token != "first" || records[1].Token != "second" {
+		t.Fatalf("Expected the records oldest first, got %+v", records)
+	}
+	if records[0].Notes != "replaced" {
+		t.Errorf("Expected the replaced record, got %q", records[0].Notes)
+	}
+
+	// Changing a listed record must not affect the stored one
+	records[0].Notes = "changed"
+	if stored, _ := repo.GetRecord("first"); stored.Notes != "replaced" {
+		t.Errorf("ListRecords should return copies, got %q", stored.Notes)
+	}
+}
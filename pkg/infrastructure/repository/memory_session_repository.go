@@ -2,7 +2,6 @@ package repository
 
 import (
 	"crypto/rand"
-	"encoding/base64"
 	"log"
 	"sync"
 	"time"
@@ -15,32 +14,27 @@ import (
 type MemorySessionRepository struct {
 	mu       sync.RWMutex
 	sessions map[string]*entities.Session
+	tokens   entities.TokenPolicy
 }
 
 // NewMemorySessionRepository creates a new in-memory session repository
-func NewMemorySessionRepository() interfaces.SessionRepository {
+// handing out tokens of the given policy
+func NewMemorySessionRepository(tokens entities.TokenPolicy) interfaces.SessionRepository {
 	return &MemorySessionRepository{
 		sessions: make(map[string]*entities.Session),
+		tokens:   tokens,
 	}
 }
 
 // CreateSession creates a new session with a unique token
 func (r *MemorySessionRepository) CreateSession(expiryDuration time.Duration) (*entities.Session, error) {
-	token, err := r.generateToken()
+	session, err := newSession(r.tokens, expiryDuration)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	session := &entities.Session{
-		Token:     token,
-		CreatedAt: now,
-		ExpiresAt: now.Add(expiryDuration),
-		Status:    entities.SessionStatusPending,
-	}
-
 	r.mu.Lock()
-	r.sessions[token] = session
+	r.sessions[session.Token] = session
 	r.mu.Unlock()
 
 	return session, nil
@@ -57,17 +51,7 @@ func (r *MemorySessionRepository) GetSession(token string) (*entities.Session, e
 	}
 
 	// Return a copy to prevent external modifications
-	sessionCopy := *session
-	if session.Offer != nil {
-		offerCopy := *session.Offer
-		sessionCopy.Offer = &offerCopy
-	}
-	if session.Answer != nil {
-		answerCopy := *session.Answer
-		sessionCopy.Answer = &answerCopy
-	}
-
-	return &sessionCopy, nil
+	return session.Clone(), nil
 }
 
 // UpdateSession updates an existing session
@@ -81,17 +65,7 @@ func (r *MemorySessionRepository) UpdateSession(session *entities.Session) error
 	}
 
 	// Create a copy to store
-	sessionCopy := *session
-	if session.Offer != nil {
-		offerCopy := *session.Offer
-		sessionCopy.Offer = &offerCopy
-	}
-	if session.Answer != nil {
-		answerCopy := *session.Answer
-		sessionCopy.Answer = &answerCopy
-	}
-
-	r.sessions[session.Token] = &sessionCopy
+	r.sessions[session.Token] = session.Clone()
 	return nil
 }
 
@@ -124,11 +98,7 @@ func (r *MemorySessionRepository) CleanupExpiredSessions() (int, error) {
 		// Convert to truncated tokens for logging
 		var truncatedTokens []string
 		for _, token := range expiredTokens {
-			if len(token) > 8 {
-				truncatedTokens = append(truncatedTokens, token[:8]+"...")
-			} else {
-				truncatedTokens = append(truncatedTokens, token+"...")
-			}
+			truncatedTokens = append(truncatedTokens, entities.ShortToken(token))
 		}
 		activeCount := len(r.sessions)
 		log.Printf("🗑️  GC: cleaned up %d expired tokens: %v (active: %d)",
@@ -153,15 +123,60 @@ func (r *MemorySessionRepository) GetActiveSessionsCount() (int, error) {
 	return count, nil
 }
 
-// generateToken generates a random token for sessions
-func (r *MemorySessionRepository) generateToken() (string, error) {
-	b := make([]byte, 9)
+// ListSessions returns copies of all stored sessions
+func (r *MemorySessionRepository) ListSessions() ([]*entities.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*entities.Session, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		sessions = append(sessions, session.Clone())
+	}
+
+	return sessions, nil
+}
+
+// RestoreSession stores a session under its own token, replacing any session
+// with that token
+func (r *MemorySessionRepository) RestoreSession(session *entities.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sessions[session.Token] = session.Clone()
+	return nil
+}
+
+// newSession creates a pending session with a new token of the policy; a
+// signed token's expiry is the session's deadline
+func newSession(tokens entities.TokenPolicy, expiryDuration time.Duration) (*entities.Session, error) {
+	now := time.Now()
+	token, err := generateToken(tokens, now)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &entities.Session{
+		Token:     token,
+		CreatedAt: now,
+		ExpiresAt: now.Add(expiryDuration),
+		Deadline:  tokens.Deadline(now),
+		Status:    entities.SessionStatusPending,
+	}
+	if !session.Deadline.IsZero() && session.ExpiresAt.After(session.Deadline) {
+		session.ExpiresAt = session.Deadline
+	}
+	return session, nil
+}
+
+// generateToken generates a random token for sessions created at now
+func generateToken(tokens entities.TokenPolicy, now time.Time) (string, error) {
+	b := make([]byte, tokens.RandomBytes())
 	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	token := base64.RawURLEncoding.EncodeToString(b)
+	token := tokens.Encode(b, now)
 	if len(token) > 8 {
-		log.Printf("🆕 New token generated: %s...", token[:8])
+		log.Printf("🆕 New token generated: %s", entities.ShortToken(token))
 	} else {
 		log.Printf("🆕 New token generated: %s...", token)
 	}
@@ -8,7 +8,7 @@ import (
 )
 
 func TestMemorySessionRepository_CreateSession(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	expiryDuration := 30 * time.Minute
 	session, err := repo.CreateSession(expiryDuration)
@@ -38,8 +38,43 @@ func TestMemorySessionRepository_CreateSession(t *testing.T) {
 	}
 }
 
+func TestMemorySessionRepository_CreateSession_TokenPolicy(t *testing.T) {
+	for _, format := range []string{"base64url", "hex", "base32", "uuid"} {
+		policy, err := entities.ParseTokenPolicy(format, 0, "", 0)
+		if err != nil {
+			t.Fatalf("ParseTokenPolicy(%q) failed: %v", format, err)
+		}
+		repo := NewMemorySessionRepository(policy)
+
+		session, err := repo.CreateSession(30 * time.Minute)
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		if !policy.Valid(session.Token) {
+			t.Errorf("Expected a %s token, got %q", format, session.Token)
+		}
+	}
+}
+
+func TestMemorySessionRepository_CreateSession_SignedToken(t *testing.T) {
+	policy, _ := entities.ParseTokenPolicy("", 0, "0123456789abcdef", 10*time.Minute)
+	repo := NewMemorySessionRepository(policy)
+
+	session, err := repo.CreateSession(30 * time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	if !policy.Valid(session.Token) || !session.Deadline.Equal(policy.ExpiresAt(session.Token)) {
+		t.Errorf("Expected a signed token carrying the deadline %v, got %q", session.Deadline, session.Token)
+	}
+	// The session cannot outlive its token
+	if session.ExpiresAt.After(session.Deadline) {
+		t.Errorf("Expected the expiry to be capped at the deadline, got %v after %v", session.ExpiresAt, session.Deadline)
+	}
+}
+
 func TestMemorySessionRepository_GetSession(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	// Test getting non-existent session
 	_, err := repo.GetSession("non-existent")
@@ -80,7 +115,7 @@ func TestMemorySessionRepository_GetSession(t *testing.T) {
 }
 
 func TestMemorySessionRepository_UpdateSession(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	// Test updating non-existent session
 	nonExistentSession := &entities.Session{
@@ -132,7 +167,7 @@ func TestMemorySessionRepository_UpdateSession(t *testing.T) {
 }
 
 func TestMemorySessionRepository_DeleteSession(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	// Create a session first
 	session, err := repo.CreateSession(30 * time.Minute)
@@ -166,7 +201,7 @@ func TestMemorySessionRepository_DeleteSession(t *testing.T) {
 }
 
 func TestMemorySessionRepository_CleanupExpiredSessions(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	// Create some sessions with different expiry times
 	now := time.Now()
@@ -227,7 +262,7 @@ func TestMemorySessionRepository_CleanupExpiredSessions(t *testing.T) {
 }
 
 func TestMemorySessionRepository_GetActiveSessionsCount(t *testing.T) {
-	repo := NewMemorySessionRepository().(*MemorySessionRepository)
+	repo := NewMemorySessionRepository(entities.TokenPolicy{}).(*MemorySessionRepository)
 
 	// Initially should be 0
 	count, err := repo.GetActiveSessionsCount()
@@ -276,3 +311,68 @@ func TestMemorySessionRepository_GetActiveSessionsCount(t *testing.T) {
 		t.Errorf("Expected 1 active session but got %d", count)
 	}
 }
+
+func TestMemorySessionRepository_ListSessions(t *testing.T) {
+	repo := NewMemorySessionRepository(entities.TokenPolicy{})
+
+	sessions, err := repo.ListSessions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("Expected no sessions but got %d", len(sessions))
+	}
+
+	created, _ := repo.CreateSession(30 * time.Minute)
+	if _, err := repo.CreateSession(30 * time.Minute); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sessions, err = repo.ListSessions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions but got %d", len(sessions))
+	}
+
+	// Modifying a listed session must not change the stored one
+	for _, session := range sessions {
+		session.Name = "changed"
+	}
+	stored, _ := repo.GetSession(created.Token)
+	if stored.Name == "changed" {
+		t.Error("ListSessions should return copies")
+	}
+}
+
+func TestMemorySessionRepository_RestoreSession(t *testing.T) {
+	repo := NewMemorySessionRepository(entities.TokenPolicy{})
+	session := &entities.Session{
+		Token:     "restored-token",
+		Name:      "Standup",
+		CreatedAt: time.Now().Add(-time.Minute),
+		ExpiresAt: time.Now().Add(29 * time.Minute),
+		Status:    entities.SessionStatusActive,
+	}
+
+	if err := repo.RestoreSession(session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session.Name = "changed"
+
+	stored, err := repo.GetSession("restored-token")
+	if err != nil {
+		t.Fatalf("Restored session not found: %v", err)
+	}
+	if stored.Name != "Standup" || stored.Status != entities.SessionStatusActive {
+		t.Errorf("Expected the session as restored, got %+v", stored)
+	}
+
+	// Restoring again replaces the stored session
+	session.Status = entities.SessionStatusEnded
+	repo.RestoreSession(session)
+	if stored, _ := repo.GetSession("restored-token"); stored.Status != entities.SessionStatusEnded {
+		t.Errorf("Expected the session to be replaced, got status %s", stored.Status)
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// maxStatsSamples bounds the series of one session: an hour of samples from
+// the sender and two viewers at the pages' five second interval
+const maxStatsSamples = 2160
+
+// MemoryStatsRepository implements StatsRepository using in-memory storage
+type MemoryStatsRepository struct {
+	mu    sync.RWMutex
+	stats map[string][]entities.StatsSample
+
+	// latency holds the latest latency result of each session by peer and path
+	latency map[string]map[string]entities.LatencySample
+}
+
+// NewMemoryStatsRepository creates a new in-memory stats repository
+func NewMemoryStatsRepository() interfaces.StatsRepository {
+	return &MemoryStatsRepository{
+		stats:   make(map[string][]entities.StatsSample),
+		latency: make(map[string]map[string]entities.LatencySample),
+	}
+}
+
+// AppendStats adds a sample to a session's series, dropping the oldest
+// samples beyond maxStatsSamples
+func (r *MemoryStatsRepository) AppendStats(token string, sample *entities.StatsSample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	series := append(r.stats[token], *sample)
+	if len(series) > maxStatsSamples {
+		series = append([]entities.StatsSample(nil), series[len(series)-maxStatsSamples:]...)
+	}
+	r.stats[token] = series
+	return nil
+}
+
+// ListStats returns a session's samples taken after since, oldest first
+func (r *MemoryStatsRepository) ListStats(token string, since time.Time) ([]entities.StatsSample, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	samples := []entities.StatsSample{}
+	for _, sample := range r.stats[token] {
+		if sample.At.After(since) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+// SetLatency records a latency result of a session, replacing the earlier
+// one of the same peer and path
+func (r *MemoryStatsRepository) SetLatency(token string, sample *entities.LatencySample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := r.latency[token]
+	if results == nil {
+		results = make(map[string]entities.LatencySample)
+		r.latency[token] = results
+	}
+	results[sample.Source()+" "+sample.Path] = *sample
+	return nil
+}
+
+// ListLatency returns the latest latency result of each peer and path of a
+// session, the sender's first and then the viewers' by ID
+func (r *MemoryStatsRepository) ListLatency(token string) ([]entities.LatencySample, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	samples := make([]entities.LatencySample, 0, len(r.latency[token]))
+	for _, sample := range r.latency[token] {
+		samples = append(samples, sample)
+	}
+	slices.SortFunc(samples, func(a, b entities.LatencySample) int {
+		return cmp.Or(cmp.Compare(a.Role, b.Role), cmp.Compare(a.ViewerID, b.ViewerID), cmp.Compare(a.Path, b.Path))
+	})
+	return samples, nil
+}
+
+// DeleteStats removes the series and latency results of a session
+func (r *MemoryStatsRepository) DeleteStats(token string) error {
+	r.mu.Lock()
+	delete(r.stats, token)
+	delete(r.latency, token)
+	r.mu.Unlock()
+
+	return nil
+}
+
+// GetTokens returns the tokens of the sessions holding stats or latency
+// results
+func (r *MemoryStatsRepository) GetTokens() ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tokens := make([]string, 0, len(r.stats))
+	for token := range r.stats {
+		tokens = append(tokens, token)
+	}
+	for token := range r.latency {
+		if _, ok := r.stats[token]; !ok {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestMemoryStatsRepository(t *testing.T) {
+	repo := NewMemoryStatsRepository()
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		sample := &entities.StatsSample{At: start.Add(time.Duration(i) * time.Second), Role: entities.StatsRoleSender, Bitrate: int64(i)}
+		if err := repo.AppendStats("token", sample); err != nil {
+			t.Fatalf("Failed to append stats: %v", err)
+		}
+	}
+
+	samples, _ := repo.ListStats("token", time.Time{})
+	if len(samples) != 3 || samples[0].Bitrate != 0 || samples[2].Bitrate != 2 {
+		t.Errorf("Expected three samples oldest first, got %+v", samples)
+	}
+	if samples, _ := repo.ListStats("token", start); len(samples) != 2 || samples[0].Bitrate != 1 {
+		t.Errorf("Expected the two samples after since, got %+v", samples)
+	}
+	if samples, _ := repo.ListStats("other", time.Time{}); samples == nil || len(samples) != 0 {
+		t.Errorf("Expected an empty series for an unknown session, got %v", samples)
+	}
+	if tokens, _ := repo.GetTokens(); len(tokens) != 1 || tokens[0] != "token" {
+		t.Errorf("Expected one session with stats, got %v", tokens)
+	}
+
+	if err := repo.DeleteStats("token"); err != nil {
+		t.Fatalf("Failed to delete stats: %v", err)
+	}
+	if samples, _ := repo.ListStats("token", time.Time{}); len(samples) != 0 {
+		t.Errorf("Expected no samples after deleting, got %+v", samples)
+	}
+}
+
+func TestMemoryStatsRepository_DropsOldest(t *testing.T) {
+	repo := NewMemoryStatsRepository()
+	start := time.Now()
+
+	for i := 0; i < maxStatsSamples+5; i++ {
+		_ = repo.AppendStats("token", &entities.StatsSample{At: start.Add(time.Duration(i) * time.Millisecond), Role: entities.StatsRoleSender, Bitrate: int64(i)})
+	}
+
+	samples, _ := repo.ListStats("token", time.Time{})
+	if len(samples) != maxStatsSamples || samples[0].Bitrate != 5 {
+		t.Errorf("Expected the newest %d samples, got %d starting at %d", maxStatsSamples, len(samples), samples[0].Bitrate)
+	}
+}
+
+func TestMemoryStatsRepository_Latency(t *testing.T) {
+	repo := NewMemoryStatsRepository()
+
+	for _, sample := range []entities.LatencySample{
+		{Role: entities.StatsRoleViewer, ViewerID: "v1", Path: entities.LatencyPathPeer, RTTMillis: 40},
+		{Role: entities.StatsRoleSender, Path: entities.LatencyPathPeer, RTTMillis: 38},
+		{Role: entities.StatsRoleViewer, ViewerID: "v1", Path: entities.LatencyPathServer, RTTMillis: 12},
+		{Role: entities.StatsRoleViewer, ViewerID: "v1", Path: entities.LatencyPathPeer, RTTMillis: 35},
+	} {
+		if err := repo.SetLatency("token", &sample); err != nil {
+			t.Fatalf("Failed to set latency: %v", err)
+		}
+	}
+
+	samples, _ := repo.ListLatency("token")
+	if len(samples) != 3 {
+		t.Fatalf("Expected one result per peer and path, got %+v", samples)
+	}
+	if samples[0].Role != entities.StatsRoleSender || samples[1].Path != entities.LatencyPathPeer || samples[1].RTTMillis != 35 || samples[2].Path != entities.LatencyPathServer {
+		t.Errorf("Expected the sender first and the viewer's latest peer result, got %+v", samples)
+	}
+	if tokens, _ := repo.GetTokens(); len(tokens) != 1 || tokens[0] != "token" {
+		t.Errorf("Expected the session holding latency results, got %v", tokens)
+	}
+
+	if err := repo.DeleteStats("token"); err != nil {
+		t.Fatalf("Failed to delete stats: %v", err)
+	}
+	if samples, _ := repo.ListLatency("token"); samples == nil || len(samples) != 0 {
+		t.Errorf("Expected no latency results after deleting, got %v", samples)
+	}
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+const (
+	// postgresConnectTimeout bounds connecting and migrating on startup
+	postgresConnectTimeout = 10 * time.Second
+	// postgresQueryTimeout bounds every repository call, so a database that
+	// stops answering fails requests instead of hanging them
+	postgresQueryTimeout = 5 * time.Second
+	// postgresCleanupBatch is how many expired rows one cleanup statement
+	// deletes; cleanup repeats until none are left
+	postgresCleanupBatch = 500
+	// postgresMigrationLock is the advisory lock key held while migrating,
+	// so instances starting together migrate one at a time
+	postgresMigrationLock = 0x5e55_10e5
+)
+
+// postgresMigrations are the schema changes, applied in order and recorded
+// in schema_migrations; append new ones, never edit applied ones
+var postgresMigrations = []string{
+	// 1: sessions as JSON, with the columns queries filter on
+	`CREATE TABLE sessions (
+		token      text PRIMARY KEY,
+		status     text NOT NULL,
+		expires_at timestamptz NOT NULL,
+		data       jsonb NOT NULL
+	);
+	CREATE INDEX sessions_expires_at ON sessions (expires_at);
+	CREATE INDEX sessions_status ON sessions (status)`,
+}
+
+// PostgresSessionRepository implements SessionRepository on PostgreSQL, so
+// several server instances behind a load balancer share their sessions.
+// Sessions are stored as JSON next to the columns queries filter on.
+type PostgresSessionRepository struct {
+	pool   *pgxpool.Pool
+	tokens entities.TokenPolicy
+}
+
+// NewPostgresSessionRepository connects a pool of at most maxConns
+// connections (the driver's default when 0) to the database at dsn, brings
+// its schema up to date and hands out tokens of the given policy
+func NewPostgresSessionRepository(dsn string, maxConns int, tokens entities.TokenPolicy) (*PostgresSessionRepository, error) {
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Postgres DSN: %w", err)
+	}
+	if maxConns > 0 {
+		config.MaxConns = int32(maxConns)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresConnectTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("connecting to Postgres: %w", err)
+	}
+	if err := migratePostgres(ctx, pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("migrating the Postgres schema: %w", err)
+	}
+
+	return &PostgresSessionRepository{pool: pool, tokens: tokens}, nil
+}
+
+// migratePostgres applies the migrations the database has not seen yet
+func migratePostgres(ctx context.Context, pool *pgxpool.Pool) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, postgresMigrationLock); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	var applied int
+	if err := tx.QueryRow(ctx, `SELECT coalesce(max(version), 0) FROM schema_migrations`).Scan(&applied); err != nil {
+		return err
+	}
+	if applied > len(postgresMigrations) {
+		return fmt.Errorf("database schema version %d is newer than this build's %d", applied, len(postgresMigrations))
+	}
+	for version := applied + 1; version <= len(postgresMigrations); version++ {
+		if _, err := tx.Exec(ctx, postgresMigrations[version-1]); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			return err
+		}
+		log.Printf("💾 Applied Postgres migration %d", version)
+	}
+	return tx.Commit(ctx)
+}
+
+// Close closes the connection pool
+func (r *PostgresSessionRepository) Close() error {
+	r.pool.Close()
+	return nil
+}
+
+// CreateSession creates a new session with a unique token
+func (r *PostgresSessionRepository) CreateSession(expiryDuration time.Duration) (*entities.Session, error) {
+	session, err := newSession(r.tokens, expiryDuration)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	_, err = r.pool.Exec(ctx, `INSERT INTO sessions (token, status, expires_at, data) VALUES ($1, $2, $3, $4)`,
+		session.Token, string(session.Status), session.ExpiresAt, data)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSession retrieves a session by token
+func (r *PostgresSessionRepository) GetSession(token string) (*entities.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	var data []byte
+	err := r.pool.QueryRow(ctx, `SELECT data FROM sessions WHERE token = $1`, token).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var session entities.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateSession updates an existing session
+func (r *PostgresSessionRepository) UpdateSession(session *entities.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	tag, err := r.pool.Exec(ctx, `UPDATE sessions SET status = $2, expires_at = $3, data = $4 WHERE token = $1`,
+		session.Token, string(session.Status), session.ExpiresAt, data)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteSession removes a session
+func (r *PostgresSessionRepository) DeleteSession(token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	_, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE token = $1`, token)
+	return err
+}
+
+// CleanupExpiredSessions removes all expired sessions in batches. Rows
+// another instance is already deleting are skipped, so instances cleaning up
+// at the same time do not wait on each other.
+func (r *PostgresSessionRepository) CleanupExpiredSessions() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	now := time.Now()
+	cleaned := 0
+	for {
+		tag, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE token IN (
+			SELECT token FROM sessions WHERE expires_at < $1
+			ORDER BY expires_at LIMIT $2 FOR UPDATE SKIP LOCKED
+		)`, now, postgresCleanupBatch)
+		if err != nil {
+			return cleaned, err
+		}
+		cleaned += int(tag.RowsAffected())
+		if tag.RowsAffected() < postgresCleanupBatch {
+			break
+		}
+	}
+
+	if cleaned > 0 {
+		log.Printf("🗑️  GC: cleaned up %d expired tokens", cleaned)
+	}
+	return cleaned, nil
+}
+
+// GetActiveSessionsCount returns the number of active sessions
+func (r *PostgresSessionRepository) GetActiveSessionsCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	var count int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM sessions WHERE status = $1 AND expires_at >= $2`,
+		string(entities.SessionStatusActive), time.Now()).Scan(&count)
+	return count, err
+}
+
+// ListSessions returns all stored sessions
+func (r *PostgresSessionRepository) ListSessions() ([]*entities.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	rows, err := r.pool.Query(ctx, `SELECT data FROM sessions`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*entities.Session, error) {
+		var data []byte
+		if err := row.Scan(&data); err != nil {
+			return nil, err
+		}
+		var session entities.Session
+		if err := json.Unmarshal(data, &session); err != nil {
+			return nil, err
+		}
+		return &session, nil
+	})
+}
+
+// RestoreSession stores a session under its own token, replacing any session
+// with that token
+func (r *PostgresSessionRepository) RestoreSession(session *entities.Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresQueryTimeout)
+	defer cancel()
+
+	_, err = r.pool.Exec(ctx, `INSERT INTO sessions (token, status, expires_at, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token) DO UPDATE SET status = excluded.status, expires_at = excluded.expires_at, data = excluded.data`,
+		session.Token, string(session.Status), session.ExpiresAt, data)
+	return err
+}
+
+// Ensure PostgresSessionRepository implements SessionRepository
+var _ interfaces.SessionRepository = (*PostgresSessionRepository)(nil)
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// newTestPostgresRepository connects to the database in POSTGRES_TEST_DSN,
+// skipping the test without one, and empties the sessions table
+func newTestPostgresRepository(t *testing.T) *PostgresSessionRepository {
+	t.Helper()
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	repo, err := NewPostgresSessionRepository(dsn, 2, entities.TokenPolicy{})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { repo.Close() })
+	if _, err := repo.pool.Exec(context.Background(), `TRUNCATE sessions`); err != nil {
+		t.Fatalf("Failed to empty sessions: %v", err)
+	}
+	return repo
+}
+
+func TestNewPostgresSessionRepository_InvalidDSN(t *testing.T) {
+	if _, err := NewPostgresSessionRepository("postgres://host:notaport/db", 0, entities.TokenPolicy{}); err == nil {
+		t.Error("Expected an invalid DSN to be refused")
+	}
+}
+
+func TestNewPostgresSessionRepository_Unreachable(t *testing.T) {
+	// Nothing listens on port 1, so connecting fails right away
+	if _, err := NewPostgresSessionRepository("postgres://user@127.0.0.1:1/db?connect_timeout=1", 0, entities.TokenPolicy{}); err == nil {
+		t.Error("Expected an unreachable database to be refused")
+	}
+}
+
+func TestPostgresSessionRepository_Lifecycle(t *testing.T) {
+	repo := newTestPostgresRepository(t)
+
+	session, err := repo.CreateSession(30 * time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stored, err := repo.GetSession(session.Token)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored.Status != entities.SessionStatusPending || !stored.ExpiresAt.Equal(session.ExpiresAt) {
+		t.Errorf("Expected the created session, got %+v", stored)
+	}
+
+	session.Status = entities.SessionStatusActive
+	session.Name = "Standup"
+	if err := repo.UpdateSession(session); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stored, _ := repo.GetSession(session.Token); stored.Name != "Standup" {
+		t.Errorf("Expected the updated session, got %+v", stored)
+	}
+	if count, _ := repo.GetActiveSessionsCount(); count != 1 {
+		t.Errorf("Expected 1 active session, got %d", count)
+	}
+	if err := repo.UpdateSession(&entities.Session{Token: "missing", Status: entities.SessionStatusActive}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	if err := repo.DeleteSession(session.Token); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := repo.GetSession(session.Token); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestPostgresSessionRepository_CleanupExpiredSessions(t *testing.T) {
+	repo := newTestPostgresRepository(t)
+	live, _ := repo.CreateSession(30 * time.Minute)
+	for _, token := range []string{"expired-1", "expired-2"} {
+		if err := repo.RestoreSession(&entities.Session{
+			Token:     token,
+			ExpiresAt: time.Now().Add(-time.Minute),
+			Status:    entities.SessionStatusActive,
+		}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	cleaned, err := repo.CleanupExpiredSessions()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cleaned != 2 {
+		t.Errorf("Expected 2 sessions cleaned up, got %d", cleaned)
+	}
+
+	sessions, _ := repo.ListSessions()
+	if len(sessions) != 1 || sessions[0].Token != live.Token {
+		t.Errorf("Expected only the live session left, got %+v", sessions)
+	}
+}
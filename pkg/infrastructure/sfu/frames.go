@@ -0,0 +1,299 @@
+package sfu
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+	"github.com/pion/webrtc/v4"
+	"golang.org/x/image/vp8"
+
+	"share-screen/pkg/domain/entities"
+)
+
+const (
+	// framePeriod is how often the relay asks for a keyframe while a stream
+	// has snapshot watchers, and so how often their picture changes
+	framePeriod = 2 * time.Second
+
+	// frameQuality is the JPEG quality of snapshots
+	frameQuality = 75
+
+	// maxKeyframeSize bounds a keyframe being put together from its packets
+	maxKeyframeSize = 4 << 20
+
+	// snapshotTimeout bounds the wait for the keyframe a snapshot asks for
+	snapshotTimeout = 5 * time.Second
+)
+
+// errFramesUnsupported is returned for streams whose video is not VP8, the
+// only codec the relay decodes
+var errFramesUnsupported = errors.New("snapshots need VP8 video")
+
+// errSnapshotTimeout is returned when no keyframe arrives in time for a
+// snapshot, e.g. while the sender is paused
+var errSnapshotTimeout = errors.New("no keyframe arrived for the snapshot")
+
+// frameTap turns the keyframes of a stream's VP8 video into JPEG snapshots
+// for its watchers, such as TV browsers showing an MJPEG stream. It receives
+// the middle layer like a viewer on LayerMid, which is sharp enough for a TV
+// and quicker to decode, and exists only while anyone watches.
+type frameTap struct {
+	viewer *viewer
+
+	// keyframes carries whole keyframes to the decoder, which drops those
+	// arriving while it is busy
+	keyframes chan []byte
+	done      chan struct{}
+
+	mu sync.Mutex
+	// frame is the keyframe being put together, and next the sequence number
+	// of the packet it continues with
+	frame      []byte
+	next       uint16
+	collecting bool
+
+	watchers map[chan []byte]struct{}
+	// picture is the latest keyframe decoded, and last its JPEG
+	picture image.Image
+	last    []byte
+	closed  bool
+}
+
+// newFrameTap creates a frame tap of a stream publishing layers
+func newFrameTap(layers []string) *frameTap {
+	return &frameTap{
+		viewer:    newViewer(nil, nil, entities.LayerMid, layers),
+		keyframes: make(chan []byte, 1),
+		done:      make(chan struct{}),
+		watchers:  make(map[chan []byte]struct{}),
+	}
+}
+
+// write collects a packet arriving on the layer rid if it belongs to a
+// keyframe on the tap's layer, and hands over each keyframe it completes
+func (t *frameTap) write(rid string, packet *rtp.Packet, keyframe bool) {
+	out := t.viewer.next(rid, packet, keyframe)
+	if out == nil {
+		return
+	}
+	var descriptor codecs.VP8Packet
+	payload, err := descriptor.Unmarshal(out.Payload)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if descriptor.S == 1 && descriptor.PID == 0 {
+		// A frame starts; bit 0 of its frame tag is clear on keyframes
+		t.frame = t.frame[:0]
+		t.collecting = len(payload) > 0 && payload[0]&0x01 == 0
+	} else if out.SequenceNumber != t.next {
+		// A lost packet spoils the frame
+		t.collecting = false
+	}
+	if !t.collecting {
+		return
+	}
+
+	t.frame = append(t.frame, payload...)
+	t.next = out.SequenceNumber + 1
+	if len(t.frame) > maxKeyframeSize {
+		t.collecting = false
+		return
+	}
+	if out.Marker {
+		t.collecting = false
+		select {
+		case t.keyframes <- bytes.Clone(t.frame):
+		default:
+		}
+	}
+}
+
+// run decodes the keyframes handed over into snapshots for the watchers
+// until the tap is closed
+func (t *frameTap) run() {
+	for {
+		select {
+		case <-t.done:
+			return
+		case keyframe := <-t.keyframes:
+			// A keyframe the decoder cannot read is skipped; the next one
+			// follows within framePeriod
+			if picture, err := decodeKeyframe(keyframe); err == nil {
+				t.publish(picture)
+			}
+		}
+	}
+}
+
+// publish hands a decoded keyframe as a JPEG to every watcher, replacing
+// one a slow watcher has not taken yet
+func (t *frameTap) publish(picture image.Image) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, picture, &jpeg.Options{Quality: frameQuality}); err != nil {
+		return
+	}
+	snapshot := encoded.Bytes()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.picture = picture
+	t.last = snapshot
+	for watcher := range t.watchers {
+		select {
+		case <-watcher:
+		default:
+		}
+		watcher <- snapshot
+	}
+}
+
+// watch returns a new watcher's channel of snapshots, starting with the
+// latest if there is one
+func (t *frameTap) watch() chan []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	watcher := make(chan []byte, 1)
+	if t.last != nil {
+		watcher <- t.last
+	}
+	t.watchers[watcher] = struct{}{}
+	return watcher
+}
+
+// latest returns the latest keyframe decoded, or nil before the first
+func (t *frameTap) latest() image.Image {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.picture
+}
+
+// unwatch closes a watcher's channel and reports how many watchers are left
+func (t *frameTap) unwatch(watcher chan []byte) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.watchers[watcher]; ok {
+		delete(t.watchers, watcher)
+		close(watcher)
+	}
+	return len(t.watchers)
+}
+
+// close stops the tap and closes the channels of its watchers
+func (t *frameTap) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	t.closed = true
+	close(t.done)
+	for watcher := range t.watchers {
+		delete(t.watchers, watcher)
+		close(watcher)
+	}
+}
+
+// decodeKeyframe decodes a VP8 keyframe
+func decodeKeyframe(keyframe []byte) (image.Image, error) {
+	decoder := vp8.NewDecoder()
+	decoder.Init(bytes.NewReader(keyframe), len(keyframe))
+	header, err := decoder.DecodeFrameHeader()
+	if err != nil {
+		return nil, err
+	}
+	if !header.KeyFrame {
+		return nil, errors.New("not a keyframe")
+	}
+	return decoder.DecodeFrame()
+}
+
+// WatchFrames streams JPEG snapshots of a session's VP8 video, decoded from
+// a keyframe the relay asks for every framePeriod while anyone watches. The
+// channel is closed when the stream ends or switches codec, or on stop.
+func (r *Relay) WatchFrames(token string) (<-chan []byte, func(), error) {
+	_, watcher, stop, err := r.tapFrames(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	return watcher, stop, nil
+}
+
+// Snapshot returns a session's screen as a PNG, decoded from the latest
+// keyframe of its VP8 video. Unless snapshots are being watched already, it
+// asks the sender for a keyframe and waits up to snapshotTimeout for it.
+func (r *Relay) Snapshot(token string) ([]byte, error) {
+	tap, watcher, stop, err := r.tapFrames(token)
+	if err != nil {
+		return nil, err
+	}
+	defer stop()
+
+	select {
+	case _, ok := <-watcher:
+		if !ok {
+			return nil, errNotPublished
+		}
+	case <-time.After(snapshotTimeout):
+		return nil, errSnapshotTimeout
+	}
+	var snapshot bytes.Buffer
+	if err := png.Encode(&snapshot, tap.latest()); err != nil {
+		return nil, err
+	}
+	return snapshot.Bytes(), nil
+}
+
+// tapFrames adds a watcher to the frame tap of a session's stream, starting
+// the tap for the first
+func (r *Relay) tapFrames(token string) (*frameTap, chan []byte, func(), error) {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil {
+		r.mu.Unlock()
+		return nil, nil, nil, errNotPublished
+	}
+	// The codec is known once the video arrives
+	if s.codec.MimeType != "" && s.codec.MimeType != webrtc.MimeTypeVP8 {
+		r.mu.Unlock()
+		return nil, nil, nil, errFramesUnsupported
+	}
+	tap := s.frames
+	started := tap == nil
+	if started {
+		tap = newFrameTap(s.layerIDs())
+		s.frames = tap
+	}
+	watcher := tap.watch()
+	r.mu.Unlock()
+
+	if started {
+		go tap.run()
+		go r.requestKeyframes(token, tap.viewer, 0, framePeriod, tap.done)
+	}
+
+	stop := func() {
+		r.mu.Lock()
+		last := tap.unwatch(watcher) == 0 && s.frames == tap
+		if last {
+			s.frames = nil
+		}
+		r.mu.Unlock()
+		if last {
+			tap.close()
+		}
+	}
+	return tap, watcher, stop, nil
+}
@@ -0,0 +1,134 @@
+package sfu
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/rtp/codecs"
+)
+
+// vp8Packets packetizes a VP8 frame into RTP packets from sequence number seq
+func vp8Packets(frame []byte, seq uint16) []*rtp.Packet {
+	var payloader codecs.VP8Payloader
+	var packets []*rtp.Packet
+	for i, payload := range payloader.Payload(500, frame) {
+		packets = append(packets, &rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: 1, SequenceNumber: seq + uint16(i), Timestamp: uint32(seq)},
+			Payload: payload,
+		})
+	}
+	packets[len(packets)-1].Marker = true
+	return packets
+}
+
+func TestFrameTap_CollectsKeyframes(t *testing.T) {
+	tap := newFrameTap([]string{""})
+	// Bit 0 of the frame tag is clear on keyframes
+	keyframe := append([]byte{0x10}, bytes.Repeat([]byte{0xab}, 1500)...)
+	interframe := append([]byte{0x11}, bytes.Repeat([]byte{0xcd}, 1500)...)
+
+	send := func(packets []*rtp.Packet) {
+		for i, packet := range packets {
+			tap.write("", packet, i == 0 && packet.Payload[1]&0x01 == 0)
+		}
+	}
+
+	send(vp8Packets(keyframe, 100))
+	select {
+	case got := <-tap.keyframes:
+		if !bytes.Equal(got, keyframe) {
+			t.Fatalf("Expected the keyframe put back together, got %d bytes", len(got))
+		}
+	default:
+		t.Fatal("Expected a keyframe")
+	}
+
+	send(vp8Packets(interframe, 200))
+	lossy := vp8Packets(keyframe, 300)
+	send(append(lossy[:1], lossy[2:]...))
+	select {
+	case got := <-tap.keyframes:
+		t.Errorf("Expected interframes and incomplete keyframes to be skipped, got %d bytes", len(got))
+	default:
+	}
+}
+
+// picture returns an image told apart from others by its width
+func picture(width int) image.Image {
+	return image.NewGray(image.Rect(0, 0, width, 1))
+}
+
+// snapshotWidth returns the width of a JPEG snapshot
+func snapshotWidth(t *testing.T, snapshot []byte) int {
+	t.Helper()
+	config, err := jpeg.DecodeConfig(bytes.NewReader(snapshot))
+	if err != nil {
+		t.Fatalf("Expected a JPEG snapshot: %v", err)
+	}
+	return config.Width
+}
+
+func TestFrameTap_Watchers(t *testing.T) {
+	tap := newFrameTap([]string{""})
+
+	first := tap.watch()
+	tap.publish(picture(1))
+	if got := snapshotWidth(t, <-first); got != 1 {
+		t.Errorf("Expected the first snapshot, got width %d", got)
+	}
+
+	// A slow watcher gets the latest snapshot only
+	tap.publish(picture(2))
+	tap.publish(picture(3))
+	if got := snapshotWidth(t, <-first); got != 3 {
+		t.Errorf("Expected the latest snapshot, got width %d", got)
+	}
+
+	// A new watcher starts with the latest snapshot
+	second := tap.watch()
+	if got := snapshotWidth(t, <-second); got != 3 {
+		t.Errorf("Expected the latest snapshot, got width %d", got)
+	}
+	if got := tap.latest().Bounds().Dx(); got != 3 {
+		t.Errorf("Expected the latest picture, got width %d", got)
+	}
+
+	if left := tap.unwatch(first); left != 1 {
+		t.Errorf("Expected one watcher left, got %d", left)
+	}
+	if _, ok := <-first; ok {
+		t.Error("Expected the channel of a watcher gone to be closed")
+	}
+
+	tap.close()
+	if _, ok := <-second; ok {
+		t.Error("Expected the channels to be closed with the tap")
+	}
+	tap.publish(picture(4))
+	tap.close()
+	if left := tap.unwatch(second); left != 0 {
+		t.Errorf("Expected no watchers left, got %d", left)
+	}
+}
+
+func TestDecodeKeyframe_Invalid(t *testing.T) {
+	if _, err := decodeKeyframe([]byte{0x10, 0x02, 0x00}); err == nil {
+		t.Error("Expected error for a truncated keyframe")
+	}
+}
+
+func TestRelay_WatchFramesNotPublished(t *testing.T) {
+	relay, err := NewRelay(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("NewRelay failed: %v", err)
+	}
+	if _, _, err := relay.WatchFrames("missing"); err == nil {
+		t.Error("Expected error for a session without a stream")
+	}
+	if _, err := relay.Snapshot("missing"); err == nil {
+		t.Error("Expected snapshot error for a session without a stream")
+	}
+}
@@ -0,0 +1,291 @@
+package sfu
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// LayerAuto steps a viewer down a layer when its receiver reports show heavy
+// loss, and back up once the loss has stayed low for a while
+const (
+	autoDownLoss = 0.10
+	autoUpLoss   = 0.02
+	autoDownWait = 2 * time.Second
+	autoUpWait   = 10 * time.Second
+)
+
+// switchGap is the timestamp step between the last packet of one source and
+// the first of the next: one frame at 30 fps on the 90 kHz video clock
+const switchGap = 3000
+
+// viewer is one viewer's connection to a stream. Each viewer gets its own
+// track so its layer can change without affecting the others.
+type viewer struct {
+	pc    *webrtc.PeerConnection
+	track *webrtc.TrackLocalStaticRTP
+
+	// connected is guarded by the relay's mutex, the fields below by mu
+	connected bool
+
+	mu sync.Mutex
+
+	// selected is the layer the viewer asked for. target is the RID of the
+	// published layer that serves it best and current the one it receives,
+	// which only changes to target on a keyframe so the picture never breaks.
+	selected entities.SimulcastLayer
+	target   string
+	current  string
+
+	// autoRank is the rank LayerAuto aims for; lastChange and calmSince
+	// pace its moves
+	autoRank   int
+	lastChange time.Time
+	calmSince  time.Time
+
+	// source is the SSRC packets last came from, and the offsets map its
+	// sequence numbers and timestamps onto the viewer's
+	source        uint32
+	started       bool
+	seqOffset     uint16
+	tsOffset      uint32
+	lastSeq       uint16
+	lastTimestamp uint32
+}
+
+// newViewer creates a viewer of the given layers receiving the selected one
+func newViewer(pc *webrtc.PeerConnection, track *webrtc.TrackLocalStaticRTP, selected entities.SimulcastLayer, layers []string) *viewer {
+	v := &viewer{pc: pc, track: track, selected: selected, autoRank: topRank}
+	v.retarget(layers)
+	v.current = v.target
+	return v
+}
+
+// topRank is the rank of the full picture
+var topRank = entities.LayerHigh.Rank()
+
+// selectLayer changes the layer the viewer asked for and reports whether its
+// target changed
+func (v *viewer) selectLayer(layer entities.SimulcastLayer, layers []string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.selected = layer
+	v.autoRank = topRank
+	v.calmSince = time.Time{}
+	return v.retarget(layers)
+}
+
+// adapt moves a viewer on LayerAuto between layers by the fraction of packets
+// its last receiver report lost, and reports whether its target changed
+func (v *viewer) adapt(loss float64, now time.Time, layers []string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.selected != entities.LayerAuto {
+		return false
+	}
+
+	switch {
+	case loss > autoDownLoss:
+		v.calmSince = time.Time{}
+		if now.Sub(v.lastChange) < autoDownWait || v.autoRank == 0 {
+			return false
+		}
+		v.autoRank--
+	case loss < autoUpLoss:
+		if v.calmSince.IsZero() {
+			v.calmSince = now
+		}
+		if now.Sub(v.calmSince) < autoUpWait || now.Sub(v.lastChange) < autoUpWait || v.autoRank == topRank {
+			return false
+		}
+		v.autoRank++
+	default:
+		v.calmSince = time.Time{}
+		return false
+	}
+	v.lastChange = now
+	return v.retarget(layers)
+}
+
+// layersChanged points the viewer at the best of the published layers
+func (v *viewer) layersChanged(layers []string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.retarget(layers)
+}
+
+// retarget picks the published layer closest to what the viewer wants and
+// reports whether it changed; a viewer whose layer is no longer published
+// moves at once, since there is nothing left to wait on
+func (v *viewer) retarget(layers []string) bool {
+	rank := v.selected.Rank()
+	if v.selected == entities.LayerAuto {
+		rank = v.autoRank
+	}
+	target := pickLayer(layers, rank)
+	changed := target != v.target
+	v.target = target
+	if !slices.Contains(layers, v.current) {
+		v.current = target
+	}
+	return changed
+}
+
+// targetLayer returns the RID of the layer the viewer is moving to
+func (v *viewer) targetLayer() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.target
+}
+
+// next returns the packet to send the viewer for one arriving on the layer
+// rid, or nil when the viewer does not get that layer
+func (v *viewer) next(rid string, packet *rtp.Packet, keyframe bool) *rtp.Packet {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if rid != v.current {
+		if rid != v.target || !keyframe {
+			return nil
+		}
+		v.current = rid
+	}
+	return v.rewrite(packet)
+}
+
+// rewrite gives a packet the viewer's own sequence numbers and timestamps,
+// carrying on where the previous source left off, so switching layers or
+// publishers looks like one stream to the viewer. The publisher's header
+// extensions are dropped as their IDs mean nothing to the viewer.
+func (v *viewer) rewrite(packet *rtp.Packet) *rtp.Packet {
+	if packet.SSRC != v.source {
+		if v.started {
+			v.seqOffset = v.lastSeq + 1 - packet.SequenceNumber
+			v.tsOffset = v.lastTimestamp + switchGap - packet.Timestamp
+		}
+		v.source = packet.SSRC
+	}
+
+	out := *packet
+	out.Header.Extension = false
+	out.Header.Extensions = nil
+	out.SequenceNumber += v.seqOffset
+	out.Timestamp += v.tsOffset
+	if !v.started || int16(out.SequenceNumber-v.lastSeq) > 0 {
+		v.lastSeq = out.SequenceNumber
+		v.lastTimestamp = out.Timestamp
+		v.started = true
+	}
+	return &out
+}
+
+// pickLayer returns the largest of the published layers (by RID) up to rank,
+// or the smallest when all are larger
+func pickLayer(layers []string, rank int) string {
+	best, bestRank := "", -1
+	smallest, smallestRank := "", len(entities.SimulcastLayers)
+	for _, rid := range layers {
+		r := entities.SimulcastLayer(rid).Rank()
+		if r <= rank && r > bestRank {
+			best, bestRank = rid, r
+		}
+		if r < smallestRank {
+			smallest, smallestRank = rid, r
+		}
+	}
+	if bestRank >= 0 {
+		return best
+	}
+	return smallest
+}
+
+// isKeyframe reports whether an RTP payload starts a keyframe, where a viewer
+// can switch layers. Payloads of codecs it cannot read count as keyframes, so
+// switching is immediate for them.
+func isKeyframe(mimeType string, payload []byte) bool {
+	switch mimeType {
+	case webrtc.MimeTypeVP8:
+		return isVP8Keyframe(payload)
+	case webrtc.MimeTypeVP9:
+		return isVP9Keyframe(payload)
+	case webrtc.MimeTypeH264:
+		return isH264Keyframe(payload)
+	}
+	return true
+}
+
+// isVP8Keyframe reads the VP8 payload descriptor (RFC 7741) and the start of
+// the frame behind it
+func isVP8Keyframe(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+	// Only the first packet of partition 0 carries the frame header
+	start, partition := payload[0]&0x10 != 0, payload[0]&0x07
+	if !start || partition != 0 {
+		return false
+	}
+	i := 1
+	if payload[0]&0x80 != 0 {
+		if len(payload) < 2 {
+			return false
+		}
+		ext := payload[1]
+		i = 2
+		if ext&0x80 != 0 { // picture ID, 7 or 15 bits
+			if len(payload) <= i {
+				return false
+			}
+			if payload[i]&0x80 != 0 {
+				i++
+			}
+			i++
+		}
+		if ext&0x40 != 0 { // TL0PICIDX
+			i++
+		}
+		if ext&0x30 != 0 { // TID and KEYIDX
+			i++
+		}
+	}
+	return len(payload) > i && payload[i]&0x01 == 0
+}
+
+// isVP9Keyframe reads the VP9 payload descriptor: a keyframe's first packet
+// begins a frame that is not inter-predicted
+func isVP9Keyframe(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+	interPredicted, beginsFrame := payload[0]&0x40 != 0, payload[0]&0x08 != 0
+	return !interPredicted && beginsFrame
+}
+
+// isH264Keyframe looks for an IDR slice or the SPS sent ahead of one, alone,
+// in a STAP-A aggregate or at the start of an FU-A fragment
+func isH264Keyframe(payload []byte) bool {
+	if len(payload) < 1 {
+		return false
+	}
+	keyNAL := func(naluType byte) bool { return naluType == 5 || naluType == 7 }
+
+	switch naluType := payload[0] & 0x1F; naluType {
+	case 24: // STAP-A
+		for i := 1; i+2 < len(payload); {
+			size := int(payload[i])<<8 | int(payload[i+1])
+			if keyNAL(payload[i+2] & 0x1F) {
+				return true
+			}
+			i += 2 + size
+		}
+		return false
+	case 28: // FU-A
+		return len(payload) > 1 && payload[1]&0x80 != 0 && keyNAL(payload[1]&0x1F)
+	default:
+		return keyNAL(naluType)
+	}
+}
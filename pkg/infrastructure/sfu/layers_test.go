@@ -0,0 +1,150 @@
+package sfu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestPickLayer(t *testing.T) {
+	all := []string{"high", "low", "mid"}
+	tests := []struct {
+		name   string
+		layers []string
+		rank   int
+		want   string
+	}{
+		{name: "full picture", layers: all, rank: 2, want: "high"},
+		{name: "middle", layers: all, rank: 1, want: "mid"},
+		{name: "smallest", layers: all, rank: 0, want: "low"},
+		{name: "closest below", layers: []string{"low", "high"}, rank: 1, want: "low"},
+		{name: "smallest when all are larger", layers: []string{"mid", "high"}, rank: 0, want: "mid"},
+		{name: "no simulcast", layers: []string{""}, rank: 0, want: ""},
+		{name: "nothing published", layers: nil, rank: 2, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pickLayer(tt.layers, tt.rank); got != tt.want {
+				t.Errorf("pickLayer(%v, %d) = %q, want %q", tt.layers, tt.rank, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKeyframe(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		payload  []byte
+		want     bool
+	}{
+		{name: "VP8 keyframe", mimeType: webrtc.MimeTypeVP8, payload: []byte{0x10, 0x00, 0x9d}, want: true},
+		{name: "VP8 interframe", mimeType: webrtc.MimeTypeVP8, payload: []byte{0x10, 0x01, 0x9d}, want: false},
+		{name: "VP8 keyframe with picture ID", mimeType: webrtc.MimeTypeVP8, payload: []byte{0x90, 0x80, 0x81, 0x23, 0x00}, want: true},
+		{name: "VP8 continuation", mimeType: webrtc.MimeTypeVP8, payload: []byte{0x00, 0x00}, want: false},
+		{name: "VP9 keyframe", mimeType: webrtc.MimeTypeVP9, payload: []byte{0x08}, want: true},
+		{name: "VP9 interframe", mimeType: webrtc.MimeTypeVP9, payload: []byte{0x48}, want: false},
+		{name: "H264 IDR", mimeType: webrtc.MimeTypeH264, payload: []byte{0x65}, want: true},
+		{name: "H264 SPS in STAP-A", mimeType: webrtc.MimeTypeH264, payload: []byte{0x78, 0x00, 0x02, 0x67, 0x42}, want: true},
+		{name: "H264 IDR fragment start", mimeType: webrtc.MimeTypeH264, payload: []byte{0x7c, 0x85}, want: true},
+		{name: "H264 IDR fragment middle", mimeType: webrtc.MimeTypeH264, payload: []byte{0x7c, 0x05}, want: false},
+		{name: "H264 non-IDR slice", mimeType: webrtc.MimeTypeH264, payload: []byte{0x41}, want: false},
+		{name: "unknown codec", mimeType: webrtc.MimeTypeAV1, payload: []byte{0x00}, want: true},
+		{name: "empty payload", mimeType: webrtc.MimeTypeVP8, payload: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isKeyframe(tt.mimeType, tt.payload); got != tt.want {
+				t.Errorf("isKeyframe(%s, %x) = %v, want %v", tt.mimeType, tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestViewer_SwitchesOnKeyframe(t *testing.T) {
+	layers := []string{"high", "low", "mid"}
+	v := newViewer(nil, nil, entities.LayerHigh, layers)
+
+	packet := func(ssrc uint32, seq uint16, ts uint32) *rtp.Packet {
+		return &rtp.Packet{Header: rtp.Header{SSRC: ssrc, SequenceNumber: seq, Timestamp: ts}}
+	}
+
+	if out := v.next("high", packet(1, 100, 9000), false); out == nil || out.SequenceNumber != 100 {
+		t.Fatalf("Expected the high layer to pass unchanged, got %+v", out)
+	}
+	if out := v.next("low", packet(2, 500, 50000), true); out != nil {
+		t.Fatal("Expected other layers to be dropped")
+	}
+
+	if !v.selectLayer(entities.LayerLow, layers) {
+		t.Fatal("Expected selecting the low layer to change the target")
+	}
+	if out := v.next("low", packet(2, 501, 51000), false); out != nil {
+		t.Fatal("Expected the switch to wait for a keyframe")
+	}
+	if out := v.next("high", packet(1, 101, 12000), false); out == nil {
+		t.Fatal("Expected the old layer to keep flowing until the switch")
+	}
+
+	out := v.next("low", packet(2, 502, 52000), true)
+	if out == nil {
+		t.Fatal("Expected the switch on the keyframe")
+	}
+	if out.SequenceNumber != 102 || out.Timestamp != 12000+switchGap {
+		t.Errorf("Expected the new layer to carry on at seq 102, ts %d; got seq %d, ts %d", 12000+switchGap, out.SequenceNumber, out.Timestamp)
+	}
+	if out := v.next("high", packet(1, 102, 15000), true); out != nil {
+		t.Error("Expected the old layer to be dropped after the switch")
+	}
+	if out := v.next("low", packet(2, 503, 53000), false); out == nil || out.SequenceNumber != 103 || out.Timestamp != 13000+switchGap {
+		t.Errorf("Expected continuous numbering after the switch, got %+v", out)
+	}
+}
+
+func TestViewer_Adapt(t *testing.T) {
+	layers := []string{"high", "low", "mid"}
+	v := newViewer(nil, nil, entities.LayerAuto, layers)
+	start := time.Now()
+
+	if !v.adapt(0.3, start, layers) || v.targetLayer() != "mid" {
+		t.Fatalf("Expected heavy loss to step down to mid, target %q", v.targetLayer())
+	}
+	if v.adapt(0.3, start.Add(time.Second), layers) {
+		t.Error("Expected no second step before autoDownWait")
+	}
+	if !v.adapt(0.3, start.Add(autoDownWait), layers) || v.targetLayer() != "low" {
+		t.Fatalf("Expected a second step down to low, target %q", v.targetLayer())
+	}
+	if v.adapt(0.3, start.Add(2*autoDownWait), layers) {
+		t.Error("Expected no step below the smallest layer")
+	}
+
+	calm := start.Add(3 * autoDownWait)
+	if v.adapt(0, calm, layers) || v.adapt(0, calm.Add(autoUpWait/2), layers) {
+		t.Error("Expected no step up before the loss stayed low for autoUpWait")
+	}
+	if !v.adapt(0, calm.Add(autoUpWait), layers) || v.targetLayer() != "mid" {
+		t.Errorf("Expected a step up to mid after calm, target %q", v.targetLayer())
+	}
+
+	fixed := newViewer(nil, nil, entities.LayerMid, layers)
+	if fixed.adapt(0.5, start, layers) || fixed.targetLayer() != "mid" {
+		t.Error("Expected a picked layer to ignore loss")
+	}
+}
+
+func TestViewer_LayerGone(t *testing.T) {
+	v := newViewer(nil, nil, entities.LayerAuto, []string{"high", "low", "mid"})
+
+	// A sender that publishes again without simulcast leaves one layer
+	v.layersChanged([]string{""})
+	if out := v.next("", &rtp.Packet{}, false); out == nil {
+		t.Error("Expected a viewer to move at once when its layer is gone")
+	}
+}
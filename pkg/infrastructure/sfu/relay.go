@@ -0,0 +1,638 @@
+// Package sfu forwards screen shares through the server: the sender of a
+// session publishes its video to the relay once, and the relay sends the same
+// packets on to every viewer, so the sender's uplink and CPU do not grow with
+// its audience. A sender may publish simulcast layers, of which each viewer
+// gets the one it picks or the one its connection copes with.
+package sfu
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// answerTimeout is how long a viewer has to answer the relay's offer before
+// its connection is dropped
+const answerTimeout = time.Minute
+
+// trackID and streamID name the forwarded video in the viewers' SDP
+const (
+	trackID  = "video"
+	streamID = "share-screen"
+)
+
+var (
+	errNotPublished  = errors.New("stream not published")
+	errUnknownViewer = errors.New("no offer was made to this viewer")
+)
+
+// Relay implements the StreamRelay interface with pion, forwarding the RTP
+// packets of each session's published video track to its viewers
+type Relay struct {
+	api    *webrtc.API
+	config webrtc.Configuration
+
+	mu        sync.Mutex
+	streams   map[string]*stream
+	onViewers func(token string, viewers int)
+
+	// restreamTo returns where to republish a session's video, or nil
+	restreamTo func(token string) interfaces.VideoRecorder
+
+	// reportMu keeps viewer counts reported in the order they were taken
+	reportMu sync.Mutex
+}
+
+// stream is the published video of one session and its viewers
+type stream struct {
+	publisher *webrtc.PeerConnection
+
+	// codec is the codec viewers negotiated. It is set when the publisher's
+	// video arrives and viewers keep their connections while the sender
+	// publishes again with the same codec, so they carry on watching after
+	// a window switch.
+	codec webrtc.RTPCodecCapability
+
+	// layers maps the RIDs of the video layers arriving from layersOf, the
+	// latest publisher to send any, to their SSRCs; a sender without
+	// simulcast sends one layer with an empty RID
+	layers   map[string]webrtc.SSRC
+	layersOf *webrtc.PeerConnection
+
+	viewers map[string]*viewer
+
+	// restream republishes the video while the relay restreams
+	restream *restream
+
+	// frames makes snapshots of the video while anyone watches them
+	frames *frameTap
+
+	// reported is the number of connected viewers last reported
+	reported int
+}
+
+// layerIDs returns the RIDs of the stream's layers
+func (s *stream) layerIDs() []string {
+	return slices.Sorted(maps.Keys(s.layers))
+}
+
+// NewRelay creates a new SFU relay. Its connections gather candidates with
+// iceServers and, when udpPort is not 0, share that single UDP port so a
+// firewall or container only has to open one; otherwise they use random ports.
+// A non-nil restreamTo also republishes the video of each session to the
+// recorder it returns for the session's token, e.g. an RTMP restreamer.
+func NewRelay(iceServers []entities.ICEServer, udpPort int, restreamTo func(token string) interfaces.VideoRecorder) (interfaces.StreamRelay, error) {
+	mediaEngine := &webrtc.MediaEngine{}
+	if err := mediaEngine.RegisterDefaultCodecs(); err != nil {
+		return nil, err
+	}
+	registry := &interceptor.Registry{}
+	if err := webrtc.RegisterDefaultInterceptors(mediaEngine, registry); err != nil {
+		return nil, err
+	}
+
+	var settings webrtc.SettingEngine
+	if udpPort != 0 {
+		conn, err := net.ListenUDP("udp", &net.UDPAddr{Port: udpPort})
+		if err != nil {
+			return nil, fmt.Errorf("listening for SFU media on UDP port %d: %w", udpPort, err)
+		}
+		settings.SetICEUDPMux(webrtc.NewICEUDPMux(nil, conn))
+	}
+
+	return &Relay{
+		api: webrtc.NewAPI(
+			webrtc.WithMediaEngine(mediaEngine),
+			webrtc.WithInterceptorRegistry(registry),
+			webrtc.WithSettingEngine(settings),
+		),
+		config:     webrtc.Configuration{ICEServers: serverICEServers(iceServers)},
+		streams:    make(map[string]*stream),
+		restreamTo: restreamTo,
+	}, nil
+}
+
+// serverICEServers picks the ICE servers the relay itself can use; TURN
+// servers whose credentials are minted for each page are left out
+func serverICEServers(servers []entities.ICEServer) []webrtc.ICEServer {
+	var usable []webrtc.ICEServer
+	for _, server := range servers {
+		if server.NeedsCredentials() {
+			continue
+		}
+		usable = append(usable, webrtc.ICEServer{
+			URLs:       server.URLs,
+			Username:   server.Username,
+			Credential: server.Credential,
+		})
+	}
+	return usable
+}
+
+// Publish connects a sender's offer to the relay; a sender that published
+// before is disconnected once the new connection is negotiated
+func (r *Relay) Publish(token string, offer *entities.WebRTCOffer) (*entities.WebRTCAnswer, error) {
+	pc, err := r.api.NewPeerConnection(r.config)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo, webrtc.RTPTransceiverInit{
+		Direction: webrtc.RTPTransceiverDirectionRecvonly,
+	}); err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	pc.OnTrack(func(remote *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if remote.Kind() == webrtc.RTPCodecTypeVideo {
+			r.forward(token, pc, remote)
+		}
+	})
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		// A sender that went away takes its viewers with it
+		if state == webrtc.PeerConnectionStateFailed || state == webrtc.PeerConnectionStateClosed {
+			r.closeStream(token, pc)
+		}
+	})
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+	local, err := setLocalDescription(pc, answer)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil {
+		s = &stream{viewers: make(map[string]*viewer)}
+		r.streams[token] = s
+	}
+	previous := s.publisher
+	s.publisher = pc
+	r.mu.Unlock()
+
+	if previous != nil {
+		_ = previous.Close()
+	}
+	return &entities.WebRTCAnswer{Type: local.Type.String(), SDP: local.SDP}, nil
+}
+
+// IsPublished reports whether a session's video is reaching the relay
+func (r *Relay) IsPublished(token string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.streams[token]
+	return s != nil && len(s.layers) > 0
+}
+
+// Offer creates the relay's offer to a viewer, sending it the session's video
+func (r *Relay) Offer(token, viewerID string) (*entities.WebRTCOffer, error) {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || len(s.layers) == 0 {
+		r.mu.Unlock()
+		return nil, errNotPublished
+	}
+	codec := s.codec
+	// A viewer connecting again, e.g. after a network change, replaces its
+	// old connection and keeps its layer
+	selected := entities.LayerAuto
+	previous := s.viewers[viewerID]
+	if previous != nil {
+		previous.mu.Lock()
+		selected = previous.selected
+		previous.mu.Unlock()
+	}
+	delete(s.viewers, viewerID)
+	r.mu.Unlock()
+
+	if previous != nil {
+		_ = previous.pc.Close()
+		r.viewersChanged(token)
+	}
+
+	track, err := webrtc.NewTrackLocalStaticRTP(codec, trackID, streamID)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := r.api.NewPeerConnection(r.config)
+	if err != nil {
+		return nil, err
+	}
+	transceiver, err := pc.AddTransceiverFromTrack(track, webrtc.RTPTransceiverInit{
+		Direction: webrtc.RTPTransceiverDirectionSendonly,
+	})
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateConnected:
+			r.viewerConnected(token, viewerID, pc)
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateClosed:
+			r.dropViewer(token, viewerID, pc)
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+	local, err := setLocalDescription(pc, offer)
+	if err != nil {
+		_ = pc.Close()
+		return nil, err
+	}
+
+	r.mu.Lock()
+	// The stream may have ended or changed codec while candidates were gathered
+	s = r.streams[token]
+	if s == nil || s.codec.MimeType != codec.MimeType {
+		r.mu.Unlock()
+		_ = pc.Close()
+		return nil, errNotPublished
+	}
+	v := newViewer(pc, track, selected, s.layerIDs())
+	s.viewers[viewerID] = v
+	r.mu.Unlock()
+
+	go r.readFeedback(token, v, transceiver.Sender())
+
+	time.AfterFunc(answerTimeout, func() {
+		if pc.RemoteDescription() == nil {
+			r.dropViewer(token, viewerID, pc)
+			_ = pc.Close()
+		}
+	})
+	return &entities.WebRTCOffer{Type: local.Type.String(), SDP: local.SDP}, nil
+}
+
+// Answer completes a viewer's connection with its answer to Offer
+func (r *Relay) Answer(token, viewerID string, answer *entities.WebRTCAnswer) error {
+	r.mu.Lock()
+	var pc *webrtc.PeerConnection
+	if s := r.streams[token]; s != nil && s.viewers[viewerID] != nil {
+		pc = s.viewers[viewerID].pc
+	}
+	r.mu.Unlock()
+
+	if pc == nil {
+		return errUnknownViewer
+	}
+	return pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP})
+}
+
+// SelectLayer sets the simulcast layer a viewer of a session receives; the
+// relay switches at the layer's next keyframe, which it asks the sender for
+func (r *Relay) SelectLayer(token, viewerID string, layer entities.SimulcastLayer) error {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || s.viewers[viewerID] == nil {
+		r.mu.Unlock()
+		return errUnknownViewer
+	}
+	v := s.viewers[viewerID]
+	changed := v.selectLayer(layer, s.layerIDs())
+	r.mu.Unlock()
+
+	log.Printf("🎚️  SFU viewer picked the %s layer for token: %s", layer, entities.ShortToken(token))
+	if changed {
+		r.requestKeyframe(token, v.targetLayer())
+	}
+	return nil
+}
+
+// DisconnectViewer closes a viewer's connection to a session
+func (r *Relay) DisconnectViewer(token, viewerID string) error {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || s.viewers[viewerID] == nil {
+		r.mu.Unlock()
+		return errUnknownViewer
+	}
+	pc := s.viewers[viewerID].pc
+	delete(s.viewers, viewerID)
+	r.mu.Unlock()
+
+	_ = pc.Close()
+	log.Printf("🚫 SFU viewer disconnected for token: %s", entities.ShortToken(token))
+	r.viewersChanged(token)
+	return nil
+}
+
+// Close disconnects the sender and viewers of a session
+func (r *Relay) Close(token string) {
+	r.closeStream(token, nil)
+}
+
+// GetTokens returns the tokens of the sessions on the relay
+func (r *Relay) GetTokens() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Sorted(maps.Keys(r.streams))
+}
+
+// OnViewersChanged registers fn to be called with the number of connected
+// viewers of a session whenever it changes
+func (r *Relay) OnViewersChanged(fn func(token string, viewers int)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onViewers = fn
+}
+
+// closeStream removes a session's stream and disconnects everyone on it. With
+// a publisher, the stream is only removed while that is still its publisher.
+func (r *Relay) closeStream(token string, publisher *webrtc.PeerConnection) {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || (publisher != nil && s.publisher != publisher) {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.streams, token)
+	reported := s.reported
+	onViewers := r.onViewers
+	r.mu.Unlock()
+
+	if s.publisher != nil {
+		_ = s.publisher.Close()
+	}
+	if s.restream != nil {
+		s.restream.close()
+	}
+	if s.frames != nil {
+		s.frames.close()
+	}
+	for _, v := range s.viewers {
+		_ = v.pc.Close()
+	}
+	log.Printf("📡 SFU stream closed for token: %s", entities.ShortToken(token))
+
+	if reported > 0 && onViewers != nil {
+		r.reportMu.Lock()
+		defer r.reportMu.Unlock()
+		onViewers(token, 0)
+	}
+}
+
+// forward sends the packets of one layer of a publisher's video to the
+// stream's viewers until the publisher goes away
+func (r *Relay) forward(token string, publisher *webrtc.PeerConnection, remote *webrtc.TrackRemote) {
+	parameters := remote.Codec()
+	codec := parameters.RTPCodecCapability
+	rid := remote.RID()
+
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || s.publisher != publisher {
+		r.mu.Unlock()
+		return
+	}
+	var dropped []*webrtc.PeerConnection
+	var unwatched *frameTap
+	if s.codec.MimeType != codec.MimeType {
+		// Viewers negotiated the old codec and have to connect again
+		for id, v := range s.viewers {
+			dropped = append(dropped, v.pc)
+			delete(s.viewers, id)
+		}
+		s.codec = codec
+		if s.frames != nil && codec.MimeType != webrtc.MimeTypeVP8 {
+			unwatched = s.frames
+			s.frames = nil
+		}
+	}
+	if s.layersOf != publisher {
+		s.layers = make(map[string]webrtc.SSRC)
+		s.layersOf = publisher
+	}
+	s.layers[rid] = remote.SSRC()
+	layers := s.layerIDs()
+	for _, v := range s.viewers {
+		v.layersChanged(layers)
+	}
+	// A restream starts over in another codec, as its sink was opened for
+	// the old one, and after an error
+	var started, stopped *restream
+	if r.restreamTo != nil && (s.restream == nil || s.restream.mimeType != codec.MimeType || s.restream.hasFailed()) {
+		stopped = s.restream
+		s.restream = newRestream(parameters, layers)
+		started = s.restream
+	} else if s.restream != nil {
+		s.restream.viewer.layersChanged(layers)
+	}
+	if s.frames != nil {
+		s.frames.viewer.layersChanged(layers)
+	}
+	r.mu.Unlock()
+
+	if unwatched != nil {
+		unwatched.close()
+	}
+
+	for _, pc := range dropped {
+		_ = pc.Close()
+	}
+	if stopped != nil {
+		stopped.close()
+	}
+	if started != nil {
+		go r.startRestream(token, started, parameters)
+	}
+	if len(dropped) > 0 {
+		r.viewersChanged(token)
+	}
+	if rid != "" {
+		log.Printf("📡 SFU forwarding %s video layer %q for token: %s", codec.MimeType, rid, entities.ShortToken(token))
+	} else {
+		log.Printf("📡 SFU forwarding %s video for token: %s", codec.MimeType, entities.ShortToken(token))
+	}
+
+	var targets []*viewer
+	for {
+		packet, _, err := remote.ReadRTP()
+		if err != nil {
+			return
+		}
+		keyframe := isKeyframe(codec.MimeType, packet.Payload)
+
+		r.mu.Lock()
+		if s := r.streams[token]; s == nil || s.publisher != publisher {
+			r.mu.Unlock()
+			return
+		}
+		targets = targets[:0]
+		for _, v := range s.viewers {
+			targets = append(targets, v)
+		}
+		restream := s.restream
+		frames := s.frames
+		r.mu.Unlock()
+
+		if restream != nil {
+			restream.write(token, rid, packet, keyframe)
+		}
+		if frames != nil && codec.MimeType == webrtc.MimeTypeVP8 {
+			frames.write(rid, packet, keyframe)
+		}
+
+		for _, v := range targets {
+			// A viewer that went away is dropped by its connection state
+			if out := v.next(rid, packet, keyframe); out != nil {
+				_ = v.track.WriteRTP(out)
+			}
+		}
+	}
+}
+
+// readFeedback asks the publisher for a keyframe whenever a viewer lost the
+// picture, and moves a viewer on LayerAuto between layers by the loss its
+// receiver reports show
+func (r *Relay) readFeedback(token string, v *viewer, sender *webrtc.RTPSender) {
+	for {
+		packets, _, err := sender.ReadRTCP()
+		if err != nil {
+			return
+		}
+		for _, packet := range packets {
+			switch p := packet.(type) {
+			case *rtcp.PictureLossIndication, *rtcp.FullIntraRequest:
+				r.requestKeyframe(token, v.targetLayer())
+			case *rtcp.ReceiverReport:
+				for _, report := range p.Reports {
+					r.adapt(token, v, float64(report.FractionLost)/256)
+				}
+			}
+		}
+	}
+}
+
+// adapt feeds a viewer's packet loss to its automatic layer choice
+func (r *Relay) adapt(token string, v *viewer, loss float64) {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil {
+		r.mu.Unlock()
+		return
+	}
+	changed := v.adapt(loss, time.Now(), s.layerIDs())
+	r.mu.Unlock()
+
+	if changed {
+		target := v.targetLayer()
+		log.Printf("📶 SFU viewer moved to layer %q at %.0f%% loss for token: %s", target, loss*100, entities.ShortToken(token))
+		r.requestKeyframe(token, target)
+	}
+}
+
+// requestKeyframe asks the publisher of a stream for a keyframe on a layer
+func (r *Relay) requestKeyframe(token, rid string) {
+	r.mu.Lock()
+	var publisher *webrtc.PeerConnection
+	var ssrc webrtc.SSRC
+	if s := r.streams[token]; s != nil {
+		publisher, ssrc = s.layersOf, s.layers[rid]
+	}
+	r.mu.Unlock()
+
+	if publisher == nil || ssrc == 0 {
+		return
+	}
+	if err := publisher.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: uint32(ssrc)}}); err != nil {
+		log.Printf("❌ SFU keyframe request failed: %v", err)
+	}
+}
+
+// viewerConnected counts a viewer whose connection is up and gets it a
+// keyframe to start from
+func (r *Relay) viewerConnected(token, viewerID string, pc *webrtc.PeerConnection) {
+	r.mu.Lock()
+	var v *viewer
+	if s := r.streams[token]; s != nil && s.viewers[viewerID] != nil && s.viewers[viewerID].pc == pc {
+		v = s.viewers[viewerID]
+		v.connected = true
+	}
+	r.mu.Unlock()
+
+	r.viewersChanged(token)
+	if v != nil {
+		r.requestKeyframe(token, v.targetLayer())
+	}
+}
+
+// dropViewer forgets a viewer's connection unless it was already replaced
+func (r *Relay) dropViewer(token, viewerID string, pc *webrtc.PeerConnection) {
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil || s.viewers[viewerID] == nil || s.viewers[viewerID].pc != pc {
+		r.mu.Unlock()
+		return
+	}
+	delete(s.viewers, viewerID)
+	r.mu.Unlock()
+
+	r.viewersChanged(token)
+}
+
+// viewersChanged reports the number of connected viewers of a session if it
+// changed since it was last reported
+func (r *Relay) viewersChanged(token string) {
+	r.reportMu.Lock()
+	defer r.reportMu.Unlock()
+
+	r.mu.Lock()
+	s := r.streams[token]
+	if s == nil {
+		r.mu.Unlock()
+		return
+	}
+	connected := 0
+	for _, v := range s.viewers {
+		if v.connected {
+			connected++
+		}
+	}
+	changed := connected != s.reported
+	s.reported = connected
+	onViewers := r.onViewers
+	r.mu.Unlock()
+
+	if changed && onViewers != nil {
+		onViewers(token, connected)
+	}
+}
+
+// setLocalDescription applies an offer or answer and waits for its candidates,
+// since peers of this server do not trickle ICE
+func setLocalDescription(pc *webrtc.PeerConnection, description webrtc.SessionDescription) (*webrtc.SessionDescription, error) {
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(description); err != nil {
+		return nil, err
+	}
+	<-gathered
+	return pc.LocalDescription(), nil
+}
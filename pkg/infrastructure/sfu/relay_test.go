@@ -0,0 +1,376 @@
+package sfu
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/sdp/v3"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// viewerCounts records the counts a relay reports
+type viewerCounts struct {
+	mu     sync.Mutex
+	counts []int
+}
+
+func (v *viewerCounts) record(_ string, viewers int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.counts = append(v.counts, viewers)
+}
+
+func (v *viewerCounts) last() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.counts) == 0 {
+		return -1
+	}
+	return v.counts[len(v.counts)-1]
+}
+
+// waitFor polls cond until it holds or the test's time is up
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// publish connects a sender to the relay and sends VP8 frames until ctx ends
+func publish(t *testing.T, ctx context.Context, relay *Relay, token string) {
+	t.Helper()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("Failed to create sender: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8}, "video", "share-screen")
+	if err != nil {
+		t.Fatalf("Failed to create track: %v", err)
+	}
+	if _, err := pc.AddTrack(track); err != nil {
+		t.Fatalf("Failed to add track: %v", err)
+	}
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("Failed to create offer: %v", err)
+	}
+	local, err := setLocalDescription(pc, offer)
+	if err != nil {
+		t.Fatalf("Failed to set offer: %v", err)
+	}
+	answer, err := relay.Publish(token, &entities.WebRTCOffer{Type: "offer", SDP: local.SDP})
+	if err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP}); err != nil {
+		t.Fatalf("Failed to set answer: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(33 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = track.WriteSample(media.Sample{Data: []byte{0x10, 0x02, 0x00, 0x9d, 0x01, 0x2a}, Duration: 33 * time.Millisecond})
+			}
+		}
+	}()
+}
+
+// watch connects a viewer to the relay and returns a channel that is closed
+// once its first video packet arrives
+func watch(t *testing.T, relay *Relay, token, viewerID string) <-chan struct{} {
+	t.Helper()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("Failed to create viewer: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	received := make(chan struct{})
+	pc.OnTrack(func(remote *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if _, _, err := remote.ReadRTP(); err == nil {
+			close(received)
+		}
+	})
+
+	offer, err := relay.Offer(token, viewerID)
+	if err != nil {
+		t.Fatalf("Failed to get offer: %v", err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		t.Fatalf("Failed to set offer: %v", err)
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		t.Fatalf("Failed to create answer: %v", err)
+	}
+	local, err := setLocalDescription(pc, answer)
+	if err != nil {
+		t.Fatalf("Failed to set answer: %v", err)
+	}
+	if err := relay.Answer(token, viewerID, &entities.WebRTCAnswer{Type: "answer", SDP: local.SDP}); err != nil {
+		t.Fatalf("Failed to answer: %v", err)
+	}
+	return received
+}
+
+// publishSimulcast connects a sender publishing every simulcast layer to the
+// relay and sends VP8 keyframes until ctx ends; the last payload byte of each
+// packet is the index of its layer
+func publishSimulcast(t *testing.T, ctx context.Context, relay *Relay, token string) {
+	t.Helper()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("Failed to create sender: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	var tracks []*webrtc.TrackLocalStaticRTP
+	var sender *webrtc.RTPSender
+	for _, layer := range entities.SimulcastLayers {
+		track, err := webrtc.NewTrackLocalStaticRTP(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8}, "video", "share-screen", webrtc.WithRTPStreamID(string(layer)))
+		if err != nil {
+			t.Fatalf("Failed to create track: %v", err)
+		}
+		if sender == nil {
+			sender, err = pc.AddTrack(track)
+		} else {
+			err = sender.AddEncoding(track)
+		}
+		if err != nil {
+			t.Fatalf("Failed to add the %s layer: %v", layer, err)
+		}
+		tracks = append(tracks, track)
+	}
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("Failed to create offer: %v", err)
+	}
+	local, err := setLocalDescription(pc, offer)
+	if err != nil {
+		t.Fatalf("Failed to set offer: %v", err)
+	}
+	answer, err := relay.Publish(token, &entities.WebRTCOffer{Type: "offer", SDP: local.SDP})
+	if err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP}); err != nil {
+		t.Fatalf("Failed to set answer: %v", err)
+	}
+
+	// The relay tells the layers apart by the MID and RID header extensions
+	var midID, ridID uint8
+	for _, extension := range sender.GetParameters().HeaderExtensions {
+		switch extension.URI {
+		case sdp.SDESMidURI:
+			midID = uint8(extension.ID)
+		case sdp.SDESRTPStreamIDURI:
+			ridID = uint8(extension.ID)
+		}
+	}
+	mid := pc.GetTransceivers()[0].Mid()
+
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for seq := uint16(0); ; seq++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			for i, track := range tracks {
+				packet := &rtp.Packet{
+					Header:  rtp.Header{Version: 2, SequenceNumber: seq, Timestamp: uint32(seq) * 1800, PayloadType: 96, Marker: true},
+					Payload: []byte{0x10, 0x00, byte(i)},
+				}
+				_ = packet.Header.SetExtension(midID, []byte(mid))
+				_ = packet.Header.SetExtension(ridID, []byte(track.RID()))
+				_ = track.WriteRTP(packet)
+			}
+		}
+	}()
+}
+
+// watchLayer connects a viewer to the relay and returns a function reporting
+// the layer index of the last packet it received, or -1 before the first
+func watchLayer(t *testing.T, relay *Relay, token, viewerID string) func() int {
+	t.Helper()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("Failed to create viewer: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	var layer atomic.Int32
+	layer.Store(-1)
+	pc.OnTrack(func(remote *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		for {
+			packet, _, err := remote.ReadRTP()
+			if err != nil {
+				return
+			}
+			if len(packet.Payload) > 0 {
+				layer.Store(int32(packet.Payload[len(packet.Payload)-1]))
+			}
+		}
+	})
+
+	offer, err := relay.Offer(token, viewerID)
+	if err != nil {
+		t.Fatalf("Failed to get offer: %v", err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		t.Fatalf("Failed to set offer: %v", err)
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		t.Fatalf("Failed to create answer: %v", err)
+	}
+	local, err := setLocalDescription(pc, answer)
+	if err != nil {
+		t.Fatalf("Failed to set answer: %v", err)
+	}
+	if err := relay.Answer(token, viewerID, &entities.WebRTCAnswer{Type: "answer", SDP: local.SDP}); err != nil {
+		t.Fatalf("Failed to answer: %v", err)
+	}
+	return func() int { return int(layer.Load()) }
+}
+
+func TestRelay_ForwardsToViewers(t *testing.T) {
+	streamRelay, err := NewRelay(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create relay: %v", err)
+	}
+	relay := streamRelay.(*Relay)
+	counts := &viewerCounts{}
+	relay.OnViewersChanged(counts.record)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publish(t, ctx, relay, "sfu-token")
+	waitFor(t, "the published video", func() bool { return relay.IsPublished("sfu-token") })
+
+	for _, viewerID := range []string{"viewer-1", "viewer-2"} {
+		select {
+		case <-watch(t, relay, "sfu-token", viewerID):
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Timed out waiting for video at %s", viewerID)
+		}
+	}
+	waitFor(t, "two viewers", func() bool { return counts.last() == 2 })
+
+	if tokens := relay.GetTokens(); len(tokens) != 1 || tokens[0] != "sfu-token" {
+		t.Errorf("Expected one stream, got %v", tokens)
+	}
+
+	relay.Close("sfu-token")
+	if counts.last() != 0 {
+		t.Errorf("Expected no viewers after closing, got %d", counts.last())
+	}
+	if relay.IsPublished("sfu-token") || len(relay.GetTokens()) != 0 {
+		t.Error("Expected the stream to be gone after closing")
+	}
+}
+
+func TestRelay_SimulcastLayers(t *testing.T) {
+	streamRelay, err := NewRelay(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create relay: %v", err)
+	}
+	relay := streamRelay.(*Relay)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publishSimulcast(t, ctx, relay, "sfu-token")
+	waitFor(t, "all three layers", func() bool {
+		relay.mu.Lock()
+		defer relay.mu.Unlock()
+		s := relay.streams["sfu-token"]
+		return s != nil && len(s.layers) == len(entities.SimulcastLayers)
+	})
+
+	// Viewers start on LayerAuto, which begins with the full picture
+	first := watchLayer(t, relay, "sfu-token", "viewer-1")
+	second := watchLayer(t, relay, "sfu-token", "viewer-2")
+	waitFor(t, "the high layer at both viewers", func() bool { return first() == 2 && second() == 2 })
+
+	if err := relay.SelectLayer("sfu-token", "viewer-1", entities.LayerLow); err != nil {
+		t.Fatalf("Failed to select a layer: %v", err)
+	}
+	waitFor(t, "the low layer at the first viewer", func() bool { return first() == 0 })
+	if second() != 2 {
+		t.Errorf("Expected the second viewer to stay on the high layer, got %d", second())
+	}
+
+	if err := relay.SelectLayer("sfu-token", "viewer-1", entities.LayerMid); err != nil {
+		t.Fatalf("Failed to select a layer: %v", err)
+	}
+	waitFor(t, "the mid layer at the first viewer", func() bool { return first() == 1 })
+
+	if err := relay.SelectLayer("sfu-token", "viewer-3", entities.LayerLow); err != errUnknownViewer {
+		t.Errorf("Expected errUnknownViewer, got %v", err)
+	}
+}
+
+func TestRelay_UnknownStream(t *testing.T) {
+	relay, err := NewRelay(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("Failed to create relay: %v", err)
+	}
+
+	if _, err := relay.Offer("missing-token", "viewer-1"); err != errNotPublished {
+		t.Errorf("Expected errNotPublished, got %v", err)
+	}
+	if err := relay.Answer("missing-token", "viewer-1", &entities.WebRTCAnswer{Type: "answer", SDP: "sdp"}); err != errUnknownViewer {
+		t.Errorf("Expected errUnknownViewer, got %v", err)
+	}
+	if err := relay.SelectLayer("missing-token", "viewer-1", entities.LayerLow); err != errUnknownViewer {
+		t.Errorf("Expected errUnknownViewer from SelectLayer, got %v", err)
+	}
+	if _, err := relay.Publish("missing-token", &entities.WebRTCOffer{Type: "offer", SDP: "not sdp"}); err == nil {
+		t.Error("Expected an invalid offer to be rejected")
+	}
+	if tokens := relay.GetTokens(); len(tokens) != 0 {
+		t.Errorf("Expected no streams after a rejected offer, got %v", tokens)
+	}
+}
+
+func TestServerICEServers(t *testing.T) {
+	servers := serverICEServers([]entities.ICEServer{
+		{URLs: []string{"stun:stun.example.com:3478"}},
+		{URLs: []string{"turn:turn.example.com:3478"}, Username: "share", Credential: "relay"},
+		{URLs: []string{"turn:minted.example.com:3478"}},
+	})
+
+	if len(servers) != 2 {
+		t.Fatalf("Expected 2 usable servers, got %+v", servers)
+	}
+	if servers[1].Username != "share" {
+		t.Errorf("Expected static TURN credentials to be kept, got %+v", servers[1])
+	}
+}
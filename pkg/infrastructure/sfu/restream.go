@@ -0,0 +1,162 @@
+package sfu
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+const (
+	// restreamWarmup is how long after opening a restream's sink the relay
+	// asks the publisher for a keyframe; packets arriving before the sink
+	// listens are lost
+	restreamWarmup = 2 * time.Second
+
+	// restreamKeyframePeriod is how often the relay asks for a keyframe while
+	// restreaming. Screen shares send keyframes only when asked, and video
+	// passed on as it is needs them to start players and cut HLS segments.
+	restreamKeyframePeriod = 2 * time.Second
+)
+
+// restream republishes the video of a stream to a sink, such as ffmpeg
+// pushing it to an RTMP server. It receives the top layer like a viewer on
+// LayerHigh, so it carries on across layer and publisher switches.
+type restream struct {
+	viewer      *viewer
+	mimeType    string
+	payloadType uint8
+
+	// failed says the sink could not be opened or stopped taking packets;
+	// the next publisher starts the restream again
+	mu     sync.Mutex
+	sink   interfaces.VideoSink
+	closed bool
+	failed bool
+
+	// done is closed when the restream ends or fails
+	done chan struct{}
+	stop sync.Once
+}
+
+// newRestream creates a restream of a stream's video sent in codec
+func newRestream(codec webrtc.RTPCodecParameters, layers []string) *restream {
+	return &restream{
+		viewer:      newViewer(nil, nil, entities.LayerHigh, layers),
+		mimeType:    codec.MimeType,
+		payloadType: uint8(codec.PayloadType),
+		done:        make(chan struct{}),
+	}
+}
+
+// attach hands the restream its opened sink, which is closed right away if
+// the restream already ended
+func (rs *restream) attach(sink interfaces.VideoSink) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.closed {
+		_ = sink.Close()
+		return
+	}
+	rs.sink = sink
+}
+
+// write passes on a packet arriving on the layer rid if the restream gets it
+func (rs *restream) write(token, rid string, packet *rtp.Packet, keyframe bool) {
+	out := rs.viewer.next(rid, packet, keyframe)
+	if out == nil {
+		return
+	}
+	out.PayloadType = rs.payloadType
+	buf, err := out.Marshal()
+	if err != nil {
+		return
+	}
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.sink == nil {
+		return
+	}
+	if err := rs.sink.WriteRTP(buf); err != nil {
+		// Writing on would only fail again
+		log.Printf("❌ SFU restream stopped for token: %s: %v", entities.ShortToken(token), err)
+		_ = rs.sink.Close()
+		rs.sink = nil
+		rs.failed = true
+		rs.stopKeyframes()
+	}
+}
+
+// stopKeyframes ends the keyframe requests of the restream
+func (rs *restream) stopKeyframes() {
+	rs.stop.Do(func() { close(rs.done) })
+}
+
+// hasFailed reports whether the restream stopped on an error
+func (rs *restream) hasFailed() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.failed
+}
+
+// close closes the restream's sink, now or once it is attached
+func (rs *restream) close() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.closed = true
+	rs.stopKeyframes()
+	if rs.sink != nil {
+		if err := rs.sink.Close(); err != nil {
+			log.Printf("❌ Closing SFU restream failed: %v", err)
+		}
+		rs.sink = nil
+	}
+}
+
+// startRestream opens the sink of a session's restream and asks for
+// keyframes, the first once the sink has had time to start listening
+func (r *Relay) startRestream(token string, rs *restream, codec webrtc.RTPCodecParameters) {
+	recorder := r.restreamTo(token)
+	if recorder == nil {
+		return
+	}
+	sink, err := recorder.Open(interfaces.VideoCodec{
+		MimeType:    codec.MimeType,
+		ClockRate:   codec.ClockRate,
+		PayloadType: uint8(codec.PayloadType),
+	})
+	if err != nil {
+		log.Printf("❌ SFU restream failed to start for token: %s: %v", entities.ShortToken(token), err)
+		rs.mu.Lock()
+		rs.failed = true
+		rs.mu.Unlock()
+		rs.stopKeyframes()
+		return
+	}
+	rs.attach(sink)
+	log.Printf("📺 SFU restreaming %s video for token: %s", codec.MimeType, entities.ShortToken(token))
+
+	r.requestKeyframes(token, rs.viewer, restreamWarmup, restreamKeyframePeriod, rs.done)
+}
+
+// requestKeyframes asks the publisher of a stream for a keyframe on the layer
+// v is after first, and then every period until done is closed
+func (r *Relay) requestKeyframes(token string, v *viewer, first, period time.Duration, done <-chan struct{}) {
+	timer := time.NewTimer(first)
+	defer timer.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+		}
+		r.requestKeyframe(token, v.targetLayer())
+		timer.Reset(period)
+	}
+}
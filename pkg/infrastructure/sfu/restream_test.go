@@ -0,0 +1,90 @@
+package sfu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pion/rtp"
+	"github.com/pion/webrtc/v4"
+)
+
+// fakeSink records the packets written to it
+type fakeSink struct {
+	packets  []rtp.Packet
+	writeErr error
+	closed   int
+}
+
+func (s *fakeSink) WriteRTP(raw []byte) error {
+	if s.writeErr != nil {
+		return s.writeErr
+	}
+	var packet rtp.Packet
+	if err := packet.Unmarshal(raw); err != nil {
+		return err
+	}
+	s.packets = append(s.packets, packet)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.closed++
+	return nil
+}
+
+func TestRestream_Write(t *testing.T) {
+	codec := webrtc.RTPCodecParameters{
+		RTPCodecCapability: webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8, ClockRate: 90000},
+		PayloadType:        102,
+	}
+	rs := newRestream(codec, []string{"high", "low"})
+	packet := &rtp.Packet{Header: rtp.Header{Version: 2, SSRC: 1, SequenceNumber: 10, PayloadType: 96}, Payload: []byte{0x10}}
+
+	// Packets before the sink is attached are dropped
+	rs.write("token", "high", packet, true)
+
+	sink := &fakeSink{}
+	rs.attach(sink)
+	rs.write("token", "high", packet, true)
+	rs.write("token", "low", packet, true)
+
+	if len(sink.packets) != 1 {
+		t.Fatalf("Expected only the top layer to be restreamed, got %d packets", len(sink.packets))
+	}
+	if sink.packets[0].PayloadType != 102 {
+		t.Errorf("Expected the negotiated payload type 102, got %d", sink.packets[0].PayloadType)
+	}
+
+	rs.close()
+	if sink.closed != 1 {
+		t.Errorf("Expected the sink to be closed once, got %d", sink.closed)
+	}
+	if rs.hasFailed() {
+		t.Error("Expected a closed restream not to count as failed")
+	}
+
+	late := &fakeSink{}
+	rs.attach(late)
+	if late.closed != 1 {
+		t.Error("Expected a sink attached after close to be closed")
+	}
+}
+
+func TestRestream_WriteFails(t *testing.T) {
+	rs := newRestream(webrtc.RTPCodecParameters{PayloadType: 96}, []string{""})
+	sink := &fakeSink{writeErr: errors.New("broken pipe")}
+	rs.attach(sink)
+
+	rs.write("token", "", &rtp.Packet{Header: rtp.Header{Version: 2}}, true)
+	if !rs.hasFailed() {
+		t.Error("Expected the restream to fail")
+	}
+	if sink.closed != 1 {
+		t.Errorf("Expected the failed sink to be closed, got %d", sink.closed)
+	}
+
+	rs.close()
+	if sink.closed != 1 {
+		t.Errorf("Expected the failed sink not to be closed again, got %d", sink.closed)
+	}
+}
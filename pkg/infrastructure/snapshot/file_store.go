@@ -0,0 +1,124 @@
+// Package snapshot stores server state as snapshots and opens storage
+// backends by location for migration.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/repository"
+)
+
+// FileStore implements the StateStore interface with a JSON snapshot file
+type FileStore struct {
+	path string
+}
+
+// NewFileStore creates a new snapshot file store
+func NewFileStore(path string) interfaces.StateStore {
+	return &FileStore{path: path}
+}
+
+// Open returns the store for a location: a snapshot file path, optionally
+// written as file:path, bolt:path for the sessions of a bbolt database, or a
+// postgres:// URL for the sessions of a PostgreSQL database
+func Open(location string) (interfaces.StateStore, error) {
+	scheme, rest, found := strings.Cut(location, ":")
+	if !found || len(scheme) == 1 {
+		// No scheme, or a Windows drive letter
+		return NewFileStore(location), nil
+	}
+
+	switch strings.ToLower(scheme) {
+	case "file":
+		path := strings.TrimPrefix(rest, "//")
+		if path == "" {
+			return nil, fmt.Errorf("missing path in %q", location)
+		}
+		return NewFileStore(path), nil
+	case "bolt", "bbolt":
+		path := strings.TrimPrefix(rest, "//")
+		if path == "" {
+			return nil, fmt.Errorf("missing path in %q", location)
+		}
+		// Migration only restores sessions, so the token policy is unused
+		repo, err := repository.NewBoltSessionRepository(path, entities.TokenPolicy{})
+		if err != nil {
+			return nil, err
+		}
+		return NewSessionStore(repo, "bolt://"+path), nil
+	case "postgres", "postgresql":
+		dsn, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Postgres URL: %w", err)
+		}
+		repo, err := repository.NewPostgresSessionRepository(location, 0, entities.TokenPolicy{})
+		if err != nil {
+			return nil, err
+		}
+		// Messages show the URL without its password
+		return NewSessionStore(repo, dsn.Redacted()), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", scheme)
+	}
+}
+
+// Load reads and decodes the snapshot file
+func (s *FileStore) Load() (*entities.Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot entities.Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("reading snapshot %s: %w", s.path, err)
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to a temporary file and renames it into place, so
+// a crash never leaves a half-written snapshot
+func (s *FileStore) Save(snapshot *entities.Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Snapshots hold session PINs, so only the owner may read them
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".snapshot-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Location returns the snapshot file path
+func (s *FileStore) Location() string {
+	return s.path
+}
+
+// SessionsOnly reports false: snapshots hold the whole state
+func (s *FileStore) SessionsOnly() bool {
+	return false
+}
+
+// Close does nothing; the file is only open while loading or saving
+func (s *FileStore) Close() error {
+	return nil
+}
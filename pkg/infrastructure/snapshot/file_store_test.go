@@ -0,0 +1,178 @@
+package snapshot
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestFileStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	store := NewFileStore(path)
+
+	if _, err := store.Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected os.ErrNotExist before saving, got %v", err)
+	}
+
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{{
+		Token:  "session-token",
+		Status: entities.SessionStatusActive,
+		Offer:  &entities.WebRTCOffer{Type: "offer", SDP: "v=0"},
+		Queue:  []entities.QueuedViewer{{ID: "viewer-1"}},
+		PIN:    "123456",
+	}}
+	state.History = []*entities.SessionRecord{{Token: "old-token", RecordingURL: "https://example.com/old.webm"}}
+	state.DeviceSettings = []*entities.DeviceSettings{{DeviceID: "device-1", LowPower: true}}
+
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Snapshot missing: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("Expected owner-only permissions, got %o", perm)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	session := loaded.Sessions[0]
+	if session.Token != "session-token" || session.Offer.SDP != "v=0" || session.Queue[0].ID != "viewer-1" || session.PIN != "123456" {
+		t.Errorf("Session not restored: %+v", session)
+	}
+	if loaded.History[0].RecordingURL != "https://example.com/old.webm" {
+		t.Errorf("Recording link not restored: %+v", loaded.History[0])
+	}
+	if !loaded.DeviceSettings[0].LowPower {
+		t.Errorf("Device settings not restored: %+v", loaded.DeviceSettings[0])
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the snapshot file, found %d entries", len(entries))
+	}
+}
+
+func TestFileStore_LoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	if _, err := NewFileStore(path).Load(); err == nil {
+		t.Error("Expected error for an invalid snapshot")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name         string
+		location     string
+		expectedPath string
+		expectError  bool
+	}{
+		{name: "plain path", location: "memory-snapshot.json", expectedPath: "memory-snapshot.json"},
+		{name: "file scheme", location: "file:/var/lib/share-screen/state.json", expectedPath: "/var/lib/share-screen/state.json"},
+		{name: "file URL", location: "file:///var/lib/state.json", expectedPath: "/var/lib/state.json"},
+		{name: "windows path", location: `C:\share-screen\state.json`, expectedPath: `C:\share-screen\state.json`},
+		{name: "empty file path", location: "file:", expectError: true},
+		{name: "empty bolt path", location: "bolt://", expectError: true},
+		{name: "redis", location: "redis://localhost:6379/0", expectError: true},
+		{name: "sqlite", location: "sqlite:///var/lib/state.db", expectError: true},
+		{name: "unknown scheme", location: "mysql://localhost/db", expectError: true},
+		{name: "invalid postgres URL", location: "postgres://share:secret@db:notaport/share", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := Open(tt.location)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if store.Location() != tt.expectedPath {
+				t.Errorf("Expected path %q, got %q", tt.expectedPath, store.Location())
+			}
+		})
+	}
+}
+
+func TestOpen_Bolt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	store, err := Open("bolt://" + path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if store.Location() != "bolt://"+path || !store.SessionsOnly() {
+		t.Errorf("Expected a session store at %s, got %s", path, store.Location())
+	}
+
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{{Token: "session-token", Status: entities.SessionStatusActive}}
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The database file keeps the sessions for the server to open
+	reopened, err := Open("bolt:" + path)
+	if err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	defer reopened.Close()
+	loaded, err := reopened.Load()
+	if err != nil || len(loaded.Sessions) != 1 || loaded.Sessions[0].Token != "session-token" {
+		t.Errorf("Expected the saved session, got %+v (%v)", loaded, err)
+	}
+}
+
+func TestOpen_Postgres(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	store, err := Open(dsn)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+	if !store.SessionsOnly() || !strings.HasPrefix(store.Location(), "postgres") {
+		t.Errorf("Expected a Postgres session store, got %s", store.Location())
+	}
+	if u, err := url.Parse(dsn); err == nil {
+		if password, ok := u.User.Password(); ok && strings.Contains(store.Location(), password) {
+			t.Errorf("Expected the password hidden in %s", store.Location())
+		}
+	}
+
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{{Token: "migrated-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Hour)}}
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := store.Load()
+	if err != nil || len(loaded.Sessions) != 1 || loaded.Sessions[0].Token != "migrated-token" {
+		t.Errorf("Expected the saved session, got %+v (%v)", loaded, err)
+	}
+}
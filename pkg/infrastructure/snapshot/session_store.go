@@ -0,0 +1,88 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// SessionStore implements the StateStore interface on a session repository,
+// such as a session database. It keeps live sessions only: session history
+// and device settings stay in memory on a server using such a backend, so
+// they are left to snapshot files.
+type SessionStore struct {
+	repo     interfaces.SessionRepository
+	location string
+}
+
+// NewSessionStore creates a state store on the sessions of repo; location
+// describes the repository in messages
+func NewSessionStore(repo interfaces.SessionRepository, location string) *SessionStore {
+	return &SessionStore{repo: repo, location: location}
+}
+
+// Load lists the stored sessions as a snapshot, or returns an error wrapping
+// os.ErrNotExist when there are none
+func (s *SessionStore) Load() (*entities.Snapshot, error) {
+	sessions, err := s.repo.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("%s holds no sessions: %w", s.location, os.ErrNotExist)
+	}
+
+	state := entities.NewSnapshot()
+	state.Sessions = sessions
+	return state, nil
+}
+
+// Save replaces the stored sessions with those of the snapshot; its history
+// and device settings are not stored
+func (s *SessionStore) Save(snapshot *entities.Snapshot) error {
+	keep := make(map[string]bool, len(snapshot.Sessions))
+	for _, session := range snapshot.Sessions {
+		if err := s.repo.RestoreSession(session); err != nil {
+			return fmt.Errorf("storing session %s: %w", entities.ShortToken(session.Token), err)
+		}
+		keep[session.Token] = true
+	}
+
+	stored, err := s.repo.ListSessions()
+	if err != nil {
+		return err
+	}
+	for _, session := range stored {
+		if keep[session.Token] {
+			continue
+		}
+		if err := s.repo.DeleteSession(session.Token); err != nil {
+			return fmt.Errorf("removing session %s: %w", entities.ShortToken(session.Token), err)
+		}
+	}
+	return nil
+}
+
+// Location describes the repository
+func (s *SessionStore) Location() string {
+	return s.location
+}
+
+// SessionsOnly reports true: the repository keeps nothing but sessions
+func (s *SessionStore) SessionsOnly() bool {
+	return true
+}
+
+// Close closes the repository, if it holds a database
+func (s *SessionStore) Close() error {
+	if closer, ok := s.repo.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Ensure SessionStore implements StateStore
+var _ interfaces.StateStore = (*SessionStore)(nil)
@@ -0,0 +1,54 @@
+package snapshot
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/repository"
+)
+
+func TestSessionStore_SaveLoad(t *testing.T) {
+	repo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	store := NewSessionStore(repo, "memory")
+
+	if _, err := store.Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Expected os.ErrNotExist without sessions, got %v", err)
+	}
+	if !store.SessionsOnly() {
+		t.Error("Expected a session store to keep sessions only")
+	}
+
+	stale := &entities.Session{Token: "stale-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := repo.RestoreSession(stale); err != nil {
+		t.Fatalf("RestoreSession failed: %v", err)
+	}
+
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{{
+		Token:     "session-token",
+		Status:    entities.SessionStatusActive,
+		ExpiresAt: time.Now().Add(time.Hour),
+		PIN:       "123456",
+	}}
+	state.History = []*entities.SessionRecord{{Token: "old-token"}}
+	if err := store.Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded.Sessions) != 1 || loaded.Sessions[0].Token != "session-token" || loaded.Sessions[0].PIN != "123456" {
+		t.Errorf("Expected the saved session only, got %+v", loaded.Sessions)
+	}
+	if len(loaded.History) != 0 || loaded.Version != entities.SnapshotVersion {
+		t.Errorf("Expected a current snapshot without history, got %+v", loaded)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package systemd tells systemd when the server is ready or stopping and
+// takes over the listening sockets systemd opened for it
+package systemd
+
+import (
+	"net"
+	"os"
+	"strconv"
+)
+
+// Notification states understood by systemd (sd_notify(3))
+const (
+	Ready    = "READY=1"
+	Stopping = "STOPPING=1"
+)
+
+// listenFDsStart is the first file descriptor passed by socket activation
+const listenFDsStart = 3
+
+// Notify sends state to the service manager; it reports false without an
+// error when the process was not started by systemd with Type=notify
+func Notify(state string) (bool, error) {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return false, nil
+	}
+	// a leading @ names a socket in the abstract namespace
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Listeners returns the sockets passed by systemd socket activation, or nil
+// when the process was not socket activated. The environment variables are
+// cleared so child processes do not take the sockets too.
+func Listeners() ([]net.Listener, error) {
+	count := activatedFDs(os.Getpid(), os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS"))
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	var listeners []net.Listener
+	for fd := listenFDsStart; fd < listenFDsStart+count; fd++ {
+		file := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		listener, err := net.FileListener(file)
+		// the listener holds its own copy of the descriptor, so closing the
+		// inherited one keeps it from leaking into child processes
+		file.Close()
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners, nil
+}
+
+// activatedFDs returns how many sockets systemd passed to the process pid,
+// or 0 when they were meant for another process
+func activatedFDs(pid int, listenPID, listenFDs string) int {
+	if listenPID != strconv.Itoa(pid) {
+		return 0
+	}
+	count, err := strconv.Atoi(listenFDs)
+	if err != nil || count < 0 {
+		return 0
+	}
+	return count
+}
@@ -0,0 +1,71 @@
+package systemd
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	defer conn.Close()
+	t.Setenv("NOTIFY_SOCKET", path)
+
+	sent, err := Notify(Ready)
+	if err != nil || !sent {
+		t.Fatalf("Expected the notification to be sent, got %v (%v)", sent, err)
+	}
+
+	buf := make([]byte, 64)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Failed to read the notification: %v", err)
+	}
+	if got := string(buf[:n]); got != Ready {
+		t.Errorf("Expected %q, got %q", Ready, got)
+	}
+}
+
+func TestNotify_WithoutSystemd(t *testing.T) {
+	t.Setenv("NOTIFY_SOCKET", "")
+
+	if sent, err := Notify(Ready); sent || err != nil {
+		t.Errorf("Expected nothing sent outside systemd, got %v (%v)", sent, err)
+	}
+}
+
+func TestActivatedFDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		listenPID string
+		listenFDs string
+		expected  int
+	}{
+		{name: "not activated", expected: 0},
+		{name: "activated", listenPID: "42", listenFDs: "2", expected: 2},
+		{name: "meant for another process", listenPID: "41", listenFDs: "2", expected: 0},
+		{name: "invalid count", listenPID: "42", listenFDs: "two", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := activatedFDs(42, tt.listenPID, tt.listenFDs); got != tt.expected {
+				t.Errorf("Expected %d sockets, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestListeners_NotActivated(t *testing.T) {
+	t.Setenv("LISTEN_PID", "")
+	t.Setenv("LISTEN_FDS", "")
+
+	listeners, err := Listeners()
+	if err != nil || listeners != nil {
+		t.Errorf("Expected no listeners, got %v (%v)", listeners, err)
+	}
+}
@@ -0,0 +1,181 @@
+package template
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// assetHashLength is how many hex digits of an asset's SHA-256 its URL
+// carries; plenty to tell versions of one file apart
+const assetHashLength = 12
+
+// ErrAssetNotFound is returned for paths that are not a registered asset
+var ErrAssetNotFound = errors.New("asset not found")
+
+// Asset is a script or stylesheet as served, with the hash of its content
+type Asset struct {
+	// Path is the URL path without the hash, e.g. /static/js/ui.js
+	Path        string
+	ContentType string
+	Body        []byte
+	Hash        string
+	ModTime     time.Time
+}
+
+// URL returns the asset's path with its hash before the extension, e.g.
+// /static/js/ui.3f9a1c2b4d5e.js; a new version of the file gets a new URL,
+// so browsers may cache each one for good
+func (a *Asset) URL() string {
+	ext := path.Ext(a.Path)
+	return strings.TrimSuffix(a.Path, ext) + "." + a.Hash + ext
+}
+
+// ETag returns the asset's entity tag, its hash
+func (a *Asset) ETag() string {
+	return `"` + a.Hash + `"`
+}
+
+// assetSource is the file an asset is read from, or rendered from for script
+// templates, and the asset built from it while the file is unchanged
+type assetSource struct {
+	file     string
+	template bool
+	size     int64
+	asset    *Asset
+}
+
+// AddAsset registers the asset served at urlPath from file; files ending in
+// .tmpl are rendered as templates. Scripts in the templates directory are
+// registered by NewTemplateService.
+func (ts *TemplateService) AddAsset(urlPath, file string) {
+	ts.assetsMu.Lock()
+	defer ts.assetsMu.Unlock()
+	ts.assets[urlPath] = &assetSource{file: file, template: strings.HasSuffix(file, ".tmpl")}
+}
+
+// addScripts registers each *.js.tmpl in templatesDir at /static/js/ that
+// is not registered yet
+func (ts *TemplateService) addScripts(templatesDir string) error {
+	files, err := filepath.Glob(filepath.Join(templatesDir, "*.js.tmpl"))
+	if err != nil {
+		return err
+	}
+	ts.assetsMu.Lock()
+	defer ts.assetsMu.Unlock()
+	for _, file := range files {
+		urlPath := "/static/js/" + strings.TrimSuffix(filepath.Base(file), ".tmpl")
+		// Scripts already known keep the asset read from them
+		if _, ok := ts.assets[urlPath]; !ok {
+			ts.assets[urlPath] = &assetSource{file: file, template: true}
+		}
+	}
+	return nil
+}
+
+// Asset returns the asset at urlPath. Its file is read again once it changed,
+// so edits show up without a restart, like the pages' scripts always did.
+func (ts *TemplateService) Asset(urlPath string) (*Asset, error) {
+	ts.assetsMu.Lock()
+	defer ts.assetsMu.Unlock()
+
+	source, ok := ts.assets[urlPath]
+	if !ok {
+		return nil, ErrAssetNotFound
+	}
+	info, err := os.Stat(source.file)
+	if err != nil {
+		return nil, fmt.Errorf("asset %s: %w", urlPath, err)
+	}
+	if source.asset != nil && source.asset.ModTime.Equal(info.ModTime()) && source.size == info.Size() {
+		return source.asset, nil
+	}
+
+	body, err := source.read()
+	if err != nil {
+		return nil, fmt.Errorf("asset %s: %w", urlPath, err)
+	}
+	sum := sha256.Sum256(body)
+	contentType := mime.TypeByExtension(path.Ext(urlPath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	source.size = info.Size()
+	source.asset = &Asset{
+		Path:        urlPath,
+		ContentType: contentType,
+		Body:        body,
+		Hash:        hex.EncodeToString(sum[:])[:assetHashLength],
+		ModTime:     info.ModTime(),
+	}
+	return source.asset, nil
+}
+
+// read returns the file's content, rendered if it is a template
+func (s *assetSource) read() ([]byte, error) {
+	if !s.template {
+		return os.ReadFile(s.file)
+	}
+	tmpl, err := template.ParseFiles(s.file)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, PageData{}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ResolveAsset returns the asset a request path names, either its plain path
+// or a hashed URL. current reports whether the path carries the asset's
+// current hash; a page rendered before the file changed still gets the new
+// content, just not cached for good.
+func (ts *TemplateService) ResolveAsset(requestPath string) (asset *Asset, current bool, err error) {
+	asset, err = ts.Asset(requestPath)
+	if !errors.Is(err, ErrAssetNotFound) {
+		return asset, false, err
+	}
+
+	ext := path.Ext(requestPath)
+	base, hash, ok := cutLast(strings.TrimSuffix(requestPath, ext), ".")
+	if !ok || len(hash) != assetHashLength {
+		return nil, false, ErrAssetNotFound
+	}
+	asset, err = ts.Asset(base + ext)
+	if err != nil {
+		return nil, false, err
+	}
+	return asset, hash == asset.Hash, nil
+}
+
+// AssetURL returns the hashed URL of the asset at urlPath, or urlPath itself
+// when it is not a registered asset or cannot be read
+func (ts *TemplateService) AssetURL(urlPath string) string {
+	asset, err := ts.Asset(urlPath)
+	if err != nil {
+		if !errors.Is(err, ErrAssetNotFound) {
+			log.Printf("Error reading %v", err)
+		}
+		return urlPath
+	}
+	return asset.URL()
+}
+
+// cutLast slices s around the last instance of sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
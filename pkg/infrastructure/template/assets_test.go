@@ -0,0 +1,92 @@
+package template
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFiles writes files, by name, into dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestTemplateService_Assets(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.html":   `<link href="{{asset "/static/css/style.css"}}"/>{{range .Scripts}}<script src="{{asset .}}"></script>{{end}}`,
+		"page.html":   `{{define "content"}}{{end}}`,
+		"app.js.tmpl": `const version = 1;`,
+		"style.css":   `body { margin: 0; }`,
+	})
+	ts, err := NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+	ts.AddAsset("/static/css/style.css", filepath.Join(dir, "style.css"))
+
+	script, err := ts.Asset("/static/js/app.js")
+	if err != nil {
+		t.Fatalf("Expected the script registered from the templates directory: %v", err)
+	}
+	if string(script.Body) != "const version = 1;" || !strings.HasPrefix(script.ContentType, "text/javascript") || len(script.Hash) != assetHashLength {
+		t.Errorf("Unexpected script %+v", script)
+	}
+	if url := script.URL(); url != "/static/js/app."+script.Hash+".js" {
+		t.Errorf("Expected the hash before the extension, got %s", url)
+	}
+
+	// Pages link to the hashed URLs
+	w := httptest.NewRecorder()
+	if err := ts.RenderPage(w, "page.html", PageData{Scripts: []string{"/static/js/app.js", "/other.js"}}); err != nil {
+		t.Fatalf("Failed to render page: %v", err)
+	}
+	style, _ := ts.Asset("/static/css/style.css")
+	for _, expected := range []string{style.URL(), script.URL(), `src="/other.js"`} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("Expected %s in %q", expected, w.Body.String())
+		}
+	}
+
+	// Plain, current and stale URLs all resolve to the asset
+	tests := []struct {
+		path    string
+		current bool
+		err     error
+	}{
+		{path: "/static/js/app.js"},
+		{path: script.URL(), current: true},
+		{path: "/static/js/app.000000000000.js"},
+		{path: "/static/js/app.0000.js", err: ErrAssetNotFound},
+		{path: "/static/js/other.js", err: ErrAssetNotFound},
+		{path: "/static/img/logo.png", err: ErrAssetNotFound},
+	}
+	for _, tt := range tests {
+		asset, current, err := ts.ResolveAsset(tt.path)
+		if err != tt.err || current != tt.current || (err == nil && asset != script) {
+			t.Errorf("ResolveAsset(%s) = %v, %v, %v", tt.path, asset != nil, current, err)
+		}
+	}
+
+	// An edited file gets a new hash, and so a new URL
+	writeFiles(t, dir, map[string]string{"app.js.tmpl": `const version = 2;`})
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(filepath.Join(dir, "app.js.tmpl"), later, later); err != nil {
+		t.Fatalf("Failed to touch the script: %v", err)
+	}
+	edited, err := ts.Asset("/static/js/app.js")
+	if err != nil || edited.Hash == script.Hash || string(edited.Body) != "const version = 2;" {
+		t.Errorf("Expected the edited script with a new hash, got %+v, %v", edited, err)
+	}
+	if _, current, _ := ts.ResolveAsset(script.URL()); current {
+		t.Error("Expected the old URL to be stale")
+	}
+}
@@ -0,0 +1,76 @@
+package template
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// brandLogoPath is where a logo read from a file is served, followed by the
+// file's extension
+const brandLogoPath = "/static/brand/logo"
+
+// Branding is the look a deployment gives every page: its name in the header
+// and the titles, a logo, the accent color and a line in the footer. Empty
+// fields keep the stock look.
+type Branding struct {
+	Name string
+	// Logo is the URL of the logo; see SetBranding for logos read from a file
+	Logo string
+	// Accent is a CSS color, e.g. #e4002b or teal, for buttons and highlights
+	Accent string
+	Footer string
+}
+
+// SetBranding gives every page the branding; it must be called before pages
+// are served. A logo that is not an http(s) URL or a path under /static/ is
+// a file, served as an asset at /static/brand/logo with the file's extension
+// and picked up again when the file changes.
+func (ts *TemplateService) SetBranding(branding Branding) error {
+	if branding.Accent != "" && !isCSSColor(branding.Accent) {
+		return fmt.Errorf("invalid accent color %q: expected #rgb, #rrggbb or a color name", branding.Accent)
+	}
+	if logo := branding.Logo; logo != "" && !isLogoURL(logo) {
+		if _, err := os.Stat(logo); err != nil {
+			return fmt.Errorf("logo: %w", err)
+		}
+		urlPath := brandLogoPath + strings.ToLower(filepath.Ext(logo))
+		ts.AddAsset(urlPath, logo)
+		branding.Logo = urlPath
+	}
+	ts.branding = branding
+	return nil
+}
+
+// isLogoURL reports whether logo is a URL rather than a file
+func isLogoURL(logo string) bool {
+	return strings.HasPrefix(logo, "https://") || strings.HasPrefix(logo, "http://") || strings.HasPrefix(logo, "/static/")
+}
+
+// isCSSColor reports whether s is a hex color with 3, 4, 6 or 8 digits or a
+// color name; nothing else may reach the page's style element
+func isCSSColor(s string) bool {
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		switch len(hex) {
+		case 3, 4, 6, 8:
+		default:
+			return false
+		}
+		for _, c := range strings.ToLower(hex) {
+			if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+				return false
+			}
+		}
+		return true
+	}
+	if s == "" || len(s) > 32 {
+		return false
+	}
+	for _, c := range strings.ToLower(s) {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package template
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateService_SetBranding(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.html": `<title>{{.Title}}{{with .Brand.Name}} · {{.}}{{end}}</title>` +
+			`{{with .Brand.Accent}}<style>:root { --accent: {{.}}; }</style>{{end}}` +
+			`{{with .Brand.Logo}}<img src="{{asset .}}"/>{{end}}{{or .Brand.Name "Share Screen"}}` +
+			`{{template "content" .}}{{with .Brand.Footer}}<footer>{{.}}</footer>{{end}}`,
+		"viewer.html": `{{define "content"}}<main></main>{{end}}`,
+		"logo.svg":    `<svg xmlns="http://www.w3.org/2000/svg"/>`,
+	})
+	render := func(ts *TemplateService) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := ts.RenderPage(w, "viewer.html", PageData{Title: "Viewer"}); err != nil {
+			t.Fatalf("Failed to render: %v", err)
+		}
+		return w.Body.String()
+	}
+
+	ts, err := NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+	if body := render(ts); body != "<title>Viewer</title>Share Screen<main></main>" {
+		t.Errorf("Expected the stock look without branding, got %q", body)
+	}
+
+	branding := Branding{
+		Name:   "Acme <Screens>",
+		Logo:   filepath.Join(dir, "logo.svg"),
+		Accent: "#E4002B",
+		Footer: "Internal use only",
+	}
+	if err := ts.SetBranding(branding); err != nil {
+		t.Fatalf("Failed to set branding: %v", err)
+	}
+	logo, err := ts.Asset("/static/brand/logo.svg")
+	if err != nil {
+		t.Fatalf("Expected the logo file served as an asset: %v", err)
+	}
+	if logo.ContentType != "image/svg+xml" {
+		t.Errorf("Expected the logo as SVG, got %q", logo.ContentType)
+	}
+
+	body := render(ts)
+	for _, expected := range []string{
+		"<title>Viewer · Acme &lt;Screens&gt;</title>",
+		"--accent: #E4002B;",
+		`<img src="` + logo.URL() + `"/>`,
+		"<footer>Internal use only</footer>",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %q in %q", expected, body)
+		}
+	}
+
+	if err := ts.SetBranding(Branding{Logo: "https://intranet.example/logo.png"}); err != nil {
+		t.Fatalf("Failed to set a logo URL: %v", err)
+	}
+	if body := render(ts); !strings.Contains(body, `<img src="https://intranet.example/logo.png"/>`) {
+		t.Errorf("Expected the logo URL as given, got %q", body)
+	}
+
+	for _, branding := range []Branding{
+		{Accent: "red; background: url(x)"},
+		{Accent: "#12345"},
+		{Accent: "#ggg"},
+		{Logo: filepath.Join(dir, "missing.png")},
+	} {
+		if err := ts.SetBranding(branding); err == nil {
+			t.Errorf("Expected an error for %+v", branding)
+		}
+	}
+}
+
+func TestIsCSSColor(t *testing.T) {
+	tests := map[string]bool{
+		"#fff":             true,
+		"#e4002b":          true,
+		"#E4002B80":        true,
+		"#abcd":            true,
+		"teal":             true,
+		"RebeccaPurple":    true,
+		"":                 false,
+		"#":                false,
+		"#12345":           false,
+		"#xyz":             false,
+		"rgb(1, 2, 3)":     false,
+		"red;color:blue":   false,
+		"</style><script>": false,
+	}
+	for color, expected := range tests {
+		if got := isCSSColor(color); got != expected {
+			t.Errorf("isCSSColor(%q) = %v, expected %v", color, got, expected)
+		}
+	}
+}
@@ -1,62 +1,220 @@
 package template
 
 import (
+	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 )
 
 // PageData represents data passed to templates
 type PageData struct {
-	Title      string
-	ExtraHead  template.HTML
-	Scripts    []string
-	STUNServer string
+	Title     string
+	ExtraHead template.HTML
+	Scripts   []string
+	Preview   *LinkPreview
+	Handout   *Handout
+
+	// Contrast is the stored contrast preference ("high", "normal", or empty
+	// to follow the system setting)
+	Contrast string
+
+	// LowPower turns off decorative animations for viewers on devices that
+	// overheat during long sessions
+	LowPower bool
+
+	// Path is the current request URI, used to return after changing preferences
+	Path string
+
+	// Brand is the deployment's branding, filled in by RenderPage
+	Brand Branding
+}
+
+// HighContrast reports whether the high contrast theme was chosen explicitly
+func (d PageData) HighContrast() bool {
+	return d.Contrast == "high"
+}
+
+// RootClass returns the classes for the page's root element
+func (d PageData) RootClass() string {
+	var classes []string
+	if d.Contrast != "" {
+		classes = append(classes, "contrast-"+d.Contrast)
+	}
+	if d.LowPower {
+		classes = append(classes, "low-power")
+	}
+	return strings.Join(classes, " ")
+}
+
+// LinkPreview holds Open Graph and Twitter card metadata for a page
+type LinkPreview struct {
+	Title       string
+	Description string
+	URL         string
+	// Image is the URL of a thumbnail, or empty for a text-only card
+	Image string
+}
+
+// Handout holds the joining details printed for a session
+type Handout struct {
+	Name        string
+	ViewerURL   string
+	QRCode      template.URL
+	ValidFrom   time.Time
+	ValidUntil  time.Time
+	PINRequired bool
 }
 
-// TemplateService handles template rendering
+// TemplateService handles template rendering and the scripts and stylesheets
+// the pages link to
 type TemplateService struct {
-	templates  *template.Template
-	stunServer string
+	templatesDir string
+	reload       bool
+
+	pagesMu sync.Mutex
+	pages   map[string]*template.Template
+	version string
+
+	assetsMu sync.Mutex
+	assets   map[string]*assetSource
+
+	branding Branding
 }
 
-// NewTemplateService creates a new template service
-func NewTemplateService(templatesDir string, stunServer string) (*TemplateService, error) {
-	tmpl, err := template.ParseGlob(templatesDir + "/*.html")
-	if err != nil {
+// NewTemplateService creates a new template service; pages link to assets
+// with {{asset "/static/..."}}, which adds the hash of their content
+func NewTemplateService(templatesDir string) (*TemplateService, error) {
+	ts := &TemplateService{
+		templatesDir: templatesDir,
+		assets:       make(map[string]*assetSource),
+	}
+	if err := ts.loadPages(); err != nil {
 		return nil, err
 	}
+	return ts, nil
+}
 
-	return &TemplateService{
-		templates:  tmpl,
-		stunServer: stunServer,
-	}, nil
+// ReloadOnChange makes the service parse the page templates again when one
+// of them changed since the last page was rendered, so edits show up on the
+// next page load; it is meant for working on the UI and must be called
+// before pages are served
+func (ts *TemplateService) ReloadOnChange() {
+	ts.reload = true
 }
 
-// RenderPage renders a page template with base layout
-func (ts *TemplateService) RenderPage(w http.ResponseWriter, templateName string, data PageData) error {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+// loadPages parses the page templates and registers the scripts next to
+// them; the caller holds pagesMu or is the constructor
+func (ts *TemplateService) loadPages() error {
+	version, err := templatesVersion(ts.templatesDir)
+	if err != nil {
+		return err
+	}
+	if err := ts.addScripts(ts.templatesDir); err != nil {
+		return err
+	}
+	pages, err := parsePages(ts.templatesDir, template.FuncMap{"asset": ts.AssetURL})
+	if err != nil {
+		return err
+	}
+	ts.pages, ts.version = pages, version
+	return nil
+}
+
+// page returns the named page, parsed again first if the templates changed
+// and the service reloads them
+func (ts *TemplateService) page(name string) (*template.Template, error) {
+	ts.pagesMu.Lock()
+	defer ts.pagesMu.Unlock()
 
-	// Set default STUN server if not provided
-	if data.STUNServer == "" {
-		data.STUNServer = ts.stunServer
+	if ts.reload {
+		version, err := templatesVersion(ts.templatesDir)
+		if err != nil {
+			return nil, err
+		}
+		if version != ts.version {
+			if err := ts.loadPages(); err != nil {
+				return nil, err
+			}
+			log.Printf("🔄 Page templates reloaded from %s", ts.templatesDir)
+		}
 	}
 
-	return ts.templates.ExecuteTemplate(w, "base.html", data)
+	page, ok := ts.pages[name]
+	if !ok {
+		return nil, fmt.Errorf("template %q not found", name)
+	}
+	return page, nil
 }
 
-// RenderJS renders JavaScript template with data
-func (ts *TemplateService) RenderJS(w http.ResponseWriter, templateFile string, data PageData) error {
-	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+// templatesVersion sums up the names, sizes and modification times of the
+// templates in dir; it changes whenever a template is edited, added or
+// removed
+func templatesVersion(dir string) (string, error) {
+	var version strings.Builder
+	for _, pattern := range []string{"*.html", "*.js.tmpl"} {
+		files, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return "", err
+		}
+		for _, file := range files {
+			info, err := os.Stat(file)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&version, "%s:%d:%d;", info.Name(), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return version.String(), nil
+}
 
-	// Set default STUN server if not provided
-	if data.STUNServer == "" {
-		data.STUNServer = ts.stunServer
+// parsePages builds one template set per page so each page's "content" block
+// is rendered inside its own copy of the base layout
+func parsePages(templatesDir string, funcs template.FuncMap) (map[string]*template.Template, error) {
+	base, err := template.New("base.html").Funcs(funcs).ParseFiles(filepath.Join(templatesDir, "base.html"))
+	if err != nil {
+		return nil, err
 	}
 
-	tmpl, err := template.ParseFiles(templateFile)
+	files, err := filepath.Glob(filepath.Join(templatesDir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*template.Template)
+	for _, file := range files {
+		name := filepath.Base(file)
+		if name == "base.html" {
+			continue
+		}
+
+		page, err := base.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := page.ParseFiles(file); err != nil {
+			return nil, err
+		}
+		pages[name] = page
+	}
+
+	return pages, nil
+}
+
+// RenderPage renders a page template with base layout
+func (ts *TemplateService) RenderPage(w http.ResponseWriter, templateName string, data PageData) error {
+	page, err := ts.page(templateName)
 	if err != nil {
 		return err
 	}
 
-	return tmpl.Execute(w, data)
+	data.Brand = ts.branding
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	return page.ExecuteTemplate(w, "base.html", data)
 }
@@ -0,0 +1,100 @@
+package template
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateService_RenderPage_UsesPageContent(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.html":  `<title>{{.Title}}</title>{{template "content" .}}`,
+		"alpha.html": `{{define "content"}}alpha page{{end}}`,
+		"omega.html": `{{define "content"}}omega page{{end}}`,
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	ts, err := NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+
+	for _, page := range []string{"alpha", "omega"} {
+		w := httptest.NewRecorder()
+		if err := ts.RenderPage(w, page+".html", PageData{Title: page}); err != nil {
+			t.Fatalf("Failed to render %s: %v", page, err)
+		}
+		if !strings.Contains(w.Body.String(), page+" page") {
+			t.Errorf("Expected %s content but got %q", page, w.Body.String())
+		}
+	}
+
+	if err := ts.RenderPage(httptest.NewRecorder(), "missing.html", PageData{}); err == nil {
+		t.Error("Expected error for unknown template")
+	}
+}
+
+func TestTemplateService_ReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"base.html":   `{{template "content" .}}`,
+		"viewer.html": `{{define "content"}}stock viewer{{end}}`,
+	})
+	stock, err := NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+	dev, err := NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+	dev.ReloadOnChange()
+
+	render := func(ts *TemplateService, page string) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := ts.RenderPage(w, page, PageData{}); err != nil {
+			t.Fatalf("Failed to render %s: %v", page, err)
+		}
+		return w.Body.String()
+	}
+
+	// An edit of the same size within the clock's resolution still changes
+	// the modification time set below
+	writeFiles(t, dir, map[string]string{
+		"viewer.html": `{{define "content"}}brand viewer{{end}}`,
+		"kiosk.html":  `{{define "content"}}kiosk{{end}}`,
+	})
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(dir, "viewer.html"), later, later); err != nil {
+		t.Fatalf("Failed to touch viewer.html: %v", err)
+	}
+
+	if body := render(stock, "viewer.html"); body != "stock viewer" {
+		t.Errorf("Expected the pages parsed at start without reloading, got %q", body)
+	}
+	if body := render(dev, "viewer.html"); body != "brand viewer" {
+		t.Errorf("Expected the edited page, got %q", body)
+	}
+	if body := render(dev, "kiosk.html"); body != "kiosk" {
+		t.Errorf("Expected the added page, got %q", body)
+	}
+
+	// A broken edit fails the render and the fix is picked up again
+	writeFiles(t, dir, map[string]string{"viewer.html": `{{define "content"}}{{.Nope}`})
+	if err := dev.RenderPage(httptest.NewRecorder(), "viewer.html", PageData{}); err == nil {
+		t.Error("Expected an error for a broken template")
+	}
+	writeFiles(t, dir, map[string]string{"viewer.html": `{{define "content"}}fixed viewer{{end}}`})
+	if body := render(dev, "viewer.html"); body != "fixed viewer" {
+		t.Errorf("Expected the fixed page, got %q", body)
+	}
+}
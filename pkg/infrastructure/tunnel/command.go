@@ -0,0 +1,47 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+)
+
+// CommandTunnel runs an external tunnel program, e.g. `cloudflared tunnel
+// --url http://localhost:8080`, and takes the public URL from its output
+type CommandTunnel struct {
+	args      []string
+	publicURL string
+}
+
+// Open starts the program, which is stopped when ctx is cancelled
+func (t *CommandTunnel) Open(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, t.args[0], t.args[1:]...)
+	output, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start the tunnel command: %w", err)
+	}
+
+	found := make(chan string, 1)
+	go scanURL(output, found)
+	go func() {
+		err := cmd.Wait()
+		writer.Close()
+		if ctx.Err() == nil {
+			log.Printf("❌ Tunnel command exited: %v", err)
+		}
+	}()
+
+	if t.publicURL != "" {
+		return t.publicURL, nil
+	}
+	publicURL, err := waitURL(found)
+	if err != nil {
+		cmd.Process.Kill()
+		return "", err
+	}
+	return publicURL, nil
+}
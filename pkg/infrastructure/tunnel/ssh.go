@@ -0,0 +1,175 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHTunnel asks an SSH server to listen on RemoteAddress and forwards the
+// connections it accepts to the local server, like `ssh -R`; services such
+// as localhost.run announce the public URL in the session output
+type SSHTunnel struct {
+	cfg     Config
+	address string
+	config  *ssh.ClientConfig
+}
+
+// newSSHTunnel checks the ssh://user@host[:port] spec and loads the
+// credentials and known hosts
+func newSSHTunnel(cfg Config) (*SSHTunnel, error) {
+	u, err := url.Parse(cfg.Spec)
+	if err != nil || u.Hostname() == "" || u.User == nil {
+		return nil, fmt.Errorf("invalid SSH tunnel %q: expected ssh://user@host[:port]", cfg.Spec)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	knownHostsFile := cfg.KnownHostsFile
+	if knownHostsFile == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	// The tunnel carries every viewer's traffic, so an unknown host key is
+	// refused rather than trusted on first use
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts (add the server with ssh-keyscan): %w", err)
+	}
+
+	auth, err := sshAuth(cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSHTunnel{
+		cfg:     cfg,
+		address: address,
+		config: &ssh.ClientConfig{
+			User:            u.User.Username(),
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         urlTimeout,
+		},
+	}, nil
+}
+
+// sshAuth offers the key file, then the SSH agent's keys, then an empty
+// keyboard-interactive login, which anonymous tunnel services accept
+func sshAuth(keyFile string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if keyFile != "" {
+		key, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the tunnel key: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the tunnel key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if socket := os.Getenv("SSH_AUTH_SOCK"); socket != "" {
+		if conn, err := net.Dial("unix", socket); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	methods = append(methods, ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+		return make([]string, len(questions)), nil
+	}))
+	return methods, nil
+}
+
+// Open connects, requests the remote forward and serves it until ctx is
+// cancelled
+func (t *SSHTunnel) Open(ctx context.Context) (string, error) {
+	dialer := net.Dialer{Timeout: urlTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", t.address)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach the SSH server: %w", err)
+	}
+	clientConn, channels, requests, err := ssh.NewClientConn(conn, t.address, t.config)
+	if err != nil {
+		conn.Close()
+		return "", fmt.Errorf("SSH login failed: %w", err)
+	}
+	client := ssh.NewClient(clientConn, channels, requests)
+
+	listener, err := client.Listen("tcp", t.cfg.RemoteAddress)
+	if err != nil {
+		client.Close()
+		return "", fmt.Errorf("the SSH server refused to listen on %s: %w", t.cfg.RemoteAddress, err)
+	}
+
+	// Services that pick the public host name print it in the session
+	found := make(chan string, 1)
+	if t.cfg.PublicURL == "" {
+		if err := t.readSession(client, found); err != nil {
+			client.Close()
+			return "", err
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		client.Close()
+	}()
+	go func() {
+		for {
+			remote, err := listener.Accept()
+			if err != nil {
+				if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+					log.Printf("❌ SSH tunnel closed: %v", err)
+				}
+				return
+			}
+			go proxy(remote, t.cfg.LocalAddress)
+		}
+	}()
+
+	if t.cfg.PublicURL != "" {
+		return t.cfg.PublicURL, nil
+	}
+	publicURL, err := waitURL(found)
+	if err != nil {
+		client.Close()
+		return "", err
+	}
+	return publicURL, nil
+}
+
+// readSession opens a shell session and scans its output for the public URL
+func (t *SSHTunnel) readSession(client *ssh.Client, found chan<- string) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open an SSH session: %w", err)
+	}
+	output, writer := io.Pipe()
+	session.Stdout = writer
+	session.Stderr = writer
+	if err := session.Shell(); err != nil {
+		session.Close()
+		return fmt.Errorf("failed to start the SSH session: %w", err)
+	}
+	go scanURL(output, found)
+	go func() {
+		session.Wait()
+		writer.Close()
+	}()
+	return nil
+}
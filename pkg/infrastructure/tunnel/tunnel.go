@@ -0,0 +1,116 @@
+// Package tunnel exposes the server to the internet through an outbound
+// connection, either an SSH remote forward or an external tunnel command
+package tunnel
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// urlTimeout bounds how long a tunnel may take to announce its public URL
+const urlTimeout = 30 * time.Second
+
+// ErrNoURL is returned when a tunnel never announced its public URL
+var ErrNoURL = errors.New("the tunnel did not announce a public URL")
+
+// urlPattern matches the public URL tunnel services print, e.g.
+// https://abc123.lhr.life
+var urlPattern = regexp.MustCompile(`https://[A-Za-z0-9.-]+\.[A-Za-z]{2,}(:[0-9]+)?`)
+
+// Config describes the tunnel to open
+type Config struct {
+	// Spec selects the provider: ssh://user@host[:port] for an SSH remote
+	// forward, or command:<program and arguments> for an external tunnel
+	// program such as cloudflared, whose output announces the public URL
+	Spec string
+
+	// LocalAddress is where the tunneled connections are forwarded to
+	LocalAddress string
+
+	// RemoteAddress is the address the SSH server listens on for us,
+	// PublicURL the URL it is reached at (empty to read it from the
+	// server's output), KeyFile an optional private key and KnownHostsFile
+	// the file the server's host key is checked against
+	RemoteAddress  string
+	PublicURL      string
+	KeyFile        string
+	KnownHostsFile string
+}
+
+// New creates the tunnel provider selected by cfg.Spec
+func New(cfg Config) (interfaces.TunnelProvider, error) {
+	switch {
+	case strings.HasPrefix(cfg.Spec, "ssh://"):
+		return newSSHTunnel(cfg)
+	case strings.HasPrefix(cfg.Spec, "command:"):
+		args := strings.Fields(strings.TrimPrefix(cfg.Spec, "command:"))
+		if len(args) == 0 {
+			return nil, errors.New("the tunnel command is empty")
+		}
+		return &CommandTunnel{args: args, publicURL: cfg.PublicURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown tunnel %q: expected ssh://user@host or command:<program>", cfg.Spec)
+	}
+}
+
+// scanURL reads r until it finds a public URL and sends it on found; the
+// rest of the output is drained so the writer never blocks
+func scanURL(r io.Reader, found chan<- string) {
+	scanner := bufio.NewScanner(r)
+	sent := false
+	for scanner.Scan() {
+		if sent {
+			continue
+		}
+		if match := urlPattern.FindString(scanner.Text()); match != "" {
+			found <- match
+			sent = true
+		}
+	}
+	if !sent {
+		close(found)
+	}
+}
+
+// waitURL waits for scanURL to find a public URL
+func waitURL(found <-chan string) (string, error) {
+	select {
+	case publicURL, ok := <-found:
+		if !ok {
+			return "", ErrNoURL
+		}
+		return publicURL, nil
+	case <-time.After(urlTimeout):
+		return "", ErrNoURL
+	}
+}
+
+// proxy copies between a tunneled connection and the local server until
+// either side closes
+func proxy(remote net.Conn, localAddress string) {
+	defer remote.Close()
+	local, err := net.Dial("tcp", localAddress)
+	if err != nil {
+		return
+	}
+	defer local.Close()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	<-done
+}
@@ -0,0 +1,76 @@
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNew_Invalid(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "known_hosts")
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "unknown provider", cfg: Config{Spec: "ngrok"}},
+		{name: "empty command", cfg: Config{Spec: "command: "}},
+		{name: "SSH without user", cfg: Config{Spec: "ssh://localhost.run", KnownHostsFile: missing}},
+		{name: "SSH without known hosts", cfg: Config{Spec: "ssh://nokey@localhost.run", KnownHostsFile: missing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestCommandTunnel_Open(t *testing.T) {
+	provider, err := New(Config{Spec: "command:echo your tunnel is at https://abc123.lhr.life now"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	publicURL, err := provider.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if publicURL != "https://abc123.lhr.life" {
+		t.Errorf("Expected the announced URL, got %q", publicURL)
+	}
+}
+
+func TestCommandTunnel_OpenWithoutURL(t *testing.T) {
+	provider, err := New(Config{Spec: "command:echo no address here"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := provider.Open(context.Background()); !errors.Is(err, ErrNoURL) {
+		t.Errorf("Expected ErrNoURL, got %v", err)
+	}
+}
+
+func TestCommandTunnel_OpenConfiguredURL(t *testing.T) {
+	provider, err := New(Config{Spec: "command:true", PublicURL: "https://share.example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if publicURL, err := provider.Open(context.Background()); err != nil || publicURL != "https://share.example.com" {
+		t.Errorf("Expected the configured URL, got %q (%v)", publicURL, err)
+	}
+}
+
+func TestScanURL(t *testing.T) {
+	found := make(chan string, 1)
+	scanURL(strings.NewReader("Connecting...\n2024 INF |  https://quiet-river.trycloudflare.com  |\nmore output\n"), found)
+
+	if publicURL := <-found; publicURL != "https://quiet-river.trycloudflare.com" {
+		t.Errorf("Expected the tunnel URL, got %q", publicURL)
+	}
+}
@@ -0,0 +1,57 @@
+package turn
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCredentialService_Mint(t *testing.T) {
+	tests := []struct {
+		name             string
+		secret           string
+		ttl              time.Duration
+		user             string
+		expectedUsername string
+		// computed independently: base64(HMAC-SHA1(secret, username))
+		expectedCredential string
+	}{
+		{
+			name:               "coturn REST credential",
+			secret:             "coturn-shared",
+			ttl:                time.Hour,
+			user:               "viewer-1",
+			expectedUsername:   "1700003600:viewer-1",
+			expectedCredential: "6TyZ68i3WOb/jdn/SkUrCr1IZzQ=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCredentialService(tt.secret, tt.ttl).(*CredentialService)
+			service.now = func() time.Time { return time.Unix(1700000000, 500) }
+
+			credential := service.Mint(tt.user)
+
+			if credential.Username != tt.expectedUsername {
+				t.Errorf("Expected username %q, got %q", tt.expectedUsername, credential.Username)
+			}
+			if credential.Credential != tt.expectedCredential {
+				t.Errorf("Expected credential %q, got %q", tt.expectedCredential, credential.Credential)
+			}
+			if !credential.ExpiresAt.Equal(time.Unix(1700003600, 0)) {
+				t.Errorf("Expected expiry at 1700003600, got %v", credential.ExpiresAt.Unix())
+			}
+		})
+	}
+}
+
+func TestCredentialService_MintDiffersBySecret(t *testing.T) {
+	now := func() time.Time { return time.Unix(1700000000, 0) }
+	first := NewCredentialService("first", time.Hour).(*CredentialService)
+	second := NewCredentialService("second", time.Hour).(*CredentialService)
+	first.now, second.now = now, now
+
+	if first.Mint("viewer").Credential == second.Mint("viewer").Credential {
+		t.Error("Expected different secrets to give different credentials")
+	}
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// discordEscaper escapes the characters Discord reads as markdown
+var discordEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`)
+
+// DiscordNotifier posts invites through a Discord channel webhook
+type DiscordNotifier struct {
+	client
+}
+
+// NewDiscordNotifier creates a notifier for the Discord webhook at
+// webhookURL, e.g. https://discord.com/api/webhooks/...
+func NewDiscordNotifier(webhookURL string) (interfaces.InviteNotifier, error) {
+	c, err := newClient("Discord", webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	return &DiscordNotifier{client: c}, nil
+}
+
+// discordMessage is the body of a Discord webhook call
+type discordMessage struct {
+	Content         string                 `json:"content"`
+	AllowedMentions discordAllowedMentions `json:"allowed_mentions"`
+}
+
+// discordAllowedMentions lists who a message may notify
+type discordAllowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// PostInvite posts the invite to the webhook's channel. Mentions are turned
+// off, so a session name cannot notify @everyone.
+func (n *DiscordNotifier) PostInvite(invite *entities.Invite) error {
+	lines := inviteLines(invite, discordTime, discordEscaper.Replace)
+	return n.post(discordMessage{
+		Content:         strings.Join(lines, "\n"),
+		AllowedMentions: discordAllowedMentions{Parse: []string{}},
+	})
+}
+
+// discordTime shows t in each reader's time zone
+func discordTime(t time.Time) string {
+	return fmt.Sprintf("<t:%d:t>", t.Unix())
+}
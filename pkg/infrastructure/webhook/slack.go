@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// slackEscaper escapes the characters Slack reads as markup, so a session
+// name cannot notify the whole channel with <!channel>
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// SlackNotifier posts invites through a Slack incoming webhook
+type SlackNotifier struct {
+	client
+}
+
+// NewSlackNotifier creates a notifier for the Slack incoming webhook at
+// webhookURL, e.g. https://hooks.slack.com/services/...
+func NewSlackNotifier(webhookURL string) (interfaces.InviteNotifier, error) {
+	c, err := newClient("Slack", webhookURL)
+	if err != nil {
+		return nil, err
+	}
+	return &SlackNotifier{client: c}, nil
+}
+
+// PostInvite posts the invite to the webhook's channel
+func (n *SlackNotifier) PostInvite(invite *entities.Invite) error {
+	lines := inviteLines(invite, slackTime, slackEscaper.Replace)
+	return n.post(map[string]string{"text": strings.Join(lines, "\n")})
+}
+
+// slackTime shows t in each reader's time zone, falling back to UTC
+func slackTime(t time.Time) string {
+	return fmt.Sprintf("<!date^%d^{time}|%s>", t.Unix(), t.UTC().Format("15:04 UTC"))
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// postTimeout bounds one webhook call, which the sender page waits for
+const postTimeout = 10 * time.Second
+
+// client posts to the incoming webhook of a chat channel
+type client struct {
+	service    string
+	webhookURL string
+	httpClient *http.Client
+}
+
+// newClient creates a client for the webhook at webhookURL, which has to be
+// an https URL since it carries the channel's credentials
+func newClient(service, webhookURL string) (client, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		// the URL is a credential, keep it out of the logs
+		return client{}, fmt.Errorf("invalid %s webhook URL: expected an https URL", service)
+	}
+	return client{
+		service:    service,
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: postTimeout},
+	}, nil
+}
+
+// Service names the chat service
+func (c client) Service() string {
+	return c.service
+}
+
+// post sends payload to the webhook as JSON
+func (c client) post(payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Post(c.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// the error quotes the URL, which is a credential
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s webhook unreachable: %w", c.service, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		reply, _ := io.ReadAll(io.LimitReader(res.Body, 200))
+		return fmt.Errorf("%s webhook answered %s: %s", c.service, res.Status, strings.TrimSpace(string(reply)))
+	}
+	return nil
+}
+
+// inviteLines are the lines of an invite message; formatTime renders the
+// expiry the way the chat service shows times in each reader's time zone,
+// and escape protects text the sender typed
+func inviteLines(invite *entities.Invite, formatTime func(time.Time) string, escape func(string) string) []string {
+	title := "A screen share is live"
+	if invite.Name != "" {
+		title = "“" + escape(invite.Name) + "” is live"
+	}
+	lines := []string{"🖥️ " + title + ": " + invite.ViewerURL}
+	if invite.ShortURL != "" {
+		lines = append(lines, "Short URL: "+invite.ShortURL)
+	}
+	if invite.PINRequired {
+		lines = append(lines, "🔐 Ask the presenter for the PIN")
+	}
+	if !invite.ExpiresAt.IsZero() {
+		lines = append(lines, "The link works until "+formatTime(invite.ExpiresAt))
+	}
+	return lines
+}
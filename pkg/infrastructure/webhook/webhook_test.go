@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// testInvite is an invite for a session whose name tries to notify everyone
+var testInvite = &entities.Invite{
+	Name:        "<!channel> @everyone *demo*",
+	ViewerURL:   "https://share.example.com/viewer?token=abc",
+	ShortURL:    "https://share.example.com/v/BlueTiger42",
+	PINRequired: true,
+	ExpiresAt:   time.Unix(1700000000, 0),
+}
+
+// recordWebhook serves a webhook answering status, recording the bodies it
+// is posted
+func recordWebhook(t *testing.T, status int) (*httptest.Server, *[]map[string]any) {
+	t.Helper()
+	var bodies []map[string]any
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Invalid webhook body: %v", err)
+		}
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestSlackNotifier_PostInvite(t *testing.T) {
+	server, bodies := recordWebhook(t, 200)
+	notifier, err := NewSlackNotifier(server.URL + "/services/T0/B0/x")
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+	notifier.(*SlackNotifier).httpClient = server.Client()
+
+	if err := notifier.PostInvite(testInvite); err != nil {
+		t.Fatalf("Expected the invite posted, got %v", err)
+	}
+	text, _ := (*bodies)[0]["text"].(string)
+	for _, want := range []string{"&lt;!channel&gt;", testInvite.ViewerURL, testInvite.ShortURL, "PIN", "<!date^1700000000^{time}|22:13 UTC>"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in the message, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "<!channel>") {
+		t.Errorf("Expected the name escaped, got %q", text)
+	}
+}
+
+func TestDiscordNotifier_PostInvite(t *testing.T) {
+	server, bodies := recordWebhook(t, 204)
+	notifier, err := NewDiscordNotifier(server.URL + "/api/webhooks/1/x")
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+	notifier.(*DiscordNotifier).httpClient = server.Client()
+
+	if err := notifier.PostInvite(testInvite); err != nil {
+		t.Fatalf("Expected the invite posted, got %v", err)
+	}
+	body := (*bodies)[0]
+	content, _ := body["content"].(string)
+	for _, want := range []string{`\*demo\*`, testInvite.ViewerURL, "<t:1700000000:t>"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in the message, got %q", want, content)
+		}
+	}
+	mentions, _ := body["allowed_mentions"].(map[string]any)
+	if parse, ok := mentions["parse"].([]any); !ok || len(parse) != 0 {
+		t.Errorf("Expected mentions turned off, got %v", body["allowed_mentions"])
+	}
+}
+
+func TestNotifier_Errors(t *testing.T) {
+	for _, webhookURL := range []string{"", "http://hooks.slack.com/services/x", "hooks.slack.com/services/x"} {
+		if _, err := NewSlackNotifier(webhookURL); err == nil {
+			t.Errorf("Expected %q to be refused", webhookURL)
+		}
+	}
+
+	server, _ := recordWebhook(t, 404)
+	notifier, err := NewSlackNotifier(server.URL + "/services/secret-path")
+	if err != nil {
+		t.Fatalf("Failed to create notifier: %v", err)
+	}
+	notifier.(*SlackNotifier).httpClient = server.Client()
+	if err := notifier.PostInvite(testInvite); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected the webhook's answer in the error, got %v", err)
+	}
+
+	server.Close()
+	if err := notifier.PostInvite(testInvite); err == nil || strings.Contains(err.Error(), "secret-path") {
+		t.Errorf("Expected an error without the webhook URL, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+// Package winsvc runs the server as a Windows service: it installs, removes
+// and starts the service through the service control manager, answers its
+// stop requests and writes the log to the event log
+package winsvc
+
+import (
+	"errors"
+	"strings"
+)
+
+// DefaultName is the service name used when none is given
+const DefaultName = "share-screen"
+
+// ErrUnsupported is returned on systems without Windows services
+var ErrUnsupported = errors.New("windows services are only available on Windows")
+
+// Event log levels of the server's log lines
+const (
+	levelInfo = iota
+	levelWarning
+	levelError
+)
+
+// eventLevel picks the event log level of a log line from the marker the
+// server's warnings and errors start with
+func eventLevel(line string) int {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.HasPrefix(line, "❌") || strings.Contains(lower, "failed"):
+		return levelError
+	case strings.HasPrefix(line, "⚠️") || strings.Contains(lower, "warning"):
+		return levelWarning
+	}
+	return levelInfo
+}
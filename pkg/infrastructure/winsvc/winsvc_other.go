@@ -0,0 +1,40 @@
+//go:build !windows
+
+package winsvc
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// IsService reports whether the process was started by the service control
+// manager
+func IsService() (bool, error) {
+	return false, nil
+}
+
+// Install registers the service name starting exe with args
+func Install(name, exe string, args []string) error {
+	return ErrUnsupported
+}
+
+// Uninstall stops and removes the service name
+func Uninstall(name string) error {
+	return ErrUnsupported
+}
+
+// Start asks the service control manager to start the service name
+func Start(name string) error {
+	return ErrUnsupported
+}
+
+// Run runs serve as the service name until the service is stopped
+func Run(name string, stopTimeout time.Duration, serve func(ctx context.Context)) error {
+	return ErrUnsupported
+}
+
+// OpenEventLog opens the event log source of the service name
+func OpenEventLog(name string) (io.WriteCloser, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,21 @@
+package winsvc
+
+import "testing"
+
+func TestEventLevel(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected int
+	}{
+		{"🚀 Server starting", levelInfo},
+		{"⚠️  Interface eth9 not found", levelWarning},
+		{"❌ Server error: bind: address already in use", levelError},
+		{"Failed to open the tunnel: timeout", levelError},
+		{"Warning: no STUN server configured", levelWarning},
+	}
+	for _, test := range tests {
+		if got := eventLevel(test.line); got != test.expected {
+			t.Errorf("eventLevel(%q) = %d, expected %d", test.line, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package winsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// eventID is the ID of every event the server logs
+const eventID = 1
+
+// restartDelay is how long the service control manager waits before
+// restarting a crashed server
+const restartDelay = 5 * time.Second
+
+// IsService reports whether the process was started by the service control
+// manager
+func IsService() (bool, error) {
+	return svc.IsWindowsService()
+}
+
+// Install registers the service name starting exe with args; it starts with
+// Windows, is restarted when it crashes and logs to the event log
+func Install(name, exe string, args []string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service control manager (run as administrator?): %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists", name)
+	}
+
+	s, err := m.CreateService(name, exe, mgr.Config{
+		DisplayName: "Share Screen",
+		Description: "WebRTC screen sharing signaling server",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: restartDelay}}, uint32((24 * time.Hour).Seconds())); err != nil {
+		s.Delete()
+		return err
+	}
+	if err := eventlog.InstallAsEventCreate(name, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		s.Delete()
+		return fmt.Errorf("registering the event log source: %w", err)
+	}
+	return nil
+}
+
+// Uninstall stops and removes the service name
+func Uninstall(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service control manager (run as administrator?): %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+
+	if status, err := s.Control(svc.Stop); err == nil {
+		deadline := time.Now().Add(30 * time.Second)
+		for status.State != svc.Stopped && time.Now().Before(deadline) {
+			time.Sleep(300 * time.Millisecond)
+			if status, err = s.Query(); err != nil {
+				return err
+			}
+		}
+	} else if !errors.Is(err, windows.ERROR_SERVICE_NOT_ACTIVE) {
+		return fmt.Errorf("stopping service %s: %w", name, err)
+	}
+
+	if err := s.Delete(); err != nil {
+		return err
+	}
+	return eventlog.Remove(name)
+}
+
+// Start asks the service control manager to start the service name
+func Start(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connecting to the service control manager (run as administrator?): %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s is not installed", name)
+	}
+	defer s.Close()
+	return s.Start()
+}
+
+// Run runs serve as the service name until the service is stopped; serve
+// must return once its context is cancelled, within stopTimeout
+func Run(name string, stopTimeout time.Duration, serve func(ctx context.Context)) error {
+	isService, err := IsService()
+	if err != nil {
+		return err
+	}
+	if !isService {
+		return errors.New("the service is started by the service control manager; use `service start`")
+	}
+	return svc.Run(name, &handler{serve: serve, stopTimeout: stopTimeout})
+}
+
+// handler translates service control requests into the server's context
+type handler struct {
+	serve       func(ctx context.Context)
+	stopTimeout time.Duration
+}
+
+// Execute implements svc.Handler
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.serve(ctx)
+	}()
+
+	changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case <-done:
+			return false, 0
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				changes <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				changes <- svc.Status{State: svc.StopPending, WaitHint: uint32(h.stopTimeout.Milliseconds())}
+				cancel()
+				<-done
+				return false, 0
+			}
+		}
+	}
+}
+
+// eventLog writes each log line to the event log at the level of its marker
+type eventLog struct {
+	log *eventlog.Log
+}
+
+// OpenEventLog opens the event log source of the service name
+func OpenEventLog(name string) (io.WriteCloser, error) {
+	log, err := eventlog.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLog{log: log}, nil
+}
+
+// Write implements io.Writer
+func (l *eventLog) Write(p []byte) (int, error) {
+	line := strings.TrimSpace(string(p))
+	var err error
+	switch eventLevel(line) {
+	case levelError:
+		err = l.log.Error(eventID, line)
+	case levelWarning:
+		err = l.log.Warning(eventID, line)
+	default:
+		err = l.log.Info(eventID, line)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close implements io.Closer
+func (l *eventLog) Close() error {
+	return l.log.Close()
+}
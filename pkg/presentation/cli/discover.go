@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/mdns"
+	"share-screen/pkg/infrastructure/qrcode"
+)
+
+// ErrNoServers is returned when no server answered on the LAN
+var ErrNoServers = errors.New("no share-screen servers found on the LAN")
+
+// RunDiscover parses the arguments of the `discover` mode and lists the
+// servers advertised on the LAN with mDNS
+func RunDiscover(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("discover", flag.ContinueOnError)
+	flags.SetOutput(out)
+	timeout := flags.Duration("timeout", 3*time.Second, "How long to wait for servers to answer")
+	showQR := flags.Bool("qr", false, "Print a QR code of each server's URL")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	services, err := mdns.Browse(context.Background(), *timeout)
+	if err != nil {
+		return fmt.Errorf("browsing the LAN: %w", err)
+	}
+	var qrCodes interfaces.QRCodeService
+	if *showQR {
+		qrCodes = qrcode.NewQRCodeService()
+	}
+	return printServers(services, qrCodes, out)
+}
+
+// printServers lists the servers found with their URLs, followed by a QR
+// code of each URL when qrCodes is set
+func printServers(services []mdns.Service, qrCodes interfaces.QRCodeService, out io.Writer) error {
+	if len(services) == 0 {
+		return ErrNoServers
+	}
+
+	for _, service := range services {
+		name := service.Name()
+		if version := service.Text["version"]; version != "" {
+			name += " (" + version + ")"
+		}
+		fmt.Fprintln(out, name)
+		fmt.Fprintf(out, "  %s\n", service.URL())
+		fmt.Fprintf(out, "  Sender: %ssender\n", service.URL())
+
+		if qrCodes != nil {
+			code, err := qrCodes.Text(service.URL())
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(out, code)
+		}
+	}
+	return nil
+}
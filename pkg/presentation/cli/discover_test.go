@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/infrastructure/mdns"
+	"share-screen/test/mocks"
+)
+
+func TestPrintServers(t *testing.T) {
+	services := []mdns.Service{{
+		Instance: "share-screen on office-mac." + mdns.ServiceType,
+		Host:     "office-mac.local.",
+		Port:     8443,
+		IPs:      []net.IP{net.ParseIP("192.168.1.10")},
+		Text:     map[string]string{"scheme": "https", "version": "1.0.0"},
+	}}
+	qrCodes := mocks.NewMockQRCodeService()
+
+	var out bytes.Buffer
+	if err := printServers(services, qrCodes, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"share-screen on office-mac (1.0.0)",
+		"https://192.168.1.10:8443/",
+		"Sender: https://192.168.1.10:8443/sender",
+		"qr:https://192.168.1.10:8443/",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected %q in %q", expected, out.String())
+		}
+	}
+}
+
+func TestPrintServers_None(t *testing.T) {
+	var out bytes.Buffer
+	if err := printServers(nil, nil, &out); !errors.Is(err, ErrNoServers) {
+		t.Errorf("Expected ErrNoServers, got %v", err)
+	}
+}
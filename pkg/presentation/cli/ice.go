@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/client"
+)
+
+// fetchICEServers asks the server which STUN and TURN servers the session's
+// peers should use
+func fetchICEServers(ctx context.Context, apiClient *client.Client, token, pin string) ([]webrtc.ICEServer, error) {
+	config, err := apiClient.GetICEConfig(ctx, token, pin)
+	if err != nil {
+		return nil, fmt.Errorf("fetching ICE servers: %w", err)
+	}
+
+	servers := make([]webrtc.ICEServer, 0, len(config.ICEServers))
+	for _, server := range config.ICEServers {
+		servers = append(servers, webrtc.ICEServer{
+			URLs:       server.URLs,
+			Username:   server.Username,
+			Credential: server.Credential,
+		})
+	}
+	return servers, nil
+}
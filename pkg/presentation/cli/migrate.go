@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/snapshot"
+)
+
+// MigrateConfig holds the settings of a migration
+type MigrateConfig struct {
+	// DryRun validates the source and reports what would be copied without writing
+	DryRun bool
+
+	// Force allows replacing state already stored at the destination
+	Force bool
+}
+
+// RunMigrate parses the arguments of the `migrate` mode and copies the stored
+// state from one backend to another
+func RunMigrate(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.SetOutput(out)
+	from := flags.String("from", "", "Location of the state to copy, e.g. memory-snapshot.json")
+	to := flags.String("to", "", "Location to copy the state to")
+	dryRun := flags.Bool("dry-run", false, "Validate and report without writing anything")
+	force := flags.Bool("force", false, "Replace state already stored at the destination")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return errors.New("both -from and -to are required")
+	}
+
+	source, err := snapshot.Open(*from)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	defer source.Close()
+	destination, err := snapshot.Open(*to)
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+	defer destination.Close()
+	return Migrate(source, destination, MigrateConfig{DryRun: *dryRun, Force: *force}, out)
+}
+
+// Migrate validates the source state, copies it to the destination and reads
+// it back to check that nothing was lost. A destination keeping sessions only
+// gets the sessions; the rest is reported as left behind.
+func Migrate(source, destination interfaces.StateStore, config MigrateConfig, out io.Writer) error {
+	if source.Location() == destination.Location() {
+		return errors.New("source and destination are the same")
+	}
+
+	state, err := source.Load()
+	if err != nil {
+		return fmt.Errorf("loading %s: %w", source.Location(), err)
+	}
+	fmt.Fprintf(out, "Source %s (snapshot version %d, taken %s)\n", source.Location(), state.Version, state.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(out, "  %s\n", describe(state))
+
+	if problems := state.Validate(); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintf(out, "  ❌ %v\n", problem)
+		}
+		return fmt.Errorf("source has %d problems, nothing was copied", len(problems))
+	}
+
+	existing, err := destination.Load()
+	switch {
+	case err == nil && !config.Force:
+		return fmt.Errorf("%s already holds %s; use -force to replace it", destination.Location(), describe(existing))
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("checking %s: %w", destination.Location(), err)
+	}
+
+	if destination.SessionsOnly() && (len(state.History) > 0 || len(state.DeviceSettings) > 0) {
+		fmt.Fprintf(out, "  ⚠️  %s keeps sessions only; %d history records and %d device settings are not copied, keep them in SNAPSHOT_FILE\n",
+			destination.Location(), len(state.History), len(state.DeviceSettings))
+		state.History, state.DeviceSettings = nil, nil
+	}
+
+	if config.DryRun {
+		fmt.Fprintf(out, "Dry run: would copy to %s\n", destination.Location())
+		return nil
+	}
+
+	state.Version = entities.SnapshotVersion
+	if err := destination.Save(state); err != nil {
+		return fmt.Errorf("writing %s: %w", destination.Location(), err)
+	}
+
+	copied, err := destination.Load()
+	if err != nil {
+		return fmt.Errorf("reading back %s: %w", destination.Location(), err)
+	}
+	if err := sameContents(state, copied); err != nil {
+		return fmt.Errorf("verifying %s: %w", destination.Location(), err)
+	}
+	fmt.Fprintf(out, "Copied to %s and verified\n", destination.Location())
+	return nil
+}
+
+// describe summarizes what a snapshot holds
+func describe(state *entities.Snapshot) string {
+	return fmt.Sprintf("%d sessions, %d history records (%d with recordings), %d device settings",
+		len(state.Sessions), len(state.History), state.Recordings(), len(state.DeviceSettings))
+}
+
+// sameContents checks that copied holds every session, record and device of state
+func sameContents(state, copied *entities.Snapshot) error {
+	if describe(state) != describe(copied) {
+		return fmt.Errorf("expected %s, found %s", describe(state), describe(copied))
+	}
+
+	sessions := make(map[string]bool)
+	for _, session := range copied.Sessions {
+		sessions[session.Token] = true
+	}
+	for _, session := range state.Sessions {
+		if !sessions[session.Token] {
+			return fmt.Errorf("session %s missing", session.Token)
+		}
+	}
+
+	records := make(map[string]string)
+	for _, record := range copied.History {
+		records[record.Token] = record.RecordingURL
+	}
+	for _, record := range state.History {
+		if url, ok := records[record.Token]; !ok || url != record.RecordingURL {
+			return fmt.Errorf("history record %s missing or changed", record.Token)
+		}
+	}
+
+	devices := make(map[string]bool)
+	for _, settings := range copied.DeviceSettings {
+		devices[settings.DeviceID] = true
+	}
+	for _, settings := range state.DeviceSettings {
+		if !devices[settings.DeviceID] {
+			return fmt.Errorf("device %s missing", settings.DeviceID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/repository"
+	"share-screen/pkg/infrastructure/snapshot"
+)
+
+// writeSnapshot saves a snapshot with one of everything to path
+func writeSnapshot(t *testing.T, path string, mutate func(*entities.Snapshot)) {
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{{Token: "session-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Hour)}}
+	state.History = []*entities.SessionRecord{{
+		Token:        "record-token",
+		StartedAt:    time.Now().Add(-time.Hour),
+		EndedAt:      time.Now(),
+		RecordingURL: "https://example.com/record.webm",
+	}}
+	state.DeviceSettings = []*entities.DeviceSettings{{DeviceID: "device-1"}}
+	if mutate != nil {
+		mutate(state)
+	}
+	if err := snapshot.NewFileStore(path).Save(state); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name          string
+		mutate        func(*entities.Snapshot)
+		existing      bool
+		config        MigrateConfig
+		expectError   string
+		expectWritten bool
+	}{
+		{
+			name:          "copied and verified",
+			expectWritten: true,
+		},
+		{
+			name:          "dry run",
+			config:        MigrateConfig{DryRun: true},
+			expectWritten: false,
+		},
+		{
+			name:        "invalid source",
+			mutate:      func(s *entities.Snapshot) { s.Sessions = append(s.Sessions, s.Sessions[0]) },
+			expectError: "source has 1 problems",
+		},
+		{
+			name:        "destination holds state",
+			existing:    true,
+			expectError: "use -force",
+		},
+		{
+			name:          "destination replaced with force",
+			existing:      true,
+			config:        MigrateConfig{Force: true},
+			expectWritten: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			from := filepath.Join(dir, "memory-snapshot.json")
+			to := filepath.Join(dir, "migrated.json")
+			writeSnapshot(t, from, tt.mutate)
+			if tt.existing {
+				writeSnapshot(t, to, func(s *entities.Snapshot) { s.Sessions = nil })
+			}
+
+			out := &bytes.Buffer{}
+			err := Migrate(snapshot.NewFileStore(from), snapshot.NewFileStore(to), tt.config, out)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Fatalf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate failed: %v\n%s", err, out.String())
+			}
+			if !strings.Contains(out.String(), "1 sessions, 1 history records (1 with recordings), 1 device settings") {
+				t.Errorf("Expected a summary of the source, got %q", out.String())
+			}
+
+			migrated, err := snapshot.NewFileStore(to).Load()
+			if !tt.expectWritten {
+				if !tt.existing && !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("Expected nothing written, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Destination missing: %v", err)
+			}
+			if len(migrated.Sessions) != 1 || migrated.History[0].RecordingURL != "https://example.com/record.webm" {
+				t.Errorf("Destination does not match the source: %+v", migrated)
+			}
+		})
+	}
+}
+
+func TestMigrate_SessionsOnly(t *testing.T) {
+	from := filepath.Join(t.TempDir(), "memory-snapshot.json")
+	writeSnapshot(t, from, nil)
+	repo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	destination := snapshot.NewSessionStore(repo, "memory")
+
+	out := &bytes.Buffer{}
+	if err := Migrate(snapshot.NewFileStore(from), destination, MigrateConfig{}, out); err != nil {
+		t.Fatalf("Migrate failed: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "1 history records and 1 device settings are not copied") {
+		t.Errorf("Expected the left-behind state reported, got %q", out.String())
+	}
+	if session, err := repo.GetSession("session-token"); err != nil || session.Status != entities.SessionStatusActive {
+		t.Errorf("Expected the session copied, got %+v (%v)", session, err)
+	}
+
+	// Copying back yields the sessions alone
+	to := filepath.Join(t.TempDir(), "migrated.json")
+	if err := Migrate(destination, snapshot.NewFileStore(to), MigrateConfig{}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Migrate back failed: %v", err)
+	}
+	migrated, err := snapshot.NewFileStore(to).Load()
+	if err != nil || len(migrated.Sessions) != 1 || len(migrated.History) != 0 {
+		t.Errorf("Expected the session alone, got %+v (%v)", migrated, err)
+	}
+}
+
+func TestRunMigrate_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown flag", args: []string{"-bogus"}},
+		{name: "missing destination", args: []string{"-from", "state.json"}},
+		{name: "same location", args: []string{"-from", "state.json", "-to", "file:state.json"}},
+		{name: "unknown backend", args: []string{"-from", "state.json", "-to", "redis://localhost:6379"}},
+		{name: "missing source", args: []string{"-from", filepath.Join(t.TempDir(), "none.json"), "-to", "out.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RunMigrate(tt.args, &bytes.Buffer{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
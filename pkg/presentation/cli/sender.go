@@ -0,0 +1,671 @@
+// Package cli holds the command line modes of the share-screen binary that run
+// instead of the HTTP server.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/control"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/capture"
+	"share-screen/pkg/infrastructure/input"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// heartbeatInterval matches the browser sender; sessions whose sender
+	// misses heartbeats for the server's heartbeat timeout go stale
+	heartbeatInterval = 10 * time.Second
+
+	// extendRetryInterval is how soon a failed extension of the session's
+	// expiry is tried again
+	extendRetryInterval = time.Minute
+
+	// endTimeout bounds the request that ends the session on exit
+	endTimeout = 5 * time.Second
+)
+
+// SenderConfig holds the settings of the native sender
+type SenderConfig struct {
+	ServerURL  string
+	Name       string
+	RequirePIN bool
+	FrameRate  int
+
+	// ReusableLink lets the viewer link be used by more than one device
+	ReusableLink bool
+
+	// Room is the named room pointed at the session, and RoomKey the key
+	// printed when the room was first claimed
+	Room    string
+	RoomKey string
+
+	// SFU streams through the server's SFU, so any number of viewers can
+	// watch at once
+	SFU bool
+}
+
+// Sender shares this machine's screen through a share-screen server without a
+// browser, renegotiating with each viewer the way the sender page does
+type Sender struct {
+	client   *client.Client
+	capturer interfaces.ScreenCapturer
+	config   SenderConfig
+	out      io.Writer
+
+	// injector replays the viewer's input when remote control is on, and is
+	// nil otherwise
+	injector interfaces.InputInjector
+
+	token      string
+	senderKey  string
+	room       *dto.ClaimRoomResponse
+	iceServers []webrtc.ICEServer
+	track      *webrtc.TrackLocalStaticSample
+	rates      chan int
+	rate       int
+
+	// requested and suggested cap the capture frame rate at the viewer's
+	// request and the server's suggested constraints; 0 leaves it alone
+	requested int
+	suggested int
+
+	pc             *webrtc.PeerConnection
+	stopAnswerWait context.CancelFunc
+	sentBefore     uint64
+
+	// failed receives SFU connections that broke, to be published again
+	failed chan *webrtc.PeerConnection
+}
+
+// NewSender creates a new native sender
+func NewSender(apiClient *client.Client, capturer interfaces.ScreenCapturer, config SenderConfig, out io.Writer) *Sender {
+	return &Sender{
+		client:   apiClient,
+		capturer: capturer,
+		config:   config,
+		out:      out,
+		rates:    make(chan int, 1),
+		rate:     config.FrameRate,
+		failed:   make(chan *webrtc.PeerConnection, 1),
+	}
+}
+
+// RunSender parses the arguments of the `sender` mode and shares the screen
+// until ctx is done
+func RunSender(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("sender", flag.ContinueOnError)
+	flags.SetOutput(out)
+	serverURL := flags.String("server", "http://localhost:8080", "URL of the share-screen server")
+	name := flags.String("name", "", "Session name shown to viewers")
+	requirePIN := flags.Bool("pin", false, "Require viewers to enter a PIN")
+	reusableLink := flags.Bool("reusable-link", false, "Let the viewer link be used by more than one device")
+	room := flags.String("room", "", "Named room that leads viewers to this session, e.g. design-review")
+	roomKey := flags.String("room-key", "", "Key printed when the room was first claimed, needed to reuse it")
+	frameRate := flags.Int("fps", 15, "Capture frame rate")
+	ffmpeg := flags.String("ffmpeg", "ffmpeg", "Path to the ffmpeg binary used for capture and encoding")
+	screen := flags.String("input", "", "Screen to capture (X11 display such as :0, avfoundation device or gdigrab input)")
+	remoteControl := flags.Bool("control", false, "Let the viewer use this machine's mouse and keyboard (X11 only)")
+	xdotool := flags.String("xdotool", "xdotool", "Path to the xdotool binary used for remote control")
+	sfu := flags.Bool("sfu", false, "Stream through the server so many viewers can watch at once (needs a server started with -sfu)")
+	apiToken := flags.String("token", "", "JWT for servers that require one to create sessions (default $SHARE_SCREEN_TOKEN)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	// The environment keeps the token out of the process list
+	if *apiToken == "" {
+		*apiToken = os.Getenv("SHARE_SCREEN_TOKEN")
+	}
+	if *frameRate <= 0 {
+		return fmt.Errorf("invalid frame rate %d", *frameRate)
+	}
+	if *remoteControl && !input.Supported(runtime.GOOS) {
+		return fmt.Errorf("remote control is not supported on %s", runtime.GOOS)
+	}
+	if *remoteControl && *sfu {
+		return errors.New("remote control needs a peer-to-peer session; drop -sfu to use -control")
+	}
+
+	apiClient := client.NewClient(*serverURL, nil)
+	apiClient.SetBearerToken(*apiToken)
+	sender := NewSender(
+		apiClient,
+		capture.NewFFmpegCapturer(*ffmpeg, *screen),
+		SenderConfig{ServerURL: *serverURL, Name: *name, RequirePIN: *requirePIN, FrameRate: *frameRate, ReusableLink: *reusableLink, Room: *room, RoomKey: *roomKey, SFU: *sfu},
+		out,
+	)
+	if *remoteControl {
+		sender.injector = input.NewXdotoolInjector(*xdotool)
+	}
+	return sender.Run(ctx)
+}
+
+// Run creates a session, publishes offers for viewers and streams the screen
+// until ctx is done, then ends the session
+func (s *Sender) Run(ctx context.Context) error {
+	info, err := s.client.Info(ctx)
+	if err != nil {
+		return fmt.Errorf("contacting server: %w", err)
+	}
+	s.track, err = webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: webrtc.MimeTypeVP8}, "video", "share-screen")
+	if err != nil {
+		return err
+	}
+
+	session, err := s.client.CreateSession(ctx, &dto.CreateSessionRequest{Name: s.config.Name, RequirePIN: s.config.RequirePIN, ReusableLink: s.config.ReusableLink, SFU: s.config.SFU})
+	if err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	s.token = session.Token
+	s.senderKey = session.SenderKey
+	defer s.finish()
+
+	if s.config.SFU && !session.SFU {
+		return errors.New("the server runs without an SFU; start it with -sfu or share without -sfu")
+	}
+
+	// The session's frame rate cap, e.g. the server default, bounds -fps
+	if session.MaxFrameRate > 0 && session.MaxFrameRate < s.config.FrameRate {
+		log.Printf("🎞️  Session capped at %d fps", session.MaxFrameRate)
+		s.config.FrameRate = session.MaxFrameRate
+		s.rate = session.MaxFrameRate
+	}
+
+	if s.config.Room != "" {
+		if s.room, err = s.client.ClaimRoom(ctx, s.config.Room, s.token, s.config.RoomKey); err != nil {
+			return fmt.Errorf("claiming room %s: %w", s.config.Room, err)
+		}
+	}
+
+	if s.iceServers, err = fetchICEServers(ctx, s.client, session.Token, session.PIN); err != nil {
+		return err
+	}
+
+	// Subscribe before the first offer so no viewer event is missed
+	events, err := s.client.Events(ctx, s.token, "")
+	if err != nil {
+		return fmt.Errorf("subscribing to session events: %w", err)
+	}
+
+	captureCtx, stopCapture := context.WithCancel(ctx)
+	captureFinished := make(chan error, 1)
+	var capturing sync.WaitGroup
+	capturing.Add(1)
+	go func() {
+		defer capturing.Done()
+		captureFinished <- s.capture(captureCtx)
+	}()
+	defer func() {
+		stopCapture()
+		capturing.Wait()
+	}()
+
+	if err := s.negotiate(ctx); err != nil {
+		return err
+	}
+	s.printJoinDetails(info, session)
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	// The session expiry is pushed back while sharing, halfway to each new expiry
+	extend := time.NewTimer(0)
+	defer extend.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-captureFinished:
+			return fmt.Errorf("screen capture stopped: %w", err)
+		case <-heartbeat.C:
+			if err := s.client.Heartbeat(ctx, s.token, int64(s.bytesSent())); err != nil {
+				log.Printf("❌ Heartbeat failed: %v", err)
+			}
+		case <-extend.C:
+			response, err := s.client.ExtendSession(ctx, s.token, s.senderKey)
+			if err != nil {
+				log.Printf("❌ Extending the session failed: %v", err)
+				extend.Reset(extendRetryInterval)
+				continue
+			}
+			extend.Reset(max(time.Duration(response.ExpiresInSeconds)*time.Second/2, extendRetryInterval))
+		case pc := <-s.failed:
+			// A connection replaced in the meantime failing needs no new one
+			if pc != s.pc {
+				continue
+			}
+			log.Printf("🔁 Connection to the server failed, publishing again")
+			if err := s.negotiate(ctx); err != nil {
+				return err
+			}
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return errors.New("event stream closed by the server")
+			}
+			if done, err := s.handleEvent(ctx, event); done {
+				return err
+			}
+		}
+	}
+}
+
+// handleEvent reacts to a session event, reporting whether sharing should stop
+func (s *Sender) handleEvent(ctx context.Context, event entities.Event) (bool, error) {
+	switch event.Type {
+	case entities.EventViewerLeft:
+		log.Printf("👋 Viewer left, waiting for the next one")
+		// The next viewer starts from the configured frame rate
+		s.suggested = 0
+		s.updateRate()
+		return false, s.negotiate(ctx)
+	case entities.EventQualityRequest:
+		// One viewer cannot slow down the stream every SFU viewer shares
+		if s.config.SFU {
+			return false, nil
+		}
+		var request dto.QualityRequest
+		if raw, ok := event.Data.(json.RawMessage); ok {
+			if err := json.Unmarshal(raw, &request); err != nil {
+				log.Printf("❌ Invalid quality request: %v", err)
+				return false, nil
+			}
+		}
+		s.applyQuality(request.MaxFrameRate)
+	case entities.EventConstraints:
+		if s.config.SFU {
+			return false, nil
+		}
+		var suggestion entities.ConstraintSuggestion
+		if raw, ok := event.Data.(json.RawMessage); ok {
+			if err := json.Unmarshal(raw, &suggestion); err != nil {
+				log.Printf("❌ Invalid constraint suggestion: %v", err)
+				return false, nil
+			}
+		}
+		s.applyConstraints(suggestion)
+	case entities.EventSFUViewers:
+		var audience struct {
+			Viewers int `json:"viewers"`
+		}
+		if raw, ok := event.Data.(json.RawMessage); ok && json.Unmarshal(raw, &audience) == nil {
+			log.Printf("👥 %d viewers watching", audience.Viewers)
+		}
+	case entities.EventLimitWarning:
+		var warning entities.LimitWarning
+		if raw, ok := event.Data.(json.RawMessage); ok && json.Unmarshal(raw, &warning) == nil {
+			log.Printf("⚠️  %s", warning.Message)
+		}
+	case entities.EventServerShutdown:
+		log.Printf("⚠️  Server is restarting, sharing will stop")
+		return true, nil
+	}
+	return false, nil
+}
+
+// negotiate replaces the peer connection with a fresh one for the next viewer
+// and publishes its offer; in SFU mode the connection goes to the server
+func (s *Sender) negotiate(ctx context.Context) error {
+	s.closePeer()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{ICEServers: s.iceServers})
+	if err != nil {
+		return err
+	}
+	rtpSender, err := pc.AddTrack(s.track)
+	if err != nil {
+		_ = pc.Close()
+		return err
+	}
+	if s.injector != nil {
+		if err := s.openControlChannel(pc); err != nil {
+			_ = pc.Close()
+			return err
+		}
+	}
+	// Reading RTCP lets the interceptors process viewer feedback
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			if _, _, err := rtpSender.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	pc.OnICEConnectionStateChange(func(state webrtc.ICEConnectionState) {
+		log.Printf("🧊 ICE connection state: %s", state)
+	})
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateConnected:
+			if !s.config.SFU {
+				log.Printf("✅ Viewer connected")
+			}
+		case webrtc.PeerConnectionStateFailed:
+			if s.config.SFU {
+				select {
+				case s.failed <- pc:
+				default:
+				}
+				return
+			}
+			// Free the slot for the next queued viewer if this one vanished without saying goodbye
+			if err := s.client.ReleaseViewer(ctx, s.token); err != nil {
+				log.Printf("❌ Releasing viewer slot failed: %v", err)
+			}
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		_ = pc.Close()
+		return err
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		_ = pc.Close()
+		return err
+	}
+	// Wait for candidates so the offer works without trickle ICE
+	select {
+	case <-gathered:
+	case <-ctx.Done():
+		_ = pc.Close()
+		return ctx.Err()
+	}
+
+	local := pc.LocalDescription()
+	if s.config.SFU {
+		return s.publish(ctx, pc, local)
+	}
+	if err := s.client.SubmitOffer(ctx, s.token, s.senderKey, &entities.WebRTCOffer{Type: local.Type.String(), SDP: local.SDP}); err != nil {
+		_ = pc.Close()
+		return fmt.Errorf("publishing offer: %w", err)
+	}
+
+	answerCtx, stopAnswerWait := context.WithCancel(ctx)
+	s.pc = pc
+	s.stopAnswerWait = stopAnswerWait
+	go s.waitForAnswer(answerCtx, pc)
+
+	log.Printf("📤 Offer published, waiting for a viewer")
+	return nil
+}
+
+// publish connects the peer connection to the server's SFU, which forwards
+// the stream to every viewer
+func (s *Sender) publish(ctx context.Context, pc *webrtc.PeerConnection, local *webrtc.SessionDescription) error {
+	answer, err := s.client.PublishStream(ctx, s.token, s.senderKey, &entities.WebRTCOffer{Type: local.Type.String(), SDP: local.SDP})
+	if err != nil {
+		_ = pc.Close()
+		return fmt.Errorf("publishing stream: %w", err)
+	}
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP}); err != nil {
+		_ = pc.Close()
+		return fmt.Errorf("applying the server's answer: %w", err)
+	}
+
+	s.pc = pc
+	log.Printf("📡 Stream published to the server, waiting for viewers")
+	return nil
+}
+
+// openControlChannel offers the viewer the data channel carrying its
+// remote-control input; the channel has to exist before the offer is made
+func (s *Sender) openControlChannel(pc *webrtc.PeerConnection) error {
+	channel, err := pc.CreateDataChannel(control.ChannelLabel, nil)
+	if err != nil {
+		return err
+	}
+	channel.OnOpen(func() {
+		log.Printf("🖱️  Viewer can now control this machine")
+	})
+	channel.OnMessage(func(message webrtc.DataChannelMessage) {
+		s.handleControl(message.Data)
+	})
+	return nil
+}
+
+// handleControl validates one message from the control channel and replays it
+func (s *Sender) handleControl(data []byte) {
+	event, err := control.Parse(data)
+	if err != nil {
+		log.Printf("❌ Ignoring control message: %v", err)
+		return
+	}
+	if err := s.injector.Inject(event); err != nil {
+		log.Printf("❌ Replaying %s failed: %v", event.Type, err)
+	}
+}
+
+// waitForAnswer polls for the viewer's answer until this connection is replaced
+func (s *Sender) waitForAnswer(ctx context.Context, pc *webrtc.PeerConnection) {
+	answer, err := s.client.WaitForAnswer(ctx, s.token)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("❌ Waiting for answer failed: %v", err)
+		}
+		return
+	}
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeAnswer, SDP: answer.SDP}); err != nil {
+		log.Printf("❌ Applying answer failed: %v", err)
+	}
+}
+
+// closePeer closes the current peer connection, carrying its traffic into the session total
+func (s *Sender) closePeer() {
+	if s.pc == nil {
+		return
+	}
+	s.sentBefore += peerBytesSent(s.pc)
+	// SFU connections are answered straight away, with no wait to stop
+	if s.stopAnswerWait != nil {
+		s.stopAnswerWait()
+		s.stopAnswerWait = nil
+	}
+	_ = s.pc.Close()
+	s.pc = nil
+}
+
+// applyQuality caps the capture frame rate at what the viewer asked for (0 = no cap)
+func (s *Sender) applyQuality(maxFrameRate int) {
+	s.requested = maxFrameRate
+	if s.updateRate() {
+		log.Printf("🎚️  Viewer requested max %d fps, capturing at %d fps", maxFrameRate, s.rate)
+	}
+}
+
+// applyConstraints follows the frame rate of the server's suggested
+// constraints; the capturer has no resolution setting, so the rest is left
+func (s *Sender) applyConstraints(suggestion entities.ConstraintSuggestion) {
+	s.suggested = suggestion.MaxFrameRate
+	log.Printf("🎚️  %s", suggestion.Message)
+	if s.updateRate() {
+		log.Printf("🎚️  Capturing at %d fps", s.rate)
+	}
+}
+
+// updateRate restarts the capture at the configured frame rate capped by the
+// viewer's request and the server's suggestion, reporting whether it changed
+func (s *Sender) updateRate() bool {
+	rate := s.config.FrameRate
+	for _, limit := range []int{s.requested, s.suggested} {
+		if limit > 0 {
+			rate = min(rate, limit)
+		}
+	}
+	if rate == s.rate {
+		return false
+	}
+	s.rate = rate
+
+	// Only the latest rate matters
+	select {
+	case <-s.rates:
+	default:
+	}
+	s.rates <- rate
+	return true
+}
+
+// capture streams the screen into the shared track, restarting the capture
+// whenever the frame rate changes
+func (s *Sender) capture(ctx context.Context) error {
+	rate := s.config.FrameRate
+	for {
+		stream, err := s.capturer.Start(ctx, rate)
+		if err != nil {
+			return err
+		}
+
+		written := make(chan error, 1)
+		go func(rate int) { written <- s.writeFrames(stream, rate) }(rate)
+
+		select {
+		case <-ctx.Done():
+			_ = stream.Close()
+			<-written
+			return nil
+		case err := <-written:
+			_ = stream.Close()
+			return err
+		case rate = <-s.rates:
+			_ = stream.Close()
+			<-written
+		}
+	}
+}
+
+// writeFrames copies frames from stream into the track until the stream ends
+func (s *Sender) writeFrames(stream interfaces.VideoStream, rate int) error {
+	duration := time.Second / time.Duration(rate)
+	for {
+		frame, err := stream.NextFrame()
+		if err != nil {
+			return err
+		}
+		if err := s.track.WriteSample(media.Sample{Data: frame, Duration: duration}); err != nil {
+			return err
+		}
+	}
+}
+
+// bytesSent returns the media bytes sent over the whole session
+func (s *Sender) bytesSent() uint64 {
+	total := s.sentBefore
+	if s.pc != nil {
+		total += peerBytesSent(s.pc)
+	}
+	return total
+}
+
+// finish reports the final byte count and ends the session
+func (s *Sender) finish() {
+	ctx, cancel := context.WithTimeout(context.Background(), endTimeout)
+	defer cancel()
+
+	bytesSent := s.bytesSent()
+	s.closePeer()
+	if err := s.client.Heartbeat(ctx, s.token, int64(bytesSent)); err != nil {
+		log.Printf("❌ Final heartbeat failed: %v", err)
+	}
+	if err := s.client.EndSession(ctx, s.token, s.senderKey); err != nil {
+		log.Printf("❌ Ending session failed: %v", err)
+		return
+	}
+	log.Printf("🛑 Session ended")
+}
+
+// printJoinDetails tells the user how viewers can join
+func (s *Sender) printJoinDetails(info *entities.ServerInfo, session *dto.CreateSessionResponse) {
+	// Links built on the server's external URL also work outside the LAN
+	serverURL, lanIP := s.config.ServerURL, info.LANIP
+	if info.ExternalURL != "" {
+		serverURL, lanIP = info.ExternalURL, ""
+	}
+
+	fmt.Fprintf(s.out, "Viewer URL: %s\n", viewerURL(serverURL, lanIP, session.Token))
+	if session.ViewerPath != "" {
+		fmt.Fprintf(s.out, "Short URL: %s\n", lanURL(serverURL, lanIP, session.ViewerPath, nil))
+	}
+	if s.room != nil {
+		fmt.Fprintf(s.out, "Room URL: %s\n", lanURL(serverURL, lanIP, s.room.Path, nil))
+		if s.config.RoomKey == "" {
+			fmt.Fprintf(s.out, "Room key: %s (pass -room-key to reuse the room next time)\n", s.room.Key)
+		}
+	}
+	if session.PIN != "" {
+		fmt.Fprintf(s.out, "PIN: %s\n", session.PIN)
+	}
+	if s.injector != nil {
+		fmt.Fprintln(s.out, "Remote control is on: the viewer can use this machine's mouse and keyboard")
+	}
+	if session.SingleUse {
+		fmt.Fprintln(s.out, "The link works on one device; pass -reusable-link to share it with more")
+	}
+	fmt.Fprintln(s.out, "Press Ctrl+C to stop sharing")
+}
+
+// peerBytesSent sums the media bytes sent on a peer connection
+func peerBytesSent(pc *webrtc.PeerConnection) uint64 {
+	var total uint64
+	for _, stat := range pc.GetStats() {
+		if outbound, ok := stat.(webrtc.OutboundRTPStreamStats); ok {
+			total += outbound.BytesSent
+		}
+	}
+	return total
+}
+
+// viewerURL builds the viewer link of a session
+func viewerURL(serverURL, lanIP, token string) string {
+	return lanURL(serverURL, lanIP, "/viewer", url.Values{"token": {token}})
+}
+
+// lanURL builds a link to a page of the server, swapping a loopback server
+// host for the server's LAN IP so the link works from other devices
+func lanURL(serverURL, lanIP, path string, query url.Values) string {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return serverURL
+	}
+
+	host := u.Hostname()
+	if lanIP != "" && (host == "localhost" || net.ParseIP(host).IsLoopback()) {
+		if port := u.Port(); port != "" {
+			u.Host = net.JoinHostPort(lanIP, port)
+		} else if strings.Contains(lanIP, ":") {
+			// an IPv6 LAN IP needs brackets even without a port
+			u.Host = "[" + lanIP + "]"
+		} else {
+			u.Host = lanIP
+		}
+	}
+
+	u.Path = path
+	u.RawQuery = query.Encode()
+	return u.String()
+}
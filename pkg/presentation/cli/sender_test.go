@@ -0,0 +1,488 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/control"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/pkg/infrastructure/repository"
+	"share-screen/pkg/infrastructure/sfu"
+	httphandlers "share-screen/pkg/presentation/http"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// newTestServer runs the API with real dependencies
+func newTestServer(t *testing.T) string {
+	return startTestServer(t, nil)
+}
+
+// newSFUTestServer runs the API with real dependencies and an SFU
+func newSFUTestServer(t *testing.T) string {
+	relay, err := sfu.NewRelay(nil, 0, nil)
+	if err != nil {
+		t.Fatalf("NewRelay failed: %v", err)
+	}
+	return startTestServer(t, relay)
+}
+
+// startTestServer runs the API, streaming SFU sessions through relay
+func startTestServer(t *testing.T, relay interfaces.StreamRelay) string {
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	broker := events.NewBroker(events.DefaultBufferSize, events.PolicyDropOldest).(*events.Broker)
+
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, broker, relay, 30*time.Minute, usecases.SessionDefaults{})
+	serverInfoUseCase := usecases.NewServerInfoUseCase(mocks.NewMockNetworkService(), nil, "", "1.0.0", "", "", nil)
+	queueUseCase := usecases.NewViewerQueueUseCase(sessionRepo, historyRepo, broker)
+	settingsUseCase := usecases.NewViewerSettingsUseCase(repository.NewMemoryDeviceSettingsRepository(), sessionRepo, historyRepo, broker)
+
+	api := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase, nil)
+	queue := httphandlers.NewQueueHandlers(queueUseCase)
+	history := httphandlers.NewHistoryHandlers(usecases.NewSessionHistoryUseCase(historyRepo))
+	settings := httphandlers.NewSettingsHandlers(settingsUseCase)
+	eventHandlers := httphandlers.NewEventHandlers(broker, queueUseCase)
+	ice := httphandlers.NewICEHandlers(usecases.NewICEConfigUseCase(sessionRepo, historyRepo, nil, nil))
+	rooms := httphandlers.NewRoomHandlers(usecases.NewRoomUseCase(repository.NewMemoryRoomRepository(), sessionRepo, historyRepo))
+
+	mux := http.NewServeMux()
+	router := httphandlers.NewRouter(mux)
+	router.API("/new", api.HandleNewToken)
+	router.API("/offer", api.HandleOffer)
+	router.API("/answer", api.HandleAnswer)
+	router.API("/info", api.HandleInfo)
+	router.API("/sessions/{token}/heartbeat", api.HandleHeartbeat)
+	router.API("/sessions/{token}/end", api.HandleEndSession)
+	router.API("/session/extend", api.HandleExtendSession)
+	router.API("/sessions/{token}/publish", api.HandlePublish)
+	router.API("/sessions/{token}/events", eventHandlers.HandleEvents)
+	router.API("/sessions/{token}/ice-config", ice.HandleICEConfig)
+	router.API("/sessions/{token}/queue", queue.HandleQueue)
+	router.API("/sessions/{token}/queue/{viewer}/leave", queue.HandleLeaveQueue)
+	router.API("/sessions/{token}/leave", queue.HandleReleaseViewer)
+	router.API("/sessions/{token}/summary", history.HandleSummary)
+	router.API("/sessions/{token}/quality", settings.HandleQualityRequest)
+	router.API("/rooms/{name}", rooms.HandleRoom)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		broker.Close()
+		server.Close()
+	})
+	return server.URL
+}
+
+// tokenFromOutput extracts the session token from the printed viewer URL
+func tokenFromOutput(t *testing.T, out *syncBuffer) string {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, rest, found := strings.Cut(out.String(), "/viewer?token="); found {
+			token, _, _ := strings.Cut(rest, "\n")
+			return token
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Sender printed no viewer URL, got %q", out.String())
+	return ""
+}
+
+// watch connects a pion viewer and waits for the first video packet;
+// onChannel, if set, receives the data channels the sender opens
+func watch(t *testing.T, ctx context.Context, c *client.Client, token string, onChannel func(*webrtc.DataChannel)) *webrtc.PeerConnection {
+	offer, err := c.WaitForOffer(ctx, token, "", "")
+	if err != nil {
+		t.Fatalf("WaitForOffer failed: %v", err)
+	}
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("NewPeerConnection failed: %v", err)
+	}
+	if onChannel != nil {
+		pc.OnDataChannel(onChannel)
+	}
+	received := make(chan string, 1)
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if _, _, err := track.ReadRTP(); err == nil {
+			received <- track.Codec().MimeType
+		}
+	})
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		t.Fatalf("SetRemoteDescription failed: %v", err)
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		t.Fatalf("CreateAnswer failed: %v", err)
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		t.Fatalf("SetLocalDescription failed: %v", err)
+	}
+	<-gathered
+
+	local := pc.LocalDescription()
+	if err := c.SubmitAnswer(ctx, token, "", "", &entities.WebRTCAnswer{Type: "answer", SDP: local.SDP}); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+
+	select {
+	case mimeType := <-received:
+		if mimeType != webrtc.MimeTypeVP8 {
+			t.Errorf("Expected VP8 video, got %s", mimeType)
+		}
+	case <-ctx.Done():
+		t.Fatal("Viewer received no video")
+	}
+	return pc
+}
+
+func TestSender_Run(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+	capturer := mocks.NewMockScreenCapturer()
+	out := &syncBuffer{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	senderCtx, stopSender := context.WithCancel(ctx)
+
+	sender := NewSender(c, capturer, SenderConfig{ServerURL: serverURL, Name: "Headless", FrameRate: 30, ReusableLink: true, Room: "headless"}, out)
+	finished := make(chan error, 1)
+	go func() { finished <- sender.Run(senderCtx) }()
+
+	token := tokenFromOutput(t, out)
+	if room, err := c.GetRoom(ctx, "headless"); err != nil || room.Token != token {
+		t.Fatalf("Expected the room to lead to the session, got %+v, %v", room, err)
+	}
+
+	// The first viewer receives video, then leaves
+	viewer := watch(t, ctx, c, token, nil)
+	if err := c.RequestQuality(ctx, token, 10); err != nil {
+		t.Fatalf("RequestQuality failed: %v", err)
+	}
+	_ = viewer.Close()
+	if err := c.ReleaseViewer(ctx, token); err != nil {
+		t.Fatalf("ReleaseViewer failed: %v", err)
+	}
+
+	// The sender renegotiates for the next viewer
+	watch(t, ctx, c, token, nil).Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(capturer.FrameRates(), 10) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rates := capturer.FrameRates(); !slices.Equal(rates, []int{30, 10}) {
+		t.Errorf("Expected capture at 30 then 10 fps, got %v", rates)
+	}
+
+	stopSender()
+	if err := <-finished; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	summary, err := c.GetSummary(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected the session to be ended, got %v", err)
+	}
+	if summary.Name != "Headless" {
+		t.Errorf("Expected session name Headless, got %q", summary.Name)
+	}
+	if !strings.Contains(out.String(), "/r/headless") || !strings.Contains(out.String(), "Room key: ") {
+		t.Errorf("Expected the room URL and key in the output, got %q", out.String())
+	}
+}
+
+func TestSender_RemoteControl(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+	injector := mocks.NewMockInputInjector()
+	out := &syncBuffer{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	senderCtx, stopSender := context.WithCancel(ctx)
+
+	sender := NewSender(c, mocks.NewMockScreenCapturer(), SenderConfig{ServerURL: serverURL, FrameRate: 30}, out)
+	sender.injector = injector
+	finished := make(chan error, 1)
+	go func() { finished <- sender.Run(senderCtx) }()
+
+	token := tokenFromOutput(t, out)
+	channels := make(chan *webrtc.DataChannel, 1)
+	viewer := watch(t, ctx, c, token, func(channel *webrtc.DataChannel) { channels <- channel })
+	defer viewer.Close()
+
+	var channel *webrtc.DataChannel
+	select {
+	case channel = <-channels:
+	case <-ctx.Done():
+		t.Fatal("Sender opened no control channel")
+	}
+	if channel.Label() != control.ChannelLabel {
+		t.Fatalf("Expected the %s channel, got %s", control.ChannelLabel, channel.Label())
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for channel.ReadyState() != webrtc.DataChannelStateOpen && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Invalid input is dropped; ordered delivery means the valid event after it arrives last
+	for _, message := range []string{`{"type":"shell","key":"ls"}`, `{"type":"pointer-down","x":0.5,"y":0.5}`} {
+		if err := channel.SendText(message); err != nil {
+			t.Fatalf("SendText failed: %v", err)
+		}
+	}
+	deadline = time.Now().Add(5 * time.Second)
+	for len(injector.Events()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	expected := []control.Event{{Type: control.EventPointerDown, X: 0.5, Y: 0.5}}
+	if events := injector.Events(); !slices.Equal(events, expected) {
+		t.Errorf("Expected %v injected, got %v", expected, events)
+	}
+
+	stopSender()
+	if err := <-finished; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Remote control is on") {
+		t.Errorf("Expected a remote control notice, got %q", out.String())
+	}
+}
+
+func TestSender_SFU(t *testing.T) {
+	serverURL := newSFUTestServer(t)
+	c := client.NewClient(serverURL, nil)
+	out := &syncBuffer{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	senderCtx, stopSender := context.WithCancel(ctx)
+
+	sender := NewSender(c, mocks.NewMockScreenCapturer(), SenderConfig{ServerURL: serverURL, FrameRate: 30, SFU: true}, out)
+	finished := make(chan error, 1)
+	go func() { finished <- sender.Run(senderCtx) }()
+	token := tokenFromOutput(t, out)
+
+	// Both viewers watch at once; neither waits in the queue
+	var recorders []*mocks.MockVideoRecorder
+	viewers := make(chan error, 2)
+	for range 2 {
+		recorder := mocks.NewMockVideoRecorder()
+		recorders = append(recorders, recorder)
+		go func() {
+			viewers <- NewViewer(c, recorder, ViewerConfig{Token: token}, &syncBuffer{}).Run(ctx)
+		}()
+	}
+	for i, recorder := range recorders {
+		waitFor(t, fmt.Sprintf("video packets at viewer %d", i+1), func() bool { return recorder.Packets() > 0 })
+	}
+	if queue, err := c.GetQueue(ctx, token); err != nil || len(queue.Viewers) != 0 {
+		t.Errorf("Expected nobody in the queue, got %+v, %v", queue, err)
+	}
+
+	stopSender()
+	if err := <-finished; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	for range recorders {
+		if err := <-viewers; err != nil {
+			t.Errorf("Expected viewers to stop cleanly, got %v", err)
+		}
+	}
+
+	summary, err := c.GetSummary(ctx, token)
+	if err != nil {
+		t.Fatalf("Expected the session to be ended, got %v", err)
+	}
+	if summary.PeakViewers != 2 {
+		t.Errorf("Expected a peak of 2 viewers, got %d", summary.PeakViewers)
+	}
+}
+
+func TestSender_SFUUnavailable(t *testing.T) {
+	serverURL := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := NewSender(client.NewClient(serverURL, nil), mocks.NewMockScreenCapturer(), SenderConfig{ServerURL: serverURL, FrameRate: 15, SFU: true}, &syncBuffer{}).Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "without an SFU") {
+		t.Errorf("Expected an SFU error, got %v", err)
+	}
+}
+
+func TestSender_CaptureFailure(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+	capturer := mocks.NewMockScreenCapturer()
+	capturer.ShouldFail = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := NewSender(c, capturer, SenderConfig{ServerURL: serverURL, FrameRate: 15}, &syncBuffer{}).Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "screen capture stopped") {
+		t.Errorf("Expected capture error, got %v", err)
+	}
+}
+
+func TestSender_HandleEventConstraints(t *testing.T) {
+	sender := NewSender(nil, mocks.NewMockScreenCapturer(), SenderConfig{FrameRate: 30}, &syncBuffer{})
+	constraints := func(level int) entities.Event {
+		raw, _ := json.Marshal(entities.ConstraintSuggestion{Constraints: entities.ConstraintsAt(level), Message: "test"})
+		return entities.Event{Type: entities.EventConstraints, Data: json.RawMessage(raw)}
+	}
+	nextRate := func() int {
+		select {
+		case rate := <-sender.rates:
+			return rate
+		default:
+			return 0
+		}
+	}
+
+	if done, err := sender.handleEvent(context.Background(), constraints(1)); done || err != nil {
+		t.Fatalf("Expected sharing to go on, got %v, %v", done, err)
+	}
+	if rate := nextRate(); rate != 15 {
+		t.Errorf("Expected the capture to restart at the suggested 15 fps, got %d", rate)
+	}
+
+	// The viewer's lower request wins, and outlasts the suggestion
+	sender.applyQuality(10)
+	_, _ = sender.handleEvent(context.Background(), constraints(0))
+	if rate := nextRate(); rate != 10 {
+		t.Errorf("Expected the viewer's 10 fps to stay, got %d", rate)
+	}
+
+	sfu := NewSender(nil, mocks.NewMockScreenCapturer(), SenderConfig{FrameRate: 30, SFU: true}, &syncBuffer{})
+	_, _ = sfu.handleEvent(context.Background(), constraints(3))
+	if sfu.rate != 30 {
+		t.Errorf("Expected SFU senders to ignore suggestions, got %d fps", sfu.rate)
+	}
+}
+
+func TestRunSender_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown flag", args: []string{"-bogus"}},
+		{name: "invalid frame rate", args: []string{"-fps", "0"}},
+		{name: "remote control through the SFU", args: []string{"-sfu", "-control"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RunSender(context.Background(), tt.args, &bytes.Buffer{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestViewerURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		serverURL string
+		lanIP     string
+		expected  string
+	}{
+		{
+			name:      "loopback replaced by LAN IP",
+			serverURL: "http://localhost:8080",
+			lanIP:     "192.168.1.10",
+			expected:  "http://192.168.1.10:8080/viewer?token=abc",
+		},
+		{
+			name:      "public host kept",
+			serverURL: "https://share.example.com/",
+			lanIP:     "192.168.1.10",
+			expected:  "https://share.example.com/viewer?token=abc",
+		},
+		{
+			name:      "IPv6 LAN IP bracketed",
+			serverURL: "http://[::1]:8080",
+			lanIP:     "fd00::10",
+			expected:  "http://[fd00::10]:8080/viewer?token=abc",
+		},
+		{
+			name:      "IPv6 LAN IP bracketed without port",
+			serverURL: "http://localhost",
+			lanIP:     "fd00::10",
+			expected:  "http://[fd00::10]/viewer?token=abc",
+		},
+		{
+			name:      "no LAN IP",
+			serverURL: "http://127.0.0.1:8080",
+			expected:  "http://127.0.0.1:8080/viewer?token=abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := viewerURL(tt.serverURL, tt.lanIP, "abc"); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSender_PrintJoinDetailsExternalURL(t *testing.T) {
+	var out bytes.Buffer
+	sender := &Sender{config: SenderConfig{ServerURL: "http://localhost:8080"}, out: &out}
+
+	sender.printJoinDetails(&entities.ServerInfo{LANIP: "192.168.1.10", ExternalURL: "https://share.example.com:8443"}, &dto.CreateSessionResponse{Token: "abc"})
+
+	if !strings.Contains(out.String(), "Viewer URL: https://share.example.com:8443/viewer?token=abc") {
+		t.Errorf("Expected a viewer URL on the external URL, got %q", out.String())
+	}
+}
+
+func TestSender_PrintJoinDetailsShortURL(t *testing.T) {
+	var out bytes.Buffer
+	sender := &Sender{config: SenderConfig{ServerURL: "http://localhost:8080"}, out: &out}
+
+	sender.printJoinDetails(&entities.ServerInfo{LANIP: "192.168.1.10"}, &dto.CreateSessionResponse{Token: "abc", ViewerPath: "/v/BlueTiger42"})
+
+	if !strings.Contains(out.String(), "Short URL: http://192.168.1.10:8080/v/BlueTiger42") {
+		t.Errorf("Expected the short URL on the LAN IP, got %q", out.String())
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the sender and the test to share
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
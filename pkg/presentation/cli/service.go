@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ServiceConfig holds the settings of the systemd units written by the
+// `install-service` mode
+type ServiceConfig struct {
+	// Name of the units, e.g. share-screen for share-screen.service
+	Name string
+
+	// Executable and Args make up the command line of the server; the
+	// templates are loaded relative to WorkingDirectory, which also holds
+	// the .env file
+	Executable       string
+	Args             []string
+	WorkingDirectory string
+
+	// User runs the server, root when empty
+	User string
+
+	// Socket adds a socket unit listening on Port, so systemd opens the port
+	// and starts the server on the first connection
+	Socket bool
+	Port   string
+}
+
+// RunInstallService parses the arguments of the `install-service` mode and
+// writes the systemd units; arguments after -- are passed to the server
+func RunInstallService(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("install-service", flag.ContinueOnError)
+	flags.SetOutput(out)
+	dir := flags.String("dir", "/etc/systemd/system", "Directory to write the units to, or - to print them")
+	name := flags.String("name", "share-screen", "Name of the units")
+	user := flags.String("user", "", "User to run the server as (default root)")
+	workDir := flags.String("workdir", "", "Directory holding web/templates and .env (default the current directory)")
+	socket := flags.Bool("socket", false, "Also write a socket unit so systemd opens the port")
+	port := flags.String("port", "8080", "Port of the socket unit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+	if *workDir, err = serverDir(*workDir); err != nil {
+		return err
+	}
+
+	return InstallService(ServiceConfig{
+		Name:             *name,
+		Executable:       executable,
+		Args:             flags.Args(),
+		WorkingDirectory: *workDir,
+		User:             *user,
+		Socket:           *socket,
+		Port:             *port,
+	}, *dir, out)
+}
+
+// serverDir returns the absolute path of dir, the current directory when
+// empty, after checking it holds the templates the server loads
+func serverDir(dir string) (string, error) {
+	if dir == "" {
+		var err error
+		if dir, err = os.Getwd(); err != nil {
+			return "", err
+		}
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "web", "templates")); err != nil {
+		return "", fmt.Errorf("%s has no web/templates; pass -workdir with the server's files", dir)
+	}
+	return dir, nil
+}
+
+// InstallService writes the units of config to dir, or prints them when dir
+// is -, and tells the user how to start them
+func InstallService(config ServiceConfig, dir string, out io.Writer) error {
+	units := map[string]string{config.Name + ".service": serviceUnit(config)}
+	if config.Socket {
+		units[config.Name+".socket"] = socketUnit(config)
+	}
+
+	files := unitFiles(config)
+	for _, file := range files {
+		if dir == "-" {
+			fmt.Fprintf(out, "# %s\n%s\n", file, units[file])
+			continue
+		}
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, []byte(units[file]), 0o644); err != nil {
+			return fmt.Errorf("writing %s (run as root?): %w", path, err)
+		}
+		fmt.Fprintf(out, "Wrote %s\n", path)
+	}
+
+	if dir != "-" {
+		fmt.Fprintf(out, "Start it with:\n  systemctl daemon-reload\n  systemctl enable --now %s\n", files[len(files)-1])
+	}
+	return nil
+}
+
+// unitFiles returns the unit file names, the one to enable last
+func unitFiles(config ServiceConfig) []string {
+	if config.Socket {
+		return []string{config.Name + ".service", config.Name + ".socket"}
+	}
+	return []string{config.Name + ".service"}
+}
+
+// serviceUnit renders the service unit; Type=notify waits for the server's
+// readiness notification and SIGTERM lets it drain connections
+func serviceUnit(config ServiceConfig) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=share-screen screen sharing server\n")
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("Wants=network-online.target\n")
+	if config.Socket {
+		fmt.Fprintf(&b, "Requires=%s.socket\n", config.Name)
+	}
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=notify\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", execStart(config))
+	fmt.Fprintf(&b, "WorkingDirectory=%s\n", config.WorkingDirectory)
+	if config.User != "" {
+		fmt.Fprintf(&b, "User=%s\n", config.User)
+	}
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=2\n")
+	b.WriteString("TimeoutStopSec=30\n")
+	b.WriteString("NoNewPrivileges=true\n")
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// socketUnit renders the socket unit listening on the server's port
+func socketUnit(config ServiceConfig) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=share-screen listening socket\n")
+	b.WriteString("\n[Socket]\n")
+	fmt.Fprintf(&b, "ListenStream=%s\n", config.Port)
+	b.WriteString("\n[Install]\n")
+	b.WriteString("WantedBy=sockets.target\n")
+	return b.String()
+}
+
+// execStart joins the command line, quoting the words systemd would split
+func execStart(config ServiceConfig) string {
+	words := append([]string{config.Executable}, config.Args...)
+	for i, word := range words {
+		if word == "" || strings.ContainsAny(word, " \t\"'\\$%;") {
+			replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+			words[i] = `"` + replacer.Replace(word) + `"`
+		}
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestServiceUnit(t *testing.T) {
+	config := ServiceConfig{
+		Name:             "share-screen",
+		Executable:       "/opt/share-screen/share-screen",
+		Args:             []string{"-port", "8443", "-tls-cert", "/etc/my certs/cert.pem"},
+		WorkingDirectory: "/opt/share-screen",
+		User:             "share",
+		Socket:           true,
+		Port:             "8443",
+	}
+
+	unit := serviceUnit(config)
+	for _, expected := range []string{
+		"Type=notify\n",
+		`ExecStart=/opt/share-screen/share-screen -port 8443 -tls-cert "/etc/my certs/cert.pem"` + "\n",
+		"WorkingDirectory=/opt/share-screen\n",
+		"User=share\n",
+		"Requires=share-screen.socket\n",
+		"WantedBy=multi-user.target\n",
+	} {
+		if !strings.Contains(unit, expected) {
+			t.Errorf("Expected %q in %q", expected, unit)
+		}
+	}
+
+	if socket := socketUnit(config); !strings.Contains(socket, "ListenStream=8443\n") {
+		t.Errorf("Expected the socket to listen on 8443, got %q", socket)
+	}
+}
+
+func TestServiceUnit_WithoutUser(t *testing.T) {
+	unit := serviceUnit(ServiceConfig{Name: "share-screen", Executable: "/usr/bin/share-screen"})
+	if strings.Contains(unit, "User=") || strings.Contains(unit, "Requires=") {
+		t.Errorf("Expected no User or Requires line, got %q", unit)
+	}
+}
+
+func TestExecStart_Quoting(t *testing.T) {
+	config := ServiceConfig{Executable: "/usr/bin/share-screen", Args: []string{"-pin", "50%$", ""}}
+	if got, want := execStart(config), `/usr/bin/share-screen -pin "50%%$$" ""`; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestInstallService(t *testing.T) {
+	dir := t.TempDir()
+	config := ServiceConfig{Name: "share-screen", Executable: "/usr/bin/share-screen", WorkingDirectory: dir, Socket: true, Port: "8080"}
+
+	var out bytes.Buffer
+	if err := InstallService(config, dir, &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, file := range []string{"share-screen.service", "share-screen.socket"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("Expected %s to be written: %v", file, err)
+		}
+	}
+	if !strings.Contains(out.String(), "systemctl enable --now share-screen.socket") {
+		t.Errorf("Expected the socket to be enabled, got %q", out.String())
+	}
+}
+
+func TestInstallService_Print(t *testing.T) {
+	var out bytes.Buffer
+	config := ServiceConfig{Name: "share-screen", Executable: "/usr/bin/share-screen"}
+	if err := InstallService(config, "-", &out); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "# share-screen.service\n[Unit]") {
+		t.Errorf("Expected the unit to be printed, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "systemctl") {
+		t.Errorf("Expected no instructions when printing, got %q", out.String())
+	}
+}
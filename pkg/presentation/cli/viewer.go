@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/pion/webrtc/v4"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/recording"
+)
+
+// ViewerConfig holds the settings of the native viewer
+type ViewerConfig struct {
+	Token    string
+	PIN      string
+	Duration time.Duration
+}
+
+// Viewer watches a session without a browser, handing the received video
+// track to a recorder; it waits in the viewer queue like the viewer page
+type Viewer struct {
+	client   *client.Client
+	recorder interfaces.VideoRecorder
+	config   ViewerConfig
+	out      io.Writer
+
+	// viewerID identifies this viewer to the server, like the viewer page's;
+	// joining the queue replaces it with the ID the queue hands out
+	viewerID  string
+	queued    bool
+	connected bool
+}
+
+// NewViewer creates a new native viewer
+func NewViewer(apiClient *client.Client, recorder interfaces.VideoRecorder, config ViewerConfig, out io.Writer) *Viewer {
+	return &Viewer{
+		client:   apiClient,
+		recorder: recorder,
+		config:   config,
+		out:      out,
+		viewerID: newViewerID(),
+	}
+}
+
+// RunViewer parses the arguments of the `view` mode and records or plays the
+// session until it ends or ctx is done
+func RunViewer(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("view", flag.ContinueOnError)
+	flags.SetOutput(out)
+	serverURL := flags.String("server", "http://localhost:8080", "URL of the share-screen server")
+	token := flags.String("token", "", "Session token from the viewer link")
+	pin := flags.String("pin", "", "PIN of a protected session")
+	output := flags.String("out", "", "Record to this .ivf or .webm file")
+	play := flags.Bool("play", false, "Play the stream in an ffplay window")
+	rtmp := flags.String("rtmp", "", "Republish the stream to this rtmp:// or rtmps:// URL through ffmpeg")
+	duration := flags.Duration("duration", 0, "Stop after this long (0 = until the session ends)")
+	ffmpeg := flags.String("ffmpeg", "ffmpeg", "Path to the ffmpeg binary used for WebM recordings and -rtmp")
+	ffplay := flags.String("ffplay", "ffplay", "Path to the ffplay binary used by -play")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		return errors.New("missing -token")
+	}
+
+	outputs := 0
+	for _, set := range []bool{*output != "", *play, *rtmp != ""} {
+		if set {
+			outputs++
+		}
+	}
+
+	var recorder interfaces.VideoRecorder
+	var err error
+	switch {
+	case outputs > 1:
+		return errors.New("use only one of -out, -play and -rtmp")
+	case *play:
+		recorder = recording.NewFFplayPlayer(*ffplay)
+	case *output != "":
+		if recorder, err = recording.NewFileRecorder(*output, *ffmpeg); err != nil {
+			return err
+		}
+	case *rtmp != "":
+		if recorder, err = recording.NewRTMPRestreamer(*rtmp, *ffmpeg); err != nil {
+			return err
+		}
+	default:
+		return errors.New("missing -out, -play or -rtmp")
+	}
+
+	viewer := NewViewer(
+		client.NewClient(*serverURL, nil),
+		recorder,
+		ViewerConfig{Token: *token, PIN: *pin, Duration: *duration},
+		out,
+	)
+	return viewer.Run(ctx)
+}
+
+// Run joins the session, receives the video until the sender stops, the
+// duration passes or ctx is done, then frees the viewer slot
+func (v *Viewer) Run(ctx context.Context) error {
+	if v.config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.config.Duration)
+		defer cancel()
+	}
+
+	offer, err := v.admit(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			v.leave()
+			return nil
+		}
+		return err
+	}
+	defer v.leave()
+
+	iceServers, err := fetchICEServers(ctx, v.client, v.config.Token, v.config.PIN)
+	if err != nil {
+		return err
+	}
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{ICEServers: iceServers})
+	if err != nil {
+		return err
+	}
+
+	stopped := make(chan error, 1)
+	var receiving sync.WaitGroup
+	defer func() {
+		// Closing the connection ends the track, which closes the recording
+		_ = pc.Close()
+		receiving.Wait()
+	}()
+
+	pc.OnTrack(func(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if track.Kind() != webrtc.RTPCodecTypeVideo {
+			return
+		}
+		receiving.Add(1)
+		go func() {
+			defer receiving.Done()
+			stop(stopped, v.receive(pc, track))
+		}()
+	})
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		switch state {
+		case webrtc.PeerConnectionStateConnected:
+			log.Printf("✅ Connected to sender")
+		case webrtc.PeerConnectionStateFailed:
+			stop(stopped, errors.New("connection to sender failed"))
+		}
+	})
+
+	if err := pc.SetRemoteDescription(webrtc.SessionDescription{Type: webrtc.SDPTypeOffer, SDP: offer.SDP}); err != nil {
+		return err
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return err
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		return err
+	}
+	// Wait for candidates so the answer works without trickle ICE
+	select {
+	case <-gathered:
+	case <-ctx.Done():
+		return nil
+	}
+
+	local := pc.LocalDescription()
+	if err := v.client.SubmitAnswer(ctx, v.config.Token, v.viewerID, v.config.PIN, &entities.WebRTCAnswer{Type: local.Type.String(), SDP: local.SDP}); err != nil {
+		return fmt.Errorf("publishing answer: %w", err)
+	}
+	v.connected = true
+	fmt.Fprintln(v.out, "Receiving video, press Ctrl+C to stop")
+
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-stopped:
+		if err == nil {
+			log.Printf("🛑 Sender stopped sharing")
+		}
+		return err
+	}
+}
+
+// admit fetches the sender's offer, waiting in the viewer queue while someone
+// else is watching
+func (v *Viewer) admit(ctx context.Context) (*entities.WebRTCOffer, error) {
+	for {
+		offer, err := v.client.WaitForOffer(ctx, v.config.Token, v.viewerID, v.config.PIN)
+		switch client.StatusCode(err) {
+		case 409:
+			if err := v.waitInQueue(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		case 403:
+			return nil, errors.New("session needs a PIN, pass the right one with -pin")
+		case 429:
+			return nil, errors.New("session locked after too many wrong PINs, ask the sender to share again")
+		}
+		return offer, err
+	}
+}
+
+// waitInQueue joins the queue and returns once this viewer is admitted
+func (v *Viewer) waitInQueue(ctx context.Context) error {
+	joined, err := v.client.JoinQueue(ctx, v.config.Token, v.config.PIN)
+	if err != nil {
+		return fmt.Errorf("joining queue: %w", err)
+	}
+	v.viewerID = joined.ViewerID
+	v.queued = true
+	if joined.Position == 0 {
+		return nil
+	}
+	log.Printf("⏳ Someone else is watching, you are #%d in line", joined.Position)
+
+	eventsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := v.client.Events(eventsCtx, v.config.Token, v.viewerID)
+	if err != nil {
+		return fmt.Errorf("subscribing to queue events: %w", err)
+	}
+
+	for event := range events {
+		switch event.Type {
+		case entities.EventQueuePosition:
+			var position struct {
+				Position int `json:"position"`
+			}
+			if raw, ok := event.Data.(json.RawMessage); ok && json.Unmarshal(raw, &position) == nil {
+				log.Printf("⏳ You are #%d in line", position.Position)
+			}
+		case entities.EventViewerAdmitted:
+			log.Printf("🎟️  Your turn to watch")
+			return nil
+		case entities.EventServerShutdown:
+			return errors.New("server is restarting")
+		case entities.EventLinkUsed:
+			return errors.New("viewer link already used on another device")
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed by the server")
+}
+
+// receive hands the track's packets to a new sink until the track ends, which
+// is not an error
+func (v *Viewer) receive(pc *webrtc.PeerConnection, track *webrtc.TrackRemote) error {
+	codec := track.Codec()
+	sink, err := v.recorder.Open(interfaces.VideoCodec{
+		MimeType:    codec.MimeType,
+		ClockRate:   codec.ClockRate,
+		PayloadType: uint8(codec.PayloadType),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := sink.Close(); err != nil {
+			log.Printf("❌ Finishing recording failed: %v", err)
+		}
+	}()
+	log.Printf("🎥 Receiving %s video", codec.MimeType)
+
+	// Ask for a keyframe so the recording starts with a full picture
+	if err := pc.WriteRTCP([]rtcp.Packet{&rtcp.PictureLossIndication{MediaSSRC: uint32(track.SSRC())}}); err != nil {
+		log.Printf("❌ Keyframe request failed: %v", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := track.Read(buf)
+		if err != nil {
+			return nil
+		}
+		if err := sink.WriteRTP(buf[:n]); err != nil {
+			return err
+		}
+	}
+}
+
+// leave frees the viewer slot or queue place so the next viewer can watch
+func (v *Viewer) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), endTimeout)
+	defer cancel()
+
+	var err error
+	switch {
+	case v.connected:
+		err = v.client.ReleaseViewer(ctx, v.config.Token)
+	case v.queued:
+		err = v.client.LeaveQueue(ctx, v.config.Token, v.viewerID)
+	default:
+		return
+	}
+	// Nothing to free once the sender has ended the session
+	if err != nil && client.StatusCode(err) != 410 {
+		log.Printf("❌ Leaving session failed: %v", err)
+	}
+}
+
+// newViewerID returns a random ID for a viewer that has not joined the queue
+func newViewerID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// stop reports why viewing ended; only the first reason is kept
+func stop(stopped chan<- error, err error) {
+	select {
+	case stopped <- err:
+	default:
+	}
+}
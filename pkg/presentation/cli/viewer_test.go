@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/client"
+	"share-screen/test/mocks"
+)
+
+// startSender shares a mock screen and returns the session token
+func startSender(t *testing.T, ctx context.Context, serverURL string) (string, <-chan error) {
+	out := &syncBuffer{}
+	sender := NewSender(client.NewClient(serverURL, nil), mocks.NewMockScreenCapturer(), SenderConfig{ServerURL: serverURL, FrameRate: 30, ReusableLink: true}, out)
+	finished := make(chan error, 1)
+	go func() { finished <- sender.Run(ctx) }()
+	return tokenFromOutput(t, out), finished
+}
+
+// waitFor polls condition until it holds or five seconds pass
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestViewer_Run(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	token, _ := startSender(t, ctx, serverURL)
+
+	recorder := mocks.NewMockVideoRecorder()
+	viewerCtx, stopViewer := context.WithCancel(ctx)
+	finished := make(chan error, 1)
+	go func() {
+		finished <- NewViewer(c, recorder, ViewerConfig{Token: token}, &syncBuffer{}).Run(viewerCtx)
+	}()
+
+	waitFor(t, "video packets", func() bool { return recorder.Packets() > 0 })
+	if codecs := recorder.Codecs(); len(codecs) != 1 || codecs[0].MimeType != "video/VP8" {
+		t.Errorf("Expected one VP8 track, got %v", codecs)
+	}
+
+	stopViewer()
+	if err := <-finished; err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if closed := recorder.Closed(); closed != 1 {
+		t.Errorf("Expected the recording to be closed once, got %d", closed)
+	}
+
+	// Leaving frees the slot, so the sender offers it to the next viewer
+	watch(t, ctx, c, token, nil).Close()
+}
+
+func TestViewer_SenderStops(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	senderCtx, stopSender := context.WithCancel(ctx)
+	token, senderFinished := startSender(t, senderCtx, serverURL)
+
+	recorder := mocks.NewMockVideoRecorder()
+	finished := make(chan error, 1)
+	go func() {
+		finished <- NewViewer(c, recorder, ViewerConfig{Token: token}, &syncBuffer{}).Run(ctx)
+	}()
+	waitFor(t, "video packets", func() bool { return recorder.Packets() > 0 })
+
+	stopSender()
+	<-senderFinished
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Errorf("Expected a clean stop, got %v", err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Viewer kept running after the sender stopped")
+	}
+	if closed := recorder.Closed(); closed != 1 {
+		t.Errorf("Expected the recording to be closed once, got %d", closed)
+	}
+}
+
+func TestViewer_Queued(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	token, _ := startSender(t, ctx, serverURL)
+
+	// Someone else is watching, so the native viewer waits in line
+	first := watch(t, ctx, c, token, nil)
+
+	recorder := mocks.NewMockVideoRecorder()
+	finished := make(chan error, 1)
+	go func() {
+		finished <- NewViewer(c, recorder, ViewerConfig{Token: token}, &syncBuffer{}).Run(ctx)
+	}()
+	waitFor(t, "the viewer to queue", func() bool {
+		queue, err := c.GetQueue(ctx, token)
+		return err == nil && len(queue.Viewers) == 1
+	})
+
+	_ = first.Close()
+	if err := c.ReleaseViewer(ctx, token); err != nil {
+		t.Fatalf("ReleaseViewer failed: %v", err)
+	}
+	waitFor(t, "video packets", func() bool { return recorder.Packets() > 0 })
+}
+
+func TestViewer_RecorderFailure(t *testing.T) {
+	serverURL := newTestServer(t)
+	c := client.NewClient(serverURL, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	token, _ := startSender(t, ctx, serverURL)
+
+	recorder := mocks.NewMockVideoRecorder()
+	recorder.ShouldFail = true
+	err := NewViewer(c, recorder, ViewerConfig{Token: token}, &syncBuffer{}).Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "failed to open recording") {
+		t.Errorf("Expected recorder error, got %v", err)
+	}
+}
+
+func TestRunViewer_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown flag", args: []string{"-bogus"}},
+		{name: "missing token", args: []string{"-out", "session.ivf"}},
+		{name: "missing output", args: []string{"-token", "abc"}},
+		{name: "output and play", args: []string{"-token", "abc", "-out", "session.ivf", "-play"}},
+		{name: "unsupported format", args: []string{"-token", "abc", "-out", "session.mp4"}},
+		{name: "output and rtmp", args: []string{"-token", "abc", "-out", "session.ivf", "-rtmp", "rtmp://live.example.com/app/key"}},
+		{name: "invalid rtmp url", args: []string{"-token", "abc", "-rtmp", "http://live.example.com/app/key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RunViewer(context.Background(), tt.args, &bytes.Buffer{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"share-screen/pkg/infrastructure/winsvc"
+)
+
+// RunService parses the arguments of the `service` mode, which installs,
+// uninstalls or starts the server as a Windows service; arguments after --
+// are passed to the server
+func RunService(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: share-screen service install|uninstall|start [flags]")
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("service "+action, flag.ContinueOnError)
+	flags.SetOutput(out)
+	name := flags.String("name", winsvc.DefaultName, "Name of the service")
+	workDir := flags.String("workdir", "", "Directory holding web/templates and .env (default the current directory)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch action {
+	case "install":
+		executable, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		if executable, err = filepath.EvalSymlinks(executable); err != nil {
+			return err
+		}
+		dir, err := serverDir(*workDir)
+		if err != nil {
+			return err
+		}
+		if err := winsvc.Install(*name, executable, serviceRunArgs(*name, dir, flags.Args())); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Installed service %s; start it with:\n  share-screen service start -name %s\n", *name, *name)
+	case "uninstall":
+		if err := winsvc.Uninstall(*name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Removed service %s\n", *name)
+	case "start":
+		if err := winsvc.Start(*name); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Started service %s; its log is in the Application event log\n", *name)
+	default:
+		return fmt.Errorf("unknown service command %q; use install, uninstall or start", action)
+	}
+	return nil
+}
+
+// serviceRunArgs returns the arguments the service control manager starts
+// the server with: `service run`, then the server's own flags
+func serviceRunArgs(name, workDir string, serverArgs []string) []string {
+	args := []string{"service", "run", "-name", name, "-workdir", workDir}
+	if len(serverArgs) > 0 {
+		args = append(append(args, "--"), serverArgs...)
+	}
+	return args
+}
+
+// ParseServiceRun parses the arguments of `service run`, returning the
+// service name, the directory to run in and the server's flags
+func ParseServiceRun(args []string) (string, string, []string, error) {
+	flags := flag.NewFlagSet("service run", flag.ContinueOnError)
+	name := flags.String("name", winsvc.DefaultName, "Name of the service")
+	workDir := flags.String("workdir", "", "Directory holding web/templates and .env")
+	if err := flags.Parse(args); err != nil {
+		return "", "", nil, err
+	}
+	if *workDir == "" {
+		return "", "", nil, errors.New("service run needs -workdir")
+	}
+	return *name, *workDir, flags.Args(), nil
+}
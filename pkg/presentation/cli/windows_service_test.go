@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestServiceRunArgs_RoundTrip(t *testing.T) {
+	args := serviceRunArgs("share-screen", `C:\share-screen`, []string{"-port", "8443"})
+	if !slices.Equal(args[:2], []string{"service", "run"}) {
+		t.Fatalf("Expected the args to start with service run, got %v", args)
+	}
+
+	name, workDir, serverArgs, err := ParseServiceRun(args[2:])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "share-screen" || workDir != `C:\share-screen` {
+		t.Errorf("Expected share-screen in C:\\share-screen, got %s in %s", name, workDir)
+	}
+	if !slices.Equal(serverArgs, []string{"-port", "8443"}) {
+		t.Errorf("Expected the server flags to be passed on, got %v", serverArgs)
+	}
+}
+
+func TestParseServiceRun_WithoutWorkDir(t *testing.T) {
+	if _, _, _, err := ParseServiceRun(nil); err == nil {
+		t.Error("Expected an error without -workdir")
+	}
+}
+
+func TestRunService_UnknownCommand(t *testing.T) {
+	err := RunService([]string{"restart"}, io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "unknown service command") {
+		t.Errorf("Expected an unknown command error, got %v", err)
+	}
+	if err := RunService(nil, io.Discard); err == nil {
+		t.Error("Expected a usage error without a command")
+	}
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/presentation/grpc/signalingpb"
+)
+
+// errUnauthenticated is returned for CreateSession calls without valid credentials
+var errUnauthenticated = errors.New("invalid credentials")
+
+// NetworkPolicy reports whether a client address may signal; the HTTP
+// server's network policy is one
+type NetworkPolicy interface {
+	Allows(remoteAddr string) bool
+}
+
+// Authenticator checks the authorization metadata of a CreateSession call
+// and returns the user it names, nil for credentials that name nobody
+type Authenticator func(authorization string) (*entities.UserIdentity, error)
+
+// BasicAuthenticator accepts "Basic <base64 user:password>" credentials that
+// check accepts, as the HTTP server's basic auth does
+func BasicAuthenticator(check func(user, password string) bool) Authenticator {
+	return func(authorization string) (*entities.UserIdentity, error) {
+		encoded, ok := strings.CutPrefix(authorization, "Basic ")
+		if !ok {
+			return nil, errUnauthenticated
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errUnauthenticated
+		}
+		name, pass, ok := strings.Cut(string(decoded), ":")
+		if !ok || !check(name, pass) {
+			return nil, errUnauthenticated
+		}
+		return nil, nil
+	}
+}
+
+// BearerAuthenticator accepts "Bearer <jwt>" credentials that verifier
+// accepts, as the HTTP server's JWT auth does
+func BearerAuthenticator(verifier interfaces.TokenVerifier) Authenticator {
+	return func(authorization string) (*entities.UserIdentity, error) {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok {
+			return nil, errUnauthenticated
+		}
+		return verifier.Verify(token)
+	}
+}
+
+// userContextKey keys the UserIdentity of an authenticated call
+type userContextKey struct{}
+
+// Access applies the rules of the HTTP signaling endpoints to every call:
+// clients outside the allowed networks and banned clients are refused,
+// malformed or expired session tokens are refused before any lookup, and
+// CreateSession needs credentials when the server has any configured
+type Access struct {
+	networks      NetworkPolicy
+	bans          interfaces.BanUseCase
+	tokens        entities.TokenPolicy
+	authenticator Authenticator
+	now           func() time.Time
+}
+
+// NewAccess creates the access rules; a nil networks, bans or authenticator
+// lets every call through that check
+func NewAccess(networks NetworkPolicy, bans interfaces.BanUseCase, tokens entities.TokenPolicy, authenticator Authenticator) *Access {
+	return &Access{
+		networks:      networks,
+		bans:          bans,
+		tokens:        tokens,
+		authenticator: authenticator,
+		now:           time.Now,
+	}
+}
+
+// UnaryInterceptor checks a call before it reaches its handler
+func (a *Access) UnaryInterceptor(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	remoteAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	if a.networks != nil && !a.networks.Allows(remoteAddr) {
+		log.Printf("🚫 Signaling call %s refused from %s", info.FullMethod, remoteAddr)
+		return nil, status.Error(codes.PermissionDenied, "outside the allowed networks")
+	}
+	if a.bans != nil && a.bans.IsBanned(peerIP(ctx)) {
+		log.Printf("🚫 Signaling call %s refused from banned %s", info.FullMethod, remoteAddr)
+		return nil, status.Error(codes.PermissionDenied, "banned")
+	}
+
+	if withToken, ok := request.(interface{ GetToken() string }); ok {
+		if err := a.checkToken(withToken.GetToken()); err != nil {
+			return nil, err
+		}
+	}
+
+	if info.FullMethod == signalingpb.Signaling_CreateSession_FullMethodName && a.authenticator != nil {
+		authorization := ""
+		if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+			authorization = values[0]
+		}
+		user, err := a.authenticator(authorization)
+		if err != nil {
+			log.Printf("🔐 Unauthenticated call %s from %s: %v", info.FullMethod, remoteAddr, err)
+			return nil, status.Error(codes.Unauthenticated, "unauthorized")
+		}
+		if user != nil {
+			ctx = context.WithValue(ctx, userContextKey{}, user)
+		}
+	}
+
+	return handler(ctx, request)
+}
+
+// checkToken refuses a malformed or forged token, and an expired signed one;
+// an empty token is left to the use case to refuse
+func (a *Access) checkToken(token string) error {
+	if token == "" {
+		return nil
+	}
+	if !a.tokens.Valid(token) {
+		return status.Error(codes.InvalidArgument, "malformed token")
+	}
+	if expiresAt := a.tokens.ExpiresAt(token); !expiresAt.IsZero() && a.now().After(expiresAt) {
+		return status.Error(codes.FailedPrecondition, "session expired")
+	}
+	return nil
+}
+
+// peerIP returns the IP address of the call's client, for the audit log and
+// the ban list
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// userFromContext returns the user Access authenticated the call as, if any
+func userFromContext(ctx context.Context) *entities.UserIdentity {
+	user, _ := ctx.Value(userContextKey{}).(*entities.UserIdentity)
+	return user
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/presentation/grpc/signalingpb"
+	"share-screen/test/mocks"
+)
+
+// fakeNetworkPolicy allows the addresses in allowed
+type fakeNetworkPolicy struct {
+	allowed map[string]bool
+}
+
+func (p *fakeNetworkPolicy) Allows(remoteAddr string) bool {
+	return p.allowed[remoteAddr]
+}
+
+// newTestClient serves the Signaling service over an in-memory connection
+// under access and returns a client for it
+func newTestClient(t *testing.T, sessionUseCase *mocks.MockSessionUseCase, access *Access) signalingpb.SignalingClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	server := NewServer(NewSignalingServer(sessionUseCase), access)
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return signalingpb.NewSignalingClient(conn)
+}
+
+// validToken is a well-formed token of the default policy
+func validToken() string {
+	return entities.TokenPolicy{}.Encode(make([]byte, entities.TokenPolicy{}.RandomBytes()), time.Now())
+}
+
+func TestAccess_NetworkPolicy(t *testing.T) {
+	networks := &fakeNetworkPolicy{allowed: map[string]bool{}}
+	client := newTestClient(t, mocks.NewMockSessionUseCase(), NewAccess(networks, nil, entities.TokenPolicy{}, nil))
+
+	_, err := client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: validToken()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied outside the allowed networks, got %v", err)
+	}
+
+	// In-memory connections come from the "bufconn" address
+	networks.allowed["bufconn"] = true
+	if _, err := client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: validToken()}); err != nil {
+		t.Errorf("Expected the allowed network through, got %v", err)
+	}
+}
+
+func TestAccess_Banned(t *testing.T) {
+	bans := mocks.NewMockBanUseCase()
+	bans.Banned = []string{"bufconn"}
+	client := newTestClient(t, mocks.NewMockSessionUseCase(), NewAccess(nil, bans, entities.TokenPolicy{}, nil))
+
+	_, err := client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: validToken()})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a banned client, got %v", err)
+	}
+}
+
+func TestAccess_Tokens(t *testing.T) {
+	policy, err := entities.ParseTokenPolicy("base64url", 0, "0123456789abcdef0123", time.Hour)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	access := NewAccess(nil, nil, policy, nil)
+	client := newTestClient(t, mocks.NewMockSessionUseCase(), access)
+
+	_, err = client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: "not-a-token"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a malformed token, got %v", err)
+	}
+
+	token := policy.Encode(make([]byte, policy.RandomBytes()), time.Now())
+	if _, err := client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: token}); err != nil {
+		t.Errorf("Expected a signed token through, got %v", err)
+	}
+
+	access.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: token})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition for an expired token, got %v", err)
+	}
+}
+
+func TestAccess_BasicAuth(t *testing.T) {
+	authenticator := BasicAuthenticator(func(user, password string) bool {
+		return user == "alice" && password == "hunter22"
+	})
+	client := newTestClient(t, mocks.NewMockSessionUseCase(), NewAccess(nil, nil, entities.TokenPolicy{}, authenticator))
+
+	_, err := client.CreateSession(context.Background(), &signalingpb.CreateSessionRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+
+	credentials := "Basic " + base64.StdEncoding.EncodeToString([]byte("alice:hunter22"))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", credentials)
+	if _, err := client.CreateSession(ctx, &signalingpb.CreateSessionRequest{}); err != nil {
+		t.Errorf("Expected the credentials accepted, got %v", err)
+	}
+
+	// Only creating sessions needs them
+	if _, err := client.Heartbeat(context.Background(), &signalingpb.HeartbeatRequest{Token: validToken()}); err != nil {
+		t.Errorf("Expected signaling without credentials, got %v", err)
+	}
+}
+
+func TestAccess_BearerAuth(t *testing.T) {
+	verifier := mocks.NewMockTokenVerifier()
+	verifier.Users["good-jwt"] = &entities.UserIdentity{Subject: "alice"}
+	sessionUseCase := mocks.NewMockSessionUseCase()
+	client := newTestClient(t, sessionUseCase, NewAccess(nil, nil, entities.TokenPolicy{}, BearerAuthenticator(verifier)))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer bad-jwt")
+	_, err := client.CreateSession(ctx, &signalingpb.CreateSessionRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for an invalid JWT, got %v", err)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer good-jwt")
+	if _, err := client.CreateSession(ctx, &signalingpb.CreateSessionRequest{}); err != nil {
+		t.Fatalf("Expected the JWT accepted, got %v", err)
+	}
+	if user := sessionUseCase.LastCreateRequest.User; user == nil || user.Subject != "alice" {
+		t.Errorf("Expected the session to record the JWT's user, got %+v", user)
+	}
+}
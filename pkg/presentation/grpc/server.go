@@ -0,0 +1,308 @@
+// Package grpc serves the session use cases over gRPC, for native sender and
+// viewer apps that want a typed API instead of the JSON endpoints. The
+// service is defined in signalingpb/signaling.proto.
+package grpc
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/presentation/grpc/signalingpb"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+)
+
+// SignalingServer implements the Signaling service on the session use case
+type SignalingServer struct {
+	signalingpb.UnimplementedSignalingServer
+
+	sessionUseCase interfaces.SessionUseCase
+}
+
+// NewSignalingServer creates the Signaling service
+func NewSignalingServer(sessionUseCase interfaces.SessionUseCase) *SignalingServer {
+	return &SignalingServer{sessionUseCase: sessionUseCase}
+}
+
+// NewServer creates a gRPC server offering the Signaling service under the
+// given access rules, with any further options such as TLS credentials
+func NewServer(signaling *SignalingServer, access *Access, options ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(options, grpc.UnaryInterceptor(access.UnaryInterceptor))...)
+	signalingpb.RegisterSignalingServer(server, signaling)
+	return server
+}
+
+// CreateSession starts a session for the user the call authenticated as, if any
+func (s *SignalingServer) CreateSession(ctx context.Context, request *signalingpb.CreateSessionRequest) (*signalingpb.Session, error) {
+	response, err := s.sessionUseCase.CreateSession(&dto.CreateSessionRequest{
+		Name:           request.GetName(),
+		DisablePreview: request.GetDisablePreview(),
+		RequirePIN:     request.GetRequirePin(),
+		ReusableLink:   request.GetReusableLink(),
+		Chat:           request.GetChat(),
+		SFU:            request.GetSfu(),
+		MaxBitrateKbps: int(request.GetMaxBitrateKbps()),
+		VideoCodec:     request.GetVideoCodec(),
+		ForceCodec:     request.GetForceCodec(),
+		Preset:         request.GetPreset(),
+		ClientIP:       peerIP(ctx),
+		User:           userFromContext(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return newSession(response), nil
+}
+
+// ResumeSession reattaches a sender to its session
+func (s *SignalingServer) ResumeSession(ctx context.Context, request *signalingpb.ResumeSessionRequest) (*signalingpb.Session, error) {
+	response, err := s.sessionUseCase.ResumeSession(&dto.ResumeSessionRequest{
+		Token:     request.GetToken(),
+		SenderKey: request.GetSenderKey(),
+		ClientIP:  peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return newSession(response), nil
+}
+
+// SubmitOffer stores or replaces the sender's offer
+func (s *SignalingServer) SubmitOffer(ctx context.Context, request *signalingpb.SubmitOfferRequest) (*signalingpb.SubmitOfferResponse, error) {
+	var offer *entities.WebRTCOffer
+	if description := request.GetOffer(); description != nil {
+		offer = &entities.WebRTCOffer{Type: description.GetType(), SDP: description.GetSdp()}
+	}
+	err := s.sessionUseCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:      request.GetToken(),
+		Offer:      offer,
+		ClientIP:   peerIP(ctx),
+		ICERestart: request.GetIceRestart(),
+		SenderKey:  request.GetSenderKey(),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.SubmitOfferResponse{}, nil
+}
+
+// GetAnswer returns the viewer's answer to the sender
+func (s *SignalingServer) GetAnswer(ctx context.Context, request *signalingpb.GetAnswerRequest) (*signalingpb.GetAnswerResponse, error) {
+	response, err := s.sessionUseCase.GetAnswer(&dto.GetAnswerRequest{
+		Token:    request.GetToken(),
+		ClientIP: peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.GetAnswerResponse{
+		Answer:   newAnswerDescription(response.Answer),
+		ViewerId: response.ViewerID,
+	}, nil
+}
+
+// PublishStream connects the sender of an SFU session to the server
+func (s *SignalingServer) PublishStream(ctx context.Context, request *signalingpb.PublishStreamRequest) (*signalingpb.PublishStreamResponse, error) {
+	var offer *entities.WebRTCOffer
+	if description := request.GetOffer(); description != nil {
+		offer = &entities.WebRTCOffer{Type: description.GetType(), SDP: description.GetSdp()}
+	}
+	response, err := s.sessionUseCase.PublishStream(&dto.PublishStreamRequest{
+		Token:     request.GetToken(),
+		Offer:     offer,
+		ClientIP:  peerIP(ctx),
+		SenderKey: request.GetSenderKey(),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.PublishStreamResponse{Answer: newAnswerDescription(response.Answer)}, nil
+}
+
+// Heartbeat records that the sender is still there
+func (s *SignalingServer) Heartbeat(_ context.Context, request *signalingpb.HeartbeatRequest) (*signalingpb.HeartbeatResponse, error) {
+	err := s.sessionUseCase.Heartbeat(&dto.HeartbeatRequest{
+		Token:     request.GetToken(),
+		BytesSent: request.GetBytesSent(),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.HeartbeatResponse{}, nil
+}
+
+// ExtendSession pushes back the expiry of a live session
+func (s *SignalingServer) ExtendSession(ctx context.Context, request *signalingpb.ExtendSessionRequest) (*signalingpb.ExtendSessionResponse, error) {
+	response, err := s.sessionUseCase.ExtendSession(&dto.ExtendSessionRequest{
+		Token:     request.GetToken(),
+		SenderKey: request.GetSenderKey(),
+		ClientIP:  peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.ExtendSessionResponse{
+		ExpiresAt:        response.ExpiresAt.Unix(),
+		ExpiresInSeconds: int64(response.ExpiresInSeconds),
+	}, nil
+}
+
+// PauseSession pauses or resumes the stream for the viewers
+func (s *SignalingServer) PauseSession(ctx context.Context, request *signalingpb.PauseSessionRequest) (*signalingpb.PauseSessionResponse, error) {
+	err := s.sessionUseCase.PauseSession(&dto.PauseSessionRequest{
+		Token:     request.GetToken(),
+		Paused:    request.GetPaused(),
+		SenderKey: request.GetSenderKey(),
+		ClientIP:  peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.PauseSessionResponse{}, nil
+}
+
+// KickViewer removes a viewer from the session, given the sender key
+func (s *SignalingServer) KickViewer(ctx context.Context, request *signalingpb.KickViewerRequest) (*signalingpb.KickViewerResponse, error) {
+	err := s.sessionUseCase.KickViewer(&dto.KickViewerRequest{
+		Token:     request.GetToken(),
+		ViewerID:  request.GetViewerId(),
+		SenderKey: request.GetSenderKey(),
+		ClientIP:  peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.KickViewerResponse{}, nil
+}
+
+// EndSession ends the session; unlike a browser page, an app that calls it
+// is not coming back, so the session does not wait for it to resume
+func (s *SignalingServer) EndSession(ctx context.Context, request *signalingpb.EndSessionRequest) (*signalingpb.EndSessionResponse, error) {
+	err := s.sessionUseCase.EndSession(&dto.EndSessionRequest{
+		Token:     request.GetToken(),
+		SenderKey: request.GetSenderKey(),
+		ClientIP:  peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.EndSessionResponse{}, nil
+}
+
+// GetOffer returns the sender's offer to a viewer
+func (s *SignalingServer) GetOffer(ctx context.Context, request *signalingpb.GetOfferRequest) (*signalingpb.GetOfferResponse, error) {
+	response, err := s.sessionUseCase.GetOffer(&dto.GetOfferRequest{
+		Token:    request.GetToken(),
+		ViewerID: request.GetViewerId(),
+		PIN:      request.GetPin(),
+		ClientIP: peerIP(ctx),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	var offer *signalingpb.SessionDescription
+	if response.Offer != nil {
+		offer = &signalingpb.SessionDescription{Type: response.Offer.Type, Sdp: response.Offer.SDP}
+	}
+	return &signalingpb.GetOfferResponse{Offer: offer, Paused: response.Paused, IceGeneration: int32(response.ICEGeneration)}, nil
+}
+
+// SubmitAnswer stores a viewer's answer
+func (s *SignalingServer) SubmitAnswer(ctx context.Context, request *signalingpb.SubmitAnswerRequest) (*signalingpb.SubmitAnswerResponse, error) {
+	var answer *entities.WebRTCAnswer
+	if description := request.GetAnswer(); description != nil {
+		answer = &entities.WebRTCAnswer{Type: description.GetType(), SDP: description.GetSdp()}
+	}
+	err := s.sessionUseCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+		Token:         request.GetToken(),
+		Answer:        answer,
+		ViewerID:      request.GetViewerId(),
+		PIN:           request.GetPin(),
+		ClientIP:      peerIP(ctx),
+		ICEGeneration: int(request.GetIceGeneration()),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.SubmitAnswerResponse{}, nil
+}
+
+// SelectLayer sets the simulcast layer a viewer of an SFU session receives
+func (s *SignalingServer) SelectLayer(_ context.Context, request *signalingpb.SelectLayerRequest) (*signalingpb.SelectLayerResponse, error) {
+	err := s.sessionUseCase.SelectLayer(&dto.SelectLayerRequest{
+		Token:    request.GetToken(),
+		ViewerID: request.GetViewerId(),
+		Layer:    entities.SimulcastLayer(request.GetLayer()),
+	})
+	if err != nil {
+		return nil, useCaseError(err)
+	}
+	return &signalingpb.SelectLayerResponse{}, nil
+}
+
+// newSession describes a session to its sender
+func newSession(response *dto.CreateSessionResponse) *signalingpb.Session {
+	session := &signalingpb.Session{
+		Token:          response.Token,
+		Pin:            response.PIN,
+		SingleUse:      response.SingleUse,
+		Chat:           response.Chat,
+		Sfu:            response.SFU,
+		MaxBitrateKbps: int32(response.MaxBitrateKbps),
+		VideoCodec:     response.VideoCodec,
+		ForceCodec:     response.ForceCodec,
+		SenderKey:      response.SenderKey,
+		Paused:         response.Paused,
+	}
+	if preset := response.Preset; preset != nil {
+		session.Preset = &signalingpb.QualityPreset{
+			Id:             preset.ID,
+			Name:           preset.Name,
+			Width:          int32(preset.Width),
+			Height:         int32(preset.Height),
+			FrameRate:      int32(preset.FrameRate),
+			MaxBitrateKbps: int32(preset.MaxBitrateKbps),
+			ContentHint:    preset.ContentHint,
+		}
+	}
+	return session
+}
+
+// newAnswerDescription converts an answer, which may be nil
+func newAnswerDescription(answer *entities.WebRTCAnswer) *signalingpb.SessionDescription {
+	if answer == nil {
+		return nil
+	}
+	return &signalingpb.SessionDescription{Type: answer.Type, Sdp: answer.SDP}
+}
+
+// useCaseError converts use case errors to gRPC statuses, following the HTTP
+// API's status codes: 404 is NOT_FOUND, 410 FAILED_PRECONDITION, 409 and 429
+// ALREADY_EXISTS or RESOURCE_EXHAUSTED, 403 PERMISSION_DENIED
+func useCaseError(err error) error {
+	switch err {
+	case usecases.ErrSessionNotFound, usecases.ErrOfferNotFound, usecases.ErrAnswerNotFound, usecases.ErrSFUDisabled, usecases.ErrViewerNotConnected:
+		return status.Error(codes.NotFound, err.Error())
+	case usecases.ErrSessionExpired, usecases.ErrSessionEnded, usecases.ErrViewerLinkUsed, usecases.ErrViewerRevoked, usecases.ErrSessionNotReady:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case usecases.ErrInvalidOffer, usecases.ErrInvalidAnswer, usecases.ErrMissingViewerID, usecases.ErrInvalidBitrate, usecases.ErrInvalidFrameRate, usecases.ErrInvalidCodec, usecases.ErrInvalidPreset, usecases.ErrInvalidLayer, usecases.ErrInvalidSessionName, usecases.ErrInvalidViewerName:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case usecases.ErrAnswerAlreadyExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case usecases.ErrStaleAnswer:
+		return status.Error(codes.Aborted, err.Error())
+	case usecases.ErrSessionFull, usecases.ErrServerBusy, usecases.ErrPINLocked:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case usecases.ErrInvalidPIN, usecases.ErrInvalidSenderKey:
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		log.Printf("Unexpected error: %v", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
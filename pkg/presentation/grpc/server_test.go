@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/presentation/grpc/signalingpb"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// peerContext is the context of a call from addr
+func peerContext(addr string) context.Context {
+	tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+	return peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})
+}
+
+func TestSignalingServer_CreateSession(t *testing.T) {
+	sessionUseCase := mocks.NewMockSessionUseCase()
+	sessionUseCase.CreateSessionResponse.Preset = entities.FindQualityPreset("text")
+	server := NewSignalingServer(sessionUseCase)
+
+	user := &entities.UserIdentity{Subject: "alice"}
+	ctx := context.WithValue(peerContext("192.168.1.20:5000"), userContextKey{}, user)
+	session, err := server.CreateSession(ctx, &signalingpb.CreateSessionRequest{
+		Name:           "Standup",
+		RequirePin:     true,
+		MaxBitrateKbps: 2500,
+		VideoCodec:     "vp9",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if session.GetToken() != "mock-token" {
+		t.Errorf("Expected the created token, got %q", session.GetToken())
+	}
+	if session.GetPreset().GetId() != "text" {
+		t.Errorf("Expected the preset's capture limits, got %+v", session.GetPreset())
+	}
+
+	request := sessionUseCase.LastCreateRequest
+	if request.Name != "Standup" || !request.RequirePIN || request.MaxBitrateKbps != 2500 || request.VideoCodec != "vp9" {
+		t.Errorf("Expected the options passed on, got %+v", request)
+	}
+	if request.ClientIP != "192.168.1.20" {
+		t.Errorf("Expected the caller's IP for the audit log, got %q", request.ClientIP)
+	}
+	if request.User != user {
+		t.Errorf("Expected the authenticated user, got %+v", request.User)
+	}
+}
+
+func TestSignalingServer_Handshake(t *testing.T) {
+	sessionUseCase := mocks.NewMockSessionUseCase()
+	server := NewSignalingServer(sessionUseCase)
+	ctx := peerContext("192.168.1.20:5000")
+
+	_, err := server.SubmitOffer(ctx, &signalingpb.SubmitOfferRequest{
+		Token: "mock-token",
+		Offer: &signalingpb.SessionDescription{Type: "offer", Sdp: "v=0"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	offer, err := server.GetOffer(ctx, &signalingpb.GetOfferRequest{Token: "mock-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if offer.GetOffer().GetSdp() != "mock-sdp" {
+		t.Errorf("Expected the stored offer, got %+v", offer.GetOffer())
+	}
+
+	answer, err := server.GetAnswer(ctx, &signalingpb.GetAnswerRequest{Token: "mock-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if answer.GetAnswer().GetType() != "answer" || answer.GetAnswer().GetSdp() != "mock-answer-sdp" {
+		t.Errorf("Expected the stored answer, got %+v", answer.GetAnswer())
+	}
+}
+
+func TestSignalingServer_KickViewer(t *testing.T) {
+	sessionUseCase := mocks.NewMockSessionUseCase()
+	server := NewSignalingServer(sessionUseCase)
+
+	_, err := server.KickViewer(peerContext("192.168.1.20:5000"), &signalingpb.KickViewerRequest{
+		Token:     "mock-token",
+		ViewerId:  "viewer-1",
+		SenderKey: "key",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	request := sessionUseCase.LastKickViewerRequest
+	if request.ViewerID != "viewer-1" || request.SenderKey != "key" || request.Admin {
+		t.Errorf("Expected a sender's kick request, got %+v", request)
+	}
+}
+
+func TestUseCaseError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{usecases.ErrSessionNotFound, codes.NotFound},
+		{usecases.ErrAnswerNotFound, codes.NotFound},
+		{usecases.ErrSessionEnded, codes.FailedPrecondition},
+		{usecases.ErrViewerRevoked, codes.FailedPrecondition},
+		{usecases.ErrInvalidOffer, codes.InvalidArgument},
+		{usecases.ErrAnswerAlreadyExists, codes.AlreadyExists},
+		{usecases.ErrStaleAnswer, codes.Aborted},
+		{usecases.ErrServerBusy, codes.ResourceExhausted},
+		{usecases.ErrInvalidPIN, codes.PermissionDenied},
+		{usecases.ErrPINLocked, codes.ResourceExhausted},
+		{errors.New("disk full"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if code := status.Code(useCaseError(tt.err)); code != tt.code {
+				t.Errorf("Expected %v, got %v", tt.code, code)
+			}
+		})
+	}
+
+	// Unexpected errors do not leak their details
+	if message := status.Convert(useCaseError(errors.New("disk full"))).Message(); message != "internal server error" {
+		t.Errorf("Expected a generic message, got %q", message)
+	}
+}
@@ -0,0 +1,629 @@
+// The signaling API for native sender and viewer apps. It carries the same
+// session calls as the JSON endpoints under /api/v1, with the same access
+// rules. Run `make proto` after editing this file.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: signaling.proto
+
+package signalingpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Signaling_CreateSession_FullMethodName = "/sharescreen.signaling.v1.Signaling/CreateSession"
+	Signaling_ResumeSession_FullMethodName = "/sharescreen.signaling.v1.Signaling/ResumeSession"
+	Signaling_SubmitOffer_FullMethodName   = "/sharescreen.signaling.v1.Signaling/SubmitOffer"
+	Signaling_GetAnswer_FullMethodName     = "/sharescreen.signaling.v1.Signaling/GetAnswer"
+	Signaling_PublishStream_FullMethodName = "/sharescreen.signaling.v1.Signaling/PublishStream"
+	Signaling_Heartbeat_FullMethodName     = "/sharescreen.signaling.v1.Signaling/Heartbeat"
+	Signaling_ExtendSession_FullMethodName = "/sharescreen.signaling.v1.Signaling/ExtendSession"
+	Signaling_PauseSession_FullMethodName  = "/sharescreen.signaling.v1.Signaling/PauseSession"
+	Signaling_KickViewer_FullMethodName    = "/sharescreen.signaling.v1.Signaling/KickViewer"
+	Signaling_EndSession_FullMethodName    = "/sharescreen.signaling.v1.Signaling/EndSession"
+	Signaling_GetOffer_FullMethodName      = "/sharescreen.signaling.v1.Signaling/GetOffer"
+	Signaling_SubmitAnswer_FullMethodName  = "/sharescreen.signaling.v1.Signaling/SubmitAnswer"
+	Signaling_SelectLayer_FullMethodName   = "/sharescreen.signaling.v1.Signaling/SelectLayer"
+)
+
+// SignalingClient is the client API for Signaling service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Signaling runs the WebRTC handshake of a screen sharing session. Calls that
+// poll, GetOffer and GetAnswer, fail with NOT_FOUND until the other peer is
+// there; calls on a session that ended or expired fail with
+// FAILED_PRECONDITION.
+type SignalingClient interface {
+	// CreateSession starts a session. With basic auth or JWTs configured, the
+	// call carries the credentials in its authorization metadata, as
+	// "Basic <base64>" or "Bearer <jwt>".
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	// ResumeSession reattaches a sender that lost its session, given the
+	// sender key it was created with
+	ResumeSession(ctx context.Context, in *ResumeSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	// SubmitOffer stores or replaces the sender's offer, or restarts ICE on
+	// the connection to the connected viewer
+	SubmitOffer(ctx context.Context, in *SubmitOfferRequest, opts ...grpc.CallOption) (*SubmitOfferResponse, error)
+	// GetAnswer returns the viewer's answer to the sender
+	GetAnswer(ctx context.Context, in *GetAnswerRequest, opts ...grpc.CallOption) (*GetAnswerResponse, error)
+	// PublishStream connects the sender of an SFU session to the server and
+	// returns the server's answer
+	PublishStream(ctx context.Context, in *PublishStreamRequest, opts ...grpc.CallOption) (*PublishStreamResponse, error)
+	// Heartbeat tells the server the sender is still there; senders call it
+	// every few seconds
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// ExtendSession gives a live session the full token expiry again
+	ExtendSession(ctx context.Context, in *ExtendSessionRequest, opts ...grpc.CallOption) (*ExtendSessionResponse, error)
+	// PauseSession pauses or resumes the stream for the viewers
+	PauseSession(ctx context.Context, in *PauseSessionRequest, opts ...grpc.CallOption) (*PauseSessionResponse, error)
+	// KickViewer removes a viewer from the session
+	KickViewer(ctx context.Context, in *KickViewerRequest, opts ...grpc.CallOption) (*KickViewerResponse, error)
+	// EndSession ends the session
+	EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*EndSessionResponse, error)
+	// GetOffer returns the sender's offer to a viewer
+	GetOffer(ctx context.Context, in *GetOfferRequest, opts ...grpc.CallOption) (*GetOfferResponse, error)
+	// SubmitAnswer stores a viewer's answer
+	SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*SubmitAnswerResponse, error)
+	// SelectLayer sets the simulcast layer a viewer of an SFU session receives
+	SelectLayer(ctx context.Context, in *SelectLayerRequest, opts ...grpc.CallOption) (*SelectLayerResponse, error)
+}
+
+type signalingClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignalingClient(cc grpc.ClientConnInterface) SignalingClient {
+	return &signalingClient{cc}
+}
+
+func (c *signalingClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Session)
+	err := c.cc.Invoke(ctx, Signaling_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) ResumeSession(ctx context.Context, in *ResumeSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Session)
+	err := c.cc.Invoke(ctx, Signaling_ResumeSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) SubmitOffer(ctx context.Context, in *SubmitOfferRequest, opts ...grpc.CallOption) (*SubmitOfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitOfferResponse)
+	err := c.cc.Invoke(ctx, Signaling_SubmitOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) GetAnswer(ctx context.Context, in *GetAnswerRequest, opts ...grpc.CallOption) (*GetAnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAnswerResponse)
+	err := c.cc.Invoke(ctx, Signaling_GetAnswer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) PublishStream(ctx context.Context, in *PublishStreamRequest, opts ...grpc.CallOption) (*PublishStreamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishStreamResponse)
+	err := c.cc.Invoke(ctx, Signaling_PublishStream_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, Signaling_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) ExtendSession(ctx context.Context, in *ExtendSessionRequest, opts ...grpc.CallOption) (*ExtendSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExtendSessionResponse)
+	err := c.cc.Invoke(ctx, Signaling_ExtendSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) PauseSession(ctx context.Context, in *PauseSessionRequest, opts ...grpc.CallOption) (*PauseSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseSessionResponse)
+	err := c.cc.Invoke(ctx, Signaling_PauseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) KickViewer(ctx context.Context, in *KickViewerRequest, opts ...grpc.CallOption) (*KickViewerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KickViewerResponse)
+	err := c.cc.Invoke(ctx, Signaling_KickViewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) EndSession(ctx context.Context, in *EndSessionRequest, opts ...grpc.CallOption) (*EndSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EndSessionResponse)
+	err := c.cc.Invoke(ctx, Signaling_EndSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) GetOffer(ctx context.Context, in *GetOfferRequest, opts ...grpc.CallOption) (*GetOfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOfferResponse)
+	err := c.cc.Invoke(ctx, Signaling_GetOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) SubmitAnswer(ctx context.Context, in *SubmitAnswerRequest, opts ...grpc.CallOption) (*SubmitAnswerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitAnswerResponse)
+	err := c.cc.Invoke(ctx, Signaling_SubmitAnswer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *signalingClient) SelectLayer(ctx context.Context, in *SelectLayerRequest, opts ...grpc.CallOption) (*SelectLayerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectLayerResponse)
+	err := c.cc.Invoke(ctx, Signaling_SelectLayer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignalingServer is the server API for Signaling service.
+// All implementations must embed UnimplementedSignalingServer
+// for forward compatibility.
+//
+// Signaling runs the WebRTC handshake of a screen sharing session. Calls that
+// poll, GetOffer and GetAnswer, fail with NOT_FOUND until the other peer is
+// there; calls on a session that ended or expired fail with
+// FAILED_PRECONDITION.
+type SignalingServer interface {
+	// CreateSession starts a session. With basic auth or JWTs configured, the
+	// call carries the credentials in its authorization metadata, as
+	// "Basic <base64>" or "Bearer <jwt>".
+	CreateSession(context.Context, *CreateSessionRequest) (*Session, error)
+	// ResumeSession reattaches a sender that lost its session, given the
+	// sender key it was created with
+	ResumeSession(context.Context, *ResumeSessionRequest) (*Session, error)
+	// SubmitOffer stores or replaces the sender's offer, or restarts ICE on
+	// the connection to the connected viewer
+	SubmitOffer(context.Context, *SubmitOfferRequest) (*SubmitOfferResponse, error)
+	// GetAnswer returns the viewer's answer to the sender
+	GetAnswer(context.Context, *GetAnswerRequest) (*GetAnswerResponse, error)
+	// PublishStream connects the sender of an SFU session to the server and
+	// returns the server's answer
+	PublishStream(context.Context, *PublishStreamRequest) (*PublishStreamResponse, error)
+	// Heartbeat tells the server the sender is still there; senders call it
+	// every few seconds
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// ExtendSession gives a live session the full token expiry again
+	ExtendSession(context.Context, *ExtendSessionRequest) (*ExtendSessionResponse, error)
+	// PauseSession pauses or resumes the stream for the viewers
+	PauseSession(context.Context, *PauseSessionRequest) (*PauseSessionResponse, error)
+	// KickViewer removes a viewer from the session
+	KickViewer(context.Context, *KickViewerRequest) (*KickViewerResponse, error)
+	// EndSession ends the session
+	EndSession(context.Context, *EndSessionRequest) (*EndSessionResponse, error)
+	// GetOffer returns the sender's offer to a viewer
+	GetOffer(context.Context, *GetOfferRequest) (*GetOfferResponse, error)
+	// SubmitAnswer stores a viewer's answer
+	SubmitAnswer(context.Context, *SubmitAnswerRequest) (*SubmitAnswerResponse, error)
+	// SelectLayer sets the simulcast layer a viewer of an SFU session receives
+	SelectLayer(context.Context, *SelectLayerRequest) (*SelectLayerResponse, error)
+	mustEmbedUnimplementedSignalingServer()
+}
+
+// UnimplementedSignalingServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSignalingServer struct{}
+
+func (UnimplementedSignalingServer) CreateSession(context.Context, *CreateSessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedSignalingServer) ResumeSession(context.Context, *ResumeSessionRequest) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSession not implemented")
+}
+func (UnimplementedSignalingServer) SubmitOffer(context.Context, *SubmitOfferRequest) (*SubmitOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitOffer not implemented")
+}
+func (UnimplementedSignalingServer) GetAnswer(context.Context, *GetAnswerRequest) (*GetAnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAnswer not implemented")
+}
+func (UnimplementedSignalingServer) PublishStream(context.Context, *PublishStreamRequest) (*PublishStreamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishStream not implemented")
+}
+func (UnimplementedSignalingServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedSignalingServer) ExtendSession(context.Context, *ExtendSessionRequest) (*ExtendSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtendSession not implemented")
+}
+func (UnimplementedSignalingServer) PauseSession(context.Context, *PauseSessionRequest) (*PauseSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSession not implemented")
+}
+func (UnimplementedSignalingServer) KickViewer(context.Context, *KickViewerRequest) (*KickViewerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickViewer not implemented")
+}
+func (UnimplementedSignalingServer) EndSession(context.Context, *EndSessionRequest) (*EndSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EndSession not implemented")
+}
+func (UnimplementedSignalingServer) GetOffer(context.Context, *GetOfferRequest) (*GetOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOffer not implemented")
+}
+func (UnimplementedSignalingServer) SubmitAnswer(context.Context, *SubmitAnswerRequest) (*SubmitAnswerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAnswer not implemented")
+}
+func (UnimplementedSignalingServer) SelectLayer(context.Context, *SelectLayerRequest) (*SelectLayerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectLayer not implemented")
+}
+func (UnimplementedSignalingServer) mustEmbedUnimplementedSignalingServer() {}
+func (UnimplementedSignalingServer) testEmbeddedByValue()                   {}
+
+// UnsafeSignalingServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SignalingServer will
+// result in compilation errors.
+type UnsafeSignalingServer interface {
+	mustEmbedUnimplementedSignalingServer()
+}
+
+func RegisterSignalingServer(s grpc.ServiceRegistrar, srv SignalingServer) {
+	// If the following call pancis, it indicates UnimplementedSignalingServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Signaling_ServiceDesc, srv)
+}
+
+func _Signaling_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_ResumeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).ResumeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_ResumeSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).ResumeSession(ctx, req.(*ResumeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_SubmitOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).SubmitOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_SubmitOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).SubmitOffer(ctx, req.(*SubmitOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_GetAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).GetAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_GetAnswer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).GetAnswer(ctx, req.(*GetAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_PublishStream_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishStreamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).PublishStream(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_PublishStream_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).PublishStream(ctx, req.(*PublishStreamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_ExtendSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExtendSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).ExtendSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_ExtendSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).ExtendSession(ctx, req.(*ExtendSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_PauseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).PauseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_PauseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).PauseSession(ctx, req.(*PauseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_KickViewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickViewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).KickViewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_KickViewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).KickViewer(ctx, req.(*KickViewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_EndSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).EndSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_EndSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).EndSession(ctx, req.(*EndSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_GetOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).GetOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_GetOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).GetOffer(ctx, req.(*GetOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_SubmitAnswer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitAnswerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).SubmitAnswer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_SubmitAnswer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).SubmitAnswer(ctx, req.(*SubmitAnswerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Signaling_SelectLayer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectLayerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignalingServer).SelectLayer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Signaling_SelectLayer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignalingServer).SelectLayer(ctx, req.(*SelectLayerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Signaling_ServiceDesc is the grpc.ServiceDesc for Signaling service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Signaling_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sharescreen.signaling.v1.Signaling",
+	HandlerType: (*SignalingServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateSession",
+			Handler:    _Signaling_CreateSession_Handler,
+		},
+		{
+			MethodName: "ResumeSession",
+			Handler:    _Signaling_ResumeSession_Handler,
+		},
+		{
+			MethodName: "SubmitOffer",
+			Handler:    _Signaling_SubmitOffer_Handler,
+		},
+		{
+			MethodName: "GetAnswer",
+			Handler:    _Signaling_GetAnswer_Handler,
+		},
+		{
+			MethodName: "PublishStream",
+			Handler:    _Signaling_PublishStream_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _Signaling_Heartbeat_Handler,
+		},
+		{
+			MethodName: "ExtendSession",
+			Handler:    _Signaling_ExtendSession_Handler,
+		},
+		{
+			MethodName: "PauseSession",
+			Handler:    _Signaling_PauseSession_Handler,
+		},
+		{
+			MethodName: "KickViewer",
+			Handler:    _Signaling_KickViewer_Handler,
+		},
+		{
+			MethodName: "EndSession",
+			Handler:    _Signaling_EndSession_Handler,
+		},
+		{
+			MethodName: "GetOffer",
+			Handler:    _Signaling_GetOffer_Handler,
+		},
+		{
+			MethodName: "SubmitAnswer",
+			Handler:    _Signaling_SubmitAnswer_Handler,
+		},
+		{
+			MethodName: "SelectLayer",
+			Handler:    _Signaling_SelectLayer_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "signaling.proto",
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"share-screen/pkg/domain/entities"
+)
+
+const (
+	// adminFeedAuthTimeout is how long a feed connection may take to send
+	// the admin token
+	adminFeedAuthTimeout = 10 * time.Second
+
+	// adminFeedKeepalive is how often an idle feed sends a keepalive, so
+	// proxies do not close it
+	adminFeedKeepalive = 30 * time.Second
+
+	// adminFeedWriteTimeout bounds each write to a feed connection
+	adminFeedWriteTimeout = 10 * time.Second
+)
+
+// Messages the admin feed sends besides the session events
+const (
+	adminFeedKeepaliveEvent    = "keepalive"
+	adminFeedUnauthorizedEvent = "unauthorized"
+)
+
+// adminFeedAuth is the first message of a feed connection; browsers cannot
+// set the Authorization header on a WebSocket
+type adminFeedAuth struct {
+	Token string `json:"token"`
+}
+
+// HandleFeed streams the lifecycle events of every session over a WebSocket
+// so the dashboard updates as sessions are created, answered, end or
+// expire. Clients that cannot send the Authorization header send
+// {"token": "..."} as their first message.
+func (h *AdminHandlers) HandleFeed(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" && !h.jwtAuth.Enabled() {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+	if h.subscriber == nil {
+		http.Error(w, "live updates unavailable", 503)
+		return
+	}
+
+	// Subscribe before the upgrade so no event falls in between
+	events, cancel := h.subscriber.Subscribe(entities.AdminTopic)
+	authorized := h.authorized(r)
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer cancel()
+		defer conn.Close()
+		if !authorized && !h.authorizeFeed(conn, r) {
+			log.Printf("🔐 Unauthorized admin feed from %s", r.RemoteAddr)
+			h.sendFeed(conn, entities.Event{Type: adminFeedUnauthorizedEvent})
+			return
+		}
+		h.streamFeed(conn, events)
+	}}
+	server.ServeHTTP(w, r)
+	// A failed handshake never runs the handler
+	cancel()
+}
+
+// authorizeFeed reads the token message of a connection and checks it as if
+// it had come in the Authorization header
+func (h *AdminHandlers) authorizeFeed(conn *websocket.Conn, r *http.Request) bool {
+	_ = conn.SetReadDeadline(time.Now().Add(adminFeedAuthTimeout))
+	var auth adminFeedAuth
+	if err := websocket.JSON.Receive(conn, &auth); err != nil || auth.Token == "" {
+		return false
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	withToken := r.Clone(r.Context())
+	withToken.Header.Set("Authorization", "Bearer "+auth.Token)
+	return h.authorized(withToken)
+}
+
+// streamFeed sends the events until the client goes away or the broker
+// closes the subscription at shutdown
+func (h *AdminHandlers) streamFeed(conn *websocket.Conn, events <-chan entities.Event) {
+	// The client sends nothing more; reading notices when it goes away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		var discard []byte
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	keepalive := time.NewTicker(adminFeedKeepalive)
+	defer keepalive.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok || !h.sendFeed(conn, event) {
+				return
+			}
+		case <-keepalive.C:
+			if !h.sendFeed(conn, entities.Event{Type: adminFeedKeepaliveEvent}) {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// sendFeed writes an event as a JSON text message, reporting whether it went
+// through
+func (h *AdminHandlers) sendFeed(conn *websocket.Conn, event entities.Event) bool {
+	_ = conn.SetWriteDeadline(time.Now().Add(adminFeedWriteTimeout))
+	return websocket.JSON.Send(conn, event) == nil
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/test/mocks"
+)
+
+func TestAdminHandlers_HandleFeed(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		token         string
+		expectedType  string
+	}{
+		{
+			name:          "token in the header",
+			authorization: "Bearer admin-token",
+			expectedType:  entities.EventSession,
+		},
+		{
+			name:         "token as the first message",
+			token:        "admin-token",
+			expectedType: entities.EventSession,
+		},
+		{
+			name:         "wrong token",
+			token:        "nope",
+			expectedType: adminFeedUnauthorizedEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := events.NewBroker(events.DefaultBufferSize, events.PolicyDropOldest).(*events.Broker)
+			defer broker.Close()
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), broker, "admin-token", nil)
+			server := httptest.NewServer(NewRequestLogger(nil, http.HandlerFunc(handlers.HandleFeed)))
+			defer server.Close()
+
+			config, err := websocket.NewConfig("ws"+strings.TrimPrefix(server.URL, "http"), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.authorization != "" {
+				config.Header.Set("Authorization", tt.authorization)
+			}
+			conn, err := websocket.DialConfig(config)
+			if err != nil {
+				t.Fatalf("Dial failed: %v", err)
+			}
+			defer conn.Close()
+			if tt.token != "" {
+				if err := websocket.JSON.Send(conn, adminFeedAuth{Token: tt.token}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The handler subscribes before the upgrade, so publishing now
+			// cannot be missed
+			broker.Publish(entities.AdminTopic, entities.Event{
+				Type: entities.EventSession,
+				Data: entities.AuditEvent{Type: entities.AuditSessionCreated, Token: "abc"},
+			})
+
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			var got struct {
+				Type string              `json:"type"`
+				Data entities.AuditEvent `json:"data"`
+			}
+			if err := websocket.JSON.Receive(conn, &got); err != nil {
+				t.Fatalf("Receive failed: %v", err)
+			}
+			if got.Type != tt.expectedType {
+				t.Errorf("Expected a %q message, got %q", tt.expectedType, got.Type)
+			}
+			if tt.expectedType == entities.EventSession && got.Data.Token != "abc" {
+				t.Errorf("Expected the audit event of session abc, got %+v", got.Data)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleFeed_Disabled(t *testing.T) {
+	handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, "", nil)
+	w := httptest.NewRecorder()
+	handlers.HandleFeed(w, httptest.NewRequest("GET", "/api/v1/admin/feed", nil))
+	if w.Code != 404 {
+		t.Errorf("Expected status code 404, got %d", w.Code)
+	}
+}
@@ -0,0 +1,238 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// AdminHandlers serves the admin dashboard's API, which lists every session
+// on the server with its stats and audit log
+type AdminHandlers struct {
+	sessionUseCase interfaces.SessionUseCase
+	statsUseCase   interfaces.StatsUseCase
+	auditUseCase   interfaces.AuditUseCase
+	cleanupUseCase interfaces.CleanupUseCase
+	banUseCase     interfaces.BanUseCase
+	subscriber     interfaces.EventSubscriber
+	token          string
+	jwtAuth        *JWTAuth
+}
+
+// NewAdminHandlers creates a new admin handlers instance; subscriber feeds
+// the live session events, token is the bearer token clients must send, and
+// JWTs with the admin scope are accepted too when jwtAuth is enabled.
+// Without either the endpoints are disabled.
+func NewAdminHandlers(sessionUseCase interfaces.SessionUseCase, statsUseCase interfaces.StatsUseCase, auditUseCase interfaces.AuditUseCase, cleanupUseCase interfaces.CleanupUseCase, banUseCase interfaces.BanUseCase, subscriber interfaces.EventSubscriber, token string, jwtAuth *JWTAuth) *AdminHandlers {
+	return &AdminHandlers{
+		sessionUseCase: sessionUseCase,
+		statsUseCase:   statsUseCase,
+		auditUseCase:   auditUseCase,
+		cleanupUseCase: cleanupUseCase,
+		banUseCase:     banUseCase,
+		subscriber:     subscriber,
+		token:          token,
+		jwtAuth:        jwtAuth,
+	}
+}
+
+// HandleSessions lists the live sessions with the latest stats of their peers
+func (h *AdminHandlers) HandleSessions(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	response, err := h.statsUseCase.ListSessionStats()
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeAdminJSON(w, response)
+}
+
+// HandleSessionStats returns the stats time series of the session in the
+// path, limited to samples taken after ?since= (RFC 3339) when given
+func (h *AdminHandlers) HandleSessionStats(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	request := &dto.GetStatsRequest{Token: r.PathValue("token")}
+	if since := r.URL.Query().Get("since"); since != "" {
+		var err error
+		if request.Since, err = time.Parse(time.RFC3339Nano, since); err != nil {
+			http.Error(w, "invalid since", 400)
+			return
+		}
+	}
+
+	response, err := h.statsUseCase.GetSessionStats(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeAdminJSON(w, response)
+}
+
+// HandleAuditLog returns the audit log of the session in the path, as JSON
+// or with ?format=jsonl as a JSON Lines download with one event per line
+func (h *AdminHandlers) HandleAuditLog(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "jsonl" {
+		http.Error(w, "invalid format", 400)
+		return
+	}
+
+	response, err := h.auditUseCase.GetAuditLog(&dto.GetAuditLogRequest{Token: r.PathValue("token")})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	if format != "jsonl" {
+		writeAdminJSON(w, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/jsonl")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "audit-" + response.Token + ".jsonl"}))
+	encoder := json.NewEncoder(w)
+	for _, event := range response.Events {
+		if err := encoder.Encode(event); err != nil {
+			log.Printf("Error encoding audit log: %v", err)
+			return
+		}
+	}
+}
+
+// HandleCleanup removes ended and expired sessions now instead of waiting
+// for the next scheduled collection, and returns what was removed
+func (h *AdminHandlers) HandleCleanup(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodPost) {
+		return
+	}
+
+	run, err := h.cleanupUseCase.RunCleanup(entities.CleanupTriggerAdmin)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeAdminJSON(w, run)
+}
+
+// HandleBans manages the ban list: GET lists it, POST adds the ban in the
+// body and DELETE lifts the ban of ?network=
+func (h *AdminHandlers) HandleBans(w http.ResponseWriter, r *http.Request) {
+	// Each method is checked below
+	if !h.allow(w, r, r.Method) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		response, err := h.banUseCase.ListBans()
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		writeAdminJSON(w, response)
+	case http.MethodPost:
+		var request dto.AddBanRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		ban, err := h.banUseCase.AddBan(&request)
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		log.Printf("🔐 %s banned by an admin from %s", ban.Network, r.RemoteAddr)
+		writeAdminJSON(w, ban)
+	case http.MethodDelete:
+		request := &dto.RemoveBanRequest{Network: r.URL.Query().Get("network")}
+		if err := h.banUseCase.RemoveBan(request); err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(204)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+// HandleKickViewer removes the viewer in the path from the session in the
+// path, as its sender can
+func (h *AdminHandlers) HandleKickViewer(w http.ResponseWriter, r *http.Request) {
+	if !h.allow(w, r, http.MethodPost) {
+		return
+	}
+
+	request := &dto.KickViewerRequest{
+		Token:    r.PathValue("token"),
+		ViewerID: r.PathValue("viewer"),
+		ClientIP: clientIP(r),
+		Admin:    true,
+	}
+	if err := h.sessionUseCase.KickViewer(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// allow answers requests the admin API does not serve and reports whether
+// the request may go ahead with the given method
+func (h *AdminHandlers) allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if h.token == "" && !h.jwtAuth.Enabled() {
+		http.NotFound(w, r)
+		return false
+	}
+	if r.Method != method {
+		http.Error(w, "method not allowed", 405)
+		return false
+	}
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="share-screen admin"`)
+		http.Error(w, "unauthorized", 401)
+		return false
+	}
+	return true
+}
+
+// authorized reports whether the request carries the admin token or a JWT
+// granting the admin scope
+func (h *AdminHandlers) authorized(r *http.Request) bool {
+	if h.token != "" && bearerAuthorized(r, h.token) {
+		return true
+	}
+	if user := h.jwtAuth.Authenticate(r); user.HasScope(entities.ScopeAdmin) {
+		log.Printf("🔐 Admin request %s %s by %s", r.Method, r.URL.Path, user.Subject)
+		return true
+	}
+	return false
+}
+
+// writeAdminJSON writes an uncached JSON response
+func writeAdminJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding admin response: %v", err)
+	}
+}
@@ -0,0 +1,439 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestAdminHandlers_HandleSessions(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		method             string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{
+			name:               "sessions listed",
+			token:              "admin-token",
+			method:             "GET",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "dashboard disabled",
+			method:             "GET",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "wrong token",
+			token:              "admin-token",
+			method:             "GET",
+			authorization:      "Bearer other",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "method not allowed",
+			token:              "admin-token",
+			method:             "POST",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, tt.token, nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/sessions", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handlers.HandleSessions(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var response dto.AdminSessionsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Sessions) != 1 || response.Sessions[0].Token != "mock-token" {
+				t.Errorf("Expected the mock session, got %+v", response)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleSessionStats(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		statsError         error
+		expectedStatusCode int
+		expectedSince      string
+	}{
+		{
+			name:               "whole series",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "series since a time",
+			query:              "?since=2026-01-02T03:04:05.5Z",
+			expectedStatusCode: 200,
+			expectedSince:      "2026-01-02T03:04:05.5Z",
+		},
+		{
+			name:               "invalid since",
+			query:              "?since=yesterday",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "unknown session",
+			statsError:         usecases.ErrSessionNotFound,
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStatsUseCase := mocks.NewMockStatsUseCase()
+			mockStatsUseCase.GetSessionStatsError = tt.statsError
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mockStatsUseCase, mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, "admin-token", nil)
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/sessions/test-token/stats"+tt.query, nil)
+			req.SetPathValue("token", "test-token")
+			req.Header.Set("Authorization", "Bearer admin-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleSessionStats(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			request := mockStatsUseCase.LastGetRequest
+			if request.Token != "test-token" {
+				t.Errorf("Expected the path token, got %q", request.Token)
+			}
+			if tt.expectedSince != "" && request.Since.Format("2006-01-02T15:04:05.9Z07:00") != tt.expectedSince {
+				t.Errorf("Expected since %s, got %s", tt.expectedSince, request.Since)
+			}
+			var response dto.SessionStatsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Samples) != 1 {
+				t.Errorf("Expected the mock series, got %s (%v)", w.Body.String(), err)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleAuditLog(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		auditError          error
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{
+			name:                "JSON log",
+			expectedStatusCode:  200,
+			expectedContentType: "application/json",
+		},
+		{
+			name:                "JSON Lines export",
+			query:               "?format=jsonl",
+			expectedStatusCode:  200,
+			expectedContentType: "application/jsonl",
+		},
+		{
+			name:               "invalid format",
+			query:              "?format=csv",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "unknown session",
+			auditError:         usecases.ErrSessionNotFound,
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditUseCase := mocks.NewMockAuditUseCase()
+			mockAuditUseCase.GetAuditLogError = tt.auditError
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mockAuditUseCase, mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, "admin-token", nil)
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/sessions/test-token/audit"+tt.query, nil)
+			req.SetPathValue("token", "test-token")
+			req.Header.Set("Authorization", "Bearer admin-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleAuditLog(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Errorf("Expected content type %s, got %s", tt.expectedContentType, got)
+			}
+			if mockAuditUseCase.LastRequest.Token != "test-token" {
+				t.Errorf("Expected the path token, got %q", mockAuditUseCase.LastRequest.Token)
+			}
+
+			if tt.expectedContentType != "application/jsonl" {
+				var response dto.AuditLogResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Events) != 2 {
+					t.Errorf("Expected the mock log, got %s (%v)", w.Body.String(), err)
+				}
+				return
+			}
+
+			if !strings.Contains(w.Header().Get("Content-Disposition"), `filename=audit-test-token.jsonl`) {
+				t.Errorf("Expected a download, got Content-Disposition %q", w.Header().Get("Content-Disposition"))
+			}
+			lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("Expected one line per event, got %q", w.Body.String())
+			}
+			var event entities.AuditEvent
+			if err := json.Unmarshal([]byte(lines[1]), &event); err != nil || event.Type != entities.AuditTerminated || event.ClientIP != "192.168.1.10" {
+				t.Errorf("Expected the second event on the second line, got %s (%v)", lines[1], err)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleCleanup(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		cleanupError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "cleanup run",
+			method:             "POST",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "cleanup failed",
+			method:             "POST",
+			cleanupError:       errors.New("repository unavailable"),
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCleanupUseCase := mocks.NewMockCleanupUseCase()
+			mockCleanupUseCase.RunCleanupError = tt.cleanupError
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mockCleanupUseCase, mocks.NewMockBanUseCase(), nil, "admin-token", nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/cleanup", nil)
+			req.Header.Set("Authorization", "Bearer admin-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleCleanup(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			if mockCleanupUseCase.LastTrigger != entities.CleanupTriggerAdmin {
+				t.Errorf("Expected an admin run, got trigger %q", mockCleanupUseCase.LastTrigger)
+			}
+			var run entities.CleanupRun
+			if err := json.Unmarshal(w.Body.Bytes(), &run); err != nil || run.ExpiredSessions != 1 {
+				t.Errorf("Expected the counts of the run, got %s (%v)", w.Body.String(), err)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleKickViewer(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{
+			name:               "viewer removed",
+			method:             "POST",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "wrong token",
+			method:             "POST",
+			authorization:      "Bearer nope",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			handlers := NewAdminHandlers(mockSessionUseCase, mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, "admin-token", nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/sessions/test-token/viewers/viewer-1/kick", nil)
+			req.SetPathValue("token", "test-token")
+			req.SetPathValue("viewer", "viewer-1")
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handlers.HandleKickViewer(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 204 {
+				return
+			}
+			request := mockSessionUseCase.LastKickViewerRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || !request.Admin {
+				t.Errorf("Expected an admin removal of viewer-1, got %+v", request)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleBans(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		body               string
+		banError           error
+		expectedStatusCode int
+	}{
+		{
+			name:               "list",
+			method:             "GET",
+			target:             "/api/v1/admin/bans",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "add",
+			method:             "POST",
+			target:             "/api/v1/admin/bans",
+			body:               `{"network": "192.168.1.50", "reason": "spam"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "add loopback",
+			method:             "POST",
+			target:             "/api/v1/admin/bans",
+			body:               `{"network": "127.0.0.1"}`,
+			banError:           usecases.ErrBanLoopback,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid body",
+			method:             "POST",
+			target:             "/api/v1/admin/bans",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "remove",
+			method:             "DELETE",
+			target:             "/api/v1/admin/bans?network=192.168.1.50",
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "remove unknown",
+			method:             "DELETE",
+			target:             "/api/v1/admin/bans?network=10.9.9.9",
+			banError:           usecases.ErrBanNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "method not allowed",
+			method:             "PUT",
+			target:             "/api/v1/admin/bans",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBanUseCase := mocks.NewMockBanUseCase()
+			mockBanUseCase.AddBanError = tt.banError
+			mockBanUseCase.RemoveBanError = tt.banError
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mockBanUseCase, nil, "admin-token", nil)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer admin-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleBans(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d: %s", tt.expectedStatusCode, w.Code, w.Body.String())
+			}
+			switch {
+			case tt.name == "list":
+				var response dto.BansResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Bans) != 1 {
+					t.Errorf("Expected the ban list, got %s (%v)", w.Body.String(), err)
+				}
+			case tt.name == "add":
+				if request := mockBanUseCase.LastAddRequest; request.Network != "192.168.1.50" || request.Reason != "spam" {
+					t.Errorf("Expected the ban of the body, got %+v", request)
+				}
+			case tt.name == "remove":
+				if request := mockBanUseCase.LastRemoveRequest; request.Network != "192.168.1.50" {
+					t.Errorf("Expected the network of the query, got %+v", request)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_HandleBansUnauthorized(t *testing.T) {
+	mockBanUseCase := mocks.NewMockBanUseCase()
+	handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mockBanUseCase, nil, "admin-token", nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/bans", strings.NewReader(`{"network": "10.0.0.0/8"}`))
+	w := httptest.NewRecorder()
+
+	handlers.HandleBans(w, req)
+
+	if w.Code != 401 || mockBanUseCase.LastAddRequest != nil {
+		t.Errorf("Expected 401 without adding a ban, got %d", w.Code)
+	}
+}
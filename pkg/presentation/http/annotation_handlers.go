@@ -0,0 +1,35 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/annotation"
+)
+
+// AnnotationHandlers contains handlers for viewer annotations
+type AnnotationHandlers struct {
+	schema annotation.Schema
+}
+
+// NewAnnotationHandlers creates a new annotation handlers instance
+func NewAnnotationHandlers(schema annotation.Schema) *AnnotationHandlers {
+	return &AnnotationHandlers{
+		schema: schema,
+	}
+}
+
+// HandleSchema describes the stroke messages viewers send the sender over the
+// annotations data channel, so both pages draw with the same palette and limits
+func (h *AnnotationHandlers) HandleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.schema); err != nil {
+		log.Printf("Error encoding annotation schema: %v", err)
+	}
+}
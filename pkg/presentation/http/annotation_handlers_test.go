@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/annotation"
+)
+
+func TestAnnotationHandlers_HandleSchema(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "schema returned",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewAnnotationHandlers(annotation.DefaultSchema())
+
+			req := httptest.NewRequest(tt.method, "/api/v1/annotations/schema", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleSchema(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			var schema annotation.Schema
+			if err := json.NewDecoder(w.Body).Decode(&schema); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if schema.Channel != annotation.ChannelLabel || len(schema.Colors) != len(annotation.Colors) {
+				t.Errorf("Expected the annotation schema, got %+v", schema)
+			}
+		})
+	}
+}
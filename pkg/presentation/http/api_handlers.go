@@ -2,9 +2,13 @@ package http
 
 import (
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
 
+	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/domain/interfaces"
 	"share-screen/pkg/usecase/dto"
 	"share-screen/pkg/usecase/usecases"
@@ -14,13 +18,19 @@ import (
 type APIHandlers struct {
 	sessionUseCase    interfaces.SessionUseCase
 	serverInfoUseCase interfaces.ServerInfoUseCase
+
+	// tokens checks the tokens sent in request bodies; tokens in the path or
+	// query string are checked by its middleware
+	tokens *TokenFilter
 }
 
-// NewAPIHandlers creates a new API handlers instance
-func NewAPIHandlers(sessionUseCase interfaces.SessionUseCase, serverInfoUseCase interfaces.ServerInfoUseCase) *APIHandlers {
+// NewAPIHandlers creates a new API handlers instance; tokens may be nil to
+// accept tokens of any form
+func NewAPIHandlers(sessionUseCase interfaces.SessionUseCase, serverInfoUseCase interfaces.ServerInfoUseCase, tokens *TokenFilter) *APIHandlers {
 	return &APIHandlers{
 		sessionUseCase:    sessionUseCase,
 		serverInfoUseCase: serverInfoUseCase,
+		tokens:            tokens,
 	}
 }
 
@@ -32,8 +42,26 @@ func (h *APIHandlers) HandleNewToken(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := h.sessionUseCase.CreateSession()
+	var request dto.CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+		log.Printf("❌ Invalid session payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	// Scripts and bookmarks pick a template in the query, e.g. ?template=meeting
+	if template := r.URL.Query().Get("template"); template != "" {
+		request.Template = template
+	}
+	request.ClientIP = clientIP(r)
+	request.Owner = ensureSenderID(w, r)
+	request.User = userFromContext(r.Context())
+
+	response, err := h.sessionUseCase.CreateSession(&request)
 	if err != nil {
+		if err == usecases.ErrInvalidSessionName || err == usecases.ErrInvalidBitrate || err == usecases.ErrInvalidFrameRate || err == usecases.ErrInvalidCodec || err == usecases.ErrInvalidPreset || err == usecases.ErrSessionTemplateNotFound || err == usecases.ErrServerBusy {
+			writeUseCaseError(w, err)
+			return
+		}
 		log.Printf("❌ Error creating session: %v", err)
 		http.Error(w, "failed to generate token", 500)
 		return
@@ -45,7 +73,8 @@ func (h *APIHandlers) HandleNewToken(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// HandleOffer handles WebRTC offer operations (POST to store, GET to retrieve)
+// HandleOffer handles WebRTC offer operations (POST to store, GET to
+// retrieve, DELETE to reset the session to pending)
 func (h *APIHandlers) HandleOffer(w http.ResponseWriter, r *http.Request) {
 	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
 	switch r.Method {
@@ -53,6 +82,8 @@ func (h *APIHandlers) HandleOffer(w http.ResponseWriter, r *http.Request) {
 		h.handleSubmitOffer(w, r)
 	case http.MethodGet:
 		h.handleGetOffer(w, r)
+	case http.MethodDelete:
+		h.handleResetOffer(w, r)
 	default:
 		http.Error(w, "method not allowed", 405)
 	}
@@ -65,8 +96,12 @@ func (h *APIHandlers) handleSubmitOffer(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	if h.tokens.Reject(w, r, request.Token) {
+		return
+	}
 
-	log.Printf("🔴 Sender posting offer for token: %s...", request.Token[:8])
+	request.ClientIP = clientIP(r)
+	log.Printf("🔴 Sender posting offer for token: %s", entities.ShortToken(request.Token))
 
 	if err := h.sessionUseCase.SubmitOffer(&request); err != nil {
 		log.Printf("❌ Error submitting offer: %v", err)
@@ -77,17 +112,49 @@ func (h *APIHandlers) handleSubmitOffer(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(204)
 }
 
+func (h *APIHandlers) handleResetOffer(w http.ResponseWriter, r *http.Request) {
+	request := &dto.ResetOfferRequest{
+		Token:     r.URL.Query().Get("token"),
+		SenderKey: r.URL.Query().Get("senderKey"),
+		ClientIP:  clientIP(r),
+	}
+	log.Printf("🔄 Sender resetting offer for token: %s", entities.ShortToken(request.Token))
+
+	if err := h.sessionUseCase.ResetOffer(request); err != nil {
+		log.Printf("❌ Error resetting offer: %v", err)
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
 func (h *APIHandlers) handleGetOffer(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
-	log.Printf("🔵 Viewer requesting offer for token: %s...", token[:8])
+	log.Printf("🔵 Viewer requesting offer for token: %s", entities.ShortToken(token))
 
-	request := &dto.GetOfferRequest{Token: token}
+	request := &dto.GetOfferRequest{
+		Token:    token,
+		ViewerID: r.URL.Query().Get("viewer"),
+		PIN:      r.URL.Query().Get("pin"),
+		ClientIP: clientIP(r),
+	}
 	response, err := h.sessionUseCase.GetOffer(request)
 	if err != nil {
 		h.handleUseCaseError(w, err)
 		return
 	}
 
+	// The body stays a plain session description that viewers hand to WebRTC
+	if response.Paused {
+		w.Header().Set("X-Session-Paused", "true")
+	}
+	if response.ICEGeneration > 0 {
+		w.Header().Set("X-ICE-Generation", strconv.Itoa(response.ICEGeneration))
+	}
+	if response.E2EE {
+		w.Header().Set("X-Session-E2EE", "true")
+	}
 	if err := json.NewEncoder(w).Encode(response.Offer); err != nil {
 		log.Printf("Error encoding offer response: %v", err)
 		http.Error(w, "internal server error", 500)
@@ -114,8 +181,12 @@ func (h *APIHandlers) handleSubmitAnswer(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), 400)
 		return
 	}
+	if h.tokens.Reject(w, r, request.Token) {
+		return
+	}
 
-	log.Printf("🔵 Viewer posting answer for token: %s...", request.Token[:8])
+	request.ClientIP = clientIP(r)
+	log.Printf("🔵 Viewer posting answer for token: %s", entities.ShortToken(request.Token))
 
 	if err := h.sessionUseCase.SubmitAnswer(&request); err != nil {
 		log.Printf("❌ Error submitting answer: %v", err)
@@ -128,21 +199,316 @@ func (h *APIHandlers) handleSubmitAnswer(w http.ResponseWriter, r *http.Request)
 
 func (h *APIHandlers) handleGetAnswer(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
-	log.Printf("🔴 Sender requesting answer for token: %s...", token[:8])
+	log.Printf("🔴 Sender requesting answer for token: %s", entities.ShortToken(token))
 
-	request := &dto.GetAnswerRequest{Token: token}
+	request := &dto.GetAnswerRequest{Token: token, ClientIP: clientIP(r)}
 	response, err := h.sessionUseCase.GetAnswer(request)
 	if err != nil {
 		h.handleUseCaseError(w, err)
 		return
 	}
 
+	// Like the offer, the body stays a plain session description
+	if response.ViewerID != "" {
+		w.Header().Set("X-Viewer-ID", response.ViewerID)
+	}
+	// Percent-encoded, since header values are not read as UTF-8
+	if response.ViewerName != "" {
+		w.Header().Set("X-Viewer-Name", url.PathEscape(response.ViewerName))
+	}
 	if err := json.NewEncoder(w).Encode(response.Answer); err != nil {
 		log.Printf("Error encoding answer response: %v", err)
 		http.Error(w, "internal server error", 500)
 	}
 }
 
+// HandleSession describes the session in the query string, so peers can
+// show its progress in one call instead of polling the offer and answer
+func (h *APIHandlers) HandleSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	response, err := h.sessionUseCase.GetSession(&dto.GetSessionRequest{Token: r.URL.Query().Get("token")})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding session response: %v", err)
+	}
+}
+
+// HandlePublish connects the sender of the SFU session in the path to the
+// server and responds with the server's answer
+func (h *APIHandlers) HandlePublish(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.PublishStreamRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid publish payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ClientIP = clientIP(r)
+
+	response, err := h.sessionUseCase.PublishStream(&request)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(response.Answer); err != nil {
+		log.Printf("Error encoding publish response: %v", err)
+		http.Error(w, "internal server error", 500)
+	}
+}
+
+// HandleSelectLayer sets the simulcast layer a viewer of the SFU session in
+// the path receives
+func (h *APIHandlers) HandleSelectLayer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.SelectLayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid layer payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	if err := h.sessionUseCase.SelectLayer(&request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleHeartbeat records a sender heartbeat for the session in the path
+func (h *APIHandlers) HandleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	// The body is optional; older sender pages send an empty heartbeat
+	request := &dto.HeartbeatRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil && err != io.EOF {
+		log.Printf("❌ Invalid heartbeat payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	if err := h.sessionUseCase.Heartbeat(request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandleResumeSession reattaches a reloaded sender page to the session in the
+// path, given the sender key it was created with
+func (h *APIHandlers) HandleResumeSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.ResumeSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid resume payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ClientIP = clientIP(r)
+
+	response, err := h.sessionUseCase.ResumeSession(&request)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding resume response: %v", err)
+	}
+}
+
+// HandleKickViewer removes the viewer in the path from the session in the
+// path; the body carries the sender key that proves the request comes from
+// the session's sender
+func (h *APIHandlers) HandleKickViewer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.KickViewerRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid kick payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ViewerID = r.PathValue("viewer")
+	request.ClientIP = clientIP(r)
+
+	if err := h.sessionUseCase.KickViewer(&request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandleExtendSession pushes back the expiry of the session in the body; the
+// sender page calls it while it shares so long sessions are not cleaned up
+func (h *APIHandlers) HandleExtendSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.ExtendSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid extend payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if h.tokens.Reject(w, r, request.Token) {
+		return
+	}
+	request.ClientIP = clientIP(r)
+
+	response, err := h.sessionUseCase.ExtendSession(&request)
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding extend response: %v", err)
+	}
+}
+
+// HandlePause pauses or resumes the stream of the session in the body; the
+// sender page calls it when the presenter needs to hide the screen briefly
+func (h *APIHandlers) HandlePause(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.PauseSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid pause payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	if h.tokens.Reject(w, r, request.Token) {
+		return
+	}
+	request.ClientIP = clientIP(r)
+
+	if err := h.sessionUseCase.PauseSession(&request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandleOwnSessions lists the live sessions created by this browser, known by
+// its sender cookie, for the landing page
+func (h *APIHandlers) HandleOwnSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	response, err := h.sessionUseCase.ListOwnSessions(&dto.ListOwnSessionsRequest{Owner: readSenderID(r)})
+	if err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding own sessions response: %v", err)
+	}
+}
+
+// HandleRenameSession renames the session in the path; only the browser that
+// created it may
+func (h *APIHandlers) HandleRenameSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.RenameSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid rename payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.Owner = readSenderID(r)
+
+	if err := h.sessionUseCase.RenameSession(&request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandleEndSession ends the session in the path; the sender page calls it via
+// navigator.sendBeacon when it unloads, with ?resume=1 as it may be reloading
+func (h *APIHandlers) HandleEndSession(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.EndSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid end payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ClientIP = clientIP(r)
+	request.Resumable = r.URL.Query().Get("resume") == "1"
+
+	if err := h.sessionUseCase.EndSession(&request); err != nil {
+		h.handleUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
 // HandleInfo provides server information including LAN IP
 func (h *APIHandlers) HandleInfo(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -160,14 +526,60 @@ func (h *APIHandlers) HandleInfo(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleInterfaces lists the server's network interfaces, so the sender page
+// can choose which address goes in viewer links
+func (h *APIHandlers) HandleInterfaces(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := json.NewEncoder(w).Encode(h.serverInfoUseCase.GetInterfaces()); err != nil {
+		log.Printf("Error encoding interfaces response: %v", err)
+	}
+}
+
+// HandleHealth reports whether the server is healthy, answering 503 when it
+// is degraded so load balancers can take it out of rotation
+func (h *APIHandlers) HandleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+
+	health := h.serverInfoUseCase.GetHealth()
+	if health.Status != dto.HealthOK {
+		w.WriteHeader(503)
+	}
+
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Printf("Error encoding health response: %v", err)
+	}
+}
+
 // handleUseCaseError converts use case errors to appropriate HTTP responses
 func (h *APIHandlers) handleUseCaseError(w http.ResponseWriter, err error) {
+	writeUseCaseError(w, err)
+}
+
+// writeUseCaseError converts use case errors to appropriate HTTP responses
+func writeUseCaseError(w http.ResponseWriter, err error) {
 	switch err {
 	case usecases.ErrSessionNotFound:
 		http.Error(w, "session not found", 404)
 	case usecases.ErrSessionExpired:
 		http.Error(w, "session expired", 410)
-	case usecases.ErrInvalidOffer, usecases.ErrInvalidAnswer:
+	case usecases.ErrSessionEnded:
+		http.Error(w, "session ended", 410)
+	case usecases.ErrViewerLinkUsed:
+		http.Error(w, "viewer link already used", 410)
+	case usecases.ErrViewerLinkExpired:
+		http.Error(w, "viewer link expired", 410)
+	case usecases.ErrViewerLinkNotFound:
+		http.Error(w, "viewer link not found", 404)
+	case usecases.ErrViewerAliasNotFound:
+		http.Error(w, "viewer path not found", 404)
+	case usecases.ErrTooManyViewerLinks:
+		http.Error(w, "too many viewer links", 409)
+	case usecases.ErrViewerRevoked:
+		http.Error(w, "viewer removed from session", 410)
+	case usecases.ErrInvalidOffer, usecases.ErrInvalidAnswer, usecases.ErrMissingViewerID, usecases.ErrInvalidNotes, usecases.ErrInvalidQuality, usecases.ErrInvalidDevice, usecases.ErrInvalidRoomName, usecases.ErrInvalidChatMessage, usecases.ErrInvalidBitrate, usecases.ErrInvalidFrameRate, usecases.ErrInvalidCodec, usecases.ErrInvalidPreset, usecases.ErrInvalidLayer, usecases.ErrInvalidStats, usecases.ErrInvalidSessionName, usecases.ErrInvalidNegotiation, usecases.ErrInvalidViewerLink, usecases.ErrInvalidViewerName:
 		http.Error(w, err.Error(), 400)
 	case usecases.ErrOfferNotFound:
 		http.Error(w, "offer not found", 404)
@@ -177,6 +589,88 @@ func (h *APIHandlers) handleUseCaseError(w http.ResponseWriter, err error) {
 		http.Error(w, "answer already exists", 409)
 	case usecases.ErrSessionNotReady:
 		http.Error(w, "session not ready", 400)
+	case usecases.ErrSessionFull:
+		http.Error(w, "session full", 409)
+	case usecases.ErrQueueFull:
+		http.Error(w, "queue full", 429)
+	case usecases.ErrServerBusy:
+		// Sessions end or go stale within minutes
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, "server busy: too many active sessions, try again later", 429)
+	case usecases.ErrViewerNotQueued:
+		http.Error(w, "viewer not in queue", 404)
+	case usecases.ErrInvalidPIN:
+		http.Error(w, "invalid pin", 403)
+	case usecases.ErrPINLocked:
+		// The lock lasts as long as the session, so there is no Retry-After
+		http.Error(w, "too many wrong pins, ask the sender to share again", 429)
+	case usecases.ErrInvalidSenderKey:
+		http.Error(w, "invalid sender key", 403)
+	case usecases.ErrNotSessionOwner:
+		http.Error(w, "session belongs to another sender", 403)
+	case usecases.ErrTURNNotConfigured:
+		http.Error(w, "turn credentials not configured", 404)
+	case usecases.ErrRoomNotFound:
+		http.Error(w, "room not found", 404)
+	case usecases.ErrRoomTaken:
+		http.Error(w, "room belongs to another sender", 403)
+	case usecases.ErrChatDisabled:
+		http.Error(w, "chat not enabled", 404)
+	case usecases.ErrFileRelayDisabled:
+		http.Error(w, "file relay not enabled", 404)
+	case usecases.ErrFileTooLarge:
+		http.Error(w, "file too large", 413)
+	case usecases.ErrFileNotFound:
+		http.Error(w, "file not found", 404)
+	case usecases.ErrSFUDisabled:
+		http.Error(w, "sfu not enabled", 404)
+	case usecases.ErrSFUOfferReset:
+		http.Error(w, "sfu senders publish again instead of resetting", 409)
+	case usecases.ErrStaleAnswer:
+		http.Error(w, "answer to an offer replaced by an ICE restart", 409)
+	case usecases.ErrViewerNotConnected:
+		http.Error(w, "viewer not connected", 404)
+	case usecases.ErrFramesUnavailable:
+		http.Error(w, err.Error(), 404)
+	case usecases.ErrRecordingsDisabled:
+		http.Error(w, "recordings not enabled", 404)
+	case usecases.ErrRecordingNotFound:
+		http.Error(w, "recording not found", 404)
+	case usecases.ErrInvitesDisabled:
+		http.Error(w, "invites not enabled", 404)
+	case usecases.ErrInviteAlreadyPosted:
+		http.Error(w, "invite already posted", 409)
+	case usecases.ErrInviteNotPosted:
+		// The chat service failed; the server log says why
+		http.Error(w, "invite could not be posted", 502)
+	case usecases.ErrInvalidBan, usecases.ErrBanLoopback:
+		http.Error(w, err.Error(), 400)
+	case usecases.ErrBanNotFound:
+		http.Error(w, "ban not found", 404)
+	case usecases.ErrSessionTemplateNotFound:
+		http.Error(w, "session template not found", 404)
+	case usecases.ErrInvalidSessionTemplate:
+		http.Error(w, err.Error(), 400)
+	case usecases.ErrProfilesDisabled:
+		http.Error(w, "sender profiles not enabled", 404)
+	case usecases.ErrInvalidSenderProfile:
+		http.Error(w, err.Error(), 400)
+	case usecases.ErrPairingUnavailable:
+		http.Error(w, err.Error(), 409)
+	case usecases.ErrInvalidPairing:
+		http.Error(w, err.Error(), 400)
+	case usecases.ErrPairingCodeNotFound:
+		http.Error(w, "pairing code not found", 404)
+	case usecases.ErrDeviceNotTrusted:
+		http.Error(w, "device not trusted", 403)
+	case usecases.ErrTrustedDeviceNotFound:
+		http.Error(w, "trusted device not found", 404)
+	case usecases.ErrE2EEDisabled:
+		http.Error(w, "end-to-end encryption not enabled", 404)
+	case usecases.ErrInvalidKeyMessage:
+		http.Error(w, err.Error(), 400)
+	case usecases.ErrFingerprintsUnavailable:
+		http.Error(w, err.Error(), 404)
 	default:
 		log.Printf("Unexpected error: %v", err)
 		http.Error(w, "internal server error", 500)
@@ -3,11 +3,13 @@ package http
 import (
 	"bytes"
 	"encoding/json"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
 	"share-screen/test/mocks"
 )
 
@@ -16,6 +18,7 @@ func TestAPIHandlers_HandleNewToken(t *testing.T) {
 		name                  string
 		method                string
 		shouldFailCreate      bool
+		createError           error
 		expectedStatusCode    int
 		expectTokenInResponse bool
 	}{
@@ -40,6 +43,20 @@ func TestAPIHandlers_HandleNewToken(t *testing.T) {
 			expectedStatusCode:    500,
 			expectTokenInResponse: false,
 		},
+		{
+			name:                  "server busy",
+			method:                "POST",
+			createError:           usecases.ErrServerBusy,
+			expectedStatusCode:    429,
+			expectTokenInResponse: false,
+		},
+		{
+			name:                  "unknown template",
+			method:                "POST",
+			createError:           usecases.ErrSessionTemplateNotFound,
+			expectedStatusCode:    404,
+			expectTokenInResponse: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -48,8 +65,9 @@ func TestAPIHandlers_HandleNewToken(t *testing.T) {
 			mockSessionUseCase := mocks.NewMockSessionUseCase()
 			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
 			mockSessionUseCase.ShouldFailCreateSession = tt.shouldFailCreate
+			mockSessionUseCase.CreateSessionError = tt.createError
 
-			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
 			// Create request
 			req := httptest.NewRequest(tt.method, "/api/new", nil)
@@ -62,6 +80,9 @@ func TestAPIHandlers_HandleNewToken(t *testing.T) {
 			if w.Code != tt.expectedStatusCode {
 				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
 			}
+			if w.Code == 429 && w.Header().Get("Retry-After") == "" {
+				t.Error("Expected a Retry-After header when the server is busy")
+			}
 
 			if tt.expectTokenInResponse {
 				var response dto.CreateSessionResponse
@@ -123,7 +144,7 @@ func TestAPIHandlers_HandleOffer_POST(t *testing.T) {
 			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
 			mockSessionUseCase.ShouldFailSubmitOffer = tt.shouldFailSubmit
 
-			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
 			// Create request body
 			var bodyBytes []byte
@@ -174,6 +195,13 @@ func TestAPIHandlers_HandleOffer_GET(t *testing.T) {
 			expectedStatusCode:    500,
 			expectOfferInResponse: false,
 		},
+		{
+			name:                  "token shorter than the logged prefix",
+			token:                 "",
+			shouldFailGet:         false,
+			expectedStatusCode:    200,
+			expectOfferInResponse: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -183,7 +211,7 @@ func TestAPIHandlers_HandleOffer_GET(t *testing.T) {
 			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
 			mockSessionUseCase.ShouldFailGetOffer = tt.shouldFailGet
 
-			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
 			// Create request
 			req := httptest.NewRequest("GET", "/api/offer?token="+tt.token, nil)
@@ -211,6 +239,75 @@ func TestAPIHandlers_HandleOffer_GET(t *testing.T) {
 	}
 }
 
+func TestAPIHandlers_HandleOffer_GETPaused(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	mockSessionUseCase.GetOfferResponse.Paused = true
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("GET", "/api/offer?token=test-token", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleOffer(w, req)
+
+	if w.Code != 200 || w.Header().Get("X-Session-Paused") != "true" {
+		t.Errorf("Expected the offer flagged as paused, got %d with headers %v", w.Code, w.Header())
+	}
+}
+
+func TestAPIHandlers_HandleOffer_GETICEGeneration(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	mockSessionUseCase.GetOfferResponse.ICEGeneration = 2
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("GET", "/api/offer?token=test-token", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleOffer(w, req)
+
+	if w.Code != 200 || w.Header().Get("X-ICE-Generation") != "2" {
+		t.Errorf("Expected the offer's ICE generation in a header, got %d with headers %v", w.Code, w.Header())
+	}
+}
+
+func TestAPIHandlers_HandleOffer_GETE2EE(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	mockSessionUseCase.GetOfferResponse.E2EE = true
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("GET", "/api/offer?token=test-token", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleOffer(w, req)
+
+	if w.Code != 200 || w.Header().Get("X-Session-E2EE") != "true" {
+		t.Errorf("Expected the offer flagged as end-to-end encrypted, got %d with headers %v", w.Code, w.Header())
+	}
+}
+
+func TestAPIHandlers_HandleOffer_DELETE(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("DELETE", "/api/offer?token=test-token&senderKey=sender-key", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleOffer(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status code 204 but got %d", w.Code)
+	}
+	if request := mockSessionUseCase.LastResetOfferRequest; request == nil || request.Token != "test-token" || request.SenderKey != "sender-key" {
+		t.Errorf("Expected the offer of test-token reset with the sender key, got %+v", request)
+	}
+
+	mockSessionUseCase.ShouldFailResetOffer = true
+	w = httptest.NewRecorder()
+	handlers.HandleOffer(w, req)
+	if w.Code != 500 {
+		t.Errorf("Expected status code 500 but got %d", w.Code)
+	}
+}
+
 func TestAPIHandlers_HandleAnswer_POST(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -257,7 +354,7 @@ func TestAPIHandlers_HandleAnswer_POST(t *testing.T) {
 			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
 			mockSessionUseCase.ShouldFailSubmitAnswer = tt.shouldFailSubmit
 
-			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
 			// Create request body
 			var bodyBytes []byte
@@ -286,6 +383,22 @@ func TestAPIHandlers_HandleAnswer_POST(t *testing.T) {
 	}
 }
 
+func TestAPIHandlers_HandleAnswer_GETViewerName(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	mockSessionUseCase.GetAnswerResponse.ViewerID = "viewer-1"
+	mockSessionUseCase.GetAnswerResponse.ViewerName = "Ari’s iPhone"
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("GET", "/api/answer?token=test-token", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleAnswer(w, req)
+
+	if w.Code != 200 || w.Header().Get("X-Viewer-Name") != "Ari%E2%80%99s%20iPhone" {
+		t.Errorf("Expected the viewer's name percent-encoded in a header, got %d with headers %v", w.Code, w.Header())
+	}
+}
+
 func TestAPIHandlers_HandleInfo(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -317,7 +430,7 @@ func TestAPIHandlers_HandleInfo(t *testing.T) {
 			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
 			mockServerInfoUseCase.ShouldFailGetServerInfo = tt.shouldFailGet
 
-			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+			handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
 			// Create request
 			req := httptest.NewRequest("GET", "/api/info", nil)
@@ -346,12 +459,400 @@ func TestAPIHandlers_HandleInfo(t *testing.T) {
 	}
 }
 
+func TestAPIHandlers_HandleResumeSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "session resumed",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "resume failed",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailResumeSession = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/resume", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleResumeSession(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var response dto.CreateSessionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Token != "test-token" || response.SenderKey != "sender-key" {
+				t.Errorf("Expected the path token with the sender key, got %s (%v)", w.Body.String(), err)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_SenderCookie(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	w := httptest.NewRecorder()
+	handlers.HandleNewToken(w, httptest.NewRequest("POST", "/api/v1/new", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != senderCookie || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HttpOnly sender cookie, got %+v", cookies)
+	}
+	id := cookies[0].Value
+	if mockSessionUseCase.LastCreateRequest.Owner != id {
+		t.Errorf("Expected the session created for sender %q, got %q", id, mockSessionUseCase.LastCreateRequest.Owner)
+	}
+
+	// A browser that has the cookie keeps its ID
+	req := httptest.NewRequest("POST", "/api/v1/new", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	handlers.HandleNewToken(w, req)
+	if len(w.Result().Cookies()) != 0 || mockSessionUseCase.LastCreateRequest.Owner != id {
+		t.Errorf("Expected the existing sender ID reused, got owner %q", mockSessionUseCase.LastCreateRequest.Owner)
+	}
+
+	req = httptest.NewRequest("GET", "/api/v1/sender/sessions", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	handlers.HandleOwnSessions(w, req)
+	var response dto.OwnSessionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Sessions) != 1 {
+		t.Errorf("Expected the sender's session, got %s (%v)", w.Body.String(), err)
+	}
+
+	// Cookies the server did not make are ignored
+	req = httptest.NewRequest("GET", "/api/v1/sender/sessions", nil)
+	req.AddCookie(&http.Cookie{Name: senderCookie, Value: "guess"})
+	w = httptest.NewRecorder()
+	handlers.HandleOwnSessions(w, req)
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || len(response.Sessions) != 0 {
+		t.Errorf("Expected no sessions for a forged cookie, got %s (%v)", w.Body.String(), err)
+	}
+}
+
+func TestAPIHandlers_HandleRenameSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "session renamed",
+			method:             "POST",
+			body:               `{"name":"Design review"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "rename failed",
+			method:             "POST",
+			body:               `{"name":"Design review"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailRename = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/rename", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleRenameSession(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleExtendSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "session extended",
+			method:             "POST",
+			body:               `{"token":"test-token"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "extend failed",
+			method:             "POST",
+			body:               `{"token":"test-token"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailExtendSession = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/session/extend", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handlers.HandleExtendSession(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var response dto.ExtendSessionResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.ExpiresInSeconds != 1800 {
+				t.Errorf("Expected the new expiry, got %s (%v)", w.Body.String(), err)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "session described",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "lookup failed",
+			method:             "GET",
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailGetSession = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/session?token=test-token", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleSession(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var response dto.SessionDetailResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Token != "test-token" || response.Status != entities.SessionStatusPending {
+				t.Errorf("Expected the session's details, got %s (%v)", w.Body.String(), err)
+			}
+			if w.Header().Get("Cache-Control") != "no-store" {
+				t.Errorf("Expected the details not cached, got %q", w.Header().Get("Cache-Control"))
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandlePause(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "stream paused",
+			method:             "POST",
+			body:               `{"token":"test-token","paused":true}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "pause failed",
+			method:             "POST",
+			body:               `{"token":"test-token","paused":false}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailPauseSession = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/pause", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handlers.HandlePause(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleHealth(t *testing.T) {
+	tests := []struct {
+		name               string
+		health             *dto.HealthResponse
+		expectedStatusCode int
+	}{
+		{
+			name:               "healthy",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "degraded",
+			health:             &dto.HealthResponse{Status: dto.HealthDegraded},
+			expectedStatusCode: 503,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
+			mockServerInfoUseCase.Health = tt.health
+			handlers := NewAPIHandlers(mocks.NewMockSessionUseCase(), mockServerInfoUseCase, nil)
+
+			req := httptest.NewRequest("GET", "/api/v1/health", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleHealth(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			var health dto.HealthResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil || health.Status == "" {
+				t.Errorf("Expected a health report, got %s (%v)", w.Body.String(), err)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleInterfaces(t *testing.T) {
+	mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
+	mockServerInfoUseCase.Interfaces = &dto.InterfacesResponse{
+		Interfaces: []entities.NetworkInterface{
+			{Name: "en0", Addresses: []string{"192.168.1.10"}, Selected: true},
+			{Name: "docker0", Addresses: []string{"172.17.0.1"}},
+		},
+		Configured: "en0",
+	}
+	handlers := NewAPIHandlers(mocks.NewMockSessionUseCase(), mockServerInfoUseCase, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/interfaces", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleInterfaces(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200 but got %d", w.Code)
+	}
+	var response dto.InterfacesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Interfaces) != 2 || !response.Interfaces[0].Selected || response.Configured != "en0" {
+		t.Errorf("Expected the interfaces with en0 selected, got %+v", response)
+	}
+}
+
 func TestAPIHandlers_HandleOffer_MethodNotAllowed(t *testing.T) {
 	mockSessionUseCase := mocks.NewMockSessionUseCase()
 	mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
-	handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase)
+	handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
 
-	req := httptest.NewRequest("DELETE", "/api/offer", nil)
+	req := httptest.NewRequest("PUT", "/api/offer", nil)
 	w := httptest.NewRecorder()
 
 	handlers.HandleOffer(w, req)
@@ -360,3 +861,311 @@ func TestAPIHandlers_HandleOffer_MethodNotAllowed(t *testing.T) {
 		t.Errorf("Expected status code 405 but got %d", w.Code)
 	}
 }
+
+func TestAPIHandlers_HandleNewToken_WithOptions(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	mockServerInfoUseCase := mocks.NewMockServerInfoUseCase()
+	handlers := NewAPIHandlers(mockSessionUseCase, mockServerInfoUseCase, nil)
+
+	body := []byte(`{"name":"Design review","disablePreview":true}`)
+	req := httptest.NewRequest("POST", "/api/new", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handlers.HandleNewToken(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200 but got %d", w.Code)
+	}
+	if mockSessionUseCase.LastCreateRequest == nil {
+		t.Fatal("Expected create request to be passed to use case")
+	}
+	if mockSessionUseCase.LastCreateRequest.Name != "Design review" {
+		t.Errorf("Expected name %q but got %q", "Design review", mockSessionUseCase.LastCreateRequest.Name)
+	}
+	if !mockSessionUseCase.LastCreateRequest.DisablePreview {
+		t.Error("Expected disablePreview to be passed through")
+	}
+
+	// A template in the query wins over one in the body
+	req = httptest.NewRequest("POST", "/api/new?template=meeting", bytes.NewReader([]byte(`{"template":"demo"}`)))
+	w = httptest.NewRecorder()
+
+	handlers.HandleNewToken(w, req)
+
+	if w.Code != 200 || mockSessionUseCase.LastCreateRequest.Template != "meeting" {
+		t.Errorf("Expected the meeting template, got %d and %q", w.Code, mockSessionUseCase.LastCreateRequest.Template)
+	}
+
+	req = httptest.NewRequest("POST", "/api/new", bytes.NewReader([]byte("not-json")))
+	w = httptest.NewRecorder()
+
+	handlers.HandleNewToken(w, req)
+
+	if w.Code != 400 {
+		t.Errorf("Expected status code 400 but got %d", w.Code)
+	}
+}
+
+func TestAPIHandlers_HandleEndSession(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		endErr             error
+		expectedStatusCode int
+	}{
+		{
+			name:               "successful end",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               "not json",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "viewer token alone",
+			method:             "POST",
+			body:               `{}`,
+			endErr:             usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "failed end",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailEndSession = tt.shouldFail
+			mockSessionUseCase.EndSessionError = tt.endErr
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/end", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleEndSession(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode == 204 && mockSessionUseCase.LastEndSessionRequest.SenderKey != "sender-key" {
+				t.Errorf("Expected the sender key passed on, got %+v", mockSessionUseCase.LastEndSessionRequest)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleHeartbeat(t *testing.T) {
+	mockSessionUseCase := mocks.NewMockSessionUseCase()
+	handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+	req := httptest.NewRequest("POST", "/api/sessions/test-token/heartbeat", nil)
+	req.SetPathValue("token", "test-token")
+	w := httptest.NewRecorder()
+
+	handlers.HandleHeartbeat(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status code 204 but got %d", w.Code)
+	}
+}
+
+func TestAPIHandlers_HandlePublish(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "stream published",
+			method:             "POST",
+			body:               `{"sdp":{"type":"offer","sdp":"test-sdp"}}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid JSON",
+			method:             "POST",
+			body:               `{"sdp":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "failed publish",
+			method:             "POST",
+			body:               `{"sdp":{"type":"offer","sdp":"test-sdp"}}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailPublish = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/publish", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandlePublish(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var answer entities.WebRTCAnswer
+			if err := json.Unmarshal(w.Body.Bytes(), &answer); err != nil {
+				t.Fatalf("Failed to decode answer: %v", err)
+			}
+			if answer.SDP != "mock-sfu-sdp" {
+				t.Errorf("Expected the relay's answer, got %+v", answer)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleSelectLayer(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "layer picked",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1","layer":"low"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid JSON",
+			method:             "POST",
+			body:               `{"layer":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "failed selection",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1","layer":"low"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailSelectLayer = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/layer", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleSelectLayer(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 204 {
+				return
+			}
+			request := mockSessionUseCase.LastSelectLayerRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.Layer != entities.LayerLow {
+				t.Errorf("Expected the path token and body to reach the use case, got %+v", request)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_HandleKickViewer(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		shouldFail         bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "viewer removed",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid JSON",
+			method:             "POST",
+			body:               `{"senderKey":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "removal failed",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			shouldFail:         true,
+			expectedStatusCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ShouldFailKickViewer = tt.shouldFail
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/viewers/viewer-1/kick", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			req.SetPathValue("viewer", "viewer-1")
+			w := httptest.NewRecorder()
+
+			handlers.HandleKickViewer(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 204 {
+				return
+			}
+			request := mockSessionUseCase.LastKickViewerRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.SenderKey != "sender-key" || request.Admin {
+				t.Errorf("Expected the path and sender key to reach the use case, got %+v", request)
+			}
+		})
+	}
+}
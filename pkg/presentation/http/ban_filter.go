@@ -0,0 +1,34 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// BanFilter keeps banned clients away from the signaling endpoints. Behind a
+// reverse proxy the proxy's address is what gets checked, as for the
+// network policy.
+type BanFilter struct {
+	banUseCase interfaces.BanUseCase
+}
+
+// NewBanFilter creates a new ban filter
+func NewBanFilter(banUseCase interfaces.BanUseCase) *BanFilter {
+	return &BanFilter{
+		banUseCase: banUseCase,
+	}
+}
+
+// Wrap rejects requests from banned clients with 403 before they reach next
+func (f *BanFilter) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if f.banUseCase.IsBanned(clientIP(r)) {
+			log.Printf("🚫 Signaling request %s %s refused from banned %s", r.Method, r.URL.Path, r.RemoteAddr)
+			http.Error(w, "forbidden: banned", 403)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/test/mocks"
+)
+
+func TestBanFilter(t *testing.T) {
+	mockBanUseCase := mocks.NewMockBanUseCase()
+	mockBanUseCase.Banned = []string{"192.168.1.50"}
+	filter := NewBanFilter(mockBanUseCase)
+
+	tests := []struct {
+		name               string
+		remoteAddr         string
+		expectedStatusCode int
+	}{
+		{
+			name:               "banned client",
+			remoteAddr:         "192.168.1.50:51000",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "other client",
+			remoteAddr:         "192.168.1.51:51000",
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := filter.Wrap(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			req := httptest.NewRequest("POST", "/api/v1/new", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if called != (tt.expectedStatusCode == 200) {
+				t.Errorf("Expected the wrapped handler to run only for allowed clients, ran: %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBasicAuth(t *testing.T) {
+	tests := []struct {
+		name               string
+		user               string
+		password           string
+		requestUser        string
+		requestPassword    string
+		withCredentials    bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "disabled without a user",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "right credentials",
+			user:               "presenter",
+			password:           "s3cret",
+			requestUser:        "presenter",
+			requestPassword:    "s3cret",
+			withCredentials:    true,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "no credentials",
+			user:               "presenter",
+			password:           "s3cret",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "wrong password",
+			user:               "presenter",
+			password:           "s3cret",
+			requestUser:        "presenter",
+			requestPassword:    "guess",
+			withCredentials:    true,
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "wrong user",
+			user:               "presenter",
+			password:           "s3cret",
+			requestUser:        "viewer",
+			requestPassword:    "s3cret",
+			withCredentials:    true,
+			expectedStatusCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth, err := NewBasicAuth(tt.user, tt.password)
+			if err != nil {
+				t.Fatalf("NewBasicAuth failed: %v", err)
+			}
+			handler := auth.Wrap(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+			})
+
+			req := httptest.NewRequest("GET", "/sender", nil)
+			if tt.withCredentials {
+				req.SetBasicAuth(tt.requestUser, tt.requestPassword)
+			}
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code == 401 && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate challenge so browsers prompt for credentials")
+			}
+		})
+	}
+}
+
+func TestNewBasicAuth_RequiresPassword(t *testing.T) {
+	if _, err := NewBasicAuth("presenter", ""); err == nil {
+		t.Error("Expected an error for a user without a password")
+	}
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// CapabilitiesHandlers contains handlers describing the deployment's optional subsystems
+type CapabilitiesHandlers struct {
+	capabilitiesUseCase interfaces.CapabilitiesUseCase
+}
+
+// NewCapabilitiesHandlers creates a new capabilities handlers instance
+func NewCapabilitiesHandlers(capabilitiesUseCase interfaces.CapabilitiesUseCase) *CapabilitiesHandlers {
+	return &CapabilitiesHandlers{
+		capabilitiesUseCase: capabilitiesUseCase,
+	}
+}
+
+// HandleCapabilities reports which optional subsystems work on this
+// deployment, so clients hide controls that would fail when clicked
+func (h *CapabilitiesHandlers) HandleCapabilities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.capabilitiesUseCase.GetCapabilities()); err != nil {
+		log.Printf("Error encoding capabilities response: %v", err)
+	}
+}
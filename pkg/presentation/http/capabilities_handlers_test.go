@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestCapabilitiesHandlers_HandleCapabilities(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "capabilities returned",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewCapabilitiesHandlers(mocks.NewMockCapabilitiesUseCase())
+
+			req := httptest.NewRequest(tt.method, "/api/v1/capabilities", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleCapabilities(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			var response entities.Capabilities
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !response.TURN.Enabled || response.SFU.Enabled {
+				t.Errorf("Unexpected capabilities %+v", response)
+			}
+			if response.AuthProvider != entities.AuthProviderNone {
+				t.Errorf("Expected auth provider %q, got %q", entities.AuthProviderNone, response.AuthProvider)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// ChatHandlers contains handlers for the chat relay used before the peers'
+// data channel is open
+type ChatHandlers struct {
+	chatUseCase interfaces.ChatUseCase
+}
+
+// NewChatHandlers creates a new chat handlers instance
+func NewChatHandlers(chatUseCase interfaces.ChatUseCase) *ChatHandlers {
+	return &ChatHandlers{
+		chatUseCase: chatUseCase,
+	}
+}
+
+// HandleChat handles chat relay operations (POST to send, GET to list the
+// messages after ?since=)
+func (h *ChatHandlers) HandleChat(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handleSendMessage(w, r)
+	case http.MethodGet:
+		h.handleGetMessages(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *ChatHandlers) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var request dto.SendChatMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid chat payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	message, err := h.chatUseCase.SendMessage(&request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(message); err != nil {
+		log.Printf("Error encoding chat message: %v", err)
+	}
+}
+
+func (h *ChatHandlers) handleGetMessages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dto.GetChatMessagesRequest{
+		Token: query.Get("token"),
+		PIN:   query.Get("pin"),
+	}
+	if since := query.Get("since"); since != "" {
+		var err error
+		if request.Since, err = strconv.Atoi(since); err != nil || request.Since < 0 {
+			http.Error(w, "invalid since", 400)
+			return
+		}
+	}
+
+	response, err := h.chatUseCase.GetMessages(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// Pages poll this until their data channel opens
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding chat messages: %v", err)
+	}
+}
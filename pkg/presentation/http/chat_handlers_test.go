@@ -0,0 +1,124 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestChatHandlers_HandleChat(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		useCaseError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "send message",
+			method:             "POST",
+			url:                "/api/chat",
+			body:               `{"token":"test-token","from":"viewer","text":"hello"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			url:                "/api/chat",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid message",
+			method:             "POST",
+			url:                "/api/chat",
+			body:               `{"token":"test-token","from":"viewer","text":""}`,
+			useCaseError:       usecases.ErrInvalidChatMessage,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "chat not enabled",
+			method:             "POST",
+			url:                "/api/chat",
+			body:               `{"token":"test-token","from":"viewer","text":"hello"}`,
+			useCaseError:       usecases.ErrChatDisabled,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "list messages",
+			method:             "GET",
+			url:                "/api/chat?token=test-token&since=1&pin=123456",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid since",
+			method:             "GET",
+			url:                "/api/chat?token=test-token&since=later",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "session ended",
+			method:             "GET",
+			url:                "/api/chat?token=test-token",
+			useCaseError:       usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			url:                "/api/chat",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockChatUseCase := mocks.NewMockChatUseCase()
+			mockChatUseCase.SendMessageError = tt.useCaseError
+			mockChatUseCase.GetMessagesError = tt.useCaseError
+			handlers := NewChatHandlers(mockChatUseCase)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handlers.HandleChat(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if tt.method == "POST" {
+				var message entities.ChatMessage
+				if err := json.NewDecoder(w.Body).Decode(&message); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if message.Text != "hello" || message.From != entities.ChatFromViewer {
+					t.Errorf("Unexpected message: %+v", message)
+				}
+				return
+			}
+
+			request := mockChatUseCase.LastGetRequest
+			if request.Token != "test-token" || request.Since != 1 || request.PIN != "123456" {
+				t.Errorf("Unexpected request: %+v", request)
+			}
+			var response dto.ChatMessagesResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Messages) != 1 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
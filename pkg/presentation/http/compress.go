@@ -0,0 +1,273 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// minCompressSize is the smallest body worth compressing; below it the
+// encoder's header and a lost write outweigh the saved bytes
+const minCompressSize = 1 << 10
+
+// compressibleTypes are the media types of pages, scripts, styles and API
+// responses. Video, JPEG snapshots, recordings and event streams are left
+// alone: they are compressed already or must reach the client as written.
+var compressibleTypes = map[string]bool{
+	"text/html":                 true,
+	"text/css":                  true,
+	"text/javascript":           true,
+	"text/plain":                true,
+	"application/javascript":    true,
+	"application/json":          true,
+	"application/manifest+json": true,
+	"image/svg+xml":             true,
+}
+
+// encoder is a compressor that can be reset onto the next response;
+// gzip.Writer and brotli.Writer both are
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoders are the content codings the middleware produces, preferred first:
+// brotli makes scripts and pages smaller than gzip, which stays for the
+// clients without brotli. Each pool reuses compressors
+// across responses, as each holds a few hundred kilobytes of state.
+var encoders = []struct {
+	coding string
+	pool   *sync.Pool
+}{
+	{coding: "br", pool: &sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(nil, brotli.DefaultCompression)
+	}}},
+	{coding: "gzip", pool: &sync.Pool{New: func() any {
+		writer, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return writer
+	}}},
+}
+
+// Compress is the middleware that compresses pages, scripts, styles and JSON
+// with brotli or gzip for clients that accept either, which speeds up the
+// first load of the viewer page on a phone over weak Wi-Fi
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		// Ranges are of the file as stored, so those responses stay as is
+		if r.Method == http.MethodHead || r.Header.Get("Range") != "" || encoding < 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding returns the index in encoders of the coding an
+// Accept-Encoding header rates highest, named directly or through *, or -1
+// when it accepts none of them. Equal ratings go to the preferred coding.
+func negotiateEncoding(header string) int {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "x-gzip" {
+			coding = "gzip"
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		qualities[coding] = q
+	}
+
+	best, bestQ := -1, 0.0
+	for i, candidate := range encoders {
+		// An explicit coding;q=0 refuses it even when * is accepted
+		q, ok := qualities[candidate.coding]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	return best
+}
+
+// compressWriter holds back the start of a response until it knows whether
+// to compress it: the type must be compressible and the body large enough. It
+// keeps event streams and WebSocket upgrades working through Flush, Hijack
+// and Unwrap.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding int
+	status   int
+	buf      []byte
+	enc      encoder
+	decided  bool
+}
+
+// WriteHeader implements http.ResponseWriter; the status is sent once the
+// encoding is decided
+func (c *compressWriter) WriteHeader(status int) {
+	if c.decided || c.status != 0 {
+		if c.decided && c.enc == nil {
+			c.ResponseWriter.WriteHeader(status)
+		}
+		return
+	}
+	// Informational responses do not end the header
+	if status < 200 {
+		c.ResponseWriter.WriteHeader(status)
+		return
+	}
+	c.status = status
+	if !c.compressible() {
+		c.decide(false)
+	}
+}
+
+// Write implements http.ResponseWriter
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.decided {
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(p))
+			if !c.compressible() {
+				c.decide(false)
+				return c.ResponseWriter.Write(p)
+			}
+		}
+		c.buf = append(c.buf, p...)
+		if len(c.buf) < minCompressSize {
+			return len(p), nil
+		}
+		if err := c.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if c.enc != nil {
+		return c.enc.Write(p)
+	}
+	return c.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher; a flushed response is sent compressed if it
+// already had enough to compress
+func (c *compressWriter) Flush() {
+	if c.status == 0 && !c.decided {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.decided {
+		_ = c.decide(len(c.buf) >= minCompressSize)
+	}
+	if c.enc != nil {
+		_ = c.enc.Flush()
+	}
+	if flusher, ok := c.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for WebSocket upgrades, which write nothing
+// through this writer
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	c.decided = true
+	return http.NewResponseController(c.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// Close sends what is held back, compressed only when it reached the minimum
+// size, and ends the compressed stream
+func (c *compressWriter) Close() {
+	if !c.decided {
+		if c.status == 0 {
+			// Handlers that write nothing still answer 200
+			c.status = http.StatusOK
+		}
+		_ = c.decide(len(c.buf) >= minCompressSize)
+	}
+	if c.enc != nil {
+		_ = c.enc.Close()
+		c.enc.Reset(nil)
+		encoders[c.encoding].pool.Put(c.enc)
+		c.enc = nil
+	}
+}
+
+// compressible reports whether the response so far may be compressed: a
+// status with a body, a compressible type, no encoding of its own and no
+// known length below the minimum
+func (c *compressWriter) compressible() bool {
+	header := c.Header()
+	if c.status == http.StatusNoContent || c.status == http.StatusNotModified || c.status == http.StatusPartialContent {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil && length < minCompressSize {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		// Not known until the first write sniffs it
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && compressibleTypes[mediaType]
+}
+
+// decide writes the header, with the negotiated encoding if compress, and
+// then whatever was held back
+func (c *compressWriter) decide(compress bool) error {
+	c.decided = true
+	header := c.Header()
+	if c.compressible() {
+		// Caches must not hand the compressed body to clients without its coding
+		header.Add("Vary", "Accept-Encoding")
+	}
+	if compress {
+		header.Set("Content-Encoding", encoders[c.encoding].coding)
+		header.Del("Content-Length")
+		c.enc = encoders[c.encoding].pool.Get().(encoder)
+		c.enc.Reset(c.ResponseWriter)
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if c.enc != nil {
+		_, err = c.enc.Write(buf)
+	} else {
+		_, err = c.ResponseWriter.Write(buf)
+	}
+	return err
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestCompress(t *testing.T) {
+	page := "<!doctype html><title>Viewer</title>" + strings.Repeat("<p>screen</p>", 200)
+	tests := []struct {
+		name             string
+		acceptEncoding   string
+		header           map[string]string
+		status           int
+		body             string
+		expectedEncoding string
+		expectedVary     bool
+	}{
+		{
+			name:             "page",
+			acceptEncoding:   "gzip, deflate, br",
+			header:           map[string]string{"Content-Type": "text/html; charset=utf-8"},
+			body:             page,
+			expectedEncoding: "br",
+			expectedVary:     true,
+		},
+		{
+			name:             "gzip preferred by the client",
+			acceptEncoding:   "br;q=0.5, gzip",
+			header:           map[string]string{"Content-Type": "text/html; charset=utf-8"},
+			body:             page,
+			expectedEncoding: "gzip",
+			expectedVary:     true,
+		},
+		{
+			name:             "sniffed page",
+			acceptEncoding:   "gzip",
+			body:             page,
+			expectedEncoding: "gzip",
+			expectedVary:     true,
+		},
+		{
+			name:             "JSON error",
+			acceptEncoding:   "gzip",
+			header:           map[string]string{"Content-Type": "application/json"},
+			status:           http.StatusNotFound,
+			body:             `{"items":[` + strings.Repeat(`"x",`, 400) + `"x"]}`,
+			expectedEncoding: "gzip",
+			expectedVary:     true,
+		},
+		{
+			name:           "small body",
+			acceptEncoding: "gzip",
+			header:         map[string]string{"Content-Type": "application/json"},
+			body:           `{"ok":true}`,
+			expectedVary:   true,
+		},
+		{
+			name:             "gzip not accepted",
+			acceptEncoding:   "br, gzip;q=0",
+			header:           map[string]string{"Content-Type": "text/html"},
+			body:             page,
+			expectedEncoding: "br",
+			expectedVary:     true,
+		},
+		{
+			name:           "neither accepted",
+			acceptEncoding: "deflate, br;q=0",
+			header:         map[string]string{"Content-Type": "text/html"},
+			body:           page,
+		},
+		{
+			name:   "no Accept-Encoding",
+			header: map[string]string{"Content-Type": "text/html"},
+			body:   page,
+		},
+		{
+			name:           "video",
+			acceptEncoding: "gzip",
+			header:         map[string]string{"Content-Type": "video/mp4"},
+			body:           page,
+		},
+		{
+			name:           "already encoded",
+			acceptEncoding: "gzip",
+			header:         map[string]string{"Content-Type": "text/html", "Content-Encoding": "br"},
+			body:           page,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for name, value := range tt.header {
+					w.Header().Set(name, value)
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				// Written in pieces, like templates are
+				for i := 0; i < len(tt.body); i += 100 {
+					_, _ = io.WriteString(w, tt.body[i:min(i+100, len(tt.body))])
+				}
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if expected := max(tt.status, http.StatusOK); w.Code != expected {
+				t.Errorf("Expected status %d, got %d", expected, w.Code)
+			}
+			if vary := w.Header().Get("Vary") == "Accept-Encoding"; vary != tt.expectedVary {
+				t.Errorf("Expected Vary %v, got %q", tt.expectedVary, w.Header().Get("Vary"))
+			}
+			body := w.Body.String()
+			// A handler's own encoding is passed through
+			expectedHeader := tt.expectedEncoding
+			if expectedHeader == "" {
+				expectedHeader = tt.header["Content-Encoding"]
+			}
+			if encoding := w.Header().Get("Content-Encoding"); encoding != expectedHeader {
+				t.Fatalf("Expected Content-Encoding %q, got %q", expectedHeader, encoding)
+			}
+			if tt.expectedEncoding != "" {
+				var reader io.Reader = brotli.NewReader(w.Body)
+				if tt.expectedEncoding == "gzip" {
+					var err error
+					if reader, err = gzip.NewReader(w.Body); err != nil {
+						t.Fatalf("Expected a gzip body: %v", err)
+					}
+				}
+				plain, err := io.ReadAll(reader)
+				if err != nil {
+					t.Fatalf("Failed to decompress: %v", err)
+				}
+				if len(body) >= len(tt.body) {
+					t.Errorf("Expected the body smaller than %d bytes, got %d", len(tt.body), len(body))
+				}
+				body = string(plain)
+			}
+			if body != tt.body {
+				t.Errorf("Expected the body back, got %d bytes", len(body))
+			}
+		})
+	}
+}
+
+func TestCompress_Streams(t *testing.T) {
+	// Event streams are flushed event by event and never compressed
+	handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		_, _ = io.WriteString(w, "data: {}\n\n")
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/sessions/token/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if !w.Flushed || w.Header().Get("Content-Encoding") != "" || w.Body.String() != "data: {}\n\n" {
+		t.Errorf("Expected the stream flushed as written, got %q with %v", w.Body.String(), w.Header())
+	}
+
+	// A range is of the stored file
+	handler = Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = io.WriteString(w, strings.Repeat("a", 2*minCompressSize))
+	}))
+	req = httptest.NewRequest("GET", "/static/css/style.css", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Range", "bytes=0-2047")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "" || w.Body.Len() != 2*minCompressSize {
+		t.Errorf("Expected the range uncompressed, got %v", w.Header())
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"gzip":                "gzip",
+		"gzip, deflate, br":   "br",
+		"br;q=1.0, gzip;q=.5": "br",
+		"br;q=.5, gzip":       "gzip",
+		"br":                  "br",
+		"*":                   "br",
+		"*, br;q=0":           "gzip",
+		"GZIP":                "gzip",
+		"x-gzip":              "gzip",
+		"":                    "",
+		"identity":            "",
+		"deflate":             "",
+		"gzip;q=0":            "",
+		"*, gzip;q=0, br;q=0": "",
+		"*;q=0":               "",
+	}
+	for header, expected := range tests {
+		got := ""
+		if i := negotiateEncoding(header); i >= 0 {
+			got = encoders[i].coding
+		}
+		if got != expected {
+			t.Errorf("negotiateEncoding(%q) = %q, expected %q", header, got, expected)
+		}
+	}
+}
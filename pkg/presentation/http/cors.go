@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// corsAllowMethods lists every method used by the signaling API
+	corsAllowMethods = "GET, POST, PUT, DELETE, OPTIONS"
+
+	// corsMaxAge lets browsers cache preflight results for ten minutes
+	corsMaxAge = "600"
+
+	// corsExposeHeaders are the response headers cross-origin clients need to read
+	corsExposeHeaders = "Retry-After, X-Server-Shutdown, X-Session-Paused, X-ICE-Generation, X-Session-E2EE, X-Viewer-ID, X-Viewer-Name, ETag, X-Request-ID, Location"
+)
+
+// CORS wraps the application handler and answers cross-origin requests to
+// the /api/ and WHIP routes for an allowlist of origins, so the signaling API
+// can be used by a separately hosted front-end or an Electron wrapper
+type CORS struct {
+	next     http.Handler
+	origins  map[string]bool
+	allowAll bool
+}
+
+// NewCORS creates a CORS middleware around next; an origin of "*" allows any
+// origin and an empty list disables cross-origin access
+func NewCORS(origins []string, next http.Handler) *CORS {
+	c := &CORS{
+		next:    next,
+		origins: make(map[string]bool),
+	}
+	for _, origin := range origins {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		switch origin {
+		case "":
+		case "*":
+			c.allowAll = true
+		default:
+			c.origins[origin] = true
+		}
+	}
+	return c
+}
+
+// ServeHTTP implements http.Handler
+func (c *CORS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !(strings.HasPrefix(r.URL.Path, "/api/") || strings.HasPrefix(r.URL.Path, whipPath)) {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Origin")
+	allowed := c.allowAll || c.origins[origin]
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		c.handlePreflight(w, r, origin, allowed)
+		return
+	}
+
+	if allowed {
+		c.setAllowOrigin(w, origin)
+		w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+	}
+	c.next.ServeHTTP(w, r)
+}
+
+func (c *CORS) handlePreflight(w http.ResponseWriter, r *http.Request, origin string, allowed bool) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if !allowed {
+		http.Error(w, "origin not allowed", 403)
+		return
+	}
+
+	c.setAllowOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", corsAllowMethods)
+	if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+	w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+	w.WriteHeader(204)
+}
+
+func (c *CORS) setAllowOrigin(w http.ResponseWriter, origin string) {
+	if c.allowAll {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+}
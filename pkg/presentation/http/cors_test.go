@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+
+	tests := []struct {
+		name               string
+		origins            []string
+		method             string
+		path               string
+		origin             string
+		preflight          bool
+		expectedStatusCode int
+		expectedAllow      string
+	}{
+		{
+			name:               "allowed origin",
+			origins:            []string{"https://app.example.com"},
+			method:             "GET",
+			path:               "/api/info",
+			origin:             "https://app.example.com",
+			expectedStatusCode: 200,
+			expectedAllow:      "https://app.example.com",
+		},
+		{
+			name:               "unlisted origin gets no CORS headers",
+			origins:            []string{"https://app.example.com"},
+			method:             "GET",
+			path:               "/api/info",
+			origin:             "https://evil.example.com",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "preflight for allowed origin",
+			origins:            []string{"https://app.example.com/"},
+			method:             "OPTIONS",
+			path:               "/api/offer",
+			origin:             "https://app.example.com",
+			preflight:          true,
+			expectedStatusCode: 204,
+			expectedAllow:      "https://app.example.com",
+		},
+		{
+			name:               "preflight for unlisted origin",
+			origins:            []string{"https://app.example.com"},
+			method:             "OPTIONS",
+			path:               "/api/offer",
+			origin:             "https://evil.example.com",
+			preflight:          true,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "wildcard allows any origin",
+			origins:            []string{"*"},
+			method:             "POST",
+			path:               "/api/new",
+			origin:             "app://electron",
+			expectedStatusCode: 200,
+			expectedAllow:      "*",
+		},
+		{
+			name:               "preflight for a browser WHIP client",
+			origins:            []string{"https://app.example.com"},
+			method:             "OPTIONS",
+			path:               "/whip",
+			origin:             "https://app.example.com",
+			preflight:          true,
+			expectedStatusCode: 204,
+			expectedAllow:      "https://app.example.com",
+		},
+		{
+			name:               "pages are not covered",
+			origins:            []string{"*"},
+			method:             "GET",
+			path:               "/sender",
+			origin:             "https://app.example.com",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "disabled without origins",
+			origins:            nil,
+			method:             "GET",
+			path:               "/api/info",
+			origin:             "https://app.example.com",
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCORS(tt.origins, next)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", "POST")
+				req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expectedAllow {
+				t.Errorf("Expected Access-Control-Allow-Origin %q, got %q", tt.expectedAllow, got)
+			}
+			if tt.preflight && tt.expectedAllow != "" {
+				if w.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+					t.Errorf("Expected requested headers to be allowed, got %q", w.Header().Get("Access-Control-Allow-Headers"))
+				}
+				if w.Header().Get("Access-Control-Allow-Methods") == "" {
+					t.Error("Expected allowed methods on preflight response")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// DevicePairingHandlers contains handlers for pairing viewer devices with
+// senders, who trust them to join their sessions without a PIN
+type DevicePairingHandlers struct {
+	pairingUseCase interfaces.DevicePairingUseCase
+}
+
+// NewDevicePairingHandlers creates a new device pairing handlers instance
+func NewDevicePairingHandlers(pairingUseCase interfaces.DevicePairingUseCase) *DevicePairingHandlers {
+	return &DevicePairingHandlers{
+		pairingUseCase: pairingUseCase,
+	}
+}
+
+// HandlePairings hands the viewer device, known by its device cookie, a code
+// for the sender of the session in the path to confirm; the body carries the
+// session's PIN, if any, and a name for the device
+func (h *DevicePairingHandlers) HandlePairings(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.RequestPairingRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid pairing payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.DeviceID = ensureDeviceID(w, r)
+
+	response, err := h.pairingUseCase.RequestPairing(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writePairingJSON(w, 201, response)
+}
+
+// HandleConfirmPairing trusts the viewer device showing the code in the
+// path; the body carries the sender key that proves the request comes from
+// the session's sender
+func (h *DevicePairingHandlers) HandleConfirmPairing(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.ConfirmPairingRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid pairing payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.Code = r.PathValue("code")
+
+	response, err := h.pairingUseCase.ConfirmPairing(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writePairingJSON(w, 200, response)
+}
+
+// HandleUnlock returns the PIN of the session in the path to a viewer device
+// its sender trusts
+func (h *DevicePairingHandlers) HandleUnlock(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.UnlockSessionRequest{Token: r.PathValue("token")}
+	if cookie, err := r.Cookie(deviceCookie); err == nil {
+		request.DeviceID = cookie.Value
+	}
+
+	response, err := h.pairingUseCase.UnlockSession(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writePairingJSON(w, 200, response)
+}
+
+// HandleDevices lists the viewer devices this browser, known by its sender
+// cookie, trusts
+func (h *DevicePairingHandlers) HandleDevices(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	response, err := h.pairingUseCase.ListDevices(&dto.ListTrustedDevicesRequest{Owner: readSenderID(r)})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writePairingJSON(w, 200, response)
+}
+
+// HandleDevice forgets the trusted device in the path
+func (h *DevicePairingHandlers) HandleDevice(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.ForgetTrustedDeviceRequest{ID: r.PathValue("id"), Owner: readSenderID(r)}
+	if err := h.pairingUseCase.ForgetDevice(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// writePairingJSON writes a pairing response, which may carry a PIN and so
+// is never cached
+func writePairingJSON(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding pairing response: %v", err)
+	}
+}
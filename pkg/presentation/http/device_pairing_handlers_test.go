@@ -0,0 +1,240 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestDevicePairingHandlers_HandlePairings(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		requestError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "request pairing",
+			method:             "POST",
+			body:               `{"pin":"135790","name":"Tablet"}`,
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "wrong PIN",
+			method:             "POST",
+			body:               `{"pin":"000000"}`,
+			requestError:       usecases.ErrInvalidPIN,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "session without a sender cookie",
+			method:             "POST",
+			body:               `{}`,
+			requestError:       usecases.ErrPairingUnavailable,
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPairingUseCase := mocks.NewMockDevicePairingUseCase()
+			mockPairingUseCase.RequestPairingError = tt.requestError
+			handlers := NewDevicePairingHandlers(mockPairingUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/pairings", strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			req.AddCookie(&http.Cookie{Name: deviceCookie, Value: "device-1"})
+			w := httptest.NewRecorder()
+
+			handlers.HandlePairings(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code >= 300 {
+				return
+			}
+
+			request := mockPairingUseCase.LastRequestPairing
+			if request.Token != "test-token" || request.DeviceID != "device-1" || request.PIN != "135790" || request.Name != "Tablet" {
+				t.Errorf("Unexpected pairing request: %+v", request)
+			}
+			var response dto.PairingCodeResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Code != "123456" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestDevicePairingHandlers_HandleConfirmPairing(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		confirmError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "confirm pairing",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "unknown code",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			confirmError:       usecases.ErrPairingCodeNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "wrong sender key",
+			method:             "POST",
+			body:               `{"senderKey":"guess"}`,
+			confirmError:       usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPairingUseCase := mocks.NewMockDevicePairingUseCase()
+			mockPairingUseCase.ConfirmPairingError = tt.confirmError
+			handlers := NewDevicePairingHandlers(mockPairingUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/pairings/123456/confirm", strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			req.SetPathValue("code", "123456")
+			w := httptest.NewRecorder()
+
+			handlers.HandleConfirmPairing(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code == 200 {
+				request := mockPairingUseCase.LastConfirmRequest
+				if request.Token != "test-token" || request.Code != "123456" || request.SenderKey != "sender-key" {
+					t.Errorf("Unexpected confirm request: %+v", request)
+				}
+			}
+		})
+	}
+}
+
+func TestDevicePairingHandlers_HandleUnlock(t *testing.T) {
+	mockPairingUseCase := mocks.NewMockDevicePairingUseCase()
+	handlers := NewDevicePairingHandlers(mockPairingUseCase)
+
+	req := httptest.NewRequest("GET", "/api/v1/sessions/test-token/unlock", nil)
+	req.SetPathValue("token", "test-token")
+	req.AddCookie(&http.Cookie{Name: deviceCookie, Value: "device-1"})
+	w := httptest.NewRecorder()
+	handlers.HandleUnlock(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+		t.Errorf("Expected Cache-Control no-store, got %q", cache)
+	}
+	if request := mockPairingUseCase.LastUnlockRequest; request.Token != "test-token" || request.DeviceID != "device-1" {
+		t.Errorf("Unexpected unlock request: %+v", request)
+	}
+	var response dto.UnlockSessionResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.PIN != "246810" {
+		t.Errorf("Unexpected response: %+v, %v", response, err)
+	}
+
+	// Devices without a cookie are not trusted
+	mockPairingUseCase.UnlockSessionError = usecases.ErrDeviceNotTrusted
+	req = httptest.NewRequest("GET", "/api/v1/sessions/test-token/unlock", nil)
+	req.SetPathValue("token", "test-token")
+	w = httptest.NewRecorder()
+	handlers.HandleUnlock(w, req)
+
+	if w.Code != 403 {
+		t.Errorf("Expected status code 403, got %d", w.Code)
+	}
+	if request := mockPairingUseCase.LastUnlockRequest; request.DeviceID != "" {
+		t.Errorf("Expected no device ID without a cookie, got %+v", request)
+	}
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("Expected no device cookie to be issued, got %v", cookies)
+	}
+}
+
+func TestDevicePairingHandlers_HandleDevices(t *testing.T) {
+	senderID := strings.Repeat("ab", senderIDLength/2)
+	mockPairingUseCase := mocks.NewMockDevicePairingUseCase()
+	handlers := NewDevicePairingHandlers(mockPairingUseCase)
+
+	req := httptest.NewRequest("GET", "/api/v1/sender/devices", nil)
+	req.AddCookie(&http.Cookie{Name: senderCookie, Value: senderID})
+	w := httptest.NewRecorder()
+	handlers.HandleDevices(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	var response dto.TrustedDevicesResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil || len(response.Devices) != 1 {
+		t.Errorf("Unexpected response: %+v, %v", response, err)
+	}
+	if strings.Contains(w.Body.String(), "deviceId") {
+		t.Errorf("Expected the device IDs to stay secret, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("DELETE", "/api/v1/sender/devices/pairing-1", nil)
+	req.SetPathValue("id", "pairing-1")
+	req.AddCookie(&http.Cookie{Name: senderCookie, Value: senderID})
+	w = httptest.NewRecorder()
+	handlers.HandleDevice(w, req)
+
+	if w.Code != 204 {
+		t.Fatalf("Expected status code 204, got %d", w.Code)
+	}
+	if request := mockPairingUseCase.LastForgetRequest; request.ID != "pairing-1" || request.Owner != senderID {
+		t.Errorf("Unexpected forget request: %+v", request)
+	}
+
+	mockPairingUseCase.ForgetDeviceError = usecases.ErrTrustedDeviceNotFound
+	w = httptest.NewRecorder()
+	handlers.HandleDevice(w, req)
+	if w.Code != 404 {
+		t.Errorf("Expected status code 404, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handlers.HandleDevice(w, httptest.NewRequest("GET", "/api/v1/sender/devices/pairing-1", nil))
+	if w.Code != 405 {
+		t.Errorf("Expected status code 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// sseKeepAlive is how often an idle event stream sends a comment so proxies
+	// and mobile browsers keep the connection open
+	sseKeepAlive = 15 * time.Second
+
+	// sseWriteTimeout drops a client whose connection stalls mid-write, e.g. a
+	// phone that lost Wi-Fi, instead of holding its stream open indefinitely
+	sseWriteTimeout = 10 * time.Second
+)
+
+// EventHandlers contains handlers for realtime event streams
+type EventHandlers struct {
+	subscriber   interfaces.EventSubscriber
+	queueUseCase interfaces.ViewerQueueUseCase
+}
+
+// NewEventHandlers creates a new event handlers instance
+func NewEventHandlers(subscriber interfaces.EventSubscriber, queueUseCase interfaces.ViewerQueueUseCase) *EventHandlers {
+	return &EventHandlers{
+		subscriber:   subscriber,
+		queueUseCase: queueUseCase,
+	}
+}
+
+// HandleEvents streams session events as Server-Sent Events. Passing
+// ?viewer=<id> adds the events addressed to that queued viewer.
+func (h *EventHandlers) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", 500)
+		return
+	}
+
+	token := r.PathValue("token")
+	viewerID := r.URL.Query().Get("viewer")
+
+	topics := []string{entities.SessionTopic(token)}
+	if viewerID != "" {
+		topics = append(topics, entities.ViewerTopic(token, viewerID))
+	}
+
+	// Subscribe before reading the current state so no change falls in between
+	events, cancel := h.subscriber.Subscribe(topics...)
+	defer cancel()
+
+	queue, err := h.queueUseCase.GetQueue(&dto.GetQueueRequest{Token: token})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	// Each write gets its own deadline; the connection may be reused afterwards
+	rc := http.NewResponseController(w)
+	defer func() { _ = rc.SetWriteDeadline(time.Time{}) }()
+	extendDeadline := func() {
+		_ = rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout))
+	}
+
+	extendDeadline()
+	w.WriteHeader(200)
+
+	for _, event := range initialEvents(queue, viewerID) {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			extendDeadline()
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// Server shutdown, or this client fell too far behind; it
+				// reconnects and starts from the current state
+				return
+			}
+			extendDeadline()
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// initialEvents describes the current queue state to a newly connected client
+func initialEvents(queue *dto.GetQueueResponse, viewerID string) []entities.Event {
+	if viewerID == "" {
+		return []entities.Event{{Type: entities.EventQueueChanged, Data: queue.Viewers}}
+	}
+
+	if queue.ReservedFor == viewerID {
+		return []entities.Event{{Type: entities.EventViewerAdmitted}}
+	}
+
+	for i, viewer := range queue.Viewers {
+		if viewer.ID == viewerID {
+			return []entities.Event{{
+				Type: entities.EventQueuePosition,
+				Data: map[string]int{"position": i + 1},
+			}}
+		}
+	}
+
+	return nil
+}
+
+// writeEvent writes a single Server-Sent Event
+func writeEvent(w http.ResponseWriter, event entities.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.Printf("Error encoding event %s: %v", event.Type, err)
+		return nil
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+)
+
+// FileHandlers contains handlers for the file relay used before the peers'
+// data channel is open
+type FileHandlers struct {
+	fileUseCase interfaces.FileUseCase
+
+	// limit bounds an upload body, matching what a session may relay
+	limit int64
+}
+
+// NewFileHandlers creates a new file handlers instance accepting uploads of
+// up to limit bytes
+func NewFileHandlers(fileUseCase interfaces.FileUseCase, limit int64) *FileHandlers {
+	return &FileHandlers{
+		fileUseCase: fileUseCase,
+		limit:       limit,
+	}
+}
+
+// HandleFiles handles the files of a session (POST the raw content with
+// ?name= to relay one, GET to list them)
+func (h *FileHandlers) HandleFiles(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handleShareFile(w, r)
+	case http.MethodGet:
+		h.handleListFiles(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+// HandleFile downloads one relayed file
+func (h *FileHandlers) HandleFile(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	file, data, err := h.fileUseCase.GetFile(&dto.GetFileRequest{
+		Token: r.PathValue("token"),
+		PIN:   r.URL.Query().Get("pin"),
+		ID:    r.PathValue("id"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// Always a download: the content is whatever the sender dropped and must
+	// never render as a page of this site
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write(data); err != nil {
+		log.Printf("Error writing file: %v", err)
+	}
+}
+
+func (h *FileHandlers) handleShareFile(w http.ResponseWriter, r *http.Request) {
+	var data []byte
+	// A disabled relay reads nothing and lets the use case say so
+	if h.limit > 0 {
+		var err error
+		data, err = io.ReadAll(http.MaxBytesReader(w, r.Body, h.limit))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeUseCaseError(w, usecases.ErrFileTooLarge)
+				return
+			}
+			log.Printf("❌ Reading file upload failed: %v", err)
+			http.Error(w, err.Error(), 400)
+			return
+		}
+	}
+
+	file, err := h.fileUseCase.ShareFile(&dto.ShareFileRequest{
+		Token:       r.PathValue("token"),
+		Name:        r.URL.Query().Get("name"),
+		ContentType: r.Header.Get("Content-Type"),
+		Data:        data,
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(file); err != nil {
+		log.Printf("Error encoding shared file: %v", err)
+	}
+}
+
+func (h *FileHandlers) handleListFiles(w http.ResponseWriter, r *http.Request) {
+	response, err := h.fileUseCase.ListFiles(&dto.GetFilesRequest{
+		Token: r.PathValue("token"),
+		PIN:   r.URL.Query().Get("pin"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding files: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestFileHandlers_HandleFiles(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		useCaseError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "share file",
+			method:             "POST",
+			url:                "/api/v1/sessions/test-token/files?name=notes.txt",
+			body:               "hello",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "upload over the limit",
+			method:             "POST",
+			url:                "/api/v1/sessions/test-token/files?name=big.bin",
+			body:               strings.Repeat("x", 11),
+			expectedStatusCode: 413,
+		},
+		{
+			name:               "relay disabled",
+			method:             "POST",
+			url:                "/api/v1/sessions/test-token/files?name=notes.txt",
+			body:               "hello",
+			useCaseError:       usecases.ErrFileRelayDisabled,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "list files",
+			method:             "GET",
+			url:                "/api/v1/sessions/test-token/files?pin=123456",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong PIN",
+			method:             "GET",
+			url:                "/api/v1/sessions/test-token/files?pin=000000",
+			useCaseError:       usecases.ErrInvalidPIN,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			url:                "/api/v1/sessions/test-token/files",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFileUseCase := mocks.NewMockFileUseCase()
+			mockFileUseCase.ShareFileError = tt.useCaseError
+			mockFileUseCase.ListFilesError = tt.useCaseError
+			handlers := NewFileHandlers(mockFileUseCase, 10)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleFiles(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if tt.method == "POST" {
+				request := mockFileUseCase.LastShareRequest
+				if request.Token != "test-token" || request.Name != "notes.txt" || request.ContentType != "text/plain" || string(request.Data) != "hello" {
+					t.Errorf("Unexpected request: %+v", request)
+				}
+				var file entities.SharedFile
+				if err := json.NewDecoder(w.Body).Decode(&file); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if file.ID == "" || file.Size != 5 {
+					t.Errorf("Unexpected file: %+v", file)
+				}
+				return
+			}
+
+			var response dto.FilesResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Files) != 1 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestFileHandlers_HandleFile(t *testing.T) {
+	mockFileUseCase := mocks.NewMockFileUseCase()
+	handlers := NewFileHandlers(mockFileUseCase, 10)
+
+	req := httptest.NewRequest("GET", "/api/v1/sessions/test-token/files/file-id?pin=123456", nil)
+	req.SetPathValue("token", "test-token")
+	req.SetPathValue("id", "file-id")
+	w := httptest.NewRecorder()
+
+	handlers.HandleFile(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	if request := mockFileUseCase.LastGetRequest; request.ID != "file-id" || request.PIN != "123456" {
+		t.Errorf("Unexpected request: %+v", request)
+	}
+	if w.Body.String() != "mock" {
+		t.Errorf("Expected the file content, got %q", w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=mock.txt" {
+		t.Errorf("Expected an attachment, got %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Expected a generic content type, got %q", got)
+	}
+
+	mockFileUseCase.GetFileError = usecases.ErrFileNotFound
+	w = httptest.NewRecorder()
+	handlers.HandleFile(w, req)
+	if w.Code != 404 {
+		t.Errorf("Expected status code 404 for a missing file, got %d", w.Code)
+	}
+}
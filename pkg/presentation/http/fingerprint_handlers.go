@@ -0,0 +1,51 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// FingerprintHandlers contains handlers for the DTLS fingerprints of a
+// session's peers
+type FingerprintHandlers struct {
+	fingerprintUseCase interfaces.FingerprintUseCase
+}
+
+// NewFingerprintHandlers creates a new fingerprint handlers instance
+func NewFingerprintHandlers(fingerprintUseCase interfaces.FingerprintUseCase) *FingerprintHandlers {
+	return &FingerprintHandlers{
+		fingerprintUseCase: fingerprintUseCase,
+	}
+}
+
+// HandleFingerprints handles GET of the fingerprints in a session's offer and
+// answer, for ?pin= or the sender's ?senderKey=
+func (h *FingerprintHandlers) HandleFingerprints(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	query := r.URL.Query()
+	response, err := h.fingerprintUseCase.GetFingerprints(&dto.GetFingerprintsRequest{
+		Token:     r.PathValue("token"),
+		PIN:       query.Get("pin"),
+		SenderKey: query.Get("senderKey"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// A viewer that reconnects brings a new certificate
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding fingerprints: %v", err)
+	}
+}
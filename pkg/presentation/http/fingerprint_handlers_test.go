@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestFingerprintHandlers_HandleFingerprints(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		useCaseError       error
+		expectedStatusCode int
+	}{
+		{name: "fingerprints", method: "GET", expectedStatusCode: 200},
+		{name: "SFU session", method: "GET", useCaseError: usecases.ErrFingerprintsUnavailable, expectedStatusCode: 404},
+		{name: "wrong PIN", method: "GET", useCaseError: usecases.ErrInvalidPIN, expectedStatusCode: 403},
+		{name: "locked after wrong PINs", method: "GET", useCaseError: usecases.ErrPINLocked, expectedStatusCode: 429},
+		{name: "method not allowed", method: "POST", expectedStatusCode: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFingerprintUseCase := mocks.NewMockFingerprintUseCase()
+			mockFingerprintUseCase.GetFingerprintsError = tt.useCaseError
+			handlers := NewFingerprintHandlers(mockFingerprintUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/fingerprints?pin=123456", nil)
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleFingerprints(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if request := mockFingerprintUseCase.LastGetRequest; request.Token != "test-token" || request.PIN != "123456" {
+				t.Errorf("Unexpected request: %+v", request)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected no-store, got %q", cache)
+			}
+			var response dto.FingerprintsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Sender == nil || response.Viewer != nil {
+				t.Errorf("Unexpected response: %+v, %v", response, err)
+			}
+		})
+	}
+}
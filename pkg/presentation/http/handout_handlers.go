@@ -0,0 +1,68 @@
+package http
+
+import (
+	"encoding/base64"
+	htmltemplate "html/template"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/template"
+	"share-screen/pkg/usecase/dto"
+)
+
+// HandoutHandlers contains handlers for printable session handouts
+type HandoutHandlers struct {
+	templateService *template.TemplateService
+	handoutUseCase  interfaces.HandoutUseCase
+}
+
+// NewHandoutHandlers creates a new handout handlers instance
+func NewHandoutHandlers(templateService *template.TemplateService, handoutUseCase interfaces.HandoutUseCase) *HandoutHandlers {
+	return &HandoutHandlers{
+		templateService: templateService,
+		handoutUseCase:  handoutUseCase,
+	}
+}
+
+// ServeHandout serves a printable page with the joining details of a session.
+// The PIN is not rendered by the server; the sender page passes it in the URL
+// fragment so it never leaves the presenter's browser.
+func (h *HandoutHandlers) ServeHandout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	request := &dto.GetHandoutRequest{
+		Token:  r.URL.Query().Get("token"),
+		Scheme: scheme,
+		Host:   r.Host,
+	}
+	response, err := h.handoutUseCase.GetHandout(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	data := pageData(r, "Join the screen share", "/static/js/handout.js")
+	data.Handout = &template.Handout{
+		Name:        response.Name,
+		ViewerURL:   response.ViewerURL,
+		QRCode:      htmltemplate.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(response.QRCode)),
+		ValidFrom:   response.ValidFrom,
+		ValidUntil:  response.ValidUntil,
+		PINRequired: response.PINRequired,
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := h.templateService.RenderPage(w, "handout.html", data); err != nil {
+		log.Printf("Error rendering handout template: %v", err)
+		http.Error(w, "Internal server error", 500)
+	}
+}
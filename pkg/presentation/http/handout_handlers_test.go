@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/infrastructure/template"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestHandoutHandlers_ServeHandout(t *testing.T) {
+	templateService, err := template.NewTemplateService("../../../web/templates")
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+
+	tests := []struct {
+		name               string
+		method             string
+		pinRequired        bool
+		handoutError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "handout rendered",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "handout with PIN placeholder",
+			method:             "GET",
+			pinRequired:        true,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "session ended",
+			method:             "GET",
+			handoutError:       usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHandoutUseCase := mocks.NewMockHandoutUseCase()
+			mockHandoutUseCase.GetHandoutError = tt.handoutError
+			mockHandoutUseCase.HandoutResponse.PINRequired = tt.pinRequired
+			handlers := NewHandoutHandlers(templateService, mockHandoutUseCase)
+
+			req := httptest.NewRequest(tt.method, "/handout?token=mock-token", nil)
+			w := httptest.NewRecorder()
+
+			handlers.ServeHandout(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, mockHandoutUseCase.HandoutResponse.ViewerURL) {
+				t.Error("Expected viewer URL in handout")
+			}
+			if !strings.Contains(body, `src="data:image/png;base64,`) {
+				t.Error("Expected inline QR code image")
+			}
+			if strings.Contains(body, `id="pin"`) != tt.pinRequired {
+				t.Errorf("Expected PIN placeholder %v", tt.pinRequired)
+			}
+			if mockHandoutUseCase.LastHandoutRequest.Host != "example.com" {
+				t.Errorf("Expected request host, got %q", mockHandoutUseCase.LastHandoutRequest.Host)
+			}
+		})
+	}
+}
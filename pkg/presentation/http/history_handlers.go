@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// HistoryHandlers contains handlers for finished session summaries
+type HistoryHandlers struct {
+	historyUseCase interfaces.SessionHistoryUseCase
+}
+
+// NewHistoryHandlers creates a new history handlers instance
+func NewHistoryHandlers(historyUseCase interfaces.SessionHistoryUseCase) *HistoryHandlers {
+	return &HistoryHandlers{
+		historyUseCase: historyUseCase,
+	}
+}
+
+// HandleSummary returns the summary of the finished session in the path
+func (h *HistoryHandlers) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.GetSessionSummaryRequest{Token: r.PathValue("token")}
+	response, err := h.historyUseCase.GetSessionSummary(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeSummary(w, response)
+}
+
+// HandleNotes replaces the notes stored with the finished session in the path
+func (h *HistoryHandlers) HandleNotes(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.UpdateSessionNotesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid notes payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.Owner = readSenderID(r)
+
+	response, err := h.historyUseCase.UpdateSessionNotes(&request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeSummary(w, response)
+}
+
+func writeSummary(w http.ResponseWriter, response *dto.SessionSummaryResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding summary response: %v", err)
+		http.Error(w, "internal server error", 500)
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestHistoryHandlers_HandleSummary(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		summaryError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "summary found",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "session not found",
+			method:             "GET",
+			summaryError:       usecases.ErrSessionNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHistoryUseCase := mocks.NewMockSessionHistoryUseCase()
+			mockHistoryUseCase.GetSummaryError = tt.summaryError
+			handlers := NewHistoryHandlers(mockHistoryUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/summary", nil)
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleSummary(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestHistoryHandlers_HandleNotes(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		notesError         error
+		expectedStatusCode int
+	}{
+		{
+			name:               "notes saved",
+			body:               `{"notes":"good session","senderKey":"sender-key"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "viewer token alone",
+			body:               `{"notes":"defaced"}`,
+			notesError:         usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "notes too long",
+			body:               `{"notes":"x","senderKey":"sender-key"}`,
+			notesError:         usecases.ErrInvalidNotes,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid json",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHistoryUseCase := mocks.NewMockSessionHistoryUseCase()
+			mockHistoryUseCase.UpdateNotesError = tt.notesError
+			handlers := NewHistoryHandlers(mockHistoryUseCase)
+
+			req := httptest.NewRequest("POST", "/api/sessions/test-token/notes", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleNotes(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode == 200 && (mockHistoryUseCase.LastNotesRequest.Token != "test-token" || mockHistoryUseCase.LastNotesRequest.SenderKey != "sender-key") {
+				t.Errorf("Expected token from path and the sender key, got %+v", mockHistoryUseCase.LastNotesRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/infrastructure/recording"
+	"share-screen/pkg/usecase/dto"
+)
+
+// HLSHandlers serve the HLS playlists the SFU packages for each session, so
+// viewers whose browser blocks WebRTC can still watch. The playlist of a
+// session is at /hls/<token>/index.m3u8 while the session is live. Requests
+// are not logged, since players fetch a segment every few seconds.
+type HLSHandlers struct {
+	sessionUseCase interfaces.SessionUseCase
+	dir            string
+}
+
+// NewHLSHandlers creates a new HLS handlers instance serving the session
+// directories under dir; an empty dir answers every request with 404
+func NewHLSHandlers(sessionUseCase interfaces.SessionUseCase, dir string) *HLSHandlers {
+	return &HLSHandlers{
+		sessionUseCase: sessionUseCase,
+		dir:            dir,
+	}
+}
+
+// ServeFile serves the playlist or a segment of a live session. Until ffmpeg
+// has written the first segments the playlist gets 503 with Retry-After.
+// Sessions with a PIN get 403, as players cannot pass the PIN on.
+func (h *HLSHandlers) ServeFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+	if h.dir == "" {
+		http.Error(w, "HLS is not enabled on this server", 404)
+		return
+	}
+
+	name := r.PathValue("file")
+	contentType, ok := recording.HLSContentType(name)
+	if !ok {
+		http.Error(w, "not found", 404)
+		return
+	}
+
+	token := r.PathValue("token")
+	session, err := h.sessionUseCase.GetSession(&dto.GetSessionRequest{Token: token})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+	switch {
+	case session.Status != entities.SessionStatusPending && session.Status != entities.SessionStatusActive:
+		http.Error(w, "session ended", 410)
+		return
+	case !session.SFU:
+		http.Error(w, "session is not streamed through the server", 404)
+		return
+	case session.RequiresPIN:
+		http.Error(w, "HLS is not available for sessions with a PIN", 403)
+		return
+	}
+
+	file, err := os.Open(filepath.Join(h.dir, token, name))
+	if err != nil {
+		if name == recording.HLSPlaylist {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "HLS playlist not ready yet", 503)
+			return
+		}
+		http.Error(w, "not found", 404)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		http.Error(w, "not found", 404)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if name == recording.HLSPlaylist {
+		// The playlist changes with every segment
+		w.Header().Set("Cache-Control", "no-cache")
+	} else {
+		w.Header().Set("Cache-Control", "private, max-age=60")
+	}
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestHLSHandlers_ServeFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "test-token"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"index.m3u8":      "#EXTM3U\n",
+		"segment00001.ts": "ts",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, "test-token", name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	live := &dto.SessionDetailResponse{Token: "test-token", Status: entities.SessionStatusActive, SFU: true}
+	tests := []struct {
+		name                string
+		method              string
+		token               string
+		file                string
+		dir                 string
+		session             *dto.SessionDetailResponse
+		failGetSession      bool
+		expectedStatusCode  int
+		expectedContentType string
+	}{
+		{name: "playlist", method: "GET", file: "index.m3u8", session: live, expectedStatusCode: 200, expectedContentType: "application/vnd.apple.mpegurl"},
+		{name: "segment", method: "GET", file: "segment00001.ts", session: live, expectedStatusCode: 200, expectedContentType: "video/mp2t"},
+		{name: "head", method: "HEAD", file: "index.m3u8", session: live, expectedStatusCode: 200, expectedContentType: "application/vnd.apple.mpegurl"},
+		{name: "segment gone", method: "GET", file: "segment00000.ts", session: live, expectedStatusCode: 404},
+		{name: "other files", method: "GET", file: "notes.txt", session: live, expectedStatusCode: 404},
+		{name: "playlist not ready", method: "GET", token: "other-token", file: "index.m3u8", session: live, expectedStatusCode: 503},
+		{
+			name:               "session ended",
+			method:             "GET",
+			file:               "index.m3u8",
+			session:            &dto.SessionDetailResponse{Status: entities.SessionStatusEnded, SFU: true},
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "peer-to-peer session",
+			method:             "GET",
+			file:               "index.m3u8",
+			session:            &dto.SessionDetailResponse{Status: entities.SessionStatusActive},
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "session with a PIN",
+			method:             "GET",
+			file:               "index.m3u8",
+			session:            &dto.SessionDetailResponse{Status: entities.SessionStatusActive, SFU: true, RequiresPIN: true},
+			expectedStatusCode: 403,
+		},
+		{name: "lookup fails", method: "GET", file: "index.m3u8", failGetSession: true, expectedStatusCode: 500},
+		{name: "HLS disabled", method: "GET", file: "index.m3u8", dir: "-", session: live, expectedStatusCode: 404},
+		{name: "method not allowed", method: "POST", file: "index.m3u8", session: live, expectedStatusCode: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockSessionUseCase()
+			mockUseCase.SessionDetailResponse = tt.session
+			mockUseCase.ShouldFailGetSession = tt.failGetSession
+			hlsDir := dir
+			if tt.dir == "-" {
+				hlsDir = ""
+			}
+			token := tt.token
+			if token == "" {
+				token = "test-token"
+			}
+			handlers := NewHLSHandlers(mockUseCase, hlsDir)
+
+			req := httptest.NewRequest(tt.method, "/hls/"+token+"/"+tt.file, nil)
+			req.SetPathValue("token", token)
+			req.SetPathValue("file", tt.file)
+			w := httptest.NewRecorder()
+
+			handlers.ServeFile(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedContentType != "" && w.Header().Get("Content-Type") != tt.expectedContentType {
+				t.Errorf("Expected content type %q, got %q", tt.expectedContentType, w.Header().Get("Content-Type"))
+			}
+			if tt.expectedStatusCode == 503 && w.Header().Get("Retry-After") == "" {
+				t.Error("Expected Retry-After while the playlist is not ready")
+			}
+		})
+	}
+}
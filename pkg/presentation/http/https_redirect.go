@@ -0,0 +1,33 @@
+package http
+
+import (
+	"net"
+	"net/http"
+)
+
+// HTTPSRedirect sends plain HTTP requests to the same URL on the server's
+// HTTPS port, keeping the host name the client used
+type HTTPSRedirect struct {
+	port string
+}
+
+// NewHTTPSRedirect creates a redirect to HTTPS on port
+func NewHTTPSRedirect(port string) *HTTPSRedirect {
+	return &HTTPSRedirect{port: port}
+}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPSRedirect) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+	if h.port != "443" {
+		host = net.JoinHostPort(host, h.port)
+	}
+
+	target := *r.URL
+	target.Scheme = "https"
+	target.Host = host
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPSRedirect(t *testing.T) {
+	tests := []struct {
+		name             string
+		port             string
+		target           string
+		expectedLocation string
+	}{
+		{
+			name:             "default HTTPS port",
+			port:             "443",
+			target:           "http://share.example.com/viewer?token=abc",
+			expectedLocation: "https://share.example.com/viewer?token=abc",
+		},
+		{
+			name:             "other HTTPS port",
+			port:             "8443",
+			target:           "http://share.example.com:80/sender",
+			expectedLocation: "https://share.example.com:8443/sender",
+		},
+		{
+			name:             "LAN IP with an escaped path",
+			port:             "8443",
+			target:           "http://192.168.1.10:8080/r/design%2Freview?key=a%26b",
+			expectedLocation: "https://192.168.1.10:8443/r/design%2Freview?key=a%26b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewHTTPSRedirect(tt.port).ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+
+			if w.Code != 302 {
+				t.Errorf("Expected status code 302, got %d", w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected redirect to %s, got %s", tt.expectedLocation, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// ICEHandlers contains handlers for the ICE servers given to peers
+type ICEHandlers struct {
+	iceUseCase interfaces.ICEConfigUseCase
+}
+
+// NewICEHandlers creates a new ICE handlers instance
+func NewICEHandlers(iceUseCase interfaces.ICEConfigUseCase) *ICEHandlers {
+	return &ICEHandlers{
+		iceUseCase: iceUseCase,
+	}
+}
+
+// HandleICEConfig returns the STUN and TURN servers for the session in the
+// path; protected sessions need ?pin=
+func (h *ICEHandlers) HandleICEConfig(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.GetICEConfigRequest{
+		Token: r.PathValue("token"),
+		PIN:   r.URL.Query().Get("pin"),
+	}
+	response, err := h.iceUseCase.GetICEConfig(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// TURN credentials must not be cached by browsers or proxies
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding ICE config response: %v", err)
+	}
+}
+
+// HandleTURNCredentials mints a short-lived credential for the TURN servers
+// configured without one; protected sessions need ?pin=
+func (h *ICEHandlers) HandleTURNCredentials(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.GetICEConfigRequest{
+		Token: r.PathValue("token"),
+		PIN:   r.URL.Query().Get("pin"),
+	}
+	response, err := h.iceUseCase.GetTURNCredentials(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding TURN credentials response: %v", err)
+	}
+}
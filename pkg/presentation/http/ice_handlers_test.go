@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestICEHandlers_HandleICEConfig(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		configError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "config returned",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong pin",
+			method:             "GET",
+			configError:        usecases.ErrInvalidPIN,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "session ended",
+			method:             "GET",
+			configError:        usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICEUseCase := mocks.NewMockICEConfigUseCase()
+			mockICEUseCase.GetICEConfigError = tt.configError
+			handlers := NewICEHandlers(mockICEUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/ice-config?pin=123456", nil)
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleICEConfig(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if request := mockICEUseCase.LastRequest; request.Token != "test-token" || request.PIN != "123456" {
+				t.Errorf("Expected token and PIN passed on, got %+v", request)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected Cache-Control no-store, got %q", cache)
+			}
+			var response dto.ICEConfigResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil || len(response.ICEServers) != 1 {
+				t.Errorf("Expected one ICE server, got %+v (%v)", response, err)
+			}
+		})
+	}
+}
+
+func TestICEHandlers_HandleTURNCredentials(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		credentialsError   error
+		expectedStatusCode int
+	}{
+		{
+			name:               "credential minted",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "no shared secret",
+			method:             "GET",
+			credentialsError:   usecases.ErrTURNNotConfigured,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "wrong pin",
+			method:             "GET",
+			credentialsError:   usecases.ErrInvalidPIN,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockICEUseCase := mocks.NewMockICEConfigUseCase()
+			mockICEUseCase.GetTURNCredentialsError = tt.credentialsError
+			handlers := NewICEHandlers(mockICEUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/turn-credentials?pin=123456", nil)
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleTURNCredentials(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if request := mockICEUseCase.LastRequest; request.Token != "test-token" || request.PIN != "123456" {
+				t.Errorf("Expected token and PIN passed on, got %+v", request)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected Cache-Control no-store, got %q", cache)
+			}
+			var response dto.TURNCredentialsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil || response.Credential != "mock-credential" {
+				t.Errorf("Expected the minted credential, got %+v (%v)", response, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// InviteHandlers contains handlers for posting viewer links to team chat
+type InviteHandlers struct {
+	inviteUseCase interfaces.InviteUseCase
+}
+
+// NewInviteHandlers creates a new invite handlers instance
+func NewInviteHandlers(inviteUseCase interfaces.InviteUseCase) *InviteHandlers {
+	return &InviteHandlers{
+		inviteUseCase: inviteUseCase,
+	}
+}
+
+// HandleInvite posts the viewer link of the session in the path to the
+// configured Slack and Discord channels; the body carries the sender key
+// that proves the request comes from the session's sender
+func (h *InviteHandlers) HandleInvite(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.PostInviteRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid invite payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.Scheme = "http"
+	if r.TLS != nil {
+		request.Scheme = "https"
+	}
+	request.Host = r.Host
+
+	response, err := h.inviteUseCase.PostInvite(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding invite response: %v", err)
+	}
+}
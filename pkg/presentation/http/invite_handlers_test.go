@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestInviteHandlers_HandleInvite(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		postError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "invite posted",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong sender key",
+			method:             "POST",
+			body:               `{"senderKey":"guess"}`,
+			postError:          usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "no webhook configured",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			postError:          usecases.ErrInvitesDisabled,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "already posted",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			postError:          usecases.ErrInviteAlreadyPosted,
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "chat service failed",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			postError:          usecases.ErrInviteNotPosted,
+			expectedStatusCode: 502,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInviteUseCase := mocks.NewMockInviteUseCase()
+			mockInviteUseCase.PostInviteError = tt.postError
+			handlers := NewInviteHandlers(mockInviteUseCase)
+
+			req := httptest.NewRequest(tt.method, "http://localhost:8080/api/v1/sessions/test-token/invite", strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleInvite(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			request := mockInviteUseCase.LastPostRequest
+			if request.Token != "test-token" || request.SenderKey != "sender-key" || request.Scheme != "http" || request.Host != "localhost:8080" {
+				t.Errorf("Unexpected invite request: %+v", request)
+			}
+			var response dto.PostInviteResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Services) != 1 || response.Services[0] != "Slack" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
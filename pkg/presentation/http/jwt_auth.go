@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// jwtAuthRealm names the protected API in 401 responses
+const jwtAuthRealm = `Bearer realm="share-screen api"`
+
+// userContextKey keys the UserIdentity of an authenticated request
+type userContextKey struct{}
+
+// JWTAuth requires a signed bearer JWT for session creation and the admin
+// API, and passes the user it names on with the request so sessions record
+// who started them. Without a verifier it lets every request through.
+type JWTAuth struct {
+	verifier interfaces.TokenVerifier
+}
+
+// NewJWTAuth creates JWT auth checking tokens with verifier; a nil verifier
+// disables it
+func NewJWTAuth(verifier interfaces.TokenVerifier) *JWTAuth {
+	return &JWTAuth{verifier: verifier}
+}
+
+// Enabled reports whether tokens are required
+func (a *JWTAuth) Enabled() bool {
+	return a != nil && a.verifier != nil
+}
+
+// Authenticate returns the user of the request's bearer JWT, or nil when
+// the request carries no valid token
+func (a *JWTAuth) Authenticate(r *http.Request) *entities.UserIdentity {
+	if !a.Enabled() {
+		return nil
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil
+	}
+	user, err := a.verifier.Verify(token)
+	if err != nil {
+		log.Printf("🔐 Rejected token for %s %s from %s: %v", r.Method, r.URL.Path, r.RemoteAddr, err)
+		return nil
+	}
+	return user
+}
+
+// Wrap answers 401 before a request without a valid token reaches next, and
+// otherwise hands next the token's user through the request context
+func (a *JWTAuth) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	if !a.Enabled() {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := a.Authenticate(r)
+		if user == nil {
+			w.Header().Set("WWW-Authenticate", jwtAuthRealm)
+			http.Error(w, "unauthorized", 401)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	}
+}
+
+// userFromContext returns the user JWTAuth authenticated the request as, if any
+func userFromContext(ctx context.Context) *entities.UserIdentity {
+	user, _ := ctx.Value(userContextKey{}).(*entities.UserIdentity)
+	return user
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestJWTAuth_HandleNewToken(t *testing.T) {
+	alice := &entities.UserIdentity{Subject: "alice", Issuer: "idp"}
+	verifier := mocks.NewMockTokenVerifier()
+	verifier.Users["alice-token"] = alice
+
+	tests := []struct {
+		name               string
+		verifier           *mocks.MockTokenVerifier
+		authorization      string
+		expectedStatusCode int
+		expectedUser       *entities.UserIdentity
+	}{
+		{
+			name:               "disabled without a verifier",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "valid token",
+			verifier:           verifier,
+			authorization:      "Bearer alice-token",
+			expectedStatusCode: 200,
+			expectedUser:       alice,
+		},
+		{
+			name:               "no token",
+			verifier:           verifier,
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "invalid token",
+			verifier:           verifier,
+			authorization:      "Bearer forged",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "basic credentials",
+			verifier:           verifier,
+			authorization:      "Basic cHJlc2VudGVyOnMzY3JldA==",
+			expectedStatusCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewJWTAuth(nil)
+			if tt.verifier != nil {
+				auth = NewJWTAuth(tt.verifier)
+			}
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			handlers := NewAPIHandlers(mockSessionUseCase, mocks.NewMockServerInfoUseCase(), nil)
+
+			req := httptest.NewRequest("POST", "/api/v1/new", strings.NewReader(`{}`))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			auth.Wrap(handlers.HandleNewToken)(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code == 401 {
+				if w.Header().Get("WWW-Authenticate") != jwtAuthRealm {
+					t.Errorf("Expected a bearer challenge, got %q", w.Header().Get("WWW-Authenticate"))
+				}
+				if mockSessionUseCase.LastCreateRequest != nil {
+					t.Error("Expected no session to be created")
+				}
+				return
+			}
+			if mockSessionUseCase.LastCreateRequest.User != tt.expectedUser {
+				t.Errorf("Expected the session created for %+v, got %+v", tt.expectedUser, mockSessionUseCase.LastCreateRequest.User)
+			}
+		})
+	}
+}
+
+func TestAdminHandlers_JWTAuth(t *testing.T) {
+	verifier := mocks.NewMockTokenVerifier()
+	verifier.Users["admin-jwt"] = &entities.UserIdentity{Subject: "alice", Scopes: []string{"share", entities.ScopeAdmin}}
+	verifier.Users["share-jwt"] = &entities.UserIdentity{Subject: "bob", Scopes: []string{"share"}}
+
+	tests := []struct {
+		name               string
+		token              string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{
+			name:               "JWT with the admin scope",
+			authorization:      "Bearer admin-jwt",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "JWT without the admin scope",
+			authorization:      "Bearer share-jwt",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "admin token alongside JWTs",
+			token:              "admin-token",
+			authorization:      "Bearer admin-token",
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, tt.token, NewJWTAuth(verifier))
+
+			req := httptest.NewRequest("GET", "/api/v1/admin/sessions", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handlers.HandleSessions(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxKeyMessageBody bounds a relayed key message, which carries at most a
+// public key and a wrapped content key
+const maxKeyMessageBody = 4 << 10
+
+// KeyExchangeHandlers contains handlers for the content key relay of end-to-end
+// encrypted sessions
+type KeyExchangeHandlers struct {
+	keyExchangeUseCase interfaces.KeyExchangeUseCase
+}
+
+// NewKeyExchangeHandlers creates a new key exchange handlers instance
+func NewKeyExchangeHandlers(keyExchangeUseCase interfaces.KeyExchangeUseCase) *KeyExchangeHandlers {
+	return &KeyExchangeHandlers{
+		keyExchangeUseCase: keyExchangeUseCase,
+	}
+}
+
+// HandleKeys handles the key relay of a session (POST to send a public key,
+// wrapped content key or rotation, GET to list the messages after ?since=
+// that ?senderKey= or ?viewer= may read)
+func (h *KeyExchangeHandlers) HandleKeys(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handleSendMessage(w, r)
+	case http.MethodGet:
+		h.handleGetMessages(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *KeyExchangeHandlers) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var request dto.SendKeyMessageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxKeyMessageBody)).Decode(&request); err != nil {
+		log.Printf("❌ Invalid key message payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	message, err := h.keyExchangeUseCase.SendMessage(&request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(message); err != nil {
+		log.Printf("Error encoding key message: %v", err)
+	}
+}
+
+func (h *KeyExchangeHandlers) handleGetMessages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dto.GetKeyMessagesRequest{
+		Token:     r.PathValue("token"),
+		PIN:       query.Get("pin"),
+		SenderKey: query.Get("senderKey"),
+		ViewerID:  query.Get("viewer"),
+	}
+	if since := query.Get("since"); since != "" {
+		var err error
+		if request.Since, err = strconv.Atoi(since); err != nil || request.Since < 0 {
+			http.Error(w, "invalid since", 400)
+			return
+		}
+	}
+
+	response, err := h.keyExchangeUseCase.GetMessages(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// Peers poll this when their event stream is down
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding key messages: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestKeyExchangeHandlers_HandleKeys(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		useCaseError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "send public key",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{"type":"public-key","viewerId":"viewer-1","publicKey":"AAAA"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "payload too large",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{"type":"public-key","viewerId":"viewer-1","publicKey":"` + strings.Repeat("A", maxKeyMessageBody) + `"}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid message",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{"type":"private-key"}`,
+			useCaseError:       usecases.ErrInvalidKeyMessage,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "session not encrypted",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{"type":"public-key","viewerId":"viewer-1","publicKey":"AAAA"}`,
+			useCaseError:       usecases.ErrE2EEDisabled,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "wrong sender key",
+			method:             "POST",
+			url:                "/api/sessions/test-token/keys",
+			body:               `{"type":"rotate","keyId":2,"senderKey":"guess"}`,
+			useCaseError:       usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "list messages",
+			method:             "GET",
+			url:                "/api/sessions/test-token/keys?viewer=viewer-1&since=1&pin=123456",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid since",
+			method:             "GET",
+			url:                "/api/sessions/test-token/keys?viewer=viewer-1&since=x",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			url:                "/api/sessions/test-token/keys",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockKeyExchangeUseCase := mocks.NewMockKeyExchangeUseCase()
+			mockKeyExchangeUseCase.SendMessageError = tt.useCaseError
+			mockKeyExchangeUseCase.GetMessagesError = tt.useCaseError
+			handlers := NewKeyExchangeHandlers(mockKeyExchangeUseCase)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleKeys(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if tt.method == "POST" {
+				if request := mockKeyExchangeUseCase.LastSendRequest; request.Token != "test-token" {
+					t.Errorf("Expected the token from the path, got %+v", request)
+				}
+				var message entities.KeyExchangeMessage
+				if err := json.NewDecoder(w.Body).Decode(&message); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if message.Type != entities.KeyMessagePublicKey || message.ViewerID != "viewer-1" || message.PublicKey != "AAAA" {
+					t.Errorf("Unexpected message: %+v", message)
+				}
+				return
+			}
+
+			request := mockKeyExchangeUseCase.LastGetRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.Since != 1 || request.PIN != "123456" {
+				t.Errorf("Unexpected request: %+v", request)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected no-store, got %q", cache)
+			}
+			var response dto.KeyMessagesResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil || len(response.Messages) != 1 {
+				t.Errorf("Unexpected response: %+v, %v", response, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// MetricsHandlers serves operational metrics
+type MetricsHandlers struct {
+	eventMetrics interfaces.EventMetricsProvider
+}
+
+// NewMetricsHandlers creates a new metrics handlers instance
+func NewMetricsHandlers(eventMetrics interfaces.EventMetricsProvider) *MetricsHandlers {
+	return &MetricsHandlers{
+		eventMetrics: eventMetrics,
+	}
+}
+
+// HandleEventMetrics reports event delivery counters, including events
+// dropped and clients closed for falling behind
+func (h *MetricsHandlers) HandleEventMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(h.eventMetrics.Metrics()); err != nil {
+		log.Printf("Error encoding event metrics: %v", err)
+	}
+}
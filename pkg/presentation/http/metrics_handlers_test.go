@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/events"
+)
+
+func TestMetricsHandlers_HandleEventMetrics(t *testing.T) {
+	broker := events.NewBroker(1, events.PolicyClose).(*events.Broker)
+	_, cancel := broker.Subscribe("session")
+	defer cancel()
+	broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+	broker.Publish("session", entities.Event{Type: entities.EventQueueChanged})
+
+	handlers := NewMetricsHandlers(broker)
+
+	tests := []struct {
+		name               string
+		method             string
+		expectedStatusCode int
+	}{
+		{name: "metrics returned", method: "GET", expectedStatusCode: 200},
+		{name: "method not allowed", method: "POST", expectedStatusCode: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/metrics/events", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleEventMetrics(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+
+			var metrics entities.EventMetrics
+			if err := json.Unmarshal(w.Body.Bytes(), &metrics); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+			expected := entities.EventMetrics{BufferSize: 1, Policy: "close", Published: 2, Delivered: 1, Dropped: 1, SlowClosed: 1}
+			if metrics != expected {
+				t.Errorf("Expected %+v, got %+v", expected, metrics)
+			}
+		})
+	}
+}
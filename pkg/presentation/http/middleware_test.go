@@ -0,0 +1,89 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// tagMiddleware appends name to the X-Chain header, to show the order the
+// middlewares ran in
+func tagMiddleware(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestMiddlewareRegistry_Chain(t *testing.T) {
+	registry := NewMiddlewareRegistry()
+	registry.Register("b", tagMiddleware("b"))
+	registry.Register("a", tagMiddleware("a"))
+	registry.Register("c", tagMiddleware("c"))
+
+	if names := registry.Names(); !slices.Equal(names, []string{"a", "b", "c"}) {
+		t.Errorf("Expected sorted names, got %v", names)
+	}
+
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Chain", "app")
+	})
+	handler, err := registry.Chain([]string{"c", "a"}, app)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if chain := w.Header().Values("X-Chain"); !slices.Equal(chain, []string{"c", "a", "app"}) {
+		t.Errorf("Expected c, a, app, got %v", chain)
+	}
+}
+
+func TestMiddlewareRegistry_ChainErrors(t *testing.T) {
+	registry := NewMiddlewareRegistry()
+	registry.Register("cors", tagMiddleware("cors"))
+	app := http.NotFoundHandler()
+
+	if _, err := registry.Chain([]string{"cors", "gzip"}, app); err == nil || !strings.Contains(err.Error(), "available: cors") {
+		t.Errorf("Expected an unknown middleware error listing cors, got %v", err)
+	}
+	if _, err := registry.Chain([]string{"cors", "cors"}, app); err == nil {
+		t.Error("Expected an error for a repeated middleware")
+	}
+	if handler, err := registry.Chain(nil, app); err != nil || handler == nil {
+		t.Errorf("Expected an empty chain to serve the app, got %v", err)
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := SecurityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/viewer?token=abc", nil))
+	for header, expected := range map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"X-Frame-Options":        "SAMEORIGIN",
+		"Referrer-Policy":        "no-referrer",
+	} {
+		if got := w.Header().Get(header); got != expected {
+			t.Errorf("Expected %s: %s, got %q", header, expected, got)
+		}
+	}
+	if w.Header().Get("Strict-Transport-Security") != "" {
+		t.Error("Expected no HSTS over plain HTTP")
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.TLS = &tls.ConnectionState{}
+	handler.ServeHTTP(w, r)
+	if w.Header().Get("Strict-Transport-Security") == "" {
+		t.Error("Expected HSTS over HTTPS")
+	}
+}
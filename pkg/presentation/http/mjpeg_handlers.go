@@ -0,0 +1,127 @@
+package http
+
+import (
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// mjpegBoundary separates the snapshots of an MJPEG stream
+	mjpegBoundary = "frame"
+
+	// mjpegWriteTimeout drops a watcher whose connection stalls mid-write
+	mjpegWriteTimeout = 10 * time.Second
+)
+
+// MJPEGHandlers contains handlers showing a session's screen as pictures:
+// an MJPEG stream and single snapshots
+type MJPEGHandlers struct {
+	frameUseCase interfaces.FrameUseCase
+}
+
+// NewMJPEGHandlers creates a new MJPEG handlers instance
+func NewMJPEGHandlers(frameUseCase interfaces.FrameUseCase) *MJPEGHandlers {
+	return &MJPEGHandlers{
+		frameUseCase: frameUseCase,
+	}
+}
+
+// HandleMJPEG streams snapshots of an SFU session's screen as
+// multipart/x-mixed-replace JPEGs, which browsers too old for WebRTC, such
+// as those of older smart TVs, show like an image that keeps changing. It
+// takes the token and, for protected sessions, the PIN from the query.
+func (h *MJPEGHandlers) HandleMJPEG(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 MJPEG: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	stream, err := h.frameUseCase.WatchFrames(&dto.WatchFramesRequest{
+		Token: r.URL.Query().Get("token"),
+		PIN:   r.URL.Query().Get("pin"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+	defer stream.Stop()
+
+	parts := multipart.NewWriter(w)
+	if err := parts.SetBoundary(mjpegBoundary); err != nil {
+		http.Error(w, "internal server error", 500)
+		return
+	}
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	// Each write gets its own deadline; the connection may be reused afterwards
+	rc := http.NewResponseController(w)
+	defer func() { _ = rc.SetWriteDeadline(time.Time{}) }()
+	_ = rc.SetWriteDeadline(time.Now().Add(mjpegWriteTimeout))
+	w.WriteHeader(200)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case frame, ok := <-stream.Frames:
+			if !ok {
+				// The session ended or its stream left VP8
+				return
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(mjpegWriteTimeout))
+			part, err := parts.CreatePart(textproto.MIMEHeader{
+				"Content-Type":   {"image/jpeg"},
+				"Content-Length": {strconv.Itoa(len(frame))},
+			})
+			if err != nil {
+				return
+			}
+			if _, err := part.Write(frame); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// HandleSnapshot returns a PNG of an SFU session's screen, e.g. for the
+// thumbnails of the admin dashboard or to check the right screen is shared.
+// It takes the token and, for protected sessions, the PIN from the query.
+func (h *MJPEGHandlers) HandleSnapshot(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 Snapshot: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	snapshot, err := h.frameUseCase.Snapshot(&dto.SnapshotRequest{
+		Token: r.URL.Query().Get("token"),
+		PIN:   r.URL.Query().Get("pin"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(snapshot)))
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write(snapshot); err != nil {
+		log.Printf("Error writing snapshot: %v", err)
+	}
+}
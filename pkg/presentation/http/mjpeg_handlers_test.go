@@ -0,0 +1,125 @@
+package http
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestMJPEGHandlers_HandleMJPEG(t *testing.T) {
+	mockUseCase := mocks.NewMockFrameUseCase()
+	mockUseCase.Frames = [][]byte{[]byte("first-jpeg"), []byte("second-jpeg")}
+	handlers := NewMJPEGHandlers(mockUseCase)
+
+	req := httptest.NewRequest("GET", "/mjpeg?token=test-token&pin=123456", nil)
+	w := httptest.NewRecorder()
+
+	handlers.HandleMJPEG(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	if request := mockUseCase.LastWatchRequest; request.Token != "test-token" || request.PIN != "123456" {
+		t.Errorf("Unexpected watch request: %+v", request)
+	}
+	if !mockUseCase.Stopped {
+		t.Error("Expected the watch to be stopped")
+	}
+
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if err != nil || mediaType != "multipart/x-mixed-replace" {
+		t.Fatalf("Expected a multipart/x-mixed-replace stream, got %q", w.Header().Get("Content-Type"))
+	}
+	reader := multipart.NewReader(w.Body, params["boundary"])
+	for _, want := range []string{"first-jpeg", "second-jpeg"} {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("Expected a part, got %v", err)
+		}
+		if part.Header.Get("Content-Type") != "image/jpeg" {
+			t.Errorf("Expected a JPEG part, got %q", part.Header.Get("Content-Type"))
+		}
+		if body, _ := io.ReadAll(part); string(body) != want {
+			t.Errorf("Expected %q, got %q", want, body)
+		}
+	}
+}
+
+func TestMJPEGHandlers_HandleMJPEG_Errors(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "method not allowed", method: "POST", expectedStatusCode: 405},
+		{name: "wrong PIN", method: "GET", err: usecases.ErrInvalidPIN, expectedStatusCode: 403},
+		{name: "peer-to-peer session", method: "GET", err: usecases.ErrSFUDisabled, expectedStatusCode: 404},
+		{name: "no VP8 video", method: "GET", err: usecases.ErrFramesUnavailable, expectedStatusCode: 404},
+		{name: "ended session", method: "GET", err: usecases.ErrSessionEnded, expectedStatusCode: 410},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockFrameUseCase()
+			mockUseCase.WatchFramesError = tt.err
+			handlers := NewMJPEGHandlers(mockUseCase)
+
+			req := httptest.NewRequest(tt.method, "/mjpeg?token=test-token", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleMJPEG(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestMJPEGHandlers_HandleSnapshot(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "snapshot", method: "GET", expectedStatusCode: 200},
+		{name: "method not allowed", method: "POST", expectedStatusCode: 405},
+		{name: "wrong PIN", method: "GET", err: usecases.ErrInvalidPIN, expectedStatusCode: 403},
+		{name: "peer-to-peer session", method: "GET", err: usecases.ErrSFUDisabled, expectedStatusCode: 404},
+		{name: "no VP8 video", method: "GET", err: usecases.ErrFramesUnavailable, expectedStatusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockFrameUseCase()
+			mockUseCase.Frames = [][]byte{[]byte("png")}
+			mockUseCase.SnapshotError = tt.err
+			handlers := NewMJPEGHandlers(mockUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/snapshot?token=test-token&pin=123456", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandleSnapshot(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			if request := mockUseCase.LastSnapshotRequest; request.Token != "test-token" || request.PIN != "123456" {
+				t.Errorf("Unexpected snapshot request: %+v", request)
+			}
+			if w.Header().Get("Content-Type") != "image/png" || w.Body.String() != "png" {
+				t.Errorf("Expected the PNG snapshot, got %q %q", w.Header().Get("Content-Type"), w.Body.String())
+			}
+		})
+	}
+}
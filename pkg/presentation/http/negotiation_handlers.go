@@ -0,0 +1,93 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxNegotiationBody bounds a relayed negotiation message; an SDP with
+// several tracks stays well below it
+const maxNegotiationBody = 64 << 10
+
+// NegotiationHandlers contains handlers for the renegotiation relay of
+// connected peers
+type NegotiationHandlers struct {
+	negotiationUseCase interfaces.NegotiationUseCase
+}
+
+// NewNegotiationHandlers creates a new negotiation handlers instance
+func NewNegotiationHandlers(negotiationUseCase interfaces.NegotiationUseCase) *NegotiationHandlers {
+	return &NegotiationHandlers{
+		negotiationUseCase: negotiationUseCase,
+	}
+}
+
+// HandleNegotiation handles the negotiation relay of a session (POST to send
+// a description or candidate, GET to list the messages on ?viewer='s
+// connection after ?since=)
+func (h *NegotiationHandlers) HandleNegotiation(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handleSendMessage(w, r)
+	case http.MethodGet:
+		h.handleGetMessages(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *NegotiationHandlers) handleSendMessage(w http.ResponseWriter, r *http.Request) {
+	var request dto.SendNegotiationMessageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxNegotiationBody)).Decode(&request); err != nil {
+		log.Printf("❌ Invalid negotiation payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	message, err := h.negotiationUseCase.SendMessage(&request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(message); err != nil {
+		log.Printf("Error encoding negotiation message: %v", err)
+	}
+}
+
+func (h *NegotiationHandlers) handleGetMessages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := &dto.GetNegotiationMessagesRequest{
+		Token:    r.PathValue("token"),
+		PIN:      query.Get("pin"),
+		ViewerID: query.Get("viewer"),
+	}
+	if since := query.Get("since"); since != "" {
+		var err error
+		if request.Since, err = strconv.Atoi(since); err != nil || request.Since < 0 {
+			http.Error(w, "invalid since", 400)
+			return
+		}
+	}
+
+	response, err := h.negotiationUseCase.GetMessages(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// Peers poll this when their event stream is down
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding negotiation messages: %v", err)
+	}
+}
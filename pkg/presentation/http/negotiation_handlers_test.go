@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestNegotiationHandlers_HandleNegotiation(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		url                string
+		body               string
+		useCaseError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "send offer",
+			method:             "POST",
+			url:                "/api/sessions/test-token/negotiation",
+			body:               `{"from":"sender","viewerId":"viewer-1","description":{"type":"offer","sdp":"sdp"}}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			url:                "/api/sessions/test-token/negotiation",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "payload too large",
+			method:             "POST",
+			url:                "/api/sessions/test-token/negotiation",
+			body:               `{"from":"sender","viewerId":"viewer-1","description":{"type":"offer","sdp":"` + strings.Repeat("a", maxNegotiationBody) + `"}}`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid message",
+			method:             "POST",
+			url:                "/api/sessions/test-token/negotiation",
+			body:               `{"from":"sender","viewerId":"viewer-1"}`,
+			useCaseError:       usecases.ErrInvalidNegotiation,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "viewer not connected",
+			method:             "POST",
+			url:                "/api/sessions/test-token/negotiation",
+			body:               `{"from":"viewer","viewerId":"viewer-2","candidate":{"candidate":"candidate:1"}}`,
+			useCaseError:       usecases.ErrViewerNotConnected,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "list messages",
+			method:             "GET",
+			url:                "/api/sessions/test-token/negotiation?viewer=viewer-1&since=1&pin=123456",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid since",
+			method:             "GET",
+			url:                "/api/sessions/test-token/negotiation?viewer=viewer-1&since=-1",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			url:                "/api/sessions/test-token/negotiation",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockNegotiationUseCase := mocks.NewMockNegotiationUseCase()
+			mockNegotiationUseCase.SendMessageError = tt.useCaseError
+			mockNegotiationUseCase.GetMessagesError = tt.useCaseError
+			handlers := NewNegotiationHandlers(mockNegotiationUseCase)
+
+			req := httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleNegotiation(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			if tt.method == "POST" {
+				if request := mockNegotiationUseCase.LastSendRequest; request.Token != "test-token" {
+					t.Errorf("Expected the token from the path, got %+v", request)
+				}
+				var message entities.NegotiationMessage
+				if err := json.NewDecoder(w.Body).Decode(&message); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if message.Description == nil || message.Description.Type != "offer" || message.ViewerID != "viewer-1" {
+					t.Errorf("Unexpected message: %+v", message)
+				}
+				return
+			}
+
+			request := mockNegotiationUseCase.LastGetRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.Since != 1 || request.PIN != "123456" {
+				t.Errorf("Unexpected request: %+v", request)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected no-store, got %q", cache)
+			}
+			var response dto.NegotiationMessagesResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Messages) != 1 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
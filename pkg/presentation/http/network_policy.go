@@ -0,0 +1,95 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// NetworkPolicy restricts the signaling endpoints to clients on allowed
+// networks, so a server reachable from the internet still only pairs peers
+// on the LAN. Loopback is always allowed for a sender on the same machine.
+// Behind a reverse proxy the proxy's address is what gets checked.
+type NetworkPolicy struct {
+	networks []netip.Prefix
+	allowAll bool
+}
+
+// NewNetworkPolicy creates a policy allowing the given CIDR ranges or single
+// addresses; "*" allows every client
+func NewNetworkPolicy(networks []string) (*NetworkPolicy, error) {
+	p := &NetworkPolicy{}
+	for _, network := range networks {
+		network = strings.TrimSpace(network)
+		switch {
+		case network == "":
+			continue
+		case network == "*":
+			p.allowAll = true
+			continue
+		}
+
+		prefix, err := entities.ParseNetwork(network)
+		if err != nil {
+			return nil, fmt.Errorf("allowed network %w", err)
+		}
+		p.networks = append(p.networks, prefix)
+	}
+	return p, nil
+}
+
+// Allows reports whether a client at remoteAddr, in http.Request.RemoteAddr
+// form, may use the signaling endpoints
+func (p *NetworkPolicy) Allows(remoteAddr string) bool {
+	if p.allowAll {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	if addr.IsLoopback() {
+		return true
+	}
+	for _, network := range p.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client of r without its port; behind
+// a reverse proxy it is the proxy's, as for the policy
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Wrap rejects requests from outside the allowed networks with 403 before
+// they reach next
+func (p *NetworkPolicy) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !p.Allows(r.RemoteAddr) {
+			log.Printf("🚫 Signaling request %s %s refused from %s", r.Method, r.URL.Path, r.RemoteAddr)
+			http.Error(w, "forbidden: outside the allowed networks", 403)
+			return
+		}
+		next(w, r)
+	}
+}
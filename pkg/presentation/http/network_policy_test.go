@@ -0,0 +1,110 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// privateNetworks are the RFC 1918 ranges the server allows by default
+var privateNetworks = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+
+func TestNetworkPolicy(t *testing.T) {
+	tests := []struct {
+		name               string
+		networks           []string
+		remoteAddr         string
+		expectedStatusCode int
+	}{
+		{
+			name:               "LAN client with default ranges",
+			networks:           privateNetworks,
+			remoteAddr:         "192.168.1.20:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "internet client with default ranges",
+			networks:           privateNetworks,
+			remoteAddr:         "203.0.113.7:51000",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "loopback is always allowed",
+			networks:           []string{"10.0.0.0/8"},
+			remoteAddr:         "127.0.0.1:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "IPv6 loopback is always allowed",
+			networks:           []string{"10.0.0.0/8"},
+			remoteAddr:         "[::1]:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "IPv4-mapped IPv6 address",
+			networks:           privateNetworks,
+			remoteAddr:         "[::ffff:10.1.2.3]:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "client outside a custom range",
+			networks:           []string{"192.168.50.0/24"},
+			remoteAddr:         "192.168.1.20:51000",
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "single address",
+			networks:           []string{"198.51.100.4"},
+			remoteAddr:         "198.51.100.4:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "IPv6 range",
+			networks:           []string{"fd00::/8"},
+			remoteAddr:         "[fd12:3456::1]:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "everyone allowed",
+			networks:           []string{"*"},
+			remoteAddr:         "203.0.113.7:51000",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "unparseable remote address",
+			networks:           privateNetworks,
+			remoteAddr:         "pipe",
+			expectedStatusCode: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewNetworkPolicy(tt.networks)
+			if err != nil {
+				t.Fatalf("NewNetworkPolicy failed: %v", err)
+			}
+			handler := policy.Wrap(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(200)
+			})
+
+			req := httptest.NewRequest("GET", "/api/v1/offer?token=test-token", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestNewNetworkPolicy_Invalid(t *testing.T) {
+	for _, network := range []string{"10.0.0.0/33", "lan", "192.168.1"} {
+		if _, err := NewNetworkPolicy([]string{network}); err == nil {
+			t.Errorf("Expected an error for %q", network)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"share-screen/pkg/annotation"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/probe"
+	"share-screen/pkg/usecase/dto"
+)
+
+// apiOperation describes one endpoint for the OpenAPI document. Request and
+// response schemas are generated from the DTO types so the spec follows the
+// code; fields the handler fills from the path are listed in pathFields,
+// optional marks a request body that may be left empty, and rawBody one sent
+// as raw bytes rather than JSON.
+type apiOperation struct {
+	method      string
+	path        string
+	summary     string
+	query       []string
+	body        interface{}
+	optional    bool
+	rawBody     bool
+	pathFields  []string
+	response    interface{}
+	status      int
+	contentType string
+}
+
+// apiOperations lists every endpoint served under /api/v1
+var apiOperations = []apiOperation{
+	{method: "POST", path: "/new", summary: "Create a session; needs a bearer JWT when the server is configured with one (401 otherwise). 429 with Retry-After once the server's session limit is reached. template, in the query or body, starts it from a session template, whose options take precedence; 404 for an unknown template", query: []string{"template"}, body: dto.CreateSessionRequest{}, response: dto.CreateSessionResponse{}, status: 200},
+	{method: "POST", path: "/offer", summary: "Publish or replace the sender's WebRTC offer; replacing takes the senderKey (403 without it) and the connected viewer is told to renegotiate. With iceRestart, which also takes the senderKey, the connected viewer answers it on its connection instead; 404 without one", body: dto.SubmitOfferRequest{}, status: 204},
+	{method: "GET", path: "/offer", summary: "Fetch the sender's offer as a viewer; 404 until posted, 403 for a wrong PIN, 429 once too many wrong PINs locked the session, 409 when the session is full, 410 once a single-use link was used by another viewer. X-ICE-Generation carries the offer's ICE restart count, and X-Session-E2EE says the media is end-to-end encrypted", query: []string{"token", "viewer", "pin"}, response: entities.WebRTCOffer{}, status: 200},
+	{method: "DELETE", path: "/offer", summary: "Clear the sender's offer and answer and return the session to pending, e.g. to capture another window under the same token; a connected viewer is told to renegotiate. 403 for a wrong senderKey, 409 for SFU sessions", query: []string{"token", "senderKey"}, status: 204},
+	{method: "POST", path: "/answer", summary: "Publish the viewer's WebRTC answer; the first answer uses up a single-use link. 403 for a wrong PIN, 429 once too many wrong PINs locked the session, 409 when iceGeneration is not that of the offer", body: dto.SubmitAnswerRequest{}, status: 204},
+	{method: "GET", path: "/answer", summary: "Fetch the viewer's answer as the sender, with the viewer's ID and percent-encoded name in the X-Viewer-ID and X-Viewer-Name headers; 404 until posted", query: []string{"token"}, response: entities.WebRTCAnswer{}, status: 200},
+	{method: "GET", path: "/session", summary: "Where a session stands: status, timestamps, whether the offer and answer are there and how many viewers are connected or queued; ended and expired sessions are described until they are cleaned up, then 404", query: []string{"token"}, response: dto.SessionDetailResponse{}, status: 200},
+	{method: "GET", path: "/info", summary: "Server and network information, with the garbage collection schedule and its last run", response: entities.ServerInfo{}, status: 200},
+	{method: "GET", path: "/interfaces", summary: "Network interfaces with the addresses viewer links may use, marking the one the server picked", response: dto.InterfacesResponse{}, status: 200},
+	{method: "GET", path: "/health", summary: "Server health; 503 when the last garbage collection failed or the scheduled ones stopped running", response: dto.HealthResponse{}, status: 200},
+	{method: "GET", path: "/capabilities", summary: "Optional subsystems that work on this deployment, so clients hide controls that would fail", response: entities.Capabilities{}, status: 200},
+	{method: "GET", path: "/annotations/schema", summary: "Message types, palette and limits of the strokes viewers draw for the sender over the annotations data channel", response: annotation.Schema{}, status: 200},
+	{method: "GET", path: "/probe/schema", summary: "Messages and timing of the latency probe: pings the sender and viewer pages echo back over the probe data channel to time their round trips", response: probe.Schema{}, status: 200},
+	{method: "GET", path: "/presets", summary: "Quality presets a sender can name when creating a session", response: dto.PresetsResponse{}, status: 200},
+	{method: "GET", path: "/templates", summary: "Session templates a sender can name when creating a session, by name", response: dto.SessionTemplatesResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/heartbeat", summary: "Keep the session alive from the sender", body: dto.HeartbeatRequest{}, optional: true, pathFields: []string{"token"}, status: 204},
+	{method: "POST", path: "/session/extend", summary: "Give a live session the full token expiry again from now, so a long share is not cleaned up while it runs. 403 for a wrong senderKey", body: dto.ExtendSessionRequest{}, response: dto.ExtendSessionResponse{}, status: 200},
+	{method: "GET", path: "/sender/sessions", summary: "Live sessions started by this browser, known by its sender cookie, for the landing page", response: dto.OwnSessionsResponse{}, status: 200},
+	{method: "GET", path: "/sender/profile", summary: "Defaults of the sender named by the signed profile cookie, which the browser is given on its first request; empty until saved. 404 when profiles are disabled", response: dto.SenderProfileResponse{}, status: 200},
+	{method: "PUT", path: "/sender/profile", summary: "Replace the sender's defaults: a quality preset, sharing sound, the LAN address in links and a contrast theme of high or normal. 400 for unknown values", body: dto.UpdateSenderProfileRequest{}, response: dto.SenderProfileResponse{}, status: 200},
+	{method: "DELETE", path: "/sender/profile", summary: "Forget the sender's defaults", status: 204},
+	{method: "POST", path: "/sessions/{token}/rename", summary: "Rename a session; 403 unless this browser started it", body: dto.RenameSessionRequest{}, pathFields: []string{"token"}, status: 204},
+	{method: "POST", path: "/sessions/{token}/viewers/{viewer}/kick", summary: "Remove a viewer from a session: its connection or queue place goes and its viewer ID is refused from then on. 403 without the sender key", body: dto.KickViewerRequest{}, pathFields: []string{"token", "viewerId"}, status: 204},
+	{method: "POST", path: "/pause", summary: "Pause the sender's stream, or resume it with paused false; viewers cover the picture while it is paused. 403 for a wrong senderKey", body: dto.PauseSessionRequest{}, status: 204},
+	{method: "POST", path: "/sessions/{token}/end", summary: "End the session; with resume=1 the sender page may come back, e.g. after a reload, and the session waits 30 seconds for it. 403 without the sender key", body: dto.EndSessionRequest{}, pathFields: []string{"token"}, query: []string{"resume"}, status: 204},
+	{method: "POST", path: "/sessions/{token}/resume", summary: "Reattach a reloaded sender page to its session with the sender key it was created with, returning the session's options; 403 for a wrong key", body: dto.ResumeSessionRequest{}, pathFields: []string{"token"}, response: dto.CreateSessionResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/publish", summary: "Publish the sender's stream to the server's SFU, which forwards it to every viewer; replacing a published stream takes the senderKey (403 without it); 404 unless the session was created with sfu", body: dto.PublishStreamRequest{}, pathFields: []string{"token"}, response: entities.WebRTCAnswer{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/layer", summary: "Pick the simulcast layer (low, mid, high or auto) an SFU viewer receives; 404 unless the viewer is connected to the SFU", body: dto.SelectLayerRequest{}, pathFields: []string{"token"}, status: 204},
+	{method: "GET", path: "/snapshot", summary: "PNG of an SFU session's screen, decoded on the server from the latest keyframe of its VP8 video, e.g. for thumbnails; 404 for peer-to-peer sessions and other codecs, or when no keyframe arrives within five seconds", query: []string{"token", "pin"}, status: 200, contentType: "image/png"},
+	{method: "GET", path: "/sessions/{token}/events", summary: "Server-sent event stream of queue, viewer, quality, pause and soft limit events", query: []string{"viewer"}, status: 200, contentType: "text/event-stream"},
+	{method: "GET", path: "/sessions/{token}/ice-config", summary: "STUN and TURN servers for the session's peers, usable as an RTCConfiguration; 403 for a wrong PIN, 429 once the session is locked", query: []string{"pin"}, response: dto.ICEConfigResponse{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/turn-credentials", summary: "Short-lived TURN credential minted from the secret shared with the TURN server; 404 when none is configured", query: []string{"pin"}, response: dto.TURNCredentialsResponse{}, status: 200},
+	{method: "GET", path: "/metrics/events", summary: "Event delivery counters, including events dropped and clients closed for falling behind", response: entities.EventMetrics{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/queue", summary: "Join the viewer queue, or reserve the free slot (position 0)", body: dto.JoinQueueRequest{}, optional: true, pathFields: []string{"token"}, response: dto.JoinQueueResponse{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/queue", summary: "List queued viewers", response: dto.GetQueueResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/queue/{viewer}/leave", summary: "Remove a viewer from the queue", status: 204},
+	{method: "POST", path: "/sessions/{token}/queue/{viewer}/promote", summary: "Move a viewer to the front of the queue", status: 204},
+	{method: "POST", path: "/sessions/{token}/leave", summary: "Free the slot held by the connected viewer", status: 204},
+	{method: "POST", path: "/chat", summary: "Relay a chat message for a session created with chat, until the peers' data channel is open; 404 when chat is not enabled", body: dto.SendChatMessageRequest{}, response: entities.ChatMessage{}, status: 200},
+	{method: "GET", path: "/chat", summary: "Chat messages relayed for a session after the message ID in since", query: []string{"token", "since", "pin"}, response: dto.ChatMessagesResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/negotiation", summary: "Relay a description or candidate of the perfect negotiation between the sender and the connected viewer, to add or remove tracks mid-session; 404 unless viewerId holds the peer-to-peer connection", body: dto.SendNegotiationMessageRequest{}, pathFields: []string{"token"}, response: entities.NegotiationMessage{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/negotiation", summary: "Negotiation messages relayed on the viewer's connection after the sequence number in since", query: []string{"viewer", "since", "pin"}, response: dto.NegotiationMessagesResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/keys", summary: "Relay a key message of an end-to-end encrypted session: a viewer's public-key, or a content-key wrapped for a viewer or a rotate to a new key ID from the sender, who gives its senderKey; the server cannot unwrap the keys. 404 unless the session was created with e2ee", body: dto.SendKeyMessageRequest{}, pathFields: []string{"token"}, response: entities.KeyExchangeMessage{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/keys", summary: "Key messages relayed after the sequence number in since: all of them for the sender's senderKey, a viewer's own and the rotations for viewer", query: []string{"viewer", "senderKey", "since", "pin"}, response: dto.KeyMessagesResponse{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/fingerprints", summary: "DTLS fingerprints announced in the session's offer (sender) and answer (viewer), for the people at both ends to read out and compare with their pages; the short form is the last 6 bytes. 404 for SFU sessions, whose viewers connect to the server", query: []string{"pin", "senderKey"}, response: dto.FingerprintsResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/files", summary: "Relay a file to the session's viewers until the peers' data channel is open; 413 once the session's relay limit is used up, 404 when the relay is off", query: []string{"name"}, rawBody: true, response: entities.SharedFile{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/files", summary: "Files relayed in a session, oldest first", query: []string{"pin"}, response: dto.FilesResponse{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/files/{id}", summary: "Download a relayed file", query: []string{"pin"}, status: 200, contentType: "application/octet-stream"},
+	{method: "GET", path: "/rooms/{name}", summary: "The session a named room currently leads to; the token is only set while that session is live", response: dto.RoomResponse{}, status: 200},
+	{method: "PUT", path: "/rooms/{name}", summary: "Point a room at a live session; a free name is claimed and its key returned, a taken one needs that key (403 otherwise)", body: dto.ClaimRoomRequest{}, pathFields: []string{"name"}, response: dto.ClaimRoomResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/links", summary: "Hand out a viewer link that expires after ttlSeconds and, with maxRedemptions, once that many viewers opened it, ahead of the session itself; viewers open its path. 403 without the sender key, 409 once the session has 20 unexpired links", body: dto.CreateViewerLinkRequest{}, response: dto.ViewerLink{}, status: 201},
+	{method: "GET", path: "/sessions/{token}/links", summary: "The session's viewer links, oldest first, with how often each was opened; 403 without the sender key", query: []string{"senderKey"}, response: dto.ViewerLinksResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/links/{id}/revoke", summary: "Withdraw a viewer link before it expires; viewers who opened it stay connected. 403 without the sender key", body: dto.RevokeViewerLinkRequest{}, status: 204},
+	{method: "POST", path: "/sessions/{token}/invite", summary: "Post the session's viewer link, never its PIN, to the configured Slack and Discord channels, once per session. 403 without the sender key, 404 when no chat webhook is configured, 409 once posted, 502 when no channel took it", body: dto.PostInviteRequest{}, response: dto.PostInviteResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/pairings", summary: "Ask to trust the requesting device, known by its device cookie, for every session of this session's sender: returns a 6-digit code for the sender to confirm within 5 minutes. 403 for a wrong PIN, 409 for sessions not started from a browser", body: dto.RequestPairingRequest{}, response: dto.PairingCodeResponse{}, status: 201},
+	{method: "POST", path: "/sessions/{token}/pairings/{code}/confirm", summary: "Trust the device showing the code, persisted to the devices file; 403 without the sender key, 404 for an unknown or expired code", body: dto.ConfirmPairingRequest{}, response: dto.TrustedDevice{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/unlock", summary: "The session's PIN, empty for sessions without one, for a device its sender trusts; 403 for other devices", response: dto.UnlockSessionResponse{}, status: 200},
+	{method: "GET", path: "/sender/devices", summary: "Viewer devices the browser that started sessions, known by its sender cookie, trusts, oldest pairing first", response: dto.TrustedDevicesResponse{}, status: 200},
+	{method: "DELETE", path: "/sender/devices/{id}", summary: "Stop trusting a device; viewers already watching stay connected", status: 204},
+	{method: "POST", path: "/links/{id}", summary: "Redeem a viewer link for the token of its session, taking one of its uses unless this viewer opened it before; 410 once it expired or was used up", body: dto.RedeemViewerLinkRequest{}, response: dto.RedeemViewerLinkResponse{}, status: 200},
+	{method: "GET", path: "/sessions/{token}/summary", summary: "Summary of a finished session", response: dto.SessionSummaryResponse{}, status: 200},
+	{method: "PUT", path: "/sessions/{token}/notes", summary: "Replace the notes of a finished session; 403 without the sender key or the sender cookie of the browser that started it", body: dto.UpdateSessionNotesRequest{}, pathFields: []string{"token"}, response: dto.SessionSummaryResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/quality", summary: "Ask the sender to cap the frame rate (0 removes the cap)", body: dto.QualityRequest{}, pathFields: []string{"token"}, status: 204},
+	{method: "GET", path: "/viewer/settings", summary: "Settings of the requesting device", response: dto.ViewerSettingsResponse{}, status: 200},
+	{method: "PUT", path: "/viewer/settings", summary: "Replace the settings of the requesting device", body: dto.UpdateViewerSettingsRequest{}, response: dto.ViewerSettingsResponse{}, status: 200},
+	{method: "GET", path: "/status", summary: "Whether anyone is viewing; needs the status bearer token, and with If-None-Match and wait (seconds, at most 60) is held until the status changes, answering 304 on timeout", query: []string{"wait"}, response: dto.ViewerStatusResponse{}, status: 200},
+	{method: "POST", path: "/stats", summary: "Upload a summary of a peer's WebRTC stats (bitrate, fps, packet loss, RTT) for the session's time series", body: dto.RecordStatsRequest{}, status: 204},
+	{method: "GET", path: "/ping", summary: "Answer at once, for pages to time a round trip to the server; network is lan when the client's address is private, link-local or loopback and internet otherwise", response: dto.PingResponse{}, status: 200},
+	{method: "POST", path: "/sessions/{token}/latency", summary: "Record the round-trip time a peer measured with the latency probe, to the other peer over the probe data channel (path peer) or to the server's ping endpoint (path server), replacing its earlier result for the path", body: dto.RecordLatencyRequest{}, pathFields: []string{"token"}, status: 204},
+	{method: "GET", path: "/sessions/{token}/viewer-stats", summary: "Connection quality of each viewer still uploading stats, for the sender: the latest sample, the averages of the last windowSeconds and a rating of good, fair or poor from the average packet loss and RTT, and the viewer's latest latency probe results; needs the sender key", query: []string{"senderKey"}, response: dto.ViewerStatsResponse{}, status: 200},
+	{method: "GET", path: "/stats/summary", summary: "Today's session totals in the server's local time: sessions created, completed by the sender and expired, the average duration of those that ended, the peak and current number of live sessions; needs the admin bearer token or a JWT with the admin scope, 404 when neither is configured", response: dto.StatsSummaryResponse{}, status: 200},
+	{method: "GET", path: "/admin/sessions", summary: "Live sessions with the latest stats of each peer; needs the admin bearer token or a JWT with the admin scope, 404 when neither is configured", response: dto.AdminSessionsResponse{}, status: 200},
+	{method: "GET", path: "/admin/sessions/{token}/stats", summary: "Stats time series of a session, oldest first, optionally only the samples after since (RFC 3339), and the latest latency probe result of each peer and path; needs admin credentials", query: []string{"since"}, response: dto.SessionStatsResponse{}, status: 200},
+	{method: "GET", path: "/admin/sessions/{token}/audit", summary: "Append-only audit log of a session's lifecycle events with client addresses; format=jsonl exports it as JSON Lines. Needs admin credentials", query: []string{"format"}, response: dto.AuditLogResponse{}, status: 200},
+	{method: "POST", path: "/admin/sessions/{token}/viewers/{viewer}/kick", summary: "Remove a viewer from any session, as its sender can. Needs admin credentials", status: 204},
+	{method: "POST", path: "/admin/cleanup", summary: "Remove ended, stale and expired sessions now instead of at the next scheduled collection, returning what was removed. Needs admin credentials", response: entities.CleanupRun{}, status: 200},
+	{method: "GET", path: "/admin/bans", summary: "Banned IP addresses and CIDR ranges; banned clients get 403 from every signaling endpoint. Needs admin credentials", response: dto.BansResponse{}, status: 200},
+	{method: "POST", path: "/admin/bans", summary: "Ban an IP address or CIDR range, persisted to the ban file. Loopback addresses cannot be banned. Needs admin credentials", body: dto.AddBanRequest{}, response: entities.Ban{}, status: 200},
+	{method: "DELETE", path: "/admin/bans", summary: "Lift the ban on an IP address or CIDR range. Needs admin credentials", query: []string{"network"}, status: 204},
+	{method: "PUT", path: "/admin/templates/{name}", summary: "Define a session template, replacing any of the same name, persisted to the templates file; sessions already started from it keep their options. 400 for options out of bounds. Needs admin credentials", body: dto.SaveSessionTemplateRequest{}, response: entities.SessionTemplate{}, status: 200},
+	{method: "DELETE", path: "/admin/templates/{name}", summary: "Remove a session template. Needs admin credentials", status: 204},
+	{method: "GET", path: "/admin/feed", summary: "WebSocket of session events (type session, data an audit event) for the admin dashboard. Needs admin credentials in the Authorization header or as a first {\"token\": \"...\"} message", status: 101},
+	{method: "GET", path: "/recordings", summary: "Recordings of SFU sessions in the recordings directory, oldest first, with the space they take up and the limits the janitor keeps them within; needs admin credentials, 404 when recordings are disabled", response: dto.RecordingsResponse{}, status: 200},
+	{method: "GET", path: "/recordings/{name}", summary: "Download a recording as Matroska, with range requests for seeking. Needs admin credentials", status: 200, contentType: "video/x-matroska"},
+	{method: "DELETE", path: "/recordings/{name}", summary: "Delete a recording. Needs admin credentials", status: 204},
+	{method: "GET", path: "/spec.json", summary: "This OpenAPI document", status: 200},
+}
+
+// pathParamPattern matches {name} segments in a route path
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPIHandlers serves the OpenAPI description of the HTTP API
+type OpenAPIHandlers struct {
+	spec []byte
+}
+
+// NewOpenAPIHandlers creates a new OpenAPI handlers instance; version is the
+// application version reported in the document
+func NewOpenAPIHandlers(version string) *OpenAPIHandlers {
+	spec, err := json.MarshalIndent(buildOpenAPISpec(version), "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to build OpenAPI document: %v", err)
+	}
+	return &OpenAPIHandlers{spec: spec}
+}
+
+// HandleSpec serves the OpenAPI document
+func (h *OpenAPIHandlers) HandleSpec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(h.spec); err != nil {
+		log.Printf("Error writing OpenAPI document: %v", err)
+	}
+}
+
+// buildOpenAPISpec assembles the OpenAPI 3.0 document from apiOperations
+func buildOpenAPISpec(version string) map[string]interface{} {
+	schemas := make(map[string]interface{})
+	paths := make(map[string]map[string]interface{})
+
+	for _, op := range apiOperations {
+		operation := map[string]interface{}{
+			"summary":     op.summary,
+			"operationId": operationID(op),
+		}
+
+		var params []interface{}
+		for _, match := range pathParamPattern.FindAllStringSubmatch(op.path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": match[1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, name := range op.query {
+			params = append(params, map[string]interface{}{
+				"name": name, "in": "query", "required": name == "token",
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+
+		if op.rawBody {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/octet-stream": map[string]interface{}{
+						"schema": map[string]interface{}{"type": "string", "format": "binary"},
+					},
+				},
+			}
+		} else if op.body != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": !op.optional,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": bodySchema(op, schemas),
+					},
+				},
+			}
+		}
+
+		success := map[string]interface{}{"description": http.StatusText(op.status)}
+		switch {
+		case op.contentType != "":
+			success["content"] = map[string]interface{}{
+				op.contentType: map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+			}
+		case op.response != nil:
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schemaFor(reflect.TypeOf(op.response), schemas),
+				},
+			}
+		}
+		operation["responses"] = map[string]interface{}{
+			strconv.Itoa(op.status): success,
+			"default": map[string]interface{}{
+				"description": "Error message as plain text",
+				"content": map[string]interface{}{
+					"text/plain": map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+				},
+			},
+		}
+
+		if paths[op.path] == nil {
+			paths[op.path] = make(map[string]interface{})
+		}
+		paths[op.path][strings.ToLower(op.method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Share Screen API",
+			"description": "WebRTC signaling for browser screen sharing. The unversioned /api paths are aliases of v1.",
+			"version":     version,
+		},
+		"servers":    []interface{}{map[string]interface{}{"url": versionedAPIPrefix}},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": schemas},
+	}
+}
+
+// bodySchema returns the request body schema, leaving out fields taken from the path
+func bodySchema(op apiOperation, schemas map[string]interface{}) interface{} {
+	t := reflect.TypeOf(op.body)
+	if len(op.pathFields) == 0 {
+		return schemaFor(t, schemas)
+	}
+	return structSchema(t, schemas, op.pathFields...)
+}
+
+// schemaFor converts a Go type to a JSON schema, registering named structs
+// under components/schemas and referring to them
+func schemaFor(t reflect.Type, schemas map[string]interface{}) interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		name := t.Name()
+		if _, exists := schemas[name]; !exists {
+			schemas[name] = nil // guards against recursive types
+			schemas[name] = structSchema(t, schemas)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	case t.Kind() == reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case t.Kind() == reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case t.Kind() == reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema builds an object schema from a struct's JSON fields
+func structSchema(t reflect.Type, schemas map[string]interface{}, skip ...string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() || slices.Contains(skip, name) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		properties[name] = schemaFor(field.Type, schemas)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// operationID derives a stable identifier such as "postSessionsTokenQueue"
+func operationID(op apiOperation) string {
+	id := strings.ToLower(op.method)
+	for _, part := range strings.FieldsFunc(op.path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '.'
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
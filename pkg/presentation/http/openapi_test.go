@@ -0,0 +1,70 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIHandlers_HandleSpec(t *testing.T) {
+	handlers := NewOpenAPIHandlers("1.2.3")
+
+	req := httptest.NewRequest("GET", "/api/v1/spec.json", nil)
+	w := httptest.NewRecorder()
+	handlers.HandleSpec(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Info    struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Spec is not valid JSON: %v", err)
+	}
+
+	if spec.OpenAPI == "" || spec.Info.Version != "1.2.3" {
+		t.Errorf("Unexpected spec header: %s %s", spec.OpenAPI, spec.Info.Version)
+	}
+
+	for _, op := range apiOperations {
+		if _, ok := spec.Paths[op.path][strings.ToLower(op.method)]; !ok {
+			t.Errorf("Missing operation %s %s", op.method, op.path)
+		}
+	}
+
+	offer, ok := spec.Components.Schemas["SubmitOfferRequest"]
+	if !ok {
+		t.Fatal("Expected SubmitOfferRequest schema")
+	}
+	if _, ok := offer.Properties["sdp"]; !ok {
+		t.Error("Expected sdp property on SubmitOfferRequest")
+	}
+}
+
+func TestBodySchema_SkipsPathFields(t *testing.T) {
+	for _, op := range apiOperations {
+		if op.path != "/sessions/{token}/quality" {
+			continue
+		}
+		schema := bodySchema(op, map[string]interface{}{}).(map[string]interface{})
+		properties := schema["properties"].(map[string]interface{})
+		if _, ok := properties["token"]; ok {
+			t.Error("Expected token to be left out of the quality body")
+		}
+		if _, ok := properties["maxFrameRate"]; !ok {
+			t.Error("Expected maxFrameRate in the quality body")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// contrastCookie stores the display contrast chosen with the page toggle
+	contrastCookie = "contrast"
+
+	// preferenceMaxAge keeps display preferences for a year
+	preferenceMaxAge = 365 * 24 * time.Hour
+)
+
+// Contrast values stored in the contrast cookie; no cookie follows the system setting
+const (
+	ContrastHigh   = "high"
+	ContrastNormal = "normal"
+)
+
+// Preferences holds per-browser display preferences
+type Preferences struct {
+	Contrast string
+}
+
+// readPreferences loads display preferences from the request cookies
+func readPreferences(r *http.Request) Preferences {
+	var prefs Preferences
+	if cookie, err := r.Cookie(contrastCookie); err == nil {
+		switch cookie.Value {
+		case ContrastHigh, ContrastNormal:
+			prefs.Contrast = cookie.Value
+		}
+	}
+	return prefs
+}
+
+// HandlePreferences stores display preferences posted by the page header form
+// and sends the browser back to the page it came from
+func (h *StaticHandlers) HandlePreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	contrast := r.FormValue("contrast")
+	if contrast != ContrastHigh && contrast != ContrastNormal {
+		http.Error(w, "invalid contrast", 400)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     contrastCookie,
+		Value:    contrast,
+		Path:     "/",
+		MaxAge:   int(preferenceMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	log.Printf("🎨 Contrast preference set to %s from %s", contrast, r.RemoteAddr)
+
+	http.Redirect(w, r, safeReturnPath(r.FormValue("return")), http.StatusSeeOther)
+}
+
+// safeReturnPath only allows redirects to paths on this server
+func safeReturnPath(path string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestStaticHandlers_HandlePreferences(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		form               url.Values
+		expectedStatusCode int
+		expectedCookie     string
+		expectedLocation   string
+	}{
+		{
+			name:               "enable high contrast",
+			method:             "POST",
+			form:               url.Values{"contrast": {"high"}, "return": {"/viewer?token=abc"}},
+			expectedStatusCode: 303,
+			expectedCookie:     "high",
+			expectedLocation:   "/viewer?token=abc",
+		},
+		{
+			name:               "external return path is ignored",
+			method:             "POST",
+			form:               url.Values{"contrast": {"normal"}, "return": {"//evil.example"}},
+			expectedStatusCode: 303,
+			expectedCookie:     "normal",
+			expectedLocation:   "/",
+		},
+		{
+			name:               "invalid contrast",
+			method:             "POST",
+			form:               url.Values{"contrast": {"neon"}},
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewStaticHandlers(nil, nil, nil, false)
+
+			req := httptest.NewRequest(tt.method, "/preferences", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			handlers.HandlePreferences(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedLocation != "" && w.Header().Get("Location") != tt.expectedLocation {
+				t.Errorf("Expected redirect to %q, got %q", tt.expectedLocation, w.Header().Get("Location"))
+			}
+			if tt.expectedCookie != "" {
+				cookies := w.Result().Cookies()
+				if len(cookies) != 1 || cookies[0].Name != contrastCookie || cookies[0].Value != tt.expectedCookie {
+					t.Errorf("Expected contrast cookie %q, got %+v", tt.expectedCookie, cookies)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPreferences(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Cookie", "contrast=high")
+	if prefs := readPreferences(req); prefs.Contrast != ContrastHigh {
+		t.Errorf("Expected high contrast, got %q", prefs.Contrast)
+	}
+
+	req = httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Cookie", "contrast=bogus")
+	if prefs := readPreferences(req); prefs.Contrast != "" {
+		t.Errorf("Expected unknown value to be ignored, got %q", prefs.Contrast)
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// PresetHandlers contains handlers for the quality presets
+type PresetHandlers struct {
+	presetUseCase interfaces.PresetUseCase
+}
+
+// NewPresetHandlers creates a new preset handlers instance
+func NewPresetHandlers(presetUseCase interfaces.PresetUseCase) *PresetHandlers {
+	return &PresetHandlers{
+		presetUseCase: presetUseCase,
+	}
+}
+
+// HandlePresets lists the quality presets a sender can start a session with
+func (h *PresetHandlers) HandlePresets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.presetUseCase.ListPresets()); err != nil {
+		log.Printf("Error encoding presets response: %v", err)
+	}
+}
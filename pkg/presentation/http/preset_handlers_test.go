@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestPresetHandlers_HandlePresets(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		expectedStatusCode int
+	}{
+		{
+			name:               "presets returned",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewPresetHandlers(mocks.NewMockPresetUseCase())
+
+			req := httptest.NewRequest(tt.method, "/api/v1/presets", nil)
+			w := httptest.NewRecorder()
+
+			handlers.HandlePresets(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+
+			var response dto.PresetsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Presets) != 1 || response.Default != "mock-preset" {
+				t.Errorf("Unexpected presets %+v", response)
+			}
+		})
+	}
+}
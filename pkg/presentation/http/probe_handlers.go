@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/netip"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/probe"
+	"share-screen/pkg/usecase/dto"
+)
+
+// ProbeHandlers contains handlers for the latency probe
+type ProbeHandlers struct {
+	schema       probe.Schema
+	statsUseCase interfaces.StatsUseCase
+}
+
+// NewProbeHandlers creates a new probe handlers instance
+func NewProbeHandlers(schema probe.Schema, statsUseCase interfaces.StatsUseCase) *ProbeHandlers {
+	return &ProbeHandlers{
+		schema:       schema,
+		statsUseCase: statsUseCase,
+	}
+}
+
+// HandleSchema describes the pings the sender and viewer pages echo over the
+// probe data channel and how often they send them
+func (h *ProbeHandlers) HandleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.schema); err != nil {
+		log.Printf("Error encoding probe schema: %v", err)
+	}
+}
+
+// HandlePing answers as quickly as it can, for pages to time a round trip to
+// the server, and tells whether the client reached it over the LAN
+func (h *ProbeHandlers) HandlePing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var response dto.PingResponse
+	if addr, err := netip.ParseAddr(clientIP(r)); err == nil {
+		response.Network = entities.NetworkOf(addr)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding ping: %v", err)
+	}
+}
+
+// HandleLatency stores the round-trip time a peer of the session measured
+// with the latency probe
+func (h *ProbeHandlers) HandleLatency(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.RecordLatencyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid latency payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	if err := h.statsUseCase.RecordLatency(&request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
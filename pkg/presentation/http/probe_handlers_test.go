@@ -0,0 +1,169 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/probe"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestProbeHandlers_HandleSchema(t *testing.T) {
+	handlers := NewProbeHandlers(probe.DefaultSchema(), mocks.NewMockStatsUseCase())
+
+	w := httptest.NewRecorder()
+	handlers.HandleSchema(w, httptest.NewRequest("GET", "/api/v1/probe/schema", nil))
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	var schema probe.Schema
+	if err := json.NewDecoder(w.Body).Decode(&schema); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if schema.Channel != probe.ChannelLabel || schema.IntervalMillis != probe.Interval.Milliseconds() {
+		t.Errorf("Expected the probe schema, got %+v", schema)
+	}
+
+	w = httptest.NewRecorder()
+	handlers.HandleSchema(w, httptest.NewRequest("POST", "/api/v1/probe/schema", nil))
+	if w.Code != 405 {
+		t.Errorf("Expected status code 405, got %d", w.Code)
+	}
+}
+
+func TestProbeHandlers_HandlePing(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		remoteAddr         string
+		expectedStatusCode int
+		expectedNetwork    string
+	}{
+		{
+			name:               "client on the LAN",
+			method:             "GET",
+			remoteAddr:         "192.168.1.20:51000",
+			expectedStatusCode: 200,
+			expectedNetwork:    "lan",
+		},
+		{
+			name:               "client on the internet",
+			method:             "GET",
+			remoteAddr:         "203.0.113.7:51000",
+			expectedStatusCode: 200,
+			expectedNetwork:    "internet",
+		},
+		{
+			name:               "unknown client address",
+			method:             "GET",
+			remoteAddr:         "@",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "method not allowed",
+			method:             "POST",
+			remoteAddr:         "192.168.1.20:51000",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := NewProbeHandlers(probe.DefaultSchema(), mocks.NewMockStatsUseCase())
+
+			req := httptest.NewRequest(tt.method, "/api/v1/ping", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+
+			handlers.HandlePing(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected the ping not to be cached, got %q", cache)
+			}
+			var response dto.PingResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Network != tt.expectedNetwork {
+				t.Errorf("Expected network %q, got %q", tt.expectedNetwork, response.Network)
+			}
+		})
+	}
+}
+
+func TestProbeHandlers_HandleLatency(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		recordError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "latency stored",
+			method:             "POST",
+			body:               `{"role":"viewer","viewerId":"viewer-1","path":"peer","rttMs":35,"network":"lan"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid JSON",
+			method:             "POST",
+			body:               `{"role":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid latency",
+			method:             "POST",
+			body:               `{"role":"viewer","path":"dns"}`,
+			recordError:        usecases.ErrInvalidStats,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "ended session",
+			method:             "POST",
+			body:               `{"role":"sender","path":"peer"}`,
+			recordError:        usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStatsUseCase := mocks.NewMockStatsUseCase()
+			mockStatsUseCase.RecordLatencyError = tt.recordError
+			handlers := NewProbeHandlers(probe.DefaultSchema(), mockStatsUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/latency", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleLatency(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 204 {
+				return
+			}
+			request := mockStatsUseCase.LastLatencyRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.Path != "peer" || request.RTTMillis != 35 || request.Network != "lan" {
+				t.Errorf("Expected the body and token to reach the use case, got %+v", request)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+)
+
+const (
+	// profileCookie holds the signed ID of a sender's profile
+	profileCookie = "profile"
+
+	// profileIDLength and profileMACLength are the lengths of a profile ID
+	// and its signature in hex characters
+	profileIDLength  = 32
+	profileMACLength = 32
+)
+
+// ProfileCookies hands out and checks the cookies naming sender profiles.
+// They are signed, so a browser cannot reach another sender's profile by
+// guessing or copying an ID from elsewhere, e.g. the unsigned sender cookie.
+type ProfileCookies struct {
+	key []byte
+}
+
+// NewProfileCookies creates profile cookies signed with secret
+func NewProfileCookies(secret string) *ProfileCookies {
+	return &ProfileCookies{key: []byte(secret)}
+}
+
+// read returns the profile ID from the request's profile cookie, or "" when
+// it has none or its signature does not match
+func (c *ProfileCookies) read(r *http.Request) string {
+	cookie, err := r.Cookie(profileCookie)
+	if err != nil {
+		return ""
+	}
+	id, mac, found := strings.Cut(cookie.Value, ".")
+	if !found || len(id) != profileIDLength || len(mac) != profileMACLength {
+		return ""
+	}
+	if !hmac.Equal([]byte(mac), []byte(c.sign(id))) {
+		return ""
+	}
+	return id
+}
+
+// ensure returns the request's profile ID, giving the browser a new profile
+// cookie when it has no valid one
+func (c *ProfileCookies) ensure(w http.ResponseWriter, r *http.Request) string {
+	if id := c.read(r); id != "" {
+		return id
+	}
+
+	b := make([]byte, profileIDLength/2)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("❌ Error generating profile ID: %v", err)
+		return ""
+	}
+	id := hex.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     profileCookie,
+		Value:    id + "." + c.sign(id),
+		Path:     "/",
+		MaxAge:   int(preferenceMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+// sign returns the signature of a profile ID in hex
+func (c *ProfileCookies) sign(id string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:profileMACLength]
+}
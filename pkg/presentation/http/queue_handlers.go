@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// QueueHandlers contains handlers for the viewer queue endpoints
+type QueueHandlers struct {
+	queueUseCase interfaces.ViewerQueueUseCase
+}
+
+// NewQueueHandlers creates a new queue handlers instance
+func NewQueueHandlers(queueUseCase interfaces.ViewerQueueUseCase) *QueueHandlers {
+	return &QueueHandlers{
+		queueUseCase: queueUseCase,
+	}
+}
+
+// HandleQueue handles queue operations (POST to join, GET to list)
+func (h *QueueHandlers) HandleQueue(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handleJoinQueue(w, r)
+	case http.MethodGet:
+		h.handleGetQueue(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *QueueHandlers) handleJoinQueue(w http.ResponseWriter, r *http.Request) {
+	// The body is optional and only carries the PIN of protected sessions
+	request := &dto.JoinQueueRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil && err != io.EOF {
+		log.Printf("❌ Invalid queue payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	response, err := h.queueUseCase.JoinQueue(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding queue response: %v", err)
+		http.Error(w, "internal server error", 500)
+	}
+}
+
+func (h *QueueHandlers) handleGetQueue(w http.ResponseWriter, r *http.Request) {
+	request := &dto.GetQueueRequest{Token: r.PathValue("token")}
+	response, err := h.queueUseCase.GetQueue(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding queue response: %v", err)
+		http.Error(w, "internal server error", 500)
+	}
+}
+
+// HandleLeaveQueue removes a viewer from the queue; queued viewers call it
+// via navigator.sendBeacon and the sender uses it to drop viewers
+func (h *QueueHandlers) HandleLeaveQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.QueueViewerRequest{
+		Token:    r.PathValue("token"),
+		ViewerID: r.PathValue("viewer"),
+	}
+	if err := h.queueUseCase.LeaveQueue(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandlePromoteViewer moves a viewer to the front of the queue
+func (h *QueueHandlers) HandlePromoteViewer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.QueueViewerRequest{
+		Token:    r.PathValue("token"),
+		ViewerID: r.PathValue("viewer"),
+	}
+	if err := h.queueUseCase.PromoteViewer(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// HandleReleaseViewer frees the viewer slot when the connected viewer leaves
+func (h *QueueHandlers) HandleReleaseViewer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.ReleaseViewerRequest{Token: r.PathValue("token")}
+	if err := h.queueUseCase.ReleaseViewer(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
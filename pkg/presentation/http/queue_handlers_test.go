@@ -0,0 +1,205 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestQueueHandlers_HandleQueue(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		joinError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "join queue",
+			method:             "POST",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "list queue",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "queue full",
+			method:             "POST",
+			joinError:          usecases.ErrQueueFull,
+			expectedStatusCode: 429,
+		},
+		{
+			name:               "session not found",
+			method:             "POST",
+			joinError:          usecases.ErrSessionNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQueueUseCase := mocks.NewMockViewerQueueUseCase()
+			mockQueueUseCase.JoinQueueError = tt.joinError
+			handlers := NewQueueHandlers(mockQueueUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/queue", nil)
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleQueue(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.method == "POST" && tt.expectedStatusCode == 200 {
+				var response dto.JoinQueueResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.ViewerID != "mock-viewer" || response.Position != 1 {
+					t.Errorf("Unexpected response: %+v", response)
+				}
+			}
+		})
+	}
+}
+
+func TestQueueHandlers_HandleLeaveQueue(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		leaveError         error
+		expectedStatusCode int
+	}{
+		{
+			name:               "viewer leaves",
+			method:             "POST",
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "viewer not queued",
+			method:             "POST",
+			leaveError:         usecases.ErrViewerNotQueued,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQueueUseCase := mocks.NewMockViewerQueueUseCase()
+			mockQueueUseCase.LeaveQueueError = tt.leaveError
+			handlers := NewQueueHandlers(mockQueueUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/queue/viewer-1/leave", nil)
+			req.SetPathValue("token", "test-token")
+			req.SetPathValue("viewer", "viewer-1")
+			w := httptest.NewRecorder()
+
+			handlers.HandleLeaveQueue(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.method == "POST" && mockQueueUseCase.LastViewerRequest.ViewerID != "viewer-1" {
+				t.Errorf("Expected viewer-1, got %+v", mockQueueUseCase.LastViewerRequest)
+			}
+		})
+	}
+}
+
+func TestQueueHandlers_HandleReleaseViewer(t *testing.T) {
+	mockQueueUseCase := mocks.NewMockViewerQueueUseCase()
+	handlers := NewQueueHandlers(mockQueueUseCase)
+
+	req := httptest.NewRequest("POST", "/api/sessions/test-token/leave", nil)
+	req.SetPathValue("token", "test-token")
+	w := httptest.NewRecorder()
+
+	handlers.HandleReleaseViewer(w, req)
+
+	if w.Code != 204 {
+		t.Errorf("Expected status code 204, got %d", w.Code)
+	}
+}
+
+func TestEventHandlers_HandleEvents(t *testing.T) {
+	broker := events.NewBroker(events.DefaultBufferSize, events.PolicyDropOldest).(*events.Broker)
+	mockQueueUseCase := mocks.NewMockViewerQueueUseCase()
+	mockQueueUseCase.GetQueueResponse = &dto.GetQueueResponse{
+		Viewers: []entities.QueuedViewer{{ID: "other"}, {ID: "viewer-1"}},
+	}
+	handlers := NewEventHandlers(broker, mockQueueUseCase)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/sessions/{token}/events", handlers.HandleEvents)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/sessions/test-token/events?viewer=viewer-1")
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %q", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	expectLine := func(want string) {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatalf("Stream closed before %q", want)
+				}
+				if strings.TrimSpace(line) == want {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("Timed out waiting for %q", want)
+			}
+		}
+	}
+
+	// Initial state tells the viewer where it stands
+	expectLine("event: position")
+	expectLine(`data: {"position":2}`)
+
+	broker.Publish(entities.ViewerTopic("test-token", "viewer-1"), entities.Event{Type: entities.EventViewerAdmitted})
+	expectLine("event: admitted")
+
+	broker.Close()
+	expectLine("event: server-shutdown")
+}
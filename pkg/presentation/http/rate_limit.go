@@ -0,0 +1,98 @@
+package http
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often buckets of clients that went quiet
+// are dropped
+const rateLimitSweepInterval = time.Minute
+
+// RateLimiter limits how many requests each client IP may make, with a
+// token bucket per IP: requests spend tokens, which refill at the rate up to
+// the burst, so polling pages keep working while a runaway script is
+// answered with 429
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*rateBucket
+	lastSweep time.Time
+}
+
+// rateBucket holds the tokens a client has left
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing perSecond requests per
+// client, with bursts of up to burst requests
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rate:    perSecond,
+		burst:   float64(max(burst, 1)),
+		now:     time.Now,
+		clients: make(map[string]*rateBucket),
+	}
+}
+
+// Allow spends a token of ip, reporting false with the time until the next
+// token when none is left
+func (l *RateLimiter) Allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.clients[ip]
+	if !ok {
+		bucket = &rateBucket{tokens: l.burst, updated: now}
+		l.clients[ip] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.rate)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return false, time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets that have refilled completely, since a new bucket
+// starts full anyway
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for ip, bucket := range l.clients {
+		if now.Sub(bucket.updated) >= full {
+			delete(l.clients, ip)
+		}
+	}
+}
+
+// Wrap is the middleware answering clients over the limit with 429
+func (l *RateLimiter) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r)
+		if ok, wait := l.Allow(ip); !ok {
+			log.Printf("🚦 Rate limit reached by %s on %s %s", ip, r.Method, r.URL.Path)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "too many requests", 429)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
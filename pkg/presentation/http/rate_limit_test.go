@@ -0,0 +1,68 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := limiter.Allow("192.168.1.20"); !ok {
+			t.Fatalf("Expected request %d of the burst to be allowed", i+1)
+		}
+	}
+	ok, wait := limiter.Allow("192.168.1.20")
+	if ok || wait != 500*time.Millisecond {
+		t.Errorf("Expected a refusal with a 500ms wait, got %v and %v", ok, wait)
+	}
+	if ok, _ := limiter.Allow("192.168.1.21"); !ok {
+		t.Error("Expected another client to have its own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := limiter.Allow("192.168.1.20"); !ok {
+		t.Error("Expected a token to have refilled")
+	}
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(1, 5)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("192.168.1.20")
+	now = now.Add(rateLimitSweepInterval)
+	limiter.Allow("192.168.1.21")
+
+	if _, ok := limiter.clients["192.168.1.20"]; ok {
+		t.Error("Expected the refilled bucket to be dropped")
+	}
+	if _, ok := limiter.clients["192.168.1.21"]; !ok {
+		t.Error("Expected the active bucket to be kept")
+	}
+}
+
+func TestRateLimiter_Wrap(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	handler := limiter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/info", nil))
+	if w.Code != 204 {
+		t.Fatalf("Expected the first request through, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/info", nil))
+	if w.Code != 429 || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Expected 429 with Retry-After: 1, got %d and %q", w.Code, w.Header().Get("Retry-After"))
+	}
+}
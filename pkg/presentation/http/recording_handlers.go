@@ -0,0 +1,76 @@
+package http
+
+import (
+	"log"
+	"mime"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// RecordingHandlers serves the recordings of SFU sessions to admins, who
+// authenticate as for the rest of the admin API
+type RecordingHandlers struct {
+	recordingUseCase interfaces.RecordingUseCase
+	admin            *AdminHandlers
+}
+
+// NewRecordingHandlers creates a new recording handlers instance; admin
+// decides who may use them
+func NewRecordingHandlers(recordingUseCase interfaces.RecordingUseCase, admin *AdminHandlers) *RecordingHandlers {
+	return &RecordingHandlers{
+		recordingUseCase: recordingUseCase,
+		admin:            admin,
+	}
+}
+
+// HandleRecordings lists the recordings, oldest first, with the space they
+// take up and the limits they are kept within
+func (h *RecordingHandlers) HandleRecordings(w http.ResponseWriter, r *http.Request) {
+	if !h.admin.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	response, err := h.recordingUseCase.ListRecordings()
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeAdminJSON(w, response)
+}
+
+// HandleRecording serves the recording named in the path: GET downloads it,
+// with range requests for seeking, and DELETE removes it
+func (h *RecordingHandlers) HandleRecording(w http.ResponseWriter, r *http.Request) {
+	// Each method is checked below
+	if !h.admin.allow(w, r, r.Method) {
+		return
+	}
+
+	name := r.PathValue("name")
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		download, err := h.recordingUseCase.GetRecording(&dto.GetRecordingRequest{Name: name})
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		defer download.Content.Close()
+
+		w.Header().Set("Content-Type", "video/x-matroska")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Recording.Name}))
+		w.Header().Set("Cache-Control", "no-store")
+		http.ServeContent(w, r, download.Recording.Name, download.Recording.EndedAt, download.Content)
+	case http.MethodDelete:
+		if err := h.recordingUseCase.DeleteRecording(&dto.DeleteRecordingRequest{Name: name}); err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		log.Printf("🔐 Recording %s deleted by an admin from %s", name, r.RemoteAddr)
+		w.WriteHeader(204)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// newRecordingHandlers creates recording handlers for admins with token
+func newRecordingHandlers(recordingUseCase *mocks.MockRecordingUseCase, token string) *RecordingHandlers {
+	admin := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, token, nil)
+	return NewRecordingHandlers(recordingUseCase, admin)
+}
+
+func TestRecordingHandlers_HandleRecordings(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		method             string
+		authorization      string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "recordings listed", token: "admin-token", method: "GET", authorization: "Bearer admin-token", expectedStatusCode: 200},
+		{name: "admin API disabled", method: "GET", authorization: "Bearer admin-token", expectedStatusCode: 404},
+		{name: "wrong token", token: "admin-token", method: "GET", authorization: "Bearer other", expectedStatusCode: 401},
+		{name: "method not allowed", token: "admin-token", method: "POST", authorization: "Bearer admin-token", expectedStatusCode: 405},
+		{name: "recordings disabled", token: "admin-token", method: "GET", authorization: "Bearer admin-token", err: usecases.ErrRecordingsDisabled, expectedStatusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockRecordingUseCase()
+			mockUseCase.ListRecordingsError = tt.err
+			handlers := newRecordingHandlers(mockUseCase, tt.token)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/recordings", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handlers.HandleRecordings(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			var response dto.RecordingsResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Recordings) != 1 || response.Recordings[0].Token != "test-token" {
+				t.Errorf("Unexpected recordings: %+v", response.Recordings)
+			}
+		})
+	}
+}
+
+func TestRecordingHandlers_HandleRecording(t *testing.T) {
+	const name = "test-token-20250115T100000Z.mkv"
+	tests := []struct {
+		name               string
+		method             string
+		authorization      string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "download", method: "GET", authorization: "Bearer admin-token", expectedStatusCode: 200},
+		{name: "delete", method: "DELETE", authorization: "Bearer admin-token", expectedStatusCode: 204},
+		{name: "unauthorized delete", method: "DELETE", authorization: "Bearer other", expectedStatusCode: 401},
+		{name: "method not allowed", method: "PUT", authorization: "Bearer admin-token", expectedStatusCode: 405},
+		{name: "unknown recording", method: "GET", authorization: "Bearer admin-token", err: usecases.ErrRecordingNotFound, expectedStatusCode: 404},
+		{name: "unknown recording deleted", method: "DELETE", authorization: "Bearer admin-token", err: usecases.ErrRecordingNotFound, expectedStatusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockRecordingUseCase()
+			mockUseCase.GetRecordingError = tt.err
+			mockUseCase.DeleteRecordingError = tt.err
+			handlers := newRecordingHandlers(mockUseCase, "admin-token")
+
+			req := httptest.NewRequest(tt.method, "/api/v1/recordings/"+name, nil)
+			req.SetPathValue("name", name)
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handlers.HandleRecording(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			switch w.Code {
+			case 200:
+				if mockUseCase.LastGetRequest.Name != name || w.Body.String() != "recorded video" {
+					t.Errorf("Expected the recording downloaded, got %q", w.Body.String())
+				}
+				if w.Header().Get("Content-Type") != "video/x-matroska" || w.Header().Get("Content-Disposition") == "" {
+					t.Errorf("Expected a Matroska attachment, got %v", w.Header())
+				}
+			case 204:
+				if mockUseCase.LastDeleteRequest.Name != name {
+					t.Errorf("Expected %s deleted, got %+v", name, mockUseCase.LastDeleteRequest)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in both directions: a proxy may
+// set it on the request and every response returns it
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the IDs accepted from clients and proxies
+const maxRequestIDLength = 128
+
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// RequestLogger wraps the application handler, gives every request an ID
+// returned in the X-Request-ID header and logs one structured line per
+// request, so an error seen by a client can be found in the server log
+type RequestLogger struct {
+	next   http.Handler
+	logger *slog.Logger
+}
+
+// NewRequestLogger creates a request logger around next; a nil logger still
+// assigns request IDs but logs nothing
+func NewRequestLogger(logger *slog.Logger, next http.Handler) *RequestLogger {
+	return &RequestLogger{next: next, logger: logger}
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "" outside the
+// request logger
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ServeHTTP implements http.Handler
+func (l *RequestLogger) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(RequestIDHeader)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	w.Header().Set(RequestIDHeader, id)
+	ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	start := time.Now()
+	l.next.ServeHTTP(recorder, r.WithContext(ctx))
+	if l.logger == nil {
+		return
+	}
+
+	level := slog.LevelInfo
+	switch {
+	case recorder.status >= 500:
+		level = slog.LevelError
+	case recorder.status >= 400:
+		level = slog.LevelWarn
+	}
+	l.logger.LogAttrs(ctx, level, "request",
+		slog.String("id", id),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", recorder.status),
+		slog.Duration("duration", time.Since(start)),
+		slog.String("remote_ip", clientIP(r)),
+		slog.Int64("bytes", recorder.bytes),
+	)
+}
+
+// validRequestID accepts IDs made of printable ASCII without spaces, so a
+// forwarded ID cannot break the log line
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random hex characters
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder remembers the status and size of a response; it keeps
+// streaming and WebSocket upgrades working through Flush, Hijack and Unwrap
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+// WriteHeader implements http.ResponseWriter
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher
+func (r *statusRecorder) Flush() {
+	r.wroteHeader = true
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker; the connection is switching protocols
+// from here on, which is what the log shows
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(r.ResponseWriter).Hijack()
+	if err == nil && !r.wroteHeader {
+		r.status = http.StatusSwitchingProtocols
+		r.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
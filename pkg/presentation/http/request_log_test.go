@@ -0,0 +1,94 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logged, nil))
+
+	var seenID string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = RequestID(r.Context())
+		http.Error(w, "session not found", 404)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/sessions/abc", nil)
+	r.RemoteAddr = "192.168.1.20:51234"
+	NewRequestLogger(logger, next).ServeHTTP(w, r)
+
+	id := w.Header().Get(RequestIDHeader)
+	if len(id) != 16 || id != seenID {
+		t.Fatalf("Expected a 16 character ID passed to the handler, got %q and %q", id, seenID)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(logged.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", logged.String(), err)
+	}
+	expected := map[string]any{
+		"level":     "WARN",
+		"msg":       "request",
+		"id":        id,
+		"method":    "GET",
+		"path":      "/api/v1/sessions/abc",
+		"status":    float64(404),
+		"remote_ip": "192.168.1.20",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("Expected the duration to be logged")
+	}
+}
+
+func TestRequestLogger_ForwardedID(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	logger := NewRequestLogger(nil, next)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(RequestIDHeader, "proxy-7f3a")
+	logger.ServeHTTP(w, r)
+	if got := w.Header().Get(RequestIDHeader); got != "proxy-7f3a" {
+		t.Errorf("Expected the forwarded ID to be kept, got %q", got)
+	}
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(RequestIDHeader, "bad id\n")
+	logger.ServeHTTP(w, r)
+	if got := w.Header().Get(RequestIDHeader); strings.Contains(got, " ") || len(got) != 16 {
+		t.Errorf("Expected an invalid ID to be replaced, got %q", got)
+	}
+}
+
+func TestRequestLogger_Streaming(t *testing.T) {
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logged, nil))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("Expected the writer to support flushing")
+		}
+		w.Write([]byte("data: hello\n\n"))
+		w.WriteHeader(500) // ignored once the body started
+	})
+
+	w := httptest.NewRecorder()
+	NewRequestLogger(logger, next).ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/sessions/abc/events", nil))
+	if !strings.Contains(logged.String(), "status=200") || !strings.Contains(logged.String(), "bytes=13") {
+		t.Errorf("Expected status 200 and 13 bytes to be logged, got %q", logged.String())
+	}
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// RoomHandlers contains handlers for named rooms
+type RoomHandlers struct {
+	roomUseCase interfaces.RoomUseCase
+}
+
+// NewRoomHandlers creates a new room handlers instance
+func NewRoomHandlers(roomUseCase interfaces.RoomUseCase) *RoomHandlers {
+	return &RoomHandlers{
+		roomUseCase: roomUseCase,
+	}
+}
+
+// HandleRoom handles room operations (GET to look up the current session,
+// PUT to point the room at a session)
+func (h *RoomHandlers) HandleRoom(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetRoom(w, r)
+	case http.MethodPut:
+		h.handleClaimRoom(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *RoomHandlers) handleGetRoom(w http.ResponseWriter, r *http.Request) {
+	response, err := h.roomUseCase.GetRoom(&dto.GetRoomRequest{Name: r.PathValue("name")})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	// Viewers poll this until the sender starts, so it must never be cached
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding room response: %v", err)
+	}
+}
+
+func (h *RoomHandlers) handleClaimRoom(w http.ResponseWriter, r *http.Request) {
+	request := &dto.ClaimRoomRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid room payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Name = r.PathValue("name")
+
+	response, err := h.roomUseCase.ClaimRoom(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding room response: %v", err)
+	}
+}
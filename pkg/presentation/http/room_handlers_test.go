@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestRoomHandlers_HandleRoom(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		claimError         error
+		getError           error
+		expectedStatusCode int
+	}{
+		{
+			name:               "look up room",
+			method:             "GET",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "unknown room",
+			method:             "GET",
+			getError:           usecases.ErrRoomNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "claim room",
+			method:             "PUT",
+			body:               `{"token":"test-token"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "room taken",
+			method:             "PUT",
+			body:               `{"token":"test-token","key":"wrong"}`,
+			claimError:         usecases.ErrRoomTaken,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "invalid room name",
+			method:             "PUT",
+			body:               `{"token":"test-token"}`,
+			claimError:         usecases.ErrInvalidRoomName,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid payload",
+			method:             "PUT",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoomUseCase := mocks.NewMockRoomUseCase()
+			mockRoomUseCase.ClaimRoomError = tt.claimError
+			mockRoomUseCase.GetRoomError = tt.getError
+			handlers := NewRoomHandlers(mockRoomUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/rooms/design-review", strings.NewReader(tt.body))
+			req.SetPathValue("name", "design-review")
+			w := httptest.NewRecorder()
+
+			handlers.HandleRoom(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected Cache-Control no-store, got %q", cache)
+			}
+
+			if tt.method == "PUT" {
+				var response dto.ClaimRoomResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if mockRoomUseCase.LastClaimRequest.Name != "design-review" || mockRoomUseCase.LastClaimRequest.Token != "test-token" {
+					t.Errorf("Unexpected claim request: %+v", mockRoomUseCase.LastClaimRequest)
+				}
+				if response.Key == "" || response.Path != "/r/design-review" {
+					t.Errorf("Unexpected response: %+v", response)
+				}
+				return
+			}
+
+			var response dto.RoomResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !response.Live || response.Token != "mock-token" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
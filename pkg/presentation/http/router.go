@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+)
+
+const (
+	// APIVersion is the current version of the HTTP API
+	APIVersion = "v1"
+
+	// apiPrefix is where the unversioned, legacy API paths live
+	apiPrefix = "/api"
+
+	// versionedAPIPrefix is where the current API version is served
+	versionedAPIPrefix = apiPrefix + "/" + APIVersion
+)
+
+// Router registers pages and API endpoints on a ServeMux. API endpoints are
+// served under /api/v1 and, for clients written before versioning, under
+// their original /api paths; a later version can replace the legacy alias
+// of an endpoint without touching the v1 route.
+type Router struct {
+	mux *http.ServeMux
+}
+
+// NewRouter creates a router that registers routes on mux
+func NewRouter(mux *http.ServeMux) *Router {
+	return &Router{mux: mux}
+}
+
+// Page registers a handler for a non-API path such as a page or asset
+func (r *Router) Page(pattern string, handler http.HandlerFunc) {
+	r.mux.HandleFunc(pattern, handler)
+}
+
+// Handle registers an http.Handler for a non-API path
+func (r *Router) Handle(pattern string, handler http.Handler) {
+	r.mux.Handle(pattern, handler)
+}
+
+// API registers an endpoint at /api/v1 plus path, and at the legacy /api
+// plus path; path must start with a slash, e.g. "/sessions/{token}/end"
+func (r *Router) API(path string, handler http.HandlerFunc) {
+	r.mux.HandleFunc(versionedAPIPrefix+path, handler)
+	r.mux.HandleFunc(apiPrefix+path, handler)
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_API(t *testing.T) {
+	mux := http.NewServeMux()
+	router := NewRouter(mux)
+	router.API("/sessions/{token}/end", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("token")))
+	})
+	router.Page("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	})
+
+	tests := []struct {
+		name               string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "versioned path",
+			path:               "/api/v1/sessions/abc/end",
+			expectedStatusCode: 200,
+			expectedBody:       "abc",
+		},
+		{
+			name:               "legacy alias",
+			path:               "/api/sessions/abc/end",
+			expectedStatusCode: 200,
+			expectedBody:       "abc",
+		},
+		{
+			name:               "unknown version",
+			path:               "/api/v2/sessions/abc/end",
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedBody != "" && w.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
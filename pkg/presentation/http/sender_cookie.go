@@ -0,0 +1,61 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// senderCookie holds the random ID that ties a browser to the sessions
+	// it creates, so its landing page can list them
+	senderCookie = "sender"
+
+	// senderIDLength is the length of a sender ID in hex characters
+	senderIDLength = 32
+
+	// senderCookieMaxAge keeps the sender ID for a year, like display preferences
+	senderCookieMaxAge = 365 * 24 * time.Hour
+)
+
+// readSenderID returns the sender ID from the request's sender cookie, or ""
+// when it has none or it was not made by this server
+func readSenderID(r *http.Request) string {
+	cookie, err := r.Cookie(senderCookie)
+	if err != nil || len(cookie.Value) != senderIDLength {
+		return ""
+	}
+	if _, err := hex.DecodeString(cookie.Value); err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// ensureSenderID returns the request's sender ID, giving the browser a new
+// sender cookie when it has none; a browser that cannot get one still shares,
+// only without the listing
+func ensureSenderID(w http.ResponseWriter, r *http.Request) string {
+	if id := readSenderID(r); id != "" {
+		return id
+	}
+
+	b := make([]byte, senderIDLength/2)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("❌ Error generating sender ID: %v", err)
+		return ""
+	}
+	id := hex.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     senderCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(senderCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
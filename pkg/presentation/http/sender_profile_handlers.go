@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// SenderProfileHandlers contains handlers for the defaults senders keep on
+// the server
+type SenderProfileHandlers struct {
+	profileUseCase interfaces.SenderProfileUseCase
+	cookies        *ProfileCookies
+}
+
+// NewSenderProfileHandlers creates a new sender profile handlers instance;
+// cookies is nil when profiles are disabled
+func NewSenderProfileHandlers(profileUseCase interfaces.SenderProfileUseCase, cookies *ProfileCookies) *SenderProfileHandlers {
+	return &SenderProfileHandlers{
+		profileUseCase: profileUseCase,
+		cookies:        cookies,
+	}
+}
+
+// HandleProfile returns (GET), replaces (PUT) or forgets (DELETE) the
+// defaults of the sender named by the signed profile cookie, which the
+// browser is given on its first request
+func (h *SenderProfileHandlers) HandleProfile(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodGet:
+		response, err := h.profileUseCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: h.profileID(w, r)})
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		writeProfile(w, response)
+	case http.MethodPut:
+		var request dto.UpdateSenderProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			log.Printf("❌ Invalid profile payload: %v", err)
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		request.ProfileID = h.profileID(w, r)
+
+		response, err := h.profileUseCase.UpdateProfile(&request)
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		writeProfile(w, response)
+	case http.MethodDelete:
+		if err := h.profileUseCase.DeleteProfile(&dto.DeleteSenderProfileRequest{ProfileID: h.profileID(w, r)}); err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(204)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+// profileID returns the request's profile ID, issuing a profile cookie when
+// the browser has none yet; without profiles there is none
+func (h *SenderProfileHandlers) profileID(w http.ResponseWriter, r *http.Request) string {
+	if h.cookies == nil {
+		return ""
+	}
+	return h.cookies.ensure(w, r)
+}
+
+// writeProfile encodes a sender profile as the uncached JSON response
+func writeProfile(w http.ResponseWriter, response *dto.SenderProfileResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding profile response: %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestSenderProfileHandlers_HandleProfile(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "get", method: "GET", expectedStatusCode: 200},
+		{name: "update", method: "PUT", body: `{"preset":"text","audio":true,"theme":"high"}`, expectedStatusCode: 200},
+		{name: "delete", method: "DELETE", expectedStatusCode: 204},
+		{name: "bad body", method: "PUT", body: `{`, expectedStatusCode: 400},
+		{name: "invalid profile", method: "PUT", body: `{"theme":"dark"}`, err: usecases.ErrInvalidSenderProfile, expectedStatusCode: 400},
+		{name: "profiles disabled", method: "GET", err: usecases.ErrProfilesDisabled, expectedStatusCode: 404},
+		{name: "method not allowed", method: "POST", expectedStatusCode: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockSenderProfileUseCase()
+			mockUseCase.GetProfileError = tt.err
+			mockUseCase.UpdateProfileError = tt.err
+			handlers := NewSenderProfileHandlers(mockUseCase, NewProfileCookies("profile-secret-for-tests"))
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sender/profile", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			handlers.HandleProfile(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			switch w.Code {
+			case 200:
+				var response dto.SenderProfileResponse
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Preset != "text" {
+					t.Errorf("Expected the text preset, got %+v", response)
+				}
+				if tt.method == "PUT" && (!mockUseCase.LastUpdateRequest.Audio || mockUseCase.LastUpdateRequest.ProfileID == "") {
+					t.Errorf("Expected the update for the browser's profile, got %+v", mockUseCase.LastUpdateRequest)
+				}
+			case 204:
+				if mockUseCase.LastDeleteRequest.ProfileID == "" {
+					t.Errorf("Expected the browser's profile deleted, got %+v", mockUseCase.LastDeleteRequest)
+				}
+			}
+		})
+	}
+}
+
+func TestSenderProfileHandlers_Disabled(t *testing.T) {
+	// Without profiles there is no cookie to hand out
+	mockUseCase := mocks.NewMockSenderProfileUseCase()
+	mockUseCase.GetProfileError = usecases.ErrProfilesDisabled
+	handlers := NewSenderProfileHandlers(mockUseCase, nil)
+
+	w := httptest.NewRecorder()
+	handlers.HandleProfile(w, httptest.NewRequest("GET", "/api/v1/sender/profile", nil))
+
+	if w.Code != 404 {
+		t.Errorf("Expected status code 404, got %d", w.Code)
+	}
+	if len(w.Result().Cookies()) != 0 || mockUseCase.LastGetRequest.ProfileID != "" {
+		t.Errorf("Expected no profile cookie, got %v", w.Result().Cookies())
+	}
+}
+
+func TestProfileCookies(t *testing.T) {
+	cookies := NewProfileCookies("profile-secret-for-tests")
+
+	// The first request gets a signed cookie, which names the same profile
+	// on the next
+	w := httptest.NewRecorder()
+	id := cookies.ensure(w, httptest.NewRequest("GET", "/", nil))
+	issued := w.Result().Cookies()
+	if id == "" || len(issued) != 1 || issued[0].Name != profileCookie || !issued[0].HttpOnly {
+		t.Fatalf("Expected a profile cookie, got %q and %v", id, issued)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(issued[0])
+	if got := cookies.read(req); got != id {
+		t.Errorf("Expected profile %q from the cookie, got %q", id, got)
+	}
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "unsigned", value: id},
+		{name: "forged signature", value: id + "." + strings.Repeat("0", profileMACLength)},
+		{name: "another ID with the signature", value: strings.Repeat("a", profileIDLength) + "." + cookies.sign(id)},
+		{name: "signed with another secret", value: id + "." + NewProfileCookies("another-profile-secret").sign(id)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.AddCookie(&http.Cookie{Name: profileCookie, Value: tt.value})
+			if got := cookies.read(req); got != "" {
+				t.Errorf("Expected the cookie to be refused, got profile %q", got)
+			}
+			// A refused cookie is replaced with a new profile
+			w := httptest.NewRecorder()
+			if got := cookies.ensure(w, req); got == "" || got == id {
+				t.Errorf("Expected a new profile, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// SessionTemplateHandlers lists the session templates to senders and lets
+// admins, who authenticate as for the rest of the admin API, define them
+type SessionTemplateHandlers struct {
+	templateUseCase interfaces.SessionTemplateUseCase
+	admin           *AdminHandlers
+}
+
+// NewSessionTemplateHandlers creates a new session template handlers
+// instance; admin decides who may change the templates
+func NewSessionTemplateHandlers(templateUseCase interfaces.SessionTemplateUseCase, admin *AdminHandlers) *SessionTemplateHandlers {
+	return &SessionTemplateHandlers{
+		templateUseCase: templateUseCase,
+		admin:           admin,
+	}
+}
+
+// HandleTemplates lists the session templates a sender can start a session
+// from
+func (h *SessionTemplateHandlers) HandleTemplates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	response, err := h.templateUseCase.ListTemplates()
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding templates response: %v", err)
+	}
+}
+
+// HandleTemplate manages the template named in the path: PUT defines it from
+// the options in the body, replacing any template of that name, and DELETE
+// removes it
+func (h *SessionTemplateHandlers) HandleTemplate(w http.ResponseWriter, r *http.Request) {
+	// Each method is checked below
+	if !h.admin.allow(w, r, r.Method) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var request dto.SaveSessionTemplateRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		request.Name = r.PathValue("name")
+		template, err := h.templateUseCase.SaveTemplate(&request)
+		if err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		log.Printf("🔐 Session template %s saved by an admin from %s", template.Name, r.RemoteAddr)
+		writeAdminJSON(w, template)
+	case http.MethodDelete:
+		request := &dto.DeleteSessionTemplateRequest{Name: r.PathValue("name")}
+		if err := h.templateUseCase.DeleteTemplate(request); err != nil {
+			writeUseCaseError(w, err)
+			return
+		}
+		w.WriteHeader(204)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
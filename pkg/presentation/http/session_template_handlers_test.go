@@ -0,0 +1,98 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// newSessionTemplateHandlers creates session template handlers for admins
+// with token
+func newSessionTemplateHandlers(templateUseCase *mocks.MockSessionTemplateUseCase, token string) *SessionTemplateHandlers {
+	admin := NewAdminHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockStatsUseCase(), mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, token, nil)
+	return NewSessionTemplateHandlers(templateUseCase, admin)
+}
+
+func TestSessionTemplateHandlers_HandleTemplates(t *testing.T) {
+	// Senders list the templates without admin credentials
+	handlers := newSessionTemplateHandlers(mocks.NewMockSessionTemplateUseCase(), "")
+
+	w := httptest.NewRecorder()
+	handlers.HandleTemplates(w, httptest.NewRequest("GET", "/api/v1/templates", nil))
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	var response dto.SessionTemplatesResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Templates) != 1 || response.Templates[0].Name != "meeting" {
+		t.Errorf("Unexpected templates: %+v", response.Templates)
+	}
+
+	w = httptest.NewRecorder()
+	handlers.HandleTemplates(w, httptest.NewRequest("POST", "/api/v1/templates", nil))
+	if w.Code != 405 {
+		t.Errorf("Expected status code 405, got %d", w.Code)
+	}
+}
+
+func TestSessionTemplateHandlers_HandleTemplate(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		authorization      string
+		body               string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "saved", method: "PUT", authorization: "Bearer admin-token", body: `{"preset":"text","requirePin":true,"maxViewers":20}`, expectedStatusCode: 200},
+		{name: "deleted", method: "DELETE", authorization: "Bearer admin-token", expectedStatusCode: 204},
+		{name: "unauthorized", method: "PUT", authorization: "Bearer other", body: `{}`, expectedStatusCode: 401},
+		{name: "method not allowed", method: "POST", authorization: "Bearer admin-token", expectedStatusCode: 405},
+		{name: "bad body", method: "PUT", authorization: "Bearer admin-token", body: `{`, expectedStatusCode: 400},
+		{name: "invalid template", method: "PUT", authorization: "Bearer admin-token", body: `{"maxViewers":-1}`, err: usecases.ErrInvalidSessionTemplate, expectedStatusCode: 400},
+		{name: "unknown template", method: "DELETE", authorization: "Bearer admin-token", err: usecases.ErrSessionTemplateNotFound, expectedStatusCode: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockSessionTemplateUseCase()
+			mockUseCase.SaveTemplateError = tt.err
+			mockUseCase.DeleteTemplateError = tt.err
+			handlers := newSessionTemplateHandlers(mockUseCase, "admin-token")
+
+			req := httptest.NewRequest(tt.method, "/api/v1/admin/templates/meeting", strings.NewReader(tt.body))
+			req.SetPathValue("name", "meeting")
+			req.Header.Set("Authorization", tt.authorization)
+			w := httptest.NewRecorder()
+
+			handlers.HandleTemplate(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			switch w.Code {
+			case 200:
+				var template entities.SessionTemplate
+				if err := json.NewDecoder(w.Body).Decode(&template); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if template.Name != "meeting" || template.Preset != "text" || !template.RequirePIN || template.MaxViewers != 20 {
+					t.Errorf("Expected the template from the path and body, got %+v", template)
+				}
+			case 204:
+				if mockUseCase.LastDeleteRequest.Name != "meeting" {
+					t.Errorf("Expected meeting deleted, got %+v", mockUseCase.LastDeleteRequest)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// deviceCookie identifies a viewer device so its settings survive reloads
+const deviceCookie = "device"
+
+// SettingsHandlers contains handlers for viewer settings and quality requests
+type SettingsHandlers struct {
+	settingsUseCase interfaces.ViewerSettingsUseCase
+}
+
+// NewSettingsHandlers creates a new settings handlers instance
+func NewSettingsHandlers(settingsUseCase interfaces.ViewerSettingsUseCase) *SettingsHandlers {
+	return &SettingsHandlers{
+		settingsUseCase: settingsUseCase,
+	}
+}
+
+// HandleViewerSettings returns (GET) or replaces (PUT) the settings of the requesting device
+func (h *SettingsHandlers) HandleViewerSettings(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodGet:
+		h.handleGetSettings(w, r)
+	case http.MethodPut, http.MethodPost:
+		h.handleUpdateSettings(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *SettingsHandlers) handleGetSettings(w http.ResponseWriter, r *http.Request) {
+	request := &dto.GetViewerSettingsRequest{DeviceID: ensureDeviceID(w, r)}
+	response, err := h.settingsUseCase.GetSettings(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeSettings(w, response)
+}
+
+func (h *SettingsHandlers) handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var request dto.UpdateViewerSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid settings payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.DeviceID = ensureDeviceID(w, r)
+
+	response, err := h.settingsUseCase.UpdateSettings(&request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeSettings(w, response)
+}
+
+// HandleQualityRequest forwards a viewer's stream request to the sender of the session in the path
+func (h *SettingsHandlers) HandleQualityRequest(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.QualityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid quality payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+
+	if err := h.settingsUseCase.RequestQuality(&request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(204)
+}
+
+// writeSettings encodes viewer settings as the JSON response
+func writeSettings(w http.ResponseWriter, response *dto.ViewerSettingsResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding settings response: %v", err)
+		http.Error(w, "internal server error", 500)
+	}
+}
+
+// ensureDeviceID returns the device identifier from the request cookie,
+// issuing a new one when the browser has none yet
+func ensureDeviceID(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(deviceCookie); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Printf("❌ Error generating device ID: %v", err)
+		return ""
+	}
+	id := hex.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     deviceCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(preferenceMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestSettingsHandlers_HandleViewerSettings(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		cookie             string
+		expectedStatusCode int
+		expectNewDevice    bool
+	}{
+		{
+			name:               "first visit issues device cookie",
+			method:             "GET",
+			expectedStatusCode: 200,
+			expectNewDevice:    true,
+		},
+		{
+			name:               "known device keeps its cookie",
+			method:             "GET",
+			cookie:             "device-1",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "settings updated",
+			method:             "PUT",
+			body:               `{"lowPower":true}`,
+			cookie:             "device-1",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid json",
+			method:             "PUT",
+			body:               `{`,
+			cookie:             "device-1",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSettingsUseCase := mocks.NewMockViewerSettingsUseCase()
+			handlers := NewSettingsHandlers(mockSettingsUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/viewer/settings", bytes.NewBufferString(tt.body))
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: deviceCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+
+			handlers.HandleViewerSettings(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			issued := len(w.Result().Cookies()) > 0
+			if issued != tt.expectNewDevice {
+				t.Errorf("Expected device cookie issued %v, got %v", tt.expectNewDevice, issued)
+			}
+
+			if tt.method == "PUT" && w.Code == 200 {
+				request := mockSettingsUseCase.LastSettingsRequest
+				if request.DeviceID != tt.cookie || !request.LowPower {
+					t.Errorf("Unexpected settings request: %+v", request)
+				}
+			}
+		})
+	}
+}
+
+func TestSettingsHandlers_HandleQualityRequest(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		qualityError       error
+		expectedStatusCode int
+	}{
+		{
+			name:               "request forwarded",
+			method:             "POST",
+			body:               `{"maxFrameRate":15}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "frame rate out of range",
+			method:             "POST",
+			body:               `{"maxFrameRate":240}`,
+			qualityError:       usecases.ErrInvalidQuality,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "session ended",
+			method:             "POST",
+			body:               `{"maxFrameRate":15}`,
+			qualityError:       usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "invalid json",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSettingsUseCase := mocks.NewMockViewerSettingsUseCase()
+			mockSettingsUseCase.RequestQualityError = tt.qualityError
+			handlers := NewSettingsHandlers(mockSettingsUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/sessions/test-token/quality", bytes.NewBufferString(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleQualityRequest(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode == 204 && mockSettingsUseCase.LastQualityRequest.Token != "test-token" {
+				t.Errorf("Expected token from path, got %+v", mockSettingsUseCase.LastQualityRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// ShutdownNotifier wraps the application handler and, once draining starts,
+// answers new requests with 503 so polling clients learn the server is going
+// away instead of seeing their handshake die mid-flight
+type ShutdownNotifier struct {
+	next     http.Handler
+	draining atomic.Bool
+}
+
+// NewShutdownNotifier creates a new shutdown notifier around next
+func NewShutdownNotifier(next http.Handler) *ShutdownNotifier {
+	return &ShutdownNotifier{next: next}
+}
+
+// Drain starts rejecting new requests
+func (n *ShutdownNotifier) Drain() {
+	n.draining.Store(true)
+}
+
+// IsDraining reports whether shutdown has started
+func (n *ShutdownNotifier) IsDraining() bool {
+	return n.draining.Load()
+}
+
+// ServeHTTP implements http.Handler
+func (n *ShutdownNotifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if n.draining.Load() {
+		w.Header().Set("Connection", "close")
+		w.Header().Set("Retry-After", "5")
+		w.Header().Set("X-Server-Shutdown", "true")
+		http.Error(w, "server shutting down", 503)
+		return
+	}
+
+	n.next.ServeHTTP(w, r)
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShutdownNotifier(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	notifier := NewShutdownNotifier(next)
+
+	w := httptest.NewRecorder()
+	notifier.ServeHTTP(w, httptest.NewRequest("GET", "/api/offer", nil))
+	if w.Code != 204 {
+		t.Errorf("Expected status code 204 before draining but got %d", w.Code)
+	}
+
+	notifier.Drain()
+	if !notifier.IsDraining() {
+		t.Error("Expected notifier to be draining")
+	}
+
+	w = httptest.NewRecorder()
+	notifier.ServeHTTP(w, httptest.NewRequest("GET", "/api/offer", nil))
+	if w.Code != 503 {
+		t.Errorf("Expected status code 503 while draining but got %d", w.Code)
+	}
+	if w.Header().Get("X-Server-Shutdown") != "true" {
+		t.Error("Expected shutdown header while draining")
+	}
+}
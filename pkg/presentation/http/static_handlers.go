@@ -1,29 +1,38 @@
 package http
 
 import (
+	"bytes"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
 
+	"share-screen/pkg/domain/interfaces"
 	"share-screen/pkg/infrastructure/template"
+	"share-screen/pkg/usecase/dto"
 )
 
 // StaticHandlers contains handlers for static content
 type StaticHandlers struct {
 	templateService *template.TemplateService
+	sessionUseCase  interfaces.SessionUseCase
+	settingsUseCase interfaces.ViewerSettingsUseCase
+	linkPreview     bool
 }
 
 // NewStaticHandlers creates a new static handlers instance
-func NewStaticHandlers(templateService *template.TemplateService) *StaticHandlers {
+func NewStaticHandlers(templateService *template.TemplateService, sessionUseCase interfaces.SessionUseCase, settingsUseCase interfaces.ViewerSettingsUseCase, linkPreview bool) *StaticHandlers {
 	return &StaticHandlers{
 		templateService: templateService,
+		sessionUseCase:  sessionUseCase,
+		settingsUseCase: settingsUseCase,
+		linkPreview:     linkPreview,
 	}
 }
 
 // ServeIndex serves the main landing page
 func (h *StaticHandlers) ServeIndex(w http.ResponseWriter, r *http.Request) {
-	data := template.PageData{
-		Title: "Mac → iPhone Screen Share",
-	}
+	data := pageData(r, "Mac → iPhone Screen Share", "/static/js/ui.js", "/static/js/index.js")
 
 	if err := h.templateService.RenderPage(w, "index.html", data); err != nil {
 		log.Printf("Error rendering index template: %v", err)
@@ -33,10 +42,7 @@ func (h *StaticHandlers) ServeIndex(w http.ResponseWriter, r *http.Request) {
 
 // ServeSender serves the sender (Mac) page
 func (h *StaticHandlers) ServeSender(w http.ResponseWriter, r *http.Request) {
-	data := template.PageData{
-		Title:   "Sender",
-		Scripts: []string{"/static/js/sender.js"},
-	}
+	data := pageData(r, "Sender", "/static/js/ui.js", "/static/js/sender.js")
 
 	if err := h.templateService.RenderPage(w, "sender.html", data); err != nil {
 		log.Printf("Error rendering sender template: %v", err)
@@ -46,10 +52,21 @@ func (h *StaticHandlers) ServeSender(w http.ResponseWriter, r *http.Request) {
 
 // ServeViewer serves the viewer (iPhone) page
 func (h *StaticHandlers) ServeViewer(w http.ResponseWriter, r *http.Request) {
-	data := template.PageData{
-		Title:   "Viewer",
-		Scripts: []string{"/static/js/viewer.js"},
+	data := pageData(r, "Viewer", "/static/js/ui.js", "/static/js/viewer.js")
+	data.Preview = h.viewerPreview(r)
+	data.LowPower = h.lowPower(w, r)
+
+	if err := h.templateService.RenderPage(w, "viewer.html", data); err != nil {
+		log.Printf("Error rendering viewer template: %v", err)
+		http.Error(w, "Internal server error", 500)
 	}
+}
+
+// ServeRoom serves the viewer page at a room's URL; the page looks up the
+// room's current session itself and waits while the sender is away
+func (h *StaticHandlers) ServeRoom(w http.ResponseWriter, r *http.Request) {
+	data := pageData(r, "Viewer", "/static/js/ui.js", "/static/js/viewer.js")
+	data.LowPower = h.lowPower(w, r)
 
 	if err := h.templateService.RenderPage(w, "viewer.html", data); err != nil {
 		log.Printf("Error rendering viewer template: %v", err)
@@ -57,22 +74,150 @@ func (h *StaticHandlers) ServeViewer(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ServeSenderJS serves the sender JavaScript with configured STUN server
-func (h *StaticHandlers) ServeSenderJS(w http.ResponseWriter, r *http.Request) {
-	data := template.PageData{}
+// ServeViewerLink serves the viewer page at a viewer link's URL; the page
+// redeems the link for its session itself, so the URL stops working once
+// the link expires
+func (h *StaticHandlers) ServeViewerLink(w http.ResponseWriter, r *http.Request) {
+	data := pageData(r, "Viewer", "/static/js/ui.js", "/static/js/viewer.js")
+	data.LowPower = h.lowPower(w, r)
 
-	if err := h.templateService.RenderJS(w, "web/templates/sender.js.tmpl", data); err != nil {
-		log.Printf("Error rendering sender.js template: %v", err)
+	if err := h.templateService.RenderPage(w, "viewer.html", data); err != nil {
+		log.Printf("Error rendering viewer template: %v", err)
 		http.Error(w, "Internal server error", 500)
 	}
 }
 
-// ServeViewerJS serves the viewer JavaScript with configured STUN server
-func (h *StaticHandlers) ServeViewerJS(w http.ResponseWriter, r *http.Request) {
-	data := template.PageData{}
+// ServeViewerAlias sends viewers opening a session by its alias, such as
+// /v/BlueTiger42, on to the session's viewer page, keeping any options in
+// the query string. Aliases last as long as their token.
+func (h *StaticHandlers) ServeViewerAlias(w http.ResponseWriter, r *http.Request) {
+	response, err := h.sessionUseCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: r.PathValue("alias")})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("token", response.Token)
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, "/viewer?"+query.Encode(), http.StatusFound)
+}
+
+// pageData builds the data shared by every page, including the visitor's
+// display preferences so the layout renders in the chosen theme
+func pageData(r *http.Request, title string, scripts ...string) template.PageData {
+	return template.PageData{
+		Title:    title,
+		Scripts:  scripts,
+		Contrast: readPreferences(r).Contrast,
+		Path:     r.URL.RequestURI(),
+	}
+}
+
+// lowPower reports whether the viewer's device has low-power mode enabled,
+// issuing a device cookie on the first visit so the setting can be saved
+func (h *StaticHandlers) lowPower(w http.ResponseWriter, r *http.Request) bool {
+	request := &dto.GetViewerSettingsRequest{DeviceID: ensureDeviceID(w, r)}
+	response, err := h.settingsUseCase.GetSettings(request)
+	if err != nil {
+		return false
+	}
+	return response.LowPower
+}
+
+// viewerPreview builds link preview metadata for a viewer URL, or nil when disabled
+func (h *StaticHandlers) viewerPreview(r *http.Request) *template.LinkPreview {
+	if !h.linkPreview {
+		return nil
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		return nil
+	}
 
-	if err := h.templateService.RenderJS(w, "web/templates/viewer.js.tmpl", data); err != nil {
-		log.Printf("Error rendering viewer.js template: %v", err)
+	response, err := h.sessionUseCase.GetLinkPreview(&dto.GetLinkPreviewRequest{Token: token})
+	if err != nil || !response.Enabled {
+		return nil
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	title := "Screen share"
+	if response.Name != "" {
+		title = response.Name
+	}
+
+	preview := &template.LinkPreview{
+		Title:       title,
+		Description: "Live screen share",
+		URL:         scheme + "://" + r.Host + r.URL.RequestURI(),
+	}
+	// The server can only take a picture of the screen when it relays it
+	if response.Thumbnail {
+		preview.Image = scheme + "://" + r.Host + "/api/v1/snapshot?" + url.Values{"token": {token}}.Encode()
+	}
+	return preview
+}
+
+// ServeDemo serves the single-page demo hosting both sender and viewer
+func (h *StaticHandlers) ServeDemo(w http.ResponseWriter, r *http.Request) {
+	data := pageData(r, "Demo", "/static/js/demo.js")
+
+	if err := h.templateService.RenderPage(w, "demo.html", data); err != nil {
+		log.Printf("Error rendering demo template: %v", err)
 		http.Error(w, "Internal server error", 500)
 	}
 }
+
+// ServeSummary serves the post-share summary page for the sender
+func (h *StaticHandlers) ServeSummary(w http.ResponseWriter, r *http.Request) {
+	data := pageData(r, "Session Summary", "/static/js/ui.js", "/static/js/summary.js")
+
+	if err := h.templateService.RenderPage(w, "summary.html", data); err != nil {
+		log.Printf("Error rendering summary template: %v", err)
+		http.Error(w, "Internal server error", 500)
+	}
+}
+
+// ServeAdmin serves the admin dashboard; its data comes from the token-protected admin API
+func (h *StaticHandlers) ServeAdmin(w http.ResponseWriter, r *http.Request) {
+	data := pageData(r, "Admin Dashboard", "/static/js/ui.js", "/static/js/admin.js")
+
+	if err := h.templateService.RenderPage(w, "admin.html", data); err != nil {
+		log.Printf("Error rendering admin template: %v", err)
+		http.Error(w, "Internal server error", 500)
+	}
+}
+
+// Assets serves the pages' scripts and stylesheet, at their plain paths or
+// the hashed URLs the pages link to, and passes other paths on to next. A
+// hashed URL never changes content, so browsers keep it for a year and
+// reconnecting viewers load nothing again; the rest is revalidated by ETag.
+func (h *StaticHandlers) Assets(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asset, current, err := h.templateService.ResolveAsset(r.URL.Path)
+		if errors.Is(err, template.ErrAssetNotFound) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			log.Printf("Error serving %v", err)
+			http.Error(w, "Internal server error", 500)
+			return
+		}
+
+		header := w.Header()
+		header.Set("Content-Type", asset.ContentType)
+		header.Set("ETag", asset.ETag())
+		if current {
+			header.Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			header.Set("Cache-Control", "no-cache")
+		}
+		http.ServeContent(w, r, asset.Path, asset.ModTime, bytes.NewReader(asset.Body))
+	})
+}
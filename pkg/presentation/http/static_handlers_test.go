@@ -0,0 +1,205 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"share-screen/pkg/infrastructure/template"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestStaticHandlers_ServeViewerAlias(t *testing.T) {
+	tests := []struct {
+		name               string
+		target             string
+		resolveError       error
+		expectedStatusCode int
+		expectedLocation   string
+	}{
+		{
+			name:               "known alias",
+			target:             "/v/BlueTiger42",
+			expectedStatusCode: 302,
+			expectedLocation:   "/viewer?token=mock-token",
+		},
+		{
+			name:               "options kept",
+			target:             "/v/BlueTiger42?hls=1&token=other",
+			expectedStatusCode: 302,
+			expectedLocation:   "/viewer?hls=1&token=mock-token",
+		},
+		{
+			name:               "unknown alias",
+			target:             "/v/BlueTiger42",
+			resolveError:       usecases.ErrViewerAliasNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "expired session",
+			target:             "/v/BlueTiger42",
+			resolveError:       usecases.ErrSessionExpired,
+			expectedStatusCode: 410,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.ResolveAliasError = tt.resolveError
+			handlers := NewStaticHandlers(nil, mockSessionUseCase, nil, false)
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+			req.SetPathValue("alias", "BlueTiger42")
+			w := httptest.NewRecorder()
+
+			handlers.ServeViewerAlias(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if location := w.Header().Get("Location"); location != tt.expectedLocation {
+				t.Errorf("Expected Location %q, got %q", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestStaticHandlers_ViewerPreview(t *testing.T) {
+	tests := []struct {
+		name          string
+		linkPreview   bool
+		response      *dto.LinkPreviewResponse
+		expectPreview bool
+		expectedImage string
+	}{
+		{
+			name:          "peer-to-peer session",
+			linkPreview:   true,
+			response:      &dto.LinkPreviewResponse{Name: "Design review", Enabled: true},
+			expectPreview: true,
+		},
+		{
+			name:          "SFU session with a thumbnail",
+			linkPreview:   true,
+			response:      &dto.LinkPreviewResponse{Enabled: true, Thumbnail: true},
+			expectPreview: true,
+			expectedImage: "http://example.com/api/v1/snapshot?token=abc123",
+		},
+		{
+			name:        "preview disabled for the session",
+			linkPreview: true,
+			response:    &dto.LinkPreviewResponse{Enabled: false, Thumbnail: true},
+		},
+		{
+			name:     "previews turned off",
+			response: &dto.LinkPreviewResponse{Enabled: true, Thumbnail: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionUseCase := mocks.NewMockSessionUseCase()
+			mockSessionUseCase.LinkPreviewResponse = tt.response
+			handlers := NewStaticHandlers(nil, mockSessionUseCase, nil, tt.linkPreview)
+
+			preview := handlers.viewerPreview(httptest.NewRequest("GET", "http://example.com/viewer?token=abc123", nil))
+
+			if (preview != nil) != tt.expectPreview {
+				t.Fatalf("Expected preview %v, got %+v", tt.expectPreview, preview)
+			}
+			if preview != nil && preview.Image != tt.expectedImage {
+				t.Errorf("Expected image %q, got %q", tt.expectedImage, preview.Image)
+			}
+		})
+	}
+}
+
+func TestStaticHandlers_Assets(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{"base.html": `{{template "content" .}}`, "app.js.tmpl": `console.log('app');`} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	templateService, err := template.NewTemplateService(dir)
+	if err != nil {
+		t.Fatalf("Failed to create template service: %v", err)
+	}
+	asset, err := templateService.Asset("/static/js/app.js")
+	if err != nil {
+		t.Fatalf("Expected the script registered: %v", err)
+	}
+	handler := NewStaticHandlers(templateService, nil, nil, false).Assets(http.NotFoundHandler())
+
+	tests := []struct {
+		name                 string
+		target               string
+		ifNoneMatch          string
+		expectedStatusCode   int
+		expectedCacheControl string
+	}{
+		{
+			name:                 "hashed URL",
+			target:               asset.URL(),
+			expectedStatusCode:   200,
+			expectedCacheControl: "public, max-age=31536000, immutable",
+		},
+		{
+			name:                 "plain path",
+			target:               "/static/js/app.js",
+			expectedStatusCode:   200,
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:                 "stale hash",
+			target:               "/static/js/app.000000000000.js",
+			expectedStatusCode:   200,
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:                 "revalidated",
+			target:               "/static/js/app.js",
+			ifNoneMatch:          asset.ETag(),
+			expectedStatusCode:   304,
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:               "other file",
+			target:             "/static/img/logo.png",
+			expectedStatusCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != tt.expectedCacheControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.expectedCacheControl, cache)
+			}
+			if tt.expectedStatusCode != 200 {
+				return
+			}
+			if etag := w.Header().Get("ETag"); etag != asset.ETag() {
+				t.Errorf("Expected ETag %s, got %s", asset.ETag(), etag)
+			}
+			if w.Body.String() != "console.log('app');" {
+				t.Errorf("Unexpected body %q", w.Body.String())
+			}
+		})
+	}
+}
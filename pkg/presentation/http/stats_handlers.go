@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// StatsHandlers contains handlers for the WebRTC stats the sender and viewer
+// pages upload and the daily totals admins read
+type StatsHandlers struct {
+	statsUseCase interfaces.StatsUseCase
+	admin        *AdminHandlers
+}
+
+// NewStatsHandlers creates a new stats handlers instance; admin decides who
+// may read the daily totals
+func NewStatsHandlers(statsUseCase interfaces.StatsUseCase, admin *AdminHandlers) *StatsHandlers {
+	return &StatsHandlers{
+		statsUseCase: statsUseCase,
+		admin:        admin,
+	}
+}
+
+// HandleStats stores a peer's periodic summary of its getStats() report
+func (h *StatsHandlers) HandleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	var request dto.RecordStatsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		log.Printf("❌ Invalid stats payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	if err := h.statsUseCase.RecordStats(&request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleSummary returns today's session totals to admins
+func (h *StatsHandlers) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	if !h.admin.allow(w, r, http.MethodGet) {
+		return
+	}
+
+	summary, err := h.statsUseCase.GetStatsSummary()
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(summary); err != nil {
+		log.Printf("Error encoding stats summary: %v", err)
+	}
+}
+
+// HandleViewerStats returns the connection quality of each of a session's
+// viewers, for the sender's ?senderKey=; the sender page polls it
+func (h *StatsHandlers) HandleViewerStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	response, err := h.statsUseCase.GetViewerStats(&dto.GetViewerStatsRequest{
+		Token:     r.PathValue("token"),
+		SenderKey: r.URL.Query().Get("senderKey"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding viewer stats: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// newStatsHandlers creates stats handlers for admins with token
+func newStatsHandlers(statsUseCase *mocks.MockStatsUseCase, token string) *StatsHandlers {
+	admin := NewAdminHandlers(mocks.NewMockSessionUseCase(), statsUseCase, mocks.NewMockAuditUseCase(), mocks.NewMockCleanupUseCase(), mocks.NewMockBanUseCase(), nil, token, nil)
+	return NewStatsHandlers(statsUseCase, admin)
+}
+
+func TestStatsHandlers_HandleStats(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		recordError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "stats stored",
+			method:             "POST",
+			body:               `{"token":"test-token","role":"viewer","viewerId":"viewer-1","bitrate":1500000,"fps":30,"packetLoss":0.02,"rttMs":40}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+		{
+			name:               "invalid JSON",
+			method:             "POST",
+			body:               `{"token":`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "invalid stats",
+			method:             "POST",
+			body:               `{"token":"test-token","role":"admin"}`,
+			recordError:        usecases.ErrInvalidStats,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "ended session",
+			method:             "POST",
+			body:               `{"token":"test-token","role":"sender"}`,
+			recordError:        usecases.ErrSessionEnded,
+			expectedStatusCode: 410,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStatsUseCase := mocks.NewMockStatsUseCase()
+			mockStatsUseCase.RecordStatsError = tt.recordError
+			handlers := newStatsHandlers(mockStatsUseCase, "admin-token")
+
+			req := httptest.NewRequest(tt.method, "/api/stats", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			handlers.HandleStats(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 204 {
+				return
+			}
+			request := mockStatsUseCase.LastRecordRequest
+			if request.Token != "test-token" || request.ViewerID != "viewer-1" || request.Bitrate != 1500000 || request.RTTMillis != 40 {
+				t.Errorf("Expected the body to reach the use case, got %+v", request)
+			}
+		})
+	}
+}
+
+func TestStatsHandlers_HandleSummary(t *testing.T) {
+	mockStatsUseCase := mocks.NewMockStatsUseCase()
+	handlers := newStatsHandlers(mockStatsUseCase, "admin-token")
+
+	req := httptest.NewRequest("GET", "/api/stats/summary", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	w := httptest.NewRecorder()
+	handlers.HandleSummary(w, req)
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200 but got %d", w.Code)
+	}
+	var summary dto.StatsSummaryResponse
+	if err := json.NewDecoder(w.Body).Decode(&summary); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if summary.Created != 3 || summary.PeakConcurrent != 2 {
+		t.Errorf("Expected the use case's totals, got %+v", summary)
+	}
+
+	w = httptest.NewRecorder()
+	handlers.HandleSummary(w, httptest.NewRequest("POST", "/api/stats/summary", nil))
+	if w.Code != 405 {
+		t.Errorf("Expected status code 405 but got %d", w.Code)
+	}
+
+	// The totals are for admins only
+	w = httptest.NewRecorder()
+	handlers.HandleSummary(w, httptest.NewRequest("GET", "/api/stats/summary", nil))
+	if w.Code != 401 {
+		t.Errorf("Expected status code 401 without the admin token but got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	newStatsHandlers(mockStatsUseCase, "").HandleSummary(w, req)
+	if w.Code != 404 {
+		t.Errorf("Expected status code 404 without an admin API but got %d", w.Code)
+	}
+}
+
+func TestStatsHandlers_HandleViewerStats(t *testing.T) {
+	mockStatsUseCase := mocks.NewMockStatsUseCase()
+	handlers := newStatsHandlers(mockStatsUseCase, "admin-token")
+
+	req := httptest.NewRequest("GET", "/api/v1/sessions/test-token/viewer-stats?senderKey=key", nil)
+	req.SetPathValue("token", "test-token")
+	w := httptest.NewRecorder()
+	handlers.HandleViewerStats(w, req)
+	if w.Code != 200 || w.Header().Get("Cache-Control") != "no-store" {
+		t.Fatalf("Expected an uncached 200, got %d with headers %v", w.Code, w.Header())
+	}
+	var response dto.ViewerStatsResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(response.Viewers) != 1 || response.Viewers[0].Quality != "good" {
+		t.Errorf("Expected the use case's viewers, got %+v", response)
+	}
+	if request := mockStatsUseCase.LastViewerStatsRequest; request.Token != "test-token" || request.SenderKey != "key" {
+		t.Errorf("Expected the token and sender key passed on, got %+v", request)
+	}
+
+	mockStatsUseCase.GetViewerStatsError = usecases.ErrInvalidSenderKey
+	w = httptest.NewRecorder()
+	handlers.HandleViewerStats(w, req)
+	if w.Code != 403 {
+		t.Errorf("Expected status code 403 for a wrong sender key but got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handlers.HandleViewerStats(w, httptest.NewRequest("POST", "/api/v1/sessions/test-token/viewer-stats", nil))
+	if w.Code != 405 {
+		t.Errorf("Expected status code 405 but got %d", w.Code)
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// maxStatusWait caps how long a status long-poll may be held open
+	maxStatusWait = 60 * time.Second
+
+	// statusPollInterval is how often a held status request rechecks the viewers
+	statusPollInterval = time.Second
+)
+
+// StatusHandlers serves the viewer status summary for widgets, watch
+// complications and menu bar apps
+type StatusHandlers struct {
+	statusUseCase interfaces.StatusUseCase
+	token         string
+	pollInterval  time.Duration
+}
+
+// NewStatusHandlers creates a new status handlers instance; token is the
+// bearer token clients must send, and an empty token disables the endpoint
+func NewStatusHandlers(statusUseCase interfaces.StatusUseCase, token string) *StatusHandlers {
+	return &StatusHandlers{
+		statusUseCase: statusUseCase,
+		token:         token,
+		pollInterval:  statusPollInterval,
+	}
+}
+
+// HandleStatus reports whether anyone is viewing. A client that sends the
+// ETag of its last response in If-None-Match along with ?wait=<seconds> is
+// held until the status changes, answering 304 if it does not change in time.
+func (h *StatusHandlers) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	if h.token == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+	if !bearerAuthorized(r, h.token) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="share-screen"`)
+		http.Error(w, "unauthorized", 401)
+		return
+	}
+
+	wait, err := parseStatusWait(r.URL.Query().Get("wait"))
+	if err != nil {
+		http.Error(w, "invalid wait", 400)
+		return
+	}
+
+	status, err := h.statusUseCase.GetViewerStatus()
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	known := r.Header.Get("If-None-Match")
+	if known != "" && wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		ticker := time.NewTicker(h.pollInterval)
+		defer ticker.Stop()
+
+		for statusETag(status) == known {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-timer.C:
+				w.Header().Set("ETag", known)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			case <-ticker.C:
+				if status, err = h.statusUseCase.GetViewerStatus(); err != nil {
+					writeUseCaseError(w, err)
+					return
+				}
+			}
+		}
+	}
+
+	etag := statusETag(status)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-store")
+	if etag == known {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Printf("Error encoding status response: %v", err)
+	}
+}
+
+// bearerAuthorized checks the request's bearer token against want in constant time
+func bearerAuthorized(r *http.Request, want string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
+
+// parseStatusWait reads the long-poll duration in seconds, capped at maxStatusWait
+func parseStatusWait(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid wait %q", value)
+	}
+	return min(time.Duration(seconds)*time.Second, maxStatusWait), nil
+}
+
+// statusETag identifies a status so clients can wait for it to change
+func statusETag(status *dto.ViewerStatusResponse) string {
+	return fmt.Sprintf(`"%t-%d-%d-%d"`, status.Viewing, status.Viewers, status.Queued, status.Sessions)
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestStatusHandlers_HandleStatus(t *testing.T) {
+	viewing := &dto.ViewerStatusResponse{Viewing: true, Viewers: 1, Sessions: 1}
+
+	tests := []struct {
+		name               string
+		token              string
+		method             string
+		authorization      string
+		query              string
+		ifNoneMatch        string
+		statusError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "status returned",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "endpoint disabled",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "missing token",
+			token:              "secret-token",
+			method:             "GET",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "wrong token",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer other",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "unchanged status",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			ifNoneMatch:        statusETag(viewing),
+			expectedStatusCode: 304,
+		},
+		{
+			name:               "long-poll times out unchanged",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			query:              "?wait=1",
+			ifNoneMatch:        statusETag(viewing),
+			expectedStatusCode: 304,
+		},
+		{
+			name:               "stale etag answered at once",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			query:              "?wait=30",
+			ifNoneMatch:        `"stale"`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "invalid wait",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			query:              "?wait=soon",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "use case error",
+			token:              "secret-token",
+			method:             "GET",
+			authorization:      "Bearer secret-token",
+			statusError:        errors.New("storage failure"),
+			expectedStatusCode: 500,
+		},
+		{
+			name:               "method not allowed",
+			token:              "secret-token",
+			method:             "POST",
+			authorization:      "Bearer secret-token",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStatusUseCase := mocks.NewMockStatusUseCase()
+			mockStatusUseCase.StatusResponse = viewing
+			mockStatusUseCase.GetViewerStatusError = tt.statusError
+			handlers := NewStatusHandlers(mockStatusUseCase, tt.token)
+			handlers.pollInterval = 10 * time.Millisecond
+
+			req := httptest.NewRequest(tt.method, "/api/status"+tt.query, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+
+			handlers.HandleStatus(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code == 401 && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header")
+			}
+			if w.Code == 200 && w.Header().Get("ETag") != statusETag(viewing) {
+				t.Errorf("Expected ETag %s, got %s", statusETag(viewing), w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+// changingStatusUseCase reports idle for a few calls, then a connected viewer
+type changingStatusUseCase struct {
+	calls int
+}
+
+func (uc *changingStatusUseCase) GetViewerStatus() (*dto.ViewerStatusResponse, error) {
+	uc.calls++
+	if uc.calls < 3 {
+		return &dto.ViewerStatusResponse{Sessions: 1}, nil
+	}
+	return &dto.ViewerStatusResponse{Viewing: true, Viewers: 1, Sessions: 1}, nil
+}
+
+func TestStatusHandlers_LongPollReturnsChange(t *testing.T) {
+	statusUseCase := &changingStatusUseCase{}
+	handlers := NewStatusHandlers(statusUseCase, "secret-token")
+	handlers.pollInterval = 10 * time.Millisecond
+
+	req := httptest.NewRequest("GET", "/api/status?wait=5", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("If-None-Match", statusETag(&dto.ViewerStatusResponse{Sessions: 1}))
+	w := httptest.NewRecorder()
+
+	start := time.Now()
+	handlers.HandleStatus(w, req)
+
+	if w.Code != 200 {
+		t.Fatalf("Expected status code 200, got %d", w.Code)
+	}
+	if statusUseCase.calls != 3 {
+		t.Errorf("Expected 3 status checks, got %d", statusUseCase.calls)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("Expected the long-poll to return as soon as the status changed")
+	}
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// TokenFilter turns away requests whose session token cannot have been
+// handed out by this server, or whose signed token has expired, before any
+// session lookup
+type TokenFilter struct {
+	policy entities.TokenPolicy
+	now    func() time.Time
+}
+
+// NewTokenFilter creates a token filter for the server's token policy
+func NewTokenFilter(policy entities.TokenPolicy) *TokenFilter {
+	return &TokenFilter{
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Reject answers 400 for a malformed or forged token and 410 for an expired
+// signed one, and reports whether it did. A nil filter accepts any token, and
+// an empty token is left to the handler to refuse.
+func (f *TokenFilter) Reject(w http.ResponseWriter, r *http.Request, token string) bool {
+	if f == nil || token == "" {
+		return false
+	}
+	if !f.policy.Valid(token) {
+		log.Printf("🚫 Malformed session token on %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+		http.Error(w, "malformed token", 400)
+		return true
+	}
+	if expiresAt := f.policy.ExpiresAt(token); !expiresAt.IsZero() && f.now().After(expiresAt) {
+		http.Error(w, "session expired", 410)
+		return true
+	}
+	return false
+}
+
+// Wrap checks the tokens in the path and the query string before they reach next
+func (f *TokenFilter) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	if f == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if f.Reject(w, r, r.PathValue("token")) || f.Reject(w, r, r.URL.Query().Get("token")) {
+			return
+		}
+		next(w, r)
+	}
+}
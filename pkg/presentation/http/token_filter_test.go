@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestTokenFilter(t *testing.T) {
+	policy, _ := entities.ParseTokenPolicy("hex", 8, "", 0)
+	filter := NewTokenFilter(policy)
+
+	tests := []struct {
+		name               string
+		target             string
+		pathToken          string
+		expectedStatusCode int
+	}{
+		{
+			name:               "well-formed path token",
+			target:             "/api/v1/sessions/0011223344556677/heartbeat",
+			pathToken:          "0011223344556677",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "malformed path token",
+			target:             "/api/v1/sessions/not-a-token/heartbeat",
+			pathToken:          "not-a-token",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "well-formed query token",
+			target:             "/api/v1/offer?token=0011223344556677",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "malformed query token",
+			target:             "/api/v1/offer?token=0011223344556677ff",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "no token",
+			target:             "/api/v1/new",
+			expectedStatusCode: 200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := filter.Wrap(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+			req.SetPathValue("token", tt.pathToken)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+			if called != (tt.expectedStatusCode == 200) {
+				t.Errorf("Expected the wrapped handler to run only for well-formed tokens, ran: %v", called)
+			}
+		})
+	}
+}
+
+func TestTokenFilter_Signed(t *testing.T) {
+	policy, _ := entities.ParseTokenPolicy("", 0, strings.Repeat("s", entities.MinTokenSecretLength), time.Hour)
+	filter := NewTokenFilter(policy)
+	random := make([]byte, policy.RandomBytes())
+
+	tests := []struct {
+		name               string
+		token              string
+		expectedStatusCode int
+	}{
+		{
+			name:               "live token",
+			token:              policy.Encode(random, time.Now()),
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "expired token",
+			token:              policy.Encode(random, time.Now().Add(-2*time.Hour)),
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "unsigned token",
+			token:              "AAAAAAAAAAAA",
+			expectedStatusCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := filter.Wrap(func(w http.ResponseWriter, r *http.Request) {})
+
+			req := httptest.NewRequest("GET", "/api/v1/sessions/"+tt.token+"/events", nil)
+			req.SetPathValue("token", tt.token)
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d but got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPIHandlers_MalformedBodyToken(t *testing.T) {
+	policy, _ := entities.ParseTokenPolicy("", 0, "", 0)
+	handlers := NewAPIHandlers(mocks.NewMockSessionUseCase(), mocks.NewMockServerInfoUseCase(), NewTokenFilter(policy))
+
+	req := httptest.NewRequest("POST", "/api/v1/offer", strings.NewReader(`{"token": "../../etc", "offer": {"type": "offer", "sdp": "v=0"}}`))
+	w := httptest.NewRecorder()
+
+	handlers.HandleOffer(w, req)
+
+	if w.Code != 400 {
+		t.Errorf("Expected status code 400 for a malformed token but got %d", w.Code)
+	}
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// ViewerLinkHandlers contains handlers for the viewer links a sender hands
+// out, which expire and run out of uses ahead of their session
+type ViewerLinkHandlers struct {
+	viewerLinkUseCase interfaces.ViewerLinkUseCase
+}
+
+// NewViewerLinkHandlers creates a new viewer link handlers instance
+func NewViewerLinkHandlers(viewerLinkUseCase interfaces.ViewerLinkUseCase) *ViewerLinkHandlers {
+	return &ViewerLinkHandlers{
+		viewerLinkUseCase: viewerLinkUseCase,
+	}
+}
+
+// HandleLinks handles the viewer links of the session in the path (GET to
+// list them, with the sender key in the query, POST to hand out a new one)
+func (h *ViewerLinkHandlers) HandleLinks(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodGet:
+		h.handleListLinks(w, r)
+	case http.MethodPost:
+		h.handleCreateLink(w, r)
+	default:
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *ViewerLinkHandlers) handleListLinks(w http.ResponseWriter, r *http.Request) {
+	response, err := h.viewerLinkUseCase.ListLinks(&dto.ListViewerLinksRequest{
+		Token:     r.PathValue("token"),
+		SenderKey: r.URL.Query().Get("senderKey"),
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeViewerLinkJSON(w, 200, response)
+}
+
+func (h *ViewerLinkHandlers) handleCreateLink(w http.ResponseWriter, r *http.Request) {
+	request := &dto.CreateViewerLinkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid viewer link payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ClientIP = clientIP(r)
+
+	response, err := h.viewerLinkUseCase.CreateLink(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeViewerLinkJSON(w, 201, response)
+}
+
+// HandleRevokeLink withdraws the viewer link in the path; the body carries
+// the sender key that proves the request comes from the session's sender
+func (h *ViewerLinkHandlers) HandleRevokeLink(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.RevokeViewerLinkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid viewer link payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.Token = r.PathValue("token")
+	request.ID = r.PathValue("id")
+	request.ClientIP = clientIP(r)
+
+	if err := h.viewerLinkUseCase.RevokeLink(request); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+	w.WriteHeader(204)
+}
+
+// HandleRedeemLink redeems the viewer link in the path for the viewer in the
+// body and answers with the token of the session it leads to
+func (h *ViewerLinkHandlers) HandleRedeemLink(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 API: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", 405)
+		return
+	}
+
+	request := &dto.RedeemViewerLinkRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		log.Printf("❌ Invalid viewer link payload: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+	request.ID = r.PathValue("id")
+	request.ClientIP = clientIP(r)
+
+	response, err := h.viewerLinkUseCase.RedeemLink(request)
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	writeViewerLinkJSON(w, 200, response)
+}
+
+// writeViewerLinkJSON writes a viewer link response, which carries secrets
+// and so is never cached
+func writeViewerLinkJSON(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Error encoding viewer link response: %v", err)
+	}
+}
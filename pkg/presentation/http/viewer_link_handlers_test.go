@@ -0,0 +1,265 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+func TestViewerLinkHandlers_HandleLinks(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		target             string
+		body               string
+		createError        error
+		listError          error
+		expectedStatusCode int
+	}{
+		{
+			name:               "hand out link",
+			method:             "POST",
+			target:             "/api/v1/sessions/test-token/links",
+			body:               `{"senderKey":"sender-key","ttlSeconds":300,"maxRedemptions":1}`,
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "invalid link",
+			method:             "POST",
+			target:             "/api/v1/sessions/test-token/links",
+			body:               `{"senderKey":"sender-key"}`,
+			createError:        usecases.ErrInvalidViewerLink,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "too many links",
+			method:             "POST",
+			target:             "/api/v1/sessions/test-token/links",
+			body:               `{"senderKey":"sender-key","ttlSeconds":300}`,
+			createError:        usecases.ErrTooManyViewerLinks,
+			expectedStatusCode: 409,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			target:             "/api/v1/sessions/test-token/links",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "list links",
+			method:             "GET",
+			target:             "/api/v1/sessions/test-token/links?senderKey=sender-key",
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "wrong sender key",
+			method:             "GET",
+			target:             "/api/v1/sessions/test-token/links?senderKey=guess",
+			listError:          usecases.ErrInvalidSenderKey,
+			expectedStatusCode: 403,
+		},
+		{
+			name:               "method not allowed",
+			method:             "DELETE",
+			target:             "/api/v1/sessions/test-token/links",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockViewerLinkUseCase := mocks.NewMockViewerLinkUseCase()
+			mockViewerLinkUseCase.CreateLinkError = tt.createError
+			mockViewerLinkUseCase.ListLinksError = tt.listError
+			handlers := NewViewerLinkHandlers(mockViewerLinkUseCase)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			w := httptest.NewRecorder()
+
+			handlers.HandleLinks(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code >= 300 {
+				return
+			}
+			if cache := w.Header().Get("Cache-Control"); cache != "no-store" {
+				t.Errorf("Expected Cache-Control no-store, got %q", cache)
+			}
+
+			if tt.method == "POST" {
+				request := mockViewerLinkUseCase.LastCreateRequest
+				if request.Token != "test-token" || request.SenderKey != "sender-key" || request.TTLSeconds != 300 || request.MaxRedemptions != 1 {
+					t.Errorf("Unexpected create request: %+v", request)
+				}
+				var response dto.ViewerLink
+				if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+					t.Fatalf("Failed to decode response: %v", err)
+				}
+				if response.Path != "/l/mock-link" {
+					t.Errorf("Unexpected response: %+v", response)
+				}
+				return
+			}
+
+			if request := mockViewerLinkUseCase.LastListRequest; request.Token != "test-token" || request.SenderKey != "sender-key" {
+				t.Errorf("Unexpected list request: %+v", request)
+			}
+			var response dto.ViewerLinksResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Links) != 1 {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestViewerLinkHandlers_HandleRevokeLink(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		revokeError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "revoke link",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "unknown link",
+			method:             "POST",
+			body:               `{"senderKey":"sender-key"}`,
+			revokeError:        usecases.ErrViewerLinkNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockViewerLinkUseCase := mocks.NewMockViewerLinkUseCase()
+			mockViewerLinkUseCase.RevokeLinkError = tt.revokeError
+			handlers := NewViewerLinkHandlers(mockViewerLinkUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/sessions/test-token/links/mock-link/revoke", strings.NewReader(tt.body))
+			req.SetPathValue("token", "test-token")
+			req.SetPathValue("id", "mock-link")
+			w := httptest.NewRecorder()
+
+			handlers.HandleRevokeLink(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code == 204 {
+				request := mockViewerLinkUseCase.LastRevokeRequest
+				if request.Token != "test-token" || request.ID != "mock-link" || request.SenderKey != "sender-key" {
+					t.Errorf("Unexpected revoke request: %+v", request)
+				}
+			}
+		})
+	}
+}
+
+func TestViewerLinkHandlers_HandleRedeemLink(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		body               string
+		redeemError        error
+		expectedStatusCode int
+	}{
+		{
+			name:               "redeem link",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1"}`,
+			expectedStatusCode: 200,
+		},
+		{
+			name:               "expired link",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1"}`,
+			redeemError:        usecases.ErrViewerLinkExpired,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "used up link",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1"}`,
+			redeemError:        usecases.ErrViewerLinkUsed,
+			expectedStatusCode: 410,
+		},
+		{
+			name:               "unknown link",
+			method:             "POST",
+			body:               `{"viewerId":"viewer-1"}`,
+			redeemError:        usecases.ErrViewerLinkNotFound,
+			expectedStatusCode: 404,
+		},
+		{
+			name:               "invalid payload",
+			method:             "POST",
+			body:               `{`,
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockViewerLinkUseCase := mocks.NewMockViewerLinkUseCase()
+			mockViewerLinkUseCase.RedeemLinkError = tt.redeemError
+			handlers := NewViewerLinkHandlers(mockViewerLinkUseCase)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/links/mock-link", strings.NewReader(tt.body))
+			req.SetPathValue("id", "mock-link")
+			w := httptest.NewRecorder()
+
+			handlers.HandleRedeemLink(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if w.Code != 200 {
+				return
+			}
+			if request := mockViewerLinkUseCase.LastRedeemRequest; request.ID != "mock-link" || request.ViewerID != "viewer-1" {
+				t.Errorf("Unexpected redeem request: %+v", request)
+			}
+			var response dto.RedeemViewerLinkResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Token != "mock-token" {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// whipPath is the WHIP endpoint; the resource of a session's ingest is
+	// whipPath plus "/" and its token
+	whipPath = "/whip"
+
+	// maxWHIPOffer bounds an encoder's offer; an SDP with audio and video
+	// stays well below it
+	maxWHIPOffer = 64 << 10
+
+	// sdpContentType is the media type of WHIP offers and answers
+	sdpContentType = "application/sdp"
+)
+
+// WHIPHandlers implement WHIP ingest (RFC 9725), so encoders such as OBS
+// publish into a session as its sender. The encoder is given the server's
+// /whip URL and the session token as its bearer token; the session must
+// stream through the SFU, which forwards the video to every viewer.
+type WHIPHandlers struct {
+	sessionUseCase interfaces.SessionUseCase
+	tokens         *TokenFilter
+}
+
+// NewWHIPHandlers creates a new WHIP handlers instance
+func NewWHIPHandlers(sessionUseCase interfaces.SessionUseCase, tokens *TokenFilter) *WHIPHandlers {
+	return &WHIPHandlers{
+		sessionUseCase: sessionUseCase,
+		tokens:         tokens,
+	}
+}
+
+// HandleEndpoint publishes an encoder's SDP offer to the session named by
+// its bearer token and responds 201 with the answer and the location of the
+// ingest's resource
+func (h *WHIPHandlers) HandleEndpoint(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 WHIP: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePublish(w, r)
+	case http.MethodOptions:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(204)
+	default:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+// HandleResource ends the session of an ingest when the encoder deletes its
+// resource. Trickle ICE and ICE restarts are not supported, so PATCH gets 405
+// as the protocol asks.
+func (h *WHIPHandlers) HandleResource(w http.ResponseWriter, r *http.Request) {
+	log.Printf("📞 WHIP: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+	switch r.Method {
+	case http.MethodDelete:
+		h.handleStop(w, r)
+	case http.MethodOptions:
+		w.Header().Set("Allow", "DELETE, OPTIONS")
+		w.WriteHeader(204)
+	default:
+		w.Header().Set("Allow", "DELETE, OPTIONS")
+		http.Error(w, "method not allowed", 405)
+	}
+}
+
+func (h *WHIPHandlers) handlePublish(w http.ResponseWriter, r *http.Request) {
+	token, ok := bearerToken(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="share-screen whip"`)
+		http.Error(w, "expected the session token as bearer token", 401)
+		return
+	}
+	if h.tokens.Reject(w, r, token) {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != sdpContentType {
+		http.Error(w, "expected an "+sdpContentType+" offer", 415)
+		return
+	}
+
+	sdp, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWHIPOffer))
+	if err != nil {
+		log.Printf("❌ Reading WHIP offer failed: %v", err)
+		http.Error(w, err.Error(), 400)
+		return
+	}
+
+	response, err := h.sessionUseCase.PublishStream(&dto.PublishStreamRequest{
+		Token:    token,
+		Offer:    &entities.WebRTCOffer{Type: "offer", SDP: string(sdp)},
+		ClientIP: clientIP(r),
+		Ingest:   true,
+	})
+	if err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	log.Printf("📡 WHIP ingest started for token: %s", entities.ShortToken(token))
+	w.Header().Set("Content-Type", sdpContentType)
+	w.Header().Set("Location", whipPath+"/"+token)
+	w.WriteHeader(201)
+	if _, err := io.WriteString(w, response.Answer.SDP); err != nil {
+		log.Printf("Error writing WHIP answer: %v", err)
+	}
+}
+
+func (h *WHIPHandlers) handleStop(w http.ResponseWriter, r *http.Request) {
+	token := r.PathValue("token")
+	// The resource is deleted with the token it was created with
+	if bearer, ok := bearerToken(r); ok && bearer != token {
+		http.Error(w, "bearer token does not match the resource", 403)
+		return
+	}
+
+	if err := h.sessionUseCase.EndSession(&dto.EndSessionRequest{Token: token, ClientIP: clientIP(r), Ingest: true}); err != nil {
+		writeUseCaseError(w, err)
+		return
+	}
+
+	log.Printf("📡 WHIP ingest stopped for token: %s", entities.ShortToken(token))
+	w.WriteHeader(200)
+}
+
+// bearerToken returns the token of a request's bearer authorization
+func bearerToken(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	token = strings.TrimSpace(token)
+	return token, ok && token != ""
+}
@@ -0,0 +1,164 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestWHIPHandlers_HandleEndpoint(t *testing.T) {
+	token := entities.TokenPolicy{}.Encode(make([]byte, entities.TokenPolicy{}.RandomBytes()), time.Now())
+	tests := []struct {
+		name               string
+		method             string
+		authorization      string
+		contentType        string
+		body               string
+		failPublish        bool
+		expectedStatusCode int
+	}{
+		{
+			name:               "publish",
+			method:             "POST",
+			authorization:      "Bearer " + token,
+			contentType:        "application/sdp",
+			body:               "v=0 obs",
+			expectedStatusCode: 201,
+		},
+		{
+			name:               "missing bearer token",
+			method:             "POST",
+			contentType:        "application/sdp",
+			body:               "v=0 obs",
+			expectedStatusCode: 401,
+		},
+		{
+			name:               "malformed token",
+			method:             "POST",
+			authorization:      "Bearer not-a-token",
+			contentType:        "application/sdp",
+			body:               "v=0 obs",
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "not an SDP offer",
+			method:             "POST",
+			authorization:      "Bearer " + token,
+			contentType:        "application/json",
+			body:               `{"sdp":"v=0"}`,
+			expectedStatusCode: 415,
+		},
+		{
+			name:               "offer too large",
+			method:             "POST",
+			authorization:      "Bearer " + token,
+			contentType:        "application/sdp",
+			body:               strings.Repeat("a", maxWHIPOffer+1),
+			expectedStatusCode: 400,
+		},
+		{
+			name:               "publish fails",
+			method:             "POST",
+			authorization:      "Bearer " + token,
+			contentType:        "application/sdp",
+			body:               "v=0 obs",
+			failPublish:        true,
+			expectedStatusCode: 500,
+		},
+		{
+			name:               "options",
+			method:             "OPTIONS",
+			expectedStatusCode: 204,
+		},
+		{
+			name:               "method not allowed",
+			method:             "GET",
+			expectedStatusCode: 405,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockSessionUseCase()
+			mockUseCase.ShouldFailPublish = tt.failPublish
+			handlers := NewWHIPHandlers(mockUseCase, NewTokenFilter(entities.TokenPolicy{}))
+
+			req := httptest.NewRequest(tt.method, "/whip", strings.NewReader(tt.body))
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handlers.HandleEndpoint(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			if tt.expectedStatusCode != 201 {
+				return
+			}
+
+			request := mockUseCase.LastPublishRequest
+			if request.Token != token || request.Offer.Type != "offer" || request.Offer.SDP != "v=0 obs" {
+				t.Errorf("Unexpected publish request: %+v", request)
+			}
+			if location := w.Header().Get("Location"); location != "/whip/"+token {
+				t.Errorf("Expected the resource of the session, got %q", location)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != "application/sdp" {
+				t.Errorf("Expected an SDP answer, got %q", contentType)
+			}
+			if body := w.Body.String(); body != "mock-sfu-sdp" {
+				t.Errorf("Expected the SFU's answer, got %q", body)
+			}
+		})
+	}
+}
+
+func TestWHIPHandlers_HandleResource(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		authorization      string
+		expectedStatusCode int
+	}{
+		{name: "stop", method: "DELETE", authorization: "Bearer test-token", expectedStatusCode: 200},
+		{name: "stop without bearer token", method: "DELETE", expectedStatusCode: 200},
+		{name: "another session's token", method: "DELETE", authorization: "Bearer other-token", expectedStatusCode: 403},
+		{name: "trickle not supported", method: "PATCH", expectedStatusCode: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUseCase := mocks.NewMockSessionUseCase()
+			handlers := NewWHIPHandlers(mockUseCase, nil)
+
+			req := httptest.NewRequest(tt.method, "/whip/test-token", nil)
+			req.SetPathValue("token", "test-token")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			handlers.HandleResource(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+			ended := mockUseCase.LastEndSessionRequest
+			if tt.expectedStatusCode == 200 && (ended == nil || ended.Token != "test-token") {
+				t.Errorf("Expected the session ended, got %+v", ended)
+			}
+			if tt.expectedStatusCode != 200 && ended != nil {
+				t.Errorf("Expected the session kept, got %+v", ended)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Package probe defines the latency probe: pings a peer echoes back over the
+// "probe" data channel, so the sender and viewer pages can show how long a
+// round trip between them takes, e.g. "~35 ms on LAN", rather than just
+// "connected".
+//
+// The server does not take part in the echo; it publishes the schema the
+// pages agree on and records the round trips they measure. Messages are tiny
+// JSON objects such as {"type":"ping","seq":7,"t":81234.5}; the peer answers
+// with the same seq and t and the type "pong", and t is only meaningful to
+// the page that sent the ping. Pages that cannot open the channel, such as
+// viewers of a session relayed by the server, time the server's ping
+// endpoint instead.
+package probe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ChannelLabel is the label of the data channel carrying probes
+const ChannelLabel = "probe"
+
+// MaxMessageSize bounds a single probe message
+const MaxMessageSize = 128
+
+// Interval is how often each page pings its peer
+const Interval = 2 * time.Second
+
+// Samples is how many recent round trips a page takes the median of, so a
+// single slow one does not show
+const Samples = 5
+
+// Message types
+const (
+	// MessagePing asks the peer to echo the message back
+	MessagePing = "ping"
+
+	// MessagePong is the echo of a ping
+	MessagePong = "pong"
+)
+
+// ErrInvalidMessage is returned for messages that are not a valid probe
+var ErrInvalidMessage = errors.New("invalid probe message")
+
+// Message is one ping or its echo
+type Message struct {
+	Type string `json:"type"`
+
+	// Seq numbers the pings of a page, so late echoes can be told apart
+	Seq uint32 `json:"seq"`
+
+	// T is when the ping was sent, in milliseconds on the pinging page's clock
+	T float64 `json:"t"`
+}
+
+// Schema describes the messages and timing the sender and viewer pages use
+type Schema struct {
+	Channel        string   `json:"channel"`
+	Types          []string `json:"types"`
+	MaxMessageSize int      `json:"maxMessageSize"`
+	IntervalMillis int64    `json:"intervalMillis"`
+	Samples        int      `json:"samples"`
+}
+
+// DefaultSchema returns the schema of this package's messages
+func DefaultSchema() Schema {
+	return Schema{
+		Channel:        ChannelLabel,
+		Types:          []string{MessagePing, MessagePong},
+		MaxMessageSize: MaxMessageSize,
+		IntervalMillis: Interval.Milliseconds(),
+		Samples:        Samples,
+	}
+}
+
+// Parse decodes and validates one probe message
+func Parse(data []byte) (Message, error) {
+	var message Message
+	if len(data) > MaxMessageSize {
+		return message, fmt.Errorf("%w: message of %d bytes is too large", ErrInvalidMessage, len(data))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&message); err != nil {
+		return Message{}, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+	if err := message.Validate(); err != nil {
+		return Message{}, err
+	}
+	return message, nil
+}
+
+// Validate checks that the message is a ping or an echo with a send time
+func (m Message) Validate() error {
+	if m.Type != MessagePing && m.Type != MessagePong {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidMessage, m.Type)
+	}
+	if m.T < 0 {
+		return fmt.Errorf("%w: negative send time %.1f", ErrInvalidMessage, m.T)
+	}
+	return nil
+}
+
+// Echo returns the answer to a ping
+func (m Message) Echo() Message {
+	return Message{Type: MessagePong, Seq: m.Seq, T: m.T}
+}
@@ -0,0 +1,88 @@
+package probe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       string
+		expected      Message
+		expectedError bool
+	}{
+		{
+			name:     "ping",
+			message:  `{"type":"ping","seq":7,"t":81234.5}`,
+			expected: Message{Type: MessagePing, Seq: 7, T: 81234.5},
+		},
+		{
+			name:     "pong",
+			message:  `{"type":"pong","seq":7,"t":81234.5}`,
+			expected: Message{Type: MessagePong, Seq: 7, T: 81234.5},
+		},
+		{
+			name:          "unknown type",
+			message:       `{"type":"stroke","seq":1,"t":1}`,
+			expectedError: true,
+		},
+		{
+			name:          "negative sequence",
+			message:       `{"type":"ping","seq":-1,"t":1}`,
+			expectedError: true,
+		},
+		{
+			name:          "negative send time",
+			message:       `{"type":"ping","seq":1,"t":-5}`,
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			message:       `{"type":"ping","seq":1,"t":1,"x":0}`,
+			expectedError: true,
+		},
+		{
+			name:          "too large",
+			message:       `{"type":"ping"` + strings.Repeat(" ", MaxMessageSize) + `}`,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := Parse([]byte(tt.message))
+			if tt.expectedError {
+				if !errors.Is(err, ErrInvalidMessage) {
+					t.Errorf("Expected ErrInvalidMessage, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if message != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, message)
+			}
+		})
+	}
+}
+
+func TestMessage_Echo(t *testing.T) {
+	ping := Message{Type: MessagePing, Seq: 3, T: 1500.25}
+	expected := Message{Type: MessagePong, Seq: 3, T: 1500.25}
+	if echo := ping.Echo(); echo != expected {
+		t.Errorf("Expected %+v, got %+v", expected, echo)
+	}
+}
+
+func TestDefaultSchema(t *testing.T) {
+	schema := DefaultSchema()
+	if schema.Channel != ChannelLabel || schema.IntervalMillis != Interval.Milliseconds() || schema.Samples != Samples {
+		t.Errorf("Expected the package limits, got %+v", schema)
+	}
+	if len(schema.Types) != 2 || schema.Types[0] != MessagePing || schema.Types[1] != MessagePong {
+		t.Errorf("Expected ping and pong, got %v", schema.Types)
+	}
+}
@@ -0,0 +1,16 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// GetAuditLogRequest represents a query for a session's audit log
+type GetAuditLogRequest struct {
+	Token string `json:"token"`
+}
+
+// AuditLogResponse represents a session's audit log, oldest event first
+type AuditLogResponse struct {
+	Token  string                `json:"token"`
+	Events []entities.AuditEvent `json:"events"`
+}
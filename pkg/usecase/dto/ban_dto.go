@@ -0,0 +1,20 @@
+package dto
+
+import "share-screen/pkg/domain/entities"
+
+// AddBanRequest represents an admin banning an address or range
+type AddBanRequest struct {
+	// Network is a CIDR range or a single IP address
+	Network string `json:"network"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// RemoveBanRequest represents an admin lifting a ban
+type RemoveBanRequest struct {
+	Network string `json:"network"`
+}
+
+// BansResponse represents the ban list
+type BansResponse struct {
+	Bans []*entities.Ban `json:"bans"`
+}
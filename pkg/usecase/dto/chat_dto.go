@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SendChatMessageRequest represents a chat message relayed through the server
+type SendChatMessageRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+
+	// From is who wrote the message, "sender" or "viewer"
+	From string `json:"from"`
+	Text string `json:"text"`
+}
+
+// GetChatMessagesRequest represents the request for relayed chat messages
+type GetChatMessagesRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+
+	// Since is the ID of the last message already seen, 0 for all
+	Since int `json:"since,omitempty"`
+}
+
+// ChatMessagesResponse lists relayed chat messages, oldest first
+type ChatMessagesResponse struct {
+	Messages []entities.ChatMessage `json:"messages"`
+}
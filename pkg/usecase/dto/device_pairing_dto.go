@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+// RequestPairingRequest represents a viewer device asking to be trusted by
+// the sender of a session it can join
+type RequestPairingRequest struct {
+	Token string `json:"-"`
+	PIN   string `json:"pin,omitempty"`
+	Name  string `json:"name,omitempty"`
+
+	// DeviceID comes from the viewer's device cookie
+	DeviceID string `json:"-"`
+}
+
+// PairingCodeResponse represents the code a viewer device shows its sender,
+// who confirms it before ExpiresAt
+type PairingCodeResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ConfirmPairingRequest represents a sender confirming the code a viewer
+// device shows
+type ConfirmPairingRequest struct {
+	Token     string `json:"-"`
+	Code      string `json:"-"`
+	SenderKey string `json:"senderKey"`
+}
+
+// UnlockSessionRequest represents a trusted viewer device asking for the PIN
+// of one of its sender's sessions
+type UnlockSessionRequest struct {
+	Token string `json:"-"`
+
+	// DeviceID comes from the viewer's device cookie
+	DeviceID string `json:"-"`
+}
+
+// UnlockSessionResponse represents the PIN a trusted device joins with,
+// empty for sessions without one
+type UnlockSessionResponse struct {
+	PIN string `json:"pin"`
+}
+
+// ListTrustedDevicesRequest represents a sender listing the devices it trusts
+type ListTrustedDevicesRequest struct {
+	// Owner comes from the sender cookie
+	Owner string `json:"-"`
+}
+
+// ForgetTrustedDeviceRequest represents a sender withdrawing its trust in a
+// device
+type ForgetTrustedDeviceRequest struct {
+	ID string `json:"-"`
+
+	// Owner comes from the sender cookie
+	Owner string `json:"-"`
+}
+
+// TrustedDevice describes a paired viewer device to its sender
+type TrustedDevice struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	PairedAt   time.Time `json:"pairedAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+// TrustedDevicesResponse represents the devices a sender trusts, oldest
+// pairing first
+type TrustedDevicesResponse struct {
+	Devices []TrustedDevice `json:"devices"`
+}
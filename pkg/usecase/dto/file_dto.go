@@ -0,0 +1,30 @@
+package dto
+
+import "share-screen/pkg/domain/entities"
+
+// ShareFileRequest represents a file the sender relays through the server;
+// the content is the raw request body rather than JSON
+type ShareFileRequest struct {
+	Token       string `json:"token"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType,omitempty"`
+	Data        []byte `json:"-"`
+}
+
+// GetFilesRequest represents a request for the files relayed in a session
+type GetFilesRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+}
+
+// FilesResponse represents the files relayed in a session, oldest first
+type FilesResponse struct {
+	Files []*entities.SharedFile `json:"files"`
+}
+
+// GetFileRequest represents a download of one relayed file
+type GetFileRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+	ID    string `json:"id"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// GetFingerprintsRequest represents the request for the DTLS fingerprints of
+// a session's peers
+type GetFingerprintsRequest struct {
+	Token string `json:"token"`
+
+	// PIN unlocks a protected session for viewers; the sender may give its
+	// SenderKey instead
+	PIN       string `json:"pin,omitempty"`
+	SenderKey string `json:"senderKey,omitempty"`
+}
+
+// FingerprintsResponse represents the DTLS fingerprints announced in a
+// session's stored offer and answer, for the people at both ends to compare
+// with what their pages show
+type FingerprintsResponse struct {
+	// Sender is from the offer; Viewer from the answer, once a viewer
+	// answered
+	Sender *entities.DTLSFingerprint `json:"sender,omitempty"`
+	Viewer *entities.DTLSFingerprint `json:"viewer,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// WatchFramesRequest represents the request for snapshots of a session's
+// screen, e.g. from a TV browser showing an MJPEG stream
+type WatchFramesRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+}
+
+// SnapshotRequest represents the request for a still picture of a session's
+// screen, e.g. for a thumbnail
+type SnapshotRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+}
+
+// FrameStream delivers JPEG snapshots of a session's screen
+type FrameStream struct {
+	// Frames carries the snapshots and is closed when the stream ends
+	Frames <-chan []byte
+
+	// Stop ends the watch; it must be called once the watcher is gone
+	Stop func()
+}
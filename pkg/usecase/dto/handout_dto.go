@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+// GetHandoutRequest represents the request for a session's printable handout;
+// Scheme and Host describe how the sender reached the server
+type GetHandoutRequest struct {
+	Token  string `json:"token"`
+	Scheme string `json:"-"`
+	Host   string `json:"-"`
+}
+
+// HandoutResponse represents the joining details printed for a session
+type HandoutResponse struct {
+	Name        string    `json:"name,omitempty"`
+	ViewerURL   string    `json:"viewerUrl"`
+	QRCode      []byte    `json:"qrCode"`
+	ValidFrom   time.Time `json:"validFrom"`
+	ValidUntil  time.Time `json:"validUntil"`
+	PINRequired bool      `json:"pinRequired"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// Health states reported by the health endpoint
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+)
+
+// HealthResponse represents the server's health for load balancers and monitoring
+type HealthResponse struct {
+	Status  string                  `json:"status"`
+	Version string                  `json:"version,omitempty"`
+	Cleanup *entities.CleanupStatus `json:"cleanup,omitempty"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// GetSessionSummaryRequest represents the request for a finished session's summary
+type GetSessionSummaryRequest struct {
+	Token string `json:"token"`
+}
+
+// SessionSummaryResponse represents the summary shown to the sender after sharing
+type SessionSummaryResponse struct {
+	Token           string    `json:"token"`
+	Name            string    `json:"name,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	EndedAt         time.Time `json:"endedAt"`
+	DurationSeconds int64     `json:"durationSeconds"`
+	PeakViewers     int       `json:"peakViewers"`
+	AverageBitrate  int64     `json:"averageBitrate"`
+	RecordingURL    string    `json:"recordingUrl,omitempty"`
+	Notes           string    `json:"notes"`
+}
+
+// UpdateSessionNotesRequest represents the sender's notes for a finished session
+type UpdateSessionNotesRequest struct {
+	Token string `json:"token"`
+	Notes string `json:"notes"`
+
+	// SenderKey or Owner, the ID in the sender cookie, proves the request
+	// comes from the session's sender
+	SenderKey string `json:"senderKey"`
+	Owner     string `json:"-"`
+}
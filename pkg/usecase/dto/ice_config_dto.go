@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// GetICEConfigRequest represents a peer's request for the ICE servers of a session
+type GetICEConfigRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+}
+
+// ICEConfigResponse is an RTCConfiguration fragment: pages pass it to
+// RTCPeerConnection as is
+type ICEConfigResponse struct {
+	ICEServers []entities.ICEServer `json:"iceServers"`
+}
+
+// TURNCredentialsResponse is a short-lived TURN credential for the session's
+// relays. It also works as an RTCIceServer; TTL is the seconds left until
+// ExpiresAt.
+type TURNCredentialsResponse struct {
+	URLs       []string  `json:"urls"`
+	Username   string    `json:"username"`
+	Credential string    `json:"credential"`
+	TTL        int       `json:"ttl"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
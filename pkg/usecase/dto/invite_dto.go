@@ -0,0 +1,20 @@
+package dto
+
+import "time"
+
+// PostInviteRequest represents a sender posting its session's viewer link to
+// the team chat; Scheme and Host describe how the sender reached the server
+type PostInviteRequest struct {
+	Token     string `json:"-"`
+	SenderKey string `json:"senderKey"`
+	Scheme    string `json:"-"`
+	Host      string `json:"-"`
+}
+
+// PostInviteResponse represents an invite posted to the team chat
+type PostInviteResponse struct {
+	// Services are the chat services the invite reached, e.g. Slack
+	Services  []string  `json:"services"`
+	ViewerURL string    `json:"viewerUrl"`
+	PostedAt  time.Time `json:"postedAt"`
+}
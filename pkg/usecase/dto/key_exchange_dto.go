@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SendKeyMessageRequest represents a key exchange message relayed between
+// the sender and a viewer of an end-to-end encrypted session
+type SendKeyMessageRequest struct {
+	Token string `json:"token"`
+
+	// PIN unlocks a protected session for viewers; SenderKey proves the
+	// sender sent the message
+	PIN       string `json:"pin,omitempty"`
+	SenderKey string `json:"senderKey,omitempty"`
+
+	// Type is "public-key" from a viewer, or "content-key" or "rotate" from
+	// the sender
+	Type string `json:"type"`
+
+	// ViewerID is the viewer sending its public key or whom the content key
+	// is wrapped for
+	ViewerID string `json:"viewerId,omitempty"`
+
+	// KeyID names the content key wrapped or to rotate to
+	KeyID int `json:"keyId"`
+
+	// PublicKey, WrappedKey and IV are base64url without padding; see
+	// entities.KeyExchangeMessage
+	PublicKey  string `json:"publicKey,omitempty"`
+	WrappedKey string `json:"wrappedKey,omitempty"`
+	IV         string `json:"iv,omitempty"`
+}
+
+// GetKeyMessagesRequest represents the request for relayed key messages;
+// the sender gives its sender key and reads every message, a viewer its ID
+// and reads its own and the rotations
+type GetKeyMessagesRequest struct {
+	Token     string `json:"token"`
+	PIN       string `json:"pin,omitempty"`
+	SenderKey string `json:"senderKey,omitempty"`
+	ViewerID  string `json:"viewerId,omitempty"`
+
+	// Since is the sequence number of the last message already seen, 0 for all
+	Since int `json:"since,omitempty"`
+}
+
+// KeyMessagesResponse lists relayed key messages, oldest first
+type KeyMessagesResponse struct {
+	Messages []entities.KeyExchangeMessage `json:"messages"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// SendNegotiationMessageRequest represents a perfect negotiation message
+// relayed between the sender and the connected viewer
+type SendNegotiationMessageRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+
+	// From is who sends the message, "sender" or "viewer"
+	From string `json:"from"`
+
+	// ViewerID is the connected viewer whose connection is renegotiated
+	ViewerID string `json:"viewerId"`
+
+	// Description or Candidate is the message, never both
+	Description *entities.SessionDescription `json:"description,omitempty"`
+	Candidate   *entities.ICECandidate       `json:"candidate,omitempty"`
+}
+
+// GetNegotiationMessagesRequest represents the request for relayed
+// negotiation messages
+type GetNegotiationMessagesRequest struct {
+	Token    string `json:"token"`
+	PIN      string `json:"pin,omitempty"`
+	ViewerID string `json:"viewerId"`
+
+	// Since is the sequence number of the last message already seen, 0 for all
+	Since int `json:"since,omitempty"`
+}
+
+// NegotiationMessagesResponse lists relayed negotiation messages, oldest first
+type NegotiationMessagesResponse struct {
+	Messages []entities.NegotiationMessage `json:"messages"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// InterfacesResponse lists the server's network interfaces with usable addresses
+type InterfacesResponse struct {
+	Interfaces []entities.NetworkInterface `json:"interfaces"`
+
+	// Configured is the interface chosen in the server's configuration, empty
+	// when the address is picked automatically
+	Configured string `json:"configured,omitempty"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+import "share-screen/pkg/domain/entities"
+
+// PresetsResponse lists the quality presets a sender can start a session with
+type PresetsResponse struct {
+	Presets []entities.QualityPreset `json:"presets"`
+
+	// Default is the ID of the preset sender pages start with selected
+	Default string `json:"default"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import "share-screen/pkg/domain/entities"
+
+// JoinQueueRequest represents a viewer asking for a place in a full session
+type JoinQueueRequest struct {
+	Token string `json:"token"`
+	PIN   string `json:"pin,omitempty"`
+}
+
+// JoinQueueResponse represents a viewer's place in the queue; a position of 0
+// means the slot was free and has been reserved for the viewer
+type JoinQueueResponse struct {
+	ViewerID string `json:"viewerId"`
+	Position int    `json:"position"`
+}
+
+// QueueViewerRequest identifies a queued viewer in a session
+type QueueViewerRequest struct {
+	Token    string `json:"token"`
+	ViewerID string `json:"viewerId"`
+}
+
+// GetQueueRequest represents the request for listing a session's queue
+type GetQueueRequest struct {
+	Token string `json:"token"`
+}
+
+// GetQueueResponse represents the viewers waiting in a session's queue
+type GetQueueResponse struct {
+	Viewers     []entities.QueuedViewer `json:"viewers"`
+	ReservedFor string                  `json:"reservedFor,omitempty"`
+}
+
+// ReleaseViewerRequest represents the connected viewer leaving a session
+type ReleaseViewerRequest struct {
+	Token string `json:"token"`
+}
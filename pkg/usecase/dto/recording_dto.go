@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"io"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// GetRecordingRequest represents an admin downloading a recording
+type GetRecordingRequest struct {
+	Name string `json:"name"`
+}
+
+// DeleteRecordingRequest represents an admin deleting a recording
+type DeleteRecordingRequest struct {
+	Name string `json:"name"`
+}
+
+// RecordingsResponse represents the recordings kept on the server and the
+// limits the janitor keeps them within
+type RecordingsResponse struct {
+	Recordings []*entities.Recording `json:"recordings"`
+	TotalBytes int64                 `json:"totalBytes"`
+
+	// MaxBytes and RetentionSeconds are 0 when there is no such limit
+	MaxBytes         int64 `json:"maxBytes"`
+	RetentionSeconds int64 `json:"retentionSeconds"`
+}
+
+// RecordingDownload is a recording and its content, which must be closed
+type RecordingDownload struct {
+	Recording *entities.Recording
+	Content   io.ReadSeekCloser
+}
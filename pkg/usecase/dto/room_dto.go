@@ -0,0 +1,31 @@
+package dto
+
+// ClaimRoomRequest points a named room at one of the sender's sessions
+type ClaimRoomRequest struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+
+	// Key is the room key handed out when the room was first claimed; leave
+	// it empty to claim a new room
+	Key string `json:"key,omitempty"`
+}
+
+// ClaimRoomResponse represents a claimed room and the key that keeps it
+type ClaimRoomResponse struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	Path string `json:"path"`
+}
+
+// GetRoomRequest represents the request for looking up a named room
+type GetRoomRequest struct {
+	Name string `json:"name"`
+}
+
+// RoomResponse tells viewers which session a room currently leads to; the
+// token is only set while that session is live
+type RoomResponse struct {
+	Name  string `json:"name"`
+	Live  bool   `json:"live"`
+	Token string `json:"token,omitempty"`
+}
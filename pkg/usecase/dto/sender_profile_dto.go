@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// GetSenderProfileRequest represents a sender page asking for its defaults
+type GetSenderProfileRequest struct {
+	// ProfileID comes from the signed profile cookie
+	ProfileID string `json:"-"`
+}
+
+// UpdateSenderProfileRequest represents a sender saving its defaults
+type UpdateSenderProfileRequest struct {
+	// ProfileID comes from the signed profile cookie
+	ProfileID string `json:"-"`
+
+	Preset    string `json:"preset,omitempty"`
+	Audio     bool   `json:"audio"`
+	Interface string `json:"interface,omitempty"`
+	Theme     string `json:"theme,omitempty"`
+}
+
+// DeleteSenderProfileRequest represents a sender going back to the server's
+// defaults
+type DeleteSenderProfileRequest struct {
+	// ProfileID comes from the signed profile cookie
+	ProfileID string `json:"-"`
+}
+
+// SenderProfileResponse represents a sender's defaults; UpdatedAt is zero
+// while the sender has saved none
+type SenderProfileResponse struct {
+	Preset    string    `json:"preset,omitempty"`
+	Audio     bool      `json:"audio"`
+	Interface string    `json:"interface,omitempty"`
+	Theme     string    `json:"theme,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
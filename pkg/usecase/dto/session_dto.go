@@ -1,40 +1,389 @@
 package dto
 
-import "share-screen/pkg/domain/entities"
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// CreateSessionRequest represents the request for creating a new session
+type CreateSessionRequest struct {
+	Name           string `json:"name,omitempty"`
+	DisablePreview bool   `json:"disablePreview,omitempty"`
+
+	// RequirePIN protects the session with a generated PIN viewers must enter
+	RequirePIN bool `json:"requirePin,omitempty"`
+
+	// ReusableLink lets more than one device use the viewer link; by default
+	// it stops working for others once the first viewer connects
+	ReusableLink bool `json:"reusableLink,omitempty"`
+
+	// Chat lets the sender and viewers exchange chat messages
+	Chat bool `json:"chat,omitempty"`
+
+	// SFU streams through the server to any number of viewers, on servers
+	// running the SFU; the viewer link is always reusable then
+	SFU bool `json:"sfu,omitempty"`
+
+	// MaxBitrateKbps caps the video bitrate; 0 keeps the server default
+	MaxBitrateKbps int `json:"maxBitrateKbps,omitempty"`
+
+	// MaxFrameRate caps the frames per second the sender captures and sends,
+	// up to 60; 0 keeps the server default
+	MaxFrameRate int `json:"maxFrameRate,omitempty"`
+
+	// VideoCodec is h264, vp8 or vp9 to prefer that codec, and ForceCodec
+	// drops the others; an empty codec keeps the server default
+	VideoCodec string `json:"videoCodec,omitempty"`
+	ForceCodec bool   `json:"forceCodec,omitempty"`
+
+	// Preset is the ID of a quality preset from /presets; its bitrate cap
+	// applies unless MaxBitrateKbps is set
+	Preset string `json:"preset,omitempty"`
+
+	// Audio shares the screen's sound along with its picture
+	Audio bool `json:"audio,omitempty"`
+
+	// E2EE encrypts the media end to end with content keys the peers
+	// exchange through /sessions/{token}/keys, so not even the SFU can see
+	// the screen
+	E2EE bool `json:"e2ee,omitempty"`
+
+	// Template is the name of a session template from /templates; its
+	// options take precedence over those above
+	Template string `json:"template,omitempty"`
+
+	// ClientIP is the sender's address, for the audit log
+	ClientIP string `json:"-"`
+
+	// Owner is the sender ID from the browser's sender cookie, if any
+	Owner string `json:"-"`
+
+	// User is who the request's API token was issued to, if any
+	User *entities.UserIdentity `json:"-"`
+}
 
 // CreateSessionResponse represents the response for creating a new session
 type CreateSessionResponse struct {
-	Token string `json:"token"`
+	Token     string `json:"token"`
+	PIN       string `json:"pin,omitempty"`
+	SingleUse bool   `json:"singleUse,omitempty"`
+	Chat      bool   `json:"chat,omitempty"`
+	SFU       bool   `json:"sfu,omitempty"`
+
+	// MaxBitrateKbps is the bitrate cap in effect, if any
+	MaxBitrateKbps int `json:"maxBitrateKbps,omitempty"`
+
+	// MaxFrameRate is the frame rate cap in effect, if any
+	MaxFrameRate int `json:"maxFrameRate,omitempty"`
+
+	// VideoCodec and ForceCodec are the codec preference in effect, if any
+	VideoCodec string `json:"videoCodec,omitempty"`
+	ForceCodec bool   `json:"forceCodec,omitempty"`
+
+	// Preset is the chosen quality preset, whose capture limits the sender
+	// applies
+	Preset *entities.QualityPreset `json:"preset,omitempty"`
+
+	// SenderKey lets the sender page resume the session after a reload; it
+	// must stay with the sender
+	SenderKey string `json:"senderKey,omitempty"`
+
+	// Paused says the sender paused the stream, so a resumed page keeps it hidden
+	Paused bool `json:"paused,omitempty"`
+
+	// ViewerPath is a short path viewers can also open the session at, such
+	// as /v/BlueTiger42, easy to read out over the phone
+	ViewerPath string `json:"viewerPath,omitempty"`
+
+	// Audio says the sender should share the screen's sound
+	Audio bool `json:"audio,omitempty"`
+
+	// E2EE says the sender encrypts the media and hands out the keys
+	E2EE bool `json:"e2ee,omitempty"`
+
+	// MaxViewers is the viewer limit in effect, if any, and Template the
+	// session template the session started from
+	MaxViewers int    `json:"maxViewers,omitempty"`
+	Template   string `json:"template,omitempty"`
 }
 
 // SubmitOfferRequest represents the request for submitting a WebRTC offer
 type SubmitOfferRequest struct {
-	Token string                `json:"token"`
-	Offer *entities.WebRTCOffer `json:"sdp"`
+	Token    string                `json:"token"`
+	Offer    *entities.WebRTCOffer `json:"sdp"`
+	ClientIP string                `json:"-"`
+
+	// SenderKey is needed to replace an offer already posted or restart ICE
+	SenderKey string `json:"senderKey,omitempty"`
+
+	// ICERestart marks an offer with new ICE credentials on the connection
+	// to the connected viewer, which answers it without reconnecting
+	ICERestart bool `json:"iceRestart,omitempty"`
+}
+
+// ResetOfferRequest represents the request for clearing a session's offer
+type ResetOfferRequest struct {
+	Token     string `json:"token"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
 }
 
 // GetOfferRequest represents the request for getting a WebRTC offer
 type GetOfferRequest struct {
-	Token string `json:"token"`
+	Token    string `json:"token"`
+	ViewerID string `json:"viewerId,omitempty"`
+	PIN      string `json:"pin,omitempty"`
+	ClientIP string `json:"-"`
 }
 
 // GetOfferResponse represents the response for getting a WebRTC offer
 type GetOfferResponse struct {
 	Offer *entities.WebRTCOffer `json:"offer"`
+
+	// Paused says the sender paused the stream before the viewer joined
+	Paused bool `json:"paused,omitempty"`
+
+	// ICEGeneration counts the ICE restarts of the offer; the answer to it
+	// carries the same count
+	ICEGeneration int `json:"iceGeneration,omitempty"`
+
+	// E2EE says the media is encrypted end to end, so the viewer asks the
+	// sender for the content key before it can show the screen
+	E2EE bool `json:"e2ee,omitempty"`
 }
 
 // SubmitAnswerRequest represents the request for submitting a WebRTC answer
 type SubmitAnswerRequest struct {
-	Token  string                 `json:"token"`
-	Answer *entities.WebRTCAnswer `json:"sdp"`
+	Token    string                 `json:"token"`
+	Answer   *entities.WebRTCAnswer `json:"sdp"`
+	ViewerID string                 `json:"viewerId,omitempty"`
+	PIN      string                 `json:"pin,omitempty"`
+	ClientIP string                 `json:"-"`
+
+	// Name is what the viewer calls itself, e.g. "Ari's iPhone", shown to
+	// the sender; at most 40 characters
+	Name string `json:"name,omitempty"`
+
+	// ICEGeneration is the ICE generation of the offer answered
+	ICEGeneration int `json:"iceGeneration,omitempty"`
 }
 
 // GetAnswerRequest represents the request for getting a WebRTC answer
 type GetAnswerRequest struct {
-	Token string `json:"token"`
+	Token    string `json:"token"`
+	ClientIP string `json:"-"`
 }
 
 // GetAnswerResponse represents the response for getting a WebRTC answer
 type GetAnswerResponse struct {
 	Answer *entities.WebRTCAnswer `json:"answer"`
+
+	// ViewerID is the viewer that answered, so the sender can remove it
+	ViewerID string `json:"viewerId,omitempty"`
+
+	// ViewerName is the name the viewer gave, if any
+	ViewerName string `json:"viewerName,omitempty"`
+}
+
+// GetLinkPreviewRequest represents the request for viewer link preview details
+type GetLinkPreviewRequest struct {
+	Token string `json:"token"`
+}
+
+// LinkPreviewResponse represents the public details shown in a viewer link preview
+type LinkPreviewResponse struct {
+	Name    string `json:"name,omitempty"`
+	Enabled bool   `json:"enabled"`
+
+	// Thumbnail says the preview may show the screen: the server decodes
+	// SFU sessions' video, and the session needs no PIN to watch
+	Thumbnail bool `json:"thumbnail,omitempty"`
+}
+
+// ResolveViewerAliasRequest represents a viewer opening a session by its alias
+type ResolveViewerAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+// ResolveViewerAliasResponse represents the session a viewer alias names
+type ResolveViewerAliasResponse struct {
+	Token string `json:"token"`
+}
+
+// HeartbeatRequest represents a sender heartbeat for a session
+type HeartbeatRequest struct {
+	Token string `json:"token"`
+
+	// BytesSent is the sender's running total of media bytes sent
+	BytesSent int64 `json:"bytesSent,omitempty"`
+}
+
+// ExtendSessionRequest represents the sender pushing back its session's expiry
+type ExtendSessionRequest struct {
+	Token     string `json:"token"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
+}
+
+// ExtendSessionResponse represents the session's new expiry
+type ExtendSessionResponse struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// ExpiresInSeconds is the time left, so clients need not trust their clock
+	ExpiresInSeconds int `json:"expiresInSeconds"`
+}
+
+// PauseSessionRequest represents the sender pausing or resuming its stream
+type PauseSessionRequest struct {
+	Token     string `json:"token"`
+	Paused    bool   `json:"paused"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
+}
+
+// GetSessionRequest represents the request for a session's details
+type GetSessionRequest struct {
+	Token string `json:"token"`
+}
+
+// SessionDetailResponse describes where a session stands, so its peers can
+// show its progress without polling the offer and answer and reading their
+// 404s. It holds nothing that would let someone with only the token take
+// the session over, such as its PIN or sender key.
+type SessionDetailResponse struct {
+	Token string `json:"token"`
+
+	// Name is left out when the sender hid it from link previews
+	Name string `json:"name,omitempty"`
+
+	// Status is the session's status; a session whose token ran out is
+	// reported expired even before it is cleaned up
+	Status entities.SessionStatus `json:"status"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// ExpiresInSeconds is the time left, so clients need not trust their clock
+	ExpiresInSeconds int `json:"expiresInSeconds"`
+
+	// OfferedAt, ConnectedAt and EndedAt are when the first offer arrived,
+	// the first viewer connected and the session ended, if it did
+	OfferedAt   *time.Time `json:"offeredAt,omitempty"`
+	ConnectedAt *time.Time `json:"connectedAt,omitempty"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+
+	// HasOffer and HasAnswer say which halves of the handshake are there
+	HasOffer  bool `json:"hasOffer"`
+	HasAnswer bool `json:"hasAnswer"`
+
+	// ICEGeneration counts the ICE restarts of the current offer
+	ICEGeneration int `json:"iceGeneration,omitempty"`
+
+	// Viewers counts the connected viewers and Queued those waiting
+	Viewers int `json:"viewers"`
+	Queued  int `json:"queued"`
+
+	Paused      bool `json:"paused,omitempty"`
+	SFU         bool `json:"sfu,omitempty"`
+	RequiresPIN bool `json:"requiresPin,omitempty"`
+	SingleUse   bool `json:"singleUse,omitempty"`
+
+	// LinkUsed says a single-use viewer link was taken by a viewer
+	LinkUsed bool `json:"linkUsed,omitempty"`
+}
+
+// ListOwnSessionsRequest represents a sender asking for its own live sessions
+type ListOwnSessionsRequest struct {
+	Owner string `json:"-"`
+}
+
+// OwnSessionsResponse represents a sender's live sessions, oldest first
+type OwnSessionsResponse struct {
+	Sessions []OwnSession `json:"sessions"`
+}
+
+// OwnSession represents one of a sender's live sessions on the landing page
+type OwnSession struct {
+	Token     string                 `json:"token"`
+	Name      string                 `json:"name,omitempty"`
+	Status    entities.SessionStatus `json:"status"`
+	CreatedAt time.Time              `json:"createdAt"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+	PIN       string                 `json:"pin,omitempty"`
+	SFU       bool                   `json:"sfu,omitempty"`
+	Paused    bool                   `json:"paused,omitempty"`
+	Viewers   int                    `json:"viewers"`
+}
+
+// RenameSessionRequest represents a sender renaming one of its sessions
+type RenameSessionRequest struct {
+	Token string `json:"-"`
+	Name  string `json:"name"`
+	Owner string `json:"-"`
+}
+
+// EndSessionRequest represents the request for ending a session
+type EndSessionRequest struct {
+	Token    string `json:"token"`
+	ClientIP string `json:"-"`
+
+	// SenderKey proves the request comes from the session's sender; viewers
+	// have the token but not the key
+	SenderKey string `json:"senderKey"`
+
+	// Ingest says a WHIP encoder is deleting its ingest resource; the
+	// protocol gives it the token alone, so it needs no sender key
+	Ingest bool `json:"-"`
+
+	// Resumable says the sender page may come back, e.g. after a reload, so
+	// the session waits entities.SenderResumeWindow for it before it ends
+	Resumable bool `json:"-"`
+}
+
+// ResumeSessionRequest represents a reloaded sender page reattaching to its session
+type ResumeSessionRequest struct {
+	Token     string `json:"token"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
+}
+
+// KickViewerRequest represents the sender or an admin removing a viewer from
+// a session
+type KickViewerRequest struct {
+	Token     string `json:"token"`
+	ViewerID  string `json:"viewerId"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
+
+	// Admin says the admin API already authorized the request, which then
+	// needs no sender key
+	Admin bool `json:"-"`
+}
+
+// PublishStreamRequest represents the sender of an SFU session publishing its stream
+type PublishStreamRequest struct {
+	Token    string                `json:"token"`
+	Offer    *entities.WebRTCOffer `json:"sdp"`
+	ClientIP string                `json:"-"`
+
+	// SenderKey is needed to replace a stream already published
+	SenderKey string `json:"senderKey,omitempty"`
+
+	// Ingest says a WHIP encoder is publishing; the protocol gives it the
+	// token alone, so it needs no sender key
+	Ingest bool `json:"-"`
+}
+
+// PublishStreamResponse represents the SFU's answer to the sender's offer
+type PublishStreamResponse struct {
+	Answer *entities.WebRTCAnswer `json:"answer"`
+}
+
+// SelectLayerRequest represents a viewer of an SFU session picking the
+// simulcast layer it receives
+type SelectLayerRequest struct {
+	Token    string                  `json:"token"`
+	ViewerID string                  `json:"viewerId"`
+	Layer    entities.SimulcastLayer `json:"layer"`
 }
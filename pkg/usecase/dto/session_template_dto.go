@@ -0,0 +1,27 @@
+package dto
+
+import "share-screen/pkg/domain/entities"
+
+// SaveSessionTemplateRequest represents an admin defining or redefining a
+// session template
+type SaveSessionTemplateRequest struct {
+	// Name comes from the request path
+	Name string `json:"-"`
+
+	Preset        string `json:"preset,omitempty"`
+	Audio         bool   `json:"audio"`
+	RequirePIN    bool   `json:"requirePin"`
+	MaxViewers    int    `json:"maxViewers,omitempty"`
+	ExpirySeconds int    `json:"expirySeconds,omitempty"`
+}
+
+// DeleteSessionTemplateRequest represents an admin removing a session template
+type DeleteSessionTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+// SessionTemplatesResponse lists the session templates a sender can start a
+// session from
+type SessionTemplatesResponse struct {
+	Templates []*entities.SessionTemplate `json:"templates"`
+}
@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// RecordStatsRequest represents a peer uploading a summary of its WebRTC stats
+type RecordStatsRequest struct {
+	Token      string  `json:"token"`
+	Role       string  `json:"role"`
+	ViewerID   string  `json:"viewerId,omitempty"`
+	Bitrate    int64   `json:"bitrate"`
+	FrameRate  float64 `json:"fps"`
+	PacketLoss float64 `json:"packetLoss"`
+	RTTMillis  float64 `json:"rttMs"`
+}
+
+// RecordLatencyRequest represents a page reporting the round-trip time it
+// measured with the latency probe
+type RecordLatencyRequest struct {
+	Token     string  `json:"token"`
+	Role      string  `json:"role"`
+	ViewerID  string  `json:"viewerId,omitempty"`
+	Path      string  `json:"path"`
+	RTTMillis float64 `json:"rttMs"`
+	Network   string  `json:"network,omitempty"`
+}
+
+// PingResponse represents the answer of the ping endpoint pages time for the
+// latency probe, with the network the client reached the server over when
+// the server can tell
+type PingResponse struct {
+	Network string `json:"network,omitempty"`
+}
+
+// GetStatsRequest represents a query for a session's stats taken after Since
+type GetStatsRequest struct {
+	Token string    `json:"token"`
+	Since time.Time `json:"since"`
+}
+
+// SessionStatsResponse represents a session's stats time series, oldest
+// first, and the latest latency probe result of each peer and path
+type SessionStatsResponse struct {
+	Token   string                   `json:"token"`
+	Samples []entities.StatsSample   `json:"samples"`
+	Latency []entities.LatencySample `json:"latency"`
+}
+
+// GetViewerStatsRequest represents the sender asking how its viewers'
+// connections are doing
+type GetViewerStatsRequest struct {
+	Token     string `json:"token"`
+	SenderKey string `json:"senderKey"`
+}
+
+// ViewerStatsResponse represents the viewers still uploading stats, with
+// their samples of the last WindowSeconds summed up
+type ViewerStatsResponse struct {
+	WindowSeconds int           `json:"windowSeconds"`
+	Viewers       []ViewerStats `json:"viewers"`
+}
+
+// ViewerStats represents one viewer's connection: its latest sample, the
+// averages over the window, a rating from those averages (good, fair or
+// poor) and the viewer's latest latency probe results
+type ViewerStats struct {
+	ViewerID string                   `json:"viewerId"`
+	Name     string                   `json:"name,omitempty"`
+	Quality  string                   `json:"quality"`
+	Latest   entities.StatsSample     `json:"latest"`
+	Samples  int                      `json:"samples"`
+	Latency  []entities.LatencySample `json:"latency,omitempty"`
+
+	AverageBitrate    int64   `json:"averageBitrate"`
+	AverageFrameRate  float64 `json:"averageFps"`
+	AveragePacketLoss float64 `json:"averagePacketLoss"`
+	AverageRTTMillis  float64 `json:"averageRttMs"`
+}
+
+// StatsSummaryResponse represents the session totals of the day Date, in
+// the server's local time, and the sessions live now
+type StatsSummaryResponse struct {
+	Date      string `json:"date"`
+	Created   int    `json:"created"`
+	Completed int    `json:"completed"`
+	Expired   int    `json:"expired"`
+	Active    int    `json:"active"`
+
+	// AverageDurationSeconds covers the sessions that ended during the day
+	AverageDurationSeconds float64 `json:"averageDurationSeconds"`
+
+	// PeakConcurrent is the most sessions live at once during the day
+	PeakConcurrent int `json:"peakConcurrent"`
+}
+
+// AdminSessionsResponse represents the live sessions shown on the admin dashboard
+type AdminSessionsResponse struct {
+	Sessions []AdminSession `json:"sessions"`
+}
+
+// AdminSession represents one live session on the admin dashboard with the
+// latest stats of each of its peers
+type AdminSession struct {
+	Token     string                 `json:"token"`
+	Name      string                 `json:"name,omitempty"`
+	Status    entities.SessionStatus `json:"status"`
+	CreatedAt time.Time              `json:"createdAt"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+	SFU       bool                   `json:"sfu,omitempty"`
+	Viewers   int                    `json:"viewers"`
+	Queued    int                    `json:"queued"`
+	Latest    []entities.StatsSample `json:"latest"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+// ViewerStatusResponse summarizes who is watching the server's live sessions,
+// sized for widgets, watch complications and menu bar items
+type ViewerStatusResponse struct {
+	Viewing  bool `json:"viewing"`
+	Viewers  int  `json:"viewers"`
+	Queued   int  `json:"queued"`
+	Sessions int  `json:"sessions"`
+}
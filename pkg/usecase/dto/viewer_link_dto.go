@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// CreateViewerLinkRequest represents the sender handing out a viewer link
+// that expires after TTLSeconds and, when MaxRedemptions is set, after that
+// many viewers opened it
+type CreateViewerLinkRequest struct {
+	Token          string `json:"-"`
+	SenderKey      string `json:"senderKey"`
+	TTLSeconds     int    `json:"ttlSeconds"`
+	MaxRedemptions int    `json:"maxRedemptions,omitempty"`
+	ClientIP       string `json:"-"`
+}
+
+// ListViewerLinksRequest represents the sender listing its viewer links
+type ListViewerLinksRequest struct {
+	Token     string `json:"-"`
+	SenderKey string `json:"-"`
+}
+
+// RevokeViewerLinkRequest represents the sender withdrawing a viewer link
+// before it expires
+type RevokeViewerLinkRequest struct {
+	Token     string `json:"-"`
+	ID        string `json:"-"`
+	SenderKey string `json:"senderKey"`
+	ClientIP  string `json:"-"`
+}
+
+// RedeemViewerLinkRequest represents a viewer opening a viewer link
+type RedeemViewerLinkRequest struct {
+	ID       string `json:"-"`
+	ViewerID string `json:"viewerId"`
+	ClientIP string `json:"-"`
+}
+
+// ViewerLink describes a viewer link to its sender; Path is where viewers
+// open it, and Usable says whether they still can
+type ViewerLink struct {
+	ID             string    `json:"id"`
+	Path           string    `json:"path"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	MaxRedemptions int       `json:"maxRedemptions"`
+	Redemptions    int       `json:"redemptions"`
+	Usable         bool      `json:"usable"`
+}
+
+// ViewerLinksResponse lists a session's viewer links, oldest first
+type ViewerLinksResponse struct {
+	Links []ViewerLink `json:"links"`
+}
+
+// RedeemViewerLinkResponse tells a viewer which session its link leads to
+type RedeemViewerLinkResponse struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// GetViewerSettingsRequest represents the request for a device's viewer settings
+type GetViewerSettingsRequest struct {
+	DeviceID string `json:"-"`
+}
+
+// UpdateViewerSettingsRequest represents a change to a device's viewer settings
+type UpdateViewerSettingsRequest struct {
+	DeviceID string `json:"-"`
+	LowPower bool   `json:"lowPower"`
+}
+
+// ViewerSettingsResponse represents a device's viewer settings
+type ViewerSettingsResponse struct {
+	LowPower     bool `json:"lowPower"`
+	MaxFrameRate int  `json:"maxFrameRate"`
+}
+
+// QualityRequest represents a viewer asking the sender to adjust the stream;
+// a MaxFrameRate of 0 removes any cap
+type QualityRequest struct {
+	Token        string `json:"token"`
+	MaxFrameRate int    `json:"maxFrameRate"`
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+const (
+	// adaptationWindow is how long packet loss or a low frame rate must last
+	// to count, and how long the stats get to show the last step's effect
+	// before the next one: four samples at the pages' five second interval
+	adaptationWindow = 20 * time.Second
+
+	// minAdaptationSamples is how many of the viewer's samples the window
+	// needs, so a single report does not move the sender
+	minAdaptationSamples = 3
+
+	// adaptationRecoverAfter is how long a session stays on a step before it
+	// may move back up, so constraints do not flap with a bursty connection
+	adaptationRecoverAfter = time.Minute
+)
+
+// adaptationState is where a session's connected viewer stands on the
+// adaptation ladder
+type adaptationState struct {
+	viewerID  string
+	level     int
+	changedAt time.Time
+}
+
+// AdaptationUseCase suggests constraints to the senders of peer-to-peer
+// sessions from the stats their viewers upload, stepping them down when the
+// connection suffers and back up once it recovers
+type AdaptationUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	statsRepo   interfaces.StatsRepository
+	publisher   interfaces.EventPublisher
+
+	mu     sync.Mutex
+	states map[string]*adaptationState
+}
+
+// NewAdaptationUseCase creates a new adaptation use case
+func NewAdaptationUseCase(sessionRepo interfaces.SessionRepository, statsRepo interfaces.StatsRepository, publisher interfaces.EventPublisher) *AdaptationUseCase {
+	return &AdaptationUseCase{
+		sessionRepo: sessionRepo,
+		statsRepo:   statsRepo,
+		publisher:   publisher,
+		states:      make(map[string]*adaptationState),
+	}
+}
+
+// CheckAdaptation runs the adaptation policy on every live peer-to-peer
+// session with a viewer and publishes a suggestion to the senders whose
+// constraints change. Sessions through the SFU are left out: their viewers
+// each get the simulcast layer they cope with, and one viewer must not slow
+// down the stream the others share.
+func (uc *AdaptationUseCase) CheckAdaptation() error {
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := time.Now()
+	watched := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		if session.SFU || session.Paused || session.ViewerID == "" {
+			continue
+		}
+		watched[session.Token] = true
+
+		// A new viewer starts from the sender's own settings
+		state := uc.states[session.Token]
+		if state == nil || state.viewerID != session.ViewerID {
+			state = &adaptationState{viewerID: session.ViewerID, changedAt: now}
+			uc.states[session.Token] = state
+		}
+		if now.Sub(state.changedAt) < adaptationWindow {
+			continue
+		}
+
+		samples, err := uc.statsRepo.ListStats(session.Token, now.Add(-adaptationWindow))
+		if err != nil {
+			return err
+		}
+		summary, ok := summarizeConnection(samples, session.ViewerID)
+		if !ok {
+			continue
+		}
+
+		level, reason := entities.SuggestConstraints(state.level, summary, now.Sub(state.changedAt) >= adaptationRecoverAfter)
+		if reason == "" {
+			continue
+		}
+		state.level = level
+		state.changedAt = now
+
+		suggestion := entities.ConstraintSuggestion{
+			Constraints: entities.ConstraintsAt(level),
+			Reason:      reason,
+			Message:     adaptationMessage(reason, summary, entities.ConstraintsAt(level)),
+		}
+		log.Printf("🎚️  %s for token: %s", suggestion.Message, entities.ShortToken(session.Token))
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{
+			Type: entities.EventConstraints,
+			Data: suggestion,
+		})
+	}
+
+	for token := range uc.states {
+		if !watched[token] {
+			delete(uc.states, token)
+		}
+	}
+	return nil
+}
+
+// summarizeConnection averages the connected viewer's samples and the
+// sender's over the window; it reports false with too few of the viewer's
+func summarizeConnection(samples []entities.StatsSample, viewerID string) (entities.ConnectionSummary, bool) {
+	var summary entities.ConnectionSummary
+	viewerSamples, senderSamples := 0, 0
+	for _, sample := range samples {
+		switch {
+		case sample.Role == entities.StatsRoleViewer && sample.ViewerID == viewerID:
+			summary.PacketLoss += sample.PacketLoss
+			summary.ViewerFrameRate += sample.FrameRate
+			viewerSamples++
+		case sample.Role == entities.StatsRoleSender:
+			summary.SenderFrameRate += sample.FrameRate
+			senderSamples++
+		}
+	}
+	if viewerSamples < minAdaptationSamples {
+		return entities.ConnectionSummary{}, false
+	}
+
+	summary.PacketLoss /= float64(viewerSamples)
+	summary.ViewerFrameRate /= float64(viewerSamples)
+	if senderSamples > 0 {
+		summary.SenderFrameRate /= float64(senderSamples)
+	}
+	return summary, true
+}
+
+// adaptationMessage tells the sender why the server suggests constraints
+func adaptationMessage(reason string, summary entities.ConnectionSummary, constraints entities.Constraints) string {
+	switch reason {
+	case entities.AdaptationPacketLoss:
+		return fmt.Sprintf("Viewer losing %.0f%% of packets, suggesting %s", summary.PacketLoss*100, describeConstraints(constraints))
+	case entities.AdaptationLowFPS:
+		return fmt.Sprintf("Viewer showing %.0f of %.0f fps, suggesting %s", summary.ViewerFrameRate, summary.SenderFrameRate, describeConstraints(constraints))
+	default:
+		return fmt.Sprintf("Viewer connection recovered, suggesting %s", describeConstraints(constraints))
+	}
+}
+
+// describeConstraints words constraints for the sender, e.g. "half
+// resolution at 15 fps"
+func describeConstraints(constraints entities.Constraints) string {
+	switch {
+	case constraints.Level == 0:
+		return "full quality"
+	case constraints.ScaleResolutionDownBy == 2:
+		return fmt.Sprintf("half resolution at %d fps", constraints.MaxFrameRate)
+	case constraints.ScaleResolutionDownBy > 1:
+		return fmt.Sprintf("1/%g resolution at %d fps", constraints.ScaleResolutionDownBy, constraints.MaxFrameRate)
+	default:
+		return fmt.Sprintf("%d fps", constraints.MaxFrameRate)
+	}
+}
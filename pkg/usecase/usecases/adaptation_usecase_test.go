@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+// constraintSuggestions returns the suggestions published to a session
+func constraintSuggestions(publisher *mocks.MockEventPublisher, token string) []entities.ConstraintSuggestion {
+	var suggestions []entities.ConstraintSuggestion
+	for _, event := range publisher.Published(entities.SessionTopic(token)) {
+		if event.Type == entities.EventConstraints {
+			suggestions = append(suggestions, event.Data.(entities.ConstraintSuggestion))
+		}
+	}
+	return suggestions
+}
+
+// appendAdaptationStats uploads three viewer samples with the packet loss and
+// frame rate, and the sender's at the same frame rate, over the last
+// adaptation window
+func appendAdaptationStats(statsRepo *mocks.MockStatsRepository, token, viewerID string, packetLoss, frameRate float64) {
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		at := now.Add(-time.Duration(i) * 5 * time.Second)
+		_ = statsRepo.AppendStats(token, &entities.StatsSample{At: at, Role: entities.StatsRoleViewer, ViewerID: viewerID, PacketLoss: packetLoss, FrameRate: frameRate})
+		_ = statsRepo.AppendStats(token, &entities.StatsSample{At: at, Role: entities.StatsRoleSender, FrameRate: frameRate})
+	}
+}
+
+func TestAdaptationUseCase_CheckAdaptation(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	statsRepo := mocks.NewMockStatsRepository()
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewAdaptationUseCase(sessionRepo, statsRepo, publisher)
+
+	session := mocks.NewTestSession("test-token", true)
+	session.ViewerID = "viewer-1"
+	sessionRepo.SetSession(session)
+	appendAdaptationStats(statsRepo, session.Token, "viewer-1", 0.08, 30)
+
+	// backdate pretends the session has been on its step for a while
+	backdate := func(d time.Duration) {
+		useCase.states[session.Token].changedAt = time.Now().Add(-d)
+	}
+
+	if err := useCase.CheckAdaptation(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if suggestions := constraintSuggestions(publisher, session.Token); len(suggestions) != 0 {
+		t.Fatalf("Expected a new viewer to get a window first, got %+v", suggestions)
+	}
+
+	backdate(adaptationWindow)
+	_ = useCase.CheckAdaptation()
+	suggestions := constraintSuggestions(publisher, session.Token)
+	if len(suggestions) != 1 {
+		t.Fatalf("Expected one suggestion after sustained loss, got %+v", suggestions)
+	}
+	if s := suggestions[0]; s.Level != 1 || s.MaxFrameRate != 15 || s.Reason != entities.AdaptationPacketLoss || s.Message != "Viewer losing 8% of packets, suggesting 15 fps" {
+		t.Errorf("Unexpected suggestion %+v", s)
+	}
+
+	_ = useCase.CheckAdaptation()
+	if suggestions := constraintSuggestions(publisher, session.Token); len(suggestions) != 1 {
+		t.Fatalf("Expected the sender to get a window on the new step, got %+v", suggestions)
+	}
+
+	// The loss is gone, but recovering waits longer than stepping down
+	_ = statsRepo.DeleteStats(session.Token)
+	appendAdaptationStats(statsRepo, session.Token, "viewer-1", 0, 15)
+	backdate(adaptationWindow)
+	_ = useCase.CheckAdaptation()
+	if suggestions := constraintSuggestions(publisher, session.Token); len(suggestions) != 1 {
+		t.Fatalf("Expected no step up before adaptationRecoverAfter, got %+v", suggestions)
+	}
+	backdate(adaptationRecoverAfter)
+	_ = useCase.CheckAdaptation()
+	suggestions = constraintSuggestions(publisher, session.Token)
+	if len(suggestions) != 2 || suggestions[1].Level != 0 || suggestions[1].Reason != entities.AdaptationRecovered || suggestions[1].Message != "Viewer connection recovered, suggesting full quality" {
+		t.Fatalf("Expected a step back up, got %+v", suggestions)
+	}
+
+	// Another viewer starts over
+	useCase.states[session.Token].level = 2
+	session.ViewerID = "viewer-2"
+	sessionRepo.SetSession(session)
+	_ = useCase.CheckAdaptation()
+	if state := useCase.states[session.Token]; state.viewerID != "viewer-2" || state.level != 0 {
+		t.Errorf("Expected the new viewer to start from level 0, got %+v", state)
+	}
+
+	// Sessions through the SFU are left alone and forgotten
+	session.SFU = true
+	sessionRepo.SetSession(session)
+	_ = useCase.CheckAdaptation()
+	if _, ok := useCase.states[session.Token]; ok {
+		t.Error("Expected the SFU session to be dropped from the adaptation state")
+	}
+}
+
+func TestSummarizeConnection(t *testing.T) {
+	samples := []entities.StatsSample{
+		{Role: entities.StatsRoleViewer, ViewerID: "viewer-1", PacketLoss: 0.02, FrameRate: 10},
+		{Role: entities.StatsRoleViewer, ViewerID: "viewer-1", PacketLoss: 0.04, FrameRate: 20},
+		{Role: entities.StatsRoleViewer, ViewerID: "other", PacketLoss: 1, FrameRate: 1},
+		{Role: entities.StatsRoleSender, FrameRate: 30},
+		{Role: entities.StatsRoleSender, FrameRate: 20},
+	}
+	if _, ok := summarizeConnection(samples, "viewer-1"); ok {
+		t.Error("Expected too few samples of the viewer to give no summary")
+	}
+
+	samples = append(samples, entities.StatsSample{Role: entities.StatsRoleViewer, ViewerID: "viewer-1", PacketLoss: 0.06, FrameRate: 30})
+	summary, ok := summarizeConnection(samples, "viewer-1")
+	if !ok {
+		t.Fatal("Expected a summary of three samples")
+	}
+	if summary.PacketLoss < 0.0399 || summary.PacketLoss > 0.0401 || summary.ViewerFrameRate != 20 || summary.SenderFrameRate != 25 {
+		t.Errorf("Expected the averages of the viewer's and sender's samples, got %+v", summary)
+	}
+}
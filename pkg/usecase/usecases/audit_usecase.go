@@ -0,0 +1,28 @@
+package usecases
+
+import (
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// AuditUseCase implements the audit use case interface; the events
+// themselves are recorded by the use cases that drive the sessions
+type AuditUseCase struct {
+	auditRepo interfaces.AuditLogRepository
+}
+
+// NewAuditUseCase creates a new audit use case
+func NewAuditUseCase(auditRepo interfaces.AuditLogRepository) *AuditUseCase {
+	return &AuditUseCase{auditRepo: auditRepo}
+}
+
+// GetAuditLog returns the audit log of a session, which outlives the session
+// itself until the log is pushed out by newer sessions
+func (uc *AuditUseCase) GetAuditLog(request *dto.GetAuditLogRequest) (*dto.AuditLogResponse, error) {
+	events, err := uc.auditRepo.ListEvents(request.Token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return &dto.AuditLogResponse{Token: request.Token, Events: events}, nil
+}
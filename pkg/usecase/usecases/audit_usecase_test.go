@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestAuditUseCase_GetAuditLog(t *testing.T) {
+	auditRepo := mocks.NewMockAuditLogRepository()
+	_ = auditRepo.AppendEvent(&entities.AuditEvent{At: time.Now(), Token: "test-token", Type: entities.AuditSessionCreated})
+	_ = auditRepo.AppendEvent(&entities.AuditEvent{At: time.Now(), Token: "test-token", Type: entities.AuditExpired})
+	useCase := NewAuditUseCase(auditRepo)
+
+	response, err := useCase.GetAuditLog(&dto.GetAuditLogRequest{Token: "test-token"})
+	if err != nil {
+		t.Fatalf("GetAuditLog failed: %v", err)
+	}
+	if response.Token != "test-token" || len(response.Events) != 2 || response.Events[1].Type != entities.AuditExpired {
+		t.Errorf("Expected the session's two events, got %+v", response)
+	}
+
+	if _, err := useCase.GetAuditLog(&dto.GetAuditLogRequest{Token: "missing-token"}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound but got %v", err)
+	}
+}
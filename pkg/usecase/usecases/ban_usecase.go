@@ -0,0 +1,135 @@
+package usecases
+
+import (
+	"log"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxBanReasonLength limits the note kept with a ban
+const maxBanReasonLength = 200
+
+// BanUseCase implements the ban use case interface. The banned ranges are
+// kept parsed next to the repository, since every signaling request is
+// checked against them.
+type BanUseCase struct {
+	banRepo interfaces.BanRepository
+
+	mu       sync.RWMutex
+	networks []netip.Prefix
+}
+
+// NewBanUseCase creates a new ban use case with the bans already stored
+func NewBanUseCase(banRepo interfaces.BanRepository) (*BanUseCase, error) {
+	uc := &BanUseCase{banRepo: banRepo}
+	if err := uc.reload(); err != nil {
+		return nil, err
+	}
+	return uc, nil
+}
+
+// ListBans returns the ban list, oldest first
+func (uc *BanUseCase) ListBans() (*dto.BansResponse, error) {
+	bans, err := uc.banRepo.ListBans()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.BansResponse{Bans: bans}, nil
+}
+
+// AddBan bans an address or range; banning a network again updates its
+// reason. Loopback cannot be banned, so the sender on the server's own
+// machine is never locked out.
+func (uc *BanUseCase) AddBan(request *dto.AddBanRequest) (*entities.Ban, error) {
+	prefix, err := entities.ParseNetwork(strings.TrimSpace(request.Network))
+	if err != nil {
+		return nil, ErrInvalidBan
+	}
+	if prefix.Contains(netip.IPv6Loopback()) || prefix.Contains(netip.MustParseAddr("127.0.0.1")) {
+		return nil, ErrBanLoopback
+	}
+	reason := strings.TrimSpace(request.Reason)
+	if len(reason) > maxBanReasonLength {
+		return nil, ErrInvalidBan
+	}
+
+	ban := &entities.Ban{
+		Network:   prefix.String(),
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	if err := uc.banRepo.SaveBan(ban); err != nil {
+		log.Printf("❌ Error saving ban: %v", err)
+		return nil, err
+	}
+	if err := uc.reload(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("🚫 Banned %s", ban.Network)
+	return ban, nil
+}
+
+// RemoveBan lifts the ban of an address or range
+func (uc *BanUseCase) RemoveBan(request *dto.RemoveBanRequest) error {
+	prefix, err := entities.ParseNetwork(strings.TrimSpace(request.Network))
+	if err != nil {
+		return ErrInvalidBan
+	}
+	if err := uc.banRepo.DeleteBan(prefix.String()); err != nil {
+		return ErrBanNotFound
+	}
+	if err := uc.reload(); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Lifted the ban of %s", prefix)
+	return nil
+}
+
+// IsBanned reports whether a client IP address falls in a banned range
+func (uc *BanUseCase) IsBanned(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	for _, network := range uc.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// reload parses the stored bans for IsBanned
+func (uc *BanUseCase) reload() error {
+	bans, err := uc.banRepo.ListBans()
+	if err != nil {
+		return err
+	}
+
+	networks := make([]netip.Prefix, 0, len(bans))
+	for _, ban := range bans {
+		prefix, err := entities.ParseNetwork(ban.Network)
+		if err != nil {
+			log.Printf("⚠️  Ignoring ban of %s", err)
+			continue
+		}
+		networks = append(networks, prefix)
+	}
+
+	uc.mu.Lock()
+	uc.networks = networks
+	uc.mu.Unlock()
+	return nil
+}
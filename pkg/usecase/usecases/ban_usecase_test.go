@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestBanUseCase(t *testing.T) {
+	banRepo := mocks.NewMockBanRepository()
+	useCase, err := NewBanUseCase(banRepo)
+	if err != nil {
+		t.Fatalf("NewBanUseCase failed: %v", err)
+	}
+
+	ban, err := useCase.AddBan(&dto.AddBanRequest{Network: " 192.168.1.50 ", Reason: "spam"})
+	if err != nil {
+		t.Fatalf("AddBan failed: %v", err)
+	}
+	if ban.Network != "192.168.1.50/32" || ban.Reason != "spam" || ban.CreatedAt.IsZero() {
+		t.Errorf("Expected a /32 ban with its reason, got %+v", ban)
+	}
+	if _, err := useCase.AddBan(&dto.AddBanRequest{Network: "10.1.2.3/16"}); err != nil {
+		t.Fatalf("AddBan of a range failed: %v", err)
+	}
+
+	for ip, banned := range map[string]bool{
+		"192.168.1.50":        true,
+		"192.168.1.51":        false,
+		"10.1.200.7":          true,
+		"::ffff:192.168.1.50": true,
+		"not an address":      false,
+	} {
+		if useCase.IsBanned(ip) != banned {
+			t.Errorf("Expected IsBanned(%q) to be %v", ip, banned)
+		}
+	}
+
+	response, _ := useCase.ListBans()
+	if len(response.Bans) != 2 || response.Bans[1].Network != "10.1.0.0/16" {
+		t.Errorf("Expected both bans with the range masked, got %+v", response.Bans)
+	}
+
+	if err := useCase.RemoveBan(&dto.RemoveBanRequest{Network: "192.168.1.50"}); err != nil {
+		t.Fatalf("RemoveBan failed: %v", err)
+	}
+	if useCase.IsBanned("192.168.1.50") {
+		t.Error("Expected the lifted ban to stop blocking")
+	}
+	if err := useCase.RemoveBan(&dto.RemoveBanRequest{Network: "192.168.1.50"}); err != ErrBanNotFound {
+		t.Errorf("Expected ErrBanNotFound but got %v", err)
+	}
+
+	// Bans already stored are enforced from the start
+	reloaded, _ := NewBanUseCase(banRepo)
+	if !reloaded.IsBanned("10.1.0.1") {
+		t.Error("Expected a stored ban to be enforced by a new use case")
+	}
+}
+
+func TestBanUseCase_AddBanInvalid(t *testing.T) {
+	useCase, _ := NewBanUseCase(mocks.NewMockBanRepository())
+
+	tests := []struct {
+		name     string
+		request  *dto.AddBanRequest
+		expected error
+	}{
+		{name: "not an address", request: &dto.AddBanRequest{Network: "guest wifi"}, expected: ErrInvalidBan},
+		{name: "empty", request: &dto.AddBanRequest{}, expected: ErrInvalidBan},
+		{name: "reason too long", request: &dto.AddBanRequest{Network: "10.0.0.1", Reason: strings.Repeat("x", maxBanReasonLength+1)}, expected: ErrInvalidBan},
+		{name: "loopback", request: &dto.AddBanRequest{Network: "127.0.0.1"}, expected: ErrBanLoopback},
+		{name: "range with loopback", request: &dto.AddBanRequest{Network: "0.0.0.0/0"}, expected: ErrBanLoopback},
+		{name: "IPv6 loopback", request: &dto.AddBanRequest{Network: "::1"}, expected: ErrBanLoopback},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := useCase.AddBan(tt.request); err != tt.expected {
+				t.Errorf("Expected %v but got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// CapabilitiesUseCase implements the capabilities use case interface
+type CapabilitiesUseCase struct {
+	capabilities entities.Capabilities
+}
+
+// NewCapabilitiesUseCase creates a new capabilities use case for a server
+// handing out iceServers; statusEnabled reports whether the viewer status
+// endpoint has a token, fileRelayEnabled whether sessions may relay files,
+// sfuEnabled whether sessions may stream through the server, hlsEnabled
+// whether the server packages their video as HLS, invitesEnabled whether
+// senders may post their viewer link to team chat and profilesEnabled
+// whether senders may keep their defaults on the server
+func NewCapabilitiesUseCase(iceServers []entities.ICEServer, statusEnabled, fileRelayEnabled, sfuEnabled, hlsEnabled, invitesEnabled, profilesEnabled bool) *CapabilitiesUseCase {
+	capabilities := entities.Capabilities{
+		TURN:         disabled("no TURN relay is configured, so viewers must reach the sender directly"),
+		SFU:          disabled("each session streams peer-to-peer to one viewer at a time"),
+		HLS:          disabled("no HLS directory is configured, so viewers need WebRTC"),
+		Recording:    disabled("the server does not record; use `share-screen view -out` to record a session"),
+		Chat:         entities.Capability{Enabled: true},
+		FileRelay:    disabled("the file relay is turned off, so files only reach a connected viewer"),
+		StatusAPI:    disabled("no status token is configured"),
+		Invites:      disabled("no Slack or Discord webhook is configured"),
+		Profiles:     disabled("no profile secret is configured, so sender defaults stay in the browser"),
+		AuthProvider: entities.AuthProviderNone,
+	}
+	for _, server := range iceServers {
+		if server.IsTURN() {
+			capabilities.TURN = entities.Capability{Enabled: true}
+			break
+		}
+	}
+	if statusEnabled {
+		capabilities.StatusAPI = entities.Capability{Enabled: true}
+	}
+	if fileRelayEnabled {
+		capabilities.FileRelay = entities.Capability{Enabled: true}
+	}
+	if sfuEnabled {
+		capabilities.SFU = entities.Capability{Enabled: true}
+	}
+	if sfuEnabled && hlsEnabled {
+		capabilities.HLS = entities.Capability{Enabled: true}
+	}
+	if invitesEnabled {
+		capabilities.Invites = entities.Capability{Enabled: true}
+	}
+	if profilesEnabled {
+		capabilities.Profiles = entities.Capability{Enabled: true}
+	}
+
+	return &CapabilitiesUseCase{capabilities: capabilities}
+}
+
+// GetCapabilities returns which optional subsystems work on this deployment
+func (uc *CapabilitiesUseCase) GetCapabilities() *entities.Capabilities {
+	capabilities := uc.capabilities
+	return &capabilities
+}
+
+// disabled returns a capability that does not work, and why
+func disabled(reason string) entities.Capability {
+	return entities.Capability{Reason: reason}
+}
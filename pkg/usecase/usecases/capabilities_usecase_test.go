@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestCapabilitiesUseCase_GetCapabilities(t *testing.T) {
+	stun := entities.ICEServer{URLs: []string{"stun:stun.example.com:3478"}}
+	turn := entities.ICEServer{URLs: []string{"turn:turn.example.com:3478"}, Username: "share", Credential: "relay"}
+
+	tests := []struct {
+		name          string
+		iceServers    []entities.ICEServer
+		statusEnabled bool
+		fileRelay     bool
+		sfu           bool
+		hls           bool
+		invites       bool
+		profiles      bool
+		wantTURN      bool
+		wantStatus    bool
+		wantHLS       bool
+	}{
+		{
+			name:       "STUN only",
+			iceServers: []entities.ICEServer{stun},
+		},
+		{
+			name:       "TURN relay configured",
+			iceServers: []entities.ICEServer{stun, turn},
+			wantTURN:   true,
+		},
+		{
+			name:          "status token configured",
+			statusEnabled: true,
+			wantStatus:    true,
+		},
+		{
+			name:      "file relay on",
+			fileRelay: true,
+		},
+		{
+			name: "SFU on",
+			sfu:  true,
+		},
+		{
+			name:    "HLS through the SFU",
+			sfu:     true,
+			hls:     true,
+			wantHLS: true,
+		},
+		{
+			name: "HLS without the SFU",
+			hls:  true,
+		},
+		{
+			name:    "chat webhook configured",
+			invites: true,
+		},
+		{
+			name:     "profile secret configured",
+			profiles: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewCapabilitiesUseCase(tt.iceServers, tt.statusEnabled, tt.fileRelay, tt.sfu, tt.hls, tt.invites, tt.profiles)
+
+			capabilities := useCase.GetCapabilities()
+
+			if capabilities.TURN.Enabled != tt.wantTURN {
+				t.Errorf("Expected TURN enabled %v, got %v", tt.wantTURN, capabilities.TURN.Enabled)
+			}
+			if capabilities.StatusAPI.Enabled != tt.wantStatus {
+				t.Errorf("Expected status API enabled %v, got %v", tt.wantStatus, capabilities.StatusAPI.Enabled)
+			}
+			if capabilities.Recording.Enabled {
+				t.Errorf("Expected unimplemented subsystems to be disabled, got %+v", capabilities)
+			}
+			if capabilities.SFU.Enabled != tt.sfu {
+				t.Errorf("Expected SFU enabled %v, got %v", tt.sfu, capabilities.SFU.Enabled)
+			}
+			if capabilities.HLS.Enabled != tt.wantHLS {
+				t.Errorf("Expected HLS enabled %v, got %v", tt.wantHLS, capabilities.HLS.Enabled)
+			}
+			if capabilities.FileRelay.Enabled != tt.fileRelay {
+				t.Errorf("Expected file relay enabled %v, got %v", tt.fileRelay, capabilities.FileRelay.Enabled)
+			}
+			if capabilities.Invites.Enabled != tt.invites {
+				t.Errorf("Expected invites enabled %v, got %v", tt.invites, capabilities.Invites.Enabled)
+			}
+			if capabilities.Profiles.Enabled != tt.profiles {
+				t.Errorf("Expected profiles enabled %v, got %v", tt.profiles, capabilities.Profiles.Enabled)
+			}
+			if !capabilities.Chat.Enabled {
+				t.Error("Expected chat to be enabled")
+			}
+			for name, capability := range map[string]entities.Capability{
+				"turn":      capabilities.TURN,
+				"sfu":       capabilities.SFU,
+				"hls":       capabilities.HLS,
+				"recording": capabilities.Recording,
+				"chat":      capabilities.Chat,
+				"fileRelay": capabilities.FileRelay,
+				"statusApi": capabilities.StatusAPI,
+				"invites":   capabilities.Invites,
+				"profiles":  capabilities.Profiles,
+			} {
+				if !capability.Enabled && capability.Reason == "" {
+					t.Errorf("Expected a reason for disabled %s", name)
+				}
+			}
+			if capabilities.AuthProvider != entities.AuthProviderNone {
+				t.Errorf("Expected auth provider %q, got %q", entities.AuthProviderNone, capabilities.AuthProvider)
+			}
+
+			// Callers must not be able to change what others are told
+			capabilities.Recording.Enabled = true
+			if useCase.GetCapabilities().Recording.Enabled {
+				t.Error("Expected the use case's capabilities to be left untouched")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxChatMessageLength limits a chat message, in characters
+const maxChatMessageLength = 500
+
+// ChatUseCase implements the chat relay use case interface
+type ChatUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	publisher   interfaces.EventPublisher
+}
+
+// NewChatUseCase creates a new chat relay use case
+func NewChatUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher) *ChatUseCase {
+	return &ChatUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		publisher:   publisher,
+	}
+}
+
+// SendMessage relays a chat message for peers whose data channel is not open
+// yet, keeping it for later polls and publishing it to the session's event
+// stream
+func (uc *ChatUseCase) SendMessage(request *dto.SendChatMessageRequest) (*entities.ChatMessage, error) {
+	text := strings.TrimSpace(request.Text)
+	if text == "" || utf8.RuneCountInString(text) > maxChatMessageLength {
+		return nil, ErrInvalidChatMessage
+	}
+	if request.From != entities.ChatFromSender && request.From != entities.ChatFromViewer {
+		return nil, ErrInvalidChatMessage
+	}
+
+	session, err := uc.authorize(request.Token, request.PIN)
+	if err != nil {
+		return nil, err
+	}
+
+	message := session.AddChatMessage(request.From, text, time.Now())
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error storing chat message: %v", err)
+		return nil, err
+	}
+
+	uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventChatMessage, Data: message})
+	return &message, nil
+}
+
+// GetMessages returns the relayed chat messages after request.Since
+func (uc *ChatUseCase) GetMessages(request *dto.GetChatMessagesRequest) (*dto.ChatMessagesResponse, error) {
+	session, err := uc.authorize(request.Token, request.PIN)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ChatMessagesResponse{Messages: session.ChatSince(request.Since)}, nil
+}
+
+// authorize returns the live session if it has chat enabled and pin unlocks it
+func (uc *ChatUseCase) authorize(token, pin string) (*entities.Session, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.ChatEnabled {
+		return nil, ErrChatDisabled
+	}
+	if err := unlockSession(uc.sessionRepo, session, pin); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestChatUseCase_SendMessage(t *testing.T) {
+	tests := []struct {
+		name          string
+		chatEnabled   bool
+		pin           string
+		request       *dto.SendChatMessageRequest
+		expectedError error
+	}{
+		{
+			name:        "sender message relayed",
+			chatEnabled: true,
+			request:     &dto.SendChatMessageRequest{Token: "test-token", From: entities.ChatFromSender, Text: "  hello  "},
+		},
+		{
+			name:        "viewer message with PIN",
+			chatEnabled: true,
+			pin:         "123456",
+			request:     &dto.SendChatMessageRequest{Token: "test-token", PIN: "123456", From: entities.ChatFromViewer, Text: "hello"},
+		},
+		{
+			name:          "wrong PIN",
+			chatEnabled:   true,
+			pin:           "123456",
+			request:       &dto.SendChatMessageRequest{Token: "test-token", PIN: "000000", From: entities.ChatFromViewer, Text: "hello"},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "chat not enabled",
+			request:       &dto.SendChatMessageRequest{Token: "test-token", From: entities.ChatFromSender, Text: "hello"},
+			expectedError: ErrChatDisabled,
+		},
+		{
+			name:          "empty message",
+			chatEnabled:   true,
+			request:       &dto.SendChatMessageRequest{Token: "test-token", From: entities.ChatFromSender, Text: "   "},
+			expectedError: ErrInvalidChatMessage,
+		},
+		{
+			name:          "message too long",
+			chatEnabled:   true,
+			request:       &dto.SendChatMessageRequest{Token: "test-token", From: entities.ChatFromSender, Text: strings.Repeat("a", maxChatMessageLength+1)},
+			expectedError: ErrInvalidChatMessage,
+		},
+		{
+			name:          "unknown author",
+			chatEnabled:   true,
+			request:       &dto.SendChatMessageRequest{Token: "test-token", From: "admin", Text: "hello"},
+			expectedError: ErrInvalidChatMessage,
+		},
+		{
+			name:          "unknown session",
+			chatEnabled:   true,
+			request:       &dto.SendChatMessageRequest{Token: "missing", From: entities.ChatFromSender, Text: "hello"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.ChatEnabled = tt.chatEnabled
+			session.PIN = tt.pin
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewChatUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), publisher)
+
+			message, err := useCase.SendMessage(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				if events := publisher.Published(entities.SessionTopic("test-token")); len(events) != 0 {
+					t.Errorf("Expected nothing published, got %+v", events)
+				}
+				return
+			}
+
+			if message.ID != 1 || message.Text != "hello" || message.From != tt.request.From {
+				t.Errorf("Unexpected message %+v", message)
+			}
+			events := publisher.Published(entities.SessionTopic("test-token"))
+			if len(events) != 1 || events[0].Type != entities.EventChatMessage {
+				t.Errorf("Expected a chat message event, got %+v", events)
+			}
+
+			relayed, err := useCase.GetMessages(&dto.GetChatMessagesRequest{Token: "test-token", PIN: tt.pin})
+			if err != nil {
+				t.Fatalf("GetMessages failed: %v", err)
+			}
+			if len(relayed.Messages) != 1 || relayed.Messages[0] != *message {
+				t.Errorf("Expected the message to be kept, got %+v", relayed.Messages)
+			}
+		})
+	}
+}
+
+func TestChatUseCase_GetMessages(t *testing.T) {
+	session := mocks.NewTestSession("test-token", false)
+	session.ChatEnabled = true
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewChatUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	for _, text := range []string{"one", "two", "three"} {
+		if _, err := useCase.SendMessage(&dto.SendChatMessageRequest{Token: "test-token", From: entities.ChatFromViewer, Text: text}); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+
+	response, err := useCase.GetMessages(&dto.GetChatMessagesRequest{Token: "test-token", Since: 1})
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if len(response.Messages) != 2 || response.Messages[0].Text != "two" || response.Messages[1].Text != "three" {
+		t.Errorf("Expected the messages after the first, got %+v", response.Messages)
+	}
+
+	response, err = useCase.GetMessages(&dto.GetChatMessagesRequest{Token: "test-token", Since: 3})
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if response.Messages == nil || len(response.Messages) != 0 {
+		t.Errorf("Expected an empty list, got %#v", response.Messages)
+	}
+}
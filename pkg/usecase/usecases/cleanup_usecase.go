@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// CleanupUseCase implements the cleanup use case interface: the garbage
+// collection of sessions that ended or expired and of what they left behind
+type CleanupUseCase struct {
+	sessionUseCase *SessionUseCase
+	fileUseCase    *FileUseCase
+	statsUseCase   *StatsUseCase
+	interval       time.Duration
+
+	// leader chooses the instance of a cluster that collects the shared
+	// sessions; nil for a single server, which always does
+	leader interfaces.LeaderElector
+
+	// running serializes runs, so one asked for by an admin waits for a
+	// scheduled one in progress
+	running sync.Mutex
+
+	mu      sync.RWMutex
+	lastRun *entities.CleanupRun
+}
+
+// NewCleanupUseCase creates a new cleanup use case; interval is how often the
+// server runs it on its own, reported with the last run, and leader is the
+// election of a cluster, or nil
+func NewCleanupUseCase(sessionUseCase *SessionUseCase, fileUseCase *FileUseCase, statsUseCase *StatsUseCase, interval time.Duration, leader interfaces.LeaderElector) *CleanupUseCase {
+	return &CleanupUseCase{
+		sessionUseCase: sessionUseCase,
+		fileUseCase:    fileUseCase,
+		statsUseCase:   statsUseCase,
+		interval:       interval,
+		leader:         leader,
+	}
+}
+
+// RunCleanup collects garbage now and returns what it removed. A failing
+// step does not stop the others; their errors are returned together.
+func (uc *CleanupUseCase) RunCleanup(trigger string) (*entities.CleanupRun, error) {
+	uc.running.Lock()
+	defer uc.running.Unlock()
+
+	run := &entities.CleanupRun{StartedAt: time.Now(), Trigger: trigger}
+	var errs []error
+
+	// Stale and idle sessions are due for cleanup straight away. In a
+	// cluster only the leader collects the shared sessions.
+	var err error
+	run.Follower = uc.leader != nil && !uc.leader.IsLeader()
+	if !run.Follower {
+		if run.StaleSessions, err = uc.sessionUseCase.MarkStaleSessions(); err != nil {
+			errs = append(errs, fmt.Errorf("checking sender heartbeats: %w", err))
+		}
+		if run.IdleSessions, err = uc.sessionUseCase.ExpireIdleSessions(); err != nil {
+			errs = append(errs, fmt.Errorf("expiring idle sessions: %w", err))
+		}
+		if run.ExpiredSessions, err = uc.sessionUseCase.CleanupExpiredSessions(); err != nil {
+			errs = append(errs, fmt.Errorf("removing expired sessions: %w", err))
+		}
+	}
+	if run.Files, err = uc.fileUseCase.PruneFiles(); err != nil {
+		errs = append(errs, fmt.Errorf("dropping relayed files: %w", err))
+	}
+	if run.Stats, err = uc.statsUseCase.PruneStats(); err != nil {
+		errs = append(errs, fmt.Errorf("dropping connection stats: %w", err))
+	}
+	run.Streams = uc.sessionUseCase.PruneStreams()
+
+	run.DurationMillis = time.Since(run.StartedAt).Milliseconds()
+	err = errors.Join(errs...)
+	if err != nil {
+		run.Error = err.Error()
+		log.Printf("❌ Cleanup failed: %v", err)
+	}
+	if trigger == entities.CleanupTriggerAdmin {
+		log.Printf("🗑️  Cleanup run by an admin: %d stale, %d expired sessions removed", run.StaleSessions, run.ExpiredSessions)
+	}
+
+	uc.mu.Lock()
+	uc.lastRun = run
+	uc.mu.Unlock()
+
+	result := *run
+	return &result, err
+}
+
+// CleanupStatus returns the collection interval and the latest run, if any
+func (uc *CleanupUseCase) CleanupStatus() *entities.CleanupStatus {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+
+	status := &entities.CleanupStatus{IntervalSeconds: uc.interval.Seconds()}
+	if uc.lastRun != nil {
+		run := *uc.lastRun
+		status.LastRun = &run
+	}
+	return status
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func newTestCleanupUseCase(sessionRepo *mocks.MockSessionRepository) *CleanupUseCase {
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	publisher := mocks.NewMockEventPublisher()
+	sessionUseCase := NewSessionUseCase(sessionRepo, historyRepo, mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+	fileUseCase := NewFileUseCase(mocks.NewMockFileRepository(), sessionRepo, historyRepo, publisher, 100)
+	statsUseCase := NewStatsUseCase(mocks.NewMockStatsRepository(), sessionRepo, historyRepo, nil)
+	return NewCleanupUseCase(sessionUseCase, fileUseCase, statsUseCase, time.Minute, nil)
+}
+
+func TestCleanupUseCase_RunCleanup(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{Token: "expired-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(-time.Second)})
+	mockRepo.SetSession(&entities.Session{Token: "live-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Minute)})
+	useCase := newTestCleanupUseCase(mockRepo)
+
+	if status := useCase.CleanupStatus(); status.IntervalSeconds != 60 || status.LastRun != nil {
+		t.Fatalf("Expected a one-minute schedule that has not run, got %+v", status)
+	}
+
+	run, err := useCase.RunCleanup(entities.CleanupTriggerAdmin)
+	if err != nil {
+		t.Fatalf("RunCleanup failed: %v", err)
+	}
+	if run.ExpiredSessions != 1 || run.Trigger != entities.CleanupTriggerAdmin || run.Error != "" {
+		t.Errorf("Expected one expired session removed by an admin, got %+v", run)
+	}
+	if mockRepo.GetSessionCount() != 1 {
+		t.Errorf("Expected the live session kept, got %d sessions", mockRepo.GetSessionCount())
+	}
+
+	status := useCase.CleanupStatus()
+	if status.LastRun == nil || status.LastRun.ExpiredSessions != 1 {
+		t.Errorf("Expected the run recorded as the last one, got %+v", status.LastRun)
+	}
+}
+
+func TestCleanupUseCase_RunCleanup_Cluster(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{Token: "expired-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(-time.Second)})
+	leader := mocks.NewMockLeaderElector(false)
+	useCase := newTestCleanupUseCase(mockRepo)
+	useCase.leader = leader
+
+	// A follower leaves the shared sessions to the leader
+	run, err := useCase.RunCleanup(entities.CleanupTriggerSchedule)
+	if err != nil {
+		t.Fatalf("RunCleanup failed: %v", err)
+	}
+	if !run.Follower || run.ExpiredSessions != 0 || mockRepo.GetSessionCount() != 1 {
+		t.Errorf("Expected a follower to keep the expired session, got %+v", run)
+	}
+
+	leader.SetLeading(true)
+	run, err = useCase.RunCleanup(entities.CleanupTriggerSchedule)
+	if err != nil {
+		t.Fatalf("RunCleanup failed: %v", err)
+	}
+	if run.Follower || run.ExpiredSessions != 1 || mockRepo.GetSessionCount() != 0 {
+		t.Errorf("Expected the leader to remove the expired session, got %+v", run)
+	}
+	if leader.Checks() != 2 {
+		t.Errorf("Expected leadership checked on every run, got %d checks", leader.Checks())
+	}
+}
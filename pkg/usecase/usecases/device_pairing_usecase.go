@@ -0,0 +1,234 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxPendingPairings caps the codes waiting for a session's sender; the
+// oldest gives way to a new one
+const maxPendingPairings = 10
+
+// DevicePairingUseCase implements the device pairing use case interface
+type DevicePairingUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	deviceRepo  interfaces.TrustedDeviceRepository
+
+	// mu guards the codes waiting for a sender to confirm them, by code
+	mu      sync.Mutex
+	pending map[string]*entities.PairingRequest
+}
+
+// NewDevicePairingUseCase creates a new device pairing use case
+func NewDevicePairingUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, deviceRepo interfaces.TrustedDeviceRepository) *DevicePairingUseCase {
+	return &DevicePairingUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		deviceRepo:  deviceRepo,
+		pending:     make(map[string]*entities.PairingRequest),
+	}
+}
+
+// RequestPairing hands a viewer device that can join a session the code its
+// sender confirms to trust it. Only sessions started from a browser can
+// pair, since trust is kept per sender cookie.
+func (uc *DevicePairingUseCase) RequestPairing(request *dto.RequestPairingRequest) (*dto.PairingCodeResponse, error) {
+	if request.DeviceID == "" {
+		return nil, ErrInvalidDevice
+	}
+	name := strings.TrimSpace(request.Name)
+	if !entities.ValidDeviceName(name) {
+		return nil, ErrInvalidPairing
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if session.Owner == "" {
+		return nil, ErrPairingUnavailable
+	}
+	if err := unlockSession(uc.sessionRepo, session, request.PIN); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := time.Now()
+	var oldest *entities.PairingRequest
+	waiting := 0
+	for code, pairing := range uc.pending {
+		switch {
+		case pairing.IsExpired(now), pairing.Token == session.Token && pairing.DeviceID == request.DeviceID:
+			// A device asking again replaces its code
+			delete(uc.pending, code)
+		case pairing.Token == session.Token:
+			waiting++
+			if oldest == nil || pairing.ExpiresAt.Before(oldest.ExpiresAt) {
+				oldest = pairing
+			}
+		}
+	}
+	if waiting >= maxPendingPairings {
+		delete(uc.pending, oldest.Code)
+	}
+
+	code, err := uc.generateCode()
+	if err != nil {
+		return nil, err
+	}
+	pairing := &entities.PairingRequest{
+		Code:      code,
+		Token:     session.Token,
+		DeviceID:  request.DeviceID,
+		Name:      name,
+		ExpiresAt: now.Add(entities.PairingCodeTTL),
+	}
+	uc.pending[code] = pairing
+
+	log.Printf("🤝 Pairing code handed out for token: %s", entities.ShortToken(session.Token))
+	return &dto.PairingCodeResponse{Code: code, ExpiresAt: pairing.ExpiresAt}, nil
+}
+
+// ConfirmPairing trusts the viewer device showing code for every session of
+// the sender; only the session's sender may confirm. A device paired before
+// keeps its pairing, renamed.
+func (uc *DevicePairingUseCase) ConfirmPairing(request *dto.ConfirmPairingRequest) (*dto.TrustedDevice, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.CheckSenderKey(request.SenderKey) {
+		log.Printf("🔒 Wrong sender key for pairing on token: %s", entities.ShortToken(request.Token))
+		return nil, ErrInvalidSenderKey
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := time.Now()
+	pairing, exists := uc.pending[request.Code]
+	if !exists || pairing.Token != session.Token || pairing.IsExpired(now) {
+		return nil, ErrPairingCodeNotFound
+	}
+
+	device, err := uc.deviceRepo.FindDevice(session.Owner, pairing.DeviceID)
+	if err != nil {
+		id, err := generateTrustedDeviceID()
+		if err != nil {
+			return nil, err
+		}
+		device = &entities.TrustedDevice{ID: id, Owner: session.Owner, DeviceID: pairing.DeviceID}
+	}
+	device.Name = pairing.Name
+	device.PairedAt = now
+	if err := uc.deviceRepo.SaveDevice(device); err != nil {
+		log.Printf("❌ Error saving trusted device: %v", err)
+		return nil, err
+	}
+	delete(uc.pending, request.Code)
+
+	log.Printf("🤝 Device paired for token: %s", entities.ShortToken(session.Token))
+	response := toTrustedDevice(device)
+	return &response, nil
+}
+
+// UnlockSession returns the PIN of a session to a device its sender trusts,
+// so the device joins without asking the viewer
+func (uc *DevicePairingUseCase) UnlockSession(request *dto.UnlockSessionRequest) (*dto.UnlockSessionResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if request.DeviceID == "" || session.Owner == "" {
+		return nil, ErrDeviceNotTrusted
+	}
+
+	device, err := uc.deviceRepo.FindDevice(session.Owner, request.DeviceID)
+	if err != nil {
+		return nil, ErrDeviceNotTrusted
+	}
+	device.LastUsedAt = time.Now()
+	if err := uc.deviceRepo.SaveDevice(device); err != nil {
+		// The device is trusted all the same
+		log.Printf("❌ Error saving trusted device use: %v", err)
+	}
+
+	log.Printf("🤝 Trusted device unlocked token: %s", entities.ShortToken(session.Token))
+	return &dto.UnlockSessionResponse{PIN: session.PIN}, nil
+}
+
+// ListDevices returns the devices a sender trusts, oldest pairing first;
+// senders without an ID trust none
+func (uc *DevicePairingUseCase) ListDevices(request *dto.ListTrustedDevicesRequest) (*dto.TrustedDevicesResponse, error) {
+	response := &dto.TrustedDevicesResponse{Devices: []dto.TrustedDevice{}}
+	if request.Owner == "" {
+		return response, nil
+	}
+
+	devices, err := uc.deviceRepo.ListDevices(request.Owner)
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		response.Devices = append(response.Devices, toTrustedDevice(device))
+	}
+	return response, nil
+}
+
+// ForgetDevice withdraws a sender's trust in a device; viewers already
+// watching stay connected
+func (uc *DevicePairingUseCase) ForgetDevice(request *dto.ForgetTrustedDeviceRequest) error {
+	if request.Owner == "" {
+		return ErrTrustedDeviceNotFound
+	}
+	if err := uc.deviceRepo.DeleteDevice(request.Owner, request.ID); err != nil {
+		return ErrTrustedDeviceNotFound
+	}
+
+	log.Printf("🤝 Trusted device forgotten")
+	return nil
+}
+
+// generateCode returns a 6-digit code no waiting pairing uses; the caller
+// holds mu
+func (uc *DevicePairingUseCase) generateCode() (string, error) {
+	for {
+		code, err := generatePIN()
+		if err != nil {
+			return "", err
+		}
+		if _, taken := uc.pending[code]; !taken {
+			return code, nil
+		}
+	}
+}
+
+// generateTrustedDeviceID creates the ID a sender names a pairing by
+func generateTrustedDeviceID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// toTrustedDevice describes a pairing to its sender, without the device ID
+func toTrustedDevice(device *entities.TrustedDevice) dto.TrustedDevice {
+	return dto.TrustedDevice{
+		ID:         device.ID,
+		Name:       device.Name,
+		PairedAt:   device.PairedAt,
+		LastUsedAt: device.LastUsedAt,
+	}
+}
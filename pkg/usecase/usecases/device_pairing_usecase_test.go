@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestDevicePairingUseCase(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	for token, owner := range map[string]string{"first": "alice", "second": "alice", "other": "bob"} {
+		session := mocks.NewTestSession(token, false)
+		session.SenderKey = "sender-key"
+		session.Owner = owner
+		session.PIN = "135790"
+		sessionRepo.SetSession(session)
+	}
+	useCase := NewDevicePairingUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockTrustedDeviceRepository())
+
+	// Untrusted devices still need the PIN
+	if _, err := useCase.UnlockSession(&dto.UnlockSessionRequest{Token: "second", DeviceID: "tablet"}); err != ErrDeviceNotTrusted {
+		t.Fatalf("Expected ErrDeviceNotTrusted before pairing, got %v", err)
+	}
+	if _, err := useCase.RequestPairing(&dto.RequestPairingRequest{Token: "first", DeviceID: "tablet", PIN: "000000"}); err != ErrInvalidPIN {
+		t.Fatalf("Expected ErrInvalidPIN for a wrong PIN, got %v", err)
+	}
+
+	code, err := useCase.RequestPairing(&dto.RequestPairingRequest{Token: "first", DeviceID: "tablet", PIN: "135790", Name: " Kitchen tablet "})
+	if err != nil {
+		t.Fatalf("RequestPairing failed: %v", err)
+	}
+	if len(code.Code) != 6 || code.ExpiresAt.IsZero() {
+		t.Fatalf("Expected a 6-digit code with an expiry, got %+v", code)
+	}
+
+	// Only the session's sender confirms, and only codes of its session
+	if _, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "first", Code: code.Code, SenderKey: "guess"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+	if _, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "other", Code: code.Code, SenderKey: "sender-key"}); err != ErrPairingCodeNotFound {
+		t.Errorf("Expected ErrPairingCodeNotFound for another session's code, got %v", err)
+	}
+	device, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "first", Code: code.Code, SenderKey: "sender-key"})
+	if err != nil {
+		t.Fatalf("ConfirmPairing failed: %v", err)
+	}
+	if device.ID == "" || device.Name != "Kitchen tablet" || device.PairedAt.IsZero() {
+		t.Errorf("Expected the named pairing, got %+v", device)
+	}
+	if _, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "first", Code: code.Code, SenderKey: "sender-key"}); err != ErrPairingCodeNotFound {
+		t.Errorf("Expected a confirmed code to be used up, got %v", err)
+	}
+
+	// The device joins the sender's other sessions, but not other senders'
+	unlocked, err := useCase.UnlockSession(&dto.UnlockSessionRequest{Token: "second", DeviceID: "tablet"})
+	if err != nil || unlocked.PIN != "135790" {
+		t.Fatalf("Expected the trusted device to get the PIN, got %+v, %v", unlocked, err)
+	}
+	if _, err := useCase.UnlockSession(&dto.UnlockSessionRequest{Token: "other", DeviceID: "tablet"}); err != ErrDeviceNotTrusted {
+		t.Errorf("Expected ErrDeviceNotTrusted for another sender's session, got %v", err)
+	}
+
+	listed, err := useCase.ListDevices(&dto.ListTrustedDevicesRequest{Owner: "alice"})
+	if err != nil || len(listed.Devices) != 1 || listed.Devices[0].ID != device.ID || listed.Devices[0].LastUsedAt.IsZero() {
+		t.Fatalf("Expected the used pairing, got %+v, %v", listed, err)
+	}
+	if none, _ := useCase.ListDevices(&dto.ListTrustedDevicesRequest{}); len(none.Devices) != 0 {
+		t.Errorf("Expected no devices without a sender ID, got %+v", none)
+	}
+
+	// Pairing again keeps the pairing
+	again, _ := useCase.RequestPairing(&dto.RequestPairingRequest{Token: "second", DeviceID: "tablet", PIN: "135790", Name: "Tablet"})
+	repaired, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "second", Code: again.Code, SenderKey: "sender-key"})
+	if err != nil || repaired.ID != device.ID || repaired.Name != "Tablet" {
+		t.Errorf("Expected the pairing renamed, got %+v, %v", repaired, err)
+	}
+
+	if err := useCase.ForgetDevice(&dto.ForgetTrustedDeviceRequest{Owner: "bob", ID: device.ID}); err != ErrTrustedDeviceNotFound {
+		t.Errorf("Expected ErrTrustedDeviceNotFound for another sender, got %v", err)
+	}
+	if err := useCase.ForgetDevice(&dto.ForgetTrustedDeviceRequest{Owner: "alice", ID: device.ID}); err != nil {
+		t.Fatalf("ForgetDevice failed: %v", err)
+	}
+	if _, err := useCase.UnlockSession(&dto.UnlockSessionRequest{Token: "second", DeviceID: "tablet"}); err != ErrDeviceNotTrusted {
+		t.Errorf("Expected ErrDeviceNotTrusted once forgotten, got %v", err)
+	}
+}
+
+func TestDevicePairingUseCase_RequestPairing(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.RequestPairingRequest
+		expectedError error
+	}{
+		{
+			name:          "no device",
+			request:       &dto.RequestPairingRequest{Token: "test-token", PIN: "135790"},
+			expectedError: ErrInvalidDevice,
+		},
+		{
+			name:          "name too long",
+			request:       &dto.RequestPairingRequest{Token: "test-token", PIN: "135790", DeviceID: "tablet", Name: strings.Repeat("a", 65)},
+			expectedError: ErrInvalidPairing,
+		},
+		{
+			name:          "session not started from a browser",
+			request:       &dto.RequestPairingRequest{Token: "api-token", PIN: "135790", DeviceID: "tablet"},
+			expectedError: ErrPairingUnavailable,
+		},
+		{
+			name:          "missing session",
+			request:       &dto.RequestPairingRequest{Token: "missing", PIN: "135790", DeviceID: "tablet"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewMockSessionRepository()
+			for token, owner := range map[string]string{"test-token": "alice", "api-token": ""} {
+				session := mocks.NewTestSession(token, false)
+				session.SenderKey = "sender-key"
+				session.Owner = owner
+				session.PIN = "135790"
+				sessionRepo.SetSession(session)
+			}
+			useCase := NewDevicePairingUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockTrustedDeviceRepository())
+
+			if _, err := useCase.RequestPairing(tt.request); err != tt.expectedError {
+				t.Errorf("Expected %v but got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestDevicePairingUseCase_PendingLimit(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SenderKey = "sender-key"
+	session.Owner = "alice"
+	session.PIN = "135790"
+	sessionRepo.SetSession(session)
+	useCase := NewDevicePairingUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockTrustedDeviceRepository())
+
+	request := func(device string) string {
+		code, err := useCase.RequestPairing(&dto.RequestPairingRequest{Token: "test-token", PIN: "135790", DeviceID: device})
+		if err != nil {
+			t.Fatalf("RequestPairing failed: %v", err)
+		}
+		return code.Code
+	}
+
+	// A device asking again replaces its code
+	first := request("device-0")
+	oldest := request("device-0")
+	if oldest != first {
+		if _, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "test-token", Code: first, SenderKey: "sender-key"}); err != ErrPairingCodeNotFound {
+			t.Errorf("Expected a replaced code to be withdrawn, got %v", err)
+		}
+	}
+
+	// The oldest code gives way once the session has too many waiting
+	for i := 0; i < maxPendingPairings; i++ {
+		request("device-" + string(rune('a'+i)))
+	}
+	if _, err := useCase.ConfirmPairing(&dto.ConfirmPairingRequest{Token: "test-token", Code: oldest, SenderKey: "sender-key"}); err != ErrPairingCodeNotFound {
+		t.Errorf("Expected the oldest code to give way, got %v", err)
+	}
+}
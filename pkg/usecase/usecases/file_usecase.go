@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"mime"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// FileUseCase implements the file relay use case interface
+type FileUseCase struct {
+	fileRepo    interfaces.FileRepository
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	publisher   interfaces.EventPublisher
+
+	// limit is how many bytes each session may relay; 0 disables the relay
+	limit int64
+
+	// mu keeps concurrent uploads of one session from overrunning its limit
+	mu sync.Mutex
+}
+
+// NewFileUseCase creates a new file relay use case letting each session relay
+// up to limit bytes
+func NewFileUseCase(fileRepo interfaces.FileRepository, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher, limit int64) *FileUseCase {
+	return &FileUseCase{
+		fileRepo:    fileRepo,
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		publisher:   publisher,
+		limit:       limit,
+	}
+}
+
+// ShareFile keeps a file the sender dropped while no data channel to the
+// viewer was open, and announces it on the session's event stream
+func (uc *FileUseCase) ShareFile(request *dto.ShareFileRequest) (*entities.SharedFile, error) {
+	if uc.limit <= 0 {
+		return nil, ErrFileRelayDisabled
+	}
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	files, err := uc.fileRepo.ListFiles(session.Token)
+	if err != nil {
+		return nil, err
+	}
+	used := int64(len(request.Data))
+	for _, file := range files {
+		used += file.Size
+	}
+	if used > uc.limit {
+		return nil, ErrFileTooLarge
+	}
+
+	id, err := generateFileID()
+	if err != nil {
+		return nil, err
+	}
+	file := &entities.SharedFile{
+		ID:          id,
+		Name:        entities.SanitizeFileName(request.Name),
+		Size:        int64(len(request.Data)),
+		ContentType: mediaType(request.ContentType),
+		SharedAt:    time.Now(),
+	}
+	if err := uc.fileRepo.SaveFile(session.Token, file, request.Data); err != nil {
+		log.Printf("❌ Error storing file: %v", err)
+		return nil, err
+	}
+
+	log.Printf("📎 File %s (%d bytes) relayed for session %s", file.Name, file.Size, entities.ShortToken(session.Token))
+	uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventFileShared, Data: file})
+	return file, nil
+}
+
+// ListFiles returns the files relayed in a session
+func (uc *FileUseCase) ListFiles(request *dto.GetFilesRequest) (*dto.FilesResponse, error) {
+	session, err := uc.authorize(request.Token, request.PIN)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := uc.fileRepo.ListFiles(session.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FilesResponse{Files: files}, nil
+}
+
+// GetFile returns one relayed file and its content
+func (uc *FileUseCase) GetFile(request *dto.GetFileRequest) (*entities.SharedFile, []byte, error) {
+	session, err := uc.authorize(request.Token, request.PIN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, data, err := uc.fileRepo.GetFile(session.Token, request.ID)
+	if err != nil {
+		return nil, nil, ErrFileNotFound
+	}
+	return file, data, nil
+}
+
+// PruneFiles drops the files of sessions that are no longer live, returning
+// how many sessions were cleaned up
+func (uc *FileUseCase) PruneFiles() (int, error) {
+	tokens, err := uc.fileRepo.GetTokens()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, token := range tokens {
+		if _, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token); err == nil {
+			continue
+		}
+		if err := uc.fileRepo.DeleteFiles(token); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	if pruned > 0 {
+		log.Printf("🗑️  Dropped relayed files of %d ended sessions", pruned)
+	}
+	return pruned, nil
+}
+
+// authorize returns the live session if the relay is on and pin unlocks it
+func (uc *FileUseCase) authorize(token, pin string) (*entities.Session, error) {
+	if uc.limit <= 0 {
+		return nil, ErrFileRelayDisabled
+	}
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := unlockSession(uc.sessionRepo, session, pin); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// mediaType keeps a well-formed content type for display, dropping anything else
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// generateFileID generates the random ID of a relayed file
+func generateFileID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,140 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestFileUseCase_ShareFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		request       *dto.ShareFileRequest
+		expectedName  string
+		expectedType  string
+		expectedError error
+	}{
+		{
+			name:         "file relayed",
+			limit:        10,
+			request:      &dto.ShareFileRequest{Token: "test-token", Name: "notes.txt", ContentType: "text/plain; charset=utf-8", Data: []byte("hello")},
+			expectedName: "notes.txt",
+			expectedType: "text/plain",
+		},
+		{
+			name:         "name and type cleaned up",
+			limit:        10,
+			request:      &dto.ShareFileRequest{Token: "test-token", Name: "../secret\n.txt", ContentType: "not a type", Data: []byte("hello")},
+			expectedName: "secret.txt",
+		},
+		{
+			name:          "over the limit",
+			limit:         4,
+			request:       &dto.ShareFileRequest{Token: "test-token", Name: "notes.txt", Data: []byte("hello")},
+			expectedError: ErrFileTooLarge,
+		},
+		{
+			name:          "relay disabled",
+			request:       &dto.ShareFileRequest{Token: "test-token", Name: "notes.txt", Data: []byte("hello")},
+			expectedError: ErrFileRelayDisabled,
+		},
+		{
+			name:          "unknown session",
+			limit:         10,
+			request:       &dto.ShareFileRequest{Token: "missing", Name: "notes.txt", Data: []byte("hello")},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewFileUseCase(mocks.NewMockFileRepository(), mockRepo, mocks.NewMockSessionHistoryRepository(), publisher, tt.limit)
+
+			file, err := useCase.ShareFile(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if file.Name != tt.expectedName || file.ContentType != tt.expectedType || file.Size != 5 || file.ID == "" {
+				t.Errorf("Unexpected file %+v", file)
+			}
+			events := publisher.Published(entities.SessionTopic("test-token"))
+			if len(events) != 1 || events[0].Type != entities.EventFileShared {
+				t.Errorf("Expected a file shared event, got %+v", events)
+			}
+		})
+	}
+}
+
+func TestFileUseCase_LimitCoversSession(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+	useCase := NewFileUseCase(mocks.NewMockFileRepository(), mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher(), 8)
+
+	if _, err := useCase.ShareFile(&dto.ShareFileRequest{Token: "test-token", Name: "a", Data: []byte("12345")}); err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+	if _, err := useCase.ShareFile(&dto.ShareFileRequest{Token: "test-token", Name: "b", Data: []byte("12345")}); err != ErrFileTooLarge {
+		t.Errorf("Expected the second file to exceed the session's limit, got %v", err)
+	}
+}
+
+func TestFileUseCase_GetFile(t *testing.T) {
+	session := mocks.NewTestSession("test-token", false)
+	session.PIN = "123456"
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewFileUseCase(mocks.NewMockFileRepository(), mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher(), 100)
+
+	shared, err := useCase.ShareFile(&dto.ShareFileRequest{Token: "test-token", Name: "notes.txt", Data: []byte("hello")})
+	if err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+
+	if _, err := useCase.ListFiles(&dto.GetFilesRequest{Token: "test-token", PIN: "000000"}); err != ErrInvalidPIN {
+		t.Errorf("Expected ErrInvalidPIN, got %v", err)
+	}
+	listed, err := useCase.ListFiles(&dto.GetFilesRequest{Token: "test-token", PIN: "123456"})
+	if err != nil || len(listed.Files) != 1 || listed.Files[0].ID != shared.ID {
+		t.Fatalf("Expected the shared file to be listed, got %+v, %v", listed, err)
+	}
+
+	file, data, err := useCase.GetFile(&dto.GetFileRequest{Token: "test-token", PIN: "123456", ID: shared.ID})
+	if err != nil || file.Name != "notes.txt" || string(data) != "hello" {
+		t.Errorf("Expected notes.txt with hello, got %+v %q, %v", file, data, err)
+	}
+	if _, _, err := useCase.GetFile(&dto.GetFileRequest{Token: "test-token", PIN: "123456", ID: "missing"}); err != ErrFileNotFound {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+}
+
+func TestFileUseCase_PruneFiles(t *testing.T) {
+	session := mocks.NewTestSession("test-token", false)
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	fileRepo := mocks.NewMockFileRepository()
+	useCase := NewFileUseCase(fileRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher(), 100)
+
+	if _, err := useCase.ShareFile(&dto.ShareFileRequest{Token: "test-token", Name: "notes.txt", Data: []byte("hello")}); err != nil {
+		t.Fatalf("ShareFile failed: %v", err)
+	}
+	_ = fileRepo.SaveFile("gone", &entities.SharedFile{ID: "old", Name: "old.txt"}, nil)
+
+	pruned, err := useCase.PruneFiles()
+	if err != nil || pruned != 1 {
+		t.Fatalf("Expected one session pruned, got %d, %v", pruned, err)
+	}
+	if tokens, _ := fileRepo.GetTokens(); strings.Join(tokens, ",") != "test-token" {
+		t.Errorf("Expected only the live session's files to remain, got %v", tokens)
+	}
+}
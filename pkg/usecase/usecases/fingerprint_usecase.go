@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"log"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// FingerprintUseCase implements the DTLS fingerprint use case interface
+type FingerprintUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+}
+
+// NewFingerprintUseCase creates a new DTLS fingerprint use case
+func NewFingerprintUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository) *FingerprintUseCase {
+	return &FingerprintUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// GetFingerprints returns the DTLS fingerprints announced in the session's
+// stored offer and answer. Viewers of an SFU session connect to the server,
+// not to the sender, so there is nothing for them to compare.
+func (uc *FingerprintUseCase) GetFingerprints(request *dto.GetFingerprintsRequest) (*dto.FingerprintsResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.SenderKey != "" {
+		if !session.CheckSenderKey(request.SenderKey) {
+			log.Printf("🔒 Wrong sender key for the fingerprints of token: %s", entities.ShortToken(request.Token))
+			return nil, ErrInvalidSenderKey
+		}
+	} else if err := unlockSession(uc.sessionRepo, session, request.PIN); err != nil {
+		return nil, err
+	}
+
+	if session.SFU || session.Offer.Fingerprint() == nil {
+		return nil, ErrFingerprintsUnavailable
+	}
+	return &dto.FingerprintsResponse{
+		Sender: session.Offer.Fingerprint(),
+		Viewer: session.Answer.Fingerprint(),
+	}, nil
+}
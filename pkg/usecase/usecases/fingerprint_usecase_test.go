@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+const (
+	testSenderFingerprint = "4A:AD:B9:B1:3F:82:18:3B:54:02:12:DF:3E:5D:49:6B:19:E5:7C:AB:3F:E5:E6:1B:B1:C5:A1:8D:11:36:F3:2E"
+	testViewerFingerprint = "0B:8E:1F:2A:77:C4:90:D3:5E:6F:A2:14:C8:3B:9D:E0:41:7A:58:B6:2C:F9:03:6D:AE:15:87:4F:C2:39:E6:D1"
+)
+
+func TestFingerprintUseCase_GetFingerprints(t *testing.T) {
+	tests := []struct {
+		name           string
+		sfu            bool
+		answered       bool
+		request        *dto.GetFingerprintsRequest
+		expectedViewer bool
+		expectedError  error
+	}{
+		{
+			name:           "viewer with the PIN",
+			answered:       true,
+			request:        &dto.GetFingerprintsRequest{Token: "test-token", PIN: "123456"},
+			expectedViewer: true,
+		},
+		{
+			name:    "sender with its key, before an answer",
+			request: &dto.GetFingerprintsRequest{Token: "test-token", SenderKey: "sender-key"},
+		},
+		{
+			name:          "wrong sender key",
+			request:       &dto.GetFingerprintsRequest{Token: "test-token", SenderKey: "guess", PIN: "123456"},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "wrong PIN",
+			request:       &dto.GetFingerprintsRequest{Token: "test-token", PIN: "000000"},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "SFU session",
+			sfu:           true,
+			request:       &dto.GetFingerprintsRequest{Token: "test-token", PIN: "123456"},
+			expectedError: ErrFingerprintsUnavailable,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.GetFingerprintsRequest{Token: "missing", PIN: "123456"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.SFU = tt.sfu
+			session.SenderKey = "sender-key"
+			session.PIN = "123456"
+			session.Offer = &entities.WebRTCOffer{Type: "offer", SDP: testSDP("offer") + "a=fingerprint:sha-256 " + testSenderFingerprint + "\r\n"}
+			if tt.answered {
+				session.Answer = &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("answer") + "a=fingerprint:sha-256 " + testViewerFingerprint + "\r\n"}
+			}
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			useCase := NewFingerprintUseCase(mockRepo, mocks.NewMockSessionHistoryRepository())
+
+			response, err := useCase.GetFingerprints(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if response.Sender == nil || response.Sender.Value != testSenderFingerprint {
+				t.Errorf("Expected the sender's fingerprint, got %+v", response.Sender)
+			}
+			if got := response.Viewer != nil && response.Viewer.Value == testViewerFingerprint; got != tt.expectedViewer {
+				t.Errorf("Expected the viewer's fingerprint %v, got %+v", tt.expectedViewer, response.Viewer)
+			}
+		})
+	}
+}
+
+func TestFingerprintUseCase_GetFingerprintsWithoutOffer(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+	useCase := NewFingerprintUseCase(mockRepo, mocks.NewMockSessionHistoryRepository())
+
+	if _, err := useCase.GetFingerprints(&dto.GetFingerprintsRequest{Token: "test-token"}); err != ErrFingerprintsUnavailable {
+		t.Errorf("Expected ErrFingerprintsUnavailable before the sender's offer, got %v", err)
+	}
+}
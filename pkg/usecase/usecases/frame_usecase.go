@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"log"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// FrameUseCase implements the frame use case interface
+type FrameUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	relay       interfaces.StreamRelay
+}
+
+// NewFrameUseCase creates a new frame use case; relay is nil when the server
+// runs no SFU, which leaves no video to take snapshots of
+func NewFrameUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, relay interfaces.StreamRelay) *FrameUseCase {
+	return &FrameUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		relay:       relay,
+	}
+}
+
+// WatchFrames streams JPEG snapshots of a live SFU session's screen, taken
+// by the relay from the video it forwards
+func (uc *FrameUseCase) WatchFrames(request *dto.WatchFramesRequest) (*dto.FrameStream, error) {
+	session, err := uc.relayedSession(request.Token, request.PIN)
+	if err != nil {
+		return nil, err
+	}
+
+	frames, stop, err := uc.relay.WatchFrames(session.Token)
+	if err != nil {
+		log.Printf("❌ Watching frames failed for token: %s: %v", entities.ShortToken(session.Token), err)
+		return nil, ErrFramesUnavailable
+	}
+	return &dto.FrameStream{Frames: frames, Stop: stop}, nil
+}
+
+// Snapshot returns a PNG of a live SFU session's screen, decoded by the relay
+// from the latest keyframe of the video it forwards
+func (uc *FrameUseCase) Snapshot(request *dto.SnapshotRequest) ([]byte, error) {
+	session, err := uc.relayedSession(request.Token, request.PIN)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := uc.relay.Snapshot(session.Token)
+	if err != nil {
+		log.Printf("❌ Snapshot failed for token: %s: %v", entities.ShortToken(session.Token), err)
+		return nil, ErrFramesUnavailable
+	}
+	return snapshot, nil
+}
+
+// relayedSession returns a live session whose video the relay forwards and
+// can decode, checking the PIN of protected sessions
+func (uc *FrameUseCase) relayedSession(token, pin string) (*entities.Session, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := unlockSession(uc.sessionRepo, session, pin); err != nil {
+		return nil, err
+	}
+	if !session.SFU || uc.relay == nil {
+		return nil, ErrSFUDisabled
+	}
+	if session.E2EE {
+		// The relay forwards only ciphertext it cannot decode
+		return nil, ErrFramesUnavailable
+	}
+	return session, nil
+}
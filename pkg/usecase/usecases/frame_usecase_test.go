@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestFrameUseCase_WatchFrames(t *testing.T) {
+	tests := []struct {
+		name          string
+		pin           string
+		sfu           bool
+		published     bool
+		noRelay       bool
+		e2ee          bool
+		request       *dto.WatchFramesRequest
+		expectedError error
+	}{
+		{
+			name:      "published SFU session",
+			sfu:       true,
+			published: true,
+			request:   &dto.WatchFramesRequest{Token: "test-token"},
+		},
+		{
+			name:      "PIN",
+			pin:       "123456",
+			sfu:       true,
+			published: true,
+			request:   &dto.WatchFramesRequest{Token: "test-token", PIN: "123456"},
+		},
+		{
+			name:          "wrong PIN",
+			pin:           "123456",
+			sfu:           true,
+			published:     true,
+			request:       &dto.WatchFramesRequest{Token: "test-token", PIN: "000000"},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "peer-to-peer session",
+			request:       &dto.WatchFramesRequest{Token: "test-token"},
+			expectedError: ErrSFUDisabled,
+		},
+		{
+			name:          "server without SFU",
+			sfu:           true,
+			noRelay:       true,
+			request:       &dto.WatchFramesRequest{Token: "test-token"},
+			expectedError: ErrSFUDisabled,
+		},
+		{
+			name:          "nothing published yet",
+			sfu:           true,
+			request:       &dto.WatchFramesRequest{Token: "test-token"},
+			expectedError: ErrFramesUnavailable,
+		},
+		{
+			name:          "end-to-end encrypted session",
+			sfu:           true,
+			published:     true,
+			e2ee:          true,
+			request:       &dto.WatchFramesRequest{Token: "test-token"},
+			expectedError: ErrFramesUnavailable,
+		},
+		{
+			name:          "unknown session",
+			sfu:           true,
+			request:       &dto.WatchFramesRequest{Token: "missing"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.PIN = tt.pin
+			session.SFU = tt.sfu
+			session.E2EE = tt.e2ee
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			relay := mocks.NewMockStreamRelay()
+			relay.Frame = []byte("jpeg")
+			if tt.published {
+				if _, err := relay.Publish("test-token", nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			useCase := NewFrameUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), relay)
+			if tt.noRelay {
+				useCase = NewFrameUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+			}
+
+			stream, err := useCase.WatchFrames(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if frame := <-stream.Frames; string(frame) != "jpeg" {
+				t.Errorf("Expected the relay's snapshot, got %q", frame)
+			}
+			stream.Stop()
+			if _, ok := <-stream.Frames; ok {
+				t.Error("Expected the frames to end on stop")
+			}
+		})
+	}
+}
+
+func TestFrameUseCase_Snapshot(t *testing.T) {
+	tests := []struct {
+		name          string
+		pin           string
+		sfu           bool
+		published     bool
+		request       *dto.SnapshotRequest
+		expectedError error
+	}{
+		{
+			name:      "published SFU session",
+			sfu:       true,
+			published: true,
+			request:   &dto.SnapshotRequest{Token: "test-token"},
+		},
+		{
+			name:          "wrong PIN",
+			pin:           "123456",
+			sfu:           true,
+			published:     true,
+			request:       &dto.SnapshotRequest{Token: "test-token"},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "peer-to-peer session",
+			request:       &dto.SnapshotRequest{Token: "test-token"},
+			expectedError: ErrSFUDisabled,
+		},
+		{
+			name:          "nothing published yet",
+			sfu:           true,
+			request:       &dto.SnapshotRequest{Token: "test-token"},
+			expectedError: ErrFramesUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.PIN = tt.pin
+			session.SFU = tt.sfu
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			relay := mocks.NewMockStreamRelay()
+			relay.Frame = []byte("png")
+			if tt.published {
+				if _, err := relay.Publish("test-token", nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			useCase := NewFrameUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), relay)
+
+			snapshot, err := useCase.Snapshot(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && string(snapshot) != "png" {
+				t.Errorf("Expected the relay's snapshot, got %q", snapshot)
+			}
+		})
+	}
+}
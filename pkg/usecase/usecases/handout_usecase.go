@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// handoutQRSize is the pixel size of the QR code printed on handouts
+const handoutQRSize = 320
+
+// HandoutUseCase implements the handout use case interface
+type HandoutUseCase struct {
+	sessionRepo   interfaces.SessionRepository
+	historyRepo   interfaces.SessionHistoryRepository
+	qrCodeService interfaces.QRCodeService
+	urls          viewerURLs
+}
+
+// NewHandoutUseCase creates a new handout use case; publicHost is the domain
+// the server's certificate is for, if any, and externalURL the base URL
+// viewers outside the LAN use, if any
+func NewHandoutUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, networkService interfaces.NetworkService, qrCodeService interfaces.QRCodeService, publicHost, externalURL string) *HandoutUseCase {
+	return &HandoutUseCase{
+		sessionRepo:   sessionRepo,
+		historyRepo:   historyRepo,
+		qrCodeService: qrCodeService,
+		urls:          newViewerURLs(networkService, publicHost, externalURL),
+	}
+}
+
+// GetHandout returns the joining details for a live session. The viewer URL
+// uses the LAN IP so it works when the sender opened the server on localhost,
+// the public host when the certificate is for that, or the external URL
+// when viewers come from outside the LAN; the PIN is never included, the
+// sender page supplies it.
+func (uc *HandoutUseCase) GetHandout(request *dto.GetHandoutRequest) (*dto.HandoutResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerURL := uc.urls.viewerURL(session.Token, request.Scheme, request.Host)
+	qrCode, err := uc.qrCodeService.PNG(viewerURL.String(), handoutQRSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.HandoutResponse{
+		Name:        session.Name,
+		ViewerURL:   viewerURL.String(),
+		QRCode:      qrCode,
+		ValidFrom:   session.CreatedAt,
+		ValidUntil:  session.ExpiresAt,
+		PINRequired: session.PIN != "",
+	}, nil
+}
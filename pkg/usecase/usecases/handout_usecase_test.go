@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestHandoutUseCase_GetHandout(t *testing.T) {
+	tests := []struct {
+		name          string
+		host          string
+		lanIP         string
+		publicHost    string
+		externalURL   string
+		pin           string
+		expectedURL   string
+		expectedError error
+	}{
+		{
+			name:        "localhost replaced by LAN IP",
+			host:        "localhost:8080",
+			lanIP:       "192.168.1.100",
+			expectedURL: "http://192.168.1.100:8080/viewer?token=test-token",
+		},
+		{
+			name:        "host kept without LAN IP",
+			host:        "share.local:8080",
+			expectedURL: "http://share.local:8080/viewer?token=test-token",
+		},
+		{
+			name:        "PIN is not part of the URL",
+			host:        "localhost:8080",
+			lanIP:       "10.0.0.5",
+			pin:         "123456",
+			expectedURL: "http://10.0.0.5:8080/viewer?token=test-token",
+		},
+		{
+			name:        "IPv6 LAN IP bracketed",
+			host:        "localhost:8080",
+			lanIP:       "fd00::10",
+			expectedURL: "http://[fd00::10]:8080/viewer?token=test-token",
+		},
+		{
+			name:        "IPv6 LAN IP bracketed without port",
+			host:        "localhost",
+			lanIP:       "2001:db8::10",
+			expectedURL: "http://[2001:db8::10]/viewer?token=test-token",
+		},
+		{
+			name:        "external URL used for viewers outside the LAN",
+			host:        "localhost:8443",
+			lanIP:       "192.168.1.100",
+			externalURL: "https://share.example.com:9443/",
+			expectedURL: "https://share.example.com:9443/viewer?token=test-token",
+		},
+		{
+			name:        "public host preferred over LAN IP",
+			host:        "localhost:8443",
+			lanIP:       "192.168.1.100",
+			publicHost:  "share.example.com",
+			expectedURL: "http://share.example.com:8443/viewer?token=test-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.PIN = tt.pin
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+
+			networkService := mocks.NewMockNetworkService()
+			networkService.SetLANIP(tt.lanIP)
+			qrCodeService := mocks.NewMockQRCodeService()
+
+			useCase := NewHandoutUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), networkService, qrCodeService, tt.publicHost, tt.externalURL)
+
+			handout, err := useCase.GetHandout(&dto.GetHandoutRequest{Token: "test-token", Scheme: "http", Host: tt.host})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if handout.ViewerURL != tt.expectedURL {
+				t.Errorf("Expected viewer URL %s, got %s", tt.expectedURL, handout.ViewerURL)
+			}
+			if qrCodeService.LastContent != tt.expectedURL {
+				t.Errorf("Expected QR code for %s, got %s", tt.expectedURL, qrCodeService.LastContent)
+			}
+			if handout.PINRequired != (tt.pin != "") {
+				t.Errorf("Expected PIN required %v, got %v", tt.pin != "", handout.PINRequired)
+			}
+			if !handout.ValidUntil.Equal(session.ExpiresAt) {
+				t.Errorf("Expected validity until %v, got %v", session.ExpiresAt, handout.ValidUntil)
+			}
+		})
+	}
+}
+
+func TestHandoutUseCase_GetHandout_Errors(t *testing.T) {
+	qrCodeService := mocks.NewMockQRCodeService()
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewHandoutUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockNetworkService(), qrCodeService, "", "")
+
+	if _, err := useCase.GetHandout(&dto.GetHandoutRequest{Token: "missing"}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+
+	mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+	qrCodeService.ShouldFail = true
+	if _, err := useCase.GetHandout(&dto.GetHandoutRequest{Token: "test-token", Scheme: "http", Host: "localhost"}); err == nil {
+		t.Error("Expected QR code error")
+	}
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"log"
+	"unicode/utf8"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxNotesLength limits the notes a sender can attach to a finished session
+const maxNotesLength = 2000
+
+// SessionHistoryUseCase implements the session history use case interface
+type SessionHistoryUseCase struct {
+	historyRepo interfaces.SessionHistoryRepository
+}
+
+// NewSessionHistoryUseCase creates a new session history use case
+func NewSessionHistoryUseCase(historyRepo interfaces.SessionHistoryRepository) *SessionHistoryUseCase {
+	return &SessionHistoryUseCase{
+		historyRepo: historyRepo,
+	}
+}
+
+// GetSessionSummary returns the summary of a finished session
+func (uc *SessionHistoryUseCase) GetSessionSummary(request *dto.GetSessionSummaryRequest) (*dto.SessionSummaryResponse, error) {
+	record, err := uc.historyRepo.GetRecord(request.Token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return toSessionSummary(record), nil
+}
+
+// UpdateSessionNotes replaces the notes stored with a finished session; only
+// its sender may
+func (uc *SessionHistoryUseCase) UpdateSessionNotes(request *dto.UpdateSessionNotesRequest) (*dto.SessionSummaryResponse, error) {
+	if utf8.RuneCountInString(request.Notes) > maxNotesLength {
+		return nil, ErrInvalidNotes
+	}
+
+	record, err := uc.historyRepo.GetRecord(request.Token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if !record.CheckSender(request.SenderKey, request.Owner) {
+		log.Printf("🔒 Notes refused for token: %s", entities.ShortToken(request.Token))
+		return nil, ErrInvalidSenderKey
+	}
+
+	record.Notes = request.Notes
+	if err := uc.historyRepo.SaveRecord(record); err != nil {
+		log.Printf("❌ Error saving session notes: %v", err)
+		return nil, err
+	}
+
+	log.Printf("📝 Notes updated for token: %s", entities.ShortToken(request.Token))
+	return toSessionSummary(record), nil
+}
+
+// toSessionSummary converts a history record to its response form
+func toSessionSummary(record *entities.SessionRecord) *dto.SessionSummaryResponse {
+	return &dto.SessionSummaryResponse{
+		Token:           record.Token,
+		Name:            record.Name,
+		StartedAt:       record.StartedAt,
+		EndedAt:         record.EndedAt,
+		DurationSeconds: int64(record.Duration().Seconds()),
+		PeakViewers:     record.PeakViewers,
+		AverageBitrate:  record.AverageBitrate(),
+		RecordingURL:    record.RecordingURL,
+		Notes:           record.Notes,
+	}
+}
@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestSessionHistoryUseCase_GetSessionSummary(t *testing.T) {
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	start := time.Now().Add(-10 * time.Minute)
+	historyRepo.SaveRecord(&entities.SessionRecord{
+		Token:       "test-token",
+		Name:        "Demo",
+		StartedAt:   start,
+		EndedAt:     start.Add(100 * time.Second),
+		PeakViewers: 3,
+		BytesSent:   1250000,
+	})
+
+	useCase := NewSessionHistoryUseCase(historyRepo)
+
+	summary, err := useCase.GetSessionSummary(&dto.GetSessionSummaryRequest{Token: "test-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.DurationSeconds != 100 {
+		t.Errorf("Expected duration 100s, got %d", summary.DurationSeconds)
+	}
+	if summary.AverageBitrate != 100000 {
+		t.Errorf("Expected average bitrate 100000, got %d", summary.AverageBitrate)
+	}
+	if summary.PeakViewers != 3 {
+		t.Errorf("Expected 3 peak viewers, got %d", summary.PeakViewers)
+	}
+
+	if _, err := useCase.GetSessionSummary(&dto.GetSessionSummaryRequest{Token: "missing"}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestSessionHistoryUseCase_UpdateSessionNotes(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.UpdateSessionNotesRequest
+		expectedError error
+	}{
+		{
+			name:    "notes saved with the sender key",
+			request: &dto.UpdateSessionNotesRequest{Token: "test-token", Notes: "Walked through the release plan", SenderKey: "sender-key"},
+		},
+		{
+			name:    "notes saved from the sender's browser",
+			request: &dto.UpdateSessionNotesRequest{Token: "test-token", Notes: "Walked through the release plan", Owner: "owner-id"},
+		},
+		{
+			name:          "viewer token alone",
+			request:       &dto.UpdateSessionNotesRequest{Token: "test-token", Notes: "defaced"},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "wrong sender key and browser",
+			request:       &dto.UpdateSessionNotesRequest{Token: "test-token", Notes: "defaced", SenderKey: "wrong-key", Owner: "other-owner"},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "notes too long",
+			request:       &dto.UpdateSessionNotesRequest{Token: "test-token", Notes: strings.Repeat("a", maxNotesLength+1), SenderKey: "sender-key"},
+			expectedError: ErrInvalidNotes,
+		},
+		{
+			name:          "session not found",
+			request:       &dto.UpdateSessionNotesRequest{Token: "missing", Notes: "hello", SenderKey: "sender-key"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			historyRepo := mocks.NewMockSessionHistoryRepository()
+			historyRepo.SaveRecord(&entities.SessionRecord{Token: "test-token", SenderKey: "sender-key", Owner: "owner-id"})
+			useCase := NewSessionHistoryUseCase(historyRepo)
+
+			response, err := useCase.UpdateSessionNotes(tt.request)
+
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError == nil && response.Notes != tt.request.Notes {
+				t.Errorf("Expected notes %q, got %q", tt.request.Notes, response.Notes)
+			}
+			if record, _ := historyRepo.GetRecord("test-token"); tt.expectedError != nil && record.Notes != "" {
+				t.Errorf("Expected the notes unchanged, got %q", record.Notes)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_EndSession_RecordsHistory(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:       "test-token",
+		CreatedAt:   time.Now().Add(-time.Minute),
+		ExpiresAt:   time.Now().Add(30 * time.Minute),
+		Status:      entities.SessionStatusActive,
+		PeakViewers: 2,
+		SenderKey:   "sender-key",
+	})
+
+	useCase := NewSessionUseCase(mockRepo, historyRepo, mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: "test-token", SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	record, err := historyRepo.GetRecord("test-token")
+	if err != nil {
+		t.Fatalf("Expected history record: %v", err)
+	}
+	if record.PeakViewers != 2 {
+		t.Errorf("Expected 2 peak viewers, got %d", record.PeakViewers)
+	}
+	if record.EndedAt.IsZero() {
+		t.Error("Expected end time to be recorded")
+	}
+}
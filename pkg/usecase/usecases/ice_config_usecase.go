@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// ICEConfigUseCase implements the ICE config use case interface
+type ICEConfigUseCase struct {
+	sessionRepo     interfaces.SessionRepository
+	historyRepo     interfaces.SessionHistoryRepository
+	iceServers      []entities.ICEServer
+	turnCredentials interfaces.TURNCredentialService
+}
+
+// NewICEConfigUseCase creates a new ICE config use case serving iceServers.
+// TURN servers listed without credentials get ones minted by turnCredentials,
+// which may be nil when every server carries its own.
+func NewICEConfigUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, iceServers []entities.ICEServer, turnCredentials interfaces.TURNCredentialService) *ICEConfigUseCase {
+	return &ICEConfigUseCase{
+		sessionRepo:     sessionRepo,
+		historyRepo:     historyRepo,
+		iceServers:      iceServers,
+		turnCredentials: turnCredentials,
+	}
+}
+
+// GetICEConfig returns the STUN and TURN servers for the peers of a live
+// session. TURN credentials are only handed to those who know the session's
+// token and, for protected sessions, its PIN.
+func (uc *ICEConfigUseCase) GetICEConfig(request *dto.GetICEConfigRequest) (*dto.ICEConfigResponse, error) {
+	if err := uc.authorize(request); err != nil {
+		return nil, err
+	}
+
+	var minted *entities.TURNCredential
+	servers := make([]entities.ICEServer, 0, len(uc.iceServers))
+	for _, server := range uc.iceServers {
+		server.URLs = append([]string(nil), server.URLs...)
+		if server.NeedsCredentials() {
+			if uc.turnCredentials == nil {
+				continue
+			}
+			if minted == nil {
+				var err error
+				if minted, err = uc.mint(); err != nil {
+					return nil, err
+				}
+			}
+			server.Username = minted.Username
+			server.Credential = minted.Credential
+		}
+		servers = append(servers, server)
+	}
+	return &dto.ICEConfigResponse{ICEServers: servers}, nil
+}
+
+// GetTURNCredentials mints a short-lived credential for the TURN servers
+// listed without one, for peers that build their own RTCIceServer
+func (uc *ICEConfigUseCase) GetTURNCredentials(request *dto.GetICEConfigRequest) (*dto.TURNCredentialsResponse, error) {
+	if err := uc.authorize(request); err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, server := range uc.iceServers {
+		if server.NeedsCredentials() {
+			urls = append(urls, server.URLs...)
+		}
+	}
+	if uc.turnCredentials == nil || len(urls) == 0 {
+		return nil, ErrTURNNotConfigured
+	}
+
+	credential, err := uc.mint()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TURNCredentialsResponse{
+		URLs:       urls,
+		Username:   credential.Username,
+		Credential: credential.Credential,
+		TTL:        int(time.Until(credential.ExpiresAt).Seconds()),
+		ExpiresAt:  credential.ExpiresAt,
+	}, nil
+}
+
+// authorize checks that the request names a live session and unlocks it
+func (uc *ICEConfigUseCase) authorize(request *dto.GetICEConfigRequest) error {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	return unlockSession(uc.sessionRepo, session, request.PIN)
+}
+
+// mint creates a TURN credential under a random user name, so relay logs
+// tell peers apart without learning session tokens
+func (uc *ICEConfigUseCase) mint() (*entities.TURNCredential, error) {
+	user, err := generateViewerID()
+	if err != nil {
+		return nil, err
+	}
+	return uc.turnCredentials.Mint(user), nil
+}
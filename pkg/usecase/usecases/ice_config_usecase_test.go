@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestICEConfigUseCase_GetICEConfig(t *testing.T) {
+	iceServers := []entities.ICEServer{
+		{URLs: []string{"stun:stun.example.com:3478"}},
+		{URLs: []string{"turn:turn.example.com:3478?transport=udp", "turns:turn.example.com:5349"}, Username: "user", Credential: "turn-pass"},
+	}
+
+	ended := mocks.NewTestSession("test-token", false)
+	ended.Token = "ended-token"
+	ended.End()
+
+	protected := mocks.NewTestSession("test-token", false)
+	protected.Token = "protected-token"
+	protected.PIN = "123456"
+
+	tests := []struct {
+		name          string
+		request       *dto.GetICEConfigRequest
+		expectedError error
+	}{
+		{
+			name:    "live session",
+			request: &dto.GetICEConfigRequest{Token: "test-token"},
+		},
+		{
+			name:    "protected session with PIN",
+			request: &dto.GetICEConfigRequest{Token: "protected-token", PIN: "123456"},
+		},
+		{
+			name:          "protected session without PIN",
+			request:       &dto.GetICEConfigRequest{Token: "protected-token"},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "ended session",
+			request:       &dto.GetICEConfigRequest{Token: "ended-token"},
+			expectedError: ErrSessionEnded,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.GetICEConfigRequest{Token: "missing-token"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+			mockRepo.SetSession(ended)
+			mockRepo.SetSession(protected)
+			useCase := NewICEConfigUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), iceServers, nil)
+
+			response, err := useCase.GetICEConfig(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(response.ICEServers) != 2 || response.ICEServers[1].Credential != "turn-pass" {
+				t.Fatalf("Expected both servers with credentials, got %+v", response.ICEServers)
+			}
+
+			// Callers get their own copy of the configuration
+			response.ICEServers[0].URLs[0] = "stun:changed"
+			if iceServers[0].URLs[0] != "stun:stun.example.com:3478" {
+				t.Error("Expected the configured servers to be left untouched")
+			}
+		})
+	}
+}
+
+func TestICEConfigUseCase_MintedTURNCredentials(t *testing.T) {
+	iceServers := []entities.ICEServer{
+		{URLs: []string{"stun:stun.example.com:3478"}},
+		{URLs: []string{"turn:turn.example.com:3478", "turns:turn.example.com:5349"}},
+		{URLs: []string{"turn:static.example.com:3478"}, Username: "user", Credential: "turn-pass"},
+	}
+
+	tests := []struct {
+		name              string
+		minter            bool
+		expectedServers   int
+		expectedError     error
+		expectedMintCalls int
+	}{
+		{
+			name:              "credentials minted from the shared secret",
+			minter:            true,
+			expectedServers:   3,
+			expectedMintCalls: 2,
+		},
+		{
+			name:            "no shared secret",
+			expectedServers: 2,
+			expectedError:   ErrTURNNotConfigured,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+			minter := mocks.NewMockTURNCredentialService()
+			var service interfaces.TURNCredentialService
+			if tt.minter {
+				service = minter
+			}
+			useCase := NewICEConfigUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), iceServers, service)
+			request := &dto.GetICEConfigRequest{Token: "test-token"}
+
+			config, err := useCase.GetICEConfig(request)
+			if err != nil {
+				t.Fatalf("GetICEConfig failed: %v", err)
+			}
+			if len(config.ICEServers) != tt.expectedServers {
+				t.Fatalf("Expected %d servers, got %+v", tt.expectedServers, config.ICEServers)
+			}
+			for _, server := range config.ICEServers {
+				if server.IsTURN() && (server.Username == "" || server.Credential == "") {
+					t.Errorf("Expected every TURN server to carry credentials, got %+v", server)
+				}
+			}
+			if iceServers[1].Username != "" {
+				t.Error("Expected the configured servers to be left untouched")
+			}
+
+			credentials, err := useCase.GetTURNCredentials(request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if len(minter.Users) != tt.expectedMintCalls {
+				t.Errorf("Expected %d credentials minted, got %d", tt.expectedMintCalls, len(minter.Users))
+			}
+			if err != nil {
+				return
+			}
+
+			if len(credentials.URLs) != 2 || credentials.URLs[0] != "turn:turn.example.com:3478" {
+				t.Errorf("Expected the URLs of the servers without credentials, got %v", credentials.URLs)
+			}
+			if credentials.Credential != "minted-credential" || credentials.Username != "minted:"+minter.Users[1] {
+				t.Errorf("Unexpected credential %+v", credentials)
+			}
+			if credentials.TTL <= 0 || credentials.TTL > int(time.Hour.Seconds()) {
+				t.Errorf("Expected a TTL within the hour, got %d", credentials.TTL)
+			}
+		})
+	}
+}
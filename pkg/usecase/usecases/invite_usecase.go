@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// InviteUseCase implements the invite use case interface
+type InviteUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	notifiers   []interfaces.InviteNotifier
+	urls        viewerURLs
+
+	// postMu keeps a double click from posting a session's invite twice
+	postMu sync.Mutex
+}
+
+// NewInviteUseCase creates a new invite use case posting to notifiers, none
+// when no chat webhook is configured; publicHost and externalURL are as for
+// NewHandoutUseCase
+func NewInviteUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, notifiers []interfaces.InviteNotifier, networkService interfaces.NetworkService, publicHost, externalURL string) *InviteUseCase {
+	return &InviteUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		notifiers:   notifiers,
+		urls:        newViewerURLs(networkService, publicHost, externalURL),
+	}
+}
+
+// PostInvite posts the viewer link of a live session to every configured
+// chat channel; only the session's sender may, and only once per session,
+// so a reloaded sender page does not post again. The PIN is never posted.
+// The invite counts as posted when any channel took it.
+func (uc *InviteUseCase) PostInvite(request *dto.PostInviteRequest) (*dto.PostInviteResponse, error) {
+	if len(uc.notifiers) == 0 {
+		return nil, ErrInvitesDisabled
+	}
+
+	uc.postMu.Lock()
+	defer uc.postMu.Unlock()
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.CheckSenderKey(request.SenderKey) {
+		log.Printf("🔒 Wrong sender key for the invite of token: %s", entities.ShortToken(request.Token))
+		return nil, ErrInvalidSenderKey
+	}
+	if !session.InvitedAt.IsZero() {
+		return nil, ErrInviteAlreadyPosted
+	}
+
+	invite := &entities.Invite{
+		Name:        session.Name,
+		ViewerURL:   uc.urls.viewerURL(session.Token, request.Scheme, request.Host).String(),
+		PINRequired: session.PIN != "",
+		ExpiresAt:   session.ExpiresAt,
+	}
+	if path := viewerAliasPath(session); path != "" {
+		invite.ShortURL = uc.urls.base(request.Scheme, request.Host).JoinPath(path).String()
+	}
+
+	var services []string
+	for _, notifier := range uc.notifiers {
+		if err := notifier.PostInvite(invite); err != nil {
+			log.Printf("❌ Error posting invite to %s: %v", notifier.Service(), err)
+			continue
+		}
+		services = append(services, notifier.Service())
+	}
+	if len(services) == 0 {
+		return nil, ErrInviteNotPosted
+	}
+
+	session.InvitedAt = time.Now()
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error saving invite: %v", err)
+		return nil, err
+	}
+
+	log.Printf("📣 Invite posted to %v for token: %s", services, entities.ShortToken(request.Token))
+	return &dto.PostInviteResponse{
+		Services:  services,
+		ViewerURL: invite.ViewerURL,
+		PostedAt:  session.InvitedAt,
+	}, nil
+}
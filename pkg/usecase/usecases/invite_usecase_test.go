@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestInviteUseCase_PostInvite(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.PostInviteRequest
+		pin           string
+		alias         string
+		externalURL   string
+		expectedURL   string
+		expectedShort string
+		expectedError error
+	}{
+		{
+			name:        "posted with the LAN viewer URL",
+			request:     &dto.PostInviteRequest{Token: "test-token", SenderKey: "sender-key", Scheme: "http", Host: "localhost:8080"},
+			expectedURL: "http://192.168.1.100:8080/viewer?token=test-token",
+		},
+		{
+			name:          "short URL included",
+			request:       &dto.PostInviteRequest{Token: "test-token", SenderKey: "sender-key", Scheme: "http", Host: "localhost:8080"},
+			alias:         "BlueTiger42",
+			expectedURL:   "http://192.168.1.100:8080/viewer?token=test-token",
+			expectedShort: "http://192.168.1.100:8080/v/BlueTiger42",
+		},
+		{
+			name:          "external URL used",
+			request:       &dto.PostInviteRequest{Token: "test-token", SenderKey: "sender-key", Scheme: "http", Host: "localhost:8080"},
+			alias:         "BlueTiger42",
+			externalURL:   "https://share.example.com/screens",
+			expectedURL:   "https://share.example.com/screens/viewer?token=test-token",
+			expectedShort: "https://share.example.com/screens/v/BlueTiger42",
+		},
+		{
+			name:        "PIN kept out of the invite",
+			request:     &dto.PostInviteRequest{Token: "test-token", SenderKey: "sender-key", Scheme: "http", Host: "localhost:8080"},
+			pin:         "123456",
+			expectedURL: "http://192.168.1.100:8080/viewer?token=test-token",
+		},
+		{
+			name:          "wrong sender key",
+			request:       &dto.PostInviteRequest{Token: "test-token", SenderKey: "guess"},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.PostInviteRequest{Token: "missing", SenderKey: "sender-key"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.SenderKey = "sender-key"
+			session.PIN = tt.pin
+			session.Alias = tt.alias
+			sessionRepo := mocks.NewMockSessionRepository()
+			sessionRepo.SetSession(session)
+			slack, discord := mocks.NewMockInviteNotifier("Slack"), mocks.NewMockInviteNotifier("Discord")
+			useCase := NewInviteUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), []interfaces.InviteNotifier{slack, discord}, mocks.NewMockNetworkService(), "", tt.externalURL)
+
+			response, err := useCase.PostInvite(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				if len(slack.Invites) != 0 {
+					t.Error("Expected nothing posted")
+				}
+				return
+			}
+
+			if len(response.Services) != 2 || response.ViewerURL != tt.expectedURL {
+				t.Errorf("Unexpected response %+v", response)
+			}
+			for _, notifier := range []*mocks.MockInviteNotifier{slack, discord} {
+				if len(notifier.Invites) != 1 {
+					t.Fatalf("Expected one invite posted to %s, got %d", notifier.Name, len(notifier.Invites))
+				}
+				invite := notifier.Invites[0]
+				if invite.ViewerURL != tt.expectedURL || invite.ShortURL != tt.expectedShort {
+					t.Errorf("Unexpected invite %+v", invite)
+				}
+				if invite.PINRequired != (tt.pin != "") || !invite.ExpiresAt.Equal(session.ExpiresAt) {
+					t.Errorf("Unexpected invite %+v", invite)
+				}
+			}
+
+			// A reloaded sender page does not post again
+			if _, err := useCase.PostInvite(tt.request); err != ErrInviteAlreadyPosted {
+				t.Errorf("Expected ErrInviteAlreadyPosted, got %v", err)
+			}
+			if len(slack.Invites) != 1 {
+				t.Errorf("Expected the invite posted once, got %d", len(slack.Invites))
+			}
+		})
+	}
+}
+
+func TestInviteUseCase_PostInviteFailures(t *testing.T) {
+	request := &dto.PostInviteRequest{Token: "test-token", SenderKey: "sender-key", Scheme: "http", Host: "localhost:8080"}
+
+	sessionRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SenderKey = "sender-key"
+	sessionRepo.SetSession(session)
+	disabled := NewInviteUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), nil, mocks.NewMockNetworkService(), "", "")
+	if _, err := disabled.PostInvite(request); err != ErrInvitesDisabled {
+		t.Errorf("Expected ErrInvitesDisabled, got %v", err)
+	}
+
+	slack, discord := mocks.NewMockInviteNotifier("Slack"), mocks.NewMockInviteNotifier("Discord")
+	slack.ShouldFail, discord.ShouldFail = true, true
+	useCase := NewInviteUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), []interfaces.InviteNotifier{slack, discord}, mocks.NewMockNetworkService(), "", "")
+	if _, err := useCase.PostInvite(request); err != ErrInviteNotPosted {
+		t.Fatalf("Expected ErrInviteNotPosted, got %v", err)
+	}
+
+	// A channel that took the invite is enough, and the failed one is not
+	// retried later
+	discord.ShouldFail = false
+	response, err := useCase.PostInvite(request)
+	if err != nil {
+		t.Fatalf("Expected the invite posted, got %v", err)
+	}
+	if len(response.Services) != 1 || response.Services[0] != "Discord" {
+		t.Errorf("Expected the invite posted to Discord only, got %v", response.Services)
+	}
+	if _, err := useCase.PostInvite(request); err != ErrInviteAlreadyPosted {
+		t.Errorf("Expected ErrInviteAlreadyPosted, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// KeyExchangeUseCase implements the key exchange relay use case interface
+type KeyExchangeUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	publisher   interfaces.EventPublisher
+
+	// mu keeps the viewers of an SFU session, who send their keys at once,
+	// from losing each other's messages
+	mu sync.Mutex
+}
+
+// NewKeyExchangeUseCase creates a new key exchange relay use case
+func NewKeyExchangeUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher) *KeyExchangeUseCase {
+	return &KeyExchangeUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		publisher:   publisher,
+	}
+}
+
+// SendMessage relays a key message of an end-to-end encrypted session,
+// keeping it for later polls and announcing it on the event stream. Viewers
+// send their public key; the sender, proven by its sender key, the content
+// key wrapped for a viewer and rotations. The server cannot unwrap the keys.
+func (uc *KeyExchangeUseCase) SendMessage(request *dto.SendKeyMessageRequest) (*entities.KeyExchangeMessage, error) {
+	message := entities.KeyExchangeMessage{
+		Type:       request.Type,
+		From:       entities.NegotiationFromViewer,
+		ViewerID:   request.ViewerID,
+		KeyID:      request.KeyID,
+		PublicKey:  request.PublicKey,
+		WrappedKey: request.WrappedKey,
+		IV:         request.IV,
+		SentAt:     time.Now(),
+	}
+	if request.Type != entities.KeyMessagePublicKey {
+		message.From = entities.NegotiationFromSender
+	}
+	if !message.Valid() {
+		return nil, ErrInvalidKeyMessage
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	session, err := uc.authorize(request.Token, request.PIN, request.SenderKey, message.From, request.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	message = session.AddKeyExchangeMessage(message)
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error storing key message: %v", err)
+		return nil, err
+	}
+
+	switch message.Type {
+	case entities.KeyMessageContentKey:
+		uc.publisher.Publish(entities.ViewerTopic(session.Token, message.ViewerID), entities.Event{Type: entities.EventKeyExchange, Data: message})
+	case entities.KeyMessageRotate:
+		log.Printf("🔑 Content key rotated to %d for token: %s", message.KeyID, entities.ShortToken(session.Token))
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventKeyExchange, Data: message})
+	default:
+		// Like negotiation, the session topic learns only that there is
+		// something to fetch, never whose key it is
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventKeyExchange, Data: map[string]int{"seq": message.Seq}})
+	}
+	return &message, nil
+}
+
+// GetMessages returns the key messages relayed after request.Since: every
+// message to the sender, and a viewer's own messages and the rotations to
+// the viewer
+func (uc *KeyExchangeUseCase) GetMessages(request *dto.GetKeyMessagesRequest) (*dto.KeyMessagesResponse, error) {
+	from, viewerID := entities.NegotiationFromViewer, request.ViewerID
+	if request.SenderKey != "" {
+		from, viewerID = entities.NegotiationFromSender, ""
+	}
+
+	session, err := uc.authorize(request.Token, request.PIN, request.SenderKey, from, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.KeyMessagesResponse{Messages: session.KeyExchangeSince(request.Since, viewerID)}, nil
+}
+
+// authorize returns the live end-to-end encrypted session if the sender key
+// proves the sender, or for viewers if pin unlocks it and viewerID was not
+// removed from it
+func (uc *KeyExchangeUseCase) authorize(token, pin, senderKey, from, viewerID string) (*entities.Session, error) {
+	if from == entities.NegotiationFromViewer && viewerID == "" {
+		return nil, ErrMissingViewerID
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.E2EE {
+		return nil, ErrE2EEDisabled
+	}
+
+	if from == entities.NegotiationFromSender {
+		if !session.CheckSenderKey(senderKey) {
+			log.Printf("🔒 Wrong sender key for the key exchange of token: %s", entities.ShortToken(token))
+			return nil, ErrInvalidSenderKey
+		}
+		return session, nil
+	}
+	if err := unlockSession(uc.sessionRepo, session, pin); err != nil {
+		return nil, err
+	}
+	if session.IsRevoked(viewerID) {
+		return nil, ErrViewerRevoked
+	}
+	return session, nil
+}
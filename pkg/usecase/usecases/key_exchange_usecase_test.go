@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestKeyExchangeUseCase_SendMessage(t *testing.T) {
+	publicKey := base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+	wrapped := base64.RawURLEncoding.EncodeToString(make([]byte, 48))
+	iv := base64.RawURLEncoding.EncodeToString(make([]byte, 12))
+	tests := []struct {
+		name          string
+		e2ee          bool
+		request       *dto.SendKeyMessageRequest
+		expectedTopic string
+		expectedError error
+	}{
+		{
+			name:          "viewer public key",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-1", PublicKey: publicKey},
+			expectedTopic: entities.SessionTopic("test-token"),
+		},
+		{
+			name:          "content key for a viewer",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", SenderKey: "sender-key", Type: entities.KeyMessageContentKey, ViewerID: "viewer-1", KeyID: 1, PublicKey: publicKey, WrappedKey: wrapped, IV: iv},
+			expectedTopic: entities.ViewerTopic("test-token", "viewer-1"),
+		},
+		{
+			name:          "rotation",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", SenderKey: "sender-key", Type: entities.KeyMessageRotate, KeyID: 2},
+			expectedTopic: entities.SessionTopic("test-token"),
+		},
+		{
+			name:          "rotation without the sender key",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "123456", Type: entities.KeyMessageRotate, KeyID: 2},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "wrong PIN",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "000000", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-1", PublicKey: publicKey},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "removed viewer",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "removed", PublicKey: publicKey},
+			expectedError: ErrViewerRevoked,
+		},
+		{
+			name:          "invalid message",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-1", PublicKey: "not base64!"},
+			expectedError: ErrInvalidKeyMessage,
+		},
+		{
+			name:          "session not encrypted",
+			request:       &dto.SendKeyMessageRequest{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-1", PublicKey: publicKey},
+			expectedError: ErrE2EEDisabled,
+		},
+		{
+			name:          "unknown session",
+			e2ee:          true,
+			request:       &dto.SendKeyMessageRequest{Token: "missing", SenderKey: "sender-key", Type: entities.KeyMessageRotate, KeyID: 2},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			session.SFU = true
+			session.E2EE = tt.e2ee
+			session.SenderKey = "sender-key"
+			session.PIN = "123456"
+			session.Revoke("removed")
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewKeyExchangeUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), publisher)
+
+			message, err := useCase.SendMessage(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				if events := publisher.Published(entities.SessionTopic("test-token")); len(events) != 0 {
+					t.Errorf("Expected nothing published, got %+v", events)
+				}
+				return
+			}
+
+			if message.Seq != 1 || message.Type != tt.request.Type {
+				t.Errorf("Unexpected message %+v", message)
+			}
+			events := publisher.Published(tt.expectedTopic)
+			if len(events) != 1 || events[0].Type != entities.EventKeyExchange {
+				t.Fatalf("Expected a key exchange event on %s, got %+v", tt.expectedTopic, events)
+			}
+			// Every viewer hears of a public key, so the event must not say
+			// whose it is
+			if message.Type == entities.KeyMessagePublicKey {
+				if data, ok := events[0].Data.(map[string]int); !ok || data["seq"] != 1 {
+					t.Errorf("Expected only the sequence number announced, got %+v", events[0].Data)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyExchangeUseCase_GetMessages(t *testing.T) {
+	publicKey := base64.RawURLEncoding.EncodeToString(make([]byte, 65))
+	wrapped := base64.RawURLEncoding.EncodeToString(make([]byte, 48))
+	iv := base64.RawURLEncoding.EncodeToString(make([]byte, 12))
+	mockRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SFU = true
+	session.E2EE = true
+	session.SenderKey = "sender-key"
+	session.PIN = "123456"
+	mockRepo.SetSession(session)
+	useCase := NewKeyExchangeUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	requests := []*dto.SendKeyMessageRequest{
+		{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-1", PublicKey: publicKey},
+		{Token: "test-token", PIN: "123456", Type: entities.KeyMessagePublicKey, ViewerID: "viewer-2", PublicKey: publicKey},
+		{Token: "test-token", SenderKey: "sender-key", Type: entities.KeyMessageContentKey, ViewerID: "viewer-1", KeyID: 1, PublicKey: publicKey, WrappedKey: wrapped, IV: iv},
+		{Token: "test-token", SenderKey: "sender-key", Type: entities.KeyMessageContentKey, ViewerID: "viewer-2", KeyID: 1, PublicKey: publicKey, WrappedKey: wrapped, IV: iv},
+		{Token: "test-token", SenderKey: "sender-key", Type: entities.KeyMessageRotate, KeyID: 1},
+	}
+	for _, request := range requests {
+		if _, err := useCase.SendMessage(request); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+
+	// The sender reads every message, to wrap the key for each viewer
+	all, err := useCase.GetMessages(&dto.GetKeyMessagesRequest{Token: "test-token", SenderKey: "sender-key"})
+	if err != nil || len(all.Messages) != 5 {
+		t.Fatalf("Expected every message for the sender, got %+v, %v", all, err)
+	}
+	if _, err := useCase.GetMessages(&dto.GetKeyMessagesRequest{Token: "test-token", SenderKey: "guess"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+
+	// A viewer reads only its own key messages and the rotations
+	own, err := useCase.GetMessages(&dto.GetKeyMessagesRequest{Token: "test-token", PIN: "123456", ViewerID: "viewer-2", Since: 2})
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if len(own.Messages) != 2 || own.Messages[0].Seq != 4 || own.Messages[1].Type != entities.KeyMessageRotate {
+		t.Errorf("Expected viewer-2's content key and the rotation, got %+v", own.Messages)
+	}
+	if _, err := useCase.GetMessages(&dto.GetKeyMessagesRequest{Token: "test-token", PIN: "123456"}); err != ErrMissingViewerID {
+		t.Errorf("Expected ErrMissingViewerID, got %v", err)
+	}
+}
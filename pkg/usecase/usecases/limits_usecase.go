@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// LimitsUseCase compares server usage with the configured soft limits and
+// warns the senders of live sessions when usage nears them
+type LimitsUseCase struct {
+	sessionRepo    interfaces.SessionRepository
+	publisher      interfaces.EventPublisher
+	maxSessions    int
+	maxBitrate     int64
+	warningPercent int
+
+	mu sync.Mutex
+	// warned holds, per limit, the sessions already sent the current warning
+	warned map[string]map[string]bool
+}
+
+// NewLimitsUseCase creates a new limits use case; maxSessions and maxBitrate
+// (bits per second) of 0 disable that limit, and warningPercent is the share
+// of a limit at which senders are warned
+func NewLimitsUseCase(sessionRepo interfaces.SessionRepository, publisher interfaces.EventPublisher, maxSessions int, maxBitrate int64, warningPercent int) *LimitsUseCase {
+	return &LimitsUseCase{
+		sessionRepo:    sessionRepo,
+		publisher:      publisher,
+		maxSessions:    maxSessions,
+		maxBitrate:     maxBitrate,
+		warningPercent: warningPercent,
+		warned:         make(map[string]map[string]bool),
+	}
+}
+
+// CheckLimits warns live sessions about every soft limit at or above the
+// warning threshold, once per session, and tells them when usage drops back
+func (uc *LimitsUseCase) CheckLimits() error {
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.maxSessions > 0 {
+		percent := len(sessions) * 100 / uc.maxSessions
+		uc.check(sessions, entities.LimitSessions, percent,
+			fmt.Sprintf("Server at %d%% of session capacity (%d of %d sessions)", percent, len(sessions), uc.maxSessions),
+			"session capacity")
+	}
+
+	if uc.maxBitrate > 0 {
+		var bitrate int64
+		for _, session := range sessions {
+			bitrate += session.Bitrate
+		}
+		percent := int(bitrate * 100 / uc.maxBitrate)
+		uc.check(sessions, entities.LimitBandwidth, percent,
+			fmt.Sprintf("Server at %d%% of its bandwidth limit (%.1f of %d Mbps)", percent, float64(bitrate)/1e6, uc.maxBitrate/1e6),
+			"its bandwidth limit")
+	}
+
+	return nil
+}
+
+// check publishes the warning for one limit to sessions not yet warned, or
+// the all-clear to warned sessions once usage is below the threshold
+func (uc *LimitsUseCase) check(sessions []*entities.Session, limit string, percent int, message, subject string) {
+	warned := uc.warned[limit]
+	if warned == nil {
+		warned = make(map[string]bool)
+		uc.warned[limit] = warned
+	}
+
+	live := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		live[session.Token] = true
+	}
+	for token := range warned {
+		if !live[token] {
+			delete(warned, token)
+		}
+	}
+
+	if percent >= uc.warningPercent {
+		sent := 0
+		for _, session := range sessions {
+			if warned[session.Token] {
+				continue
+			}
+			warned[session.Token] = true
+			uc.publish(session.Token, entities.LimitWarning{Limit: limit, Percent: percent, Message: message})
+			sent++
+		}
+		if sent > 0 {
+			log.Printf("⚠️  %s, warned %d sender(s)", message, sent)
+		}
+		return
+	}
+
+	if len(warned) == 0 {
+		return
+	}
+	cleared := fmt.Sprintf("Server back below %d%% of %s", uc.warningPercent, subject)
+	for token := range warned {
+		uc.publish(token, entities.LimitWarning{Limit: limit, Percent: percent, Message: cleared, Cleared: true})
+	}
+	log.Printf("✅ %s", cleared)
+	uc.warned[limit] = make(map[string]bool)
+}
+
+// publish sends a limit warning to the sender of a session
+func (uc *LimitsUseCase) publish(token string, warning entities.LimitWarning) {
+	uc.publisher.Publish(entities.SessionTopic(token), entities.Event{
+		Type: entities.EventLimitWarning,
+		Data: warning,
+	})
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"fmt"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+// newLimitTestSessions creates n live sessions, each sending at bitrate
+func newLimitTestSessions(sessionRepo *mocks.MockSessionRepository, n int, bitrate int64) []*entities.Session {
+	var sessions []*entities.Session
+	for i := 0; i < n; i++ {
+		session := mocks.NewTestSession("test-token", false)
+		session.Token = fmt.Sprintf("token-%d", i)
+		session.Bitrate = bitrate
+		sessionRepo.SetSession(session)
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// limitWarnings returns the limit warnings published to a session
+func limitWarnings(publisher *mocks.MockEventPublisher, token string) []entities.LimitWarning {
+	var warnings []entities.LimitWarning
+	for _, event := range publisher.Published(entities.SessionTopic(token)) {
+		if event.Type == entities.EventLimitWarning {
+			warnings = append(warnings, event.Data.(entities.LimitWarning))
+		}
+	}
+	return warnings
+}
+
+func TestLimitsUseCase_CheckLimits(t *testing.T) {
+	tests := []struct {
+		name             string
+		sessions         int
+		bitrate          int64
+		maxSessions      int
+		maxBitrate       int64
+		expectedWarnings []string
+	}{
+		{
+			name:        "below thresholds",
+			sessions:    8,
+			bitrate:     1_000_000,
+			maxSessions: 10,
+			maxBitrate:  100_000_000,
+		},
+		{
+			name:             "near session capacity",
+			sessions:         9,
+			maxSessions:      10,
+			expectedWarnings: []string{entities.LimitSessions},
+		},
+		{
+			name:             "near bandwidth limit",
+			sessions:         2,
+			bitrate:          5_000_000,
+			maxBitrate:       10_000_000,
+			expectedWarnings: []string{entities.LimitBandwidth},
+		},
+		{
+			name:             "both limits",
+			sessions:         10,
+			bitrate:          2_000_000,
+			maxSessions:      10,
+			maxBitrate:       20_000_000,
+			expectedWarnings: []string{entities.LimitSessions, entities.LimitBandwidth},
+		},
+		{
+			name:     "limits disabled",
+			sessions: 50,
+			bitrate:  50_000_000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewMockSessionRepository()
+			publisher := mocks.NewMockEventPublisher()
+			newLimitTestSessions(sessionRepo, tt.sessions, tt.bitrate)
+			useCase := NewLimitsUseCase(sessionRepo, publisher, tt.maxSessions, tt.maxBitrate, 90)
+
+			if err := useCase.CheckLimits(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			warnings := limitWarnings(publisher, "token-0")
+			if len(warnings) != len(tt.expectedWarnings) {
+				t.Fatalf("Expected warnings %v, got %+v", tt.expectedWarnings, warnings)
+			}
+			for i, limit := range tt.expectedWarnings {
+				if warnings[i].Limit != limit || warnings[i].Percent < 90 || warnings[i].Message == "" {
+					t.Errorf("Unexpected warning %+v for limit %s", warnings[i], limit)
+				}
+			}
+		})
+	}
+}
+
+func TestLimitsUseCase_WarnsOnceAndClears(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	publisher := mocks.NewMockEventPublisher()
+	sessions := newLimitTestSessions(sessionRepo, 9, 0)
+	useCase := NewLimitsUseCase(sessionRepo, publisher, 10, 0, 90)
+
+	// Repeated checks at the same load warn each sender only once
+	for i := 0; i < 3; i++ {
+		if err := useCase.CheckLimits(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if warnings := limitWarnings(publisher, "token-0"); len(warnings) != 1 {
+		t.Fatalf("Expected one warning, got %+v", warnings)
+	}
+
+	// A sender that starts while the server is busy is warned too
+	late := mocks.NewTestSession("test-token", false)
+	late.Token = "late"
+	sessionRepo.SetSession(late)
+	if err := useCase.CheckLimits(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if warnings := limitWarnings(publisher, late.Token); len(warnings) != 1 {
+		t.Errorf("Expected the new sender to be warned, got %+v", warnings)
+	}
+
+	// Ending sessions brings usage below the threshold
+	for _, session := range append(sessions[:5], late) {
+		session.End()
+		sessionRepo.SetSession(session)
+	}
+	if err := useCase.CheckLimits(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	warnings := limitWarnings(publisher, "token-8")
+	if len(warnings) != 2 || !warnings[1].Cleared {
+		t.Errorf("Expected an all-clear after the warning, got %+v", warnings)
+	}
+	if warnings := limitWarnings(publisher, late.Token); len(warnings) != 1 {
+		t.Errorf("Expected no all-clear for an ended session, got %+v", warnings)
+	}
+}
@@ -0,0 +1,287 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// DefaultMQTTPrefix is the topic prefix of the MQTT bridge
+const DefaultMQTTPrefix = "share-screen"
+
+// mqttClientIP stands in for the client address in the audit log of what
+// devices send through the broker, whose addresses the bridge never sees
+const mqttClientIP = "mqtt"
+
+// Subtopics of a session's topic
+const (
+	mqttOffer        = "offer"
+	mqttAnswer       = "answer"
+	mqttEnded        = "ended"
+	mqttError        = "error"
+	mqttSubmitOffer  = "offer/submit"
+	mqttSubmitAnswer = "answer/submit"
+	mqttHeartbeat    = "heartbeat"
+)
+
+// mqttPublicErrors are the errors a device is told about; any other error
+// is reported as internal
+var mqttPublicErrors = []error{
+	ErrSessionNotFound, ErrSessionExpired, ErrSessionEnded, ErrInvalidOffer,
+	ErrInvalidAnswer, ErrAnswerAlreadyExists, ErrStaleAnswer, ErrSessionNotReady,
+	ErrSessionFull, ErrViewerLinkUsed, ErrViewerRevoked, ErrMissingViewerID,
+	ErrServerBusy, ErrInvalidViewerName,
+}
+
+// mqttDescription is the payload of the offer and answer topics
+type mqttDescription struct {
+	Type string `json:"type"`
+	SDP  string `json:"sdp"`
+
+	// ViewerID names the viewer that answered, so the sender can remove it,
+	// and ViewerName is the name it gave itself
+	ViewerID   string `json:"viewerId,omitempty"`
+	ViewerName string `json:"viewerName,omitempty"`
+
+	// Paused says the sender paused the stream
+	Paused bool `json:"paused,omitempty"`
+
+	// SenderKey lets a sending device replace the offer it posted
+	SenderKey string `json:"senderKey,omitempty"`
+
+	// ICEGeneration counts the ICE restarts of the offer; a viewing device
+	// answers a restart on its connection with the same count
+	ICEGeneration int `json:"iceGeneration,omitempty"`
+}
+
+// mqttErrorMessage tells a device why the bridge refused its message
+type mqttErrorMessage struct {
+	Action string `json:"action"`
+	Error  string `json:"error"`
+}
+
+// MQTTBridgeUseCase mirrors the handshake of sessions onto MQTT topics, so
+// devices already connected to a broker, such as kiosks and Raspberry Pi
+// displays, join sessions without polling the HTTP API. Each session has
+// these topics under <prefix>/<token>:
+//
+//	offer          the sender's offer, retained while the session lasts
+//	answer         the viewer's answer, retained likewise
+//	ended          a message when the session ends
+//	error          why the bridge refused a message a device sent
+//	offer/submit   offers of devices that send their screen
+//	answer/submit  answers of devices that view
+//	heartbeat      heartbeats of devices that send
+//
+// ICE candidates travel inside the descriptions, as with the HTTP API.
+// Every instance of a cluster mirrors the events it records itself, while
+// the messages of devices reach one of them through a shared subscription.
+// Sessions with a PIN or streaming through the SFU are not mirrored, since
+// their offers are handed out per viewer.
+type MQTTBridgeUseCase struct {
+	bus            interfaces.MessageBus
+	subscriber     interfaces.EventSubscriber
+	sessionRepo    interfaces.SessionRepository
+	sessionUseCase interfaces.SessionUseCase
+	tokens         entities.TokenPolicy
+	prefix         string
+	shared         bool
+	now            func() time.Time
+}
+
+// NewMQTTBridgeUseCase creates a bridge between bus and the sessions,
+// following their lifecycle events on subscriber; an empty prefix uses
+// DefaultMQTTPrefix, and shared subscribes as one of the instances of a
+// cluster
+func NewMQTTBridgeUseCase(bus interfaces.MessageBus, subscriber interfaces.EventSubscriber, sessionRepo interfaces.SessionRepository, sessionUseCase interfaces.SessionUseCase, tokens entities.TokenPolicy, prefix string, shared bool) *MQTTBridgeUseCase {
+	if prefix == "" {
+		prefix = DefaultMQTTPrefix
+	}
+	return &MQTTBridgeUseCase{
+		bus:            bus,
+		subscriber:     subscriber,
+		sessionRepo:    sessionRepo,
+		sessionUseCase: sessionUseCase,
+		tokens:         tokens,
+		prefix:         strings.TrimSuffix(prefix, "/"),
+		shared:         shared,
+		now:            time.Now,
+	}
+}
+
+// Run bridges until ctx is done or the event broker closes at shutdown
+func (uc *MQTTBridgeUseCase) Run(ctx context.Context) error {
+	events, cancel := uc.subscriber.Subscribe(entities.AdminTopic)
+	defer cancel()
+
+	for _, action := range []string{mqttSubmitOffer, mqttSubmitAnswer, mqttHeartbeat} {
+		filter := uc.prefix + "/+/" + action
+		if uc.shared {
+			// The broker hands each message to one member of the group
+			filter = "$share/" + strings.ReplaceAll(uc.prefix, "/", "-") + "/" + filter
+		}
+		if err := uc.bus.Subscribe(filter, uc.HandleMessage); err != nil {
+			return fmt.Errorf("subscribing to %s: %w", filter, err)
+		}
+	}
+	log.Printf("📡 MQTT bridge on topics %s/<token>/...", uc.prefix)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if audit, ok := event.Data.(entities.AuditEvent); ok {
+				uc.Mirror(&audit)
+			}
+		}
+	}
+}
+
+// Mirror publishes what a session's lifecycle event changed on its topics
+func (uc *MQTTBridgeUseCase) Mirror(event *entities.AuditEvent) {
+	switch event.Type {
+	case entities.AuditOffer, entities.AuditPaused, entities.AuditUnpaused:
+		session, err := uc.sessionRepo.GetSession(event.Token)
+		if err != nil || !mirrored(session) || session.Offer == nil {
+			return
+		}
+		// A new offer leaves the last answer behind
+		if event.Type == entities.AuditOffer {
+			uc.publish(session.Token, mqttAnswer, nil, true)
+		}
+		offer := session.Offer.WithCodec(session.Codec).WithBandwidth(session.MaxBitrateKbps).WithFrameRate(session.MaxFrameRate)
+		uc.publish(session.Token, mqttOffer, &mqttDescription{Type: offer.Type, SDP: offer.SDP, Paused: session.Paused, ICEGeneration: session.ICEGeneration}, true)
+
+	case entities.AuditAnswer:
+		session, err := uc.sessionRepo.GetSession(event.Token)
+		if err != nil || !mirrored(session) || session.Answer == nil {
+			return
+		}
+		answer := session.Answer.WithCodec(session.Codec).WithBandwidth(session.MaxBitrateKbps).WithFrameRate(session.MaxFrameRate)
+		uc.publish(session.Token, mqttAnswer, &mqttDescription{Type: answer.Type, SDP: answer.SDP, ViewerID: session.ViewerID, ViewerName: session.ViewerName}, true)
+
+	case entities.AuditOfferReset:
+		// Devices wait for the offer the sender captures next
+		uc.publish(event.Token, mqttOffer, nil, true)
+		uc.publish(event.Token, mqttAnswer, nil, true)
+
+	case entities.AuditTerminated, entities.AuditStale, entities.AuditExpired:
+		uc.publish(event.Token, mqttOffer, nil, true)
+		uc.publish(event.Token, mqttAnswer, nil, true)
+		uc.publish(event.Token, mqttEnded, map[string]string{"reason": string(event.Type)}, false)
+	}
+}
+
+// HandleMessage passes a message a device sent on to the session use case
+func (uc *MQTTBridgeUseCase) HandleMessage(topic string, payload []byte) {
+	rest, ok := strings.CutPrefix(topic, uc.prefix+"/")
+	if !ok {
+		return
+	}
+	token, action, ok := strings.Cut(rest, "/")
+	if !ok || !uc.validToken(token) {
+		log.Printf("🚫 MQTT message refused on topic %s", topic)
+		return
+	}
+
+	var err error
+	switch action {
+	case mqttSubmitOffer:
+		var offer mqttDescription
+		if json.Unmarshal(payload, &offer) != nil {
+			err = ErrInvalidOffer
+			break
+		}
+		err = uc.sessionUseCase.SubmitOffer(&dto.SubmitOfferRequest{
+			Token:     token,
+			Offer:     &entities.WebRTCOffer{Type: offer.Type, SDP: offer.SDP},
+			ClientIP:  mqttClientIP,
+			SenderKey: offer.SenderKey,
+		})
+
+	case mqttSubmitAnswer:
+		var answer mqttDescription
+		if json.Unmarshal(payload, &answer) != nil {
+			err = ErrInvalidAnswer
+			break
+		}
+		err = uc.sessionUseCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+			Token:         token,
+			Answer:        &entities.WebRTCAnswer{Type: answer.Type, SDP: answer.SDP},
+			ViewerID:      answer.ViewerID,
+			Name:          answer.ViewerName,
+			ClientIP:      mqttClientIP,
+			ICEGeneration: answer.ICEGeneration,
+		})
+
+	case mqttHeartbeat:
+		request := dto.HeartbeatRequest{}
+		// The payload is optional; devices that count no bytes send nothing
+		if len(payload) > 0 {
+			_ = json.Unmarshal(payload, &request)
+		}
+		request.Token = token
+		err = uc.sessionUseCase.Heartbeat(&request)
+
+	default:
+		return
+	}
+
+	if err != nil {
+		log.Printf("❌ MQTT %s refused for token %s: %v", action, entities.ShortToken(token), err)
+		uc.publish(token, mqttError, &mqttErrorMessage{Action: action, Error: publicError(err)}, false)
+	}
+}
+
+// validToken refuses a malformed or forged token, and an expired signed one,
+// before any lookup, as the HTTP API does
+func (uc *MQTTBridgeUseCase) validToken(token string) bool {
+	if !uc.tokens.Valid(token) {
+		return false
+	}
+	expiresAt := uc.tokens.ExpiresAt(token)
+	return expiresAt.IsZero() || !uc.now().After(expiresAt)
+}
+
+// publish sends message as JSON to a topic of the session; a nil message
+// sends an empty payload, which clears a retained one
+func (uc *MQTTBridgeUseCase) publish(token, subtopic string, message any, retained bool) {
+	var payload []byte
+	if message != nil {
+		var err error
+		if payload, err = json.Marshal(message); err != nil {
+			log.Printf("❌ Error encoding MQTT %s message: %v", subtopic, err)
+			return
+		}
+	}
+	if err := uc.bus.Publish(uc.prefix+"/"+token+"/"+subtopic, payload, retained); err != nil {
+		log.Printf("❌ Error publishing MQTT %s for token %s: %v", subtopic, entities.ShortToken(token), err)
+	}
+}
+
+// mirrored reports whether the bridge publishes a session's descriptions
+func mirrored(session *entities.Session) bool {
+	return session.PIN == "" && !session.SFU
+}
+
+// publicError is what a device is told about err
+func publicError(err error) string {
+	for _, public := range mqttPublicErrors {
+		if errors.Is(err, public) {
+			return public.Error()
+		}
+	}
+	return "internal error"
+}
@@ -0,0 +1,205 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+// fakeSubscriber hands out one subscription fed by events
+type fakeSubscriber struct {
+	events chan entities.Event
+}
+
+func (s *fakeSubscriber) Subscribe(topics ...string) (<-chan entities.Event, func()) {
+	return s.events, func() {}
+}
+
+// newBridgeTestUseCase returns a bridge over a real session use case
+func newBridgeTestUseCase(sessionRepo *mocks.MockSessionRepository, bus *mocks.MockMessageBus) *MQTTBridgeUseCase {
+	sessionUseCase := NewSessionUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+	return NewMQTTBridgeUseCase(bus, &fakeSubscriber{}, sessionRepo, sessionUseCase, entities.TokenPolicy{}, "", false)
+}
+
+// lastDescription decodes the last message published to topic
+func lastDescription(t *testing.T, bus *mocks.MockMessageBus, topic string) (mqttDescription, mocks.BusMessage) {
+	t.Helper()
+	messages := bus.Published(topic)
+	if len(messages) == 0 {
+		t.Fatalf("Expected a message on %s", topic)
+	}
+	message := messages[len(messages)-1]
+	var description mqttDescription
+	if len(message.Payload) > 0 {
+		if err := json.Unmarshal(message.Payload, &description); err != nil {
+			t.Fatalf("Expected a JSON description on %s, got %q", topic, message.Payload)
+		}
+	}
+	return description, message
+}
+
+// descriptionJSON encodes a description as a device publishes it
+func descriptionJSON(t *testing.T, description map[string]string) []byte {
+	t.Helper()
+	payload, err := json.Marshal(description)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestMQTTBridgeUseCase_Handshake(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	bus := mocks.NewMockMessageBus()
+	bridge := newBridgeTestUseCase(sessionRepo, bus)
+	token := entities.TokenPolicy{}.Encode(make([]byte, entities.TokenPolicy{}.RandomBytes()), time.Now())
+	session := mocks.NewTestSession(token, false)
+	sessionRepo.SetSession(session)
+	topic := DefaultMQTTPrefix + "/" + token
+
+	// The sending device submits its offer, which is retained for viewers
+	bridge.HandleMessage(topic+"/offer/submit", descriptionJSON(t, map[string]string{"type": "offer", "sdp": testSDP("sender-sdp")}))
+	bridge.Mirror(&entities.AuditEvent{Token: token, Type: entities.AuditOffer})
+
+	offer, message := lastDescription(t, bus, topic+"/offer")
+	if offer.Type != "offer" || offer.SDP != testSDP("sender-sdp") || !message.Retained {
+		t.Errorf("Expected the retained offer, got %+v retained %v", offer, message.Retained)
+	}
+
+	// The viewing device answers, which is retained for the sender
+	bridge.HandleMessage(topic+"/answer/submit", descriptionJSON(t, map[string]string{"type": "answer", "sdp": testSDP("viewer-sdp"), "viewerId": "kiosk-1"}))
+	bridge.Mirror(&entities.AuditEvent{Token: token, Type: entities.AuditAnswer})
+
+	answer, message := lastDescription(t, bus, topic+"/answer")
+	if answer.SDP != testSDP("viewer-sdp") || answer.ViewerID != "kiosk-1" || !message.Retained {
+		t.Errorf("Expected the retained answer of the kiosk, got %+v retained %v", answer, message.Retained)
+	}
+
+	bridge.HandleMessage(topic+"/heartbeat", []byte(`{"bytesSent":2048}`))
+	if session, _ := sessionRepo.GetSession(token); session.BytesSent != 2048 {
+		t.Errorf("Expected the heartbeat recorded, got %d bytes", session.BytesSent)
+	}
+	if reported := bus.Published(topic + "/error"); len(reported) != 0 {
+		t.Errorf("Expected no errors, got %+v", reported)
+	}
+
+	// Resetting the offer clears the descriptions until the sender offers again
+	bridge.Mirror(&entities.AuditEvent{Token: token, Type: entities.AuditOfferReset})
+	for _, subtopic := range []string{"/offer", "/answer"} {
+		if _, message := lastDescription(t, bus, topic+subtopic); len(message.Payload) != 0 || !message.Retained {
+			t.Errorf("Expected %s cleared after the reset, got %+v", subtopic, message)
+		}
+	}
+
+	// Ending the session clears the retained descriptions
+	bridge.Mirror(&entities.AuditEvent{Token: token, Type: entities.AuditTerminated})
+	for _, subtopic := range []string{"/offer", "/answer"} {
+		if _, message := lastDescription(t, bus, topic+subtopic); len(message.Payload) != 0 || !message.Retained {
+			t.Errorf("Expected %s cleared, got %+v", subtopic, message)
+		}
+	}
+	if ended := bus.Published(topic + "/ended"); len(ended) != 1 {
+		t.Errorf("Expected one ended message, got %+v", ended)
+	}
+}
+
+func TestMQTTBridgeUseCase_PINSessionNotMirrored(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	bus := mocks.NewMockMessageBus()
+	bridge := newBridgeTestUseCase(sessionRepo, bus)
+	token := entities.TokenPolicy{}.Encode(make([]byte, entities.TokenPolicy{}.RandomBytes()), time.Now())
+	session := mocks.NewTestSession(token, false)
+	session.PIN = "123456"
+	sessionRepo.SetSession(session)
+
+	bridge.HandleMessage(DefaultMQTTPrefix+"/"+token+"/offer/submit", descriptionJSON(t, map[string]string{"type": "offer", "sdp": testSDP("sender-sdp")}))
+	bridge.Mirror(&entities.AuditEvent{Token: token, Type: entities.AuditOffer})
+
+	if offers := bus.Published(DefaultMQTTPrefix + "/" + token + "/offer"); len(offers) != 0 {
+		t.Errorf("Expected the offer of a PIN session kept off the broker, got %+v", offers)
+	}
+}
+
+func TestMQTTBridgeUseCase_Errors(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	bus := mocks.NewMockMessageBus()
+	bridge := newBridgeTestUseCase(sessionRepo, bus)
+
+	// A well-formed token of no session is told so
+	unknown := entities.TokenPolicy{}.Encode(bytes.Repeat([]byte{1}, entities.TokenPolicy{}.RandomBytes()), time.Now())
+	bridge.HandleMessage(DefaultMQTTPrefix+"/"+unknown+"/heartbeat", nil)
+
+	var message mqttErrorMessage
+	reported := bus.Published(DefaultMQTTPrefix + "/" + unknown + "/error")
+	if len(reported) != 1 || json.Unmarshal(reported[0].Payload, &message) != nil {
+		t.Fatalf("Expected one error message, got %+v", reported)
+	}
+	if message.Action != "heartbeat" || message.Error != ErrSessionNotFound.Error() {
+		t.Errorf("Expected session not found for the heartbeat, got %+v", message)
+	}
+
+	// An invalid description is refused before reaching the session
+	token := entities.TokenPolicy{}.Encode(make([]byte, entities.TokenPolicy{}.RandomBytes()), time.Now())
+	session := mocks.NewTestSession(token, false)
+	sessionRepo.SetSession(session)
+	bridge.HandleMessage(DefaultMQTTPrefix+"/"+token+"/offer/submit", []byte("not json"))
+	if reported := bus.Published(DefaultMQTTPrefix + "/" + token + "/error"); len(reported) != 1 {
+		t.Errorf("Expected the invalid offer reported, got %+v", reported)
+	}
+
+	// Malformed tokens get no answer at all
+	bridge.HandleMessage(DefaultMQTTPrefix+"/not-a-token/heartbeat", nil)
+	if reported := bus.Published(DefaultMQTTPrefix + "/not-a-token/error"); len(reported) != 0 {
+		t.Errorf("Expected a malformed token ignored, got %+v", reported)
+	}
+}
+
+func TestMQTTBridgeUseCase_Run(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	bus := mocks.NewMockMessageBus()
+	subscriber := &fakeSubscriber{events: make(chan entities.Event, 1)}
+	bridge := NewMQTTBridgeUseCase(bus, subscriber, sessionRepo, mocks.NewMockSessionUseCase(), entities.TokenPolicy{}, "devices/", false)
+
+	done := make(chan error)
+	go func() { done <- bridge.Run(context.Background()) }()
+
+	subscriber.events <- entities.Event{Type: entities.EventSession, Data: entities.AuditEvent{Token: "gone", Type: entities.AuditExpired}}
+	// The broker closes the subscription at shutdown
+	close(subscriber.events)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Run to return once the subscription closed")
+	}
+
+	for _, filter := range []string{"devices/+/offer/submit", "devices/+/answer/submit", "devices/+/heartbeat"} {
+		if !bus.Subscribed(filter) {
+			t.Errorf("Expected a subscription to %s", filter)
+		}
+	}
+	if ended := bus.Published("devices/gone/ended"); len(ended) != 1 {
+		t.Errorf("Expected the expiry mirrored, got %+v", ended)
+	}
+}
+
+func TestMQTTBridgeUseCase_SharedSubscriptions(t *testing.T) {
+	bus := mocks.NewMockMessageBus()
+	subscriber := &fakeSubscriber{events: make(chan entities.Event)}
+	bridge := NewMQTTBridgeUseCase(bus, subscriber, mocks.NewMockSessionRepository(), mocks.NewMockSessionUseCase(), entities.TokenPolicy{}, "", true)
+
+	close(subscriber.events)
+	if err := bridge.Run(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bus.Subscribed("$share/share-screen/share-screen/+/offer/submit") {
+		t.Error("Expected the instances of a cluster to share their subscriptions")
+	}
+}
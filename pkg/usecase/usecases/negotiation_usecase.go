@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// NegotiationUseCase implements the negotiation relay use case interface
+type NegotiationUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	publisher   interfaces.EventPublisher
+}
+
+// NewNegotiationUseCase creates a new negotiation relay use case
+func NewNegotiationUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher) *NegotiationUseCase {
+	return &NegotiationUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		publisher:   publisher,
+	}
+}
+
+// SendMessage relays a description or candidate between the sender and the
+// connected viewer, so they can run the perfect negotiation pattern once
+// connected, keeping it for later polls and announcing it on the event stream
+func (uc *NegotiationUseCase) SendMessage(request *dto.SendNegotiationMessageRequest) (*entities.NegotiationMessage, error) {
+	message := entities.NegotiationMessage{
+		From:        request.From,
+		ViewerID:    request.ViewerID,
+		Description: request.Description,
+		Candidate:   request.Candidate,
+		SentAt:      time.Now(),
+	}
+	if !message.Valid() {
+		return nil, ErrInvalidNegotiation
+	}
+	if message.Description != nil {
+		sdp, err := sanitizeSDP(message.Description.SDP)
+		if err != nil {
+			log.Printf("❌ Invalid negotiation SDP: %v", err)
+			return nil, ErrInvalidNegotiation
+		}
+		message.Description = &entities.SessionDescription{Type: message.Description.Type, SDP: sdp}
+	}
+
+	session, err := uc.authorize(request.Token, request.PIN, request.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	message = session.AddNegotiationMessage(message)
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error storing negotiation message: %v", err)
+		return nil, err
+	}
+
+	if message.From == entities.NegotiationFromSender {
+		uc.publisher.Publish(entities.ViewerTopic(session.Token, message.ViewerID), entities.Event{Type: entities.EventNegotiation, Data: message})
+	} else {
+		// Queued viewers hear the session topic too; they learn only that
+		// there is something to fetch, never the viewer's SDP or addresses
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventNegotiation, Data: map[string]int{"seq": message.Seq}})
+	}
+	return &message, nil
+}
+
+// GetMessages returns the messages relayed on the viewer's connection after
+// request.Since
+func (uc *NegotiationUseCase) GetMessages(request *dto.GetNegotiationMessagesRequest) (*dto.NegotiationMessagesResponse, error) {
+	session, err := uc.authorize(request.Token, request.PIN, request.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.NegotiationMessagesResponse{Messages: session.NegotiationSince(request.Since, request.ViewerID)}, nil
+}
+
+// authorize returns the live session if pin unlocks it and viewerID holds its
+// peer-to-peer connection; SFU sessions renegotiate with the server instead
+func (uc *NegotiationUseCase) authorize(token, pin, viewerID string) (*entities.Session, error) {
+	if viewerID == "" {
+		return nil, ErrMissingViewerID
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unlockSession(uc.sessionRepo, session, pin); err != nil {
+		return nil, err
+	}
+	if session.SFU || !session.IsFull() || session.ViewerID != viewerID {
+		return nil, ErrViewerNotConnected
+	}
+	return session, nil
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestNegotiationUseCase_SendMessage(t *testing.T) {
+	offer := &entities.SessionDescription{Type: "offer", SDP: testSDP("offer-sdp")}
+	tests := []struct {
+		name          string
+		pin           string
+		sfu           bool
+		request       *dto.SendNegotiationMessageRequest
+		expectedTopic string
+		expectedError error
+	}{
+		{
+			name:          "sender offer to the viewer",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", From: entities.NegotiationFromSender, ViewerID: "viewer-1", Description: offer},
+			expectedTopic: entities.ViewerTopic("test-token", "viewer-1"),
+		},
+		{
+			name:          "viewer candidate with PIN",
+			pin:           "123456",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", PIN: "123456", From: entities.NegotiationFromViewer, ViewerID: "viewer-1", Candidate: &entities.ICECandidate{Candidate: "candidate:1"}},
+			expectedTopic: entities.SessionTopic("test-token"),
+		},
+		{
+			name:          "wrong PIN",
+			pin:           "123456",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", PIN: "000000", From: entities.NegotiationFromSender, ViewerID: "viewer-1", Description: offer},
+			expectedError: ErrInvalidPIN,
+		},
+		{
+			name:          "invalid message",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", From: entities.NegotiationFromSender, ViewerID: "viewer-1"},
+			expectedError: ErrInvalidNegotiation,
+		},
+		{
+			name:          "missing viewer",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", From: entities.NegotiationFromSender, Description: offer},
+			expectedError: ErrMissingViewerID,
+		},
+		{
+			name:          "viewer not connected",
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", From: entities.NegotiationFromViewer, ViewerID: "viewer-2", Description: offer},
+			expectedError: ErrViewerNotConnected,
+		},
+		{
+			name:          "SFU session",
+			sfu:           true,
+			request:       &dto.SendNegotiationMessageRequest{Token: "test-token", From: entities.NegotiationFromSender, ViewerID: "viewer-1", Description: offer},
+			expectedError: ErrViewerNotConnected,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.SendNegotiationMessageRequest{Token: "missing", From: entities.NegotiationFromSender, ViewerID: "viewer-1", Description: offer},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", true)
+			session.ViewerID = "viewer-1"
+			session.PIN = tt.pin
+			session.SFU = tt.sfu
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(session)
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewNegotiationUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), publisher)
+
+			message, err := useCase.SendMessage(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				if events := publisher.Published(entities.SessionTopic("test-token")); len(events) != 0 {
+					t.Errorf("Expected nothing published, got %+v", events)
+				}
+				return
+			}
+
+			if message.Seq != 1 || message.From != tt.request.From || message.ViewerID != "viewer-1" {
+				t.Errorf("Unexpected message %+v", message)
+			}
+			events := publisher.Published(tt.expectedTopic)
+			if len(events) != 1 || events[0].Type != entities.EventNegotiation {
+				t.Fatalf("Expected a negotiation event on %s, got %+v", tt.expectedTopic, events)
+			}
+			// What the viewer sends is announced to the whole session, so it
+			// must not carry the viewer's SDP or addresses
+			if tt.request.From == entities.NegotiationFromViewer {
+				if data, ok := events[0].Data.(map[string]int); !ok || data["seq"] != 1 {
+					t.Errorf("Expected only the sequence number announced, got %+v", events[0].Data)
+				}
+			}
+
+			relayed, err := useCase.GetMessages(&dto.GetNegotiationMessagesRequest{Token: "test-token", PIN: tt.pin, ViewerID: "viewer-1"})
+			if err != nil {
+				t.Fatalf("GetMessages failed: %v", err)
+			}
+			if len(relayed.Messages) != 1 || relayed.Messages[0].Seq != message.Seq {
+				t.Errorf("Expected the message to be kept, got %+v", relayed.Messages)
+			}
+		})
+	}
+}
+
+func TestNegotiationUseCase_GetMessages(t *testing.T) {
+	session := mocks.NewTestSession("test-token", true)
+	session.ViewerID = "viewer-1"
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewNegotiationUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	for _, from := range []string{entities.NegotiationFromSender, entities.NegotiationFromViewer, entities.NegotiationFromSender} {
+		request := &dto.SendNegotiationMessageRequest{Token: "test-token", From: from, ViewerID: "viewer-1", Candidate: &entities.ICECandidate{Candidate: "candidate:1"}}
+		if _, err := useCase.SendMessage(request); err != nil {
+			t.Fatalf("SendMessage failed: %v", err)
+		}
+	}
+
+	response, err := useCase.GetMessages(&dto.GetNegotiationMessagesRequest{Token: "test-token", ViewerID: "viewer-1", Since: 1})
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if len(response.Messages) != 2 || response.Messages[0].Seq != 2 || response.Messages[1].Seq != 3 {
+		t.Errorf("Expected the messages after 1, got %+v", response.Messages)
+	}
+
+	// Another viewer taking the slot sees nothing of the previous connection
+	session, _ = mockRepo.GetSession("test-token")
+	session.ViewerID = "viewer-2"
+	mockRepo.SetSession(session)
+	if _, err := useCase.GetMessages(&dto.GetNegotiationMessagesRequest{Token: "test-token", ViewerID: "viewer-1"}); err != ErrViewerNotConnected {
+		t.Errorf("Expected the previous viewer refused, got %v", err)
+	}
+	response, err = useCase.GetMessages(&dto.GetNegotiationMessagesRequest{Token: "test-token", ViewerID: "viewer-2"})
+	if err != nil || len(response.Messages) != 0 {
+		t.Errorf("Expected no messages for the new viewer, got %+v, %v", response, err)
+	}
+}
@@ -0,0 +1,24 @@
+package usecases
+
+import (
+	"slices"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+)
+
+// PresetUseCase implements the preset use case interface
+type PresetUseCase struct{}
+
+// NewPresetUseCase creates a new preset use case
+func NewPresetUseCase() *PresetUseCase {
+	return &PresetUseCase{}
+}
+
+// ListPresets returns the quality presets the server offers
+func (uc *PresetUseCase) ListPresets() *dto.PresetsResponse {
+	return &dto.PresetsResponse{
+		Presets: slices.Clone(entities.QualityPresets),
+		Default: entities.QualityPresets[0].ID,
+	}
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+func TestPresetUseCase_ListPresets(t *testing.T) {
+	useCase := NewPresetUseCase()
+
+	response := useCase.ListPresets()
+	if len(response.Presets) != len(entities.QualityPresets) {
+		t.Fatalf("Expected %d presets, got %d", len(entities.QualityPresets), len(response.Presets))
+	}
+	if entities.FindQualityPreset(response.Default) == nil {
+		t.Errorf("Expected the default %q to be a known preset", response.Default)
+	}
+	for _, preset := range response.Presets {
+		if preset.Width <= 0 || preset.Height <= 0 || preset.FrameRate <= 0 {
+			t.Errorf("Expected capture limits for preset %q, got %+v", preset.ID, preset)
+		}
+		if preset.MaxBitrateKbps < minBitrateCapKbps || preset.MaxBitrateKbps > maxBitrateCapKbps {
+			t.Errorf("Expected preset %q to have a valid bitrate cap, got %d", preset.ID, preset.MaxBitrateKbps)
+		}
+	}
+
+	// Callers get their own copy of the list
+	response.Presets[0].Name = "changed"
+	if useCase.ListPresets().Presets[0].Name == "changed" {
+		t.Error("Expected the presets to be unaffected by changes to a response")
+	}
+}
@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// maxQueueLength caps how many viewers may wait on one session
+	maxQueueLength = 50
+
+	// reservationTimeout is how long an admitted viewer has to answer before
+	// the slot can go to someone else
+	reservationTimeout = time.Minute
+)
+
+// ViewerQueueUseCase implements the viewer queue use case interface
+type ViewerQueueUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	publisher   interfaces.EventPublisher
+}
+
+// NewViewerQueueUseCase creates a new viewer queue use case
+func NewViewerQueueUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher) *ViewerQueueUseCase {
+	return &ViewerQueueUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		publisher:   publisher,
+	}
+}
+
+// JoinQueue places a viewer in the queue of a full session, or reserves the
+// slot straight away when it is free
+func (uc *ViewerQueueUseCase) JoinQueue(request *dto.JoinQueueRequest) (*dto.JoinQueueResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unlockSession(uc.sessionRepo, session, request.PIN); err != nil {
+		return nil, err
+	}
+
+	// Nobody else will get the slot of a used single-use link
+	if session.IsConsumed() {
+		return nil, ErrViewerLinkUsed
+	}
+
+	// The viewer limit counts everyone watching, through the server too,
+	// and waiting
+	if session.IsAudienceFull() {
+		return nil, ErrQueueFull
+	}
+
+	viewerID, err := generateViewerID()
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.IsFull() && !session.IsReservedForOther(viewerID) && len(session.Queue) == 0 {
+		session.Reserve(viewerID, reservationTimeout)
+		if err := uc.sessionRepo.UpdateSession(session); err != nil {
+			return nil, err
+		}
+		return &dto.JoinQueueResponse{ViewerID: viewerID, Position: 0}, nil
+	}
+
+	if len(session.Queue) >= maxQueueLength {
+		return nil, ErrQueueFull
+	}
+
+	session.Queue = append(session.Queue, entities.QueuedViewer{
+		ID:       viewerID,
+		JoinedAt: time.Now(),
+	})
+
+	// A lapsed reservation leaves the slot free; hand it to the queue head
+	admitNext(uc.publisher, session)
+	session.RecordAudience()
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error adding viewer to queue: %v", err)
+		return nil, err
+	}
+
+	position := session.QueuePosition(viewerID)
+	log.Printf("⏳ Viewer queued for token: %s (position %d)", entities.ShortToken(request.Token), position)
+	publishQueue(uc.publisher, session)
+
+	return &dto.JoinQueueResponse{
+		ViewerID: viewerID,
+		Position: position,
+	}, nil
+}
+
+// LeaveQueue removes a viewer from the queue; the sender uses it to drop
+// viewers and queued viewers call it when their page closes
+func (uc *ViewerQueueUseCase) LeaveQueue(request *dto.QueueViewerRequest) error {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	removed := session.RemoveFromQueue(request.ViewerID)
+	if session.ReservedFor == request.ViewerID {
+		session.ClearReservation()
+		removed = true
+	}
+	if !removed {
+		return ErrViewerNotQueued
+	}
+
+	admitNext(uc.publisher, session)
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error removing viewer from queue: %v", err)
+		return err
+	}
+
+	publishQueue(uc.publisher, session)
+	return nil
+}
+
+// PromoteViewer moves a queued viewer to the front of the queue
+func (uc *ViewerQueueUseCase) PromoteViewer(request *dto.QueueViewerRequest) error {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !session.PromoteInQueue(request.ViewerID) {
+		return ErrViewerNotQueued
+	}
+
+	admitNext(uc.publisher, session)
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error promoting viewer: %v", err)
+		return err
+	}
+
+	publishQueue(uc.publisher, session)
+	return nil
+}
+
+// GetQueue lists the viewers waiting on a session
+func (uc *ViewerQueueUseCase) GetQueue(request *dto.GetQueueRequest) (*dto.GetQueueResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	viewers := session.Queue
+	if viewers == nil {
+		viewers = []entities.QueuedViewer{}
+	}
+
+	return &dto.GetQueueResponse{
+		Viewers:     viewers,
+		ReservedFor: session.ReservedFor,
+	}, nil
+}
+
+// ReleaseViewer frees the slot held by the connected viewer, returns the
+// session to pending so the sender can offer again, and admits the next
+// queued viewer
+func (uc *ViewerQueueUseCase) ReleaseViewer(request *dto.ReleaseViewerRequest) error {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !session.IsFull() && session.Offer == nil {
+		// Already released, e.g. by both the viewer beacon and the sender
+		return nil
+	}
+
+	session.Offer = nil
+	session.Answer = nil
+	session.ViewerID = ""
+	session.ViewerName = ""
+	session.Status = entities.SessionStatusPending
+	admitNext(uc.publisher, session)
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error releasing viewer slot: %v", err)
+		return err
+	}
+
+	log.Printf("👋 Viewer left session for token: %s", entities.ShortToken(request.Token))
+	uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventViewerLeft})
+	publishQueue(uc.publisher, session)
+	return nil
+}
+
+// admitNext reserves a free slot for the viewer at the head of the queue
+func admitNext(publisher interfaces.EventPublisher, session *entities.Session) {
+	if session.IsFull() || len(session.Queue) == 0 {
+		return
+	}
+	if session.IsReservedForOther("") {
+		return
+	}
+
+	next := session.Queue[0]
+	session.Queue = session.Queue[1:]
+	session.Reserve(next.ID, reservationTimeout)
+
+	log.Printf("🎟️  Viewer admitted from queue for token: %s", entities.ShortToken(session.Token))
+	publisher.Publish(entities.ViewerTopic(session.Token, next.ID), entities.Event{Type: entities.EventViewerAdmitted})
+}
+
+// publishQueue tells the sender the queue contents and each viewer its position
+func publishQueue(publisher interfaces.EventPublisher, session *entities.Session) {
+	publisher.Publish(entities.SessionTopic(session.Token), entities.Event{
+		Type: entities.EventQueueChanged,
+		Data: session.Queue,
+	})
+
+	for i, viewer := range session.Queue {
+		publisher.Publish(entities.ViewerTopic(session.Token, viewer.ID), entities.Event{
+			Type: entities.EventQueuePosition,
+			Data: map[string]int{"position": i + 1},
+		})
+	}
+}
+
+// generateViewerID generates a random identifier for a queued viewer
+func generateViewerID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,202 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestViewerQueueUseCase_JoinQueue(t *testing.T) {
+	tests := []struct {
+		name             string
+		session          *entities.Session
+		expectedPosition int
+		expectedError    error
+	}{
+		{
+			name:             "free slot is reserved immediately",
+			session:          mocks.NewTestSession("test-token", false),
+			expectedPosition: 0,
+		},
+		{
+			name:             "full session queues viewer",
+			session:          mocks.NewTestSession("test-token", true),
+			expectedPosition: 1,
+		},
+		{
+			name:             "joins behind existing viewers",
+			session:          mocks.NewTestSession("test-token", true, "a", "b"),
+			expectedPosition: 3,
+		},
+		{
+			name:          "session not found",
+			session:       nil,
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			if tt.session != nil {
+				mockRepo.SetSession(tt.session)
+			}
+			useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+			response, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"})
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if response.ViewerID == "" {
+				t.Error("Expected viewer ID")
+			}
+			if response.Position != tt.expectedPosition {
+				t.Errorf("Expected position %d, got %d", tt.expectedPosition, response.Position)
+			}
+
+			stored, _ := mockRepo.GetSession("test-token")
+			if tt.expectedPosition == 0 && stored.ReservedFor != response.ViewerID {
+				t.Error("Expected slot to be reserved for viewer")
+			}
+		})
+	}
+}
+
+func TestViewerQueueUseCase_JoinQueue_Full(t *testing.T) {
+	session := mocks.NewTestSession("test-token", true)
+	for i := 0; i < maxQueueLength; i++ {
+		session.Queue = append(session.Queue, entities.QueuedViewer{ID: string(rune('a' + i%26))})
+	}
+
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	if _, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestViewerQueueUseCase_JoinQueue_ViewerLimit(t *testing.T) {
+	// The connected viewer and one waiting make two
+	session := mocks.NewTestSession("test-token", true, "a")
+	session.MaxViewers = 2
+
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	if _, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull at the viewer limit, got %v", err)
+	}
+
+	session.MaxViewers = 3
+	mockRepo.SetSession(session)
+	if response, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"}); err != nil || response.Position != 2 {
+		t.Errorf("Expected to queue below the viewer limit, got %+v, %v", response, err)
+	}
+}
+
+func TestViewerQueueUseCase_JoinQueue_PIN(t *testing.T) {
+	session := mocks.NewTestSession("test-token", true)
+	session.PIN = "482913"
+
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	if _, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"}); !errors.Is(err, ErrInvalidPIN) {
+		t.Errorf("Expected ErrInvalidPIN, got %v", err)
+	}
+	if _, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token", PIN: "482913"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestViewerQueueUseCase_LeaveAndPromote(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", true, "a", "b", "c"))
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	if err := useCase.PromoteViewer(&dto.QueueViewerRequest{Token: "test-token", ViewerID: "c"}); err != nil {
+		t.Fatalf("Unexpected promote error: %v", err)
+	}
+	if err := useCase.LeaveQueue(&dto.QueueViewerRequest{Token: "test-token", ViewerID: "a"}); err != nil {
+		t.Fatalf("Unexpected leave error: %v", err)
+	}
+	if err := useCase.LeaveQueue(&dto.QueueViewerRequest{Token: "test-token", ViewerID: "missing"}); !errors.Is(err, ErrViewerNotQueued) {
+		t.Errorf("Expected ErrViewerNotQueued, got %v", err)
+	}
+
+	queue, err := useCase.GetQueue(&dto.GetQueueRequest{Token: "test-token"})
+	if err != nil {
+		t.Fatalf("Unexpected get queue error: %v", err)
+	}
+	if len(queue.Viewers) != 2 || queue.Viewers[0].ID != "c" || queue.Viewers[1].ID != "b" {
+		t.Errorf("Unexpected queue order: %+v", queue.Viewers)
+	}
+}
+
+func TestViewerQueueUseCase_ReleaseViewer(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", true, "next", "later"))
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), publisher)
+
+	if err := useCase.ReleaseViewer(&dto.ReleaseViewerRequest{Token: "test-token"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stored, _ := mockRepo.GetSession("test-token")
+	if stored.Offer != nil || stored.Answer != nil {
+		t.Error("Expected offer and answer to be cleared")
+	}
+	if stored.Status != entities.SessionStatusPending {
+		t.Errorf("Expected pending status, got %s", stored.Status)
+	}
+	if stored.ReservedFor != "next" {
+		t.Errorf("Expected slot reserved for queue head, got %q", stored.ReservedFor)
+	}
+	if len(stored.Queue) != 1 || stored.Queue[0].ID != "later" {
+		t.Errorf("Unexpected queue after release: %+v", stored.Queue)
+	}
+
+	admitted := publisher.Published(entities.ViewerTopic("test-token", "next"))
+	if len(admitted) != 1 || admitted[0].Type != entities.EventViewerAdmitted {
+		t.Errorf("Expected admitted event for queue head, got %+v", admitted)
+	}
+
+	sessionEvents := publisher.Published(entities.SessionTopic("test-token"))
+	if len(sessionEvents) == 0 || sessionEvents[0].Type != entities.EventViewerLeft {
+		t.Errorf("Expected viewer-left event, got %+v", sessionEvents)
+	}
+
+	// Releasing again is a no-op
+	if err := useCase.ReleaseViewer(&dto.ReleaseViewerRequest{Token: "test-token"}); err != nil {
+		t.Errorf("Expected repeated release to succeed, got %v", err)
+	}
+}
+
+func TestViewerQueueUseCase_JoinQueue_LinkUsed(t *testing.T) {
+	session := mocks.NewTestSession("test-token", true)
+	session.SingleUse = true
+	session.ConsumedBy = "first"
+
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(session)
+	useCase := NewViewerQueueUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	if _, err := useCase.JoinQueue(&dto.JoinQueueRequest{Token: "test-token"}); !errors.Is(err, ErrViewerLinkUsed) {
+		t.Errorf("Expected ErrViewerLinkUsed, got %v", err)
+	}
+}
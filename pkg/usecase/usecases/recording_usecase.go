@@ -0,0 +1,111 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// RecordingUseCase implements the recording use case interface: the admin's
+// access to the recordings of SFU sessions and the janitor keeping them
+// within their retention period and disk limit
+type RecordingUseCase struct {
+	recordingRepo interfaces.RecordingRepository
+	maxBytes      int64
+	retention     time.Duration
+}
+
+// NewRecordingUseCase creates a new recording use case; recordingRepo is nil
+// when recordings are disabled, and maxBytes and retention are 0 for no limit
+func NewRecordingUseCase(recordingRepo interfaces.RecordingRepository, maxBytes int64, retention time.Duration) *RecordingUseCase {
+	return &RecordingUseCase{
+		recordingRepo: recordingRepo,
+		maxBytes:      maxBytes,
+		retention:     retention,
+	}
+}
+
+// ListRecordings returns the recordings, oldest first, with the space they
+// take up
+func (uc *RecordingUseCase) ListRecordings() (*dto.RecordingsResponse, error) {
+	if uc.recordingRepo == nil {
+		return nil, ErrRecordingsDisabled
+	}
+	recordings, err := uc.recordingRepo.ListRecordings()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RecordingsResponse{
+		Recordings:       recordings,
+		MaxBytes:         uc.maxBytes,
+		RetentionSeconds: int64(uc.retention / time.Second),
+	}
+	for _, recording := range recordings {
+		response.TotalBytes += recording.Size
+	}
+	return response, nil
+}
+
+// GetRecording opens a recording for download
+func (uc *RecordingUseCase) GetRecording(request *dto.GetRecordingRequest) (*dto.RecordingDownload, error) {
+	if uc.recordingRepo == nil {
+		return nil, ErrRecordingsDisabled
+	}
+	recording, content, err := uc.recordingRepo.OpenRecording(request.Name)
+	if err != nil {
+		return nil, ErrRecordingNotFound
+	}
+	return &dto.RecordingDownload{Recording: recording, Content: content}, nil
+}
+
+// DeleteRecording removes a recording
+func (uc *RecordingUseCase) DeleteRecording(request *dto.DeleteRecordingRequest) error {
+	if uc.recordingRepo == nil {
+		return ErrRecordingsDisabled
+	}
+	if err := uc.recordingRepo.DeleteRecording(request.Name); err != nil {
+		return ErrRecordingNotFound
+	}
+
+	log.Printf("🗑️  Deleted recording %s", request.Name)
+	return nil
+}
+
+// PruneRecordings removes the recordings that ended before the retention
+// period, then the oldest until the rest fit within the disk limit, and
+// returns the recordings removed
+func (uc *RecordingUseCase) PruneRecordings() ([]*entities.Recording, error) {
+	if uc.recordingRepo == nil {
+		return nil, nil
+	}
+	recordings, err := uc.recordingRepo.ListRecordings()
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, recording := range recordings {
+		total += recording.Size
+	}
+
+	var removed []*entities.Recording
+	now := time.Now()
+	for _, recording := range recordings {
+		expired := uc.retention > 0 && now.Sub(recording.EndedAt) > uc.retention
+		overLimit := uc.maxBytes > 0 && total > uc.maxBytes
+		if !expired && !overLimit {
+			continue
+		}
+		if err := uc.recordingRepo.DeleteRecording(recording.Name); err != nil {
+			log.Printf("❌ Error pruning recording %s: %v", recording.Name, err)
+			continue
+		}
+		total -= recording.Size
+		removed = append(removed, recording)
+	}
+	return removed, nil
+}
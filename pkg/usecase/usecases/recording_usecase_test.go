@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+// addRecording stores a recording of size bytes that ended age ago
+func addRecording(repo *mocks.MockRecordingRepository, token string, size int64, age time.Duration) *entities.Recording {
+	ended := time.Now().Add(-age)
+	recording := &entities.Recording{
+		Name:      entities.RecordingName(token, ended.Add(-time.Minute)),
+		Token:     token,
+		Size:      size,
+		StartedAt: ended.Add(-time.Minute).Truncate(time.Second),
+		EndedAt:   ended,
+	}
+	repo.AddRecording(recording, "video of "+token)
+	return recording
+}
+
+func TestRecordingUseCase(t *testing.T) {
+	repo := mocks.NewMockRecordingRepository()
+	first := addRecording(repo, "first", 100, time.Hour)
+	addRecording(repo, "second", 200, time.Minute)
+	useCase := NewRecordingUseCase(repo, 1000, 24*time.Hour)
+
+	response, err := useCase.ListRecordings()
+	if err != nil {
+		t.Fatalf("ListRecordings failed: %v", err)
+	}
+	if len(response.Recordings) != 2 || response.TotalBytes != 300 || response.MaxBytes != 1000 || response.RetentionSeconds != 86400 {
+		t.Errorf("Unexpected recordings: %+v", response)
+	}
+
+	download, err := useCase.GetRecording(&dto.GetRecordingRequest{Name: first.Name})
+	if err != nil {
+		t.Fatalf("GetRecording failed: %v", err)
+	}
+	content, _ := io.ReadAll(download.Content)
+	if download.Recording.Token != "first" || string(content) != "video of first" {
+		t.Errorf("Expected the first recording, got %+v %q", download.Recording, content)
+	}
+	if _, err := useCase.GetRecording(&dto.GetRecordingRequest{Name: "missing"}); err != ErrRecordingNotFound {
+		t.Errorf("Expected ErrRecordingNotFound but got %v", err)
+	}
+
+	if err := useCase.DeleteRecording(&dto.DeleteRecordingRequest{Name: first.Name}); err != nil {
+		t.Fatalf("DeleteRecording failed: %v", err)
+	}
+	if err := useCase.DeleteRecording(&dto.DeleteRecordingRequest{Name: first.Name}); err != ErrRecordingNotFound {
+		t.Errorf("Expected ErrRecordingNotFound but got %v", err)
+	}
+}
+
+func TestRecordingUseCase_Disabled(t *testing.T) {
+	useCase := NewRecordingUseCase(nil, 0, 0)
+
+	if _, err := useCase.ListRecordings(); err != ErrRecordingsDisabled {
+		t.Errorf("Expected ErrRecordingsDisabled but got %v", err)
+	}
+	if _, err := useCase.GetRecording(&dto.GetRecordingRequest{Name: "x.mkv"}); err != ErrRecordingsDisabled {
+		t.Errorf("Expected ErrRecordingsDisabled but got %v", err)
+	}
+	if err := useCase.DeleteRecording(&dto.DeleteRecordingRequest{Name: "x.mkv"}); err != ErrRecordingsDisabled {
+		t.Errorf("Expected ErrRecordingsDisabled but got %v", err)
+	}
+	if removed, err := useCase.PruneRecordings(); err != nil || len(removed) != 0 {
+		t.Errorf("Expected nothing to prune, got %v %v", removed, err)
+	}
+}
+
+func TestRecordingUseCase_PruneRecordings(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxBytes  int64
+		retention time.Duration
+		expected  []string
+	}{
+		{name: "no limits"},
+		{name: "retention", retention: 24 * time.Hour, expected: []string{"old"}},
+		{name: "disk limit", maxBytes: 150, expected: []string{"old", "middle"}},
+		{name: "both", maxBytes: 300, retention: 24 * time.Hour, expected: []string{"old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mocks.NewMockRecordingRepository()
+			addRecording(repo, "old", 100, 48*time.Hour)
+			addRecording(repo, "middle", 100, time.Hour)
+			addRecording(repo, "new", 100, time.Minute)
+			useCase := NewRecordingUseCase(repo, tt.maxBytes, tt.retention)
+
+			removed, err := useCase.PruneRecordings()
+			if err != nil {
+				t.Fatalf("PruneRecordings failed: %v", err)
+			}
+			if len(removed) != len(tt.expected) {
+				t.Fatalf("Expected %v removed, got %d", tt.expected, len(removed))
+			}
+			for i, recording := range removed {
+				if recording.Token != tt.expected[i] {
+					t.Errorf("Expected %s removed, got %s", tt.expected[i], recording.Token)
+				}
+			}
+			left, _ := repo.ListRecordings()
+			if len(left) != 3-len(removed) {
+				t.Errorf("Expected %d recordings left, got %d", 3-len(removed), len(left))
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// roomPathPrefix is where the viewer page of a room is served
+const roomPathPrefix = "/r/"
+
+// RoomUseCase implements the room use case interface
+type RoomUseCase struct {
+	roomRepo    interfaces.RoomRepository
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+}
+
+// NewRoomUseCase creates a new room use case
+func NewRoomUseCase(roomRepo interfaces.RoomRepository, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository) *RoomUseCase {
+	return &RoomUseCase{
+		roomRepo:    roomRepo,
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// ClaimRoom points a room at a live session. A free name becomes a new room
+// owned by whoever claims it first, who gets the room key; after that only
+// requests carrying the key can move the room to another session.
+func (uc *RoomUseCase) ClaimRoom(request *dto.ClaimRoomRequest) (*dto.ClaimRoomResponse, error) {
+	name := entities.NormalizeRoomName(request.Name)
+	if name == "" {
+		return nil, ErrInvalidRoomName
+	}
+
+	if _, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	room, err := uc.roomRepo.GetRoom(name)
+	if err != nil {
+		key, err := generateRoomKey()
+		if err != nil {
+			return nil, err
+		}
+		room = &entities.Room{Name: name, Key: key, CreatedAt: now}
+	} else if !room.CheckKey(request.Key) {
+		log.Printf("🔐 Room %s claimed without its key", name)
+		return nil, ErrRoomTaken
+	}
+
+	room.SessionToken = request.Token
+	room.UpdatedAt = now
+	if err := uc.roomRepo.SaveRoom(room); err != nil {
+		log.Printf("❌ Error saving room: %v", err)
+		return nil, err
+	}
+
+	log.Printf("🚪 Room %s now leads to token: %s", name, entities.ShortToken(request.Token))
+	return &dto.ClaimRoomResponse{
+		Name: name,
+		Key:  room.Key,
+		Path: roomPathPrefix + name,
+	}, nil
+}
+
+// GetRoom returns the session a room currently leads to; a room whose last
+// session has ended is reported as not live until its sender starts another
+func (uc *RoomUseCase) GetRoom(request *dto.GetRoomRequest) (*dto.RoomResponse, error) {
+	name := entities.NormalizeRoomName(request.Name)
+	if name == "" {
+		return nil, ErrInvalidRoomName
+	}
+
+	room, err := uc.roomRepo.GetRoom(name)
+	if err != nil {
+		return nil, ErrRoomNotFound
+	}
+
+	response := &dto.RoomResponse{Name: name}
+	if _, err := getLiveSession(uc.sessionRepo, uc.historyRepo, room.SessionToken); err == nil {
+		response.Live = true
+		response.Token = room.SessionToken
+	}
+	return response, nil
+}
+
+// generateRoomKey creates the secret that lets a sender reuse its room
+func generateRoomKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,119 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestRoomUseCase_ClaimRoom(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	sessionRepo.SetSession(mocks.NewTestSession("first-token", false))
+	sessionRepo.SetSession(mocks.NewTestSession("second-token", false))
+	useCase := NewRoomUseCase(mocks.NewMockRoomRepository(), sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+	claimed, err := useCase.ClaimRoom(&dto.ClaimRoomRequest{Name: "Design-Review", Token: "first-token"})
+	if err != nil {
+		t.Fatalf("Expected the free room to be claimed, got %v", err)
+	}
+	if claimed.Name != "design-review" || claimed.Path != "/r/design-review" || claimed.Key == "" {
+		t.Fatalf("Unexpected claim response %+v", claimed)
+	}
+
+	tests := []struct {
+		name          string
+		request       *dto.ClaimRoomRequest
+		expectedError error
+	}{
+		{
+			name:          "invalid name",
+			request:       &dto.ClaimRoomRequest{Name: "design review", Token: "second-token"},
+			expectedError: ErrInvalidRoomName,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.ClaimRoomRequest{Name: "design-review", Token: "missing", Key: claimed.Key},
+			expectedError: ErrSessionNotFound,
+		},
+		{
+			name:          "another sender without the key",
+			request:       &dto.ClaimRoomRequest{Name: "design-review", Token: "second-token"},
+			expectedError: ErrRoomTaken,
+		},
+		{
+			name:          "another sender with a wrong key",
+			request:       &dto.ClaimRoomRequest{Name: "design-review", Token: "second-token", Key: "guess"},
+			expectedError: ErrRoomTaken,
+		},
+		{
+			name:    "owner moves the room to a new session",
+			request: &dto.ClaimRoomRequest{Name: "design-review", Token: "second-token", Key: claimed.Key},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := useCase.ClaimRoom(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && response.Key != claimed.Key {
+				t.Errorf("Expected the room to keep its key, got %q", response.Key)
+			}
+		})
+	}
+
+	room, err := useCase.GetRoom(&dto.GetRoomRequest{Name: "design-review"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !room.Live || room.Token != "second-token" {
+		t.Errorf("Expected the room to lead to the second session, got %+v", room)
+	}
+}
+
+func TestRoomUseCase_GetRoom(t *testing.T) {
+	tests := []struct {
+		name          string
+		room          string
+		ended         bool
+		expectedLive  bool
+		expectedError error
+	}{
+		{name: "live session", room: "design-review", expectedLive: true},
+		{name: "name is case-insensitive", room: "DESIGN-REVIEW", expectedLive: true},
+		{name: "ended session", room: "design-review", ended: true, expectedLive: false},
+		{name: "unknown room", room: "standup", expectedError: ErrRoomNotFound},
+		{name: "invalid name", room: "../admin", expectedError: ErrInvalidRoomName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := mocks.NewTestSession("test-token", false)
+			if tt.ended {
+				session.End()
+			}
+			sessionRepo := mocks.NewMockSessionRepository()
+			sessionRepo.SetSession(session)
+			roomRepo := mocks.NewMockRoomRepository()
+			roomRepo.SaveRoom(&entities.Room{Name: "design-review", Key: "key", SessionToken: "test-token"})
+			useCase := NewRoomUseCase(roomRepo, sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+			response, err := useCase.GetRoom(&dto.GetRoomRequest{Name: tt.room})
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.Live != tt.expectedLive {
+				t.Errorf("Expected live %v, got %v", tt.expectedLive, response.Live)
+			}
+			if tt.expectedLive != (response.Token == "test-token") {
+				t.Errorf("Expected the token only while live, got %q", response.Token)
+			}
+		})
+	}
+}
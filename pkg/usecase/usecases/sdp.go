@@ -0,0 +1,191 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// SDP limits; browsers' descriptions stay well below them, even with
+// simulcast and every codec they support listed
+const (
+	maxSDPLength        = 32 << 10
+	maxSDPLineLength    = 4 << 10
+	maxSDPMediaSections = 16
+)
+
+// sdpMediaTypes are the media sections peers exchange: the screen's video,
+// its sound and the data channels. Sections of any other type are stripped.
+var sdpMediaTypes = map[string]bool{"audio": true, "video": true, "application": true}
+
+// sdpSessionLineTypes and sdpMediaLineTypes are the line types RFC 8866
+// allows before the first media section and within one
+const (
+	sdpSessionLineTypes = "vosiuepcbtrzka"
+	sdpMediaLineTypes   = "micbka"
+)
+
+// sdpSection is a media section of a description being sanitized
+type sdpSection struct {
+	media string
+	mid   string
+	lines []string
+}
+
+// sanitizeOffer returns offer with its SDP sanitized; see sanitizeSDP
+func sanitizeOffer(offer *entities.WebRTCOffer) (*entities.WebRTCOffer, error) {
+	if offer == nil || !offer.IsValid() || offer.Type != "offer" {
+		return nil, ErrInvalidOffer
+	}
+	sdp, err := sanitizeSDP(offer.SDP)
+	if err != nil {
+		log.Printf("❌ Invalid offer SDP: %v", err)
+		return nil, ErrInvalidOffer
+	}
+	return &entities.WebRTCOffer{Type: offer.Type, SDP: sdp}, nil
+}
+
+// sanitizeAnswer returns answer with its SDP sanitized; see sanitizeSDP
+func sanitizeAnswer(answer *entities.WebRTCAnswer) (*entities.WebRTCAnswer, error) {
+	if answer == nil || !answer.IsValid() || answer.Type != "answer" {
+		return nil, ErrInvalidAnswer
+	}
+	sdp, err := sanitizeSDP(answer.SDP)
+	if err != nil {
+		log.Printf("❌ Invalid answer SDP: %v", err)
+		return nil, ErrInvalidAnswer
+	}
+	return &entities.WebRTCAnswer{Type: answer.Type, SDP: sdp}, nil
+}
+
+// sanitizeSDP checks that sdp is a well-formed session description of a
+// sensible size and returns it normalized: CRLF line endings, no blank lines,
+// and only audio, video and application media sections, with the mids of the
+// sections stripped dropped from the BUNDLE group too
+func sanitizeSDP(sdp string) (string, error) {
+	if len(sdp) > maxSDPLength {
+		return "", fmt.Errorf("description of %d bytes exceeds %d", len(sdp), maxSDPLength)
+	}
+	if !utf8.ValidString(sdp) {
+		return "", errors.New("description is not valid UTF-8")
+	}
+
+	var session []string
+	var sections []*sdpSection
+	seen := make(map[byte]bool)
+	lines := strings.Split(strings.ReplaceAll(sdp, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		if err := checkSDPLine(line); err != nil {
+			return "", fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(session) == 0 && line != "v=0" {
+			return "", errors.New("description does not start with v=0")
+		}
+
+		kind := line[0]
+		if kind == 'm' {
+			section, err := parseSDPMediaLine(line)
+			if err != nil {
+				return "", fmt.Errorf("line %d: %w", i+1, err)
+			}
+			sections = append(sections, section)
+			continue
+		}
+		if len(sections) == 0 {
+			if !strings.ContainsRune(sdpSessionLineTypes, rune(kind)) || kind == 'v' && seen['v'] {
+				return "", fmt.Errorf("line %d: unexpected %c= line in the session section", i+1, kind)
+			}
+			seen[kind] = true
+			session = append(session, line)
+			continue
+		}
+		if !strings.ContainsRune(sdpMediaLineTypes, rune(kind)) {
+			return "", fmt.Errorf("line %d: unexpected %c= line in a media section", i+1, kind)
+		}
+		section := sections[len(sections)-1]
+		if mid, ok := strings.CutPrefix(line, "a=mid:"); ok {
+			section.mid = mid
+		}
+		section.lines = append(section.lines, line)
+	}
+	if !seen['o'] || !seen['s'] || !seen['t'] {
+		return "", errors.New("description lacks an o=, s= or t= line")
+	}
+
+	kept := sections[:0]
+	stripped := make(map[string]bool)
+	for _, section := range sections {
+		if sdpMediaTypes[section.media] {
+			kept = append(kept, section)
+			continue
+		}
+		if section.mid != "" {
+			stripped[section.mid] = true
+		}
+	}
+	switch {
+	case len(kept) == 0:
+		return "", errors.New("description has no audio, video or application section")
+	case len(kept) > maxSDPMediaSections:
+		return "", fmt.Errorf("description has %d media sections, more than %d", len(kept), maxSDPMediaSections)
+	}
+
+	var out strings.Builder
+	for _, line := range session {
+		if group, ok := strings.CutPrefix(line, "a=group:BUNDLE"); ok && len(stripped) > 0 {
+			mids := strings.Fields(group)
+			mids = slices.DeleteFunc(mids, func(mid string) bool { return stripped[mid] })
+			if len(mids) == 0 {
+				continue
+			}
+			line = "a=group:BUNDLE " + strings.Join(mids, " ")
+		}
+		out.WriteString(line + "\r\n")
+	}
+	for _, section := range kept {
+		for _, line := range section.lines {
+			out.WriteString(line + "\r\n")
+		}
+	}
+	return out.String(), nil
+}
+
+// checkSDPLine checks that line is a type letter, "=" and a value of printable
+// characters, within the length limit
+func checkSDPLine(line string) error {
+	if len(line) > maxSDPLineLength {
+		return fmt.Errorf("line of %d bytes exceeds %d", len(line), maxSDPLineLength)
+	}
+	if len(line) < 2 || line[1] != '=' || line[0] < 'a' || line[0] > 'z' {
+		return errors.New("line is not of the form <type>=<value>")
+	}
+	for _, r := range line {
+		if r < ' ' && r != '\t' || r == 0x7f {
+			return errors.New("line holds a control character")
+		}
+	}
+	return nil
+}
+
+// parseSDPMediaLine starts the media section of an m= line of the form
+// "m=<media> <port>[/<count>] <proto> <fmt> ..."
+func parseSDPMediaLine(line string) (*sdpSection, error) {
+	fields := strings.Fields(line[2:])
+	if len(fields) < 4 {
+		return nil, errors.New("m= line lacks a media type, port, protocol or format")
+	}
+	port, _, _ := strings.Cut(fields[1], "/")
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return nil, fmt.Errorf("m= line has an invalid port %q", fields[1])
+	}
+	return &sdpSection{media: fields[0], lines: []string{line}}, nil
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// testSDP builds a minimal valid session description named after label, so
+// tests can tell descriptions apart
+func testSDP(label string) string {
+	return "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=" + label + "\r\nt=0 0\r\n" +
+		"a=group:BUNDLE 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\na=rtpmap:96 VP8/90000\r\n"
+}
+
+func TestSanitizeSDP(t *testing.T) {
+	tests := []struct {
+		name     string
+		sdp      string
+		expected string
+		valid    bool
+	}{
+		{
+			name:     "valid description",
+			sdp:      testSDP("valid"),
+			expected: testSDP("valid"),
+			valid:    true,
+		},
+		{
+			name:     "LF line endings and blank lines",
+			sdp:      strings.ReplaceAll(testSDP("lf"), "\r\n", "\n") + "\n\n",
+			expected: testSDP("lf"),
+			valid:    true,
+		},
+		{
+			name:     "missing final line ending",
+			sdp:      strings.TrimSuffix(testSDP("eol"), "\r\n"),
+			expected: testSDP("eol"),
+			valid:    true,
+		},
+		{
+			name: "unexpected media section",
+			sdp: "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\na=group:BUNDLE 0 1 2\r\n" +
+				"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n" +
+				"m=text 9 RTP/AVP 98\r\na=mid:1\r\n" +
+				"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\na=mid:2\r\n",
+			expected: "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\na=group:BUNDLE 0 2\r\n" +
+				"m=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n" +
+				"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\na=mid:2\r\n",
+			valid: true,
+		},
+		{name: "empty", sdp: ""},
+		{name: "not SDP", sdp: "not sdp"},
+		{name: "no version", sdp: strings.TrimPrefix(testSDP("x"), "v=0\r\n")},
+		{name: "no origin", sdp: strings.Replace(testSDP("x"), "o=- 0 0 IN IP4 127.0.0.1\r\n", "", 1)},
+		{name: "no media section", sdp: "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\n"},
+		{name: "only unexpected media", sdp: "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=image 9 udptl t38\r\n"},
+		{name: "invalid port", sdp: strings.Replace(testSDP("x"), "m=video 9 ", "m=video port ", 1)},
+		{name: "short media line", sdp: strings.Replace(testSDP("x"), "m=video 9 UDP/TLS/RTP/SAVPF 96", "m=video 9", 1)},
+		{name: "session line in a media section", sdp: testSDP("x") + "t=0 0\r\n"},
+		{name: "malformed line", sdp: testSDP("x") + "a:mid\r\n"},
+		{name: "control character", sdp: testSDP("x") + "a=x\x00y\r\n"},
+		{name: "lone carriage return", sdp: testSDP("x") + "a=x\ra=y\r\n"},
+		{name: "line too long", sdp: testSDP("x") + "a=" + strings.Repeat("x", maxSDPLineLength) + "\r\n"},
+		{name: "too large", sdp: testSDP("x") + strings.Repeat("a=x\r\n", maxSDPLength/5)},
+		{name: "too many media sections", sdp: testSDP("x") + strings.Repeat("m=audio 9 UDP/TLS/RTP/SAVPF 111\r\n", maxSDPMediaSections)},
+		{name: "invalid UTF-8", sdp: testSDP("\xff")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sanitized, err := sanitizeSDP(tt.sdp)
+			if (err == nil) != tt.valid {
+				t.Fatalf("Expected valid %v, got %v", tt.valid, err)
+			}
+			if sanitized != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, sanitized)
+			}
+		})
+	}
+}
+
+func TestSanitizeOfferAndAnswer(t *testing.T) {
+	if offer, err := sanitizeOffer(&entities.WebRTCOffer{Type: "offer", SDP: strings.ReplaceAll(testSDP("o"), "\r\n", "\n")}); err != nil || offer.SDP != testSDP("o") {
+		t.Errorf("Expected the offer normalized, got %+v, %v", offer, err)
+	}
+	if _, err := sanitizeOffer(&entities.WebRTCOffer{Type: "answer", SDP: testSDP("o")}); err != ErrInvalidOffer {
+		t.Errorf("Expected ErrInvalidOffer for an answer, got %v", err)
+	}
+	if _, err := sanitizeOffer(nil); err != ErrInvalidOffer {
+		t.Errorf("Expected ErrInvalidOffer for no offer, got %v", err)
+	}
+	if answer, err := sanitizeAnswer(&entities.WebRTCAnswer{Type: "answer", SDP: testSDP("a")}); err != nil || answer.SDP != testSDP("a") {
+		t.Errorf("Expected the answer kept, got %+v, %v", answer, err)
+	}
+	if _, err := sanitizeAnswer(&entities.WebRTCAnswer{Type: "answer", SDP: "v=0"}); err != ErrInvalidAnswer {
+		t.Errorf("Expected ErrInvalidAnswer for a malformed answer, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// SenderProfileUseCase implements the sender profile use case interface
+type SenderProfileUseCase struct {
+	profileRepo interfaces.SenderProfileRepository
+}
+
+// NewSenderProfileUseCase creates a new sender profile use case; profileRepo
+// is nil when profiles are disabled
+func NewSenderProfileUseCase(profileRepo interfaces.SenderProfileRepository) *SenderProfileUseCase {
+	return &SenderProfileUseCase{profileRepo: profileRepo}
+}
+
+// GetProfile returns a sender's defaults, or empty ones when the sender has
+// not saved any yet
+func (uc *SenderProfileUseCase) GetProfile(request *dto.GetSenderProfileRequest) (*dto.SenderProfileResponse, error) {
+	if uc.profileRepo == nil {
+		return nil, ErrProfilesDisabled
+	}
+	if request.ProfileID == "" {
+		return nil, ErrInvalidDevice
+	}
+
+	profile, err := uc.profileRepo.GetProfile(request.ProfileID)
+	if err != nil {
+		profile = &entities.SenderProfile{ID: request.ProfileID}
+	}
+	return toSenderProfile(profile), nil
+}
+
+// UpdateProfile replaces a sender's defaults
+func (uc *SenderProfileUseCase) UpdateProfile(request *dto.UpdateSenderProfileRequest) (*dto.SenderProfileResponse, error) {
+	if uc.profileRepo == nil {
+		return nil, ErrProfilesDisabled
+	}
+	if request.ProfileID == "" {
+		return nil, ErrInvalidDevice
+	}
+
+	profile := &entities.SenderProfile{
+		ID:        request.ProfileID,
+		Preset:    request.Preset,
+		Audio:     request.Audio,
+		Interface: request.Interface,
+		Theme:     request.Theme,
+		UpdatedAt: time.Now(),
+	}
+	if !profile.IsValid() {
+		return nil, ErrInvalidSenderProfile
+	}
+	if profile.Preset != "" && entities.FindQualityPreset(profile.Preset) == nil {
+		return nil, ErrInvalidSenderProfile
+	}
+
+	if err := uc.profileRepo.SaveProfile(profile); err != nil {
+		log.Printf("❌ Error saving sender profile: %v", err)
+		return nil, err
+	}
+
+	log.Printf("👤 Sender profile updated (preset: %q, audio: %t)", profile.Preset, profile.Audio)
+	return toSenderProfile(profile), nil
+}
+
+// DeleteProfile forgets a sender's defaults; forgetting none is not an error
+func (uc *SenderProfileUseCase) DeleteProfile(request *dto.DeleteSenderProfileRequest) error {
+	if uc.profileRepo == nil {
+		return ErrProfilesDisabled
+	}
+	if request.ProfileID == "" {
+		return ErrInvalidDevice
+	}
+
+	if err := uc.profileRepo.DeleteProfile(request.ProfileID); err == nil {
+		log.Printf("👤 Sender profile deleted")
+	}
+	return nil
+}
+
+// toSenderProfile converts a sender profile to its response form, which
+// leaves out the ID
+func toSenderProfile(profile *entities.SenderProfile) *dto.SenderProfileResponse {
+	return &dto.SenderProfileResponse{
+		Preset:    profile.Preset,
+		Audio:     profile.Audio,
+		Interface: profile.Interface,
+		Theme:     profile.Theme,
+		UpdatedAt: profile.UpdatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestSenderProfileUseCase(t *testing.T) {
+	useCase := NewSenderProfileUseCase(mocks.NewMockSenderProfileRepository())
+
+	// A sender that saved nothing gets empty defaults
+	profile, err := useCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: "sender-1"})
+	if err != nil {
+		t.Fatalf("GetProfile failed: %v", err)
+	}
+	if profile.Preset != "" || profile.Audio || !profile.UpdatedAt.IsZero() {
+		t.Errorf("Expected empty defaults, got %+v", profile)
+	}
+
+	update := &dto.UpdateSenderProfileRequest{ProfileID: "sender-1", Preset: "text", Audio: true, Interface: "192.168.1.20", Theme: "high"}
+	if _, err := useCase.UpdateProfile(update); err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	profile, _ = useCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: "sender-1"})
+	if profile.Preset != "text" || !profile.Audio || profile.Interface != "192.168.1.20" || profile.Theme != "high" || profile.UpdatedAt.IsZero() {
+		t.Errorf("Expected the saved defaults, got %+v", profile)
+	}
+	if other, _ := useCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: "sender-2"}); other.Preset != "" {
+		t.Errorf("Expected another sender's defaults to be separate, got %+v", other)
+	}
+
+	if err := useCase.DeleteProfile(&dto.DeleteSenderProfileRequest{ProfileID: "sender-1"}); err != nil {
+		t.Fatalf("DeleteProfile failed: %v", err)
+	}
+	if profile, _ = useCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: "sender-1"}); profile.Preset != "" {
+		t.Errorf("Expected the defaults to be forgotten, got %+v", profile)
+	}
+	if err := useCase.DeleteProfile(&dto.DeleteSenderProfileRequest{ProfileID: "sender-1"}); err != nil {
+		t.Errorf("Expected forgetting no defaults to succeed, got %v", err)
+	}
+
+	if _, err := useCase.GetProfile(&dto.GetSenderProfileRequest{}); err != ErrInvalidDevice {
+		t.Errorf("Expected ErrInvalidDevice without a profile ID, got %v", err)
+	}
+}
+
+func TestSenderProfileUseCase_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		request dto.UpdateSenderProfileRequest
+	}{
+		{name: "unknown preset", request: dto.UpdateSenderProfileRequest{ProfileID: "sender-1", Preset: "cinema"}},
+		{name: "interface not an address", request: dto.UpdateSenderProfileRequest{ProfileID: "sender-1", Interface: "en0"}},
+		{name: "unknown theme", request: dto.UpdateSenderProfileRequest{ProfileID: "sender-1", Theme: "dark"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewSenderProfileUseCase(mocks.NewMockSenderProfileRepository())
+			if _, err := useCase.UpdateProfile(&tt.request); err != ErrInvalidSenderProfile {
+				t.Errorf("Expected ErrInvalidSenderProfile but got %v", err)
+			}
+		})
+	}
+}
+
+func TestSenderProfileUseCase_Disabled(t *testing.T) {
+	useCase := NewSenderProfileUseCase(nil)
+
+	if _, err := useCase.GetProfile(&dto.GetSenderProfileRequest{ProfileID: "sender-1"}); err != ErrProfilesDisabled {
+		t.Errorf("Expected ErrProfilesDisabled, got %v", err)
+	}
+	if _, err := useCase.UpdateProfile(&dto.UpdateSenderProfileRequest{ProfileID: "sender-1"}); err != ErrProfilesDisabled {
+		t.Errorf("Expected ErrProfilesDisabled, got %v", err)
+	}
+	if err := useCase.DeleteProfile(&dto.DeleteSenderProfileRequest{ProfileID: "sender-1"}); err != ErrProfilesDisabled {
+		t.Errorf("Expected ErrProfilesDisabled, got %v", err)
+	}
+}
@@ -1,32 +1,94 @@
 package usecases
 
 import (
+	"time"
+
 	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
 )
 
 // ServerInfoUseCase implements the server info use case interface
 type ServerInfoUseCase struct {
-	networkService interfaces.NetworkService
-	stunServer     string
-	version        string
+	networkService  interfaces.NetworkService
+	publicIPService interfaces.PublicIPService
+	cleanupUseCase  interfaces.CleanupUseCase
+	stunServer      string
+	version         string
+	publicHost      string
+	externalURL     string
 }
 
-// NewServerInfoUseCase creates a new server info use case
-func NewServerInfoUseCase(networkService interfaces.NetworkService, stunServer, version string) *ServerInfoUseCase {
+// NewServerInfoUseCase creates a new server info use case; publicIPService
+// discovers the server's public IP and may be nil, publicHost is the domain
+// the server's certificate is for, if any, externalURL is the base URL
+// viewers outside the LAN use, if any, and cleanupUseCase reports garbage
+// collection and may be nil
+func NewServerInfoUseCase(networkService interfaces.NetworkService, publicIPService interfaces.PublicIPService, stunServer, version, publicHost, externalURL string, cleanupUseCase interfaces.CleanupUseCase) *ServerInfoUseCase {
 	return &ServerInfoUseCase{
-		networkService: networkService,
-		stunServer:     stunServer,
-		version:        version,
+		networkService:  networkService,
+		publicIPService: publicIPService,
+		cleanupUseCase:  cleanupUseCase,
+		stunServer:      stunServer,
+		version:         version,
+		publicHost:      publicHost,
+		externalURL:     externalURL,
 	}
 }
 
 // GetServerInfo returns server information including network details
 func (uc *ServerInfoUseCase) GetServerInfo(host string) (*entities.ServerInfo, error) {
+	lanIPs := uc.networkService.GetLANIPs()
+	lanIP := ""
+	if len(lanIPs) > 0 {
+		lanIP = lanIPs[0]
+	}
+
+	publicIP := ""
+	if uc.publicIPService != nil {
+		publicIP = uc.publicIPService.PublicIP()
+	}
+
 	return &entities.ServerInfo{
-		Host:       host,
-		LANIP:      uc.networkService.GetLANIP(),
-		STUNServer: uc.stunServer,
-		Version:    uc.version,
+		Host:        host,
+		LANIP:       lanIP,
+		LANIPs:      lanIPs,
+		PublicHost:  uc.publicHost,
+		PublicIP:    publicIP,
+		ExternalURL: uc.externalURL,
+		STUNServer:  uc.stunServer,
+		Version:     uc.version,
+		Cleanup:     uc.cleanupStatus(),
 	}, nil
 }
+
+// GetInterfaces lists the server's network interfaces with usable addresses
+func (uc *ServerInfoUseCase) GetInterfaces() *dto.InterfacesResponse {
+	return &dto.InterfacesResponse{
+		Interfaces: uc.networkService.GetInterfaces(),
+		Configured: uc.networkService.InterfaceName(),
+	}
+}
+
+// GetHealth reports the server degraded when its last garbage collection
+// failed or the scheduled ones have stopped running
+func (uc *ServerInfoUseCase) GetHealth() *dto.HealthResponse {
+	health := &dto.HealthResponse{
+		Status:  dto.HealthOK,
+		Version: uc.version,
+		Cleanup: uc.cleanupStatus(),
+	}
+	if cleanup := health.Cleanup; cleanup != nil {
+		if (cleanup.LastRun != nil && cleanup.LastRun.Error != "") || cleanup.IsOverdue(time.Now()) {
+			health.Status = dto.HealthDegraded
+		}
+	}
+	return health
+}
+
+func (uc *ServerInfoUseCase) cleanupStatus() *entities.CleanupStatus {
+	if uc.cleanupUseCase == nil {
+		return nil
+	}
+	return uc.cleanupUseCase.CleanupStatus()
+}
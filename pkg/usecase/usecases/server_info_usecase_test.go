@@ -2,7 +2,10 @@ package usecases
 
 import (
 	"testing"
+	"time"
 
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
 	"share-screen/test/mocks"
 )
 
@@ -13,6 +16,7 @@ func TestServerInfoUseCase_GetServerInfo(t *testing.T) {
 		mockLANIP       string
 		stunServer      string
 		version         string
+		publicHost      string
 		expectedHost    string
 		expectedLANIP   string
 		expectedSTUN    string
@@ -51,6 +55,16 @@ func TestServerInfoUseCase_GetServerInfo(t *testing.T) {
 			expectedSTUN:    "stun:stun.l.google.com:19302",
 			expectedVersion: "1.2.3",
 		},
+		{
+			name:            "certificate for a public host",
+			host:            "share.example.com",
+			mockLANIP:       "192.168.1.100",
+			version:         "1.2.3",
+			publicHost:      "share.example.com",
+			expectedHost:    "share.example.com",
+			expectedLANIP:   "192.168.1.100",
+			expectedVersion: "1.2.3",
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,7 +73,7 @@ func TestServerInfoUseCase_GetServerInfo(t *testing.T) {
 			mockNetworkService := mocks.NewMockNetworkService()
 			mockNetworkService.SetLANIP(tt.mockLANIP)
 
-			useCase := NewServerInfoUseCase(mockNetworkService, tt.stunServer, tt.version)
+			useCase := NewServerInfoUseCase(mockNetworkService, nil, tt.stunServer, tt.version, tt.publicHost, "", nil)
 
 			// Execute
 			result, err := useCase.GetServerInfo(tt.host)
@@ -88,6 +102,113 @@ func TestServerInfoUseCase_GetServerInfo(t *testing.T) {
 			if result.Version != tt.expectedVersion {
 				t.Errorf("Expected version %q but got %q", tt.expectedVersion, result.Version)
 			}
+
+			if result.PublicHost != tt.publicHost {
+				t.Errorf("Expected public host %q but got %q", tt.publicHost, result.PublicHost)
+			}
+		})
+	}
+}
+
+func TestServerInfoUseCase_GetServerInfoLANIPs(t *testing.T) {
+	mockNetworkService := mocks.NewMockNetworkService()
+	mockNetworkService.LANIPsToReturn = []string{"fd00::10", "2001:db8::10"}
+
+	useCase := NewServerInfoUseCase(mockNetworkService, nil, "", "", "", "", nil)
+
+	result, err := useCase.GetServerInfo("localhost:8080")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.LANIP != "fd00::10" {
+		t.Errorf("Expected the first address as LAN IP, got %q", result.LANIP)
+	}
+	if len(result.LANIPs) != 2 || result.LANIPs[1] != "2001:db8::10" {
+		t.Errorf("Expected every LAN address, got %v", result.LANIPs)
+	}
+}
+
+func TestServerInfoUseCase_GetServerInfoWAN(t *testing.T) {
+	publicIPService := mocks.NewMockPublicIPService("203.0.113.7")
+	useCase := NewServerInfoUseCase(mocks.NewMockNetworkService(), publicIPService, "", "", "", "https://share.example.com:8443", nil)
+
+	result, err := useCase.GetServerInfo("localhost:8080")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.PublicIP != "203.0.113.7" {
+		t.Errorf("Expected public IP 203.0.113.7, got %q", result.PublicIP)
+	}
+	if result.ExternalURL != "https://share.example.com:8443" {
+		t.Errorf("Expected the external URL, got %q", result.ExternalURL)
+	}
+}
+
+func TestServerInfoUseCase_GetInterfaces(t *testing.T) {
+	mockNetworkService := mocks.NewMockNetworkService()
+	mockNetworkService.Interface = "en0"
+	mockNetworkService.InterfacesToReturn = []entities.NetworkInterface{
+		{Name: "en0", Addresses: []string{"192.168.1.10"}, Selected: true},
+	}
+
+	useCase := NewServerInfoUseCase(mockNetworkService, nil, "", "", "", "", nil)
+
+	result := useCase.GetInterfaces()
+	if result.Configured != "en0" {
+		t.Errorf("Expected configured interface en0, got %q", result.Configured)
+	}
+	if len(result.Interfaces) != 1 || result.Interfaces[0].Name != "en0" {
+		t.Errorf("Expected the network service's interfaces, got %+v", result.Interfaces)
+	}
+}
+
+func TestServerInfoUseCase_GetHealth(t *testing.T) {
+	tests := []struct {
+		name           string
+		lastRun        *entities.CleanupRun
+		expectedStatus string
+	}{
+		{
+			name:           "not run yet",
+			expectedStatus: dto.HealthOK,
+		},
+		{
+			name:           "recent run",
+			lastRun:        &entities.CleanupRun{StartedAt: time.Now()},
+			expectedStatus: dto.HealthOK,
+		},
+		{
+			name:           "failed run",
+			lastRun:        &entities.CleanupRun{StartedAt: time.Now(), Error: "listing sessions: mock error"},
+			expectedStatus: dto.HealthDegraded,
+		},
+		{
+			name:           "overdue run",
+			lastRun:        &entities.CleanupRun{StartedAt: time.Now().Add(-10 * time.Minute)},
+			expectedStatus: dto.HealthDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanupUseCase := newTestCleanupUseCase(mocks.NewMockSessionRepository())
+			cleanupUseCase.lastRun = tt.lastRun
+			useCase := NewServerInfoUseCase(mocks.NewMockNetworkService(), nil, "", "1.0.0", "", "", cleanupUseCase)
+
+			health := useCase.GetHealth()
+			if health.Status != tt.expectedStatus {
+				t.Errorf("Expected status %q but got %q", tt.expectedStatus, health.Status)
+			}
+			if health.Cleanup == nil || health.Cleanup.IntervalSeconds != 60 {
+				t.Errorf("Expected the cleanup schedule reported, got %+v", health.Cleanup)
+			}
+
+			info, _ := useCase.GetServerInfo("localhost")
+			if info.Cleanup == nil {
+				t.Error("Expected the cleanup schedule in the server info")
+			}
 		})
 	}
 }
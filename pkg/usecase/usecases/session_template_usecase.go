@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// SessionTemplateUseCase implements the session template use case interface
+type SessionTemplateUseCase struct {
+	templateRepo interfaces.SessionTemplateRepository
+}
+
+// NewSessionTemplateUseCase creates a new session template use case
+func NewSessionTemplateUseCase(templateRepo interfaces.SessionTemplateRepository) *SessionTemplateUseCase {
+	return &SessionTemplateUseCase{templateRepo: templateRepo}
+}
+
+// ListTemplates returns the session templates, by name
+func (uc *SessionTemplateUseCase) ListTemplates() (*dto.SessionTemplatesResponse, error) {
+	templates, err := uc.templateRepo.ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SessionTemplatesResponse{Templates: templates}, nil
+}
+
+// SaveTemplate defines a session template, replacing any of the same name;
+// names are case-insensitive. Sessions already started from the template
+// keep the options they started with.
+func (uc *SessionTemplateUseCase) SaveTemplate(request *dto.SaveSessionTemplateRequest) (*entities.SessionTemplate, error) {
+	template := &entities.SessionTemplate{
+		Name:          entities.NormalizeRoomName(request.Name),
+		Preset:        request.Preset,
+		Audio:         request.Audio,
+		RequirePIN:    request.RequirePIN,
+		MaxViewers:    request.MaxViewers,
+		ExpirySeconds: request.ExpirySeconds,
+		UpdatedAt:     time.Now(),
+	}
+	if !template.IsValid() {
+		return nil, ErrInvalidSessionTemplate
+	}
+	if template.Preset != "" && entities.FindQualityPreset(template.Preset) == nil {
+		return nil, ErrInvalidSessionTemplate
+	}
+
+	if err := uc.templateRepo.SaveTemplate(template); err != nil {
+		log.Printf("❌ Error saving session template: %v", err)
+		return nil, err
+	}
+
+	log.Printf("🧩 Saved session template %s", template.Name)
+	return template, nil
+}
+
+// DeleteTemplate removes a session template
+func (uc *SessionTemplateUseCase) DeleteTemplate(request *dto.DeleteSessionTemplateRequest) error {
+	name := entities.NormalizeRoomName(request.Name)
+	if name == "" {
+		return ErrSessionTemplateNotFound
+	}
+	if err := uc.templateRepo.DeleteTemplate(name); err != nil {
+		return ErrSessionTemplateNotFound
+	}
+
+	log.Printf("🗑️  Deleted session template %s", name)
+	return nil
+}
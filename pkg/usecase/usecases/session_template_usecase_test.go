@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"testing"
+
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestSessionTemplateUseCase(t *testing.T) {
+	repo := mocks.NewMockSessionTemplateRepository()
+	useCase := NewSessionTemplateUseCase(repo)
+
+	template, err := useCase.SaveTemplate(&dto.SaveSessionTemplateRequest{Name: " Meeting ", Preset: "text", RequirePIN: true, MaxViewers: 20, ExpirySeconds: 3600})
+	if err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+	if template.Name != "meeting" || template.UpdatedAt.IsZero() {
+		t.Errorf("Expected a normalized, dated template, got %+v", template)
+	}
+	if _, err := useCase.SaveTemplate(&dto.SaveSessionTemplateRequest{Name: "demo", Audio: true}); err != nil {
+		t.Fatalf("SaveTemplate failed: %v", err)
+	}
+
+	response, err := useCase.ListTemplates()
+	if err != nil {
+		t.Fatalf("ListTemplates failed: %v", err)
+	}
+	if len(response.Templates) != 2 || response.Templates[0].Name != "demo" || response.Templates[1].Name != "meeting" {
+		t.Errorf("Expected the templates by name, got %+v", response.Templates)
+	}
+
+	if err := useCase.DeleteTemplate(&dto.DeleteSessionTemplateRequest{Name: "MEETING"}); err != nil {
+		t.Fatalf("DeleteTemplate failed: %v", err)
+	}
+	if err := useCase.DeleteTemplate(&dto.DeleteSessionTemplateRequest{Name: "meeting"}); err != ErrSessionTemplateNotFound {
+		t.Errorf("Expected ErrSessionTemplateNotFound but got %v", err)
+	}
+}
+
+func TestSessionTemplateUseCase_SaveTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		request dto.SaveSessionTemplateRequest
+	}{
+		{name: "no name", request: dto.SaveSessionTemplateRequest{}},
+		{name: "name with spaces", request: dto.SaveSessionTemplateRequest{Name: "all hands"}},
+		{name: "unknown preset", request: dto.SaveSessionTemplateRequest{Name: "meeting", Preset: "cinema"}},
+		{name: "negative viewer limit", request: dto.SaveSessionTemplateRequest{Name: "meeting", MaxViewers: -1}},
+		{name: "viewer limit too high", request: dto.SaveSessionTemplateRequest{Name: "meeting", MaxViewers: 1001}},
+		{name: "expiry too short", request: dto.SaveSessionTemplateRequest{Name: "meeting", ExpirySeconds: 30}},
+		{name: "expiry too long", request: dto.SaveSessionTemplateRequest{Name: "meeting", ExpirySeconds: 86401}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useCase := NewSessionTemplateUseCase(mocks.NewMockSessionTemplateRepository())
+			if _, err := useCase.SaveTemplate(&tt.request); err != ErrInvalidSessionTemplate {
+				t.Errorf("Expected ErrInvalidSessionTemplate but got %v", err)
+			}
+		})
+	}
+}
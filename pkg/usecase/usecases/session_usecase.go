@@ -1,9 +1,18 @@
 package usecases
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"log"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/domain/interfaces"
@@ -11,151 +20,1562 @@ import (
 )
 
 var (
-	ErrSessionNotFound     = errors.New("session not found")
-	ErrSessionExpired      = errors.New("session expired")
-	ErrInvalidOffer        = errors.New("invalid offer")
-	ErrInvalidAnswer       = errors.New("invalid answer")
-	ErrOfferNotFound       = errors.New("offer not found")
-	ErrAnswerNotFound      = errors.New("answer not found")
-	ErrAnswerAlreadyExists = errors.New("answer already exists")
-	ErrSessionNotReady     = errors.New("session not ready for answer")
+	ErrSessionNotFound         = errors.New("session not found")
+	ErrSessionExpired          = errors.New("session expired")
+	ErrInvalidOffer            = errors.New("invalid offer")
+	ErrInvalidAnswer           = errors.New("invalid answer")
+	ErrOfferNotFound           = errors.New("offer not found")
+	ErrAnswerNotFound          = errors.New("answer not found")
+	ErrAnswerAlreadyExists     = errors.New("answer already exists")
+	ErrSessionNotReady         = errors.New("session not ready for answer")
+	ErrInvalidSessionName      = errors.New("invalid session name")
+	ErrSessionEnded            = errors.New("session ended")
+	ErrSessionFull             = errors.New("session full")
+	ErrQueueFull               = errors.New("queue full")
+	ErrViewerNotQueued         = errors.New("viewer not in queue")
+	ErrInvalidNotes            = errors.New("invalid notes")
+	ErrInvalidQuality          = errors.New("invalid quality request")
+	ErrInvalidDevice           = errors.New("invalid device")
+	ErrInvalidPIN              = errors.New("invalid pin")
+	ErrPINLocked               = errors.New("too many wrong pins")
+	ErrTURNNotConfigured       = errors.New("TURN credentials not configured")
+	ErrViewerLinkUsed          = errors.New("viewer link already used")
+	ErrViewerRevoked           = errors.New("viewer removed from session")
+	ErrInvalidRoomName         = errors.New("invalid room name")
+	ErrRoomNotFound            = errors.New("room not found")
+	ErrRoomTaken               = errors.New("room belongs to another sender")
+	ErrChatDisabled            = errors.New("chat not enabled")
+	ErrInvalidChatMessage      = errors.New("invalid chat message")
+	ErrFileRelayDisabled       = errors.New("file relay not enabled")
+	ErrFileTooLarge            = errors.New("file too large")
+	ErrFileNotFound            = errors.New("file not found")
+	ErrSFUDisabled             = errors.New("sfu not enabled")
+	ErrMissingViewerID         = errors.New("missing viewer id")
+	ErrInvalidBitrate          = errors.New("invalid bitrate cap")
+	ErrInvalidFrameRate        = errors.New("invalid frame rate cap")
+	ErrInvalidCodec            = errors.New("invalid video codec")
+	ErrInvalidPreset           = errors.New("unknown quality preset")
+	ErrInvalidLayer            = errors.New("invalid simulcast layer")
+	ErrViewerNotConnected      = errors.New("viewer not connected")
+	ErrInvalidStats            = errors.New("invalid stats")
+	ErrInvalidSenderKey        = errors.New("invalid sender key")
+	ErrNotSessionOwner         = errors.New("session belongs to another sender")
+	ErrInvalidBan              = errors.New("invalid ban: expected a CIDR range or IP address and a reason of at most 200 characters")
+	ErrBanLoopback             = errors.New("loopback addresses cannot be banned")
+	ErrBanNotFound             = errors.New("ban not found")
+	ErrServerBusy              = errors.New("server busy")
+	ErrSFUOfferReset           = errors.New("sfu senders publish again instead of resetting")
+	ErrStaleAnswer             = errors.New("answer to an offer replaced by an ICE restart")
+	ErrInvalidNegotiation      = errors.New("invalid negotiation message: expected an offer or answer description or a candidate")
+	ErrFramesUnavailable       = errors.New("no VP8 video to take snapshots of")
+	ErrRecordingsDisabled      = errors.New("recordings not enabled")
+	ErrRecordingNotFound       = errors.New("recording not found")
+	ErrInvalidViewerLink       = errors.New("invalid viewer link: expected a positive ttlSeconds and a maxRedemptions of 0 or more")
+	ErrViewerLinkNotFound      = errors.New("viewer link not found")
+	ErrViewerLinkExpired       = errors.New("viewer link expired")
+	ErrTooManyViewerLinks      = errors.New("too many viewer links")
+	ErrViewerAliasNotFound     = errors.New("viewer path not found")
+	ErrInvitesDisabled         = errors.New("invites not enabled")
+	ErrInviteAlreadyPosted     = errors.New("invite already posted")
+	ErrInviteNotPosted         = errors.New("invite could not be posted")
+	ErrSessionTemplateNotFound = errors.New("session template not found")
+	ErrInvalidSessionTemplate  = errors.New("invalid session template: expected a room-style name, a known preset, maxViewers from 0 to 1000 and expirySeconds of 0 or from 60 to 86400")
+	ErrProfilesDisabled        = errors.New("sender profiles not enabled")
+	ErrInvalidSenderProfile    = errors.New("invalid sender profile: expected a known preset, an IP address as the interface and a theme of high or normal")
+	ErrPairingUnavailable      = errors.New("session cannot pair devices: it was not started from a browser")
+	ErrInvalidPairing          = errors.New("invalid pairing: expected a device name of at most 64 characters")
+	ErrPairingCodeNotFound     = errors.New("pairing code not found")
+	ErrDeviceNotTrusted        = errors.New("device not trusted")
+	ErrTrustedDeviceNotFound   = errors.New("trusted device not found")
+	ErrE2EEDisabled            = errors.New("end-to-end encryption not enabled")
+	ErrInvalidKeyMessage       = errors.New("invalid key message: expected a viewer's public key, or a wrapped content key or rotation from the sender, base64url-encoded")
+	ErrFingerprintsUnavailable = errors.New("no peer-to-peer descriptions to take DTLS fingerprints from")
+	ErrInvalidViewerName       = errors.New("invalid viewer name: expected at most 40 characters of printable text")
 )
 
+// errViewerAliasesTaken is returned when no free viewer alias was found
+var errViewerAliasesTaken = errors.New("no free viewer alias found")
+
+// DefaultHeartbeatTimeout is how long a sender page may stay silent before its
+// session goes stale; background tabs throttle timers, so this is well above
+// the client ping interval
+const DefaultHeartbeatTimeout = 2 * time.Minute
+
+const (
+	// maxSessionNameLength limits names shown in link previews
+	maxSessionNameLength = 80
+
+	// maxViewerNameLength limits the names viewers give themselves, which
+	// the sender page shows in one line
+	maxViewerNameLength = 40
+
+	// pinRange is the number of possible session PINs (6 digits)
+	pinRange = 1000000
+
+	// viewerAliasPathPrefix is where viewers open a session by its alias
+	viewerAliasPathPrefix = "/v/"
+
+	// maxViewerAliasAttempts bounds the random picks for a free viewer alias;
+	// with far fewer live sessions than aliases the first nearly always is
+	maxViewerAliasAttempts = 20
+
+	// minBitrateCapKbps and maxBitrateCapKbps bound a session's bitrate cap;
+	// below the minimum even a still screen turns to mush
+	minBitrateCapKbps = 100
+	maxBitrateCapKbps = 100000
+)
+
+// MaxFrameRateCap bounds a session's frame rate cap, the most a browser
+// captures a screen at
+const MaxFrameRateCap = 60
+
+// SessionDefaults are the server-wide settings of new sessions; the zero
+// value of each field gives the built-in behaviour
+type SessionDefaults struct {
+	// HeartbeatTimeout is how long a sender may go silent before its session
+	// goes stale; 0 uses DefaultHeartbeatTimeout
+	HeartbeatTimeout time.Duration
+
+	// IdleTimeout is how long a session waits for its first viewer; 0 lets
+	// it wait until the token expires
+	IdleTimeout time.Duration
+
+	// MaxBitrateKbps and MaxFrameRate cap sessions that ask for no cap of
+	// their own; 0 leaves them uncapped
+	MaxBitrateKbps int
+	MaxFrameRate   int
+
+	// Codec is the codec preference of sessions that state none
+	Codec entities.CodecPreference
+
+	// MaxSessions caps the live sessions; 0 leaves them unlimited
+	MaxSessions int
+}
+
 // SessionUseCase implements the session use case interface
 type SessionUseCase struct {
 	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	auditRepo   interfaces.AuditLogRepository
+	publisher   interfaces.EventPublisher
 	tokenExpiry time.Duration
+
+	// templateRepo holds the session templates; nil when there are none
+	templateRepo interfaces.SessionTemplateRepository
+
+	// relay forwards the streams of SFU sessions; nil when the server runs no SFU
+	relay interfaces.StreamRelay
+
+	// defaults are given to every new session that asks for nothing else.
+	// createMu makes counting the live sessions and creating one a single
+	// step, so concurrent requests cannot overshoot defaults.MaxSessions.
+	defaults SessionDefaults
+	createMu sync.Mutex
+
+	// aliasMu makes picking a free viewer alias and storing it one step
+	aliasMu sync.Mutex
 }
 
-// NewSessionUseCase creates a new session use case
-func NewSessionUseCase(sessionRepo interfaces.SessionRepository, tokenExpiry time.Duration) *SessionUseCase {
-	return &SessionUseCase{
-		sessionRepo: sessionRepo,
-		tokenExpiry: tokenExpiry,
+// NewSessionUseCase creates a new session use case; templateRepo may be nil
+// for a server without session templates, relay may be nil to stream every
+// session peer-to-peer, and defaults configures new sessions
+func NewSessionUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, auditRepo interfaces.AuditLogRepository, templateRepo interfaces.SessionTemplateRepository, publisher interfaces.EventPublisher, relay interfaces.StreamRelay, tokenExpiry time.Duration, defaults SessionDefaults) *SessionUseCase {
+	if defaults.HeartbeatTimeout <= 0 {
+		defaults.HeartbeatTimeout = DefaultHeartbeatTimeout
 	}
+	uc := &SessionUseCase{
+		sessionRepo:  sessionRepo,
+		historyRepo:  historyRepo,
+		auditRepo:    auditRepo,
+		templateRepo: templateRepo,
+		publisher:    publisher,
+		tokenExpiry:  tokenExpiry,
+		relay:        relay,
+		defaults:     defaults,
+	}
+	if relay != nil {
+		relay.OnViewersChanged(uc.recordSFUViewers)
+	}
+	return uc
 }
 
 // CreateSession creates a new screen sharing session
-func (uc *SessionUseCase) CreateSession() (*dto.CreateSessionResponse, error) {
-	session, err := uc.sessionRepo.CreateSession(uc.tokenExpiry)
+func (uc *SessionUseCase) CreateSession(request *dto.CreateSessionRequest) (*dto.CreateSessionResponse, error) {
+	if request == nil {
+		request = &dto.CreateSessionRequest{}
+	}
+
+	name, err := validSessionName(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	expiry := uc.tokenExpiry
+	var template *entities.SessionTemplate
+	if request.Template != "" {
+		if template, err = uc.getTemplate(request.Template); err != nil {
+			return nil, err
+		}
+		request = applyTemplate(request, template)
+		if template.ExpirySeconds > 0 {
+			expiry = template.Expiry()
+		}
+	}
+
+	var preset *entities.QualityPreset
+	if request.Preset != "" {
+		if preset = entities.FindQualityPreset(request.Preset); preset == nil {
+			return nil, ErrInvalidPreset
+		}
+	}
+
+	maxBitrate := request.MaxBitrateKbps
+	if maxBitrate != 0 && (maxBitrate < minBitrateCapKbps || maxBitrate > maxBitrateCapKbps) {
+		return nil, ErrInvalidBitrate
+	}
+	if maxBitrate == 0 && preset != nil {
+		maxBitrate = preset.MaxBitrateKbps
+	}
+	if maxBitrate == 0 {
+		maxBitrate = uc.defaults.MaxBitrateKbps
+	}
+
+	maxFrameRate := request.MaxFrameRate
+	if maxFrameRate < 0 || maxFrameRate > MaxFrameRateCap {
+		return nil, ErrInvalidFrameRate
+	}
+	if maxFrameRate == 0 {
+		maxFrameRate = uc.defaults.MaxFrameRate
+	}
+
+	codec := uc.defaults.Codec
+	if request.VideoCodec != "" {
+		parsed, err := entities.ParseVideoCodec(request.VideoCodec)
+		if err != nil {
+			return nil, ErrInvalidCodec
+		}
+		codec = entities.CodecPreference{Codec: parsed, Force: request.ForceCodec}
+	}
+
+	if uc.defaults.MaxSessions > 0 {
+		uc.createMu.Lock()
+		defer uc.createMu.Unlock()
+
+		live, err := listLiveSessions(uc.sessionRepo)
+		if err != nil {
+			return nil, err
+		}
+		if len(live) >= uc.defaults.MaxSessions {
+			log.Printf("🚦 New session refused: %d of %d sessions live", len(live), uc.defaults.MaxSessions)
+			return nil, ErrServerBusy
+		}
+	}
+
+	session, err := uc.sessionRepo.CreateSession(expiry)
 	if err != nil {
 		log.Printf("❌ Error creating session: %v", err)
 		return nil, err
 	}
 
+	if session.SenderKey, err = generateSenderKey(); err != nil {
+		log.Printf("❌ Error generating sender key: %v", err)
+		return nil, err
+	}
+
+	if request.RequirePIN {
+		if session.PIN, err = generatePIN(); err != nil {
+			log.Printf("❌ Error generating session PIN: %v", err)
+			return nil, err
+		}
+	}
+
+	uc.aliasMu.Lock()
+	defer uc.aliasMu.Unlock()
+	if session.Alias, err = uc.newViewerAlias(); err != nil {
+		// The session still works through its token
+		log.Printf("⚠️  No viewer alias for token %s: %v", entities.ShortToken(session.Token), err)
+	}
+
+	session.Name = name
+	session.Owner = request.Owner
+	session.User = request.User
+	session.PreviewDisabled = request.DisablePreview
+	session.SFU = request.SFU && uc.relay != nil
+	// A link for a whole audience cannot stop working after the first viewer
+	session.SingleUse = !request.ReusableLink && !session.SFU
+	session.ChatEnabled = request.Chat
+	session.HeartbeatTimeout = uc.defaults.HeartbeatTimeout
+	session.IdleTimeout = uc.defaults.IdleTimeout
+	session.MaxBitrateKbps = maxBitrate
+	session.MaxFrameRate = maxFrameRate
+	session.Codec = codec
+	if preset != nil {
+		session.Preset = preset.ID
+	}
+	session.Audio = request.Audio
+	session.E2EE = request.E2EE
+	if template != nil {
+		session.Template = template.Name
+		session.MaxViewers = template.MaxViewers
+	}
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error storing session options: %v", err)
+		return nil, err
+	}
+
 	log.Printf("🚀 Sender session started with token: %s...", session.Token[:8])
+	created := &entities.AuditEvent{Token: session.Token, Type: entities.AuditSessionCreated, ClientIP: request.ClientIP}
+	if request.User != nil {
+		created.User = request.User.Subject
+	}
+	uc.audit(created)
+
+	return newCreateSessionResponse(session), nil
+}
+
+// ResumeSession reattaches a reloaded sender page to its session, which it
+// proves it owns with the sender key it was given, and returns the session's
+// options so the page can offer again on the same token
+func (uc *SessionUseCase) ResumeSession(request *dto.ResumeSessionRequest) (*dto.CreateSessionResponse, error) {
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.CheckSenderKey(request.SenderKey) {
+		log.Printf("🔒 Wrong sender key for token: %s", entities.ShortToken(request.Token))
+		return nil, ErrInvalidSenderKey
+	}
+
+	session.Reattach()
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error resuming session: %v", err)
+		return nil, err
+	}
+
+	log.Printf("🔄 Sender resumed session for token: %s", entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditResumed, ClientIP: request.ClientIP})
+	return newCreateSessionResponse(session), nil
+}
 
+// newCreateSessionResponse describes a session to its sender
+func newCreateSessionResponse(session *entities.Session) *dto.CreateSessionResponse {
 	return &dto.CreateSessionResponse{
-		Token: session.Token,
-	}, nil
+		Token:     session.Token,
+		PIN:       session.PIN,
+		SingleUse: session.SingleUse,
+		Chat:      session.ChatEnabled,
+		SFU:       session.SFU,
+
+		MaxBitrateKbps: session.MaxBitrateKbps,
+		MaxFrameRate:   session.MaxFrameRate,
+		VideoCodec:     string(session.Codec.Codec),
+		ForceCodec:     session.Codec.Force,
+		Preset:         entities.FindQualityPreset(session.Preset),
+		SenderKey:      session.SenderKey,
+		Paused:         session.Paused,
+		ViewerPath:     viewerAliasPath(session),
+		Audio:          session.Audio,
+		E2EE:           session.E2EE,
+		MaxViewers:     session.MaxViewers,
+		Template:       session.Template,
+	}
 }
 
-// SubmitOffer submits a WebRTC offer for a session
-func (uc *SessionUseCase) SubmitOffer(request *dto.SubmitOfferRequest) error {
-	if request.Offer == nil || !request.Offer.IsValid() {
-		return ErrInvalidOffer
+// getTemplate returns the session template of the given name; names are
+// case-insensitive
+func (uc *SessionUseCase) getTemplate(name string) (*entities.SessionTemplate, error) {
+	name = entities.NormalizeRoomName(name)
+	if uc.templateRepo == nil || name == "" {
+		return nil, ErrSessionTemplateNotFound
+	}
+	template, err := uc.templateRepo.GetTemplate(name)
+	if err != nil {
+		return nil, ErrSessionTemplateNotFound
 	}
+	return template, nil
+}
 
-	session, err := uc.sessionRepo.GetSession(request.Token)
+// applyTemplate returns a copy of request with the template's options in
+// place of the sender's. A template without a PIN still lets the sender ask
+// for one, and one without a preset lets the sender pick it.
+func applyTemplate(request *dto.CreateSessionRequest, template *entities.SessionTemplate) *dto.CreateSessionRequest {
+	applied := *request
+	applied.Audio = template.Audio
+	applied.RequirePIN = request.RequirePIN || template.RequirePIN
+	if template.Preset != "" {
+		applied.Preset = template.Preset
+	}
+	return &applied
+}
+
+// viewerAliasPath is where viewers open a session by its alias, if it has one
+func viewerAliasPath(session *entities.Session) string {
+	if session.Alias == "" {
+		return ""
+	}
+	return viewerAliasPathPrefix + session.Alias
+}
+
+// newViewerAlias picks a viewer alias that no stored session has, ended
+// ones included, since their aliases still answer until they are cleaned
+// up. The caller holds aliasMu until the session is stored, so two new
+// sessions cannot pick the same alias.
+func (uc *SessionUseCase) newViewerAlias() (string, error) {
+	sessions, err := uc.sessionRepo.ListSessions()
 	if err != nil {
-		return ErrSessionNotFound
+		return "", err
+	}
+	taken := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		if session.Alias != "" {
+			taken[strings.ToLower(session.Alias)] = true
+		}
 	}
 
-	if session.IsExpired() {
-		return ErrSessionExpired
+	for attempt := 0; attempt < maxViewerAliasAttempts; attempt++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(entities.ViewerAliasCount))
+		if err != nil {
+			return "", err
+		}
+		if alias := entities.ViewerAliasAt(n.Int64()); !taken[strings.ToLower(alias)] {
+			return alias, nil
+		}
+	}
+	return "", errViewerAliasesTaken
+}
+
+// ResolveViewerAlias returns the token of the session a viewer alias names,
+// in any case. The alias lasts exactly as long as the token: it answers
+// ErrSessionExpired or ErrSessionEnded once the session is over.
+func (uc *SessionUseCase) ResolveViewerAlias(request *dto.ResolveViewerAliasRequest) (*dto.ResolveViewerAliasResponse, error) {
+	if !entities.IsViewerAlias(request.Alias) {
+		return nil, ErrViewerAliasNotFound
+	}
+
+	sessions, err := uc.sessionRepo.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		if !strings.EqualFold(session.Alias, request.Alias) {
+			continue
+		}
+		if _, err := uc.getLiveSession(session.Token); err != nil {
+			return nil, err
+		}
+		return &dto.ResolveViewerAliasResponse{Token: session.Token}, nil
+	}
+	return nil, ErrViewerAliasNotFound
+}
+
+// ListOwnSessions lists the live sessions created by the sender with the given
+// ID, oldest first; senders without an ID have none
+func (uc *SessionUseCase) ListOwnSessions(request *dto.ListOwnSessionsRequest) (*dto.OwnSessionsResponse, error) {
+	response := &dto.OwnSessionsResponse{Sessions: []dto.OwnSession{}}
+	if request.Owner == "" {
+		return response, nil
+	}
+
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(sessions, func(a, b *entities.Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	for _, session := range sessions {
+		if !session.CheckOwner(request.Owner) {
+			continue
+		}
+		viewers := session.SFUViewers
+		if session.IsFull() {
+			viewers++
+		}
+		response.Sessions = append(response.Sessions, dto.OwnSession{
+			Token:     session.Token,
+			Name:      session.Name,
+			Status:    session.Status,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			PIN:       session.PIN,
+			SFU:       session.SFU,
+			Paused:    session.Paused,
+			Viewers:   viewers,
+		})
+	}
+	return response, nil
+}
+
+// RenameSession changes the name of a live session; only the sender that
+// created it may
+func (uc *SessionUseCase) RenameSession(request *dto.RenameSessionRequest) error {
+	name, err := validSessionName(request.Name)
+	if err != nil {
+		return err
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !session.CheckOwner(request.Owner) {
+		log.Printf("🔒 Rename refused for token: %s", entities.ShortToken(request.Token))
+		return ErrNotSessionOwner
+	}
+
+	session.Name = name
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error renaming session: %v", err)
+		return err
+	}
+
+	log.Printf("🏷️  Session renamed for token: %s", entities.ShortToken(request.Token))
+	return nil
+}
+
+// PublishStream connects the sender of an SFU session to the server, which
+// forwards its stream to every viewer. A sender publishes again to replace
+// its stream, e.g. after switching the shared window; viewers stay connected.
+func (uc *SessionUseCase) PublishStream(request *dto.PublishStreamRequest) (response *dto.PublishStreamResponse, err error) {
+	defer func() { uc.auditFailure(request.Token, request.ClientIP, "", err) }()
+
+	offer, err := sanitizeOffer(request.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.SFU {
+		return nil, ErrSFUDisabled
+	}
+
+	// The first stream comes right after the session is created; replacing
+	// it cuts the viewers off the sender's, which only the sender may do
+	if !session.OfferedAt.IsZero() && !request.Ingest && !session.CheckSenderKey(request.SenderKey) {
+		return nil, ErrInvalidSenderKey
+	}
+
+	answer, err := uc.relay.Publish(session.Token, offer)
+	if err != nil {
+		log.Printf("❌ Error publishing to the SFU: %v", err)
+		return nil, ErrInvalidOffer
+	}
+
+	session.Status = entities.SessionStatusActive
+	session.RecordOffer()
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error updating session with published stream: %v", err)
+		return nil, err
+	}
+
+	log.Printf("📡 Stream published to the SFU for token: %s", entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditOffer, ClientIP: request.ClientIP, Detail: "published to the SFU"})
+	// The answer picks the codec the SFU receives and so forwards to viewers
+	return &dto.PublishStreamResponse{Answer: answer.WithCodec(session.Codec).WithBandwidth(session.MaxBitrateKbps).WithFrameRate(session.MaxFrameRate)}, nil
+}
+
+// SelectLayer sets the simulcast layer a viewer of an SFU session receives:
+// one of the layers, or LayerAuto to follow the viewer's connection. A sender
+// without simulcast publishes one layer, which every choice falls back to.
+func (uc *SessionUseCase) SelectLayer(request *dto.SelectLayerRequest) error {
+	if !request.Layer.IsValid() {
+		return ErrInvalidLayer
+	}
+	if request.ViewerID == "" {
+		return ErrMissingViewerID
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !session.SFU {
+		return ErrSFUDisabled
+	}
+
+	if err := uc.relay.SelectLayer(session.Token, request.ViewerID, request.Layer); err != nil {
+		return ErrViewerNotConnected
+	}
+	return nil
+}
+
+// SubmitOffer submits a WebRTC offer for a session. A sender may replace its
+// offer at any time, e.g. after switching the shared window; a connected
+// viewer keeps its slot and is told to renegotiate. An ICE restart offer, sent
+// when the network path of the connection broke, e.g. as a phone roams to
+// another access point, goes to the connected viewer, which answers it on the
+// connection it has.
+func (uc *SessionUseCase) SubmitOffer(request *dto.SubmitOfferRequest) (err error) {
+	defer func() { uc.auditFailure(request.Token, request.ClientIP, "", err) }()
+
+	if request.Offer, err = sanitizeOffer(request.Offer); err != nil {
+		return err
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
 	}
 
 	if !session.CanAcceptOffer() {
 		return errors.New("session cannot accept offer")
 	}
 
+	if request.ICERestart {
+		return uc.restartICE(session, request)
+	}
+
+	// The first offer comes right after the session is created; replacing
+	// it cuts off the connected viewer, which only the sender may do
+	replaced := session.Offer != nil
+	if replaced && !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
+	}
+	renegotiationID, held := holdSlotForRenegotiation(session)
+
 	session.Offer = request.Offer
+	session.ICEGeneration = 0
 	session.Status = entities.SessionStatusActive
+	session.RecordOffer()
 
 	if err := uc.sessionRepo.UpdateSession(session); err != nil {
 		log.Printf("❌ Error updating session with offer: %v", err)
 		return err
 	}
 
-	log.Printf("📤 Offer created for token: %s (type: %s)", request.Token[:8]+"...", request.Offer.Type)
+	if !replaced {
+		log.Printf("📤 Offer created for token: %s (type: %s)", entities.ShortToken(request.Token), request.Offer.Type)
+		uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditOffer, ClientIP: request.ClientIP})
+		return nil
+	}
+
+	log.Printf("🔁 Offer replaced for token: %s (type: %s)", entities.ShortToken(request.Token), request.Offer.Type)
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditOffer, ClientIP: request.ClientIP, Detail: "replaced"})
+	uc.announceRenegotiation(session, renegotiationID, held)
 	return nil
 }
 
-// GetOffer retrieves a WebRTC offer for a session
-func (uc *SessionUseCase) GetOffer(request *dto.GetOfferRequest) (*dto.GetOfferResponse, error) {
-	session, err := uc.sessionRepo.GetSession(request.Token)
-	if err != nil {
-		return nil, ErrSessionNotFound
+// restartICE stores an ICE restart offer and tells the connected viewer to
+// answer it. Like a replaced offer it needs the sender key. The slot is held
+// for the viewer under the ID it answered with, since it keeps its
+// connection; one that did not say who it is gets an ID.
+func (uc *SessionUseCase) restartICE(session *entities.Session, request *dto.SubmitOfferRequest) error {
+	if !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
+	}
+	if !session.IsFull() {
+		return ErrViewerNotConnected
 	}
 
-	if session.IsExpired() {
-		return nil, ErrSessionExpired
+	identified := session.ViewerID != ""
+	viewerID := session.ViewerID
+	if !identified {
+		var err error
+		if viewerID, err = generateViewerID(); err != nil {
+			return err
+		}
 	}
 
-	if session.Offer == nil {
-		log.Printf("❌ Offer not found for token: %s", request.Token[:8]+"...")
-		return nil, ErrOfferNotFound
+	session.Offer = request.Offer
+	session.Answer = nil
+	session.ViewerID = viewerID
+	session.ICEGeneration++
+	session.Reserve(viewerID, reservationTimeout)
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error updating session with ICE restart: %v", err)
+		return err
 	}
 
-	log.Printf("📥 Offer retrieved for token: %s", request.Token[:8]+"...")
-	return &dto.GetOfferResponse{
-		Offer: session.Offer,
-	}, nil
+	log.Printf("🧊 ICE restart %d for token: %s", session.ICEGeneration, entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditOffer, ClientIP: request.ClientIP, Detail: fmt.Sprintf("ICE restart %d", session.ICEGeneration)})
+	// A viewer known by its ID hears about it alone; anyone could listen on
+	// the session topic for the ID that holds the slot
+	topic := entities.SessionTopic(session.Token)
+	if identified {
+		topic = entities.ViewerTopic(session.Token, viewerID)
+	}
+	uc.publisher.Publish(topic, entities.Event{
+		Type: entities.EventICERestart,
+		Data: map[string]any{"viewerId": viewerID, "iceGeneration": session.ICEGeneration},
+	})
+	return nil
 }
 
-// SubmitAnswer submits a WebRTC answer for a session
-func (uc *SessionUseCase) SubmitAnswer(request *dto.SubmitAnswerRequest) error {
-	if request.Answer == nil || !request.Answer.IsValid() {
-		return ErrInvalidAnswer
-	}
+// ResetOffer clears a session's offer and answer and returns it to pending,
+// so the sender can restart its capture, e.g. to share another window,
+// without a new token. It needs the sender key, since viewers have the token. A connected viewer keeps its slot under a reservation
+// and is told to renegotiate; it polls for the offer until the new one comes.
+func (uc *SessionUseCase) ResetOffer(request *dto.ResetOfferRequest) (err error) {
+	defer func() { uc.auditFailure(request.Token, request.ClientIP, "", err) }()
 
-	session, err := uc.sessionRepo.GetSession(request.Token)
+	session, err := uc.getLiveSession(request.Token)
 	if err != nil {
-		return ErrSessionNotFound
+		return err
 	}
 
-	if session.IsExpired() {
-		return ErrSessionExpired
+	if !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
 	}
 
-	if !session.CanAcceptAnswer() {
-		if session.Answer != nil {
-			log.Printf("⚠️  Answer already exists for token: %s", request.Token[:8]+"...")
-			return ErrAnswerAlreadyExists
-		}
-		return ErrSessionNotReady
+	if session.SFU {
+		return ErrSFUOfferReset
 	}
 
-	session.Answer = request.Answer
+	// Retries after a lost response change nothing
+	if session.Offer == nil && session.Status == entities.SessionStatusPending {
+		return nil
+	}
+
+	renegotiationID, held := holdSlotForRenegotiation(session)
+
+	session.Offer = nil
+	session.Answer = nil
+	session.ICEGeneration = 0
+	session.Status = entities.SessionStatusPending
 
 	if err := uc.sessionRepo.UpdateSession(session); err != nil {
-		log.Printf("❌ Error updating session with answer: %v", err)
+		log.Printf("❌ Error resetting offer: %v", err)
 		return err
 	}
 
-	log.Printf("📤 Answer created for token: %s (type: %s)", request.Token[:8]+"...", request.Answer.Type)
-	log.Printf("🎯 WebRTC handshake completed for token: %s", request.Token[:8]+"...")
+	log.Printf("🔄 Offer reset for token: %s", entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditOfferReset, ClientIP: request.ClientIP})
+	uc.announceRenegotiation(session, renegotiationID, held)
 	return nil
 }
 
-// GetAnswer retrieves a WebRTC answer for a session
-func (uc *SessionUseCase) GetAnswer(request *dto.GetAnswerRequest) (*dto.GetAnswerResponse, error) {
-	session, err := uc.sessionRepo.GetSession(request.Token)
+// holdSlotForRenegotiation drops the answer of the connected viewer, if
+// any, and reports whether there was one. A viewer known by its ID, or by the
+// ID that used a single-use link, answers the next offer under it with a
+// reservation, so nobody in the queue can take the slot in between; the ID is
+// returned. One that never said who it is loses the slot, since an ID made up
+// for it could only reach it over the session topic, where anyone with the
+// token would read it.
+func holdSlotForRenegotiation(session *entities.Session) (string, bool) {
+	if !session.IsFull() {
+		return "", false
+	}
+	viewerID := session.ViewerID
+	if session.IsConsumed() {
+		viewerID = session.ConsumedBy
+	}
+	session.Answer = nil
+	session.ViewerID = viewerID
+	if viewerID != "" {
+		session.Reserve(viewerID, reservationTimeout)
+	}
+	return viewerID, true
+}
+
+// announceRenegotiation tells the viewer that held the slot to negotiate
+// again, on its own topic when its ID is known; held false tells nobody
+func (uc *SessionUseCase) announceRenegotiation(session *entities.Session, viewerID string, held bool) {
+	if !held {
+		return
+	}
+	if viewerID == "" {
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventRenegotiate})
+		return
+	}
+	uc.publisher.Publish(entities.ViewerTopic(session.Token, viewerID), entities.Event{
+		Type: entities.EventRenegotiate,
+		Data: map[string]string{"viewerId": viewerID},
+	})
+}
+
+// GetOffer retrieves a WebRTC offer for a session
+func (uc *SessionUseCase) GetOffer(request *dto.GetOfferRequest) (response *dto.GetOfferResponse, err error) {
+	defer func() {
+		// Viewers poll until the offer is there and their turn comes
+		if !errors.Is(err, ErrOfferNotFound) && !errors.Is(err, ErrSessionFull) {
+			uc.auditFailure(request.Token, request.ClientIP, request.ViewerID, err)
+		}
+	}()
+
+	session, err := uc.getLiveSession(request.Token)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
 
-	if session.IsExpired() {
-		return nil, ErrSessionExpired
+	if err := unlockSession(uc.sessionRepo, session, request.PIN); err != nil {
+		return nil, err
 	}
 
-	if session.Answer == nil {
-		log.Printf("❌ Answer not ready for token: %s", request.Token[:8]+"...")
-		return nil, ErrAnswerNotFound
+	if session.IsRevoked(request.ViewerID) {
+		return nil, ErrViewerRevoked
 	}
 
-	log.Printf("📥 Answer retrieved for token: %s", request.Token[:8]+"...")
-	return &dto.GetAnswerResponse{
-		Answer: session.Answer,
+	if session.SFU {
+		return uc.getSFUOffer(session, request.ViewerID)
+	}
+
+	if session.IsConsumedByOther(request.ViewerID) {
+		log.Printf("🔐 Viewer link already used for token: %s", entities.ShortToken(request.Token))
+		return nil, ErrViewerLinkUsed
+	}
+
+	if session.IsFull() || session.IsReservedForOther(request.ViewerID) {
+		return nil, ErrSessionFull
+	}
+
+	if session.Offer == nil {
+		log.Printf("❌ Offer not found for token: %s", entities.ShortToken(request.Token))
+		return nil, ErrOfferNotFound
+	}
+
+	log.Printf("📥 Offer retrieved for token: %s", entities.ShortToken(request.Token))
+	return &dto.GetOfferResponse{
+		Offer:         session.Offer.WithCodec(session.Codec).WithBandwidth(session.MaxBitrateKbps).WithFrameRate(session.MaxFrameRate),
+		Paused:        session.Paused,
+		ICEGeneration: session.ICEGeneration,
+		E2EE:          session.E2EE,
 	}, nil
 }
+
+// SubmitAnswer submits a WebRTC answer for a session
+func (uc *SessionUseCase) SubmitAnswer(request *dto.SubmitAnswerRequest) (err error) {
+	defer func() { uc.auditFailure(request.Token, request.ClientIP, request.ViewerID, err) }()
+
+	if request.Answer, err = sanitizeAnswer(request.Answer); err != nil {
+		return err
+	}
+	if request.Name, err = validViewerName(request.Name); err != nil {
+		return err
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	// Answering takes the slot as much as fetching the offer does
+	if err := unlockSession(uc.sessionRepo, session, request.PIN); err != nil {
+		return err
+	}
+
+	if session.IsRevoked(request.ViewerID) {
+		return ErrViewerRevoked
+	}
+
+	if session.SFU {
+		return uc.submitSFUAnswer(session, request)
+	}
+
+	if !session.CanAcceptAnswer() {
+		if session.Answer != nil {
+			log.Printf("⚠️  Answer already exists for token: %s", entities.ShortToken(request.Token))
+			return ErrAnswerAlreadyExists
+		}
+		return ErrSessionNotReady
+	}
+
+	if session.IsConsumedByOther(request.ViewerID) {
+		return ErrViewerLinkUsed
+	}
+
+	if session.IsReservedForOther(request.ViewerID) {
+		return ErrSessionFull
+	}
+
+	// A late answer to the offer before an ICE restart would break the
+	// restarted connection
+	if request.ICEGeneration != session.ICEGeneration {
+		return ErrStaleAnswer
+	}
+
+	// The first answer uses up a single-use link; viewers queued behind it
+	// will never get the slot
+	var turnedAway []entities.QueuedViewer
+	viewerID := request.ViewerID
+	usedUp := session.SingleUse && !session.IsConsumed()
+	if usedUp {
+		if viewerID == "" {
+			// Viewers that did not say who they are cannot come back
+			if viewerID, err = generateViewerID(); err != nil {
+				return err
+			}
+		}
+		session.Consume(viewerID)
+		turnedAway, session.Queue = session.Queue, nil
+	}
+
+	session.Answer = request.Answer
+	session.ViewerID = viewerID
+	session.ViewerName = request.Name
+	session.ClearReservation()
+	session.RecordAudience()
+	session.RecordConnection()
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error updating session with answer: %v", err)
+		return err
+	}
+
+	if usedUp {
+		log.Printf("🔐 Viewer link used up for token: %s", entities.ShortToken(request.Token))
+	}
+	answered := &entities.AuditEvent{Token: session.Token, Type: entities.AuditAnswer, ClientIP: request.ClientIP, ViewerID: request.ViewerID, ViewerName: request.Name}
+	if session.ICEGeneration > 0 {
+		answered.Detail = fmt.Sprintf("ICE restart %d", session.ICEGeneration)
+	}
+	uc.audit(answered)
+	for _, viewer := range turnedAway {
+		uc.publisher.Publish(entities.ViewerTopic(session.Token, viewer.ID), entities.Event{Type: entities.EventLinkUsed})
+	}
+	if len(turnedAway) > 0 {
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{
+			Type: entities.EventQueueChanged,
+			Data: session.Queue,
+		})
+	}
+
+	log.Printf("📤 Answer created for token: %s (type: %s)", entities.ShortToken(request.Token), request.Answer.Type)
+	log.Printf("🎯 WebRTC handshake completed for token: %s", entities.ShortToken(request.Token))
+	return nil
+}
+
+// getSFUOffer has the SFU offer the session's stream to a viewer; every viewer
+// gets one, so there is no slot to wait for
+func (uc *SessionUseCase) getSFUOffer(session *entities.Session, viewerID string) (*dto.GetOfferResponse, error) {
+	// The viewer's answer is matched to its connection by the viewer ID
+	if viewerID == "" {
+		return nil, ErrMissingViewerID
+	}
+
+	if !uc.relay.IsPublished(session.Token) {
+		return nil, ErrOfferNotFound
+	}
+
+	if session.IsAudienceFull() {
+		log.Printf("🚦 Viewer refused: %d viewers is the limit for token: %s", session.MaxViewers, entities.ShortToken(session.Token))
+		return nil, ErrSessionFull
+	}
+
+	offer, err := uc.relay.Offer(session.Token, viewerID)
+	if err != nil {
+		log.Printf("❌ Error creating SFU offer: %v", err)
+		return nil, err
+	}
+
+	log.Printf("📥 SFU offer created for token: %s", entities.ShortToken(session.Token))
+	return &dto.GetOfferResponse{Offer: offer, Paused: session.Paused, E2EE: session.E2EE}, nil
+}
+
+// submitSFUAnswer connects a viewer to the SFU with its answer to getSFUOffer
+func (uc *SessionUseCase) submitSFUAnswer(session *entities.Session, request *dto.SubmitAnswerRequest) error {
+	if request.ViewerID == "" {
+		return ErrMissingViewerID
+	}
+
+	if err := uc.relay.Answer(session.Token, request.ViewerID, request.Answer); err != nil {
+		log.Printf("❌ Error answering SFU offer: %v", err)
+		return ErrInvalidAnswer
+	}
+
+	log.Printf("🎯 Viewer joined the SFU for token: %s", entities.ShortToken(session.Token))
+	// The viewer connects to the server itself, so its answer completes the handshake
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditAnswer, ClientIP: request.ClientIP, ViewerID: request.ViewerID, ViewerName: request.Name})
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditConnected, ClientIP: request.ClientIP, ViewerID: request.ViewerID, ViewerName: request.Name, Detail: "through the SFU"})
+	return nil
+}
+
+// GetAnswer retrieves a WebRTC answer for a session
+func (uc *SessionUseCase) GetAnswer(request *dto.GetAnswerRequest) (*dto.GetAnswerResponse, error) {
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Answer == nil {
+		log.Printf("❌ Answer not ready for token: %s", entities.ShortToken(request.Token))
+		return nil, ErrAnswerNotFound
+	}
+
+	log.Printf("📥 Answer retrieved for token: %s", entities.ShortToken(request.Token))
+	// The sender has both halves of the handshake once it holds the answer
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditConnected, ClientIP: request.ClientIP, ViewerID: session.ViewerID, ViewerName: session.ViewerName})
+	// The sender's browser picks its encoder and bitrate from the answer, so
+	// the codec preference and the cap go there
+	return &dto.GetAnswerResponse{
+		Answer:     session.Answer.WithCodec(session.Codec).WithBandwidth(session.MaxBitrateKbps).WithFrameRate(session.MaxFrameRate),
+		ViewerID:   session.ViewerID,
+		ViewerName: session.ViewerName,
+	}, nil
+}
+
+// GetSession describes where a session stands. Unlike the signaling calls
+// it answers for sessions that ended or expired, until they are cleaned up,
+// so their peers can tell why the session is over.
+func (uc *SessionUseCase) GetSession(request *dto.GetSessionRequest) (*dto.SessionDetailResponse, error) {
+	session, err := uc.sessionRepo.GetSession(request.Token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	status := session.Status
+	if session.IsExpired() && !session.IsEnded() && !session.IsStale() {
+		status = entities.SessionStatusExpired
+	}
+	viewers := session.SFUViewers
+	if session.IsFull() {
+		viewers++
+	}
+
+	response := &dto.SessionDetailResponse{
+		Token:            session.Token,
+		Status:           status,
+		CreatedAt:        session.CreatedAt,
+		ExpiresAt:        session.ExpiresAt,
+		ExpiresInSeconds: max(int(time.Until(session.ExpiresAt).Seconds()), 0),
+		OfferedAt:        optionalTime(session.OfferedAt),
+		ConnectedAt:      optionalTime(session.ConnectedAt),
+		EndedAt:          optionalTime(session.EndedAt),
+		HasOffer:         session.Offer != nil,
+		HasAnswer:        session.Answer != nil,
+		ICEGeneration:    session.ICEGeneration,
+		Viewers:          viewers,
+		Queued:           len(session.Queue),
+		Paused:           session.Paused,
+		SFU:              session.SFU,
+		RequiresPIN:      session.PIN != "",
+		SingleUse:        session.SingleUse,
+		LinkUsed:         session.IsConsumed(),
+	}
+	if !session.PreviewDisabled {
+		response.Name = session.Name
+	}
+	return response, nil
+}
+
+// GetLinkPreview returns the public details used for viewer link previews
+func (uc *SessionUseCase) GetLinkPreview(request *dto.GetLinkPreviewRequest) (*dto.LinkPreviewResponse, error) {
+	session, err := uc.sessionRepo.GetSession(request.Token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.IsExpired() {
+		return nil, ErrSessionExpired
+	}
+
+	if session.PreviewDisabled {
+		return &dto.LinkPreviewResponse{Enabled: false}, nil
+	}
+
+	return &dto.LinkPreviewResponse{
+		Name:      session.Name,
+		Enabled:   true,
+		Thumbnail: session.SFU && session.PIN == "",
+	}, nil
+}
+
+// Heartbeat records that the sender is still there; senders should call it
+// every few seconds, well within the heartbeat timeout
+func (uc *SessionUseCase) Heartbeat(request *dto.HeartbeatRequest) error {
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	session.RecordTraffic(request.BytesSent, time.Now())
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error recording heartbeat: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ExtendSession gives a live session the full token expiry again from now, so
+// a long share is not cleaned up while it runs; it never shortens the expiry.
+// It needs the sender key, so a viewer cannot keep a session alive.
+func (uc *SessionUseCase) ExtendSession(request *dto.ExtendSessionRequest) (*dto.ExtendSessionResponse, error) {
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.CheckSenderKey(request.SenderKey) {
+		return nil, ErrInvalidSenderKey
+	}
+
+	expiresAt := time.Now().Add(uc.tokenExpiry)
+	if !session.Deadline.IsZero() && expiresAt.After(session.Deadline) {
+		// A signed token stops working at its deadline whatever the session says
+		expiresAt = session.Deadline
+	}
+	if expiresAt.After(session.ExpiresAt) {
+		session.ExpiresAt = expiresAt
+		if err := uc.sessionRepo.UpdateSession(session); err != nil {
+			log.Printf("❌ Error extending session: %v", err)
+			return nil, err
+		}
+		log.Printf("⏰ Session extended for token: %s", entities.ShortToken(request.Token))
+		uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditExtended, ClientIP: request.ClientIP})
+	}
+
+	return &dto.ExtendSessionResponse{
+		ExpiresAt:        session.ExpiresAt,
+		ExpiresInSeconds: int(time.Until(session.ExpiresAt).Seconds()),
+	}, nil
+}
+
+// PauseSession records that the sender paused or resumed its stream and tells
+// the viewers, who cover the picture while it is paused; the sender stops
+// sending the picture itself, so the server never holds back media. It needs
+// the sender key, so a viewer cannot cover the picture for everyone.
+func (uc *SessionUseCase) PauseSession(request *dto.PauseSessionRequest) error {
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
+	}
+
+	// Retries after a lost response change nothing
+	if session.Paused == request.Paused {
+		return nil
+	}
+
+	session.Paused = request.Paused
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error pausing session: %v", err)
+		return err
+	}
+
+	event := entities.AuditUnpaused
+	if session.Paused {
+		event = entities.AuditPaused
+	}
+	log.Printf("⏯️  Session %s for token: %s", event, entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: event, ClientIP: request.ClientIP})
+	uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{
+		Type: entities.EventPaused,
+		Data: map[string]bool{"paused": session.Paused},
+	})
+	return nil
+}
+
+// EndSession ends a session at the sender's request, e.g. when the sender page unloads
+func (uc *SessionUseCase) EndSession(request *dto.EndSessionRequest) error {
+	session, err := uc.sessionRepo.GetSession(request.Token)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+
+	if !request.Ingest && !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
+	}
+
+	// Beacons may be retried or fire after the heartbeat or idle check already ended it
+	if session.IsEnded() || session.IsStale() || session.IsIdleExpired() {
+		return nil
+	}
+
+	// A page that may come back, e.g. after a reload, gets a moment to resume
+	if request.Resumable && session.SenderKey != "" {
+		session.Detach()
+		if err := uc.sessionRepo.UpdateSession(session); err != nil {
+			log.Printf("❌ Error detaching sender: %v", err)
+			return err
+		}
+		log.Printf("⏸️  Sender page left token: %s, waiting %v for it to resume", entities.ShortToken(request.Token), entities.SenderResumeWindow)
+		uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditDetached, ClientIP: request.ClientIP})
+		return nil
+	}
+
+	if err := endSession(uc.sessionRepo, uc.historyRepo, session); err != nil {
+		log.Printf("❌ Error ending session: %v", err)
+		return err
+	}
+	if session.SFU {
+		uc.relay.Close(session.Token)
+	}
+
+	log.Printf("🛑 Session ended by sender for token: %s", entities.ShortToken(request.Token))
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditTerminated, ClientIP: request.ClientIP})
+	return nil
+}
+
+// KickViewer removes a viewer from a session, e.g. when the wrong person
+// opened the link: its connection or place in the queue goes, its viewer ID
+// gets no more offers and its page is told to stop. Requests that do not come
+// through the admin API need the session's sender key.
+func (uc *SessionUseCase) KickViewer(request *dto.KickViewerRequest) error {
+	if request.ViewerID == "" {
+		return ErrMissingViewerID
+	}
+
+	session, err := uc.getLiveSession(request.Token)
+	if err != nil {
+		return err
+	}
+
+	if !request.Admin && !session.CheckSenderKey(request.SenderKey) {
+		return ErrInvalidSenderKey
+	}
+
+	// Retries after a lost response change nothing
+	if session.IsRevoked(request.ViewerID) {
+		return nil
+	}
+
+	connected := session.Revoke(request.ViewerID)
+	admitNext(uc.publisher, session)
+
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error removing viewer: %v", err)
+		return err
+	}
+	if session.SFU {
+		// Viewers that never connected have nothing to close
+		_ = uc.relay.DisconnectViewer(session.Token, request.ViewerID)
+	}
+
+	log.Printf("🚫 Viewer removed from token: %s", entities.ShortToken(request.Token))
+	detail := ""
+	if request.Admin {
+		detail = "by an admin"
+	}
+	uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditKicked, ClientIP: request.ClientIP, ViewerID: request.ViewerID, Detail: detail})
+	uc.publisher.Publish(entities.ViewerTopic(session.Token, request.ViewerID), entities.Event{Type: entities.EventKicked})
+	if connected {
+		uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{Type: entities.EventViewerLeft})
+	}
+	publishQueue(uc.publisher, session)
+	return nil
+}
+
+// MarkStaleSessions gives up the sessions whose sender stopped sending
+// heartbeats, archiving them so they are cleaned up before their token
+// expires. It returns how many sessions went stale.
+func (uc *SessionUseCase) MarkStaleSessions() (int, error) {
+	sessions, err := uc.sessionRepo.ListSessions()
+	if err != nil {
+		log.Printf("❌ Error listing sessions: %v", err)
+		return 0, err
+	}
+
+	stale := 0
+	for _, session := range sessions {
+		if session.IsEnded() || session.IsStale() || session.IsExpired() || !session.ShouldGoStale() {
+			continue
+		}
+		if err := markStale(uc.sessionRepo, uc.historyRepo, session); err != nil {
+			log.Printf("❌ Error marking session stale: %v", err)
+			return stale, err
+		}
+		uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditStale, Detail: "sender heartbeat lost"})
+		stale++
+	}
+	return stale, nil
+}
+
+// ExpireIdleSessions expires the sessions that stalled before any viewer
+// connected, so abandoned sessions do not wait out their token expiry. It
+// returns how many were expired.
+func (uc *SessionUseCase) ExpireIdleSessions() (int, error) {
+	sessions, err := uc.sessionRepo.ListSessions()
+	if err != nil {
+		log.Printf("❌ Error listing sessions: %v", err)
+		return 0, err
+	}
+
+	idle := 0
+	for _, session := range sessions {
+		if session.IsEnded() || session.IsStale() || session.IsExpired() || !session.IsIdle() {
+			continue
+		}
+		if err := expireIdle(uc.sessionRepo, uc.historyRepo, session); err != nil {
+			log.Printf("❌ Error expiring idle session: %v", err)
+			return idle, err
+		}
+		if session.SFU {
+			uc.relay.Close(session.Token)
+		}
+		idle++
+	}
+	return idle, nil
+}
+
+// CleanupExpiredSessions removes the sessions whose token expired, noting in
+// each one's audit log how it ended. It returns how many were removed.
+func (uc *SessionUseCase) CleanupExpiredSessions() (int, error) {
+	sessions, err := uc.sessionRepo.ListSessions()
+	if err != nil {
+		log.Printf("❌ Error listing sessions: %v", err)
+		return 0, err
+	}
+
+	for _, session := range sessions {
+		if !session.IsExpired() {
+			continue
+		}
+		detail := "token expired"
+		switch {
+		case session.IsEnded():
+			detail = "removed after the sender ended it"
+		case session.IsStale():
+			detail = "removed after the sender's heartbeat was lost"
+		case session.IsIdleExpired():
+			detail = "removed after idling before a viewer connected"
+		}
+		uc.audit(&entities.AuditEvent{Token: session.Token, Type: entities.AuditExpired, Detail: detail})
+	}
+
+	return uc.sessionRepo.CleanupExpiredSessions()
+}
+
+// PruneStreams disconnects the SFU streams of sessions that are no longer
+// live, returning how many were closed
+func (uc *SessionUseCase) PruneStreams() int {
+	if uc.relay == nil {
+		return 0
+	}
+
+	pruned := 0
+	for _, token := range uc.relay.GetTokens() {
+		if _, err := uc.getLiveSession(token); err == nil {
+			continue
+		}
+		uc.relay.Close(token)
+		pruned++
+	}
+	return pruned
+}
+
+// recordSFUViewers stores how many viewers watch an SFU session and tells
+// its sender
+func (uc *SessionUseCase) recordSFUViewers(token string, viewers int) {
+	session, err := uc.sessionRepo.GetSession(token)
+	if err != nil {
+		return
+	}
+
+	session.SFUViewers = viewers
+	session.RecordAudience()
+	if viewers > 0 {
+		session.RecordConnection()
+	}
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error recording SFU viewers: %v", err)
+		return
+	}
+
+	log.Printf("👥 %d viewers on the SFU for token: %s", viewers, entities.ShortToken(token))
+	uc.publisher.Publish(entities.SessionTopic(token), entities.Event{
+		Type: entities.EventSFUViewers,
+		Data: map[string]int{"viewers": viewers},
+	})
+}
+
+// audit appends an event to its session's audit log, stamped with the
+// current time, and passes it on to the admin dashboard; an event that
+// cannot be stored is only logged
+func (uc *SessionUseCase) audit(event *entities.AuditEvent) {
+	event.At = time.Now()
+	if err := uc.auditRepo.AppendEvent(event); err != nil {
+		log.Printf("❌ Error recording %s audit event: %v", event.Type, err)
+	}
+	uc.publisher.Publish(entities.AdminTopic, entities.Event{Type: entities.EventSession, Data: *event})
+}
+
+// auditFailure records a failed request in the audit log of its session.
+// Requests for unknown sessions have no log to go to, and must not start one
+// that pushes out the logs of real sessions.
+func (uc *SessionUseCase) auditFailure(token, clientIP, viewerID string, err error) {
+	if err == nil || errors.Is(err, ErrSessionNotFound) {
+		return
+	}
+	if _, lookupErr := uc.sessionRepo.GetSession(token); lookupErr != nil {
+		return
+	}
+	uc.audit(&entities.AuditEvent{Token: token, Type: entities.AuditError, ClientIP: clientIP, ViewerID: viewerID, Detail: err.Error()})
+}
+
+// getLiveSession loads a session and rejects it if it has expired, was ended,
+// or its sender stopped sending heartbeats
+func (uc *SessionUseCase) getLiveSession(token string) (*entities.Session, error) {
+	return getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+}
+
+// pinFailureMu keeps concurrent wrong PINs from overwriting each other's count
+var pinFailureMu sync.Mutex
+
+// unlockSession checks pin against a protected session for every use case
+// viewers reach with the PIN. Wrong PINs are counted on the session, and
+// once entities.MaxPINFailures are reached it stays locked; an empty PIN is
+// a viewer finding out that one is needed, not a guess.
+func unlockSession(sessionRepo interfaces.SessionRepository, session *entities.Session, pin string) error {
+	if session.PINLocked() {
+		return ErrPINLocked
+	}
+	if session.CheckPIN(pin) {
+		return nil
+	}
+	if pin == "" {
+		return ErrInvalidPIN
+	}
+
+	log.Printf("🔒 Wrong PIN for token: %s", entities.ShortToken(session.Token))
+	pinFailureMu.Lock()
+	defer pinFailureMu.Unlock()
+	// Count on the stored session, which may have moved on since it was loaded
+	current, err := sessionRepo.GetSession(session.Token)
+	if err != nil {
+		return ErrInvalidPIN
+	}
+	current.RecordPINFailure()
+	if err := sessionRepo.UpdateSession(current); err != nil {
+		log.Printf("❌ Error counting a wrong PIN: %v", err)
+	}
+	if current.PINLocked() {
+		log.Printf("🔒 Locked token after %d wrong PINs: %s", entities.MaxPINFailures, entities.ShortToken(session.Token))
+	}
+	return ErrInvalidPIN
+}
+
+// getLiveSession is shared by the use cases that operate on running sessions
+func getLiveSession(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, token string) (*entities.Session, error) {
+	session, err := sessionRepo.GetSession(token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if session.IsEnded() || session.IsStale() {
+		return nil, ErrSessionEnded
+	}
+
+	if session.IsExpired() {
+		return nil, ErrSessionExpired
+	}
+
+	if session.ShouldGoStale() {
+		if err := markStale(sessionRepo, historyRepo, session); err != nil {
+			log.Printf("❌ Error marking session stale: %v", err)
+		}
+		return nil, ErrSessionEnded
+	}
+
+	if session.IsIdle() {
+		if err := expireIdle(sessionRepo, historyRepo, session); err != nil {
+			log.Printf("❌ Error expiring idle session: %v", err)
+		}
+		return nil, ErrSessionExpired
+	}
+
+	return session, nil
+}
+
+// listLiveSessions returns the sessions that are neither ended, expired nor
+// abandoned by their sender
+func listLiveSessions(sessionRepo interfaces.SessionRepository) ([]*entities.Session, error) {
+	sessions, err := sessionRepo.ListSessions()
+	if err != nil {
+		log.Printf("❌ Error listing sessions: %v", err)
+		return nil, err
+	}
+
+	live := sessions[:0]
+	for _, session := range sessions {
+		if !session.IsEnded() && !session.IsStale() && !session.IsExpired() && !session.ShouldGoStale() && !session.IsIdle() {
+			live = append(live, session)
+		}
+	}
+	return live, nil
+}
+
+// validSessionName trims a session name and checks that it fits link previews
+func validSessionName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxSessionNameLength {
+		return "", ErrInvalidSessionName
+	}
+	return name, nil
+}
+
+// validViewerName trims the name a viewer gave itself and checks that it fits
+// the sender page; control and formatting characters, which could hide or
+// reorder text, are refused
+func validViewerName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > maxViewerNameLength || !utf8.ValidString(name) {
+		return "", ErrInvalidViewerName
+	}
+	if strings.IndexFunc(name, func(r rune) bool { return unicode.IsControl(r) || unicode.Is(unicode.Cf, r) }) >= 0 {
+		return "", ErrInvalidViewerName
+	}
+	return name, nil
+}
+
+// generateSenderKey creates the secret that lets a sender page resume its session
+func generateSenderKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// generatePIN returns a random 6-digit PIN
+func generatePIN() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(pinRange))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// endSession marks a session as ended and archives its summary to history
+func endSession(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, session *entities.Session) error {
+	session.End()
+	return archiveSession(sessionRepo, historyRepo, session)
+}
+
+// markStale marks a session whose sender went silent and archives its summary
+func markStale(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, session *entities.Session) error {
+	session.MarkStale()
+	log.Printf("💔 Sender heartbeat lost for token: %s, session is stale", entities.ShortToken(session.Token))
+	return archiveSession(sessionRepo, historyRepo, session)
+}
+
+// expireIdle expires a session that stalled before any viewer connected and
+// archives its summary to history
+func expireIdle(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, session *entities.Session) error {
+	session.ExpireIdle()
+	log.Printf("💤 No viewer connected for token: %s, session expired early", entities.ShortToken(session.Token))
+	return archiveSession(sessionRepo, historyRepo, session)
+}
+
+// archiveSession stores a finished session and saves its summary to history
+func archiveSession(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, session *entities.Session) error {
+	if err := sessionRepo.UpdateSession(session); err != nil {
+		return err
+	}
+
+	// The session itself is still ended if archiving fails, only the summary is lost
+	if err := historyRepo.SaveRecord(entities.NewSessionRecord(session)); err != nil {
+		log.Printf("❌ Error saving session history: %v", err)
+	}
+	return nil
+}
+
+// optionalTime returns nil for the zero time, so it is left out of responses
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
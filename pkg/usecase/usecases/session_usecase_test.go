@@ -1,10 +1,13 @@
 package usecases
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
 	"share-screen/pkg/usecase/dto"
 	"share-screen/test/mocks"
 )
@@ -33,10 +36,10 @@ func TestSessionUseCase_CreateSession(t *testing.T) {
 			mockRepo := mocks.NewMockSessionRepository()
 			mockRepo.ShouldFailCreateSession = tt.shouldFailCreate
 
-			useCase := NewSessionUseCase(mockRepo, 30*time.Minute)
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
 
 			// Execute
-			response, err := useCase.CreateSession()
+			response, err := useCase.CreateSession(&dto.CreateSessionRequest{})
 
 			// Assert
 			if tt.shouldFailCreate {
@@ -74,7 +77,7 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 				Token: "test-token",
 				Offer: &entities.WebRTCOffer{
 					Type: "offer",
-					SDP:  "test-sdp",
+					SDP:  testSDP("test-sdp"),
 				},
 			},
 			setupSession: func(repo *mocks.MockSessionRepository) {
@@ -103,7 +106,7 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 				Token: "test-token",
 				Offer: &entities.WebRTCOffer{
 					Type: "",
-					SDP:  "test-sdp",
+					SDP:  testSDP("test-sdp"),
 				},
 			},
 			setupSession:  func(repo *mocks.MockSessionRepository) {},
@@ -115,7 +118,7 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 				Token: "non-existent-token",
 				Offer: &entities.WebRTCOffer{
 					Type: "offer",
-					SDP:  "test-sdp",
+					SDP:  testSDP("test-sdp"),
 				},
 			},
 			setupSession:  func(repo *mocks.MockSessionRepository) {},
@@ -127,7 +130,7 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 				Token: "expired-token",
 				Offer: &entities.WebRTCOffer{
 					Type: "offer",
-					SDP:  "test-sdp",
+					SDP:  testSDP("test-sdp"),
 				},
 			},
 			setupSession: func(repo *mocks.MockSessionRepository) {
@@ -149,7 +152,7 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 			mockRepo := mocks.NewMockSessionRepository()
 			tt.setupSession(mockRepo)
 
-			useCase := NewSessionUseCase(mockRepo, 30*time.Minute)
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
 
 			// Execute
 			err := useCase.SubmitOffer(tt.request)
@@ -171,6 +174,332 @@ func TestSessionUseCase_SubmitOffer(t *testing.T) {
 	}
 }
 
+func TestSessionUseCase_ReplaceOffer(t *testing.T) {
+	tests := []struct {
+		name              string
+		answer            *entities.WebRTCAnswer
+		viewerID          string
+		expectRenegotiate bool
+	}{
+		{
+			name:              "no viewer connected yet",
+			expectRenegotiate: false,
+		},
+		{
+			name:              "connected viewer renegotiates",
+			answer:            &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+			viewerID:          "phone",
+			expectRenegotiate: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(&entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(30 * time.Minute),
+				Status:    entities.SessionStatusActive,
+				Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-sdp")},
+				Answer:    tt.answer,
+				ViewerID:  tt.viewerID,
+				SenderKey: "sender-key",
+			})
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+			err := useCase.SubmitOffer(&dto.SubmitOfferRequest{
+				Token:     "test-token",
+				Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("second-sdp")},
+				SenderKey: "sender-key",
+			})
+			if err != nil {
+				t.Fatalf("Expected the offer to be replaced, got %v", err)
+			}
+
+			session, _ := mockRepo.GetSession("test-token")
+			if session.Offer.SDP != testSDP("second-sdp") || session.Answer != nil {
+				t.Errorf("Expected the new offer without an answer, got offer %+v answer %+v", session.Offer, session.Answer)
+			}
+
+			// Anyone with the token listens on the session topic, so the ID
+			// holding the slot never goes there
+			if events := publisher.Published(entities.SessionTopic("test-token")); len(events) != 0 {
+				t.Errorf("Expected nothing on the session topic, got %+v", events)
+			}
+			events := publisher.Published(entities.ViewerTopic("test-token", tt.viewerID))
+			if !tt.expectRenegotiate {
+				if len(events) != 0 || session.ReservedFor != "" {
+					t.Errorf("Expected no renegotiation, got events %+v reserved for %q", events, session.ReservedFor)
+				}
+				return
+			}
+
+			if len(events) != 1 || events[0].Type != entities.EventRenegotiate {
+				t.Fatalf("Expected a renegotiate event for the viewer, got %+v", events)
+			}
+			if session.ReservedFor != tt.viewerID {
+				t.Fatalf("Expected the slot reserved for %q, got %q", tt.viewerID, session.ReservedFor)
+			}
+
+			// Someone else cannot take the slot while the viewer renegotiates
+			if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token"}); err != ErrSessionFull {
+				t.Errorf("Expected ErrSessionFull for another viewer, got %v", err)
+			}
+			if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: tt.viewerID}); err != nil {
+				t.Errorf("Expected the renegotiating viewer to get the offer, got %v", err)
+			}
+			err = useCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+				Token:    "test-token",
+				ViewerID: tt.viewerID,
+				Answer:   &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("renegotiated-sdp")},
+			})
+			if err != nil {
+				t.Errorf("Expected the renegotiated answer to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_ReplaceOffer_WrongSenderKey(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-sdp")},
+		Answer:    &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+		ViewerID:  "phone",
+		SenderKey: "sender-key",
+	})
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	// A viewer has the token but not the key
+	for _, key := range []string{"", "wrong-key"} {
+		err := useCase.SubmitOffer(&dto.SubmitOfferRequest{
+			Token:     "test-token",
+			Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("viewer-offer")},
+			SenderKey: key,
+		})
+		if err != ErrInvalidSenderKey {
+			t.Errorf("Expected ErrInvalidSenderKey for key %q, got %v", key, err)
+		}
+	}
+
+	session, _ := mockRepo.GetSession("test-token")
+	if session.Offer.SDP != testSDP("first-sdp") || session.Answer == nil {
+		t.Errorf("Expected the sender's offer and the viewer's answer kept, got offer %+v answer %+v", session.Offer, session.Answer)
+	}
+	if events := publisher.Published(entities.ViewerTopic("test-token", "phone")); len(events) != 0 {
+		t.Errorf("Expected no renegotiation, got %+v", events)
+	}
+}
+
+func TestSessionUseCase_ICERestart(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-sdp")},
+		Answer:    &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+		ViewerID:  "phone",
+		SenderKey: "sender-key",
+	})
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	// A viewer has the token but not the key
+	err := useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:      "test-token",
+		Offer:      &entities.WebRTCOffer{Type: "offer", SDP: testSDP("viewer-sdp")},
+		ICERestart: true,
+	})
+	if err != ErrInvalidSenderKey {
+		t.Fatalf("Expected ErrInvalidSenderKey without the sender key, got %v", err)
+	}
+	if events := publisher.Published(entities.ViewerTopic("test-token", "phone")); len(events) != 0 {
+		t.Fatalf("Expected no ice-restart event, got %+v", events)
+	}
+
+	err = useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:      "test-token",
+		Offer:      &entities.WebRTCOffer{Type: "offer", SDP: testSDP("restart-sdp")},
+		SenderKey:  "sender-key",
+		ICERestart: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected the ICE restart to be accepted, got %v", err)
+	}
+
+	session, _ := mockRepo.GetSession("test-token")
+	if session.ICEGeneration != 1 || session.Answer != nil || session.ReservedFor != "phone" {
+		t.Errorf("Expected generation 1 held for the phone, got generation %d answer %+v reserved for %q", session.ICEGeneration, session.Answer, session.ReservedFor)
+	}
+
+	// Only the connected viewer hears about it, under the ID it has
+	events := publisher.Published(entities.ViewerTopic("test-token", "phone"))
+	if len(events) != 1 || events[0].Type != entities.EventICERestart {
+		t.Fatalf("Expected an ice-restart event for the phone, got %+v", events)
+	}
+	if events := publisher.Published(entities.SessionTopic("test-token")); len(events) != 0 {
+		t.Errorf("Expected nothing on the session topic, got %+v", events)
+	}
+
+	offer, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: "phone"})
+	if err != nil || offer.Offer.SDP != testSDP("restart-sdp") || offer.ICEGeneration != 1 {
+		t.Fatalf("Expected the restart offer of generation 1, got %+v (%v)", offer, err)
+	}
+
+	// A late answer to the offer before the restart is refused
+	err = useCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+		Token:    "test-token",
+		ViewerID: "phone",
+		Answer:   &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("late-sdp")},
+	})
+	if err != ErrStaleAnswer {
+		t.Errorf("Expected ErrStaleAnswer, got %v", err)
+	}
+
+	err = useCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+		Token:         "test-token",
+		ViewerID:      "phone",
+		Answer:        &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("restarted-sdp")},
+		ICEGeneration: 1,
+	})
+	if err != nil {
+		t.Fatalf("Expected the restart answer to be accepted, got %v", err)
+	}
+
+	// A new offer starts the count again
+	err = useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:     "test-token",
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("second-sdp")},
+		SenderKey: "sender-key",
+	})
+	if err != nil {
+		t.Fatalf("Expected the offer to be replaced, got %v", err)
+	}
+	if session, _ := mockRepo.GetSession("test-token"); session.ICEGeneration != 0 {
+		t.Errorf("Expected the generation reset by a new offer, got %d", session.ICEGeneration)
+	}
+}
+
+func TestSessionUseCase_ICERestart_NoViewer(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-sdp")},
+		SenderKey: "sender-key",
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	err := useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:      "test-token",
+		Offer:      &entities.WebRTCOffer{Type: "offer", SDP: testSDP("restart-sdp")},
+		SenderKey:  "sender-key",
+		ICERestart: true,
+	})
+	if err != ErrViewerNotConnected {
+		t.Errorf("Expected ErrViewerNotConnected, got %v", err)
+	}
+	if session, _ := mockRepo.GetSession("test-token"); session.Offer.SDP != testSDP("first-sdp") {
+		t.Errorf("Expected the offer kept, got %+v", session.Offer)
+	}
+}
+
+func TestSessionUseCase_ResetOffer(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		OfferedAt: time.Now(),
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-window")},
+		Answer:    &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+		ViewerID:  "phone",
+		SenderKey: "sender-key",
+	})
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	// A viewer has the token but not the key
+	for _, key := range []string{"", "wrong-key"} {
+		if err := useCase.ResetOffer(&dto.ResetOfferRequest{Token: "test-token", SenderKey: key}); err != ErrInvalidSenderKey {
+			t.Errorf("Expected ErrInvalidSenderKey for key %q, got %v", key, err)
+		}
+	}
+	if session, _ := mockRepo.GetSession("test-token"); session.Offer == nil || session.Answer == nil {
+		t.Fatalf("Expected the session kept as it was, got offer %+v answer %+v", session.Offer, session.Answer)
+	}
+
+	if err := useCase.ResetOffer(&dto.ResetOfferRequest{Token: "test-token", SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Expected the offer to be reset, got %v", err)
+	}
+
+	session, _ := mockRepo.GetSession("test-token")
+	if session.Offer != nil || session.Answer != nil || session.Status != entities.SessionStatusPending {
+		t.Errorf("Expected a pending session without descriptions, got status %s offer %+v answer %+v", session.Status, session.Offer, session.Answer)
+	}
+
+	// The connected viewer waits for the next offer in its slot
+	viewerID := "phone"
+	events := publisher.Published(entities.ViewerTopic("test-token", viewerID))
+	if len(events) != 1 || events[0].Type != entities.EventRenegotiate {
+		t.Fatalf("Expected a renegotiate event for the viewer, got %+v", events)
+	}
+	if session.ReservedFor != viewerID {
+		t.Fatalf("Expected the slot reserved for %q, got %q", viewerID, session.ReservedFor)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: viewerID}); err != ErrOfferNotFound {
+		t.Errorf("Expected ErrOfferNotFound until the sender offers again, got %v", err)
+	}
+
+	// Retrying the reset changes nothing
+	if err := useCase.ResetOffer(&dto.ResetOfferRequest{Token: "test-token", SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Expected a repeated reset to succeed, got %v", err)
+	}
+	if events := publisher.Published(entities.ViewerTopic("test-token", viewerID)); len(events) != 1 {
+		t.Errorf("Expected no second renegotiation, got %+v", events)
+	}
+
+	err := useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token: "test-token",
+		Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("second-window")},
+	})
+	if err != nil {
+		t.Fatalf("Expected a new offer under the same token, got %v", err)
+	}
+	if offer, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: viewerID}); err != nil || offer.Offer.SDP != testSDP("second-window") {
+		t.Errorf("Expected the viewer to get the new offer, got %+v (%v)", offer, err)
+	}
+}
+
+func TestSessionUseCase_ResetOffer_SFU(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		SFU:       true,
+		SenderKey: "sender-key",
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	if err := useCase.ResetOffer(&dto.ResetOfferRequest{Token: "test-token", SenderKey: "sender-key"}); err != ErrSFUOfferReset {
+		t.Errorf("Expected ErrSFUOfferReset, got %v", err)
+	}
+}
+
 func TestSessionUseCase_GetOffer(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -192,7 +521,7 @@ func TestSessionUseCase_GetOffer(t *testing.T) {
 					Status:    entities.SessionStatusActive,
 					Offer: &entities.WebRTCOffer{
 						Type: "offer",
-						SDP:  "test-sdp",
+						SDP:  testSDP("test-sdp"),
 					},
 				}
 				repo.SetSession(session)
@@ -227,6 +556,86 @@ func TestSessionUseCase_GetOffer(t *testing.T) {
 			expectedError:   ErrOfferNotFound,
 			shouldHaveOffer: false,
 		},
+		{
+			name: "session full",
+			request: &dto.GetOfferRequest{
+				Token: "test-token",
+			},
+			setupSession: func(repo *mocks.MockSessionRepository) {
+				session := &entities.Session{
+					Token:     "test-token",
+					CreatedAt: time.Now(),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+					Status:    entities.SessionStatusActive,
+					Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+					Answer:    &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("test-answer")},
+				}
+				repo.SetSession(session)
+			},
+			expectedError:   ErrSessionFull,
+			shouldHaveOffer: false,
+		},
+		{
+			name: "slot reserved for another viewer",
+			request: &dto.GetOfferRequest{
+				Token:    "test-token",
+				ViewerID: "late-viewer",
+			},
+			setupSession: func(repo *mocks.MockSessionRepository) {
+				session := &entities.Session{
+					Token:         "test-token",
+					CreatedAt:     time.Now(),
+					ExpiresAt:     time.Now().Add(30 * time.Minute),
+					Status:        entities.SessionStatusActive,
+					Offer:         &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+					ReservedFor:   "queued-viewer",
+					ReservedUntil: time.Now().Add(time.Minute),
+				}
+				repo.SetSession(session)
+			},
+			expectedError:   ErrSessionFull,
+			shouldHaveOffer: false,
+		},
+		{
+			name: "correct PIN",
+			request: &dto.GetOfferRequest{
+				Token: "test-token",
+				PIN:   "482913",
+			},
+			setupSession: func(repo *mocks.MockSessionRepository) {
+				session := &entities.Session{
+					Token:     "test-token",
+					CreatedAt: time.Now(),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+					Status:    entities.SessionStatusActive,
+					Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+					PIN:       "482913",
+				}
+				repo.SetSession(session)
+			},
+			expectedError:   nil,
+			shouldHaveOffer: true,
+		},
+		{
+			name: "wrong PIN",
+			request: &dto.GetOfferRequest{
+				Token: "test-token",
+				PIN:   "000000",
+			},
+			setupSession: func(repo *mocks.MockSessionRepository) {
+				session := &entities.Session{
+					Token:     "test-token",
+					CreatedAt: time.Now(),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+					Status:    entities.SessionStatusActive,
+					Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+					PIN:       "482913",
+				}
+				repo.SetSession(session)
+			},
+			expectedError:   ErrInvalidPIN,
+			shouldHaveOffer: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -235,7 +644,7 @@ func TestSessionUseCase_GetOffer(t *testing.T) {
 			mockRepo := mocks.NewMockSessionRepository()
 			tt.setupSession(mockRepo)
 
-			useCase := NewSessionUseCase(mockRepo, 30*time.Minute)
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
 
 			// Execute
 			response, err := useCase.GetOffer(tt.request)
@@ -281,7 +690,7 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 				Token: "test-token",
 				Answer: &entities.WebRTCAnswer{
 					Type: "answer",
-					SDP:  "test-answer-sdp",
+					SDP:  testSDP("test-answer-sdp"),
 				},
 			},
 			setupSession: func(repo *mocks.MockSessionRepository) {
@@ -292,7 +701,7 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 					Status:    entities.SessionStatusActive,
 					Offer: &entities.WebRTCOffer{
 						Type: "offer",
-						SDP:  "test-sdp",
+						SDP:  testSDP("test-sdp"),
 					},
 					Answer: nil,
 				}
@@ -315,7 +724,7 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 				Token: "test-token",
 				Answer: &entities.WebRTCAnswer{
 					Type: "answer",
-					SDP:  "test-answer-sdp",
+					SDP:  testSDP("test-answer-sdp"),
 				},
 			},
 			setupSession: func(repo *mocks.MockSessionRepository) {
@@ -326,11 +735,11 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 					Status:    entities.SessionStatusActive,
 					Offer: &entities.WebRTCOffer{
 						Type: "offer",
-						SDP:  "test-sdp",
+						SDP:  testSDP("test-sdp"),
 					},
 					Answer: &entities.WebRTCAnswer{
 						Type: "answer",
-						SDP:  "existing-answer-sdp",
+						SDP:  testSDP("existing-answer-sdp"),
 					},
 				}
 				repo.SetSession(session)
@@ -345,7 +754,7 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 			mockRepo := mocks.NewMockSessionRepository()
 			tt.setupSession(mockRepo)
 
-			useCase := NewSessionUseCase(mockRepo, 30*time.Minute)
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
 
 			// Execute
 			err := useCase.SubmitAnswer(tt.request)
@@ -366,3 +775,1790 @@ func TestSessionUseCase_SubmitAnswer(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionUseCase_SubmitAnswer_ViewerName(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+	mockRepo.SetSession(&entities.Session{
+		Token:     "named-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+	})
+	answer := &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("test-answer-sdp")}
+
+	for _, name := range []string{strings.Repeat("a", maxViewerNameLength+1), "Ari\u202eenohPi", "Ari\nBob"} {
+		err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: "named-token", Answer: answer, ViewerID: "viewer-1", Name: name})
+		if err != ErrInvalidViewerName {
+			t.Errorf("Expected ErrInvalidViewerName for %q, got %v", name, err)
+		}
+	}
+
+	err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: "named-token", Answer: answer, ViewerID: "viewer-1", Name: "  Ari’s iPhone "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	response, err := useCase.GetAnswer(&dto.GetAnswerRequest{Token: "named-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.ViewerName != "Ari’s iPhone" {
+		t.Errorf("Expected the trimmed name for the sender, got %q", response.ViewerName)
+	}
+
+	events, _ := auditRepo.ListEvents("named-token")
+	named := 0
+	for _, event := range events {
+		if event.Type == entities.AuditAnswer || event.Type == entities.AuditConnected {
+			if event.ViewerName != "Ari’s iPhone" {
+				t.Errorf("Expected the name in the %s event, got %q", event.Type, event.ViewerName)
+			}
+			named++
+		}
+	}
+	if named != 2 {
+		t.Errorf("Expected answer and connected events, got %+v", events)
+	}
+
+	session, _ := mockRepo.GetSession("named-token")
+	if !session.Revoke("viewer-1") || session.ViewerName != "" {
+		t.Errorf("Expected the name dropped with the viewer, got %q", session.ViewerName)
+	}
+}
+func TestSessionUseCase_CreateSession_Options(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	response, err := useCase.CreateSession(&dto.CreateSessionRequest{
+		Name:           "  Design review  ",
+		DisablePreview: true,
+		Chat:           true,
+		E2EE:           true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	session, err := mockRepo.GetSession(response.Token)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if session.Name != "Design review" {
+		t.Errorf("Expected trimmed name %q but got %q", "Design review", session.Name)
+	}
+	if !session.PreviewDisabled {
+		t.Error("Expected preview to be disabled")
+	}
+	if !session.ChatEnabled || !response.Chat {
+		t.Error("Expected chat to be enabled")
+	}
+	if !session.E2EE || !response.E2EE {
+		t.Error("Expected end-to-end encryption to be enabled")
+	}
+
+	longName := make([]byte, maxSessionNameLength+1)
+	for i := range longName {
+		longName[i] = 'a'
+	}
+	if _, err := useCase.CreateSession(&dto.CreateSessionRequest{Name: string(longName)}); err != ErrInvalidSessionName {
+		t.Errorf("Expected ErrInvalidSessionName but got %v", err)
+	}
+}
+
+func TestSessionUseCase_CreateSession_BitrateAndPreset(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultKbps   int
+		requestKbps   int
+		preset        string
+		expectedKbps  int
+		expectedError error
+	}{
+		{name: "uncapped", defaultKbps: 0, requestKbps: 0, expectedKbps: 0},
+		{name: "server default", defaultKbps: 2500, requestKbps: 0, expectedKbps: 2500},
+		{name: "session cap overrides default", defaultKbps: 2500, requestKbps: 800, expectedKbps: 800},
+		{name: "too low", requestKbps: minBitrateCapKbps - 1, expectedError: ErrInvalidBitrate},
+		{name: "too high", requestKbps: maxBitrateCapKbps + 1, expectedError: ErrInvalidBitrate},
+		{name: "negative", requestKbps: -1, expectedError: ErrInvalidBitrate},
+		{name: "preset cap overrides default", defaultKbps: 2500, preset: "low", expectedKbps: 600},
+		{name: "session cap overrides preset", preset: "low", requestKbps: 800, expectedKbps: 800},
+		{name: "unknown preset", preset: "cinema", expectedError: ErrInvalidPreset},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{MaxBitrateKbps: tt.defaultKbps})
+
+			response, err := useCase.CreateSession(&dto.CreateSessionRequest{MaxBitrateKbps: tt.requestKbps, Preset: tt.preset})
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v but got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.MaxBitrateKbps != tt.expectedKbps {
+				t.Errorf("Expected cap %d in the response but got %d", tt.expectedKbps, response.MaxBitrateKbps)
+			}
+			session, _ := mockRepo.GetSession(response.Token)
+			if session.MaxBitrateKbps != tt.expectedKbps {
+				t.Errorf("Expected stored cap %d but got %d", tt.expectedKbps, session.MaxBitrateKbps)
+			}
+			if session.Preset != tt.preset || (tt.preset != "") != (response.Preset != nil) {
+				t.Errorf("Expected preset %q, got %q in the session and %+v in the response", tt.preset, session.Preset, response.Preset)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_CreateSession_FrameRate(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultFPS    int
+		requestFPS    int
+		expectedFPS   int
+		expectedError error
+	}{
+		{name: "uncapped", expectedFPS: 0},
+		{name: "server default", defaultFPS: 15, expectedFPS: 15},
+		{name: "session cap overrides default", defaultFPS: 15, requestFPS: 5, expectedFPS: 5},
+		{name: "session may raise the default", defaultFPS: 15, requestFPS: 30, expectedFPS: 30},
+		{name: "too high", requestFPS: MaxFrameRateCap + 1, expectedError: ErrInvalidFrameRate},
+		{name: "negative", requestFPS: -1, expectedError: ErrInvalidFrameRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{MaxFrameRate: tt.defaultFPS})
+
+			response, err := useCase.CreateSession(&dto.CreateSessionRequest{MaxFrameRate: tt.requestFPS})
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v but got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.MaxFrameRate != tt.expectedFPS {
+				t.Errorf("Expected cap %d in the response but got %d", tt.expectedFPS, response.MaxFrameRate)
+			}
+			session, _ := mockRepo.GetSession(response.Token)
+			if session.MaxFrameRate != tt.expectedFPS {
+				t.Errorf("Expected stored cap %d but got %d", tt.expectedFPS, session.MaxFrameRate)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_CreateSession_Template(t *testing.T) {
+	templateRepo := mocks.NewMockSessionTemplateRepository()
+	templateRepo.SaveTemplate(&entities.SessionTemplate{Name: "meeting", Preset: "text", Audio: true, RequirePIN: true, MaxViewers: 20, ExpirySeconds: 3600})
+	templateRepo.SaveTemplate(&entities.SessionTemplate{Name: "demo"})
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), templateRepo, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	// The template's options win over the sender's; names are case-insensitive
+	response, err := useCase.CreateSession(&dto.CreateSessionRequest{Template: "Meeting", Preset: "motion"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Template != "meeting" || !response.Audio || response.MaxViewers != 20 || response.PIN == "" || response.Preset == nil || response.Preset.ID != "text" {
+		t.Errorf("Expected the meeting template's options, got %+v", response)
+	}
+	session, _ := mockRepo.GetSession(response.Token)
+	if expiry := session.ExpiresAt.Sub(session.CreatedAt); expiry < 59*time.Minute || expiry > 61*time.Minute {
+		t.Errorf("Expected the template's expiry of an hour, got %v", expiry)
+	}
+
+	// A template that leaves an option open keeps the sender's choice
+	response, err = useCase.CreateSession(&dto.CreateSessionRequest{Template: "demo", RequirePIN: true, Audio: true, Preset: "motion"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.PIN == "" || response.Preset == nil || response.Preset.ID != "motion" || response.Audio || response.MaxViewers != 0 {
+		t.Errorf("Expected the sender's PIN and preset without sound, got %+v", response)
+	}
+	session, _ = mockRepo.GetSession(response.Token)
+	if expiry := session.ExpiresAt.Sub(session.CreatedAt); expiry < 29*time.Minute || expiry > 31*time.Minute {
+		t.Errorf("Expected the server's expiry, got %v", expiry)
+	}
+
+	if _, err := useCase.CreateSession(&dto.CreateSessionRequest{Template: "webinar"}); err != ErrSessionTemplateNotFound {
+		t.Errorf("Expected ErrSessionTemplateNotFound but got %v", err)
+	}
+	withoutTemplates := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+	if _, err := withoutTemplates.CreateSession(&dto.CreateSessionRequest{Template: "meeting"}); err != ErrSessionTemplateNotFound {
+		t.Errorf("Expected ErrSessionTemplateNotFound without templates but got %v", err)
+	}
+}
+
+func TestSessionUseCase_RelayedSDP(t *testing.T) {
+	const sdp = "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96 102\r\nc=IN IP4 0.0.0.0\r\na=mid:0\r\n" +
+		"a=rtpmap:96 VP8/90000\r\na=rtpmap:102 H264/90000\r\n"
+
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:          "capped-token",
+		Status:         entities.SessionStatusActive,
+		CreatedAt:      time.Now(),
+		ExpiresAt:      time.Now().Add(30 * time.Minute),
+		Offer:          &entities.WebRTCOffer{Type: "offer", SDP: sdp},
+		MaxBitrateKbps: 1500,
+		MaxFrameRate:   10,
+		Codec:          entities.CodecPreference{Codec: entities.VideoCodecH264, Force: true},
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	offer, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "capped-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error getting offer: %v", err)
+	}
+	if err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: "capped-token", Answer: &entities.WebRTCAnswer{Type: "answer", SDP: sdp}}); err != nil {
+		t.Fatalf("Unexpected error submitting answer: %v", err)
+	}
+	answer, err := useCase.GetAnswer(&dto.GetAnswerRequest{Token: "capped-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error getting answer: %v", err)
+	}
+	for name, got := range map[string]string{"offer": offer.Offer.SDP, "answer": answer.Answer.SDP} {
+		if !strings.Contains(got, "b=AS:1500\r\n") || !strings.Contains(got, "b=TIAS:1500000\r\n") {
+			t.Errorf("Expected the %s to carry the bitrate cap, got %q", name, got)
+		}
+		if !strings.Contains(got, "a=framerate:10\r\n") {
+			t.Errorf("Expected the %s to carry the frame rate cap, got %q", name, got)
+		}
+		if !strings.Contains(got, "SAVPF 102\r\n") || strings.Contains(got, "VP8") {
+			t.Errorf("Expected the %s to offer only H264, got %q", name, got)
+		}
+	}
+
+	session, _ := mockRepo.GetSession("capped-token")
+	if session.Offer.SDP != sdp || session.Answer.SDP != sdp {
+		t.Error("Expected the stored descriptions to stay unchanged")
+	}
+}
+
+func TestSessionUseCase_CreateSession_Codec(t *testing.T) {
+	serverDefault := entities.CodecPreference{Codec: entities.VideoCodecVP8}
+	tests := []struct {
+		name          string
+		request       *dto.CreateSessionRequest
+		expected      entities.CodecPreference
+		expectedError error
+	}{
+		{name: "server default", request: &dto.CreateSessionRequest{}, expected: serverDefault},
+		{name: "session codec", request: &dto.CreateSessionRequest{VideoCodec: "H264", ForceCodec: true}, expected: entities.CodecPreference{Codec: entities.VideoCodecH264, Force: true}},
+		{name: "unknown codec", request: &dto.CreateSessionRequest{VideoCodec: "av1"}, expectedError: ErrInvalidCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{Codec: serverDefault})
+
+			response, err := useCase.CreateSession(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v but got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.VideoCodec != string(tt.expected.Codec) || response.ForceCodec != tt.expected.Force {
+				t.Errorf("Expected codec %+v in the response but got %q (force %v)", tt.expected, response.VideoCodec, response.ForceCodec)
+			}
+			session, _ := mockRepo.GetSession(response.Token)
+			if session.Codec != tt.expected {
+				t.Errorf("Expected stored codec %+v but got %+v", tt.expected, session.Codec)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_CreateSession_PIN(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	response, err := useCase.CreateSession(&dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.PIN) != 6 {
+		t.Errorf("Expected a 6-digit PIN but got %q", response.PIN)
+	}
+
+	session, _ := mockRepo.GetSession(response.Token)
+	if session.PIN != response.PIN {
+		t.Errorf("Expected stored PIN %q but got %q", response.PIN, session.PIN)
+	}
+
+	response, err = useCase.CreateSession(&dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.PIN != "" {
+		t.Errorf("Expected no PIN but got %q", response.PIN)
+	}
+}
+
+func TestSessionUseCase_PINLockout(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	useCase := NewSessionUseCase(mockRepo, historyRepo, mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+	iceConfig := NewICEConfigUseCase(mockRepo, historyRepo, nil, nil)
+	queue := NewViewerQueueUseCase(mockRepo, historyRepo, mocks.NewMockEventPublisher())
+
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{RequirePIN: true})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}
+	if err := useCase.SubmitOffer(&dto.SubmitOfferRequest{Token: created.Token, Offer: offer}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	wrong := "000000"
+	if created.PIN == wrong {
+		wrong = "111111"
+	}
+
+	// Viewers ask without a PIN to learn that one is needed; that is no guess
+	for i := 0; i < entities.MaxPINFailures; i++ {
+		if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token}); err != ErrInvalidPIN {
+			t.Fatalf("Expected ErrInvalidPIN without a PIN, got %v", err)
+		}
+	}
+
+	// Wrong PINs count across every use case that checks the PIN
+	for i := 1; i < entities.MaxPINFailures; i++ {
+		if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token, PIN: wrong}); err != ErrInvalidPIN {
+			t.Fatalf("Expected ErrInvalidPIN for wrong PIN %d, got %v", i, err)
+		}
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token, PIN: created.PIN}); err != nil {
+		t.Fatalf("Expected the right PIN to work before the lock, got %v", err)
+	}
+	if _, err := iceConfig.GetICEConfig(&dto.GetICEConfigRequest{Token: created.Token, PIN: wrong}); err != ErrInvalidPIN {
+		t.Fatalf("Expected ErrInvalidPIN for the last wrong PIN, got %v", err)
+	}
+
+	// Once locked, even the right PIN is refused
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token, PIN: created.PIN}); err != ErrPINLocked {
+		t.Errorf("Expected ErrPINLocked for the offer, got %v", err)
+	}
+	if _, err := queue.JoinQueue(&dto.JoinQueueRequest{Token: created.Token, PIN: created.PIN}); err != ErrPINLocked {
+		t.Errorf("Expected ErrPINLocked for the queue, got %v", err)
+	}
+	if session, _ := mockRepo.GetSession(created.Token); session.PINFailures != entities.MaxPINFailures {
+		t.Errorf("Expected %d wrong PINs counted, got %d", entities.MaxPINFailures, session.PINFailures)
+	}
+}
+
+func TestSessionUseCase_SubmitAnswer_PIN(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("sender-sdp")},
+		PIN:       "123456",
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+	answer := &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")}
+
+	// Answering without the PIN would take the slot of a protected session
+	for _, pin := range []string{"", "000000"} {
+		err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: "test-token", Answer: answer, ViewerID: "viewer-1", PIN: pin})
+		if err != ErrInvalidPIN {
+			t.Errorf("Expected ErrInvalidPIN for PIN %q, got %v", pin, err)
+		}
+	}
+	session, _ := mockRepo.GetSession("test-token")
+	if session.Answer != nil {
+		t.Fatalf("Expected no answer stored, got %+v", session.Answer)
+	}
+	if session.PINFailures != 1 {
+		t.Errorf("Expected the wrong PIN counted, got %d failures", session.PINFailures)
+	}
+
+	if err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: "test-token", Answer: answer, ViewerID: "viewer-1", PIN: "123456"}); err != nil {
+		t.Fatalf("Expected the answer with the PIN to be accepted, got %v", err)
+	}
+}
+
+func TestSessionUseCase_CreateSession_Limit(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	now := time.Now()
+	mockRepo.SetSession(&entities.Session{Token: "live-1", Status: entities.SessionStatusActive, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	mockRepo.SetSession(&entities.Session{Token: "live-2", Status: entities.SessionStatusPending, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	// Sessions that are over no longer count
+	mockRepo.SetSession(&entities.Session{Token: "ended", Status: entities.SessionStatusEnded, CreatedAt: now, ExpiresAt: now.Add(time.Hour)})
+	mockRepo.SetSession(&entities.Session{Token: "expired", Status: entities.SessionStatusPending, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{MaxSessions: 3})
+
+	if _, err := useCase.CreateSession(&dto.CreateSessionRequest{}); err != nil {
+		t.Fatalf("Expected the third live session to be created, got %v", err)
+	}
+	if _, err := useCase.CreateSession(&dto.CreateSessionRequest{}); err != ErrServerBusy {
+		t.Errorf("Expected ErrServerBusy beyond the limit but got %v", err)
+	}
+	if count := mockRepo.GetSessionCount(); count != 5 {
+		t.Errorf("Expected no session stored for the refused request, got %d sessions", count)
+	}
+}
+
+func TestSessionUseCase_GetSession(t *testing.T) {
+	offeredAt := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name          string
+		session       *entities.Session
+		expectedError error
+		check         func(t *testing.T, response *dto.SessionDetailResponse)
+	}{
+		{
+			name: "waiting for a viewer",
+			session: &entities.Session{
+				Token:     "test-token",
+				Name:      "Design review",
+				CreatedAt: time.Now().Add(-2 * time.Minute),
+				ExpiresAt: time.Now().Add(30 * time.Minute),
+				Status:    entities.SessionStatusActive,
+				Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+				OfferedAt: offeredAt,
+				PIN:       "123456",
+				Queue:     []entities.QueuedViewer{{ID: "viewer-2"}},
+			},
+			check: func(t *testing.T, response *dto.SessionDetailResponse) {
+				if response.Status != entities.SessionStatusActive || !response.HasOffer || response.HasAnswer {
+					t.Errorf("Expected an offer without an answer, got %+v", response)
+				}
+				if response.OfferedAt == nil || !response.OfferedAt.Equal(offeredAt) || response.ConnectedAt != nil || response.EndedAt != nil {
+					t.Errorf("Expected only the offer time, got %+v", response)
+				}
+				if response.Name != "Design review" || !response.RequiresPIN || response.Queued != 1 || response.Viewers != 0 {
+					t.Errorf("Expected the session's details, got %+v", response)
+				}
+				if response.ExpiresInSeconds < 1790 || response.ExpiresInSeconds > 1800 {
+					t.Errorf("Expected about 30 minutes left, got %d seconds", response.ExpiresInSeconds)
+				}
+			},
+		},
+		{
+			name: "viewer connected on a used link",
+			session: &entities.Session{
+				Token:           "test-token",
+				Name:            "Secret plans",
+				CreatedAt:       time.Now(),
+				ExpiresAt:       time.Now().Add(30 * time.Minute),
+				Status:          entities.SessionStatusActive,
+				Offer:           &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+				Answer:          &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+				ConnectedAt:     time.Now(),
+				SingleUse:       true,
+				ConsumedBy:      "viewer-1",
+				PreviewDisabled: true,
+			},
+			check: func(t *testing.T, response *dto.SessionDetailResponse) {
+				if !response.HasAnswer || response.Viewers != 1 || response.ConnectedAt == nil || !response.LinkUsed {
+					t.Errorf("Expected the connected viewer on the used link, got %+v", response)
+				}
+				if response.Name != "" {
+					t.Errorf("Expected the name hidden like in link previews, got %q", response.Name)
+				}
+			},
+		},
+		{
+			name: "ended session",
+			session: &entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now().Add(-10 * time.Minute),
+				ExpiresAt: time.Now().Add(-time.Second),
+				EndedAt:   time.Now().Add(-time.Second),
+				Status:    entities.SessionStatusEnded,
+			},
+			check: func(t *testing.T, response *dto.SessionDetailResponse) {
+				if response.Status != entities.SessionStatusEnded || response.EndedAt == nil || response.ExpiresInSeconds != 0 {
+					t.Errorf("Expected the session reported ended, got %+v", response)
+				}
+			},
+		},
+		{
+			name: "token ran out",
+			session: &entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now().Add(-60 * time.Minute),
+				ExpiresAt: time.Now().Add(-30 * time.Minute),
+				Status:    entities.SessionStatusPending,
+			},
+			check: func(t *testing.T, response *dto.SessionDetailResponse) {
+				if response.Status != entities.SessionStatusExpired {
+					t.Errorf("Expected the session reported expired, got %q", response.Status)
+				}
+			},
+		},
+		{
+			name:          "session not found",
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			if tt.session != nil {
+				mockRepo.SetSession(tt.session)
+			}
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+			response, err := useCase.GetSession(&dto.GetSessionRequest{Token: "test-token"})
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.check != nil {
+				tt.check(t, response)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_GetLinkPreview(t *testing.T) {
+	tests := []struct {
+		name            string
+		session         *entities.Session
+		token           string
+		expectedError   error
+		expectedEnabled bool
+		expectedName    string
+		expectThumbnail bool
+	}{
+		{
+			name: "named session",
+			session: &entities.Session{
+				Token:     "test-token",
+				Name:      "Design review",
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(30 * time.Minute),
+				Status:    entities.SessionStatusPending,
+			},
+			token:           "test-token",
+			expectedEnabled: true,
+			expectedName:    "Design review",
+		},
+		{
+			name: "SFU session",
+			session: &entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(30 * time.Minute),
+				Status:    entities.SessionStatusActive,
+				SFU:       true,
+			},
+			token:           "test-token",
+			expectedEnabled: true,
+			expectThumbnail: true,
+		},
+		{
+			name: "PIN-protected SFU session",
+			session: &entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now(),
+				ExpiresAt: time.Now().Add(30 * time.Minute),
+				Status:    entities.SessionStatusActive,
+				SFU:       true,
+				PIN:       "482913",
+			},
+			token:           "test-token",
+			expectedEnabled: true,
+		},
+		{
+			name: "preview disabled",
+			session: &entities.Session{
+				Token:           "test-token",
+				Name:            "Secret plans",
+				CreatedAt:       time.Now(),
+				ExpiresAt:       time.Now().Add(30 * time.Minute),
+				Status:          entities.SessionStatusPending,
+				PreviewDisabled: true,
+			},
+			token:           "test-token",
+			expectedEnabled: false,
+		},
+		{
+			name: "expired session",
+			session: &entities.Session{
+				Token:     "test-token",
+				CreatedAt: time.Now().Add(-60 * time.Minute),
+				ExpiresAt: time.Now().Add(-30 * time.Minute),
+				Status:    entities.SessionStatusPending,
+			},
+			token:         "test-token",
+			expectedError: ErrSessionExpired,
+		},
+		{
+			name:          "session not found",
+			token:         "missing-token",
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			if tt.session != nil {
+				mockRepo.SetSession(tt.session)
+			}
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+			response, err := useCase.GetLinkPreview(&dto.GetLinkPreviewRequest{Token: tt.token})
+
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v but got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				return
+			}
+			if response.Enabled != tt.expectedEnabled {
+				t.Errorf("Expected enabled %v but got %v", tt.expectedEnabled, response.Enabled)
+			}
+			if response.Name != tt.expectedName {
+				t.Errorf("Expected name %q but got %q", tt.expectedName, response.Name)
+			}
+			if response.Thumbnail != tt.expectThumbnail {
+				t.Errorf("Expected thumbnail %v but got %v", tt.expectThumbnail, response.Thumbnail)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_EndSession(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusActive,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+		SenderKey: "sender-key",
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	// Viewers hold the token too, so it alone cannot end or detach the share
+	for _, request := range []*dto.EndSessionRequest{
+		{Token: "test-token"},
+		{Token: "test-token", SenderKey: "wrong-key"},
+		{Token: "test-token", SenderKey: "wrong-key", Resumable: true},
+	} {
+		if err := useCase.EndSession(request); err != ErrInvalidSenderKey {
+			t.Fatalf("Expected ErrInvalidSenderKey for %+v but got %v", request, err)
+		}
+	}
+	if session, _ := mockRepo.GetSession("test-token"); session.IsEnded() || !session.DetachedAt.IsZero() {
+		t.Fatal("Expected a rejected end to leave the session live")
+	}
+
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: "test-token", SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A repeated beacon is not an error
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: "test-token", SenderKey: "sender-key"}); err != nil {
+		t.Errorf("Expected repeated end to succeed but got %v", err)
+	}
+
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token"}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded but got %v", err)
+	}
+
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: "missing-token"}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound but got %v", err)
+	}
+}
+
+func TestSessionUseCase_Heartbeat(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "live-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusPending,
+	})
+	mockRepo.SetSession(&entities.Session{
+		Token:            "silent-token",
+		CreatedAt:        time.Now().Add(-10 * time.Minute),
+		ExpiresAt:        time.Now().Add(20 * time.Minute),
+		Status:           entities.SessionStatusActive,
+		Offer:            &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")},
+		SenderSeenAt:     time.Now().Add(-DefaultHeartbeatTimeout - time.Minute),
+		HeartbeatTimeout: DefaultHeartbeatTimeout,
+	})
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	if err := useCase.Heartbeat(&dto.HeartbeatRequest{Token: "live-token"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	session, _ := mockRepo.GetSession("live-token")
+	if session.SenderSeenAt.IsZero() {
+		t.Error("Expected heartbeat time to be recorded")
+	}
+
+	// A sender that stopped sending heartbeats leaves its session stale
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "silent-token"}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded but got %v", err)
+	}
+	session, _ = mockRepo.GetSession("silent-token")
+	if !session.IsStale() {
+		t.Errorf("Expected silent session to be stale, got %s", session.Status)
+	}
+}
+
+func TestSessionUseCase_MarkStaleSessions(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	useCase := NewSessionUseCase(mockRepo, historyRepo, mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{HeartbeatTimeout: time.Minute})
+
+	created, err := useCase.CreateSession(nil)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	session, _ := mockRepo.GetSession(created.Token)
+	if session.HeartbeatTimeout != time.Minute {
+		t.Errorf("Expected new sessions to get the configured timeout, got %v", session.HeartbeatTimeout)
+	}
+
+	sessions := map[string]time.Time{
+		"silent-token":  time.Now().Add(-2 * time.Minute),
+		"recent-token":  time.Now().Add(-10 * time.Second),
+		"waiting-token": {}, // no heartbeat yet
+	}
+	for token, seenAt := range sessions {
+		mockRepo.SetSession(&entities.Session{
+			Token:            token,
+			CreatedAt:        time.Now().Add(-5 * time.Minute),
+			ExpiresAt:        time.Now().Add(25 * time.Minute),
+			Status:           entities.SessionStatusActive,
+			SenderSeenAt:     seenAt,
+			HeartbeatTimeout: time.Minute,
+		})
+	}
+
+	stale, err := useCase.MarkStaleSessions()
+	if err != nil {
+		t.Fatalf("MarkStaleSessions failed: %v", err)
+	}
+	if stale != 1 {
+		t.Errorf("Expected 1 stale session, got %d", stale)
+	}
+
+	for token := range sessions {
+		session, _ := mockRepo.GetSession(token)
+		if expected := token == "silent-token"; session.IsStale() != expected {
+			t.Errorf("Session %s: expected stale %v, got status %s", token, expected, session.Status)
+		}
+	}
+
+	silent, _ := mockRepo.GetSession("silent-token")
+	if !silent.IsExpired() {
+		t.Error("Expected the stale session to be due for cleanup")
+	}
+	if _, err := historyRepo.GetRecord("silent-token"); err != nil {
+		t.Errorf("Expected the stale session in history, got %v", err)
+	}
+
+	// Already stale sessions are not counted again
+	if stale, _ := useCase.MarkStaleSessions(); stale != 0 {
+		t.Errorf("Expected no new stale sessions, got %d", stale)
+	}
+}
+
+func TestSessionUseCase_ExpireIdleSessions(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, historyRepo, auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{HeartbeatTimeout: time.Minute, IdleTimeout: 5 * time.Minute})
+
+	created, err := useCase.CreateSession(nil)
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	session, _ := mockRepo.GetSession(created.Token)
+	if session.IdleTimeout != 5*time.Minute {
+		t.Errorf("Expected new sessions to get the configured idle timeout, got %v", session.IdleTimeout)
+	}
+
+	sessions := map[string]*entities.Session{
+		"no-offer-token":  {CreatedAt: time.Now().Add(-6 * time.Minute)},
+		"no-viewer-token": {CreatedAt: time.Now().Add(-9 * time.Minute), OfferedAt: time.Now().Add(-6 * time.Minute)},
+		"waiting-token":   {CreatedAt: time.Now().Add(-6 * time.Minute), OfferedAt: time.Now().Add(-time.Minute)},
+		"watched-token":   {CreatedAt: time.Now().Add(-20 * time.Minute), OfferedAt: time.Now().Add(-19 * time.Minute), ConnectedAt: time.Now().Add(-18 * time.Minute)},
+	}
+	for token, session := range sessions {
+		session.Token = token
+		session.ExpiresAt = time.Now().Add(10 * time.Minute)
+		session.Status = entities.SessionStatusActive
+		session.IdleTimeout = 5 * time.Minute
+		mockRepo.SetSession(session)
+	}
+
+	idle, err := useCase.ExpireIdleSessions()
+	if err != nil {
+		t.Fatalf("ExpireIdleSessions failed: %v", err)
+	}
+	if idle != 2 {
+		t.Errorf("Expected 2 idle sessions, got %d", idle)
+	}
+
+	for token := range sessions {
+		session, _ := mockRepo.GetSession(token)
+		if expected := strings.HasPrefix(token, "no-"); session.IsExpired() != expected {
+			t.Errorf("Session %s: expected expired %v, got status %s", token, expected, session.Status)
+		}
+	}
+	if _, err := historyRepo.GetRecord("no-viewer-token"); err != nil {
+		t.Errorf("Expected the idle session in history, got %v", err)
+	}
+
+	// The cleanup says why the idle sessions went
+	if removed, _ := useCase.CleanupExpiredSessions(); removed != 2 {
+		t.Errorf("Expected the idle sessions removed, got %d", removed)
+	}
+	events, _ := auditRepo.ListEvents("no-offer-token")
+	if len(events) != 1 || !strings.Contains(events[0].Detail, "idling") {
+		t.Errorf("Expected an expired event for idling, got %+v", events)
+	}
+}
+
+func TestSessionUseCase_ResumeSession(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{Preset: "text"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if created.SenderKey == "" {
+		t.Fatal("Expected a sender key for the new session")
+	}
+
+	// The page unloading for a reload detaches the sender instead of ending the session
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: created.Token, SenderKey: created.SenderKey, Resumable: true}); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	session, _ := mockRepo.GetSession(created.Token)
+	if session.IsEnded() || session.DetachedAt.IsZero() {
+		t.Fatalf("Expected a detached live session, got status %s", session.Status)
+	}
+
+	if _, err := useCase.ResumeSession(&dto.ResumeSessionRequest{Token: created.Token, SenderKey: "wrong-key"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+
+	resumed, err := useCase.ResumeSession(&dto.ResumeSessionRequest{Token: created.Token, SenderKey: created.SenderKey})
+	if err != nil {
+		t.Fatalf("ResumeSession failed: %v", err)
+	}
+	if resumed.Token != created.Token || resumed.Preset == nil || resumed.Preset.ID != "text" {
+		t.Errorf("Expected the session's options back, got %+v", resumed)
+	}
+	session, _ = mockRepo.GetSession(created.Token)
+	if !session.DetachedAt.IsZero() {
+		t.Error("Expected the sender reattached")
+	}
+
+	if types := auditRepo.Types(created.Token); !slices.Contains(types, entities.AuditDetached) || !slices.Contains(types, entities.AuditResumed) {
+		t.Errorf("Expected detached and resumed events, got %v", types)
+	}
+
+	// Stopping the share still ends the session outright
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: created.Token, SenderKey: created.SenderKey}); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+	if _, err := useCase.ResumeSession(&dto.ResumeSessionRequest{Token: created.Token, SenderKey: created.SenderKey}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded after the sender ended the session, got %v", err)
+	}
+}
+
+func TestSessionUseCase_ExtendSession(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	mockRepo.SetSession(&entities.Session{Token: "live-token", Status: entities.SessionStatusActive, CreatedAt: time.Now().Add(-25 * time.Minute), ExpiresAt: time.Now().Add(5 * time.Minute), SenderKey: "sender-key"})
+	mockRepo.SetSession(&entities.Session{Token: "ended-token", Status: entities.SessionStatusEnded, ExpiresAt: time.Now()})
+
+	// A viewer has the token but not the key
+	if _, err := useCase.ExtendSession(&dto.ExtendSessionRequest{Token: "live-token", SenderKey: "wrong-key"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+
+	response, err := useCase.ExtendSession(&dto.ExtendSessionRequest{Token: "live-token", SenderKey: "sender-key", ClientIP: "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("ExtendSession failed: %v", err)
+	}
+	session, _ := mockRepo.GetSession("live-token")
+	if time.Until(session.ExpiresAt) < 29*time.Minute || !session.ExpiresAt.Equal(response.ExpiresAt) {
+		t.Errorf("Expected the full token expiry from now, got %v (response %v)", session.ExpiresAt, response.ExpiresAt)
+	}
+	if events, _ := auditRepo.ListEvents("live-token"); len(events) != 1 || events[0].Type != entities.AuditExtended {
+		t.Errorf("Expected an extended event, got %+v", events)
+	}
+
+	if _, err := useCase.ExtendSession(&dto.ExtendSessionRequest{Token: "ended-token"}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded, got %v", err)
+	}
+	if _, err := useCase.ExtendSession(&dto.ExtendSessionRequest{Token: "unknown-token"}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestSessionUseCase_ExtendSession_Deadline(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	deadline := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	mockRepo.SetSession(&entities.Session{Token: "signed-token", Status: entities.SessionStatusActive, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(5 * time.Minute), Deadline: deadline, SenderKey: "sender-key"})
+
+	response, err := useCase.ExtendSession(&dto.ExtendSessionRequest{Token: "signed-token", SenderKey: "sender-key"})
+	if err != nil {
+		t.Fatalf("ExtendSession failed: %v", err)
+	}
+	if !response.ExpiresAt.Equal(deadline) {
+		t.Errorf("Expected the extension to stop at the token's deadline %v, got %v", deadline, response.ExpiresAt)
+	}
+}
+
+func TestSessionUseCase_PauseSession(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	mockRepo.SetSession(&entities.Session{
+		Token:     "live-token",
+		Status:    entities.SessionStatusActive,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("sender-sdp")},
+		SenderKey: "sender-key",
+	})
+
+	// A viewer has the token but not the key
+	for _, key := range []string{"", "wrong-key"} {
+		if err := useCase.PauseSession(&dto.PauseSessionRequest{Token: "live-token", Paused: true, SenderKey: key}); err != ErrInvalidSenderKey {
+			t.Errorf("Expected ErrInvalidSenderKey for key %q, got %v", key, err)
+		}
+	}
+	if session, _ := mockRepo.GetSession("live-token"); session.Paused {
+		t.Fatal("Expected the session not to be paused by a viewer")
+	}
+
+	// The repeated request stands in for a retry after a lost response
+	for range 2 {
+		if err := useCase.PauseSession(&dto.PauseSessionRequest{Token: "live-token", Paused: true, SenderKey: "sender-key", ClientIP: "192.168.1.10"}); err != nil {
+			t.Fatalf("PauseSession failed: %v", err)
+		}
+	}
+	offer, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "live-token", ViewerID: "late"})
+	if err != nil || !offer.Paused {
+		t.Errorf("Expected a viewer joining now to be told the stream is paused, got %+v (%v)", offer, err)
+	}
+
+	if err := useCase.PauseSession(&dto.PauseSessionRequest{Token: "live-token", Paused: false, SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("PauseSession failed to resume: %v", err)
+	}
+	if session, _ := mockRepo.GetSession("live-token"); session.Paused {
+		t.Error("Expected the session to be resumed")
+	}
+
+	events := publisher.Published(entities.SessionTopic("live-token"))
+	if len(events) != 2 || events[0].Type != entities.EventPaused || events[1].Type != entities.EventPaused {
+		t.Fatalf("Expected a paused event for each change, got %+v", events)
+	}
+	if data := events[1].Data.(map[string]bool); data["paused"] {
+		t.Errorf("Expected the second event to resume the stream, got %+v", data)
+	}
+	if audit, _ := auditRepo.ListEvents("live-token"); len(audit) != 2 || audit[0].Type != entities.AuditPaused || audit[1].Type != entities.AuditUnpaused {
+		t.Errorf("Expected paused and unpaused audit events, got %+v", audit)
+	}
+
+	if err := useCase.PauseSession(&dto.PauseSessionRequest{Token: "unknown-token", Paused: true}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestSessionUseCase_OwnSessions(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	// The mock repository names sessions by the second, so they are set up directly
+	now := time.Now()
+	live := func(token, owner, pin string, age time.Duration) *entities.Session {
+		return &entities.Session{Token: token, Owner: owner, PIN: pin, Status: entities.SessionStatusPending, CreatedAt: now.Add(-age), ExpiresAt: now.Add(30 * time.Minute)}
+	}
+	first := live("first-token", "mine", "", 2*time.Minute)
+	second := live("second-token", "mine", "123456", time.Minute)
+	other := live("other-token", "theirs", "", time.Minute)
+	ended := live("ended-token", "mine", "", time.Minute)
+	ended.Status = entities.SessionStatusEnded
+	for _, session := range []*entities.Session{second, first, other, ended} {
+		mockRepo.SetSession(session)
+	}
+
+	response, err := useCase.ListOwnSessions(&dto.ListOwnSessionsRequest{Owner: "mine"})
+	if err != nil {
+		t.Fatalf("ListOwnSessions failed: %v", err)
+	}
+	if len(response.Sessions) != 2 || response.Sessions[0].Token != first.Token || response.Sessions[1].Token != second.Token {
+		t.Fatalf("Expected the sender's two live sessions, oldest first, got %+v", response.Sessions)
+	}
+	if response.Sessions[1].PIN != "123456" {
+		t.Errorf("Expected the owner to see the PIN, got %+v", response.Sessions[1])
+	}
+
+	if response, _ := useCase.ListOwnSessions(&dto.ListOwnSessionsRequest{}); len(response.Sessions) != 0 {
+		t.Errorf("Expected no sessions for a sender without an ID, got %+v", response.Sessions)
+	}
+
+	if err := useCase.RenameSession(&dto.RenameSessionRequest{Token: first.Token, Name: "  Retro  ", Owner: "mine"}); err != nil {
+		t.Fatalf("RenameSession failed: %v", err)
+	}
+	if session, _ := mockRepo.GetSession(first.Token); session.Name != "Retro" {
+		t.Errorf("Expected the session renamed to Retro, got %q", session.Name)
+	}
+	if err := useCase.RenameSession(&dto.RenameSessionRequest{Token: other.Token, Name: "Mine now", Owner: "mine"}); err != ErrNotSessionOwner {
+		t.Errorf("Expected ErrNotSessionOwner, got %v", err)
+	}
+	if err := useCase.RenameSession(&dto.RenameSessionRequest{Token: first.Token, Name: strings.Repeat("a", 81), Owner: "mine"}); err != ErrInvalidSessionName {
+		t.Errorf("Expected ErrInvalidSessionName, got %v", err)
+	}
+	if err := useCase.RenameSession(&dto.RenameSessionRequest{Token: ended.Token, Name: "Late", Owner: "mine"}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded, got %v", err)
+	}
+
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{Owner: "mine"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if session, _ := mockRepo.GetSession(created.Token); session.Owner != "mine" {
+		t.Errorf("Expected a new session to belong to its sender, got %q", session.Owner)
+	}
+}
+
+func TestSessionUseCase_SingleUseLink(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	mockRepo.SetSession(&entities.Session{
+		Token:     "test-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusPending,
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("first-sdp")},
+		SingleUse: true,
+		Queue:     []entities.QueuedViewer{{ID: "waiting", JoinedAt: time.Now()}},
+		SenderKey: "sender-key",
+	})
+
+	err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+		Token:    "test-token",
+		ViewerID: "first",
+		Answer:   &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")},
+	})
+	if err != nil {
+		t.Fatalf("Expected the first answer to be accepted, got %v", err)
+	}
+
+	session, _ := mockRepo.GetSession("test-token")
+	if session.ConsumedBy != "first" || len(session.Queue) != 0 {
+		t.Fatalf("Expected the link consumed by the first viewer with an empty queue, got %q and %+v", session.ConsumedBy, session.Queue)
+	}
+	events := publisher.Published(entities.ViewerTopic("test-token", "waiting"))
+	if len(events) != 1 || events[0].Type != entities.EventLinkUsed {
+		t.Errorf("Expected the queued viewer to be told the link is used, got %+v", events)
+	}
+
+	// The sender re-offers after the first viewer drops out
+	err = useCase.SubmitOffer(&dto.SubmitOfferRequest{
+		Token:     "test-token",
+		Offer:     &entities.WebRTCOffer{Type: "offer", SDP: testSDP("second-sdp")},
+		SenderKey: "sender-key",
+	})
+	if err != nil {
+		t.Fatalf("Expected the offer to be replaced, got %v", err)
+	}
+	for _, event := range publisher.Published(entities.SessionTopic("test-token")) {
+		if event.Type == entities.EventRenegotiate {
+			t.Errorf("Expected no renegotiate event on the session topic, got %+v", event)
+		}
+	}
+	events = publisher.Published(entities.ViewerTopic("test-token", "first"))
+	if len(events) != 1 || events[0].Type != entities.EventRenegotiate {
+		t.Errorf("Expected the renegotiate event on the consumer's topic, got %+v", events)
+	}
+
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: "second"}); err != ErrViewerLinkUsed {
+		t.Errorf("Expected ErrViewerLinkUsed for another viewer, got %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token"}); err != ErrViewerLinkUsed {
+		t.Errorf("Expected ErrViewerLinkUsed without a viewer ID, got %v", err)
+	}
+	err = useCase.SubmitAnswer(&dto.SubmitAnswerRequest{
+		Token:    "test-token",
+		ViewerID: "second",
+		Answer:   &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("other-sdp")},
+	})
+	if err != ErrViewerLinkUsed {
+		t.Errorf("Expected ErrViewerLinkUsed for another viewer's answer, got %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "test-token", ViewerID: "first"}); err != nil {
+		t.Errorf("Expected the first viewer to reconnect, got %v", err)
+	}
+}
+
+func TestSessionUseCase_CreateSession_SingleUse(t *testing.T) {
+	tests := []struct {
+		name          string
+		reusable      bool
+		wantSingleUse bool
+	}{
+		{name: "single use by default", wantSingleUse: true},
+		{name: "reusable on request", reusable: true, wantSingleUse: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+			response, err := useCase.CreateSession(&dto.CreateSessionRequest{ReusableLink: tt.reusable})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			session, _ := mockRepo.GetSession(response.Token)
+			if session.SingleUse != tt.wantSingleUse || response.SingleUse != tt.wantSingleUse {
+				t.Errorf("Expected single use %v, got session %v response %v", tt.wantSingleUse, session.SingleUse, response.SingleUse)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_CreateSession_SFU(t *testing.T) {
+	tests := []struct {
+		name          string
+		relay         *mocks.MockStreamRelay
+		sfu           bool
+		wantSFU       bool
+		wantSingleUse bool
+	}{
+		{name: "peer-to-peer by default", relay: mocks.NewMockStreamRelay(), wantSingleUse: true},
+		{name: "sfu on request", relay: mocks.NewMockStreamRelay(), sfu: true, wantSFU: true},
+		{name: "sfu ignored without a relay", sfu: true, wantSingleUse: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			var relay interfaces.StreamRelay
+			if tt.relay != nil {
+				relay = tt.relay
+			}
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+
+			response, err := useCase.CreateSession(&dto.CreateSessionRequest{SFU: tt.sfu})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			session, _ := mockRepo.GetSession(response.Token)
+			if session.SFU != tt.wantSFU || response.SFU != tt.wantSFU {
+				t.Errorf("Expected SFU %v, got session %v response %v", tt.wantSFU, session.SFU, response.SFU)
+			}
+			if session.SingleUse != tt.wantSingleUse {
+				t.Errorf("Expected single use %v, got %v", tt.wantSingleUse, session.SingleUse)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_PublishStream(t *testing.T) {
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}
+
+	tests := []struct {
+		name          string
+		request       *dto.PublishStreamRequest
+		published     bool
+		failPublish   bool
+		expectedError error
+	}{
+		{
+			name:    "stream published",
+			request: &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer},
+		},
+		{
+			name:      "stream replaced by the sender",
+			request:   &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer, SenderKey: "sender-key"},
+			published: true,
+		},
+		{
+			name:          "stream replaced without the sender key",
+			request:       &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer},
+			published:     true,
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "stream replaced with a wrong sender key",
+			request:       &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer, SenderKey: "wrong-key"},
+			published:     true,
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:      "WHIP encoder publishes again",
+			request:   &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer, Ingest: true},
+			published: true,
+		},
+		{
+			name:          "invalid offer",
+			request:       &dto.PublishStreamRequest{Token: "sfu-token"},
+			expectedError: ErrInvalidOffer,
+		},
+		{
+			name:          "peer-to-peer session",
+			request:       &dto.PublishStreamRequest{Token: "p2p-token", Offer: offer},
+			expectedError: ErrSFUDisabled,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.PublishStreamRequest{Token: "missing-token", Offer: offer},
+			expectedError: ErrSessionNotFound,
+		},
+		{
+			name:          "relay rejects the offer",
+			request:       &dto.PublishStreamRequest{Token: "sfu-token", Offer: offer},
+			failPublish:   true,
+			expectedError: ErrInvalidOffer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			for token, sfu := range map[string]bool{"sfu-token": true, "p2p-token": false} {
+				session := &entities.Session{
+					Token:     token,
+					CreatedAt: time.Now(),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+					Status:    entities.SessionStatusPending,
+					SFU:       sfu,
+					SenderKey: "sender-key",
+				}
+				if tt.published {
+					session.Status = entities.SessionStatusActive
+					session.OfferedAt = time.Now()
+				}
+				mockRepo.SetSession(session)
+			}
+			relay := mocks.NewMockStreamRelay()
+			relay.ShouldFailPublish = tt.failPublish
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+
+			response, err := useCase.PublishStream(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.Answer == nil || response.Answer.SDP != "relay-answer-sdp" {
+				t.Errorf("Expected the relay's answer, got %+v", response.Answer)
+			}
+			session, _ := mockRepo.GetSession("sfu-token")
+			if session.Status != entities.SessionStatusActive {
+				t.Errorf("Expected session to be active, got %s", session.Status)
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_SelectLayer(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.SelectLayerRequest
+		expectedError error
+	}{
+		{
+			name:    "layer picked",
+			request: &dto.SelectLayerRequest{Token: "sfu-token", ViewerID: "viewer-1", Layer: entities.LayerLow},
+		},
+		{
+			name:    "automatic layer",
+			request: &dto.SelectLayerRequest{Token: "sfu-token", ViewerID: "viewer-1", Layer: entities.LayerAuto},
+		},
+		{
+			name:          "unknown layer",
+			request:       &dto.SelectLayerRequest{Token: "sfu-token", ViewerID: "viewer-1", Layer: "ultra"},
+			expectedError: ErrInvalidLayer,
+		},
+		{
+			name:          "missing viewer",
+			request:       &dto.SelectLayerRequest{Token: "sfu-token", Layer: entities.LayerLow},
+			expectedError: ErrMissingViewerID,
+		},
+		{
+			name:          "viewer without an offer",
+			request:       &dto.SelectLayerRequest{Token: "sfu-token", ViewerID: "viewer-2", Layer: entities.LayerLow},
+			expectedError: ErrViewerNotConnected,
+		},
+		{
+			name:          "peer-to-peer session",
+			request:       &dto.SelectLayerRequest{Token: "p2p-token", ViewerID: "viewer-1", Layer: entities.LayerLow},
+			expectedError: ErrSFUDisabled,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.SelectLayerRequest{Token: "missing-token", ViewerID: "viewer-1", Layer: entities.LayerLow},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			for token, sfu := range map[string]bool{"sfu-token": true, "p2p-token": false} {
+				mockRepo.SetSession(&entities.Session{
+					Token:     token,
+					CreatedAt: time.Now(),
+					ExpiresAt: time.Now().Add(30 * time.Minute),
+					Status:    entities.SessionStatusActive,
+					SFU:       sfu,
+				})
+			}
+			relay := mocks.NewMockStreamRelay()
+			useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+			if _, err := useCase.PublishStream(&dto.PublishStreamRequest{Token: "sfu-token", Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-1"}); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			err := useCase.SelectLayer(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if err == nil && relay.Layers["sfu-token/viewer-1"] != tt.request.Layer {
+				t.Errorf("Expected the relay to get layer %q, got %q", tt.request.Layer, relay.Layers["sfu-token/viewer-1"])
+			}
+		})
+	}
+}
+
+func TestSessionUseCase_SFUViewers(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:     "sfu-token",
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(30 * time.Minute),
+		Status:    entities.SessionStatusPending,
+		SFU:       true,
+		SenderKey: "sender-key",
+	})
+	relay := mocks.NewMockStreamRelay()
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, publisher, relay, 30*time.Minute, SessionDefaults{})
+
+	// Viewers wait until the sender's stream reaches the relay
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-1"}); err != ErrOfferNotFound {
+		t.Fatalf("Expected ErrOfferNotFound before publishing, got %v", err)
+	}
+	if _, err := useCase.PublishStream(&dto.PublishStreamRequest{Token: "sfu-token", Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token"}); err != ErrMissingViewerID {
+		t.Errorf("Expected ErrMissingViewerID, got %v", err)
+	}
+
+	// Every viewer gets an offer of its own; none waits for a slot
+	for _, viewerID := range []string{"viewer-1", "viewer-2"} {
+		response, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: viewerID})
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", viewerID, err)
+		}
+		if response.Offer.SDP != "relay-offer-"+viewerID {
+			t.Errorf("Expected the relay's offer for %s, got %q", viewerID, response.Offer.SDP)
+		}
+		answer := &dto.SubmitAnswerRequest{Token: "sfu-token", ViewerID: viewerID, Answer: &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("answer-sdp")}}
+		if err := useCase.SubmitAnswer(answer); err != nil {
+			t.Errorf("Unexpected answer error for %s: %v", viewerID, err)
+		}
+	}
+	stranger := &dto.SubmitAnswerRequest{Token: "sfu-token", ViewerID: "viewer-3", Answer: &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("answer-sdp")}}
+	if err := useCase.SubmitAnswer(stranger); err != ErrInvalidAnswer {
+		t.Errorf("Expected ErrInvalidAnswer for a viewer without an offer, got %v", err)
+	}
+
+	relay.SetViewers("sfu-token", 2)
+	session, _ := mockRepo.GetSession("sfu-token")
+	if session.SFUViewers != 2 || session.PeakViewers != 2 {
+		t.Errorf("Expected 2 viewers with a peak of 2, got %d and %d", session.SFUViewers, session.PeakViewers)
+	}
+	events := publisher.Published(entities.SessionTopic("sfu-token"))
+	if len(events) != 1 || events[0].Type != entities.EventSFUViewers {
+		t.Errorf("Expected one %s event, got %+v", entities.EventSFUViewers, events)
+	}
+
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: "sfu-token", SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(relay.Closed) != 1 || relay.Closed[0] != "sfu-token" {
+		t.Errorf("Expected the stream to be closed with the session, got %v", relay.Closed)
+	}
+}
+
+func TestSessionUseCase_SFUViewerLimit(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(&entities.Session{
+		Token:      "sfu-token",
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(30 * time.Minute),
+		Status:     entities.SessionStatusActive,
+		SFU:        true,
+		SFUViewers: 2,
+		MaxViewers: 2,
+	})
+	relay := mocks.NewMockStreamRelay()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+	if _, err := useCase.PublishStream(&dto.PublishStreamRequest{Token: "sfu-token", Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-3"}); err != ErrSessionFull {
+		t.Errorf("Expected ErrSessionFull at the viewer limit, got %v", err)
+	}
+
+	relay.SetViewers("sfu-token", 1)
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-3"}); err != nil {
+		t.Errorf("Expected an offer below the viewer limit, got %v", err)
+	}
+}
+
+func TestSessionUseCase_PruneStreams(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	for token, expiresAt := range map[string]time.Time{
+		"live-token":    time.Now().Add(30 * time.Minute),
+		"expired-token": time.Now().Add(-time.Minute),
+	} {
+		mockRepo.SetSession(&entities.Session{
+			Token:     token,
+			CreatedAt: time.Now().Add(-time.Hour),
+			ExpiresAt: expiresAt,
+			Status:    entities.SessionStatusPending,
+			SFU:       true,
+		})
+	}
+	relay := mocks.NewMockStreamRelay()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}
+	for _, token := range []string{"live-token", "expired-token", "gone-token"} {
+		// Publish straight to the relay; only the live session would accept it
+		if _, err := relay.Publish(token, offer); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if pruned := useCase.PruneStreams(); pruned != 2 {
+		t.Errorf("Expected 2 streams pruned, got %d", pruned)
+	}
+	if tokens := relay.GetTokens(); len(tokens) != 1 || tokens[0] != "live-token" {
+		t.Errorf("Expected only the live stream to remain, got %v", tokens)
+	}
+}
+
+func TestSessionUseCase_AuditLog(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{RequirePIN: true, ClientIP: "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	token := created.Token
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}
+	if err := useCase.SubmitOffer(&dto.SubmitOfferRequest{Token: token, Offer: offer, ClientIP: "192.168.1.10"}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	// A wrong PIN is logged, waiting for a slot is not
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: token, PIN: "000000x", ClientIP: "192.168.1.30"}); err != ErrInvalidPIN {
+		t.Fatalf("Expected ErrInvalidPIN but got %v", err)
+	}
+	answer := &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("viewer-sdp")}
+	if err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: token, Answer: answer, ViewerID: "viewer-1", PIN: created.PIN, ClientIP: "192.168.1.20"}); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: token, PIN: created.PIN, ViewerID: "viewer-2"}); err != ErrViewerLinkUsed {
+		t.Fatalf("Expected ErrViewerLinkUsed but got %v", err)
+	}
+	if _, err := useCase.GetAnswer(&dto.GetAnswerRequest{Token: token, ClientIP: "192.168.1.10"}); err != nil {
+		t.Fatalf("GetAnswer failed: %v", err)
+	}
+	if err := useCase.EndSession(&dto.EndSessionRequest{Token: token, SenderKey: created.SenderKey, ClientIP: "192.168.1.10"}); err != nil {
+		t.Fatalf("EndSession failed: %v", err)
+	}
+
+	expected := []entities.AuditEventType{
+		entities.AuditSessionCreated,
+		entities.AuditOffer,
+		entities.AuditError,
+		entities.AuditAnswer,
+		entities.AuditError,
+		entities.AuditConnected,
+		entities.AuditTerminated,
+	}
+	if got := auditRepo.Types(token); !slices.Equal(got, expected) {
+		t.Fatalf("Expected events %v, got %v", expected, got)
+	}
+
+	events, _ := auditRepo.ListEvents(token)
+	if events[2].ClientIP != "192.168.1.30" || events[2].Detail != ErrInvalidPIN.Error() {
+		t.Errorf("Expected the wrong PIN with the viewer's address, got %+v", events[2])
+	}
+	if events[3].ClientIP != "192.168.1.20" || events[3].ViewerID != "viewer-1" {
+		t.Errorf("Expected the answer with the viewer's address and ID, got %+v", events[3])
+	}
+	if events[5].ViewerID != "viewer-1" || events[6].ClientIP != "192.168.1.10" {
+		t.Errorf("Expected the connection to name the viewer and the end the sender, got %+v and %+v", events[5], events[6])
+	}
+	for _, event := range events {
+		if event.At.IsZero() || event.Token != token {
+			t.Errorf("Expected every event stamped with its time and session, got %+v", event)
+		}
+	}
+
+	// Requests for unknown sessions start no log
+	if err := useCase.SubmitOffer(&dto.SubmitOfferRequest{Token: "unknown-token", Offer: &entities.WebRTCOffer{}}); err != ErrInvalidOffer {
+		t.Fatalf("Expected ErrInvalidOffer but got %v", err)
+	}
+	if _, err := auditRepo.ListEvents("unknown-token"); err == nil {
+		t.Error("Expected no log for an unknown session")
+	}
+}
+
+func TestSessionUseCase_CreateSessionRecordsUser(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	user := &entities.UserIdentity{Subject: "alice", Issuer: "idp", Email: "alice@example.com"}
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{User: user})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+
+	session, _ := mockRepo.GetSession(created.Token)
+	if session.User != user {
+		t.Errorf("Expected the session to record %+v, got %+v", user, session.User)
+	}
+	events, _ := auditRepo.ListEvents(created.Token)
+	if len(events) != 1 || events[0].User != "alice" {
+		t.Errorf("Expected the creation audited for alice, got %+v", events)
+	}
+}
+
+func TestSessionUseCase_CleanupExpiredSessions(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	sessions := map[string]entities.SessionStatus{
+		"expired-token": entities.SessionStatusActive,
+		"ended-token":   entities.SessionStatusEnded,
+		"stale-token":   entities.SessionStatusStale,
+	}
+	for token, status := range sessions {
+		mockRepo.SetSession(&entities.Session{Token: token, Status: status, ExpiresAt: time.Now().Add(-time.Second)})
+	}
+	mockRepo.SetSession(&entities.Session{Token: "live-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Minute)})
+
+	removed, err := useCase.CleanupExpiredSessions()
+	if err != nil {
+		t.Fatalf("CleanupExpiredSessions failed: %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Expected 3 sessions removed, got %d", removed)
+	}
+
+	for token := range sessions {
+		events, err := auditRepo.ListEvents(token)
+		if err != nil || len(events) != 1 || events[0].Type != entities.AuditExpired {
+			t.Errorf("Session %s: expected one expired event, got %+v (%v)", token, events, err)
+		}
+	}
+	ended, _ := auditRepo.ListEvents("ended-token")
+	if len(ended) == 1 && !strings.Contains(ended[0].Detail, "ended") {
+		t.Errorf("Expected the detail to say how the session ended, got %q", ended[0].Detail)
+	}
+	if _, err := auditRepo.ListEvents("live-token"); err == nil {
+		t.Error("Expected nothing logged for a live session")
+	}
+}
+
+func TestSessionUseCase_KickViewer(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	publisher := mocks.NewMockEventPublisher()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), auditRepo, nil, publisher, nil, 30*time.Minute, SessionDefaults{})
+
+	created, err := useCase.CreateSession(&dto.CreateSessionRequest{})
+	if err != nil {
+		t.Fatalf("CreateSession failed: %v", err)
+	}
+	if err := useCase.SubmitOffer(&dto.SubmitOfferRequest{Token: created.Token, Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	if err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: created.Token, ViewerID: "wrong-viewer", Answer: &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("SubmitAnswer failed: %v", err)
+	}
+
+	// The sender learns who answered
+	answer, err := useCase.GetAnswer(&dto.GetAnswerRequest{Token: created.Token})
+	if err != nil {
+		t.Fatalf("GetAnswer failed: %v", err)
+	}
+	if answer.ViewerID != "wrong-viewer" {
+		t.Errorf("Expected the answering viewer's ID, got %q", answer.ViewerID)
+	}
+
+	kick := &dto.KickViewerRequest{Token: created.Token, ViewerID: "wrong-viewer", SenderKey: "wrong-key"}
+	if err := useCase.KickViewer(kick); err != ErrInvalidSenderKey {
+		t.Fatalf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+	if err := useCase.KickViewer(&dto.KickViewerRequest{Token: created.Token, SenderKey: created.SenderKey}); err != ErrMissingViewerID {
+		t.Fatalf("Expected ErrMissingViewerID, got %v", err)
+	}
+
+	kick.SenderKey = created.SenderKey
+	if err := useCase.KickViewer(kick); err != nil {
+		t.Fatalf("KickViewer failed: %v", err)
+	}
+	// Retries change nothing
+	if err := useCase.KickViewer(kick); err != nil {
+		t.Fatalf("Repeated KickViewer failed: %v", err)
+	}
+
+	session, _ := mockRepo.GetSession(created.Token)
+	if session.IsFull() || session.Offer != nil || session.Status != entities.SessionStatusPending {
+		t.Errorf("Expected the slot freed for a new offer, got status %s", session.Status)
+	}
+	if events := publisher.Published(entities.ViewerTopic(created.Token, "wrong-viewer")); len(events) != 1 || events[0].Type != entities.EventKicked {
+		t.Errorf("Expected one kicked event for the viewer, got %v", events)
+	}
+	if events := publisher.Published(entities.SessionTopic(created.Token)); !slices.ContainsFunc(events, func(e entities.Event) bool { return e.Type == entities.EventViewerLeft }) {
+		t.Errorf("Expected the sender told the viewer left, got %v", events)
+	}
+	kicked := 0
+	for _, eventType := range auditRepo.Types(created.Token) {
+		if eventType == entities.AuditKicked {
+			kicked++
+		}
+	}
+	if kicked != 1 {
+		t.Errorf("Expected one kicked event in the audit log, got %d", kicked)
+	}
+
+	// The removed viewer gets no more offers and cannot answer, others can
+	if err := useCase.SubmitOffer(&dto.SubmitOfferRequest{Token: created.Token, Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("SubmitOffer failed: %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token, ViewerID: "wrong-viewer"}); err != ErrViewerRevoked {
+		t.Errorf("Expected ErrViewerRevoked for the offer, got %v", err)
+	}
+	if err := useCase.SubmitAnswer(&dto.SubmitAnswerRequest{Token: created.Token, ViewerID: "wrong-viewer", Answer: &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("test-sdp")}}); err != ErrViewerRevoked {
+		t.Errorf("Expected ErrViewerRevoked for the answer, got %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: created.Token, ViewerID: "right-viewer"}); err != nil {
+		t.Errorf("Expected another viewer to get the offer, got %v", err)
+	}
+
+	// Admins need no sender key
+	if err := useCase.KickViewer(&dto.KickViewerRequest{Token: created.Token, ViewerID: "right-viewer", Admin: true}); err != nil {
+		t.Errorf("Admin KickViewer failed: %v", err)
+	}
+}
+
+func TestSessionUseCase_KickViewer_SFU(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	relay := mocks.NewMockStreamRelay()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), relay, 30*time.Minute, SessionDefaults{})
+	mockRepo.SetSession(&entities.Session{Token: "sfu-token", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(30 * time.Minute), Status: entities.SessionStatusActive, SFU: true})
+
+	if _, err := useCase.PublishStream(&dto.PublishStreamRequest{Token: "sfu-token", Offer: &entities.WebRTCOffer{Type: "offer", SDP: testSDP("test-sdp")}}); err != nil {
+		t.Fatalf("PublishStream failed: %v", err)
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-1"}); err != nil {
+		t.Fatalf("GetOffer failed: %v", err)
+	}
+
+	if err := useCase.KickViewer(&dto.KickViewerRequest{Token: "sfu-token", ViewerID: "viewer-1", Admin: true}); err != nil {
+		t.Fatalf("KickViewer failed: %v", err)
+	}
+	if err := relay.Answer("sfu-token", "viewer-1", &entities.WebRTCAnswer{Type: "answer", SDP: testSDP("test-sdp")}); err == nil {
+		t.Error("Expected the viewer's relay connection closed")
+	}
+	if _, err := useCase.GetOffer(&dto.GetOfferRequest{Token: "sfu-token", ViewerID: "viewer-1"}); err != ErrViewerRevoked {
+		t.Errorf("Expected ErrViewerRevoked, got %v", err)
+	}
+}
+
+func TestSessionUseCase_ViewerAlias(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	useCase := NewSessionUseCase(mockRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, SessionDefaults{})
+
+	aliases := make(map[string]bool)
+	var tokens []string
+	for i := 0; i < 50; i++ {
+		response, err := useCase.CreateSession(&dto.CreateSessionRequest{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		session, _ := mockRepo.GetSession(response.Token)
+		if !entities.IsViewerAlias(session.Alias) || response.ViewerPath != "/v/"+session.Alias {
+			t.Fatalf("Expected a viewer alias and its path, got %q and %q", session.Alias, response.ViewerPath)
+		}
+		if aliases[session.Alias] {
+			t.Fatalf("Expected distinct aliases, got %s twice", session.Alias)
+		}
+		aliases[session.Alias] = true
+		tokens = append(tokens, response.Token)
+	}
+
+	session, _ := mockRepo.GetSession(tokens[0])
+	resolved, err := useCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: strings.ToLower(session.Alias)})
+	if err != nil || resolved.Token != session.Token {
+		t.Fatalf("Expected the alias to resolve to its token in any case, got %+v, %v", resolved, err)
+	}
+
+	if _, err := useCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: "not an alias"}); err != ErrViewerAliasNotFound {
+		t.Errorf("Expected ErrViewerAliasNotFound for a malformed alias, got %v", err)
+	}
+	if _, err := useCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: "NoSuchAlias10"}); err != ErrViewerAliasNotFound {
+		t.Errorf("Expected ErrViewerAliasNotFound for an unknown alias, got %v", err)
+	}
+
+	// The alias expires with the token
+	session.ExpiresAt = time.Now().Add(-time.Second)
+	mockRepo.SetSession(session)
+	if _, err := useCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: session.Alias}); err != ErrSessionExpired {
+		t.Errorf("Expected ErrSessionExpired, got %v", err)
+	}
+
+	ended, _ := mockRepo.GetSession(tokens[1])
+	ended.End()
+	mockRepo.SetSession(ended)
+	if _, err := useCase.ResolveViewerAlias(&dto.ResolveViewerAliasRequest{Alias: ended.Alias}); err != ErrSessionEnded {
+		t.Errorf("Expected ErrSessionEnded, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// SnapshotUseCase saves the in-memory state to a state store and loads it
+// back on startup, so a restarted server picks up the sessions it had
+type SnapshotUseCase struct {
+	store        interfaces.StateStore
+	sessionRepo  interfaces.SessionRepository
+	historyRepo  interfaces.SessionHistoryRepository
+	settingsRepo interfaces.DeviceSettingsRepository
+
+	// mu keeps a scheduled save and the one on shutdown from overlapping
+	mu sync.Mutex
+}
+
+// NewSnapshotUseCase creates a new snapshot use case
+func NewSnapshotUseCase(store interfaces.StateStore, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, settingsRepo interfaces.DeviceSettingsRepository) *SnapshotUseCase {
+	return &SnapshotUseCase{
+		store:        store,
+		sessionRepo:  sessionRepo,
+		historyRepo:  historyRepo,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// SaveSnapshot writes the current sessions, history and device settings to
+// the store and returns what was written
+func (uc *SnapshotUseCase) SaveSnapshot() (*entities.Snapshot, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	snapshot := entities.NewSnapshot()
+	var err error
+	if snapshot.Sessions, err = uc.sessionRepo.ListSessions(); err != nil {
+		return nil, err
+	}
+	if snapshot.History, err = uc.historyRepo.ListRecords(); err != nil {
+		return nil, err
+	}
+	if snapshot.DeviceSettings, err = uc.settingsRepo.ListSettings(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.store.Save(snapshot); err != nil {
+		return nil, fmt.Errorf("saving snapshot to %s: %w", uc.store.Location(), err)
+	}
+	return snapshot, nil
+}
+
+// RestoreSnapshot loads the stored state into the repositories and returns
+// how many sessions came back. Sessions that expired while the server was
+// down are left out, and a store holding nothing yet restores nothing. A
+// snapshot that fails validation is not loaded at all.
+func (uc *SnapshotUseCase) RestoreSnapshot() (int, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	snapshot, err := uc.store.Load()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if problems := snapshot.Validate(); len(problems) > 0 {
+		return 0, fmt.Errorf("snapshot %s is invalid: %w", uc.store.Location(), errors.Join(problems...))
+	}
+
+	for _, record := range snapshot.History {
+		if err := uc.historyRepo.SaveRecord(record); err != nil {
+			return 0, err
+		}
+	}
+	for _, settings := range snapshot.DeviceSettings {
+		if err := uc.settingsRepo.SaveSettings(settings); err != nil {
+			return 0, err
+		}
+	}
+
+	restored := 0
+	for _, session := range snapshot.Sessions {
+		if session.IsExpired() {
+			continue
+		}
+		// Media relayed through the SFU did not survive the restart; its
+		// viewers count again as they reconnect
+		session.SFUViewers = 0
+		if err := uc.sessionRepo.RestoreSession(session); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// Location describes where snapshots are stored, for messages
+func (uc *SnapshotUseCase) Location() string {
+	return uc.store.Location()
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestSnapshotUseCase_SaveAndRestore(t *testing.T) {
+	store := mocks.NewMockStateStore()
+	sessionRepo := mocks.NewMockSessionRepository()
+	historyRepo := mocks.NewMockSessionHistoryRepository()
+	settingsRepo := mocks.NewMockDeviceSettingsRepository()
+
+	live := mocks.NewTestSession("test-token", false)
+	live.Token = "live-token"
+	live.SFU = true
+	live.SFUViewers = 3
+	sessionRepo.SetSession(live)
+	expired := mocks.NewTestSession("test-token", false)
+	expired.Token = "expired-token"
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	sessionRepo.SetSession(expired)
+	historyRepo.SaveRecord(&entities.SessionRecord{Token: "old-token", RecordingURL: "https://example.com/old.webm"})
+	settingsRepo.SaveSettings(&entities.DeviceSettings{DeviceID: "device-1", LowPower: true})
+
+	saved, err := NewSnapshotUseCase(store, sessionRepo, historyRepo, settingsRepo).SaveSnapshot()
+	if err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+	if len(saved.Sessions) != 2 || len(saved.History) != 1 || len(saved.DeviceSettings) != 1 {
+		t.Fatalf("Expected 2 sessions, 1 record and 1 device in the snapshot, got %+v", saved)
+	}
+
+	// A restarted server starts with empty repositories
+	restoredSessions := mocks.NewMockSessionRepository()
+	restoredHistory := mocks.NewMockSessionHistoryRepository()
+	restoredSettings := mocks.NewMockDeviceSettingsRepository()
+	restored, err := NewSnapshotUseCase(store, restoredSessions, restoredHistory, restoredSettings).RestoreSnapshot()
+	if err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+	if restored != 1 {
+		t.Errorf("Expected 1 restored session, got %d", restored)
+	}
+
+	session, err := restoredSessions.GetSession("live-token")
+	if err != nil {
+		t.Fatalf("Live session not restored: %v", err)
+	}
+	if session.Status != live.Status || !session.SFU || session.SFUViewers != 0 {
+		t.Errorf("Expected the session back with no SFU viewers, got %+v", session)
+	}
+	if _, err := restoredSessions.GetSession("expired-token"); err == nil {
+		t.Error("Expected the expired session to be left out")
+	}
+	if record, err := restoredHistory.GetRecord("old-token"); err != nil || record.RecordingURL != "https://example.com/old.webm" {
+		t.Errorf("History not restored: %+v, %v", record, err)
+	}
+	if settings, err := restoredSettings.GetSettings("device-1"); err != nil || !settings.LowPower {
+		t.Errorf("Device settings not restored: %+v, %v", settings, err)
+	}
+}
+
+func TestSnapshotUseCase_RestoreSnapshot_Empty(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	uc := NewSnapshotUseCase(mocks.NewMockStateStore(), sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockDeviceSettingsRepository())
+
+	restored, err := uc.RestoreSnapshot()
+	if err != nil {
+		t.Fatalf("Expected a store holding nothing to restore nothing, got %v", err)
+	}
+	if restored != 0 || sessionRepo.GetSessionCount() != 0 {
+		t.Errorf("Expected no sessions, got %d", restored)
+	}
+}
+
+func TestSnapshotUseCase_RestoreSnapshot_Invalid(t *testing.T) {
+	store := mocks.NewMockStateStore()
+	state := entities.NewSnapshot()
+	state.Sessions = []*entities.Session{
+		{Token: "good-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Hour)},
+		{Token: "good-token", Status: entities.SessionStatusActive, ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	store.SetSnapshot(state)
+	sessionRepo := mocks.NewMockSessionRepository()
+	uc := NewSnapshotUseCase(store, sessionRepo, mocks.NewMockSessionHistoryRepository(), mocks.NewMockDeviceSettingsRepository())
+
+	if _, err := uc.RestoreSnapshot(); err == nil {
+		t.Fatal("Expected an invalid snapshot to be refused")
+	}
+	if sessionRepo.GetSessionCount() != 0 {
+		t.Error("Expected nothing to be loaded from an invalid snapshot")
+	}
+}
+
+func TestSnapshotUseCase_SaveSnapshot_Error(t *testing.T) {
+	store := mocks.NewMockStateStore()
+	store.ShouldFailSave = true
+	uc := NewSnapshotUseCase(store, mocks.NewMockSessionRepository(), mocks.NewMockSessionHistoryRepository(), mocks.NewMockDeviceSettingsRepository())
+
+	if _, err := uc.SaveSnapshot(); err == nil {
+		t.Error("Expected the store's error")
+	}
+}
@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+)
+
+// statsDayLayout names the day the summary totals cover
+const statsDayLayout = "2006-01-02"
+
+// StatsAggregator keeps today's totals of the sessions' lifecycle for the
+// stats summary. It sits in front of the audit log repository, so it counts
+// each lifecycle event as the session use case records it, and looks the
+// sessions up in the session repository for their durations and for how
+// many are live. Days follow the server's local time.
+type StatsAggregator struct {
+	interfaces.AuditLogRepository
+	sessionRepo interfaces.SessionRepository
+	now         func() time.Time
+
+	mu     sync.Mutex
+	day    string
+	totals statsTotals
+}
+
+// statsTotals are the counts of one day
+type statsTotals struct {
+	created   int
+	completed int
+	expired   int
+	peak      int
+
+	// ended and duration give the average duration of the sessions that
+	// ended during the day
+	ended    int
+	duration time.Duration
+}
+
+// NewStatsAggregator creates an aggregator recording the events in auditRepo
+func NewStatsAggregator(auditRepo interfaces.AuditLogRepository, sessionRepo interfaces.SessionRepository) *StatsAggregator {
+	return &StatsAggregator{
+		AuditLogRepository: auditRepo,
+		sessionRepo:        sessionRepo,
+		now:                time.Now,
+	}
+}
+
+// AppendEvent counts the event, then adds it to the audit log
+func (a *StatsAggregator) AppendEvent(event *entities.AuditEvent) error {
+	a.observe(event)
+	return a.AuditLogRepository.AppendEvent(event)
+}
+
+// observe counts a created or finished session; a session that ended or went
+// stale is counted then, not again when it is removed
+func (a *StatsAggregator) observe(event *entities.AuditEvent) {
+	switch event.Type {
+	case entities.AuditSessionCreated:
+		live, err := listLiveSessions(a.sessionRepo)
+		if err != nil {
+			return
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.rollover(event.At)
+		a.totals.created++
+		a.totals.peak = max(a.totals.peak, len(live))
+
+	case entities.AuditTerminated, entities.AuditStale, entities.AuditExpired:
+		session, err := a.sessionRepo.GetSession(event.Token)
+		if err != nil {
+			return
+		}
+		if event.Type == entities.AuditExpired && (session.IsEnded() || session.IsStale()) {
+			return
+		}
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.rollover(event.At)
+		if event.Type == entities.AuditTerminated {
+			a.totals.completed++
+		} else {
+			a.totals.expired++
+		}
+		a.totals.ended++
+		a.totals.duration += sessionDuration(session, event.At)
+	}
+}
+
+// today returns the day and its totals, with the peak raised to the live
+// sessions counted by the caller
+func (a *StatsAggregator) today(live int) (string, statsTotals) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rollover(a.now())
+	a.totals.peak = max(a.totals.peak, live)
+	return a.day, a.totals
+}
+
+// rollover starts new totals when at falls on another day; must be called
+// with the lock held
+func (a *StatsAggregator) rollover(at time.Time) {
+	if day := at.Local().Format(statsDayLayout); day != a.day {
+		a.day = day
+		a.totals = statsTotals{}
+	}
+}
+
+// sessionDuration returns how long a finished session ran; a session that
+// expired while live ran until its token expired
+func sessionDuration(session *entities.Session, at time.Time) time.Duration {
+	end := session.EndedAt
+	if end.IsZero() {
+		end = at
+		if session.ExpiresAt.Before(at) {
+			end = session.ExpiresAt
+		}
+	}
+	return max(end.Sub(session.CreatedAt), 0)
+}
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/test/mocks"
+)
+
+func TestStatsUseCase_GetStatsSummary(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	auditRepo := mocks.NewMockAuditLogRepository()
+	aggregator := NewStatsAggregator(auditRepo, sessionRepo)
+	useCase := NewStatsUseCase(mocks.NewMockStatsRepository(), sessionRepo, mocks.NewMockSessionHistoryRepository(), aggregator)
+
+	record := func(token string, eventType entities.AuditEventType) {
+		t.Helper()
+		if err := aggregator.AppendEvent(&entities.AuditEvent{At: time.Now(), Token: token, Type: eventType}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	live := mocks.NewTestSession("live", false)
+	live.CreatedAt = time.Now().Add(-time.Minute)
+	sessionRepo.SetSession(live)
+	record("live", entities.AuditSessionCreated)
+
+	ended := mocks.NewTestSession("ended", false)
+	ended.CreatedAt = time.Now().Add(-10 * time.Minute)
+	sessionRepo.SetSession(ended)
+	record("ended", entities.AuditSessionCreated)
+	ended.End()
+	sessionRepo.SetSession(ended)
+	record("ended", entities.AuditTerminated)
+
+	stale := mocks.NewTestSession("stale", false)
+	stale.CreatedAt = time.Now().Add(-20 * time.Minute)
+	sessionRepo.SetSession(stale)
+	record("stale", entities.AuditSessionCreated)
+	stale.SenderSeenAt = stale.CreatedAt.Add(5 * time.Minute)
+	stale.MarkStale()
+	sessionRepo.SetSession(stale)
+	record("stale", entities.AuditStale)
+
+	// Removing the ended session must not count it again
+	record("ended", entities.AuditExpired)
+
+	summary, err := useCase.GetStatsSummary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary.Created != 3 || summary.Completed != 1 || summary.Expired != 1 || summary.Active != 1 || summary.PeakConcurrent != 2 {
+		t.Errorf("Unexpected totals %+v", summary)
+	}
+	// (10 minutes + 5 minutes) / 2
+	if summary.AverageDurationSeconds < 449 || summary.AverageDurationSeconds > 451 {
+		t.Errorf("Expected an average of 450s, got %v", summary.AverageDurationSeconds)
+	}
+	if summary.Date != time.Now().Format(statsDayLayout) {
+		t.Errorf("Expected today's date, got %q", summary.Date)
+	}
+	if events, _ := auditRepo.ListEvents("ended"); len(events) != 3 {
+		t.Errorf("Expected the events to reach the audit log, got %d", len(events))
+	}
+}
+
+func TestStatsAggregator_ExpiredWhileLive(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	aggregator := NewStatsAggregator(mocks.NewMockAuditLogRepository(), sessionRepo)
+
+	session := mocks.NewTestSession("idle", false)
+	session.CreatedAt = time.Now().Add(-10 * time.Minute)
+	session.ExpiresAt = session.CreatedAt.Add(2 * time.Minute)
+	sessionRepo.SetSession(session)
+	_ = aggregator.AppendEvent(&entities.AuditEvent{At: time.Now(), Token: "idle", Type: entities.AuditExpired})
+
+	_, totals := aggregator.today(0)
+	if totals.expired != 1 || totals.duration != 2*time.Minute {
+		t.Errorf("Expected one session expired after its 2 minute token, got %+v", totals)
+	}
+}
+
+func TestStatsAggregator_Rollover(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	aggregator := NewStatsAggregator(mocks.NewMockAuditLogRepository(), sessionRepo)
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	old := mocks.NewTestSession("old", false)
+	old.CreatedAt = time.Now().Add(-time.Hour)
+	sessionRepo.SetSession(old)
+	_ = aggregator.AppendEvent(&entities.AuditEvent{At: yesterday, Token: "old", Type: entities.AuditSessionCreated})
+
+	day, totals := aggregator.today(1)
+	if day != time.Now().Format(statsDayLayout) || totals.created != 0 {
+		t.Errorf("Expected new totals for today, got %s %+v", day, totals)
+	}
+	if totals.peak != 1 {
+		t.Errorf("Expected the peak to start at the live sessions, got %d", totals.peak)
+	}
+}
@@ -0,0 +1,298 @@
+package usecases
+
+import (
+	"cmp"
+	"log"
+	"slices"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+const (
+	// statsFreshness is how old a peer's last sample may be for the dashboard
+	// to show it; pages upload every five seconds, so an older one has gone away
+	statsFreshness = 30 * time.Second
+
+	// maxStatsViewerIDLength bounds the viewer IDs stored with samples
+	maxStatsViewerIDLength = 64
+
+	// viewerStatsWindow is how far back the sender's view of its viewers
+	// averages their samples, long enough to smooth out a single bad one
+	viewerStatsWindow = time.Minute
+)
+
+// StatsUseCase implements the WebRTC stats use case interface
+type StatsUseCase struct {
+	statsRepo   interfaces.StatsRepository
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+	aggregator  *StatsAggregator
+}
+
+// NewStatsUseCase creates a new stats use case; aggregator provides the
+// totals of the stats summary
+func NewStatsUseCase(statsRepo interfaces.StatsRepository, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, aggregator *StatsAggregator) *StatsUseCase {
+	return &StatsUseCase{
+		statsRepo:   statsRepo,
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+		aggregator:  aggregator,
+	}
+}
+
+// RecordStats stores a peer's stats summary, stamped with the server's time,
+// in the time series of its live session
+func (uc *StatsUseCase) RecordStats(request *dto.RecordStatsRequest) error {
+	sample := &entities.StatsSample{
+		At:         time.Now(),
+		Role:       request.Role,
+		ViewerID:   request.ViewerID,
+		Bitrate:    request.Bitrate,
+		FrameRate:  request.FrameRate,
+		PacketLoss: request.PacketLoss,
+		RTTMillis:  request.RTTMillis,
+	}
+	if !sample.IsValid() || len(sample.ViewerID) > maxStatsViewerIDLength {
+		return ErrInvalidStats
+	}
+	if sample.Role == entities.StatsRoleSender {
+		sample.ViewerID = ""
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	return uc.statsRepo.AppendStats(session.Token, sample)
+}
+
+// RecordLatency stores the round-trip time a peer of a live session measured
+// with the latency probe, stamped with the server's time, in place of its
+// earlier result for the same path
+func (uc *StatsUseCase) RecordLatency(request *dto.RecordLatencyRequest) error {
+	sample := &entities.LatencySample{
+		At:        time.Now(),
+		Role:      request.Role,
+		ViewerID:  request.ViewerID,
+		Path:      request.Path,
+		RTTMillis: request.RTTMillis,
+		Network:   request.Network,
+	}
+	if !sample.IsValid() || len(sample.ViewerID) > maxStatsViewerIDLength {
+		return ErrInvalidStats
+	}
+	if sample.Role == entities.StatsRoleSender {
+		sample.ViewerID = ""
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	return uc.statsRepo.SetLatency(session.Token, sample)
+}
+
+// GetSessionStats returns the stats of a session taken after request.Since,
+// oldest first, and its latest latency results; ended sessions keep theirs
+// until they are cleaned up
+func (uc *StatsUseCase) GetSessionStats(request *dto.GetStatsRequest) (*dto.SessionStatsResponse, error) {
+	if _, err := uc.sessionRepo.GetSession(request.Token); err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	samples, err := uc.statsRepo.ListStats(request.Token, request.Since)
+	if err != nil {
+		return nil, err
+	}
+	latency, err := uc.statsRepo.ListLatency(request.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.SessionStatsResponse{Token: request.Token, Samples: samples, Latency: latency}, nil
+}
+
+// ListSessionStats returns the live sessions, oldest first, with the latest
+// sample of each peer still uploading stats
+func (uc *StatsUseCase) ListSessionStats() (*dto.AdminSessionsResponse, error) {
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(sessions, func(a, b *entities.Session) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	response := &dto.AdminSessionsResponse{Sessions: []dto.AdminSession{}}
+	for _, session := range sessions {
+		samples, err := uc.statsRepo.ListStats(session.Token, time.Now().Add(-statsFreshness))
+		if err != nil {
+			return nil, err
+		}
+
+		viewers := session.SFUViewers
+		if session.IsFull() {
+			viewers++
+		}
+		response.Sessions = append(response.Sessions, dto.AdminSession{
+			Token:     session.Token,
+			Name:      session.Name,
+			Status:    session.Status,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			SFU:       session.SFU,
+			Viewers:   viewers,
+			Queued:    len(session.Queue),
+			Latest:    latestPerSource(samples),
+		})
+	}
+	return response, nil
+}
+
+// GetStatsSummary returns today's session totals and the sessions live now
+func (uc *StatsUseCase) GetStatsSummary() (*dto.StatsSummaryResponse, error) {
+	live, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	day, totals := uc.aggregator.today(len(live))
+	response := &dto.StatsSummaryResponse{
+		Date:           day,
+		Created:        totals.created,
+		Completed:      totals.completed,
+		Expired:        totals.expired,
+		Active:         len(live),
+		PeakConcurrent: totals.peak,
+	}
+	if totals.ended > 0 {
+		response.AverageDurationSeconds = totals.duration.Seconds() / float64(totals.ended)
+	}
+	return response, nil
+}
+
+// GetViewerStats sums up the samples each viewer of the session uploaded
+// during the last viewerStatsWindow, with the viewer's fresh latency results,
+// for the sender holding its sender key. Viewers whose latest sample is older
+// than statsFreshness have gone away and are left out.
+func (uc *StatsUseCase) GetViewerStats(request *dto.GetViewerStatsRequest) (*dto.ViewerStatsResponse, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.CheckSenderKey(request.SenderKey) {
+		log.Printf("🔒 Wrong sender key for the viewer stats of token: %s", entities.ShortToken(request.Token))
+		return nil, ErrInvalidSenderKey
+	}
+
+	now := time.Now()
+	samples, err := uc.statsRepo.ListStats(session.Token, now.Add(-viewerStatsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	byViewer := make(map[string][]entities.StatsSample)
+	for _, sample := range samples {
+		if sample.Role == entities.StatsRoleViewer {
+			byViewer[sample.ViewerID] = append(byViewer[sample.ViewerID], sample)
+		}
+	}
+	latency, err := uc.statsRepo.ListLatency(session.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ViewerStatsResponse{WindowSeconds: int(viewerStatsWindow.Seconds()), Viewers: []dto.ViewerStats{}}
+	for viewerID, samples := range byViewer {
+		latest := samples[len(samples)-1]
+		if now.Sub(latest.At) > statsFreshness {
+			continue
+		}
+		stats := summarizeViewerStats(viewerID, samples)
+		if viewerID != "" && viewerID == session.ViewerID {
+			stats.Name = session.ViewerName
+		}
+		for _, result := range latency {
+			if result.Role == entities.StatsRoleViewer && result.ViewerID == viewerID && now.Sub(result.At) <= statsFreshness {
+				stats.Latency = append(stats.Latency, result)
+			}
+		}
+		response.Viewers = append(response.Viewers, stats)
+	}
+	slices.SortFunc(response.Viewers, func(a, b dto.ViewerStats) int {
+		return cmp.Compare(a.ViewerID, b.ViewerID)
+	})
+	return response, nil
+}
+
+// summarizeViewerStats averages a viewer's samples, oldest first, and rates
+// its connection by the averages
+func summarizeViewerStats(viewerID string, samples []entities.StatsSample) dto.ViewerStats {
+	var bitrate int64
+	var frameRate, packetLoss, rtt float64
+	for _, sample := range samples {
+		bitrate += sample.Bitrate
+		frameRate += sample.FrameRate
+		packetLoss += sample.PacketLoss
+		rtt += sample.RTTMillis
+	}
+	n := float64(len(samples))
+	stats := dto.ViewerStats{
+		ViewerID:          viewerID,
+		Latest:            samples[len(samples)-1],
+		Samples:           len(samples),
+		AverageBitrate:    bitrate / int64(len(samples)),
+		AverageFrameRate:  frameRate / n,
+		AveragePacketLoss: packetLoss / n,
+		AverageRTTMillis:  rtt / n,
+	}
+	stats.Quality = entities.RateConnection(stats.AveragePacketLoss, stats.AverageRTTMillis)
+	return stats
+}
+
+// PruneStats drops the stats of sessions that have been cleaned up, returning
+// how many sessions were pruned
+func (uc *StatsUseCase) PruneStats() (int, error) {
+	tokens, err := uc.statsRepo.GetTokens()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, token := range tokens {
+		if _, err := uc.sessionRepo.GetSession(token); err == nil {
+			continue
+		}
+		if err := uc.statsRepo.DeleteStats(token); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	if pruned > 0 {
+		log.Printf("🗑️  Dropped stats of %d cleaned up sessions", pruned)
+	}
+	return pruned, nil
+}
+
+// latestPerSource keeps the newest of the samples from each peer, the sender
+// first and then the viewers by ID
+func latestPerSource(samples []entities.StatsSample) []entities.StatsSample {
+	latest := make(map[string]entities.StatsSample)
+	for _, sample := range samples {
+		latest[sample.Source()] = sample
+	}
+
+	result := make([]entities.StatsSample, 0, len(latest))
+	for _, sample := range latest {
+		result = append(result, sample)
+	}
+	slices.SortFunc(result, func(a, b entities.StatsSample) int {
+		// "sender" sorts before "viewer"
+		return cmp.Or(cmp.Compare(a.Role, b.Role), cmp.Compare(a.ViewerID, b.ViewerID))
+	})
+	return result
+}
@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestStatsUseCase_RecordStats(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.RecordStatsRequest
+		expectedError error
+	}{
+		{
+			name:    "sender stats",
+			request: &dto.RecordStatsRequest{Token: "test-token", Role: "sender", Bitrate: 2_000_000, FrameRate: 30, PacketLoss: 0.01, RTTMillis: 15},
+		},
+		{
+			name:    "viewer stats",
+			request: &dto.RecordStatsRequest{Token: "test-token", Role: "viewer", ViewerID: "viewer-1", Bitrate: 1_900_000, FrameRate: 29},
+		},
+		{
+			name:          "unknown role",
+			request:       &dto.RecordStatsRequest{Token: "test-token", Role: "admin"},
+			expectedError: ErrInvalidStats,
+		},
+		{
+			name:          "loss out of range",
+			request:       &dto.RecordStatsRequest{Token: "test-token", Role: "viewer", PacketLoss: 2},
+			expectedError: ErrInvalidStats,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.RecordStatsRequest{Token: "missing", Role: "sender"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(mocks.NewTestSession("test-token", true))
+			statsRepo := mocks.NewMockStatsRepository()
+			useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+			err := useCase.RecordStats(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			samples, _ := statsRepo.ListStats("test-token", time.Time{})
+			if err != nil {
+				if len(samples) != 0 {
+					t.Errorf("Expected nothing stored, got %+v", samples)
+				}
+				return
+			}
+			if len(samples) != 1 || samples[0].Bitrate != tt.request.Bitrate || samples[0].At.IsZero() {
+				t.Errorf("Expected the stamped sample to be stored, got %+v", samples)
+			}
+		})
+	}
+}
+
+func TestStatsUseCase_RecordLatency(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.RecordLatencyRequest
+		expectedError error
+	}{
+		{
+			name:    "sender to viewer",
+			request: &dto.RecordLatencyRequest{Token: "test-token", Role: "sender", ViewerID: "ignored", Path: "peer", RTTMillis: 35, Network: "lan"},
+		},
+		{
+			name:    "viewer to server",
+			request: &dto.RecordLatencyRequest{Token: "test-token", Role: "viewer", ViewerID: "viewer-1", Path: "server", RTTMillis: 80, Network: "internet"},
+		},
+		{
+			name:          "unknown path",
+			request:       &dto.RecordLatencyRequest{Token: "test-token", Role: "viewer", Path: "dns"},
+			expectedError: ErrInvalidStats,
+		},
+		{
+			name:          "unknown network",
+			request:       &dto.RecordLatencyRequest{Token: "test-token", Role: "viewer", Path: "peer", Network: "5g"},
+			expectedError: ErrInvalidStats,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.RecordLatencyRequest{Token: "missing", Role: "sender", Path: "peer"},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			mockRepo.SetSession(mocks.NewTestSession("test-token", true))
+			statsRepo := mocks.NewMockStatsRepository()
+			useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+			err := useCase.RecordLatency(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			results, _ := statsRepo.ListLatency("test-token")
+			if err != nil {
+				if len(results) != 0 {
+					t.Errorf("Expected nothing stored, got %+v", results)
+				}
+				return
+			}
+			if len(results) != 1 || results[0].RTTMillis != tt.request.RTTMillis || results[0].At.IsZero() {
+				t.Fatalf("Expected the stamped result to be stored, got %+v", results)
+			}
+			if results[0].Role == "sender" && results[0].ViewerID != "" {
+				t.Errorf("Expected the sender's result without a viewer ID, got %+v", results[0])
+			}
+		})
+	}
+}
+
+func TestStatsUseCase_GetSessionStats(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", true))
+	statsRepo := mocks.NewMockStatsRepository()
+	useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_ = statsRepo.AppendStats("test-token", &entities.StatsSample{At: start.Add(time.Duration(i) * time.Second), Role: "sender", Bitrate: int64(i)})
+	}
+	_ = statsRepo.SetLatency("test-token", &entities.LatencySample{At: start, Role: "sender", Path: entities.LatencyPathPeer, RTTMillis: 35})
+
+	response, err := useCase.GetSessionStats(&dto.GetStatsRequest{Token: "test-token", Since: start})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.Token != "test-token" || len(response.Samples) != 2 || response.Samples[0].Bitrate != 1 {
+		t.Errorf("Expected the two samples after since, got %+v", response)
+	}
+	if len(response.Latency) != 1 || response.Latency[0].RTTMillis != 35 {
+		t.Errorf("Expected the sender's latency result, got %+v", response.Latency)
+	}
+
+	if _, err := useCase.GetSessionStats(&dto.GetStatsRequest{Token: "missing"}); err != ErrSessionNotFound {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestStatsUseCase_ListSessionStats(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", true, "queued-1"))
+	ended := mocks.NewTestSession("test-token", false)
+	ended.Token = "ended-token"
+	ended.End()
+	mockRepo.SetSession(ended)
+	statsRepo := mocks.NewMockStatsRepository()
+	useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+	now := time.Now()
+	for _, sample := range []entities.StatsSample{
+		{At: now.Add(-time.Minute), Role: "viewer", ViewerID: "gone", Bitrate: 1},
+		{At: now.Add(-2 * time.Second), Role: "viewer", ViewerID: "viewer-1", Bitrate: 2},
+		{At: now.Add(-time.Second), Role: "viewer", ViewerID: "viewer-1", Bitrate: 3},
+		{At: now.Add(-time.Second), Role: "sender", Bitrate: 4},
+	} {
+		_ = statsRepo.AppendStats("test-token", &sample)
+	}
+
+	response, err := useCase.ListSessionStats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(response.Sessions) != 1 {
+		t.Fatalf("Expected only the live session, got %+v", response.Sessions)
+	}
+	session := response.Sessions[0]
+	if session.Token != "test-token" || session.Viewers != 1 || session.Queued != 1 {
+		t.Errorf("Unexpected session summary %+v", session)
+	}
+	if len(session.Latest) != 2 || session.Latest[0].Bitrate != 4 || session.Latest[1].Bitrate != 3 {
+		t.Errorf("Expected the sender's and the connected viewer's latest samples, got %+v", session.Latest)
+	}
+}
+
+func TestStatsUseCase_GetViewerStats(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", true)
+	session.SenderKey = "sender-key"
+	session.ViewerID = "viewer-1"
+	session.ViewerName = "Ari's iPhone"
+	mockRepo.SetSession(session)
+	statsRepo := mocks.NewMockStatsRepository()
+	useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+	now := time.Now()
+	for _, sample := range []entities.StatsSample{
+		// Outside the window, so not averaged
+		{At: now.Add(-2 * viewerStatsWindow), Role: "viewer", ViewerID: "viewer-1", Bitrate: 9_000_000, PacketLoss: 1},
+		{At: now.Add(-10 * time.Second), Role: "viewer", ViewerID: "viewer-1", Bitrate: 1_000_000, FrameRate: 20, PacketLoss: 0.02, RTTMillis: 40},
+		{At: now.Add(-5 * time.Second), Role: "viewer", ViewerID: "viewer-1", Bitrate: 3_000_000, FrameRate: 30, PacketLoss: 0, RTTMillis: 20},
+		{At: now.Add(-3 * time.Second), Role: "viewer", ViewerID: "sfu-2", Bitrate: 500_000, PacketLoss: 0.1, RTTMillis: 300},
+		// Stopped uploading a while ago
+		{At: now.Add(-statsFreshness - time.Second), Role: "viewer", ViewerID: "gone", Bitrate: 1},
+		{At: now.Add(-time.Second), Role: "sender", Bitrate: 4_000_000},
+	} {
+		_ = statsRepo.AppendStats("test-token", &sample)
+	}
+	for _, sample := range []entities.LatencySample{
+		{At: now.Add(-2 * time.Second), Role: "viewer", ViewerID: "viewer-1", Path: entities.LatencyPathPeer, RTTMillis: 35, Network: entities.NetworkLAN},
+		{At: now.Add(-time.Second), Role: "sender", Path: entities.LatencyPathPeer, RTTMillis: 36},
+		// Measured before the viewer's latest stats stopped coming
+		{At: now.Add(-statsFreshness - time.Second), Role: "viewer", ViewerID: "sfu-2", Path: entities.LatencyPathServer, RTTMillis: 90},
+	} {
+		_ = statsRepo.SetLatency("test-token", &sample)
+	}
+
+	if _, err := useCase.GetViewerStats(&dto.GetViewerStatsRequest{Token: "test-token", SenderKey: "wrong"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+
+	response, err := useCase.GetViewerStats(&dto.GetViewerStatsRequest{Token: "test-token", SenderKey: "sender-key"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.WindowSeconds != 60 || len(response.Viewers) != 2 {
+		t.Fatalf("Expected the two viewers still uploading, got %+v", response)
+	}
+	sfu, p2p := response.Viewers[0], response.Viewers[1]
+	if p2p.ViewerID != "viewer-1" || p2p.Name != "Ari's iPhone" || p2p.Samples != 2 || p2p.Latest.Bitrate != 3_000_000 {
+		t.Errorf("Unexpected summary of the connected viewer %+v", p2p)
+	}
+	if p2p.AverageBitrate != 2_000_000 || p2p.AverageFrameRate != 25 || p2p.AveragePacketLoss != 0.01 || p2p.AverageRTTMillis != 30 || p2p.Quality != entities.QualityFair {
+		t.Errorf("Expected the window's averages rated fair, got %+v", p2p)
+	}
+	if len(p2p.Latency) != 1 || p2p.Latency[0].RTTMillis != 35 || p2p.Latency[0].Network != entities.NetworkLAN {
+		t.Errorf("Expected the viewer's latency result, got %+v", p2p.Latency)
+	}
+	if sfu.ViewerID != "sfu-2" || sfu.Name != "" || sfu.Quality != entities.QualityPoor || sfu.Latency != nil {
+		t.Errorf("Unexpected summary of the SFU viewer %+v", sfu)
+	}
+}
+
+func TestStatsUseCase_PruneStats(t *testing.T) {
+	mockRepo := mocks.NewMockSessionRepository()
+	mockRepo.SetSession(mocks.NewTestSession("test-token", false))
+	statsRepo := mocks.NewMockStatsRepository()
+	useCase := NewStatsUseCase(statsRepo, mockRepo, mocks.NewMockSessionHistoryRepository(), nil)
+
+	sample := &entities.StatsSample{At: time.Now(), Role: "sender"}
+	_ = statsRepo.AppendStats("test-token", sample)
+	_ = statsRepo.AppendStats("cleaned-up", sample)
+
+	pruned, err := useCase.PruneStats()
+	if err != nil || pruned != 1 {
+		t.Fatalf("Expected one session pruned, got %d, %v", pruned, err)
+	}
+	if tokens, _ := statsRepo.GetTokens(); len(tokens) != 1 || tokens[0] != "test-token" {
+		t.Errorf("Expected the live session's stats to stay, got %v", tokens)
+	}
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import (
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// StatusUseCase implements the viewer status use case interface
+type StatusUseCase struct {
+	sessionRepo interfaces.SessionRepository
+}
+
+// NewStatusUseCase creates a new status use case
+func NewStatusUseCase(sessionRepo interfaces.SessionRepository) *StatusUseCase {
+	return &StatusUseCase{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// GetViewerStatus counts the connected and queued viewers across live sessions
+func (uc *StatusUseCase) GetViewerStatus() (*dto.ViewerStatusResponse, error) {
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.ViewerStatusResponse{Sessions: len(sessions)}
+	for _, session := range sessions {
+		if session.IsFull() {
+			status.Viewers++
+		}
+		status.Viewers += session.SFUViewers
+		status.Queued += len(session.Queue)
+	}
+	status.Viewing = status.Viewers > 0
+
+	return status, nil
+}
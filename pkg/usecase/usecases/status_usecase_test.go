@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestStatusUseCase_GetViewerStatus(t *testing.T) {
+	ended := mocks.NewTestSession("test-token", true)
+	ended.Token = "ended"
+	ended.End()
+
+	expired := mocks.NewTestSession("test-token", true, "viewer-1")
+	expired.Token = "expired"
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	abandoned := mocks.NewTestSession("test-token", true)
+	abandoned.Token = "abandoned"
+	abandoned.SenderSeenAt = time.Now().Add(-2 * DefaultHeartbeatTimeout)
+	abandoned.HeartbeatTimeout = DefaultHeartbeatTimeout
+
+	watched := mocks.NewTestSession("test-token", true, "viewer-2", "viewer-3")
+	watched.Token = "watched"
+
+	waiting := mocks.NewTestSession("test-token", false)
+	waiting.Token = "waiting"
+
+	relayed := mocks.NewTestSession("test-token", false)
+	relayed.Token = "relayed"
+	relayed.SFU = true
+	relayed.SFUViewers = 5
+
+	tests := []struct {
+		name     string
+		sessions []*entities.Session
+		expected dto.ViewerStatusResponse
+	}{
+		{
+			name:     "no sessions",
+			expected: dto.ViewerStatusResponse{},
+		},
+		{
+			name:     "session without viewer",
+			sessions: []*entities.Session{waiting},
+			expected: dto.ViewerStatusResponse{Sessions: 1},
+		},
+		{
+			name:     "viewer connected with queue",
+			sessions: []*entities.Session{watched, waiting},
+			expected: dto.ViewerStatusResponse{Viewing: true, Viewers: 1, Queued: 2, Sessions: 2},
+		},
+		{
+			name:     "viewers on the SFU",
+			sessions: []*entities.Session{relayed, watched},
+			expected: dto.ViewerStatusResponse{Viewing: true, Viewers: 6, Queued: 2, Sessions: 2},
+		},
+		{
+			name:     "finished sessions ignored",
+			sessions: []*entities.Session{ended, expired, abandoned},
+			expected: dto.ViewerStatusResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewMockSessionRepository()
+			for _, session := range tt.sessions {
+				sessionRepo.SetSession(session)
+			}
+			useCase := NewStatusUseCase(sessionRepo)
+
+			status, err := useCase.GetViewerStatus()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if *status != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, *status)
+			}
+		})
+	}
+}
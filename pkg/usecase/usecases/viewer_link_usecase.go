@@ -0,0 +1,196 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// viewerLinkPathPrefix is where the viewer page of a viewer link is served
+const viewerLinkPathPrefix = "/l/"
+
+// ViewerLinkUseCase implements the viewer link use case interface
+type ViewerLinkUseCase struct {
+	sessionRepo interfaces.SessionRepository
+	historyRepo interfaces.SessionHistoryRepository
+
+	// redeemMu keeps two viewers from taking a link's last use at once
+	redeemMu sync.Mutex
+}
+
+// NewViewerLinkUseCase creates a new viewer link use case
+func NewViewerLinkUseCase(sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository) *ViewerLinkUseCase {
+	return &ViewerLinkUseCase{
+		sessionRepo: sessionRepo,
+		historyRepo: historyRepo,
+	}
+}
+
+// CreateLink hands out a new viewer link for a live session; only the
+// session's sender may. The link expires on its own, and with its session
+// at the latest.
+func (uc *ViewerLinkUseCase) CreateLink(request *dto.CreateViewerLinkRequest) (*dto.ViewerLink, error) {
+	if request.TTLSeconds <= 0 || request.MaxRedemptions < 0 {
+		return nil, ErrInvalidViewerLink
+	}
+
+	session, err := uc.senderSession(request.Token, request.SenderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := generateViewerLinkID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	link := entities.ViewerLink{
+		ID:             id,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(time.Duration(request.TTLSeconds) * time.Second),
+		MaxRedemptions: request.MaxRedemptions,
+	}
+	if !session.AddLink(link) {
+		return nil, ErrTooManyViewerLinks
+	}
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error saving viewer link: %v", err)
+		return nil, err
+	}
+
+	log.Printf("🔗 Viewer link for %ds handed out for token: %s", request.TTLSeconds, entities.ShortToken(request.Token))
+	response := newViewerLinkResponse(&link, now)
+	return &response, nil
+}
+
+// ListLinks returns the viewer links of a live session, oldest first,
+// including those that expired since the last one was handed out
+func (uc *ViewerLinkUseCase) ListLinks(request *dto.ListViewerLinksRequest) (*dto.ViewerLinksResponse, error) {
+	session, err := uc.senderSession(request.Token, request.SenderKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	response := &dto.ViewerLinksResponse{Links: make([]dto.ViewerLink, 0, len(session.Links))}
+	for i := range session.Links {
+		response.Links = append(response.Links, newViewerLinkResponse(&session.Links[i], now))
+	}
+	return response, nil
+}
+
+// RevokeLink withdraws a viewer link before it expires; viewers who already
+// opened it stay connected, and the sender can remove them separately
+func (uc *ViewerLinkUseCase) RevokeLink(request *dto.RevokeViewerLinkRequest) error {
+	session, err := uc.senderSession(request.Token, request.SenderKey)
+	if err != nil {
+		return err
+	}
+
+	if !session.RemoveLink(request.ID) {
+		return ErrViewerLinkNotFound
+	}
+	if err := uc.sessionRepo.UpdateSession(session); err != nil {
+		log.Printf("❌ Error revoking viewer link: %v", err)
+		return err
+	}
+
+	log.Printf("🔗 Viewer link revoked for token: %s", entities.ShortToken(request.Token))
+	return nil
+}
+
+// RedeemLink returns the session a viewer link leads to, taking one of the
+// link's uses. A viewer that already opened the link, e.g. before reloading
+// its page, may open it again until it expires without taking another.
+func (uc *ViewerLinkUseCase) RedeemLink(request *dto.RedeemViewerLinkRequest) (*dto.RedeemViewerLinkResponse, error) {
+	if request.ViewerID == "" {
+		return nil, ErrMissingViewerID
+	}
+
+	uc.redeemMu.Lock()
+	defer uc.redeemMu.Unlock()
+
+	sessions, err := listLiveSessions(uc.sessionRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		link := session.FindLink(request.ID)
+		if link == nil {
+			continue
+		}
+
+		// The listing may be a moment old; check the session as viewers
+		// would find it
+		session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, session.Token)
+		if err != nil {
+			return nil, err
+		}
+		link = session.FindLink(request.ID)
+		if link == nil {
+			return nil, ErrViewerLinkNotFound
+		}
+		if link.IsExpired(time.Now()) {
+			return nil, ErrViewerLinkExpired
+		}
+		if link.HasRedeemed(request.ViewerID) {
+			return &dto.RedeemViewerLinkResponse{Token: session.Token}, nil
+		}
+		if link.IsUsedUp() {
+			log.Printf("🔒 Used up viewer link opened from %s for token: %s", request.ClientIP, entities.ShortToken(session.Token))
+			return nil, ErrViewerLinkUsed
+		}
+
+		link.Redeem(request.ViewerID)
+		if err := uc.sessionRepo.UpdateSession(session); err != nil {
+			log.Printf("❌ Error redeeming viewer link: %v", err)
+			return nil, err
+		}
+		log.Printf("🔗 Viewer link redeemed (%d/%d) for token: %s", len(link.Redeemers), link.MaxRedemptions, entities.ShortToken(session.Token))
+		return &dto.RedeemViewerLinkResponse{Token: session.Token}, nil
+	}
+	return nil, ErrViewerLinkNotFound
+}
+
+// senderSession returns the live session with token once key proves the
+// request comes from its sender
+func (uc *ViewerLinkUseCase) senderSession(token, key string) (*entities.Session, error) {
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, token)
+	if err != nil {
+		return nil, err
+	}
+	if !session.CheckSenderKey(key) {
+		log.Printf("🔒 Wrong sender key for viewer links of token: %s", entities.ShortToken(token))
+		return nil, ErrInvalidSenderKey
+	}
+	return session, nil
+}
+
+// newViewerLinkResponse describes a viewer link to its sender as of now
+func newViewerLinkResponse(link *entities.ViewerLink, now time.Time) dto.ViewerLink {
+	return dto.ViewerLink{
+		ID:             link.ID,
+		Path:           viewerLinkPathPrefix + link.ID,
+		CreatedAt:      link.CreatedAt,
+		ExpiresAt:      link.ExpiresAt,
+		MaxRedemptions: link.MaxRedemptions,
+		Redemptions:    len(link.Redeemers),
+		Usable:         !link.IsExpired(now) && !link.IsUsedUp(),
+	}
+}
+
+// generateViewerLinkID creates the unguessable ID a viewer link is opened by
+func generateViewerLinkID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
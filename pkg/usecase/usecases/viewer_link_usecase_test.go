@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestViewerLinkUseCase_CreateLink(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       *dto.CreateViewerLinkRequest
+		expectedError error
+	}{
+		{
+			name:    "link for five minutes",
+			request: &dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 300, MaxRedemptions: 1},
+		},
+		{
+			name:          "no ttl",
+			request:       &dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key"},
+			expectedError: ErrInvalidViewerLink,
+		},
+		{
+			name:          "negative redemptions",
+			request:       &dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 300, MaxRedemptions: -1},
+			expectedError: ErrInvalidViewerLink,
+		},
+		{
+			name:          "wrong sender key",
+			request:       &dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "guess", TTLSeconds: 300},
+			expectedError: ErrInvalidSenderKey,
+		},
+		{
+			name:          "unknown session",
+			request:       &dto.CreateViewerLinkRequest{Token: "missing", SenderKey: "sender-key", TTLSeconds: 300},
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionRepo := mocks.NewMockSessionRepository()
+			session := mocks.NewTestSession("test-token", false)
+			session.SenderKey = "sender-key"
+			sessionRepo.SetSession(session)
+			useCase := NewViewerLinkUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+			link, err := useCase.CreateLink(tt.request)
+			if err != tt.expectedError {
+				t.Fatalf("Expected error %v, got %v", tt.expectedError, err)
+			}
+			if tt.expectedError != nil {
+				return
+			}
+
+			if link.ID == "" || link.Path != "/l/"+link.ID || !link.Usable || link.MaxRedemptions != 1 {
+				t.Errorf("Unexpected link %+v", link)
+			}
+			if ttl := link.ExpiresAt.Sub(link.CreatedAt); ttl != 5*time.Minute {
+				t.Errorf("Expected the link to last 5 minutes, got %v", ttl)
+			}
+			session, _ = sessionRepo.GetSession("test-token")
+			if session.FindLink(link.ID) == nil {
+				t.Error("Expected the link stored with its session")
+			}
+		})
+	}
+}
+
+func TestViewerLinkUseCase_CreateLinkLimit(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SenderKey = "sender-key"
+	sessionRepo.SetSession(session)
+	useCase := NewViewerLinkUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+	request := &dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 60}
+	for i := 0; i < entities.MaxViewerLinks; i++ {
+		if _, err := useCase.CreateLink(request); err != nil {
+			t.Fatalf("Expected link %d to be handed out, got %v", i, err)
+		}
+	}
+	if _, err := useCase.CreateLink(request); err != ErrTooManyViewerLinks {
+		t.Errorf("Expected ErrTooManyViewerLinks, got %v", err)
+	}
+}
+
+func TestViewerLinkUseCase_RedeemLink(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SenderKey = "sender-key"
+	sessionRepo.SetSession(session)
+	useCase := NewViewerLinkUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+	link, err := useCase.CreateLink(&dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 300, MaxRedemptions: 1})
+	if err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+
+	redeemed, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID, ViewerID: "viewer-1"})
+	if err != nil || redeemed.Token != "test-token" {
+		t.Fatalf("Expected the link to lead to its session, got %+v, %v", redeemed, err)
+	}
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID, ViewerID: "viewer-1"}); err != nil {
+		t.Errorf("Expected the viewer that opened the link to open it again, got %v", err)
+	}
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID, ViewerID: "viewer-2"}); err != ErrViewerLinkUsed {
+		t.Errorf("Expected ErrViewerLinkUsed for a second viewer, got %v", err)
+	}
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID}); err != ErrMissingViewerID {
+		t.Errorf("Expected ErrMissingViewerID, got %v", err)
+	}
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: "missing", ViewerID: "viewer-1"}); err != ErrViewerLinkNotFound {
+		t.Errorf("Expected ErrViewerLinkNotFound, got %v", err)
+	}
+
+	listed, err := useCase.ListLinks(&dto.ListViewerLinksRequest{Token: "test-token", SenderKey: "sender-key"})
+	if err != nil || len(listed.Links) != 1 || listed.Links[0].Redemptions != 1 || listed.Links[0].Usable {
+		t.Errorf("Expected the link listed as used up, got %+v, %v", listed, err)
+	}
+
+	// The link expires on its own while the session goes on
+	session, _ = sessionRepo.GetSession("test-token")
+	session.FindLink(link.ID).ExpiresAt = time.Now().Add(-time.Second)
+	sessionRepo.SetSession(session)
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID, ViewerID: "viewer-1"}); err != ErrViewerLinkExpired {
+		t.Errorf("Expected ErrViewerLinkExpired, got %v", err)
+	}
+
+	// Links lead nowhere once their session ended
+	ended, err := useCase.CreateLink(&dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 300})
+	if err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+	session, _ = sessionRepo.GetSession("test-token")
+	session.End()
+	sessionRepo.SetSession(session)
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: ended.ID, ViewerID: "viewer-1"}); err != ErrViewerLinkNotFound {
+		t.Errorf("Expected ErrViewerLinkNotFound after the session ended, got %v", err)
+	}
+}
+
+func TestViewerLinkUseCase_RevokeLink(t *testing.T) {
+	sessionRepo := mocks.NewMockSessionRepository()
+	session := mocks.NewTestSession("test-token", false)
+	session.SenderKey = "sender-key"
+	sessionRepo.SetSession(session)
+	useCase := NewViewerLinkUseCase(sessionRepo, mocks.NewMockSessionHistoryRepository())
+
+	link, err := useCase.CreateLink(&dto.CreateViewerLinkRequest{Token: "test-token", SenderKey: "sender-key", TTLSeconds: 300})
+	if err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+
+	if err := useCase.RevokeLink(&dto.RevokeViewerLinkRequest{Token: "test-token", ID: link.ID, SenderKey: "guess"}); err != ErrInvalidSenderKey {
+		t.Errorf("Expected ErrInvalidSenderKey, got %v", err)
+	}
+	if err := useCase.RevokeLink(&dto.RevokeViewerLinkRequest{Token: "test-token", ID: link.ID, SenderKey: "sender-key"}); err != nil {
+		t.Fatalf("Expected the link revoked, got %v", err)
+	}
+	if err := useCase.RevokeLink(&dto.RevokeViewerLinkRequest{Token: "test-token", ID: link.ID, SenderKey: "sender-key"}); err != ErrViewerLinkNotFound {
+		t.Errorf("Expected ErrViewerLinkNotFound for a revoked link, got %v", err)
+	}
+	if _, err := useCase.RedeemLink(&dto.RedeemViewerLinkRequest{ID: link.ID, ViewerID: "viewer-1"}); err != ErrViewerLinkNotFound {
+		t.Errorf("Expected a revoked link to lead nowhere, got %v", err)
+	}
+}
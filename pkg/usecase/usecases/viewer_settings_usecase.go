@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/domain/interfaces"
+	"share-screen/pkg/usecase/dto"
+)
+
+// maxRequestedFrameRate is the highest frame rate a viewer may ask for
+const maxRequestedFrameRate = 60
+
+// ViewerSettingsUseCase implements the viewer settings use case interface
+type ViewerSettingsUseCase struct {
+	settingsRepo interfaces.DeviceSettingsRepository
+	sessionRepo  interfaces.SessionRepository
+	historyRepo  interfaces.SessionHistoryRepository
+	publisher    interfaces.EventPublisher
+}
+
+// NewViewerSettingsUseCase creates a new viewer settings use case
+func NewViewerSettingsUseCase(settingsRepo interfaces.DeviceSettingsRepository, sessionRepo interfaces.SessionRepository, historyRepo interfaces.SessionHistoryRepository, publisher interfaces.EventPublisher) *ViewerSettingsUseCase {
+	return &ViewerSettingsUseCase{
+		settingsRepo: settingsRepo,
+		sessionRepo:  sessionRepo,
+		historyRepo:  historyRepo,
+		publisher:    publisher,
+	}
+}
+
+// GetSettings returns the settings stored for a device, or the defaults when
+// the device has not saved any yet
+func (uc *ViewerSettingsUseCase) GetSettings(request *dto.GetViewerSettingsRequest) (*dto.ViewerSettingsResponse, error) {
+	if request.DeviceID == "" {
+		return nil, ErrInvalidDevice
+	}
+
+	settings, err := uc.settingsRepo.GetSettings(request.DeviceID)
+	if err != nil {
+		settings = &entities.DeviceSettings{DeviceID: request.DeviceID}
+	}
+
+	return toViewerSettings(settings), nil
+}
+
+// UpdateSettings stores the settings for a device
+func (uc *ViewerSettingsUseCase) UpdateSettings(request *dto.UpdateViewerSettingsRequest) (*dto.ViewerSettingsResponse, error) {
+	if request.DeviceID == "" {
+		return nil, ErrInvalidDevice
+	}
+
+	settings := &entities.DeviceSettings{
+		DeviceID:  request.DeviceID,
+		LowPower:  request.LowPower,
+		UpdatedAt: time.Now(),
+	}
+	if err := uc.settingsRepo.SaveSettings(settings); err != nil {
+		log.Printf("❌ Error saving viewer settings: %v", err)
+		return nil, err
+	}
+
+	log.Printf("🔋 Viewer settings updated (low power: %t)", settings.LowPower)
+	return toViewerSettings(settings), nil
+}
+
+// RequestQuality forwards a viewer's stream request to the sender of a live session
+func (uc *ViewerSettingsUseCase) RequestQuality(request *dto.QualityRequest) error {
+	if request.MaxFrameRate < 0 || request.MaxFrameRate > maxRequestedFrameRate {
+		return ErrInvalidQuality
+	}
+
+	session, err := getLiveSession(uc.sessionRepo, uc.historyRepo, request.Token)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🎚️  Viewer requested max %d fps for token: %s", request.MaxFrameRate, entities.ShortToken(session.Token))
+	uc.publisher.Publish(entities.SessionTopic(session.Token), entities.Event{
+		Type: entities.EventQualityRequest,
+		Data: map[string]int{"maxFrameRate": request.MaxFrameRate},
+	})
+
+	return nil
+}
+
+// toViewerSettings converts device settings to their response form
+func toViewerSettings(settings *entities.DeviceSettings) *dto.ViewerSettingsResponse {
+	return &dto.ViewerSettingsResponse{
+		LowPower:     settings.LowPower,
+		MaxFrameRate: settings.MaxFrameRate(),
+	}
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/test/mocks"
+)
+
+func TestViewerSettingsUseCase_Settings(t *testing.T) {
+	settingsRepo := mocks.NewMockDeviceSettingsRepository()
+	useCase := NewViewerSettingsUseCase(settingsRepo, mocks.NewMockSessionRepository(), mocks.NewMockSessionHistoryRepository(), mocks.NewMockEventPublisher())
+
+	settings, err := useCase.GetSettings(&dto.GetViewerSettingsRequest{DeviceID: "device-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if settings.LowPower || settings.MaxFrameRate != 0 {
+		t.Errorf("Expected default settings, got %+v", settings)
+	}
+
+	if _, err := useCase.UpdateSettings(&dto.UpdateViewerSettingsRequest{DeviceID: "device-1", LowPower: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	settings, err = useCase.GetSettings(&dto.GetViewerSettingsRequest{DeviceID: "device-1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !settings.LowPower || settings.MaxFrameRate != entities.LowPowerFrameRate {
+		t.Errorf("Expected low power settings, got %+v", settings)
+	}
+
+	if _, err := useCase.GetSettings(&dto.GetViewerSettingsRequest{}); err != ErrInvalidDevice {
+		t.Errorf("Expected ErrInvalidDevice, got %v", err)
+	}
+
+	settingsRepo.ShouldFailSaveSettings = true
+	if _, err := useCase.UpdateSettings(&dto.UpdateViewerSettingsRequest{DeviceID: "device-1"}); err == nil {
+		t.Error("Expected save error")
+	}
+}
+
+func TestViewerSettingsUseCase_RequestQuality(t *testing.T) {
+	tests := []struct {
+		name          string
+		session       *entities.Session
+		maxFrameRate  int
+		expectedError error
+	}{
+		{
+			name:         "low power request forwarded",
+			session:      mocks.NewTestSession("test-token", true),
+			maxFrameRate: entities.LowPowerFrameRate,
+		},
+		{
+			name:         "cap removed",
+			session:      mocks.NewTestSession("test-token", true),
+			maxFrameRate: 0,
+		},
+		{
+			name:          "frame rate out of range",
+			session:       mocks.NewTestSession("test-token", true),
+			maxFrameRate:  120,
+			expectedError: ErrInvalidQuality,
+		},
+		{
+			name:          "session not found",
+			maxFrameRate:  entities.LowPowerFrameRate,
+			expectedError: ErrSessionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := mocks.NewMockSessionRepository()
+			if tt.session != nil {
+				mockRepo.SetSession(tt.session)
+			}
+			publisher := mocks.NewMockEventPublisher()
+			useCase := NewViewerSettingsUseCase(mocks.NewMockDeviceSettingsRepository(), mockRepo, mocks.NewMockSessionHistoryRepository(), publisher)
+
+			err := useCase.RequestQuality(&dto.QualityRequest{Token: "test-token", MaxFrameRate: tt.maxFrameRate})
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+				if len(publisher.Published(entities.SessionTopic("test-token"))) != 0 {
+					t.Error("Expected no event to be published")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			events := publisher.Published(entities.SessionTopic("test-token"))
+			if len(events) != 1 || events[0].Type != entities.EventQualityRequest {
+				t.Fatalf("Expected one quality event, got %+v", events)
+			}
+			data := events[0].Data.(map[string]int)
+			if data["maxFrameRate"] != tt.maxFrameRate {
+				t.Errorf("Expected max frame rate %d, got %d", tt.maxFrameRate, data["maxFrameRate"])
+			}
+		})
+	}
+}
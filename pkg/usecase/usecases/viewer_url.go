@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// viewerURLs builds the viewer links handed out of band, on handouts and in
+// chat invites, where the sender's own host name may not work for viewers
+type viewerURLs struct {
+	networkService interfaces.NetworkService
+	publicHost     string
+	externalURL    *url.URL
+}
+
+// newViewerURLs creates a viewer link builder; publicHost is the domain the
+// server's certificate is for, if any, and externalURL the base URL viewers
+// outside the LAN use, if any
+func newViewerURLs(networkService interfaces.NetworkService, publicHost, externalURL string) viewerURLs {
+	urls := viewerURLs{networkService: networkService, publicHost: publicHost}
+	if externalURL != "" {
+		// validated with the configuration
+		urls.externalURL, _ = url.Parse(externalURL)
+	}
+	return urls
+}
+
+// base returns the URL the server is reached on for a sender that reached it
+// with scheme and host: the external URL, or the LAN host
+func (u viewerURLs) base(scheme, host string) *url.URL {
+	if u.externalURL != nil {
+		return u.externalURL.JoinPath("/")
+	}
+	return &url.URL{Scheme: scheme, Host: u.lanHost(host), Path: "/"}
+}
+
+// viewerURL returns the viewer page URL of a session
+func (u viewerURLs) viewerURL(token, scheme, host string) *url.URL {
+	viewerURL := u.base(scheme, host).JoinPath("viewer")
+	viewerURL.RawQuery = url.Values{"token": {token}}.Encode()
+	return viewerURL
+}
+
+// lanHost swaps the host name for the public host or the LAN IP, keeping
+// the port
+func (u viewerURLs) lanHost(host string) string {
+	target := u.publicHost
+	if target == "" {
+		target = u.networkService.GetLANIP()
+	}
+	if target == "" {
+		return host
+	}
+
+	if _, port, err := net.SplitHostPort(host); err == nil {
+		return net.JoinHostPort(target, port)
+	}
+	// an IPv6 LAN IP needs brackets in a URL even without a port
+	if strings.Contains(target, ":") {
+		return "[" + target + "]"
+	}
+	return target
+}
@@ -0,0 +1,167 @@
+// Package clientsim simulates the browser sender and viewer pages against the
+// public HTTP API. The simulated clients poll, heartbeat, queue, retry and
+// vanish the way the pages do, with fake SDP instead of real media, so tests
+// exercise the server with real client traffic rather than direct use case
+// calls.
+package clientsim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"share-screen/pkg/client"
+)
+
+// Cadence is how often a simulated client polls and heartbeats
+type Cadence struct {
+	// Poll is the offer and answer polling interval
+	Poll time.Duration
+
+	// Heartbeat is the sender heartbeat interval
+	Heartbeat time.Duration
+
+	// ICETimeout is how long a sender takes to notice that its viewer
+	// vanished and mark the connection failed
+	ICETimeout time.Duration
+}
+
+// BrowserCadence matches the browser pages
+var BrowserCadence = Cadence{
+	Poll:       time.Second,
+	Heartbeat:  10 * time.Second,
+	ICETimeout: 30 * time.Second,
+}
+
+// Scaled returns the cadence sped up by factor, keeping its proportions
+func (c Cadence) Scaled(factor int) Cadence {
+	if factor <= 1 {
+		return c
+	}
+	return Cadence{
+		Poll:       c.Poll / time.Duration(factor),
+		Heartbeat:  c.Heartbeat / time.Duration(factor),
+		ICETimeout: c.ICETimeout / time.Duration(factor),
+	}
+}
+
+// Config holds the settings shared by simulated clients
+type Config struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	Cadence    Cadence
+
+	// Network connects simulated peers so senders notice abrupt viewer
+	// disconnects; without one, a vanished viewer is never noticed
+	Network *Network
+
+	// Retries is how many times a request failing with a network error or a
+	// 5xx status is retried, one poll interval apart
+	Retries int
+}
+
+// apiClient builds the API client for the configuration
+func (c Config) apiClient() *client.Client {
+	return client.NewClient(c.BaseURL, c.HTTPClient)
+}
+
+// cadence returns the configured cadence, or the browser's when unset
+func (c Config) cadence() Cadence {
+	if c.Cadence.Poll <= 0 {
+		return BrowserCadence
+	}
+	return c.Cadence
+}
+
+// retry runs call, retrying transient failures as configured
+func (c Config) retry(ctx context.Context, call func() error) error {
+	var err error
+	for attempt := 0; attempt <= c.Retries; attempt++ {
+		if err = call(); err == nil || !transient(ctx, err) {
+			return err
+		}
+		if sleepErr := sleep(ctx, c.cadence().Poll); sleepErr != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// transient reports whether a failed request is worth retrying
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	status := client.StatusCode(err)
+	return status == 0 || status >= 500
+}
+
+// Network stands in for the media path between simulated peers. A viewer's
+// answer names its peer, and the sender treats the connection as failed once
+// that peer disconnects, as ICE consent checks do in a browser.
+type Network struct {
+	mu    sync.Mutex
+	peers map[string]bool
+	next  int
+}
+
+// NewNetwork creates an empty simulated network
+func NewNetwork() *Network {
+	return &Network{peers: make(map[string]bool)}
+}
+
+// join registers a connected peer and returns its ID
+func (n *Network) join() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.next++
+	id := fmt.Sprintf("peer-%d", n.next)
+	n.peers[id] = true
+	return id
+}
+
+// drop disconnects a peer
+func (n *Network) drop(id string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.peers, id)
+}
+
+// alive reports whether a peer is still connected
+func (n *Network) alive(id string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.peers[id]
+}
+
+// fakeSDP builds a minimal session description naming the peer that made it
+func fakeSDP(peerID string) string {
+	return "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=" + peerID + "\r\nt=0 0\r\n" +
+		"m=application 9 UDP/DTLS/SCTP webrtc-datachannel\r\na=mid:0\r\n"
+}
+
+// peerFromSDP extracts the peer named by fakeSDP
+func peerFromSDP(sdp string) string {
+	for _, line := range strings.Split(sdp, "\r\n") {
+		if name, ok := strings.CutPrefix(line, "s="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
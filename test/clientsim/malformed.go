@@ -0,0 +1,72 @@
+package clientsim
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Probe is a malformed request that a buggy page, a stale tab or a hostile
+// client might send; the server must reject it with a 4xx status
+type Probe struct {
+	Name   string
+	Method string
+	Path   string // relative to the server root
+	Body   string
+}
+
+// MalformedProbes returns the probes for a live session token; they leave the
+// session usable
+func MalformedProbes(token string) []Probe {
+	session := "/api/v1/sessions/" + url.PathEscape(token)
+	return []Probe{
+		{Name: "offer with invalid JSON", Method: "POST", Path: "/api/v1/offer", Body: `{"token":`},
+		{Name: "offer without SDP", Method: "POST", Path: "/api/v1/offer", Body: `{"token":"` + token + `","sdp":{"type":"offer"}}`},
+		{Name: "offer with malformed SDP", Method: "POST", Path: "/api/v1/offer", Body: `{"token":"` + token + `","sdp":{"type":"offer","sdp":"v=0\r\nm=video"}}`},
+		{Name: "offer for unknown token", Method: "POST", Path: "/api/v1/offer", Body: `{"token":"unknown","sdp":{"type":"offer","sdp":"v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\n"}}`},
+		{Name: "offer without token", Method: "GET", Path: "/api/v1/offer"},
+		{Name: "offer with wrong method", Method: "PUT", Path: "/api/v1/offer"},
+		{Name: "answer with invalid JSON", Method: "POST", Path: "/api/v1/answer", Body: `[]`},
+		{Name: "answer without SDP", Method: "POST", Path: "/api/v1/answer", Body: `{"token":"` + token + `"}`},
+		{Name: "answer with malformed SDP", Method: "POST", Path: "/api/v1/answer", Body: `{"token":"` + token + `","sdp":{"type":"answer","sdp":"not sdp"}}`},
+		{Name: "answer for unknown token", Method: "GET", Path: "/api/v1/answer?token=unknown"},
+		{Name: "heartbeat with invalid JSON", Method: "POST", Path: session + "/heartbeat", Body: `{"bytesSent":"lots"}`},
+		{Name: "heartbeat for unknown token", Method: "POST", Path: "/api/v1/sessions/unknown/heartbeat", Body: `{}`},
+		{Name: "events for unknown token", Method: "GET", Path: "/api/v1/sessions/unknown/events"},
+		{Name: "queue join with invalid JSON", Method: "POST", Path: session + "/queue", Body: `{"pin":`},
+		{Name: "leave for unknown queued viewer", Method: "POST", Path: session + "/queue/nobody/leave"},
+		{Name: "quality out of range", Method: "POST", Path: session + "/quality", Body: `{"maxFrameRate":-5}`},
+		{Name: "summary of live session", Method: "GET", Path: session + "/summary"},
+		{Name: "token with path characters", Method: "GET", Path: "/api/v1/offer?token=" + url.QueryEscape("../../etc/passwd")},
+		{Name: "legacy path with invalid JSON", Method: "POST", Path: "/api/offer", Body: `not json`},
+	}
+}
+
+// Send sends the probe and returns the response status
+func (p Probe) Send(ctx context.Context, httpClient *http.Client, baseURL string) (int, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	var body io.Reader
+	if p.Body != "" {
+		body = strings.NewReader(p.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, p.Method, strings.TrimRight(baseURL, "/")+p.Path, body)
+	if err != nil {
+		return 0, err
+	}
+	if p.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	return res.StatusCode, nil
+}
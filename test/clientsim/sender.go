@@ -0,0 +1,262 @@
+package clientsim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/usecase/dto"
+)
+
+// bytesPerHeartbeat is the traffic a simulated sender reports per heartbeat,
+// about 2 Mbit/s at the browser cadence
+const bytesPerHeartbeat = 2_500_000
+
+// Sender simulates the sender page: it creates a session, publishes an offer,
+// polls for the answer, heartbeats and renegotiates whenever its viewer
+// leaves or its connection fails
+type Sender struct {
+	config Config
+	client *client.Client
+
+	token     string
+	pin       string
+	senderKey string
+
+	mu        sync.Mutex
+	offers    int
+	answers   int
+	bytesSent int64
+	peer      context.CancelFunc // stops polling and watching for the current offer
+	peers     sync.WaitGroup
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// NewSender creates a simulated sender
+func NewSender(config Config) *Sender {
+	return &Sender{
+		config: config,
+		client: config.apiClient(),
+	}
+}
+
+// Start creates the session and shares until End or Disconnect is called or
+// ctx is done
+func (s *Sender) Start(ctx context.Context, request *dto.CreateSessionRequest) error {
+	if request == nil {
+		request = &dto.CreateSessionRequest{}
+	}
+
+	var session *dto.CreateSessionResponse
+	err := s.config.retry(ctx, func() error {
+		var err error
+		session, err = s.client.CreateSession(ctx, request)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	s.token = session.Token
+	s.pin = session.PIN
+	s.senderKey = session.SenderKey
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	// Subscribe before the first offer so no viewer event is missed
+	events, err := s.client.Events(ctx, s.token, "")
+	if err != nil {
+		s.cancel()
+		close(s.done)
+		return fmt.Errorf("subscribing to session events: %w", err)
+	}
+	if err := s.negotiate(ctx); err != nil {
+		s.cancel()
+		close(s.done)
+		return err
+	}
+
+	go s.run(ctx, events)
+	return nil
+}
+
+// Token returns the session token
+func (s *Sender) Token() string {
+	return s.token
+}
+
+// PIN returns the session PIN, empty for unprotected sessions
+func (s *Sender) PIN() string {
+	return s.pin
+}
+
+// Offers returns how many offers the sender has published
+func (s *Sender) Offers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offers
+}
+
+// Answers returns how many viewer answers the sender has applied
+func (s *Sender) Answers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.answers
+}
+
+// Err returns why the sender stopped on its own, such as a server shutdown
+func (s *Sender) Err() error {
+	<-s.done
+	return s.err
+}
+
+// End stops sharing the way the page's stop button does: a last heartbeat,
+// then ending the session
+func (s *Sender) End(ctx context.Context) error {
+	s.stop()
+	if err := s.heartbeat(ctx); err != nil && client.StatusCode(err) != 410 {
+		return err
+	}
+	return s.config.retry(ctx, func() error { return s.client.EndSession(ctx, s.token, s.senderKey) })
+}
+
+// Disconnect stops the sender without telling the server, as when the laptop
+// lid closes or the browser crashes
+func (s *Sender) Disconnect() {
+	s.stop()
+}
+
+// stop ends the sender's loops and waits for them
+func (s *Sender) stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+// run heartbeats and reacts to session events until ctx is done
+func (s *Sender) run(ctx context.Context, events <-chan entities.Event) {
+	defer close(s.done)
+	defer s.peers.Wait()
+	defer s.closePeer()
+
+	heartbeat := time.NewTicker(s.config.cadence().Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			// The page ignores failed heartbeats and tries again next time
+			_ = s.heartbeat(ctx)
+		case event, ok := <-events:
+			if !ok {
+				if ctx.Err() == nil {
+					s.err = errors.New("event stream closed by the server")
+				}
+				return
+			}
+			switch event.Type {
+			case entities.EventViewerLeft:
+				if err := s.negotiate(ctx); err != nil && ctx.Err() == nil {
+					s.err = err
+					return
+				}
+			case entities.EventServerShutdown:
+				s.err = errors.New("server is restarting")
+				return
+			}
+		}
+	}
+}
+
+// heartbeat reports the traffic sent so far
+func (s *Sender) heartbeat(ctx context.Context) error {
+	s.mu.Lock()
+	s.bytesSent += bytesPerHeartbeat
+	bytesSent := s.bytesSent
+	s.mu.Unlock()
+
+	return s.client.Heartbeat(ctx, s.token, bytesSent)
+}
+
+// negotiate publishes a fresh offer for the next viewer and polls for its answer
+func (s *Sender) negotiate(ctx context.Context) error {
+	s.closePeer()
+
+	offer := &entities.WebRTCOffer{Type: "offer", SDP: fakeSDP("sender")}
+	if err := s.config.retry(ctx, func() error { return s.client.SubmitOffer(ctx, s.token, s.senderKey, offer) }); err != nil {
+		return fmt.Errorf("publishing offer: %w", err)
+	}
+
+	peerCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.offers++
+	s.peer = cancel
+	s.mu.Unlock()
+
+	s.peers.Add(1)
+	go func() {
+		defer s.peers.Done()
+		s.connect(peerCtx)
+	}()
+	return nil
+}
+
+// connect polls for the viewer's answer, then watches the viewer the way ICE
+// would and frees the slot when it vanishes, as the page does on a failed
+// connection
+func (s *Sender) connect(ctx context.Context) {
+	var answer *entities.WebRTCAnswer
+	for answer == nil {
+		var err error
+		answer, err = s.client.GetAnswer(ctx, s.token)
+		switch {
+		case err == nil:
+		case client.StatusCode(err) == 410:
+			return
+		default:
+			if sleep(ctx, s.config.cadence().Poll) != nil {
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.answers++
+	s.mu.Unlock()
+
+	if s.config.Network == nil {
+		return
+	}
+	peerID := peerFromSDP(answer.SDP)
+	for s.config.Network.alive(peerID) {
+		if sleep(ctx, s.config.cadence().Poll) != nil {
+			return
+		}
+	}
+	if sleep(ctx, s.config.cadence().ICETimeout) != nil {
+		return
+	}
+	// The page fires and forgets this request
+	_ = s.client.ReleaseViewer(ctx, s.token)
+}
+
+// closePeer stops polling and watching for the current offer
+func (s *Sender) closePeer() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.peer != nil {
+		s.peer()
+		s.peer = nil
+	}
+}
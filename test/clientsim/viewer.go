@@ -0,0 +1,199 @@
+package clientsim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/domain/entities"
+)
+
+// ErrWrongPIN is returned when a protected session rejects the viewer's PIN
+var ErrWrongPIN = errors.New("wrong or missing PIN")
+
+// Viewer simulates the viewer page: it polls for the offer, waits in the
+// queue while someone else is watching, answers and later leaves or vanishes
+type Viewer struct {
+	config Config
+	client *client.Client
+	token  string
+	pin    string
+
+	mu        sync.Mutex
+	viewerID  string
+	peerID    string
+	positions []int
+	connected bool
+}
+
+// NewViewer creates a simulated viewer for a session; pin is empty for
+// unprotected sessions
+func NewViewer(config Config, token, pin string) *Viewer {
+	return &Viewer{
+		config: config,
+		client: config.apiClient(),
+		token:  token,
+		pin:    pin,
+	}
+}
+
+// Watch joins the session, queueing if needed, and returns once the viewer's
+// answer is posted
+func (v *Viewer) Watch(ctx context.Context) error {
+	if _, err := v.fetchOffer(ctx); err != nil {
+		return err
+	}
+
+	peerID := "viewer"
+	if v.config.Network != nil {
+		peerID = v.config.Network.join()
+	}
+	answer := &entities.WebRTCAnswer{Type: "answer", SDP: fakeSDP(peerID)}
+	err := v.config.retry(ctx, func() error {
+		return v.client.SubmitAnswer(ctx, v.token, v.ViewerID(), v.pin, answer)
+	})
+	if err != nil {
+		if v.config.Network != nil {
+			v.config.Network.drop(peerID)
+		}
+		return fmt.Errorf("posting answer: %w", err)
+	}
+
+	v.mu.Lock()
+	v.peerID = peerID
+	v.connected = true
+	v.mu.Unlock()
+	return nil
+}
+
+// ViewerID returns the ID the queue assigned, empty if the viewer never queued
+func (v *Viewer) ViewerID() string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.viewerID
+}
+
+// Positions returns every queue position the viewer was told about
+func (v *Viewer) Positions() []int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]int(nil), v.positions...)
+}
+
+// Leave frees the slot or queue place the way the page does when closed
+func (v *Viewer) Leave(ctx context.Context) error {
+	v.mu.Lock()
+	connected, viewerID := v.connected, v.viewerID
+	v.connected = false
+	v.mu.Unlock()
+
+	v.disconnect()
+	switch {
+	case connected:
+		return v.client.ReleaseViewer(ctx, v.token)
+	case viewerID != "":
+		return v.client.LeaveQueue(ctx, v.token, viewerID)
+	}
+	return nil
+}
+
+// Disconnect drops the viewer without telling the server, as when the phone
+// loses Wi-Fi; the sender notices once its connection fails
+func (v *Viewer) Disconnect() {
+	v.mu.Lock()
+	v.connected = false
+	v.mu.Unlock()
+
+	v.disconnect()
+}
+
+// disconnect drops the viewer's peer from the network
+func (v *Viewer) disconnect() {
+	v.mu.Lock()
+	peerID := v.peerID
+	v.peerID = ""
+	v.mu.Unlock()
+
+	if peerID != "" && v.config.Network != nil {
+		v.config.Network.drop(peerID)
+	}
+}
+
+// fetchOffer polls for the sender's offer, waiting in line while the session
+// is taken
+func (v *Viewer) fetchOffer(ctx context.Context) (*entities.WebRTCOffer, error) {
+	failures := 0
+	for {
+		offer, err := v.client.GetOffer(ctx, v.token, v.ViewerID(), v.pin)
+		switch status := client.StatusCode(err); {
+		case err == nil:
+			return offer, nil
+		case status == 404:
+			// The sender has not published its offer yet
+		case status == 409:
+			if err := v.waitInQueue(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		case status == 403:
+			return nil, ErrWrongPIN
+		case transient(ctx, err) && failures < v.config.Retries:
+			failures++
+		default:
+			return nil, err
+		}
+		if err := sleep(ctx, v.config.cadence().Poll); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitInQueue joins the queue and returns once the viewer is admitted
+func (v *Viewer) waitInQueue(ctx context.Context) error {
+	joined, err := v.client.JoinQueue(ctx, v.token, v.pin)
+	if err != nil {
+		if client.StatusCode(err) == 403 {
+			return ErrWrongPIN
+		}
+		return fmt.Errorf("joining queue: %w", err)
+	}
+
+	v.mu.Lock()
+	v.viewerID = joined.ViewerID
+	v.mu.Unlock()
+	if joined.Position == 0 {
+		return nil
+	}
+
+	eventsCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := v.client.Events(eventsCtx, v.token, joined.ViewerID)
+	if err != nil {
+		return fmt.Errorf("subscribing to queue events: %w", err)
+	}
+
+	for event := range events {
+		switch event.Type {
+		case entities.EventQueuePosition:
+			var position struct {
+				Position int `json:"position"`
+			}
+			if raw, ok := event.Data.(json.RawMessage); ok && json.Unmarshal(raw, &position) == nil {
+				v.mu.Lock()
+				v.positions = append(v.positions, position.Position)
+				v.mu.Unlock()
+			}
+		case entities.EventViewerAdmitted:
+			return nil
+		case entities.EventServerShutdown:
+			return errors.New("server is restarting")
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("event stream closed by the server")
+}
@@ -0,0 +1,311 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"share-screen/pkg/client"
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/events"
+	"share-screen/pkg/infrastructure/repository"
+	httphandlers "share-screen/pkg/presentation/http"
+	"share-screen/pkg/usecase/dto"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/clientsim"
+	"share-screen/test/mocks"
+)
+
+// fastCadence runs the browser cadence fifty times faster
+var fastCadence = clientsim.BrowserCadence.Scaled(50)
+
+// newAPIServer serves the public API with real dependencies, routed as in main
+func newAPIServer(t *testing.T) clientsim.Config {
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	broker := events.NewBroker(events.DefaultBufferSize, events.PolicyDropOldest).(*events.Broker)
+
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, broker, nil, 30*time.Minute, usecases.SessionDefaults{})
+	serverInfoUseCase := usecases.NewServerInfoUseCase(mocks.NewMockNetworkService(), nil, "", "test-version", "", "", nil)
+	queueUseCase := usecases.NewViewerQueueUseCase(sessionRepo, historyRepo, broker)
+	settingsUseCase := usecases.NewViewerSettingsUseCase(repository.NewMemoryDeviceSettingsRepository(), sessionRepo, historyRepo, broker)
+
+	api := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase, nil)
+	queue := httphandlers.NewQueueHandlers(queueUseCase)
+	history := httphandlers.NewHistoryHandlers(usecases.NewSessionHistoryUseCase(historyRepo))
+	settings := httphandlers.NewSettingsHandlers(settingsUseCase)
+	eventHandlers := httphandlers.NewEventHandlers(broker, queueUseCase)
+	ice := httphandlers.NewICEHandlers(usecases.NewICEConfigUseCase(sessionRepo, historyRepo, nil, nil))
+
+	mux := http.NewServeMux()
+	router := httphandlers.NewRouter(mux)
+	router.API("/new", api.HandleNewToken)
+	router.API("/offer", api.HandleOffer)
+	router.API("/answer", api.HandleAnswer)
+	router.API("/info", api.HandleInfo)
+	router.API("/sessions/{token}/heartbeat", api.HandleHeartbeat)
+	router.API("/sessions/{token}/end", api.HandleEndSession)
+	router.API("/sessions/{token}/events", eventHandlers.HandleEvents)
+	router.API("/sessions/{token}/ice-config", ice.HandleICEConfig)
+	router.API("/sessions/{token}/queue", queue.HandleQueue)
+	router.API("/sessions/{token}/queue/{viewer}/leave", queue.HandleLeaveQueue)
+	router.API("/sessions/{token}/queue/{viewer}/promote", queue.HandlePromoteViewer)
+	router.API("/sessions/{token}/leave", queue.HandleReleaseViewer)
+	router.API("/sessions/{token}/summary", history.HandleSummary)
+	router.API("/sessions/{token}/notes", history.HandleNotes)
+	router.API("/sessions/{token}/quality", settings.HandleQualityRequest)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		broker.Close()
+		server.Close()
+	})
+	return clientsim.Config{
+		BaseURL: server.URL,
+		Cadence: fastCadence,
+		Network: clientsim.NewNetwork(),
+		Retries: 2,
+	}
+}
+
+// eventually polls condition until it holds or five seconds pass
+func eventually(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// startSender starts a simulated sender that is disconnected when the test ends
+func startSender(t *testing.T, ctx context.Context, config clientsim.Config, request *dto.CreateSessionRequest) *clientsim.Sender {
+	sender := clientsim.NewSender(config)
+	if err := sender.Start(ctx, request); err != nil {
+		t.Fatalf("Sender failed to start: %v", err)
+	}
+	t.Cleanup(sender.Disconnect)
+	return sender
+}
+
+// TestClientSim_ViewersTakeTurns tests a sender renegotiating for successive viewers
+func TestClientSim_ViewersTakeTurns(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, &dto.CreateSessionRequest{Name: "Standup", ReusableLink: true})
+
+	for i := 1; i <= 3; i++ {
+		viewer := clientsim.NewViewer(config, sender.Token(), "")
+		if err := viewer.Watch(ctx); err != nil {
+			t.Fatalf("Viewer %d failed to watch: %v", i, err)
+		}
+		eventually(t, "the sender to apply the answer", func() bool { return sender.Answers() == i })
+
+		if err := viewer.Leave(ctx); err != nil {
+			t.Fatalf("Viewer %d failed to leave: %v", i, err)
+		}
+		eventually(t, "the sender to renegotiate", func() bool { return sender.Offers() == i+1 })
+	}
+
+	if err := sender.End(ctx); err != nil {
+		t.Fatalf("Sender failed to end: %v", err)
+	}
+	summary, err := client.NewClient(config.BaseURL, nil).GetSummary(ctx, sender.Token())
+	if err != nil {
+		t.Fatalf("Expected a summary once ended, got %v", err)
+	}
+	if summary.Name != "Standup" {
+		t.Errorf("Expected session name Standup, got %q", summary.Name)
+	}
+}
+
+// TestClientSim_QueueAfterAbruptDisconnect tests that a viewer who vanishes
+// without leaving is replaced by the next one in line once the sender notices
+func TestClientSim_QueueAfterAbruptDisconnect(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, &dto.CreateSessionRequest{ReusableLink: true})
+
+	first := clientsim.NewViewer(config, sender.Token(), "")
+	if err := first.Watch(ctx); err != nil {
+		t.Fatalf("First viewer failed to watch: %v", err)
+	}
+
+	second := clientsim.NewViewer(config, sender.Token(), "")
+	third := clientsim.NewViewer(config, sender.Token(), "")
+	watching := make(chan error, 2)
+	go func() { watching <- second.Watch(ctx) }()
+	eventually(t, "the second viewer to queue", func() bool { return len(second.Positions()) > 0 })
+	go func() { watching <- third.Watch(ctx) }()
+	eventually(t, "the third viewer to queue", func() bool { return len(third.Positions()) > 0 })
+
+	// The third viewer gives up before its turn
+	if err := third.Leave(ctx); err != nil {
+		t.Fatalf("Third viewer failed to leave the queue: %v", err)
+	}
+
+	first.Disconnect()
+	if err := <-watching; err != nil {
+		t.Fatalf("Second viewer failed to watch: %v", err)
+	}
+	if positions := second.Positions(); positions[0] != 1 {
+		t.Errorf("Expected the second viewer to be first in line, got %v", positions)
+	}
+	eventually(t, "the sender to apply the second answer", func() bool { return sender.Answers() == 2 })
+
+	cancel()
+	if err := <-watching; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the third viewer to stop waiting, got %v", err)
+	}
+}
+
+// TestClientSim_SingleUseLink tests that a viewer link stops working for other
+// devices once the first viewer has answered
+func TestClientSim_SingleUseLink(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, nil)
+
+	first := clientsim.NewViewer(config, sender.Token(), "")
+	if err := first.Watch(ctx); err != nil {
+		t.Fatalf("First viewer failed to watch: %v", err)
+	}
+
+	err := clientsim.NewViewer(config, sender.Token(), "").Watch(ctx)
+	if status := client.StatusCode(err); status != 410 {
+		t.Errorf("Expected 410 for a second device while the first watches, got %v", err)
+	}
+
+	// The slot frees up, but the link stays used
+	if err := first.Leave(ctx); err != nil {
+		t.Fatalf("First viewer failed to leave: %v", err)
+	}
+	eventually(t, "the sender to renegotiate", func() bool { return sender.Offers() == 2 })
+	err = clientsim.NewViewer(config, sender.Token(), "").Watch(ctx)
+	if status := client.StatusCode(err); status != 410 {
+		t.Errorf("Expected 410 for a later device, got %v", err)
+	}
+}
+
+// TestClientSim_ProtectedSession tests viewers with and without the PIN
+func TestClientSim_ProtectedSession(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, &dto.CreateSessionRequest{RequirePIN: true})
+
+	if err := clientsim.NewViewer(config, sender.Token(), "").Watch(ctx); !errors.Is(err, clientsim.ErrWrongPIN) {
+		t.Errorf("Expected ErrWrongPIN without a PIN, got %v", err)
+	}
+	if err := clientsim.NewViewer(config, sender.Token(), sender.PIN()).Watch(ctx); err != nil {
+		t.Errorf("Expected the PIN to be accepted, got %v", err)
+	}
+}
+
+// TestClientSim_SenderEnds tests viewers arriving after the sender ended the session
+func TestClientSim_SenderEnds(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, nil)
+	if err := sender.End(ctx); err != nil {
+		t.Fatalf("Sender failed to end: %v", err)
+	}
+
+	err := clientsim.NewViewer(config, sender.Token(), "").Watch(ctx)
+	if status := client.StatusCode(err); status != 410 {
+		t.Errorf("Expected 410 for an ended session, got %v", err)
+	}
+}
+
+// TestClientSim_MalformedRequests tests that malformed requests are rejected
+// without breaking the session they target
+func TestClientSim_MalformedRequests(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	sender := startSender(t, ctx, config, nil)
+
+	for _, probe := range clientsim.MalformedProbes(sender.Token()) {
+		t.Run(probe.Name, func(t *testing.T) {
+			status, err := probe.Send(ctx, nil, config.BaseURL)
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			if status < 400 || status > 499 {
+				t.Errorf("Expected a 4xx status, got %d", status)
+			}
+		})
+	}
+
+	if err := clientsim.NewViewer(config, sender.Token(), "").Watch(ctx); err != nil {
+		t.Fatalf("Session unusable after malformed requests: %v", err)
+	}
+	eventually(t, "the sender to apply the answer", func() bool { return sender.Answers() == 1 })
+}
+
+// TestClientSim_ConcurrentSessions tests many senders and viewers at once
+func TestClientSim_ConcurrentSessions(t *testing.T) {
+	config := newAPIServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const sessions = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, sessions)
+	for i := 0; i < sessions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sender := clientsim.NewSender(config)
+			if err := sender.Start(ctx, &dto.CreateSessionRequest{Name: fmt.Sprintf("Room %d", i), ReusableLink: true}); err != nil {
+				errs <- err
+				return
+			}
+			defer sender.Disconnect()
+
+			// A second viewer arrives while the first is watching and waits in line
+			first := clientsim.NewViewer(config, sender.Token(), "")
+			if err := first.Watch(ctx); err != nil {
+				errs <- fmt.Errorf("session %d: %w", i, err)
+				return
+			}
+			second := clientsim.NewViewer(config, sender.Token(), "")
+			watching := make(chan error, 1)
+			go func() { watching <- second.Watch(ctx) }()
+
+			if err := first.Leave(ctx); err != nil {
+				errs <- fmt.Errorf("session %d: %w", i, err)
+				return
+			}
+			if err := <-watching; err != nil {
+				errs <- fmt.Errorf("session %d: %w", i, err)
+				return
+			}
+			if err := second.Leave(ctx); err != nil {
+				errs <- fmt.Errorf("session %d: %w", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
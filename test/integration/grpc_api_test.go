@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"share-screen/pkg/domain/entities"
+	"share-screen/pkg/infrastructure/repository"
+	grpcserver "share-screen/pkg/presentation/grpc"
+	"share-screen/pkg/presentation/grpc/signalingpb"
+	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
+)
+
+// TestGRPCAPIIntegration tests the complete handshake over the gRPC API
+func TestGRPCAPIIntegration(t *testing.T) {
+	// Setup real dependencies
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, usecases.SessionDefaults{})
+
+	ln := bufconn.Listen(1 << 20)
+	server := grpcserver.NewServer(grpcserver.NewSignalingServer(sessionUseCase), grpcserver.NewAccess(nil, nil, entities.TokenPolicy{}, nil))
+	go server.Serve(ln)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	client := signalingpb.NewSignalingClient(conn)
+	ctx := context.Background()
+
+	// Step 1: The sender app creates a session
+	session, err := client.CreateSession(ctx, &signalingpb.CreateSessionRequest{Name: "Desktop app"})
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	token := session.GetToken()
+	if token == "" || session.GetSenderKey() == "" {
+		t.Fatalf("Expected a token and sender key, got %+v", session)
+	}
+
+	// Step 2: The viewer polls before the offer is there
+	_, err = client.GetOffer(ctx, &signalingpb.GetOfferRequest{Token: token})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound before the offer, got %v", err)
+	}
+
+	// Step 3: The sender submits its offer and the viewer gets it
+	_, err = client.SubmitOffer(ctx, &signalingpb.SubmitOfferRequest{
+		Token: token,
+		Offer: &signalingpb.SessionDescription{Type: "offer", Sdp: "v=0\r\no=- 1 1 IN IP4 192.168.1.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit offer: %v", err)
+	}
+	offer, err := client.GetOffer(ctx, &signalingpb.GetOfferRequest{Token: token})
+	if err != nil {
+		t.Fatalf("Failed to get offer: %v", err)
+	}
+	if offer.GetOffer().GetType() != "offer" {
+		t.Errorf("Expected the sender's offer, got %+v", offer.GetOffer())
+	}
+
+	// Step 4: The viewer answers and the sender gets the answer
+	_, err = client.SubmitAnswer(ctx, &signalingpb.SubmitAnswerRequest{
+		Token:  token,
+		Answer: &signalingpb.SessionDescription{Type: "answer", Sdp: "v=0\r\no=- 2 2 IN IP4 192.168.1.2\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to submit answer: %v", err)
+	}
+	answer, err := client.GetAnswer(ctx, &signalingpb.GetAnswerRequest{Token: token})
+	if err != nil {
+		t.Fatalf("Failed to get answer: %v", err)
+	}
+	if answer.GetAnswer().GetType() != "answer" {
+		t.Errorf("Expected the viewer's answer, got %+v", answer.GetAnswer())
+	}
+
+	// Step 5: The sender keeps the session alive, then ends it
+	if _, err := client.Heartbeat(ctx, &signalingpb.HeartbeatRequest{Token: token, BytesSent: 1024}); err != nil {
+		t.Errorf("Failed to send heartbeat: %v", err)
+	}
+	_, err = client.EndSession(ctx, &signalingpb.EndSessionRequest{Token: token})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a viewer's token alone, got %v", err)
+	}
+	if _, err := client.EndSession(ctx, &signalingpb.EndSessionRequest{Token: token, SenderKey: session.GetSenderKey()}); err != nil {
+		t.Fatalf("Failed to end session: %v", err)
+	}
+	_, err = client.Heartbeat(ctx, &signalingpb.HeartbeatRequest{Token: token})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("Expected FailedPrecondition after the session ended, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package integration
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -13,18 +14,20 @@ import (
 	httphandlers "share-screen/pkg/presentation/http"
 	"share-screen/pkg/usecase/dto"
 	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
 )
 
 // TestHTTPAPIIntegration tests the complete HTTP API flow
 func TestHTTPAPIIntegration(t *testing.T) {
 	// Setup real dependencies
-	sessionRepo := repository.NewMemorySessionRepository()
-	networkService := network.NewNetworkService()
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	networkService := network.NewNetworkService("")
 
-	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, 30*time.Minute)
-	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, "stun:test.com:19302", "1.0.0")
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, usecases.SessionDefaults{})
+	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, nil, "stun:test.com:19302", "1.0.0", "", "", nil)
 
-	apiHandlers := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase)
+	apiHandlers := httphandlers.NewAPIHandlers(sessionUseCase, serverInfoUseCase, nil)
 
 	t.Run("complete HTTP API workflow", func(t *testing.T) {
 		// Step 1: Create a new session
@@ -54,7 +57,7 @@ func TestHTTPAPIIntegration(t *testing.T) {
 			Token: token,
 			Offer: &entities.WebRTCOffer{
 				Type: "offer",
-				SDP:  "v=0\no=- 123456789 123456789 IN IP4 192.168.1.1\ns=-\nt=0 0\n",
+				SDP:  "v=0\r\no=- 123456789 123456789 IN IP4 192.168.1.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
 			},
 		}
 
@@ -98,7 +101,7 @@ func TestHTTPAPIIntegration(t *testing.T) {
 			Token: token,
 			Answer: &entities.WebRTCAnswer{
 				Type: "answer",
-				SDP:  "v=0\no=- 987654321 987654321 IN IP4 192.168.1.2\ns=-\nt=0 0\n",
+				SDP:  "v=0\r\no=- 987654321 987654321 IN IP4 192.168.1.2\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
 			},
 		}
 
@@ -186,7 +189,7 @@ func TestHTTPAPIIntegration(t *testing.T) {
 		}
 
 		// Test method not allowed
-		req = httptest.NewRequest("DELETE", "/api/offer", nil)
+		req = httptest.NewRequest("PUT", "/api/offer", nil)
 		w = httptest.NewRecorder()
 
 		apiHandlers.HandleOffer(w, req)
@@ -245,7 +248,7 @@ func TestHTTPAPIIntegration(t *testing.T) {
 				Token: token,
 				Offer: &entities.WebRTCOffer{
 					Type: "offer",
-					SDP:  "test-sdp-" + string(rune(i)),
+					SDP:  testSDP(fmt.Sprintf("test-sdp-%d", i)),
 				},
 			}
 
@@ -282,7 +285,7 @@ func TestHTTPAPIIntegration(t *testing.T) {
 				t.Fatalf("Failed to unmarshal offer for session %d: %v", i, err)
 			}
 
-			expectedSDP := "test-sdp-" + string(rune(i))
+			expectedSDP := testSDP(fmt.Sprintf("test-sdp-%d", i))
 			if offer.SDP != expectedSDP {
 				t.Errorf("Expected SDP %q for session %d but got %q", expectedSDP, i, offer.SDP)
 			}
@@ -9,20 +9,27 @@ import (
 	"share-screen/pkg/infrastructure/repository"
 	"share-screen/pkg/usecase/dto"
 	"share-screen/pkg/usecase/usecases"
+	"share-screen/test/mocks"
 )
 
+// testSDP builds a minimal valid session description named after label
+func testSDP(label string) string {
+	return "v=0\r\no=- 0 0 IN IP4 127.0.0.1\r\ns=" + label + "\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n"
+}
+
 // TestSessionFlow tests the complete session flow from creation to completion
 func TestSessionFlow(t *testing.T) {
 	// Setup real dependencies (not mocks)
-	sessionRepo := repository.NewMemorySessionRepository()
-	networkService := network.NewNetworkService()
+	sessionRepo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
+	historyRepo := repository.NewMemorySessionHistoryRepository()
+	networkService := network.NewNetworkService("")
 
-	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, 30*time.Minute)
-	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, "stun:test.com:19302", "test-version")
+	sessionUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 30*time.Minute, usecases.SessionDefaults{})
+	serverInfoUseCase := usecases.NewServerInfoUseCase(networkService, nil, "stun:test.com:19302", "test-version", "", "", nil)
 
 	t.Run("complete session workflow", func(t *testing.T) {
 		// Step 1: Create a new session
-		createResponse, err := sessionUseCase.CreateSession()
+		createResponse, err := sessionUseCase.CreateSession(&dto.CreateSessionRequest{})
 		if err != nil {
 			t.Fatalf("Failed to create session: %v", err)
 		}
@@ -35,7 +42,7 @@ func TestSessionFlow(t *testing.T) {
 		// Step 2: Submit an offer
 		offer := &entities.WebRTCOffer{
 			Type: "offer",
-			SDP:  "v=0\no=- 123456789 123456789 IN IP4 192.168.1.1\ns=-\nt=0 0\n",
+			SDP:  "v=0\r\no=- 123456789 123456789 IN IP4 192.168.1.1\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
 		}
 
 		submitOfferRequest := &dto.SubmitOfferRequest{
@@ -70,7 +77,7 @@ func TestSessionFlow(t *testing.T) {
 		// Step 4: Submit an answer
 		answer := &entities.WebRTCAnswer{
 			Type: "answer",
-			SDP:  "v=0\no=- 987654321 987654321 IN IP4 192.168.1.2\ns=-\nt=0 0\n",
+			SDP:  "v=0\r\no=- 987654321 987654321 IN IP4 192.168.1.2\r\ns=-\r\nt=0 0\r\nm=video 9 UDP/TLS/RTP/SAVPF 96\r\na=mid:0\r\n",
 		}
 
 		submitAnswerRequest := &dto.SubmitAnswerRequest{
@@ -123,9 +130,9 @@ func TestSessionFlow(t *testing.T) {
 
 	t.Run("session expiry workflow", func(t *testing.T) {
 		// Create a session with very short expiry
-		shortExpiryUseCase := usecases.NewSessionUseCase(sessionRepo, 1*time.Millisecond)
+		shortExpiryUseCase := usecases.NewSessionUseCase(sessionRepo, historyRepo, repository.NewMemoryAuditLogRepository(), nil, mocks.NewMockEventPublisher(), nil, 1*time.Millisecond, usecases.SessionDefaults{})
 
-		createResponse, err := shortExpiryUseCase.CreateSession()
+		createResponse, err := shortExpiryUseCase.CreateSession(&dto.CreateSessionRequest{})
 		if err != nil {
 			t.Fatalf("Failed to create session: %v", err)
 		}
@@ -138,7 +145,7 @@ func TestSessionFlow(t *testing.T) {
 		// Try to submit offer to expired session
 		offer := &entities.WebRTCOffer{
 			Type: "offer",
-			SDP:  "test-sdp",
+			SDP:  testSDP("test-sdp"),
 		}
 
 		submitOfferRequest := &dto.SubmitOfferRequest{
@@ -156,6 +163,48 @@ func TestSessionFlow(t *testing.T) {
 		}
 	})
 
+	t.Run("session summary workflow", func(t *testing.T) {
+		historyUseCase := usecases.NewSessionHistoryUseCase(historyRepo)
+
+		createResponse, err := sessionUseCase.CreateSession(&dto.CreateSessionRequest{Name: "Standup"})
+		if err != nil {
+			t.Fatalf("Failed to create session: %v", err)
+		}
+		token := createResponse.Token
+
+		// No summary while the session is still running
+		if _, err := historyUseCase.GetSessionSummary(&dto.GetSessionSummaryRequest{Token: token}); err != usecases.ErrSessionNotFound {
+			t.Errorf("Expected ErrSessionNotFound before end but got %v", err)
+		}
+
+		if err := sessionUseCase.Heartbeat(&dto.HeartbeatRequest{Token: token, BytesSent: 125000}); err != nil {
+			t.Fatalf("Failed to record heartbeat: %v", err)
+		}
+		if err := sessionUseCase.EndSession(&dto.EndSessionRequest{Token: token, SenderKey: createResponse.SenderKey}); err != nil {
+			t.Fatalf("Failed to end session: %v", err)
+		}
+
+		summary, err := historyUseCase.GetSessionSummary(&dto.GetSessionSummaryRequest{Token: token})
+		if err != nil {
+			t.Fatalf("Failed to get summary: %v", err)
+		}
+		if summary.Name != "Standup" {
+			t.Errorf("Expected name %q but got %q", "Standup", summary.Name)
+		}
+
+		if _, err := historyUseCase.UpdateSessionNotes(&dto.UpdateSessionNotesRequest{Token: token, Notes: "Sprint review", SenderKey: createResponse.SenderKey}); err != nil {
+			t.Fatalf("Failed to update notes: %v", err)
+		}
+
+		summary, err = historyUseCase.GetSessionSummary(&dto.GetSessionSummaryRequest{Token: token})
+		if err != nil {
+			t.Fatalf("Failed to get summary: %v", err)
+		}
+		if summary.Notes != "Sprint review" {
+			t.Errorf("Expected notes to be stored but got %q", summary.Notes)
+		}
+	})
+
 	t.Run("invalid operations workflow", func(t *testing.T) {
 		// Test submitting invalid offer
 		invalidOfferRequest := &dto.SubmitOfferRequest{
@@ -180,7 +229,7 @@ func TestSessionFlow(t *testing.T) {
 			Token: "valid-token",
 			Answer: &entities.WebRTCAnswer{
 				Type: "", // Invalid answer
-				SDP:  "test-sdp",
+				SDP:  testSDP("test-sdp"),
 			},
 		}
 
@@ -193,7 +242,7 @@ func TestSessionFlow(t *testing.T) {
 
 // TestRepositoryCleanup tests the repository cleanup functionality
 func TestRepositoryCleanup(t *testing.T) {
-	repo := repository.NewMemorySessionRepository()
+	repo := repository.NewMemorySessionRepository(entities.TokenPolicy{})
 
 	// Create multiple sessions with different expiry times
 	now := time.Now()
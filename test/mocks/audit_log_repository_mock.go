@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockAuditLogRepository is a mock implementation of AuditLogRepository interface
+type MockAuditLogRepository struct {
+	mu     sync.Mutex
+	events map[string][]entities.AuditEvent
+
+	// For controlling behavior in tests
+	ShouldFailAppendEvent bool
+}
+
+// NewMockAuditLogRepository creates a new mock audit log repository
+func NewMockAuditLogRepository() *MockAuditLogRepository {
+	return &MockAuditLogRepository{
+		events: make(map[string][]entities.AuditEvent),
+	}
+}
+
+// AppendEvent adds an event to the log of its session
+func (m *MockAuditLogRepository) AppendEvent(event *entities.AuditEvent) error {
+	if m.ShouldFailAppendEvent {
+		return mockError("failed to append audit event")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.events[event.Token] = append(m.events[event.Token], *event)
+	return nil
+}
+
+// ListEvents returns the log of a session, oldest first
+func (m *MockAuditLogRepository) ListEvents(token string) ([]entities.AuditEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log, exists := m.events[token]
+	if !exists {
+		return nil, mockError("audit log not found")
+	}
+	return append([]entities.AuditEvent(nil), log...), nil
+}
+
+// Types returns the types of a session's events in order, for assertions
+func (m *MockAuditLogRepository) Types(token string) []entities.AuditEventType {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var types []entities.AuditEventType
+	for _, event := range m.events[token] {
+		types = append(types, event.Type)
+	}
+	return types
+}
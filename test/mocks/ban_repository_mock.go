@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"slices"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockBanRepository is a mock implementation of BanRepository interface
+type MockBanRepository struct {
+	mu   sync.Mutex
+	bans []entities.Ban
+
+	// For controlling behavior in tests
+	ShouldFailSaveBan bool
+}
+
+// NewMockBanRepository creates a new mock ban repository
+func NewMockBanRepository() *MockBanRepository {
+	return &MockBanRepository{}
+}
+
+// ListBans returns every ban, oldest first
+func (m *MockBanRepository) ListBans() ([]*entities.Ban, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bans := make([]*entities.Ban, 0, len(m.bans))
+	for _, ban := range m.bans {
+		banCopy := ban
+		bans = append(bans, &banCopy)
+	}
+	return bans, nil
+}
+
+// SaveBan stores a ban, replacing any ban of the same network
+func (m *MockBanRepository) SaveBan(ban *entities.Ban) error {
+	if m.ShouldFailSaveBan {
+		return mockError("failed to save ban")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := slices.IndexFunc(m.bans, func(b entities.Ban) bool { return b.Network == ban.Network }); i >= 0 {
+		m.bans[i] = *ban
+		return nil
+	}
+	m.bans = append(m.bans, *ban)
+	return nil
+}
+
+// DeleteBan removes the ban of a network
+func (m *MockBanRepository) DeleteBan(network string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.bans, func(b entities.Ban) bool { return b.Network == network })
+	if i < 0 {
+		return mockError("ban not found")
+	}
+	m.bans = slices.Delete(m.bans, i, i+1)
+	return nil
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// MockDeviceSettingsRepository is a mock implementation of DeviceSettingsRepository interface
+type MockDeviceSettingsRepository struct {
+	settings map[string]*entities.DeviceSettings
+
+	// For controlling behavior in tests
+	ShouldFailSaveSettings bool
+}
+
+// NewMockDeviceSettingsRepository creates a new mock device settings repository
+func NewMockDeviceSettingsRepository() *MockDeviceSettingsRepository {
+	return &MockDeviceSettingsRepository{
+		settings: make(map[string]*entities.DeviceSettings),
+	}
+}
+
+// GetSettings retrieves the settings for a device
+func (m *MockDeviceSettingsRepository) GetSettings(deviceID string) (*entities.DeviceSettings, error) {
+	settings, exists := m.settings[deviceID]
+	if !exists {
+		return nil, mockError("settings not found")
+	}
+
+	settingsCopy := *settings
+	return &settingsCopy, nil
+}
+
+// SaveSettings stores or replaces the settings for a device
+func (m *MockDeviceSettingsRepository) SaveSettings(settings *entities.DeviceSettings) error {
+	if m.ShouldFailSaveSettings {
+		return mockError("failed to save settings")
+	}
+
+	settingsCopy := *settings
+	m.settings[settings.DeviceID] = &settingsCopy
+	return nil
+}
+
+// ListSettings returns copies of the settings of every device
+func (m *MockDeviceSettingsRepository) ListSettings() ([]*entities.DeviceSettings, error) {
+	settings := make([]*entities.DeviceSettings, 0, len(m.settings))
+	for _, stored := range m.settings {
+		settingsCopy := *stored
+		settings = append(settings, &settingsCopy)
+	}
+	return settings, nil
+}
@@ -0,0 +1,45 @@
+package mocks
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// PublishedEvent is an event recorded by MockEventPublisher
+type PublishedEvent struct {
+	Topic string
+	Event entities.Event
+}
+
+// MockEventPublisher is a mock implementation of EventPublisher interface
+type MockEventPublisher struct {
+	mu     sync.Mutex
+	events []PublishedEvent
+}
+
+// NewMockEventPublisher creates a new mock event publisher
+func NewMockEventPublisher() *MockEventPublisher {
+	return &MockEventPublisher{}
+}
+
+// Publish records the event
+func (m *MockEventPublisher) Publish(topic string, event entities.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, PublishedEvent{Topic: topic, Event: event})
+}
+
+// Published returns the events published to a topic (helper method for testing)
+func (m *MockEventPublisher) Published(topic string) []entities.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Event
+	for _, e := range m.events {
+		if e.Topic == topic {
+			result = append(result, e.Event)
+		}
+	}
+	return result
+}
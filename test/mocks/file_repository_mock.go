@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// mockFile is a file stored by MockFileRepository
+type mockFile struct {
+	file entities.SharedFile
+	data []byte
+}
+
+// MockFileRepository is a mock implementation of FileRepository interface
+type MockFileRepository struct {
+	files map[string][]mockFile
+
+	// For controlling behavior in tests
+	ShouldFailSaveFile bool
+}
+
+// NewMockFileRepository creates a new mock file repository
+func NewMockFileRepository() *MockFileRepository {
+	return &MockFileRepository{
+		files: make(map[string][]mockFile),
+	}
+}
+
+// SaveFile stores a file and its content for the session with token
+func (m *MockFileRepository) SaveFile(token string, file *entities.SharedFile, data []byte) error {
+	if m.ShouldFailSaveFile {
+		return mockError("failed to save file")
+	}
+	m.files[token] = append(m.files[token], mockFile{file: *file, data: data})
+	return nil
+}
+
+// GetFile retrieves a file of a session and its content
+func (m *MockFileRepository) GetFile(token, id string) (*entities.SharedFile, []byte, error) {
+	for _, stored := range m.files[token] {
+		if stored.file.ID == id {
+			fileCopy := stored.file
+			return &fileCopy, stored.data, nil
+		}
+	}
+	return nil, nil, mockError("file not found")
+}
+
+// ListFiles returns the files of a session, oldest first
+func (m *MockFileRepository) ListFiles(token string) ([]*entities.SharedFile, error) {
+	files := make([]*entities.SharedFile, 0, len(m.files[token]))
+	for _, stored := range m.files[token] {
+		fileCopy := stored.file
+		files = append(files, &fileCopy)
+	}
+	return files, nil
+}
+
+// DeleteFiles removes every file of a session
+func (m *MockFileRepository) DeleteFiles(token string) error {
+	delete(m.files, token)
+	return nil
+}
+
+// GetTokens returns the tokens of the sessions holding files
+func (m *MockFileRepository) GetTokens() ([]string, error) {
+	tokens := make([]string, 0, len(m.files))
+	for token := range m.files {
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"sync"
+
+	"share-screen/pkg/control"
+)
+
+// MockInputInjector is a mock implementation of InputInjector interface that
+// records the events it is given
+type MockInputInjector struct {
+	mu     sync.Mutex
+	events []control.Event
+
+	// For controlling behavior in tests
+	ShouldFail bool
+}
+
+// NewMockInputInjector creates a new mock input injector
+func NewMockInputInjector() *MockInputInjector {
+	return &MockInputInjector{}
+}
+
+// Inject records the event
+func (m *MockInputInjector) Inject(event control.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ShouldFail {
+		return mockError("failed to inject input")
+	}
+	m.events = append(m.events, event)
+	return nil
+}
+
+// Events returns every event injected (helper method for testing)
+func (m *MockInputInjector) Events() []control.Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]control.Event(nil), m.events...)
+}
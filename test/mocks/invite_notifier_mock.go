@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// MockInviteNotifier is a mock implementation of InviteNotifier interface
+type MockInviteNotifier struct {
+	// For controlling behavior in tests
+	ShouldFail bool
+
+	// Name is the chat service reported by Service
+	Name string
+
+	// Invites records the posted invites, oldest first
+	Invites []*entities.Invite
+}
+
+// NewMockInviteNotifier creates a new mock invite notifier for the named
+// chat service
+func NewMockInviteNotifier(name string) *MockInviteNotifier {
+	return &MockInviteNotifier{Name: name}
+}
+
+// Service names the chat service
+func (m *MockInviteNotifier) Service() string {
+	return m.Name
+}
+
+// PostInvite records the invite
+func (m *MockInviteNotifier) PostInvite(invite *entities.Invite) error {
+	if m.ShouldFail {
+		return mockError("failed to post invite")
+	}
+	m.Invites = append(m.Invites, invite)
+	return nil
+}
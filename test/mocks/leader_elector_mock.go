@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"sync"
+)
+
+// MockLeaderElector is a mock implementation of LeaderElector interface
+type MockLeaderElector struct {
+	mu      sync.Mutex
+	leading bool
+	checks  int
+}
+
+// NewMockLeaderElector creates a new mock elector that leads or not
+func NewMockLeaderElector(leading bool) *MockLeaderElector {
+	return &MockLeaderElector{leading: leading}
+}
+
+// IsLeader reports whether this instance leads
+func (m *MockLeaderElector) IsLeader() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.checks++
+	return m.leading
+}
+
+// Close gives up the lead
+func (m *MockLeaderElector) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leading = false
+	return nil
+}
+
+// SetLeading changes whether this instance leads (for testing purposes)
+func (m *MockLeaderElector) SetLeading(leading bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.leading = leading
+}
+
+// Checks returns how often leadership was checked (for testing purposes)
+func (m *MockLeaderElector) Checks() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.checks
+}
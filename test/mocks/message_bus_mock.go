@@ -0,0 +1,123 @@
+package mocks
+
+import (
+	"strings"
+	"sync"
+)
+
+// BusMessage is a message recorded by MockMessageBus
+type BusMessage struct {
+	Topic    string
+	Payload  []byte
+	Retained bool
+}
+
+// MockMessageBus is a mock implementation of MessageBus interface
+type MockMessageBus struct {
+	mu            sync.Mutex
+	published     []BusMessage
+	subscriptions map[string]func(topic string, payload []byte)
+	closed        bool
+
+	// PublishError is returned by Publish when set
+	PublishError error
+}
+
+// NewMockMessageBus creates a new mock message bus
+func NewMockMessageBus() *MockMessageBus {
+	return &MockMessageBus{subscriptions: make(map[string]func(topic string, payload []byte))}
+}
+
+// Publish records the message
+func (m *MockMessageBus) Publish(topic string, payload []byte, retained bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.PublishError != nil {
+		return m.PublishError
+	}
+	m.published = append(m.published, BusMessage{Topic: topic, Payload: payload, Retained: retained})
+	return nil
+}
+
+// Subscribe records the handler of the filter
+func (m *MockMessageBus) Subscribe(filter string, handler func(topic string, payload []byte)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscriptions[filter] = handler
+	return nil
+}
+
+// Close marks the bus closed
+func (m *MockMessageBus) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	return nil
+}
+
+// Deliver hands a message to the handlers whose filter matches topic, as a
+// broker would (helper method for testing)
+func (m *MockMessageBus) Deliver(topic string, payload []byte) {
+	m.mu.Lock()
+	var handlers []func(topic string, payload []byte)
+	for filter, handler := range m.subscriptions {
+		if topicMatches(filter, topic) {
+			handlers = append(handlers, handler)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(topic, payload)
+	}
+}
+
+// Subscribed reports whether a handler is subscribed to filter (helper method for testing)
+func (m *MockMessageBus) Subscribed(filter string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.subscriptions[filter]
+	return ok
+}
+
+// Published returns the messages published to a topic (helper method for testing)
+func (m *MockMessageBus) Published(topic string) []BusMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []BusMessage
+	for _, message := range m.published {
+		if message.Topic == topic {
+			result = append(result, message)
+		}
+	}
+	return result
+}
+
+// Closed reports whether the bus was closed (helper method for testing)
+func (m *MockMessageBus) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.closed
+}
+
+// topicMatches reports whether an MQTT topic filter with + and # wildcards
+// matches topic
+func topicMatches(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) || (level != "+" && level != topicLevels[i]) {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
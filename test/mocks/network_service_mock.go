@@ -1,8 +1,15 @@
 package mocks
 
+import (
+	"share-screen/pkg/domain/entities"
+)
+
 // MockNetworkService is a mock implementation of NetworkService interface
 type MockNetworkService struct {
-	LANIPToReturn string
+	LANIPToReturn      string
+	LANIPsToReturn     []string
+	InterfacesToReturn []entities.NetworkInterface
+	Interface          string
 }
 
 // NewMockNetworkService creates a new mock network service
@@ -21,3 +28,25 @@ func (m *MockNetworkService) GetLANIP() string {
 func (m *MockNetworkService) SetLANIP(ip string) {
 	m.LANIPToReturn = ip
 }
+
+// GetLANIPs returns the configured mock LAN IPs, or the single LAN IP when
+// none are set
+func (m *MockNetworkService) GetLANIPs() []string {
+	if m.LANIPsToReturn != nil {
+		return m.LANIPsToReturn
+	}
+	if m.LANIPToReturn == "" {
+		return nil
+	}
+	return []string{m.LANIPToReturn}
+}
+
+// GetInterfaces returns the configured mock interfaces
+func (m *MockNetworkService) GetInterfaces() []entities.NetworkInterface {
+	return m.InterfacesToReturn
+}
+
+// InterfaceName returns the configured mock interface name
+func (m *MockNetworkService) InterfaceName() string {
+	return m.Interface
+}
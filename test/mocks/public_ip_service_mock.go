@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+)
+
+// MockPublicIPService is a mock implementation of PublicIPService interface
+type MockPublicIPService struct {
+	IP           string
+	RefreshError error
+}
+
+// NewMockPublicIPService creates a new mock public IP service
+func NewMockPublicIPService(ip string) *MockPublicIPService {
+	return &MockPublicIPService{IP: ip}
+}
+
+// PublicIP returns the configured mock public IP
+func (m *MockPublicIPService) PublicIP() string {
+	return m.IP
+}
+
+// Refresh returns the configured error
+func (m *MockPublicIPService) Refresh(ctx context.Context) error {
+	return m.RefreshError
+}
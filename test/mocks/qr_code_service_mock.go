@@ -0,0 +1,33 @@
+package mocks
+
+// MockQRCodeService is a mock implementation of QRCodeService interface
+type MockQRCodeService struct {
+	// For controlling behavior in tests
+	ShouldFail bool
+
+	// LastContent records the most recently encoded content
+	LastContent string
+}
+
+// NewMockQRCodeService creates a new mock QR code service
+func NewMockQRCodeService() *MockQRCodeService {
+	return &MockQRCodeService{}
+}
+
+// PNG returns placeholder image bytes for the content
+func (m *MockQRCodeService) PNG(content string, size int) ([]byte, error) {
+	m.LastContent = content
+	if m.ShouldFail {
+		return nil, mockError("failed to encode QR code")
+	}
+	return []byte("png:" + content), nil
+}
+
+// Text returns placeholder text for the content
+func (m *MockQRCodeService) Text(content string) (string, error) {
+	m.LastContent = content
+	if m.ShouldFail {
+		return "", mockError("failed to encode QR code")
+	}
+	return "qr:" + content, nil
+}
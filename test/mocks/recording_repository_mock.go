@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockRecordingRepository is a mock implementation of RecordingRepository
+// interface
+type MockRecordingRepository struct {
+	mu         sync.Mutex
+	recordings []entities.Recording
+	contents   map[string]string
+}
+
+// NewMockRecordingRepository creates a new mock recording repository
+func NewMockRecordingRepository() *MockRecordingRepository {
+	return &MockRecordingRepository{contents: make(map[string]string)}
+}
+
+// AddRecording stores a recording with content; recordings are expected in
+// the order they started
+func (m *MockRecordingRepository) AddRecording(recording *entities.Recording, content string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recordings = append(m.recordings, *recording)
+	m.contents[recording.Name] = content
+}
+
+// ListRecordings returns every recording, oldest first
+func (m *MockRecordingRepository) ListRecordings() ([]*entities.Recording, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	recordings := make([]*entities.Recording, 0, len(m.recordings))
+	for _, recording := range m.recordings {
+		recordingCopy := recording
+		recordings = append(recordings, &recordingCopy)
+	}
+	return recordings, nil
+}
+
+// OpenRecording returns a recording and its content
+func (m *MockRecordingRepository) OpenRecording(name string) (*entities.Recording, io.ReadSeekCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.recordings, func(r entities.Recording) bool { return r.Name == name })
+	if i < 0 {
+		return nil, nil, mockError("recording not found")
+	}
+	recording := m.recordings[i]
+	return &recording, nopSeekCloser{strings.NewReader(m.contents[name])}, nil
+}
+
+// DeleteRecording removes a recording
+func (m *MockRecordingRepository) DeleteRecording(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := slices.IndexFunc(m.recordings, func(r entities.Recording) bool { return r.Name == name })
+	if i < 0 {
+		return mockError("recording not found")
+	}
+	m.recordings = slices.Delete(m.recordings, i, i+1)
+	delete(m.contents, name)
+	return nil
+}
+
+// nopSeekCloser adds a Close that does nothing to a reader
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
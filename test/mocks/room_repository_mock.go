@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// MockRoomRepository is a mock implementation of RoomRepository interface
+type MockRoomRepository struct {
+	rooms map[string]*entities.Room
+
+	// For controlling behavior in tests
+	ShouldFailSaveRoom bool
+}
+
+// NewMockRoomRepository creates a new mock room repository
+func NewMockRoomRepository() *MockRoomRepository {
+	return &MockRoomRepository{
+		rooms: make(map[string]*entities.Room),
+	}
+}
+
+// GetRoom retrieves a room by its normalized name
+func (m *MockRoomRepository) GetRoom(name string) (*entities.Room, error) {
+	room, exists := m.rooms[name]
+	if !exists {
+		return nil, mockError("room not found")
+	}
+
+	roomCopy := *room
+	return &roomCopy, nil
+}
+
+// SaveRoom stores or replaces a room
+func (m *MockRoomRepository) SaveRoom(room *entities.Room) error {
+	if m.ShouldFailSaveRoom {
+		return mockError("failed to save room")
+	}
+
+	roomCopy := *room
+	m.rooms[room.Name] = &roomCopy
+	return nil
+}
@@ -0,0 +1,68 @@
+package mocks
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// MockScreenCapturer is a mock implementation of ScreenCapturer interface that
+// produces placeholder frames at the requested rate
+type MockScreenCapturer struct {
+	mu         sync.Mutex
+	frameRates []int
+
+	// For controlling behavior in tests
+	ShouldFail bool
+}
+
+// NewMockScreenCapturer creates a new mock screen capturer
+func NewMockScreenCapturer() *MockScreenCapturer {
+	return &MockScreenCapturer{}
+}
+
+// Start records the frame rate and returns a stream of placeholder frames
+func (m *MockScreenCapturer) Start(ctx context.Context, frameRate int) (interfaces.VideoStream, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ShouldFail {
+		return nil, mockError("failed to start capture")
+	}
+	m.frameRates = append(m.frameRates, frameRate)
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &mockVideoStream{ctx: ctx, cancel: cancel, interval: time.Second / time.Duration(frameRate)}, nil
+}
+
+// FrameRates returns the frame rate of every capture started (helper method for testing)
+func (m *MockScreenCapturer) FrameRates() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int(nil), m.frameRates...)
+}
+
+// mockVideoStream emits a placeholder frame every interval until closed
+type mockVideoStream struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	interval time.Duration
+}
+
+// NextFrame returns a placeholder frame after one frame interval
+func (s *mockVideoStream) NextFrame() ([]byte, error) {
+	select {
+	case <-s.ctx.Done():
+		return nil, io.EOF
+	case <-time.After(s.interval):
+		return []byte{0x10, 0x02, 0x00, 0x9d, 0x01, 0x2a}, nil
+	}
+}
+
+// Close stops the stream
+func (s *mockVideoStream) Close() error {
+	s.cancel()
+	return nil
+}
@@ -0,0 +1,58 @@
+package mocks
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockSenderProfileRepository is a mock implementation of
+// SenderProfileRepository interface
+type MockSenderProfileRepository struct {
+	mu       sync.Mutex
+	profiles map[string]entities.SenderProfile
+
+	// For controlling behavior in tests
+	ShouldFailSaveProfile bool
+}
+
+// NewMockSenderProfileRepository creates a new mock sender profile repository
+func NewMockSenderProfileRepository() *MockSenderProfileRepository {
+	return &MockSenderProfileRepository{profiles: make(map[string]entities.SenderProfile)}
+}
+
+// GetProfile returns the profile with the given ID
+func (m *MockSenderProfileRepository) GetProfile(id string) (*entities.SenderProfile, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	profile, exists := m.profiles[id]
+	if !exists {
+		return nil, mockError("sender profile not found")
+	}
+	return &profile, nil
+}
+
+// SaveProfile stores a profile, replacing any profile with the same ID
+func (m *MockSenderProfileRepository) SaveProfile(profile *entities.SenderProfile) error {
+	if m.ShouldFailSaveProfile {
+		return mockError("failed to save sender profile")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.profiles[profile.ID] = *profile
+	return nil
+}
+
+// DeleteProfile removes the profile with the given ID
+func (m *MockSenderProfileRepository) DeleteProfile(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.profiles[id]; !exists {
+		return mockError("sender profile not found")
+	}
+	delete(m.profiles, id)
+	return nil
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"share-screen/pkg/domain/entities"
+)
+
+// MockSessionHistoryRepository is a mock implementation of SessionHistoryRepository interface
+type MockSessionHistoryRepository struct {
+	records map[string]*entities.SessionRecord
+
+	// For controlling behavior in tests
+	ShouldFailSaveRecord bool
+}
+
+// NewMockSessionHistoryRepository creates a new mock session history repository
+func NewMockSessionHistoryRepository() *MockSessionHistoryRepository {
+	return &MockSessionHistoryRepository{
+		records: make(map[string]*entities.SessionRecord),
+	}
+}
+
+// SaveRecord stores or replaces the record for a session
+func (m *MockSessionHistoryRepository) SaveRecord(record *entities.SessionRecord) error {
+	if m.ShouldFailSaveRecord {
+		return mockError("failed to save record")
+	}
+
+	recordCopy := *record
+	m.records[record.Token] = &recordCopy
+	return nil
+}
+
+// GetRecord retrieves the record for a session by token
+func (m *MockSessionHistoryRepository) GetRecord(token string) (*entities.SessionRecord, error) {
+	record, exists := m.records[token]
+	if !exists {
+		return nil, mockError("record not found")
+	}
+
+	recordCopy := *record
+	return &recordCopy, nil
+}
+
+// ListRecords returns copies of all stored records
+func (m *MockSessionHistoryRepository) ListRecords() ([]*entities.SessionRecord, error) {
+	records := make([]*entities.SessionRecord, 0, len(m.records))
+	for _, record := range m.records {
+		recordCopy := *record
+		records = append(records, &recordCopy)
+	}
+	return records, nil
+}
+
+// GetRecordCount returns the number of stored records (for testing purposes)
+func (m *MockSessionHistoryRepository) GetRecordCount() int {
+	return len(m.records)
+}
@@ -54,17 +54,7 @@ func (m *MockSessionRepository) GetSession(token string) (*entities.Session, err
 	}
 
 	// Return a copy to prevent external modifications
-	sessionCopy := *session
-	if session.Offer != nil {
-		offerCopy := *session.Offer
-		sessionCopy.Offer = &offerCopy
-	}
-	if session.Answer != nil {
-		answerCopy := *session.Answer
-		sessionCopy.Answer = &answerCopy
-	}
-
-	return &sessionCopy, nil
+	return session.Clone(), nil
 }
 
 // UpdateSession updates an existing session
@@ -79,17 +69,7 @@ func (m *MockSessionRepository) UpdateSession(session *entities.Session) error {
 	}
 
 	// Create a copy to store
-	sessionCopy := *session
-	if session.Offer != nil {
-		offerCopy := *session.Offer
-		sessionCopy.Offer = &offerCopy
-	}
-	if session.Answer != nil {
-		answerCopy := *session.Answer
-		sessionCopy.Answer = &answerCopy
-	}
-
-	m.sessions[session.Token] = &sessionCopy
+	m.sessions[session.Token] = session.Clone()
 	return nil
 }
 
@@ -126,11 +106,53 @@ func (m *MockSessionRepository) GetActiveSessionsCount() (int, error) {
 	return count, nil
 }
 
+// ListSessions returns copies of all stored sessions
+func (m *MockSessionRepository) ListSessions() ([]*entities.Session, error) {
+	sessions := make([]*entities.Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session.Clone())
+	}
+	return sessions, nil
+}
+
+// RestoreSession stores a session under its own token, replacing any session
+// with that token
+func (m *MockSessionRepository) RestoreSession(session *entities.Session) error {
+	if m.ShouldFailUpdateSession {
+		return mockError("failed to restore session")
+	}
+
+	m.sessions[session.Token] = session.Clone()
+	return nil
+}
+
 // SetSession directly sets a session (for testing purposes)
 func (m *MockSessionRepository) SetSession(session *entities.Session) {
 	m.sessions[session.Token] = session
 }
 
+// NewTestSession returns a pending session under token, created now and
+// expiring in 30 minutes, for tests to adjust and store with SetSession. A
+// full session has a viewer connected, and queued viewers wait in line.
+func NewTestSession(token string, full bool, queued ...string) *entities.Session {
+	now := time.Now()
+	session := &entities.Session{
+		Token:     token,
+		CreatedAt: now,
+		ExpiresAt: now.Add(30 * time.Minute),
+		Status:    entities.SessionStatusPending,
+	}
+	if full {
+		session.Offer = &entities.WebRTCOffer{Type: "offer", SDP: "sdp"}
+		session.Answer = &entities.WebRTCAnswer{Type: "answer", SDP: "sdp"}
+		session.Status = entities.SessionStatusActive
+	}
+	for _, id := range queued {
+		session.Queue = append(session.Queue, entities.QueuedViewer{ID: id, JoinedAt: now})
+	}
+	return session
+}
+
 // GetSessionCount returns the total number of sessions (for testing purposes)
 func (m *MockSessionRepository) GetSessionCount() int {
 	return len(m.sessions)
@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockSessionTemplateRepository is a mock implementation of
+// SessionTemplateRepository interface
+type MockSessionTemplateRepository struct {
+	mu        sync.Mutex
+	templates []entities.SessionTemplate
+
+	// For controlling behavior in tests
+	ShouldFailSaveTemplate bool
+}
+
+// NewMockSessionTemplateRepository creates a new mock session template repository
+func NewMockSessionTemplateRepository() *MockSessionTemplateRepository {
+	return &MockSessionTemplateRepository{}
+}
+
+// ListTemplates returns every template, by name
+func (m *MockSessionTemplateRepository) ListTemplates() ([]*entities.SessionTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	templates := make([]*entities.SessionTemplate, 0, len(m.templates))
+	for _, template := range m.templates {
+		templateCopy := template
+		templates = append(templates, &templateCopy)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns the template with the given name
+func (m *MockSessionTemplateRepository) GetTemplate(name string) (*entities.SessionTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.index(name)
+	if i < 0 {
+		return nil, mockError("session template not found")
+	}
+	template := m.templates[i]
+	return &template, nil
+}
+
+// SaveTemplate stores a template, replacing any template of the same name
+func (m *MockSessionTemplateRepository) SaveTemplate(template *entities.SessionTemplate) error {
+	if m.ShouldFailSaveTemplate {
+		return mockError("failed to save session template")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.index(template.Name); i >= 0 {
+		m.templates[i] = *template
+		return nil
+	}
+	m.templates = append(m.templates, *template)
+	slices.SortFunc(m.templates, func(a, b entities.SessionTemplate) int { return strings.Compare(a.Name, b.Name) })
+	return nil
+}
+
+// DeleteTemplate removes the template with the given name
+func (m *MockSessionTemplateRepository) DeleteTemplate(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.index(name)
+	if i < 0 {
+		return mockError("session template not found")
+	}
+	m.templates = slices.Delete(m.templates, i, i+1)
+	return nil
+}
+
+// index returns the position of the named template, or -1
+func (m *MockSessionTemplateRepository) index(name string) int {
+	return slices.IndexFunc(m.templates, func(t entities.SessionTemplate) bool { return t.Name == name })
+}
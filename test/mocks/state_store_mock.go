@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"fmt"
+	"os"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockStateStore is a mock implementation of StateStore interface
+type MockStateStore struct {
+	snapshot *entities.Snapshot
+
+	// For controlling behavior in tests
+	ShouldFailSave bool
+	Sessions       bool
+}
+
+// NewMockStateStore creates a new mock state store holding nothing yet
+func NewMockStateStore() *MockStateStore {
+	return &MockStateStore{}
+}
+
+// Load returns the stored snapshot, or an error wrapping os.ErrNotExist
+func (m *MockStateStore) Load() (*entities.Snapshot, error) {
+	if m.snapshot == nil {
+		return nil, fmt.Errorf("mock store: %w", os.ErrNotExist)
+	}
+	return m.snapshot, nil
+}
+
+// Save replaces the stored snapshot
+func (m *MockStateStore) Save(snapshot *entities.Snapshot) error {
+	if m.ShouldFailSave {
+		return mockError("failed to save snapshot")
+	}
+
+	m.snapshot = snapshot
+	return nil
+}
+
+// Location describes the store
+func (m *MockStateStore) Location() string {
+	return "mock"
+}
+
+// SessionsOnly reports whether the mock stands in for a session backend
+func (m *MockStateStore) SessionsOnly() bool {
+	return m.Sessions
+}
+
+// Close does nothing
+func (m *MockStateStore) Close() error {
+	return nil
+}
+
+// SetSnapshot directly sets the stored snapshot (for testing purposes)
+func (m *MockStateStore) SetSnapshot(snapshot *entities.Snapshot) {
+	m.snapshot = snapshot
+}
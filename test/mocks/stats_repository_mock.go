@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockStatsRepository is a mock implementation of StatsRepository interface
+type MockStatsRepository struct {
+	stats   map[string][]entities.StatsSample
+	latency map[string][]entities.LatencySample
+
+	// For controlling behavior in tests
+	ShouldFailAppendStats bool
+}
+
+// NewMockStatsRepository creates a new mock stats repository
+func NewMockStatsRepository() *MockStatsRepository {
+	return &MockStatsRepository{
+		stats:   make(map[string][]entities.StatsSample),
+		latency: make(map[string][]entities.LatencySample),
+	}
+}
+
+// AppendStats adds a sample to a session's series
+func (m *MockStatsRepository) AppendStats(token string, sample *entities.StatsSample) error {
+	if m.ShouldFailAppendStats {
+		return mockError("failed to append stats")
+	}
+	m.stats[token] = append(m.stats[token], *sample)
+	return nil
+}
+
+// ListStats returns a session's samples taken after since, oldest first
+func (m *MockStatsRepository) ListStats(token string, since time.Time) ([]entities.StatsSample, error) {
+	samples := []entities.StatsSample{}
+	for _, sample := range m.stats[token] {
+		if sample.At.After(since) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples, nil
+}
+
+// SetLatency records a latency result of a session, replacing the earlier
+// one of the same peer and path
+func (m *MockStatsRepository) SetLatency(token string, sample *entities.LatencySample) error {
+	results := m.latency[token]
+	for i, result := range results {
+		if result.Source() == sample.Source() && result.Path == sample.Path {
+			results[i] = *sample
+			return nil
+		}
+	}
+	m.latency[token] = append(results, *sample)
+	return nil
+}
+
+// ListLatency returns the latency results of a session in the order they
+// were first recorded
+func (m *MockStatsRepository) ListLatency(token string) ([]entities.LatencySample, error) {
+	return append([]entities.LatencySample{}, m.latency[token]...), nil
+}
+
+// DeleteStats removes the series and latency results of a session
+func (m *MockStatsRepository) DeleteStats(token string) error {
+	delete(m.stats, token)
+	delete(m.latency, token)
+	return nil
+}
+
+// GetTokens returns the tokens of the sessions holding stats or latency
+// results
+func (m *MockStatsRepository) GetTokens() ([]string, error) {
+	tokens := make([]string, 0, len(m.stats))
+	for token := range m.stats {
+		tokens = append(tokens, token)
+	}
+	for token := range m.latency {
+		if _, ok := m.stats[token]; !ok {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,174 @@
+package mocks
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockStreamRelay is a mock implementation of StreamRelay interface
+type MockStreamRelay struct {
+	mu sync.Mutex
+
+	// ShouldFailPublish makes Publish reject every offer
+	ShouldFailPublish bool
+
+	// published holds the tokens of sessions whose video reached the relay,
+	// viewers the viewers offered a connection per token
+	published map[string]bool
+	viewers   map[string]map[string]bool
+	onViewers func(token string, viewers int)
+
+	// Closed records the tokens passed to Close
+	Closed []string
+
+	// Layers records the layer last selected per token and viewer
+	Layers map[string]entities.SimulcastLayer
+
+	// Frame is the snapshot WatchFrames sends each watcher and Snapshot
+	// returns
+	Frame []byte
+}
+
+// NewMockStreamRelay creates a new mock stream relay
+func NewMockStreamRelay() *MockStreamRelay {
+	return &MockStreamRelay{
+		published: make(map[string]bool),
+		viewers:   make(map[string]map[string]bool),
+		Layers:    make(map[string]entities.SimulcastLayer),
+	}
+}
+
+// Publish answers the sender's offer and marks its video as arriving
+func (m *MockStreamRelay) Publish(token string, offer *entities.WebRTCOffer) (*entities.WebRTCAnswer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ShouldFailPublish {
+		return nil, errors.New("mock publish failed")
+	}
+	m.published[token] = true
+	return &entities.WebRTCAnswer{Type: "answer", SDP: "relay-answer-sdp"}, nil
+}
+
+// IsPublished reports whether a session published its stream
+func (m *MockStreamRelay) IsPublished(token string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.published[token]
+}
+
+// Offer returns a predictable offer for a viewer of a published session
+func (m *MockStreamRelay) Offer(token, viewerID string) (*entities.WebRTCOffer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.published[token] {
+		return nil, errors.New("mock stream not published")
+	}
+	if m.viewers[token] == nil {
+		m.viewers[token] = make(map[string]bool)
+	}
+	m.viewers[token][viewerID] = true
+	return &entities.WebRTCOffer{Type: "offer", SDP: "relay-offer-" + viewerID}, nil
+}
+
+// Answer accepts the answer of a viewer that was offered a connection
+func (m *MockStreamRelay) Answer(token, viewerID string, answer *entities.WebRTCAnswer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.viewers[token][viewerID] {
+		return errors.New("mock unknown viewer")
+	}
+	return nil
+}
+
+// SelectLayer records the layer picked by a viewer that was offered a connection
+func (m *MockStreamRelay) SelectLayer(token, viewerID string, layer entities.SimulcastLayer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.viewers[token][viewerID] {
+		return errors.New("mock unknown viewer")
+	}
+	m.Layers[token+"/"+viewerID] = layer
+	return nil
+}
+
+// WatchFrames sends Frame once to a watcher of a published session and
+// closes the channel on stop
+func (m *MockStreamRelay) WatchFrames(token string) (<-chan []byte, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.published[token] {
+		return nil, nil, errors.New("mock stream not published")
+	}
+	frames := make(chan []byte, 1)
+	frames <- m.Frame
+	var once sync.Once
+	return frames, func() { once.Do(func() { close(frames) }) }, nil
+}
+
+// Snapshot returns Frame for a published session
+func (m *MockStreamRelay) Snapshot(token string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.published[token] {
+		return nil, errors.New("mock stream not published")
+	}
+	return m.Frame, nil
+}
+
+// DisconnectViewer forgets a viewer that was offered a connection
+func (m *MockStreamRelay) DisconnectViewer(token, viewerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.viewers[token][viewerID] {
+		return errors.New("mock unknown viewer")
+	}
+	delete(m.viewers[token], viewerID)
+	return nil
+}
+
+// Close forgets a session's stream and viewers
+func (m *MockStreamRelay) Close(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.published, token)
+	delete(m.viewers, token)
+	m.Closed = append(m.Closed, token)
+}
+
+// GetTokens returns the tokens of the published sessions
+func (m *MockStreamRelay) GetTokens() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Sorted(maps.Keys(m.published))
+}
+
+// OnViewersChanged registers the viewer count callback
+func (m *MockStreamRelay) OnViewersChanged(fn func(token string, viewers int)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onViewers = fn
+}
+
+// SetViewers reports a session's viewer count as the relay would (helper
+// method for testing)
+func (m *MockStreamRelay) SetViewers(token string, viewers int) {
+	m.mu.Lock()
+	fn := m.onViewers
+	m.mu.Unlock()
+
+	if fn != nil {
+		fn(token, viewers)
+	}
+}
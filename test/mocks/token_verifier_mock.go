@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"errors"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockTokenVerifier is a mock implementation of TokenVerifier interface
+type MockTokenVerifier struct {
+	// Users maps the tokens the mock accepts to their users
+	Users map[string]*entities.UserIdentity
+}
+
+// NewMockTokenVerifier creates a new mock token verifier accepting no tokens
+func NewMockTokenVerifier() *MockTokenVerifier {
+	return &MockTokenVerifier{
+		Users: make(map[string]*entities.UserIdentity),
+	}
+}
+
+// Verify returns the user of a known token
+func (m *MockTokenVerifier) Verify(token string) (*entities.UserIdentity, error) {
+	user, ok := m.Users[token]
+	if !ok {
+		return nil, errors.New("invalid token")
+	}
+	return user, nil
+}
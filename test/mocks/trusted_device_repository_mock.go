@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"slices"
+	"sync"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockTrustedDeviceRepository is a mock implementation of
+// TrustedDeviceRepository interface
+type MockTrustedDeviceRepository struct {
+	mu      sync.Mutex
+	devices map[string]entities.TrustedDevice
+
+	// For controlling behavior in tests
+	ShouldFailSaveDevice bool
+}
+
+// NewMockTrustedDeviceRepository creates a new mock trusted device repository
+func NewMockTrustedDeviceRepository() *MockTrustedDeviceRepository {
+	return &MockTrustedDeviceRepository{devices: make(map[string]entities.TrustedDevice)}
+}
+
+// ListDevices returns the devices a sender trusts, oldest pairing first
+func (m *MockTrustedDeviceRepository) ListDevices(owner string) ([]*entities.TrustedDevice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var devices []*entities.TrustedDevice
+	for _, device := range m.devices {
+		if device.Owner == owner {
+			devices = append(devices, &device)
+		}
+	}
+	slices.SortFunc(devices, func(a, b *entities.TrustedDevice) int {
+		return a.PairedAt.Compare(b.PairedAt)
+	})
+	return devices, nil
+}
+
+// FindDevice returns the pairing of a viewer device with a sender
+func (m *MockTrustedDeviceRepository) FindDevice(owner, deviceID string) (*entities.TrustedDevice, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, device := range m.devices {
+		if device.Owner == owner && device.DeviceID == deviceID {
+			return &device, nil
+		}
+	}
+	return nil, mockError("trusted device not found")
+}
+
+// SaveDevice stores a pairing, replacing any pairing with the same ID
+func (m *MockTrustedDeviceRepository) SaveDevice(device *entities.TrustedDevice) error {
+	if m.ShouldFailSaveDevice {
+		return mockError("failed to save trusted device")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.devices[device.ID] = *device
+	return nil
+}
+
+// DeleteDevice removes the pairing with the given ID from a sender's devices
+func (m *MockTrustedDeviceRepository) DeleteDevice(owner, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if device, exists := m.devices[id]; !exists || device.Owner != owner {
+		return mockError("trusted device not found")
+	}
+	delete(m.devices, id)
+	return nil
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"time"
+
+	"share-screen/pkg/domain/entities"
+)
+
+// MockTURNCredentialService is a mock implementation of TURNCredentialService interface
+type MockTURNCredentialService struct {
+	// ExpiresAt is the expiry given to minted credentials
+	ExpiresAt time.Time
+
+	// Users records who credentials were minted for
+	Users []string
+}
+
+// NewMockTURNCredentialService creates a new mock TURN credential service
+func NewMockTURNCredentialService() *MockTURNCredentialService {
+	return &MockTURNCredentialService{
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+// Mint returns a predictable credential for user
+func (m *MockTURNCredentialService) Mint(user string) *entities.TURNCredential {
+	m.Users = append(m.Users, user)
+	return &entities.TURNCredential{
+		Username:   "minted:" + user,
+		Credential: "minted-credential",
+		ExpiresAt:  m.ExpiresAt,
+	}
+}
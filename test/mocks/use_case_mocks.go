@@ -2,6 +2,9 @@ package mocks
 
 import (
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"share-screen/pkg/domain/entities"
 	"share-screen/pkg/usecase/dto"
@@ -15,11 +18,54 @@ type MockSessionUseCase struct {
 	ShouldFailGetOffer      bool
 	ShouldFailSubmitAnswer  bool
 	ShouldFailGetAnswer     bool
+	ShouldFailGetPreview    bool
+	ShouldFailHeartbeat     bool
+	ShouldFailEndSession    bool
+	ShouldFailExtendSession bool
+	ShouldFailResumeSession bool
+	ShouldFailPauseSession  bool
+	ShouldFailListOwn       bool
+	ShouldFailRename        bool
+	ShouldFailPublish       bool
+	ShouldFailSelectLayer   bool
+	ShouldFailKickViewer    bool
+	ShouldFailGetSession    bool
+	ShouldFailResetOffer    bool
+
+	// CreateSessionError, when set, is returned by CreateSession
+	CreateSessionError error
+
+	// ResolveAliasError, when set, is returned by ResolveViewerAlias
+	ResolveAliasError error
+
+	// EndSessionError, when set, is returned by EndSession
+	EndSessionError error
 
 	// For returning specific data
 	CreateSessionResponse *dto.CreateSessionResponse
 	GetOfferResponse      *dto.GetOfferResponse
 	GetAnswerResponse     *dto.GetAnswerResponse
+	LinkPreviewResponse   *dto.LinkPreviewResponse
+	PublishResponse       *dto.PublishStreamResponse
+	SessionDetailResponse *dto.SessionDetailResponse
+
+	// LastCreateRequest records the most recent CreateSession request
+	LastCreateRequest *dto.CreateSessionRequest
+
+	// LastSelectLayerRequest records the most recent SelectLayer request
+	LastSelectLayerRequest *dto.SelectLayerRequest
+
+	// LastKickViewerRequest records the most recent KickViewer request
+	LastKickViewerRequest *dto.KickViewerRequest
+
+	// LastResetOfferRequest records the most recent ResetOffer request
+	LastResetOfferRequest *dto.ResetOfferRequest
+
+	// LastPublishRequest records the most recent PublishStream request
+	LastPublishRequest *dto.PublishStreamRequest
+
+	// LastEndSessionRequest records the most recent EndSession request
+	LastEndSessionRequest *dto.EndSessionRequest
 }
 
 // NewMockSessionUseCase creates a new mock session use case
@@ -32,14 +78,22 @@ func NewMockSessionUseCase() *MockSessionUseCase {
 		GetAnswerResponse: &dto.GetAnswerResponse{
 			Answer: &entities.WebRTCAnswer{Type: "answer", SDP: "mock-answer-sdp"},
 		},
+		LinkPreviewResponse: &dto.LinkPreviewResponse{Name: "Mock Session", Enabled: true},
+		PublishResponse: &dto.PublishStreamResponse{
+			Answer: &entities.WebRTCAnswer{Type: "answer", SDP: "mock-sfu-sdp"},
+		},
 	}
 }
 
 // CreateSession creates a new screen sharing session
-func (m *MockSessionUseCase) CreateSession() (*dto.CreateSessionResponse, error) {
+func (m *MockSessionUseCase) CreateSession(request *dto.CreateSessionRequest) (*dto.CreateSessionResponse, error) {
+	m.LastCreateRequest = request
 	if m.ShouldFailCreateSession {
 		return nil, errors.New("mock create session error")
 	}
+	if m.CreateSessionError != nil {
+		return nil, m.CreateSessionError
+	}
 	return m.CreateSessionResponse, nil
 }
 
@@ -51,6 +105,15 @@ func (m *MockSessionUseCase) SubmitOffer(request *dto.SubmitOfferRequest) error
 	return nil
 }
 
+// ResetOffer clears a session's offer and answer
+func (m *MockSessionUseCase) ResetOffer(request *dto.ResetOfferRequest) error {
+	m.LastResetOfferRequest = request
+	if m.ShouldFailResetOffer {
+		return errors.New("mock reset offer error")
+	}
+	return nil
+}
+
 // GetOffer retrieves a WebRTC offer for a session
 func (m *MockSessionUseCase) GetOffer(request *dto.GetOfferRequest) (*dto.GetOfferResponse, error) {
 	if m.ShouldFailGetOffer {
@@ -75,6 +138,326 @@ func (m *MockSessionUseCase) GetAnswer(request *dto.GetAnswerRequest) (*dto.GetA
 	return m.GetAnswerResponse, nil
 }
 
+// PublishStream connects the sender of an SFU session to the server
+func (m *MockSessionUseCase) PublishStream(request *dto.PublishStreamRequest) (*dto.PublishStreamResponse, error) {
+	m.LastPublishRequest = request
+	if m.ShouldFailPublish {
+		return nil, errors.New("mock publish error")
+	}
+	return m.PublishResponse, nil
+}
+
+// SelectLayer sets the simulcast layer a viewer of an SFU session receives
+func (m *MockSessionUseCase) SelectLayer(request *dto.SelectLayerRequest) error {
+	m.LastSelectLayerRequest = request
+	if m.ShouldFailSelectLayer {
+		return errors.New("mock select layer error")
+	}
+	return nil
+}
+
+// KickViewer removes a viewer from a session
+func (m *MockSessionUseCase) KickViewer(request *dto.KickViewerRequest) error {
+	m.LastKickViewerRequest = request
+	if m.ShouldFailKickViewer {
+		return errors.New("mock kick viewer error")
+	}
+	return nil
+}
+
+// GetLinkPreview returns the public details used for viewer link previews
+func (m *MockSessionUseCase) GetLinkPreview(request *dto.GetLinkPreviewRequest) (*dto.LinkPreviewResponse, error) {
+	if m.ShouldFailGetPreview {
+		return nil, errors.New("mock get preview error")
+	}
+	return m.LinkPreviewResponse, nil
+}
+
+// ResolveViewerAlias returns mock-token for any alias
+func (m *MockSessionUseCase) ResolveViewerAlias(request *dto.ResolveViewerAliasRequest) (*dto.ResolveViewerAliasResponse, error) {
+	if m.ResolveAliasError != nil {
+		return nil, m.ResolveAliasError
+	}
+	return &dto.ResolveViewerAliasResponse{Token: "mock-token"}, nil
+}
+
+// Heartbeat records that the sender is still present
+func (m *MockSessionUseCase) Heartbeat(request *dto.HeartbeatRequest) error {
+	if m.ShouldFailHeartbeat {
+		return errors.New("mock heartbeat error")
+	}
+	return nil
+}
+
+// ResumeSession reattaches a reloaded sender page to its session
+func (m *MockSessionUseCase) ResumeSession(request *dto.ResumeSessionRequest) (*dto.CreateSessionResponse, error) {
+	if m.ShouldFailResumeSession {
+		return nil, errors.New("mock resume session error")
+	}
+	return &dto.CreateSessionResponse{Token: request.Token, SenderKey: request.SenderKey}, nil
+}
+
+// ExtendSession pushes back the expiry of a live session by 30 minutes
+func (m *MockSessionUseCase) ExtendSession(request *dto.ExtendSessionRequest) (*dto.ExtendSessionResponse, error) {
+	if m.ShouldFailExtendSession {
+		return nil, errors.New("mock extend session error")
+	}
+	return &dto.ExtendSessionResponse{ExpiresAt: time.Now().Add(30 * time.Minute), ExpiresInSeconds: 1800}, nil
+}
+
+// GetSession describes a waiting session
+func (m *MockSessionUseCase) GetSession(request *dto.GetSessionRequest) (*dto.SessionDetailResponse, error) {
+	if m.ShouldFailGetSession {
+		return nil, errors.New("mock get session error")
+	}
+	if m.SessionDetailResponse != nil {
+		return m.SessionDetailResponse, nil
+	}
+	return &dto.SessionDetailResponse{
+		Token:            request.Token,
+		Status:           entities.SessionStatusPending,
+		ExpiresAt:        time.Now().Add(30 * time.Minute),
+		ExpiresInSeconds: 1800,
+	}, nil
+}
+
+// PauseSession pauses or resumes the sender's stream
+func (m *MockSessionUseCase) PauseSession(request *dto.PauseSessionRequest) error {
+	if m.ShouldFailPauseSession {
+		return errors.New("mock pause session error")
+	}
+	return nil
+}
+
+// ListOwnSessions lists one session for any sender with an ID
+func (m *MockSessionUseCase) ListOwnSessions(request *dto.ListOwnSessionsRequest) (*dto.OwnSessionsResponse, error) {
+	if m.ShouldFailListOwn {
+		return nil, errors.New("mock list own sessions error")
+	}
+	response := &dto.OwnSessionsResponse{Sessions: []dto.OwnSession{}}
+	if request.Owner != "" {
+		response.Sessions = append(response.Sessions, dto.OwnSession{Token: "mock-token", Status: entities.SessionStatusPending})
+	}
+	return response, nil
+}
+
+// RenameSession renames a session
+func (m *MockSessionUseCase) RenameSession(request *dto.RenameSessionRequest) error {
+	if m.ShouldFailRename {
+		return errors.New("mock rename session error")
+	}
+	return nil
+}
+
+// EndSession ends a session at the sender's request
+func (m *MockSessionUseCase) EndSession(request *dto.EndSessionRequest) error {
+	m.LastEndSessionRequest = request
+	if m.ShouldFailEndSession {
+		return errors.New("mock end session error")
+	}
+	return m.EndSessionError
+}
+
+// MockViewerQueueUseCase is a mock implementation of ViewerQueueUseCase interface
+type MockViewerQueueUseCase struct {
+	// For controlling behavior in tests
+	JoinQueueError     error
+	LeaveQueueError    error
+	PromoteViewerError error
+	GetQueueError      error
+	ReleaseViewerError error
+
+	// For returning specific data
+	JoinQueueResponse *dto.JoinQueueResponse
+	GetQueueResponse  *dto.GetQueueResponse
+
+	// LastViewerRequest records the most recent leave or promote request
+	LastViewerRequest *dto.QueueViewerRequest
+}
+
+// NewMockViewerQueueUseCase creates a new mock viewer queue use case
+func NewMockViewerQueueUseCase() *MockViewerQueueUseCase {
+	return &MockViewerQueueUseCase{
+		JoinQueueResponse: &dto.JoinQueueResponse{ViewerID: "mock-viewer", Position: 1},
+		GetQueueResponse:  &dto.GetQueueResponse{Viewers: []entities.QueuedViewer{}},
+	}
+}
+
+// JoinQueue places a viewer in the queue
+func (m *MockViewerQueueUseCase) JoinQueue(request *dto.JoinQueueRequest) (*dto.JoinQueueResponse, error) {
+	if m.JoinQueueError != nil {
+		return nil, m.JoinQueueError
+	}
+	return m.JoinQueueResponse, nil
+}
+
+// LeaveQueue removes a viewer from the queue
+func (m *MockViewerQueueUseCase) LeaveQueue(request *dto.QueueViewerRequest) error {
+	m.LastViewerRequest = request
+	return m.LeaveQueueError
+}
+
+// PromoteViewer moves a viewer to the front of the queue
+func (m *MockViewerQueueUseCase) PromoteViewer(request *dto.QueueViewerRequest) error {
+	m.LastViewerRequest = request
+	return m.PromoteViewerError
+}
+
+// GetQueue lists the viewers waiting on a session
+func (m *MockViewerQueueUseCase) GetQueue(request *dto.GetQueueRequest) (*dto.GetQueueResponse, error) {
+	if m.GetQueueError != nil {
+		return nil, m.GetQueueError
+	}
+	return m.GetQueueResponse, nil
+}
+
+// ReleaseViewer frees the slot held by the connected viewer
+func (m *MockViewerQueueUseCase) ReleaseViewer(request *dto.ReleaseViewerRequest) error {
+	return m.ReleaseViewerError
+}
+
+// MockSessionHistoryUseCase is a mock implementation of SessionHistoryUseCase interface
+type MockSessionHistoryUseCase struct {
+	// For controlling behavior in tests
+	GetSummaryError  error
+	UpdateNotesError error
+
+	// For returning specific data
+	SummaryResponse *dto.SessionSummaryResponse
+
+	// LastNotesRequest records the most recent UpdateSessionNotes request
+	LastNotesRequest *dto.UpdateSessionNotesRequest
+}
+
+// NewMockSessionHistoryUseCase creates a new mock session history use case
+func NewMockSessionHistoryUseCase() *MockSessionHistoryUseCase {
+	return &MockSessionHistoryUseCase{
+		SummaryResponse: &dto.SessionSummaryResponse{Token: "mock-token", DurationSeconds: 60},
+	}
+}
+
+// GetSessionSummary returns the summary of a finished session
+func (m *MockSessionHistoryUseCase) GetSessionSummary(request *dto.GetSessionSummaryRequest) (*dto.SessionSummaryResponse, error) {
+	if m.GetSummaryError != nil {
+		return nil, m.GetSummaryError
+	}
+	return m.SummaryResponse, nil
+}
+
+// UpdateSessionNotes replaces the notes stored with a finished session
+func (m *MockSessionHistoryUseCase) UpdateSessionNotes(request *dto.UpdateSessionNotesRequest) (*dto.SessionSummaryResponse, error) {
+	m.LastNotesRequest = request
+	if m.UpdateNotesError != nil {
+		return nil, m.UpdateNotesError
+	}
+	return m.SummaryResponse, nil
+}
+
+// MockViewerSettingsUseCase is a mock implementation of ViewerSettingsUseCase interface
+type MockViewerSettingsUseCase struct {
+	// For controlling behavior in tests
+	GetSettingsError    error
+	UpdateSettingsError error
+	RequestQualityError error
+
+	// For returning specific data
+	SettingsResponse *dto.ViewerSettingsResponse
+
+	// Last*Request record the most recent requests
+	LastSettingsRequest *dto.UpdateViewerSettingsRequest
+	LastQualityRequest  *dto.QualityRequest
+}
+
+// NewMockViewerSettingsUseCase creates a new mock viewer settings use case
+func NewMockViewerSettingsUseCase() *MockViewerSettingsUseCase {
+	return &MockViewerSettingsUseCase{
+		SettingsResponse: &dto.ViewerSettingsResponse{},
+	}
+}
+
+// GetSettings returns the settings stored for a device
+func (m *MockViewerSettingsUseCase) GetSettings(request *dto.GetViewerSettingsRequest) (*dto.ViewerSettingsResponse, error) {
+	if m.GetSettingsError != nil {
+		return nil, m.GetSettingsError
+	}
+	return m.SettingsResponse, nil
+}
+
+// UpdateSettings stores the settings for a device
+func (m *MockViewerSettingsUseCase) UpdateSettings(request *dto.UpdateViewerSettingsRequest) (*dto.ViewerSettingsResponse, error) {
+	m.LastSettingsRequest = request
+	if m.UpdateSettingsError != nil {
+		return nil, m.UpdateSettingsError
+	}
+	return m.SettingsResponse, nil
+}
+
+// RequestQuality asks the sender of a live session to adjust its stream
+func (m *MockViewerSettingsUseCase) RequestQuality(request *dto.QualityRequest) error {
+	m.LastQualityRequest = request
+	return m.RequestQualityError
+}
+
+// MockHandoutUseCase is a mock implementation of HandoutUseCase interface
+type MockHandoutUseCase struct {
+	// For controlling behavior in tests
+	GetHandoutError error
+
+	// For returning specific data
+	HandoutResponse *dto.HandoutResponse
+
+	// LastHandoutRequest records the most recent GetHandout request
+	LastHandoutRequest *dto.GetHandoutRequest
+}
+
+// NewMockHandoutUseCase creates a new mock handout use case
+func NewMockHandoutUseCase() *MockHandoutUseCase {
+	return &MockHandoutUseCase{
+		HandoutResponse: &dto.HandoutResponse{
+			ViewerURL:  "http://192.168.1.100:8080/viewer?token=mock-token",
+			QRCode:     []byte("png"),
+			ValidFrom:  time.Now(),
+			ValidUntil: time.Now().Add(30 * time.Minute),
+		},
+	}
+}
+
+// GetHandout returns the joining details for a live session
+func (m *MockHandoutUseCase) GetHandout(request *dto.GetHandoutRequest) (*dto.HandoutResponse, error) {
+	m.LastHandoutRequest = request
+	if m.GetHandoutError != nil {
+		return nil, m.GetHandoutError
+	}
+	return m.HandoutResponse, nil
+}
+
+// MockInviteUseCase is a mock implementation of InviteUseCase interface
+type MockInviteUseCase struct {
+	// For controlling behavior in tests
+	PostInviteError error
+
+	// LastPostRequest records the most recent PostInvite request
+	LastPostRequest *dto.PostInviteRequest
+}
+
+// NewMockInviteUseCase creates a new mock invite use case
+func NewMockInviteUseCase() *MockInviteUseCase {
+	return &MockInviteUseCase{}
+}
+
+// PostInvite posts the viewer link of a live session to the team chat
+func (m *MockInviteUseCase) PostInvite(request *dto.PostInviteRequest) (*dto.PostInviteResponse, error) {
+	m.LastPostRequest = request
+	if m.PostInviteError != nil {
+		return nil, m.PostInviteError
+	}
+	return &dto.PostInviteResponse{
+		Services:  []string{"Slack"},
+		ViewerURL: "http://192.168.1.100:8080/viewer?token=" + request.Token,
+		PostedAt:  time.Now(),
+	}, nil
+}
+
 // MockServerInfoUseCase is a mock implementation of ServerInfoUseCase interface
 type MockServerInfoUseCase struct {
 	// For controlling behavior in tests
@@ -82,6 +465,8 @@ type MockServerInfoUseCase struct {
 
 	// For returning specific data
 	ServerInfo *entities.ServerInfo
+	Health     *dto.HealthResponse
+	Interfaces *dto.InterfacesResponse
 }
 
 // NewMockServerInfoUseCase creates a new mock server info use case
@@ -107,3 +492,869 @@ func (m *MockServerInfoUseCase) GetServerInfo(host string) (*entities.ServerInfo
 	result.Host = host
 	return &result, nil
 }
+
+// GetHealth returns whether the server is healthy
+func (m *MockServerInfoUseCase) GetHealth() *dto.HealthResponse {
+	if m.Health != nil {
+		return m.Health
+	}
+	return &dto.HealthResponse{Status: dto.HealthOK, Version: m.ServerInfo.Version}
+}
+
+// GetInterfaces lists the network interfaces links may use
+func (m *MockServerInfoUseCase) GetInterfaces() *dto.InterfacesResponse {
+	if m.Interfaces != nil {
+		return m.Interfaces
+	}
+	return &dto.InterfacesResponse{}
+}
+
+// MockStatusUseCase is a mock implementation of StatusUseCase interface
+type MockStatusUseCase struct {
+	// For controlling behavior in tests
+	GetViewerStatusError error
+
+	// For returning specific data
+	StatusResponse *dto.ViewerStatusResponse
+}
+
+// NewMockStatusUseCase creates a new mock status use case
+func NewMockStatusUseCase() *MockStatusUseCase {
+	return &MockStatusUseCase{
+		StatusResponse: &dto.ViewerStatusResponse{},
+	}
+}
+
+// GetViewerStatus counts the connected and queued viewers across live sessions
+func (m *MockStatusUseCase) GetViewerStatus() (*dto.ViewerStatusResponse, error) {
+	if m.GetViewerStatusError != nil {
+		return nil, m.GetViewerStatusError
+	}
+	status := *m.StatusResponse
+	return &status, nil
+}
+
+// MockStatsUseCase is a mock implementation of StatsUseCase interface
+type MockStatsUseCase struct {
+	// For controlling behavior in tests
+	RecordStatsError      error
+	RecordLatencyError    error
+	GetSessionStatsError  error
+	ListSessionStatsError error
+	GetStatsSummaryError  error
+	GetViewerStatsError   error
+
+	// LastRecordRequest, LastLatencyRequest, LastGetRequest and
+	// LastViewerStatsRequest record the most recent requests
+	LastRecordRequest      *dto.RecordStatsRequest
+	LastLatencyRequest     *dto.RecordLatencyRequest
+	LastGetRequest         *dto.GetStatsRequest
+	LastViewerStatsRequest *dto.GetViewerStatsRequest
+}
+
+// NewMockStatsUseCase creates a new mock stats use case
+func NewMockStatsUseCase() *MockStatsUseCase {
+	return &MockStatsUseCase{}
+}
+
+// RecordStats stores a peer's stats summary in its session's time series
+func (m *MockStatsUseCase) RecordStats(request *dto.RecordStatsRequest) error {
+	m.LastRecordRequest = request
+	return m.RecordStatsError
+}
+
+// RecordLatency stores a latency probe result of a peer of a session
+func (m *MockStatsUseCase) RecordLatency(request *dto.RecordLatencyRequest) error {
+	m.LastLatencyRequest = request
+	return m.RecordLatencyError
+}
+
+// GetSessionStats returns a session's stats time series
+func (m *MockStatsUseCase) GetSessionStats(request *dto.GetStatsRequest) (*dto.SessionStatsResponse, error) {
+	m.LastGetRequest = request
+	if m.GetSessionStatsError != nil {
+		return nil, m.GetSessionStatsError
+	}
+	return &dto.SessionStatsResponse{
+		Token:   request.Token,
+		Samples: []entities.StatsSample{{At: time.Now(), Role: entities.StatsRoleSender, Bitrate: 1_000_000}},
+		Latency: []entities.LatencySample{},
+	}, nil
+}
+
+// ListSessionStats returns the live sessions with their peers' latest stats
+func (m *MockStatsUseCase) ListSessionStats() (*dto.AdminSessionsResponse, error) {
+	if m.ListSessionStatsError != nil {
+		return nil, m.ListSessionStatsError
+	}
+	return &dto.AdminSessionsResponse{Sessions: []dto.AdminSession{{Token: "mock-token", Status: entities.SessionStatusActive}}}, nil
+}
+
+// GetStatsSummary returns today's session totals
+func (m *MockStatsUseCase) GetStatsSummary() (*dto.StatsSummaryResponse, error) {
+	if m.GetStatsSummaryError != nil {
+		return nil, m.GetStatsSummaryError
+	}
+	return &dto.StatsSummaryResponse{Date: "2024-01-15", Created: 3, Completed: 2, Active: 1, AverageDurationSeconds: 600, PeakConcurrent: 2}, nil
+}
+
+// GetViewerStats returns the connection quality of each of a session's viewers
+func (m *MockStatsUseCase) GetViewerStats(request *dto.GetViewerStatsRequest) (*dto.ViewerStatsResponse, error) {
+	m.LastViewerStatsRequest = request
+	if m.GetViewerStatsError != nil {
+		return nil, m.GetViewerStatsError
+	}
+	return &dto.ViewerStatsResponse{
+		WindowSeconds: 60,
+		Viewers: []dto.ViewerStats{{
+			ViewerID: "viewer-1",
+			Quality:  entities.QualityGood,
+			Latest:   entities.StatsSample{At: time.Now(), Role: entities.StatsRoleViewer, ViewerID: "viewer-1", Bitrate: 1_000_000},
+			Samples:  1,
+		}},
+	}, nil
+}
+
+// MockAuditUseCase is a mock implementation of AuditUseCase interface
+type MockAuditUseCase struct {
+	// For controlling behavior in tests
+	GetAuditLogError error
+
+	// LastRequest records the most recent GetAuditLog request
+	LastRequest *dto.GetAuditLogRequest
+}
+
+// NewMockAuditUseCase creates a new mock audit use case
+func NewMockAuditUseCase() *MockAuditUseCase {
+	return &MockAuditUseCase{}
+}
+
+// GetAuditLog returns the lifecycle events recorded for a session
+func (m *MockAuditUseCase) GetAuditLog(request *dto.GetAuditLogRequest) (*dto.AuditLogResponse, error) {
+	m.LastRequest = request
+	if m.GetAuditLogError != nil {
+		return nil, m.GetAuditLogError
+	}
+	return &dto.AuditLogResponse{
+		Token: request.Token,
+		Events: []entities.AuditEvent{
+			{At: time.Now(), Token: request.Token, Type: entities.AuditSessionCreated, ClientIP: "192.168.1.10"},
+			{At: time.Now(), Token: request.Token, Type: entities.AuditTerminated, ClientIP: "192.168.1.10"},
+		},
+	}, nil
+}
+
+// MockCleanupUseCase is a mock implementation of CleanupUseCase interface
+type MockCleanupUseCase struct {
+	// For controlling behavior in tests
+	RunCleanupError error
+
+	// LastTrigger records the trigger of the most recent run
+	LastTrigger string
+}
+
+// NewMockCleanupUseCase creates a new mock cleanup use case
+func NewMockCleanupUseCase() *MockCleanupUseCase {
+	return &MockCleanupUseCase{}
+}
+
+// RunCleanup reports a run that removed one expired session
+func (m *MockCleanupUseCase) RunCleanup(trigger string) (*entities.CleanupRun, error) {
+	m.LastTrigger = trigger
+	if m.RunCleanupError != nil {
+		return nil, m.RunCleanupError
+	}
+	return &entities.CleanupRun{StartedAt: time.Now(), Trigger: trigger, ExpiredSessions: 1}, nil
+}
+
+// CleanupStatus returns a one-minute schedule that has not run yet
+func (m *MockCleanupUseCase) CleanupStatus() *entities.CleanupStatus {
+	return &entities.CleanupStatus{IntervalSeconds: 60}
+}
+
+// MockBanUseCase is a mock implementation of BanUseCase interface
+type MockBanUseCase struct {
+	// For controlling behavior in tests
+	AddBanError    error
+	RemoveBanError error
+
+	// Banned lists the addresses IsBanned reports
+	Banned []string
+
+	// LastAddRequest and LastRemoveRequest record the most recent changes
+	LastAddRequest    *dto.AddBanRequest
+	LastRemoveRequest *dto.RemoveBanRequest
+}
+
+// NewMockBanUseCase creates a new mock ban use case
+func NewMockBanUseCase() *MockBanUseCase {
+	return &MockBanUseCase{}
+}
+
+// ListBans returns one ban of a guest network
+func (m *MockBanUseCase) ListBans() (*dto.BansResponse, error) {
+	return &dto.BansResponse{Bans: []*entities.Ban{{Network: "192.168.50.0/24", Reason: "guest network", CreatedAt: time.Now()}}}, nil
+}
+
+// AddBan returns the requested ban
+func (m *MockBanUseCase) AddBan(request *dto.AddBanRequest) (*entities.Ban, error) {
+	m.LastAddRequest = request
+	if m.AddBanError != nil {
+		return nil, m.AddBanError
+	}
+	return &entities.Ban{Network: request.Network, Reason: request.Reason, CreatedAt: time.Now()}, nil
+}
+
+// RemoveBan records the lifted ban
+func (m *MockBanUseCase) RemoveBan(request *dto.RemoveBanRequest) error {
+	m.LastRemoveRequest = request
+	return m.RemoveBanError
+}
+
+// IsBanned reports whether the address is in Banned
+func (m *MockBanUseCase) IsBanned(ip string) bool {
+	return slices.Contains(m.Banned, ip)
+}
+
+// MockICEConfigUseCase is a mock implementation of ICEConfigUseCase interface
+type MockICEConfigUseCase struct {
+	// For controlling behavior in tests
+	GetICEConfigError       error
+	GetTURNCredentialsError error
+
+	// For returning specific data
+	ICEConfigResponse       *dto.ICEConfigResponse
+	TURNCredentialsResponse *dto.TURNCredentialsResponse
+
+	// LastRequest records the most recent GetICEConfig request
+	LastRequest *dto.GetICEConfigRequest
+}
+
+// NewMockICEConfigUseCase creates a new mock ICE config use case
+func NewMockICEConfigUseCase() *MockICEConfigUseCase {
+	return &MockICEConfigUseCase{
+		ICEConfigResponse: &dto.ICEConfigResponse{
+			ICEServers: []entities.ICEServer{{URLs: []string{"stun:mock.com:19302"}}},
+		},
+		TURNCredentialsResponse: &dto.TURNCredentialsResponse{
+			URLs:       []string{"turn:mock.com:3478"},
+			Username:   "1700000000:mock",
+			Credential: "mock-credential",
+			TTL:        3600,
+		},
+	}
+}
+
+// GetICEConfig returns the STUN and TURN servers for the peers of a session
+func (m *MockICEConfigUseCase) GetICEConfig(request *dto.GetICEConfigRequest) (*dto.ICEConfigResponse, error) {
+	m.LastRequest = request
+	if m.GetICEConfigError != nil {
+		return nil, m.GetICEConfigError
+	}
+	return m.ICEConfigResponse, nil
+}
+
+// GetTURNCredentials mints a short-lived credential for the session's TURN servers
+func (m *MockICEConfigUseCase) GetTURNCredentials(request *dto.GetICEConfigRequest) (*dto.TURNCredentialsResponse, error) {
+	m.LastRequest = request
+	if m.GetTURNCredentialsError != nil {
+		return nil, m.GetTURNCredentialsError
+	}
+	return m.TURNCredentialsResponse, nil
+}
+
+// MockCapabilitiesUseCase is a mock implementation of CapabilitiesUseCase interface
+type MockCapabilitiesUseCase struct {
+	// For returning specific data
+	Capabilities *entities.Capabilities
+}
+
+// NewMockCapabilitiesUseCase creates a new mock capabilities use case
+func NewMockCapabilitiesUseCase() *MockCapabilitiesUseCase {
+	return &MockCapabilitiesUseCase{
+		Capabilities: &entities.Capabilities{
+			TURN:         entities.Capability{Enabled: true},
+			AuthProvider: entities.AuthProviderNone,
+		},
+	}
+}
+
+// GetCapabilities returns which optional subsystems work on this deployment
+func (m *MockCapabilitiesUseCase) GetCapabilities() *entities.Capabilities {
+	return m.Capabilities
+}
+
+// MockPresetUseCase is a mock implementation of PresetUseCase interface
+type MockPresetUseCase struct {
+	// For returning specific data
+	PresetsResponse *dto.PresetsResponse
+}
+
+// NewMockPresetUseCase creates a new mock preset use case
+func NewMockPresetUseCase() *MockPresetUseCase {
+	return &MockPresetUseCase{
+		PresetsResponse: &dto.PresetsResponse{
+			Presets: []entities.QualityPreset{{ID: "mock-preset", Name: "Mock", Width: 1280, Height: 720, FrameRate: 30, MaxBitrateKbps: 1000}},
+			Default: "mock-preset",
+		},
+	}
+}
+
+// ListPresets returns the quality presets the server offers
+func (m *MockPresetUseCase) ListPresets() *dto.PresetsResponse {
+	return m.PresetsResponse
+}
+
+// MockRoomUseCase is a mock implementation of RoomUseCase interface
+type MockRoomUseCase struct {
+	// For controlling behavior in tests
+	ClaimRoomError error
+	GetRoomError   error
+
+	// For returning specific data
+	RoomResponse *dto.RoomResponse
+
+	// LastClaimRequest records the most recent ClaimRoom request
+	LastClaimRequest *dto.ClaimRoomRequest
+}
+
+// NewMockRoomUseCase creates a new mock room use case
+func NewMockRoomUseCase() *MockRoomUseCase {
+	return &MockRoomUseCase{
+		RoomResponse: &dto.RoomResponse{Name: "design-review", Live: true, Token: "mock-token"},
+	}
+}
+
+// ClaimRoom points a room at a live session, creating the room if the name is free
+func (m *MockRoomUseCase) ClaimRoom(request *dto.ClaimRoomRequest) (*dto.ClaimRoomResponse, error) {
+	m.LastClaimRequest = request
+	if m.ClaimRoomError != nil {
+		return nil, m.ClaimRoomError
+	}
+	return &dto.ClaimRoomResponse{Name: request.Name, Key: "mock-room-key", Path: "/r/" + request.Name}, nil
+}
+
+// GetRoom returns the session a room currently leads to
+func (m *MockRoomUseCase) GetRoom(request *dto.GetRoomRequest) (*dto.RoomResponse, error) {
+	if m.GetRoomError != nil {
+		return nil, m.GetRoomError
+	}
+	return m.RoomResponse, nil
+}
+
+// MockViewerLinkUseCase is a mock implementation of ViewerLinkUseCase interface
+type MockViewerLinkUseCase struct {
+	// For controlling behavior in tests
+	CreateLinkError error
+	ListLinksError  error
+	RevokeLinkError error
+	RedeemLinkError error
+
+	// Links are the links listed and the first is the one created
+	Links []dto.ViewerLink
+
+	// LastCreateRequest, LastListRequest, LastRevokeRequest and
+	// LastRedeemRequest record the most recent requests
+	LastCreateRequest *dto.CreateViewerLinkRequest
+	LastListRequest   *dto.ListViewerLinksRequest
+	LastRevokeRequest *dto.RevokeViewerLinkRequest
+	LastRedeemRequest *dto.RedeemViewerLinkRequest
+}
+
+// NewMockViewerLinkUseCase creates a new mock viewer link use case
+func NewMockViewerLinkUseCase() *MockViewerLinkUseCase {
+	now := time.Now()
+	return &MockViewerLinkUseCase{
+		Links: []dto.ViewerLink{{
+			ID:             "mock-link",
+			Path:           "/l/mock-link",
+			CreatedAt:      now,
+			ExpiresAt:      now.Add(5 * time.Minute),
+			MaxRedemptions: 1,
+			Usable:         true,
+		}},
+	}
+}
+
+// CreateLink hands out a new viewer link for the sender's session
+func (m *MockViewerLinkUseCase) CreateLink(request *dto.CreateViewerLinkRequest) (*dto.ViewerLink, error) {
+	m.LastCreateRequest = request
+	if m.CreateLinkError != nil {
+		return nil, m.CreateLinkError
+	}
+	return &m.Links[0], nil
+}
+
+// ListLinks returns the viewer links of the sender's session
+func (m *MockViewerLinkUseCase) ListLinks(request *dto.ListViewerLinksRequest) (*dto.ViewerLinksResponse, error) {
+	m.LastListRequest = request
+	if m.ListLinksError != nil {
+		return nil, m.ListLinksError
+	}
+	return &dto.ViewerLinksResponse{Links: m.Links}, nil
+}
+
+// RevokeLink withdraws a viewer link before it expires
+func (m *MockViewerLinkUseCase) RevokeLink(request *dto.RevokeViewerLinkRequest) error {
+	m.LastRevokeRequest = request
+	return m.RevokeLinkError
+}
+
+// RedeemLink returns the session a viewer link leads to
+func (m *MockViewerLinkUseCase) RedeemLink(request *dto.RedeemViewerLinkRequest) (*dto.RedeemViewerLinkResponse, error) {
+	m.LastRedeemRequest = request
+	if m.RedeemLinkError != nil {
+		return nil, m.RedeemLinkError
+	}
+	return &dto.RedeemViewerLinkResponse{Token: "mock-token"}, nil
+}
+
+// MockChatUseCase is a mock implementation of ChatUseCase interface
+type MockChatUseCase struct {
+	// For controlling behavior in tests
+	SendMessageError error
+	GetMessagesError error
+
+	// LastSendRequest and LastGetRequest record the most recent requests
+	LastSendRequest *dto.SendChatMessageRequest
+	LastGetRequest  *dto.GetChatMessagesRequest
+}
+
+// NewMockChatUseCase creates a new mock chat use case
+func NewMockChatUseCase() *MockChatUseCase {
+	return &MockChatUseCase{}
+}
+
+// SendMessage relays a chat message to the other peer
+func (m *MockChatUseCase) SendMessage(request *dto.SendChatMessageRequest) (*entities.ChatMessage, error) {
+	m.LastSendRequest = request
+	if m.SendMessageError != nil {
+		return nil, m.SendMessageError
+	}
+	return &entities.ChatMessage{ID: 1, From: request.From, Text: request.Text, SentAt: time.Now()}, nil
+}
+
+// GetMessages returns the relayed chat messages after request.Since
+func (m *MockChatUseCase) GetMessages(request *dto.GetChatMessagesRequest) (*dto.ChatMessagesResponse, error) {
+	m.LastGetRequest = request
+	if m.GetMessagesError != nil {
+		return nil, m.GetMessagesError
+	}
+	return &dto.ChatMessagesResponse{Messages: []entities.ChatMessage{{ID: 2, From: "sender", Text: "mock"}}}, nil
+}
+
+// MockNegotiationUseCase is a mock implementation of NegotiationUseCase interface
+type MockNegotiationUseCase struct {
+	// For controlling behavior in tests
+	SendMessageError error
+	GetMessagesError error
+
+	// LastSendRequest and LastGetRequest record the most recent requests
+	LastSendRequest *dto.SendNegotiationMessageRequest
+	LastGetRequest  *dto.GetNegotiationMessagesRequest
+}
+
+// NewMockNegotiationUseCase creates a new mock negotiation use case
+func NewMockNegotiationUseCase() *MockNegotiationUseCase {
+	return &MockNegotiationUseCase{}
+}
+
+// SendMessage relays a description or candidate to the other peer
+func (m *MockNegotiationUseCase) SendMessage(request *dto.SendNegotiationMessageRequest) (*entities.NegotiationMessage, error) {
+	m.LastSendRequest = request
+	if m.SendMessageError != nil {
+		return nil, m.SendMessageError
+	}
+	return &entities.NegotiationMessage{Seq: 1, From: request.From, ViewerID: request.ViewerID, Description: request.Description, Candidate: request.Candidate, SentAt: time.Now()}, nil
+}
+
+// GetMessages returns the messages relayed on the viewer's connection after request.Since
+func (m *MockNegotiationUseCase) GetMessages(request *dto.GetNegotiationMessagesRequest) (*dto.NegotiationMessagesResponse, error) {
+	m.LastGetRequest = request
+	if m.GetMessagesError != nil {
+		return nil, m.GetMessagesError
+	}
+	return &dto.NegotiationMessagesResponse{Messages: []entities.NegotiationMessage{{Seq: 2, From: "sender", ViewerID: request.ViewerID, Candidate: &entities.ICECandidate{Candidate: "mock"}}}}, nil
+}
+
+// MockKeyExchangeUseCase is a mock implementation of KeyExchangeUseCase interface
+type MockKeyExchangeUseCase struct {
+	// For controlling behavior in tests
+	SendMessageError error
+	GetMessagesError error
+
+	// LastSendRequest and LastGetRequest record the most recent requests
+	LastSendRequest *dto.SendKeyMessageRequest
+	LastGetRequest  *dto.GetKeyMessagesRequest
+}
+
+// NewMockKeyExchangeUseCase creates a new mock key exchange use case
+func NewMockKeyExchangeUseCase() *MockKeyExchangeUseCase {
+	return &MockKeyExchangeUseCase{}
+}
+
+// SendMessage relays a public key, wrapped content key or rotation
+func (m *MockKeyExchangeUseCase) SendMessage(request *dto.SendKeyMessageRequest) (*entities.KeyExchangeMessage, error) {
+	m.LastSendRequest = request
+	if m.SendMessageError != nil {
+		return nil, m.SendMessageError
+	}
+	return &entities.KeyExchangeMessage{Seq: 1, Type: request.Type, ViewerID: request.ViewerID, KeyID: request.KeyID, PublicKey: request.PublicKey, SentAt: time.Now()}, nil
+}
+
+// GetMessages returns the key messages the caller may read after request.Since
+func (m *MockKeyExchangeUseCase) GetMessages(request *dto.GetKeyMessagesRequest) (*dto.KeyMessagesResponse, error) {
+	m.LastGetRequest = request
+	if m.GetMessagesError != nil {
+		return nil, m.GetMessagesError
+	}
+	return &dto.KeyMessagesResponse{Messages: []entities.KeyExchangeMessage{{Seq: 2, Type: entities.KeyMessageRotate, From: "sender", KeyID: 1}}}, nil
+}
+
+// MockFingerprintUseCase is a mock implementation of FingerprintUseCase interface
+type MockFingerprintUseCase struct {
+	// For controlling behavior in tests
+	GetFingerprintsError error
+
+	// LastGetRequest records the most recent request
+	LastGetRequest *dto.GetFingerprintsRequest
+}
+
+// NewMockFingerprintUseCase creates a new mock DTLS fingerprint use case
+func NewMockFingerprintUseCase() *MockFingerprintUseCase {
+	return &MockFingerprintUseCase{}
+}
+
+// GetFingerprints returns the fingerprints of the session's offer and answer
+func (m *MockFingerprintUseCase) GetFingerprints(request *dto.GetFingerprintsRequest) (*dto.FingerprintsResponse, error) {
+	m.LastGetRequest = request
+	if m.GetFingerprintsError != nil {
+		return nil, m.GetFingerprintsError
+	}
+	return &dto.FingerprintsResponse{
+		Sender: &entities.DTLSFingerprint{Algorithm: "sha-256", Value: "AA:BB:CC:DD:EE:FF", Short: "AA:BB:CC:DD:EE:FF"},
+	}, nil
+}
+
+// MockFileUseCase is a mock implementation of FileUseCase interface
+type MockFileUseCase struct {
+	// For controlling behavior in tests
+	ShareFileError error
+	ListFilesError error
+	GetFileError   error
+
+	// LastShareRequest and LastGetRequest record the most recent requests
+	LastShareRequest *dto.ShareFileRequest
+	LastGetRequest   *dto.GetFileRequest
+}
+
+// NewMockFileUseCase creates a new mock file use case
+func NewMockFileUseCase() *MockFileUseCase {
+	return &MockFileUseCase{}
+}
+
+// ShareFile keeps a file for the session's viewers and announces it
+func (m *MockFileUseCase) ShareFile(request *dto.ShareFileRequest) (*entities.SharedFile, error) {
+	m.LastShareRequest = request
+	if m.ShareFileError != nil {
+		return nil, m.ShareFileError
+	}
+	return &entities.SharedFile{ID: "file-id", Name: request.Name, Size: int64(len(request.Data)), ContentType: request.ContentType, SharedAt: time.Now()}, nil
+}
+
+// ListFiles returns the files relayed in a session
+func (m *MockFileUseCase) ListFiles(request *dto.GetFilesRequest) (*dto.FilesResponse, error) {
+	if m.ListFilesError != nil {
+		return nil, m.ListFilesError
+	}
+	return &dto.FilesResponse{Files: []*entities.SharedFile{{ID: "file-id", Name: "mock.txt", Size: 4}}}, nil
+}
+
+// GetFile returns one relayed file and its content
+func (m *MockFileUseCase) GetFile(request *dto.GetFileRequest) (*entities.SharedFile, []byte, error) {
+	m.LastGetRequest = request
+	if m.GetFileError != nil {
+		return nil, nil, m.GetFileError
+	}
+	return &entities.SharedFile{ID: request.ID, Name: "mock.txt", Size: 4}, []byte("mock"), nil
+}
+
+// MockFrameUseCase is a mock implementation of FrameUseCase interface
+type MockFrameUseCase struct {
+	// For controlling behavior in tests
+	WatchFramesError error
+
+	// Frames are sent to the watcher, after which the stream ends
+	Frames [][]byte
+
+	// LastWatchRequest records the most recent WatchFrames request, and
+	// Stopped whether its watch was stopped
+	LastWatchRequest *dto.WatchFramesRequest
+	Stopped          bool
+
+	// For controlling Snapshot, which returns the first of Frames
+	SnapshotError       error
+	LastSnapshotRequest *dto.SnapshotRequest
+}
+
+// NewMockFrameUseCase creates a new mock frame use case
+func NewMockFrameUseCase() *MockFrameUseCase {
+	return &MockFrameUseCase{}
+}
+
+// WatchFrames streams Frames and closes the stream
+func (m *MockFrameUseCase) WatchFrames(request *dto.WatchFramesRequest) (*dto.FrameStream, error) {
+	m.LastWatchRequest = request
+	if m.WatchFramesError != nil {
+		return nil, m.WatchFramesError
+	}
+	frames := make(chan []byte, len(m.Frames))
+	for _, frame := range m.Frames {
+		frames <- frame
+	}
+	close(frames)
+	return &dto.FrameStream{Frames: frames, Stop: func() { m.Stopped = true }}, nil
+}
+
+// Snapshot returns the first of Frames
+func (m *MockFrameUseCase) Snapshot(request *dto.SnapshotRequest) ([]byte, error) {
+	m.LastSnapshotRequest = request
+	if m.SnapshotError != nil {
+		return nil, m.SnapshotError
+	}
+	if len(m.Frames) == 0 {
+		return nil, errors.New("mock no frames")
+	}
+	return m.Frames[0], nil
+}
+
+// MockRecordingUseCase is a mock implementation of RecordingUseCase interface
+type MockRecordingUseCase struct {
+	// For controlling behavior in tests
+	ListRecordingsError  error
+	GetRecordingError    error
+	DeleteRecordingError error
+
+	// Content is served as every recording
+	Content string
+
+	// LastGetRequest and LastDeleteRequest record the most recent requests
+	LastGetRequest    *dto.GetRecordingRequest
+	LastDeleteRequest *dto.DeleteRecordingRequest
+}
+
+// NewMockRecordingUseCase creates a new mock recording use case
+func NewMockRecordingUseCase() *MockRecordingUseCase {
+	return &MockRecordingUseCase{Content: "recorded video"}
+}
+
+// ListRecordings returns one recording
+func (m *MockRecordingUseCase) ListRecordings() (*dto.RecordingsResponse, error) {
+	if m.ListRecordingsError != nil {
+		return nil, m.ListRecordingsError
+	}
+	recording := m.recording("test-token-20250115T100000Z.mkv")
+	return &dto.RecordingsResponse{Recordings: []*entities.Recording{recording}, TotalBytes: recording.Size}, nil
+}
+
+// GetRecording returns Content as the requested recording
+func (m *MockRecordingUseCase) GetRecording(request *dto.GetRecordingRequest) (*dto.RecordingDownload, error) {
+	m.LastGetRequest = request
+	if m.GetRecordingError != nil {
+		return nil, m.GetRecordingError
+	}
+	return &dto.RecordingDownload{
+		Recording: m.recording(request.Name),
+		Content:   nopSeekCloser{strings.NewReader(m.Content)},
+	}, nil
+}
+
+// DeleteRecording records the request
+func (m *MockRecordingUseCase) DeleteRecording(request *dto.DeleteRecordingRequest) error {
+	m.LastDeleteRequest = request
+	return m.DeleteRecordingError
+}
+
+// PruneRecordings removes nothing
+func (m *MockRecordingUseCase) PruneRecordings() ([]*entities.Recording, error) {
+	return nil, nil
+}
+
+// recording describes a recording of Content named name
+func (m *MockRecordingUseCase) recording(name string) *entities.Recording {
+	return &entities.Recording{
+		Name:      name,
+		Token:     "test-token",
+		Size:      int64(len(m.Content)),
+		StartedAt: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC),
+		EndedAt:   time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+}
+
+// MockSessionTemplateUseCase is a mock implementation of
+// SessionTemplateUseCase interface
+type MockSessionTemplateUseCase struct {
+	// For controlling behavior in tests
+	ListTemplatesError  error
+	SaveTemplateError   error
+	DeleteTemplateError error
+
+	// LastSaveRequest and LastDeleteRequest record the most recent requests
+	LastSaveRequest   *dto.SaveSessionTemplateRequest
+	LastDeleteRequest *dto.DeleteSessionTemplateRequest
+}
+
+// NewMockSessionTemplateUseCase creates a new mock session template use case
+func NewMockSessionTemplateUseCase() *MockSessionTemplateUseCase {
+	return &MockSessionTemplateUseCase{}
+}
+
+// ListTemplates returns one template
+func (m *MockSessionTemplateUseCase) ListTemplates() (*dto.SessionTemplatesResponse, error) {
+	if m.ListTemplatesError != nil {
+		return nil, m.ListTemplatesError
+	}
+	return &dto.SessionTemplatesResponse{Templates: []*entities.SessionTemplate{{
+		Name:       "meeting",
+		Preset:     "text",
+		RequirePIN: true,
+		MaxViewers: 20,
+	}}}, nil
+}
+
+// SaveTemplate returns the requested template
+func (m *MockSessionTemplateUseCase) SaveTemplate(request *dto.SaveSessionTemplateRequest) (*entities.SessionTemplate, error) {
+	m.LastSaveRequest = request
+	if m.SaveTemplateError != nil {
+		return nil, m.SaveTemplateError
+	}
+	return &entities.SessionTemplate{
+		Name:          request.Name,
+		Preset:        request.Preset,
+		Audio:         request.Audio,
+		RequirePIN:    request.RequirePIN,
+		MaxViewers:    request.MaxViewers,
+		ExpirySeconds: request.ExpirySeconds,
+	}, nil
+}
+
+// DeleteTemplate records the request
+func (m *MockSessionTemplateUseCase) DeleteTemplate(request *dto.DeleteSessionTemplateRequest) error {
+	m.LastDeleteRequest = request
+	return m.DeleteTemplateError
+}
+
+// MockSenderProfileUseCase is a mock implementation of SenderProfileUseCase
+// interface
+type MockSenderProfileUseCase struct {
+	// For controlling behavior in tests
+	GetProfileError    error
+	UpdateProfileError error
+	DeleteProfileError error
+
+	// LastGetRequest, LastUpdateRequest and LastDeleteRequest record the
+	// most recent requests
+	LastGetRequest    *dto.GetSenderProfileRequest
+	LastUpdateRequest *dto.UpdateSenderProfileRequest
+	LastDeleteRequest *dto.DeleteSenderProfileRequest
+}
+
+// NewMockSenderProfileUseCase creates a new mock sender profile use case
+func NewMockSenderProfileUseCase() *MockSenderProfileUseCase {
+	return &MockSenderProfileUseCase{}
+}
+
+// GetProfile returns a profile with the text preset
+func (m *MockSenderProfileUseCase) GetProfile(request *dto.GetSenderProfileRequest) (*dto.SenderProfileResponse, error) {
+	m.LastGetRequest = request
+	if m.GetProfileError != nil {
+		return nil, m.GetProfileError
+	}
+	return &dto.SenderProfileResponse{Preset: "text"}, nil
+}
+
+// UpdateProfile returns the requested profile
+func (m *MockSenderProfileUseCase) UpdateProfile(request *dto.UpdateSenderProfileRequest) (*dto.SenderProfileResponse, error) {
+	m.LastUpdateRequest = request
+	if m.UpdateProfileError != nil {
+		return nil, m.UpdateProfileError
+	}
+	return &dto.SenderProfileResponse{
+		Preset:    request.Preset,
+		Audio:     request.Audio,
+		Interface: request.Interface,
+		Theme:     request.Theme,
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// DeleteProfile records the request
+func (m *MockSenderProfileUseCase) DeleteProfile(request *dto.DeleteSenderProfileRequest) error {
+	m.LastDeleteRequest = request
+	return m.DeleteProfileError
+}
+
+// MockDevicePairingUseCase is a mock implementation of DevicePairingUseCase
+// interface
+type MockDevicePairingUseCase struct {
+	// For controlling behavior in tests
+	RequestPairingError error
+	ConfirmPairingError error
+	UnlockSessionError  error
+	ForgetDeviceError   error
+
+	// The most recent requests
+	LastRequestPairing *dto.RequestPairingRequest
+	LastConfirmRequest *dto.ConfirmPairingRequest
+	LastUnlockRequest  *dto.UnlockSessionRequest
+	LastListRequest    *dto.ListTrustedDevicesRequest
+	LastForgetRequest  *dto.ForgetTrustedDeviceRequest
+}
+
+// NewMockDevicePairingUseCase creates a new mock device pairing use case
+func NewMockDevicePairingUseCase() *MockDevicePairingUseCase {
+	return &MockDevicePairingUseCase{}
+}
+
+// RequestPairing returns a fixed code
+func (m *MockDevicePairingUseCase) RequestPairing(request *dto.RequestPairingRequest) (*dto.PairingCodeResponse, error) {
+	m.LastRequestPairing = request
+	if m.RequestPairingError != nil {
+		return nil, m.RequestPairingError
+	}
+	return &dto.PairingCodeResponse{Code: "123456", ExpiresAt: time.Now().Add(entities.PairingCodeTTL)}, nil
+}
+
+// ConfirmPairing returns a new pairing
+func (m *MockDevicePairingUseCase) ConfirmPairing(request *dto.ConfirmPairingRequest) (*dto.TrustedDevice, error) {
+	m.LastConfirmRequest = request
+	if m.ConfirmPairingError != nil {
+		return nil, m.ConfirmPairingError
+	}
+	return &dto.TrustedDevice{ID: "pairing-1", Name: "Tablet", PairedAt: time.Now()}, nil
+}
+
+// UnlockSession returns a fixed PIN
+func (m *MockDevicePairingUseCase) UnlockSession(request *dto.UnlockSessionRequest) (*dto.UnlockSessionResponse, error) {
+	m.LastUnlockRequest = request
+	if m.UnlockSessionError != nil {
+		return nil, m.UnlockSessionError
+	}
+	return &dto.UnlockSessionResponse{PIN: "246810"}, nil
+}
+
+// ListDevices returns one pairing for senders with an ID
+func (m *MockDevicePairingUseCase) ListDevices(request *dto.ListTrustedDevicesRequest) (*dto.TrustedDevicesResponse, error) {
+	m.LastListRequest = request
+	response := &dto.TrustedDevicesResponse{Devices: []dto.TrustedDevice{}}
+	if request.Owner != "" {
+		response.Devices = append(response.Devices, dto.TrustedDevice{ID: "pairing-1", Name: "Tablet"})
+	}
+	return response, nil
+}
+
+// ForgetDevice records the request
+func (m *MockDevicePairingUseCase) ForgetDevice(request *dto.ForgetTrustedDeviceRequest) error {
+	m.LastForgetRequest = request
+	return m.ForgetDeviceError
+}
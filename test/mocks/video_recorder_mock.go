@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"sync"
+
+	"share-screen/pkg/domain/interfaces"
+)
+
+// MockVideoRecorder is a mock implementation of VideoRecorder interface that
+// counts the packets it receives
+type MockVideoRecorder struct {
+	mu      sync.Mutex
+	codecs  []interfaces.VideoCodec
+	packets int
+	closed  int
+
+	// For controlling behavior in tests
+	ShouldFail bool
+}
+
+// NewMockVideoRecorder creates a new mock video recorder
+func NewMockVideoRecorder() *MockVideoRecorder {
+	return &MockVideoRecorder{}
+}
+
+// Open records the codec and returns a counting sink
+func (m *MockVideoRecorder) Open(codec interfaces.VideoCodec) (interfaces.VideoSink, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ShouldFail {
+		return nil, mockError("failed to open recording")
+	}
+	m.codecs = append(m.codecs, codec)
+	return &mockVideoSink{recorder: m}, nil
+}
+
+// Codecs returns the codec of every track opened (helper method for testing)
+func (m *MockVideoRecorder) Codecs() []interfaces.VideoCodec {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]interfaces.VideoCodec(nil), m.codecs...)
+}
+
+// Packets returns how many packets were written (helper method for testing)
+func (m *MockVideoRecorder) Packets() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.packets
+}
+
+// Closed returns how many sinks were closed (helper method for testing)
+func (m *MockVideoRecorder) Closed() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// mockVideoSink counts packets on its recorder
+type mockVideoSink struct {
+	recorder *MockVideoRecorder
+}
+
+// WriteRTP counts the packet
+func (s *mockVideoSink) WriteRTP(packet []byte) error {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.packets++
+	return nil
+}
+
+// Close counts the closed sink
+func (s *mockVideoSink) Close() error {
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+	s.recorder.closed++
+	return nil
+}